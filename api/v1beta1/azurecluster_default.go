@@ -17,9 +17,15 @@ limitations under the License.
 package v1beta1
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
+	"net"
 
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 )
 
 const (
@@ -37,12 +43,40 @@ const (
 	DefaultAzureBastionSubnetName = "AzureBastionSubnet"
 	// DefaultAzureBastionSubnetRole is the default Subnet role for AzureBastion.
 	DefaultAzureBastionSubnetRole = SubnetBastion
+	// DefaultGatewaySubnetCIDR is the default CIDR of the GatewaySubnet.
+	DefaultGatewaySubnetCIDR = "10.255.255.192/27"
+	// GatewaySubnetName is the name Azure requires for the subnet of virtual network gateways.
+	GatewaySubnetName = "GatewaySubnet"
+	// DefaultApplicationGatewaySubnetCIDR is the default CIDR of the subnet of the application gateway.
+	DefaultApplicationGatewaySubnetCIDR = "10.255.255.128/26"
+	// DefaultWAFRuleSetVersion is the default version of the OWASP core rule set of web application firewalls.
+	DefaultWAFRuleSetVersion = "3.2"
+	// DefaultVpnGatewaySKU is the default SKU of VPN gateways.
+	DefaultVpnGatewaySKU = "VpnGw1AZ"
+	// DefaultExpressRouteGatewaySKU is the default SKU of ExpressRoute gateways.
+	DefaultExpressRouteGatewaySKU = "ErGw1AZ"
+	// DefaultNetworkWatcherResourceGroup is the resource group of the network watchers Azure creates automatically.
+	DefaultNetworkWatcherResourceGroup = "NetworkWatcherRG"
+	// DefaultTrafficAnalyticsIntervalInMinutes is the default processing interval of traffic analytics.
+	DefaultTrafficAnalyticsIntervalInMinutes = 60
 	// DefaultInternalLBIPAddress is the default internal load balancer ip address.
 	DefaultInternalLBIPAddress = "10.0.0.100"
 	// DefaultOutboundRuleIdleTimeoutInMinutes is the default for IdleTimeoutInMinutes for the load balancer.
 	DefaultOutboundRuleIdleTimeoutInMinutes = 4
+	// DefaultLBProbeRequestPath is the default request path of the API server load balancer Http and Https health probes.
+	DefaultLBProbeRequestPath = "/readyz"
+	// DefaultLBProbeIntervalInSeconds is the default interval of the API server load balancer health probe.
+	DefaultLBProbeIntervalInSeconds = 15
+	// DefaultLBProbeNumberOfProbes is the default number of failed API server load balancer health probes
+	// after which a backend is taken out of rotation.
+	DefaultLBProbeNumberOfProbes = 4
 	// DefaultAzureCloud is the public cloud that will be used by most users.
 	DefaultAzureCloud = "AzurePublicCloud"
+	// DefaultDNSRecordTTL is the default TTL, in seconds, of DNS record sets.
+	DefaultDNSRecordTTL = 300
+	// DefaultEgressProxyPort is the default port of the egress proxy of the API server, the agent port of the
+	// konnectivity server.
+	DefaultEgressProxyPort = 8132
 )
 
 func (c *AzureCluster) setDefaults() {
@@ -54,19 +88,34 @@ func (c *AzureCluster) setDefaults() {
 func (c *AzureCluster) setNetworkSpecDefaults() {
 	c.setVnetDefaults()
 	c.setBastionDefaults()
+	c.setGatewayDefaults()
+	c.setApplicationGatewayDefaults()
 	c.setSubnetDefaults()
 	c.setVnetPeeringDefaults()
 	c.setAPIServerLBDefaults()
 	c.SetNodeOutboundLBDefaults()
 	c.SetControlPlaneOutboundLBDefaults()
+	c.setNodeInternalLBDefaults()
+	c.setAPIServerDNSRecordDefaults()
+	c.setFlowLogsDefaults()
+	c.setEgressProxyDefaults()
+	c.setSecondaryRegionsDefaults()
 }
 
 func (c *AzureCluster) setResourceGroupDefault() {
 	if c.Spec.ResourceGroup == "" {
-		c.Spec.ResourceGroup = c.Name
+		c.Spec.ResourceGroup = c.generatedName(c.Name)
 	}
 }
 
+// networkResourceGroup returns the resource group network resources are created in by default.
+func (c *AzureCluster) networkResourceGroup() string {
+	if c.Spec.NetworkResourceGroup != "" {
+		return c.Spec.NetworkResourceGroup
+	}
+	return c.Spec.ResourceGroup
+}
+
 func (c *AzureCluster) setAzureEnvironmentDefault() {
 	if c.Spec.AzureEnvironment == "" {
 		c.Spec.AzureEnvironment = DefaultAzureCloud
@@ -75,10 +124,13 @@ func (c *AzureCluster) setAzureEnvironmentDefault() {
 
 func (c *AzureCluster) setVnetDefaults() {
 	if c.Spec.NetworkSpec.Vnet.ResourceGroup == "" {
-		c.Spec.NetworkSpec.Vnet.ResourceGroup = c.Spec.ResourceGroup
+		c.Spec.NetworkSpec.Vnet.ResourceGroup = c.networkResourceGroup()
 	}
 	if c.Spec.NetworkSpec.Vnet.Name == "" {
-		c.Spec.NetworkSpec.Vnet.Name = generateVnetName(c.ObjectMeta.Name)
+		c.Spec.NetworkSpec.Vnet.Name = c.generatedName(generateVnetName(c.ObjectMeta.Name))
+	}
+	if plan := c.Spec.NetworkSpec.Vnet.DDoSProtectionPlan; plan != nil && plan.ID == "" && plan.Name == "" {
+		plan.Name = c.generatedName(generateDDoSProtectionPlanName(c.ObjectMeta.Name))
 	}
 	c.Spec.NetworkSpec.Vnet.VnetClassSpec.setDefaults()
 }
@@ -91,14 +143,15 @@ func (c *AzureCluster) setSubnetDefaults() {
 	}
 
 	if cpSubnet.Name == "" {
-		cpSubnet.Name = generateControlPlaneSubnetName(c.ObjectMeta.Name)
+		cpSubnet.Name = c.generatedName(generateControlPlaneSubnetName(c.ObjectMeta.Name))
 	}
 
-	cpSubnet.SubnetClassSpec.setDefaults(DefaultControlPlaneSubnetCIDR)
-
-	if cpSubnet.SecurityGroup.Name == "" {
-		cpSubnet.SecurityGroup.Name = generateControlPlaneSecurityGroupName(c.ObjectMeta.Name)
+	// The address space of a subnet claimed from an IP address pool is set once the claim is fulfilled.
+	if cpSubnet.CIDRBlockFromPool == nil {
+		cpSubnet.SubnetClassSpec.setDefaults(defaultSubnetCIDR(c.Spec.NetworkSpec.Vnet.CIDRBlocks, 0, DefaultControlPlaneSubnetCIDR))
 	}
+
+	cpSubnet.SecurityGroup.setNameDefault(c.generatedName(generateControlPlaneSecurityGroupName(c.ObjectMeta.Name)))
 	cpSubnet.SecurityGroup.SecurityGroupClass.setDefaults()
 
 	c.Spec.NetworkSpec.UpdateControlPlaneSubnet(cpSubnet)
@@ -112,25 +165,26 @@ func (c *AzureCluster) setSubnetDefaults() {
 		nodeSubnetCounter++
 		nodeSubnetFound = true
 		if subnet.Name == "" {
-			subnet.Name = withIndex(generateNodeSubnetName(c.ObjectMeta.Name), nodeSubnetCounter)
+			subnet.Name = c.generatedName(withIndex(generateNodeSubnetName(c.ObjectMeta.Name), nodeSubnetCounter))
 		}
-		subnet.SubnetClassSpec.setDefaults(fmt.Sprintf(DefaultNodeSubnetCIDRPattern, nodeSubnetCounter))
-
-		if subnet.SecurityGroup.Name == "" {
-			subnet.SecurityGroup.Name = generateNodeSecurityGroupName(c.ObjectMeta.Name)
+		if subnet.CIDRBlockFromPool == nil {
+			subnet.SubnetClassSpec.setDefaults(defaultSubnetCIDR(c.Spec.NetworkSpec.Vnet.CIDRBlocks, nodeSubnetCounter, fmt.Sprintf(DefaultNodeSubnetCIDRPattern, nodeSubnetCounter)))
 		}
+
+		subnet.SecurityGroup.setNameDefault(c.generatedName(generateNodeSecurityGroupName(c.ObjectMeta.Name)))
 		subnet.SecurityGroup.SecurityGroupClass.setDefaults()
 
 		if subnet.RouteTable.Name == "" {
-			subnet.RouteTable.Name = generateNodeRouteTableName(c.ObjectMeta.Name)
+			subnet.RouteTable.Name = c.generatedName(generateNodeRouteTableName(c.ObjectMeta.Name))
 		}
 
 		// NAT gateway only supports the use of IPv4 public IP addresses for outbound connectivity.
 		// So default use the NAT gateway for outbound traffic in IPv4 cluster instead of loadbalancer.
 		// We assume that if the ID is set, the subnet already exists so we shouldn't add a NAT gateway.
-		if !subnet.IsIPv6Enabled() && subnet.ID == "" {
+		// Nodes egress through the node outbound LB instead when one is set explicitly or NAT gateways are disabled.
+		if !subnet.IsIPv6Enabled() && subnet.ID == "" && c.Spec.NetworkSpec.NodeOutboundLB == nil && feature.Gates.Enabled(feature.NATGateway) {
 			if subnet.NatGateway.Name == "" {
-				subnet.NatGateway.Name = withIndex(generateNatGatewayName(c.ObjectMeta.Name), nodeSubnetCounter)
+				subnet.NatGateway.Name = c.generatedName(withIndex(generateNatGatewayName(c.ObjectMeta.Name), nodeSubnetCounter))
 			}
 			if subnet.NatGateway.NatGatewayIP.Name == "" {
 				subnet.NatGateway.NatGatewayIP.Name = generateNatGatewayIPName(subnet.NatGateway.Name)
@@ -144,20 +198,23 @@ func (c *AzureCluster) setSubnetDefaults() {
 		nodeSubnet := SubnetSpec{
 			SubnetClassSpec: SubnetClassSpec{
 				Role:       SubnetNode,
-				CIDRBlocks: []string{DefaultNodeSubnetCIDR},
-				Name:       generateNodeSubnetName(c.ObjectMeta.Name),
+				CIDRBlocks: []string{defaultSubnetCIDR(c.Spec.NetworkSpec.Vnet.CIDRBlocks, 1, DefaultNodeSubnetCIDR)},
+				Name:       c.generatedName(generateNodeSubnetName(c.ObjectMeta.Name)),
 			},
 			SecurityGroup: SecurityGroup{
-				Name: generateNodeSecurityGroupName(c.ObjectMeta.Name),
+				Name: c.generatedName(generateNodeSecurityGroupName(c.ObjectMeta.Name)),
 			},
 			RouteTable: RouteTable{
-				Name: generateNodeRouteTableName(c.ObjectMeta.Name),
+				Name: c.generatedName(generateNodeRouteTableName(c.ObjectMeta.Name)),
 			},
-			NatGateway: NatGateway{
+		}
+		if c.Spec.NetworkSpec.NodeOutboundLB == nil && feature.Gates.Enabled(feature.NATGateway) {
+			nodeSubnet.NatGateway = NatGateway{
 				NatGatewayClassSpec: NatGatewayClassSpec{
-					Name: generateNatGatewayName(c.ObjectMeta.Name),
+					Name: c.generatedName(generateNatGatewayName(c.ObjectMeta.Name)),
 				},
-			},
+			}
+			nodeSubnet.NatGateway.NatGatewayIP.Name = generateNatGatewayIPName(nodeSubnet.NatGateway.Name)
 		}
 		c.Spec.NetworkSpec.Subnets = append(c.Spec.NetworkSpec.Subnets, nodeSubnet)
 	}
@@ -166,7 +223,7 @@ func (c *AzureCluster) setSubnetDefaults() {
 func (c *AzureCluster) setVnetPeeringDefaults() {
 	for i, peering := range c.Spec.NetworkSpec.Vnet.Peerings {
 		if peering.ResourceGroup == "" {
-			c.Spec.NetworkSpec.Vnet.Peerings[i].ResourceGroup = c.Spec.ResourceGroup
+			c.Spec.NetworkSpec.Vnet.Peerings[i].ResourceGroup = c.networkResourceGroup()
 		}
 	}
 }
@@ -174,32 +231,56 @@ func (c *AzureCluster) setVnetPeeringDefaults() {
 func (c *AzureCluster) setAPIServerLBDefaults() {
 	lb := &c.Spec.NetworkSpec.APIServerLB
 
+	// Clusters without public network access can only have an internal API server load balancer.
+	if c.Spec.PublicNetworkAccess == PublicNetworkAccessDisabled && lb.Type == "" {
+		lb.Type = Internal
+	}
 	lb.LoadBalancerClassSpec.setAPIServerLBDefaults()
 
 	if lb.Type == Public {
 		if lb.Name == "" {
-			lb.Name = generatePublicLBName(c.ObjectMeta.Name)
+			lb.Name = c.generatedName(generatePublicLBName(c.ObjectMeta.Name))
 		}
 		if len(lb.FrontendIPs) == 0 {
 			lb.FrontendIPs = []FrontendIP{
 				{
 					Name: generateFrontendIPConfigName(lb.Name),
 					PublicIP: &PublicIPSpec{
-						Name: generatePublicIPName(c.ObjectMeta.Name),
+						Name: c.generatedName(generatePublicIPName(c.ObjectMeta.Name)),
 					},
 				},
 			}
 		}
+		// The first frontend IP serves the API server, additional ones only provide more SNAT ports to the outbound rule.
+		// Counts above the maximum are rejected by validation.
+		for i := len(lb.FrontendIPs); i < int(ptr.Deref[int32](lb.FrontendIPsCount, 1)) && i < MaxLoadBalancerOutboundIPs; i++ {
+			lb.FrontendIPs = append(lb.FrontendIPs, FrontendIP{
+				Name: withIndex(generateFrontendIPConfigName(lb.Name), i+1),
+				PublicIP: &PublicIPSpec{
+					Name: c.generatedName(withIndex(generatePublicIPName(c.ObjectMeta.Name), i+1)),
+				},
+			})
+		}
+		// An existing public IP keeps its own name.
+		if ip := lb.FrontendIPs[0].PublicIP; ip != nil && ip.Existing != nil && ip.Existing.ID != "" && ip.Name == "" {
+			if resourceID, err := azureutil.ParseResourceID(ip.Existing.ID); err == nil {
+				ip.Name = resourceID.Name
+			}
+		}
 	} else if lb.Type == Internal {
 		if lb.Name == "" {
-			lb.Name = generateInternalLBName(c.ObjectMeta.Name)
+			lb.Name = c.generatedName(generateInternalLBName(c.ObjectMeta.Name))
 		}
 		if len(lb.FrontendIPs) == 0 {
+			var cpSubnetCIDRBlocks []string
+			if cpSubnet, err := c.Spec.NetworkSpec.GetControlPlaneSubnet(); err == nil {
+				cpSubnetCIDRBlocks = cpSubnet.CIDRBlocks
+			}
 			lb.FrontendIPs = []FrontendIP{
 				{
 					Name: generateFrontendIPConfigName(lb.Name),
 					FrontendIPClass: FrontendIPClass{
-						PrivateIPAddress: DefaultInternalLBIPAddress,
+						PrivateIPAddress: defaultInternalLBIPAddress(cpSubnetCIDRBlocks),
 					},
 				},
 			}
@@ -215,9 +296,11 @@ func (c *AzureCluster) SetNodeOutboundLBDefaults() {
 			return
 		}
 
+		// Node subnets without a NAT gateway need the outbound LB too while NAT gateways are disabled.
+		natGatewayEnabled := feature.Gates.Enabled(feature.NATGateway)
 		var needsOutboundLB bool
 		for _, subnet := range c.Spec.NetworkSpec.Subnets {
-			if subnet.Role == SubnetNode && subnet.IsIPv6Enabled() {
+			if subnet.Role == SubnetNode && (subnet.IsIPv6Enabled() || (!natGatewayEnabled && !subnet.IsNatGatewayEnabled())) {
 				needsOutboundLB = true
 				break
 			}
@@ -237,7 +320,7 @@ func (c *AzureCluster) SetNodeOutboundLBDefaults() {
 	lb.LoadBalancerClassSpec.setNodeOutboundLBDefaults()
 
 	if lb.Name == "" {
-		lb.Name = c.ObjectMeta.Name
+		lb.Name = c.generatedName(c.ObjectMeta.Name)
 	}
 
 	if lb.FrontendIPsCount == nil {
@@ -258,7 +341,7 @@ func (c *AzureCluster) SetControlPlaneOutboundLBDefaults() {
 
 	lb.LoadBalancerClassSpec.setControlPlaneOutboundLBDefaults()
 	if lb.Name == "" {
-		lb.Name = generateControlPlaneOutboundLBName(c.ObjectMeta.Name)
+		lb.Name = c.generatedName(generateControlPlaneOutboundLBName(c.ObjectMeta.Name))
 	}
 	if lb.FrontendIPsCount == nil {
 		lb.FrontendIPsCount = ptr.To[int32](1)
@@ -267,6 +350,65 @@ func (c *AzureCluster) SetControlPlaneOutboundLBDefaults() {
 	c.SetControlPlaneOutboundLBBackendPoolNameDefault()
 }
 
+// setNodeInternalLBDefaults sets the default values for the internal LB of the worker nodes, if any.
+// Its frontend IP is allocated dynamically in the node subnet unless a private IP is set.
+func (c *AzureCluster) setNodeInternalLBDefaults() {
+	lb := c.Spec.NetworkSpec.NodeInternalLB
+	if lb == nil {
+		return
+	}
+
+	lb.Type = Internal
+	if lb.SKU == "" {
+		lb.SKU = SKUStandard
+	}
+	if lb.IdleTimeoutInMinutes == nil {
+		lb.IdleTimeoutInMinutes = ptr.To[int32](DefaultOutboundRuleIdleTimeoutInMinutes)
+	}
+	if lb.HealthProbe != nil {
+		// Unlike the API server, the services behind the node load balancer are not known to serve /readyz.
+		if lb.HealthProbe.Protocol == "" {
+			lb.HealthProbe.Protocol = LoadBalancerProbeProtocolTCP
+		}
+		lb.HealthProbe.setDefaults()
+	}
+	lb.setAdditionalRulesDefaults()
+	if lb.Name == "" {
+		lb.Name = c.generatedName(generateNodeInternalLBName(c.ObjectMeta.Name))
+	}
+	if len(lb.FrontendIPs) == 0 {
+		lb.FrontendIPs = []FrontendIP{{Name: generateFrontendIPConfigName(lb.Name)}}
+	}
+	if lb.BackendPool.Name == "" {
+		lb.BackendPool.Name = generateBackendAddressPoolName(lb.Name)
+	}
+	if lb.SubnetName == "" {
+		for _, subnet := range c.Spec.NetworkSpec.Subnets {
+			if subnet.Role == SubnetNode {
+				lb.SubnetName = subnet.Name
+				break
+			}
+		}
+	}
+}
+
+// setAPIServerDNSRecordDefaults defaults the API server DNS record, if any.
+func (c *AzureCluster) setAPIServerDNSRecordDefaults() {
+	record := c.Spec.NetworkSpec.APIServerDNSRecord
+	if record == nil {
+		return
+	}
+	if record.ZoneResourceGroup == "" {
+		record.ZoneResourceGroup = c.Spec.ResourceGroup
+	}
+	if record.RecordName == "" {
+		record.RecordName = c.ObjectMeta.Name
+	}
+	if record.TTL == nil {
+		record.TTL = ptr.To[int64](DefaultDNSRecordTTL)
+	}
+}
+
 // SetBackendPoolNameDefault defaults the backend pool name of the LBs.
 func (c *AzureCluster) SetBackendPoolNameDefault() {
 	c.SetAPIServerLBBackendPoolNameDefault()
@@ -294,13 +436,17 @@ func (c *AzureCluster) SetNodeOutboundLBBackendPoolNameDefault() {
 func (c *AzureCluster) SetControlPlaneOutboundLBBackendPoolNameDefault() {
 	controlPlaneOutboundLB := c.Spec.NetworkSpec.ControlPlaneOutboundLB
 	if controlPlaneOutboundLB != nil && controlPlaneOutboundLB.BackendPool.Name == "" {
-		controlPlaneOutboundLB.BackendPool.Name = generateOutboundBackendAddressPoolName(generateControlPlaneOutboundLBName(c.ObjectMeta.Name))
+		controlPlaneOutboundLB.BackendPool.Name = generateOutboundBackendAddressPoolName(c.generatedName(generateControlPlaneOutboundLBName(c.ObjectMeta.Name)))
 	}
 }
 
 // setOutboundLBFrontendIPs sets the frontend ips for the given load balancer.
 // The name of the frontend ip is generated using generatePublicIPName function.
 func (c *AzureCluster) setOutboundLBFrontendIPs(lb *LoadBalancerSpec, generatePublicIPName func(string) string) {
+	// Out of range counts are rejected by validation.
+	if *lb.FrontendIPsCount < 0 || *lb.FrontendIPsCount > MaxLoadBalancerOutboundIPs {
+		return
+	}
 	switch *lb.FrontendIPsCount {
 	case 0:
 		lb.FrontendIPs = []FrontendIP{}
@@ -309,7 +455,7 @@ func (c *AzureCluster) setOutboundLBFrontendIPs(lb *LoadBalancerSpec, generatePu
 			{
 				Name: generateFrontendIPConfigName(lb.Name),
 				PublicIP: &PublicIPSpec{
-					Name: generatePublicIPName(c.ObjectMeta.Name),
+					Name: c.generatedName(generatePublicIPName(c.ObjectMeta.Name)),
 				},
 			},
 		}
@@ -319,7 +465,7 @@ func (c *AzureCluster) setOutboundLBFrontendIPs(lb *LoadBalancerSpec, generatePu
 			lb.FrontendIPs[i] = FrontendIP{
 				Name: withIndex(generateFrontendIPConfigName(lb.Name), i+1),
 				PublicIP: &PublicIPSpec{
-					Name: withIndex(generatePublicIPName(c.ObjectMeta.Name), i+1),
+					Name: c.generatedName(withIndex(generatePublicIPName(c.ObjectMeta.Name), i+1)),
 				},
 			}
 		}
@@ -329,25 +475,143 @@ func (c *AzureCluster) setOutboundLBFrontendIPs(lb *LoadBalancerSpec, generatePu
 func (c *AzureCluster) setBastionDefaults() {
 	if c.Spec.BastionSpec.AzureBastion != nil {
 		if c.Spec.BastionSpec.AzureBastion.Name == "" {
-			c.Spec.BastionSpec.AzureBastion.Name = generateAzureBastionName(c.ObjectMeta.Name)
+			c.Spec.BastionSpec.AzureBastion.Name = c.generatedName(generateAzureBastionName(c.ObjectMeta.Name))
 		}
 		// Ensure defaults for the Subnet settings.
 		if c.Spec.BastionSpec.AzureBastion.Subnet.Name == "" {
 			c.Spec.BastionSpec.AzureBastion.Subnet.Name = DefaultAzureBastionSubnetName
 		}
 		if len(c.Spec.BastionSpec.AzureBastion.Subnet.CIDRBlocks) == 0 {
-			c.Spec.BastionSpec.AzureBastion.Subnet.CIDRBlocks = []string{DefaultAzureBastionSubnetCIDR}
+			c.Spec.BastionSpec.AzureBastion.Subnet.CIDRBlocks = []string{defaultBastionSubnetCIDR(c.Spec.NetworkSpec.Vnet.CIDRBlocks)}
 		}
 		if c.Spec.BastionSpec.AzureBastion.Subnet.Role == "" {
 			c.Spec.BastionSpec.AzureBastion.Subnet.Role = DefaultAzureBastionSubnetRole
 		}
 		// Ensure defaults for the PublicIP settings.
 		if c.Spec.BastionSpec.AzureBastion.PublicIP.Name == "" {
-			c.Spec.BastionSpec.AzureBastion.PublicIP.Name = generateAzureBastionPublicIPName(c.ObjectMeta.Name)
+			c.Spec.BastionSpec.AzureBastion.PublicIP.Name = c.generatedName(generateAzureBastionPublicIPName(c.ObjectMeta.Name))
+		}
+	}
+}
+
+func (c *AzureCluster) setGatewayDefaults() {
+	gateway := c.Spec.NetworkSpec.Gateway
+	if gateway == nil {
+		return
+	}
+	if len(gateway.CIDRBlocks) == 0 {
+		gateway.CIDRBlocks = []string{defaultGatewaySubnetCIDR(c.Spec.NetworkSpec.Vnet.CIDRBlocks)}
+	}
+	if vng := gateway.VirtualNetworkGateway; vng != nil {
+		if vng.Name == "" {
+			vng.Name = c.generatedName(generateVirtualNetworkGatewayName(c.ObjectMeta.Name))
+		}
+		if vng.SKU == "" {
+			vng.SKU = DefaultVpnGatewaySKU
+			if vng.Type == VirtualNetworkGatewayTypeExpressRoute {
+				vng.SKU = DefaultExpressRouteGatewaySKU
+			}
+		}
+		if vng.PublicIP.Name == "" {
+			vng.PublicIP.Name = c.generatedName(generateVirtualNetworkGatewayPublicIPName(c.ObjectMeta.Name))
+		}
+	}
+}
+
+func (c *AzureCluster) setApplicationGatewayDefaults() {
+	appGateway := c.Spec.ApplicationGateway
+	if appGateway == nil {
+		return
+	}
+	if appGateway.Name == "" {
+		appGateway.Name = c.generatedName(generateApplicationGatewayName(c.ObjectMeta.Name))
+	}
+	if appGateway.SubnetName == "" {
+		appGateway.SubnetName = c.generatedName(generateApplicationGatewaySubnetName(c.ObjectMeta.Name))
+	}
+	if len(appGateway.CIDRBlocks) == 0 {
+		appGateway.CIDRBlocks = []string{defaultApplicationGatewaySubnetCIDR(c.Spec.NetworkSpec.Vnet.CIDRBlocks)}
+	}
+	if appGateway.PublicIP.Name == "" {
+		appGateway.PublicIP.Name = c.generatedName(generateApplicationGatewayPublicIPName(c.ObjectMeta.Name))
+	}
+	if waf := appGateway.WAF; waf != nil {
+		if waf.Mode == "" {
+			waf.Mode = WebApplicationFirewallModeDetection
+		}
+		if waf.RuleSetVersion == "" {
+			waf.RuleSetVersion = DefaultWAFRuleSetVersion
+		}
+	}
+}
+
+func (c *AzureCluster) setSecondaryRegionsDefaults() {
+	for i := range c.Spec.SecondaryRegions {
+		region := &c.Spec.SecondaryRegions[i]
+		if region.VnetName == "" {
+			region.VnetName = c.generatedName(generateSecondaryRegionVnetName(c.ObjectMeta.Name, region.Location))
+		}
+		if len(region.Subnets) == 0 && len(region.CIDRBlocks) > 0 {
+			region.Subnets = []SecondaryRegionSubnetSpec{{
+				Name:       c.generatedName(generateSecondaryRegionNodeSubnetName(c.ObjectMeta.Name, region.Location)),
+				CIDRBlocks: []string{region.CIDRBlocks[0]},
+			}}
+		}
+		for j := range region.Subnets {
+			subnet := &region.Subnets[j]
+			if subnet.SecurityGroupName == "" {
+				subnet.SecurityGroupName = fmt.Sprintf("%s-nsg", subnet.Name)
+			}
+			for k := range subnet.SecurityRules {
+				if subnet.SecurityRules[k].Direction == "" {
+					subnet.SecurityRules[k].Direction = SecurityRuleDirectionInbound
+				}
+			}
+		}
+	}
+}
+
+func (c *AzureCluster) setEgressProxyDefaults() {
+	if egressProxy := c.Spec.NetworkSpec.EgressProxy; egressProxy != nil && egressProxy.Port == 0 {
+		egressProxy.Port = DefaultEgressProxyPort
+	}
+}
+
+func (c *AzureCluster) setFlowLogsDefaults() {
+	flowLogs := c.Spec.NetworkSpec.FlowLogs
+	if flowLogs == nil {
+		return
+	}
+	if flowLogs.NetworkWatcher.Name == "" {
+		flowLogs.NetworkWatcher.Name = generateNetworkWatcherName(c.Spec.Location)
+	}
+	if flowLogs.NetworkWatcher.ResourceGroup == "" {
+		flowLogs.NetworkWatcher.ResourceGroup = DefaultNetworkWatcherResourceGroup
+	}
+	if analytics := flowLogs.TrafficAnalytics; analytics != nil {
+		if analytics.WorkspaceRegion == "" {
+			analytics.WorkspaceRegion = c.Spec.Location
+		}
+		if analytics.IntervalInMinutes == 0 {
+			analytics.IntervalInMinutes = DefaultTrafficAnalyticsIntervalInMinutes
 		}
 	}
 }
 
+// setNameDefault sets the name of the security group to the name of the existing NSG it references, or else to name.
+func (sg *SecurityGroup) setNameDefault(name string) {
+	if sg.Name != "" {
+		return
+	}
+	if sg.Existing != nil {
+		if resourceID, err := azureutil.ParseResourceID(sg.Existing.ID); err == nil {
+			sg.Name = resourceID.Name
+			return
+		}
+	}
+	sg.Name = name
+}
+
 func (lb *LoadBalancerClassSpec) setAPIServerLBDefaults() {
 	if lb.Type == "" {
 		lb.Type = Public
@@ -358,6 +622,40 @@ func (lb *LoadBalancerClassSpec) setAPIServerLBDefaults() {
 	if lb.IdleTimeoutInMinutes == nil {
 		lb.IdleTimeoutInMinutes = ptr.To[int32](DefaultOutboundRuleIdleTimeoutInMinutes)
 	}
+	if lb.HealthProbe != nil {
+		lb.HealthProbe.setDefaults()
+	}
+	lb.setAdditionalRulesDefaults()
+}
+
+// setAdditionalRulesDefaults sets default values for the additional load balancing rules.
+func (lb *LoadBalancerClassSpec) setAdditionalRulesDefaults() {
+	for i := range lb.AdditionalRules {
+		rule := &lb.AdditionalRules[i]
+		if rule.Protocol == "" {
+			rule.Protocol = LoadBalancingRuleProtocolTCP
+		}
+		if rule.BackendPort == nil {
+			rule.BackendPort = ptr.To(rule.FrontendPort)
+		}
+	}
+}
+
+// setDefaults sets default values for a LoadBalancerHealthProbe.
+// The port is left empty so that it follows the API server port.
+func (p *LoadBalancerHealthProbe) setDefaults() {
+	if p.Protocol == "" {
+		p.Protocol = LoadBalancerProbeProtocolHTTPS
+	}
+	if p.RequestPath == "" && p.Protocol != LoadBalancerProbeProtocolTCP {
+		p.RequestPath = DefaultLBProbeRequestPath
+	}
+	if p.IntervalInSeconds == nil {
+		p.IntervalInSeconds = ptr.To[int32](DefaultLBProbeIntervalInSeconds)
+	}
+	if p.NumberOfProbes == nil {
+		p.NumberOfProbes = ptr.To[int32](DefaultLBProbeNumberOfProbes)
+	}
 }
 
 func (lb *LoadBalancerClassSpec) setNodeOutboundLBDefaults() {
@@ -395,6 +693,20 @@ func setControlPlaneOutboundLBDefaults(lb *LoadBalancerClassSpec, apiserverLBTyp
 	}
 }
 
+// generatedName applies the resource naming of the cluster to a name generated for one of its resources, and
+// truncates it to the maximum length of Azure resource names, keeping the suffix of the resource naming.
+func (c *AzureCluster) generatedName(name string) string {
+	naming := c.Spec.ResourceNaming
+	if naming == nil {
+		return azureutil.TruncateName(name, "", azureutil.MaxResourceNameLength)
+	}
+	if naming.HashStrategy == ResourceNameHashStrategyNamespacedName {
+		sum := sha256.Sum256([]byte(c.Namespace + "/" + c.Name))
+		name = fmt.Sprintf("%s-%s", name, hex.EncodeToString(sum[:])[:6])
+	}
+	return azureutil.TruncateName(naming.Prefix+name, naming.Suffix, azureutil.MaxResourceNameLength)
+}
+
 // generateVnetName generates a virtual network name, based on the cluster name.
 func generateVnetName(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, "vnet")
@@ -420,6 +732,51 @@ func generateAzureBastionPublicIPName(clusterName string) string {
 	return fmt.Sprintf("%s-azure-bastion-pip", clusterName)
 }
 
+// generateVirtualNetworkGatewayName generates a virtual network gateway name, based on the cluster name.
+func generateVirtualNetworkGatewayName(clusterName string) string {
+	return fmt.Sprintf("%s-vnet-gateway", clusterName)
+}
+
+// generateVirtualNetworkGatewayPublicIPName generates a virtual network gateway public ip name.
+func generateVirtualNetworkGatewayPublicIPName(clusterName string) string {
+	return fmt.Sprintf("%s-vnet-gateway-pip", clusterName)
+}
+
+// generateApplicationGatewayName generates an application gateway name, based on the cluster name.
+func generateApplicationGatewayName(clusterName string) string {
+	return fmt.Sprintf("%s-appgw", clusterName)
+}
+
+// generateApplicationGatewaySubnetName generates an application gateway subnet name, based on the cluster name.
+func generateApplicationGatewaySubnetName(clusterName string) string {
+	return fmt.Sprintf("%s-appgw-subnet", clusterName)
+}
+
+// generateApplicationGatewayPublicIPName generates an application gateway public ip name.
+func generateApplicationGatewayPublicIPName(clusterName string) string {
+	return fmt.Sprintf("%s-appgw-pip", clusterName)
+}
+
+// generateSecondaryRegionVnetName generates the virtual network name of a secondary region, based on the cluster name.
+func generateSecondaryRegionVnetName(clusterName, location string) string {
+	return fmt.Sprintf("%s-vnet-%s", clusterName, location)
+}
+
+// generateSecondaryRegionNodeSubnetName generates the node subnet name of a secondary region, based on the cluster name.
+func generateSecondaryRegionNodeSubnetName(clusterName, location string) string {
+	return fmt.Sprintf("%s-node-subnet-%s", clusterName, location)
+}
+
+// generateDDoSProtectionPlanName generates a DDoS protection plan name, based on the cluster name.
+func generateDDoSProtectionPlanName(clusterName string) string {
+	return fmt.Sprintf("%s-ddos-protection-plan", clusterName)
+}
+
+// generateNetworkWatcherName generates the name of the network watcher Azure creates for a location.
+func generateNetworkWatcherName(location string) string {
+	return fmt.Sprintf("NetworkWatcher_%s", location)
+}
+
 // generateControlPlaneSecurityGroupName generates a control plane security group name, based on the cluster name.
 func generateControlPlaneSecurityGroupName(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, "controlplane-nsg")
@@ -450,6 +807,11 @@ func generateControlPlaneOutboundLBName(clusterName string) string {
 	return fmt.Sprintf("%s-outbound-lb", clusterName)
 }
 
+// generateNodeInternalLBName generates the name of the internal LB of the worker nodes.
+func generateNodeInternalLBName(clusterName string) string {
+	return fmt.Sprintf("%s-node-internal-lb", clusterName)
+}
+
 // generatePublicIPName generates a public IP name, based on the cluster name and a hash.
 func generatePublicIPName(clusterName string) string {
 	return fmt.Sprintf("pip-%s-apiserver", clusterName)
@@ -477,10 +839,100 @@ func generateNatGatewayName(clusterName string) string {
 
 // generateNatGatewayIPName generates a NAT gateway IP name.
 func generateNatGatewayIPName(natGatewayName string) string {
-	return fmt.Sprintf("pip-%s", natGatewayName)
+	return azureutil.TruncateName("pip-"+natGatewayName, "", azureutil.MaxResourceNameLength)
 }
 
 // withIndex appends the index as suffix to a generated name.
+// defaultSubnetCIDR returns the index-th subnet, 8 bits longer than the prefix of the first IPv4 vnet CIDR block,
+// so that default subnets stay within a custom vnet. With the default vnet CIDR this is 10.<index>.0.0/16.
+// The fallback is returned when there is no usable IPv4 vnet CIDR block.
+func defaultSubnetCIDR(vnetCIDRBlocks []string, index int, fallback string) string {
+	vnet := firstIPv4Network(vnetCIDRBlocks)
+	if vnet == nil || index < 0 || index > 255 {
+		return fallback
+	}
+	ones, _ := vnet.Mask.Size()
+	if ones+8 > 29 {
+		return fallback
+	}
+	return offsetIPv4(vnet.IP, uint32(index)<<(32-(ones+8))).String() + fmt.Sprintf("/%d", ones+8)
+}
+
+// defaultBastionSubnetCIDR returns the last /27 of the first IPv4 vnet CIDR block, or DefaultAzureBastionSubnetCIDR
+// when there is no usable IPv4 vnet CIDR block.
+func defaultBastionSubnetCIDR(vnetCIDRBlocks []string) string {
+	vnet := firstIPv4Network(vnetCIDRBlocks)
+	if vnet == nil {
+		return DefaultAzureBastionSubnetCIDR
+	}
+	ones, _ := vnet.Mask.Size()
+	if ones > 26 {
+		return DefaultAzureBastionSubnetCIDR
+	}
+	return offsetIPv4(vnet.IP, uint32(1)<<(32-ones)-32).String() + "/27"
+}
+
+// defaultGatewaySubnetCIDR returns the /27 preceding the last one of the first IPv4 vnet CIDR block, which is the
+// default Azure Bastion subnet, or DefaultGatewaySubnetCIDR when the vnet has no IPv4 CIDR block large enough.
+func defaultGatewaySubnetCIDR(vnetCIDRBlocks []string) string {
+	vnet := firstIPv4Network(vnetCIDRBlocks)
+	if vnet == nil {
+		return DefaultGatewaySubnetCIDR
+	}
+	ones, _ := vnet.Mask.Size()
+	if ones > 25 {
+		return DefaultGatewaySubnetCIDR
+	}
+	return offsetIPv4(vnet.IP, uint32(1)<<(32-ones)-64).String() + "/27"
+}
+
+// defaultApplicationGatewaySubnetCIDR returns the /26 preceding the default GatewaySubnet at the end of the first IPv4
+// vnet CIDR block, or DefaultApplicationGatewaySubnetCIDR when the vnet has no IPv4 CIDR block large enough.
+func defaultApplicationGatewaySubnetCIDR(vnetCIDRBlocks []string) string {
+	vnet := firstIPv4Network(vnetCIDRBlocks)
+	if vnet == nil {
+		return DefaultApplicationGatewaySubnetCIDR
+	}
+	ones, _ := vnet.Mask.Size()
+	if ones > 24 {
+		return DefaultApplicationGatewaySubnetCIDR
+	}
+	return offsetIPv4(vnet.IP, uint32(1)<<(32-ones)-128).String() + "/26"
+}
+
+// defaultInternalLBIPAddress returns the 100th address of the first IPv4 control plane subnet CIDR block,
+// or DefaultInternalLBIPAddress when the subnet has no IPv4 CIDR block large enough.
+func defaultInternalLBIPAddress(cpSubnetCIDRBlocks []string) string {
+	subnet := firstIPv4Network(cpSubnetCIDRBlocks)
+	if subnet == nil {
+		return DefaultInternalLBIPAddress
+	}
+	ones, _ := subnet.Mask.Size()
+	if ones > 25 {
+		return DefaultInternalLBIPAddress
+	}
+	return offsetIPv4(subnet.IP, 100).String()
+}
+
+// firstIPv4Network returns the first valid IPv4 network in cidrBlocks, or nil if there is none.
+func firstIPv4Network(cidrBlocks []string) *net.IPNet {
+	for _, cidr := range cidrBlocks {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil || ipNet.IP.To4() == nil {
+			continue
+		}
+		return ipNet
+	}
+	return nil
+}
+
+// offsetIPv4 returns the IPv4 address offset addresses after ip.
+func offsetIPv4(ip net.IP, offset uint32) net.IP {
+	out := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(out, binary.BigEndian.Uint32(ip.To4())+offset)
+	return out
+}
+
 func withIndex(name string, n int) string {
 	return fmt.Sprintf("%s-%d", name, n)
 }
@@ -19,11 +19,16 @@ package v1beta1
 import (
 	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
 
+	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 )
 
 func TestResourceGroupDefault(t *testing.T) {
@@ -81,6 +86,81 @@ func TestResourceGroupDefault(t *testing.T) {
 	}
 }
 
+func TestResourceNamingDefaults(t *testing.T) {
+	cases := map[string]struct {
+		naming *ResourceNamingSpec
+		names  func(c *AzureCluster) []string
+		want   []string
+	}{
+		"default names without resource naming": {
+			names: func(c *AzureCluster) []string {
+				return []string{c.Spec.ResourceGroup, c.Spec.NetworkSpec.Vnet.Name, c.Spec.NetworkSpec.Subnets[1].Name, c.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].PublicIP.Name}
+			},
+			want: []string{"foo", "foo-vnet", "foo-node-subnet", "pip-foo-apiserver"},
+		},
+		"prefix and suffix": {
+			naming: &ResourceNamingSpec{Prefix: "corp-", Suffix: "-weu"},
+			names: func(c *AzureCluster) []string {
+				return []string{c.Spec.ResourceGroup, c.Spec.NetworkSpec.Vnet.Name, c.Spec.NetworkSpec.Subnets[1].Name, c.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].PublicIP.Name}
+			},
+			want: []string{"corp-foo-weu", "corp-foo-vnet-weu", "corp-foo-node-subnet-weu", "corp-pip-foo-apiserver-weu"},
+		},
+		"namespaced name hash": {
+			naming: &ResourceNamingSpec{Suffix: "-001", HashStrategy: ResourceNameHashStrategyNamespacedName},
+			names: func(c *AzureCluster) []string {
+				return []string{c.Spec.ResourceGroup, c.Spec.NetworkSpec.Subnets[0].SecurityGroup.Name, c.Spec.NetworkSpec.APIServerLB.Name}
+			},
+			want: []string{"foo-947c8f-001", "foo-controlplane-nsg-947c8f-001", "foo-public-lb-947c8f-001"},
+		},
+		"sub-resources are named after their templated parent": {
+			naming: &ResourceNamingSpec{Prefix: "corp-"},
+			names: func(c *AzureCluster) []string {
+				return []string{c.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].Name, c.Spec.NetworkSpec.APIServerLB.BackendPool.Name}
+			},
+			want: []string{"corp-foo-public-lb-frontEnd", "corp-foo-public-lb-backendPool"},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			cluster := &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "default",
+				},
+			}
+			cluster.Spec.ResourceNaming = c.naming
+			cluster.setDefaults()
+			g.Expect(c.names(cluster)).To(Equal(c.want))
+		})
+	}
+}
+
+func TestResourceNamingLengthDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      strings.Repeat("c", 65),
+			Namespace: "default",
+		},
+	}
+	cluster.Spec.ResourceNaming = &ResourceNamingSpec{Suffix: "-weu"}
+	cluster.setDefaults()
+
+	routeTable := cluster.Spec.NetworkSpec.Subnets[1].RouteTable.Name
+	publicIP := cluster.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].PublicIP.Name
+	for _, name := range []string{routeTable, publicIP} {
+		g.Expect(name).To(HaveLen(80))
+		g.Expect(name).To(MatchRegexp(`-[0-9a-f]{8}-weu$`))
+	}
+	g.Expect(routeTable).NotTo(Equal(publicIP))
+	// Names which fit are left untouched.
+	g.Expect(cluster.Spec.NetworkSpec.Vnet.Name).To(Equal(cluster.Name + "-vnet-weu"))
+}
+
 func TestVnetDefaults(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -279,6 +359,74 @@ func TestVnetDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "DDoS protection plan created for the vnet",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					ResourceGroup: "cluster-test",
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{
+							DDoSProtectionPlan: &DDoSProtectionPlanSpec{},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					ResourceGroup: "cluster-test",
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{
+							ResourceGroup:      "cluster-test",
+							Name:               "cluster-test-vnet",
+							DDoSProtectionPlan: &DDoSProtectionPlanSpec{Name: "cluster-test-ddos-protection-plan"},
+							VnetClassSpec: VnetClassSpec{
+								CIDRBlocks: []string{DefaultVnetCIDR},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "existing DDoS protection plan",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					ResourceGroup: "cluster-test",
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{
+							DDoSProtectionPlan: &DDoSProtectionPlanSpec{ID: "my-plan-id"},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					ResourceGroup: "cluster-test",
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{
+							ResourceGroup:      "cluster-test",
+							Name:               "cluster-test-vnet",
+							DDoSProtectionPlan: &DDoSProtectionPlanSpec{ID: "my-plan-id"},
+							VnetClassSpec: VnetClassSpec{
+								CIDRBlocks: []string{DefaultVnetCIDR},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -336,9 +484,187 @@ func TestSubnetDefaults(t *testing.T) {
 								},
 								SecurityGroup: SecurityGroup{Name: "cluster-test-node-nsg"},
 								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
-								NatGateway: NatGateway{NatGatewayClassSpec: NatGatewayClassSpec{
-									Name: "cluster-test-node-natgw",
-								}},
+								NatGateway: NatGateway{
+									NatGatewayIP: PublicIPSpec{
+										Name: "pip-cluster-test-node-natgw",
+									},
+									NatGatewayClassSpec: NatGatewayClassSpec{
+										Name: "cluster-test-node-natgw",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "no nat gateway when node outbound lb is set",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NodeOutboundLB: &LoadBalancerSpec{},
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetNode,
+									CIDRBlocks: []string{"10.1.0.16/24"},
+									Name:       "my-node-subnet",
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NodeOutboundLB: &LoadBalancerSpec{},
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetNode,
+									CIDRBlocks: []string{"10.1.0.16/24"},
+									Name:       "my-node-subnet",
+								},
+								SecurityGroup: SecurityGroup{Name: "cluster-test-node-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+							},
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetControlPlane,
+									CIDRBlocks: []string{DefaultControlPlaneSubnetCIDR},
+									Name:       "cluster-test-controlplane-subnet",
+								},
+								SecurityGroup: SecurityGroup{Name: "cluster-test-controlplane-nsg"},
+								RouteTable:    RouteTable{},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "no cidr blocks defaulted for subnets claimed from an ip address pool",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NodeOutboundLB: &LoadBalancerSpec{},
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role: SubnetControlPlane,
+									Name: "my-controlplane-subnet",
+								},
+								CIDRBlockFromPool: &corev1.TypedLocalObjectReference{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "subnets"},
+							},
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role: SubnetNode,
+									Name: "my-node-subnet",
+								},
+								CIDRBlockFromPool: &corev1.TypedLocalObjectReference{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "subnets"},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NodeOutboundLB: &LoadBalancerSpec{},
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role: SubnetControlPlane,
+									Name: "my-controlplane-subnet",
+								},
+								CIDRBlockFromPool: &corev1.TypedLocalObjectReference{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "subnets"},
+								SecurityGroup:     SecurityGroup{Name: "cluster-test-controlplane-nsg"},
+								RouteTable:        RouteTable{},
+							},
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role: SubnetNode,
+									Name: "my-node-subnet",
+								},
+								CIDRBlockFromPool: &corev1.TypedLocalObjectReference{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "subnets"},
+								SecurityGroup:     SecurityGroup{Name: "cluster-test-node-nsg"},
+								RouteTable:        RouteTable{Name: "cluster-test-node-routetable"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "node subnet with an existing security group",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NodeOutboundLB: &LoadBalancerSpec{},
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetNode,
+									CIDRBlocks: []string{"10.1.0.16/24"},
+									Name:       "my-node-subnet",
+								},
+								SecurityGroup: SecurityGroup{
+									Existing: &ExistingSecurityGroup{
+										ID: "/subscriptions/123/resourceGroups/security-rg/providers/Microsoft.Network/networkSecurityGroups/shared-nsg",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NodeOutboundLB: &LoadBalancerSpec{},
+						Subnets: Subnets{
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetNode,
+									CIDRBlocks: []string{"10.1.0.16/24"},
+									Name:       "my-node-subnet",
+								},
+								SecurityGroup: SecurityGroup{
+									Name: "shared-nsg",
+									Existing: &ExistingSecurityGroup{
+										ID: "/subscriptions/123/resourceGroups/security-rg/providers/Microsoft.Network/networkSecurityGroups/shared-nsg",
+									},
+								},
+								RouteTable: RouteTable{Name: "cluster-test-node-routetable"},
+							},
+							{
+								SubnetClassSpec: SubnetClassSpec{
+									Role:       SubnetControlPlane,
+									CIDRBlocks: []string{DefaultControlPlaneSubnetCIDR},
+									Name:       "cluster-test-controlplane-subnet",
+								},
+								SecurityGroup: SecurityGroup{Name: "cluster-test-controlplane-nsg"},
+								RouteTable:    RouteTable{},
 							},
 						},
 					},
@@ -884,7 +1210,7 @@ func TestSubnetDefaults(t *testing.T) {
 								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
 								NatGateway: NatGateway{
 									NatGatewayIP: PublicIPSpec{
-										Name: "",
+										Name: "pip-cluster-test-node-natgw",
 									},
 									NatGatewayClassSpec: NatGatewayClassSpec{
 										Name: "cluster-test-node-natgw",
@@ -979,6 +1305,34 @@ func TestSubnetDefaults(t *testing.T) {
 	}
 }
 
+func TestNetworkResourceGroupDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+		Spec: AzureClusterSpec{
+			ResourceGroup:        "cluster-rg",
+			NetworkResourceGroup: "network-rg",
+			NetworkSpec: NetworkSpec{
+				Vnet: VnetSpec{
+					Peerings: VnetPeerings{
+						{VnetPeeringClassSpec: VnetPeeringClassSpec{RemoteVnetName: "hub-vnet"}},
+						{VnetPeeringClassSpec: VnetPeeringClassSpec{RemoteVnetName: "other-vnet", ResourceGroup: "other-rg"}},
+					},
+				},
+			},
+		},
+	}
+	cluster.setDefaults()
+
+	g.Expect(cluster.Spec.ResourceGroup).To(Equal("cluster-rg"))
+	g.Expect(cluster.Spec.NetworkSpec.Vnet.ResourceGroup).To(Equal("network-rg"))
+	g.Expect(cluster.Spec.NetworkSpec.Vnet.Peerings[0].ResourceGroup).To(Equal("network-rg"))
+	g.Expect(cluster.Spec.NetworkSpec.Vnet.Peerings[1].ResourceGroup).To(Equal("other-rg"))
+}
+
 func TestVnetPeeringDefaults(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -1149,6 +1503,113 @@ func TestAPIServerLBDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "public lb with an existing public IP",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							FrontendIPs: []FrontendIP{
+								{
+									Name: "cluster-test-public-lb-frontEnd",
+									PublicIP: &PublicIPSpec{
+										Existing: &ExistingPublicIP{ID: "/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/publicIPAddresses/byo-ip"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							Name: "cluster-test-public-lb",
+							FrontendIPs: []FrontendIP{
+								{
+									Name: "cluster-test-public-lb-frontEnd",
+									PublicIP: &PublicIPSpec{
+										Name:     "byo-ip",
+										Existing: &ExistingPublicIP{ID: "/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/publicIPAddresses/byo-ip"},
+									},
+								},
+							},
+							BackendPool: BackendPool{
+								Name: "cluster-test-public-lb-backendPool",
+							},
+							LoadBalancerClassSpec: LoadBalancerClassSpec{
+								SKU:                  SKUStandard,
+								Type:                 Public,
+								IdleTimeoutInMinutes: ptr.To[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "public lb with additional frontend IPs",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							FrontendIPsCount: ptr.To[int32](3),
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							Name: "cluster-test-public-lb",
+							FrontendIPs: []FrontendIP{
+								{
+									Name: "cluster-test-public-lb-frontEnd",
+									PublicIP: &PublicIPSpec{
+										Name: "pip-cluster-test-apiserver",
+									},
+								},
+								{
+									Name: "cluster-test-public-lb-frontEnd-2",
+									PublicIP: &PublicIPSpec{
+										Name: "pip-cluster-test-apiserver-2",
+									},
+								},
+								{
+									Name: "cluster-test-public-lb-frontEnd-3",
+									PublicIP: &PublicIPSpec{
+										Name: "pip-cluster-test-apiserver-3",
+									},
+								},
+							},
+							FrontendIPsCount: ptr.To[int32](3),
+							BackendPool: BackendPool{
+								Name: "cluster-test-public-lb-backendPool",
+							},
+							LoadBalancerClassSpec: LoadBalancerClassSpec{
+								SKU:                  SKUStandard,
+								Type:                 Public,
+								IdleTimeoutInMinutes: ptr.To[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "internal lb",
 			cluster: &AzureCluster{
@@ -1194,49 +1655,193 @@ func TestAPIServerLBDefaults(t *testing.T) {
 				},
 			},
 		},
-		{
-			name: "with custom backend pool name",
+		{
+			name: "internal lb by default without public network access",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					PublicNetworkAccess: PublicNetworkAccessDisabled,
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					PublicNetworkAccess: PublicNetworkAccessDisabled,
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							FrontendIPs: []FrontendIP{
+								{
+									Name: "cluster-test-internal-lb-frontEnd",
+									FrontendIPClass: FrontendIPClass{
+										PrivateIPAddress: DefaultInternalLBIPAddress,
+									},
+								},
+							},
+							BackendPool: BackendPool{
+								Name: "cluster-test-internal-lb-backendPool",
+							},
+							LoadBalancerClassSpec: LoadBalancerClassSpec{
+								SKU:                  SKUStandard,
+								Type:                 Internal,
+								IdleTimeoutInMinutes: ptr.To[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
+							},
+							Name: "cluster-test-internal-lb",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "with custom backend pool name",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							LoadBalancerClassSpec: LoadBalancerClassSpec{
+								Type: Internal,
+							},
+							BackendPool: BackendPool{
+								Name: "custom-backend-pool",
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							FrontendIPs: []FrontendIP{
+								{
+									Name: "cluster-test-internal-lb-frontEnd",
+									FrontendIPClass: FrontendIPClass{
+										PrivateIPAddress: DefaultInternalLBIPAddress,
+									},
+								},
+							},
+							BackendPool: BackendPool{
+								Name: "custom-backend-pool",
+							},
+							LoadBalancerClassSpec: LoadBalancerClassSpec{
+								SKU:                  SKUStandard,
+								Type:                 Internal,
+								IdleTimeoutInMinutes: ptr.To[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
+							},
+							Name: "cluster-test-internal-lb",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.cluster.setAPIServerLBDefaults()
+			if !reflect.DeepEqual(tc.cluster, tc.output) {
+				expected, _ := json.MarshalIndent(tc.output, "", "\t")
+				actual, _ := json.MarshalIndent(tc.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestAPIServerDNSRecordDefaults(t *testing.T) {
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no DNS record": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					ResourceGroup: "my-rg",
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					ResourceGroup: "my-rg",
+				},
+			},
+		},
+		"default DNS record": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					NetworkSpec: NetworkSpec{
+						APIServerDNSRecord: &DNSRecordSpec{
+							ZoneName: "example.com",
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					NetworkSpec: NetworkSpec{
+						APIServerDNSRecord: &DNSRecordSpec{
+							ZoneName:          "example.com",
+							ZoneResourceGroup: "my-rg",
+							RecordName:        "foo",
+							TTL:               ptr.To[int64](DefaultDNSRecordTTL),
+						},
+					},
+				},
+			},
+		},
+		"don't change user provided values": {
 			cluster: &AzureCluster{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: "cluster-test",
+					Name: "foo",
 				},
 				Spec: AzureClusterSpec{
+					ResourceGroup: "my-rg",
 					NetworkSpec: NetworkSpec{
-						APIServerLB: LoadBalancerSpec{
-							LoadBalancerClassSpec: LoadBalancerClassSpec{
-								Type: Internal,
-							},
-							BackendPool: BackendPool{
-								Name: "custom-backend-pool",
-							},
+						APIServerDNSRecord: &DNSRecordSpec{
+							ZoneName:          "example.com",
+							ZoneResourceGroup: "dns-rg",
+							RecordName:        "api",
+							TTL:               ptr.To[int64](60),
 						},
 					},
 				},
 			},
 			output: &AzureCluster{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: "cluster-test",
+					Name: "foo",
 				},
 				Spec: AzureClusterSpec{
+					ResourceGroup: "my-rg",
 					NetworkSpec: NetworkSpec{
-						APIServerLB: LoadBalancerSpec{
-							FrontendIPs: []FrontendIP{
-								{
-									Name: "cluster-test-internal-lb-frontEnd",
-									FrontendIPClass: FrontendIPClass{
-										PrivateIPAddress: DefaultInternalLBIPAddress,
-									},
-								},
-							},
-							BackendPool: BackendPool{
-								Name: "custom-backend-pool",
-							},
-							LoadBalancerClassSpec: LoadBalancerClassSpec{
-								SKU:                  SKUStandard,
-								Type:                 Internal,
-								IdleTimeoutInMinutes: ptr.To[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
-							},
-							Name: "cluster-test-internal-lb",
+						APIServerDNSRecord: &DNSRecordSpec{
+							ZoneName:          "example.com",
+							ZoneResourceGroup: "dns-rg",
+							RecordName:        "api",
+							TTL:               ptr.To[int64](60),
 						},
 					},
 				},
@@ -1244,14 +1849,14 @@ func TestAPIServerLBDefaults(t *testing.T) {
 		},
 	}
 
-	for _, c := range cases {
-		tc := c
-		t.Run(tc.name, func(t *testing.T) {
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			tc.cluster.setAPIServerLBDefaults()
-			if !reflect.DeepEqual(tc.cluster, tc.output) {
-				expected, _ := json.MarshalIndent(tc.output, "", "\t")
-				actual, _ := json.MarshalIndent(tc.cluster, "", "\t")
+			c.cluster.setAPIServerDNSRecordDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
 				t.Errorf("Expected %s, got %s", string(expected), string(actual))
 			}
 		})
@@ -2007,6 +2612,31 @@ func TestNodeOutboundLBDefaults(t *testing.T) {
 	}
 }
 
+func TestNodeOutboundLBDefaultsWithNATGatewayDisabled(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.NATGateway, false)()
+	g := NewWithT(t)
+
+	cluster := &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster-test",
+		},
+		Spec: AzureClusterSpec{
+			NetworkSpec: NetworkSpec{
+				APIServerLB: LoadBalancerSpec{LoadBalancerClassSpec: LoadBalancerClassSpec{Type: Public}},
+				Vnet:        VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{DefaultVnetCIDR}}},
+			},
+		},
+	}
+	cluster.setSubnetDefaults()
+	cluster.SetNodeOutboundLBDefaults()
+
+	for _, subnet := range cluster.Spec.NetworkSpec.Subnets {
+		g.Expect(subnet.IsNatGatewayEnabled()).To(BeFalse())
+	}
+	g.Expect(cluster.Spec.NetworkSpec.NodeOutboundLB).NotTo(BeNil())
+	g.Expect(cluster.Spec.NetworkSpec.NodeOutboundLB.Name).To(Equal("cluster-test"))
+}
+
 func TestControlPlaneOutboundLBDefaults(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -2418,3 +3048,558 @@ func TestBastionDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultSubnetCIDR(t *testing.T) {
+	cases := []struct {
+		name      string
+		vnetCIDRs []string
+		index     int
+		fallback  string
+		expected  string
+	}{
+		{
+			name:      "default vnet, control plane subnet",
+			vnetCIDRs: []string{DefaultVnetCIDR},
+			index:     0,
+			fallback:  "fallback",
+			expected:  DefaultControlPlaneSubnetCIDR,
+		},
+		{
+			name:      "default vnet, first node subnet",
+			vnetCIDRs: []string{DefaultVnetCIDR},
+			index:     1,
+			fallback:  "fallback",
+			expected:  DefaultNodeSubnetCIDR,
+		},
+		{
+			name:      "custom vnet, third node subnet",
+			vnetCIDRs: []string{"192.168.0.0/16"},
+			index:     3,
+			fallback:  "fallback",
+			expected:  "192.168.3.0/24",
+		},
+		{
+			name:      "skips IPv6 vnet CIDR blocks",
+			vnetCIDRs: []string{"2001:1234:5678:9a00::/56", "172.16.0.0/12"},
+			index:     2,
+			fallback:  "fallback",
+			expected:  "172.16.32.0/20",
+		},
+		{
+			name:      "vnet too small to split",
+			vnetCIDRs: []string{"192.168.0.0/24"},
+			index:     1,
+			fallback:  "fallback",
+			expected:  "fallback",
+		},
+		{
+			name:      "index out of range",
+			vnetCIDRs: []string{DefaultVnetCIDR},
+			index:     256,
+			fallback:  "fallback",
+			expected:  "fallback",
+		},
+		{
+			name:      "invalid vnet CIDR",
+			vnetCIDRs: []string{"not-a-cidr"},
+			index:     1,
+			fallback:  "fallback",
+			expected:  "fallback",
+		},
+	}
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			g.Expect(defaultSubnetCIDR(tc.vnetCIDRs, tc.index, tc.fallback)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestDefaultBastionSubnetCIDR(t *testing.T) {
+	cases := []struct {
+		name      string
+		vnetCIDRs []string
+		expected  string
+	}{
+		{
+			name:      "default vnet",
+			vnetCIDRs: []string{DefaultVnetCIDR},
+			expected:  DefaultAzureBastionSubnetCIDR,
+		},
+		{
+			name:      "custom vnet",
+			vnetCIDRs: []string{"192.168.0.0/16"},
+			expected:  "192.168.255.224/27",
+		},
+		{
+			name:      "vnet too small",
+			vnetCIDRs: []string{"192.168.0.0/27"},
+			expected:  DefaultAzureBastionSubnetCIDR,
+		},
+		{
+			name:      "no vnet CIDR blocks",
+			vnetCIDRs: nil,
+			expected:  DefaultAzureBastionSubnetCIDR,
+		},
+	}
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			g.Expect(defaultBastionSubnetCIDR(tc.vnetCIDRs)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestDefaultGatewaySubnetCIDR(t *testing.T) {
+	cases := []struct {
+		name      string
+		vnetCIDRs []string
+		expected  string
+	}{
+		{
+			name:      "default vnet",
+			vnetCIDRs: []string{DefaultVnetCIDR},
+			expected:  DefaultGatewaySubnetCIDR,
+		},
+		{
+			name:      "custom vnet",
+			vnetCIDRs: []string{"192.168.0.0/16"},
+			expected:  "192.168.255.192/27",
+		},
+		{
+			name:      "vnet too small",
+			vnetCIDRs: []string{"192.168.0.0/26"},
+			expected:  DefaultGatewaySubnetCIDR,
+		},
+	}
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			g.Expect(defaultGatewaySubnetCIDR(tc.vnetCIDRs)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestGatewayDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: AzureClusterSpec{
+			NetworkSpec: NetworkSpec{
+				Vnet: VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"192.168.0.0/16"}}},
+				Gateway: &GatewaySpec{
+					VirtualNetworkGateway: &VirtualNetworkGatewaySpec{Type: VirtualNetworkGatewayTypeExpressRoute},
+				},
+			},
+		},
+	}
+	cluster.setGatewayDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.Gateway).To(Equal(&GatewaySpec{
+		CIDRBlocks: []string{"192.168.255.192/27"},
+		VirtualNetworkGateway: &VirtualNetworkGatewaySpec{
+			Name:     "foo-vnet-gateway",
+			Type:     VirtualNetworkGatewayTypeExpressRoute,
+			SKU:      DefaultExpressRouteGatewaySKU,
+			PublicIP: PublicIPSpec{Name: "foo-vnet-gateway-pip"},
+		},
+	}))
+
+	// Values set by the user are kept.
+	cluster.Spec.NetworkSpec.Gateway = &GatewaySpec{
+		CIDRBlocks: []string{"192.168.254.0/27"},
+		VirtualNetworkGateway: &VirtualNetworkGatewaySpec{
+			Name:     "my-gateway",
+			Type:     VirtualNetworkGatewayTypeVpn,
+			SKU:      "VpnGw2AZ",
+			PublicIP: PublicIPSpec{Name: "my-gateway-pip"},
+		},
+	}
+	expected := cluster.Spec.NetworkSpec.Gateway.DeepCopy()
+	cluster.setGatewayDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.Gateway).To(Equal(expected))
+
+	// Without a virtual network gateway, only the GatewaySubnet is defaulted.
+	cluster.Spec.NetworkSpec.Gateway = &GatewaySpec{}
+	cluster.setGatewayDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.Gateway).To(Equal(&GatewaySpec{CIDRBlocks: []string{"192.168.255.192/27"}}))
+}
+
+func TestDefaultApplicationGatewaySubnetCIDR(t *testing.T) {
+	cases := []struct {
+		name      string
+		vnetCIDRs []string
+		expected  string
+	}{
+		{
+			name:      "default vnet",
+			vnetCIDRs: []string{DefaultVnetCIDR},
+			expected:  DefaultApplicationGatewaySubnetCIDR,
+		},
+		{
+			name:      "custom vnet",
+			vnetCIDRs: []string{"192.168.0.0/16"},
+			expected:  "192.168.255.128/26",
+		},
+		{
+			name:      "vnet too small",
+			vnetCIDRs: []string{"192.168.0.0/25"},
+			expected:  DefaultApplicationGatewaySubnetCIDR,
+		},
+	}
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			g.Expect(defaultApplicationGatewaySubnetCIDR(tc.vnetCIDRs)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestApplicationGatewayDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: AzureClusterSpec{
+			NetworkSpec: NetworkSpec{
+				Vnet: VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"192.168.0.0/16"}}},
+			},
+			ApplicationGateway: &ApplicationGatewaySpec{
+				WAF: &WebApplicationFirewallSpec{},
+			},
+		},
+	}
+	cluster.setApplicationGatewayDefaults()
+	g.Expect(cluster.Spec.ApplicationGateway).To(Equal(&ApplicationGatewaySpec{
+		Name:       "foo-appgw",
+		SubnetName: "foo-appgw-subnet",
+		CIDRBlocks: []string{"192.168.255.128/26"},
+		PublicIP:   PublicIPSpec{Name: "foo-appgw-pip"},
+		WAF: &WebApplicationFirewallSpec{
+			Mode:           WebApplicationFirewallModeDetection,
+			RuleSetVersion: DefaultWAFRuleSetVersion,
+		},
+	}))
+
+	// Values set by the user are kept.
+	cluster.Spec.ApplicationGateway = &ApplicationGatewaySpec{
+		Name:        "my-appgw",
+		SubnetName:  "my-appgw-subnet",
+		CIDRBlocks:  []string{"192.168.254.0/24"},
+		PublicIP:    PublicIPSpec{Name: "my-appgw-pip"},
+		MinCapacity: 2,
+		MaxCapacity: ptr.To[int32](4),
+		WAF: &WebApplicationFirewallSpec{
+			Mode:           WebApplicationFirewallModePrevention,
+			RuleSetVersion: "3.1",
+		},
+	}
+	expected := cluster.Spec.ApplicationGateway.DeepCopy()
+	cluster.setApplicationGatewayDefaults()
+	g.Expect(cluster.Spec.ApplicationGateway).To(Equal(expected))
+}
+
+func TestFlowLogsDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: AzureClusterSpec{
+			AzureClusterClassSpec: AzureClusterClassSpec{Location: "westus"},
+			NetworkSpec: NetworkSpec{
+				FlowLogs: &FlowLogsSpec{
+					StorageAccountID: "my-storage-account-id",
+					TrafficAnalytics: &TrafficAnalyticsSpec{
+						WorkspaceResourceID: "my-workspace-resource-id",
+						WorkspaceID:         "my-workspace-id",
+					},
+				},
+			},
+		},
+	}
+	cluster.setFlowLogsDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.FlowLogs).To(Equal(&FlowLogsSpec{
+		StorageAccountID: "my-storage-account-id",
+		TrafficAnalytics: &TrafficAnalyticsSpec{
+			WorkspaceResourceID: "my-workspace-resource-id",
+			WorkspaceID:         "my-workspace-id",
+			WorkspaceRegion:     "westus",
+			IntervalInMinutes:   60,
+		},
+		NetworkWatcher: NetworkWatcherReference{
+			Name:          "NetworkWatcher_westus",
+			ResourceGroup: "NetworkWatcherRG",
+		},
+	}))
+
+	// Values set by the user are kept.
+	cluster.Spec.NetworkSpec.FlowLogs = &FlowLogsSpec{
+		StorageAccountID: "my-storage-account-id",
+		RetentionDays:    30,
+		TrafficAnalytics: &TrafficAnalyticsSpec{
+			WorkspaceResourceID: "my-workspace-resource-id",
+			WorkspaceID:         "my-workspace-id",
+			WorkspaceRegion:     "eastus",
+			IntervalInMinutes:   10,
+		},
+		NetworkWatcher: NetworkWatcherReference{
+			Name:          "my-network-watcher",
+			ResourceGroup: "my-network-watcher-rg",
+		},
+	}
+	expected := cluster.Spec.NetworkSpec.FlowLogs.DeepCopy()
+	cluster.setFlowLogsDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.FlowLogs).To(Equal(expected))
+}
+
+func TestEgressProxyDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &AzureCluster{
+		Spec: AzureClusterSpec{
+			NetworkSpec: NetworkSpec{
+				EgressProxy: &EgressProxySpec{},
+			},
+		},
+	}
+	cluster.setEgressProxyDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.EgressProxy).To(Equal(&EgressProxySpec{Port: DefaultEgressProxyPort}))
+
+	cluster.Spec.NetworkSpec.EgressProxy = &EgressProxySpec{Port: 8133}
+	cluster.setEgressProxyDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.EgressProxy).To(Equal(&EgressProxySpec{Port: 8133}))
+
+	cluster.Spec.NetworkSpec.EgressProxy = nil
+	cluster.setEgressProxyDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.EgressProxy).To(BeNil())
+}
+
+func TestSecondaryRegionsDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: AzureClusterSpec{
+			SecondaryRegions: []SecondaryRegionSpec{
+				{
+					Location:   "eastus",
+					CIDRBlocks: []string{"172.16.0.0/16", "172.17.0.0/16"},
+				},
+				{
+					Location:   "westus",
+					VnetName:   "my-vnet",
+					CIDRBlocks: []string{"172.18.0.0/16"},
+					Subnets: []SecondaryRegionSubnetSpec{
+						{
+							Name:              "my-subnet",
+							CIDRBlocks:        []string{"172.18.1.0/24"},
+							SecurityGroupName: "my-nsg",
+							SecurityRules:     SecurityRules{{Name: "allow-ssh", Direction: SecurityRuleDirectionOutbound}},
+						},
+						{
+							Name:          "my-other-subnet",
+							CIDRBlocks:    []string{"172.18.2.0/24"},
+							SecurityRules: SecurityRules{{Name: "allow-https"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	cluster.setSecondaryRegionsDefaults()
+	g.Expect(cluster.Spec.SecondaryRegions).To(Equal([]SecondaryRegionSpec{
+		{
+			Location:   "eastus",
+			VnetName:   "foo-vnet-eastus",
+			CIDRBlocks: []string{"172.16.0.0/16", "172.17.0.0/16"},
+			Subnets: []SecondaryRegionSubnetSpec{
+				{
+					Name:              "foo-node-subnet-eastus",
+					CIDRBlocks:        []string{"172.16.0.0/16"},
+					SecurityGroupName: "foo-node-subnet-eastus-nsg",
+				},
+			},
+		},
+		{
+			Location:   "westus",
+			VnetName:   "my-vnet",
+			CIDRBlocks: []string{"172.18.0.0/16"},
+			Subnets: []SecondaryRegionSubnetSpec{
+				{
+					Name:              "my-subnet",
+					CIDRBlocks:        []string{"172.18.1.0/24"},
+					SecurityGroupName: "my-nsg",
+					SecurityRules:     SecurityRules{{Name: "allow-ssh", Direction: SecurityRuleDirectionOutbound}},
+				},
+				{
+					Name:              "my-other-subnet",
+					CIDRBlocks:        []string{"172.18.2.0/24"},
+					SecurityGroupName: "my-other-subnet-nsg",
+					SecurityRules:     SecurityRules{{Name: "allow-https", Direction: SecurityRuleDirectionInbound}},
+				},
+			},
+		},
+	}))
+}
+
+func TestLoadBalancerHealthProbeDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	lb := &LoadBalancerClassSpec{HealthProbe: &LoadBalancerHealthProbe{}}
+	lb.setAPIServerLBDefaults()
+	g.Expect(lb.HealthProbe).To(Equal(&LoadBalancerHealthProbe{
+		Protocol:          LoadBalancerProbeProtocolHTTPS,
+		RequestPath:       "/readyz",
+		IntervalInSeconds: ptr.To[int32](15),
+		NumberOfProbes:    ptr.To[int32](4),
+	}))
+
+	// Tcp probes have no request path.
+	lb.HealthProbe = &LoadBalancerHealthProbe{Protocol: LoadBalancerProbeProtocolTCP, ProbeThreshold: ptr.To[int32](2)}
+	lb.setAPIServerLBDefaults()
+	g.Expect(lb.HealthProbe).To(Equal(&LoadBalancerHealthProbe{
+		Protocol:          LoadBalancerProbeProtocolTCP,
+		IntervalInSeconds: ptr.To[int32](15),
+		NumberOfProbes:    ptr.To[int32](4),
+		ProbeThreshold:    ptr.To[int32](2),
+	}))
+
+	// No health probe is defaulted when none is set.
+	lb.HealthProbe = nil
+	lb.setAPIServerLBDefaults()
+	g.Expect(lb.HealthProbe).To(BeNil())
+}
+
+func TestAdditionalLBRulesDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	lb := &LoadBalancerClassSpec{AdditionalRules: []LoadBalancingRule{
+		{Name: "konnectivity", FrontendPort: 8132},
+		{Name: "https", Protocol: LoadBalancingRuleProtocolTCP, FrontendPort: 443, BackendPort: ptr.To[int32](6443)},
+	}}
+	lb.setAPIServerLBDefaults()
+	g.Expect(lb.AdditionalRules).To(Equal([]LoadBalancingRule{
+		{Name: "konnectivity", Protocol: LoadBalancingRuleProtocolTCP, FrontendPort: 8132, BackendPort: ptr.To[int32](8132)},
+		{Name: "https", Protocol: LoadBalancingRuleProtocolTCP, FrontendPort: 443, BackendPort: ptr.To[int32](6443)},
+	}))
+}
+
+func TestNodeInternalLBDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: AzureClusterSpec{
+			NetworkSpec: NetworkSpec{
+				Subnets: Subnets{
+					{SubnetClassSpec: SubnetClassSpec{Role: SubnetControlPlane, Name: "cp-subnet"}},
+					{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "node-subnet"}},
+				},
+				NodeInternalLB: &NodeInternalLBSpec{
+					LoadBalancerSpec: LoadBalancerSpec{
+						LoadBalancerClassSpec: LoadBalancerClassSpec{
+							HealthProbe: &LoadBalancerHealthProbe{Port: ptr.To[int32](30080)},
+						},
+					},
+				},
+			},
+		},
+	}
+	cluster.setNodeInternalLBDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.NodeInternalLB).To(Equal(&NodeInternalLBSpec{
+		LoadBalancerSpec: LoadBalancerSpec{
+			Name:        "foo-node-internal-lb",
+			FrontendIPs: []FrontendIP{{Name: "foo-node-internal-lb-frontEnd"}},
+			BackendPool: BackendPool{Name: "foo-node-internal-lb-backendPool"},
+			LoadBalancerClassSpec: LoadBalancerClassSpec{
+				SKU:                  SKUStandard,
+				Type:                 Internal,
+				IdleTimeoutInMinutes: ptr.To[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
+				HealthProbe: &LoadBalancerHealthProbe{
+					Protocol:          LoadBalancerProbeProtocolTCP,
+					Port:              ptr.To[int32](30080),
+					IntervalInSeconds: ptr.To[int32](15),
+					NumberOfProbes:    ptr.To[int32](4),
+				},
+			},
+		},
+		SubnetName: "node-subnet",
+	}))
+
+	// No node internal LB is defaulted when none is set.
+	cluster.Spec.NetworkSpec.NodeInternalLB = nil
+	cluster.setNodeInternalLBDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.NodeInternalLB).To(BeNil())
+}
+
+func TestDefaultInternalLBIPAddress(t *testing.T) {
+	cases := []struct {
+		name        string
+		subnetCIDRs []string
+		expected    string
+	}{
+		{
+			name:        "default control plane subnet",
+			subnetCIDRs: []string{DefaultControlPlaneSubnetCIDR},
+			expected:    DefaultInternalLBIPAddress,
+		},
+		{
+			name:        "custom control plane subnet",
+			subnetCIDRs: []string{"192.168.0.0/24"},
+			expected:    "192.168.0.100",
+		},
+		{
+			name:        "control plane subnet too small",
+			subnetCIDRs: []string{"192.168.0.0/26"},
+			expected:    DefaultInternalLBIPAddress,
+		},
+	}
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			g.Expect(defaultInternalLBIPAddress(tc.subnetCIDRs)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestNetworkSpecDefaultsWithCustomVnetCIDR(t *testing.T) {
+	g := NewWithT(t)
+	cluster := &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-test"},
+		Spec: AzureClusterSpec{
+			NetworkSpec: NetworkSpec{
+				Vnet: VnetSpec{
+					VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"192.168.0.0/16"}},
+				},
+				APIServerLB: LoadBalancerSpec{
+					LoadBalancerClassSpec: LoadBalancerClassSpec{Type: Internal},
+				},
+			},
+			BastionSpec: BastionSpec{AzureBastion: &AzureBastion{}},
+		},
+	}
+	cluster.setNetworkSpecDefaults()
+
+	cpSubnet, err := cluster.Spec.NetworkSpec.GetControlPlaneSubnet()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cpSubnet.CIDRBlocks).To(Equal([]string{"192.168.0.0/24"}))
+	for _, subnet := range cluster.Spec.NetworkSpec.Subnets {
+		if subnet.Role == SubnetNode {
+			g.Expect(subnet.CIDRBlocks).To(Equal([]string{"192.168.1.0/24"}))
+		}
+	}
+	g.Expect(cluster.Spec.BastionSpec.AzureBastion.Subnet.CIDRBlocks).To(Equal([]string{"192.168.255.224/27"}))
+	g.Expect(cluster.Spec.NetworkSpec.APIServerLB.FrontendIPs).To(HaveLen(1))
+	g.Expect(cluster.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].PrivateIPAddress).To(Equal("192.168.0.100"))
+	g.Expect(validateNetworkSpec(cluster.Spec.NetworkSpec, NetworkSpec{}, field.NewPath("spec").Child("networkSpec"))).To(BeEmpty())
+}
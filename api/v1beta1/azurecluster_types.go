@@ -41,10 +41,39 @@ type AzureClusterSpec struct {
 	// +optional
 	ResourceGroup string `json:"resourceGroup,omitempty"`
 
+	// NetworkResourceGroup is the name of a resource group, distinct from ResourceGroup, in which the virtual network,
+	// subnets, network security groups and route tables of the cluster are created. It is typically owned by a
+	// networking team, so CAPZ neither creates nor deletes it, and it must exist before the cluster is created.
+	// The virtual network resource group and the resource group of vnet peerings default to it.
+	// If not set, network resources are created in the cluster resource group.
+	// +optional
+	NetworkResourceGroup string `json:"networkResourceGroup,omitempty"`
+
 	// BastionSpec encapsulates all things related to the Bastions in the cluster.
 	// +optional
 	BastionSpec BastionSpec `json:"bastionSpec,omitempty"`
 
+	// ApplicationGateway creates an application gateway, with a public IP and in a dedicated subnet of the virtual
+	// network, for the Application Gateway Ingress Controller (AGIC) of the workload cluster. Its resource IDs are
+	// reported in status.network.applicationGateway. It can be added to an existing cluster, but neither changed nor
+	// removed.
+	// +optional
+	ApplicationGateway *ApplicationGatewaySpec `json:"applicationGateway,omitempty"`
+
+	// PublicNetworkAccess is the policy for the public endpoints of the cluster. When Disabled, the API server load
+	// balancer defaults to Internal and any configuration creating a public IP is rejected, namely a public API server
+	// load balancer, outbound load balancers, NAT gateways, Azure Bastion, a virtual network gateway, an application
+	// gateway and machines allocating a public IP. Defaults to Enabled. It cannot be changed after cluster creation.
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	// +optional
+	PublicNetworkAccess PublicNetworkAccess `json:"publicNetworkAccess,omitempty"`
+
+	// SecondaryRegions are regions other than the location of the cluster where a virtual network, its subnets and
+	// their network security groups are pre-provisioned and peered with the virtual network of the cluster, for disaster
+	// recovery topologies. Regions can be added to an existing cluster, but neither changed nor removed.
+	// +optional
+	SecondaryRegions []SecondaryRegionSpec `json:"secondaryRegions,omitempty"`
+
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane. It is not recommended to set
 	// this when creating an AzureCluster as CAPZ will set this for you. However, if it is set, CAPZ will not change it.
 	// +optional
@@ -74,6 +103,41 @@ type AzureClusterStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+
+	// ResourceGroupManaged is true if the resource group of the cluster was created by CAPZ and is deleted along with
+	// the cluster, and false if it already existed and is left in place when the cluster is deleted. It is unset until
+	// the resource group is reconciled.
+	// +optional
+	ResourceGroupManaged *bool `json:"resourceGroupManaged,omitempty"`
+
+	// CostEstimate is the estimated monthly cost of the cluster, when cost estimation is enabled.
+	// +optional
+	CostEstimate *CostEstimate `json:"costEstimate,omitempty"`
+
+	// EncryptionPosture summarizes the disk encryption settings of the AzureMachines and AzureMachinePools of the
+	// cluster.
+	// +optional
+	EncryptionPosture *EncryptionPosture `json:"encryptionPosture,omitempty"`
+
+	// ObservedGeneration is the generation of the AzureCluster spec last reconciled successfully.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ObservedSpecHash is the hash of the AzureCluster spec last reconciled successfully, leaving out its additional
+	// tags and those of its subnets. A change of the spec which keeps the same hash only changes tags, and is reconciled
+	// by updating the tags of the resources of the cluster without reconciling the resources themselves.
+	// +optional
+	ObservedSpecHash string `json:"observedSpecHash,omitempty"`
+
+	// Plan lists the Azure operations the last reconciliation in plan mode would have performed.
+	// It is only set while the AzureCluster carries the plan annotation.
+	// +optional
+	Plan *ReconcilePlan `json:"plan,omitempty"`
+
+	// Network describes the network resources of the cluster as resolved in Azure by the last successful
+	// reconciliation.
+	// +optional
+	Network *NetworkStatus `json:"network,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -85,6 +149,7 @@ type AzureClusterStatus struct {
 // +kubebuilder:printcolumn:name="SubscriptionID",type="string",priority=1,JSONPath=".spec.subscriptionID"
 // +kubebuilder:printcolumn:name="Location",type="string",priority=1,JSONPath=".spec.location"
 // +kubebuilder:printcolumn:name="Endpoint",type="string",priority=1,JSONPath=".spec.controlPlaneEndpoint.host",description="Control Plane Endpoint"
+// +kubebuilder:printcolumn:name="Monthly Cost",type="string",priority=1,JSONPath=".status.costEstimate.monthlyCost",description="Estimated monthly cost of the cluster"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of this AzureCluster"
 // +kubebuilder:resource:path=azureclusters,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
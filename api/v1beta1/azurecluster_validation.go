@@ -21,14 +21,18 @@ import (
 	"net"
 	"reflect"
 	"regexp"
+	"slices"
+	"strings"
 
 	valid "github.com/asaskevich/govalidator"
+	"github.com/google/uuid"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
@@ -50,6 +54,20 @@ const (
 	MinLBIdleTimeoutInMinutes = 4
 	// MaxLBIdleTimeoutInMinutes is the maximum number of minutes for the LB idle timeout.
 	MaxLBIdleTimeoutInMinutes = 30
+	// APIServerLBRuleName is the name of the load balancing rule of the API server port.
+	APIServerLBRuleName = "LBRuleHTTPS"
+	// EgressProxyLBRuleName is the name of the load balancing rule of the egress proxy port.
+	EgressProxyLBRuleName = "LBRuleEgressProxy"
+	// SSHSecurityRuleName is the name of the default security rule of the control plane subnet allowing SSH.
+	SSHSecurityRuleName = "allow_ssh"
+	// APIServerSecurityRuleName is the name of the default security rule of the control plane subnet allowing the API
+	// server port.
+	APIServerSecurityRuleName = "allow_apiserver"
+	// ICMPv6SecurityRuleName is the name of the default security rule of dual-stack subnets allowing ICMPv6.
+	ICMPv6SecurityRuleName = "allow_icmpv6"
+	// EgressProxySecurityRuleName is the name of the security rule of the control plane subnet allowing the egress
+	// proxy port.
+	EgressProxySecurityRuleName = "allow_egress_proxy"
 	// Network security rules should be a number between 100 and 4096.
 	// https://learn.microsoft.com/azure/virtual-network/network-security-groups-overview#security-rules
 	minRulePriority = 100
@@ -67,6 +85,32 @@ const (
 var (
 	serviceEndpointServiceRegex  = regexp.MustCompile(serviceEndpointServiceRegexPattern)
 	serviceEndpointLocationRegex = regexp.MustCompile(serviceEndpointLocationRegexPattern)
+
+	// locationsWithoutAvailabilityZones lists the Azure public cloud regions that are known not to offer availability zones.
+	// See: https://learn.microsoft.com/azure/reliability/availability-zones-service-support#azure-regions-with-availability-zone-support
+	locationsWithoutAvailabilityZones = map[string]struct{}{
+		"australiacentral":   {},
+		"australiacentral2":  {},
+		"australiasoutheast": {},
+		"brazilsoutheast":    {},
+		"canadaeast":         {},
+		"francesouth":        {},
+		"germanynorth":       {},
+		"japanwest":          {},
+		"jioindiacentral":    {},
+		"jioindiawest":       {},
+		"koreasouth":         {},
+		"northcentralus":     {},
+		"norwaywest":         {},
+		"southafricawest":    {},
+		"southindia":         {},
+		"switzerlandwest":    {},
+		"uaecentral":         {},
+		"ukwest":             {},
+		"westcentralus":      {},
+		"westindia":          {},
+		"westus":             {},
+	}
 )
 
 // validateCluster validates a cluster.
@@ -89,8 +133,21 @@ func (c *AzureCluster) validateClusterSpec(old *AzureCluster) field.ErrorList {
 	var oldNetworkSpec NetworkSpec
 	if old != nil {
 		oldNetworkSpec = old.Spec.NetworkSpec
+		// While the control plane endpoint rotates, the API server load balancer is re-issued with new frontends,
+		// possibly of another type, so only its name, SKU and SSH NAT rules stay immutable.
+		if c.Annotations[RotateControlPlaneEndpointAnnotation] == "true" {
+			oldLB := oldNetworkSpec.APIServerLB
+			oldNetworkSpec.APIServerLB = LoadBalancerSpec{
+				Name: oldLB.Name,
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					SKU:                oldLB.SKU,
+					DisableSSHNATRules: oldLB.DisableSSHNATRules,
+				},
+			}
+		}
 	}
 	allErrs = append(allErrs, validateNetworkSpec(c.Spec.NetworkSpec, oldNetworkSpec, field.NewPath("spec").Child("networkSpec"))...)
+	allErrs = append(allErrs, c.validateNetworkResourceGroup()...)
 
 	var oldCloudProviderConfigOverrides *CloudProviderConfigOverrides
 	if old != nil {
@@ -104,10 +161,23 @@ func (c *AzureCluster) validateClusterSpec(old *AzureCluster) field.ErrorList {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "ExtendedLocation"), "can be set only if the EdgeZone feature flag is enabled"))
 	}
 
+	allErrs = append(allErrs, c.validateServiceFeatureGates(old)...)
+
+	allErrs = append(allErrs, c.validateZoneRedundantPublicIPs()...)
+
 	if err := validateBastionSpec(c.Spec.BastionSpec, field.NewPath("spec").Child("azureBastion").Child("bastionSpec")); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
+	allErrs = append(allErrs, validateApplicationGateway(c.Spec.ApplicationGateway, c.Spec.NetworkSpec, field.NewPath("spec", "applicationGateway"),
+		field.NewPath("spec", "networkSpec"))...)
+
+	allErrs = append(allErrs, c.validatePublicNetworkAccess()...)
+
+	allErrs = append(allErrs, c.validateSecondaryRegions(field.NewPath("spec", "secondaryRegions"))...)
+
+	allErrs = append(allErrs, validateSSHPublicKeys(c.Spec.SSHPublicKeys, field.NewPath("spec", "sshPublicKeys"))...)
+
 	if err := validateIdentityRef(c.Spec.IdentityRef, field.NewPath("spec").Child("identityRef")); err != nil {
 		allErrs = append(allErrs, err)
 	}
@@ -115,6 +185,208 @@ func (c *AzureCluster) validateClusterSpec(old *AzureCluster) field.ErrorList {
 	return allErrs
 }
 
+// validateSSHPublicKeys validates the SSH public keys authorized on the machines of a cluster.
+func validateSSHPublicKeys(keys *SSHPublicKeysSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if keys == nil {
+		return allErrs
+	}
+	for i, key := range keys.ControlPlane {
+		allErrs = append(allErrs, ValidateSSHKey(key, fldPath.Child("controlPlane").Index(i))...)
+	}
+	for i, key := range keys.Nodes {
+		allErrs = append(allErrs, ValidateSSHKey(key, fldPath.Child("nodes").Index(i))...)
+	}
+	return allErrs
+}
+
+// validateServiceFeatureGates validates that the cluster does not add a bastion host or NAT gateway while their feature
+// gate is disabled. Services the cluster already had keep being allowed so that disabling a gate does not block updates.
+func (c *AzureCluster) validateServiceFeatureGates(old *AzureCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	if !feature.Gates.Enabled(feature.Bastion) && c.Spec.BastionSpec.AzureBastion != nil &&
+		(old == nil || old.Spec.BastionSpec.AzureBastion == nil) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "bastionSpec", "azureBastion"), "can be set only if the Bastion feature flag is enabled"))
+	}
+
+	if !feature.Gates.Enabled(feature.NATGateway) {
+		oldNatGateways := make(map[string]bool)
+		if old != nil {
+			for _, subnet := range old.Spec.NetworkSpec.Subnets {
+				if subnet.IsNatGatewayEnabled() {
+					oldNatGateways[subnet.Name] = true
+				}
+			}
+		}
+		for i, subnet := range c.Spec.NetworkSpec.Subnets {
+			if subnet.IsNatGatewayEnabled() && !oldNatGateways[subnet.Name] {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "networkSpec", "subnets").Index(i).Child("natGateway"), "can be set only if the NATGateway feature flag is enabled"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateSecondaryRegions validates the secondary regions of a cluster: their virtual networks must not overlap the
+// virtual network of the cluster or each other, and their subnets must lie within their virtual network.
+func (c *AzureCluster) validateSecondaryRegions(fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(c.Spec.SecondaryRegions) == 0 {
+		return allErrs
+	}
+
+	type vnetNetwork struct {
+		path *field.Path
+		cidr string
+		nw   *net.IPNet
+	}
+	var vnetNws []vnetNetwork
+	for _, cidr := range c.Spec.NetworkSpec.Vnet.CIDRBlocks {
+		if _, nw, err := net.ParseCIDR(cidr); err == nil {
+			vnetNws = append(vnetNws, vnetNetwork{path: field.NewPath("spec", "networkSpec", "vnet"), cidr: cidr, nw: nw})
+		}
+	}
+	locations := map[string]bool{strings.ToLower(c.Spec.Location): true}
+	vnetNames := map[string]bool{c.Spec.NetworkSpec.Vnet.Name: true}
+	subnetNames := make(map[string]bool, len(c.Spec.NetworkSpec.Subnets))
+	for _, subnet := range c.Spec.NetworkSpec.Subnets {
+		subnetNames[subnet.Name] = true
+	}
+
+	for i, region := range c.Spec.SecondaryRegions {
+		regionPath := fldPath.Index(i)
+		if region.Location == "" {
+			allErrs = append(allErrs, field.Required(regionPath.Child("location"), "location is required"))
+		} else if locations[strings.ToLower(region.Location)] {
+			allErrs = append(allErrs, field.Duplicate(regionPath.Child("location"), region.Location))
+		}
+		locations[strings.ToLower(region.Location)] = true
+
+		if region.VnetName != "" && vnetNames[region.VnetName] {
+			allErrs = append(allErrs, field.Duplicate(regionPath.Child("vnetName"), region.VnetName))
+		}
+		vnetNames[region.VnetName] = true
+
+		cidrPath := regionPath.Child("cidrBlocks")
+		allErrs = append(allErrs, validateVnetCIDR(region.CIDRBlocks, cidrPath)...)
+		for _, cidr := range region.CIDRBlocks {
+			_, nw, err := net.ParseCIDR(cidr)
+			if err != nil {
+				continue
+			}
+			for _, other := range vnetNws {
+				if other.nw.Contains(nw.IP) || nw.Contains(other.nw.IP) {
+					allErrs = append(allErrs, field.Invalid(cidrPath, cidr, fmt.Sprintf("vnet CIDR overlaps with CIDR %s of %s", other.cidr, other.path)))
+				}
+			}
+		}
+		for _, cidr := range region.CIDRBlocks {
+			if _, nw, err := net.ParseCIDR(cidr); err == nil {
+				vnetNws = append(vnetNws, vnetNetwork{path: regionPath, cidr: cidr, nw: nw})
+			}
+		}
+
+		subnetsPath := regionPath.Child("subnets")
+		subnetCidrBlocks := make([][]string, len(region.Subnets))
+		for j, subnet := range region.Subnets {
+			subnetPath := subnetsPath.Index(j)
+			if err := validateSubnetName(subnet.Name, subnetPath.Child("name")); err != nil {
+				allErrs = append(allErrs, err)
+			}
+			// Subnets are tracked by name in the cluster spec, so their names must be unique across all the regions.
+			if subnetNames[subnet.Name] {
+				allErrs = append(allErrs, field.Duplicate(subnetPath.Child("name"), subnet.Name))
+			}
+			subnetNames[subnet.Name] = true
+			allErrs = append(allErrs, validateSubnetCIDR(subnet.CIDRBlocks, region.CIDRBlocks, subnetPath.Child("cidrBlocks"))...)
+			for k, rule := range subnet.SecurityRules {
+				if err := validateSecurityRule(rule, subnetPath.Child("securityRules").Index(k)); err != nil {
+					allErrs = append(allErrs, err)
+				}
+			}
+			subnetCidrBlocks[j] = subnet.CIDRBlocks
+		}
+		allErrs = append(allErrs, validateSubnetCIDROverlaps(subnetCidrBlocks, subnetsPath)...)
+	}
+
+	return allErrs
+}
+
+// validatePublicNetworkAccess validates that a cluster without public network access creates no public IP.
+func (c *AzureCluster) validatePublicNetworkAccess() field.ErrorList {
+	var allErrs field.ErrorList
+	if c.Spec.PublicNetworkAccess != PublicNetworkAccessDisabled {
+		return nil
+	}
+	detail := fmt.Sprintf("creates a public IP, which is not allowed when spec.publicNetworkAccess is %s", PublicNetworkAccessDisabled)
+	networkSpecPath := field.NewPath("spec", "networkSpec")
+	if c.Spec.NetworkSpec.APIServerLB.Type == Public {
+		allErrs = append(allErrs, field.Forbidden(networkSpecPath.Child("apiServerLB", "type"), "a Public API server load balancer "+detail))
+	}
+	if c.Spec.NetworkSpec.NodeOutboundLB != nil {
+		allErrs = append(allErrs, field.Forbidden(networkSpecPath.Child("nodeOutboundLB"), "the node outbound load balancer "+detail))
+	}
+	if c.Spec.NetworkSpec.ControlPlaneOutboundLB != nil {
+		allErrs = append(allErrs, field.Forbidden(networkSpecPath.Child("controlPlaneOutboundLB"), "the control plane outbound load balancer "+detail))
+	}
+	for i, subnet := range c.Spec.NetworkSpec.Subnets {
+		if subnet.IsNatGatewayEnabled() {
+			allErrs = append(allErrs, field.Forbidden(networkSpecPath.Child("subnets").Index(i).Child("natGateway"), "a NAT gateway "+detail))
+		}
+	}
+	if gateway := c.Spec.NetworkSpec.Gateway; gateway != nil && gateway.VirtualNetworkGateway != nil {
+		allErrs = append(allErrs, field.Forbidden(networkSpecPath.Child("gateway", "virtualNetworkGateway"), "a virtual network gateway "+detail))
+	}
+	if c.Spec.BastionSpec.AzureBastion != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "bastionSpec", "azureBastion"), "Azure Bastion "+detail))
+	}
+	if c.Spec.ApplicationGateway != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "applicationGateway"), "an application gateway "+detail))
+	}
+	return allErrs
+}
+
+// validateZoneRedundantPublicIPs validates that zone-redundant public IPs can be created in the cluster's location.
+func (c *AzureCluster) validateZoneRedundantPublicIPs() field.ErrorList {
+	var allErrs field.ErrorList
+	if !c.Spec.ZoneRedundantPublicIPs {
+		return nil
+	}
+	fldPath := field.NewPath("spec", "zoneRedundantPublicIPs")
+	if c.Spec.ExtendedLocation != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath,
+			fmt.Sprintf("zone-redundant public IPs are not supported in edge zone %s, which has no availability zones", c.Spec.ExtendedLocation.Name)))
+	}
+	if _, ok := locationsWithoutAvailabilityZones[strings.ToLower(strings.ReplaceAll(c.Spec.Location, " ", ""))]; ok {
+		allErrs = append(allErrs, field.Forbidden(fldPath,
+			fmt.Sprintf("zone-redundant public IPs require a region with availability zones, but location %s has none", c.Spec.Location)))
+	}
+	return allErrs
+}
+
+// validateNetworkResourceGroup validates that the network resource group is distinct from the cluster resource group
+// and that it is the resource group of the virtual network.
+func (c *AzureCluster) validateNetworkResourceGroup() field.ErrorList {
+	var allErrs field.ErrorList
+	networkResourceGroup := c.Spec.NetworkResourceGroup
+	if networkResourceGroup == "" {
+		return nil
+	}
+	fldPath := field.NewPath("spec", "networkResourceGroup")
+	if err := validateResourceGroup(networkResourceGroup, fldPath); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if strings.EqualFold(networkResourceGroup, c.Spec.ResourceGroup) {
+		allErrs = append(allErrs, field.Invalid(fldPath, networkResourceGroup, "must be different from the cluster resource group"))
+	}
+	if vnetResourceGroup := c.Spec.NetworkSpec.Vnet.ResourceGroup; vnetResourceGroup != "" && !strings.EqualFold(vnetResourceGroup, networkResourceGroup) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "networkSpec", "vnet", "resourceGroup"), vnetResourceGroup,
+			"must be the network resource group when networkResourceGroup is set"))
+	}
+	return allErrs
+}
+
 // validateClusterName validates ClusterName.
 func (c *AzureCluster) validateClusterName() field.ErrorList {
 	var allErrs field.ErrorList
@@ -165,13 +437,15 @@ func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *fiel
 			allErrs = append(allErrs, err)
 		}
 
-		allErrs = append(allErrs, validateVnetCIDR(networkSpec.Vnet.CIDRBlocks, fldPath.Child("cidrBlocks"))...)
+		allErrs = append(allErrs, validateVnetCIDR(networkSpec.Vnet.CIDRBlocks, fldPath.Child("vnet").Child("cidrBlocks"))...)
 
 		allErrs = append(allErrs, validateSubnets(networkSpec.Subnets, networkSpec.Vnet, fldPath.Child("subnets"))...)
 
 		allErrs = append(allErrs, validateVnetPeerings(networkSpec.Vnet.Peerings, fldPath.Child("peerings"))...)
 	}
 
+	allErrs = append(allErrs, validateDDoSProtectionPlan(networkSpec.Vnet.DDoSProtectionPlan, fldPath.Child("vnet").Child("ddosProtectionPlan"))...)
+
 	var cidrBlocks []string
 	controlPlaneSubnet, err := networkSpec.GetControlPlaneSubnet()
 	if err != nil {
@@ -180,7 +454,7 @@ func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *fiel
 
 	cidrBlocks = controlPlaneSubnet.CIDRBlocks
 
-	allErrs = append(allErrs, validateAPIServerLB(networkSpec.APIServerLB, old.APIServerLB, cidrBlocks, fldPath.Child("apiServerLB"))...)
+	allErrs = append(allErrs, validateAPIServerLB(networkSpec.APIServerLB, old.APIServerLB, cidrBlocks, networkSpec.Subnets, fldPath.Child("apiServerLB"))...)
 
 	var needOutboundLB bool
 	for _, subnet := range networkSpec.Subnets {
@@ -189,14 +463,25 @@ func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *fiel
 			break
 		}
 	}
-	if needOutboundLB {
+	// A node outbound LB set explicitly, e.g. to give nodes of a private cluster internet egress, is validated too.
+	if needOutboundLB || networkSpec.NodeOutboundLB != nil {
 		allErrs = append(allErrs, validateNodeOutboundLB(networkSpec.NodeOutboundLB, old.NodeOutboundLB, networkSpec.APIServerLB, fldPath.Child("nodeOutboundLB"))...)
 	}
 
 	allErrs = append(allErrs, validateControlPlaneOutboundLB(networkSpec.ControlPlaneOutboundLB, networkSpec.APIServerLB, fldPath.Child("controlPlaneOutboundLB"))...)
 
+	allErrs = append(allErrs, validateNodeInternalLB(networkSpec, old.NodeInternalLB, fldPath.Child("nodeInternalLB"))...)
+
 	allErrs = append(allErrs, validatePrivateDNSZoneName(networkSpec.PrivateDNSZoneName, networkSpec.APIServerLB.Type, fldPath.Child("privateDNSZoneName"))...)
 
+	allErrs = append(allErrs, validateAPIServerDNSRecord(networkSpec.APIServerDNSRecord, networkSpec.APIServerLB.Type, fldPath.Child("apiServerDNSRecord"))...)
+
+	allErrs = append(allErrs, validateGateway(networkSpec.Gateway, old.Gateway, networkSpec.Subnets, networkSpec.Vnet, fldPath.Child("gateway"), fldPath.Child("subnets"))...)
+	allErrs = append(allErrs, validateFlowLogs(networkSpec.FlowLogs, fldPath.Child("flowLogs"))...)
+	allErrs = append(allErrs, validateDiagnosticSettings(networkSpec.DiagnosticSettings, fldPath.Child("diagnosticSettings"))...)
+	allErrs = append(allErrs, validateEgressProxy(networkSpec.EgressProxy, old.EgressProxy, networkSpec.APIServerLB, fldPath.Child("egressProxy"))...)
+	allErrs = append(allErrs, validateControlPlaneExtraRules(networkSpec.ControlPlaneExtraRules, controlPlaneSecurityRules(networkSpec, controlPlaneSubnet), fldPath.Child("controlPlaneExtraRules"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -243,6 +528,11 @@ func validateSubnets(subnets Subnets, vnet VnetSpec, fldPath *field.Path) field.
 			}
 		}
 		allErrs = append(allErrs, validateSubnetCIDR(subnet.CIDRBlocks, vnet.CIDRBlocks, fldPath.Index(i).Child("cidrBlocks"))...)
+		if subnet.CIDRBlockFromPool != nil {
+			allErrs = append(allErrs, validateIPAddressPoolRef(*subnet.CIDRBlockFromPool, fldPath.Index(i).Child("cidrBlockFromPool"))...)
+		}
+
+		allErrs = append(allErrs, validateExistingSecurityGroup(subnet.SecurityGroup, fldPath.Index(i).Child("securityGroup"))...)
 
 		if len(subnet.ServiceEndpoints) > 0 {
 			allErrs = append(allErrs, validateServiceEndpoints(subnet.ServiceEndpoints, fldPath.Index(i).Child("serviceEndpoints"))...)
@@ -258,6 +548,46 @@ func validateSubnets(subnets Subnets, vnet VnetSpec, fldPath *field.Path) field.
 				fmt.Sprintf("required role %s not included in provided subnets", k)))
 		}
 	}
+
+	subnetCidrBlocks := make([][]string, len(subnets))
+	for i, subnet := range subnets {
+		subnetCidrBlocks[i] = subnet.CIDRBlocks
+	}
+	allErrs = append(allErrs, validateSubnetCIDROverlaps(subnetCidrBlocks, fldPath)...)
+	return allErrs
+}
+
+// validateIPAddressPoolRef validates a reference to an IP address pool implementing the Cluster API IPAM contract.
+func validateIPAddressPoolRef(ref corev1.TypedLocalObjectReference, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if ptr.Deref(ref.APIGroup, "") == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("apiGroup"), "the API group of the IP address pool is required"))
+	}
+	if ref.Kind == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("kind"), "the kind of the IP address pool is required"))
+	}
+	if ref.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "the name of the IP address pool is required"))
+	}
+	return allErrs
+}
+
+// validateExistingSecurityGroup validates the reference of a subnet to an existing network security group.
+func validateExistingSecurityGroup(sg SecurityGroup, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if sg.Existing == nil {
+		return allErrs
+	}
+
+	resourceID, err := azureutil.ParseResourceID(sg.Existing.ID)
+	if err != nil || !strings.EqualFold(resourceID.ResourceType.String(), "Microsoft.Network/networkSecurityGroups") {
+		return append(allErrs, field.Invalid(fldPath.Child("existing", "id"), sg.Existing.ID, "id must be the resource ID of a network security group"))
+	}
+	if sg.Name != "" && sg.Name != resourceID.Name {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), sg.Name, "name must be the name of the existing network security group"))
+	}
+
 	return allErrs
 }
 
@@ -282,14 +612,15 @@ func validateSubnetCIDR(subnetCidrBlocks []string, vnetCidrBlocks []string, fldP
 	}
 
 	for _, subnetCidr := range subnetCidrBlocks {
-		subnetCidrIP, _, err := net.ParseCIDR(subnetCidr)
+		_, subnetNw, err := net.ParseCIDR(subnetCidr)
 		if err != nil {
 			allErrs = append(allErrs, field.Invalid(fldPath, subnetCidr, "invalid CIDR format"))
+			continue
 		}
 
 		var found bool
 		for _, vnetNw := range vnetNws {
-			if vnetNw.Contains(subnetCidrIP) {
+			if networkContains(vnetNw, subnetNw) {
 				found = true
 				break
 			}
@@ -303,6 +634,44 @@ func validateSubnetCIDR(subnetCidrBlocks []string, vnetCidrBlocks []string, fldP
 	return allErrs
 }
 
+// validateSubnetCIDROverlaps validates that the CIDR blocks of different subnets do not overlap.
+// subnetCidrBlocks holds the CIDR blocks of each subnet, in the order the subnets are listed.
+func validateSubnetCIDROverlaps(subnetCidrBlocks [][]string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	type subnetNetwork struct {
+		subnet int
+		cidr   string
+		nw     *net.IPNet
+	}
+	var seen []subnetNetwork
+
+	for i, cidrs := range subnetCidrBlocks {
+		for _, cidr := range cidrs {
+			_, nw, err := net.ParseCIDR(cidr)
+			if err != nil {
+				// Malformed CIDR blocks are reported by validateSubnetCIDR.
+				continue
+			}
+			for _, other := range seen {
+				if other.subnet != i && (other.nw.Contains(nw.IP) || nw.Contains(other.nw.IP)) {
+					allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("cidrBlocks"), cidr,
+						fmt.Sprintf("subnet CIDR overlaps with CIDR %s of %s", other.cidr, fldPath.Index(other.subnet))))
+				}
+			}
+			seen = append(seen, subnetNetwork{subnet: i, cidr: cidr, nw: nw})
+		}
+	}
+
+	return allErrs
+}
+
+// networkContains returns true if the whole inner network lies inside the outer network.
+func networkContains(outer, inner *net.IPNet) bool {
+	outerOnes, outerBits := outer.Mask.Size()
+	innerOnes, innerBits := inner.Mask.Size()
+	return outerBits == innerBits && outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
+
 // validateVnetCIDR validates the CIDR blocks of a Vnet.
 func validateVnetCIDR(vnetCIDRBlocks []string, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -339,7 +708,7 @@ func validateLoadBalancerName(name string, fldPath *field.Path) *field.Error {
 }
 
 // validateInternalLBIPAddress validates a InternalLBIPAddress.
-func validateInternalLBIPAddress(address string, cidrs []string, fldPath *field.Path) *field.Error {
+func validateInternalLBIPAddress(address string, cidrs []string, subnet string, fldPath *field.Path) *field.Error {
 	ip := net.ParseIP(address)
 	if ip == nil {
 		return field.Invalid(fldPath, address,
@@ -352,7 +721,7 @@ func validateInternalLBIPAddress(address string, cidrs []string, fldPath *field.
 		}
 	}
 	return field.Invalid(fldPath, address,
-		fmt.Sprintf("Internal LB IP address needs to be in control plane subnet range (%s)", cidrs))
+		fmt.Sprintf("Internal LB IP address needs to be in %s subnet range (%s)", subnet, cidrs))
 }
 
 // validateSecurityRule validates a SecurityRule.
@@ -361,10 +730,28 @@ func validateSecurityRule(rule SecurityRule, fldPath *field.Path) *field.Error {
 		return field.Invalid(fldPath, rule.Priority, fmt.Sprintf("security rule priorities should be between %d and %d", minRulePriority, maxRulePriority))
 	}
 
+	if rule.Protocol == SecurityGroupProtocolICMPv6 {
+		if isIPv4AddressPrefix(ptr.Deref(rule.Source, "")) {
+			return field.Invalid(fldPath.Child("source"), *rule.Source, "ICMPv6 security rules cannot match an IPv4 source")
+		}
+		if isIPv4AddressPrefix(ptr.Deref(rule.Destination, "")) {
+			return field.Invalid(fldPath.Child("destination"), *rule.Destination, "ICMPv6 security rules cannot match an IPv4 destination")
+		}
+	}
+
 	return nil
 }
 
-func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []string, fldPath *field.Path) field.ErrorList {
+// isIPv4AddressPrefix returns true if the address prefix is an IPv4 address or CIDR.
+func isIPv4AddressPrefix(prefix string) bool {
+	if ip, _, err := net.ParseCIDR(prefix); err == nil {
+		return ip.To4() != nil
+	}
+	ip := net.ParseIP(prefix)
+	return ip != nil && ip.To4() != nil
+}
+
+func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []string, subnets Subnets, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
 	lbClassSpec := lb.LoadBalancerClassSpec
@@ -380,35 +767,216 @@ func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []stri
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "API Server load balancer name should not be modified after AzureCluster creation."))
 	}
 
-	// There should only be one IP config.
-	if len(lb.FrontendIPs) != 1 || ptr.Deref[int32](lb.FrontendIPsCount, 1) != 1 {
+	// Public LBs can have additional frontend IPs for outbound connectivity, internal LBs can have additional private
+	// frontend IPs, e.g. one per zone, and are sized by their frontend IPs rather than by frontendIPsCount.
+	frontendIPsCount := ptr.Deref[int32](lb.FrontendIPsCount, 1)
+	if lb.Type == Public && (frontendIPsCount < 1 || frontendIPsCount > MaxLoadBalancerOutboundIPs) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPsCount"), frontendIPsCount,
+			fmt.Sprintf("API Server load balancer frontend IPs count should be between 1 and %d", MaxLoadBalancerOutboundIPs)))
+	} else if lb.Type == Public && len(lb.FrontendIPs) != int(frontendIPsCount) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPConfigs"), lb.FrontendIPs,
+			fmt.Sprintf("API Server load balancer should have %d Frontend IPs", frontendIPsCount)))
+	} else if lb.Type == Internal && (len(lb.FrontendIPs) == 0 || frontendIPsCount != 1) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPConfigs"), lb.FrontendIPs,
+			"Internal API Server load balancer should have at least 1 Frontend IP and no frontend IPs count"))
+	} else if lb.Type != Public && lb.Type != Internal && (len(lb.FrontendIPs) != 1 || frontendIPsCount != 1) {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPConfigs"), lb.FrontendIPs,
 			"API Server Load balancer should have 1 Frontend IP"))
 	} else {
-		// if Internal, IP config should not have a public IP.
+		// if Internal, IP configs should not have a public IP.
 		if lb.Type == Internal {
-			if lb.FrontendIPs[0].PublicIP != nil {
-				allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPConfigs").Index(0).Child("publicIP"),
-					"Internal Load Balancers cannot have a Public IP"))
-			}
-			if lb.FrontendIPs[0].PrivateIPAddress != "" {
-				if err := validateInternalLBIPAddress(lb.FrontendIPs[0].PrivateIPAddress, cidrs,
-					fldPath.Child("frontendIPConfigs").Index(0).Child("privateIP")); err != nil {
-					allErrs = append(allErrs, err)
+			allErrs = append(allErrs, validateInternalAPIServerFrontendIPs(lb.FrontendIPs, old.FrontendIPs, cidrs, subnets, fldPath.Child("frontendIPConfigs"))...)
+		}
+
+		// if Public, IP config should not have a private IP.
+		if lb.Type == Public {
+			for i, frontendIP := range lb.FrontendIPs {
+				if frontendIP.PrivateIPAddress != "" {
+					allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPConfigs").Index(i).Child("privateIP"),
+						"Public Load Balancers cannot have a Private IP"))
+				}
+				if len(frontendIP.Zones) != 0 {
+					allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPConfigs").Index(i).Child("zones"),
+						"Only frontend IPs of Internal Load Balancers can be pinned to zones"))
 				}
-				if len(old.FrontendIPs) != 0 && old.FrontendIPs[0].PrivateIPAddress != lb.FrontendIPs[0].PrivateIPAddress {
-					allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "API Server load balancer private IP should not be modified after AzureCluster creation."))
+				if frontendIP.SubnetName != "" {
+					allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPConfigs").Index(i).Child("subnetName"),
+						"Public Load Balancers cannot have a subnet"))
+				}
+				if i > 0 && frontendIP.PublicIP == nil {
+					allErrs = append(allErrs, field.Required(fldPath.Child("frontendIPConfigs").Index(i).Child("publicIP"),
+						"Additional frontend IPs of Public Load Balancers should have a Public IP"))
+				}
+				if i > 0 && frontendIP.PublicIP != nil && frontendIP.PublicIP.Existing != nil {
+					allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPConfigs").Index(i).Child("publicIP", "existing"),
+						"Only the first frontend IP of the API server load balancer can use an existing Public IP"))
 				}
 			}
 		}
+	}
 
-		// if Public, IP config should not have a private IP.
-		if lb.Type == Public {
-			if lb.FrontendIPs[0].PrivateIPAddress != "" {
-				allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPConfigs").Index(0).Child("privateIP"),
-					"Public Load Balancers cannot have a Private IP"))
+	if lb.Type == Public && len(lb.FrontendIPs) > 0 && lb.FrontendIPs[0].PublicIP != nil {
+		var oldPublicIP *PublicIPSpec
+		if len(old.FrontendIPs) > 0 {
+			oldPublicIP = old.FrontendIPs[0].PublicIP
+		}
+		allErrs = append(allErrs, validateExistingPublicIP(lb.FrontendIPs[0].PublicIP, oldPublicIP,
+			fldPath.Child("frontendIPConfigs").Index(0).Child("publicIP", "existing"))...)
+	}
+
+	// Frontend IPs are referenced by the outbound rule, so they can be added but not removed.
+	if old.FrontendIPsCount != nil && frontendIPsCount < *old.FrontendIPsCount {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPsCount"),
+			"API Server load balancer frontend IPs count cannot be decreased after AzureCluster creation."))
+	}
+
+	return allErrs
+}
+
+// validateInternalAPIServerFrontendIPs validates the private frontend IPs of an internal API server load balancer.
+// Each private IP must be in the range of its subnet, which defaults to the control plane subnet, and the frontend IPs
+// can be added but neither removed nor moved, as the API server endpoint and the load balancing rules reference them.
+func validateInternalAPIServerFrontendIPs(frontendIPs, old []FrontendIP, cidrs []string, subnets Subnets, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := make(map[string]bool, len(frontendIPs))
+	for i, frontendIP := range frontendIPs {
+		if names[frontendIP.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), frontendIP.Name))
+		}
+		names[frontendIP.Name] = true
+
+		if frontendIP.PublicIP != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("publicIP"),
+				"Internal Load Balancers cannot have a Public IP"))
+		}
+
+		subnetName, subnetCIDRs := "control plane", cidrs
+		if frontendIP.SubnetName != "" {
+			subnetName = frontendIP.SubnetName
+			subnetCIDRs = nil
+			found := false
+			for _, subnet := range subnets {
+				if subnet.Name == frontendIP.SubnetName {
+					subnetCIDRs, found = subnet.CIDRBlocks, true
+					break
+				}
+			}
+			if !found {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("subnetName"), frontendIP.SubnetName,
+					"Internal LB frontend IP subnet should be one of the subnets of the cluster"))
+				continue
 			}
 		}
+		if frontendIP.PrivateIPAddress != "" {
+			if err := validateInternalLBIPAddress(frontendIP.PrivateIPAddress, subnetCIDRs, subnetName,
+				fldPath.Index(i).Child("privateIP")); err != nil {
+				allErrs = append(allErrs, err)
+			}
+		}
+	}
+
+	for i, oldFrontendIP := range old {
+		j := slices.IndexFunc(frontendIPs, func(frontendIP FrontendIP) bool { return frontendIP.Name == oldFrontendIP.Name })
+		if j < 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i), "API Server load balancer frontend IPs cannot be removed after AzureCluster creation."))
+			continue
+		}
+		if oldFrontendIP.PrivateIPAddress != "" && oldFrontendIP.PrivateIPAddress != frontendIPs[j].PrivateIPAddress {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(j).Child("privateIP"), "API Server load balancer private IP should not be modified after AzureCluster creation."))
+		}
+		if oldFrontendIP.SubnetName != frontendIPs[j].SubnetName {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(j).Child("subnetName"), "API Server load balancer frontend IP subnet should not be modified after AzureCluster creation."))
+		}
+		if !slices.Equal(oldFrontendIP.Zones, frontendIPs[j].Zones) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(j).Child("zones"), "API Server load balancer frontend IP zones should not be modified after AzureCluster creation."))
+		}
+	}
+
+	return allErrs
+}
+
+// validateNodeInternalLB validates the internal load balancer of the worker nodes.
+func validateNodeInternalLB(networkSpec NetworkSpec, old *NodeInternalLBSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	lb := networkSpec.NodeInternalLB
+	if lb == nil {
+		return allErrs
+	}
+
+	if err := validateLoadBalancerName(lb.Name, fldPath.Child("name")); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	for _, other := range []*LoadBalancerSpec{&networkSpec.APIServerLB, networkSpec.NodeOutboundLB, networkSpec.ControlPlaneOutboundLB} {
+		if other != nil && other.Name != "" && other.Name == lb.Name {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), lb.Name, "Node internal load balancer cannot share another load balancer of the cluster."))
+		}
+	}
+	if lb.SKU != SKUStandard {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("sku"), lb.SKU, []string{string(SKUStandard)}))
+	}
+	if lb.Type != Internal {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), lb.Type, []string{string(Internal)}))
+	}
+	if lb.IdleTimeoutInMinutes != nil && (*lb.IdleTimeoutInMinutes < MinLBIdleTimeoutInMinutes || *lb.IdleTimeoutInMinutes > MaxLBIdleTimeoutInMinutes) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("idleTimeoutInMinutes"), *lb.IdleTimeoutInMinutes,
+			fmt.Sprintf("Node internal idle timeout should be between %d and %d minutes", MinLBIdleTimeoutInMinutes, MaxLBIdleTimeoutInMinutes)))
+	}
+
+	// The node internal load balancer has no API server port to fall back to.
+	allErrs = append(allErrs, validateLoadBalancerHealthProbe(lb.HealthProbe, fldPath.Child("healthProbe"))...)
+	if lb.HealthProbe != nil && lb.HealthProbe.Port == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("healthProbe", "port"), "port is required for the node internal load balancer health probe"))
+	}
+
+	var oldRules []LoadBalancingRule
+	if old != nil {
+		oldRules = old.AdditionalRules
+	}
+	allErrs = append(allErrs, validateAdditionalLBRules(lb.AdditionalRules, oldRules, fldPath.Child("additionalRules"))...)
+
+	if lb.DisableSSHNATRules {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("disableSSHNATRules"), "Node internal load balancer does not support SSH NAT rules."))
+	}
+
+	var subnet *SubnetSpec
+	for i := range networkSpec.Subnets {
+		if networkSpec.Subnets[i].Name == lb.SubnetName && networkSpec.Subnets[i].Role == SubnetNode {
+			subnet = &networkSpec.Subnets[i]
+		}
+	}
+	if subnet == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subnetName"), lb.SubnetName, "subnetName must be the name of a node subnet"))
+	}
+
+	if len(lb.FrontendIPs) != 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPs"), lb.FrontendIPs, "Node internal load balancer should have 1 Frontend IP"))
+	} else {
+		if lb.FrontendIPs[0].PublicIP != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPs").Index(0).Child("publicIP"),
+				"Internal Load Balancers cannot have a Public IP"))
+		}
+		if lb.FrontendIPs[0].PrivateIPAddress != "" && subnet != nil {
+			if err := validateInternalLBIPAddress(lb.FrontendIPs[0].PrivateIPAddress, subnet.CIDRBlocks, "node",
+				fldPath.Child("frontendIPs").Index(0).Child("privateIP")); err != nil {
+				allErrs = append(allErrs, err)
+			}
+		}
+	}
+
+	if old != nil {
+		if old.Name != lb.Name {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "Node internal load balancer name should not be modified after AzureCluster creation."))
+		}
+		if old.SubnetName != lb.SubnetName {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("subnetName"), "Node internal load balancer subnet should not be modified after AzureCluster creation."))
+		}
+		if !reflect.DeepEqual(old.FrontendIPs, lb.FrontendIPs) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPs"), "Node internal load balancer FrontendIPs cannot be modified after AzureCluster creation."))
+		}
+		if old.BackendPool.Name != lb.BackendPool.Name {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("backendPool", "name"), "Node internal load balancer backend pool should not be modified after AzureCluster creation."))
+		}
 	}
 
 	return allErrs
@@ -432,6 +1000,10 @@ func validateNodeOutboundLB(lb *LoadBalancerSpec, old *LoadBalancerSpec, apiserv
 		return allErrs
 	}
 
+	if lb.Name != "" && lb.Name == apiserverLB.Name {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), lb.Name, "Node outbound load balancer cannot share the API server load balancer."))
+	}
+
 	if old != nil && old.ID != lb.ID {
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("id"), "Node outbound load balancer ID should not be modified after AzureCluster creation."))
 	}
@@ -460,6 +1032,9 @@ func validateNodeOutboundLB(lb *LoadBalancerSpec, old *LoadBalancerSpec, apiserv
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPsCount"), *lb.FrontendIPsCount,
 			fmt.Sprintf("Max front end ips allowed is %d", MaxLoadBalancerOutboundIPs)))
 	}
+	if lb.FrontendIPsCount != nil && *lb.FrontendIPsCount < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPsCount"), *lb.FrontendIPsCount, "must be greater than or equal to 0"))
+	}
 
 	return allErrs
 }
@@ -480,6 +1055,9 @@ func validateControlPlaneOutboundLB(lb *LoadBalancerSpec, apiserverLB LoadBalanc
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPsCount"), *lb.FrontendIPsCount,
 				fmt.Sprintf("Max front end ips allowed is %d", MaxLoadBalancerOutboundIPs)))
 		}
+		if lb.FrontendIPsCount != nil && *lb.FrontendIPsCount < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPsCount"), *lb.FrontendIPsCount, "must be greater than or equal to 0"))
+		}
 	}
 
 	return allErrs
@@ -504,6 +1082,238 @@ func validatePrivateDNSZoneName(privateDNSZoneName string, apiserverLBType LBTyp
 	return allErrs
 }
 
+// validateAPIServerDNSRecord validates the DNS record of the API server.
+func validateAPIServerDNSRecord(record *DNSRecordSpec, apiServerLBType LBType, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if record == nil {
+		return allErrs
+	}
+
+	if apiServerLBType != Public {
+		allErrs = append(allErrs, field.Invalid(fldPath, record, "API server DNS record is available only if APIServerLB.Type is Public"))
+	}
+	if !valid.IsDNSName(record.ZoneName) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("zoneName"), record.ZoneName, "zoneName must be a valid DNS zone name"))
+	}
+	if record.RecordName != "" && record.RecordName != "@" && !valid.IsDNSName(record.RecordName) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("recordName"), record.RecordName, "recordName must be a valid relative DNS name or @"))
+	}
+
+	return allErrs
+}
+
+// validateDDoSProtectionPlan validates the DDoS protection plan of the virtual network.
+func validateDDoSProtectionPlan(plan *DDoSProtectionPlanSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// A DDoS protection plan is created when no id is given.
+	if plan == nil || plan.ID == "" {
+		return allErrs
+	}
+
+	if !isResourceIDOfType(plan.ID, "Microsoft.Network/ddosProtectionPlans") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("id"), plan.ID, "id must be the resource ID of a DDoS protection plan"))
+	}
+	if plan.Name != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "name cannot be set with id"))
+	}
+
+	return allErrs
+}
+
+// validateFlowLogs validates the NSG flow logs configuration.
+func validateFlowLogs(flowLogs *FlowLogsSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if flowLogs == nil {
+		return allErrs
+	}
+
+	if !isResourceIDOfType(flowLogs.StorageAccountID, "Microsoft.Storage/storageAccounts") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("storageAccountID"), flowLogs.StorageAccountID, "storageAccountID must be the resource ID of a storage account"))
+	}
+	if analytics := flowLogs.TrafficAnalytics; analytics != nil {
+		analyticsPath := fldPath.Child("trafficAnalytics")
+		if !isResourceIDOfType(analytics.WorkspaceResourceID, "Microsoft.OperationalInsights/workspaces") {
+			allErrs = append(allErrs, field.Invalid(analyticsPath.Child("workspaceResourceID"), analytics.WorkspaceResourceID, "workspaceResourceID must be the resource ID of a Log Analytics workspace"))
+		}
+		if _, err := uuid.Parse(analytics.WorkspaceID); err != nil {
+			allErrs = append(allErrs, field.Invalid(analyticsPath.Child("workspaceID"), analytics.WorkspaceID, "workspaceID must be a GUID"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateDiagnosticSettings validates the Azure Monitor diagnostic settings configuration.
+func validateDiagnosticSettings(diagnosticSettings *DiagnosticSettingsSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if diagnosticSettings == nil {
+		return allErrs
+	}
+
+	if !isResourceIDOfType(diagnosticSettings.WorkspaceResourceID, "Microsoft.OperationalInsights/workspaces") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("workspaceResourceID"), diagnosticSettings.WorkspaceResourceID, "workspaceResourceID must be the resource ID of a Log Analytics workspace"))
+	}
+	seen := make(map[DiagnosticLogCategory]bool, len(diagnosticSettings.Categories))
+	for i, category := range diagnosticSettings.Categories {
+		if seen[category] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("categories").Index(i), category))
+		}
+		seen[category] = true
+	}
+
+	return allErrs
+}
+
+// isResourceIDOfType returns true if id is a valid Azure resource ID of the given resource type.
+func isResourceIDOfType(id, resourceType string) bool {
+	resourceID, err := azureutil.ParseResourceID(id)
+	return err == nil && strings.EqualFold(resourceID.ResourceType.String(), resourceType)
+}
+
+// validateGateway validates the GatewaySubnet and the virtual network gateway.
+func validateGateway(gateway, old *GatewaySpec, subnets Subnets, vnet VnetSpec, fldPath, subnetsPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if gateway == nil {
+		return allErrs
+	}
+
+	for i, subnet := range subnets {
+		if subnet.Name == GatewaySubnetName {
+			allErrs = append(allErrs, field.Forbidden(subnetsPath.Index(i).Child("name"),
+				fmt.Sprintf("the %s subnet is configured by the gateway field", GatewaySubnetName)))
+		}
+	}
+
+	cidrPath := fldPath.Child("cidrBlocks")
+	allErrs = append(allErrs, validateSubnetCIDR(gateway.CIDRBlocks, vnet.CIDRBlocks, cidrPath)...)
+	for _, cidr := range gateway.CIDRBlocks {
+		if _, nw, err := net.ParseCIDR(cidr); err == nil {
+			if ones, bits := nw.Mask.Size(); bits == 32 && ones > 29 {
+				allErrs = append(allErrs, field.Invalid(cidrPath, cidr, "the GatewaySubnet must be /29 or larger"))
+			}
+		}
+	}
+	subnetCIDRBlocks := make([][]string, 0, len(subnets)+1)
+	for _, subnet := range subnets {
+		subnetCIDRBlocks = append(subnetCIDRBlocks, subnet.CIDRBlocks)
+	}
+	// The GatewaySubnet comes last, so its overlaps with other subnets are reported at its index.
+	gatewayField := subnetsPath.Index(len(subnets)).Child("cidrBlocks").String()
+	for _, err := range validateSubnetCIDROverlaps(append(subnetCIDRBlocks, gateway.CIDRBlocks), subnetsPath) {
+		if err.Field == gatewayField {
+			err.Field = cidrPath.String()
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	vng := gateway.VirtualNetworkGateway
+	if vng == nil {
+		return allErrs
+	}
+	if old != nil && old.VirtualNetworkGateway != nil && old.VirtualNetworkGateway.Type != vng.Type {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("virtualNetworkGateway", "type"), "the type of the virtual network gateway cannot be changed"))
+	}
+	if vng.PublicIP.Existing != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("virtualNetworkGateway", "publicIP", "existing"), "the virtual network gateway cannot use an existing Public IP"))
+	}
+
+	return allErrs
+}
+
+// validateApplicationGateway validates the application gateway and its subnet.
+func validateApplicationGateway(appGateway *ApplicationGatewaySpec, networkSpec NetworkSpec, fldPath, networkSpecPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if appGateway == nil {
+		return allErrs
+	}
+
+	if appGateway.SubnetName == GatewaySubnetName {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subnetName"), appGateway.SubnetName,
+			fmt.Sprintf("the %s subnet is reserved for virtual network gateways", GatewaySubnetName)))
+	}
+	for i, subnet := range networkSpec.Subnets {
+		if subnet.Name == appGateway.SubnetName {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("subnetName"), appGateway.SubnetName,
+				fmt.Sprintf("the subnet of the application gateway cannot be %s, as it must be dedicated to the application gateway",
+					networkSpecPath.Child("subnets").Index(i))))
+		}
+	}
+
+	// The subnet of the application gateway must not overlap with the other subnets of the virtual network.
+	cidrPath := fldPath.Child("cidrBlocks")
+	allErrs = append(allErrs, validateSubnetCIDR(appGateway.CIDRBlocks, networkSpec.Vnet.CIDRBlocks, cidrPath)...)
+	type subnetCIDRs struct {
+		path  *field.Path
+		cidrs []string
+	}
+	others := make([]subnetCIDRs, 0, len(networkSpec.Subnets)+1)
+	for i, subnet := range networkSpec.Subnets {
+		others = append(others, subnetCIDRs{path: networkSpecPath.Child("subnets").Index(i), cidrs: subnet.CIDRBlocks})
+	}
+	if networkSpec.Gateway != nil {
+		others = append(others, subnetCIDRs{path: networkSpecPath.Child("gateway"), cidrs: networkSpec.Gateway.CIDRBlocks})
+	}
+	for _, cidr := range appGateway.CIDRBlocks {
+		_, nw, err := net.ParseCIDR(cidr)
+		if err != nil {
+			// Malformed CIDR blocks are reported by validateSubnetCIDR.
+			continue
+		}
+		for _, other := range others {
+			for _, otherCIDR := range other.cidrs {
+				if _, otherNw, err := net.ParseCIDR(otherCIDR); err == nil && (otherNw.Contains(nw.IP) || nw.Contains(otherNw.IP)) {
+					allErrs = append(allErrs, field.Invalid(cidrPath, cidr, fmt.Sprintf("subnet CIDR overlaps with CIDR %s of %s", otherCIDR, other.path)))
+				}
+			}
+		}
+	}
+
+	if appGateway.MaxCapacity != nil && *appGateway.MaxCapacity < appGateway.MinCapacity {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxCapacity"), *appGateway.MaxCapacity, "must not be lower than minCapacity"))
+	}
+	if appGateway.PublicIP.Existing != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("publicIP", "existing"), "the application gateway cannot use an existing Public IP"))
+	}
+
+	return allErrs
+}
+
+// validateExistingPublicIP validates the reference to an existing public IP of the API server load balancer.
+func validateExistingPublicIP(ip, old *PublicIPSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if old != nil && (old.Existing == nil) != (ip.Existing == nil) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "API server load balancer cannot switch between a new and an existing Public IP after AzureCluster creation."))
+	}
+
+	existing := ip.Existing
+	if existing == nil {
+		return allErrs
+	}
+
+	if existing.ID == "" && existing.DNSLabel == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "one of id and dnsLabel must be set"))
+	}
+	if existing.ID != "" {
+		resourceID, err := azureutil.ParseResourceID(existing.ID)
+		if err != nil || !strings.EqualFold(resourceID.ResourceType.String(), "Microsoft.Network/publicIPAddresses") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("id"), existing.ID, "id must be the resource ID of a Public IP"))
+		}
+	}
+	// A DNS label is resolved to an ID once, after which the ID must not change.
+	if old != nil && old.Existing != nil && old.Existing.ID != "" && old.Existing.ID != existing.ID {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("id"), "API server existing Public IP should not be modified after it is attached."))
+	}
+
+	return allErrs
+}
+
 // validateCloudProviderConfigOverrides validates CloudProviderConfigOverrides.
 func validateCloudProviderConfigOverrides(oldConfig, newConfig *CloudProviderConfigOverrides, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -516,8 +1326,9 @@ func validateCloudProviderConfigOverrides(oldConfig, newConfig *CloudProviderCon
 func validateClassSpecForAPIServerLB(lb LoadBalancerClassSpec, old *LoadBalancerClassSpec, apiServerLBPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
-	// SKU should be Standard
-	if lb.SKU != SKUStandard {
+	// SKU should be Standard. Basic is only allowed on existing load balancers already using it.
+	legacyBasic := old != nil && old.SKU == SKUBasic && lb.SKU == SKUBasic
+	if lb.SKU != SKUStandard && !legacyBasic {
 		allErrs = append(allErrs, field.NotSupported(apiServerLBPath.Child("sku"), lb.SKU, []string{string(SKUStandard)}))
 	}
 
@@ -527,8 +1338,8 @@ func validateClassSpecForAPIServerLB(lb LoadBalancerClassSpec, old *LoadBalancer
 			[]string{string(Public), string(Internal)}))
 	}
 
-	// SKU should be immutable.
-	if old != nil && old.SKU != "" && old.SKU != lb.SKU {
+	// SKU should be immutable, except to migrate a Basic load balancer to the Standard SKU.
+	if old != nil && old.SKU != "" && old.SKU != lb.SKU && !(old.SKU == SKUBasic && lb.SKU == SKUStandard) {
 		allErrs = append(allErrs, field.Forbidden(apiServerLBPath.Child("sku"), "API Server load balancer SKU should not be modified after AzureCluster creation."))
 	}
 
@@ -537,9 +1348,9 @@ func validateClassSpecForAPIServerLB(lb LoadBalancerClassSpec, old *LoadBalancer
 		allErrs = append(allErrs, field.Forbidden(apiServerLBPath.Child("type"), "API Server load balancer type should not be modified after AzureCluster creation."))
 	}
 
-	// IdletimeoutInMinutes should be immutable.
-	if old != nil && old.IdleTimeoutInMinutes != nil && !ptr.Equal(old.IdleTimeoutInMinutes, lb.IdleTimeoutInMinutes) {
-		allErrs = append(allErrs, field.Forbidden(apiServerLBPath.Child("idleTimeoutInMinutes"), "API Server load balancer idle timeout cannot be modified after AzureCluster creation."))
+	// The network interfaces of control plane machines reference their SSH NAT rule, which can't be added or removed.
+	if old != nil && old.DisableSSHNATRules != lb.DisableSSHNATRules {
+		allErrs = append(allErrs, field.Forbidden(apiServerLBPath.Child("disableSSHNATRules"), "API Server load balancer SSH NAT rules should not be enabled or disabled after AzureCluster creation."))
 	}
 
 	if lb.IdleTimeoutInMinutes != nil && (*lb.IdleTimeoutInMinutes < MinLBIdleTimeoutInMinutes || *lb.IdleTimeoutInMinutes > MaxLBIdleTimeoutInMinutes) {
@@ -547,6 +1358,219 @@ func validateClassSpecForAPIServerLB(lb LoadBalancerClassSpec, old *LoadBalancer
 			fmt.Sprintf("Node outbound idle timeout should be between %d and %d minutes", MinLBIdleTimeoutInMinutes, MaxLoadBalancerOutboundIPs)))
 	}
 
+	allErrs = append(allErrs, validateLoadBalancerHealthProbe(lb.HealthProbe, apiServerLBPath.Child("healthProbe"))...)
+
+	var oldRules []LoadBalancingRule
+	if old != nil {
+		oldRules = old.AdditionalRules
+	}
+	allErrs = append(allErrs, validateAdditionalLBRules(lb.AdditionalRules, oldRules, apiServerLBPath.Child("additionalRules"))...)
+	for i, rule := range lb.AdditionalRules {
+		if rule.FrontendPort == 22 || (rule.FrontendPort >= 2201 && rule.FrontendPort < 2220) {
+			allErrs = append(allErrs, field.Invalid(apiServerLBPath.Child("additionalRules").Index(i).Child("frontendPort"), rule.FrontendPort,
+				"frontendPort cannot be 22 or between 2201 and 2219, which are used by the SSH inbound NAT rules of control plane machines"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateEgressProxy validates the egress proxy of the API server. Its port must not be used by another rule of the
+// API server load balancer, and as load balancing rules are never removed, it cannot be changed or removed once set.
+func validateEgressProxy(egressProxy, old *EgressProxySpec, apiServerLB LoadBalancerSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if egressProxy == nil {
+		if old != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "egress proxy cannot be removed after it is set"))
+		}
+		return allErrs
+	}
+
+	portPath := fldPath.Child("port")
+	if egressProxy.Port < 1 || egressProxy.Port > 65534 {
+		allErrs = append(allErrs, field.Invalid(portPath, egressProxy.Port, "port should be between 1 and 65534"))
+	}
+	if egressProxy.Port == 22 || (egressProxy.Port >= 2201 && egressProxy.Port < 2220) {
+		allErrs = append(allErrs, field.Invalid(portPath, egressProxy.Port,
+			"port cannot be 22 or between 2201 and 2219, which are used by the SSH inbound NAT rules of control plane machines"))
+	}
+	for _, rule := range apiServerLB.AdditionalRules {
+		if rule.Protocol == LoadBalancingRuleProtocolTCP && rule.FrontendPort == egressProxy.Port {
+			allErrs = append(allErrs, field.Invalid(portPath, egressProxy.Port, fmt.Sprintf("port is already used by the additional rule %s of the API server load balancer", rule.Name)))
+		}
+	}
+	if old != nil && old.Port != egressProxy.Port {
+		allErrs = append(allErrs, field.Forbidden(portPath, "egress proxy port cannot be changed after it is set"))
+	}
+	return allErrs
+}
+
+// controlPlaneSecurityRules returns the security rules of the control plane subnet the extra rules of the control plane
+// are added to. These are the default rules set at reconcile time when the subnet has none, and the rule of the egress
+// proxy port when it is enabled, with only their names, directions and priorities.
+func controlPlaneSecurityRules(networkSpec NetworkSpec, subnet SubnetSpec) SecurityRules {
+	rules := subnet.SecurityGroup.SecurityRules
+	if rules == nil {
+		rules = SecurityRules{
+			{Name: SSHSecurityRuleName, Direction: SecurityRuleDirectionInbound, Priority: 2200},
+			{Name: APIServerSecurityRuleName, Direction: SecurityRuleDirectionInbound, Priority: 2201},
+		}
+		if networkSpec.Vnet.IsIPv6Enabled() {
+			rules = append(rules, SecurityRule{Name: ICMPv6SecurityRuleName, Direction: SecurityRuleDirectionInbound, Priority: 2202})
+		}
+	}
+	if networkSpec.EgressProxy == nil {
+		return rules
+	}
+	priorities := make(map[int32]struct{}, len(rules))
+	for _, rule := range rules {
+		if rule.Name == EgressProxySecurityRuleName {
+			return rules
+		}
+		if rule.Direction == SecurityRuleDirectionInbound {
+			priorities[rule.Priority] = struct{}{}
+		}
+	}
+	priority := int32(2203)
+	for ; ; priority++ {
+		if _, ok := priorities[priority]; !ok {
+			break
+		}
+	}
+	withRule := make(SecurityRules, 0, len(rules)+1)
+	withRule = append(withRule, rules...)
+	return append(withRule, SecurityRule{Name: EgressProxySecurityRuleName, Direction: SecurityRuleDirectionInbound, Priority: priority})
+}
+
+// validateControlPlaneExtraRules validates the extra security rules of the control plane. A rule without a priority
+// gets a managed one, and the names and priorities of the rules must not clash with the security rules of the control
+// plane subnet.
+func validateControlPlaneExtraRules(rules, subnetRules SecurityRules, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := make(map[string]struct{}, len(subnetRules))
+	priorities := make(map[string]struct{}, len(subnetRules))
+	for _, rule := range subnetRules {
+		names[rule.Name] = struct{}{}
+		priorities[fmt.Sprintf("%s/%d", rule.Direction, rule.Priority)] = struct{}{}
+	}
+	for i, rule := range rules {
+		rulePath := fldPath.Index(i)
+		if _, ok := names[rule.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(rulePath.Child("name"), rule.Name))
+		}
+		names[rule.Name] = struct{}{}
+
+		if rule.Priority == 0 {
+			// The priority is managed, validate the rest of the rule.
+			rule.Priority = minRulePriority
+		} else {
+			priority := fmt.Sprintf("%s/%d", rule.Direction, rule.Priority)
+			if _, ok := priorities[priority]; ok {
+				allErrs = append(allErrs, field.Duplicate(rulePath.Child("priority"), rule.Priority))
+			}
+			priorities[priority] = struct{}{}
+		}
+		if err := validateSecurityRule(rule, rulePath); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	return allErrs
+}
+
+// validateAdditionalLBRules validates the additional load balancing rules of a load balancer.
+func validateAdditionalLBRules(rules, oldRules []LoadBalancingRule, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := make(map[string]struct{}, len(rules))
+	frontendPorts := make(map[string]struct{}, len(rules))
+	for i, rule := range rules {
+		rulePath := fldPath.Index(i)
+		if err := validateLoadBalancerName(rule.Name, rulePath.Child("name")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+		if strings.EqualFold(rule.Name, APIServerLBRuleName) {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("name"), rule.Name, "name is reserved for the API server load balancing rule"))
+		}
+		if strings.EqualFold(rule.Name, EgressProxyLBRuleName) {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("name"), rule.Name, "name is reserved for the egress proxy load balancing rule"))
+		}
+		if _, ok := names[rule.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(rulePath.Child("name"), rule.Name))
+		}
+		names[rule.Name] = struct{}{}
+
+		if rule.Protocol != LoadBalancingRuleProtocolTCP && rule.Protocol != LoadBalancingRuleProtocolUDP {
+			allErrs = append(allErrs, field.NotSupported(rulePath.Child("protocol"), rule.Protocol,
+				[]string{string(LoadBalancingRuleProtocolTCP), string(LoadBalancingRuleProtocolUDP)}))
+		}
+		if rule.FrontendPort < 1 || rule.FrontendPort > 65534 {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("frontendPort"), rule.FrontendPort, "frontendPort should be between 1 and 65534"))
+		}
+		frontendPort := fmt.Sprintf("%s/%d", rule.Protocol, rule.FrontendPort)
+		if _, ok := frontendPorts[frontendPort]; ok {
+			allErrs = append(allErrs, field.Duplicate(rulePath.Child("frontendPort"), rule.FrontendPort))
+		}
+		frontendPorts[frontendPort] = struct{}{}
+		if rule.BackendPort != nil && (*rule.BackendPort < 1 || *rule.BackendPort > 65535) {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("backendPort"), *rule.BackendPort, "backendPort should be between 1 and 65535"))
+		}
+	}
+
+	// Rules are only ever added to the load balancer, so they cannot be modified or removed once created.
+	for _, oldRule := range oldRules {
+		found := false
+		for _, rule := range rules {
+			if rule.Name == oldRule.Name {
+				found = true
+				if !reflect.DeepEqual(rule, oldRule) {
+					allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("additional rule %s cannot be modified after AzureCluster creation", oldRule.Name)))
+				}
+				break
+			}
+		}
+		if !found {
+			allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("additional rule %s cannot be removed after AzureCluster creation", oldRule.Name)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateLoadBalancerHealthProbe validates the health probe of the API server load balancer.
+func validateLoadBalancerHealthProbe(probe *LoadBalancerHealthProbe, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if probe == nil {
+		return allErrs
+	}
+
+	switch probe.Protocol {
+	case LoadBalancerProbeProtocolTCP:
+		if probe.RequestPath != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("requestPath"), "requestPath cannot be set for Tcp probes"))
+		}
+	case LoadBalancerProbeProtocolHTTP, LoadBalancerProbeProtocolHTTPS:
+		if !strings.HasPrefix(probe.RequestPath, "/") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("requestPath"), probe.RequestPath, "requestPath must be an absolute path"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("protocol"), probe.Protocol,
+			[]string{string(LoadBalancerProbeProtocolTCP), string(LoadBalancerProbeProtocolHTTP), string(LoadBalancerProbeProtocolHTTPS)}))
+	}
+
+	if probe.Port != nil && (*probe.Port < 1 || *probe.Port > 65535) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("port"), *probe.Port, "port should be between 1 and 65535"))
+	}
+	if probe.IntervalInSeconds != nil && *probe.IntervalInSeconds < 5 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("intervalInSeconds"), *probe.IntervalInSeconds, "intervalInSeconds should be at least 5"))
+	}
+	if probe.NumberOfProbes != nil && *probe.NumberOfProbes < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("numberOfProbes"), *probe.NumberOfProbes, "numberOfProbes should be at least 1"))
+	}
+	if probe.ProbeThreshold != nil && *probe.ProbeThreshold < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("probeThreshold"), *probe.ProbeThreshold, "probeThreshold should be at least 1"))
+	}
+
 	return allErrs
 }
 
@@ -571,15 +1595,31 @@ func validateClassSpecForNodeOutboundLB(lb *LoadBalancerClassSpec, old *LoadBala
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("type"), "Node outbound load balancer Type cannot be modified after AzureCluster creation."))
 	}
 
-	if old != nil && !ptr.Equal(old.IdleTimeoutInMinutes, lb.IdleTimeoutInMinutes) {
-		allErrs = append(allErrs, field.Forbidden(fldPath.Child("idleTimeoutInMinutes"), "Node outbound load balancer idle timeout cannot be modified after AzureCluster creation."))
-	}
-
 	if lb.IdleTimeoutInMinutes != nil && (*lb.IdleTimeoutInMinutes < MinLBIdleTimeoutInMinutes || *lb.IdleTimeoutInMinutes > MaxLBIdleTimeoutInMinutes) {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("idleTimeoutInMinutes"), *lb.IdleTimeoutInMinutes,
 			fmt.Sprintf("Node outbound idle timeout should be between %d and %d minutes", MinLBIdleTimeoutInMinutes, MaxLoadBalancerOutboundIPs)))
 	}
 
+	if lb.HealthProbe != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("healthProbe"), "Node outbound load balancer does not support health probes."))
+	}
+
+	if len(lb.AdditionalRules) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalRules"), "Node outbound load balancer does not support load balancing rules."))
+	}
+
+	if lb.DisableSSHNATRules {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("disableSSHNATRules"), "Node outbound load balancer does not support SSH NAT rules."))
+	}
+
+	if lb.EnableFloatingIP != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("enableFloatingIP"), "Node outbound load balancer does not support floating IP."))
+	}
+
+	if lb.EnableTCPReset != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("enableTCPReset"), "Node outbound load balancer does not support TCP reset."))
+	}
+
 	return allErrs
 }
 
@@ -601,6 +1641,26 @@ func validateClassSpecForControlPlaneOutboundLB(lb *LoadBalancerClassSpec, apise
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("idleTimeoutInMinutes"), *lb.IdleTimeoutInMinutes,
 				fmt.Sprintf("Control plane outbound idle timeout should be between %d and %d minutes", MinLBIdleTimeoutInMinutes, MaxLoadBalancerOutboundIPs)))
 		}
+
+		if lb.HealthProbe != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("healthProbe"), "Control plane outbound load balancer does not support health probes."))
+		}
+
+		if len(lb.AdditionalRules) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalRules"), "Control plane outbound load balancer does not support load balancing rules."))
+		}
+
+		if lb.DisableSSHNATRules {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("disableSSHNATRules"), "Control plane outbound load balancer does not support SSH NAT rules."))
+		}
+
+		if lb.EnableFloatingIP != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("enableFloatingIP"), "Control plane outbound load balancer does not support floating IP."))
+		}
+
+		if lb.EnableTCPReset != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("enableTCPReset"), "Control plane outbound load balancer does not support TCP reset."))
+		}
 	}
 
 	return allErrs
@@ -23,7 +23,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 )
 
 func TestClusterNameValidation(t *testing.T) {
@@ -402,6 +404,371 @@ func TestResourceGroupInvalid(t *testing.T) {
 	})
 }
 
+func TestValidateNetworkResourceGroup(t *testing.T) {
+	tests := []struct {
+		name                 string
+		networkResourceGroup string
+		vnetResourceGroup    string
+		wantErrs             []string
+	}{
+		{
+			name: "no network resource group",
+		},
+		{
+			name:                 "network resource group is the vnet resource group",
+			networkResourceGroup: "network-rg",
+			vnetResourceGroup:    "network-rg",
+		},
+		{
+			name:                 "vnet resource group is not set",
+			networkResourceGroup: "network-rg",
+		},
+		{
+			name:                 "invalid network resource group name",
+			networkResourceGroup: "inv@lid-rg",
+			wantErrs:             []string{"spec.networkResourceGroup"},
+		},
+		{
+			name:                 "network resource group is the cluster resource group",
+			networkResourceGroup: "Cluster-RG",
+			wantErrs:             []string{"spec.networkResourceGroup"},
+		},
+		{
+			name:                 "vnet resource group differs from the network resource group",
+			networkResourceGroup: "network-rg",
+			vnetResourceGroup:    "other-rg",
+			wantErrs:             []string{"spec.networkSpec.vnet.resourceGroup"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cluster := &AzureCluster{
+				Spec: AzureClusterSpec{
+					ResourceGroup:        "cluster-rg",
+					NetworkResourceGroup: tc.networkResourceGroup,
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{
+							ResourceGroup: tc.vnetResourceGroup,
+						},
+					},
+				},
+			}
+			errs := cluster.validateNetworkResourceGroup()
+			g.Expect(errs).To(HaveLen(len(tc.wantErrs)))
+			for i, err := range errs {
+				g.Expect(err.Field).To(Equal(tc.wantErrs[i]))
+			}
+		})
+	}
+}
+
+func TestValidateZoneRedundantPublicIPs(t *testing.T) {
+	tests := []struct {
+		name             string
+		zoneRedundant    bool
+		location         string
+		extendedLocation *ExtendedLocationSpec
+		wantErr          bool
+	}{
+		{
+			name:     "zone redundancy not requested in a location without zones",
+			location: "westus",
+		},
+		{
+			name:          "zone redundancy in a location with zones",
+			zoneRedundant: true,
+			location:      "eastus2",
+		},
+		{
+			name:          "zone redundancy in a location without zones",
+			zoneRedundant: true,
+			location:      "West US",
+			wantErr:       true,
+		},
+		{
+			name:          "zone redundancy in an edge zone",
+			zoneRedundant: true,
+			location:      "eastus2",
+			extendedLocation: &ExtendedLocationSpec{
+				Name: "microsoftlosangeles1",
+				Type: "EdgeZone",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cluster := &AzureCluster{
+				Spec: AzureClusterSpec{
+					AzureClusterClassSpec: AzureClusterClassSpec{
+						Location:               tc.location,
+						ExtendedLocation:       tc.extendedLocation,
+						ZoneRedundantPublicIPs: tc.zoneRedundant,
+					},
+				},
+			}
+			errs := cluster.validateZoneRedundantPublicIPs()
+			if tc.wantErr {
+				g.Expect(errs).To(HaveLen(1))
+				g.Expect(errs[0].Field).To(Equal("spec.zoneRedundantPublicIPs"))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidatePublicNetworkAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		access     PublicNetworkAccess
+		spec       func(*AzureClusterSpec)
+		wantFields []string
+	}{
+		{
+			name:   "public resources with public network access",
+			access: PublicNetworkAccessEnabled,
+			spec: func(spec *AzureClusterSpec) {
+				spec.NetworkSpec.APIServerLB.Type = Public
+				spec.BastionSpec.AzureBastion = &AzureBastion{}
+			},
+		},
+		{
+			name: "public resources without a policy",
+			spec: func(spec *AzureClusterSpec) {
+				spec.NetworkSpec.APIServerLB.Type = Public
+			},
+		},
+		{
+			name:   "private cluster without public network access",
+			access: PublicNetworkAccessDisabled,
+			spec: func(spec *AzureClusterSpec) {
+				spec.NetworkSpec.APIServerLB.Type = Internal
+				spec.NetworkSpec.Subnets = Subnets{{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "node-subnet"}}}
+				spec.NetworkSpec.Gateway = &GatewaySpec{}
+			},
+		},
+		{
+			name:   "public resources without public network access",
+			access: PublicNetworkAccessDisabled,
+			spec: func(spec *AzureClusterSpec) {
+				spec.NetworkSpec.APIServerLB.Type = Public
+				spec.NetworkSpec.NodeOutboundLB = &LoadBalancerSpec{}
+				spec.NetworkSpec.ControlPlaneOutboundLB = &LoadBalancerSpec{}
+				spec.NetworkSpec.Subnets = Subnets{
+					{SubnetClassSpec: SubnetClassSpec{Role: SubnetControlPlane, Name: "cp-subnet"}},
+					{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "node-subnet"}, NatGateway: NatGateway{NatGatewayClassSpec: NatGatewayClassSpec{Name: "node-natgw"}}},
+				}
+				spec.NetworkSpec.Gateway = &GatewaySpec{VirtualNetworkGateway: &VirtualNetworkGatewaySpec{}}
+				spec.BastionSpec.AzureBastion = &AzureBastion{}
+				spec.ApplicationGateway = &ApplicationGatewaySpec{}
+			},
+			wantFields: []string{
+				"spec.networkSpec.apiServerLB.type",
+				"spec.networkSpec.nodeOutboundLB",
+				"spec.networkSpec.controlPlaneOutboundLB",
+				"spec.networkSpec.subnets[1].natGateway",
+				"spec.networkSpec.gateway.virtualNetworkGateway",
+				"spec.bastionSpec.azureBastion",
+				"spec.applicationGateway",
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cluster := &AzureCluster{Spec: AzureClusterSpec{PublicNetworkAccess: tc.access}}
+			tc.spec(&cluster.Spec)
+			errs := cluster.validatePublicNetworkAccess()
+			fields := make([]string, 0, len(errs))
+			for _, err := range errs {
+				g.Expect(err.Type).To(Equal(field.ErrorTypeForbidden))
+				fields = append(fields, err.Field)
+			}
+			g.Expect(fields).To(ConsistOf(tc.wantFields))
+		})
+	}
+}
+
+func TestValidateServiceFeatureGates(t *testing.T) {
+	natGatewaySubnets := Subnets{
+		{SubnetClassSpec: SubnetClassSpec{Role: SubnetControlPlane, Name: "cp-subnet"}},
+		{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "node-subnet"}, NatGateway: NatGateway{NatGatewayClassSpec: NatGatewayClassSpec{Name: "node-natgw"}}},
+	}
+	tests := []struct {
+		name       string
+		enabled    bool
+		spec       func(*AzureClusterSpec)
+		oldSpec    func(*AzureClusterSpec)
+		wantFields []string
+	}{
+		{
+			name:    "bastion and NAT gateway with the feature gates enabled",
+			enabled: true,
+			spec: func(spec *AzureClusterSpec) {
+				spec.BastionSpec.AzureBastion = &AzureBastion{}
+				spec.NetworkSpec.Subnets = natGatewaySubnets
+			},
+		},
+		{
+			name:    "new bastion and NAT gateway with the feature gates disabled",
+			enabled: false,
+			spec: func(spec *AzureClusterSpec) {
+				spec.BastionSpec.AzureBastion = &AzureBastion{}
+				spec.NetworkSpec.Subnets = natGatewaySubnets
+			},
+			wantFields: []string{
+				"spec.bastionSpec.azureBastion",
+				"spec.networkSpec.subnets[1].natGateway",
+			},
+		},
+		{
+			name:    "existing bastion and NAT gateway with the feature gates disabled",
+			enabled: false,
+			spec: func(spec *AzureClusterSpec) {
+				spec.BastionSpec.AzureBastion = &AzureBastion{}
+				spec.NetworkSpec.Subnets = natGatewaySubnets
+			},
+			oldSpec: func(spec *AzureClusterSpec) {
+				spec.BastionSpec.AzureBastion = &AzureBastion{}
+				spec.NetworkSpec.Subnets = natGatewaySubnets
+			},
+		},
+		{
+			name:    "NAT gateway added to an existing cluster with the feature gate disabled",
+			enabled: false,
+			spec: func(spec *AzureClusterSpec) {
+				spec.NetworkSpec.Subnets = natGatewaySubnets
+			},
+			oldSpec: func(spec *AzureClusterSpec) {
+				spec.NetworkSpec.Subnets = Subnets{
+					{SubnetClassSpec: SubnetClassSpec{Role: SubnetControlPlane, Name: "cp-subnet"}},
+					{SubnetClassSpec: SubnetClassSpec{Role: SubnetNode, Name: "node-subnet"}},
+				}
+			},
+			wantFields: []string{"spec.networkSpec.subnets[1].natGateway"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.Bastion, tc.enabled)()
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.NATGateway, tc.enabled)()
+			g := NewWithT(t)
+			cluster := &AzureCluster{}
+			tc.spec(&cluster.Spec)
+			var old *AzureCluster
+			if tc.oldSpec != nil {
+				old = &AzureCluster{}
+				tc.oldSpec(&old.Spec)
+			}
+			errs := cluster.validateServiceFeatureGates(old)
+			fields := make([]string, 0, len(errs))
+			for _, err := range errs {
+				g.Expect(err.Type).To(Equal(field.ErrorTypeForbidden))
+				fields = append(fields, err.Field)
+			}
+			g.Expect(fields).To(ConsistOf(tc.wantFields))
+		})
+	}
+}
+
+func TestValidateSecondaryRegions(t *testing.T) {
+	tests := []struct {
+		name       string
+		regions    []SecondaryRegionSpec
+		wantFields []string
+	}{
+		{
+			name: "valid secondary regions",
+			regions: []SecondaryRegionSpec{
+				{
+					Location:   "eastus",
+					VnetName:   "my-vnet-eastus",
+					CIDRBlocks: []string{"172.16.0.0/16"},
+					Subnets:    []SecondaryRegionSubnetSpec{{Name: "node-subnet-eastus", CIDRBlocks: []string{"172.16.0.0/24"}}},
+				},
+				{
+					Location:   "centralus",
+					VnetName:   "my-vnet-centralus",
+					CIDRBlocks: []string{"172.17.0.0/16"},
+					Subnets: []SecondaryRegionSubnetSpec{
+						{Name: "node-subnet-centralus", CIDRBlocks: []string{"172.17.0.0/24"}},
+						{Name: "other-subnet-centralus", CIDRBlocks: []string{"172.17.1.0/24"}},
+					},
+				},
+			},
+		},
+		{
+			name: "secondary region in the location of the cluster",
+			regions: []SecondaryRegionSpec{
+				{Location: "WestUS2", VnetName: "my-vnet-westus2", CIDRBlocks: []string{"172.16.0.0/16"}},
+			},
+			wantFields: []string{"spec.secondaryRegions[0].location"},
+		},
+		{
+			name: "duplicate secondary regions",
+			regions: []SecondaryRegionSpec{
+				{Location: "eastus", VnetName: "my-vnet-eastus", CIDRBlocks: []string{"172.16.0.0/16"}},
+				{Location: "eastus", VnetName: "my-vnet-eastus", CIDRBlocks: []string{"172.17.0.0/16"}},
+			},
+			wantFields: []string{"spec.secondaryRegions[1].location", "spec.secondaryRegions[1].vnetName"},
+		},
+		{
+			name: "overlapping vnets",
+			regions: []SecondaryRegionSpec{
+				{Location: "eastus", VnetName: "my-vnet-eastus", CIDRBlocks: []string{"172.16.0.0/16"}},
+				{Location: "centralus", VnetName: "my-vnet-centralus", CIDRBlocks: []string{"172.16.0.0/12"}},
+				{Location: "westus", VnetName: "my-vnet-westus", CIDRBlocks: []string{"10.1.0.0/16"}},
+			},
+			wantFields: []string{"spec.secondaryRegions[1].cidrBlocks", "spec.secondaryRegions[2].cidrBlocks"},
+		},
+		{
+			name: "invalid subnets",
+			regions: []SecondaryRegionSpec{
+				{
+					Location:   "eastus",
+					VnetName:   "my-vnet-eastus",
+					CIDRBlocks: []string{"172.16.0.0/16"},
+					Subnets: []SecondaryRegionSubnetSpec{
+						{Name: "node-subnet", CIDRBlocks: []string{"172.16.0.0/24"}},
+						{Name: "other-subnet", CIDRBlocks: []string{"172.16.0.0/25"}},
+						{Name: "outside-subnet", CIDRBlocks: []string{"172.17.0.0/24"}},
+						{Name: "insecure-subnet", CIDRBlocks: []string{"172.16.2.0/24"}, SecurityRules: SecurityRules{{Name: "rule", Priority: 50}}},
+					},
+				},
+			},
+			wantFields: []string{
+				"spec.secondaryRegions[0].subnets[0].name",
+				"spec.secondaryRegions[0].subnets[1].cidrBlocks",
+				"spec.secondaryRegions[0].subnets[2].cidrBlocks",
+				"spec.secondaryRegions[0].subnets[3].securityRules[0]",
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cluster := createValidCluster()
+			cluster.Spec.Location = "westus2"
+			cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{"10.0.0.0/8"}
+			cluster.Spec.SecondaryRegions = tc.regions
+			errs := cluster.validateSecondaryRegions(field.NewPath("spec", "secondaryRegions"))
+			fields := make([]string, 0, len(errs))
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			g.Expect(fields).To(ConsistOf(tc.wantFields))
+		})
+	}
+}
+
 func TestValidateVnetCIDR(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -506,6 +873,33 @@ func TestSubnetsInvalidLackRequiredSubnet(t *testing.T) {
 	})
 }
 
+func TestSubnetsInvalidCIDRBlockFromPool(t *testing.T) {
+	type test struct {
+		name    string
+		subnets Subnets
+	}
+
+	testCase := test{
+		name:    "subnets - cidr block pool without kind",
+		subnets: createValidSubnets(),
+	}
+
+	testCase.subnets[1].CIDRBlocks = nil
+	testCase.subnets[1].CIDRBlockFromPool = &corev1.TypedLocalObjectReference{
+		APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+		Name:     "subnets",
+	}
+
+	t.Run(testCase.name, func(t *testing.T) {
+		g := NewWithT(t)
+		errs := validateSubnets(testCase.subnets, createValidVnet(),
+			field.NewPath("spec").Child("networkSpec").Child("subnets"))
+		g.Expect(errs).To(HaveLen(1))
+		g.Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
+		g.Expect(errs[0].Field).To(Equal("spec.networkSpec.subnets[1].cidrBlockFromPool.kind"))
+	})
+}
+
 func TestSubnetNamesNotUnique(t *testing.T) {
 	type test struct {
 		name    string
@@ -609,6 +1003,30 @@ func TestValidateSubnetCIDR(t *testing.T) {
 				Detail:   "subnet CIDR not in vnet address space: [10.0.0.0/8]",
 			},
 		},
+		{
+			name:             "subnet cidr larger than the vnet range",
+			vnetCidrBlocks:   []string{"10.0.0.0/16"},
+			subnetCidrBlocks: []string{"10.0.0.0/8"},
+			wantErr:          true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "subnets.cidrBlocks",
+				BadValue: "10.0.0.0/8",
+				Detail:   "subnet CIDR not in vnet address space: [10.0.0.0/16]",
+			},
+		},
+		{
+			name:             "ipv6 subnet cidr in an ipv4 vnet",
+			vnetCidrBlocks:   []string{"10.0.0.0/8"},
+			subnetCidrBlocks: []string{"2001:1234:5678:9abd::/64"},
+			wantErr:          true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "subnets.cidrBlocks",
+				BadValue: "2001:1234:5678:9abd::/64",
+				Detail:   "subnet CIDR not in vnet address space: [10.0.0.0/8]",
+			},
+		},
 		{
 			name:             "subnet cidr in at least one vnet's range in case of multiple vnet cidr blocks",
 			vnetCidrBlocks:   []string{"10.0.0.0/8", "11.0.0.0/8"},
@@ -630,6 +1048,49 @@ func TestValidateSubnetCIDR(t *testing.T) {
 	}
 }
 
+func TestValidateSubnetCIDROverlaps(t *testing.T) {
+	tests := []struct {
+		name             string
+		subnetCidrBlocks [][]string
+		expectedErrs     field.ErrorList
+	}{
+		{
+			name:             "disjoint subnets",
+			subnetCidrBlocks: [][]string{{"10.0.0.0/16"}, {"10.1.0.0/16", "2001:1234:5678:9abd::/64"}},
+		},
+		{
+			name:             "subnets with the same cidr",
+			subnetCidrBlocks: [][]string{{"10.0.0.0/16"}, {"10.0.0.0/16"}},
+			expectedErrs: field.ErrorList{
+				field.Invalid(field.NewPath("subnets").Index(1).Child("cidrBlocks"), "10.0.0.0/16", "subnet CIDR overlaps with CIDR 10.0.0.0/16 of subnets[0]"),
+			},
+		},
+		{
+			name:             "subnet nested in another subnet",
+			subnetCidrBlocks: [][]string{{"10.0.1.0/24"}, {"10.1.0.0/16"}, {"10.0.0.0/16"}},
+			expectedErrs: field.ErrorList{
+				field.Invalid(field.NewPath("subnets").Index(2).Child("cidrBlocks"), "10.0.0.0/16", "subnet CIDR overlaps with CIDR 10.0.1.0/24 of subnets[0]"),
+			},
+		},
+		{
+			name:             "malformed cidrs are ignored",
+			subnetCidrBlocks: [][]string{{"foo/bar"}, {"foo/bar"}},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateSubnetCIDROverlaps(tc.subnetCidrBlocks, field.NewPath("subnets"))
+			if tc.expectedErrs == nil {
+				g.Expect(errs).To(BeEmpty())
+			} else {
+				g.Expect(errs).To(Equal(tc.expectedErrs))
+			}
+		})
+	}
+}
+
 func TestValidateSecurityRule(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -663,6 +1124,40 @@ func TestValidateSecurityRule(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "security rule - valid icmpv6 rule",
+			validRule: SecurityRule{
+				Name:        "allow_icmpv6",
+				Description: "Allow ICMPv6",
+				Priority:    2202,
+				Protocol:    SecurityGroupProtocolICMPv6,
+				Source:      ptr.To("*"),
+				Destination: ptr.To("2001:1234:5678:9abc::/64"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "security rule - icmpv6 rule with ipv4 source",
+			validRule: SecurityRule{
+				Name:        "allow_icmpv6",
+				Description: "Allow ICMPv6",
+				Priority:    2202,
+				Protocol:    SecurityGroupProtocolICMPv6,
+				Source:      ptr.To("10.0.0.0/8"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "security rule - icmpv6 rule with ipv4 destination",
+			validRule: SecurityRule{
+				Name:        "allow_icmpv6",
+				Description: "Allow ICMPv6",
+				Priority:    2202,
+				Protocol:    SecurityGroupProtocolICMPv6,
+				Destination: ptr.To("10.0.0.4"),
+			},
+			wantErr: true,
+		},
 	}
 	for _, testCase := range tests {
 		testCase := testCase
@@ -682,48 +1177,394 @@ func TestValidateSecurityRule(t *testing.T) {
 	}
 }
 
-func TestValidateAPIServerLB(t *testing.T) {
-	testcases := []struct {
-		name        string
-		lb          LoadBalancerSpec
-		old         LoadBalancerSpec
-		cpCIDRS     []string
-		wantErr     bool
-		expectedErr field.Error
+func TestValidateExistingSecurityGroup(t *testing.T) {
+	existingID := "/subscriptions/123/resourceGroups/security-rg/providers/Microsoft.Network/networkSecurityGroups/shared-nsg"
+	tests := []struct {
+		name    string
+		sg      SecurityGroup
+		wantErr string
 	}{
 		{
-			name: "invalid SKU",
-			lb: LoadBalancerSpec{
-				Name: "my-awesome-lb",
-				FrontendIPs: []FrontendIP{
-					{
-						Name: "ip-config",
-					},
-				},
-				LoadBalancerClassSpec: LoadBalancerClassSpec{
-					SKU:  "Awesome",
-					Type: Public,
-				},
+			name: "not an existing security group",
+			sg:   SecurityGroup{Name: "my-nsg"},
+		},
+		{
+			name: "valid existing security group",
+			sg: SecurityGroup{
+				Name:     "shared-nsg",
+				Existing: &ExistingSecurityGroup{ID: existingID},
 			},
-			wantErr: true,
-			expectedErr: field.Error{
-				Type:     "FieldValueNotSupported",
-				Field:    "apiServerLB.sku",
-				BadValue: "Awesome",
-				Detail:   "supported values: \"Standard\"",
+		},
+		{
+			name: "invalid resource ID",
+			sg: SecurityGroup{
+				Name:     "shared-nsg",
+				Existing: &ExistingSecurityGroup{ID: "shared-nsg"},
 			},
+			wantErr: "id must be the resource ID of a network security group",
 		},
 		{
-			name: "invalid Type",
-			lb: LoadBalancerSpec{
-				LoadBalancerClassSpec: LoadBalancerClassSpec{
-					Type: "Foo",
-				},
+			name: "resource ID of another resource type",
+			sg: SecurityGroup{
+				Name:     "shared-nsg",
+				Existing: &ExistingSecurityGroup{ID: "/subscriptions/123/resourceGroups/security-rg/providers/Microsoft.Network/routeTables/shared-nsg"},
 			},
-			wantErr: true,
-			expectedErr: field.Error{
-				Type:     "FieldValueNotSupported",
-				Field:    "apiServerLB.type",
+			wantErr: "id must be the resource ID of a network security group",
+		},
+		{
+			name: "name does not match the existing security group",
+			sg: SecurityGroup{
+				Name:     "my-nsg",
+				Existing: &ExistingSecurityGroup{ID: existingID},
+			},
+			wantErr: "name must be the name of the existing network security group",
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			errs := validateExistingSecurityGroup(
+				testCase.sg,
+				field.NewPath("spec").Child("networkSpec").Child("subnets").Index(0).Child("securityGroup"),
+			)
+			if testCase.wantErr != "" {
+				g.Expect(errs).To(HaveLen(1))
+				g.Expect(errs[0].Detail).To(Equal(testCase.wantErr))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateLoadBalancerHealthProbe(t *testing.T) {
+	tests := []struct {
+		name    string
+		probe   *LoadBalancerHealthProbe
+		wantErr string
+	}{
+		{
+			name:  "no health probe",
+			probe: nil,
+		},
+		{
+			name: "valid HTTPS probe",
+			probe: &LoadBalancerHealthProbe{
+				Protocol:          LoadBalancerProbeProtocolHTTPS,
+				RequestPath:       "/readyz",
+				IntervalInSeconds: ptr.To[int32](5),
+				NumberOfProbes:    ptr.To[int32](2),
+				ProbeThreshold:    ptr.To[int32](3),
+			},
+		},
+		{
+			name: "valid TCP probe",
+			probe: &LoadBalancerHealthProbe{
+				Protocol: LoadBalancerProbeProtocolTCP,
+				Port:     ptr.To[int32](6443),
+			},
+		},
+		{
+			name: "TCP probe with a request path",
+			probe: &LoadBalancerHealthProbe{
+				Protocol:    LoadBalancerProbeProtocolTCP,
+				RequestPath: "/readyz",
+			},
+			wantErr: "requestPath cannot be set for Tcp probes",
+		},
+		{
+			name: "HTTP probe with a relative request path",
+			probe: &LoadBalancerHealthProbe{
+				Protocol:    LoadBalancerProbeProtocolHTTP,
+				RequestPath: "readyz",
+			},
+			wantErr: "requestPath must be an absolute path",
+		},
+		{
+			name: "unsupported protocol",
+			probe: &LoadBalancerHealthProbe{
+				Protocol: "Udp",
+			},
+			wantErr: "supported values: \"Tcp\", \"Http\", \"Https\"",
+		},
+		{
+			name: "invalid port",
+			probe: &LoadBalancerHealthProbe{
+				Protocol: LoadBalancerProbeProtocolTCP,
+				Port:     ptr.To[int32](70000),
+			},
+			wantErr: "port should be between 1 and 65535",
+		},
+		{
+			name: "interval too short",
+			probe: &LoadBalancerHealthProbe{
+				Protocol:          LoadBalancerProbeProtocolTCP,
+				IntervalInSeconds: ptr.To[int32](1),
+			},
+			wantErr: "intervalInSeconds should be at least 5",
+		},
+		{
+			name: "invalid threshold",
+			probe: &LoadBalancerHealthProbe{
+				Protocol:       LoadBalancerProbeProtocolTCP,
+				ProbeThreshold: ptr.To[int32](0),
+			},
+			wantErr: "probeThreshold should be at least 1",
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			errs := validateLoadBalancerHealthProbe(testCase.probe, field.NewPath("apiServerLB").Child("healthProbe"))
+			if testCase.wantErr != "" {
+				g.Expect(errs).To(HaveLen(1))
+				g.Expect(errs[0].Detail).To(Equal(testCase.wantErr))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateAdditionalLBRules(t *testing.T) {
+	konnectivity := LoadBalancingRule{Name: "konnectivity", Protocol: LoadBalancingRuleProtocolTCP, FrontendPort: 8132, BackendPort: ptr.To[int32](8132)}
+	https := LoadBalancingRule{Name: "https", Protocol: LoadBalancingRuleProtocolTCP, FrontendPort: 443, BackendPort: ptr.To[int32](6443)}
+	tests := []struct {
+		name     string
+		rules    []LoadBalancingRule
+		oldRules []LoadBalancingRule
+		wantErr  *field.Error
+	}{
+		{
+			name:  "valid rules",
+			rules: []LoadBalancingRule{https, konnectivity},
+		},
+		{
+			name:     "rule added after creation",
+			rules:    []LoadBalancingRule{https, konnectivity},
+			oldRules: []LoadBalancingRule{https},
+		},
+		{
+			name:    "reserved name",
+			rules:   []LoadBalancingRule{{Name: "LBRuleHTTPS", Protocol: LoadBalancingRuleProtocolTCP, FrontendPort: 443}},
+			wantErr: &field.Error{Type: field.ErrorTypeInvalid, Detail: "name is reserved for the API server load balancing rule"},
+		},
+		{
+			name:    "duplicate name",
+			rules:   []LoadBalancingRule{https, {Name: "https", Protocol: LoadBalancingRuleProtocolTCP, FrontendPort: 8443}},
+			wantErr: &field.Error{Type: field.ErrorTypeDuplicate},
+		},
+		{
+			name:    "unsupported protocol",
+			rules:   []LoadBalancingRule{{Name: "icmp", Protocol: "Icmp", FrontendPort: 443}},
+			wantErr: &field.Error{Type: field.ErrorTypeNotSupported, Detail: "supported values: \"Tcp\", \"Udp\""},
+		},
+		{
+			name:    "duplicate frontend port",
+			rules:   []LoadBalancingRule{https, {Name: "https-2", Protocol: LoadBalancingRuleProtocolTCP, FrontendPort: 443}},
+			wantErr: &field.Error{Type: field.ErrorTypeDuplicate},
+		},
+		{
+			name:    "invalid backend port",
+			rules:   []LoadBalancingRule{{Name: "https", Protocol: LoadBalancingRuleProtocolTCP, FrontendPort: 443, BackendPort: ptr.To[int32](0)}},
+			wantErr: &field.Error{Type: field.ErrorTypeInvalid, Detail: "backendPort should be between 1 and 65535"},
+		},
+		{
+			name:     "rule modified after creation",
+			rules:    []LoadBalancingRule{{Name: "https", Protocol: LoadBalancingRuleProtocolTCP, FrontendPort: 8443, BackendPort: ptr.To[int32](6443)}},
+			oldRules: []LoadBalancingRule{https},
+			wantErr:  &field.Error{Type: field.ErrorTypeForbidden, Detail: "additional rule https cannot be modified after AzureCluster creation"},
+		},
+		{
+			name:     "rule removed after creation",
+			rules:    []LoadBalancingRule{konnectivity},
+			oldRules: []LoadBalancingRule{https, konnectivity},
+			wantErr:  &field.Error{Type: field.ErrorTypeForbidden, Detail: "additional rule https cannot be removed after AzureCluster creation"},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			errs := validateAdditionalLBRules(testCase.rules, testCase.oldRules, field.NewPath("apiServerLB").Child("additionalRules"))
+			if testCase.wantErr != nil {
+				g.Expect(errs).To(HaveLen(1))
+				g.Expect(errs[0].Type).To(Equal(testCase.wantErr.Type))
+				g.Expect(errs[0].Detail).To(Equal(testCase.wantErr.Detail))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateNodeInternalLB(t *testing.T) {
+	validLB := func() *NodeInternalLBSpec {
+		return &NodeInternalLBSpec{
+			LoadBalancerSpec: LoadBalancerSpec{
+				Name:        "my-node-internal-lb",
+				FrontendIPs: []FrontendIP{{Name: "my-node-internal-lb-frontEnd"}},
+				BackendPool: BackendPool{Name: "my-node-internal-lb-backendPool"},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					SKU:  SKUStandard,
+					Type: Internal,
+				},
+			},
+			SubnetName: "node-subnet",
+		}
+	}
+	tests := []struct {
+		name    string
+		lb      func() *NodeInternalLBSpec
+		old     *NodeInternalLBSpec
+		wantErr string
+	}{
+		{
+			name: "valid node internal LB",
+			lb:   validLB,
+		},
+		{
+			name: "valid node internal LB with a private IP, a health probe and rules",
+			lb: func() *NodeInternalLBSpec {
+				lb := validLB()
+				lb.FrontendIPs[0].PrivateIPAddress = "10.1.0.10"
+				lb.HealthProbe = &LoadBalancerHealthProbe{Protocol: LoadBalancerProbeProtocolTCP, Port: ptr.To[int32](30080)}
+				lb.AdditionalRules = []LoadBalancingRule{{Name: "ingress", Protocol: LoadBalancingRuleProtocolTCP, FrontendPort: 80, BackendPort: ptr.To[int32](30080)}}
+				return lb
+			},
+		},
+		{
+			name: "public node internal LB",
+			lb: func() *NodeInternalLBSpec {
+				lb := validLB()
+				lb.Type = Public
+				return lb
+			},
+			wantErr: "supported values: \"Internal\"",
+		},
+		{
+			name: "name of another load balancer",
+			lb: func() *NodeInternalLBSpec {
+				lb := validLB()
+				lb.Name = "my-lb"
+				return lb
+			},
+			wantErr: "Node internal load balancer cannot share another load balancer of the cluster.",
+		},
+		{
+			name: "control plane subnet",
+			lb: func() *NodeInternalLBSpec {
+				lb := validLB()
+				lb.SubnetName = "control-plane-subnet"
+				return lb
+			},
+			wantErr: "subnetName must be the name of a node subnet",
+		},
+		{
+			name: "private IP outside of the subnet",
+			lb: func() *NodeInternalLBSpec {
+				lb := validLB()
+				lb.FrontendIPs[0].PrivateIPAddress = "10.0.0.10"
+				return lb
+			},
+			wantErr: "Internal LB IP address needs to be in node subnet range ([10.1.0.0/16])",
+		},
+		{
+			name: "public IP",
+			lb: func() *NodeInternalLBSpec {
+				lb := validLB()
+				lb.FrontendIPs[0].PublicIP = &PublicIPSpec{Name: "pip"}
+				return lb
+			},
+			wantErr: "Internal Load Balancers cannot have a Public IP",
+		},
+		{
+			name: "health probe without a port",
+			lb: func() *NodeInternalLBSpec {
+				lb := validLB()
+				lb.HealthProbe = &LoadBalancerHealthProbe{Protocol: LoadBalancerProbeProtocolTCP}
+				return lb
+			},
+			wantErr: "port is required for the node internal load balancer health probe",
+		},
+		{
+			name: "subnet modified after creation",
+			lb:   validLB,
+			old: func() *NodeInternalLBSpec {
+				lb := validLB()
+				lb.SubnetName = "other-node-subnet"
+				return lb
+			}(),
+			wantErr: "Node internal load balancer subnet should not be modified after AzureCluster creation.",
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			networkSpec := createValidNetworkSpec()
+			networkSpec.Subnets[1].CIDRBlocks = []string{"10.1.0.0/16"}
+			networkSpec.NodeInternalLB = testCase.lb()
+			errs := validateNodeInternalLB(networkSpec, testCase.old, field.NewPath("nodeInternalLB"))
+			if testCase.wantErr != "" {
+				g.Expect(errs).To(HaveLen(1))
+				g.Expect(errs[0].Detail).To(Equal(testCase.wantErr))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateAPIServerLB(t *testing.T) {
+	testcases := []struct {
+		name        string
+		lb          LoadBalancerSpec
+		old         LoadBalancerSpec
+		cpCIDRS     []string
+		subnets     Subnets
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name: "invalid SKU",
+			lb: LoadBalancerSpec{
+				Name: "my-awesome-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-config",
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					SKU:  "Awesome",
+					Type: Public,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueNotSupported",
+				Field:    "apiServerLB.sku",
+				BadValue: "Awesome",
+				Detail:   "supported values: \"Standard\"",
+			},
+		},
+		{
+			name: "invalid Type",
+			lb: LoadBalancerSpec{
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: "Foo",
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueNotSupported",
+				Field:    "apiServerLB.type",
 				BadValue: "Foo",
 				Detail:   "supported values: \"Public\", \"Internal\"",
 			},
@@ -791,171 +1632,1393 @@ func TestValidateAPIServerLB(t *testing.T) {
 			},
 		},
 		{
-			name: "internal LB with public IP",
-			lb: LoadBalancerSpec{
-				FrontendIPs: []FrontendIP{
-					{
-						Name: "ip-1",
-						PublicIP: &PublicIPSpec{
-							Name: "my-invalid-ip",
-						},
-					},
-				},
-				LoadBalancerClassSpec: LoadBalancerClassSpec{
-					Type: Internal,
-				},
-			},
-			wantErr: true,
-			expectedErr: field.Error{
-				Type:   "FieldValueForbidden",
-				Field:  "apiServerLB.frontendIPConfigs[0].publicIP",
-				Detail: "Internal Load Balancers cannot have a Public IP",
-			},
+			name: "internal LB with public IP",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						PublicIP: &PublicIPSpec{
+							Name: "my-invalid-ip",
+						},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "apiServerLB.frontendIPConfigs[0].publicIP",
+				Detail: "Internal Load Balancers cannot have a Public IP",
+			},
+		},
+		{
+			name: "internal LB with invalid private IP",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "NAIP",
+						},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "apiServerLB.frontendIPConfigs[0].privateIP",
+				BadValue: "NAIP",
+				Detail:   "Internal LB IP address isn't a valid IPv4 or IPv6 address",
+			},
+		},
+		{
+			name: "internal LB with out of range private IP",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "20.1.2.3",
+						},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "apiServerLB.frontendIPConfigs[0].privateIP",
+				BadValue: "20.1.2.3",
+				Detail:   "Internal LB IP address needs to be in control plane subnet range ([10.0.0.0/24 10.1.0.0/24])",
+			},
+		},
+		{
+			name: "internal LB with in range private IP",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.1.0.3",
+						},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+					SKU:  SKUStandard,
+				},
+				Name: "my-private-lb",
+			},
+			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
+			wantErr: false,
+		},
+		{
+			name: "internal LB with a private frontend IP per zone",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.0.0.100",
+						},
+						Zones: []string{"1"},
+					},
+					{
+						Name: "ip-2",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.1.0.100",
+						},
+						Zones:      []string{"2"},
+						SubnetName: "cp-subnet-2",
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+					SKU:  SKUStandard,
+				},
+				Name: "my-private-lb",
+			},
+			cpCIDRS: []string{"10.0.0.0/24"},
+			subnets: Subnets{
+				{SubnetClassSpec: SubnetClassSpec{Name: "cp-subnet-2", CIDRBlocks: []string{"10.1.0.0/24"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "internal LB with a private IP out of the range of its subnet",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.0.0.100",
+						},
+					},
+					{
+						Name: "ip-2",
+						FrontendIPClass: FrontendIPClass{
+							PrivateIPAddress: "10.0.0.101",
+						},
+						SubnetName: "cp-subnet-2",
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			cpCIDRS: []string{"10.0.0.0/24"},
+			subnets: Subnets{
+				{SubnetClassSpec: SubnetClassSpec{Name: "cp-subnet-2", CIDRBlocks: []string{"10.1.0.0/24"}}},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "apiServerLB.frontendIPConfigs[1].privateIP",
+				BadValue: "10.0.0.101",
+				Detail:   "Internal LB IP address needs to be in cp-subnet-2 subnet range ([10.1.0.0/24])",
+			},
+		},
+		{
+			name: "internal LB with a frontend IP in an unknown subnet",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name:       "ip-1",
+						SubnetName: "foo",
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "apiServerLB.frontendIPConfigs[0].subnetName",
+				BadValue: "foo",
+				Detail:   "Internal LB frontend IP subnet should be one of the subnets of the cluster",
+			},
+		},
+		{
+			name: "internal LB with duplicate frontend IP names",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{Name: "ip-1"},
+					{Name: "ip-1"},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueDuplicate",
+				Field:    "apiServerLB.frontendIPConfigs[1].name",
+				BadValue: "ip-1",
+			},
+		},
+		{
+			name: "internal LB with a removed frontend IP",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{Name: "ip-1"},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			old: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{Name: "ip-1"},
+					{Name: "ip-2"},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "apiServerLB.frontendIPConfigs[1]",
+				Detail: "API Server load balancer frontend IPs cannot be removed after AzureCluster creation.",
+			},
+		},
+		{
+			name: "internal LB with changed frontend IP zones",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{Name: "ip-1", Zones: []string{"2"}},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			old: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{Name: "ip-1", Zones: []string{"1"}},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "apiServerLB.frontendIPConfigs[0].zones",
+				Detail: "API Server load balancer frontend IP zones should not be modified after AzureCluster creation.",
+			},
+		},
+		{
+			name: "public LB with a frontend IP pinned to a zone",
+			lb: LoadBalancerSpec{
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+						Zones:    []string{"1"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "apiServerLB.frontendIPConfigs[0].zones",
+				Detail: "Only frontend IPs of Internal Load Balancers can be pinned to zones",
+			},
+		},
+		{
+			name: "public LB with additional frontend IPs",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+					{
+						Name:     "ip-2",
+						PublicIP: &PublicIPSpec{Name: "pip-2"},
+					},
+				},
+				FrontendIPsCount: ptr.To[int32](2),
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type:                 Public,
+					SKU:                  SKUStandard,
+					IdleTimeoutInMinutes: ptr.To[int32](10),
+				},
+			},
+			old: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type:                 Public,
+					SKU:                  SKUStandard,
+					IdleTimeoutInMinutes: ptr.To[int32](4),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "public LB with too many frontend IPs",
+			lb: LoadBalancerSpec{
+				Name:             "my-public-lb",
+				FrontendIPsCount: ptr.To[int32](17),
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "apiServerLB.frontendIPsCount",
+				BadValue: 17,
+				Detail:   "API Server load balancer frontend IPs count should be between 1 and 16",
+			},
+		},
+		{
+			name: "public LB frontend IPs count decreased",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+				FrontendIPsCount: ptr.To[int32](1),
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			old: LoadBalancerSpec{
+				FrontendIPsCount: ptr.To[int32](2),
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "apiServerLB.frontendIPsCount",
+				Detail: "API Server load balancer frontend IPs count cannot be decreased after AzureCluster creation.",
+			},
+		},
+		{
+			name: "public LB with an existing public IP",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						PublicIP: &PublicIPSpec{
+							Name:     "byo-ip",
+							Existing: &ExistingPublicIP{ID: "/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/publicIPAddresses/byo-ip"},
+						},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "public LB with an existing public IP without id or DNS label",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Existing: &ExistingPublicIP{}},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueRequired",
+				Field:  "apiServerLB.frontendIPConfigs[0].publicIP.existing",
+				Detail: "one of id and dnsLabel must be set",
+			},
+		},
+		{
+			name: "public LB with an existing public IP with an invalid id",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Existing: &ExistingPublicIP{ID: "/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/loadBalancers/my-lb"}},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "apiServerLB.frontendIPConfigs[0].publicIP.existing.id",
+				BadValue: "/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/loadBalancers/my-lb",
+				Detail:   "id must be the resource ID of a Public IP",
+			},
+		},
+		{
+			name: "public LB with an existing public IP on an additional frontend IP",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+					{
+						Name:     "ip-2",
+						PublicIP: &PublicIPSpec{Existing: &ExistingPublicIP{DNSLabel: "my-api"}},
+					},
+				},
+				FrontendIPsCount: ptr.To[int32](2),
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "apiServerLB.frontendIPConfigs[1].publicIP.existing",
+				Detail: "Only the first frontend IP of the API server load balancer can use an existing Public IP",
+			},
+		},
+		{
+			name: "public LB switched to an existing public IP",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1", Existing: &ExistingPublicIP{DNSLabel: "my-api"}},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			old: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "apiServerLB.frontendIPConfigs[0].publicIP.existing",
+				Detail: "API server load balancer cannot switch between a new and an existing Public IP after AzureCluster creation.",
+			},
+		},
+		{
+			name: "public LB existing public IP resolved from a DNS label",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name: "ip-1",
+						PublicIP: &PublicIPSpec{Name: "byo-ip", Existing: &ExistingPublicIP{
+							ID:       "/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/publicIPAddresses/byo-ip",
+							DNSLabel: "my-api",
+						}},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			old: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Existing: &ExistingPublicIP{DNSLabel: "my-api"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "additional rule on a frontend port used by SSH NAT rules",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+					AdditionalRules: []LoadBalancingRule{
+						{Name: "ssh", Protocol: LoadBalancingRuleProtocolTCP, FrontendPort: 2201},
+					},
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "apiServerLB.additionalRules[0].frontendPort",
+				BadValue: int32(2201),
+				Detail:   "frontendPort cannot be 22 or between 2201 and 2219, which are used by the SSH inbound NAT rules of control plane machines",
+			},
+		},
+		{
+			name: "SSH NAT rules disabled after creation",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type:               Public,
+					SKU:                SKUStandard,
+					DisableSSHNATRules: true,
+				},
+			},
+			old: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "apiServerLB.disableSSHNATRules",
+				Detail: "API Server load balancer SSH NAT rules should not be enabled or disabled after AzureCluster creation.",
+			},
+		},
+		{
+			name: "Basic SKU on a new load balancer",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUBasic,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueNotSupported",
+				Field:    "apiServerLB.sku",
+				BadValue: SKUBasic,
+				Detail:   "supported values: \"Standard\"",
+			},
+		},
+		{
+			name: "Basic SKU kept on an existing load balancer",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUBasic,
+				},
+			},
+			old: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUBasic,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Basic SKU migrated to Standard",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			old: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUBasic,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Standard SKU changed to Basic",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUBasic,
+				},
+			},
+			old: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-1",
+						PublicIP: &PublicIPSpec{Name: "pip-1"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "apiServerLB.sku",
+				Detail: "API Server load balancer SKU should not be modified after AzureCluster creation.",
+			},
+		},
+	}
+
+	for _, test := range testcases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			err := validateAPIServerLB(test.lb, test.old, test.cpCIDRS, test.subnets, field.NewPath("apiServerLB"))
+			if test.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(test.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+func TestPrivateDNSZoneName(t *testing.T) {
+	testcases := []struct {
+		name        string
+		network     NetworkSpec
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name: "testInvalidPrivateDNSZoneName",
+			network: NetworkSpec{
+				NetworkClassSpec: NetworkClassSpec{
+					PrivateDNSZoneName: "wrong@d_ns.io",
+				},
+				APIServerLB: createValidAPIServerInternalLB(),
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.privateDNSZoneName",
+				BadValue: "wrong@d_ns.io",
+				Detail:   "PrivateDNSZoneName can only contain alphanumeric characters, underscores and dashes, must end with an alphanumeric character",
+			},
+			wantErr: true,
+		},
+		{
+			name: "testValidPrivateDNSZoneName",
+			network: NetworkSpec{
+				NetworkClassSpec: NetworkClassSpec{
+					PrivateDNSZoneName: "good.dns.io",
+				},
+				APIServerLB: createValidAPIServerInternalLB(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "testValidPrivateDNSZoneNameWithUnderscore",
+			network: NetworkSpec{
+				NetworkClassSpec: NetworkClassSpec{
+					PrivateDNSZoneName: "_good.__dns.io",
+				},
+				APIServerLB: createValidAPIServerInternalLB(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "testBadAPIServerLBType",
+			network: NetworkSpec{
+				NetworkClassSpec: NetworkClassSpec{
+					PrivateDNSZoneName: "good.dns.io",
+				},
+				APIServerLB: LoadBalancerSpec{
+					Name: "my-lb",
+					LoadBalancerClassSpec: LoadBalancerClassSpec{
+						Type: Public,
+					},
+				},
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.privateDNSZoneName",
+				BadValue: "Public",
+				Detail:   "PrivateDNSZoneName is available only if APIServerLB.Type is Internal",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range testcases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			err := validatePrivateDNSZoneName(test.network.PrivateDNSZoneName, test.network.APIServerLB.Type, field.NewPath("spec", "networkSpec", "privateDNSZoneName"))
+			if test.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(test.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateGateway(t *testing.T) {
+	vnet := VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/16"}}}
+	subnets := Subnets{
+		{SubnetClassSpec: SubnetClassSpec{Name: "node-subnet", CIDRBlocks: []string{"10.0.0.0/24"}}},
+	}
+	testcases := []struct {
+		name        string
+		gateway     *GatewaySpec
+		old         *GatewaySpec
+		subnets     Subnets
+		expectedErr string
+	}{
+		{
+			name: "no gateway",
+		},
+		{
+			name: "valid gateway",
+			gateway: &GatewaySpec{
+				CIDRBlocks:            []string{"10.0.255.0/27"},
+				VirtualNetworkGateway: &VirtualNetworkGatewaySpec{Type: VirtualNetworkGatewayTypeVpn},
+			},
+			subnets: subnets,
+		},
+		{
+			name:        "GatewaySubnet outside of the vnet",
+			gateway:     &GatewaySpec{CIDRBlocks: []string{"10.1.0.0/27"}},
+			subnets:     subnets,
+			expectedErr: "spec.networkSpec.gateway.cidrBlocks: Invalid value: \"10.1.0.0/27\": subnet CIDR not in vnet address space: [10.0.0.0/16]",
+		},
+		{
+			name:        "GatewaySubnet too small",
+			gateway:     &GatewaySpec{CIDRBlocks: []string{"10.0.255.0/30"}},
+			subnets:     subnets,
+			expectedErr: "spec.networkSpec.gateway.cidrBlocks: Invalid value: \"10.0.255.0/30\": the GatewaySubnet must be /29 or larger",
+		},
+		{
+			name:        "GatewaySubnet overlapping another subnet",
+			gateway:     &GatewaySpec{CIDRBlocks: []string{"10.0.0.128/27"}},
+			subnets:     subnets,
+			expectedErr: "spec.networkSpec.gateway.cidrBlocks: Invalid value: \"10.0.0.128/27\": subnet CIDR overlaps with CIDR 10.0.0.0/24 of spec.networkSpec.subnets[0]",
+		},
+		{
+			name:    "GatewaySubnet in the subnets",
+			gateway: &GatewaySpec{CIDRBlocks: []string{"10.0.255.0/27"}},
+			subnets: Subnets{
+				{SubnetClassSpec: SubnetClassSpec{Name: GatewaySubnetName, CIDRBlocks: []string{"10.0.254.0/27"}}},
+			},
+			expectedErr: "spec.networkSpec.subnets[0].name: Forbidden: the GatewaySubnet subnet is configured by the gateway field",
+		},
+		{
+			name: "gateway type changed",
+			gateway: &GatewaySpec{
+				CIDRBlocks:            []string{"10.0.255.0/27"},
+				VirtualNetworkGateway: &VirtualNetworkGatewaySpec{Type: VirtualNetworkGatewayTypeExpressRoute},
+			},
+			old: &GatewaySpec{
+				CIDRBlocks:            []string{"10.0.255.0/27"},
+				VirtualNetworkGateway: &VirtualNetworkGatewaySpec{Type: VirtualNetworkGatewayTypeVpn},
+			},
+			expectedErr: "spec.networkSpec.gateway.virtualNetworkGateway.type: Forbidden: the type of the virtual network gateway cannot be changed",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateGateway(tc.gateway, tc.old, tc.subnets, vnet, field.NewPath("spec", "networkSpec", "gateway"), field.NewPath("spec", "networkSpec", "subnets"))
+			if tc.expectedErr == "" {
+				g.Expect(errs).To(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(HaveLen(1))
+			g.Expect(errs[0].Error()).To(Equal(tc.expectedErr))
+		})
+	}
+}
+
+func TestValidateApplicationGateway(t *testing.T) {
+	networkSpec := NetworkSpec{
+		Vnet: VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/16"}}},
+		Subnets: Subnets{
+			{SubnetClassSpec: SubnetClassSpec{Name: "node-subnet", CIDRBlocks: []string{"10.0.0.0/24"}}},
+		},
+		Gateway: &GatewaySpec{CIDRBlocks: []string{"10.0.255.192/27"}},
+	}
+	testcases := []struct {
+		name        string
+		appGateway  *ApplicationGatewaySpec
+		expectedErr string
+	}{
+		{
+			name: "no application gateway",
+		},
+		{
+			name: "valid application gateway",
+			appGateway: &ApplicationGatewaySpec{
+				SubnetName:  "appgw-subnet",
+				CIDRBlocks:  []string{"10.0.255.128/26"},
+				MinCapacity: 2,
+				MaxCapacity: ptr.To[int32](2),
+				WAF:         &WebApplicationFirewallSpec{Mode: WebApplicationFirewallModePrevention},
+			},
+		},
+		{
+			name:        "subnet outside of the vnet",
+			appGateway:  &ApplicationGatewaySpec{SubnetName: "appgw-subnet", CIDRBlocks: []string{"10.1.0.0/26"}},
+			expectedErr: "spec.applicationGateway.cidrBlocks: Invalid value: \"10.1.0.0/26\": subnet CIDR not in vnet address space: [10.0.0.0/16]",
+		},
+		{
+			name:        "subnet overlapping a subnet of the cluster",
+			appGateway:  &ApplicationGatewaySpec{SubnetName: "appgw-subnet", CIDRBlocks: []string{"10.0.0.128/26"}},
+			expectedErr: "spec.applicationGateway.cidrBlocks: Invalid value: \"10.0.0.128/26\": subnet CIDR overlaps with CIDR 10.0.0.0/24 of spec.networkSpec.subnets[0]",
+		},
+		{
+			name:        "subnet overlapping the GatewaySubnet",
+			appGateway:  &ApplicationGatewaySpec{SubnetName: "appgw-subnet", CIDRBlocks: []string{"10.0.255.192/26"}},
+			expectedErr: "spec.applicationGateway.cidrBlocks: Invalid value: \"10.0.255.192/26\": subnet CIDR overlaps with CIDR 10.0.255.192/27 of spec.networkSpec.gateway",
+		},
+		{
+			name:        "subnet of the cluster",
+			appGateway:  &ApplicationGatewaySpec{SubnetName: "node-subnet", CIDRBlocks: []string{"10.0.255.128/26"}},
+			expectedErr: "spec.applicationGateway.subnetName: Invalid value: \"node-subnet\": the subnet of the application gateway cannot be spec.networkSpec.subnets[0], as it must be dedicated to the application gateway",
+		},
+		{
+			name:        "GatewaySubnet",
+			appGateway:  &ApplicationGatewaySpec{SubnetName: GatewaySubnetName, CIDRBlocks: []string{"10.0.255.128/26"}},
+			expectedErr: "spec.applicationGateway.subnetName: Invalid value: \"GatewaySubnet\": the GatewaySubnet subnet is reserved for virtual network gateways",
+		},
+		{
+			name: "max capacity lower than min capacity",
+			appGateway: &ApplicationGatewaySpec{
+				SubnetName:  "appgw-subnet",
+				CIDRBlocks:  []string{"10.0.255.128/26"},
+				MinCapacity: 3,
+				MaxCapacity: ptr.To[int32](2),
+			},
+			expectedErr: "spec.applicationGateway.maxCapacity: Invalid value: 2: must not be lower than minCapacity",
+		},
+		{
+			name: "existing public IP",
+			appGateway: &ApplicationGatewaySpec{
+				SubnetName: "appgw-subnet",
+				CIDRBlocks: []string{"10.0.255.128/26"},
+				PublicIP:   PublicIPSpec{Existing: &ExistingPublicIP{DNSLabel: "my-ip"}},
+			},
+			expectedErr: "spec.applicationGateway.publicIP.existing: Forbidden: the application gateway cannot use an existing Public IP",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateApplicationGateway(tc.appGateway, networkSpec, field.NewPath("spec", "applicationGateway"), field.NewPath("spec", "networkSpec"))
+			if tc.expectedErr == "" {
+				g.Expect(errs).To(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(HaveLen(1))
+			g.Expect(errs[0].Error()).To(Equal(tc.expectedErr))
+		})
+	}
+}
+
+func TestValidateDDoSProtectionPlan(t *testing.T) {
+	testcases := []struct {
+		name        string
+		plan        *DDoSProtectionPlanSpec
+		expectedErr string
+	}{
+		{
+			name: "no DDoS protection plan",
+			plan: nil,
+		},
+		{
+			name: "new DDoS protection plan",
+			plan: &DDoSProtectionPlanSpec{Name: "my-plan"},
+		},
+		{
+			name: "existing DDoS protection plan",
+			plan: &DDoSProtectionPlanSpec{ID: "/subscriptions/123/resourceGroups/security-rg/providers/Microsoft.Network/ddosProtectionPlans/shared-plan"},
+		},
+		{
+			name:        "invalid DDoS protection plan ID",
+			plan:        &DDoSProtectionPlanSpec{ID: "shared-plan"},
+			expectedErr: "spec.networkSpec.vnet.ddosProtectionPlan.id: Invalid value: \"shared-plan\": id must be the resource ID of a DDoS protection plan",
+		},
+		{
+			name: "name set with ID",
+			plan: &DDoSProtectionPlanSpec{
+				ID:   "/subscriptions/123/resourceGroups/security-rg/providers/Microsoft.Network/ddosProtectionPlans/shared-plan",
+				Name: "my-plan",
+			},
+			expectedErr: "spec.networkSpec.vnet.ddosProtectionPlan.name: Forbidden: name cannot be set with id",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateDDoSProtectionPlan(tc.plan, field.NewPath("spec", "networkSpec", "vnet", "ddosProtectionPlan"))
+			if tc.expectedErr == "" {
+				g.Expect(errs).To(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(HaveLen(1))
+			g.Expect(errs[0].Error()).To(Equal(tc.expectedErr))
+		})
+	}
+}
+
+func TestValidateFlowLogs(t *testing.T) {
+	storageAccountID := "/subscriptions/123/resourceGroups/logs-rg/providers/Microsoft.Storage/storageAccounts/flowlogs"
+	workspaceResourceID := "/subscriptions/123/resourceGroups/logs-rg/providers/Microsoft.OperationalInsights/workspaces/logs"
+	testcases := []struct {
+		name        string
+		flowLogs    *FlowLogsSpec
+		expectedErr string
+	}{
+		{
+			name:     "flow logs disabled",
+			flowLogs: nil,
+		},
+		{
+			name:     "valid flow logs",
+			flowLogs: &FlowLogsSpec{StorageAccountID: storageAccountID},
+		},
+		{
+			name: "valid flow logs with traffic analytics",
+			flowLogs: &FlowLogsSpec{
+				StorageAccountID: storageAccountID,
+				TrafficAnalytics: &TrafficAnalyticsSpec{
+					WorkspaceResourceID: workspaceResourceID,
+					WorkspaceID:         "8a1c3a0e-5b0c-4d8e-9c5f-1d2e3f4a5b6c",
+				},
+			},
+		},
+		{
+			name:        "invalid storage account ID",
+			flowLogs:    &FlowLogsSpec{StorageAccountID: workspaceResourceID},
+			expectedErr: "spec.networkSpec.flowLogs.storageAccountID: Invalid value: \"" + workspaceResourceID + "\": storageAccountID must be the resource ID of a storage account",
+		},
+		{
+			name: "invalid workspace resource ID",
+			flowLogs: &FlowLogsSpec{
+				StorageAccountID: storageAccountID,
+				TrafficAnalytics: &TrafficAnalyticsSpec{
+					WorkspaceResourceID: "logs",
+					WorkspaceID:         "8a1c3a0e-5b0c-4d8e-9c5f-1d2e3f4a5b6c",
+				},
+			},
+			expectedErr: "spec.networkSpec.flowLogs.trafficAnalytics.workspaceResourceID: Invalid value: \"logs\": workspaceResourceID must be the resource ID of a Log Analytics workspace",
+		},
+		{
+			name: "invalid workspace ID",
+			flowLogs: &FlowLogsSpec{
+				StorageAccountID: storageAccountID,
+				TrafficAnalytics: &TrafficAnalyticsSpec{
+					WorkspaceResourceID: workspaceResourceID,
+					WorkspaceID:         "logs",
+				},
+			},
+			expectedErr: "spec.networkSpec.flowLogs.trafficAnalytics.workspaceID: Invalid value: \"logs\": workspaceID must be a GUID",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateFlowLogs(tc.flowLogs, field.NewPath("spec", "networkSpec", "flowLogs"))
+			if tc.expectedErr == "" {
+				g.Expect(errs).To(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(HaveLen(1))
+			g.Expect(errs[0].Error()).To(Equal(tc.expectedErr))
+		})
+	}
+}
+
+func TestValidateDiagnosticSettings(t *testing.T) {
+	workspaceResourceID := "/subscriptions/123/resourceGroups/logs-rg/providers/Microsoft.OperationalInsights/workspaces/logs"
+	testcases := []struct {
+		name               string
+		diagnosticSettings *DiagnosticSettingsSpec
+		expectedErr        string
+	}{
+		{
+			name:               "diagnostic settings disabled",
+			diagnosticSettings: nil,
+		},
+		{
+			name:               "valid diagnostic settings",
+			diagnosticSettings: &DiagnosticSettingsSpec{WorkspaceResourceID: workspaceResourceID},
+		},
+		{
+			name: "valid diagnostic settings with categories",
+			diagnosticSettings: &DiagnosticSettingsSpec{
+				WorkspaceResourceID: workspaceResourceID,
+				Categories:          []DiagnosticLogCategory{NetworkSecurityGroupEventCategory, LoadBalancerHealthEventCategory},
+			},
+		},
+		{
+			name:               "invalid workspace resource ID",
+			diagnosticSettings: &DiagnosticSettingsSpec{WorkspaceResourceID: "logs"},
+			expectedErr:        "spec.networkSpec.diagnosticSettings.workspaceResourceID: Invalid value: \"logs\": workspaceResourceID must be the resource ID of a Log Analytics workspace",
+		},
+		{
+			name: "duplicate category",
+			diagnosticSettings: &DiagnosticSettingsSpec{
+				WorkspaceResourceID: workspaceResourceID,
+				Categories:          []DiagnosticLogCategory{NetworkSecurityGroupEventCategory, NetworkSecurityGroupEventCategory},
+			},
+			expectedErr: "spec.networkSpec.diagnosticSettings.categories[1]: Duplicate value: \"NetworkSecurityGroupEvent\"",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateDiagnosticSettings(tc.diagnosticSettings, field.NewPath("spec", "networkSpec", "diagnosticSettings"))
+			if tc.expectedErr == "" {
+				g.Expect(errs).To(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(HaveLen(1))
+			g.Expect(errs[0].Error()).To(Equal(tc.expectedErr))
+		})
+	}
+}
+
+func TestValidateEgressProxy(t *testing.T) {
+	apiServerLB := LoadBalancerSpec{
+		LoadBalancerClassSpec: LoadBalancerClassSpec{
+			AdditionalRules: []LoadBalancingRule{
+				{
+					Name:         "konnectivity",
+					Protocol:     LoadBalancingRuleProtocolTCP,
+					FrontendPort: 8133,
+				},
+			},
+		},
+	}
+	testcases := []struct {
+		name        string
+		egressProxy *EgressProxySpec
+		old         *EgressProxySpec
+		expectedErr string
+	}{
+		{
+			name: "no egress proxy",
+		},
+		{
+			name:        "valid egress proxy",
+			egressProxy: &EgressProxySpec{Port: 8132},
+		},
+		{
+			name:        "unchanged egress proxy",
+			egressProxy: &EgressProxySpec{Port: 8132},
+			old:         &EgressProxySpec{Port: 8132},
+		},
+		{
+			name:        "egress proxy removed",
+			old:         &EgressProxySpec{Port: 8132},
+			expectedErr: "spec.networkSpec.egressProxy: Forbidden: egress proxy cannot be removed after it is set",
+		},
+		{
+			name:        "port out of range",
+			egressProxy: &EgressProxySpec{Port: 65535},
+			expectedErr: "spec.networkSpec.egressProxy.port: Invalid value: 65535: port should be between 1 and 65534",
+		},
+		{
+			name:        "port used by SSH inbound NAT rules",
+			egressProxy: &EgressProxySpec{Port: 2201},
+			expectedErr: "spec.networkSpec.egressProxy.port: Invalid value: 2201: port cannot be 22 or between 2201 and 2219, which are used by the SSH inbound NAT rules of control plane machines",
+		},
+		{
+			name:        "port used by an additional rule",
+			egressProxy: &EgressProxySpec{Port: 8133},
+			expectedErr: "spec.networkSpec.egressProxy.port: Invalid value: 8133: port is already used by the additional rule konnectivity of the API server load balancer",
+		},
+		{
+			name:        "port changed",
+			egressProxy: &EgressProxySpec{Port: 8134},
+			old:         &EgressProxySpec{Port: 8132},
+			expectedErr: "spec.networkSpec.egressProxy.port: Forbidden: egress proxy port cannot be changed after it is set",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateEgressProxy(tc.egressProxy, tc.old, apiServerLB, field.NewPath("spec", "networkSpec", "egressProxy"))
+			if tc.expectedErr == "" {
+				g.Expect(errs).To(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(HaveLen(1))
+			g.Expect(errs[0].Error()).To(Equal(tc.expectedErr))
+		})
+	}
+}
+
+func TestValidateControlPlaneExtraRules(t *testing.T) {
+	subnetRules := SecurityRules{
+		{Name: "allow_ssh", Priority: 2200, Direction: SecurityRuleDirectionInbound, Protocol: SecurityGroupProtocolTCP},
+		{Name: "allow_apiserver", Priority: 2201, Direction: SecurityRuleDirectionInbound, Protocol: SecurityGroupProtocolTCP},
+	}
+	etcdRule := SecurityRule{
+		Name:             "allow_etcd",
+		Description:      "Allow etcd from the control plane",
+		Direction:        SecurityRuleDirectionInbound,
+		Protocol:         SecurityGroupProtocolTCP,
+		DestinationPorts: ptr.To("2379-2380"),
+		Action:           SecurityRuleActionAllow,
+	}
+	testcases := []struct {
+		name        string
+		rules       SecurityRules
+		expectedErr string
+	}{
+		{
+			name: "no extra rules",
+		},
+		{
+			name:  "rule with a managed priority",
+			rules: SecurityRules{etcdRule},
+		},
+		{
+			name: "rule with a priority of the other direction",
+			rules: SecurityRules{func() SecurityRule {
+				rule := *etcdRule.DeepCopy()
+				rule.Direction = SecurityRuleDirectionOutbound
+				rule.Priority = 2200
+				return rule
+			}()},
 		},
 		{
-			name: "internal LB with invalid private IP",
-			lb: LoadBalancerSpec{
-				FrontendIPs: []FrontendIP{
-					{
-						Name: "ip-1",
-						FrontendIPClass: FrontendIPClass{
-							PrivateIPAddress: "NAIP",
-						},
-					},
-				},
-				LoadBalancerClassSpec: LoadBalancerClassSpec{
-					Type: Internal,
-				},
-			},
-			wantErr: true,
-			expectedErr: field.Error{
-				Type:     "FieldValueInvalid",
-				Field:    "apiServerLB.frontendIPConfigs[0].privateIP",
-				BadValue: "NAIP",
-				Detail:   "Internal LB IP address isn't a valid IPv4 or IPv6 address",
-			},
+			name: "rule with a subnet rule name",
+			rules: SecurityRules{func() SecurityRule {
+				rule := *etcdRule.DeepCopy()
+				rule.Name = "allow_ssh"
+				return rule
+			}()},
+			expectedErr: `spec.networkSpec.controlPlaneExtraRules[0].name: Duplicate value: "allow_ssh"`,
 		},
 		{
-			name: "internal LB with out of range private IP",
-			lb: LoadBalancerSpec{
-				FrontendIPs: []FrontendIP{
-					{
-						Name: "ip-1",
-						FrontendIPClass: FrontendIPClass{
-							PrivateIPAddress: "20.1.2.3",
-						},
-					},
-				},
-				LoadBalancerClassSpec: LoadBalancerClassSpec{
-					Type: Internal,
-				},
-			},
-			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
-			wantErr: true,
-			expectedErr: field.Error{
-				Type:     "FieldValueInvalid",
-				Field:    "apiServerLB.frontendIPConfigs[0].privateIP",
-				BadValue: "20.1.2.3",
-				Detail:   "Internal LB IP address needs to be in control plane subnet range ([10.0.0.0/24 10.1.0.0/24])",
-			},
+			name: "rule with a subnet rule priority",
+			rules: SecurityRules{func() SecurityRule {
+				rule := *etcdRule.DeepCopy()
+				rule.Priority = 2201
+				return rule
+			}()},
+			expectedErr: "spec.networkSpec.controlPlaneExtraRules[0].priority: Duplicate value: 2201",
 		},
 		{
-			name: "internal LB with in range private IP",
-			lb: LoadBalancerSpec{
-				FrontendIPs: []FrontendIP{
-					{
-						Name: "ip-1",
-						FrontendIPClass: FrontendIPClass{
-							PrivateIPAddress: "10.1.0.3",
-						},
-					},
-				},
-				LoadBalancerClassSpec: LoadBalancerClassSpec{
-					Type: Internal,
-					SKU:  SKUStandard,
-				},
-				Name: "my-private-lb",
-			},
-			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
-			wantErr: false,
+			name: "rule with a priority out of range",
+			rules: SecurityRules{func() SecurityRule {
+				rule := *etcdRule.DeepCopy()
+				rule.Priority = 5000
+				return rule
+			}()},
+			expectedErr: "spec.networkSpec.controlPlaneExtraRules[0]: Invalid value: 5000: security rule priorities should be between 100 and 4096",
 		},
 	}
-
-	for _, test := range testcases {
-		test := test
-		t.Run(test.name, func(t *testing.T) {
-			t.Parallel()
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
 			g := NewWithT(t)
-			err := validateAPIServerLB(test.lb, test.old, test.cpCIDRS, field.NewPath("apiServerLB"))
-			if test.wantErr {
-				g.Expect(err).To(ContainElement(MatchError(test.expectedErr.Error())))
-			} else {
-				g.Expect(err).To(BeEmpty())
+			errs := validateControlPlaneExtraRules(tc.rules, subnetRules, field.NewPath("spec", "networkSpec", "controlPlaneExtraRules"))
+			if tc.expectedErr == "" {
+				g.Expect(errs).To(BeEmpty())
+				return
 			}
+			g.Expect(errs).To(HaveLen(1))
+			g.Expect(errs[0].Error()).To(Equal(tc.expectedErr))
 		})
 	}
 }
-func TestPrivateDNSZoneName(t *testing.T) {
+
+func TestControlPlaneSecurityRules(t *testing.T) {
+	defaultRules := SecurityRules{
+		{Name: "allow_ssh", Direction: SecurityRuleDirectionInbound, Priority: 2200},
+		{Name: "allow_apiserver", Direction: SecurityRuleDirectionInbound, Priority: 2201},
+	}
+	subnetRules := SecurityRules{
+		{Name: "allow_https", Direction: SecurityRuleDirectionInbound, Priority: 2203},
+	}
+	testcases := []struct {
+		name          string
+		networkSpec   NetworkSpec
+		subnetRules   SecurityRules
+		expectedRules SecurityRules
+	}{
+		{
+			name:          "default rules of an IPv4 cluster",
+			expectedRules: defaultRules,
+		},
+		{
+			name:        "default rules of a dual-stack cluster",
+			networkSpec: NetworkSpec{Vnet: VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/8", "2001:1234:5678:9a00::/56"}}}},
+			expectedRules: append(defaultRules[:2:2],
+				SecurityRule{Name: "allow_icmpv6", Direction: SecurityRuleDirectionInbound, Priority: 2202}),
+		},
+		{
+			name:        "default rules with the egress proxy rule",
+			networkSpec: NetworkSpec{EgressProxy: &EgressProxySpec{Port: 8132}},
+			expectedRules: append(defaultRules[:2:2],
+				SecurityRule{Name: "allow_egress_proxy", Direction: SecurityRuleDirectionInbound, Priority: 2203}),
+		},
+		{
+			name:          "subnet rules",
+			subnetRules:   subnetRules,
+			expectedRules: subnetRules,
+		},
+		{
+			name:        "subnet rules with the egress proxy rule",
+			networkSpec: NetworkSpec{EgressProxy: &EgressProxySpec{Port: 8132}},
+			subnetRules: subnetRules,
+			expectedRules: append(subnetRules[:1:1],
+				SecurityRule{Name: "allow_egress_proxy", Direction: SecurityRuleDirectionInbound, Priority: 2204}),
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			subnet := SubnetSpec{SecurityGroup: SecurityGroup{SecurityGroupClass: SecurityGroupClass{SecurityRules: tc.subnetRules}}}
+			rules := controlPlaneSecurityRules(tc.networkSpec, subnet)
+			g.Expect(rules).To(Equal(tc.expectedRules))
+			g.Expect(validateControlPlaneExtraRules(SecurityRules{{
+				Name:      "allow_ssh",
+				Direction: SecurityRuleDirectionInbound,
+				Priority:  rules[len(rules)-1].Priority,
+				Protocol:  SecurityGroupProtocolTCP,
+				Action:    SecurityRuleActionAllow,
+			}}, rules, field.NewPath("spec", "networkSpec", "controlPlaneExtraRules"))).NotTo(BeEmpty())
+		})
+	}
+}
+
+func TestValidateAPIServerDNSRecord(t *testing.T) {
 	testcases := []struct {
 		name        string
-		network     NetworkSpec
+		record      *DNSRecordSpec
+		lbType      LBType
 		wantErr     bool
 		expectedErr field.Error
 	}{
 		{
-			name: "testInvalidPrivateDNSZoneName",
-			network: NetworkSpec{
-				NetworkClassSpec: NetworkClassSpec{
-					PrivateDNSZoneName: "wrong@d_ns.io",
-				},
-				APIServerLB: createValidAPIServerInternalLB(),
+			name:    "no DNS record",
+			record:  nil,
+			lbType:  Internal,
+			wantErr: false,
+		},
+		{
+			name: "valid DNS record",
+			record: &DNSRecordSpec{
+				ZoneName:   "example.com",
+				RecordName: "my-cluster",
+			},
+			lbType:  Public,
+			wantErr: false,
+		},
+		{
+			name: "valid DNS record at the zone apex",
+			record: &DNSRecordSpec{
+				ZoneName:   "example.com",
+				RecordName: "@",
+			},
+			lbType:  Public,
+			wantErr: false,
+		},
+		{
+			name: "internal API server load balancer",
+			record: &DNSRecordSpec{
+				ZoneName: "example.com",
 			},
+			lbType: Internal,
 			expectedErr: field.Error{
 				Type:     "FieldValueInvalid",
-				Field:    "spec.networkSpec.privateDNSZoneName",
-				BadValue: "wrong@d_ns.io",
-				Detail:   "PrivateDNSZoneName can only contain alphanumeric characters, underscores and dashes, must end with an alphanumeric character",
+				Field:    "spec.networkSpec.apiServerDNSRecord",
+				BadValue: &DNSRecordSpec{ZoneName: "example.com"},
+				Detail:   "API server DNS record is available only if APIServerLB.Type is Public",
 			},
 			wantErr: true,
 		},
 		{
-			name: "testValidPrivateDNSZoneName",
-			network: NetworkSpec{
-				NetworkClassSpec: NetworkClassSpec{
-					PrivateDNSZoneName: "good.dns.io",
-				},
-				APIServerLB: createValidAPIServerInternalLB(),
+			name: "invalid zone name",
+			record: &DNSRecordSpec{
+				ZoneName: "wrong@zone",
 			},
-			wantErr: false,
-		},
-		{
-			name: "testValidPrivateDNSZoneNameWithUnderscore",
-			network: NetworkSpec{
-				NetworkClassSpec: NetworkClassSpec{
-					PrivateDNSZoneName: "_good.__dns.io",
-				},
-				APIServerLB: createValidAPIServerInternalLB(),
+			lbType: Public,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.apiServerDNSRecord.zoneName",
+				BadValue: "wrong@zone",
+				Detail:   "zoneName must be a valid DNS zone name",
 			},
-			wantErr: false,
+			wantErr: true,
 		},
 		{
-			name: "testBadAPIServerLBType",
-			network: NetworkSpec{
-				NetworkClassSpec: NetworkClassSpec{
-					PrivateDNSZoneName: "good.dns.io",
-				},
-				APIServerLB: LoadBalancerSpec{
-					Name: "my-lb",
-					LoadBalancerClassSpec: LoadBalancerClassSpec{
-						Type: Public,
-					},
-				},
+			name: "invalid record name",
+			record: &DNSRecordSpec{
+				ZoneName:   "example.com",
+				RecordName: "bad_record!",
 			},
+			lbType: Public,
 			expectedErr: field.Error{
 				Type:     "FieldValueInvalid",
-				Field:    "spec.networkSpec.privateDNSZoneName",
-				BadValue: "Public",
-				Detail:   "PrivateDNSZoneName is available only if APIServerLB.Type is Internal",
+				Field:    "spec.networkSpec.apiServerDNSRecord.recordName",
+				BadValue: "bad_record!",
+				Detail:   "recordName must be a valid relative DNS name or @",
 			},
 			wantErr: true,
 		},
@@ -966,7 +3029,7 @@ func TestPrivateDNSZoneName(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 			g := NewWithT(t)
-			err := validatePrivateDNSZoneName(test.network.PrivateDNSZoneName, test.network.APIServerLB.Type, field.NewPath("spec", "networkSpec", "privateDNSZoneName"))
+			err := validateAPIServerDNSRecord(test.record, test.lbType, field.NewPath("spec", "networkSpec", "apiServerDNSRecord"))
 			if test.wantErr {
 				g.Expect(err).To(ContainElement(MatchError(test.expectedErr.Error())))
 			} else {
@@ -1011,6 +3074,38 @@ func TestValidateNodeOutboundLB(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "lb allowed for internal clusters",
+			lb: &LoadBalancerSpec{
+				Name: "my-cluster",
+			},
+			apiServerLB: LoadBalancerSpec{
+				Name: "my-cluster-internal-lb",
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "lb cannot share the api server lb",
+			lb: &LoadBalancerSpec{
+				Name: "my-cluster-public-lb",
+			},
+			apiServerLB: LoadBalancerSpec{
+				Name: "my-cluster-public-lb",
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "nodeOutboundLB.name",
+				BadValue: "my-cluster-public-lb",
+				Detail:   "Node outbound load balancer cannot share the API server load balancer.",
+			},
+		},
 		{
 			name: "invalid ID update",
 			lb: &LoadBalancerSpec{
@@ -1116,6 +3211,33 @@ func TestValidateNodeOutboundLB(t *testing.T) {
 				Detail:   "Max front end ips allowed is 16",
 			},
 		},
+		{
+			name: "frontend ips count is negative",
+			lb: &LoadBalancerSpec{
+				FrontendIPsCount: ptr.To[int32](-1),
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "nodeOutboundLB.frontendIPsCount",
+				BadValue: -1,
+				Detail:   "must be greater than or equal to 0",
+			},
+		},
+		{
+			name: "floating IP on the node outbound lb",
+			lb: &LoadBalancerSpec{
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					EnableFloatingIP: ptr.To(true),
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "nodeOutboundLB.enableFloatingIP",
+				Detail: "Node outbound load balancer does not support floating IP.",
+			},
+		},
 	}
 
 	for _, test := range testcases {
@@ -1196,6 +3318,24 @@ func TestValidateControlPlaneNodeOutboundLB(t *testing.T) {
 				Detail:   "Max front end ips allowed is 16",
 			},
 		},
+		{
+			name: "frontend ips count is negative",
+			lb: &LoadBalancerSpec{
+				FrontendIPsCount: ptr.To[int32](-1),
+			},
+			apiServerLB: LoadBalancerSpec{
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "controlPlaneOutboundLB.frontendIPsCount",
+				BadValue: -1,
+				Detail:   "must be greater than or equal to 0",
+			},
+		},
 	}
 
 	for _, test := range testcases {
@@ -1547,3 +3687,50 @@ func TestClusterWithExtendedLocationInvalid(t *testing.T) {
 		g.Expect(err).NotTo(BeNil())
 	})
 }
+
+func TestValidateSSHPublicKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    *SSHPublicKeysSpec
+		wantErr bool
+	}{
+		{
+			name:    "no keys",
+			keys:    nil,
+			wantErr: false,
+		},
+		{
+			name: "valid keys",
+			keys: &SSHPublicKeysSpec{
+				ControlPlane: []string{validSSHPublicKey},
+				Nodes:        []string{validSSHPublicKey, generateSSHPublicKey(true)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid control plane key",
+			keys: &SSHPublicKeysSpec{
+				ControlPlane: []string{"invalidKey"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid node key",
+			keys: &SSHPublicKeysSpec{
+				Nodes: []string{validSSHPublicKey, "invalidKey"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateSSHPublicKeys(tc.keys, field.NewPath("spec", "sshPublicKeys"))
+			if tc.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"reflect"
+	"strings"
 
+	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -28,10 +31,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-// SetupWebhookWithManager sets up and registers the webhook with the manager.
-func (c *AzureCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// SetupAzureClusterWebhookWithManager sets up and registers the webhook with the manager. AzureClusters created
+// without a location get the location returned by defaultLocation for their subscription, when it is not nil. The
+// locations of the clusters are only checked against the locations available to their subscriptions when
+// checkLocations is not nil. New clusters are rejected when they exceed a quota of the management cluster returned by
+// checkQuota, when it is not nil.
+func SetupAzureClusterWebhookWithManager(mgr ctrl.Manager, defaultLocation LocationDefaulter, checkLocations LocationChecker, checkQuota ClusterQuotaChecker) error {
+	w := &azureClusterWebhook{defaultLocation: defaultLocation, checkLocations: checkLocations, checkQuota: checkQuota}
 	return ctrl.NewWebhookManagedBy(mgr).
-		For(c).
+		For(&AzureCluster{}).
+		WithDefaulter(w).
+		WithValidator(w).
 		Complete()
 }
 
@@ -63,6 +73,13 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) (admission.Warnings
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "NetworkResourceGroup"),
+		old.Spec.NetworkResourceGroup,
+		c.Spec.NetworkResourceGroup); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "SubscriptionID"),
 		old.Spec.SubscriptionID,
@@ -77,14 +94,92 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) (admission.Warnings
 		allErrs = append(allErrs, err)
 	}
 
-	if old.Spec.ControlPlaneEndpoint.Host != "" && c.Spec.ControlPlaneEndpoint.Host != old.Spec.ControlPlaneEndpoint.Host {
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "ExtendedLocation"),
+		old.Spec.ExtendedLocation,
+		c.Spec.ExtendedLocation); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "ResourceNaming"),
+		old.Spec.ResourceNaming,
+		c.Spec.ResourceNaming); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	// An unset policy is equivalent to Enabled.
+	if (old.Spec.PublicNetworkAccess == PublicNetworkAccessDisabled) != (c.Spec.PublicNetworkAccess == PublicNetworkAccessDisabled) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "publicNetworkAccess"), c.Spec.PublicNetworkAccess, "field is immutable"))
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "ZoneRedundantPublicIPs"),
+		old.Spec.ZoneRedundantPublicIPs,
+		c.Spec.ZoneRedundantPublicIPs); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	// The fault domain count of the existing availability sets cannot change, so it cannot be set after creation either.
+	if !reflect.DeepEqual(old.Spec.PlatformFaultDomainCount, c.Spec.PlatformFaultDomainCount) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "platformFaultDomainCount"), c.Spec.PlatformFaultDomainCount, "field is immutable"))
+	}
+
+	// The settings of the bootstrap data storage account are reconciled, only its name is immutable.
+	if old.Spec.BootstrapDataStorage == nil || c.Spec.BootstrapDataStorage == nil {
+		if err := webhookutils.ValidateImmutable(
+			field.NewPath("Spec", "BootstrapDataStorage"),
+			old.Spec.BootstrapDataStorage,
+			c.Spec.BootstrapDataStorage); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	} else if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "BootstrapDataStorage", "StorageAccountName"),
+		old.Spec.BootstrapDataStorage.StorageAccountName,
+		c.Spec.BootstrapDataStorage.StorageAccountName); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "BootstrapDataKeyVault"),
+		old.Spec.BootstrapDataKeyVault,
+		c.Spec.BootstrapDataKeyVault); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "ManagedIdentity"),
+		old.Spec.ManagedIdentity,
+		c.Spec.ManagedIdentity); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	// The vnet name and resource group are defaulted on create, so only reject changes once they are set.
+	if old.Spec.NetworkSpec.Vnet.Name != "" && c.Spec.NetworkSpec.Vnet.Name != old.Spec.NetworkSpec.Vnet.Name {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "networkSpec", "vnet", "name"),
+				c.Spec.NetworkSpec.Vnet.Name, "field is immutable"),
+		)
+	}
+
+	if old.Spec.NetworkSpec.Vnet.ResourceGroup != "" && c.Spec.NetworkSpec.Vnet.ResourceGroup != old.Spec.NetworkSpec.Vnet.ResourceGroup {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "networkSpec", "vnet", "resourceGroup"),
+				c.Spec.NetworkSpec.Vnet.ResourceGroup, "field is immutable"),
+		)
+	}
+
+	rotatingEndpoint := c.Annotations[RotateControlPlaneEndpointAnnotation] == "true"
+	if !rotatingEndpoint && old.Spec.ControlPlaneEndpoint.Host != "" && c.Spec.ControlPlaneEndpoint.Host != old.Spec.ControlPlaneEndpoint.Host {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "ControlPlaneEndpoint", "Host"),
 				c.Spec.ControlPlaneEndpoint.Host, "field is immutable"),
 		)
 	}
 
-	if old.Spec.ControlPlaneEndpoint.Port != 0 && c.Spec.ControlPlaneEndpoint.Port != old.Spec.ControlPlaneEndpoint.Port {
+	if !rotatingEndpoint && old.Spec.ControlPlaneEndpoint.Port != 0 && c.Spec.ControlPlaneEndpoint.Port != old.Spec.ControlPlaneEndpoint.Port {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "ControlPlaneEndpoint", "Port"),
 				c.Spec.ControlPlaneEndpoint.Port, "field is immutable"),
@@ -115,6 +210,24 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) (admission.Warnings
 		allErrs = append(allErrs, err)
 	}
 
+	// Allow adding an application gateway but avoid changing or removing it.
+	if old.Spec.ApplicationGateway != nil && !reflect.DeepEqual(old.Spec.ApplicationGateway, c.Spec.ApplicationGateway) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "applicationGateway"),
+				c.Spec.ApplicationGateway, "the application gateway cannot be changed or removed"),
+		)
+	}
+
+	// Allow adding secondary regions but avoid changing or removing them.
+	for i, oldRegion := range old.Spec.SecondaryRegions {
+		if i >= len(c.Spec.SecondaryRegions) || !reflect.DeepEqual(oldRegion, c.Spec.SecondaryRegions[i]) {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "secondaryRegions").Index(i),
+					oldRegion.Location, "secondary regions cannot be changed or removed"),
+			)
+		}
+	}
+
 	// Allow enabling azure bastion but avoid disabling it.
 	if old.Spec.BastionSpec.AzureBastion != nil && !reflect.DeepEqual(old.Spec.BastionSpec.AzureBastion, c.Spec.BastionSpec.AzureBastion) {
 		allErrs = append(allErrs,
@@ -130,6 +243,21 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) (admission.Warnings
 		allErrs = append(allErrs, err)
 	}
 
+	// Machines only join the backend pool of the node internal LB when they are created.
+	if (old.Spec.NetworkSpec.NodeInternalLB == nil) != (c.Spec.NetworkSpec.NodeInternalLB == nil) {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("spec", "networkSpec", "nodeInternalLB"),
+				"node internal load balancer cannot be added or removed after AzureCluster creation"),
+		)
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "NetworkSpec", "APIServerDNSRecord"),
+		old.Spec.NetworkSpec.APIServerDNSRecord,
+		c.Spec.NetworkSpec.APIServerDNSRecord); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	allErrs = append(allErrs, c.validateSubnetUpdate(old)...)
 
 	if len(allErrs) == 0 {
@@ -157,7 +285,9 @@ func (c *AzureCluster) validateSubnetUpdate(old *AzureCluster) field.ErrorList {
 			// This technically allows the cidr block to be modified in the brief
 			// moments before the Vnet is created (because the tags haven't been
 			// set yet) but once the Vnet has been created it becomes immutable.
-			if old.Spec.NetworkSpec.Vnet.Tags.HasOwned(old.Name) && !reflect.DeepEqual(subnet.CIDRBlocks, oldSubnet.CIDRBlocks) {
+			// The CIDR blocks of a subnet claimed from an IP address pool are set once, when the claim is fulfilled.
+			claimedFromPool := len(oldSubnet.CIDRBlocks) == 0 && subnet.CIDRBlockFromPool != nil
+			if old.Spec.NetworkSpec.Vnet.Tags.HasOwned(old.Name) && !claimedFromPool && !reflect.DeepEqual(subnet.CIDRBlocks, oldSubnet.CIDRBlocks) {
 				allErrs = append(allErrs,
 					field.Invalid(field.NewPath("spec", "networkSpec", "subnets").Index(oldSubnetIndex[subnet.Name]).Child("CIDRBlocks"),
 						c.Spec.NetworkSpec.Subnets[i].CIDRBlocks, "field is immutable"),
@@ -181,6 +311,12 @@ func (c *AzureCluster) validateSubnetUpdate(old *AzureCluster) field.ErrorList {
 						c.Spec.NetworkSpec.Subnets[i].SecurityGroup.Name, "field is immutable"),
 				)
 			}
+			if subnet.SecurityGroup.ExistingID() != oldSubnet.SecurityGroup.ExistingID() {
+				allErrs = append(allErrs,
+					field.Invalid(field.NewPath("spec", "networkSpec", "subnets").Index(oldSubnetIndex[subnet.Name]).Child("SecurityGroup").Child("Existing").Child("ID"),
+						c.Spec.NetworkSpec.Subnets[i].SecurityGroup.ExistingID(), "field is immutable"),
+				)
+			}
 		}
 	}
 
@@ -191,3 +327,88 @@ func (c *AzureCluster) validateSubnetUpdate(old *AzureCluster) field.ErrorList {
 func (c *AzureCluster) ValidateDelete() (admission.Warnings, error) {
 	return nil, nil
 }
+
+// ClusterQuotaChecker returns a description of each quota of the management cluster that creating cluster would
+// exceed, e.g. on the number of AzureClusters of its namespace, along with warnings about what could not be counted.
+type ClusterQuotaChecker func(ctx context.Context, cluster *AzureCluster) (exceeded []string, warnings admission.Warnings, err error)
+
+// azureClusterWebhook implements a defaulting and validating webhook for AzureClusters, defaulting their location and
+// checking that it is available to their subscription and that the cluster fits in the quotas of the management
+// cluster in addition to their spec.
+type azureClusterWebhook struct {
+	defaultLocation LocationDefaulter
+	checkLocations  LocationChecker
+	checkQuota      ClusterQuotaChecker
+}
+
+var _ webhook.CustomDefaulter = &azureClusterWebhook{}
+var _ webhook.CustomValidator = &azureClusterWebhook{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type.
+func (w *azureClusterWebhook) Default(_ context.Context, obj runtime.Object) error {
+	c, ok := obj.(*AzureCluster)
+	if !ok {
+		return apierrors.NewBadRequest("expected an AzureCluster resource")
+	}
+	if c.Spec.Location == "" && w.defaultLocation != nil {
+		c.Spec.Location = w.defaultLocation(c.Spec.SubscriptionID)
+	}
+	c.Default()
+	return nil
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (w *azureClusterWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	c, ok := obj.(*AzureCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureCluster resource")
+	}
+	if warnings, err := c.ValidateCreate(); err != nil {
+		return warnings, err
+	}
+
+	fldPath := field.NewPath("spec", "location")
+	if c.Spec.Location == "" {
+		return nil, invalidCluster(c, field.Required(fldPath, "the subscription of the cluster has no default location"))
+	}
+	var warnings admission.Warnings
+	if w.checkLocations != nil {
+		locationWarnings, err := ValidateLocationAvailability(ctx, w.checkLocations, c, fldPath)
+		if err != nil {
+			return locationWarnings, invalidCluster(c, err)
+		}
+		warnings = append(warnings, locationWarnings...)
+	}
+	if w.checkQuota != nil {
+		exceeded, quotaWarnings, err := w.checkQuota(ctx, c)
+		if err != nil {
+			return warnings, apierrors.NewInternalError(errors.Wrap(err, "failed to check the cluster quotas"))
+		}
+		warnings = append(warnings, quotaWarnings...)
+		if len(exceeded) > 0 {
+			return warnings, apierrors.NewForbidden(GroupVersion.WithResource("azureclusters").GroupResource(), c.Name,
+				errors.Errorf("exceeded cluster quota: %s", strings.Join(exceeded, "; ")))
+		}
+	}
+	return warnings, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type. The location of a
+// cluster is immutable, so it is only checked when the cluster is created.
+func (w *azureClusterWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	c, ok := newObj.(*AzureCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureCluster resource")
+	}
+	return c.ValidateUpdate(oldObj)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (w *azureClusterWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// invalidCluster returns the error rejecting a cluster for err.
+func invalidCluster(c *AzureCluster, err *field.Error) error {
+	return apierrors.NewInvalid(GroupVersion.WithKind("AzureCluster").GroupKind(), c.Name, field.ErrorList{err})
+}
@@ -17,10 +17,14 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func TestAzureCluster_ValidateCreate(t *testing.T) {
@@ -145,6 +149,112 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			}(),
 			wantErr: true,
 		},
+		{
+			name: "azurecluster with pre-existing control plane endpoint rotating its endpoint - valid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
+					Host: "apiserver.example.com",
+					Port: 8443,
+				}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Annotations = map[string]string{RotateControlPlaneEndpointAnnotation: "true"}
+				cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
+					Host: "apiserver.example.io",
+					Port: 6443,
+				}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster setting the cidr blocks of a subnet claimed from an ip address pool - valid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{"10.0.0.0/8"}
+				cluster.Spec.NetworkSpec.Subnets[1].CIDRBlockFromPool = &corev1.TypedLocalObjectReference{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "subnets"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{"10.0.0.0/8"}
+				cluster.Spec.NetworkSpec.Subnets[1].CIDRBlocks = []string{"10.0.1.0/24"}
+				cluster.Spec.NetworkSpec.Subnets[1].CIDRBlockFromPool = &corev1.TypedLocalObjectReference{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "subnets"}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster changing the cidr blocks of a subnet claimed from an ip address pool - invalid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{"10.0.0.0/8"}
+				cluster.Spec.NetworkSpec.Subnets[1].CIDRBlocks = []string{"10.0.1.0/24"}
+				cluster.Spec.NetworkSpec.Subnets[1].CIDRBlockFromPool = &corev1.TypedLocalObjectReference{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "subnets"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{"10.0.0.0/8"}
+				cluster.Spec.NetworkSpec.Subnets[1].CIDRBlocks = []string{"10.0.2.0/24"}
+				cluster.Spec.NetworkSpec.Subnets[1].CIDRBlockFromPool = &corev1.TypedLocalObjectReference{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "subnets"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster switching its API server load balancer from public to internal - invalid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{"10.0.0.0/8"}
+				cluster.Spec.NetworkSpec.Subnets[0].CIDRBlocks = []string{"10.10.0.0/16"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{"10.0.0.0/8"}
+				cluster.Spec.NetworkSpec.Subnets[0].CIDRBlocks = []string{"10.10.0.0/16"}
+				cluster.Spec.NetworkSpec.APIServerLB = createValidAPIServerInternalLB()
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster rotating its endpoint from a public to an internal API server load balancer - valid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{"10.0.0.0/8"}
+				cluster.Spec.NetworkSpec.Subnets[0].CIDRBlocks = []string{"10.10.0.0/16"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Annotations = map[string]string{RotateControlPlaneEndpointAnnotation: "true"}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{"10.0.0.0/8"}
+				cluster.Spec.NetworkSpec.Subnets[0].CIDRBlocks = []string{"10.10.0.0/16"}
+				cluster.Spec.NetworkSpec.APIServerLB = createValidAPIServerInternalLB()
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name:       "azurecluster rotating its endpoint and renaming its API server load balancer - invalid spec",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Annotations = map[string]string{RotateControlPlaneEndpointAnnotation: "true"}
+				cluster.Spec.NetworkSpec.APIServerLB.Name = "my-other-lb"
+				return cluster
+			}(),
+			wantErr: true,
+		},
 		{
 			name:       "azurecluster with no control plane endpoint - valid spec",
 			oldCluster: createValidCluster(),
@@ -233,6 +343,38 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "azurecluster network resource group is immutable",
+			oldCluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkResourceGroup: "network-rg",
+				},
+			},
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkResourceGroup: "network-rg-2",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azurecluster resource naming is immutable",
+			oldCluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					AzureClusterClassSpec: AzureClusterClassSpec{
+						ResourceNaming: &ResourceNamingSpec{Prefix: "corp-"},
+					},
+				},
+			},
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					AzureClusterClassSpec: AzureClusterClassSpec{
+						ResourceNaming: &ResourceNamingSpec{Prefix: "other-"},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "azurecluster subscription ID is immutable",
 			oldCluster: &AzureCluster{
@@ -269,6 +411,158 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:       "azurecluster extendedLocation is immutable",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.ExtendedLocation = &ExtendedLocationSpec{Name: "losangeles", Type: "EdgeZone"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "azurecluster zoneRedundantPublicIPs is immutable",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.ZoneRedundantPublicIPs = true
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "azurecluster bootstrapDataStorage cannot be enabled",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BootstrapDataStorage = &BootstrapDataStorage{}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster bootstrapDataStorage storage account name is immutable",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BootstrapDataStorage = &BootstrapDataStorage{StorageAccountName: "capzboot"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BootstrapDataStorage = &BootstrapDataStorage{StorageAccountName: "capzbootother"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "azurecluster bootstrapDataKeyVault cannot be enabled",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BootstrapDataKeyVault = &BootstrapDataKeyVault{Name: "capz-bootstrap"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster bootstrapDataKeyVault is immutable",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BootstrapDataKeyVault = &BootstrapDataKeyVault{Name: "capz-bootstrap"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BootstrapDataKeyVault = &BootstrapDataKeyVault{Name: "capz-bootstrap", ResourceGroup: "other-rg"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "azurecluster managedIdentity cannot be enabled",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.ManagedIdentity = &ClusterManagedIdentity{}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster managedIdentity roles are immutable",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.ManagedIdentity = &ClusterManagedIdentity{}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.ManagedIdentity = &ClusterManagedIdentity{
+					Roles: []ManagedIdentityRole{{DefinitionID: "/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/" + ContributorRoleID}},
+				}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster bootstrapDataStorage settings are mutable",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BootstrapDataStorage = &BootstrapDataStorage{StorageAccountName: "capzboot"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.BootstrapDataStorage = &BootstrapDataStorage{
+					StorageAccountName: "capzboot",
+					HTTPSTrafficOnly:   ptr.To(true),
+					MinimumTLSVersion:  StorageTLSVersion12,
+				}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name:       "azurecluster vnet name is immutable",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Name = "my-other-vnet"
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "azurecluster vnet resource group is immutable",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.ResourceGroup = "my-other-rg"
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster vnet name can be set when previously empty",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Name = ""
+				return cluster
+			}(),
+			cluster: createValidCluster(),
+			wantErr: false,
+		},
+		{
+			name:       "azurecluster additional tags are mutable",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.AdditionalTags = Tags{"team": "platform"}
+				return cluster
+			}(),
+			wantErr: false,
+		},
 		{
 			name: "azurecluster azureEnvironment is immutable",
 			oldCluster: &AzureCluster{
@@ -315,6 +609,169 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "apiServerDNSRecord is immutable",
+			oldCluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerDNSRecord: &DNSRecordSpec{ZoneName: "example.com"},
+					},
+				},
+			},
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerDNSRecord: &DNSRecordSpec{ZoneName: "example.org"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "nodeInternalLB cannot be added after creation",
+			oldCluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{},
+				},
+			},
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NodeInternalLB: &NodeInternalLBSpec{},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "application gateway can be added",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+				cluster.Spec.ApplicationGateway = &ApplicationGatewaySpec{
+					Name:       "my-appgw",
+					SubnetName: "my-appgw-subnet",
+					CIDRBlocks: []string{DefaultApplicationGatewaySubnetCIDR},
+					PublicIP:   PublicIPSpec{Name: "my-appgw-pip"},
+				}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "application gateway cannot be removed",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+				cluster.Spec.ApplicationGateway = &ApplicationGatewaySpec{
+					Name:       "my-appgw",
+					SubnetName: "my-appgw-subnet",
+					CIDRBlocks: []string{DefaultApplicationGatewaySubnetCIDR},
+					PublicIP:   PublicIPSpec{Name: "my-appgw-pip"},
+				}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "platformFaultDomainCount is immutable",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.PlatformFaultDomainCount = ptr.To[int32](2)
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.PlatformFaultDomainCount = ptr.To[int32](3)
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "platformFaultDomainCount cannot be set after creation",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.PlatformFaultDomainCount = ptr.To[int32](2)
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "secondary regions can be added",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.SecondaryRegions = []SecondaryRegionSpec{
+					{Location: "eastus", VnetName: "my-vnet-eastus", CIDRBlocks: []string{"172.16.0.0/16"}},
+				}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.SecondaryRegions = []SecondaryRegionSpec{
+					{Location: "eastus", VnetName: "my-vnet-eastus", CIDRBlocks: []string{"172.16.0.0/16"}},
+					{Location: "centralus", VnetName: "my-vnet-centralus", CIDRBlocks: []string{"172.17.0.0/16"}},
+				}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "secondary regions cannot be removed",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.SecondaryRegions = []SecondaryRegionSpec{
+					{Location: "eastus", VnetName: "my-vnet-eastus", CIDRBlocks: []string{"172.16.0.0/16"}},
+				}
+				return cluster
+			}(),
+			cluster: createValidCluster(),
+			wantErr: true,
+		},
+		{
+			name: "secondary regions cannot be changed",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.SecondaryRegions = []SecondaryRegionSpec{
+					{Location: "eastus", VnetName: "my-vnet-eastus", CIDRBlocks: []string{"172.16.0.0/16"}},
+				}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.SecondaryRegions = []SecondaryRegionSpec{
+					{Location: "eastus", VnetName: "my-vnet-eastus", CIDRBlocks: []string{"172.18.0.0/16"}},
+				}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "publicNetworkAccess can be set to Enabled",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.PublicNetworkAccess = PublicNetworkAccessEnabled
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "publicNetworkAccess cannot be enabled",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.PublicNetworkAccess = PublicNetworkAccessDisabled
+				return cluster
+			}(),
+			cluster: createValidCluster(),
+			wantErr: true,
+		},
 		{
 			name: "natGateway name is immutable",
 			oldCluster: func() *AzureCluster {
@@ -354,3 +811,121 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureClusterWebhook_Default(t *testing.T) {
+	defaultLocation := func(subscriptionID string) string {
+		if subscriptionID == "123" {
+			return "westeurope"
+		}
+		return ""
+	}
+	tests := []struct {
+		name            string
+		location        string
+		subscriptionID  string
+		defaultLocation LocationDefaulter
+		want            string
+	}{
+		{
+			name:            "cluster with a location",
+			location:        "eastus",
+			subscriptionID:  "123",
+			defaultLocation: defaultLocation,
+			want:            "eastus",
+		},
+		{
+			name:            "cluster without a location in a subscription with a default location",
+			subscriptionID:  "123",
+			defaultLocation: defaultLocation,
+			want:            "westeurope",
+		},
+		{
+			name:            "cluster without a location in a subscription without a default location",
+			subscriptionID:  "456",
+			defaultLocation: defaultLocation,
+		},
+		{
+			name:           "cluster without a location without default locations",
+			subscriptionID: "123",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			w := &azureClusterWebhook{defaultLocation: tc.defaultLocation}
+			cluster := createValidCluster()
+			cluster.Spec.Location = tc.location
+			cluster.Spec.SubscriptionID = tc.subscriptionID
+			g.Expect(w.Default(context.Background(), cluster)).To(Succeed())
+			g.Expect(cluster.Spec.Location).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestAzureClusterWebhook_ValidateCreate(t *testing.T) {
+	checker := fakeLocationChecker{locations: []string{"eastus", "westus2"}}
+	quotaExceeded := func(_ context.Context, _ *AzureCluster) ([]string, admission.Warnings, error) {
+		return []string{"namespace default already has 2 AzureClusters, the maximum is 2"}, nil, nil
+	}
+	quotaAvailable := func(_ context.Context, _ *AzureCluster) ([]string, admission.Warnings, error) {
+		return nil, admission.Warnings{"the vCPUs of VM size Standard_D2s_v3 in location eastus are not counted"}, nil
+	}
+	tests := []struct {
+		name           string
+		location       string
+		checkLocations LocationChecker
+		checkQuota     ClusterQuotaChecker
+		wantWarnings   admission.Warnings
+		wantErr        string
+	}{
+		{
+			name:     "cluster without a location",
+			location: "",
+			wantErr:  "spec.location: Required value: the subscription of the cluster has no default location",
+		},
+		{
+			name:     "cluster with a location when locations are not checked",
+			location: "eastus3",
+		},
+		{
+			name:           "cluster with an available location",
+			location:       "westus2",
+			checkLocations: checker,
+		},
+		{
+			name:           "cluster with an unavailable location",
+			location:       "eastus3",
+			checkLocations: checker,
+			wantErr:        `spec.location: Unsupported value: "eastus3": supported values: "eastus", "westus2"`,
+		},
+		{
+			name:       "cluster exceeding a quota",
+			location:   "eastus",
+			checkQuota: quotaExceeded,
+			wantErr:    "exceeded cluster quota: namespace default already has 2 AzureClusters, the maximum is 2",
+		},
+		{
+			name:         "cluster within the quotas",
+			location:     "eastus",
+			checkQuota:   quotaAvailable,
+			wantWarnings: admission.Warnings{"the vCPUs of VM size Standard_D2s_v3 in location eastus are not counted"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			w := &azureClusterWebhook{checkLocations: tc.checkLocations, checkQuota: tc.checkQuota}
+			cluster := createValidCluster()
+			cluster.Spec.Location = tc.location
+			warnings, err := w.ValidateCreate(context.Background(), cluster)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.wantErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(warnings).To(Equal(tc.wantWarnings))
+			}
+		})
+	}
+}
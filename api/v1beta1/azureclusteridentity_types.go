@@ -22,6 +22,16 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+const (
+	// ClientSecretKey is the key of the password in the secret of a service principal identity.
+	ClientSecretKey = "clientSecret"
+	// ClientCertificateKey is the key of the certificate in the secret of a service principal certificate identity.
+	ClientCertificateKey = "certificate"
+	// ClientCertificatePasswordKey is the key of the optional password of the certificate in the secret of a service
+	// principal certificate identity.
+	ClientCertificatePasswordKey = "password"
+)
+
 // AllowedNamespaces defines the namespaces the clusters are allowed to use the identity from
 // NamespaceList takes precedence over the Selector.
 type AllowedNamespaces struct {
@@ -17,19 +17,32 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	webhookutils "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-// SetupWebhookWithManager sets up and registers the webhook with the manager.
-func (c *AzureClusterIdentity) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// IdentityCredentialsChecker checks that a token can be acquired with the credentials of an AzureClusterIdentity.
+// secret is the secret referenced by the identity, or nil for identity types without one.
+type IdentityCredentialsChecker func(ctx context.Context, identity *AzureClusterIdentity, secret *corev1.Secret) error
+
+// SetupAzureClusterIdentityWebhookWithManager sets up and registers the webhook with the manager. The credentials of
+// the identities are only checked when checkCredentials is not nil.
+func SetupAzureClusterIdentityWebhookWithManager(mgr ctrl.Manager, checkCredentials IdentityCredentialsChecker) error {
+	w := &azureClusterIdentityWebhook{Client: mgr.GetClient(), checkCredentials: checkCredentials}
 	return ctrl.NewWebhookManagedBy(mgr).
-		For(c).
+		For(&AzureClusterIdentity{}).
+		WithValidator(w).
 		Complete()
 }
 
@@ -62,3 +75,104 @@ func (c *AzureClusterIdentity) ValidateUpdate(oldRaw runtime.Object) (admission.
 func (c *AzureClusterIdentity) ValidateDelete() (admission.Warnings, error) {
 	return nil, nil
 }
+
+// azureClusterIdentityWebhook implements a validating webhook for AzureClusterIdentities, checking their credentials
+// in addition to their spec.
+type azureClusterIdentityWebhook struct {
+	Client           client.Client
+	checkCredentials IdentityCredentialsChecker
+}
+
+var _ webhook.CustomValidator = &azureClusterIdentityWebhook{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (w *azureClusterIdentityWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	c, ok := obj.(*AzureClusterIdentity)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureClusterIdentity resource")
+	}
+	if warnings, err := c.ValidateCreate(); err != nil {
+		return warnings, err
+	}
+	return w.validateCredentials(ctx, c)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type. Credentials are
+// only checked again when the client, tenant or secret of the identity change, so that other updates don't depend on
+// Azure.
+func (w *azureClusterIdentityWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	c, ok := newObj.(*AzureClusterIdentity)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureClusterIdentity resource")
+	}
+	old, ok := oldObj.(*AzureClusterIdentity)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureClusterIdentity resource")
+	}
+	if warnings, err := c.ValidateUpdate(old); err != nil {
+		return warnings, err
+	}
+	if old.Spec.ClientID == c.Spec.ClientID && old.Spec.TenantID == c.Spec.TenantID && reflect.DeepEqual(old.Spec.ClientSecret, c.Spec.ClientSecret) {
+		return nil, nil
+	}
+	return w.validateCredentials(ctx, c)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (w *azureClusterIdentityWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateCredentials checks that the secret referenced by an identity holds its credentials and, when enabled, that
+// a token can be acquired with them. A missing secret only yields a warning, since tools like clusterctl move may
+// create the identity before its secret.
+func (w *azureClusterIdentityWebhook) validateCredentials(ctx context.Context, c *AzureClusterIdentity) (admission.Warnings, error) {
+	var secret *corev1.Secret
+	if key := identitySecretKey(c.Spec.Type); key != "" {
+		fldPath := field.NewPath("spec", "clientSecret")
+		if c.Spec.ClientSecret.Name == "" {
+			return nil, invalidClusterIdentity(c, field.Required(fldPath.Child("name"), fmt.Sprintf("a secret holding the credentials of %s identities is required", c.Spec.Type)))
+		}
+		secretKey := client.ObjectKey{Namespace: c.Spec.ClientSecret.Namespace, Name: c.Spec.ClientSecret.Name}
+		if secretKey.Namespace == "" {
+			secretKey.Namespace = c.Namespace
+		}
+		secret = &corev1.Secret{}
+		if err := w.Client.Get(ctx, secretKey, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return admission.Warnings{fmt.Sprintf("secret %s referenced by spec.clientSecret not found, the credentials of the identity were not validated", secretKey)}, nil
+			}
+			return nil, apierrors.NewInternalError(err)
+		}
+		if len(secret.Data[key]) == 0 {
+			return nil, invalidClusterIdentity(c, field.Invalid(fldPath, secretKey.String(), fmt.Sprintf("secret has no %s key", key)))
+		}
+	}
+
+	if w.checkCredentials == nil {
+		return nil, nil
+	}
+	if err := w.checkCredentials(ctx, c, secret); err != nil {
+		return nil, invalidClusterIdentity(c, field.Invalid(field.NewPath("spec", "clientID"), c.Spec.ClientID,
+			fmt.Sprintf("failed to acquire a token with the credentials of the identity: %v", err)))
+	}
+	return nil, nil
+}
+
+// invalidClusterIdentity returns the error rejecting an identity for err.
+func invalidClusterIdentity(c *AzureClusterIdentity, err *field.Error) error {
+	return apierrors.NewInvalid(GroupVersion.WithKind("AzureClusterIdentity").GroupKind(), c.Name, field.ErrorList{err})
+}
+
+// identitySecretKey returns the key of the credentials in the secret of identities of type identityType, or an empty
+// string for identity types without a secret.
+func identitySecretKey(identityType IdentityType) string {
+	switch identityType {
+	case ServicePrincipal, ManualServicePrincipal:
+		return ClientSecretKey
+	case ServicePrincipalCertificate:
+		return ClientCertificateKey
+	default:
+		return ""
+	}
+}
@@ -17,9 +17,15 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 const fakeClientID = "fake-client-id"
@@ -172,3 +178,135 @@ func TestAzureClusterIdentity_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureClusterIdentityWebhook_ValidateCredentials(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sp-secret", Namespace: "default"},
+		Data:       map[string][]byte{ClientSecretKey: []byte("password")},
+	}
+	newIdentity := func(identityType IdentityType, secretName string) *AzureClusterIdentity {
+		return &AzureClusterIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: "identity", Namespace: "default"},
+			Spec: AzureClusterIdentitySpec{
+				Type:         identityType,
+				ClientID:     fakeClientID,
+				TenantID:     fakeTenantID,
+				ClientSecret: corev1.SecretReference{Name: secretName, Namespace: "default"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		identity         *AzureClusterIdentity
+		checkErr         error
+		expectCheck      bool
+		expectCheckedKey bool
+		wantWarning      string
+		wantErr          string
+	}{
+		{
+			name:             "service principal with its secret",
+			identity:         newIdentity(ServicePrincipal, "sp-secret"),
+			expectCheck:      true,
+			expectCheckedKey: true,
+		},
+		{
+			name:     "service principal without secret",
+			identity: newIdentity(ServicePrincipal, ""),
+			wantErr:  "spec.clientSecret.name: Required value",
+		},
+		{
+			name:        "service principal with a missing secret",
+			identity:    newIdentity(ManualServicePrincipal, "missing-secret"),
+			wantWarning: "secret default/missing-secret referenced by spec.clientSecret not found",
+		},
+		{
+			name:     "service principal certificate with a secret without certificate",
+			identity: newIdentity(ServicePrincipalCertificate, "sp-secret"),
+			wantErr:  "secret has no certificate key",
+		},
+		{
+			name:        "workload identity without secret",
+			identity:    newIdentity(WorkloadIdentity, ""),
+			expectCheck: true,
+		},
+		{
+			name:             "credentials not accepted by Microsoft Entra ID",
+			identity:         newIdentity(ServicePrincipal, "sp-secret"),
+			checkErr:         errors.New("AADSTS7000215: Invalid client secret provided"),
+			expectCheck:      true,
+			expectCheckedKey: true,
+			wantErr:          "failed to acquire a token with the credentials of the identity: AADSTS7000215: Invalid client secret provided",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			checked := false
+			w := &azureClusterIdentityWebhook{
+				Client: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(secret.DeepCopy()).Build(),
+				checkCredentials: func(_ context.Context, identity *AzureClusterIdentity, secret *corev1.Secret) error {
+					checked = true
+					g.Expect(identity.Spec.ClientID).To(Equal(fakeClientID))
+					if tc.expectCheckedKey {
+						g.Expect(secret.Data).To(HaveKeyWithValue(ClientSecretKey, []byte("password")))
+					} else {
+						g.Expect(secret).To(BeNil())
+					}
+					return tc.checkErr
+				},
+			}
+			warnings, err := w.ValidateCreate(context.Background(), tc.identity)
+			if tc.wantWarning != "" {
+				g.Expect(warnings).To(ConsistOf(ContainSubstring(tc.wantWarning)))
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.wantErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(checked).To(Equal(tc.expectCheck))
+		})
+	}
+}
+
+func TestAzureClusterIdentityWebhook_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+	checks := 0
+	w := &azureClusterIdentityWebhook{
+		Client: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build(),
+		checkCredentials: func(context.Context, *AzureClusterIdentity, *corev1.Secret) error {
+			checks++
+			return nil
+		},
+	}
+	old := &AzureClusterIdentity{
+		Spec: AzureClusterIdentitySpec{
+			Type:     WorkloadIdentity,
+			ClientID: fakeClientID,
+			TenantID: fakeTenantID,
+		},
+	}
+
+	// Credentials are not checked again when they don't change.
+	updated := old.DeepCopy()
+	updated.Spec.AllowedNamespaces = &AllowedNamespaces{NamespaceList: []string{"default"}}
+	_, err := w.ValidateUpdate(context.Background(), old, updated)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(checks).To(BeZero())
+
+	updated.Spec.ClientID = "new-client-id"
+	_, err = w.ValidateUpdate(context.Background(), old, updated)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(checks).To(Equal(1))
+
+	// The spec is validated as well.
+	updated.Spec.Type = ServicePrincipal
+	_, err = w.ValidateUpdate(context.Background(), old, updated)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(checks).To(Equal(1))
+}
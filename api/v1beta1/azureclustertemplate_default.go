@@ -44,7 +44,7 @@ func (c *AzureClusterTemplate) setBastionTemplateDefaults() {
 	if c.Spec.Template.Spec.BastionSpec.AzureBastion != nil {
 		// Ensure defaults for Subnet settings.
 		if len(c.Spec.Template.Spec.BastionSpec.AzureBastion.Subnet.CIDRBlocks) == 0 {
-			c.Spec.Template.Spec.BastionSpec.AzureBastion.Subnet.CIDRBlocks = []string{DefaultAzureBastionSubnetCIDR}
+			c.Spec.Template.Spec.BastionSpec.AzureBastion.Subnet.CIDRBlocks = []string{defaultBastionSubnetCIDR(c.Spec.Template.Spec.NetworkSpec.Vnet.CIDRBlocks)}
 		}
 		if c.Spec.Template.Spec.BastionSpec.AzureBastion.Subnet.Role == "" {
 			c.Spec.Template.Spec.BastionSpec.AzureBastion.Subnet.Role = DefaultAzureBastionSubnetRole
@@ -58,7 +58,7 @@ func (c *AzureClusterTemplate) setSubnetsTemplateDefaults() {
 		cpSubnet = SubnetTemplateSpec{SubnetClassSpec: SubnetClassSpec{Role: SubnetControlPlane}}
 		c.Spec.Template.Spec.NetworkSpec.Subnets = append(c.Spec.Template.Spec.NetworkSpec.Subnets, cpSubnet)
 	}
-	cpSubnet.SubnetClassSpec.setDefaults(DefaultControlPlaneSubnetCIDR)
+	cpSubnet.SubnetClassSpec.setDefaults(defaultSubnetCIDR(c.Spec.Template.Spec.NetworkSpec.Vnet.CIDRBlocks, 0, DefaultControlPlaneSubnetCIDR))
 	cpSubnet.SecurityGroup.setDefaults()
 	c.Spec.Template.Spec.NetworkSpec.UpdateControlPlaneSubnetTemplate(cpSubnet)
 
@@ -70,7 +70,7 @@ func (c *AzureClusterTemplate) setSubnetsTemplateDefaults() {
 		}
 		nodeSubnetCounter++
 		nodeSubnetFound = true
-		subnet.SubnetClassSpec.setDefaults(fmt.Sprintf(DefaultNodeSubnetCIDRPattern, nodeSubnetCounter))
+		subnet.SubnetClassSpec.setDefaults(defaultSubnetCIDR(c.Spec.Template.Spec.NetworkSpec.Vnet.CIDRBlocks, nodeSubnetCounter, fmt.Sprintf(DefaultNodeSubnetCIDRPattern, nodeSubnetCounter)))
 		subnet.SecurityGroup.setDefaults()
 		c.Spec.Template.Spec.NetworkSpec.Subnets[i] = subnet
 	}
@@ -79,7 +79,7 @@ func (c *AzureClusterTemplate) setSubnetsTemplateDefaults() {
 		nodeSubnet := SubnetTemplateSpec{
 			SubnetClassSpec: SubnetClassSpec{
 				Role:       SubnetNode,
-				CIDRBlocks: []string{DefaultNodeSubnetCIDR},
+				CIDRBlocks: []string{defaultSubnetCIDR(c.Spec.Template.Spec.NetworkSpec.Vnet.CIDRBlocks, 1, DefaultNodeSubnetCIDR)},
 			},
 		}
 		c.Spec.Template.Spec.NetworkSpec.Subnets = append(c.Spec.Template.Spec.NetworkSpec.Subnets, nodeSubnet)
@@ -1332,3 +1332,36 @@ func TestBastionTemplateDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkTemplateSpecDefaultsWithCustomVnetCIDR(t *testing.T) {
+	clusterTemplate := &AzureClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-template"},
+		Spec: AzureClusterTemplateSpec{
+			Template: AzureClusterTemplateResource{
+				Spec: AzureClusterTemplateResourceSpec{
+					NetworkSpec: NetworkTemplateSpec{
+						Vnet: VnetTemplateSpec{
+							VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"192.168.0.0/16"}},
+						},
+					},
+					BastionSpec: BastionTemplateSpec{AzureBastion: &AzureBastionTemplateSpec{}},
+				},
+			},
+		},
+	}
+	clusterTemplate.setNetworkTemplateSpecDefaults()
+
+	expected := map[SubnetRole][]string{
+		SubnetControlPlane: {"192.168.0.0/24"},
+		SubnetNode:         {"192.168.1.0/24"},
+	}
+	for _, subnet := range clusterTemplate.Spec.Template.Spec.NetworkSpec.Subnets {
+		if !reflect.DeepEqual(subnet.CIDRBlocks, expected[subnet.Role]) {
+			t.Errorf("Expected %s subnet CIDR blocks %v, got %v", subnet.Role, expected[subnet.Role], subnet.CIDRBlocks)
+		}
+	}
+	bastionCIDRBlocks := clusterTemplate.Spec.Template.Spec.BastionSpec.AzureBastion.Subnet.CIDRBlocks
+	if !reflect.DeepEqual(bastionCIDRBlocks, []string{"192.168.255.224/27"}) {
+		t.Errorf("Expected bastion subnet CIDR blocks [192.168.255.224/27], got %v", bastionCIDRBlocks)
+	}
+}
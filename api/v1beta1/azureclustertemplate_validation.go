@@ -62,6 +62,24 @@ func (c *AzureClusterTemplate) validateClusterTemplateSpec() field.ErrorList {
 
 	allErrs = append(allErrs, c.validatePrivateDNSZoneName()...)
 
+	allErrs = append(allErrs, c.validateBootstrapDataStorage()...)
+
+	allErrs = append(allErrs, validateSSHPublicKeys(c.Spec.Template.Spec.SSHPublicKeys, field.NewPath("spec", "template", "spec", "sshPublicKeys"))...)
+
+	return allErrs
+}
+
+// validateBootstrapDataStorage validates that the template doesn't name a storage account, since every cluster created
+// from the template would otherwise try to use the same, globally unique, storage account name.
+func (c *AzureClusterTemplate) validateBootstrapDataStorage() field.ErrorList {
+	var allErrs field.ErrorList
+	storage := c.Spec.Template.Spec.BootstrapDataStorage
+	if storage != nil && storage.StorageAccountName != "" {
+		allErrs = append(allErrs, field.Forbidden(
+			field.NewPath("spec", "template", "spec", "bootstrapDataStorage", "storageAccountName"),
+			"storage account names are globally unique and can't be set in a template",
+		))
+	}
 	return allErrs
 }
 
@@ -76,7 +94,7 @@ func (c *AzureClusterTemplate) validateNetworkSpec() field.ErrorList {
 			break
 		}
 	}
-	if needOutboundLB {
+	if needOutboundLB || networkSpec.NodeOutboundLB != nil {
 		allErrs = append(allErrs, c.validateNodeOutboundLB()...)
 	}
 
@@ -120,6 +138,12 @@ func validateSubnetTemplates(subnets SubnetTemplatesSpec, vnet VnetTemplateSpec,
 				fmt.Sprintf("required role %s not included in provided subnets", k)))
 		}
 	}
+
+	subnetCidrBlocks := make([][]string, len(subnets))
+	for i, subnet := range subnets {
+		subnetCidrBlocks[i] = subnet.CIDRBlocks
+	}
+	allErrs = append(allErrs, validateSubnetCIDROverlaps(subnetCidrBlocks, fld)...)
 	return allErrs
 }
 
@@ -894,3 +894,53 @@ func TestValidateNetworkSpec(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBootstrapDataStorageTemplate(t *testing.T) {
+	cases := []struct {
+		name        string
+		storage     *BootstrapDataStorage
+		expectValid bool
+	}{
+		{
+			name:        "not set",
+			storage:     nil,
+			expectValid: true,
+		},
+		{
+			name:        "enabled with a generated storage account name",
+			storage:     &BootstrapDataStorage{},
+			expectValid: true,
+		},
+		{
+			name:        "storage account name set",
+			storage:     &BootstrapDataStorage{StorageAccountName: "mystorageaccount"},
+			expectValid: false,
+		},
+	}
+
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			clusterTemplate := &AzureClusterTemplate{
+				Spec: AzureClusterTemplateSpec{
+					Template: AzureClusterTemplateResource{
+						Spec: AzureClusterTemplateResourceSpec{
+							AzureClusterClassSpec: AzureClusterClassSpec{
+								BootstrapDataStorage: tc.storage,
+							},
+						},
+					},
+				},
+			}
+			res := clusterTemplate.validateBootstrapDataStorage()
+
+			if tc.expectValid {
+				g.Expect(res).To(BeNil())
+			} else {
+				g.Expect(res).NotTo(BeNil())
+			}
+		})
+	}
+}
@@ -17,11 +17,14 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api/util/topology"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -34,43 +37,70 @@ const AzureClusterTemplateImmutableMsg = "AzureClusterTemplate spec.template.spe
 func (c *AzureClusterTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
+		WithValidator(c).
+		WithDefaulter(c).
 		Complete()
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azureclustertemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azureclustertemplates,versions=v1beta1,name=validation.azureclustertemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-azureclustertemplate,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azureclustertemplates,versions=v1beta1,name=default.azureclustertemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
-var _ webhook.Defaulter = &AzureClusterTemplate{}
+var _ webhook.CustomDefaulter = &AzureClusterTemplate{}
 
-// Default implements webhook.Defaulter so a webhook will be registered for the type.
-func (c *AzureClusterTemplate) Default() {
-	c.setDefaults()
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type.
+func (c *AzureClusterTemplate) Default(ctx context.Context, obj runtime.Object) error {
+	t, ok := obj.(*AzureClusterTemplate)
+	if !ok {
+		return apierrors.NewBadRequest("expected an AzureClusterTemplate")
+	}
+	t.setDefaults()
+	return nil
 }
 
-var _ webhook.Validator = &AzureClusterTemplate{}
+var _ webhook.CustomValidator = &AzureClusterTemplate{}
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (c *AzureClusterTemplate) ValidateCreate() (admission.Warnings, error) {
-	return c.validateClusterTemplate()
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (c *AzureClusterTemplate) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	t, ok := obj.(*AzureClusterTemplate)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureClusterTemplate")
+	}
+	return t.validateClusterTemplate()
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (c *AzureClusterTemplate) ValidateUpdate(oldRaw runtime.Object) (admission.Warnings, error) {
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (c *AzureClusterTemplate) ValidateUpdate(ctx context.Context, oldRaw, newRaw runtime.Object) (admission.Warnings, error) {
 	var allErrs field.ErrorList
-	old := oldRaw.(*AzureClusterTemplate)
-	if !reflect.DeepEqual(c.Spec.Template.Spec, old.Spec.Template.Spec) {
+	old, ok := oldRaw.(*AzureClusterTemplate)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureClusterTemplate")
+	}
+	t, ok := newRaw.(*AzureClusterTemplate)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureClusterTemplate")
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a admission.Request inside context: %v", err))
+	}
+
+	// The topology controller dry-runs its changes to compute diffs; those requests must not be
+	// rejected because of template immutability.
+	if !topology.ShouldSkipImmutabilityChecks(req, t) &&
+		!reflect.DeepEqual(t.Spec.Template.Spec, old.Spec.Template.Spec) {
 		allErrs = append(allErrs,
-			field.Invalid(field.NewPath("AzureClusterTemplate", "spec", "template", "spec"), c, AzureClusterTemplateImmutableMsg),
+			field.Invalid(field.NewPath("AzureClusterTemplate", "spec", "template", "spec"), t, AzureClusterTemplateImmutableMsg),
 		)
 	}
 
 	if len(allErrs) == 0 {
 		return nil, nil
 	}
-	return nil, apierrors.NewInvalid(GroupVersion.WithKind("AzureClusterTemplate").GroupKind(), c.Name, allErrs)
+	return nil, apierrors.NewInvalid(GroupVersion.WithKind("AzureClusterTemplate").GroupKind(), t.Name, allErrs)
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
-func (c *AzureClusterTemplate) ValidateDelete() (admission.Warnings, error) {
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (c *AzureClusterTemplate) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	return nil, nil
 }
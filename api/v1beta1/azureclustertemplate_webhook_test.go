@@ -17,9 +17,14 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func TestValidateUpdate(t *testing.T) {
@@ -57,7 +62,24 @@ func TestValidateUpdate(t *testing.T) {
 
 	t.Run("template is immutable", func(t *testing.T) {
 		g := NewWithT(t)
-		_, err := newClusterTemplate.ValidateUpdate(oldClusterTemplate)
+		ctx := admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: ptr.To(false)}})
+		_, err := newClusterTemplate.ValidateUpdate(ctx, oldClusterTemplate, newClusterTemplate)
 		g.Expect(err).To(HaveOccurred())
 	})
+
+	t.Run("topology dry-run skips the immutability check", func(t *testing.T) {
+		g := NewWithT(t)
+		dryRunTemplate := newClusterTemplate.DeepCopy()
+		dryRunTemplate.Annotations = map[string]string{clusterv1.TopologyDryRunAnnotation: ""}
+		ctx := admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: ptr.To(true)}})
+		_, err := dryRunTemplate.ValidateUpdate(ctx, oldClusterTemplate, dryRunTemplate)
+		g.Expect(err).NotTo(HaveOccurred())
+	})
+
+	t.Run("unchanged template", func(t *testing.T) {
+		g := NewWithT(t)
+		ctx := admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: ptr.To(false)}})
+		_, err := oldClusterTemplate.ValidateUpdate(ctx, oldClusterTemplate, oldClusterTemplate.DeepCopy())
+		g.Expect(err).NotTo(HaveOccurred())
+	})
 }
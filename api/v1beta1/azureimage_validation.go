@@ -17,6 +17,9 @@ limitations under the License.
 package v1beta1
 
 import (
+	"text/template"
+
+	"github.com/google/uuid"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -117,6 +120,11 @@ func validateSharedGalleryImage(image *Image, fldPath *field.Path) field.ErrorLi
 	if image.SharedGallery.Version == "" {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("Version"), "", "Version cannot be empty when specifying an AzureSharedGalleryImage"))
 	}
+	if image.SharedGallery.TenantID != nil {
+		if _, err := uuid.Parse(*image.SharedGallery.TenantID); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("TenantID"), *image.SharedGallery.TenantID, "TenantID must be a valid GUID when specifying an AzureSharedGalleryImage"))
+		}
+	}
 
 	return allErrs
 }
@@ -148,3 +156,35 @@ func validateSpecificImage(image *Image, fldPath *field.Path) field.ErrorList {
 
 	return allErrs
 }
+
+// ValidateImageLookup validates the lookup of the image of a machine in a compute gallery.
+func ValidateImageLookup(image *Image, lookup *ImageLookup, variant ImageVariant, osType string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if lookup == nil {
+		if variant != "" {
+			allErrs = append(allErrs, field.Required(fldPath, "imageLookup is required to select an image variant"))
+		}
+		return allErrs
+	}
+
+	if image != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "imageLookup cannot be used when an image is specified"))
+	}
+	if lookup.SubscriptionID != nil && lookup.ResourceGroup == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("resourceGroup"), "", "resourceGroup cannot be empty when subscriptionID is specified"))
+	}
+	if lookup.ResourceGroup != nil && lookup.SubscriptionID == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subscriptionID"), "", "subscriptionID cannot be empty when resourceGroup is specified"))
+	}
+	if lookup.Format != "" {
+		if _, err := template.New("format").Option("missingkey=error").Parse(lookup.Format); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("format"), lookup.Format, err.Error()))
+		}
+	}
+	if osType == WindowsOS && lookup.BaseOS == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("baseOS"), "baseOS is required to look up the images of Windows machines"))
+	}
+
+	return allErrs
+}
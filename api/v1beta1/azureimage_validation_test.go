@@ -90,6 +90,31 @@ func TestComputeImageGalleryValid(t *testing.T) {
 	}
 }
 
+func TestComputeGalleryImageLatestVersionPrefix(t *testing.T) {
+	testCases := map[string]struct {
+		version        string
+		expectedPrefix string
+		expectedOK     bool
+	}{
+		"latest":                   {version: "latest"},
+		"Major.Minor.Build":        {version: "1.2.3"},
+		"Major.Minor.latest":       {version: "1.2.latest", expectedPrefix: "1.2", expectedOK: true},
+		"Major.latest":             {version: "1.latest"},
+		"non-numeric Major.Minor":  {version: "a.b.latest"},
+		"Major.Minor.Build.latest": {version: "1.2.3.latest"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			image := &AzureComputeGalleryImage{Version: tc.version}
+			prefix, ok := image.LatestVersionPrefix()
+			g.Expect(ok).To(Equal(tc.expectedOK))
+			g.Expect(prefix).To(Equal(tc.expectedPrefix))
+		})
+	}
+}
+
 func TestSharedImageGalleryValid(t *testing.T) {
 	testCases := map[string]struct {
 		image          *Image
@@ -119,6 +144,22 @@ func TestSharedImageGalleryValid(t *testing.T) {
 			expectedErrors: 1,
 			image:          createTestSharedImage("SUB1243", "RG1234", "IMAGENAME", "GALLERY9876", ""),
 		},
+		"AzureSharedGalleryImage - in another tenant": {
+			expectedErrors: 0,
+			image: func() *Image {
+				image := createTestSharedImage("SUB1243", "RG1234", "IMAGENAME", "GALLERY9876", "1.0.0")
+				image.SharedGallery.TenantID = ptr.To("00000000-0000-0000-0000-000000000001")
+				return image
+			}(),
+		},
+		"AzureSharedGalleryImage - invalid tenant": {
+			expectedErrors: 1,
+			image: func() *Image {
+				image := createTestSharedImage("SUB1243", "RG1234", "IMAGENAME", "GALLERY9876", "1.0.0")
+				image.SharedGallery.TenantID = ptr.To("not-a-tenant")
+				return image
+			}(),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -181,6 +222,65 @@ func TestImageByIDValid(t *testing.T) {
 	}
 }
 
+func TestImageLookupValid(t *testing.T) {
+	testCases := map[string]struct {
+		image          *Image
+		lookup         *ImageLookup
+		variant        ImageVariant
+		osType         string
+		expectedErrors int
+	}{
+		"no lookup": {
+			expectedErrors: 0,
+		},
+		"variant without lookup": {
+			variant:        ImageVariantFIPS,
+			expectedErrors: 1,
+		},
+		"community gallery lookup with variant": {
+			lookup:         &ImageLookup{Gallery: "hardened"},
+			variant:        ImageVariantCIS,
+			osType:         LinuxOS,
+			expectedErrors: 0,
+		},
+		"private gallery lookup": {
+			lookup:         &ImageLookup{Gallery: "hardened", SubscriptionID: ptr.To("SUB1234"), ResourceGroup: ptr.To("RG1234")},
+			osType:         LinuxOS,
+			expectedErrors: 0,
+		},
+		"lookup with an image": {
+			image:          createTestImageByID("ID1234"),
+			lookup:         &ImageLookup{Gallery: "hardened"},
+			expectedErrors: 1,
+		},
+		"lookup missing resource group": {
+			lookup:         &ImageLookup{Gallery: "hardened", SubscriptionID: ptr.To("SUB1234")},
+			expectedErrors: 1,
+		},
+		"lookup with an invalid format": {
+			lookup:         &ImageLookup{Gallery: "hardened", Format: "capi-{{.BaseOS"},
+			expectedErrors: 1,
+		},
+		"Windows lookup without base OS": {
+			lookup:         &ImageLookup{Gallery: "hardened"},
+			osType:         WindowsOS,
+			expectedErrors: 1,
+		},
+		"Windows lookup with base OS": {
+			lookup:         &ImageLookup{Gallery: "hardened", BaseOS: "windows-2022"},
+			osType:         WindowsOS,
+			expectedErrors: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(ValidateImageLookup(tc.image, tc.lookup, tc.variant, tc.osType, field.NewPath("imageLookup"))).To(HaveLen(tc.expectedErrors))
+		})
+	}
+}
+
 func createTestComputeImage(subscriptionID, resourceGroup *string) *Image {
 	return &Image{
 		ComputeGallery: &AzureComputeGalleryImage{
@@ -27,6 +27,7 @@ import (
 	"golang.org/x/crypto/ssh"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/utils/ptr"
 	utilSSH "sigs.k8s.io/cluster-api-provider-azure/util/ssh"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -77,8 +78,8 @@ func (s *AzureMachineSpec) SetDataDisksDefaults() {
 			}
 		}
 		if disk.CachingType == "" {
-			if s.DataDisks[i].ManagedDisk != nil &&
-				s.DataDisks[i].ManagedDisk.StorageAccountType == string(armcompute.StorageAccountTypesUltraSSDLRS) {
+			if ptr.Deref(s.DataDisks[i].WriteAcceleratorEnabled, false) || (s.DataDisks[i].ManagedDisk != nil &&
+				hasConfigurablePerformance(s.DataDisks[i].ManagedDisk.StorageAccountType)) {
 				s.DataDisks[i].CachingType = string(armcompute.CachingTypesNone)
 			} else {
 				s.DataDisks[i].CachingType = string(armcompute.CachingTypesReadWrite)
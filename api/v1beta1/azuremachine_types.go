@@ -36,6 +36,8 @@ type AzureMachineSpec struct {
 	// +optional
 	ProviderID *string `json:"providerID,omitempty"`
 
+	// VMSize is the size of the VM. Changing it on an existing AzureMachine resizes the VM in place, which requires
+	// the new size to be available on the hardware cluster hosting the VM.
 	VMSize string `json:"vmSize"`
 
 	// FailureDomain is the failure domain unique identifier this Machine should be attached to,
@@ -43,6 +45,13 @@ type AzureMachineSpec struct {
 	// +optional
 	FailureDomain *string `json:"failureDomain,omitempty"`
 
+	// AllowZoneFallback allows the VM to be created in another availability zone of the cluster when its size is not
+	// available to the subscription in the zone of the failure domain of the machine, instead of failing the machine.
+	// The zone used instead is recorded in status.zoneSubstitution. It cannot be set along with allocatePublicIP, as
+	// the zonal public IP of the machine is created before the VM.
+	// +optional
+	AllowZoneFallback bool `json:"allowZoneFallback,omitempty"`
+
 	// Image is used to provide details of an image to use during VM creation.
 	// If image details are omitted the image will default the Azure Marketplace "capi" offer,
 	// which is based on Ubuntu.
@@ -50,6 +59,14 @@ type AzureMachineSpec struct {
 	// +optional
 	Image *Image `json:"image,omitempty"`
 
+	// ImageLookup looks the image of the machine up by Kubernetes version in a compute gallery when image is not set.
+	// +optional
+	ImageLookup *ImageLookup `json:"imageLookup,omitempty"`
+
+	// ImageVariant selects a hardened variant of the image looked up with imageLookup, which it requires.
+	// +optional
+	ImageVariant ImageVariant `json:"imageVariant,omitempty"`
+
 	// Identity is the type of identity used for the virtual machine.
 	// The type 'SystemAssigned' is an implicitly created identity.
 	// The generated identity will be assigned a Subscription contributor role.
@@ -86,12 +103,39 @@ type AzureMachineSpec struct {
 	// +optional
 	SSHPublicKey string `json:"sshPublicKey"`
 
+	// AdditionalSSHPublicKeys are SSH public key strings, base64-encoded, authorized on the Virtual Machine in addition
+	// to SSHPublicKey and to the SSH public keys of the cluster, e.g. one per member of the team operating the machine.
+	// Linux only.
+	// +optional
+	AdditionalSSHPublicKeys []string `json:"additionalSSHPublicKeys,omitempty"`
+
+	// AdminUsername is the name of the administrator account created on the virtual machine. Linux machines only allow
+	// SSH key authentication for this account. Defaults to "capi".
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	AdminUsername string `json:"adminUsername,omitempty"`
+
+	// StoreAdminPassword generates a password for the administrator account of a Windows machine and stores it with the
+	// username in a Secret named "<AzureMachine name>-admin-password", owned by the AzureMachine. Windows only.
+	// +optional
+	StoreAdminPassword bool `json:"storeAdminPassword,omitempty"`
+
 	// AdditionalTags is an optional set of tags to add to an instance, in addition to the ones added by default by the
 	// Azure provider. If both the AzureCluster and the AzureMachine specify the same tag name with different values, the
 	// AzureMachine's value takes precedence.
 	// +optional
 	AdditionalTags Tags `json:"additionalTags,omitempty"`
 
+	// NodeLabelTagMapping mirrors labels of the Kubernetes node of the machine onto tags of its VM, and tags of the VM
+	// onto labels of the node, once the node has joined the cluster.
+	// +optional
+	NodeLabelTagMapping *NodeLabelTagMapping `json:"nodeLabelTagMapping,omitempty"`
+
+	// NodeMetadataAnnotations copies the metadata of the VM reported in the AzureMachine status, such as its VM ID, size
+	// and platform fault domain, onto annotations of the Kubernetes node of the machine once it has joined the cluster.
+	// +optional
+	NodeMetadataAnnotations bool `json:"nodeMetadataAnnotations,omitempty"`
+
 	// AdditionalCapabilities specifies additional capabilities enabled or disabled on the virtual machine.
 	// +optional
 	AdditionalCapabilities *AdditionalCapabilities `json:"additionalCapabilities,omitempty"`
@@ -124,6 +168,12 @@ type AzureMachineSpec struct {
 	// +optional
 	SecurityProfile *SecurityProfile `json:"securityProfile,omitempty"`
 
+	// AzureDiskEncryption enables Azure Disk Encryption (ADE) on the disks of the virtual machine, with the Azure Disk
+	// Encryption VM extension storing the disk encryption secrets in a Key Vault. It can't be combined with an
+	// ephemeral OS disk or with encryption at host. Immutable.
+	// +optional
+	AzureDiskEncryption *AzureDiskEncryption `json:"azureDiskEncryption,omitempty"`
+
 	// Deprecated: SubnetName should be set in the networkInterfaces field.
 	// +optional
 	SubnetName string `json:"subnetName,omitempty"`
@@ -132,6 +182,20 @@ type AzureMachineSpec struct {
 	// +optional
 	DNSServers []string `json:"dnsServers,omitempty"`
 
+	// LoadBalancerBackendPoolIDs are the IDs of existing load balancer backend address pools, managed outside of the
+	// provider, the primary network interface of the machine joins in addition to the backend pools of the cluster, e.g.
+	// /subscriptions/<subscription-id>/resourceGroups/<resource-group>/providers/Microsoft.Network/loadBalancers/<name>/backendAddressPools/<pool>.
+	// The load balancer must be in the virtual network of the machine. It is immutable.
+	// +optional
+	LoadBalancerBackendPoolIDs []string `json:"loadBalancerBackendPoolIDs,omitempty"`
+
+	// ApplicationGatewayBackendPoolIDs are the IDs of existing application gateway backend address pools, managed outside
+	// of the provider, the primary network interface of the machine joins, e.g.
+	// /subscriptions/<subscription-id>/resourceGroups/<resource-group>/providers/Microsoft.Network/applicationGateways/<name>/backendAddressPools/<pool>.
+	// It is immutable.
+	// +optional
+	ApplicationGatewayBackendPoolIDs []string `json:"applicationGatewayBackendPoolIDs,omitempty"`
+
 	// VMExtensions specifies a list of extensions to be added to the virtual machine.
 	// +optional
 	VMExtensions []VMExtension `json:"vmExtensions,omitempty"`
@@ -142,6 +206,121 @@ type AzureMachineSpec struct {
 	// The primary interface will be the first networkInterface specified (index 0) in the list.
 	// +optional
 	NetworkInterfaces []NetworkInterface `json:"networkInterfaces,omitempty"`
+
+	// AdditionalCloudInit specifies cloud-init sections merged into the bootstrap data of the machine, which allows
+	// tuning the OS or installing node agents without building a custom image.
+	// It is only supported with cloud-config bootstrap data.
+	// +optional
+	AdditionalCloudInit *CloudInit `json:"additionalCloudInit,omitempty"`
+
+	// SecondaryUserData references a Secret key whose value is passed to the VM as Azure user data, separately from the
+	// bootstrap data passed as custom data. Node agents can fetch it from the Instance Metadata Service at any time
+	// instead of having it baked into cloud-init. Any process on the VM can read the user data, so it must not hold
+	// credentials. It is only set when the VM is created.
+	// +optional
+	SecondaryUserData *SecondaryUserDataSource `json:"secondaryUserData,omitempty"`
+
+	// SkipShutdown skips powering off the VM before deleting it. By default, the VM is powered off and given a bounded
+	// amount of time to shut down its OS cleanly, so the kubelet and workloads terminate and disks get detached before
+	// the VM is deleted.
+	// +optional
+	SkipShutdown bool `json:"skipShutdown,omitempty"`
+
+	// DataDiskDetach makes the deletion of the VM wait for the data disks attached to it by a CSI driver, i.e. the
+	// data disks which are not listed in DataDisks, to be detached, so they don't get stuck attached to a VM being
+	// deleted. By default, the VM is deleted regardless of the data disks still attached to it.
+	// +optional
+	DataDiskDetach *DataDiskDetach `json:"dataDiskDetach,omitempty"`
+
+	// ReimageOnFailure reimages the VM once when it ends up in the Failed provisioning state, before the machine is
+	// reported as failed. Reimaging keeps the NICs, IP addresses and data disks of the VM and reruns its bootstrap,
+	// which recovers from transient platform issues much faster than replacing the machine.
+	// +optional
+	ReimageOnFailure bool `json:"reimageOnFailure,omitempty"`
+}
+
+// CloudInit defines cloud-init sections to merge into the bootstrap data.
+type CloudInit struct {
+	// WriteFiles specifies files to write on the machine in addition to the ones from the bootstrap data.
+	// +optional
+	WriteFiles []CloudInitFile `json:"writeFiles,omitempty"`
+
+	// RunCmd specifies commands to run before the commands from the bootstrap data.
+	// +optional
+	RunCmd []string `json:"runCmd,omitempty"`
+}
+
+// SecondaryUserDataSource references the Secret key holding the user data of a VM.
+type SecondaryUserDataSource struct {
+	// SecretName is the name of a Secret in the namespace of the AzureMachine.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// Key is the key of the Secret data holding the user data.
+	// +kubebuilder:default=value
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// CloudInitFile defines a file written by cloud-init.
+type CloudInitFile struct {
+	// Path is the absolute path of the file on the machine.
+	Path string `json:"path"`
+
+	// Owner is the owner of the file, in the user:group format.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	// Permissions are the permissions of the file, in octal format, e.g. "0644".
+	// +kubebuilder:validation:Pattern=`^0?[0-7]{3}$`
+	// +optional
+	Permissions string `json:"permissions,omitempty"`
+
+	// Content is the content of the file.
+	Content string `json:"content"`
+}
+
+// NodeLabelTagMapping defines which node labels are mirrored onto VM tags and which VM tags are mirrored onto node labels.
+type NodeLabelTagMapping struct {
+	// LabelsToTags lists the keys of the node labels to copy onto tags of the VM. The tag key is the label key with "/"
+	// replaced by "_", e.g. the label "example.com/team" becomes the tag "example.com_team". The tag is removed when
+	// the label is removed from the node.
+	// +optional
+	LabelsToTags []string `json:"labelsToTags,omitempty"`
+
+	// TagPrefix selects the VM tags to copy onto node labels: the label key is the tag key without the prefix, e.g. the
+	// tag "k8s-label-team" becomes the label "team" with the prefix "k8s-label-". Tags whose key or value is not a
+	// valid label are skipped. The label is removed when the tag is removed from the VM. It must not
+	// select the tags mirroring LabelsToTags. When empty, no tag is copied.
+	// +optional
+	TagPrefix string `json:"tagPrefix,omitempty"`
+}
+
+// DataDiskDetachPolicy is what to do with the data disks still attached to a VM once the data disk detach timeout
+// elapsed.
+// +kubebuilder:validation:Enum=Wait;ForceDetach
+type DataDiskDetachPolicy string
+
+const (
+	// DataDiskDetachWait deletes the VM with the data disks still attached to it.
+	DataDiskDetachWait DataDiskDetachPolicy = "Wait"
+	// DataDiskDetachForceDetach force-detaches the data disks still attached to the VM before deleting it.
+	DataDiskDetachForceDetach DataDiskDetachPolicy = "ForceDetach"
+)
+
+// DataDiskDetach defines how long to wait for the data disks attached to a VM by a CSI driver to be detached before
+// deleting the VM, and what to do with the ones still attached afterwards.
+type DataDiskDetach struct {
+	// Policy is what to do with the data disks still attached once Timeout elapsed. Wait deletes the VM anyway and
+	// ForceDetach force-detaches the disks first, which may lose data not yet flushed to the disks. Defaults to Wait.
+	// +kubebuilder:default=Wait
+	// +optional
+	Policy DataDiskDetachPolicy `json:"policy,omitempty"`
+
+	// Timeout is how long to wait for the data disks to be detached, counted from the deletion of the AzureMachine.
+	// Defaults to 5 minutes.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // SpotVMOptions defines the options relevant to running the Machine on Spot VMs.
@@ -173,6 +352,78 @@ type SystemAssignedIdentityRole struct {
 	Scope string `json:"scope,omitempty"`
 }
 
+// EtcdDataDiskStatus describes the etcd data disk of a machine.
+type EtcdDataDiskStatus struct {
+	// Name is the name of the managed disk.
+	Name string `json:"name"`
+
+	// Device is the path of the block device of the disk on the machine.
+	Device string `json:"device"`
+
+	// MountPath is the path where the disk is mounted on the machine.
+	MountPath string `json:"mountPath"`
+}
+
+// SSHEndpoint describes the endpoint forwarded to the SSH port of a machine.
+type SSHEndpoint struct {
+	// Host is the DNS name of the API server public IP. It is empty when the public IP has no DNS name.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Port is the frontend port of the API server load balancer forwarded to the SSH port of the machine.
+	Port int32 `json:"port"`
+}
+
+// VMStatus describes the Azure resources of the virtual machine of a machine.
+type VMStatus struct {
+	// ID is the resource ID of the virtual machine.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// NetworkInterfaceIDs are the resource IDs of the network interfaces attached to the virtual machine.
+	// +optional
+	NetworkInterfaceIDs []string `json:"networkInterfaceIDs,omitempty"`
+
+	// OSDiskID is the resource ID of the OS disk of the virtual machine.
+	// +optional
+	OSDiskID string `json:"osDiskID,omitempty"`
+
+	// Image is the image the virtual machine was created from. The version of marketplace images is the version that
+	// was actually applied, even when the machine specifies 'latest'.
+	// +optional
+	Image *Image `json:"image,omitempty"`
+
+	// Zone is the availability zone the virtual machine was placed in.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+
+	// VMID is the unique ID of the virtual machine, as also reported by the Azure Instance Metadata Service.
+	// +optional
+	VMID string `json:"vmID,omitempty"`
+
+	// Size is the size of the virtual machine.
+	// +optional
+	Size string `json:"size,omitempty"`
+
+	// PlatformFaultDomain is the fault domain the virtual machine was placed in.
+	// +optional
+	PlatformFaultDomain *int32 `json:"platformFaultDomain,omitempty"`
+}
+
+// ZoneSubstitution describes the availability zone used for a virtual machine instead of the zone of the failure
+// domain of its machine.
+type ZoneSubstitution struct {
+	// FailureDomain is the failure domain of the machine, whose zone the VM size is not available in.
+	FailureDomain string `json:"failureDomain"`
+
+	// Zone is the availability zone used instead.
+	Zone string `json:"zone"`
+
+	// Reason explains why the zone of the failure domain cannot be used.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
 // AzureMachineStatus defines the observed state of AzureMachine.
 type AzureMachineStatus struct {
 	// Ready is true when the provider resource is ready.
@@ -187,6 +438,44 @@ type AzureMachineStatus struct {
 	// +optional
 	VMState *ProvisioningState `json:"vmState,omitempty"`
 
+	// PowerState is the power state of the Azure virtual machine.
+	// +optional
+	PowerState VMPowerState `json:"powerState,omitempty"`
+
+	// Reimaged is true once the VM has been reimaged after it ended up in the Failed provisioning state.
+	// +optional
+	Reimaged bool `json:"reimaged,omitempty"`
+
+	// VM describes the Azure resources of the virtual machine, to correlate the machine with them without querying
+	// Azure.
+	// +optional
+	VM *VMStatus `json:"vm,omitempty"`
+
+	// FailureDomain is the platform fault domain of the availability set the virtual machine was placed in, in regions
+	// without availability zones. It matches the topology.kubernetes.io/zone label set on the node by the cloud
+	// provider.
+	// +optional
+	FailureDomain string `json:"failureDomain,omitempty"`
+
+	// ZoneSubstitution records the availability zone the virtual machine is created in instead of the zone of the
+	// failure domain of the machine, when allowZoneFallback is set and the VM size is not available in that zone.
+	// +optional
+	ZoneSubstitution *ZoneSubstitution `json:"zoneSubstitution,omitempty"`
+
+	// EtcdDataDisk describes the data disk designated as the etcd data disk of the machine.
+	// +optional
+	EtcdDataDisk *EtcdDataDiskStatus `json:"etcdDataDisk,omitempty"`
+
+	// SSHEndpoint is the endpoint of the API server load balancer forwarded to the SSH port of the machine by an inbound
+	// NAT rule. Only set on control plane machines of clusters with a public API server load balancer.
+	// +optional
+	SSHEndpoint *SSHEndpoint `json:"sshEndpoint,omitempty"`
+
+	// NodeRef is the node of the workload cluster running on the virtual machine, found by its provider ID. It is set
+	// as soon as the node registers, before the Machine references it.
+	// +optional
+	NodeRef *corev1.ObjectReference `json:"nodeRef,omitempty"`
+
 	// ErrorReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
@@ -242,6 +531,11 @@ type AdditionalCapabilities struct {
 	// otherwise it doesn't set the capability on the VM.
 	// +optional
 	UltraSSDEnabled *bool `json:"ultraSSDEnabled,omitempty"`
+
+	// HibernationEnabled enables or disables the hibernation capability of the virtual machine, which allows
+	// hibernating it through the power state annotation.
+	// +optional
+	HibernationEnabled *bool `json:"hibernationEnabled,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -249,6 +543,7 @@ type AdditionalCapabilities struct {
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason"
 // +kubebuilder:printcolumn:name="Message",type="string",priority=1,JSONPath=".status.conditions[?(@.type=='Ready')].message"
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.vmState",description="Azure VM provisioning state"
+// +kubebuilder:printcolumn:name="Power State",type="string",priority=1,JSONPath=".status.powerState",description="Azure VM power state"
 // +kubebuilder:printcolumn:name="Cluster",type="string",priority=1,JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this AzureMachine belongs"
 // +kubebuilder:printcolumn:name="Machine",type="string",priority=1,JSONPath=".metadata.ownerReferences[?(@.kind==\"Machine\")].name",description="Machine object to which this AzureMachine belongs"
 // +kubebuilder:printcolumn:name="VM ID",type="string",priority=1,JSONPath=".spec.providerID",description="Azure VM ID"
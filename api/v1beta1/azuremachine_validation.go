@@ -19,11 +19,19 @@ package v1beta1
 import (
 	"encoding/base64"
 	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/ssh"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 )
 
@@ -35,18 +43,52 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if spec.Image != nil && spec.Image.ComputeGallery != nil {
+		if _, ok := spec.Image.ComputeGallery.LatestVersionPrefix(); ok {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("image", "computeGallery", "version"), "Major.Minor.latest versions are only supported by AzureMachinePools"))
+		}
+	}
+
+	if errs := ValidateImageLookup(spec.Image, spec.ImageLookup, spec.ImageVariant, spec.OSDisk.OSType, field.NewPath("imageLookup")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := ValidateOSDisk(spec.OSDisk, field.NewPath("osDisk")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if spec.OSDisk.Source != nil && spec.SecondaryUserData != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("secondaryUserData"), "secondaryUserData cannot be set when the OS disk has a source, as the bootstrap data is passed as user data"))
+	}
+
 	if errs := ValidateConfidentialCompute(spec.OSDisk.ManagedDisk, spec.SecurityProfile, field.NewPath("securityProfile")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateAzureDiskEncryption(spec.AzureDiskEncryption, spec.OSDisk, spec.SecurityProfile, field.NewPath("azureDiskEncryption")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := ValidateSSHKey(spec.SSHPublicKey, field.NewPath("sshPublicKey")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
 
+	for i, key := range spec.AdditionalSSHPublicKeys {
+		allErrs = append(allErrs, ValidateSSHKey(key, field.NewPath("additionalSSHPublicKeys").Index(i))...)
+	}
+
+	if errs := ValidateAdminUsername(spec.AdminUsername, spec.OSDisk.OSType, field.NewPath("adminUsername")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if spec.StoreAdminPassword && spec.OSDisk.OSType != WindowsOS {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("storeAdminPassword"), "password authentication is disabled on Linux machines, only SSH keys can be used"))
+	}
+
+	if spec.AllowZoneFallback && spec.AllocatePublicIP {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("allowZoneFallback"), "allowZoneFallback cannot be set along with allocatePublicIP, as the zonal public IP of the machine is created before the VM"))
+	}
+
 	if errs := ValidateUserAssignedIdentity(spec.Identity, spec.UserAssignedIdentities, field.NewPath("userAssignedIdentities")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
@@ -55,6 +97,14 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateWriteAccelerator(spec.VMSize, spec.OSDisk, spec.DataDisks, field.NewPath("osDisk"), field.NewPath("dataDisks")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateEtcdDataDisk(spec.OSDisk.OSType, spec.DataDisks, field.NewPath("dataDisks")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := ValidateDiagnostics(spec.Diagnostics, field.NewPath("diagnostics")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
@@ -63,10 +113,137 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateBackendPoolIDs(spec.LoadBalancerBackendPoolIDs, loadBalancerBackendPoolID, field.NewPath("loadBalancerBackendPoolIDs")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateBackendPoolIDs(spec.ApplicationGatewayBackendPoolIDs, applicationGatewayBackendPoolID, field.NewPath("applicationGatewayBackendPoolIDs")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := ValidateSystemAssignedIdentityRole(spec.Identity, spec.RoleAssignmentName, spec.SystemAssignedIdentityRole, field.NewPath("systemAssignedIdentityRole")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateAdditionalCloudInit(spec.AdditionalCloudInit, field.NewPath("additionalCloudInit")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateNodeLabelTagMapping(spec.NodeLabelTagMapping, field.NewPath("nodeLabelTagMapping")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	return allErrs
+}
+
+var (
+	loadBalancerBackendPoolID       = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourcegroups/[^/]+/providers/microsoft\.network/loadbalancers/[^/]+/backendaddresspools/[^/]+$`)
+	applicationGatewayBackendPoolID = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourcegroups/[^/]+/providers/microsoft\.network/applicationgateways/[^/]+/backendaddresspools/[^/]+$`)
+	galleryImageVersionID           = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourcegroups/[^/]+/providers/microsoft\.compute/galleries/[^/]+/images/[^/]+/versions/[0-9]+\.[0-9]+\.[0-9]+$`)
+	keyVaultID                      = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourcegroups/[^/]+/providers/microsoft\.keyvault/vaults/[^/]+$`)
+)
+
+// ValidateAzureDiskEncryption validates the Azure Disk Encryption settings of a machine. Azure Disk Encryption can't
+// encrypt an ephemeral OS disk, and can't be enabled along with encryption at host.
+func ValidateAzureDiskEncryption(ade *AzureDiskEncryption, osDisk OSDisk, profile *SecurityProfile, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if ade == nil {
+		return allErrs
+	}
+
+	if !keyVaultID.MatchString(ade.KeyVaultID) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("keyVaultID"), ade.KeyVaultID, "is not a valid Key Vault ID"))
+	}
+	if ade.KeyEncryptionKeyVaultID != "" && !keyVaultID.MatchString(ade.KeyEncryptionKeyVaultID) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("keyEncryptionKeyVaultID"), ade.KeyEncryptionKeyVaultID, "is not a valid Key Vault ID"))
+	}
+	if u, err := url.Parse(ade.KeyVaultURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("keyVaultURL"), ade.KeyVaultURL, "must be an https URL"))
+	}
+	if u, err := url.Parse(ade.KeyEncryptionKeyURL); err != nil || u.Scheme != "https" || u.Host == "" || !strings.HasPrefix(u.Path, "/keys/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("keyEncryptionKeyURL"), ade.KeyEncryptionKeyURL, "must be the https URL of a Key Vault key"))
+	}
+
+	if osDisk.DiffDiskSettings != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Azure Disk Encryption cannot be enabled on a machine with an ephemeral OS disk"))
+	}
+	if profile != nil && ptr.Deref(profile.EncryptionAtHost, false) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Azure Disk Encryption cannot be enabled on a machine with encryption at host"))
+	}
+	return allErrs
+}
+
+// ValidateBackendPoolIDs validates the IDs of existing backend address pools a machine joins.
+func ValidateBackendPoolIDs(ids []string, pattern *regexp.Regexp, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := make(map[string]bool, len(ids))
+	for i, id := range ids {
+		if !pattern.MatchString(id) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), id, "is not a valid backend address pool ID"))
+			continue
+		}
+		if seen[strings.ToLower(id)] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), id))
+		}
+		seen[strings.ToLower(id)] = true
+	}
+	return allErrs
+}
+
+// ValidateNodeLabelTagMapping validates the mapping between node labels and VM tags.
+func ValidateNodeLabelTagMapping(mapping *NodeLabelTagMapping, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if mapping == nil {
+		return allErrs
+	}
+
+	seen := make(map[string]bool, len(mapping.LabelsToTags))
+	for i, label := range mapping.LabelsToTags {
+		labelPath := fldPath.Child("labelsToTags").Index(i)
+		for _, msg := range validation.IsQualifiedName(label) {
+			allErrs = append(allErrs, field.Invalid(labelPath, label, msg))
+		}
+		if seen[label] {
+			allErrs = append(allErrs, field.Duplicate(labelPath, label))
+		}
+		seen[label] = true
+	}
+
+	if mapping.TagPrefix != "" {
+		for _, label := range mapping.LabelsToTags {
+			if strings.HasPrefix(strings.ReplaceAll(label, "/", "_"), mapping.TagPrefix) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("tagPrefix"), mapping.TagPrefix, fmt.Sprintf("must not be a prefix of the tag mirroring label %q", label)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateAdditionalCloudInit validates the cloud-init sections merged into the bootstrap data.
+func ValidateAdditionalCloudInit(cloudInit *CloudInit, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if cloudInit == nil {
+		return allErrs
+	}
+
+	paths := map[string]struct{}{}
+	for i, file := range cloudInit.WriteFiles {
+		filePath := fldPath.Child("writeFiles").Index(i).Child("path")
+		if !path.IsAbs(file.Path) {
+			allErrs = append(allErrs, field.Invalid(filePath, file.Path, "path must be absolute"))
+		}
+		if _, ok := paths[file.Path]; ok {
+			allErrs = append(allErrs, field.Duplicate(filePath, file.Path))
+		}
+		paths[file.Path] = struct{}{}
+	}
+
+	for i, cmd := range cloudInit.RunCmd {
+		if strings.TrimSpace(cmd) == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("runCmd").Index(i), "command must not be empty"))
+		}
+	}
+
 	return allErrs
 }
 
@@ -80,13 +257,20 @@ func ValidateNetwork(subnetName string, acceleratedNetworking *bool, networkInte
 		return field.ErrorList{field.Invalid(fldPath, networkInterfaces, "cannot set both networkInterfaces and machine acceleratedNetworking")}
 	}
 
-	for _, nic := range networkInterfaces {
+	allErrs := field.ErrorList{}
+	for i, nic := range networkInterfaces {
 		if nic.PrivateIPConfigs < 1 {
 			return field.ErrorList{field.Invalid(fldPath, networkInterfaces, "number of privateIPConfigs per interface must be at least 1")}
 		}
+		if len(nic.AddressesFromPools) > nic.PrivateIPConfigs {
+			allErrs = append(allErrs, field.TooMany(fldPath.Index(i).Child("addressesFromPools"), len(nic.AddressesFromPools), nic.PrivateIPConfigs))
+		}
+		for j, pool := range nic.AddressesFromPools {
+			allErrs = append(allErrs, validateIPAddressPoolRef(pool, fldPath.Index(i).Child("addressesFromPools").Index(j))...)
+		}
 	}
 
-	return field.ErrorList{}
+	return allErrs
 }
 
 // ValidateSSHKey validates an SSHKey.
@@ -107,6 +291,40 @@ func ValidateSSHKey(sshKey string, fldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+// reservedAdminUsernames are the names Azure does not allow for the administrator account of a virtual machine.
+var reservedAdminUsernames = sets.New(
+	"1", "123", "a", "actuser", "adm", "admin", "admin1", "admin2", "administrator", "aspnet", "backup", "console",
+	"david", "guest", "john", "owner", "root", "server", "sql", "support", "support_388945a0", "sys", "test", "test1",
+	"test2", "test3", "user", "user1", "user2", "user3", "user4", "user5",
+)
+
+// ValidateAdminUsername validates the name of the administrator account of a virtual machine.
+func ValidateAdminUsername(username, osType string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if username == "" {
+		return allErrs
+	}
+
+	maxLength := 64
+	if osType == WindowsOS {
+		maxLength = 20
+	}
+	if len(username) > maxLength {
+		allErrs = append(allErrs, field.TooLong(fldPath, username, maxLength))
+	}
+	if strings.HasSuffix(username, ".") {
+		allErrs = append(allErrs, field.Invalid(fldPath, username, "the admin username cannot end with a period"))
+	}
+	if strings.ContainsAny(username, `\/"[]:|<>+=;,?*@& `) {
+		allErrs = append(allErrs, field.Invalid(fldPath, username, `the admin username cannot contain spaces or any of the characters \/"[]:|<>+=;,?*@&`))
+	}
+	if reservedAdminUsernames.Has(strings.ToLower(username)) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("%q is a reserved name and cannot be used as the admin username", username)))
+	}
+
+	return allErrs
+}
+
 // ValidateSystemAssignedIdentity validates the system-assigned identities list.
 func ValidateSystemAssignedIdentity(identityType VMIdentity, oldIdentity, newIdentity string, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -210,6 +428,26 @@ func ValidateDataDisks(dataDisks []DataDisk, fieldPath *field.Path) field.ErrorL
 	return allErrs
 }
 
+// ValidateEtcdDataDisk validates that at most one data disk of a Linux machine is designated as the etcd data disk.
+func ValidateEtcdDataDisk(osType string, dataDisks []DataDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	etcdDataDiskFound := false
+	for i, disk := range dataDisks {
+		if !ptr.Deref(disk.Etcd, false) {
+			continue
+		}
+		etcdPath := fieldPath.Index(i).Child("etcd")
+		if etcdDataDiskFound {
+			allErrs = append(allErrs, field.Invalid(etcdPath, true, "only one data disk can be designated as the etcd data disk"))
+		}
+		if osType == string(armcompute.OperatingSystemTypesWindows) {
+			allErrs = append(allErrs, field.Invalid(etcdPath, true, "etcd data disks are not supported on Windows machines"))
+		}
+		etcdDataDiskFound = true
+	}
+	return allErrs
+}
+
 // ValidateOSDisk validates the OSDisk spec.
 func ValidateOSDisk(osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -240,6 +478,56 @@ func ValidateOSDisk(osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
 		))
 	}
 
+	if osDisk.Source != nil {
+		allErrs = append(allErrs, validateOSDiskSource(osDisk, fieldPath.Child("source"))...)
+	}
+
+	allErrs = append(allErrs, ValidateOSDiskZoneRedundancy(osDisk, fieldPath)...)
+
+	return allErrs
+}
+
+// ValidateOSDiskZoneRedundancy validates that a zone-redundant OS disk is a managed disk with a storage account type
+// that has a zone-redundant variant.
+func ValidateOSDiskZoneRedundancy(osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !ptr.Deref(osDisk.ZoneRedundant, false) {
+		return allErrs
+	}
+
+	zoneRedundantPath := fieldPath.Child("zoneRedundant")
+	if osDisk.DiffDiskSettings != nil {
+		allErrs = append(allErrs, field.Forbidden(zoneRedundantPath, "ephemeral OS disks cannot be zone-redundant"))
+	}
+	if osDisk.ManagedDisk == nil {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("managedDisk"), "a zone-redundant OS disk must be a managed disk"))
+	} else if _, ok := zoneRedundantStorageAccountTypes[osDisk.ManagedDisk.StorageAccountType]; !ok {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("managedDisk", "storageAccountType"), osDisk.ManagedDisk.StorageAccountType,
+			"a zone-redundant OS disk must be a Premium SSD or a Standard SSD disk"))
+	}
+	return allErrs
+}
+
+// validateOSDiskSource validates the snapshot or disk restore point an OS disk is created from.
+func validateOSDiskSource(osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	source := osDisk.Source
+
+	switch {
+	case source.SnapshotID == nil && source.RestorePointID == nil:
+		allErrs = append(allErrs, field.Required(fieldPath, "one of snapshotID and restorePointID must be set"))
+	case source.SnapshotID != nil && source.RestorePointID != nil:
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "only one of snapshotID and restorePointID can be set"))
+	case source.SnapshotID != nil && !isResourceIDOfType(*source.SnapshotID, "Microsoft.Compute/snapshots"):
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("snapshotID"), *source.SnapshotID, "must be the resource ID of a snapshot"))
+	case source.RestorePointID != nil && !isResourceIDOfType(*source.RestorePointID, "Microsoft.Compute/restorePointCollections/restorePoints/diskRestorePoints"):
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("restorePointID"), *source.RestorePointID, "must be the resource ID of a disk restore point"))
+	}
+
+	if osDisk.DiffDiskSettings != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "an ephemeral OS disk cannot be created from a snapshot or a restore point"))
+	}
+
 	return allErrs
 }
 
@@ -250,6 +538,14 @@ func validateManagedDisk(m *ManagedDiskParameters, fieldPath *field.Path, isOSDi
 	if m != nil {
 		allErrs = append(allErrs, validateStorageAccountType(m.StorageAccountType, fieldPath.Child("StorageAccountType"), isOSDisk)...)
 
+		// Only Premium SSD v2 and Ultra disks have a configurable performance, and only as data disks.
+		if m.DiskIOPSReadWrite != nil || m.DiskMBpsReadWrite != nil {
+			if isOSDisk || !hasConfigurablePerformance(m.StorageAccountType) {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("storageAccountType"), m.StorageAccountType,
+					fmt.Sprintf("diskIOPSReadWrite and diskMBpsReadWrite can only be set on data disks when storageAccountType is '%s' or '%s'", armcompute.StorageAccountTypesPremiumV2LRS, armcompute.StorageAccountTypesUltraSSDLRS)))
+			}
+		}
+
 		// DiskEncryptionSet can only be set when SecurityEncryptionType is set to DiskWithVMGuestState
 		// https://learn.microsoft.com/en-us/rest/api/compute/virtual-machines/create-or-update?tabs=HTTP#securityencryptiontypes
 		if isOSDisk && m.SecurityProfile != nil && m.SecurityProfile.DiskEncryptionSet != nil {
@@ -301,6 +597,10 @@ func ValidateDataDisksUpdate(oldDataDisks, newDataDisks []DataDisk, fieldPath *f
 			if newDisk.CachingType != oldDisk.CachingType {
 				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("cachingType"), newDataDisks, fieldErrMsg))
 			}
+
+			if ptr.Deref(newDisk.WriteAcceleratorEnabled, false) != ptr.Deref(oldDisk.WriteAcceleratorEnabled, false) {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("writeAcceleratorEnabled"), newDataDisks, fieldErrMsg))
+			}
 		} else {
 			allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("nameSuffix"), newDataDisks, diskErrMsg))
 		}
@@ -337,6 +637,9 @@ func validateStorageAccountType(storageAccountType string, fieldPath *field.Path
 	if isOSDisk && storageAccountType == string(armcompute.StorageAccountTypesUltraSSDLRS) {
 		allErrs = append(allErrs, field.Invalid(fieldPath.Child("managedDisks").Child("storageAccountType"), storageAccountType, "UltraSSD_LRS can only be used with data disks, it cannot be used with OS Disks"))
 	}
+	if isOSDisk && storageAccountType == string(armcompute.StorageAccountTypesPremiumV2LRS) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("managedDisks").Child("storageAccountType"), storageAccountType, "PremiumV2_LRS can only be used with data disks, it cannot be used with OS Disks"))
+	}
 
 	if storageAccountType == "" {
 		allErrs = append(allErrs, field.Required(fieldPath, "the Storage Account Type for Managed Disk cannot be empty"))
@@ -356,9 +659,9 @@ func validateCachingType(cachingType string, fieldPath *field.Path, managedDisk
 	allErrs := field.ErrorList{}
 	cachingTypeChildPath := fieldPath.Child("CachingType")
 
-	if managedDisk != nil && managedDisk.StorageAccountType == string(armcompute.StorageAccountTypesUltraSSDLRS) {
+	if managedDisk != nil && hasConfigurablePerformance(managedDisk.StorageAccountType) {
 		if cachingType != string(armcompute.CachingTypesNone) {
-			allErrs = append(allErrs, field.Invalid(cachingTypeChildPath, cachingType, fmt.Sprintf("cachingType '%s' is not supported when storageAccountType is '%s'. Allowed values are: '%s'", cachingType, armcompute.StorageAccountTypesUltraSSDLRS, armcompute.CachingTypesNone)))
+			allErrs = append(allErrs, field.Invalid(cachingTypeChildPath, cachingType, fmt.Sprintf("cachingType '%s' is not supported when storageAccountType is '%s'. Allowed values are: '%s'", cachingType, managedDisk.StorageAccountType, armcompute.CachingTypesNone)))
 		}
 	}
 
@@ -372,6 +675,70 @@ func validateCachingType(cachingType string, fieldPath *field.Path, managedDisk
 	return allErrs
 }
 
+// withoutDiskPerformance returns a copy of the data disks without their IOPS and throughput.
+func withoutDiskPerformance(dataDisks []DataDisk) []DataDisk {
+	if dataDisks == nil {
+		return nil
+	}
+	disks := make([]DataDisk, len(dataDisks))
+	for i := range dataDisks {
+		dataDisks[i].DeepCopyInto(&disks[i])
+		if disks[i].ManagedDisk != nil {
+			disks[i].ManagedDisk.DiskIOPSReadWrite = nil
+			disks[i].ManagedDisk.DiskMBpsReadWrite = nil
+		}
+	}
+	return disks
+}
+
+// hasConfigurablePerformance returns true for the storage account types of the disks whose IOPS and throughput can be
+// set independently of their size, i.e. Premium SSD v2 and Ultra disks. Those disks support no host caching.
+func hasConfigurablePerformance(storageAccountType string) bool {
+	return storageAccountType == string(armcompute.StorageAccountTypesPremiumV2LRS) ||
+		storageAccountType == string(armcompute.StorageAccountTypesUltraSSDLRS)
+}
+
+// ValidateWriteAccelerator validates that write accelerator is only enabled on disks and VM sizes that support it.
+func ValidateWriteAccelerator(vmSize string, osDisk OSDisk, dataDisks []DataDisk, osDiskPath, dataDisksPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if ptr.Deref(osDisk.WriteAcceleratorEnabled, false) {
+		allErrs = append(allErrs, validateWriteAcceleratorDisk(vmSize, osDisk.CachingType, osDisk.ManagedDisk, osDiskPath)...)
+		if osDisk.DiffDiskSettings != nil {
+			allErrs = append(allErrs, field.Invalid(osDiskPath.Child("writeAcceleratorEnabled"), true, "write accelerator is not supported on ephemeral OS disks"))
+		}
+	}
+
+	for i, disk := range dataDisks {
+		if ptr.Deref(disk.WriteAcceleratorEnabled, false) {
+			allErrs = append(allErrs, validateWriteAcceleratorDisk(vmSize, disk.CachingType, disk.ManagedDisk, dataDisksPath.Index(i))...)
+		}
+	}
+
+	return allErrs
+}
+
+func validateWriteAcceleratorDisk(vmSize, cachingType string, managedDisk *ManagedDiskParameters, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	writeAcceleratorPath := fieldPath.Child("writeAcceleratorEnabled")
+
+	// Write accelerator is only offered on the M-series VM sizes, the number of disks it can be enabled on depends on
+	// the size and is checked against the SKU capabilities when the VM is created.
+	if !strings.HasPrefix(strings.ToLower(vmSize), "standard_m") {
+		allErrs = append(allErrs, field.Invalid(writeAcceleratorPath, true, fmt.Sprintf("write accelerator is not supported on VM size '%s', only M-series VM sizes support it", vmSize)))
+	}
+
+	if managedDisk == nil || (managedDisk.StorageAccountType != string(armcompute.StorageAccountTypesPremiumLRS) && managedDisk.StorageAccountType != string(armcompute.StorageAccountTypesPremiumZRS)) {
+		allErrs = append(allErrs, field.Invalid(writeAcceleratorPath, true, fmt.Sprintf("write accelerator is only supported when storageAccountType is '%s' or '%s'", armcompute.StorageAccountTypesPremiumLRS, armcompute.StorageAccountTypesPremiumZRS)))
+	}
+
+	if cachingType == string(armcompute.CachingTypesReadWrite) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("cachingType"), cachingType, fmt.Sprintf("cachingType '%s' is not supported when write accelerator is enabled. Allowed values are: '%s', '%s'", cachingType, armcompute.CachingTypesNone, armcompute.CachingTypesReadOnly)))
+	}
+
+	return allErrs
+}
+
 // ValidateDiagnostics validates the Diagnostic spec.
 func ValidateDiagnostics(diagnostics *Diagnostics, fieldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -454,3 +821,56 @@ func ValidateConfidentialCompute(managedDisk *ManagedDiskParameters, profile *Se
 
 	return allErrs
 }
+
+// ValidateVMPowerStateAnnotation validates the power state requested through the VMPowerStateAnnotation, if any. A VM
+// can only be hibernated when its hibernation capability is enabled.
+func ValidateVMPowerStateAnnotation(annotations map[string]string, capabilities *AdditionalCapabilities, fieldPath *field.Path) field.ErrorList {
+	powerState, ok := annotations[VMPowerStateAnnotation]
+	if !ok {
+		return nil
+	}
+	switch VMPowerState(powerState) {
+	case VMPowerStateRunning, VMPowerStateDeallocated:
+		return nil
+	case VMPowerStateHibernated:
+		if capabilities == nil || !ptr.Deref(capabilities.HibernationEnabled, false) {
+			return field.ErrorList{field.Forbidden(fieldPath.Key(VMPowerStateAnnotation),
+				"VMs can only be hibernated when spec.additionalCapabilities.hibernationEnabled is true")}
+		}
+		return nil
+	default:
+		return field.ErrorList{field.NotSupported(fieldPath.Key(VMPowerStateAnnotation), powerState,
+			[]string{string(VMPowerStateRunning), string(VMPowerStateDeallocated), string(VMPowerStateHibernated)})}
+	}
+}
+
+// ValidateWindowsFeatureGate validates that new Windows machines are only created while the Windows feature gate is
+// enabled.
+func ValidateWindowsFeatureGate(osType string, fieldPath *field.Path) field.ErrorList {
+	if osType == WindowsOS && !feature.Gates.Enabled(feature.Windows) {
+		return field.ErrorList{field.Forbidden(fieldPath, "Windows machines can be created only if the Windows feature flag is enabled")}
+	}
+	return nil
+}
+
+// ValidateCaptureImageAnnotation validates the gallery image version requested through the CaptureImageAnnotation, if
+// any. Only Linux VMs can be captured, and the power state of a VM being captured cannot be requested at the same time.
+func ValidateCaptureImageAnnotation(annotations map[string]string, osType string, fieldPath *field.Path) field.ErrorList {
+	id, ok := annotations[CaptureImageAnnotation]
+	if !ok {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if !galleryImageVersionID.MatchString(id) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Key(CaptureImageAnnotation), id,
+			"must be the resource ID of a compute gallery image version with a Major.Minor.Patch version"))
+	}
+	if osType == WindowsOS {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Key(CaptureImageAnnotation), "Windows VMs cannot be captured"))
+	}
+	if _, ok := annotations[VMPowerStateAnnotation]; ok {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Key(CaptureImageAnnotation),
+			fmt.Sprintf("cannot be set together with the %s annotation", VMPowerStateAnnotation)))
+	}
+	return allErrs
+}
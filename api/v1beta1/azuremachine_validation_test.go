@@ -21,14 +21,19 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/google/uuid"
 	. "github.com/onsi/gomega"
 	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 )
 
 func TestAzureMachine_ValidateSSHKey(t *testing.T) {
@@ -82,6 +87,76 @@ type osDiskTestInput struct {
 	osDisk  OSDisk
 }
 
+func TestAzureMachine_ValidateAdminUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		osType   string
+		wantErr  bool
+	}{
+		{
+			name:     "default username",
+			username: "",
+			osType:   LinuxOS,
+			wantErr:  false,
+		},
+		{
+			name:     "valid Linux username",
+			username: "azureuser",
+			osType:   LinuxOS,
+			wantErr:  false,
+		},
+		{
+			name:     "valid Windows username",
+			username: "capiadmin",
+			osType:   WindowsOS,
+			wantErr:  false,
+		},
+		{
+			name:     "Windows username too long",
+			username: "averylongadminusername",
+			osType:   WindowsOS,
+			wantErr:  true,
+		},
+		{
+			name:     "Linux username too long",
+			username: strings.Repeat("a", 65),
+			osType:   LinuxOS,
+			wantErr:  true,
+		},
+		{
+			name:     "username ending with a period",
+			username: "azureuser.",
+			osType:   LinuxOS,
+			wantErr:  true,
+		},
+		{
+			name:     "username with a forbidden character",
+			username: "azure@user",
+			osType:   LinuxOS,
+			wantErr:  true,
+		},
+		{
+			name:     "reserved username",
+			username: "Administrator",
+			osType:   WindowsOS,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateAdminUsername(tc.username, tc.osType, field.NewPath("adminUsername"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestAzureMachine_ValidateOSDisk(t *testing.T) {
 	testcases := []osDiskTestInput{
 		{
@@ -127,6 +202,133 @@ func TestAzureMachine_ValidateOSDisk(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "valid zone-redundant os disk spec",
+			wantErr: false,
+			osDisk: OSDisk{
+				DiskSizeGB:    ptr.To[int32](30),
+				CachingType:   "None",
+				OSType:        "blah",
+				ZoneRedundant: ptr.To(true),
+				ManagedDisk: &ManagedDiskParameters{
+					StorageAccountType: "StandardSSD_LRS",
+				},
+			},
+		},
+		{
+			name:    "zone-redundant os disk without a managed disk",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:    ptr.To[int32](30),
+				CachingType:   "None",
+				OSType:        "blah",
+				ZoneRedundant: ptr.To(true),
+			},
+		},
+		{
+			name:    "zone-redundant os disk with a storage account type without a zone-redundant variant",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:    ptr.To[int32](30),
+				CachingType:   "None",
+				OSType:        "blah",
+				ZoneRedundant: ptr.To(true),
+				ManagedDisk: &ManagedDiskParameters{
+					StorageAccountType: "Standard_LRS",
+				},
+			},
+		},
+		{
+			name:    "zone-redundant ephemeral os disk",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:    ptr.To[int32](30),
+				CachingType:   "ReadOnly",
+				OSType:        "blah",
+				ZoneRedundant: ptr.To(true),
+				DiffDiskSettings: &DiffDiskSettings{
+					Option: string(armcompute.DiffDiskOptionsLocal),
+				},
+				ManagedDisk: &ManagedDiskParameters{
+					StorageAccountType: "Premium_LRS",
+				},
+			},
+		}, {
+			name:    "valid os disk created from a snapshot",
+			wantErr: false,
+			osDisk: OSDisk{
+				DiskSizeGB:  ptr.To[int32](30),
+				CachingType: "None",
+				OSType:      "Linux",
+				Source: &OSDiskSource{
+					SnapshotID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot"),
+				},
+			},
+		},
+		{
+			name:    "valid os disk created from a disk restore point",
+			wantErr: false,
+			osDisk: OSDisk{
+				DiskSizeGB:  ptr.To[int32](30),
+				CachingType: "None",
+				OSType:      "Linux",
+				Source: &OSDiskSource{
+					RestorePointID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/restorePointCollections/my-collection/restorePoints/my-restore-point/diskRestorePoints/my-disk-restore-point"),
+				},
+			},
+		},
+		{
+			name:    "os disk source with both a snapshot and a restore point",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:  ptr.To[int32](30),
+				CachingType: "None",
+				OSType:      "Linux",
+				Source: &OSDiskSource{
+					SnapshotID:     ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot"),
+					RestorePointID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/restorePointCollections/my-collection/restorePoints/my-restore-point/diskRestorePoints/my-disk-restore-point"),
+				},
+			},
+		},
+		{
+			name:    "os disk source with an invalid snapshot ID",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:  ptr.To[int32](30),
+				CachingType: "None",
+				OSType:      "Linux",
+				Source: &OSDiskSource{
+					SnapshotID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk"),
+				},
+			},
+		},
+		{
+			name:    "ephemeral os disk created from a snapshot",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:  ptr.To[int32](30),
+				CachingType: "ReadOnly",
+				OSType:      "Linux",
+				DiffDiskSettings: &DiffDiskSettings{
+					Option: string(armcompute.DiffDiskOptionsLocal),
+				},
+				Source: &OSDiskSource{
+					SnapshotID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot"),
+				},
+			},
+		},
+		{
+			name:    "invalid PremiumV2_LRS os disk",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:  ptr.To[int32](30),
+				CachingType: "None",
+				OSType:      "blah",
+				ManagedDisk: &ManagedDiskParameters{
+					StorageAccountType: string(armcompute.StorageAccountTypesPremiumV2LRS),
+				},
+			},
+		},
 	}
 	testcases = append(testcases, generateNegativeTestCases()...)
 
@@ -426,6 +628,69 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid PremiumV2_LRS data disk with IOPS and throughput",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(armcompute.StorageAccountTypesPremiumV2LRS),
+						DiskIOPSReadWrite:  ptr.To[int64](5000),
+						DiskMBpsReadWrite:  ptr.To[int64](200),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: string(armcompute.CachingTypesNone),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid combination of managed disk storage account type PremiumV2_LRS and cachingType ReadOnly",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(armcompute.StorageAccountTypesPremiumV2LRS),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: string(armcompute.CachingTypesReadOnly),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid IOPS on a Premium_LRS data disk",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(armcompute.StorageAccountTypesPremiumLRS),
+						DiskIOPSReadWrite:  ptr.To[int64](5000),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: string(armcompute.CachingTypesNone),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid zone-redundant data disk",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(armcompute.StorageAccountTypesStandardSSDZRS),
+					},
+					Lun:         ptr.To[int32](0),
+					CachingType: string(armcompute.CachingTypesReadWrite),
+				},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, test := range testcases {
@@ -915,6 +1180,39 @@ func TestAzureMachine_ValidateNetwork(t *testing.T) {
 			}},
 			wantErr: true,
 		},
+		{
+			name: "valid config with addresses from pools",
+			networkInterfaces: []NetworkInterface{{
+				SubnetName:       "subnet1",
+				PrivateIPConfigs: 2,
+				AddressesFromPools: []corev1.TypedLocalObjectReference{
+					{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "nodes"},
+					{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "nodes"},
+				},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "invalid config with more addresses from pools than privateIPConfigs",
+			networkInterfaces: []NetworkInterface{{
+				SubnetName:       "subnet1",
+				PrivateIPConfigs: 1,
+				AddressesFromPools: []corev1.TypedLocalObjectReference{
+					{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "nodes"},
+					{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "nodes"},
+				},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "invalid config with an address pool without API group",
+			networkInterfaces: []NetworkInterface{{
+				SubnetName:         "subnet1",
+				PrivateIPConfigs:   1,
+				AddressesFromPools: []corev1.TypedLocalObjectReference{{Kind: "InClusterIPPool", Name: "nodes"}},
+			}},
+			wantErr: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -930,6 +1228,519 @@ func TestAzureMachine_ValidateNetwork(t *testing.T) {
 	}
 }
 
+func TestAzureMachine_ValidateAdditionalCloudInit(t *testing.T) {
+	tests := []struct {
+		name      string
+		cloudInit *CloudInit
+		wantErr   bool
+	}{
+		{
+			name: "nil cloud-init",
+		},
+		{
+			name: "valid cloud-init",
+			cloudInit: &CloudInit{
+				WriteFiles: []CloudInitFile{{Path: "/etc/sysctl.d/90-custom.conf", Content: "vm.max_map_count = 262144"}},
+				RunCmd:     []string{"sysctl --system"},
+			},
+		},
+		{
+			name: "relative file path",
+			cloudInit: &CloudInit{
+				WriteFiles: []CloudInitFile{{Path: "etc/sysctl.d/90-custom.conf"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate file path",
+			cloudInit: &CloudInit{
+				WriteFiles: []CloudInitFile{{Path: "/etc/foo"}, {Path: "/etc/foo"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty command",
+			cloudInit: &CloudInit{
+				RunCmd: []string{" "},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateAdditionalCloudInit(tc.cloudInit, field.NewPath("additionalCloudInit"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateNodeLabelTagMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping *NodeLabelTagMapping
+		wantErr bool
+	}{
+		{
+			name: "nil mapping",
+		},
+		{
+			name: "valid mapping",
+			mapping: &NodeLabelTagMapping{
+				LabelsToTags: []string{"topology.kubernetes.io/zone", "team"},
+				TagPrefix:    "k8s-label-",
+			},
+		},
+		{
+			name:    "invalid label",
+			mapping: &NodeLabelTagMapping{LabelsToTags: []string{"not a label"}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate label",
+			mapping: &NodeLabelTagMapping{LabelsToTags: []string{"team", "team"}},
+			wantErr: true,
+		},
+		{
+			name: "tag prefix matching a mirrored label",
+			mapping: &NodeLabelTagMapping{
+				LabelsToTags: []string{"k8s-label-team"},
+				TagPrefix:    "k8s-label-",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateNodeLabelTagMapping(tc.mapping, field.NewPath("nodeLabelTagMapping"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateBackendPoolIDs(t *testing.T) {
+	const lbPool = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb/backendAddressPools/my-pool"
+	tests := []struct {
+		name    string
+		ids     []string
+		pattern *regexp.Regexp
+		wantErr bool
+	}{
+		{
+			name:    "no pools",
+			pattern: loadBalancerBackendPoolID,
+		},
+		{
+			name:    "valid load balancer pool",
+			ids:     []string{lbPool},
+			pattern: loadBalancerBackendPoolID,
+		},
+		{
+			name:    "valid application gateway pool",
+			ids:     []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationGateways/my-agw/backendAddressPools/my-pool"},
+			pattern: applicationGatewayBackendPoolID,
+		},
+		{
+			name:    "application gateway pool as load balancer pool",
+			ids:     []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationGateways/my-agw/backendAddressPools/my-pool"},
+			pattern: loadBalancerBackendPoolID,
+			wantErr: true,
+		},
+		{
+			name:    "load balancer ID",
+			ids:     []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb"},
+			pattern: loadBalancerBackendPoolID,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate pool",
+			ids:     []string{lbPool, strings.ToUpper(lbPool)},
+			pattern: loadBalancerBackendPoolID,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateBackendPoolIDs(tc.ids, tc.pattern, field.NewPath("loadBalancerBackendPoolIDs"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateWriteAccelerator(t *testing.T) {
+	premiumDisk := &ManagedDiskParameters{StorageAccountType: "Premium_LRS"}
+	tests := []struct {
+		name      string
+		vmSize    string
+		osDisk    OSDisk
+		dataDisks []DataDisk
+		wantErr   bool
+	}{
+		{
+			name:   "write accelerator disabled",
+			vmSize: "Standard_D2s_v3",
+			osDisk: OSDisk{CachingType: "ReadWrite"},
+		},
+		{
+			name:      "write accelerator on a premium data disk of an M-series VM",
+			vmSize:    "Standard_M8ms",
+			osDisk:    OSDisk{CachingType: "ReadWrite"},
+			dataDisks: []DataDisk{{CachingType: "None", ManagedDisk: premiumDisk, WriteAcceleratorEnabled: ptr.To(true)}},
+		},
+		{
+			name:   "write accelerator on a premium OS disk with read only caching",
+			vmSize: "Standard_M208ms_v2",
+			osDisk: OSDisk{CachingType: "ReadOnly", ManagedDisk: premiumDisk, WriteAcceleratorEnabled: ptr.To(true)},
+		},
+		{
+			name:      "write accelerator on a VM size that is not M-series",
+			vmSize:    "Standard_D2s_v3",
+			dataDisks: []DataDisk{{CachingType: "None", ManagedDisk: premiumDisk, WriteAcceleratorEnabled: ptr.To(true)}},
+			wantErr:   true,
+		},
+		{
+			name:      "write accelerator on a standard disk",
+			vmSize:    "Standard_M8ms",
+			dataDisks: []DataDisk{{CachingType: "None", ManagedDisk: &ManagedDiskParameters{StorageAccountType: "StandardSSD_LRS"}, WriteAcceleratorEnabled: ptr.To(true)}},
+			wantErr:   true,
+		},
+		{
+			name:      "write accelerator with read write caching",
+			vmSize:    "Standard_M8ms",
+			dataDisks: []DataDisk{{CachingType: "ReadWrite", ManagedDisk: premiumDisk, WriteAcceleratorEnabled: ptr.To(true)}},
+			wantErr:   true,
+		},
+		{
+			name:   "write accelerator on an ephemeral OS disk",
+			vmSize: "Standard_M8ms",
+			osDisk: OSDisk{
+				CachingType:             "ReadOnly",
+				ManagedDisk:             premiumDisk,
+				DiffDiskSettings:        &DiffDiskSettings{Option: "Local"},
+				WriteAcceleratorEnabled: ptr.To(true),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateWriteAccelerator(tc.vmSize, tc.osDisk, tc.dataDisks, field.NewPath("osDisk"), field.NewPath("dataDisks"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateEtcdDataDisk(t *testing.T) {
+	tests := []struct {
+		name      string
+		osType    string
+		dataDisks []DataDisk
+		wantErr   bool
+	}{
+		{
+			name:      "no etcd data disk",
+			osType:    "Linux",
+			dataDisks: []DataDisk{{NameSuffix: "data"}},
+		},
+		{
+			name:      "one etcd data disk",
+			osType:    "Linux",
+			dataDisks: []DataDisk{{NameSuffix: "data"}, {NameSuffix: "etcddisk", Etcd: ptr.To(true)}},
+		},
+		{
+			name:      "several etcd data disks",
+			osType:    "Linux",
+			dataDisks: []DataDisk{{NameSuffix: "etcddisk", Etcd: ptr.To(true)}, {NameSuffix: "etcddisk2", Etcd: ptr.To(true)}},
+			wantErr:   true,
+		},
+		{
+			name:      "etcd data disk on a windows machine",
+			osType:    "Windows",
+			dataDisks: []DataDisk{{NameSuffix: "etcddisk", Etcd: ptr.To(true)}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateEtcdDataDisk(tc.osType, tc.dataDisks, field.NewPath("dataDisks"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateAzureDiskEncryption(t *testing.T) {
+	validADE := func() *AzureDiskEncryption {
+		return &AzureDiskEncryption{
+			KeyVaultID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+			KeyVaultURL:         "https://my-vault.vault.azure.net/",
+			KeyEncryptionKeyURL: "https://my-vault.vault.azure.net/keys/my-key/0123456789abcdef0123456789abcdef",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ade     *AzureDiskEncryption
+		osDisk  OSDisk
+		profile *SecurityProfile
+		wantErr bool
+	}{
+		{
+			name: "no azure disk encryption",
+			osDisk: OSDisk{
+				DiffDiskSettings: &DiffDiskSettings{Option: "Local"},
+			},
+		},
+		{
+			name: "valid azure disk encryption",
+			ade:  validADE(),
+		},
+		{
+			name: "key encryption key in another key vault",
+			ade: func() *AzureDiskEncryption {
+				ade := validADE()
+				ade.KeyEncryptionKeyVaultID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-kek-vault"
+				ade.KeyEncryptionKeyURL = "https://my-kek-vault.vault.azure.net/keys/my-key/0123456789abcdef0123456789abcdef"
+				return ade
+			}(),
+		},
+		{
+			name: "invalid key vault ID",
+			ade: func() *AzureDiskEncryption {
+				ade := validADE()
+				ade.KeyVaultID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk"
+				return ade
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "key vault URL is not https",
+			ade: func() *AzureDiskEncryption {
+				ade := validADE()
+				ade.KeyVaultURL = "http://my-vault.vault.azure.net/"
+				return ade
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "key encryption key URL is a secret URL",
+			ade: func() *AzureDiskEncryption {
+				ade := validADE()
+				ade.KeyEncryptionKeyURL = "https://my-vault.vault.azure.net/secrets/my-secret"
+				return ade
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "ephemeral OS disk",
+			ade:  validADE(),
+			osDisk: OSDisk{
+				DiffDiskSettings: &DiffDiskSettings{Option: "Local"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "encryption at host",
+			ade:     validADE(),
+			profile: &SecurityProfile{EncryptionAtHost: ptr.To(true)},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateAzureDiskEncryption(tc.ade, tc.osDisk, tc.profile, field.NewPath("azureDiskEncryption"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateVMPowerStateAnnotation(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		capabilities *AdditionalCapabilities
+		wantErr      bool
+	}{
+		{
+			name:        "valid when the annotation is not set",
+			annotations: map[string]string{"foo": "bar"},
+			wantErr:     false,
+		},
+		{
+			name:        "valid when requesting a deallocated VM",
+			annotations: map[string]string{VMPowerStateAnnotation: "Deallocated"},
+			wantErr:     false,
+		},
+		{
+			name:        "valid when requesting a running VM",
+			annotations: map[string]string{VMPowerStateAnnotation: "Running"},
+			wantErr:     false,
+		},
+		{
+			name:         "valid when requesting a hibernated VM with hibernation enabled",
+			annotations:  map[string]string{VMPowerStateAnnotation: "Hibernated"},
+			capabilities: &AdditionalCapabilities{HibernationEnabled: ptr.To(true)},
+			wantErr:      false,
+		},
+		{
+			name:        "invalid when requesting a hibernated VM without hibernation enabled",
+			annotations: map[string]string{VMPowerStateAnnotation: "Hibernated"},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid when requesting another power state",
+			annotations: map[string]string{VMPowerStateAnnotation: "Stopped"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateVMPowerStateAnnotation(tc.annotations, tc.capabilities, field.NewPath("metadata", "annotations"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateCaptureImageAnnotation(t *testing.T) {
+	const versionID = "/subscriptions/123/resourceGroups/images-rg/providers/Microsoft.Compute/galleries/golden/images/node/versions/1.2.3"
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		osType      string
+		wantErr     bool
+	}{
+		{
+			name:        "valid when the annotation is not set",
+			annotations: map[string]string{"foo": "bar"},
+			osType:      LinuxOS,
+			wantErr:     false,
+		},
+		{
+			name:        "valid when capturing a Linux VM into an image version",
+			annotations: map[string]string{CaptureImageAnnotation: versionID},
+			osType:      LinuxOS,
+			wantErr:     false,
+		},
+		{
+			name:        "invalid when the image version ID is not a gallery image version",
+			annotations: map[string]string{CaptureImageAnnotation: "/subscriptions/123/resourceGroups/images-rg/providers/Microsoft.Compute/images/node"},
+			osType:      LinuxOS,
+			wantErr:     true,
+		},
+		{
+			name:        "invalid when the image version is not Major.Minor.Patch",
+			annotations: map[string]string{CaptureImageAnnotation: "/subscriptions/123/resourceGroups/images-rg/providers/Microsoft.Compute/galleries/golden/images/node/versions/latest"},
+			osType:      LinuxOS,
+			wantErr:     true,
+		},
+		{
+			name:        "invalid when capturing a Windows VM",
+			annotations: map[string]string{CaptureImageAnnotation: versionID},
+			osType:      WindowsOS,
+			wantErr:     true,
+		},
+		{
+			name:        "invalid when a power state is requested at the same time",
+			annotations: map[string]string{CaptureImageAnnotation: versionID, VMPowerStateAnnotation: "Running"},
+			osType:      LinuxOS,
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateCaptureImageAnnotation(tc.annotations, tc.osType, field.NewPath("metadata", "annotations"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateWindowsFeatureGate(t *testing.T) {
+	tests := []struct {
+		name    string
+		osType  string
+		enabled bool
+		wantErr bool
+	}{
+		{
+			name:    "valid Linux machine with the feature gate disabled",
+			osType:  LinuxOS,
+			enabled: false,
+			wantErr: false,
+		},
+		{
+			name:    "valid Windows machine with the feature gate enabled",
+			osType:  WindowsOS,
+			enabled: true,
+			wantErr: false,
+		},
+		{
+			name:    "invalid Windows machine with the feature gate disabled",
+			osType:  WindowsOS,
+			enabled: false,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.Windows, tc.enabled)()
+			g := NewWithT(t)
+			err := ValidateWindowsFeatureGate(tc.osType, field.NewPath("spec", "osDisk", "osType"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestAzureMachine_ValidateConfidentialCompute(t *testing.T) {
 	tests := []struct {
 		name            string
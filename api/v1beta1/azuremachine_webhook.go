@@ -18,8 +18,10 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 
+	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -29,22 +31,36 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-// SetupAzureMachineWebhookWithManager sets up and registers the webhook with the manager.
-func SetupAzureMachineWebhookWithManager(mgr ctrl.Manager) error {
-	mw := &azureMachineWebhook{Client: mgr.GetClient()}
+// SetupAzureMachineWebhookWithManager sets up and registers the webhook with the manager. The VM sizes of the machines
+// are only checked against the VM sizes available in the locations of their clusters when checkLocations is not nil.
+// The defaulting webhook warns about the deprecated fields of the machines before moving them to their replacements.
+func SetupAzureMachineWebhookWithManager(mgr ctrl.Manager, checkLocations LocationChecker) error {
+	mw := &azureMachineWebhook{Client: mgr.GetClient(), checkLocations: checkLocations}
+	if err := webhookutils.RegisterDefaulterWithWarnings(mgr, &AzureMachine{}, mw, azureMachineDeprecationWarnings); err != nil {
+		return err
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&AzureMachine{}).
-		WithDefaulter(mw).
 		WithValidator(mw).
 		Complete()
 }
 
+// azureMachineDeprecationWarnings returns the admission warnings for the deprecated fields set in an AzureMachine.
+func azureMachineDeprecationWarnings(obj runtime.Object) admission.Warnings {
+	m, ok := obj.(*AzureMachine)
+	if !ok {
+		return nil
+	}
+	return AzureMachineSpecDeprecationWarnings(m.Spec, field.NewPath("spec"))
+}
+
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachine,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,versions=v1beta1,name=validation.azuremachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachine,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,versions=v1beta1,name=default.azuremachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 // azureMachineWebhook implements a validating and defaulting webhook for AzureMachines.
 type azureMachineWebhook struct {
-	Client client.Client
+	Client         client.Client
+	checkLocations LocationChecker
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
@@ -66,11 +82,70 @@ func (mw *azureMachineWebhook) ValidateCreate(ctx context.Context, obj runtime.O
 		allErrs = append(allErrs, errs...)
 	}
 
-	if len(allErrs) == 0 {
-		return nil, nil
+	allErrs = append(allErrs, ValidateVMPowerStateAnnotation(m.Annotations, m.Spec.AdditionalCapabilities, field.NewPath("metadata", "annotations"))...)
+	allErrs = append(allErrs, ValidateCaptureImageAnnotation(m.Annotations, m.Spec.OSDisk.OSType, field.NewPath("metadata", "annotations"))...)
+	allErrs = append(allErrs, ValidateWindowsFeatureGate(m.Spec.OSDisk.OSType, field.NewPath("spec", "osDisk", "osType"))...)
+
+	if len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("AzureMachine").GroupKind(), m.Name, allErrs)
 	}
 
-	return nil, apierrors.NewInvalid(GroupVersion.WithKind("AzureMachine").GroupKind(), m.Name, allErrs)
+	if err := mw.validatePublicIP(ctx, m); err != nil {
+		return nil, err
+	}
+
+	return mw.validateVMSize(ctx, m)
+}
+
+// validatePublicIP checks that a machine allocating a public IP does not belong to a cluster without public network
+// access. Unlike the VM size, it is not skipped when the cluster of the machine cannot be read.
+func (mw *azureMachineWebhook) validatePublicIP(ctx context.Context, m *AzureMachine) error {
+	if !m.Spec.AllocatePublicIP {
+		return nil
+	}
+	cluster, err := GetOwnerAzureCluster(ctx, mw.Client, m)
+	if err != nil {
+		return apierrors.NewInternalError(errors.Wrap(err, "failed to get the AzureCluster of the machine to check its public network access"))
+	}
+	if cluster == nil || cluster.Spec.PublicNetworkAccess != PublicNetworkAccessDisabled {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("AzureMachine").GroupKind(), m.Name, field.ErrorList{
+		field.Forbidden(field.NewPath("spec", "allocatePublicIP"),
+			fmt.Sprintf("machines cannot allocate a public IP when the publicNetworkAccess of AzureCluster %s is %s", cluster.Name, PublicNetworkAccessDisabled)),
+	})
+}
+
+// validateVMSize checks that the VM size of a machine is available in the location of its cluster and supports the
+// features of the machine, when enabled. The VM size is not checked when the machine has no cluster name label yet or
+// its cluster is not an AzureCluster.
+func (mw *azureMachineWebhook) validateVMSize(ctx context.Context, m *AzureMachine) (admission.Warnings, error) {
+	if mw.checkLocations == nil {
+		return nil, nil
+	}
+	cluster, err := GetOwnerAzureCluster(ctx, mw.Client, m)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("failed to get the AzureCluster of the machine, VM size %s was not validated: %v", m.Spec.VMSize, err)}, nil
+	}
+	if cluster == nil {
+		return nil, nil
+	}
+	warnings, fieldErr := ValidateVMSizeAvailability(ctx, mw.checkLocations, cluster, cluster.Spec.Location, m.Spec.VMSize, field.NewPath("spec", "vmSize"))
+	if fieldErr != nil {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind("AzureMachine").GroupKind(), m.Name, field.ErrorList{fieldErr})
+	}
+	features := VMSizeFeatures{
+		OSDisk:            m.Spec.OSDisk,
+		DataDisks:         m.Spec.DataDisks,
+		SecurityProfile:   m.Spec.SecurityProfile,
+		NetworkInterfaces: m.Spec.NetworkInterfaces,
+	}
+	capabilityWarnings, errs := ValidateVMSizeCapabilities(ctx, mw.checkLocations, cluster, cluster.Spec.Location, m.Spec.VMSize, features, field.NewPath("spec"))
+	warnings = append(warnings, capabilityWarnings...)
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind("AzureMachine").GroupKind(), m.Name, errs)
+	}
+	return warnings, nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -127,17 +202,50 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 		allErrs = append(allErrs, err)
 	}
 
+	// The performance of Premium SSD v2 and Ultra data disks can be changed on running machines.
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "DataDisks"),
-		old.Spec.DataDisks,
-		m.Spec.DataDisks); err != nil {
+		withoutDiskPerformance(old.Spec.DataDisks),
+		withoutDiskPerformance(m.Spec.DataDisks)); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	// The SSH public keys of Linux machines opted into SSH key rotation are replaced on their VM.
+	if m.Annotations[SSHKeyRotationAnnotation] == "true" && m.Spec.OSDisk.OSType != WindowsOS {
+		if old.Spec.SSHPublicKey != m.Spec.SSHPublicKey {
+			allErrs = append(allErrs, ValidateSSHKey(m.Spec.SSHPublicKey, field.NewPath("Spec", "SSHPublicKey"))...)
+		}
+		if !reflect.DeepEqual(old.Spec.AdditionalSSHPublicKeys, m.Spec.AdditionalSSHPublicKeys) {
+			for i, key := range m.Spec.AdditionalSSHPublicKeys {
+				allErrs = append(allErrs, ValidateSSHKey(key, field.NewPath("Spec", "AdditionalSSHPublicKeys").Index(i))...)
+			}
+		}
+	} else {
+		if err := webhookutils.ValidateImmutable(
+			field.NewPath("Spec", "SSHPublicKey"),
+			old.Spec.SSHPublicKey,
+			m.Spec.SSHPublicKey); err != nil {
+			allErrs = append(allErrs, err)
+		}
+		if err := webhookutils.ValidateImmutable(
+			field.NewPath("Spec", "AdditionalSSHPublicKeys"),
+			old.Spec.AdditionalSSHPublicKeys,
+			m.Spec.AdditionalSSHPublicKeys); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "AdminUsername"),
+		old.Spec.AdminUsername,
+		m.Spec.AdminUsername); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
 	if err := webhookutils.ValidateImmutable(
-		field.NewPath("Spec", "SSHPublicKey"),
-		old.Spec.SSHPublicKey,
-		m.Spec.SSHPublicKey); err != nil {
+		field.NewPath("Spec", "StoreAdminPassword"),
+		old.Spec.StoreAdminPassword,
+		m.Spec.StoreAdminPassword); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
@@ -165,6 +273,20 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "AdditionalCloudInit"),
+		old.Spec.AdditionalCloudInit,
+		m.Spec.AdditionalCloudInit); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "SecondaryUserData"),
+		old.Spec.SecondaryUserData,
+		m.Spec.SecondaryUserData); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "SpotVMOptions"),
 		old.Spec.SpotVMOptions,
@@ -179,6 +301,27 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "AzureDiskEncryption"),
+		old.Spec.AzureDiskEncryption,
+		m.Spec.AzureDiskEncryption); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "LoadBalancerBackendPoolIDs"),
+		old.Spec.LoadBalancerBackendPoolIDs,
+		m.Spec.LoadBalancerBackendPoolIDs); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "ApplicationGatewayBackendPoolIDs"),
+		old.Spec.ApplicationGatewayBackendPoolIDs,
+		m.Spec.ApplicationGatewayBackendPoolIDs); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if old.Spec.Diagnostics != nil {
 		if err := webhookutils.ValidateImmutable(
 			field.NewPath("Spec", "Diagnostics"),
@@ -188,6 +331,14 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 		}
 	}
 
+	allErrs = append(allErrs, ValidateVMPowerStateAnnotation(m.Annotations, m.Spec.AdditionalCapabilities, field.NewPath("metadata", "annotations"))...)
+	allErrs = append(allErrs, ValidateCaptureImageAnnotation(m.Annotations, m.Spec.OSDisk.OSType, field.NewPath("metadata", "annotations"))...)
+
+	// Spec.VMSize can be changed to resize the VM in place, as long as the new size supports the disk settings.
+	if m.Spec.VMSize != old.Spec.VMSize {
+		allErrs = append(allErrs, ValidateWriteAccelerator(m.Spec.VMSize, m.Spec.OSDisk, m.Spec.DataDisks, field.NewPath("spec", "osDisk"), field.NewPath("spec", "dataDisks"))...)
+	}
+
 	if !reflect.DeepEqual(m.Spec.NetworkInterfaces, old.Spec.NetworkInterfaces) {
 		// The defaulting webhook may have migrated values from the old SubnetName field to the new NetworkInterfaces format.
 		old.Spec.SetNetworkInterfacesDefaults()
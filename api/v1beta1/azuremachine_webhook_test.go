@@ -26,9 +26,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var (
@@ -62,6 +64,16 @@ func TestAzureMachine_ValidateCreate(t *testing.T) {
 			machine: createMachineWithSharedImage("", "RG124", "NAME124", "GALLERY1", "2.0.0"),
 			wantErr: true,
 		},
+		{
+			name:    "azuremachine with compute gallery image - full",
+			machine: createMachineWithComputeGalleryImage("1.2.3"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachine with compute gallery image - latest in major.minor",
+			machine: createMachineWithComputeGalleryImage("1.2.latest"),
+			wantErr: true,
+		},
 		{
 			name:    "azuremachine with image by - with id",
 			machine: createMachineWithImageByID("ID123"),
@@ -110,6 +122,26 @@ func TestAzureMachine_ValidateCreate(t *testing.T) {
 			machine: createMachineWithOsDiskCacheType("invalid_cache_type"),
 			wantErr: true,
 		},
+		{
+			name:    "azuremachine storing the admin password of a Windows machine",
+			machine: createMachineWithStoreAdminPassword(WindowsOS),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachine storing the admin password of a Linux machine",
+			machine: createMachineWithStoreAdminPassword(LinuxOS),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachine with zone fallback",
+			machine: createMachineWithZoneFallback(false),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachine with zone fallback and a public IP",
+			machine: createMachineWithZoneFallback(true),
+			wantErr: true,
+		},
 		{
 			name:    "azuremachinepool with managed diagnostics profile",
 			machine: createMachineWithDiagnostics(ManagedDiagnosticsStorage, nil),
@@ -237,6 +269,42 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 		newMachine *AzureMachine
 		wantErr    bool
 	}{
+		{
+			name: "validTest: azuremachine.spec.vmSize is mutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_D2s_v3",
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_D4s_v3",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.vmSize must support write accelerator",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_M8ms",
+					OSDisk: OSDisk{
+						ManagedDisk:             &ManagedDiskParameters{StorageAccountType: "Premium_LRS"},
+						WriteAcceleratorEnabled: ptr.To(true),
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_D4s_v3",
+					OSDisk: OSDisk{
+						ManagedDisk:             &ManagedDiskParameters{StorageAccountType: "Premium_LRS"},
+						WriteAcceleratorEnabled: ptr.To(true),
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.image is immutable",
 			oldMachine: &AzureMachine{
@@ -351,6 +419,46 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalidTest: azuremachine.spec.SecondaryUserData is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					SecondaryUserData: &SecondaryUserDataSource{SecretName: "user-data", Key: "value"},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					SecondaryUserData: &SecondaryUserDataSource{SecretName: "other-user-data", Key: "value"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.LoadBalancerBackendPoolIDs is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					LoadBalancerBackendPoolIDs: []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb/backendAddressPools/pool-1"},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					LoadBalancerBackendPoolIDs: []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb/backendAddressPools/pool-2"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.ApplicationGatewayBackendPoolIDs is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					ApplicationGatewayBackendPoolIDs: []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationGateways/my-agw/backendAddressPools/pool"},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "validTest: azuremachine.spec.RoleAssignmentName is immutable",
 			oldMachine: &AzureMachine{
@@ -489,6 +597,39 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "validTest: azuremachine.spec.DataDisks performance is mutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{
+							DiskSizeGB: 128,
+							ManagedDisk: &ManagedDiskParameters{
+								StorageAccountType: "PremiumV2_LRS",
+								DiskIOPSReadWrite:  ptr.To[int64](3000),
+							},
+							CachingType: "None",
+						},
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{
+							DiskSizeGB: 128,
+							ManagedDisk: &ManagedDiskParameters{
+								StorageAccountType: "PremiumV2_LRS",
+								DiskIOPSReadWrite:  ptr.To[int64](5000),
+								DiskMBpsReadWrite:  ptr.To[int64](200),
+							},
+							CachingType: "None",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.SSHPublicKey is immutable",
 			oldMachine: &AzureMachine{
@@ -503,6 +644,107 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "validTest: azuremachine.spec.SSHPublicKey is mutable with SSH key rotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					SSHPublicKey: generateSSHPublicKey(true),
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{SSHKeyRotationAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					SSHPublicKey: validSSHPublicKey,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.SSHPublicKey must be valid with SSH key rotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					SSHPublicKey: validSSHPublicKey,
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{SSHKeyRotationAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					SSHPublicKey: "invalidKey",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.SSHPublicKey is immutable on Windows machines",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					SSHPublicKey: generateSSHPublicKey(true),
+					OSDisk:       OSDisk{OSType: WindowsOS},
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{SSHKeyRotationAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					SSHPublicKey: validSSHPublicKey,
+					OSDisk:       OSDisk{OSType: WindowsOS},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.AdditionalSSHPublicKeys is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AdditionalSSHPublicKeys: []string{validSSHPublicKey},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AdditionalSSHPublicKeys: []string{validSSHPublicKey, generateSSHPublicKey(true)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.AdditionalSSHPublicKeys is mutable with SSH key rotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AdditionalSSHPublicKeys: []string{validSSHPublicKey},
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{SSHKeyRotationAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					AdditionalSSHPublicKeys: []string{validSSHPublicKey, generateSSHPublicKey(true)},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.AdditionalSSHPublicKeys must be valid with SSH key rotation",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AdditionalSSHPublicKeys: []string{validSSHPublicKey},
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{SSHKeyRotationAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					AdditionalSSHPublicKeys: []string{"invalidKey"},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "validTest: azuremachine.spec.SSHPublicKey is immutable",
 			oldMachine: &AzureMachine{
@@ -693,6 +935,22 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalidTest: azuremachine.spec.AzureDiskEncryption is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AzureDiskEncryption: &AzureDiskEncryption{
+						KeyVaultID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+						KeyVaultURL:         "https://my-vault.vault.azure.net/",
+						KeyEncryptionKeyURL: "https://my-vault.vault.azure.net/keys/my-key/0123456789abcdef0123456789abcdef",
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.Diagnostics is immutable",
 			oldMachine: &AzureMachine{
@@ -884,6 +1142,162 @@ func TestAzureMachine_Default(t *testing.T) {
 	}
 }
 
+func TestAzureMachineWebhook_ValidateCreateVMSize(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{Kind: "AzureCluster", Name: "test-cluster"},
+			},
+		},
+		&AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec:       AzureClusterSpec{AzureClusterClassSpec: AzureClusterClassSpec{Location: "eastus"}},
+		},
+	).Build()
+	checker := fakeLocationChecker{
+		vmSizes:      map[string][]string{"eastus": {"Standard_D2s_v3"}},
+		capabilities: map[string]*VMSizeCapabilities{"Standard_D2s_v3": {}},
+	}
+
+	tests := []struct {
+		name                  string
+		vmSize                string
+		clusterName           string
+		acceleratedNetworking bool
+		checkLocations        LocationChecker
+		wantErr               string
+	}{
+		{
+			name:           "VM size available in the location of the cluster",
+			vmSize:         "Standard_D2s_v3",
+			clusterName:    "test-cluster",
+			checkLocations: checker,
+		},
+		{
+			name:           "VM size not available in the location of the cluster",
+			vmSize:         "Standard_D4s_v3",
+			clusterName:    "test-cluster",
+			checkLocations: checker,
+			wantErr:        "VM size is not available in location eastus, the sizes of the same series available there are: Standard_D2s_v3",
+		},
+		{
+			name:                  "feature not supported by the VM size",
+			vmSize:                "Standard_D2s_v3",
+			clusterName:           "test-cluster",
+			acceleratedNetworking: true,
+			checkLocations:        checker,
+			wantErr:               "spec.networkInterfaces[0].acceleratedNetworking: Invalid value: true: VM size Standard_D2s_v3 does not support accelerated networking",
+		},
+		{
+			name:        "VM sizes not checked",
+			vmSize:      "Standard_D4s_v3",
+			clusterName: "test-cluster",
+		},
+		{
+			name:           "machine without a cluster name label",
+			vmSize:         "Standard_D4s_v3",
+			checkLocations: checker,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mw := &azureMachineWebhook{Client: cli, checkLocations: tc.checkLocations}
+			machine := createMachineWithSSHPublicKey(validSSHPublicKey)
+			machine.Namespace = "default"
+			machine.Spec.VMSize = tc.vmSize
+			if tc.acceleratedNetworking {
+				machine.Spec.NetworkInterfaces = []NetworkInterface{{SubnetName: "subnet", PrivateIPConfigs: 1, AcceleratedNetworking: ptr.To(true)}}
+			}
+			machine.Labels = nil
+			if tc.clusterName != "" {
+				machine.Labels = map[string]string{clusterv1.ClusterNameLabel: tc.clusterName}
+			}
+			_, err := mw.ValidateCreate(context.Background(), machine)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.wantErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachineWebhook_ValidateCreatePublicIP(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+	newCluster := func(name string, access PublicNetworkAccess) []client.Object {
+		return []client.Object{
+			&clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{Kind: "AzureCluster", Name: name},
+				},
+			},
+			&AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec:       AzureClusterSpec{PublicNetworkAccess: access},
+			},
+		}
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(newCluster("public-cluster", "")...).
+		WithObjects(newCluster("private-cluster", PublicNetworkAccessDisabled)...).
+		Build()
+
+	tests := []struct {
+		name             string
+		clusterName      string
+		allocatePublicIP bool
+		wantErr          string
+	}{
+		{
+			name:             "public IP in a cluster with public network access",
+			clusterName:      "public-cluster",
+			allocatePublicIP: true,
+		},
+		{
+			name:             "public IP in a cluster without public network access",
+			clusterName:      "private-cluster",
+			allocatePublicIP: true,
+			wantErr:          "machines cannot allocate a public IP when the publicNetworkAccess of AzureCluster private-cluster is Disabled",
+		},
+		{
+			name:        "no public IP in a cluster without public network access",
+			clusterName: "private-cluster",
+		},
+		{
+			name:             "public IP in a cluster that does not exist",
+			clusterName:      "missing-cluster",
+			allocatePublicIP: true,
+			wantErr:          "failed to get the AzureCluster of the machine to check its public network access",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mw := &azureMachineWebhook{Client: cli}
+			machine := createMachineWithSSHPublicKey(validSSHPublicKey)
+			machine.Namespace = "default"
+			machine.Labels = map[string]string{clusterv1.ClusterNameLabel: tc.clusterName}
+			machine.Spec.AllocatePublicIP = tc.allocatePublicIP
+			_, err := mw.ValidateCreate(context.Background(), machine)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.wantErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func createMachineWithNetworkConfig(subnetName string, acceleratedNetworking *bool, interfaces []NetworkInterface) *AzureMachine {
 	return &AzureMachine{
 		Spec: AzureMachineSpec{
@@ -937,6 +1351,24 @@ func createMachineWithMarketPlaceImage(publisher, offer, sku, version string) *A
 	}
 }
 
+func createMachineWithComputeGalleryImage(version string) *AzureMachine {
+	image := &Image{
+		ComputeGallery: &AzureComputeGalleryImage{
+			Gallery: "GALLERY1",
+			Name:    "NAME123",
+			Version: version,
+		},
+	}
+
+	return &AzureMachine{
+		Spec: AzureMachineSpec{
+			Image:        image,
+			SSHPublicKey: validSSHPublicKey,
+			OSDisk:       validOSDisk,
+		},
+	}
+}
+
 func createMachineWithImageByID(imageID string) *AzureMachine {
 	image := &Image{
 		ID: &imageID,
@@ -962,6 +1394,29 @@ func createMachineWithOsDiskCacheType(cacheType string) *AzureMachine {
 	return machine
 }
 
+func createMachineWithStoreAdminPassword(osType string) *AzureMachine {
+	machine := &AzureMachine{
+		Spec: AzureMachineSpec{
+			SSHPublicKey:       validSSHPublicKey,
+			OSDisk:             validOSDisk,
+			StoreAdminPassword: true,
+		},
+	}
+	machine.Spec.OSDisk.OSType = osType
+	return machine
+}
+
+func createMachineWithZoneFallback(allocatePublicIP bool) *AzureMachine {
+	return &AzureMachine{
+		Spec: AzureMachineSpec{
+			SSHPublicKey:      validSSHPublicKey,
+			OSDisk:            validOSDisk,
+			AllowZoneFallback: true,
+			AllocatePublicIP:  allocatePublicIP,
+		},
+	}
+}
+
 func createMachineWithSystemAssignedIdentityRoleName() *AzureMachine {
 	machine := &AzureMachine{
 		Spec: AzureMachineSpec{
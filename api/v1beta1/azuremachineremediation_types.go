@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemediationStrategyType is the action taken on the VM of an unhealthy machine.
+type RemediationStrategyType string
+
+const (
+	// RemediationStrategyRestart restarts the VM.
+	RemediationStrategyRestart RemediationStrategyType = "Restart"
+	// RemediationStrategyRedeploy redeploys the VM to a new Azure host, which also restarts it.
+	RemediationStrategyRedeploy RemediationStrategyType = "Redeploy"
+)
+
+// RemediationPhase is the phase of the remediation of a machine.
+type RemediationPhase string
+
+const (
+	// RemediationPhaseRunning means the VM of the machine is being restarted or redeployed.
+	RemediationPhaseRunning RemediationPhase = "Running"
+	// RemediationPhaseWaiting means the node of the machine is given time to become healthy again.
+	RemediationPhaseWaiting RemediationPhase = "Waiting"
+	// RemediationPhaseDeleting means the remediation gave up and the machine is left to be deleted by its owner.
+	RemediationPhaseDeleting RemediationPhase = "Deleting"
+)
+
+// RemediationStrategy describes how the VM of an unhealthy machine is remediated.
+type RemediationStrategy struct {
+	// Type is the action taken on the VM, either Restart or Redeploy.
+	// +kubebuilder:validation:Enum=Restart;Redeploy
+	// +kubebuilder:default=Restart
+	// +optional
+	Type RemediationStrategyType `json:"type,omitempty"`
+
+	// RetryLimit is the number of times the VM is restarted or redeployed before the machine is deleted.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	RetryLimit int `json:"retryLimit,omitempty"`
+
+	// Timeout is how long the node of the machine is given to become healthy again after each restart or redeployment.
+	// Defaults to 5 minutes.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// AzureMachineRemediationSpec defines the desired state of AzureMachineRemediation.
+type AzureMachineRemediationSpec struct {
+	// Strategy is how the VM of the unhealthy machine is remediated. Defaults to a single restart.
+	// +optional
+	Strategy *RemediationStrategy `json:"strategy,omitempty"`
+}
+
+// AzureMachineRemediationStatus defines the observed state of AzureMachineRemediation.
+type AzureMachineRemediationStatus struct {
+	// Phase is the phase of the remediation, one of Running, Waiting or Deleting.
+	// +optional
+	Phase RemediationPhase `json:"phase,omitempty"`
+
+	// RetryCount is the number of times the VM has been restarted or redeployed.
+	// +optional
+	RetryCount int `json:"retryCount,omitempty"`
+
+	// LastRemediated is the time the VM was last restarted or redeployed.
+	// +optional
+	LastRemediated *metav1.Time `json:"lastRemediated,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase of the remediation"
+// +kubebuilder:printcolumn:name="Retries",type="integer",JSONPath=".status.retryCount",description="Number of restarts or redeployments of the VM"
+// +kubebuilder:printcolumn:name="Last Remediated",type="date",JSONPath=".status.lastRemediated",description="Time of the last restart or redeployment of the VM"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of this AzureMachineRemediation"
+// +kubebuilder:resource:path=azuremachineremediations,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+
+// AzureMachineRemediation is the Schema for the azuremachineremediations API. It is created by a MachineHealthCheck
+// from an AzureMachineRemediationTemplate for an unhealthy machine, with the name of the machine.
+type AzureMachineRemediation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AzureMachineRemediationSpec   `json:"spec,omitempty"`
+	Status AzureMachineRemediationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AzureMachineRemediationList contains a list of AzureMachineRemediations.
+type AzureMachineRemediationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AzureMachineRemediation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AzureMachineRemediation{}, &AzureMachineRemediationList{})
+}
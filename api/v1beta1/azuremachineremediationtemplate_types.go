@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AzureMachineRemediationTemplateSpec defines the desired state of AzureMachineRemediationTemplate.
+type AzureMachineRemediationTemplateSpec struct {
+	Template AzureMachineRemediationTemplateResource `json:"template"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=azuremachineremediationtemplates,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+
+// AzureMachineRemediationTemplate is the Schema for the azuremachineremediationtemplates API. It is referenced by the
+// remediationTemplate of a MachineHealthCheck to remediate unhealthy machines by restarting or redeploying their VM
+// before they are deleted.
+type AzureMachineRemediationTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AzureMachineRemediationTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AzureMachineRemediationTemplateList contains a list of AzureMachineRemediationTemplates.
+type AzureMachineRemediationTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AzureMachineRemediationTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AzureMachineRemediationTemplate{}, &AzureMachineRemediationTemplateList{})
+}
+
+// AzureMachineRemediationTemplateResource describes the data needed to create an AzureMachineRemediation from a
+// template.
+type AzureMachineRemediationTemplateResource struct {
+	// Spec is the specification of the desired behavior of the remediation.
+	Spec AzureMachineRemediationSpec `json:"spec"`
+}
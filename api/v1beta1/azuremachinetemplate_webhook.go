@@ -24,6 +24,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	webhookutils "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
 	"sigs.k8s.io/cluster-api/util/topology"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -39,13 +40,25 @@ const (
 
 // SetupWebhookWithManager sets up and registers the webhook with the manager.
 func (r *AzureMachineTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if err := webhookutils.RegisterDefaulterWithWarnings(mgr, r, r, azureMachineTemplateDeprecationWarnings); err != nil {
+		return err
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		WithValidator(r).
-		WithDefaulter(r).
 		Complete()
 }
 
+// azureMachineTemplateDeprecationWarnings returns the admission warnings for the deprecated fields set in an
+// AzureMachineTemplate.
+func azureMachineTemplateDeprecationWarnings(obj runtime.Object) admission.Warnings {
+	t, ok := obj.(*AzureMachineTemplate)
+	if !ok {
+		return nil
+	}
+	return AzureMachineSpecDeprecationWarnings(t.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))
+}
+
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachinetemplate,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachinetemplates,versions=v1beta1,name=default.azuremachinetemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachinetemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachinetemplates,versions=v1beta1,name=validation.azuremachinetemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
@@ -58,6 +71,7 @@ func (r *AzureMachineTemplate) ValidateCreate(ctx context.Context, obj runtime.O
 	spec := t.Spec.Template.Spec
 
 	allErrs := ValidateAzureMachineSpec(spec)
+	allErrs = append(allErrs, ValidateWindowsFeatureGate(spec.OSDisk.OSType, field.NewPath("AzureMachineTemplate", "spec", "template", "spec", "osDisk", "osType"))...)
 
 	if spec.RoleAssignmentName != "" {
 		allErrs = append(allErrs,
@@ -52,6 +52,12 @@ func (r *AzureManagedCluster) ValidateCreate() (admission.Warnings, error) {
 			"can be set only if the Cluster API 'MachinePool' feature flag is enabled",
 		)
 	}
+	if !feature.Gates.Enabled(feature.AKS) {
+		return nil, field.Forbidden(
+			field.NewPath("spec"),
+			"can be set only if the AKS feature flag is enabled",
+		)
+	}
 	return nil, nil
 }
 
@@ -249,6 +249,14 @@ func TestAzureManagedCluster_ValidateCreateFailure(t *testing.T) {
 	}
 }
 
+func TestAzureManagedCluster_ValidateCreateAKSDisabled(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.AKS, false)()
+	g := NewWithT(t)
+	_, err := getKnownValidAzureManagedCluster().ValidateCreate()
+	g.Expect(err).To(HaveOccurred())
+}
+
 func getKnownValidAzureManagedCluster() *AzureManagedCluster {
 	return &AzureManagedCluster{}
 }
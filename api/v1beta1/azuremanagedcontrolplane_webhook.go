@@ -52,13 +52,26 @@ var (
 // SetupAzureManagedControlPlaneWebhookWithManager sets up and registers the webhook with the manager.
 func SetupAzureManagedControlPlaneWebhookWithManager(mgr ctrl.Manager) error {
 	mw := &azureManagedControlPlaneWebhook{Client: mgr.GetClient()}
+	if err := webhookutils.RegisterDefaulterWithWarnings(mgr, &AzureManagedControlPlane{}, mw, azureManagedControlPlaneDeprecationWarnings); err != nil {
+		return err
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&AzureManagedControlPlane{}).
-		WithDefaulter(mw).
 		WithValidator(mw).
 		Complete()
 }
 
+// azureManagedControlPlaneDeprecationWarnings returns the admission warnings for the deprecated values set in an
+// AzureManagedControlPlane, which the defaulting webhook replaces.
+func azureManagedControlPlaneDeprecationWarnings(obj runtime.Object) admission.Warnings {
+	m, ok := obj.(*AzureManagedControlPlane)
+	if !ok || m.Spec.SKU == nil || m.Spec.SKU.Tier != PaidManagedControlPlaneTier {
+		return nil
+	}
+	return admission.Warnings{fmt.Sprintf("%s %s is deprecated and will be removed in %s, use %s instead",
+		field.NewPath("spec", "sku", "tier"), PaidManagedControlPlaneTier, DeprecatedFieldsRemovalVersion, StandardManagedControlPlaneTier)}
+}
+
 // +kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-azuremanagedcontrolplane,mutating=true,failurePolicy=fail,groups=infrastructure.cluster.x-k8s.io,resources=azuremanagedcontrolplanes,verbs=create;update,versions=v1beta1,name=default.azuremanagedcontrolplanes.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 // azureManagedControlPlaneWebhook implements a validating and defaulting webhook for AzureManagedControlPlane.
@@ -129,6 +142,12 @@ func (mw *azureManagedControlPlaneWebhook) ValidateCreate(ctx context.Context, o
 			"can be set only if the Cluster API 'MachinePool' feature flag is enabled",
 		)
 	}
+	if !feature.Gates.Enabled(feature.AKS) {
+		return nil, field.Forbidden(
+			field.NewPath("spec"),
+			"can be set only if the AKS feature flag is enabled",
+		)
+	}
 
 	return nil, m.Validate(mw.Client)
 }
@@ -106,6 +106,12 @@ func (mw *azureManagedMachinePoolWebhook) ValidateCreate(ctx context.Context, ob
 			"can be set only if the Cluster API 'MachinePool' feature flag is enabled",
 		)
 	}
+	if !feature.Gates.Enabled(feature.AKS) {
+		return nil, field.Forbidden(
+			field.NewPath("spec"),
+			"can be set only if the AKS feature flag is enabled",
+		)
+	}
 	validators := []func() error{
 		m.validateMaxPods,
 		m.validateOSType,
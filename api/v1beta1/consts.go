@@ -42,16 +42,27 @@ const (
 	VMProvisionFailedReason = "VMProvisionFailed"
 	// UserAssignedIdentityMissingReason used for failures when a user-assigned identity is missing.
 	UserAssignedIdentityMissingReason = "UserAssignedIdentityMissing"
+	// MarketplaceTermsNotAcceptedReason used when a VM can't be created because the marketplace terms of the purchase
+	// plan of its image are not accepted on the subscription.
+	MarketplaceTermsNotAcceptedReason = "MarketplaceTermsNotAccepted"
+	// VCPUQuotaExceededReason used when a VM can't be created without exceeding the vCPU quota of the subscription.
+	VCPUQuotaExceededReason = "VCPUQuotaExceeded"
 	// WaitingForClusterInfrastructureReason used when machine is waiting for cluster infrastructure to be ready before proceeding.
 	WaitingForClusterInfrastructureReason = "WaitingForClusterInfrastructure"
 	// WaitingForBootstrapDataReason used when machine is waiting for bootstrap data to be ready before proceeding.
 	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
+	// WaitingForIPAddressReason used when a cluster or machine is waiting for IP address pools to fulfill its IP address
+	// claims before proceeding.
+	WaitingForIPAddressReason = "WaitingForIPAddress"
 	// BootstrapSucceededCondition reports the result of the execution of the bootstrap data on the machine.
 	BootstrapSucceededCondition clusterv1.ConditionType = "BootstrapSucceeded"
 	// BootstrapInProgressReason is used to indicate the bootstrap data has not finished executing.
 	BootstrapInProgressReason = "BootstrapInProgress"
 	// BootstrapFailedReason is used to indicate the bootstrap process ran into an error.
 	BootstrapFailedReason = "BootstrapFailed"
+	// ImageCapturedCondition means the VM has been generalized and captured into the compute gallery image version
+	// requested through the CaptureImageAnnotation.
+	ImageCapturedCondition clusterv1.ConditionType = "ImageCaptured"
 )
 
 // AzureMachinePool Conditions and Reasons.
@@ -102,6 +113,8 @@ const (
 	SecurityGroupsReadyCondition clusterv1.ConditionType = "SecurityGroupsReady"
 	// RouteTablesReadyCondition means the route tables exist and are ready to be used.
 	RouteTablesReadyCondition clusterv1.ConditionType = "RouteTablesReady"
+	// NodeRoutesReadyCondition means the routes to the pod CIDR of the node of a machine exist and are ready to be used.
+	NodeRoutesReadyCondition clusterv1.ConditionType = "NodeRoutesReady"
 	// PublicIPsReadyCondition means the public IPs exist and are ready to be used.
 	PublicIPsReadyCondition clusterv1.ConditionType = "PublicIPsReady"
 	// NATGatewaysReadyCondition means the NAT gateways exist and are ready to be used.
@@ -116,20 +129,66 @@ const (
 	PrivateDNSLinkReadyCondition clusterv1.ConditionType = "PrivateDNSLinkReady"
 	// PrivateDNSRecordReadyCondition means the private DNS records exist and are ready to be used.
 	PrivateDNSRecordReadyCondition clusterv1.ConditionType = "PrivateDNSRecordReady"
+	// DNSRecordsReadyCondition means the public DNS records exist and are ready to be used.
+	DNSRecordsReadyCondition clusterv1.ConditionType = "DNSRecordsReady"
 	// BastionHostReadyCondition means the bastion host exists and is ready to be used.
 	BastionHostReadyCondition clusterv1.ConditionType = "BastionHostReady"
+	// VirtualNetworkGatewayReadyCondition means the virtual network gateway exists and is ready to be used.
+	VirtualNetworkGatewayReadyCondition clusterv1.ConditionType = "VirtualNetworkGatewayReady"
+	// ApplicationGatewayReadyCondition means the application gateway exists and is ready to be used.
+	ApplicationGatewayReadyCondition clusterv1.ConditionType = "ApplicationGatewayReady"
+	// FlowLogsReadyCondition means the NSG flow logs of the cluster exist and are ready to be used.
+	FlowLogsReadyCondition clusterv1.ConditionType = "FlowLogsReady"
+	// DiagnosticSettingsReadyCondition means the Azure Monitor diagnostic settings of the cluster exist and are ready to be used.
+	DiagnosticSettingsReadyCondition clusterv1.ConditionType = "DiagnosticSettingsReady"
 	// InboundNATRulesReadyCondition means the inbound NAT rules exist and are ready to be used.
 	InboundNATRulesReadyCondition clusterv1.ConditionType = "InboundNATRulesReady"
 	// AvailabilitySetReadyCondition means the availability set exists and is ready to be used.
 	AvailabilitySetReadyCondition clusterv1.ConditionType = "AvailabilitySetReady"
 	// RoleAssignmentReadyCondition means the role assignment exists and is ready to be used.
 	RoleAssignmentReadyCondition clusterv1.ConditionType = "RoleAssignmentReady"
+	// ManagedIdentityReadyCondition means the managed identity of the cluster and its role assignments exist and are
+	// ready to be used.
+	ManagedIdentityReadyCondition clusterv1.ConditionType = "ManagedIdentityReady"
 	// DisksReadyCondition means the disks exist and are ready to be used.
 	DisksReadyCondition clusterv1.ConditionType = "DisksReady"
 	// NetworkInterfaceReadyCondition means the network interfaces exist and are ready to be used.
 	NetworkInterfaceReadyCondition clusterv1.ConditionType = "NetworkInterfacesReady"
 	// PrivateEndpointsReadyCondition means the private endpoints exist and are ready to be used.
 	PrivateEndpointsReadyCondition clusterv1.ConditionType = "PrivateEndpointsReady"
+	// StorageAccountReadyCondition means the storage account used to deliver bootstrap data exists and is ready to be used.
+	StorageAccountReadyCondition clusterv1.ConditionType = "StorageAccountReady"
+	// BootstrapDataReadyCondition means the bootstrap data blob of the machine has been uploaded, or deleted once the machine has joined the cluster.
+	BootstrapDataReadyCondition clusterv1.ConditionType = "BootstrapDataReady"
+	// BootstrapDataSecretReadyCondition means the Key Vault secret holding the bootstrap data of the machine has been
+	// stored and can be read by the VM, or deleted once the machine has joined the cluster.
+	BootstrapDataSecretReadyCondition clusterv1.ConditionType = "BootstrapDataSecretReady"
+	// ResourceGroupLockedCondition means the cluster resource group is protected from deletion by a management lock.
+	ResourceGroupLockedCondition clusterv1.ConditionType = "ResourceGroupLocked"
+	// PolicyCompliantCondition means the resources of the cluster are not denied by Azure Policy.
+	PolicyCompliantCondition clusterv1.ConditionType = "PolicyCompliant"
+	// APIServerReachableCondition means the control plane endpoint of the cluster accepts TLS connections from the management cluster.
+	APIServerReachableCondition clusterv1.ConditionType = "APIServerReachable"
+	// NoDriftCondition means no Azure resource was found changed out-of-band by the last reconciliation. It is only set
+	// with the Report drift remediation policy.
+	NoDriftCondition clusterv1.ConditionType = "NoDrift"
+	// OutdatedImageCondition is True when a newer build of the Major.Minor version of the image of a machine is
+	// available in its location. It is only set when image freshness checks are enabled, and removed once the machine
+	// uses the latest build, so that it does not affect the Ready condition of up-to-date machines.
+	OutdatedImageCondition clusterv1.ConditionType = "OutdatedImage"
+	// NodeNotJoinedCondition is True when the VM of a machine has been running for longer than the node join timeout
+	// without its node joining the cluster, with the last lines of the serial console log of the VM as message. It is
+	// only set when serial console log collection is enabled, and removed once the node joins.
+	NodeNotJoinedCondition clusterv1.ConditionType = "NodeNotJoined"
+	// PlatformManagedKeysOnlyCondition is True when the disks of some VMs of a cluster are only encrypted at rest with
+	// platform-managed keys, without customer-managed keys, encryption at host or Azure Disk Encryption. It is removed
+	// once every VM has additional encryption, so that it does not affect the Ready condition of the cluster.
+	PlatformManagedKeysOnlyCondition clusterv1.ConditionType = "PlatformManagedKeysOnly"
+	// ControlPlaneSingleZoneCondition is True when all the machines of a control plane of at least three machines are in
+	// the same failure domain while the location of the cluster offers several, e.g. after remediation recreated
+	// machines in the remaining zone. It is removed once the control plane spans several failure domains again, so that
+	// it does not affect the Ready condition of the cluster.
+	ControlPlaneSingleZoneCondition clusterv1.ConditionType = "ControlPlaneSingleZone"
 
 	// CreatingReason means the resource is being created.
 	CreatingReason = "Creating"
@@ -143,6 +202,20 @@ const (
 	DeletionFailedReason = "DeletionFailed"
 	// UpdatingReason means the resource is being updated.
 	UpdatingReason = "Updating"
+	// PolicyViolationReason means resources would be denied by Azure Policy.
+	PolicyViolationReason = "PolicyViolation"
+	// APIServerUnreachableReason means the control plane endpoint could not be reached.
+	APIServerUnreachableReason = "APIServerUnreachable"
+	// DriftDetectedReason means Azure resources were changed out-of-band and no longer match the spec.
+	DriftDetectedReason = "DriftDetected"
+	// NewerImageAvailableReason means a newer build of the image of a machine is available.
+	NewerImageAvailableReason = "NewerImageAvailable"
+	// NodeJoinTimedOutReason means the node of a running machine did not join the cluster within the node join timeout.
+	NodeJoinTimedOutReason = "NodeJoinTimedOut"
+	// NoAdditionalEncryptionReason means the disks of VMs are only encrypted at rest with platform-managed keys.
+	NoAdditionalEncryptionReason = "NoAdditionalEncryption"
+	// ZoneRedundancyLostReason means the machines of a control plane are all in the same failure domain.
+	ZoneRedundancyLostReason = "ZoneRedundancyLost"
 )
 
 const (
@@ -166,3 +239,60 @@ const (
 	// value for the label is the CAPI Cluster Name.
 	OwnedByClusterLabelKey = NameAzureProviderPrefix + string(ResourceLifecycleOwned)
 )
+
+const (
+	// ExternallyManagedReportStatusAnnotation can be set to "true" on an AzureCluster which also carries the
+	// Cluster API "cluster.x-k8s.io/managed-by" annotation. CAPZ then leaves all Azure resources untouched but
+	// still reports the cluster status: failure domains, control plane endpoint and readiness.
+	ExternallyManagedReportStatusAnnotation = "infrastructure.cluster.x-k8s.io/externally-managed-report-status"
+
+	// DeletionDryRunAnnotation can be set to "true" on an AzureCluster to make its deletion a dry run: CAPZ lists the
+	// Azure resources deleting the cluster would delete in an event, and deletes nothing until the annotation is removed.
+	DeletionDryRunAnnotation = "infrastructure.cluster.x-k8s.io/deletion-dry-run"
+
+	// PlanAnnotation can be set to "true" on an AzureCluster to put its reconciliation in plan mode: CAPZ records the
+	// Azure resources it would create, update or delete in the plan of the AzureCluster status, and changes nothing
+	// until the annotation is removed.
+	PlanAnnotation = "infrastructure.cluster.x-k8s.io/plan"
+
+	// RotateControlPlaneEndpointAnnotation can be set to "true" on an AzureCluster to change its control plane
+	// endpoint: the type and frontend IPs of the API server load balancer can then be changed, and the control plane
+	// endpoint of the AzureCluster and its Cluster follow the new API server host once the load balancer is updated.
+	// CAPZ removes the annotation when the endpoint has been rotated, or when the API server host did not change.
+	RotateControlPlaneEndpointAnnotation = "infrastructure.cluster.x-k8s.io/rotate-control-plane-endpoint"
+
+	// VMPowerStateAnnotation can be set on an AzureMachine to the power state its VM should be in, either "Running",
+	// "Deallocated" or "Hibernated". CAPZ deallocates, hibernates or starts the VM accordingly, which allows putting the
+	// machines of a cluster to sleep without deleting them. Hibernation requires the hibernation capability of the VM to
+	// be enabled. The power state of the VM is left untouched when the annotation is not set.
+	VMPowerStateAnnotation = "infrastructure.cluster.x-k8s.io/power-state"
+
+	// CaptureImageAnnotation can be set on an AzureMachine to the resource ID of a compute gallery image version to
+	// capture its VM into. CAPZ deprovisions, deallocates and generalizes the VM, then creates the image version from
+	// it. A generalized VM cannot be started again, so the machine should be deleted once the image has been captured.
+	CaptureImageAnnotation = "infrastructure.cluster.x-k8s.io/capture-image"
+
+	// SSHKeyRotationAnnotation can be set to "true" on a Linux AzureMachine to allow changing its SSH public key. CAPZ
+	// then replaces the SSH public keys authorized on its VM with the VMAccess extension whenever the key changes,
+	// instead of requiring the machine to be replaced.
+	SSHKeyRotationAnnotation = "infrastructure.cluster.x-k8s.io/ssh-key-rotation"
+
+	// AdoptResourcesAnnotation can be set to "true" on an AzureCluster to adopt the pre-existing virtual network,
+	// security groups and load balancers with the names of its own which are not tagged as owned by any cluster. CAPZ
+	// tags them as owned by the cluster and manages them from then on, deleting them along with the cluster, instead of
+	// leaving the virtual network unmanaged.
+	AdoptResourcesAnnotation = "infrastructure.cluster.x-k8s.io/adopt-resources"
+
+	// ExportResourcesAnnotation can be set on an AzureCluster to request an export of the ARM templates of the Azure
+	// resources tagged as owned by the cluster into its "<name>-azure-export" ConfigMap, e.g. for audits or to recreate
+	// them out of band. CAPZ exports the templates once per value of the annotation, so changing its value, for example
+	// to the current date, requests a new export.
+	ExportResourcesAnnotation = "infrastructure.cluster.x-k8s.io/export-resources"
+
+	// ForceDeleteAnnotation can be set to "true" on an AzureCluster or an AzureMachine whose deletion is permanently
+	// blocked in Azure, e.g. by a resource lock or a policy denying deletes. When deleting its Azure resources is
+	// denied by a resource lock or a policy, or has been failing with non-transient errors for 30 minutes, CAPZ then
+	// removes its finalizer anyway, abandoning the remaining resources, which it lists in a warning event and in the
+	// "<kind>-<name>-abandoned-resources" ConfigMap next to it.
+	ForceDeleteAnnotation = "infrastructure.cluster.x-k8s.io/force-delete"
+)
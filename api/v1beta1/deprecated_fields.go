@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DeprecatedFieldsRemovalVersion is the API version in which the deprecated fields of this API version are planned to
+// be removed.
+const DeprecatedFieldsRemovalVersion = "v1beta2"
+
+// DeprecatedFieldWarning returns the admission warning for a deprecated field that is set, naming its replacement.
+func DeprecatedFieldWarning(fldPath *field.Path, replacement string) string {
+	return fmt.Sprintf("%s is deprecated and will be removed in %s, use %s instead", fldPath, DeprecatedFieldsRemovalVersion, replacement)
+}
+
+// AzureMachineSpecDeprecationWarnings returns the admission warnings for the deprecated fields set in an AzureMachine
+// spec at fldPath.
+func AzureMachineSpecDeprecationWarnings(spec AzureMachineSpec, fldPath *field.Path) admission.Warnings {
+	var warnings admission.Warnings
+	if spec.RoleAssignmentName != "" {
+		warnings = append(warnings, DeprecatedFieldWarning(fldPath.Child("roleAssignmentName"), fldPath.Child("systemAssignedIdentityRole", "name").String()))
+	}
+	if spec.SubnetName != "" {
+		warnings = append(warnings, DeprecatedFieldWarning(fldPath.Child("subnetName"), fldPath.Child("networkInterfaces").String()+"[].subnetName"))
+	}
+	if spec.AcceleratedNetworking != nil {
+		warnings = append(warnings, DeprecatedFieldWarning(fldPath.Child("acceleratedNetworking"), fldPath.Child("networkInterfaces").String()+"[].acceleratedNetworking"))
+	}
+	return append(warnings, ImageDeprecationWarnings(spec.Image, fldPath.Child("image"))...)
+}
+
+// ImageDeprecationWarnings returns the admission warnings for the deprecated fields set in an image at fldPath.
+func ImageDeprecationWarnings(image *Image, fldPath *field.Path) admission.Warnings {
+	if image == nil || image.SharedGallery == nil {
+		return nil
+	}
+	return admission.Warnings{DeprecatedFieldWarning(fldPath.Child("sharedGallery"), fldPath.Child("computeGallery").String())}
+}
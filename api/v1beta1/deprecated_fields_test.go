@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestAzureMachineSpecDeprecationWarnings(t *testing.T) {
+	tests := []struct {
+		name             string
+		spec             AzureMachineSpec
+		expectedWarnings admission.Warnings
+	}{
+		{
+			name: "no deprecated fields",
+			spec: AzureMachineSpec{
+				NetworkInterfaces: []NetworkInterface{{SubnetName: "subnet", AcceleratedNetworking: ptr.To(true)}},
+				Image:             &Image{ComputeGallery: &AzureComputeGalleryImage{Name: "image"}},
+			},
+		},
+		{
+			name: "deprecated fields",
+			spec: AzureMachineSpec{
+				RoleAssignmentName:    "role",
+				SubnetName:            "subnet",
+				AcceleratedNetworking: ptr.To(false),
+				Image:                 &Image{SharedGallery: &AzureSharedGalleryImage{Name: "image"}},
+			},
+			expectedWarnings: admission.Warnings{
+				"spec.roleAssignmentName is deprecated and will be removed in v1beta2, use spec.systemAssignedIdentityRole.name instead",
+				"spec.subnetName is deprecated and will be removed in v1beta2, use spec.networkInterfaces[].subnetName instead",
+				"spec.acceleratedNetworking is deprecated and will be removed in v1beta2, use spec.networkInterfaces[].acceleratedNetworking instead",
+				"spec.image.sharedGallery is deprecated and will be removed in v1beta2, use spec.image.computeGallery instead",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			warnings := AzureMachineSpecDeprecationWarnings(tc.spec, field.NewPath("spec"))
+			if tc.expectedWarnings == nil {
+				g.Expect(warnings).To(BeEmpty())
+			} else {
+				g.Expect(warnings).To(Equal(tc.expectedWarnings))
+			}
+		})
+	}
+}
+
+func TestAzureManagedControlPlaneDeprecationWarnings(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(azureManagedControlPlaneDeprecationWarnings(&AzureManagedControlPlane{})).To(BeEmpty())
+	g.Expect(azureManagedControlPlaneDeprecationWarnings(&AzureManagedControlPlane{
+		Spec: AzureManagedControlPlaneSpec{SKU: &AKSSku{Tier: StandardManagedControlPlaneTier}},
+	})).To(BeEmpty())
+	g.Expect(azureManagedControlPlaneDeprecationWarnings(&AzureManagedControlPlane{
+		Spec: AzureManagedControlPlaneSpec{SKU: &AKSSku{Tier: PaidManagedControlPlaneTier}},
+	})).To(ConsistOf("spec.sku.tier Paid is deprecated and will be removed in v1beta2, use Standard instead"))
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// defaultFuzzIterations is the number of random objects of each type checked by default, to keep go test fast.
+	defaultFuzzIterations = 100
+
+	// fuzzIterationsEnv and fuzzSeedEnv are the environment variables setting the number of random objects of each
+	// type checked, e.g. thousands in the go-test-scale make target, and the seed of the random objects, to reproduce
+	// a failure.
+	fuzzIterationsEnv = "CAPZ_FUZZ_ITERATIONS"
+	fuzzSeedEnv       = "CAPZ_FUZZ_SEED"
+)
+
+// newFuzzer returns a fuzzer populating API objects at random, and the number of objects to check.
+func newFuzzer(t *testing.T) (fuzz func(obj interface{}), iterations int) {
+	t.Helper()
+
+	iterations = defaultFuzzIterations
+	if v := os.Getenv(fuzzIterationsEnv); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			t.Fatalf("invalid %s: %v", fuzzIterationsEnv, err)
+		}
+		iterations = n
+	}
+	seed := time.Now().UnixNano()
+	if v := os.Getenv(fuzzSeedEnv); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			t.Fatalf("invalid %s: %v", fuzzSeedEnv, err)
+		}
+		seed = n
+	}
+	t.Logf("fuzzing %d objects with %s=%d", iterations, fuzzSeedEnv, seed)
+
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	f := fuzzer.FuzzerFor(metafuzzer.Funcs, rand.NewSource(seed), runtimeserializer.NewCodecFactory(scheme)) //nolint:gosec // Fuzzing doesn't need a secure source.
+	return f.Fuzz, iterations
+}
+
+// dnsLabel maps s to a DNS label of at most maxLength characters, like the names the API server accepts, as generated
+// Azure resource names derive from names the API server has validated before calling the webhooks.
+func dnsLabel(s string, maxLength int) string {
+	b := make([]byte, 0, maxLength)
+	for _, r := range strings.ToLower(s) {
+		if len(b) == maxLength {
+			break
+		}
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || (r == '-' && len(b) > 0) {
+			b = append(b, byte(r))
+		} else {
+			b = append(b, 'x')
+		}
+	}
+	if len(b) > 0 && b[len(b)-1] == '-' {
+		b[len(b)-1] = 'x'
+	}
+	return string(b)
+}
+
+// checkRoundTrip fails when obj doesn't survive a JSON round trip, as done by the API server, or a round trip through
+// unstructured, as done by the controllers using unstructured clients.
+func checkRoundTrip[T runtime.Object](t *testing.T, obj T, newObj func() T) {
+	t.Helper()
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to marshal %T: %v", obj, err)
+	}
+	fromJSON := newObj()
+	if err := json.Unmarshal(data, fromJSON); err != nil {
+		t.Fatalf("failed to unmarshal %T: %v", obj, err)
+	}
+	if !apiequality.Semantic.DeepEqual(obj, fromJSON) {
+		t.Fatalf("%T changed after a JSON round trip:\n%s", obj, cmp.Diff(obj, fromJSON))
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatalf("failed to convert %T to unstructured: %v", obj, err)
+	}
+	fromUnstructured := newObj()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u, fromUnstructured); err != nil {
+		t.Fatalf("failed to convert unstructured to %T: %v", obj, err)
+	}
+	if !apiequality.Semantic.DeepEqual(obj, fromUnstructured) {
+		t.Fatalf("%T changed after an unstructured round trip:\n%s", obj, cmp.Diff(obj, fromUnstructured))
+	}
+
+	if copied := obj.DeepCopyObject(); !apiequality.Semantic.DeepEqual(obj, copied) {
+		t.Fatalf("%T changed after a deep copy:\n%s", obj, cmp.Diff(obj, copied))
+	}
+}
+
+// noPanic runs f, failing with the fuzzed object instead of crashing the test binary if f panics.
+func noPanic(t *testing.T, what string, obj runtime.Object, f func()) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil {
+			data, _ := json.Marshal(obj)
+			t.Fatalf("%s panicked: %v\n%s", what, r, data)
+		}
+	}()
+	f()
+}
+
+func TestFuzzAzureCluster(t *testing.T) {
+	fuzz, iterations := newFuzzer(t)
+	for i := 0; i < iterations; i++ {
+		c := &AzureCluster{}
+		fuzz(c)
+		c.Name = "c" + dnsLabel(c.Name, 62)
+		c.Namespace = dnsLabel(c.Namespace, 63)
+		if naming := c.Spec.ResourceNaming; naming != nil {
+			naming.Prefix = dnsLabel(naming.Prefix, 20)
+			naming.Suffix = dnsLabel(naming.Suffix, 20)
+		}
+		checkRoundTrip(t, c, func() *AzureCluster { return &AzureCluster{} })
+
+		noPanic(t, "defaulting", c, c.Default)
+		defaulted := c.DeepCopy()
+		noPanic(t, "defaulting again", defaulted, defaulted.Default)
+		if !apiequality.Semantic.DeepEqual(c, defaulted) {
+			t.Fatalf("defaulting an AzureCluster is not idempotent:\n%s", cmp.Diff(c, defaulted))
+		}
+		checkRoundTrip(t, c, func() *AzureCluster { return &AzureCluster{} })
+
+		noPanic(t, "validation", c, func() {
+			_, createErr := c.ValidateCreate()
+			_, updateErr := c.ValidateUpdate(c.DeepCopy())
+			if createErr == nil && updateErr != nil {
+				t.Fatalf("a valid AzureCluster can't be updated to itself: %v", updateErr)
+			}
+		})
+	}
+}
+
+func TestFuzzAzureMachine(t *testing.T) {
+	fuzz, iterations := newFuzzer(t)
+	mw := &azureMachineWebhook{Client: mockDefaultClient{SubscriptionID: "test-subscription-id"}}
+	for i := 0; i < iterations; i++ {
+		m := &AzureMachine{}
+		fuzz(m)
+		m.Name = "m" + dnsLabel(m.Name, 62)
+		m.Namespace = dnsLabel(m.Namespace, 63)
+		// Defaulting looks the cluster up, and generates an SSH key for machines without one, which is too slow
+		// to do thousands of times.
+		if m.Labels == nil {
+			m.Labels = map[string]string{}
+		}
+		m.Labels[clusterv1.ClusterNameLabel] = "test-cluster"
+		if m.Spec.SSHPublicKey == "" {
+			m.Spec.SSHPublicKey = fmt.Sprintf("key-%d", i)
+		}
+		checkRoundTrip(t, m, func() *AzureMachine { return &AzureMachine{} })
+
+		noPanic(t, "defaulting", m, func() { _ = mw.Default(context.Background(), m) })
+		defaulted := m.DeepCopy()
+		noPanic(t, "defaulting again", defaulted, func() { _ = mw.Default(context.Background(), defaulted) })
+		if !apiequality.Semantic.DeepEqual(m, defaulted) {
+			t.Fatalf("defaulting an AzureMachine is not idempotent:\n%s", cmp.Diff(m, defaulted))
+		}
+		checkRoundTrip(t, m, func() *AzureMachine { return &AzureMachine{} })
+
+		noPanic(t, "validation", m, func() {
+			_, createErr := mw.ValidateCreate(context.Background(), m)
+			_, updateErr := mw.ValidateUpdate(context.Background(), m.DeepCopy(), m)
+			if createErr == nil && updateErr != nil {
+				t.Fatalf("a valid AzureMachine can't be updated to itself: %v", updateErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// LocationChecker lists the locations and VM sizes available to the subscriptions of AzureClusters, so that webhooks
+// can reject the locations and VM sizes that are not available at admission rather than when provisioning them.
+type LocationChecker interface {
+	// Locations returns the names of the locations available to the subscription of cluster.
+	Locations(ctx context.Context, cluster *AzureCluster) ([]string, error)
+	// VMSizes returns the names of the VM sizes available to the subscription of cluster in location.
+	VMSizes(ctx context.Context, cluster *AzureCluster, location string) ([]string, error)
+	// VMSizeCapabilities returns the capabilities of vmSize in location for the subscription of cluster, or nil when
+	// the VM size is not available there.
+	VMSizeCapabilities(ctx context.Context, cluster *AzureCluster, location, vmSize string) (*VMSizeCapabilities, error)
+}
+
+// VMSizeCapabilities are the capabilities of a VM size that machines can request, from the resource SKU of the size.
+// +kubebuilder:object:generate=false
+type VMSizeCapabilities struct {
+	// AcceleratedNetworking is true if the network interfaces of the VM size support accelerated networking.
+	AcceleratedNetworking bool
+	// EphemeralOSDisk is true if the VM size supports ephemeral OS disks.
+	EphemeralOSDisk bool
+	// EncryptionAtHost is true if the VM size supports encryption at host.
+	EncryptionAtHost bool
+	// TrustedLaunch is true if the VM size supports secure boot and vTPM.
+	TrustedLaunch bool
+	// ConfidentialComputing is true if the VM size supports confidential computing.
+	ConfidentialComputing bool
+	// MaxWriteAcceleratorDisks is the number of disks of the VM size which can use write accelerator.
+	MaxWriteAcceleratorDisks int64
+}
+
+// VMSizeFeatures are the features of the VMs of a machine or a machine pool which their VM size must support.
+// +kubebuilder:object:generate=false
+type VMSizeFeatures struct {
+	OSDisk            OSDisk
+	DataDisks         []DataDisk
+	SecurityProfile   *SecurityProfile
+	NetworkInterfaces []NetworkInterface
+}
+
+// LocationDefaulter returns the location of the AzureClusters created without one in subscriptionID, or an empty
+// string when the subscription has no default location. subscriptionID is empty for the clusters in the subscription
+// of the controller manager.
+type LocationDefaulter func(subscriptionID string) string
+
+// ValidateLocationAvailability checks that the location of cluster is available to its subscription, listing the
+// available locations otherwise. Failing to list the available locations only yields a warning, so that clusters can
+// still be created when their locations can't be listed, e.g. when the controller manager has no access to their
+// subscription.
+func ValidateLocationAvailability(ctx context.Context, checker LocationChecker, cluster *AzureCluster, fldPath *field.Path) (admission.Warnings, *field.Error) {
+	locations, err := checker.Locations(ctx, cluster)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("failed to list the available locations, location %s was not validated: %v", cluster.Spec.Location, err)}, nil
+	}
+	location := normalizeLocation(cluster.Spec.Location)
+	for _, l := range locations {
+		if normalizeLocation(l) == location {
+			return nil, nil
+		}
+	}
+	sort.Strings(locations)
+	return nil, field.NotSupported(fldPath, cluster.Spec.Location, locations)
+}
+
+// ValidateVMSizeAvailability checks that vmSize is available in location to the subscription of cluster, listing the
+// available sizes of the same series otherwise. Like ValidateLocationAvailability, failing to list the available VM
+// sizes only yields a warning.
+func ValidateVMSizeAvailability(ctx context.Context, checker LocationChecker, cluster *AzureCluster, location, vmSize string, fldPath *field.Path) (admission.Warnings, *field.Error) {
+	vmSizes, err := checker.VMSizes(ctx, cluster, location)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("failed to list the VM sizes available in location %s, VM size %s was not validated: %v", location, vmSize, err)}, nil
+	}
+	for _, size := range vmSizes {
+		if strings.EqualFold(size, vmSize) {
+			return nil, nil
+		}
+	}
+	detail := fmt.Sprintf("VM size is not available in location %s", location)
+	if alternatives := similarVMSizes(vmSize, vmSizes); len(alternatives) > 0 {
+		detail += fmt.Sprintf(", the sizes of the same series available there are: %s", strings.Join(alternatives, ", "))
+	}
+	return nil, field.Invalid(fldPath, vmSize, detail)
+}
+
+// ValidateVMSizeCapabilities checks that vmSize supports the features of the VMs of a machine or a machine pool in
+// location, such as ephemeral OS disks or accelerated networking, so that they are rejected at admission rather than
+// when the VMs are created. fldPath is the path of the spec holding the features. Like ValidateVMSizeAvailability,
+// failing to get the capabilities of the VM size only yields a warning, and nothing is checked when the VM size is not
+// available in location.
+func ValidateVMSizeCapabilities(ctx context.Context, checker LocationChecker, cluster *AzureCluster, location, vmSize string, features VMSizeFeatures, fldPath *field.Path) (admission.Warnings, field.ErrorList) {
+	capabilities, err := checker.VMSizeCapabilities(ctx, cluster, location, vmSize)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("failed to get the capabilities of VM size %s in location %s, its features were not validated: %v", vmSize, location, err)}, nil
+	}
+	if capabilities == nil {
+		return nil, nil
+	}
+
+	var allErrs field.ErrorList
+	if features.OSDisk.DiffDiskSettings != nil && !capabilities.EphemeralOSDisk {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("osDisk", "diffDiskSettings"), features.OSDisk.DiffDiskSettings.Option,
+			fmt.Sprintf("VM size %s does not support ephemeral OS disks", vmSize)))
+	}
+	if features.OSDisk.ManagedDisk != nil && features.OSDisk.ManagedDisk.SecurityProfile != nil && !capabilities.ConfidentialComputing {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("osDisk", "managedDisk", "securityProfile"),
+			fmt.Sprintf("VM size %s does not support confidential computing", vmSize)))
+	}
+	writeAcceleratorDisks := 0
+	if ptr.Deref(features.OSDisk.WriteAcceleratorEnabled, false) {
+		writeAcceleratorDisks++
+	}
+	for _, disk := range features.DataDisks {
+		if ptr.Deref(disk.WriteAcceleratorEnabled, false) {
+			writeAcceleratorDisks++
+		}
+	}
+	if int64(writeAcceleratorDisks) > capabilities.MaxWriteAcceleratorDisks {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("vmSize"), vmSize,
+			fmt.Sprintf("VM size supports write accelerator on %d disks, %d disks enable it", capabilities.MaxWriteAcceleratorDisks, writeAcceleratorDisks)))
+	}
+	if profile := features.SecurityProfile; profile != nil {
+		if ptr.Deref(profile.EncryptionAtHost, false) && !capabilities.EncryptionAtHost {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("securityProfile", "encryptionAtHost"), true,
+				fmt.Sprintf("VM size %s does not support encryption at host", vmSize)))
+		}
+		if profile.UefiSettings != nil && !capabilities.TrustedLaunch {
+			if ptr.Deref(profile.UefiSettings.SecureBootEnabled, false) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("securityProfile", "uefiSettings", "secureBootEnabled"), true,
+					fmt.Sprintf("VM size %s does not support secure boot", vmSize)))
+			}
+			if ptr.Deref(profile.UefiSettings.VTpmEnabled, false) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("securityProfile", "uefiSettings", "vTpmEnabled"), true,
+					fmt.Sprintf("VM size %s does not support vTPM", vmSize)))
+			}
+		}
+	}
+	for i, nic := range features.NetworkInterfaces {
+		if ptr.Deref(nic.AcceleratedNetworking, false) && !capabilities.AcceleratedNetworking {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("networkInterfaces").Index(i).Child("acceleratedNetworking"), true,
+				fmt.Sprintf("VM size %s does not support accelerated networking", vmSize)))
+		}
+	}
+	return nil, allErrs
+}
+
+// GetOwnerAzureCluster returns the AzureCluster of the cluster named by the cluster name label of obj. It returns nil
+// when obj has no cluster name label yet or when its cluster isn't an AzureCluster.
+func GetOwnerAzureCluster(ctx context.Context, cli client.Client, obj client.Object) (*AzureCluster, error) {
+	clusterName, ok := obj.GetLabels()[clusterv1.ClusterNameLabel]
+	if !ok {
+		return nil, nil
+	}
+	cluster := &clusterv1.Cluster{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: clusterName}, cluster); err != nil {
+		return nil, err
+	}
+	ref := cluster.Spec.InfrastructureRef
+	if ref == nil || ref.Kind != "AzureCluster" {
+		return nil, nil
+	}
+	key := client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}
+	if key.Namespace == "" {
+		key.Namespace = cluster.Namespace
+	}
+	azureCluster := &AzureCluster{}
+	if err := cli.Get(ctx, key, azureCluster); err != nil {
+		return nil, err
+	}
+	return azureCluster, nil
+}
+
+// normalizeLocation returns the name of a location from either its name or its display name, e.g. eastus for East US.
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+}
+
+// similarVMSizes returns the sizes of vmSizes from the same series as vmSize, i.e. whose names only differ by their
+// number of vCPUs or their version, e.g. Standard_D2s_v3 and Standard_D8s_v5 for Standard_D4s_v3.
+func similarVMSizes(vmSize string, vmSizes []string) []string {
+	series := vmSizeSeries(vmSize)
+	var similar []string
+	for _, size := range vmSizes {
+		if vmSizeSeries(size) == series {
+			similar = append(similar, size)
+		}
+	}
+	sort.Strings(similar)
+	return similar
+}
+
+// vmSizeSeries returns the name of a VM size without its digits.
+func vmSizeSeries(vmSize string) string {
+	return strings.ToLower(strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return -1
+		}
+		return r
+	}, vmSize))
+}
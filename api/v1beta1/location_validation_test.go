@@ -0,0 +1,301 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeLocationChecker is a LocationChecker returning fixed locations, VM sizes and VM size capabilities.
+type fakeLocationChecker struct {
+	locations    []string
+	vmSizes      map[string][]string
+	capabilities map[string]*VMSizeCapabilities
+	err          error
+}
+
+func (c fakeLocationChecker) Locations(_ context.Context, _ *AzureCluster) ([]string, error) {
+	return append([]string(nil), c.locations...), c.err
+}
+
+func (c fakeLocationChecker) VMSizes(_ context.Context, _ *AzureCluster, location string) ([]string, error) {
+	return c.vmSizes[location], c.err
+}
+
+func (c fakeLocationChecker) VMSizeCapabilities(_ context.Context, _ *AzureCluster, _, vmSize string) (*VMSizeCapabilities, error) {
+	return c.capabilities[vmSize], c.err
+}
+
+func TestValidateLocationAvailability(t *testing.T) {
+	checker := fakeLocationChecker{locations: []string{"westus2", "eastus", "westeurope"}}
+	tests := []struct {
+		name        string
+		location    string
+		checker     fakeLocationChecker
+		wantErr     string
+		wantWarning string
+	}{
+		{
+			name:     "available location",
+			location: "eastus",
+			checker:  checker,
+		},
+		{
+			name:     "available location by display name",
+			location: "East US",
+			checker:  checker,
+		},
+		{
+			name:     "unavailable location",
+			location: "eastus3",
+			checker:  checker,
+			wantErr:  `spec.location: Unsupported value: "eastus3": supported values: "eastus", "westeurope", "westus2"`,
+		},
+		{
+			name:        "locations can't be listed",
+			location:    "eastus",
+			checker:     fakeLocationChecker{err: errors.New("AuthorizationFailed")},
+			wantWarning: "failed to list the available locations, location eastus was not validated: AuthorizationFailed",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cluster := &AzureCluster{Spec: AzureClusterSpec{AzureClusterClassSpec: AzureClusterClassSpec{Location: tc.location}}}
+			warnings, err := ValidateLocationAvailability(context.Background(), tc.checker, cluster, field.NewPath("spec", "location"))
+			if tc.wantErr != "" {
+				g.Expect(err).NotTo(BeNil())
+				g.Expect(err.Error()).To(Equal(tc.wantErr))
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+			if tc.wantWarning != "" {
+				g.Expect(warnings).To(ConsistOf(tc.wantWarning))
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateVMSizeAvailability(t *testing.T) {
+	checker := fakeLocationChecker{
+		vmSizes: map[string][]string{
+			"eastus": {"Standard_D8s_v3", "Standard_D2s_v3", "Standard_D4s_v5", "Standard_D4_v3", "Standard_B2s"},
+		},
+	}
+	tests := []struct {
+		name        string
+		vmSize      string
+		checker     fakeLocationChecker
+		wantErr     string
+		wantWarning string
+	}{
+		{
+			name:    "available VM size",
+			vmSize:  "Standard_D2s_v3",
+			checker: checker,
+		},
+		{
+			name:    "available VM size with a different case",
+			vmSize:  "standard_d2s_v3",
+			checker: checker,
+		},
+		{
+			name:    "unavailable VM size with available sizes of the same series",
+			vmSize:  "Standard_D4s_v3",
+			checker: checker,
+			wantErr: `spec.vmSize: Invalid value: "Standard_D4s_v3": VM size is not available in location eastus, the sizes of the same series available there are: Standard_D2s_v3, Standard_D4s_v5, Standard_D8s_v3`,
+		},
+		{
+			name:    "unavailable VM size without available sizes of the same series",
+			vmSize:  "Standard_NC6",
+			checker: checker,
+			wantErr: `spec.vmSize: Invalid value: "Standard_NC6": VM size is not available in location eastus`,
+		},
+		{
+			name:        "VM sizes can't be listed",
+			vmSize:      "Standard_D2s_v3",
+			checker:     fakeLocationChecker{err: errors.New("AuthorizationFailed")},
+			wantWarning: "failed to list the VM sizes available in location eastus, VM size Standard_D2s_v3 was not validated: AuthorizationFailed",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			warnings, err := ValidateVMSizeAvailability(context.Background(), tc.checker, &AzureCluster{}, "eastus", tc.vmSize, field.NewPath("spec", "vmSize"))
+			if tc.wantErr != "" {
+				g.Expect(err).NotTo(BeNil())
+				g.Expect(err.Error()).To(Equal(tc.wantErr))
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+			if tc.wantWarning != "" {
+				g.Expect(warnings).To(ConsistOf(tc.wantWarning))
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateVMSizeCapabilities(t *testing.T) {
+	checker := fakeLocationChecker{
+		capabilities: map[string]*VMSizeCapabilities{
+			"Standard_D2s_v3": {EphemeralOSDisk: true, EncryptionAtHost: true, TrustedLaunch: true, MaxWriteAcceleratorDisks: 1},
+			"Standard_B2s":    {},
+		},
+	}
+	features := VMSizeFeatures{
+		OSDisk: OSDisk{
+			DiffDiskSettings:        &DiffDiskSettings{Option: "Local"},
+			WriteAcceleratorEnabled: ptr.To(true),
+		},
+		SecurityProfile: &SecurityProfile{
+			EncryptionAtHost: ptr.To(true),
+			UefiSettings:     &UefiSettings{SecureBootEnabled: ptr.To(true)},
+		},
+	}
+	tests := []struct {
+		name        string
+		vmSize      string
+		features    VMSizeFeatures
+		checker     fakeLocationChecker
+		wantErrs    []string
+		wantWarning string
+	}{
+		{
+			name:     "features supported by the VM size",
+			vmSize:   "Standard_D2s_v3",
+			features: features,
+			checker:  checker,
+		},
+		{
+			name:     "features not supported by the VM size",
+			vmSize:   "Standard_B2s",
+			features: features,
+			checker:  checker,
+			wantErrs: []string{
+				`spec.osDisk.diffDiskSettings: Invalid value: "Local": VM size Standard_B2s does not support ephemeral OS disks`,
+				`spec.vmSize: Invalid value: "Standard_B2s": VM size supports write accelerator on 0 disks, 1 disks enable it`,
+				`spec.securityProfile.encryptionAtHost: Invalid value: true: VM size Standard_B2s does not support encryption at host`,
+				`spec.securityProfile.uefiSettings.secureBootEnabled: Invalid value: true: VM size Standard_B2s does not support secure boot`,
+			},
+		},
+		{
+			name:   "accelerated networking not supported by the VM size",
+			vmSize: "Standard_D2s_v3",
+			features: VMSizeFeatures{
+				NetworkInterfaces: []NetworkInterface{{}, {AcceleratedNetworking: ptr.To(true)}},
+			},
+			checker:  checker,
+			wantErrs: []string{`spec.networkInterfaces[1].acceleratedNetworking: Invalid value: true: VM size Standard_D2s_v3 does not support accelerated networking`},
+		},
+		{
+			name:     "VM size not available",
+			vmSize:   "Standard_NC6",
+			features: features,
+			checker:  checker,
+		},
+		{
+			name:        "capabilities can't be listed",
+			vmSize:      "Standard_B2s",
+			features:    features,
+			checker:     fakeLocationChecker{err: errors.New("AuthorizationFailed")},
+			wantWarning: "failed to get the capabilities of VM size Standard_B2s in location eastus, its features were not validated: AuthorizationFailed",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			warnings, errs := ValidateVMSizeCapabilities(context.Background(), tc.checker, &AzureCluster{}, "eastus", tc.vmSize, tc.features, field.NewPath("spec"))
+			errStrings := make([]string, 0, len(errs))
+			for _, err := range errs {
+				errStrings = append(errStrings, err.Error())
+			}
+			g.Expect(errStrings).To(Equal(append([]string{}, tc.wantErrs...)))
+			if tc.wantWarning != "" {
+				g.Expect(warnings).To(ConsistOf(tc.wantWarning))
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestGetOwnerAzureCluster(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(Succeed())
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "azure", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{Kind: "AzureCluster", Name: "azure-cluster"},
+			},
+		},
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{Kind: "AzureManagedCluster", Name: "managed"},
+			},
+		},
+		&AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "azure-cluster", Namespace: "default"},
+			Spec:       AzureClusterSpec{AzureClusterClassSpec: AzureClusterClassSpec{Location: "eastus"}},
+		},
+	).Build()
+	machine := func(clusterName string) client.Object {
+		m := &AzureMachine{ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"}}
+		if clusterName != "" {
+			m.Labels = map[string]string{clusterv1.ClusterNameLabel: clusterName}
+		}
+		return m
+	}
+
+	cluster, err := GetOwnerAzureCluster(context.Background(), cli, machine("azure"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Name).To(Equal("azure-cluster"))
+	g.Expect(cluster.Spec.Location).To(Equal("eastus"))
+
+	cluster, err = GetOwnerAzureCluster(context.Background(), cli, machine("managed"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster).To(BeNil())
+
+	cluster, err = GetOwnerAzureCluster(context.Background(), cli, machine(""))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster).To(BeNil())
+
+	_, err = GetOwnerAzureCluster(context.Background(), cli, machine("missing"))
+	g.Expect(err).To(HaveOccurred())
+}
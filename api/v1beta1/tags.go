@@ -19,6 +19,8 @@ package v1beta1
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 // Tags defines a map of tags.
@@ -41,6 +43,18 @@ func (t Tags) HasOwned(cluster string) bool {
 	return ok && ResourceLifecycle(value) == ResourceLifecycleOwned
 }
 
+// Owners returns the names of the clusters the tags mark the resource as owned by, in alphabetical order.
+func (t Tags) Owners() []string {
+	var owners []string
+	for key, value := range t {
+		if strings.HasPrefix(key, NameAzureProviderOwned) && ResourceLifecycle(value) == ResourceLifecycleOwned {
+			owners = append(owners, strings.TrimPrefix(key, NameAzureProviderOwned))
+		}
+	}
+	sort.Strings(owners)
+	return owners
+}
+
 // HasAzureCloudProviderOwned returns true if the tags contains a tag that marks the resource as owned by the cluster from the perspective of the in-tree cloud provider.
 func (t Tags) HasAzureCloudProviderOwned(cluster string) bool {
 	value, ok := t[ClusterAzureCloudProviderTagKey(cluster)]
@@ -116,12 +130,19 @@ const (
 	// dedicated to this cluster api provider implementation.
 	NameAzureClusterAPIRole = NameAzureProviderPrefix + "role"
 
+	// NameAzureClusterAPIMachine is the tag name we use to mark resources dedicated to a single machine,
+	// the value is the name of the AzureMachine. It is used to find the resources leaked by a deleted machine.
+	NameAzureClusterAPIMachine = NameAzureProviderPrefix + "machine"
+
 	// APIServerRole describes the value for the apiserver role.
 	APIServerRole = "apiserver"
 
 	// NodeOutboundRole describes the value for the node outbound LB role.
 	NodeOutboundRole = "nodeOutbound"
 
+	// NodeInternalRole describes the value for the node internal LB role.
+	NodeInternalRole = "nodeInternal"
+
 	// ControlPlaneOutboundRole describes the value for the control plane outbound LB role.
 	ControlPlaneOutboundRole = "controlPlaneOutbound"
 
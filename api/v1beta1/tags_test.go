@@ -87,3 +87,16 @@ func TestTags_Merge(t *testing.T) {
 		})
 	}
 }
+
+func TestTags_Owners(t *testing.T) {
+	g := NewWithT(t)
+	tags := Tags{
+		ClusterTagKey("b"):                   string(ResourceLifecycleOwned),
+		ClusterTagKey("a"):                   string(ResourceLifecycleOwned),
+		ClusterTagKey("c"):                   string(ResourceLifecycleShared),
+		ClusterAzureCloudProviderTagKey("d"): string(ResourceLifecycleOwned),
+		"foo":                                "bar",
+	}
+	g.Expect(tags.Owners()).To(Equal([]string{"a", "b"}))
+	g.Expect(Tags{}.Owners()).To(BeEmpty())
+}
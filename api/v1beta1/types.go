@@ -17,9 +17,16 @@ limitations under the License.
 package v1beta1
 
 import (
+	"regexp"
+	"strings"
+
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/net"
+	"k8s.io/utils/ptr"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 )
 
 const (
@@ -63,6 +70,8 @@ const (
 	PutFuture string = "PUT"
 	// DeleteFuture is a future that was derived from a DELETE request.
 	DeleteFuture string = "DELETE"
+	// PostFuture is a future that was derived from a POST request, such as a VM power operation.
+	PostFuture string = "POST"
 )
 
 // Future contains the data needed for an Azure long-running operation to continue across reconcile loops.
@@ -84,6 +93,11 @@ type Future struct {
 
 	// Data is the base64 url encoded json Azure AutoRest Future.
 	Data string `json:"data"`
+
+	// StartTime is the time at which the long-running operation was first observed as in progress.
+	// It is carried over every time the future is polled, so it reflects the total duration of the operation.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
 }
 
 // NetworkSpec specifies what the Azure networking resources should look like.
@@ -109,9 +123,349 @@ type NetworkSpec struct {
 	// +optional
 	ControlPlaneOutboundLB *LoadBalancerSpec `json:"controlPlaneOutboundLB,omitempty"`
 
+	// NodeInternalLB is the configuration for an internal load balancer of the worker nodes, for clusters where the
+	// cloud provider is not allowed to create load balancers. All worker machines join its backend pool.
+	// It cannot be added or removed after cluster creation.
+	// +optional
+	NodeInternalLB *NodeInternalLBSpec `json:"nodeInternalLB,omitempty"`
+
+	// APIServerDNSRecord registers the public IP of the API server load balancer in an existing Azure DNS zone.
+	// When set, the control plane endpoint is the FQDN of the record instead of the FQDN of the public IP.
+	// It can only be used with a public API server load balancer and cannot be changed after cluster creation.
+	// +optional
+	APIServerDNSRecord *DNSRecordSpec `json:"apiServerDNSRecord,omitempty"`
+
+	// Gateway reserves the GatewaySubnet of the virtual network for a VPN or ExpressRoute gateway, and optionally
+	// creates the gateway. No network security group is associated with the GatewaySubnet, as Azure requires.
+	// +optional
+	Gateway *GatewaySpec `json:"gateway,omitempty"`
+
+	// FlowLogs enables NSG flow logs on the network security groups created for the subnets of the cluster.
+	// Network security groups referenced with securityGroup.existing are not changed.
+	// +optional
+	FlowLogs *FlowLogsSpec `json:"flowLogs,omitempty"`
+
+	// DiagnosticSettings sends the resource logs of the load balancers, network security groups and public IPs created
+	// for the cluster to a Log Analytics workspace with Azure Monitor diagnostic settings. Network security groups
+	// referenced with securityGroup.existing are not changed. Removing it does not delete the diagnostic settings
+	// already created.
+	// +optional
+	DiagnosticSettings *DiagnosticSettingsSpec `json:"diagnosticSettings,omitempty"`
+
+	// NodeRoutes configures the routes of the node route tables for kubenet-style networking, where the traffic to the
+	// pod CIDR of each node is routed to the node. Defaults to leaving the routes to the cloud provider.
+	// +optional
+	NodeRoutes *NodeRoutesSpec `json:"nodeRoutes,omitempty"`
+
+	// EgressProxy opens the port of a proxy for the egress traffic of the API server running on the control plane
+	// machines, such as the konnectivity server, to the proxy agents running on the nodes: a rule of the API server load
+	// balancer forwards the port to the control plane machines, and a security rule of the control plane subnet allows
+	// it. It can be added after cluster creation, but not changed or removed.
+	// +optional
+	EgressProxy *EgressProxySpec `json:"egressProxy,omitempty"`
+
+	// ControlPlaneExtraRules are additional security rules of the network security group of the control plane subnet,
+	// such as a rule allowing the etcd ports 2379-2380 only from the control plane subnet. A rule without a priority
+	// gets the first priority of its direction left unused from 2300, and a rule without a source matches the CIDR
+	// blocks of the control plane subnet, with one rule per CIDR block. The rules are removed from the network
+	// security group when they are removed from the list.
+	// +optional
+	ControlPlaneExtraRules SecurityRules `json:"controlPlaneExtraRules,omitempty"`
+
 	NetworkClassSpec `json:",inline"`
 }
 
+// NodeRoutesMode defines how the routes to the pod CIDRs of the nodes are managed.
+// +kubebuilder:validation:Enum=CloudProvider;Managed
+type NodeRoutesMode string
+
+const (
+	// NodeRoutesModeCloudProvider leaves the routes to cloud-provider-azure, which manages them in the route table of the
+	// node subnets when it runs with --configure-cloud-routes.
+	NodeRoutesModeCloudProvider NodeRoutesMode = "CloudProvider"
+	// NodeRoutesModeManaged adds a route to the pod CIDR of the node of each AzureMachine to the route tables of the node
+	// subnets, and removes it when the AzureMachine is deleted.
+	NodeRoutesModeManaged NodeRoutesMode = "Managed"
+)
+
+// NodeRoutesSpec configures the routes to the pod CIDRs of the nodes.
+type NodeRoutesSpec struct {
+	// Mode defines how the routes to the pod CIDRs of the nodes are managed. With Managed, cloud-provider-azure must run
+	// with --configure-cloud-routes=false.
+	// +kubebuilder:default=CloudProvider
+	// +optional
+	Mode NodeRoutesMode `json:"mode,omitempty"`
+}
+
+// EgressProxySpec configures the access to the egress proxy of the API server on the control plane machines.
+type EgressProxySpec struct {
+	// Port is the port the proxy agents connect to, on both the API server load balancer and the control plane
+	// machines. Defaults to 8132, the agent port of the konnectivity server.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65534
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// FlowLogsSpec configures the NSG flow logs of the network security groups of a cluster.
+type FlowLogsSpec struct {
+	// StorageAccountID is the resource ID of the storage account the flow logs are written to.
+	// The storage account must be in the location of the cluster.
+	StorageAccountID string `json:"storageAccountID"`
+
+	// RetentionDays is the number of days the flow logs are kept in the storage account.
+	// When 0 or unset, the flow logs are kept indefinitely.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=365
+	// +optional
+	RetentionDays int32 `json:"retentionDays,omitempty"`
+
+	// TrafficAnalytics sends the flow logs to a Log Analytics workspace for traffic analytics.
+	// +optional
+	TrafficAnalytics *TrafficAnalyticsSpec `json:"trafficAnalytics,omitempty"`
+
+	// NetworkWatcher is the network watcher the flow logs are created in. It must be in the location of the cluster.
+	// Defaults to the network watcher Azure creates for the location of the cluster, NetworkWatcher_<location> in the
+	// NetworkWatcherRG resource group.
+	// +optional
+	NetworkWatcher NetworkWatcherReference `json:"networkWatcher,omitempty"`
+}
+
+// DiagnosticLogCategory is an Azure Monitor resource log category of a load balancer, network security group or public IP.
+// +kubebuilder:validation:Enum=LoadBalancerHealthEvent;NetworkSecurityGroupEvent;NetworkSecurityGroupRuleCounter;DDoSProtectionNotifications;DDoSMitigationFlowLogs;DDoSMitigationReports
+type DiagnosticLogCategory string
+
+const (
+	// LoadBalancerHealthEventCategory is the category of the health events of a load balancer.
+	LoadBalancerHealthEventCategory DiagnosticLogCategory = "LoadBalancerHealthEvent"
+	// NetworkSecurityGroupEventCategory is the category of the rules of a network security group applied to VMs.
+	NetworkSecurityGroupEventCategory DiagnosticLogCategory = "NetworkSecurityGroupEvent"
+	// NetworkSecurityGroupRuleCounterCategory is the category of the number of times each rule of a network security
+	// group is applied.
+	NetworkSecurityGroupRuleCounterCategory DiagnosticLogCategory = "NetworkSecurityGroupRuleCounter"
+	// DDoSProtectionNotificationsCategory is the category of the DDoS attack notifications of a public IP.
+	DDoSProtectionNotificationsCategory DiagnosticLogCategory = "DDoSProtectionNotifications"
+	// DDoSMitigationFlowLogsCategory is the category of the flow logs of a public IP during a DDoS mitigation.
+	DDoSMitigationFlowLogsCategory DiagnosticLogCategory = "DDoSMitigationFlowLogs"
+	// DDoSMitigationReportsCategory is the category of the DDoS mitigation reports of a public IP.
+	DDoSMitigationReportsCategory DiagnosticLogCategory = "DDoSMitigationReports"
+)
+
+// DiagnosticSettingsSpec configures the Azure Monitor diagnostic settings of the network resources of a cluster.
+type DiagnosticSettingsSpec struct {
+	// WorkspaceResourceID is the resource ID of the Log Analytics workspace the resource logs are sent to.
+	WorkspaceResourceID string `json:"workspaceResourceID"`
+
+	// Categories are the resource log categories collected. Each resource only collects the categories of its type:
+	// LoadBalancerHealthEvent for load balancers, NetworkSecurityGroupEvent and NetworkSecurityGroupRuleCounter for
+	// network security groups, DDoSProtectionNotifications, DDoSMitigationFlowLogs and DDoSMitigationReports for
+	// public IPs. Resources without any of the categories get no diagnostic setting. Defaults to all the categories.
+	// +optional
+	Categories []DiagnosticLogCategory `json:"categories,omitempty"`
+}
+
+// NetworkWatcherReference references an existing network watcher.
+type NetworkWatcherReference struct {
+	// Name is the name of the network watcher.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// ResourceGroup is the resource group of the network watcher.
+	// +optional
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+}
+
+// TrafficAnalyticsSpec configures traffic analytics for NSG flow logs.
+type TrafficAnalyticsSpec struct {
+	// WorkspaceResourceID is the resource ID of the Log Analytics workspace.
+	WorkspaceResourceID string `json:"workspaceResourceID"`
+
+	// WorkspaceID is the workspace ID (a GUID) of the Log Analytics workspace.
+	WorkspaceID string `json:"workspaceID"`
+
+	// WorkspaceRegion is the location of the Log Analytics workspace. Defaults to the location of the cluster.
+	// +optional
+	WorkspaceRegion string `json:"workspaceRegion,omitempty"`
+
+	// IntervalInMinutes is how often the flow logs are processed by traffic analytics. Defaults to 60.
+	// +kubebuilder:validation:Enum=10;60
+	// +optional
+	IntervalInMinutes int32 `json:"intervalInMinutes,omitempty"`
+}
+
+// GatewaySpec configures the GatewaySubnet of a virtual network and the virtual network gateway deployed in it.
+type GatewaySpec struct {
+	// CIDRBlocks are the address prefixes of the GatewaySubnet. Defaults to the /27 preceding the default Azure
+	// Bastion subnet at the end of the first IPv4 CIDR block of the virtual network.
+	// +optional
+	CIDRBlocks []string `json:"cidrBlocks,omitempty"`
+
+	// VirtualNetworkGateway creates a virtual network gateway in the GatewaySubnet. When nil, the subnet is only
+	// reserved for a gateway managed outside of the cluster.
+	// +optional
+	VirtualNetworkGateway *VirtualNetworkGatewaySpec `json:"virtualNetworkGateway,omitempty"`
+}
+
+// VirtualNetworkGatewayType is the type of a virtual network gateway.
+type VirtualNetworkGatewayType string
+
+const (
+	// VirtualNetworkGatewayTypeVpn is a VPN gateway.
+	VirtualNetworkGatewayTypeVpn VirtualNetworkGatewayType = "Vpn"
+	// VirtualNetworkGatewayTypeExpressRoute is an ExpressRoute gateway.
+	VirtualNetworkGatewayTypeExpressRoute VirtualNetworkGatewayType = "ExpressRoute"
+)
+
+// VirtualNetworkGatewaySpec configures a virtual network gateway.
+type VirtualNetworkGatewaySpec struct {
+	// Name is the name of the gateway. Defaults to <cluster name>-vnet-gateway.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Type is the type of the gateway, either Vpn or ExpressRoute. It cannot be changed once the gateway is created.
+	// +kubebuilder:validation:Enum=Vpn;ExpressRoute
+	Type VirtualNetworkGatewayType `json:"type"`
+
+	// SKU is the SKU of the gateway, e.g. VpnGw2AZ or ErGw2AZ. Defaults to VpnGw1AZ for VPN gateways and ErGw1AZ for
+	// ExpressRoute gateways.
+	// +optional
+	SKU string `json:"sku,omitempty"`
+
+	// PublicIP is the public IP created for the gateway. Its name defaults to <cluster name>-vnet-gateway-pip.
+	// +optional
+	PublicIP PublicIPSpec `json:"publicIP,omitempty"`
+}
+
+// PublicNetworkAccess is the policy for the public endpoints of a cluster.
+type PublicNetworkAccess string
+
+const (
+	// PublicNetworkAccessEnabled allows the cluster to create public IPs.
+	PublicNetworkAccessEnabled PublicNetworkAccess = "Enabled"
+	// PublicNetworkAccessDisabled rejects any configuration of the cluster creating a public IP.
+	PublicNetworkAccessDisabled PublicNetworkAccess = "Disabled"
+)
+
+// ApplicationGatewaySpec configures an application gateway for the Application Gateway Ingress Controller.
+type ApplicationGatewaySpec struct {
+	// Name is the name of the application gateway. Defaults to <cluster name>-appgw.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// SubnetName is the name of the subnet of the application gateway, which cannot contain any other resource.
+	// Defaults to <cluster name>-appgw-subnet.
+	// +optional
+	SubnetName string `json:"subnetName,omitempty"`
+
+	// CIDRBlocks are the address prefixes of the subnet of the application gateway. Defaults to the /26 preceding the
+	// default GatewaySubnet at the end of the first IPv4 CIDR block of the virtual network.
+	// +optional
+	CIDRBlocks []string `json:"cidrBlocks,omitempty"`
+
+	// PublicIP is the public IP created for the frontend of the application gateway. Its name defaults to
+	// <cluster name>-appgw-pip.
+	// +optional
+	PublicIP PublicIPSpec `json:"publicIP,omitempty"`
+
+	// MinCapacity is the minimum number of instances the application gateway autoscales to. Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=125
+	// +optional
+	MinCapacity int32 `json:"minCapacity,omitempty"`
+
+	// MaxCapacity is the maximum number of instances the application gateway autoscales to. When not set, it is
+	// chosen by Azure. It must not be lower than MinCapacity.
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=125
+	// +optional
+	MaxCapacity *int32 `json:"maxCapacity,omitempty"`
+
+	// WAF enables the web application firewall of the application gateway, which then uses the WAF_v2 SKU instead of
+	// Standard_v2.
+	// +optional
+	WAF *WebApplicationFirewallSpec `json:"waf,omitempty"`
+}
+
+// WebApplicationFirewallMode is the mode of a web application firewall.
+type WebApplicationFirewallMode string
+
+const (
+	// WebApplicationFirewallModeDetection logs the requests matching the firewall rules.
+	WebApplicationFirewallModeDetection WebApplicationFirewallMode = "Detection"
+	// WebApplicationFirewallModePrevention blocks the requests matching the firewall rules.
+	WebApplicationFirewallModePrevention WebApplicationFirewallMode = "Prevention"
+)
+
+// WebApplicationFirewallSpec configures the web application firewall of an application gateway.
+type WebApplicationFirewallSpec struct {
+	// Mode is the mode of the firewall, either Detection or Prevention. Defaults to Detection.
+	// +kubebuilder:validation:Enum=Detection;Prevention
+	// +optional
+	Mode WebApplicationFirewallMode `json:"mode,omitempty"`
+
+	// RuleSetVersion is the version of the OWASP core rule set of the firewall. Defaults to 3.2.
+	// +optional
+	RuleSetVersion string `json:"ruleSetVersion,omitempty"`
+}
+
+// SecondaryRegionSpec configures the network pre-provisioned in a secondary region of a cluster.
+type SecondaryRegionSpec struct {
+	// Location is the Azure region, which must differ from the location of the cluster.
+	Location string `json:"location"`
+
+	// VnetName is the name of the virtual network of the region. Defaults to <cluster name>-vnet-<location>.
+	// +optional
+	VnetName string `json:"vnetName,omitempty"`
+
+	// CIDRBlocks are the address prefixes of the virtual network of the region, which must not overlap the virtual
+	// network of the cluster or of any other secondary region.
+	// +kubebuilder:validation:MinItems=1
+	CIDRBlocks []string `json:"cidrBlocks"`
+
+	// Subnets are the subnets of the virtual network of the region. Defaults to a single node subnet named
+	// <cluster name>-node-subnet-<location> spanning the first CIDR block of the virtual network.
+	// +optional
+	Subnets []SecondaryRegionSubnetSpec `json:"subnets,omitempty"`
+}
+
+// SecondaryRegionSubnetSpec configures a subnet of the virtual network of a secondary region.
+type SecondaryRegionSubnetSpec struct {
+	// Name is the name of the subnet, which must be unique across all the subnets of the cluster.
+	Name string `json:"name"`
+
+	// CIDRBlocks are the address prefixes of the subnet, within the CIDR blocks of the virtual network of the region.
+	// +kubebuilder:validation:MinItems=1
+	CIDRBlocks []string `json:"cidrBlocks"`
+
+	// SecurityGroupName is the name of the network security group created for the subnet. Defaults to
+	// <subnet name>-nsg.
+	// +optional
+	SecurityGroupName string `json:"securityGroupName,omitempty"`
+
+	// SecurityRules are the rules of the network security group of the subnet.
+	// +optional
+	SecurityRules SecurityRules `json:"securityRules,omitempty"`
+}
+
+// DNSRecordSpec defines an alias record set in an existing Azure DNS zone.
+type DNSRecordSpec struct {
+	// ZoneName is the name of the existing Azure DNS zone, e.g. "example.com".
+	ZoneName string `json:"zoneName"`
+
+	// ZoneResourceGroup is the resource group of the DNS zone. Defaults to the cluster resource group.
+	// +optional
+	ZoneResourceGroup string `json:"zoneResourceGroup,omitempty"`
+
+	// RecordName is the name of the record set relative to the zone. Defaults to the cluster name.
+	// +optional
+	RecordName string `json:"recordName,omitempty"`
+
+	// TTL is the time-to-live of the record set, in seconds. Defaults to 300.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTL *int64 `json:"ttl,omitempty"`
+}
+
 // VnetSpec configures an Azure virtual network.
 type VnetSpec struct {
 	// ResourceGroup is the name of the resource group of the existing virtual network
@@ -131,9 +485,33 @@ type VnetSpec struct {
 	// +optional
 	Peerings VnetPeerings `json:"peerings,omitempty"`
 
+	// DDoSProtectionPlan enables DDoS Protection Standard on the virtual network with an existing or a new DDoS
+	// protection plan. It is ignored for a pre-existing virtual network.
+	// +optional
+	DDoSProtectionPlan *DDoSProtectionPlanSpec `json:"ddosProtectionPlan,omitempty"`
+
+	// Shared allows several AzureClusters to use the same managed virtual network. Every cluster using it tags the
+	// virtual network and its security groups as owned, and they are only deleted along with the last of these
+	// clusters. Each cluster should use its own subnets.
+	// +optional
+	Shared bool `json:"shared,omitempty"`
+
 	VnetClassSpec `json:",inline"`
 }
 
+// DDoSProtectionPlanSpec references the DDoS protection plan of a virtual network.
+type DDoSProtectionPlanSpec struct {
+	// ID is the resource ID of an existing DDoS protection plan. When empty, a DDoS protection plan is created in the
+	// resource group of the virtual network and deleted with it.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name is the name of the DDoS protection plan created for the virtual network. It cannot be set with ID.
+	// Defaults to <cluster-name>-ddos-protection-plan when ID is empty.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
 // VnetPeeringSpec specifies an existing remote virtual network to peer with the AzureCluster's virtual network.
 type VnetPeeringSpec struct {
 	VnetPeeringClassSpec `json:",inline"`
@@ -215,9 +593,57 @@ type SecurityGroup struct {
 	ID   string `json:"id,omitempty"`
 	Name string `json:"name"`
 
+	// Existing references a pre-existing network security group to associate with the subnet instead of creating one,
+	// e.g. an NSG owned by a security team. Such an NSG is never created, deleted or retagged, and the rules not added
+	// by CAPZ are never modified. The name defaults to the name of the existing NSG.
+	// +optional
+	Existing *ExistingSecurityGroup `json:"existing,omitempty"`
+
 	SecurityGroupClass `json:",inline"`
 }
 
+// SecurityRuleManagement defines how the security rules of an existing network security group are managed.
+type SecurityRuleManagement string
+
+const (
+	// SecurityRuleManagementAppendOnly adds the security rules CAPZ requires to the NSG, marking their description
+	// as managed by CAPZ, and only ever removes those rules.
+	SecurityRuleManagementAppendOnly SecurityRuleManagement = "AppendOnly"
+	// SecurityRuleManagementNone leaves the security rules of the NSG alone.
+	SecurityRuleManagementNone SecurityRuleManagement = "None"
+)
+
+// ExistingSecurityGroup references a network security group whose lifecycle is not managed by CAPZ.
+type ExistingSecurityGroup struct {
+	// ID is the Azure resource ID of the network security group. It must be in the subscription of the cluster.
+	ID string `json:"id"`
+
+	// RuleManagement defines how the security rules of the NSG are managed: AppendOnly adds the rules required by the
+	// cluster without touching the others, and None leaves the rules alone. Defaults to AppendOnly.
+	// +kubebuilder:validation:Enum=AppendOnly;None
+	// +kubebuilder:default=AppendOnly
+	// +optional
+	RuleManagement SecurityRuleManagement `json:"ruleManagement,omitempty"`
+}
+
+// ExistingID returns the ID of the existing network security group referenced by the security group, if any.
+func (sg SecurityGroup) ExistingID() string {
+	if sg.Existing == nil {
+		return ""
+	}
+	return sg.Existing.ID
+}
+
+// ExistingResourceGroup returns the resource group of the existing network security group referenced by the security
+// group, or "" if there is none or its ID is invalid.
+func (sg SecurityGroup) ExistingResourceGroup() string {
+	resourceID, err := azureutil.ParseResourceID(sg.ExistingID())
+	if err != nil {
+		return ""
+	}
+	return resourceID.ResourceGroupName
+}
+
 // RouteTable defines an Azure route table.
 type RouteTable struct {
 	// ID is the Azure resource ID of the route table.
@@ -257,8 +683,13 @@ const (
 	SecurityGroupProtocolUDP = SecurityGroupProtocol("Udp")
 	// SecurityGroupProtocolICMP represents the ICMP protocol.
 	SecurityGroupProtocolICMP = SecurityGroupProtocol("Icmp")
+	// SecurityGroupProtocolICMPv6 represents the ICMPv6 protocol.
+	SecurityGroupProtocolICMPv6 = SecurityGroupProtocol("Icmpv6")
 )
 
+// IPv6AnyAddressPrefix is the address prefix matching any IPv6 address in a security rule.
+const IPv6AnyAddressPrefix = "::/0"
+
 // SecurityRuleDirection defines the direction type for a security group rule.
 type SecurityRuleDirection string
 
@@ -287,8 +718,8 @@ type SecurityRule struct {
 	Name string `json:"name"`
 	// A description for this rule. Restricted to 140 chars.
 	Description string `json:"description"`
-	// Protocol specifies the protocol type. "Tcp", "Udp", "Icmp", or "*".
-	// +kubebuilder:validation:Enum=Tcp;Udp;Icmp;*
+	// Protocol specifies the protocol type. "Tcp", "Udp", "Icmp", "Icmpv6", or "*".
+	// +kubebuilder:validation:Enum=Tcp;Udp;Icmp;Icmpv6;*
 	Protocol SecurityGroupProtocol `json:"protocol"`
 	// Direction indicates whether the rule applies to inbound, or outbound traffic. "Inbound" or "Outbound".
 	// +kubebuilder:validation:Enum=Inbound;Outbound
@@ -340,12 +771,46 @@ type LoadBalancerSpec struct {
 	LoadBalancerClassSpec `json:",inline"`
 }
 
+// NodeInternalLBSpec defines an internal load balancer of the worker nodes.
+type NodeInternalLBSpec struct {
+	LoadBalancerSpec `json:",inline"`
+
+	// SubnetName is the name of the node subnet of the load balancer frontend IP. Defaults to the first node subnet.
+	// +optional
+	SubnetName string `json:"subnetName,omitempty"`
+}
+
 // SKU defines an Azure load balancer SKU.
 type SKU string
 
 const (
 	// SKUStandard is the value for the Azure load balancer Standard SKU.
 	SKUStandard = SKU("Standard")
+	// SKUBasic is the value for the Azure load balancer Basic SKU. It is only accepted on the API server load balancer of
+	// existing clusters already using it, which can be migrated to the Standard SKU by changing it.
+	SKUBasic = SKU("Basic")
+)
+
+// LoadBalancerProbeProtocol defines the protocol of a load balancer health probe.
+type LoadBalancerProbeProtocol string
+
+const (
+	// LoadBalancerProbeProtocolTCP probes backends by opening a TCP connection.
+	LoadBalancerProbeProtocolTCP = LoadBalancerProbeProtocol("Tcp")
+	// LoadBalancerProbeProtocolHTTP probes backends with an HTTP GET request.
+	LoadBalancerProbeProtocolHTTP = LoadBalancerProbeProtocol("Http")
+	// LoadBalancerProbeProtocolHTTPS probes backends with an HTTPS GET request.
+	LoadBalancerProbeProtocolHTTPS = LoadBalancerProbeProtocol("Https")
+)
+
+// LoadBalancingRuleProtocol defines the transport protocol of a load balancing rule.
+type LoadBalancingRuleProtocol string
+
+const (
+	// LoadBalancingRuleProtocolTCP is the TCP transport protocol.
+	LoadBalancingRuleProtocolTCP = LoadBalancingRuleProtocol("Tcp")
+	// LoadBalancingRuleProtocolUDP is the UDP transport protocol.
+	LoadBalancingRuleProtocolUDP = LoadBalancingRuleProtocol("Udp")
 )
 
 // LBType defines an Azure load balancer Type.
@@ -364,6 +829,16 @@ type FrontendIP struct {
 	Name string `json:"name"`
 	// +optional
 	PublicIP *PublicIPSpec `json:"publicIP,omitempty"`
+	// Zones pins the frontend IP of an internal load balancer to availability zones, e.g. to give each zone of a
+	// regional control plane its own frontend IP. Frontend IPs of internal load balancers are zone-redundant by default.
+	// Only supported on internal load balancers, and cannot be changed once the frontend IP is created.
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+	// SubnetName is the name of the subnet of the frontend IP of an internal load balancer. Defaults to the subnet of the
+	// load balancer, e.g. the control plane subnet for the API server load balancer.
+	// Only supported on internal load balancers.
+	// +optional
+	SubnetName string `json:"subnetName,omitempty"`
 
 	FrontendIPClass `json:",inline"`
 }
@@ -375,6 +850,23 @@ type PublicIPSpec struct {
 	DNSName string `json:"dnsName,omitempty"`
 	// +optional
 	IPTags []IPTag `json:"ipTags,omitempty"`
+	// Existing references a public IP created outside of CAPZ to attach instead of creating a new one.
+	// CAPZ never updates or deletes an existing public IP.
+	// Only supported for the first frontend IP of a public API server load balancer.
+	// +optional
+	Existing *ExistingPublicIP `json:"existing,omitempty"`
+}
+
+// ExistingPublicIP references a public IP that is not managed by CAPZ. One of ID and DNSLabel must be set.
+type ExistingPublicIP struct {
+	// ID is the resource ID of the public IP. It must be in the subscription of the cluster but not in its resource group.
+	// ID takes precedence over DNSLabel.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// DNSLabel is the domain name label of a public IP in the subscription and location of the cluster.
+	// It is resolved to ID on the first reconciliation.
+	// +optional
+	DNSLabel string `json:"dnsLabel,omitempty"`
 }
 
 // IPTag contains the IpTag associated with the object.
@@ -412,6 +904,29 @@ const (
 	Deleted ProvisioningState = "Deleted"
 )
 
+// VMPowerState describes the power state of an Azure virtual machine.
+type VMPowerState string
+
+const (
+	// VMPowerStateStarting means the VM is being started.
+	VMPowerStateStarting VMPowerState = "Starting"
+	// VMPowerStateRunning means the VM is running.
+	VMPowerStateRunning VMPowerState = "Running"
+	// VMPowerStateStopping means the VM is being stopped.
+	VMPowerStateStopping VMPowerState = "Stopping"
+	// VMPowerStateStopped means the VM is stopped but still allocated, so its compute resources are still billed.
+	VMPowerStateStopped VMPowerState = "Stopped"
+	// VMPowerStateDeallocating means the VM is being deallocated.
+	VMPowerStateDeallocating VMPowerState = "Deallocating"
+	// VMPowerStateDeallocated means the VM is stopped and its compute resources are released.
+	VMPowerStateDeallocated VMPowerState = "Deallocated"
+	// VMPowerStateHibernated means the VM is deallocated and the content of its memory is kept on its OS disk, to be
+	// restored when the VM is started again.
+	VMPowerStateHibernated VMPowerState = "Hibernated"
+	// VMPowerStateUnknown means the power state of the VM is not known.
+	VMPowerStateUnknown VMPowerState = "Unknown"
+)
+
 // Image defines information about the image to use for VM creation.
 // There are three ways to specify an image: by ID, Marketplace Image or SharedImageGallery
 // One of ID, SharedImage or Marketplace should be set.
@@ -447,6 +962,9 @@ type AzureComputeGalleryImage struct {
 	// Specify 'latest' to use the latest version of an image available at deploy time.
 	// Even if you use 'latest', the VM image will not automatically update after deploy
 	// time even if a new version becomes available.
+	// AzureMachinePools also accept Major.Minor.latest to use the latest published build
+	// of a Major.Minor version, which is resolved by the controller and recorded in the
+	// AzureMachinePool status.
 	// +kubebuilder:validation:MinLength=1
 	Version string `json:"version"`
 	// SubscriptionID is the identifier of the subscription that contains the private compute gallery.
@@ -460,6 +978,58 @@ type AzureComputeGalleryImage struct {
 	Plan *ImagePlan `json:"plan,omitempty"`
 }
 
+// majorMinorRegex matches a Major.Minor version prefix.
+var majorMinorRegex = regexp.MustCompile(`^\d+\.\d+$`)
+
+// LatestVersionPrefix returns the Major.Minor prefix of a Major.Minor.latest version,
+// and false if the version does not use that format.
+func (i *AzureComputeGalleryImage) LatestVersionPrefix() (string, bool) {
+	prefix, ok := strings.CutSuffix(i.Version, ".latest")
+	if !ok || !majorMinorRegex.MatchString(prefix) {
+		return "", false
+	}
+	return prefix, true
+}
+
+// ImageVariant is a hardened variant of the image of a machine.
+// +kubebuilder:validation:Enum=FIPS;CIS
+type ImageVariant string
+
+const (
+	// ImageVariantFIPS is an image using FIPS 140 validated cryptographic modules.
+	ImageVariantFIPS ImageVariant = "FIPS"
+	// ImageVariantCIS is an image hardened following the CIS benchmarks.
+	ImageVariantCIS ImageVariant = "CIS"
+)
+
+// DefaultImageLookupFormat is the default format of the names of the image definitions looked up in a compute gallery.
+const DefaultImageLookupFormat = "capi-{{.BaseOS}}{{if .Variant}}-{{.Variant}}{{end}}"
+
+// ImageLookup defines a compute gallery in which the image of a machine is looked up by Kubernetes version.
+// The image definition is named after Format, and its version is the Kubernetes version of the machine,
+// e.g. capi-ubuntu-2204-fips/versions/1.28.3.
+type ImageLookup struct {
+	// Gallery is the name of the compute gallery that contains the images.
+	// +kubebuilder:validation:MinLength=1
+	Gallery string `json:"gallery"`
+	// SubscriptionID is the identifier of the subscription that contains the private compute gallery.
+	// The gallery is used as a community gallery when it is not set.
+	// +optional
+	SubscriptionID *string `json:"subscriptionID,omitempty"`
+	// ResourceGroup specifies the resource group containing the private compute gallery.
+	// +optional
+	ResourceGroup *string `json:"resourceGroup,omitempty"`
+	// Format is a Go template of the name of the image definition, which can use the fields .BaseOS, .Variant (the
+	// lower-cased image variant, or empty) and .K8sVersion (e.g. 1.28.3).
+	// Defaults to "capi-{{.BaseOS}}{{if .Variant}}-{{.Variant}}{{end}}".
+	// +optional
+	Format string `json:"format,omitempty"`
+	// BaseOS is the operating system of the image, e.g. ubuntu-2204. Defaults to the Ubuntu version of the default
+	// images of the Kubernetes version of the machine, and is required for Windows machines.
+	// +optional
+	BaseOS string `json:"baseOS,omitempty"`
+}
+
 // ImagePlan contains plan information for marketplace images.
 type ImagePlan struct {
 	// Publisher is the name of the organization that created the image
@@ -498,6 +1068,12 @@ type AzureSharedGalleryImage struct {
 	// SubscriptionID is the identifier of the subscription that contains the shared image gallery
 	// +kubebuilder:validation:MinLength=1
 	SubscriptionID string `json:"subscriptionID"`
+	// TenantID is the identifier of the tenant that contains the shared image gallery, when it is not the tenant of
+	// the cluster identity. Virtual machines are then created with an auxiliary token for that tenant, which requires
+	// the identity to be a multi-tenant application using a client secret or workload identity, with read access to
+	// the gallery.
+	// +optional
+	TenantID *string `json:"tenantID,omitempty"`
 	// ResourceGroup specifies the resource group containing the shared image gallery
 	// +kubebuilder:validation:MinLength=1
 	ResourceGroup string `json:"resourceGroup"`
@@ -613,6 +1189,60 @@ type OSDisk struct {
 	// +optional
 	// +kubebuilder:validation:Enum=None;ReadOnly;ReadWrite
 	CachingType string `json:"cachingType,omitempty"`
+	// WriteAcceleratorEnabled specifies whether write accelerator should be enabled on the disk. Write accelerator is
+	// only supported on M-series VM sizes for Premium SSD disks with a caching type of None or ReadOnly.
+	// +optional
+	WriteAcceleratorEnabled *bool `json:"writeAcceleratorEnabled,omitempty"`
+	// ZoneRedundant specifies whether the OS disk is zone-redundant (ZRS), in which case it is replicated synchronously
+	// across the availability zones of the region instead of being created in the zone of its VM, so that it survives
+	// the failure of that zone and can be attached to a VM in another zone. The storage account type of the disk is
+	// switched to its zone-redundant variant, which is only available for Premium SSD and Standard SSD disks in regions
+	// with availability zones. Not supported with ephemeral OS disks.
+	// +optional
+	ZoneRedundant *bool `json:"zoneRedundant,omitempty"`
+	// Source specifies a snapshot or a disk restore point to create the OS disk from, instead of the image of the
+	// machine. It allows cloning pre-warmed machines, e.g. with container images already pulled. The OS disk is created
+	// before the VM and attached to it, so the bootstrap data is passed to the VM as user data, which requires
+	// cloud-init 22.1 or later in the source disk. Only supported by AzureMachines.
+	// +optional
+	Source *OSDiskSource `json:"source,omitempty"`
+}
+
+// zoneRedundantStorageAccountTypes maps the storage account types of managed disks with a zone-redundant variant to it.
+var zoneRedundantStorageAccountTypes = map[string]string{
+	"Premium_LRS":     "Premium_ZRS",
+	"StandardSSD_LRS": "StandardSSD_ZRS",
+	"Premium_ZRS":     "Premium_ZRS",
+	"StandardSSD_ZRS": "StandardSSD_ZRS",
+}
+
+// StorageAccountType returns the storage account type of the managed OS disk, switched to its zone-redundant variant
+// when the disk is zone-redundant.
+func (d OSDisk) StorageAccountType() string {
+	if d.ManagedDisk == nil {
+		return ""
+	}
+	if ptr.Deref(d.ZoneRedundant, false) {
+		if zrs, ok := zoneRedundantStorageAccountTypes[d.ManagedDisk.StorageAccountType]; ok {
+			return zrs
+		}
+	}
+	return d.ManagedDisk.StorageAccountType
+}
+
+// IsZoneRedundant returns true if the OS disk is replicated across zones rather than created in the zone of its VM.
+func (d OSDisk) IsZoneRedundant() bool {
+	return strings.HasSuffix(d.StorageAccountType(), "_ZRS")
+}
+
+// OSDiskSource specifies the source of an OS disk. Exactly one of SnapshotID and RestorePointID must be set.
+type OSDiskSource struct {
+	// SnapshotID is the resource ID of the managed disk snapshot to copy.
+	// +optional
+	SnapshotID *string `json:"snapshotID,omitempty"`
+	// RestorePointID is the resource ID of the disk restore point to restore.
+	// +optional
+	RestorePointID *string `json:"restorePointID,omitempty"`
 }
 
 // DataDisk specifies the parameters that are used to add one or more data disks to the machine.
@@ -633,6 +1263,17 @@ type DataDisk struct {
 	// +optional
 	// +kubebuilder:validation:Enum=None;ReadOnly;ReadWrite
 	CachingType string `json:"cachingType,omitempty"`
+	// WriteAcceleratorEnabled specifies whether write accelerator should be enabled on the disk. Write accelerator is
+	// only supported on M-series VM sizes for Premium SSD disks with a caching type of None or ReadOnly.
+	// +optional
+	WriteAcceleratorEnabled *bool `json:"writeAcceleratorEnabled,omitempty"`
+	// Etcd designates the data disk as the etcd data disk of a control plane machine. The disk is partitioned,
+	// formatted and mounted at /var/lib/etcddisk by cloud-init before the bootstrap commands run, so the etcd data
+	// directory of the control plane should be set to a directory of this mount point, e.g. /var/lib/etcddisk/etcd.
+	// Only one data disk of a machine can be designated as the etcd data disk, and it requires a Linux machine with
+	// cloud-config bootstrap data.
+	// +optional
+	Etcd *bool `json:"etcd,omitempty"`
 }
 
 // VMExtension specifies the parameters for a custom VM extension.
@@ -661,6 +1302,16 @@ type ManagedDiskParameters struct {
 	// SecurityProfile specifies the security profile for the managed disk.
 	// +optional
 	SecurityProfile *VMDiskSecurityProfile `json:"securityProfile,omitempty"`
+	// DiskIOPSReadWrite is the number of read-write IOPS of a PremiumV2_LRS or UltraSSD_LRS data disk. Defaults to the
+	// baseline performance of the disk size. It can be changed after the disk is created.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DiskIOPSReadWrite *int64 `json:"diskIOPSReadWrite,omitempty"`
+	// DiskMBpsReadWrite is the read-write throughput in MB per second of a PremiumV2_LRS or UltraSSD_LRS data disk.
+	// Defaults to the baseline performance of the disk size. It can be changed after the disk is created.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DiskMBpsReadWrite *int64 `json:"diskMBpsReadWrite,omitempty"`
 }
 
 // VMDiskSecurityProfile specifies the security profile settings for the managed disk.
@@ -731,6 +1382,17 @@ type SubnetSpec struct {
 	// +optional
 	NatGateway NatGateway `json:"natGateway,omitempty"`
 
+	// AdditionalTags is an optional set of tags to add to the network security group and route table of this subnet, in
+	// addition to the cluster's AdditionalTags. Tags with the same key override the cluster's AdditionalTags.
+	// +optional
+	AdditionalTags Tags `json:"additionalTags,omitempty"`
+
+	// CIDRBlockFromPool references an IP address pool implementing the Cluster API IPAM contract, such as an
+	// InClusterIPPool, from which the address space of the subnet is claimed instead of being defaulted. The subnet
+	// is the network of the claimed address and its prefix. The pool is only claimed from while CIDRBlocks is empty.
+	// +optional
+	CIDRBlockFromPool *corev1.TypedLocalObjectReference `json:"cidrBlockFromPool,omitempty"`
+
 	SubnetClassSpec `json:",inline"`
 }
 
@@ -799,6 +1461,13 @@ type NetworkInterface struct {
 	// +kubebuilder:validation:nullable
 	// +optional
 	AcceleratedNetworking *bool `json:"acceleratedNetworking,omitempty"`
+
+	// AddressesFromPools references IP address pools implementing the Cluster API IPAM contract, from which static
+	// private IP addresses are claimed for the IP configurations of the interface, in order: the address claimed from
+	// the first pool is assigned to the primary IP configuration. IP configurations without a pool are allocated
+	// dynamically by Azure.
+	// +optional
+	AddressesFromPools []corev1.TypedLocalObjectReference `json:"addressesFromPools,omitempty"`
 }
 
 // GetControlPlaneSubnet returns the cluster control plane subnet.
@@ -834,6 +1503,16 @@ func (s SubnetSpec) IsNatGatewayEnabled() bool {
 	return s.NatGateway.Name != ""
 }
 
+// IsIPv6Enabled returns whether or not IPv6 is enabled on the virtual network.
+func (v VnetSpec) IsIPv6Enabled() bool {
+	for _, cidr := range v.CIDRBlocks {
+		if net.IsIPv6CIDRString(cidr) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsIPv6Enabled returns whether or not IPv6 is enabled on the subnet.
 func (s SubnetSpec) IsIPv6Enabled() bool {
 	for _, cidr := range s.CIDRBlocks {
@@ -850,6 +1529,7 @@ type SecurityProfile struct {
 	// This field indicates whether Host Encryption should be enabled
 	// or disabled for a virtual machine or virtual machine scale set.
 	// This should be disabled when SecurityEncryptionType is set to DiskWithVMGuestState.
+	// Enabling it requires the Microsoft.Compute/EncryptionAtHost feature to be registered on the subscription.
 	// Default is disabled.
 	// +optional
 	EncryptionAtHost *bool `json:"encryptionAtHost,omitempty"`
@@ -863,6 +1543,43 @@ type SecurityProfile struct {
 	UefiSettings *UefiSettings `json:"uefiSettings,omitempty"`
 }
 
+// AzureDiskEncryptionVolumeType is the type of the volumes encrypted with Azure Disk Encryption.
+type AzureDiskEncryptionVolumeType string
+
+const (
+	// AzureDiskEncryptionVolumeTypeOS encrypts the OS volume only.
+	AzureDiskEncryptionVolumeTypeOS AzureDiskEncryptionVolumeType = "OS"
+	// AzureDiskEncryptionVolumeTypeData encrypts the data volumes only.
+	AzureDiskEncryptionVolumeTypeData AzureDiskEncryptionVolumeType = "Data"
+	// AzureDiskEncryptionVolumeTypeAll encrypts the OS and data volumes.
+	AzureDiskEncryptionVolumeTypeAll AzureDiskEncryptionVolumeType = "All"
+)
+
+// AzureDiskEncryption specifies the Key Vault settings of the Azure Disk Encryption VM extension. The extension stores
+// the encryption secret of each volume in the Key Vault, wrapped with a Key Vault key.
+type AzureDiskEncryption struct {
+	// KeyVaultID is the resource ID of the Key Vault the disk encryption secrets are stored in. The Key Vault must be
+	// enabled for disk encryption, and be in the same region and subscription as the virtual machine.
+	KeyVaultID string `json:"keyVaultID"`
+
+	// KeyVaultURL is the URL of the Key Vault the disk encryption secrets are stored in, e.g.
+	// https://my-vault.vault.azure.net/.
+	KeyVaultURL string `json:"keyVaultURL"`
+
+	// KeyEncryptionKeyURL is the URL of the Key Vault key wrapping the disk encryption secrets, including its version,
+	// e.g. https://my-vault.vault.azure.net/keys/my-key/0123456789abcdef0123456789abcdef.
+	KeyEncryptionKeyURL string `json:"keyEncryptionKeyURL"`
+
+	// KeyEncryptionKeyVaultID is the resource ID of the Key Vault of the key encryption key. Defaults to KeyVaultID.
+	// +optional
+	KeyEncryptionKeyVaultID string `json:"keyEncryptionKeyVaultID,omitempty"`
+
+	// VolumeType is the type of the volumes to encrypt. Defaults to All.
+	// +kubebuilder:validation:Enum=OS;Data;All
+	// +optional
+	VolumeType AzureDiskEncryptionVolumeType `json:"volumeType,omitempty"`
+}
+
 // UefiSettings specifies the security settings like secure boot and vTPM used while creating the virtual
 // machine.
 // +optional
@@ -891,6 +1608,11 @@ type AddressRecord struct {
 type CloudProviderConfigOverrides struct {
 	// +optional
 	RateLimits []RateLimitSpec `json:"rateLimits,omitempty"`
+	// VMType is the type of nodes the cloud provider manages. "vmss", the default, manages nodes running in both
+	// virtual machine scale sets and standalone virtual machines. "standard" only manages standalone virtual machines.
+	// +kubebuilder:validation:Enum=standard;vmss
+	// +optional
+	VMType string `json:"vmType,omitempty"`
 	// +optional
 	BackOffs BackOffConfig `json:"backOffs,omitempty"`
 }
@@ -1088,3 +1810,280 @@ const (
 	// UniformOrchestrationMode treats VMs as identical instances accessible by the VMSS VM API.
 	UniformOrchestrationMode OrchestrationModeType = "Uniform"
 )
+
+// CostEstimationSpec configures the estimation of the cost of a cluster.
+type CostEstimationSpec struct {
+	// CurrencyCode is the ISO 4217 code of the currency of the estimate, e.g. EUR.
+	// Defaults to USD.
+	// +kubebuilder:validation:Pattern=`^[A-Z]{3}$`
+	// +optional
+	CurrencyCode string `json:"currencyCode,omitempty"`
+}
+
+// CostEstimate is an estimate of the monthly cost of the Azure resources of a cluster, based on their retail
+// pay-as-you-go prices. Discounts, reservations, savings plans and usage-based charges such as bandwidth are not
+// taken into account.
+type CostEstimate struct {
+	// MonthlyCost is the estimated cost of the resources for a month of 730 hours, e.g. "1234.56".
+	MonthlyCost string `json:"monthlyCost"`
+
+	// CurrencyCode is the currency of MonthlyCost.
+	CurrencyCode string `json:"currencyCode"`
+
+	// UnpricedResources lists the resources whose price could not be found. Their cost is not part of MonthlyCost.
+	// +optional
+	UnpricedResources []string `json:"unpricedResources,omitempty"`
+
+	// LastUpdated is the last time the estimate changed.
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// EncryptionPosture summarizes the disk encryption settings of the VMs of a cluster. The managed disks of every VM are
+// encrypted at rest with platform-managed keys, the counts are of the VMs with additional encryption.
+type EncryptionPosture struct {
+	// Machines is the number of VMs of the cluster, counting each replica of its AzureMachinePools.
+	Machines int32 `json:"machines"`
+
+	// CustomerManagedKeys is the number of VMs whose managed OS and data disks are all encrypted at rest with the
+	// customer-managed keys of disk encryption sets (SSE+CMK).
+	CustomerManagedKeys int32 `json:"customerManagedKeys"`
+
+	// EncryptionAtHost is the number of VMs with encryption at host, which encrypts their temporary and ephemeral
+	// disks and the caches of their managed disks.
+	EncryptionAtHost int32 `json:"encryptionAtHost"`
+
+	// AzureDiskEncryption is the number of VMs with the Azure Disk Encryption (ADE) extension, which encrypts their
+	// disks from within the guest operating system.
+	AzureDiskEncryption int32 `json:"azureDiskEncryption"`
+
+	// PlatformManagedKeysOnly lists the AzureMachines and AzureMachinePools whose VMs use none of customer-managed
+	// keys, encryption at host and Azure Disk Encryption, e.g. "AzureMachine/my-cluster-md-0-abcde".
+	// +optional
+	PlatformManagedKeysOnly []string `json:"platformManagedKeysOnly,omitempty"`
+
+	// LastUpdated is the last time the posture changed.
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// PlannedOperationType is the type of an Azure operation recorded in a reconcile plan.
+// +kubebuilder:validation:Enum=Create;Update;Delete
+type PlannedOperationType string
+
+const (
+	// PlannedOperationCreate is the creation of a resource which does not exist yet.
+	PlannedOperationCreate PlannedOperationType = "Create"
+	// PlannedOperationUpdate is the update of an existing resource which differs from its desired state.
+	PlannedOperationUpdate PlannedOperationType = "Update"
+	// PlannedOperationDelete is the deletion of an existing resource.
+	PlannedOperationDelete PlannedOperationType = "Delete"
+)
+
+// PlannedOperation is an Azure operation a reconciliation would perform.
+type PlannedOperation struct {
+	// Type is the type of the operation.
+	Type PlannedOperationType `json:"type"`
+
+	// Service is the name of the CAPZ service which would perform the operation, e.g. "virtualnetworks".
+	Service string `json:"service"`
+
+	// ResourceGroup is the resource group of the resource.
+	// +optional
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+
+	// Name is the name of the resource, or its ID when the resource is not identified by a name.
+	Name string `json:"name"`
+}
+
+// ReconcilePlan is the set of Azure operations a reconciliation would perform, computed without performing them.
+type ReconcilePlan struct {
+	// Operations lists the operations in the order the reconciliation would perform them.
+	// +optional
+	Operations []PlannedOperation `json:"operations,omitempty"`
+
+	// Message explains why the plan is incomplete, e.g. because a service failed to compute its operations.
+	// Operations of services depending on resources which do not exist yet may be missing from the plan.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// GeneratedAt is the time the plan was computed.
+	GeneratedAt metav1.Time `json:"generatedAt"`
+}
+
+// NetworkStatus describes the network resources of a cluster as resolved in Azure by the last successful
+// reconciliation, so that automation such as DNS or firewall management can consume their IDs and addresses without
+// querying Azure.
+type NetworkStatus struct {
+	// Vnet is the virtual network of the cluster.
+	// +optional
+	Vnet *VnetStatus `json:"vnet,omitempty"`
+
+	// Subnets are the subnets of the cluster.
+	// +optional
+	Subnets []SubnetStatus `json:"subnets,omitempty"`
+
+	// LoadBalancers are the load balancers of the cluster.
+	// +optional
+	LoadBalancers []LoadBalancerStatus `json:"loadBalancers,omitempty"`
+
+	// NatGateways are the NAT gateways of the subnets of the cluster.
+	// +optional
+	NatGateways []NatGatewayStatus `json:"natGateways,omitempty"`
+
+	// ApplicationGateway is the application gateway of the cluster, to configure the Application Gateway Ingress
+	// Controller with.
+	// +optional
+	ApplicationGateway *ApplicationGatewayStatus `json:"applicationGateway,omitempty"`
+}
+
+// VnetStatus describes a virtual network.
+type VnetStatus struct {
+	// Name is the name of the virtual network.
+	Name string `json:"name"`
+
+	// ID is the resource ID of the virtual network.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// CIDRBlocks are the address prefixes of the virtual network.
+	// +optional
+	CIDRBlocks []string `json:"cidrBlocks,omitempty"`
+}
+
+// SubnetStatus describes a subnet.
+type SubnetStatus struct {
+	// Name is the name of the subnet.
+	Name string `json:"name"`
+
+	// ID is the resource ID of the subnet.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Role is the role of the subnet in the cluster.
+	Role SubnetRole `json:"role"`
+
+	// CIDRBlocks are the address prefixes of the subnet.
+	// +optional
+	CIDRBlocks []string `json:"cidrBlocks,omitempty"`
+
+	// SecurityGroupID is the resource ID of the network security group of the subnet.
+	// +optional
+	SecurityGroupID string `json:"securityGroupID,omitempty"`
+
+	// RouteTableID is the resource ID of the route table of the subnet.
+	// +optional
+	RouteTableID string `json:"routeTableID,omitempty"`
+
+	// NatGatewayID is the resource ID of the NAT gateway of the subnet.
+	// +optional
+	NatGatewayID string `json:"natGatewayID,omitempty"`
+}
+
+// LoadBalancerRole is the role of a load balancer in a cluster.
+// +kubebuilder:validation:Enum=APIServer;NodeOutbound;ControlPlaneOutbound;NodeInternal
+type LoadBalancerRole string
+
+const (
+	// APIServerLoadBalancerRole is the role of the load balancer of the API server.
+	APIServerLoadBalancerRole LoadBalancerRole = "APIServer"
+	// NodeOutboundLoadBalancerRole is the role of the load balancer for the outbound traffic of the nodes.
+	NodeOutboundLoadBalancerRole LoadBalancerRole = "NodeOutbound"
+	// ControlPlaneOutboundLoadBalancerRole is the role of the load balancer for the outbound traffic of the control
+	// plane.
+	ControlPlaneOutboundLoadBalancerRole LoadBalancerRole = "ControlPlaneOutbound"
+	// NodeInternalLoadBalancerRole is the role of the internal load balancer of the nodes.
+	NodeInternalLoadBalancerRole LoadBalancerRole = "NodeInternal"
+)
+
+// LoadBalancerStatus describes a load balancer.
+type LoadBalancerStatus struct {
+	// Name is the name of the load balancer.
+	Name string `json:"name"`
+
+	// ID is the resource ID of the load balancer.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Role is the role of the load balancer in the cluster.
+	Role LoadBalancerRole `json:"role"`
+
+	// FrontendIPs are the frontend IPs of the load balancer.
+	// +optional
+	FrontendIPs []FrontendIPStatus `json:"frontendIPs,omitempty"`
+}
+
+// FrontendIPStatus describes a frontend IP of a load balancer.
+type FrontendIPStatus struct {
+	// Name is the name of the frontend IP configuration.
+	Name string `json:"name"`
+
+	// PrivateIPAddress is the private IP address of the frontend IP of an internal load balancer.
+	// +optional
+	PrivateIPAddress string `json:"privateIPAddress,omitempty"`
+
+	// PublicIPID is the resource ID of the public IP of the frontend IP of a public load balancer.
+	// +optional
+	PublicIPID string `json:"publicIPID,omitempty"`
+
+	// PublicIPAddress is the address of the public IP of the frontend IP of a public load balancer.
+	// +optional
+	PublicIPAddress string `json:"publicIPAddress,omitempty"`
+
+	// DNSName is the DNS name of the public IP of the frontend IP of a public load balancer.
+	// +optional
+	DNSName string `json:"dnsName,omitempty"`
+}
+
+// NatGatewayStatus describes a NAT gateway.
+type NatGatewayStatus struct {
+	// Name is the name of the NAT gateway.
+	Name string `json:"name"`
+
+	// ID is the resource ID of the NAT gateway.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// PublicIPID is the resource ID of the public IP of the NAT gateway.
+	// +optional
+	PublicIPID string `json:"publicIPID,omitempty"`
+
+	// PublicIPAddress is the address of the public IP of the NAT gateway, the source address of the outbound traffic of
+	// its subnets.
+	// +optional
+	PublicIPAddress string `json:"publicIPAddress,omitempty"`
+}
+
+// ApplicationGatewayStatus describes the application gateway of a cluster.
+type ApplicationGatewayStatus struct {
+	// Name is the name of the application gateway.
+	Name string `json:"name"`
+
+	// ID is the resource ID of the application gateway.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// SubnetID is the resource ID of the subnet of the application gateway.
+	// +optional
+	SubnetID string `json:"subnetID,omitempty"`
+
+	// PublicIPID is the resource ID of the public IP of the application gateway.
+	// +optional
+	PublicIPID string `json:"publicIPID,omitempty"`
+
+	// PublicIPAddress is the address of the public IP of the application gateway, the address the ingresses it serves
+	// are reached on.
+	// +optional
+	PublicIPAddress string `json:"publicIPAddress,omitempty"`
+}
+
+// DriftRemediationPolicy is the policy applied to Azure resources which were changed out-of-band.
+// +kubebuilder:validation:Enum=Correct;Report
+type DriftRemediationPolicy string
+
+const (
+	// DriftRemediationCorrect reverts out-of-band changes by updating the resources back to their desired state.
+	DriftRemediationCorrect DriftRemediationPolicy = "Correct"
+	// DriftRemediationReport leaves resources changed out-of-band untouched and reports them.
+	DriftRemediationReport DriftRemediationPolicy = "Report"
+)
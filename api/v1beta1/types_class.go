@@ -26,7 +26,10 @@ type AzureClusterClassSpec struct {
 	// +optional
 	SubscriptionID string `json:"subscriptionID,omitempty"`
 
-	Location string `json:"location"`
+	// Location is the Azure region of the cluster, e.g. eastus. When omitted, the cluster gets the default location
+	// configured on the controller manager for its subscription.
+	// +optional
+	Location string `json:"location,omitempty"`
 
 	// ExtendedLocation is an optional set of ExtendedLocation properties for clusters on Azure public MEC.
 	// +optional
@@ -64,6 +67,221 @@ type AzureClusterClassSpec struct {
 	// See: https://learn.microsoft.com/azure/reliability/availability-zones-overview
 	// +optional
 	FailureDomains clusterv1.FailureDomains `json:"failureDomains,omitempty"`
+
+	// FailureDomainVMSizes is a list of virtual machine sizes that must all be available in an availability zone
+	// for the zone to be reported as a failure domain of the cluster. When empty, every availability zone of the
+	// cluster's region is reported.
+	// +optional
+	FailureDomainVMSizes []string `json:"failureDomainVMSizes,omitempty"`
+
+	// PlatformFaultDomainCount is the number of fault domains of the availability sets the machines of the cluster are
+	// placed in, in regions without availability zones. Azure spreads the virtual machines of an availability set
+	// across its fault domains, and the fault domain of each machine is reported in its status.failureDomain.
+	// Defaults to the maximum supported by the region. It cannot be changed after cluster creation, as the fault domain
+	// count of an existing availability set is immutable.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3
+	// +optional
+	PlatformFaultDomainCount *int32 `json:"platformFaultDomainCount,omitempty"`
+
+	// ZoneRedundantPublicIPs requires the Standard public IPs created for the cluster's load balancers, NAT gateways,
+	// Azure Bastion and virtual network gateway to span the availability zones of the cluster's region.
+	// Zone redundancy is not available in edge zones or in regions without availability zones.
+	// The zones of a public IP cannot be changed after creation, so this field is immutable.
+	// +optional
+	ZoneRedundantPublicIPs bool `json:"zoneRedundantPublicIPs,omitempty"`
+
+	// SSHKeyPair configures an SSH key pair generated by the provider and shared by the machines in the cluster.
+	// The private key is kept in a Secret in the cluster namespace and only the public key is added to the
+	// authorized keys of each virtual machine, in addition to the machine's own SSHPublicKey.
+	// +optional
+	SSHKeyPair *SSHKeyPairSpec `json:"sshKeyPair,omitempty"`
+
+	// SSHPublicKeys are SSH public keys authorized on the virtual machines of the cluster, in addition to the SSH public
+	// keys of each machine, with different keys for control plane and worker machines. Changes only apply to new
+	// machines, and to existing machines opted into SSH key rotation.
+	// +optional
+	SSHPublicKeys *SSHPublicKeysSpec `json:"sshPublicKeys,omitempty"`
+
+	// ResourceNaming customizes the names generated for the Azure resources of the cluster, such as its resource
+	// group, virtual network, subnets, security groups, route tables, NAT gateways, load balancers, public IPs and
+	// Azure Bastion. Names set explicitly in the spec are used as is. It is immutable.
+	// +optional
+	ResourceNaming *ResourceNamingSpec `json:"resourceNaming,omitempty"`
+
+	// ResourceGroupLock, if true, places a CanNotDelete management lock on the cluster resource group to protect it,
+	// and the resources in it, from accidental deletion, for example through the Azure portal.
+	// Management locks apply to every resource in the resource group, so the VMs of machines can't be deleted either
+	// while the lock is in place. The lock is removed once the Cluster is deleted, or when this is set back to false.
+	// Managing management locks requires the Microsoft.Authorization/locks/* permissions, which are not part of the
+	// Contributor role.
+	// +optional
+	ResourceGroupLock bool `json:"resourceGroupLock,omitempty"`
+
+	// PolicyPreflightCheck, if true, checks the resource group, virtual network, network security groups, route tables,
+	// public IPs and load balancers of the cluster against the Azure Policy assignments with a deny effect before any
+	// resource is created. Resources that would be denied are reported in the PolicyCompliant condition, and no resources
+	// are created until the check passes. The check is not repeated once it has passed.
+	// +optional
+	PolicyPreflightCheck bool `json:"policyPreflightCheck,omitempty"`
+
+	// CostEstimation enables the estimation of the monthly cost of the virtual machines, managed disks and load
+	// balancers of the cluster from the Azure retail prices. The estimate is reported in the status.
+	// +optional
+	CostEstimation *CostEstimationSpec `json:"costEstimation,omitempty"`
+
+	// BootstrapDataStorage configures a storage account used to deliver the bootstrap data of machines that cannot be
+	// passed as VM custom data, either because it exceeds the custom data size limit or because it uses Ignition.
+	// The bootstrap data is uploaded as a blob, the VM is given a short-lived SAS URL to fetch it, and the blob is deleted
+	// once the machine has joined the cluster.
+	// +optional
+	BootstrapDataStorage *BootstrapDataStorage `json:"bootstrapDataStorage,omitempty"`
+
+	// BootstrapDataKeyVault configures an existing Key Vault used to deliver the bootstrap data of Linux machines in
+	// cloud-config format, so that secrets such as join tokens and certificates are not embedded in VM custom data.
+	// The bootstrap data is stored as a secret of the Key Vault, the system-assigned identity of the VM is given an
+	// access policy to read it, and the VM is given a script fetching it as custom data. The secret and the access policy
+	// are deleted once the machine has joined the cluster. Machines without a system-assigned identity are not created.
+	// It is immutable.
+	// +optional
+	BootstrapDataKeyVault *BootstrapDataKeyVault `json:"bootstrapDataKeyVault,omitempty"`
+
+	// DriftRemediation is the policy applied to the Azure resources of the cluster and of its machines which were
+	// changed out-of-band and no longer match the spec, such as network security group rules, load balancer rules or
+	// VM tags. Drift is checked on every reconciliation. Correct, the default, reverts the changes and records a
+	// DriftCorrected event. Report leaves the resources untouched and reports them in the NoDrift condition and in a
+	// DriftDetected event. Changes to the spec of the AzureCluster are applied with either policy.
+	// +optional
+	DriftRemediation DriftRemediationPolicy `json:"driftRemediation,omitempty"`
+
+	// ManagedIdentity, if set, creates a user-assigned managed identity dedicated to the cluster in its resource group,
+	// assigns it roles and attaches it to the virtual machines and virtual machine scale sets of the cluster, so that
+	// no identity has to be created beforehand. The identity and its role assignments are deleted with the cluster.
+	// It is immutable.
+	// +optional
+	ManagedIdentity *ClusterManagedIdentity `json:"managedIdentity,omitempty"`
+}
+
+// ResourceNameHashStrategy defines the hash appended to generated resource names.
+type ResourceNameHashStrategy string
+
+const (
+	// ResourceNameHashStrategyNone does not append a hash to generated resource names.
+	ResourceNameHashStrategyNone ResourceNameHashStrategy = "None"
+	// ResourceNameHashStrategyNamespacedName appends a hash of the namespace and name of the AzureCluster to generated
+	// resource names, which keeps them unique across clusters with the same name in different namespaces.
+	ResourceNameHashStrategyNamespacedName ResourceNameHashStrategy = "NamespacedName"
+)
+
+// ResourceNamingSpec defines how the names of the Azure resources of a cluster are generated. A generated name is
+// the prefix, followed by the default name, the hash and the suffix, e.g. <prefix><cluster-name>-vnet-<hash><suffix>.
+type ResourceNamingSpec struct {
+	// Prefix is prepended to generated resource names, e.g. "corp-prod-".
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9-]{0,19}$`
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Suffix is appended to generated resource names, e.g. "-weu-001".
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9-]{0,19}[a-zA-Z0-9]$`
+	// +optional
+	Suffix string `json:"suffix,omitempty"`
+
+	// HashStrategy defines the hash appended to generated resource names, before the suffix.
+	// Defaults to None.
+	// +kubebuilder:validation:Enum=None;NamespacedName
+	// +optional
+	HashStrategy ResourceNameHashStrategy `json:"hashStrategy,omitempty"`
+}
+
+// BootstrapDataKeyVault defines the Key Vault used to deliver bootstrap data.
+type BootstrapDataKeyVault struct {
+	// Name is the name of the Key Vault. It must use the vault access policy permission model.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9-]{1,22}[a-zA-Z0-9]$`
+	Name string `json:"name"`
+
+	// ResourceGroup is the resource group of the Key Vault. Defaults to the resource group of the cluster.
+	// +optional
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+}
+
+// BootstrapDataStorage defines the storage account used to deliver bootstrap data.
+type BootstrapDataStorage struct {
+	// StorageAccountName is the name of the storage account created in the cluster resource group.
+	// If not specified, a name is generated from the subscription, resource group and cluster name.
+	// Storage account names are globally unique, so it can't be set in an AzureClusterTemplate.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]{3,24}$`
+	// +optional
+	StorageAccountName string `json:"storageAccountName,omitempty"`
+
+	// HTTPSTrafficOnly only allows requests to the storage account over HTTPS. Defaults to true.
+	// +optional
+	HTTPSTrafficOnly *bool `json:"httpsTrafficOnly,omitempty"`
+
+	// MinimumTLSVersion is the minimum TLS version of the requests to the storage account. Defaults to TLS1_2.
+	// +optional
+	MinimumTLSVersion StorageTLSVersion `json:"minimumTLSVersion,omitempty"`
+
+	// AllowBlobPublicAccess allows containers of the storage account to be configured for anonymous public read access.
+	// The bootstrap data container is always private. Defaults to false.
+	// +optional
+	AllowBlobPublicAccess *bool `json:"allowBlobPublicAccess,omitempty"`
+}
+
+// ClusterManagedIdentity defines the user-assigned managed identity created for a cluster.
+type ClusterManagedIdentity struct {
+	// Name is the name of the identity, created in the cluster resource group. Defaults to "<cluster-name>-identity".
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9_-]{2,127}$`
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Roles are the roles assigned to the identity. Defaults to the Contributor role on the cluster resource group.
+	// +optional
+	Roles []ManagedIdentityRole `json:"roles,omitempty"`
+}
+
+// ManagedIdentityRole defines a role assigned to the managed identity of a cluster.
+type ManagedIdentityRole struct {
+	// DefinitionID is the ID of the role definition, e.g.
+	// /subscriptions/<subscription-id>/providers/Microsoft.Authorization/roleDefinitions/<role-id>.
+	DefinitionID string `json:"definitionID"`
+
+	// Scope is the ID of the resource the role is assigned on. Defaults to the cluster resource group.
+	// +optional
+	Scope string `json:"scope,omitempty"`
+}
+
+// StorageTLSVersion is the minimum TLS version of the requests to a storage account.
+// +kubebuilder:validation:Enum=TLS1_0;TLS1_1;TLS1_2
+type StorageTLSVersion string
+
+const (
+	// StorageTLSVersion10 allows TLS 1.0 and later.
+	StorageTLSVersion10 StorageTLSVersion = "TLS1_0"
+	// StorageTLSVersion11 allows TLS 1.1 and later.
+	StorageTLSVersion11 StorageTLSVersion = "TLS1_1"
+	// StorageTLSVersion12 allows TLS 1.2 and later.
+	StorageTLSVersion12 StorageTLSVersion = "TLS1_2"
+)
+
+// SSHKeyPairSpec defines a cluster-wide SSH key pair stored in a Kubernetes Secret.
+type SSHKeyPairSpec struct {
+	// SecretName is the name of the Secret holding the key pair under the "ssh-privatekey" and "ssh-publickey" keys.
+	// The Secret is generated if it does not exist. A user-provided Secret is used as is and never modified.
+	// Defaults to "<cluster-name>-ssh-keypair".
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// SSHPublicKeysSpec defines the SSH public keys authorized on the virtual machines of a cluster, by machine role.
+// Keys are SSH public key strings, base64-encoded. Linux only.
+type SSHPublicKeysSpec struct {
+	// ControlPlane are the SSH public keys authorized on control plane machines.
+	// +optional
+	ControlPlane []string `json:"controlPlane,omitempty"`
+
+	// Nodes are the SSH public keys authorized on worker machines, including the instances of machine pools.
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
 }
 
 // ExtendedLocationSpec defines the ExtendedLocation properties to enable CAPZ for Azure public MEC.
@@ -125,6 +343,86 @@ type LoadBalancerClassSpec struct {
 	// IdleTimeoutInMinutes specifies the timeout for the TCP idle connection.
 	// +optional
 	IdleTimeoutInMinutes *int32 `json:"idleTimeoutInMinutes,omitempty"`
+	// HealthProbe configures the health probe of the API server load balancing rule.
+	// When omitted, an HTTPS probe on the /readyz path of the API server port is used.
+	// Only supported on the API server load balancer.
+	// +optional
+	HealthProbe *LoadBalancerHealthProbe `json:"healthProbe,omitempty"`
+	// AdditionalRules are load balancing rules added to the API server load balancer besides the API server rule,
+	// e.g. to expose the API server on port 443 or the konnectivity server. They use the API server frontend IP,
+	// backend pool and health probe. Rules can be added after cluster creation, but not modified or removed.
+	// Only supported on the API server load balancer.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	AdditionalRules []LoadBalancingRule `json:"additionalRules,omitempty"`
+	// DisableSSHNATRules disables the inbound NAT rules forwarding a unique frontend port of the API server load balancer
+	// to the SSH port of each control plane machine: 22 for the first one, then 2201 to 2219. The SSH endpoint of each
+	// control plane machine is reported in its status. Cannot be changed after cluster creation.
+	// Only supported on the API server load balancer.
+	// +optional
+	DisableSSHNATRules bool `json:"disableSSHNATRules,omitempty"`
+	// EnableFloatingIP enables floating IP on the load balancing rules of the load balancer, so that the backends
+	// receive the traffic with the frontend IP rather than their own IP as destination, as needed e.g. by kube-vip.
+	// When omitted, new rules have floating IP disabled and existing rules keep their setting.
+	// Only supported on the API server and node internal load balancers.
+	// +optional
+	EnableFloatingIP *bool `json:"enableFloatingIP,omitempty"`
+	// EnableTCPReset makes the load balancer send a TCP reset to both ends of the connections of its load balancing
+	// rules when they reach the idle timeout, instead of silently dropping them.
+	// When omitted, new rules have TCP reset disabled and existing rules keep their setting.
+	// Only supported on the API server and node internal load balancers.
+	// +optional
+	EnableTCPReset *bool `json:"enableTCPReset,omitempty"`
+}
+
+// LoadBalancingRule defines an additional load balancing rule of a load balancer.
+type LoadBalancingRule struct {
+	// Name is the name of the load balancing rule.
+	Name string `json:"name"`
+	// Protocol is the transport protocol of the rule. Defaults to Tcp.
+	// +kubebuilder:validation:Enum=Tcp;Udp
+	// +optional
+	Protocol LoadBalancingRuleProtocol `json:"protocol,omitempty"`
+	// FrontendPort is the port of the load balancer frontend IP the rule listens on.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65534
+	FrontendPort int32 `json:"frontendPort"`
+	// BackendPort is the port of the control plane nodes traffic is forwarded to. Defaults to the frontend port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	BackendPort *int32 `json:"backendPort,omitempty"`
+}
+
+// LoadBalancerHealthProbe defines the health probe of a load balancer.
+type LoadBalancerHealthProbe struct {
+	// Protocol is the protocol of the health probe. Defaults to Https.
+	// +kubebuilder:validation:Enum=Tcp;Http;Https
+	// +optional
+	Protocol LoadBalancerProbeProtocol `json:"protocol,omitempty"`
+	// Port is the backend port probed. Defaults to the API server port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+	// RequestPath is the URI requested by Http and Https probes. Defaults to /readyz for Http and Https probes.
+	// Must not be set for Tcp probes.
+	// +optional
+	RequestPath string `json:"requestPath,omitempty"`
+	// IntervalInSeconds is the interval between two probes. Defaults to 15.
+	// +kubebuilder:validation:Minimum=5
+	// +optional
+	IntervalInSeconds *int32 `json:"intervalInSeconds,omitempty"`
+	// NumberOfProbes is the number of failed probes after which a backend is taken out of rotation. Defaults to 4.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	NumberOfProbes *int32 `json:"numberOfProbes,omitempty"`
+	// ProbeThreshold is the number of consecutive successful or failed probes needed to allow or deny traffic to a backend.
+	// Raising it avoids flapping backends while the API server restarts, e.g. during upgrades.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProbeThreshold *int32 `json:"probeThreshold,omitempty"`
 }
 
 // SecurityGroupClass defines the SecurityGroup properties that may be shared across several Azure clusters.
@@ -107,6 +107,11 @@ func (in *AdditionalCapabilities) DeepCopyInto(out *AdditionalCapabilities) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.HibernationEnabled != nil {
+		in, out := &in.HibernationEnabled, &out.HibernationEnabled
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalCapabilities.
@@ -181,6 +186,52 @@ func (in *AllowedNamespaces) DeepCopy() *AllowedNamespaces {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationGatewaySpec) DeepCopyInto(out *ApplicationGatewaySpec) {
+	*out = *in
+	if in.CIDRBlocks != nil {
+		in, out := &in.CIDRBlocks, &out.CIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.PublicIP.DeepCopyInto(&out.PublicIP)
+	if in.MaxCapacity != nil {
+		in, out := &in.MaxCapacity, &out.MaxCapacity
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WAF != nil {
+		in, out := &in.WAF, &out.WAF
+		*out = new(WebApplicationFirewallSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationGatewaySpec.
+func (in *ApplicationGatewaySpec) DeepCopy() *ApplicationGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationGatewayStatus) DeepCopyInto(out *ApplicationGatewayStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationGatewayStatus.
+func (in *ApplicationGatewayStatus) DeepCopy() *ApplicationGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoScalerProfile) DeepCopyInto(out *AutoScalerProfile) {
 	*out = *in
@@ -373,6 +424,51 @@ func (in *AzureClusterClassSpec) DeepCopyInto(out *AzureClusterClassSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.FailureDomainVMSizes != nil {
+		in, out := &in.FailureDomainVMSizes, &out.FailureDomainVMSizes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlatformFaultDomainCount != nil {
+		in, out := &in.PlatformFaultDomainCount, &out.PlatformFaultDomainCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SSHKeyPair != nil {
+		in, out := &in.SSHKeyPair, &out.SSHKeyPair
+		*out = new(SSHKeyPairSpec)
+		**out = **in
+	}
+	if in.SSHPublicKeys != nil {
+		in, out := &in.SSHPublicKeys, &out.SSHPublicKeys
+		*out = new(SSHPublicKeysSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceNaming != nil {
+		in, out := &in.ResourceNaming, &out.ResourceNaming
+		*out = new(ResourceNamingSpec)
+		**out = **in
+	}
+	if in.CostEstimation != nil {
+		in, out := &in.CostEstimation, &out.CostEstimation
+		*out = new(CostEstimationSpec)
+		**out = **in
+	}
+	if in.BootstrapDataStorage != nil {
+		in, out := &in.BootstrapDataStorage, &out.BootstrapDataStorage
+		*out = new(BootstrapDataStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BootstrapDataKeyVault != nil {
+		in, out := &in.BootstrapDataKeyVault, &out.BootstrapDataKeyVault
+		*out = new(BootstrapDataKeyVault)
+		**out = **in
+	}
+	if in.ManagedIdentity != nil {
+		in, out := &in.ManagedIdentity, &out.ManagedIdentity
+		*out = new(ClusterManagedIdentity)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterClassSpec.
@@ -525,6 +621,18 @@ func (in *AzureClusterSpec) DeepCopyInto(out *AzureClusterSpec) {
 	in.AzureClusterClassSpec.DeepCopyInto(&out.AzureClusterClassSpec)
 	in.NetworkSpec.DeepCopyInto(&out.NetworkSpec)
 	in.BastionSpec.DeepCopyInto(&out.BastionSpec)
+	if in.ApplicationGateway != nil {
+		in, out := &in.ApplicationGateway, &out.ApplicationGateway
+		*out = new(ApplicationGatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecondaryRegions != nil {
+		in, out := &in.SecondaryRegions, &out.SecondaryRegions
+		*out = make([]SecondaryRegionSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
 }
 
@@ -558,7 +666,34 @@ func (in *AzureClusterStatus) DeepCopyInto(out *AzureClusterStatus) {
 	if in.LongRunningOperationStates != nil {
 		in, out := &in.LongRunningOperationStates, &out.LongRunningOperationStates
 		*out = make(Futures, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourceGroupManaged != nil {
+		in, out := &in.ResourceGroupManaged, &out.ResourceGroupManaged
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CostEstimate != nil {
+		in, out := &in.CostEstimate, &out.CostEstimate
+		*out = new(CostEstimate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EncryptionPosture != nil {
+		in, out := &in.EncryptionPosture, &out.EncryptionPosture
+		*out = new(EncryptionPosture)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(ReconcilePlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(NetworkStatus)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -710,6 +845,21 @@ func (in *AzureComputeGalleryImage) DeepCopy() *AzureComputeGalleryImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureDiskEncryption) DeepCopyInto(out *AzureDiskEncryption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureDiskEncryption.
+func (in *AzureDiskEncryption) DeepCopy() *AzureDiskEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureDiskEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachine) DeepCopyInto(out *AzureMachine) {
 	*out = *in
@@ -769,6 +919,194 @@ func (in *AzureMachineList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachineRemediation) DeepCopyInto(out *AzureMachineRemediation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineRemediation.
+func (in *AzureMachineRemediation) DeepCopy() *AzureMachineRemediation {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachineRemediation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureMachineRemediation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachineRemediationList) DeepCopyInto(out *AzureMachineRemediationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureMachineRemediation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineRemediationList.
+func (in *AzureMachineRemediationList) DeepCopy() *AzureMachineRemediationList {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachineRemediationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureMachineRemediationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachineRemediationSpec) DeepCopyInto(out *AzureMachineRemediationSpec) {
+	*out = *in
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(RemediationStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineRemediationSpec.
+func (in *AzureMachineRemediationSpec) DeepCopy() *AzureMachineRemediationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachineRemediationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachineRemediationStatus) DeepCopyInto(out *AzureMachineRemediationStatus) {
+	*out = *in
+	if in.LastRemediated != nil {
+		in, out := &in.LastRemediated, &out.LastRemediated
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineRemediationStatus.
+func (in *AzureMachineRemediationStatus) DeepCopy() *AzureMachineRemediationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachineRemediationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachineRemediationTemplate) DeepCopyInto(out *AzureMachineRemediationTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineRemediationTemplate.
+func (in *AzureMachineRemediationTemplate) DeepCopy() *AzureMachineRemediationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachineRemediationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureMachineRemediationTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachineRemediationTemplateList) DeepCopyInto(out *AzureMachineRemediationTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureMachineRemediationTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineRemediationTemplateList.
+func (in *AzureMachineRemediationTemplateList) DeepCopy() *AzureMachineRemediationTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachineRemediationTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureMachineRemediationTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachineRemediationTemplateResource) DeepCopyInto(out *AzureMachineRemediationTemplateResource) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineRemediationTemplateResource.
+func (in *AzureMachineRemediationTemplateResource) DeepCopy() *AzureMachineRemediationTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachineRemediationTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachineRemediationTemplateSpec) DeepCopyInto(out *AzureMachineRemediationTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineRemediationTemplateSpec.
+func (in *AzureMachineRemediationTemplateSpec) DeepCopy() *AzureMachineRemediationTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachineRemediationTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 	*out = *in
@@ -787,6 +1125,11 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 		*out = new(Image)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ImageLookup != nil {
+		in, out := &in.ImageLookup, &out.ImageLookup
+		*out = new(ImageLookup)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.UserAssignedIdentities != nil {
 		in, out := &in.UserAssignedIdentities, &out.UserAssignedIdentities
 		*out = make([]UserAssignedIdentity, len(*in))
@@ -805,6 +1148,11 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AdditionalSSHPublicKeys != nil {
+		in, out := &in.AdditionalSSHPublicKeys, &out.AdditionalSSHPublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AdditionalTags != nil {
 		in, out := &in.AdditionalTags, &out.AdditionalTags
 		*out = make(Tags, len(*in))
@@ -812,6 +1160,11 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.NodeLabelTagMapping != nil {
+		in, out := &in.NodeLabelTagMapping, &out.NodeLabelTagMapping
+		*out = new(NodeLabelTagMapping)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AdditionalCapabilities != nil {
 		in, out := &in.AdditionalCapabilities, &out.AdditionalCapabilities
 		*out = new(AdditionalCapabilities)
@@ -837,11 +1190,26 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 		*out = new(SecurityProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AzureDiskEncryption != nil {
+		in, out := &in.AzureDiskEncryption, &out.AzureDiskEncryption
+		*out = new(AzureDiskEncryption)
+		**out = **in
+	}
 	if in.DNSServers != nil {
 		in, out := &in.DNSServers, &out.DNSServers
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.LoadBalancerBackendPoolIDs != nil {
+		in, out := &in.LoadBalancerBackendPoolIDs, &out.LoadBalancerBackendPoolIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ApplicationGatewayBackendPoolIDs != nil {
+		in, out := &in.ApplicationGatewayBackendPoolIDs, &out.ApplicationGatewayBackendPoolIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.VMExtensions != nil {
 		in, out := &in.VMExtensions, &out.VMExtensions
 		*out = make([]VMExtension, len(*in))
@@ -856,6 +1224,21 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AdditionalCloudInit != nil {
+		in, out := &in.AdditionalCloudInit, &out.AdditionalCloudInit
+		*out = new(CloudInit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecondaryUserData != nil {
+		in, out := &in.SecondaryUserData, &out.SecondaryUserData
+		*out = new(SecondaryUserDataSource)
+		**out = **in
+	}
+	if in.DataDiskDetach != nil {
+		in, out := &in.DataDiskDetach, &out.DataDiskDetach
+		*out = new(DataDiskDetach)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineSpec.
@@ -881,7 +1264,32 @@ func (in *AzureMachineStatus) DeepCopyInto(out *AzureMachineStatus) {
 		*out = new(ProvisioningState)
 		**out = **in
 	}
-	if in.FailureReason != nil {
+	if in.VM != nil {
+		in, out := &in.VM, &out.VM
+		*out = new(VMStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ZoneSubstitution != nil {
+		in, out := &in.ZoneSubstitution, &out.ZoneSubstitution
+		*out = new(ZoneSubstitution)
+		**out = **in
+	}
+	if in.EtcdDataDisk != nil {
+		in, out := &in.EtcdDataDisk, &out.EtcdDataDisk
+		*out = new(EtcdDataDiskStatus)
+		**out = **in
+	}
+	if in.SSHEndpoint != nil {
+		in, out := &in.SSHEndpoint, &out.SSHEndpoint
+		*out = new(SSHEndpoint)
+		**out = **in
+	}
+	if in.NodeRef != nil {
+		in, out := &in.NodeRef, &out.NodeRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(errors.MachineStatusError)
 		**out = **in
@@ -901,7 +1309,9 @@ func (in *AzureMachineStatus) DeepCopyInto(out *AzureMachineStatus) {
 	if in.LongRunningOperationStates != nil {
 		in, out := &in.LongRunningOperationStates, &out.LongRunningOperationStates
 		*out = make(Futures, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -1289,7 +1699,9 @@ func (in *AzureManagedControlPlaneStatus) DeepCopyInto(out *AzureManagedControlP
 	if in.LongRunningOperationStates != nil {
 		in, out := &in.LongRunningOperationStates, &out.LongRunningOperationStates
 		*out = make(Futures, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.OIDCIssuerProfile != nil {
 		in, out := &in.OIDCIssuerProfile, &out.OIDCIssuerProfile
@@ -1519,7 +1931,9 @@ func (in *AzureManagedMachinePoolStatus) DeepCopyInto(out *AzureManagedMachinePo
 	if in.LongRunningOperationStates != nil {
 		in, out := &in.LongRunningOperationStates, &out.LongRunningOperationStates
 		*out = make(Futures, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -1552,6 +1966,11 @@ func (in *AzureMarketplaceImage) DeepCopy() *AzureMarketplaceImage {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureSharedGalleryImage) DeepCopyInto(out *AzureSharedGalleryImage) {
 	*out = *in
+	if in.TenantID != nil {
+		in, out := &in.TenantID, &out.TenantID
+		*out = new(string)
+		**out = **in
+	}
 	if in.Publisher != nil {
 		in, out := &in.Publisher, &out.Publisher
 		*out = new(string)
@@ -1679,6 +2098,46 @@ func (in *BootDiagnostics) DeepCopy() *BootDiagnostics {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapDataKeyVault) DeepCopyInto(out *BootstrapDataKeyVault) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapDataKeyVault.
+func (in *BootstrapDataKeyVault) DeepCopy() *BootstrapDataKeyVault {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapDataKeyVault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapDataStorage) DeepCopyInto(out *BootstrapDataStorage) {
+	*out = *in
+	if in.HTTPSTrafficOnly != nil {
+		in, out := &in.HTTPSTrafficOnly, &out.HTTPSTrafficOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowBlobPublicAccess != nil {
+		in, out := &in.AllowBlobPublicAccess, &out.AllowBlobPublicAccess
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapDataStorage.
+func (in *BootstrapDataStorage) DeepCopy() *BootstrapDataStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapDataStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildParams) DeepCopyInto(out *BuildParams) {
 	*out = *in
@@ -1711,6 +2170,46 @@ func (in *BuildParams) DeepCopy() *BuildParams {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudInit) DeepCopyInto(out *CloudInit) {
+	*out = *in
+	if in.WriteFiles != nil {
+		in, out := &in.WriteFiles, &out.WriteFiles
+		*out = make([]CloudInitFile, len(*in))
+		copy(*out, *in)
+	}
+	if in.RunCmd != nil {
+		in, out := &in.RunCmd, &out.RunCmd
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInit.
+func (in *CloudInit) DeepCopy() *CloudInit {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudInit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudInitFile) DeepCopyInto(out *CloudInitFile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInitFile.
+func (in *CloudInitFile) DeepCopy() *CloudInitFile {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudInitFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudProviderConfigOverrides) DeepCopyInto(out *CloudProviderConfigOverrides) {
 	*out = *in
@@ -1735,289 +2234,614 @@ func (in *CloudProviderConfigOverrides) DeepCopy() *CloudProviderConfigOverrides
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataDisk) DeepCopyInto(out *DataDisk) {
+func (in *ClusterManagedIdentity) DeepCopyInto(out *ClusterManagedIdentity) {
 	*out = *in
-	if in.ManagedDisk != nil {
-		in, out := &in.ManagedDisk, &out.ManagedDisk
-		*out = new(ManagedDiskParameters)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Lun != nil {
-		in, out := &in.Lun, &out.Lun
-		*out = new(int32)
-		**out = **in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ManagedIdentityRole, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDisk.
-func (in *DataDisk) DeepCopy() *DataDisk {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterManagedIdentity.
+func (in *ClusterManagedIdentity) DeepCopy() *ClusterManagedIdentity {
 	if in == nil {
 		return nil
 	}
-	out := new(DataDisk)
+	out := new(ClusterManagedIdentity)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Diagnostics) DeepCopyInto(out *Diagnostics) {
+func (in *CostEstimate) DeepCopyInto(out *CostEstimate) {
 	*out = *in
-	if in.Boot != nil {
-		in, out := &in.Boot, &out.Boot
-		*out = new(BootDiagnostics)
-		(*in).DeepCopyInto(*out)
+	if in.UnpricedResources != nil {
+		in, out := &in.UnpricedResources, &out.UnpricedResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Diagnostics.
-func (in *Diagnostics) DeepCopy() *Diagnostics {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimate.
+func (in *CostEstimate) DeepCopy() *CostEstimate {
 	if in == nil {
 		return nil
 	}
-	out := new(Diagnostics)
+	out := new(CostEstimate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DiffDiskSettings) DeepCopyInto(out *DiffDiskSettings) {
+func (in *CostEstimationSpec) DeepCopyInto(out *CostEstimationSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiffDiskSettings.
-func (in *DiffDiskSettings) DeepCopy() *DiffDiskSettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimationSpec.
+func (in *CostEstimationSpec) DeepCopy() *CostEstimationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DiffDiskSettings)
+	out := new(CostEstimationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DiskEncryptionSetParameters) DeepCopyInto(out *DiskEncryptionSetParameters) {
+func (in *DDoSProtectionPlanSpec) DeepCopyInto(out *DDoSProtectionPlanSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskEncryptionSetParameters.
-func (in *DiskEncryptionSetParameters) DeepCopy() *DiskEncryptionSetParameters {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DDoSProtectionPlanSpec.
+func (in *DDoSProtectionPlanSpec) DeepCopy() *DDoSProtectionPlanSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DiskEncryptionSetParameters)
+	out := new(DDoSProtectionPlanSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExtendedLocationSpec) DeepCopyInto(out *ExtendedLocationSpec) {
+func (in *DNSRecordSpec) DeepCopyInto(out *DNSRecordSpec) {
 	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int64)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtendedLocationSpec.
-func (in *ExtendedLocationSpec) DeepCopy() *ExtendedLocationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSRecordSpec.
+func (in *DNSRecordSpec) DeepCopy() *DNSRecordSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ExtendedLocationSpec)
+	out := new(DNSRecordSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FrontendIP) DeepCopyInto(out *FrontendIP) {
+func (in *DataDisk) DeepCopyInto(out *DataDisk) {
 	*out = *in
-	if in.PublicIP != nil {
-		in, out := &in.PublicIP, &out.PublicIP
-		*out = new(PublicIPSpec)
+	if in.ManagedDisk != nil {
+		in, out := &in.ManagedDisk, &out.ManagedDisk
+		*out = new(ManagedDiskParameters)
 		(*in).DeepCopyInto(*out)
 	}
-	out.FrontendIPClass = in.FrontendIPClass
+	if in.Lun != nil {
+		in, out := &in.Lun, &out.Lun
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WriteAcceleratorEnabled != nil {
+		in, out := &in.WriteAcceleratorEnabled, &out.WriteAcceleratorEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Etcd != nil {
+		in, out := &in.Etcd, &out.Etcd
+		*out = new(bool)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIP.
-func (in *FrontendIP) DeepCopy() *FrontendIP {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDisk.
+func (in *DataDisk) DeepCopy() *DataDisk {
 	if in == nil {
 		return nil
 	}
-	out := new(FrontendIP)
+	out := new(DataDisk)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FrontendIPClass) DeepCopyInto(out *FrontendIPClass) {
+func (in *DataDiskDetach) DeepCopyInto(out *DataDiskDetach) {
 	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIPClass.
-func (in *FrontendIPClass) DeepCopy() *FrontendIPClass {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDiskDetach.
+func (in *DataDiskDetach) DeepCopy() *DataDiskDetach {
 	if in == nil {
 		return nil
 	}
-	out := new(FrontendIPClass)
+	out := new(DataDiskDetach)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Future) DeepCopyInto(out *Future) {
+func (in *DiagnosticSettingsSpec) DeepCopyInto(out *DiagnosticSettingsSpec) {
 	*out = *in
+	if in.Categories != nil {
+		in, out := &in.Categories, &out.Categories
+		*out = make([]DiagnosticLogCategory, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Future.
-func (in *Future) DeepCopy() *Future {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticSettingsSpec.
+func (in *DiagnosticSettingsSpec) DeepCopy() *DiagnosticSettingsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Future)
+	out := new(DiagnosticSettingsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in Futures) DeepCopyInto(out *Futures) {
-	{
-		in := &in
-		*out = make(Futures, len(*in))
-		copy(*out, *in)
+func (in *Diagnostics) DeepCopyInto(out *Diagnostics) {
+	*out = *in
+	if in.Boot != nil {
+		in, out := &in.Boot, &out.Boot
+		*out = new(BootDiagnostics)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Futures.
-func (in Futures) DeepCopy() Futures {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Diagnostics.
+func (in *Diagnostics) DeepCopy() *Diagnostics {
 	if in == nil {
 		return nil
 	}
-	out := new(Futures)
+	out := new(Diagnostics)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPProxyConfig) DeepCopyInto(out *HTTPProxyConfig) {
+func (in *DiffDiskSettings) DeepCopyInto(out *DiffDiskSettings) {
 	*out = *in
-	if in.HTTPProxy != nil {
-		in, out := &in.HTTPProxy, &out.HTTPProxy
-		*out = new(string)
-		**out = **in
-	}
-	if in.HTTPSProxy != nil {
-		in, out := &in.HTTPSProxy, &out.HTTPSProxy
-		*out = new(string)
-		**out = **in
-	}
-	if in.NoProxy != nil {
-		in, out := &in.NoProxy, &out.NoProxy
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.TrustedCA != nil {
-		in, out := &in.TrustedCA, &out.TrustedCA
-		*out = new(string)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProxyConfig.
-func (in *HTTPProxyConfig) DeepCopy() *HTTPProxyConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiffDiskSettings.
+func (in *DiffDiskSettings) DeepCopy() *DiffDiskSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPProxyConfig)
+	out := new(DiffDiskSettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IPTag) DeepCopyInto(out *IPTag) {
+func (in *DiskEncryptionSetParameters) DeepCopyInto(out *DiskEncryptionSetParameters) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPTag.
-func (in *IPTag) DeepCopy() *IPTag {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskEncryptionSetParameters.
+func (in *DiskEncryptionSetParameters) DeepCopy() *DiskEncryptionSetParameters {
 	if in == nil {
 		return nil
 	}
-	out := new(IPTag)
+	out := new(DiskEncryptionSetParameters)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Identity) DeepCopyInto(out *Identity) {
+func (in *EgressProxySpec) DeepCopyInto(out *EgressProxySpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Identity.
-func (in *Identity) DeepCopy() *Identity {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressProxySpec.
+func (in *EgressProxySpec) DeepCopy() *EgressProxySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Identity)
+	out := new(EgressProxySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Image) DeepCopyInto(out *Image) {
+func (in *EncryptionPosture) DeepCopyInto(out *EncryptionPosture) {
 	*out = *in
-	if in.ID != nil {
-		in, out := &in.ID, &out.ID
-		*out = new(string)
-		**out = **in
-	}
-	if in.SharedGallery != nil {
-		in, out := &in.SharedGallery, &out.SharedGallery
-		*out = new(AzureSharedGalleryImage)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Marketplace != nil {
-		in, out := &in.Marketplace, &out.Marketplace
-		*out = new(AzureMarketplaceImage)
-		**out = **in
+	if in.PlatformManagedKeysOnly != nil {
+		in, out := &in.PlatformManagedKeysOnly, &out.PlatformManagedKeysOnly
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.ComputeGallery != nil {
-		in, out := &in.ComputeGallery, &out.ComputeGallery
-		*out = new(AzureComputeGalleryImage)
-		(*in).DeepCopyInto(*out)
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
-func (in *Image) DeepCopy() *Image {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionPosture.
+func (in *EncryptionPosture) DeepCopy() *EncryptionPosture {
 	if in == nil {
 		return nil
 	}
-	out := new(Image)
+	out := new(EncryptionPosture)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImagePlan) DeepCopyInto(out *ImagePlan) {
+func (in *EtcdDataDiskStatus) DeepCopyInto(out *EtcdDataDiskStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePlan.
-func (in *ImagePlan) DeepCopy() *ImagePlan {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdDataDiskStatus.
+func (in *EtcdDataDiskStatus) DeepCopy() *EtcdDataDiskStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ImagePlan)
+	out := new(EtcdDataDiskStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeletConfig) DeepCopyInto(out *KubeletConfig) {
+func (in *ExistingPublicIP) DeepCopyInto(out *ExistingPublicIP) {
 	*out = *in
-	if in.CPUManagerPolicy != nil {
-		in, out := &in.CPUManagerPolicy, &out.CPUManagerPolicy
-		*out = new(CPUManagerPolicy)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExistingPublicIP.
+func (in *ExistingPublicIP) DeepCopy() *ExistingPublicIP {
+	if in == nil {
+		return nil
 	}
-	if in.CPUCfsQuota != nil {
+	out := new(ExistingPublicIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExistingSecurityGroup) DeepCopyInto(out *ExistingSecurityGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExistingSecurityGroup.
+func (in *ExistingSecurityGroup) DeepCopy() *ExistingSecurityGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(ExistingSecurityGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtendedLocationSpec) DeepCopyInto(out *ExtendedLocationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtendedLocationSpec.
+func (in *ExtendedLocationSpec) DeepCopy() *ExtendedLocationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtendedLocationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowLogsSpec) DeepCopyInto(out *FlowLogsSpec) {
+	*out = *in
+	if in.TrafficAnalytics != nil {
+		in, out := &in.TrafficAnalytics, &out.TrafficAnalytics
+		*out = new(TrafficAnalyticsSpec)
+		**out = **in
+	}
+	out.NetworkWatcher = in.NetworkWatcher
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowLogsSpec.
+func (in *FlowLogsSpec) DeepCopy() *FlowLogsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowLogsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FrontendIP) DeepCopyInto(out *FrontendIP) {
+	*out = *in
+	if in.PublicIP != nil {
+		in, out := &in.PublicIP, &out.PublicIP
+		*out = new(PublicIPSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.FrontendIPClass = in.FrontendIPClass
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIP.
+func (in *FrontendIP) DeepCopy() *FrontendIP {
+	if in == nil {
+		return nil
+	}
+	out := new(FrontendIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FrontendIPClass) DeepCopyInto(out *FrontendIPClass) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIPClass.
+func (in *FrontendIPClass) DeepCopy() *FrontendIPClass {
+	if in == nil {
+		return nil
+	}
+	out := new(FrontendIPClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FrontendIPStatus) DeepCopyInto(out *FrontendIPStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIPStatus.
+func (in *FrontendIPStatus) DeepCopy() *FrontendIPStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FrontendIPStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Future) DeepCopyInto(out *Future) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Future.
+func (in *Future) DeepCopy() *Future {
+	if in == nil {
+		return nil
+	}
+	out := new(Future)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in Futures) DeepCopyInto(out *Futures) {
+	{
+		in := &in
+		*out = make(Futures, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Futures.
+func (in Futures) DeepCopy() Futures {
+	if in == nil {
+		return nil
+	}
+	out := new(Futures)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
+	*out = *in
+	if in.CIDRBlocks != nil {
+		in, out := &in.CIDRBlocks, &out.CIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VirtualNetworkGateway != nil {
+		in, out := &in.VirtualNetworkGateway, &out.VirtualNetworkGateway
+		*out = new(VirtualNetworkGatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
+func (in *GatewaySpec) DeepCopy() *GatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPProxyConfig) DeepCopyInto(out *HTTPProxyConfig) {
+	*out = *in
+	if in.HTTPProxy != nil {
+		in, out := &in.HTTPProxy, &out.HTTPProxy
+		*out = new(string)
+		**out = **in
+	}
+	if in.HTTPSProxy != nil {
+		in, out := &in.HTTPSProxy, &out.HTTPSProxy
+		*out = new(string)
+		**out = **in
+	}
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TrustedCA != nil {
+		in, out := &in.TrustedCA, &out.TrustedCA
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProxyConfig.
+func (in *HTTPProxyConfig) DeepCopy() *HTTPProxyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPProxyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPTag) DeepCopyInto(out *IPTag) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPTag.
+func (in *IPTag) DeepCopy() *IPTag {
+	if in == nil {
+		return nil
+	}
+	out := new(IPTag)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Identity) DeepCopyInto(out *Identity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Identity.
+func (in *Identity) DeepCopy() *Identity {
+	if in == nil {
+		return nil
+	}
+	out := new(Identity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Image) DeepCopyInto(out *Image) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.SharedGallery != nil {
+		in, out := &in.SharedGallery, &out.SharedGallery
+		*out = new(AzureSharedGalleryImage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Marketplace != nil {
+		in, out := &in.Marketplace, &out.Marketplace
+		*out = new(AzureMarketplaceImage)
+		**out = **in
+	}
+	if in.ComputeGallery != nil {
+		in, out := &in.ComputeGallery, &out.ComputeGallery
+		*out = new(AzureComputeGalleryImage)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
+func (in *Image) DeepCopy() *Image {
+	if in == nil {
+		return nil
+	}
+	out := new(Image)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageLookup) DeepCopyInto(out *ImageLookup) {
+	*out = *in
+	if in.SubscriptionID != nil {
+		in, out := &in.SubscriptionID, &out.SubscriptionID
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourceGroup != nil {
+		in, out := &in.ResourceGroup, &out.ResourceGroup
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageLookup.
+func (in *ImageLookup) DeepCopy() *ImageLookup {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageLookup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePlan) DeepCopyInto(out *ImagePlan) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePlan.
+func (in *ImagePlan) DeepCopy() *ImagePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletConfig) DeepCopyInto(out *KubeletConfig) {
+	*out = *in
+	if in.CPUManagerPolicy != nil {
+		in, out := &in.CPUManagerPolicy, &out.CPUManagerPolicy
+		*out = new(CPUManagerPolicy)
+		**out = **in
+	}
+	if in.CPUCfsQuota != nil {
 		in, out := &in.CPUCfsQuota, &out.CPUCfsQuota
 		*out = new(bool)
 		**out = **in
@@ -2122,6 +2946,28 @@ func (in *LoadBalancerClassSpec) DeepCopyInto(out *LoadBalancerClassSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.HealthProbe != nil {
+		in, out := &in.HealthProbe, &out.HealthProbe
+		*out = new(LoadBalancerHealthProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalRules != nil {
+		in, out := &in.AdditionalRules, &out.AdditionalRules
+		*out = make([]LoadBalancingRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnableFloatingIP != nil {
+		in, out := &in.EnableFloatingIP, &out.EnableFloatingIP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableTCPReset != nil {
+		in, out := &in.EnableTCPReset, &out.EnableTCPReset
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerClassSpec.
@@ -2134,6 +2980,41 @@ func (in *LoadBalancerClassSpec) DeepCopy() *LoadBalancerClassSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerHealthProbe) DeepCopyInto(out *LoadBalancerHealthProbe) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IntervalInSeconds != nil {
+		in, out := &in.IntervalInSeconds, &out.IntervalInSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NumberOfProbes != nil {
+		in, out := &in.NumberOfProbes, &out.NumberOfProbes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProbeThreshold != nil {
+		in, out := &in.ProbeThreshold, &out.ProbeThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerHealthProbe.
+func (in *LoadBalancerHealthProbe) DeepCopy() *LoadBalancerHealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerHealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancerProfile) DeepCopyInto(out *LoadBalancerProfile) {
 	*out = *in
@@ -2203,6 +3084,46 @@ func (in *LoadBalancerSpec) DeepCopy() *LoadBalancerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerStatus) DeepCopyInto(out *LoadBalancerStatus) {
+	*out = *in
+	if in.FrontendIPs != nil {
+		in, out := &in.FrontendIPs, &out.FrontendIPs
+		*out = make([]FrontendIPStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerStatus.
+func (in *LoadBalancerStatus) DeepCopy() *LoadBalancerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancingRule) DeepCopyInto(out *LoadBalancingRule) {
+	*out = *in
+	if in.BackendPort != nil {
+		in, out := &in.BackendPort, &out.BackendPort
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancingRule.
+func (in *LoadBalancingRule) DeepCopy() *LoadBalancingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancingRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedControlPlaneSubnet) DeepCopyInto(out *ManagedControlPlaneSubnet) {
 	*out = *in
@@ -2261,6 +3182,16 @@ func (in *ManagedDiskParameters) DeepCopyInto(out *ManagedDiskParameters) {
 		*out = new(VMDiskSecurityProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DiskIOPSReadWrite != nil {
+		in, out := &in.DiskIOPSReadWrite, &out.DiskIOPSReadWrite
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DiskMBpsReadWrite != nil {
+		in, out := &in.DiskMBpsReadWrite, &out.DiskMBpsReadWrite
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedDiskParameters.
@@ -2273,6 +3204,21 @@ func (in *ManagedDiskParameters) DeepCopy() *ManagedDiskParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedIdentityRole) DeepCopyInto(out *ManagedIdentityRole) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedIdentityRole.
+func (in *ManagedIdentityRole) DeepCopy() *ManagedIdentityRole {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedIdentityRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedMachinePoolScaling) DeepCopyInto(out *ManagedMachinePoolScaling) {
 	*out = *in
@@ -2330,6 +3276,21 @@ func (in *NatGatewayClassSpec) DeepCopy() *NatGatewayClassSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NatGatewayStatus) DeepCopyInto(out *NatGatewayStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NatGatewayStatus.
+func (in *NatGatewayStatus) DeepCopy() *NatGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NatGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkClassSpec) DeepCopyInto(out *NetworkClassSpec) {
 	*out = *in
@@ -2353,6 +3314,13 @@ func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.AddressesFromPools != nil {
+		in, out := &in.AddressesFromPools, &out.AddressesFromPools
+		*out = make([]corev1.TypedLocalObjectReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
@@ -2387,6 +3355,48 @@ func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 		*out = new(LoadBalancerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeInternalLB != nil {
+		in, out := &in.NodeInternalLB, &out.NodeInternalLB
+		*out = new(NodeInternalLBSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIServerDNSRecord != nil {
+		in, out := &in.APIServerDNSRecord, &out.APIServerDNSRecord
+		*out = new(DNSRecordSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FlowLogs != nil {
+		in, out := &in.FlowLogs, &out.FlowLogs
+		*out = new(FlowLogsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiagnosticSettings != nil {
+		in, out := &in.DiagnosticSettings, &out.DiagnosticSettings
+		*out = new(DiagnosticSettingsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeRoutes != nil {
+		in, out := &in.NodeRoutes, &out.NodeRoutes
+		*out = new(NodeRoutesSpec)
+		**out = **in
+	}
+	if in.EgressProxy != nil {
+		in, out := &in.EgressProxy, &out.EgressProxy
+		*out = new(EgressProxySpec)
+		**out = **in
+	}
+	if in.ControlPlaneExtraRules != nil {
+		in, out := &in.ControlPlaneExtraRules, &out.ControlPlaneExtraRules
+		*out = make(SecurityRules, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.NetworkClassSpec = in.NetworkClassSpec
 }
 
@@ -2400,6 +3410,50 @@ func (in *NetworkSpec) DeepCopy() *NetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkStatus) DeepCopyInto(out *NetworkStatus) {
+	*out = *in
+	if in.Vnet != nil {
+		in, out := &in.Vnet, &out.Vnet
+		*out = new(VnetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]SubnetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LoadBalancers != nil {
+		in, out := &in.LoadBalancers, &out.LoadBalancers
+		*out = make([]LoadBalancerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NatGateways != nil {
+		in, out := &in.NatGateways, &out.NatGateways
+		*out = make([]NatGatewayStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ApplicationGateway != nil {
+		in, out := &in.ApplicationGateway, &out.ApplicationGateway
+		*out = new(ApplicationGatewayStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkStatus.
+func (in *NetworkStatus) DeepCopy() *NetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkTemplateSpec) DeepCopyInto(out *NetworkTemplateSpec) {
 	*out = *in
@@ -2435,6 +3489,72 @@ func (in *NetworkTemplateSpec) DeepCopy() *NetworkTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkWatcherReference) DeepCopyInto(out *NetworkWatcherReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkWatcherReference.
+func (in *NetworkWatcherReference) DeepCopy() *NetworkWatcherReference {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkWatcherReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeInternalLBSpec) DeepCopyInto(out *NodeInternalLBSpec) {
+	*out = *in
+	in.LoadBalancerSpec.DeepCopyInto(&out.LoadBalancerSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeInternalLBSpec.
+func (in *NodeInternalLBSpec) DeepCopy() *NodeInternalLBSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeInternalLBSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeLabelTagMapping) DeepCopyInto(out *NodeLabelTagMapping) {
+	*out = *in
+	if in.LabelsToTags != nil {
+		in, out := &in.LabelsToTags, &out.LabelsToTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeLabelTagMapping.
+func (in *NodeLabelTagMapping) DeepCopy() *NodeLabelTagMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeLabelTagMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeRoutesSpec) DeepCopyInto(out *NodeRoutesSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRoutesSpec.
+func (in *NodeRoutesSpec) DeepCopy() *NodeRoutesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRoutesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OIDCIssuerProfile) DeepCopyInto(out *OIDCIssuerProfile) {
 	*out = *in
@@ -2493,14 +3613,69 @@ func (in *OSDisk) DeepCopyInto(out *OSDisk) {
 		*out = new(DiffDiskSettings)
 		**out = **in
 	}
+	if in.WriteAcceleratorEnabled != nil {
+		in, out := &in.WriteAcceleratorEnabled, &out.WriteAcceleratorEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ZoneRedundant != nil {
+		in, out := &in.ZoneRedundant, &out.ZoneRedundant
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(OSDiskSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDisk.
+func (in *OSDisk) DeepCopy() *OSDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDiskSource) DeepCopyInto(out *OSDiskSource) {
+	*out = *in
+	if in.SnapshotID != nil {
+		in, out := &in.SnapshotID, &out.SnapshotID
+		*out = new(string)
+		**out = **in
+	}
+	if in.RestorePointID != nil {
+		in, out := &in.RestorePointID, &out.RestorePointID
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDiskSource.
+func (in *OSDiskSource) DeepCopy() *OSDiskSource {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDiskSource)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDisk.
-func (in *OSDisk) DeepCopy() *OSDisk {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedOperation) DeepCopyInto(out *PlannedOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedOperation.
+func (in *PlannedOperation) DeepCopy() *PlannedOperation {
 	if in == nil {
 		return nil
 	}
-	out := new(OSDisk)
+	out := new(PlannedOperation)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2586,6 +3761,11 @@ func (in *PublicIPSpec) DeepCopyInto(out *PublicIPSpec) {
 		*out = make([]IPTag, len(*in))
 		copy(*out, *in)
 	}
+	if in.Existing != nil {
+		in, out := &in.Existing, &out.Existing
+		*out = new(ExistingPublicIP)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicIPSpec.
@@ -2639,6 +3819,62 @@ func (in *RateLimitSpec) DeepCopy() *RateLimitSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcilePlan) DeepCopyInto(out *ReconcilePlan) {
+	*out = *in
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]PlannedOperation, len(*in))
+		copy(*out, *in)
+	}
+	in.GeneratedAt.DeepCopyInto(&out.GeneratedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcilePlan.
+func (in *ReconcilePlan) DeepCopy() *ReconcilePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcilePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationStrategy) DeepCopyInto(out *RemediationStrategy) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationStrategy.
+func (in *RemediationStrategy) DeepCopy() *RemediationStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceNamingSpec) DeepCopyInto(out *ResourceNamingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceNamingSpec.
+func (in *ResourceNamingSpec) DeepCopy() *ResourceNamingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceNamingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteTable) DeepCopyInto(out *RouteTable) {
 	*out = *in
@@ -2654,9 +3890,138 @@ func (in *RouteTable) DeepCopy() *RouteTable {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHEndpoint) DeepCopyInto(out *SSHEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHEndpoint.
+func (in *SSHEndpoint) DeepCopy() *SSHEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHKeyPairSpec) DeepCopyInto(out *SSHKeyPairSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHKeyPairSpec.
+func (in *SSHKeyPairSpec) DeepCopy() *SSHKeyPairSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHKeyPairSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHPublicKeysSpec) DeepCopyInto(out *SSHPublicKeysSpec) {
+	*out = *in
+	if in.ControlPlane != nil {
+		in, out := &in.ControlPlane, &out.ControlPlane
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHPublicKeysSpec.
+func (in *SSHPublicKeysSpec) DeepCopy() *SSHPublicKeysSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHPublicKeysSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecondaryRegionSpec) DeepCopyInto(out *SecondaryRegionSpec) {
+	*out = *in
+	if in.CIDRBlocks != nil {
+		in, out := &in.CIDRBlocks, &out.CIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]SecondaryRegionSubnetSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecondaryRegionSpec.
+func (in *SecondaryRegionSpec) DeepCopy() *SecondaryRegionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecondaryRegionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecondaryRegionSubnetSpec) DeepCopyInto(out *SecondaryRegionSubnetSpec) {
+	*out = *in
+	if in.CIDRBlocks != nil {
+		in, out := &in.CIDRBlocks, &out.CIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityRules != nil {
+		in, out := &in.SecurityRules, &out.SecurityRules
+		*out = make(SecurityRules, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecondaryRegionSubnetSpec.
+func (in *SecondaryRegionSubnetSpec) DeepCopy() *SecondaryRegionSubnetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecondaryRegionSubnetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecondaryUserDataSource) DeepCopyInto(out *SecondaryUserDataSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecondaryUserDataSource.
+func (in *SecondaryUserDataSource) DeepCopy() *SecondaryUserDataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SecondaryUserDataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecurityGroup) DeepCopyInto(out *SecurityGroup) {
 	*out = *in
+	if in.Existing != nil {
+		in, out := &in.Existing, &out.Existing
+		*out = new(ExistingSecurityGroup)
+		**out = **in
+	}
 	in.SecurityGroupClass.DeepCopyInto(&out.SecurityGroupClass)
 }
 
@@ -2886,6 +4251,18 @@ func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 	in.SecurityGroup.DeepCopyInto(&out.SecurityGroup)
 	out.RouteTable = in.RouteTable
 	in.NatGateway.DeepCopyInto(&out.NatGateway)
+	if in.AdditionalTags != nil {
+		in, out := &in.AdditionalTags, &out.AdditionalTags
+		*out = make(Tags, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CIDRBlockFromPool != nil {
+		in, out := &in.CIDRBlockFromPool, &out.CIDRBlockFromPool
+		*out = new(corev1.TypedLocalObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
 	in.SubnetClassSpec.DeepCopyInto(&out.SubnetClassSpec)
 }
 
@@ -2899,6 +4276,26 @@ func (in *SubnetSpec) DeepCopy() *SubnetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetStatus) DeepCopyInto(out *SubnetStatus) {
+	*out = *in
+	if in.CIDRBlocks != nil {
+		in, out := &in.CIDRBlocks, &out.CIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetStatus.
+func (in *SubnetStatus) DeepCopy() *SubnetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubnetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubnetTemplateSpec) DeepCopyInto(out *SubnetTemplateSpec) {
 	*out = *in
@@ -3184,6 +4581,21 @@ func (in Taints) DeepCopy() Taints {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficAnalyticsSpec) DeepCopyInto(out *TrafficAnalyticsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficAnalyticsSpec.
+func (in *TrafficAnalyticsSpec) DeepCopy() *TrafficAnalyticsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficAnalyticsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UefiSettings) DeepCopyInto(out *UefiSettings) {
 	*out = *in
@@ -3288,6 +4700,52 @@ func (in *VMExtension) DeepCopy() *VMExtension {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMStatus) DeepCopyInto(out *VMStatus) {
+	*out = *in
+	if in.NetworkInterfaceIDs != nil {
+		in, out := &in.NetworkInterfaceIDs, &out.NetworkInterfaceIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(Image)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PlatformFaultDomain != nil {
+		in, out := &in.PlatformFaultDomain, &out.PlatformFaultDomain
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMStatus.
+func (in *VMStatus) DeepCopy() *VMStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualNetworkGatewaySpec) DeepCopyInto(out *VirtualNetworkGatewaySpec) {
+	*out = *in
+	in.PublicIP.DeepCopyInto(&out.PublicIP)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNetworkGatewaySpec.
+func (in *VirtualNetworkGatewaySpec) DeepCopy() *VirtualNetworkGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualNetworkGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VnetClassSpec) DeepCopyInto(out *VnetClassSpec) {
 	*out = *in
@@ -3435,6 +4893,11 @@ func (in *VnetSpec) DeepCopyInto(out *VnetSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DDoSProtectionPlan != nil {
+		in, out := &in.DDoSProtectionPlan, &out.DDoSProtectionPlan
+		*out = new(DDoSProtectionPlanSpec)
+		**out = **in
+	}
 	in.VnetClassSpec.DeepCopyInto(&out.VnetClassSpec)
 }
 
@@ -3448,6 +4911,26 @@ func (in *VnetSpec) DeepCopy() *VnetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VnetStatus) DeepCopyInto(out *VnetStatus) {
+	*out = *in
+	if in.CIDRBlocks != nil {
+		in, out := &in.CIDRBlocks, &out.CIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VnetStatus.
+func (in *VnetStatus) DeepCopy() *VnetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VnetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VnetTemplateSpec) DeepCopyInto(out *VnetTemplateSpec) {
 	*out = *in
@@ -3470,3 +4953,33 @@ func (in *VnetTemplateSpec) DeepCopy() *VnetTemplateSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebApplicationFirewallSpec) DeepCopyInto(out *WebApplicationFirewallSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebApplicationFirewallSpec.
+func (in *WebApplicationFirewallSpec) DeepCopy() *WebApplicationFirewallSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WebApplicationFirewallSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneSubstitution) DeepCopyInto(out *ZoneSubstitution) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneSubstitution.
+func (in *ZoneSubstitution) DeepCopy() *ZoneSubstitution {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneSubstitution)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// AuditResultSucceeded is the result of a mutating ARM request accepted by Azure.
+	AuditResultSucceeded = "Succeeded"
+	// AuditResultFailed is the result of a mutating ARM request rejected by Azure or which failed without a response.
+	AuditResultFailed = "Failed"
+)
+
+// AuditRecord is the audit record of a mutating ARM request.
+type AuditRecord struct {
+	// Time is when the request was sent.
+	Time time.Time `json:"time"`
+	// Object is the Kubernetes object whose reconciliation sent the request, e.g. "AzureCluster default/my-cluster".
+	Object string `json:"object,omitempty"`
+	// Operation is the HTTP method of the request: PUT, PATCH, POST or DELETE.
+	Operation string `json:"operation"`
+	// ResourceID is the URL path of the request, which is the ID of the resource it changes.
+	ResourceID string `json:"resourceID"`
+	// RequestHash is the hex-encoded SHA-256 hash of the request body, or empty if the request has no body.
+	RequestHash string `json:"requestHash,omitempty"`
+	// Result is either Succeeded or Failed.
+	Result string `json:"result"`
+	// StatusCode is the status code of the response, or 0 if the request failed without a response.
+	StatusCode int `json:"statusCode,omitempty"`
+	// Error is the error of a request which failed without a response.
+	Error string `json:"error,omitempty"`
+	// CorrelationID is the correlation ID of the request, which can be used to find it in the Azure Activity Log.
+	CorrelationID string `json:"correlationID,omitempty"`
+	// RequestID is the ID ARM assigned to the request.
+	RequestID string `json:"requestID,omitempty"`
+}
+
+// AuditSink persists the audit records of mutating ARM requests.
+type AuditSink interface {
+	Write(record AuditRecord) error
+}
+
+var (
+	auditSinkMu sync.RWMutex
+	auditSink   AuditSink
+)
+
+// SetAuditSink sets the sink the audit records of all mutating ARM requests are persisted to, in addition to being
+// logged. Records are only logged when sink is nil.
+func SetAuditSink(sink AuditSink) {
+	auditSinkMu.Lock()
+	defer auditSinkMu.Unlock()
+	auditSink = sink
+}
+
+func getAuditSink() AuditSink {
+	auditSinkMu.RLock()
+	defer auditSinkMu.RUnlock()
+	return auditSink
+}
+
+// FileAuditSink appends audit records to a file as JSON lines.
+type FileAuditSink struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+var _ AuditSink = (*FileAuditSink)(nil)
+
+// NewFileAuditSink opens, or creates, the file at path to append audit records to.
+func NewFileAuditSink(path string) (*FileAuditSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open audit log file %s", path)
+	}
+	return &FileAuditSink{file: file, enc: json.NewEncoder(file)}, nil
+}
+
+// Write appends a record to the file.
+func (s *FileAuditSink) Write(record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(record)
+}
+
+// Close closes the file.
+func (s *FileAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// auditPolicy records an audit record of every mutating ARM request.
+// It implements the policy.Policy interface.
+type auditPolicy struct{}
+
+// Do sends the request and, if it is a mutating request, logs its audit record and writes it to the audit sink.
+// Failing to write a record doesn't fail the request, which has already been sent.
+func (p auditPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	if !isMutatingMethod(raw.Method) {
+		return req.Next()
+	}
+
+	record := AuditRecord{
+		Time:          time.Now().UTC(),
+		Operation:     raw.Method,
+		ResourceID:    raw.URL.Path,
+		CorrelationID: raw.Header.Get(string(tele.CorrIDKeyVal)),
+	}
+	if obj, ok := tele.ObjectFromCtx(raw.Context()); ok {
+		record.Object = obj.String()
+	}
+	hash, err := hashRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	record.RequestHash = hash
+
+	resp, err := req.Next()
+	record.Result = AuditResultFailed
+	switch {
+	case err != nil:
+		record.Error = err.Error()
+	case resp != nil:
+		record.StatusCode = resp.StatusCode
+		record.RequestID = resp.Header.Get(armRequestIDHeader)
+		if corrID := resp.Header.Get(string(tele.CorrIDKeyVal)); corrID != "" {
+			record.CorrelationID = corrID
+		}
+		if resp.StatusCode < http.StatusBadRequest {
+			record.Result = AuditResultSucceeded
+		}
+	}
+
+	logger := log.FromContext(raw.Context()).WithName("audit")
+	logger.Info("Azure API mutation",
+		"object", record.Object,
+		"operation", record.Operation,
+		"resourceID", record.ResourceID,
+		"requestHash", record.RequestHash,
+		"result", record.Result,
+		"statusCode", record.StatusCode,
+		string(tele.CorrIDKeyVal), record.CorrelationID,
+		armRequestIDHeader, record.RequestID,
+	)
+	if sink := getAuditSink(); sink != nil {
+		if sinkErr := sink.Write(record); sinkErr != nil {
+			logger.Error(sinkErr, "failed to write the audit record of an Azure API mutation")
+		}
+	}
+	return resp, err
+}
+
+// isMutatingMethod returns true for the HTTP methods of ARM requests which may change resources. POST requests are
+// actions, such as starting a VM, and are audited even though a few of them only read.
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// hashRequestBody returns the hex-encoded SHA-256 hash of the body of a request, or an empty string if it has none.
+// The body is rewound so that it can still be sent.
+func hashRequestBody(req *policy.Request) (string, error) {
+	body := req.Body()
+	if body == nil {
+		return "", nil
+	}
+	h := sha256.New()
+	n, err := io.Copy(h, body)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the request body")
+	}
+	if err := req.RewindBody(); err != nil {
+		return "", errors.Wrap(err, "failed to rewind the request body")
+	}
+	if n == 0 {
+		return "", nil
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
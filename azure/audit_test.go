@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// fakeAuditSink keeps the audit records written to it.
+type fakeAuditSink struct {
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+func (s *fakeAuditSink) Write(record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func TestAuditPolicy(t *testing.T) {
+	const (
+		corrID = "test-corr-id"
+		path   = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"
+		body   = `{"location":"eastus"}`
+	)
+	bodyHash := sha256.Sum256([]byte(body))
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		statusCode int
+		want       []AuditRecord
+	}{
+		{
+			name:       "successful PUT",
+			method:     http.MethodPut,
+			body:       body,
+			statusCode: http.StatusCreated,
+			want: []AuditRecord{{
+				Object:        "AzureCluster default/my-cluster",
+				Operation:     http.MethodPut,
+				ResourceID:    path,
+				RequestHash:   hex.EncodeToString(bodyHash[:]),
+				Result:        AuditResultSucceeded,
+				StatusCode:    http.StatusCreated,
+				CorrelationID: corrID,
+				RequestID:     "test-request-id",
+			}},
+		},
+		{
+			name:       "failed DELETE",
+			method:     http.MethodDelete,
+			statusCode: http.StatusConflict,
+			want: []AuditRecord{{
+				Object:        "AzureCluster default/my-cluster",
+				Operation:     http.MethodDelete,
+				ResourceID:    path,
+				Result:        AuditResultFailed,
+				StatusCode:    http.StatusConflict,
+				CorrelationID: corrID,
+				RequestID:     "test-request-id",
+			}},
+		},
+		{
+			name:       "GET is not audited",
+			method:     http.MethodGet,
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The body must still be sent after being hashed.
+				received, err := io.ReadAll(r.Body)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(string(received)).To(Equal(tc.body))
+				w.Header().Set(string(tele.CorrIDKeyVal), r.Header.Get(string(tele.CorrIDKeyVal)))
+				w.Header().Set(armRequestIDHeader, "test-request-id")
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			sink := &fakeAuditSink{}
+			SetAuditSink(sink)
+			defer SetAuditSink(nil)
+
+			ctx := context.WithValue(context.Background(), tele.CorrIDKeyVal, tele.CorrID(corrID))
+			ctx, _, done := tele.StartSpanWithLogger(ctx, "test",
+				tele.KVP("namespace", "default"),
+				tele.KVP("name", "my-cluster"),
+				tele.KVP("kind", "AzureCluster"),
+			)
+			defer done()
+			req, err := runtime.NewRequest(ctx, tc.method, server.URL+path)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.body != "" {
+				g.Expect(req.SetBody(streaming.NopCloser(strings.NewReader(tc.body)), "application/json")).To(Succeed())
+			}
+
+			resp, err := defaultTestPipeline([]policy.Policy{correlationIDPolicy{}, auditPolicy{}}).Do(req)
+			g.Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+
+			for i := range sink.records {
+				g.Expect(sink.records[i].Time).NotTo(BeZero())
+				sink.records[i].Time = tc.want[i].Time
+			}
+			g.Expect(sink.records).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestAuditPolicyLocallyThrottled(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("a locally throttled request was sent")
+	}))
+	defer server.Close()
+
+	sink := &fakeAuditSink{}
+	SetAuditSink(sink)
+	defer SetAuditSink(nil)
+
+	th := newThrottler()
+	th.block(throttlingKey{subscriptionID: "123", operation: rateLimitWrites}, time.Minute)
+
+	path := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"
+	req, err := runtime.NewRequest(context.Background(), http.MethodPut, server.URL+path)
+	g.Expect(err).NotTo(HaveOccurred())
+	resp, err := defaultTestPipeline([]policy.Policy{throttlingPolicy{throttler: th}, auditPolicy{}}).Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	defer resp.Body.Close()
+
+	g.Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+	g.Expect(sink.records).To(BeEmpty())
+}
+
+func TestFileAuditSink(t *testing.T) {
+	g := NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileAuditSink(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	records := []AuditRecord{
+		{Operation: http.MethodPut, ResourceID: "/a", Result: AuditResultSucceeded, StatusCode: http.StatusOK},
+		{Operation: http.MethodDelete, ResourceID: "/b", Result: AuditResultFailed, Error: "connection reset"},
+	}
+	for _, record := range records {
+		g.Expect(sink.Write(record)).To(Succeed())
+	}
+	g.Expect(sink.Close()).To(Succeed())
+
+	content, err := os.ReadFile(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	g.Expect(lines).To(HaveLen(len(records)))
+	for i, line := range lines {
+		var record AuditRecord
+		g.Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+		g.Expect(record.Time.Equal(records[i].Time)).To(BeTrue())
+		record.Time = records[i].Time
+		g.Expect(record).To(Equal(records[i]))
+	}
+}
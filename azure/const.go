@@ -35,6 +35,60 @@ const (
 	// for annotation formatting rules.
 	ManagedClusterTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-managedcluster"
 
+	// NICTagsLastAppliedAnnotation is the prefix of the key for the machine object annotations
+	// which track the AdditionalTags of each network interface. The index of the network interface is appended to it.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	NICTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-nic"
+
+	// OSDiskTagsLastAppliedAnnotation is the key for the machine object annotation
+	// which tracks the tags applied to the OS disk of the virtual machine.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	OSDiskTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-os-disk"
+
+	// VNetTagsLastAppliedAnnotation is the key for the Azure Cluster object annotation
+	// which tracks the AdditionalTags for the virtual network.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	VNetTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-vnet"
+
+	// APIServerLBTagsLastAppliedAnnotation is the key for the Azure Cluster object annotation
+	// which tracks the AdditionalTags for the API server load balancer.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	APIServerLBTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-apiserver-lb"
+
+	// NodeOutboundLBTagsLastAppliedAnnotation is the key for the Azure Cluster object annotation
+	// which tracks the AdditionalTags for the node outbound load balancer.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	NodeOutboundLBTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-node-outbound-lb"
+
+	// NodeInternalLBTagsLastAppliedAnnotation is the key for the Azure Cluster object annotation
+	// which tracks the AdditionalTags for the node internal load balancer.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	NodeInternalLBTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-node-internal-lb"
+
+	// ControlPlaneOutboundLBTagsLastAppliedAnnotation is the key for the Azure Cluster object annotation
+	// which tracks the AdditionalTags for the control plane outbound load balancer.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	ControlPlaneOutboundLBTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-cp-outbound-lb"
+
+	// SecurityGroupTagsLastAppliedAnnotation is the prefix of the key for the Azure Cluster object annotations
+	// which track the AdditionalTags for the network security group of each subnet, suffixed with the subnet index.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	SecurityGroupTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-nsg"
+
+	// RouteTableTagsLastAppliedAnnotation is the prefix of the key for the Azure Cluster object annotations
+	// which track the AdditionalTags for the route table of each subnet, suffixed with the subnet index.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	RouteTableTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-route-table"
+
 	// SecurityRuleLastAppliedAnnotation is the key for the Azure Cluster
 	// object annotation which tracks the security rules for security groups.
 	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
@@ -46,4 +100,8 @@ const (
 	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
 	// for annotation formatting rules.
 	CustomDataHashAnnotation = "sigs.k8s.io/cluster-api-provider-azure-vmss-custom-data-hash"
+
+	// SSHKeyPairPublicKey is the key of the public key in the Secret holding a cluster SSH key pair.
+	// The private key is stored under the standard corev1.SSHAuthPrivateKey key.
+	SSHKeyPairPublicKey = "ssh-publickey"
 )
@@ -23,8 +23,11 @@ import (
 
 // SKUtoSDK converts infrav1.SKU into an armnetwork.LoadBalancerSKUName.
 func SKUtoSDK(src infrav1.SKU) armnetwork.LoadBalancerSKUName {
-	if src == infrav1.SKUStandard {
+	switch src {
+	case infrav1.SKUStandard:
 		return armnetwork.LoadBalancerSKUNameStandard
+	case infrav1.SKUBasic:
+		return armnetwork.LoadBalancerSKUNameBasic
 	}
 	return ""
 }
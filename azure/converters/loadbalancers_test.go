@@ -35,6 +35,11 @@ func TestSKUtoSDK(t *testing.T) {
 			sku:  infrav1.SKUStandard,
 			want: armnetwork.LoadBalancerSKUNameStandard,
 		},
+		{
+			name: "basic sku",
+			sku:  infrav1.SKUBasic,
+			want: armnetwork.LoadBalancerSKUNameBasic,
+		},
 		{
 			name: "unknown",
 			sku:  "unknown",
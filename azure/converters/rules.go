@@ -46,6 +46,15 @@ func SecurityRuleToSDK(rule infrav1.SecurityRule) *armnetwork.SecurityRule {
 		secRule.Properties.Protocol = ptr.To(armnetwork.SecurityRuleProtocolUDP)
 	case infrav1.SecurityGroupProtocolICMP:
 		secRule.Properties.Protocol = ptr.To(armnetwork.SecurityRuleProtocolIcmp)
+	case infrav1.SecurityGroupProtocolICMPv6:
+		// Azure matches ICMPv6 with the Icmp protocol, the address prefixes select the IP family.
+		secRule.Properties.Protocol = ptr.To(armnetwork.SecurityRuleProtocolIcmp)
+		if ptr.Deref(rule.Source, "*") == "*" {
+			secRule.Properties.SourceAddressPrefix = ptr.To(infrav1.IPv6AnyAddressPrefix)
+		}
+		if ptr.Deref(rule.Destination, "*") == "*" {
+			secRule.Properties.DestinationAddressPrefix = ptr.To(infrav1.IPv6AnyAddressPrefix)
+		}
 	}
 
 	switch rule.Direction {
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestSecurityRuleToSDK(t *testing.T) {
+	tests := []struct {
+		name string
+		rule infrav1.SecurityRule
+		want *armnetwork.SecurityRule
+	}{
+		{
+			name: "tcp rule",
+			rule: infrav1.SecurityRule{
+				Name:             "allow_ssh",
+				Description:      "Allow SSH",
+				Priority:         2200,
+				Protocol:         infrav1.SecurityGroupProtocolTCP,
+				Direction:        infrav1.SecurityRuleDirectionInbound,
+				Source:           ptr.To("*"),
+				SourcePorts:      ptr.To("*"),
+				Destination:      ptr.To("*"),
+				DestinationPorts: ptr.To("22"),
+				Action:           infrav1.SecurityRuleActionAllow,
+			},
+			want: &armnetwork.SecurityRule{
+				Name: ptr.To("allow_ssh"),
+				Properties: &armnetwork.SecurityRulePropertiesFormat{
+					Description:              ptr.To("Allow SSH"),
+					SourceAddressPrefix:      ptr.To("*"),
+					SourcePortRange:          ptr.To("*"),
+					DestinationAddressPrefix: ptr.To("*"),
+					DestinationPortRange:     ptr.To("22"),
+					Access:                   ptr.To(armnetwork.SecurityRuleAccessAllow),
+					Priority:                 ptr.To[int32](2200),
+					Protocol:                 ptr.To(armnetwork.SecurityRuleProtocolTCP),
+					Direction:                ptr.To(armnetwork.SecurityRuleDirectionInbound),
+				},
+			},
+		},
+		{
+			name: "icmpv6 rule matching any address",
+			rule: infrav1.SecurityRule{
+				Name:             "allow_icmpv6",
+				Description:      "Allow ICMPv6",
+				Priority:         2202,
+				Protocol:         infrav1.SecurityGroupProtocolICMPv6,
+				Direction:        infrav1.SecurityRuleDirectionInbound,
+				Source:           ptr.To("*"),
+				SourcePorts:      ptr.To("*"),
+				DestinationPorts: ptr.To("*"),
+				Action:           infrav1.SecurityRuleActionAllow,
+			},
+			want: &armnetwork.SecurityRule{
+				Name: ptr.To("allow_icmpv6"),
+				Properties: &armnetwork.SecurityRulePropertiesFormat{
+					Description:              ptr.To("Allow ICMPv6"),
+					SourceAddressPrefix:      ptr.To("::/0"),
+					SourcePortRange:          ptr.To("*"),
+					DestinationAddressPrefix: ptr.To("::/0"),
+					DestinationPortRange:     ptr.To("*"),
+					Access:                   ptr.To(armnetwork.SecurityRuleAccessAllow),
+					Priority:                 ptr.To[int32](2202),
+					Protocol:                 ptr.To(armnetwork.SecurityRuleProtocolIcmp),
+					Direction:                ptr.To(armnetwork.SecurityRuleDirectionInbound),
+				},
+			},
+		},
+		{
+			name: "icmpv6 rule with an explicit destination",
+			rule: infrav1.SecurityRule{
+				Name:             "allow_icmpv6",
+				Description:      "Allow ICMPv6",
+				Priority:         2202,
+				Protocol:         infrav1.SecurityGroupProtocolICMPv6,
+				Direction:        infrav1.SecurityRuleDirectionInbound,
+				Source:           ptr.To("*"),
+				SourcePorts:      ptr.To("*"),
+				Destination:      ptr.To("2001:1234:5678:9abc::/64"),
+				DestinationPorts: ptr.To("*"),
+				Action:           infrav1.SecurityRuleActionAllow,
+			},
+			want: &armnetwork.SecurityRule{
+				Name: ptr.To("allow_icmpv6"),
+				Properties: &armnetwork.SecurityRulePropertiesFormat{
+					Description:              ptr.To("Allow ICMPv6"),
+					SourceAddressPrefix:      ptr.To("::/0"),
+					SourcePortRange:          ptr.To("*"),
+					DestinationAddressPrefix: ptr.To("2001:1234:5678:9abc::/64"),
+					DestinationPortRange:     ptr.To("*"),
+					Access:                   ptr.To(armnetwork.SecurityRuleAccessAllow),
+					Priority:                 ptr.To[int32](2202),
+					Protocol:                 ptr.To(armnetwork.SecurityRuleProtocolIcmp),
+					Direction:                ptr.To(armnetwork.SecurityRuleDirectionInbound),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			g.Expect(SecurityRuleToSDK(tt.rule)).To(Equal(tt.want))
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package converters
 
 import (
+	"strings"
+
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
@@ -27,17 +29,25 @@ import (
 type VM struct {
 	ID               string `json:"id,omitempty"`
 	Name             string `json:"name,omitempty"`
+	VMID             string `json:"vmID,omitempty"`
 	AvailabilityZone string `json:"availabilityZone,omitempty"`
+	// PlatformFaultDomain - The fault domain of the VM, which only appears when the instance view is included in the response.
+	PlatformFaultDomain *int32 `json:"platformFaultDomain,omitempty"`
 	// Hardware profile
 	VMSize string `json:"vmSize,omitempty"`
 	// Storage profile
-	Image         infrav1.Image  `json:"image,omitempty"`
+	Image         *infrav1.Image `json:"image,omitempty"`
 	OSDisk        infrav1.OSDisk `json:"osDisk,omitempty"`
+	OSDiskID      string         `json:"osDiskID,omitempty"`
 	StartupScript string         `json:"startupScript,omitempty"`
+	// Network profile
+	NetworkInterfaceIDs []string `json:"networkInterfaceIDs,omitempty"`
 	// State - The provisioning state, which only appears in the response.
-	State    infrav1.ProvisioningState `json:"vmState,omitempty"`
-	Identity infrav1.VMIdentity        `json:"identity,omitempty"`
-	Tags     infrav1.Tags              `json:"tags,omitempty"`
+	State infrav1.ProvisioningState `json:"vmState,omitempty"`
+	// PowerState - The power state, which only appears when the instance view is included in the response.
+	PowerState infrav1.VMPowerState `json:"powerState,omitempty"`
+	Identity   infrav1.VMIdentity   `json:"identity,omitempty"`
+	Tags       infrav1.Tags         `json:"tags,omitempty"`
 
 	// Addresses contains the addresses associated with the Azure VM.
 	Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
@@ -45,6 +55,51 @@ type VM struct {
 	UserAssignedIdentities []infrav1.UserAssignedIdentity `json:"userAssignedIdentities,omitempty"`
 }
 
+// SDKToVMPowerState converts the statuses of an Azure SDK VirtualMachine instance view to a VM power state. A
+// deallocated VM with the hibernated hibernation state is hibernated.
+func SDKToVMPowerState(statuses []*armcompute.InstanceViewStatus) infrav1.VMPowerState {
+	powerState := sdkToVMPowerState(statuses)
+	if powerState != infrav1.VMPowerStateDeallocated {
+		return powerState
+	}
+	for _, status := range statuses {
+		if status != nil && ptr.Deref(status.Code, "") == "HibernationState/Hibernated" {
+			return infrav1.VMPowerStateHibernated
+		}
+	}
+	return powerState
+}
+
+// sdkToVMPowerState converts the power state status of an Azure SDK VirtualMachine instance view to a VM power state.
+func sdkToVMPowerState(statuses []*armcompute.InstanceViewStatus) infrav1.VMPowerState {
+	for _, status := range statuses {
+		if status == nil || status.Code == nil {
+			continue
+		}
+		code, found := strings.CutPrefix(*status.Code, "PowerState/")
+		if !found {
+			continue
+		}
+		switch code {
+		case "starting":
+			return infrav1.VMPowerStateStarting
+		case "running":
+			return infrav1.VMPowerStateRunning
+		case "stopping":
+			return infrav1.VMPowerStateStopping
+		case "stopped":
+			return infrav1.VMPowerStateStopped
+		case "deallocating":
+			return infrav1.VMPowerStateDeallocating
+		case "deallocated":
+			return infrav1.VMPowerStateDeallocated
+		default:
+			return infrav1.VMPowerStateUnknown
+		}
+	}
+	return ""
+}
+
 // SDKToVM converts an Azure SDK VirtualMachine to the CAPZ VM type.
 func SDKToVM(v armcompute.VirtualMachine) *VM {
 	vm := &VM{
@@ -53,10 +108,41 @@ func SDKToVM(v armcompute.VirtualMachine) *VM {
 		State: infrav1.ProvisioningState(ptr.Deref(v.Properties.ProvisioningState, "")),
 	}
 
+	if v.Properties != nil {
+		vm.VMID = ptr.Deref(v.Properties.VMID, "")
+	}
+
+	if v.Properties != nil && v.Properties.InstanceView != nil {
+		vm.PowerState = SDKToVMPowerState(v.Properties.InstanceView.Statuses)
+		vm.PlatformFaultDomain = v.Properties.InstanceView.PlatformFaultDomain
+	}
+
 	if v.Properties != nil && v.Properties.HardwareProfile != nil && v.Properties.HardwareProfile.VMSize != nil {
 		vm.VMSize = string(*v.Properties.HardwareProfile.VMSize)
 	}
 
+	if v.Properties != nil && v.Properties.StorageProfile != nil {
+		if imageRef := v.Properties.StorageProfile.ImageReference; imageRef != nil {
+			image := SDKImageToImage(imageRef, v.Plan != nil)
+			// The exact version is the version that was applied when the image reference uses 'latest'.
+			if image.Marketplace != nil && imageRef.ExactVersion != nil {
+				image.Marketplace.Version = *imageRef.ExactVersion
+			}
+			vm.Image = &image
+		}
+		if osDisk := v.Properties.StorageProfile.OSDisk; osDisk != nil && osDisk.ManagedDisk != nil {
+			vm.OSDiskID = ptr.Deref(osDisk.ManagedDisk.ID, "")
+		}
+	}
+
+	if v.Properties != nil && v.Properties.NetworkProfile != nil {
+		for _, nic := range v.Properties.NetworkProfile.NetworkInterfaces {
+			if nic != nil && nic.ID != nil {
+				vm.NetworkInterfaceIDs = append(vm.NetworkInterfaceIDs, *nic.ID)
+			}
+		}
+	}
+
 	if len(v.Zones) > 0 && v.Zones[0] != nil {
 		vm.AvailabilityZone = *v.Zones[0]
 	}
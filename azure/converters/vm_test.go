@@ -66,6 +66,72 @@ func TestSDKToVM(t *testing.T) {
 				VMSize: "Standard_A1",
 			},
 		},
+		{
+			name: "Should convert and populate with power state",
+			sdk: armcompute.VirtualMachine{
+				ID:   ptr.To("test-vm-id"),
+				Name: ptr.To("test-vm-name"),
+				Properties: &armcompute.VirtualMachineProperties{
+					ProvisioningState: ptr.To("Succeeded"),
+					InstanceView: &armcompute.VirtualMachineInstanceView{
+						Statuses: []*armcompute.InstanceViewStatus{
+							{Code: ptr.To("ProvisioningState/succeeded")},
+							{Code: ptr.To("PowerState/deallocated")},
+						},
+					},
+				},
+			},
+			want: &VM{
+				ID:         "test-vm-id",
+				Name:       "test-vm-name",
+				State:      infrav1.ProvisioningState("Succeeded"),
+				PowerState: infrav1.VMPowerStateDeallocated,
+			},
+		},
+		{
+			name: "Should convert and populate with hibernated power state",
+			sdk: armcompute.VirtualMachine{
+				ID:   ptr.To("test-vm-id"),
+				Name: ptr.To("test-vm-name"),
+				Properties: &armcompute.VirtualMachineProperties{
+					ProvisioningState: ptr.To("Succeeded"),
+					InstanceView: &armcompute.VirtualMachineInstanceView{
+						Statuses: []*armcompute.InstanceViewStatus{
+							{Code: ptr.To("ProvisioningState/succeeded")},
+							{Code: ptr.To("PowerState/deallocated")},
+							{Code: ptr.To("HibernationState/Hibernated")},
+						},
+					},
+				},
+			},
+			want: &VM{
+				ID:         "test-vm-id",
+				Name:       "test-vm-name",
+				State:      infrav1.ProvisioningState("Succeeded"),
+				PowerState: infrav1.VMPowerStateHibernated,
+			},
+		},
+		{
+			name: "Should convert and populate with VM ID and platform fault domain",
+			sdk: armcompute.VirtualMachine{
+				ID:   ptr.To("test-vm-id"),
+				Name: ptr.To("test-vm-name"),
+				Properties: &armcompute.VirtualMachineProperties{
+					ProvisioningState: ptr.To("Succeeded"),
+					VMID:              ptr.To("4a1c6b1e-0d0f-4a8e-9b8a-2b2f6c1d0e3f"),
+					InstanceView: &armcompute.VirtualMachineInstanceView{
+						PlatformFaultDomain: ptr.To[int32](1),
+					},
+				},
+			},
+			want: &VM{
+				ID:                  "test-vm-id",
+				Name:                "test-vm-name",
+				VMID:                "4a1c6b1e-0d0f-4a8e-9b8a-2b2f6c1d0e3f",
+				State:               infrav1.ProvisioningState("Succeeded"),
+				PlatformFaultDomain: ptr.To[int32](1),
+			},
+		},
 		{
 			name: "Should convert and populate with availability zones",
 			sdk: armcompute.VirtualMachine{
@@ -100,6 +166,51 @@ func TestSDKToVM(t *testing.T) {
 				Tags:  infrav1.Tags{"foo": "bar"},
 			},
 		},
+		{
+			name: "Should convert and populate with resource IDs and the applied image",
+			sdk: armcompute.VirtualMachine{
+				ID:   ptr.To("test-vm-id"),
+				Name: ptr.To("test-vm-name"),
+				Properties: &armcompute.VirtualMachineProperties{
+					ProvisioningState: ptr.To("Succeeded"),
+					StorageProfile: &armcompute.StorageProfile{
+						ImageReference: &armcompute.ImageReference{
+							Publisher:    ptr.To("test-publisher"),
+							Offer:        ptr.To("test-offer"),
+							SKU:          ptr.To("test-sku"),
+							Version:      ptr.To("latest"),
+							ExactVersion: ptr.To("1.2.3"),
+						},
+						OSDisk: &armcompute.OSDisk{
+							ManagedDisk: &armcompute.ManagedDiskParameters{ID: ptr.To("test-os-disk-id")},
+						},
+					},
+					NetworkProfile: &armcompute.NetworkProfile{
+						NetworkInterfaces: []*armcompute.NetworkInterfaceReference{
+							{ID: ptr.To("test-nic-id-1")},
+							{ID: ptr.To("test-nic-id-2")},
+						},
+					},
+				},
+			},
+			want: &VM{
+				ID:    "test-vm-id",
+				Name:  "test-vm-name",
+				State: infrav1.ProvisioningState("Succeeded"),
+				Image: &infrav1.Image{
+					Marketplace: &infrav1.AzureMarketplaceImage{
+						ImagePlan: infrav1.ImagePlan{
+							Publisher: "test-publisher",
+							Offer:     "test-offer",
+							SKU:       "test-sku",
+						},
+						Version: "1.2.3",
+					},
+				},
+				OSDiskID:            "test-os-disk-id",
+				NetworkInterfaceIDs: []string{"test-nic-id-1", "test-nic-id-2"},
+			},
+		},
 		{
 			name: "Should convert and populate with all fields",
 			sdk: armcompute.VirtualMachine{
@@ -17,15 +17,30 @@ limitations under the License.
 package azure
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
+	"unicode"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/faultinjection"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	"sigs.k8s.io/cluster-api-provider-azure/version"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 const (
@@ -64,6 +79,15 @@ const (
 	BootstrappingExtensionLinux = "CAPZ.Linux.Bootstrapping"
 	// BootstrappingExtensionWindows is the name of the Windows CAPZ bootstrapping VM extension.
 	BootstrappingExtensionWindows = "CAPZ.Windows.Bootstrapping"
+	// BootstrapScriptExtensionLinux is the name of the Custom Script VM extension running bootstrap data which is a shell
+	// script on Linux.
+	BootstrapScriptExtensionLinux = "CustomScript"
+	// SSHKeyRotationExtensionLinux is the name of the VMAccess VM extension replacing the SSH public keys of a Linux VM.
+	SSHKeyRotationExtensionLinux = "VMAccessForLinux"
+	// AzureDiskEncryptionExtensionLinux is the name of the Azure Disk Encryption VM extension on Linux.
+	AzureDiskEncryptionExtensionLinux = "AzureDiskEncryptionForLinux"
+	// AzureDiskEncryptionExtensionWindows is the name of the Azure Disk Encryption VM extension on Windows.
+	AzureDiskEncryptionExtensionWindows = "AzureDiskEncryption"
 )
 
 const (
@@ -131,12 +155,12 @@ func GenerateFrontendIPConfigName(lbName string) string {
 
 // GenerateNodeOutboundIPName generates a public IP name, based on the cluster name.
 func GenerateNodeOutboundIPName(clusterName string) string {
-	return fmt.Sprintf("pip-%s-node-outbound", clusterName)
+	return azureutil.TruncateName("pip-"+clusterName, "-node-outbound", azureutil.MaxResourceNameLength)
 }
 
 // GenerateNodePublicIPName generates a node public IP name, based on the machine name.
 func GenerateNodePublicIPName(machineName string) string {
-	return fmt.Sprintf("pip-%s", machineName)
+	return azureutil.TruncateName("pip-"+machineName, "", azureutil.MaxResourceNameLength)
 }
 
 // GenerateControlPlaneOutboundLBName generates the name of the control plane outbound LB.
@@ -146,7 +170,7 @@ func GenerateControlPlaneOutboundLBName(clusterName string) string {
 
 // GenerateControlPlaneOutboundIPName generates a public IP name, based on the cluster name.
 func GenerateControlPlaneOutboundIPName(clusterName string) string {
-	return fmt.Sprintf("pip-%s-controlplane-outbound", clusterName)
+	return azureutil.TruncateName("pip-"+clusterName, "-controlplane-outbound", azureutil.MaxResourceNameLength)
 }
 
 // GeneratePrivateDNSZoneName generates the name of a private DNS zone based on the cluster name.
@@ -167,24 +191,36 @@ func GenerateVNetLinkName(vnetName string) string {
 // GenerateNICName generates the name of a network interface based on the name of a VM.
 func GenerateNICName(machineName string, multiNIC bool, index int) string {
 	if multiNIC {
-		return fmt.Sprintf("%s-nic-%d", machineName, index)
+		return azureutil.TruncateName(machineName, fmt.Sprintf("-nic-%d", index), azureutil.MaxResourceNameLength)
 	}
-	return fmt.Sprintf("%s-nic", machineName)
+	return azureutil.TruncateName(machineName, "-nic", azureutil.MaxResourceNameLength)
 }
 
 // GeneratePublicNICName generates the name of a public network interface based on the name of a VM.
 func GeneratePublicNICName(machineName string) string {
-	return fmt.Sprintf("%s-public-nic", machineName)
+	return azureutil.TruncateName(machineName, "-public-nic", azureutil.MaxResourceNameLength)
+}
+
+// GenerateNICIPAddressClaimName generates the name of the IPAddressClaim of an IP configuration of a network interface
+// of a machine.
+func GenerateNICIPAddressClaimName(machineName string, nicIndex, ipConfigIndex int) string {
+	return fmt.Sprintf("%s-nic-%d-ipconfig-%d", machineName, nicIndex, ipConfigIndex)
+}
+
+// GenerateSubnetIPAddressClaimName generates the name of the IPAddressClaim of the address space of a subnet. Subnet
+// names may contain upper case letters and underscores, which are not allowed in Kubernetes object names.
+func GenerateSubnetIPAddressClaimName(clusterName, subnetName string) string {
+	return fmt.Sprintf("%s-%s-cidr", clusterName, strings.ReplaceAll(strings.ToLower(subnetName), "_", "-"))
 }
 
 // GenerateOSDiskName generates the name of an OS disk based on the name of a VM.
 func GenerateOSDiskName(machineName string) string {
-	return fmt.Sprintf("%s_OSDisk", machineName)
+	return azureutil.TruncateName(machineName, "_OSDisk", azureutil.MaxResourceNameLength)
 }
 
 // GenerateDataDiskName generates the name of a data disk based on the name of a VM.
 func GenerateDataDiskName(machineName, nameSuffix string) string {
-	return fmt.Sprintf("%s_%s", machineName, nameSuffix)
+	return azureutil.TruncateName(machineName, "_"+nameSuffix, azureutil.MaxResourceNameLength)
 }
 
 // GenerateVnetPeeringName generates the name for a peering between two vnets.
@@ -200,6 +236,47 @@ func GenerateAvailabilitySetName(clusterName, nodeGroup string) string {
 	return fmt.Sprintf("%s_%s-as", clusterName, nodeGroup)
 }
 
+// GenerateSSHKeyPairSecretName generates the name of the Secret holding the cluster SSH key pair.
+func GenerateSSHKeyPairSecretName(clusterName string) string {
+	return fmt.Sprintf("%s-ssh-keypair", clusterName)
+}
+
+// GenerateBootstrapDataStorageAccountName generates the name of the storage account holding bootstrap data. Storage account
+// names are globally unique and limited to 24 lowercase alphanumeric characters, so the name is derived from a hash.
+func GenerateBootstrapDataStorageAccountName(subscriptionID, resourceGroup, clusterName string) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s", subscriptionID, resourceGroup, clusterName)))
+	return fmt.Sprintf("capzboot%s", hex.EncodeToString(hash[:])[:16])
+}
+
+// GenerateResourceGroupLockName generates the name of the management lock protecting the resource group of a cluster.
+func GenerateResourceGroupLockName(clusterName string) string {
+	return fmt.Sprintf("%s-deletion-protection", clusterName)
+}
+
+// GenerateManagedIdentityName generates the name of the user-assigned identity of a cluster.
+func GenerateManagedIdentityName(clusterName string) string {
+	return fmt.Sprintf("%s-identity", clusterName)
+}
+
+// GenerateRoleAssignmentName generates the name of the role assignment of a role definition to a principal on a scope.
+// Role assignment names must be GUIDs, so the name is a UUID derived from its arguments, which stays the same across
+// reconciliations.
+func GenerateRoleAssignmentName(principal, roleDefinitionID, scope string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(strings.ToLower(principal+"|"+roleDefinitionID+"|"+scope))).String()
+}
+
+// GenerateFlowLogName generates the name of the NSG flow log of a network security group. Flow logs of all the network
+// security groups of a location share a network watcher, so the name includes the resource group of the security group.
+func GenerateFlowLogName(nsgName, resourceGroup string) string {
+	return fmt.Sprintf("%s-%s-flowlog", nsgName, resourceGroup)
+}
+
+// GenerateDiagnosticSettingName generates the name of the Azure Monitor diagnostic settings created by a cluster.
+// Diagnostic settings can't be tagged, so the name identifies the cluster which created them.
+func GenerateDiagnosticSettingName(clusterName string) string {
+	return fmt.Sprintf("%s-diagnostics", clusterName)
+}
+
 // WithIndex appends the index as suffix to a generated name.
 func WithIndex(name string, n int) string {
 	return fmt.Sprintf("%s-%d", name, n)
@@ -215,6 +292,11 @@ func VMID(subscriptionID, resourceGroup, vmName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines/%s", subscriptionID, resourceGroup, vmName)
 }
 
+// DiskID returns the azure resource ID for a given managed disk.
+func DiskID(subscriptionID, resourceGroup, diskName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/disks/%s", subscriptionID, resourceGroup, diskName)
+}
+
 // VNetID returns the azure resource ID for a given VNet.
 func VNetID(subscriptionID, resourceGroup, vnetName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s", subscriptionID, resourceGroup, vnetName)
@@ -240,6 +322,16 @@ func SecurityGroupID(subscriptionID, resourceGroup, nsgName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkSecurityGroups/%s", subscriptionID, resourceGroup, nsgName)
 }
 
+// DDoSProtectionPlanID returns the azure resource ID for a given DDoS protection plan.
+func DDoSProtectionPlanID(subscriptionID, resourceGroup, planName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/ddosProtectionPlans/%s", subscriptionID, resourceGroup, planName)
+}
+
+// ApplicationGatewayID returns the azure resource ID for a given application gateway.
+func ApplicationGatewayID(subscriptionID, resourceGroup, appGatewayName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/applicationGateways/%s", subscriptionID, resourceGroup, appGatewayName)
+}
+
 // NatGatewayID returns the azure resource ID for a given NAT gateway.
 func NatGatewayID(subscriptionID, resourceGroup, natgatewayName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/natGateways/%s", subscriptionID, resourceGroup, natgatewayName)
@@ -250,6 +342,11 @@ func NetworkInterfaceID(subscriptionID, resourceGroup, nicName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkInterfaces/%s", subscriptionID, resourceGroup, nicName)
 }
 
+// LoadBalancerID returns the azure resource ID for a given load balancer.
+func LoadBalancerID(subscriptionID, resourceGroup, loadBalancerName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/loadBalancers/%s", subscriptionID, resourceGroup, loadBalancerName)
+}
+
 // FrontendIPConfigID returns the azure resource ID for a given frontend IP config.
 func FrontendIPConfigID(subscriptionID, resourceGroup, loadBalancerName, configName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/loadBalancers/%s/frontendIPConfigurations/%s", subscriptionID, resourceGroup, loadBalancerName, configName)
@@ -290,6 +387,21 @@ func ManagedClusterID(subscriptionID, resourceGroup, managedClusterName string)
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerService/managedClusters/%s", subscriptionID, resourceGroup, managedClusterName)
 }
 
+// UserAssignedIdentityID returns the azure resource ID for a given user-assigned identity.
+func UserAssignedIdentityID(subscriptionID, resourceGroup, identityName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ManagedIdentity/userAssignedIdentities/%s", subscriptionID, resourceGroup, identityName)
+}
+
+// DNSRecordSetID returns the azure resource ID for a given record set of a public DNS zone.
+func DNSRecordSetID(subscriptionID, resourceGroup, dnsZoneName, recordType, recordName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/dnsZones/%s/%s/%s", subscriptionID, resourceGroup, dnsZoneName, recordType, recordName)
+}
+
+// DiagnosticSettingID returns the azure resource ID for a given diagnostic setting of a resource.
+func DiagnosticSettingID(resourceID, diagnosticSettingName string) string {
+	return fmt.Sprintf("%s/providers/Microsoft.Insights/diagnosticSettings/%s", resourceID, diagnosticSettingName)
+}
+
 // GetBootstrappingVMExtension returns the CAPZ Bootstrapping VM extension.
 // The CAPZ Bootstrapping extension is a simple clone of https://github.com/Azure/custom-script-extension-linux for Linux or
 // https://learn.microsoft.com/azure/virtual-machines/extensions/custom-script-windows for Windows.
@@ -333,14 +445,135 @@ func GetBootstrappingVMExtension(osType string, cloud string, vmName string, cpu
 	return nil
 }
 
+// GetBootstrapScriptVMExtension returns the spec of the Custom Script extension running the bootstrap data of a Linux
+// VM, a base64 encoded shell script, in place of cloud-init.
+func GetBootstrapScriptVMExtension(vmName string, script string) *ExtensionSpec {
+	return &ExtensionSpec{
+		Name:      BootstrapScriptExtensionLinux,
+		VMName:    vmName,
+		Publisher: "Microsoft.Azure.Extensions",
+		Version:   "2.1",
+		ProtectedSettings: map[string]string{
+			"script": script,
+		},
+	}
+}
+
+// DecodeSSHPublicKeys decodes the given base64-encoded SSH public keys, in order, dropping empty and duplicate keys.
+func DecodeSSHPublicKeys(keys []string) ([]string, error) {
+	decoded := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		data, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode ssh public key %d", i)
+		}
+		if len(data) == 0 || seen[string(data)] {
+			continue
+		}
+		seen[string(data)] = true
+		decoded = append(decoded, string(data))
+	}
+	return decoded, nil
+}
+
+// GetSSHKeyRotationVMExtension returns the spec of the VMAccess extension replacing the authorized SSH public keys of
+// the admin user of a Linux VM with the given newline-separated keys. The extension runs again whenever the keys change.
+func GetSSHKeyRotationVMExtension(vmName, username, sshKeys string) *ExtensionSpec {
+	hash := sha256.Sum256([]byte(username + "\n" + sshKeys))
+	return &ExtensionSpec{
+		Name:      SSHKeyRotationExtensionLinux,
+		VMName:    vmName,
+		Publisher: "Microsoft.OSTCExtensions",
+		Version:   "1.5",
+		ProtectedSettings: map[string]string{
+			"username":          username,
+			"ssh_key":           sshKeys,
+			"remove_prior_keys": "true",
+		},
+		ForceUpdateTag: hex.EncodeToString(hash[:8]),
+	}
+}
+
+// GetAzureDiskEncryptionVMExtension returns the spec of the Azure Disk Encryption extension encrypting the volumes of a
+// VM, with the disk encryption secrets stored in a Key Vault and wrapped with a Key Vault key.
+func GetAzureDiskEncryptionVMExtension(osType string, vmName string, ade *infrav1.AzureDiskEncryption) *ExtensionSpec {
+	name, version := AzureDiskEncryptionExtensionLinux, "1.1"
+	if osType == WindowsOS {
+		name, version = AzureDiskEncryptionExtensionWindows, "2.2"
+	}
+	kekVaultID := ade.KeyEncryptionKeyVaultID
+	if kekVaultID == "" {
+		kekVaultID = ade.KeyVaultID
+	}
+	volumeType := ade.VolumeType
+	if volumeType == "" {
+		volumeType = infrav1.AzureDiskEncryptionVolumeTypeAll
+	}
+	return &ExtensionSpec{
+		Name:      name,
+		VMName:    vmName,
+		Publisher: "Microsoft.Azure.Security",
+		Version:   version,
+		Settings: map[string]string{
+			"EncryptionOperation":    "EnableEncryption",
+			"KeyVaultURL":            ade.KeyVaultURL,
+			"KeyVaultResourceId":     ade.KeyVaultID,
+			"KeyEncryptionKeyURL":    ade.KeyEncryptionKeyURL,
+			"KekVaultResourceId":     kekVaultID,
+			"KeyEncryptionAlgorithm": "RSA-OAEP",
+			"VolumeType":             string(volumeType),
+		},
+	}
+}
+
+// userAgentExtensions are appended to CAPZ's user agent, see SetUserAgentExtensions.
+var userAgentExtensions string
+
+// SetUserAgentExtensions appends a partner ID and a custom suffix to the user agent of all ARM requests.
+// The partner ID is the GUID used by Azure customer usage attribution, sent as "pid-<GUID>", so that products
+// embedding CAPZ get their Azure usage attributed and can trace their API traffic.
+func SetUserAgentExtensions(suffix, partnerID string) error {
+	var extensions []string
+	if partnerID != "" {
+		id, err := uuid.Parse(partnerID)
+		if err != nil {
+			return errors.Wrapf(err, "invalid partner ID %q", partnerID)
+		}
+		extensions = append(extensions, "pid-"+id.String())
+	}
+	if suffix != "" {
+		if strings.IndexFunc(suffix, unicode.IsControl) >= 0 {
+			return errors.Errorf("invalid user agent suffix %q: control characters are not allowed", suffix)
+		}
+		extensions = append(extensions, suffix)
+	}
+	userAgentExtensions = strings.Join(extensions, " ")
+	return nil
+}
+
 // UserAgent specifies a string to append to the agent identifier.
 func UserAgent() string {
-	return fmt.Sprintf("cluster-api-provider-azure/%s", version.Get().String())
+	userAgent := fmt.Sprintf("cluster-api-provider-azure/%s", version.Get().String())
+	if userAgentExtensions != "" {
+		userAgent += " " + userAgentExtensions
+	}
+	return userAgent
+}
+
+// registeredClouds holds the client options of the clouds registered with RegisterCloud, keyed by cloud name.
+var registeredClouds sync.Map
+
+// RegisterCloud makes ARMClientOptions send the requests for the cloud named name to the endpoints of
+// clientOptions.Cloud with clientOptions.Transport. It lets tests target a simulated ARM server.
+func RegisterCloud(name string, clientOptions policy.ClientOptions) {
+	registeredClouds.Store(name, clientOptions)
 }
 
 // ARMClientOptions returns default ARM client options for CAPZ SDK v2 requests.
 func ARMClientOptions(azureEnvironment string, extraPolicies ...policy.Policy) (*arm.ClientOptions, error) {
 	opts := &arm.ClientOptions{}
+	opts.Transport = azureutil.Transport()
 
 	switch azureEnvironment {
 	case PublicCloudName:
@@ -352,28 +585,90 @@ func ARMClientOptions(azureEnvironment string, extraPolicies ...policy.Policy) (
 	case "":
 		// No cloud name provided, so leave at defaults.
 	default:
-		return nil, fmt.Errorf("invalid cloud name %q", azureEnvironment)
+		registered, ok := registeredClouds.Load(azureEnvironment)
+		if !ok {
+			return nil, fmt.Errorf("invalid cloud name %q", azureEnvironment)
+		}
+		clientOptions := registered.(policy.ClientOptions)
+		opts.Cloud = clientOptions.Cloud
+		opts.Transport = clientOptions.Transport
 	}
 	opts.PerCallPolicies = []policy.Policy{
+		tracingPolicy{tracer: tele.Tracer()},
 		correlationIDPolicy{},
 		userAgentPolicy{},
+		throttlingPolicy{throttler: armThrottler},
+		metricsPolicy{},
+	}
+	if faults := faultinjection.Policy(); faults != nil {
+		opts.PerCallPolicies = append(opts.PerCallPolicies, faults)
 	}
 	opts.PerCallPolicies = append(opts.PerCallPolicies, extraPolicies...)
+	// The audit policy comes last so that requests held back by the throttling policy or answered by the fault
+	// injection policy, which are never sent to ARM, are not audited.
+	opts.PerCallPolicies = append(opts.PerCallPolicies, auditPolicy{})
 	opts.Retry.MaxRetries = -1 // Less than zero means one try and no retries.
 
 	return opts, nil
 }
 
+// tracingPolicy records a span for each ARM request, so the time spent in Azure can be told apart from the
+// time spent in the controllers.
+// It implements the policy.Policy interface.
+type tracingPolicy struct {
+	tracer trace.Tracer
+}
+
+// Do sends the request within a new span and records the response status on it.
+func (p tracingPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	ctx, span := p.tracer.Start(raw.Context(), fmt.Sprintf("azure.ARM.%s", raw.Method),
+		trace.WithAttributes(
+			attribute.String("http.method", raw.Method),
+			attribute.String("http.path", raw.URL.Path),
+		),
+	)
+	defer span.End()
+
+	resp, err := req.Clone(ctx).Next()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
+// armRequestIDHeader is the response header ARM uses to identify a single request.
+const armRequestIDHeader = "x-ms-request-id"
+
 // correlationIDPolicy adds the "x-ms-correlation-request-id" header to requests.
 // It implements the policy.Policy interface.
 type correlationIDPolicy struct{}
 
 // Do adds the "x-ms-correlation-request-id" header if a request has a correlation ID in its context.
+// Failed responses are logged with the correlation and request IDs returned by ARM, which can be used
+// to find the operation in the Azure Activity Log.
 func (p correlationIDPolicy) Do(req *policy.Request) (*http.Response, error) {
 	if corrID, ok := tele.CorrIDFromCtx(req.Raw().Context()); ok {
 		req.Raw().Header.Set(string(tele.CorrIDKeyVal), string(corrID))
 	}
-	return req.Next()
+	resp, err := req.Next()
+	// Not found responses are expected when checking whether a resource exists.
+	if resp != nil && resp.StatusCode >= http.StatusBadRequest && resp.StatusCode != http.StatusNotFound {
+		log.FromContext(req.Raw().Context()).Info("Azure API request failed",
+			"method", req.Raw().Method,
+			"path", req.Raw().URL.Path,
+			"statusCode", resp.StatusCode,
+			string(tele.CorrIDKeyVal), resp.Header.Get(string(tele.CorrIDKeyVal)),
+			armRequestIDHeader, resp.Header.Get(armRequestIDHeader),
+		)
+	}
+	return resp, err
 }
 
 // userAgentPolicy extends the "User-Agent" header on requests.
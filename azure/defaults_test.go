@@ -21,15 +21,24 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/faultinjection"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // TestARMClientOptions tests the `ARMClientOptions()` factory function.
@@ -80,11 +89,20 @@ func TestARMClientOptions(t *testing.T) {
 			g.Expect(err).NotTo(HaveOccurred())
 			g.Expect(opts.Cloud).To(Equal(tc.expectedCloud))
 			g.Expect(opts.Retry.MaxRetries).To(BeNumerically("==", -1))
-			g.Expect(opts.PerCallPolicies).To(HaveLen(2))
+			g.Expect(opts.PerCallPolicies).To(HaveLen(perCallPolicies()))
 		})
 	}
 }
 
+// perCallPolicies returns the number of per-call policies of `ARMClientOptions()`, which include the fault injection
+// policy in binaries built with the faultinjection build tag.
+func perCallPolicies() int {
+	if faultinjection.Enabled {
+		return 7
+	}
+	return 6
+}
+
 // TestPerCallPolicies tests the per-call policies returned by `ARMClientOptions()`.
 func TestPerCallPolicies(t *testing.T) {
 	g := NewWithT(t)
@@ -98,12 +116,16 @@ func TestPerCallPolicies(t *testing.T) {
 	}))
 	defer server.Close()
 
-	// Call the factory function and ensure it has both PerCallPolicies.
+	// Call the factory function and ensure it has all PerCallPolicies.
 	opts, err := ARMClientOptions("")
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(opts.PerCallPolicies).To(HaveLen(2))
+	g.Expect(opts.PerCallPolicies).To(HaveLen(perCallPolicies()))
+	g.Expect(opts.PerCallPolicies).To(ContainElement(BeAssignableToTypeOf(tracingPolicy{})))
+	g.Expect(opts.PerCallPolicies).To(ContainElement(BeAssignableToTypeOf(metricsPolicy{})))
 	g.Expect(opts.PerCallPolicies).To(ContainElement(BeAssignableToTypeOf(correlationIDPolicy{})))
+	g.Expect(opts.PerCallPolicies[len(opts.PerCallPolicies)-1]).To(BeAssignableToTypeOf(auditPolicy{}))
 	g.Expect(opts.PerCallPolicies).To(ContainElement(BeAssignableToTypeOf(userAgentPolicy{})))
+	g.Expect(opts.PerCallPolicies).To(ContainElement(BeAssignableToTypeOf(throttlingPolicy{})))
 
 	// Create a request with a correlation ID.
 	ctx := context.WithValue(context.Background(), tele.CorrIDKeyVal, tele.CorrID(corrID))
@@ -118,6 +140,170 @@ func TestPerCallPolicies(t *testing.T) {
 	g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
 }
 
+func TestSetUserAgentExtensions(t *testing.T) {
+	tests := []struct {
+		name          string
+		suffix        string
+		partnerID     string
+		wantUserAgent string
+		wantErr       bool
+	}{
+		{
+			name:          "no extensions",
+			wantUserAgent: UserAgent(),
+		},
+		{
+			name:          "partner ID and suffix",
+			suffix:        "plural/1.2.3",
+			partnerID:     "8C7E3A3B-9F1D-4F3C-A1B2-0D9E8F7A6B5C",
+			wantUserAgent: UserAgent() + " pid-8c7e3a3b-9f1d-4f3c-a1b2-0d9e8f7a6b5c plural/1.2.3",
+		},
+		{
+			name:      "invalid partner ID",
+			partnerID: "not-a-guid",
+			wantErr:   true,
+		},
+		{
+			name:    "suffix with a newline",
+			suffix:  "plural\r\nX-Injected: true",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer func() { g.Expect(SetUserAgentExtensions("", "")).To(Succeed()) }()
+			err := SetUserAgentExtensions(tc.suffix, tc.partnerID)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(UserAgent()).To(Equal(tc.wantUserAgent))
+		})
+	}
+}
+
+func TestCorrelationIDPolicyLogsFailures(t *testing.T) {
+	testcases := []struct {
+		name       string
+		statusCode int
+		expectLog  bool
+	}{
+		{
+			name:       "successful response is not logged",
+			statusCode: http.StatusOK,
+			expectLog:  false,
+		},
+		{
+			name:       "not found response is not logged",
+			statusCode: http.StatusNotFound,
+			expectLog:  false,
+		},
+		{
+			name:       "failed response is logged with ARM request IDs",
+			statusCode: http.StatusConflict,
+			expectLog:  true,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(string(tele.CorrIDKeyVal), r.Header.Get(string(tele.CorrIDKeyVal)))
+				w.Header().Set(armRequestIDHeader, "request-5678")
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			var logs []string
+			logger := funcr.New(func(prefix, args string) {
+				logs = append(logs, args)
+			}, funcr.Options{})
+			ctx := log.IntoContext(context.Background(), logger)
+			ctx = context.WithValue(ctx, tele.CorrIDKeyVal, tele.CorrID("corr-1234"))
+			req, err := runtime.NewRequest(ctx, http.MethodPut, server.URL+"/subscriptions/123/resourceGroups/my-rg")
+			g.Expect(err).NotTo(HaveOccurred())
+
+			pipeline := defaultTestPipeline([]policy.Policy{correlationIDPolicy{}})
+			resp, err := pipeline.Do(req)
+			g.Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+			g.Expect(resp.StatusCode).To(Equal(tc.statusCode))
+
+			if !tc.expectLog {
+				g.Expect(logs).To(BeEmpty())
+				return
+			}
+			g.Expect(logs).To(HaveLen(1))
+			g.Expect(logs[0]).To(ContainSubstring(`"statusCode"=409`))
+			g.Expect(logs[0]).To(ContainSubstring(`"path"="/subscriptions/123/resourceGroups/my-rg"`))
+			g.Expect(logs[0]).To(ContainSubstring(`"x-ms-correlation-request-id"="corr-1234"`))
+			g.Expect(logs[0]).To(ContainSubstring(`"x-ms-request-id"="request-5678"`))
+		})
+	}
+}
+
+func TestTracingPolicy(t *testing.T) {
+	testcases := []struct {
+		name           string
+		statusCode     int
+		expectedStatus codes.Code
+	}{
+		{
+			name:           "successful request",
+			statusCode:     http.StatusOK,
+			expectedStatus: codes.Unset,
+		},
+		{
+			name:           "throttled request",
+			statusCode:     http.StatusTooManyRequests,
+			expectedStatus: codes.Error,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			req, err := runtime.NewRequest(context.Background(), http.MethodGet, server.URL+"/subscriptions/123/resourceGroups/my-rg")
+			g.Expect(err).NotTo(HaveOccurred())
+
+			pipeline := defaultTestPipeline([]policy.Policy{
+				tracingPolicy{tracer: tp.Tracer("test")},
+				throttlingPolicy{throttler: newThrottler()},
+			})
+			resp, err := pipeline.Do(req)
+			g.Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+
+			spans := recorder.Ended()
+			g.Expect(spans).To(HaveLen(1))
+			g.Expect(spans[0].Name()).To(Equal("azure.ARM.GET"))
+			g.Expect(spans[0].Status().Code).To(Equal(tc.expectedStatus))
+			g.Expect(spans[0].Attributes()).To(ContainElements(
+				attribute.String("http.path", "/subscriptions/123/resourceGroups/my-rg"),
+				attribute.Int("http.status_code", tc.statusCode),
+			))
+			if tc.statusCode == http.StatusTooManyRequests {
+				g.Expect(spans[0].Attributes()).To(ContainElement(attribute.String(throttledSpanAttribute, "azure")))
+			}
+		})
+	}
+}
+
 func TestCustomPutPatchHeaderPolicy(t *testing.T) {
 	testHeaders := map[string]string{
 		"X-Test-Header":  "test-value",
@@ -277,3 +463,78 @@ func TestGetBootstrappingVMExtension(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBootstrapScriptVMExtension(t *testing.T) {
+	g := NewWithT(t)
+
+	extension := GetBootstrapScriptVMExtension("test-vm", "IyEvYmluL3NoCg==")
+	g.Expect(extension).To(Equal(&ExtensionSpec{
+		Name:              "CustomScript",
+		VMName:            "test-vm",
+		Publisher:         "Microsoft.Azure.Extensions",
+		Version:           "2.1",
+		ProtectedSettings: map[string]string{"script": "IyEvYmluL3NoCg=="},
+	}))
+}
+
+func TestGetAzureDiskEncryptionVMExtension(t *testing.T) {
+	g := NewWithT(t)
+
+	ade := &infrav1.AzureDiskEncryption{
+		KeyVaultID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+		KeyVaultURL:         "https://my-vault.vault.azure.net/",
+		KeyEncryptionKeyURL: "https://my-vault.vault.azure.net/keys/my-key/0123456789abcdef0123456789abcdef",
+	}
+	g.Expect(GetAzureDiskEncryptionVMExtension(LinuxOS, "test-vm", ade)).To(Equal(&ExtensionSpec{
+		Name:      "AzureDiskEncryptionForLinux",
+		VMName:    "test-vm",
+		Publisher: "Microsoft.Azure.Security",
+		Version:   "1.1",
+		Settings: map[string]string{
+			"EncryptionOperation":    "EnableEncryption",
+			"KeyVaultURL":            "https://my-vault.vault.azure.net/",
+			"KeyVaultResourceId":     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+			"KeyEncryptionKeyURL":    "https://my-vault.vault.azure.net/keys/my-key/0123456789abcdef0123456789abcdef",
+			"KekVaultResourceId":     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+			"KeyEncryptionAlgorithm": "RSA-OAEP",
+			"VolumeType":             "All",
+		},
+	}))
+
+	ade.KeyEncryptionKeyVaultID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-kek-vault"
+	ade.VolumeType = infrav1.AzureDiskEncryptionVolumeTypeData
+	extension := GetAzureDiskEncryptionVMExtension(WindowsOS, "test-vm", ade)
+	g.Expect(extension.Name).To(Equal("AzureDiskEncryption"))
+	g.Expect(extension.Version).To(Equal("2.2"))
+	g.Expect(extension.Settings).To(HaveKeyWithValue("KekVaultResourceId", ade.KeyEncryptionKeyVaultID))
+	g.Expect(extension.Settings).To(HaveKeyWithValue("VolumeType", "Data"))
+}
+
+func TestGenerateMachineResourceNames(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(GenerateNICName("my-machine", false, 0)).To(Equal("my-machine-nic"))
+	g.Expect(GenerateOSDiskName("my-machine")).To(Equal("my-machine_OSDisk"))
+	g.Expect(GenerateNodePublicIPName("my-machine")).To(Equal("pip-my-machine"))
+
+	machineName := strings.Repeat("m", 80)
+	for _, name := range []string{
+		GenerateNICName(machineName, false, 0),
+		GenerateNICName(machineName, true, 1),
+		GenerateOSDiskName(machineName),
+		GenerateNodePublicIPName(machineName),
+	} {
+		g.Expect(name).To(HaveLen(80))
+	}
+	g.Expect(GenerateNICName(machineName, true, 1)).To(HaveSuffix("-nic-1"))
+	g.Expect(GenerateOSDiskName(machineName)).To(HaveSuffix("_OSDisk"))
+	g.Expect(GenerateOSDiskName(machineName)).To(Equal(GenerateOSDiskName(machineName)))
+}
+
+func TestGenerateIPAddressClaimNames(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(GenerateNICIPAddressClaimName("my-machine", 1, 0)).To(Equal("my-machine-nic-1-ipconfig-0"))
+	g.Expect(GenerateSubnetIPAddressClaimName("my-cluster", "node-subnet")).To(Equal("my-cluster-node-subnet-cidr"))
+	g.Expect(GenerateSubnetIPAddressClaimName("my-cluster", "Node_Subnet")).To(Equal("my-cluster-node-subnet-cidr"))
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Drift records the existing Azure resources which differ from their desired state although their spec did not
+// change, i.e. which were changed out-of-band. Services reconciling with a context carrying a Drift record the
+// resources they would update in it, and only update them when Correct is true.
+type Drift struct {
+	// Correct is true when drifted resources are updated back to their desired state once recorded.
+	Correct bool
+
+	mu        sync.Mutex
+	resources []string
+}
+
+type driftKey struct{}
+
+// WithDrift returns a copy of ctx in which services record the resources changed out-of-band in drift.
+func WithDrift(ctx context.Context, drift *Drift) context.Context {
+	return context.WithValue(ctx, driftKey{}, drift)
+}
+
+// DriftFromContext returns the Drift of ctx, or nil when drift is not checked.
+func DriftFromContext(ctx context.Context) *Drift {
+	drift, _ := ctx.Value(driftKey{}).(*Drift)
+	return drift
+}
+
+// Record adds a resource changed out-of-band.
+func (d *Drift) Record(serviceName, resourceGroup, name string) {
+	resource := name
+	if resourceGroup != "" {
+		resource = resourceGroup + "/" + name
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.resources = append(d.resources, fmt.Sprintf("%s (%s)", resource, serviceName))
+}
+
+// Resources returns the resources changed out-of-band in the order they were recorded.
+func (d *Drift) Resources() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.resources...)
+}
@@ -21,11 +21,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/go-autorest/autorest"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 )
 
 // ResourceNotFound parses an error to check if its status code is Not Found (404).
@@ -33,6 +36,12 @@ func ResourceNotFound(err error) bool {
 	return hasStatusCode(err, http.StatusNotFound)
 }
 
+// ResourceModified parses an error to check if its status code is Precondition Failed (412), i.e. a conditional
+// write was rejected because the resource was modified since it was read.
+func ResourceModified(err error) bool {
+	return hasStatusCode(err, http.StatusPreconditionFailed)
+}
+
 // hasStatusCode returns true if an error is a DetailedError or ResponseError with a matching status code.
 func hasStatusCode(err error, statusCode int) bool {
 	derr := autorest.DetailedError{} // azure-sdk-for-go v1
@@ -43,6 +52,109 @@ func hasStatusCode(err error, statusCode int) bool {
 	return errors.As(err, &rerr) && rerr.StatusCode == statusCode
 }
 
+// RequeueAfterForError returns how long to wait before retrying after an Azure API error, depending on its class:
+// throttled requests are retried once the Retry-After period elapsed, transient server errors after a medium
+// interval, and resources not found, which are usually dependencies still being created, after a short one.
+// The boolean is false if the error does not belong to any of these classes.
+func RequeueAfterForError(err error) (time.Duration, bool) {
+	var reconcileErr ReconcileError
+	if errors.As(err, &reconcileErr) && reconcileErr.IsTransient() {
+		return reconcileErr.RequeueAfter(), true
+	}
+	statusCode, header := 0, http.Header{}
+	derr := autorest.DetailedError{} // azure-sdk-for-go v1
+	var rerr *azcore.ResponseError   // azure-sdk-for-go v2
+	switch {
+	case errors.As(err, &derr):
+		statusCode, _ = derr.StatusCode.(int)
+		if derr.Response != nil {
+			header = derr.Response.Header
+		}
+	case errors.As(err, &rerr):
+		statusCode = rerr.StatusCode
+		if rerr.RawResponse != nil {
+			header = rerr.RawResponse.Header
+		}
+	default:
+		return 0, false
+	}
+
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return retryAfterFromHeader(header), true
+	case statusCode >= http.StatusInternalServerError:
+		return reconciler.DefaultServerErrorRequeue, true
+	case statusCode == http.StatusNotFound:
+		return reconciler.DefaultDependencyRequeue, true
+	default:
+		return 0, false
+	}
+}
+
+// terminalErrorCodes maps the codes of Azure API errors that retrying the same request cannot fix
+// to the failure reason a machine should report for them.
+var terminalErrorCodes = map[string]capierrors.MachineStatusError{
+	"QuotaExceeded":             capierrors.InsufficientResourcesMachineError,
+	"SkuNotAvailable":           capierrors.InsufficientResourcesMachineError,
+	"RequestDisallowedByPolicy": capierrors.InvalidConfigurationMachineError,
+}
+
+// IsTerminalAzureError returns true if the error is an Azure API error that will not succeed on retry,
+// such as an exceeded quota, a SKU that is not available in the requested location or zone, or a request
+// denied by Azure Policy.
+func IsTerminalAzureError(err error) bool {
+	_, ok := TerminalFailureReason(err)
+	return ok
+}
+
+// TerminalFailureReason returns the machine failure reason for a terminal Azure API error.
+// The boolean is false if the error is not a terminal Azure API error.
+func TerminalFailureReason(err error) (capierrors.MachineStatusError, bool) {
+	reconcileErr := &ReconcileError{}
+	if errors.As(err, reconcileErr) {
+		return TerminalFailureReason(reconcileErr.error)
+	}
+	var rerr *azcore.ResponseError
+	if !errors.As(err, &rerr) {
+		return "", false
+	}
+	// Compute reports exhausted vCPU quotas as OperationNotAllowed, which is also used for retryable conflicts.
+	if rerr.ErrorCode == "OperationNotAllowed" && rerr.RawResponse != nil && strings.Contains(rerr.Error(), "quota") {
+		return capierrors.InsufficientResourcesMachineError, true
+	}
+	reason, ok := terminalErrorCodes[rerr.ErrorCode]
+	return reason, ok
+}
+
+// IsSKUNotAvailableError returns true if the error is an Azure API error reporting that the requested SKU is not
+// available to the subscription in the requested location or zone.
+func IsSKUNotAvailableError(err error) bool {
+	reconcileErr := &ReconcileError{}
+	if errors.As(err, reconcileErr) {
+		return IsSKUNotAvailableError(reconcileErr.error)
+	}
+	var rerr *azcore.ResponseError
+	return errors.As(err, &rerr) && rerr.ErrorCode == "SkuNotAvailable"
+}
+
+// HasErrorCode returns true if the error is an Azure API error with one of the given error codes.
+func HasErrorCode(err error, codes ...string) bool {
+	reconcileErr := &ReconcileError{}
+	if errors.As(err, reconcileErr) {
+		return HasErrorCode(reconcileErr.error, codes...)
+	}
+	var rerr *azcore.ResponseError
+	if !errors.As(err, &rerr) {
+		return false
+	}
+	for _, code := range codes {
+		if rerr.ErrorCode == code {
+			return true
+		}
+	}
+	return false
+}
+
 // VMDeletedError is returned when a virtual machine is deleted outside of capz.
 type VMDeletedError struct {
 	ProviderID string
@@ -18,13 +18,18 @@ package azure
 
 import (
 	"context"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 )
 
 func TestIsContextDeadlineExceededOrCanceled(t *testing.T) {
@@ -113,3 +118,217 @@ func TestResourceNotFound(t *testing.T) {
 		})
 	}
 }
+
+func TestRequeueAfterForError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		requeueAfter time.Duration
+		classified   bool
+	}{
+		{
+			name: "throttled with a Retry-After header",
+			err: &azcore.ResponseError{
+				StatusCode:  http.StatusTooManyRequests,
+				RawResponse: &http.Response{Header: http.Header{"Retry-After": []string{"90"}}},
+			},
+			requeueAfter: 90 * time.Second,
+			classified:   true,
+		},
+		{
+			name:         "throttled without a Retry-After header",
+			err:          &azcore.ResponseError{StatusCode: http.StatusTooManyRequests},
+			requeueAfter: defaultThrottlingBackoff,
+			classified:   true,
+		},
+		{
+			name:         "server error",
+			err:          errors.Wrap(&azcore.ResponseError{StatusCode: http.StatusServiceUnavailable}, "failed to get"),
+			requeueAfter: reconciler.DefaultServerErrorRequeue,
+			classified:   true,
+		},
+		{
+			name:         "not found",
+			err:          autorest.DetailedError{StatusCode: http.StatusNotFound},
+			requeueAfter: reconciler.DefaultDependencyRequeue,
+			classified:   true,
+		},
+		{
+			name:         "transient reconcile error",
+			err:          WithTransientError(errors.New("foo"), 42*time.Second),
+			requeueAfter: 42 * time.Second,
+			classified:   true,
+		},
+		{
+			name: "conflict",
+			err:  &azcore.ResponseError{StatusCode: http.StatusConflict},
+		},
+		{
+			name: "generic error",
+			err:  errors.New("foo"),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			requeueAfter, ok := RequeueAfterForError(tc.err)
+			if ok != tc.classified || requeueAfter != tc.requeueAfter {
+				t.Errorf("RequeueAfterForError() = (%s, %v), want (%s, %v)", requeueAfter, ok, tc.requeueAfter, tc.classified)
+			}
+		})
+	}
+}
+
+func TestTerminalFailureReason(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedReason capierrors.MachineStatusError
+		terminal       bool
+	}{
+		{
+			name:           "quota exceeded",
+			err:            &azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "QuotaExceeded"},
+			expectedReason: capierrors.InsufficientResourcesMachineError,
+			terminal:       true,
+		},
+		{
+			name:           "SKU not available",
+			err:            errors.Wrap(&azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "SkuNotAvailable"}, "failed to create"),
+			expectedReason: capierrors.InsufficientResourcesMachineError,
+			terminal:       true,
+		},
+		{
+			name:           "denied by policy",
+			err:            &azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "RequestDisallowedByPolicy"},
+			expectedReason: capierrors.InvalidConfigurationMachineError,
+			terminal:       true,
+		},
+		{
+			name:           "wrapped in a reconcile error",
+			err:            WithTerminalError(&azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "QuotaExceeded"}),
+			expectedReason: capierrors.InsufficientResourcesMachineError,
+			terminal:       true,
+		},
+		{
+			name: "vCPU quota exceeded",
+			err: &azcore.ResponseError{
+				StatusCode: http.StatusConflict,
+				ErrorCode:  "OperationNotAllowed",
+				RawResponse: &http.Response{
+					StatusCode: http.StatusConflict,
+					Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{}},
+					Body:       io.NopCloser(strings.NewReader(`{"error":{"code":"OperationNotAllowed","message":"Operation could not be completed as it results in exceeding approved standardDSv3Family Cores quota."}}`)),
+				},
+			},
+			expectedReason: capierrors.InsufficientResourcesMachineError,
+			terminal:       true,
+		},
+		{
+			name: "operation not allowed for another reason",
+			err: &azcore.ResponseError{
+				StatusCode: http.StatusConflict,
+				ErrorCode:  "OperationNotAllowed",
+				RawResponse: &http.Response{
+					StatusCode: http.StatusConflict,
+					Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{}},
+					Body:       io.NopCloser(strings.NewReader(`{"error":{"code":"OperationNotAllowed","message":"Another operation is in progress."}}`)),
+				},
+			},
+		},
+		{
+			name: "throttled",
+			err:  &azcore.ResponseError{StatusCode: http.StatusTooManyRequests, ErrorCode: "TooManyRequests"},
+		},
+		{
+			name: "generic error",
+			err:  errors.New("QuotaExceeded"),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			reason, ok := TerminalFailureReason(tc.err)
+			if ok != tc.terminal || reason != tc.expectedReason {
+				t.Errorf("TerminalFailureReason() = (%q, %v), want (%q, %v)", reason, ok, tc.expectedReason, tc.terminal)
+			}
+			if got := IsTerminalAzureError(tc.err); got != tc.terminal {
+				t.Errorf("IsTerminalAzureError() = %v, want %v", got, tc.terminal)
+			}
+		})
+	}
+}
+
+func TestIsSKUNotAvailableError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "SKU not available",
+			err:      &azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "SkuNotAvailable"},
+			expected: true,
+		},
+		{
+			name:     "wrapped in a reconcile error",
+			err:      WithTerminalError(errors.Wrap(&azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "SkuNotAvailable"}, "failed to create")),
+			expected: true,
+		},
+		{
+			name: "quota exceeded",
+			err:  &azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "QuotaExceeded"},
+		},
+		{
+			name: "generic error",
+			err:  errors.New("SkuNotAvailable"),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsSKUNotAvailableError(tc.err); got != tc.expected {
+				t.Errorf("IsSKUNotAvailableError() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestHasErrorCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "matching error code",
+			err:      &azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "ScopeLocked"},
+			expected: true,
+		},
+		{
+			name:     "wrapped in a reconcile error",
+			err:      WithTerminalError(errors.Wrap(&azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "RequestDisallowedByPolicy"}, "failed to delete")),
+			expected: true,
+		},
+		{
+			name: "other error code",
+			err:  &azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "Conflict"},
+		},
+		{
+			name: "generic error",
+			err:  errors.New("ScopeLocked"),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := HasErrorCode(tc.err, "ScopeLocked", "RequestDisallowedByPolicy"); got != tc.expected {
+				t.Errorf("HasErrorCode() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
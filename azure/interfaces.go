@@ -73,6 +73,9 @@ type NetworkDescriber interface {
 	GetPrivateDNSZoneName() string
 	OutboundLBName(string) string
 	OutboundPoolName(string) string
+	NodeInternalLBName() string
+	NodeInternalLBPoolName() string
+	NodeRoutes() *infrav1.NodeRoutesSpec
 }
 
 // ClusterDescriber is an interface which can get common Azure Cluster information.
@@ -88,6 +91,12 @@ type ClusterDescriber interface {
 	AvailabilitySetEnabled() bool
 	CloudProviderConfigOverrides() *infrav1.CloudProviderConfigOverrides
 	FailureDomains() []*string
+	SSHKeyPairSecretName() string
+	SSHPublicKeys(role string) []string
+	BootstrapDataStorageAccountName() string
+	BootstrapDataKeyVault() *infrav1.BootstrapDataKeyVault
+	ManagedIdentityProviderID() string
+	PlatformFaultDomainCount() *int32
 }
 
 // AsyncStatusUpdater is an interface used to keep track of long running operations in Status that has Conditions and Futures.
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	armRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "capz_arm_requests_total",
+		Help: "Number of ARM requests sent, by resource type, HTTP method and response status code.",
+	}, []string{"resource_type", "method", "code"})
+
+	armRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "capz_arm_request_duration_seconds",
+		Help:    "Latency of ARM requests, by resource type and HTTP method.",
+		Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+	}, []string{"resource_type", "method"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(armRequests, armRequestDuration)
+}
+
+// metricsPolicy records the count, status and latency of ARM requests.
+// It implements the policy.Policy interface.
+type metricsPolicy struct{}
+
+// Do sends the request and records its outcome. Requests that fail without a response are counted with the code "error".
+func (p metricsPolicy) Do(req *policy.Request) (*http.Response, error) {
+	resourceType := resourceTypeFromPath(req.Raw().URL.Path)
+	method := req.Raw().Method
+
+	start := time.Now()
+	resp, err := req.Next()
+	armRequestDuration.WithLabelValues(resourceType, method).Observe(time.Since(start).Seconds())
+
+	code := "error"
+	if err == nil && resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	armRequests.WithLabelValues(resourceType, method, code).Inc()
+	return resp, err
+}
+
+// resourceTypeFromPath returns the lower-cased ARM resource type an ARM request path refers to, e.g.
+// "microsoft.network/virtualnetworks/subnets", leaving out resource names so it can be used as a metric label.
+func resourceTypeFromPath(path string) string {
+	segments := strings.Split(strings.Trim(strings.ToLower(path), "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] != "providers" || i+2 >= len(segments) {
+			continue
+		}
+		// Provider namespace followed by alternating type and name segments.
+		types := []string{segments[i+1]}
+		for j := i + 2; j < len(segments); j += 2 {
+			types = append(types, segments[j])
+		}
+		return strings.Join(types, "/")
+	}
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] == "resourcegroups" || segments[i] == "subscriptions" {
+			return segments[i]
+		}
+	}
+	return "unknown"
+}
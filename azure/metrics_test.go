@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsPolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	path := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/metricsTestResources/my-resource"
+	counter := armRequests.WithLabelValues("microsoft.network/metricstestresources", http.MethodPut, "409")
+	before := testutil.ToFloat64(counter)
+
+	req, err := runtime.NewRequest(context.Background(), http.MethodPut, server.URL+path)
+	g.Expect(err).NotTo(HaveOccurred())
+	resp, err := defaultTestPipeline([]policy.Policy{metricsPolicy{}}).Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	defer resp.Body.Close()
+
+	g.Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+}
+
+func TestResourceTypeFromPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{
+			path:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+			expected: "microsoft.network/virtualnetworks",
+		},
+		{
+			path:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+			expected: "microsoft.network/virtualnetworks/subnets",
+		},
+		{
+			path:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines",
+			expected: "microsoft.compute/virtualmachines",
+		},
+		{
+			path:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm/providers/Microsoft.Authorization/roleAssignments/abc",
+			expected: "microsoft.authorization/roleassignments",
+		},
+		{
+			path:     "/subscriptions/123/providers/Microsoft.Network/locations/eastus/operations/abc",
+			expected: "microsoft.network/locations/operations",
+		},
+		{
+			path:     "/subscriptions/123/resourcegroups/my-rg",
+			expected: "resourcegroups",
+		},
+		{
+			path:     "/subscriptions/123",
+			expected: "subscriptions",
+		},
+		{
+			path:     "/metadata/endpoints",
+			expected: "unknown",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			g.Expect(resourceTypeFromPath(tc.path)).To(Equal(tc.expected))
+		})
+	}
+}
@@ -472,6 +472,48 @@ func (mr *MockNetworkDescriberMockRecorder) IsVnetManaged() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsVnetManaged", reflect.TypeOf((*MockNetworkDescriber)(nil).IsVnetManaged))
 }
 
+// NodeInternalLBName mocks base method.
+func (m *MockNetworkDescriber) NodeInternalLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeInternalLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeInternalLBName indicates an expected call of NodeInternalLBName.
+func (mr *MockNetworkDescriberMockRecorder) NodeInternalLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeInternalLBName", reflect.TypeOf((*MockNetworkDescriber)(nil).NodeInternalLBName))
+}
+
+// NodeInternalLBPoolName mocks base method.
+func (m *MockNetworkDescriber) NodeInternalLBPoolName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeInternalLBPoolName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeInternalLBPoolName indicates an expected call of NodeInternalLBPoolName.
+func (mr *MockNetworkDescriberMockRecorder) NodeInternalLBPoolName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeInternalLBPoolName", reflect.TypeOf((*MockNetworkDescriber)(nil).NodeInternalLBPoolName))
+}
+
+// NodeRoutes mocks base method.
+func (m *MockNetworkDescriber) NodeRoutes() *v1beta1.NodeRoutesSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeRoutes")
+	ret0, _ := ret[0].(*v1beta1.NodeRoutesSpec)
+	return ret0
+}
+
+// NodeRoutes indicates an expected call of NodeRoutes.
+func (mr *MockNetworkDescriberMockRecorder) NodeRoutes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeRoutes", reflect.TypeOf((*MockNetworkDescriber)(nil).NodeRoutes))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockNetworkDescriber) NodeSubnets() []v1beta1.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -633,6 +675,34 @@ func (mr *MockClusterDescriberMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockClusterDescriber)(nil).BaseURI))
 }
 
+// BootstrapDataKeyVault mocks base method.
+func (m *MockClusterDescriber) BootstrapDataKeyVault() *v1beta1.BootstrapDataKeyVault {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataKeyVault")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataKeyVault)
+	return ret0
+}
+
+// BootstrapDataKeyVault indicates an expected call of BootstrapDataKeyVault.
+func (mr *MockClusterDescriberMockRecorder) BootstrapDataKeyVault() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataKeyVault", reflect.TypeOf((*MockClusterDescriber)(nil).BootstrapDataKeyVault))
+}
+
+// BootstrapDataStorageAccountName mocks base method.
+func (m *MockClusterDescriber) BootstrapDataStorageAccountName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorageAccountName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BootstrapDataStorageAccountName indicates an expected call of BootstrapDataStorageAccountName.
+func (mr *MockClusterDescriberMockRecorder) BootstrapDataStorageAccountName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorageAccountName", reflect.TypeOf((*MockClusterDescriber)(nil).BootstrapDataStorageAccountName))
+}
+
 // ClientID mocks base method.
 func (m *MockClusterDescriber) ClientID() string {
 	m.ctrl.T.Helper()
@@ -787,6 +857,34 @@ func (mr *MockClusterDescriberMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockClusterDescriber)(nil).Location))
 }
 
+// ManagedIdentityProviderID mocks base method.
+func (m *MockClusterDescriber) ManagedIdentityProviderID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedIdentityProviderID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ManagedIdentityProviderID indicates an expected call of ManagedIdentityProviderID.
+func (mr *MockClusterDescriberMockRecorder) ManagedIdentityProviderID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedIdentityProviderID", reflect.TypeOf((*MockClusterDescriber)(nil).ManagedIdentityProviderID))
+}
+
+// PlatformFaultDomainCount mocks base method.
+func (m *MockClusterDescriber) PlatformFaultDomainCount() *int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlatformFaultDomainCount")
+	ret0, _ := ret[0].(*int32)
+	return ret0
+}
+
+// PlatformFaultDomainCount indicates an expected call of PlatformFaultDomainCount.
+func (mr *MockClusterDescriberMockRecorder) PlatformFaultDomainCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlatformFaultDomainCount", reflect.TypeOf((*MockClusterDescriber)(nil).PlatformFaultDomainCount))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockClusterDescriber) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -801,6 +899,34 @@ func (mr *MockClusterDescriberMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockClusterDescriber)(nil).ResourceGroup))
 }
 
+// SSHKeyPairSecretName mocks base method.
+func (m *MockClusterDescriber) SSHKeyPairSecretName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHKeyPairSecretName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SSHKeyPairSecretName indicates an expected call of SSHKeyPairSecretName.
+func (mr *MockClusterDescriberMockRecorder) SSHKeyPairSecretName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHKeyPairSecretName", reflect.TypeOf((*MockClusterDescriber)(nil).SSHKeyPairSecretName))
+}
+
+// SSHPublicKeys mocks base method.
+func (m *MockClusterDescriber) SSHPublicKeys(role string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHPublicKeys", role)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SSHPublicKeys indicates an expected call of SSHPublicKeys.
+func (mr *MockClusterDescriberMockRecorder) SSHPublicKeys(role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHPublicKeys", reflect.TypeOf((*MockClusterDescriber)(nil).SSHPublicKeys), role)
+}
+
 // SubscriptionID mocks base method.
 func (m *MockClusterDescriber) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -1047,6 +1173,34 @@ func (mr *MockClusterScoperMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockClusterScoper)(nil).BaseURI))
 }
 
+// BootstrapDataKeyVault mocks base method.
+func (m *MockClusterScoper) BootstrapDataKeyVault() *v1beta1.BootstrapDataKeyVault {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataKeyVault")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataKeyVault)
+	return ret0
+}
+
+// BootstrapDataKeyVault indicates an expected call of BootstrapDataKeyVault.
+func (mr *MockClusterScoperMockRecorder) BootstrapDataKeyVault() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataKeyVault", reflect.TypeOf((*MockClusterScoper)(nil).BootstrapDataKeyVault))
+}
+
+// BootstrapDataStorageAccountName mocks base method.
+func (m *MockClusterScoper) BootstrapDataStorageAccountName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorageAccountName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BootstrapDataStorageAccountName indicates an expected call of BootstrapDataStorageAccountName.
+func (mr *MockClusterScoperMockRecorder) BootstrapDataStorageAccountName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorageAccountName", reflect.TypeOf((*MockClusterScoper)(nil).BootstrapDataStorageAccountName))
+}
+
 // ClientID mocks base method.
 func (m *MockClusterScoper) ClientID() string {
 	m.ctrl.T.Helper()
@@ -1285,6 +1439,62 @@ func (mr *MockClusterScoperMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockClusterScoper)(nil).Location))
 }
 
+// ManagedIdentityProviderID mocks base method.
+func (m *MockClusterScoper) ManagedIdentityProviderID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedIdentityProviderID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ManagedIdentityProviderID indicates an expected call of ManagedIdentityProviderID.
+func (mr *MockClusterScoperMockRecorder) ManagedIdentityProviderID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedIdentityProviderID", reflect.TypeOf((*MockClusterScoper)(nil).ManagedIdentityProviderID))
+}
+
+// NodeInternalLBName mocks base method.
+func (m *MockClusterScoper) NodeInternalLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeInternalLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeInternalLBName indicates an expected call of NodeInternalLBName.
+func (mr *MockClusterScoperMockRecorder) NodeInternalLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeInternalLBName", reflect.TypeOf((*MockClusterScoper)(nil).NodeInternalLBName))
+}
+
+// NodeInternalLBPoolName mocks base method.
+func (m *MockClusterScoper) NodeInternalLBPoolName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeInternalLBPoolName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeInternalLBPoolName indicates an expected call of NodeInternalLBPoolName.
+func (mr *MockClusterScoperMockRecorder) NodeInternalLBPoolName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeInternalLBPoolName", reflect.TypeOf((*MockClusterScoper)(nil).NodeInternalLBPoolName))
+}
+
+// NodeRoutes mocks base method.
+func (m *MockClusterScoper) NodeRoutes() *v1beta1.NodeRoutesSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeRoutes")
+	ret0, _ := ret[0].(*v1beta1.NodeRoutesSpec)
+	return ret0
+}
+
+// NodeRoutes indicates an expected call of NodeRoutes.
+func (mr *MockClusterScoperMockRecorder) NodeRoutes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeRoutes", reflect.TypeOf((*MockClusterScoper)(nil).NodeRoutes))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockClusterScoper) NodeSubnets() []v1beta1.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -1327,6 +1537,20 @@ func (mr *MockClusterScoperMockRecorder) OutboundPoolName(arg0 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboundPoolName", reflect.TypeOf((*MockClusterScoper)(nil).OutboundPoolName), arg0)
 }
 
+// PlatformFaultDomainCount mocks base method.
+func (m *MockClusterScoper) PlatformFaultDomainCount() *int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlatformFaultDomainCount")
+	ret0, _ := ret[0].(*int32)
+	return ret0
+}
+
+// PlatformFaultDomainCount indicates an expected call of PlatformFaultDomainCount.
+func (mr *MockClusterScoperMockRecorder) PlatformFaultDomainCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlatformFaultDomainCount", reflect.TypeOf((*MockClusterScoper)(nil).PlatformFaultDomainCount))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockClusterScoper) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -1341,6 +1565,34 @@ func (mr *MockClusterScoperMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockClusterScoper)(nil).ResourceGroup))
 }
 
+// SSHKeyPairSecretName mocks base method.
+func (m *MockClusterScoper) SSHKeyPairSecretName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHKeyPairSecretName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SSHKeyPairSecretName indicates an expected call of SSHKeyPairSecretName.
+func (mr *MockClusterScoperMockRecorder) SSHKeyPairSecretName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHKeyPairSecretName", reflect.TypeOf((*MockClusterScoper)(nil).SSHKeyPairSecretName))
+}
+
+// SSHPublicKeys mocks base method.
+func (m *MockClusterScoper) SSHPublicKeys(role string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHPublicKeys", role)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SSHPublicKeys indicates an expected call of SSHPublicKeys.
+func (mr *MockClusterScoperMockRecorder) SSHPublicKeys(role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHPublicKeys", reflect.TypeOf((*MockClusterScoper)(nil).SSHPublicKeys), role)
+}
+
 // SetSubnet mocks base method.
 func (m *MockClusterScoper) SetSubnet(arg0 v1beta1.SubnetSpec) {
 	m.ctrl.T.Helper()
@@ -1502,6 +1754,34 @@ func (mr *MockManagedClusterScoperMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockManagedClusterScoper)(nil).BaseURI))
 }
 
+// BootstrapDataKeyVault mocks base method.
+func (m *MockManagedClusterScoper) BootstrapDataKeyVault() *v1beta1.BootstrapDataKeyVault {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataKeyVault")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataKeyVault)
+	return ret0
+}
+
+// BootstrapDataKeyVault indicates an expected call of BootstrapDataKeyVault.
+func (mr *MockManagedClusterScoperMockRecorder) BootstrapDataKeyVault() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataKeyVault", reflect.TypeOf((*MockManagedClusterScoper)(nil).BootstrapDataKeyVault))
+}
+
+// BootstrapDataStorageAccountName mocks base method.
+func (m *MockManagedClusterScoper) BootstrapDataStorageAccountName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorageAccountName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BootstrapDataStorageAccountName indicates an expected call of BootstrapDataStorageAccountName.
+func (mr *MockManagedClusterScoperMockRecorder) BootstrapDataStorageAccountName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorageAccountName", reflect.TypeOf((*MockManagedClusterScoper)(nil).BootstrapDataStorageAccountName))
+}
+
 // ClientID mocks base method.
 func (m *MockManagedClusterScoper) ClientID() string {
 	m.ctrl.T.Helper()
@@ -1656,6 +1936,20 @@ func (mr *MockManagedClusterScoperMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockManagedClusterScoper)(nil).Location))
 }
 
+// ManagedIdentityProviderID mocks base method.
+func (m *MockManagedClusterScoper) ManagedIdentityProviderID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedIdentityProviderID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ManagedIdentityProviderID indicates an expected call of ManagedIdentityProviderID.
+func (mr *MockManagedClusterScoperMockRecorder) ManagedIdentityProviderID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedIdentityProviderID", reflect.TypeOf((*MockManagedClusterScoper)(nil).ManagedIdentityProviderID))
+}
+
 // NodeResourceGroup mocks base method.
 func (m *MockManagedClusterScoper) NodeResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -1670,6 +1964,20 @@ func (mr *MockManagedClusterScoperMockRecorder) NodeResourceGroup() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeResourceGroup", reflect.TypeOf((*MockManagedClusterScoper)(nil).NodeResourceGroup))
 }
 
+// PlatformFaultDomainCount mocks base method.
+func (m *MockManagedClusterScoper) PlatformFaultDomainCount() *int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlatformFaultDomainCount")
+	ret0, _ := ret[0].(*int32)
+	return ret0
+}
+
+// PlatformFaultDomainCount indicates an expected call of PlatformFaultDomainCount.
+func (mr *MockManagedClusterScoperMockRecorder) PlatformFaultDomainCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlatformFaultDomainCount", reflect.TypeOf((*MockManagedClusterScoper)(nil).PlatformFaultDomainCount))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockManagedClusterScoper) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -1684,6 +1992,34 @@ func (mr *MockManagedClusterScoperMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockManagedClusterScoper)(nil).ResourceGroup))
 }
 
+// SSHKeyPairSecretName mocks base method.
+func (m *MockManagedClusterScoper) SSHKeyPairSecretName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHKeyPairSecretName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SSHKeyPairSecretName indicates an expected call of SSHKeyPairSecretName.
+func (mr *MockManagedClusterScoperMockRecorder) SSHKeyPairSecretName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHKeyPairSecretName", reflect.TypeOf((*MockManagedClusterScoper)(nil).SSHKeyPairSecretName))
+}
+
+// SSHPublicKeys mocks base method.
+func (m *MockManagedClusterScoper) SSHPublicKeys(role string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHPublicKeys", role)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SSHPublicKeys indicates an expected call of SSHPublicKeys.
+func (mr *MockManagedClusterScoperMockRecorder) SSHPublicKeys(role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHPublicKeys", reflect.TypeOf((*MockManagedClusterScoper)(nil).SSHPublicKeys), role)
+}
+
 // SubscriptionID mocks base method.
 func (m *MockManagedClusterScoper) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// CollectPages fetches every page of a pager, following its next links, and returns the non-nil items of all pages.
+// values extracts the items of a page. When any page fails to be fetched, the error is returned without any item,
+// so that callers never mistake a truncated list for a complete one and conclude that a resource is missing.
+func CollectPages[P, V any](ctx context.Context, pager *runtime.Pager[P], values func(P) []*V) ([]V, error) {
+	var items []V
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values(page) {
+			if value != nil {
+				items = append(items, *value)
+			}
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+)
+
+type fakePage struct {
+	values   []*string
+	nextLink *string
+}
+
+// fakePager returns a pager over pages, failing to fetch the page at index failAt if it is not negative.
+func fakePager(pages []fakePage, failAt int) *runtime.Pager[fakePage] {
+	fetched := 0
+	return runtime.NewPager(runtime.PagingHandler[fakePage]{
+		More: func(page fakePage) bool {
+			return page.nextLink != nil
+		},
+		Fetcher: func(_ context.Context, _ *fakePage) (fakePage, error) {
+			if fetched == failAt {
+				return fakePage{}, errors.New("failed to fetch page")
+			}
+			page := pages[fetched]
+			fetched++
+			return page, nil
+		},
+	})
+}
+
+func TestCollectPages(t *testing.T) {
+	pages := []fakePage{
+		{values: []*string{ptr.To("a"), nil, ptr.To("b")}, nextLink: ptr.To("next")},
+		{values: []*string{ptr.To("c")}, nextLink: ptr.To("next")},
+		{values: []*string{ptr.To("d")}},
+	}
+	values := func(page fakePage) []*string { return page.values }
+
+	t.Run("collects the items of all pages", func(t *testing.T) {
+		g := NewWithT(t)
+		items, err := CollectPages(context.Background(), fakePager(pages, -1), values)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(items).To(Equal([]string{"a", "b", "c", "d"}))
+	})
+
+	t.Run("returns no item when a page fails to be fetched", func(t *testing.T) {
+		g := NewWithT(t)
+		items, err := CollectPages(context.Background(), fakePager(pages, 2), values)
+		g.Expect(err).To(MatchError("failed to fetch page"))
+		g.Expect(items).To(BeNil())
+	})
+}
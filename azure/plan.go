@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"sync"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// Plan records the Azure operations a reconciliation would perform. Services reconciling with a context carrying a
+// Plan record their create, update and delete operations in it instead of performing them.
+type Plan struct {
+	mu         sync.Mutex
+	operations []infrav1.PlannedOperation
+}
+
+type planKey struct{}
+
+// WithPlan returns a copy of ctx in which services record their operations in plan instead of performing them.
+func WithPlan(ctx context.Context, plan *Plan) context.Context {
+	return context.WithValue(ctx, planKey{}, plan)
+}
+
+// PlanFromContext returns the Plan of ctx, or nil when the reconciliation is not in plan mode.
+func PlanFromContext(ctx context.Context) *Plan {
+	plan, _ := ctx.Value(planKey{}).(*Plan)
+	return plan
+}
+
+// Record adds an operation to the plan.
+func (p *Plan) Record(opType infrav1.PlannedOperationType, serviceName, resourceGroup, name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.operations = append(p.operations, infrav1.PlannedOperation{
+		Type:          opType,
+		Service:       serviceName,
+		ResourceGroup: resourceGroup,
+		Name:          name,
+	})
+}
+
+// Operations returns the recorded operations in the order they were recorded.
+func (p *Plan) Operations() []infrav1.PlannedOperation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]infrav1.PlannedOperation(nil), p.operations...)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+// bootstrapDataDelivery is the mechanism delivering the bootstrap data of a machine to its VM.
+type bootstrapDataDelivery string
+
+const (
+	// customDataDelivery passes the bootstrap data as the custom data of the VM, which cloud-init, Ignition and
+	// cloudbase-init read on first boot.
+	customDataDelivery bootstrapDataDelivery = "CustomData"
+	// userDataDelivery passes the bootstrap data as the user data of the VM, which an agent of the bootstrap provider
+	// baked into the image can read from the Instance Metadata Service.
+	userDataDelivery bootstrapDataDelivery = "UserData"
+	// extensionDelivery runs the bootstrap data, a shell script, with the Custom Script extension, which does not
+	// depend on cloud-init being installed in the image.
+	extensionDelivery bootstrapDataDelivery = "Extension"
+)
+
+// isCustomData returns whether the bootstrap data is passed as custom data, which is the default when the delivery has
+// not been detected.
+func (d bootstrapDataDelivery) isCustomData() bool {
+	return d == "" || d == customDataDelivery
+}
+
+// detectBootstrapDataDelivery chooses the mechanism delivering base64 encoded bootstrap data of the given format to a
+// VM. Cloud-init and Ignition configs, and all the bootstrap data of Windows VMs, are passed as custom data. Bootstrap
+// data of other formats is run with the Custom Script extension when it is a shell script, or passed as user data
+// otherwise.
+func detectBootstrapDataDelivery(osType, format, bootstrapData string) bootstrapDataDelivery {
+	if osType == azure.WindowsOS {
+		return customDataDelivery
+	}
+	switch format {
+	case "", string(kubeadmv1.CloudConfig), ignitionFormat:
+		return customDataDelivery
+	}
+	data, err := base64.StdEncoding.DecodeString(bootstrapData)
+	if err == nil && bytes.HasPrefix(data, []byte("#!")) {
+		return extensionDelivery
+	}
+	return userDataDelivery
+}
+
+// validateBootstrapDataDelivery returns an error if the detected delivery of the bootstrap data conflicts with the
+// spec of the AzureMachine.
+func (m *MachineScope) validateBootstrapDataDelivery() error {
+	switch m.cache.BootstrapDataDelivery {
+	case userDataDelivery:
+		if m.AzureMachine.Spec.SecondaryUserData != nil {
+			return errors.Errorf("AzureMachine %s/%s cannot have secondary user data as its bootstrap data of format %q is passed as user data", m.Namespace(), m.Name(), m.cache.BootstrapDataFormat)
+		}
+	case extensionDelivery:
+		for _, extension := range m.AzureMachine.Spec.VMExtensions {
+			if extension.Name == azure.BootstrapScriptExtensionLinux {
+				return errors.Errorf("AzureMachine %s/%s cannot have the %s VM extension as it runs its bootstrap data of format %q", m.Namespace(), m.Name(), extension.Name, m.cache.BootstrapDataFormat)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"encoding/base64"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestDetectBootstrapDataDelivery(t *testing.T) {
+	encode := func(data string) string {
+		return base64.StdEncoding.EncodeToString([]byte(data))
+	}
+
+	tests := []struct {
+		name          string
+		osType        string
+		format        string
+		bootstrapData string
+		expected      bootstrapDataDelivery
+	}{
+		{
+			name:          "bootstrap data without a format is passed as custom data",
+			osType:        "Linux",
+			bootstrapData: encode("#cloud-config\n"),
+			expected:      customDataDelivery,
+		},
+		{
+			name:          "cloud-config is passed as custom data",
+			osType:        "Linux",
+			format:        "cloud-config",
+			bootstrapData: encode("#cloud-config\n"),
+			expected:      customDataDelivery,
+		},
+		{
+			name:          "ignition is passed as custom data",
+			osType:        "Linux",
+			format:        "ignition",
+			bootstrapData: encode(`{"ignition":{"version":"3.1.0"}}`),
+			expected:      customDataDelivery,
+		},
+		{
+			name:          "bootstrap data of Windows VMs is always passed as custom data",
+			osType:        "Windows",
+			format:        "script",
+			bootstrapData: encode("#!/bin/sh\n"),
+			expected:      customDataDelivery,
+		},
+		{
+			name:          "shell script of another format is run by the Custom Script extension",
+			osType:        "Linux",
+			format:        "script",
+			bootstrapData: encode("#!/bin/bash\nk0s install worker\n"),
+			expected:      extensionDelivery,
+		},
+		{
+			name:          "other bootstrap data of another format is passed as user data",
+			osType:        "Linux",
+			format:        "talos",
+			bootstrapData: encode("version: v1alpha1\nmachine:\n  type: worker\n"),
+			expected:      userDataDelivery,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(detectBootstrapDataDelivery(tc.osType, tc.format, tc.bootstrapData)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestMachineScope_ValidateBootstrapDataDelivery(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          infrav1.AzureMachineSpec
+		delivery      bootstrapDataDelivery
+		expectedError string
+	}{
+		{
+			name:     "custom data can be combined with secondary user data",
+			spec:     infrav1.AzureMachineSpec{SecondaryUserData: &infrav1.SecondaryUserDataSource{}},
+			delivery: customDataDelivery,
+		},
+		{
+			name:          "user data cannot be combined with secondary user data",
+			spec:          infrav1.AzureMachineSpec{SecondaryUserData: &infrav1.SecondaryUserDataSource{}},
+			delivery:      userDataDelivery,
+			expectedError: "cannot have secondary user data",
+		},
+		{
+			name:          "extension cannot be combined with a Custom Script extension",
+			spec:          infrav1.AzureMachineSpec{VMExtensions: []infrav1.VMExtension{{Name: "CustomScript"}}},
+			delivery:      extensionDelivery,
+			expectedError: "cannot have the CustomScript VM extension",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := &MachineScope{
+				AzureMachine: &infrav1.AzureMachine{Spec: tc.spec},
+				cache:        &MachineCache{BootstrapDataDelivery: tc.delivery},
+			}
+			err := m.validateBootstrapDataDelivery()
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
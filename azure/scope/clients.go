@@ -101,12 +101,23 @@ func (c *AzureClients) setCredentials(subscriptionID, environmentName string) er
 	c.Values[auth.SubscriptionID] = strings.TrimSuffix(subscriptionID, "\n")
 	c.Values[auth.TenantID] = strings.TrimSuffix(c.Values[auth.TenantID], "\n")
 
-	if c.Authorizer == nil {
-		c.Authorizer, err = azureutil.GetAuthorizer(settings)
+	if c.TokenCredential == nil {
+		// The credential of the environment of the manager is shared by all the clusters without an identity.
+		key := credentialCacheKey{
+			activeDirectoryEndpoint: settings.Environment.ActiveDirectoryEndpoint,
+			resourceManagerEndpoint: settings.Environment.ResourceManagerEndpoint,
+			tokenAudience:           settings.Environment.TokenAudience,
+		}
+		c.TokenCredential, err = tokenCredentials.getOrStore(key, func() (azcore.TokenCredential, error) {
+			return azureutil.GetTokenCredential(settings.Environment)
+		})
 		if err != nil {
 			return err
 		}
 	}
+	if c.Authorizer == nil {
+		c.Authorizer = azureutil.GetAuthorizerForCredential(c.TokenCredential, settings.Environment)
+	}
 	return nil
 }
 
@@ -82,3 +82,12 @@ func TestGettingEnvironment(t *testing.T) {
 		})
 	}
 }
+
+func TestSetCredentialsFromEnvironmentSetsTokenCredential(t *testing.T) {
+	g := NewWithT(t)
+
+	c := AzureClients{}
+	g.Expect(c.setCredentials("1234", "")).To(Succeed())
+	g.Expect(c.Token()).NotTo(BeNil())
+	g.Expect(c.Authorizer).NotTo(BeNil())
+}
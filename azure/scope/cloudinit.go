@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	cloudConfigHeader = "#cloud-config"
+
+	// ignitionFormat is the format of bootstrap data which is an Ignition config rather than cloud-init user data.
+	ignitionFormat = "ignition"
+
+	// etcdDataDiskLabel is the label of the file system of the etcd data disk.
+	etcdDataDiskLabel = "etcd_disk"
+	// etcdDataDiskMountPath is the path where the etcd data disk is mounted.
+	etcdDataDiskMountPath = "/var/lib/etcddisk"
+
+	// wireServerAddress is the address of the Azure host node agent endpoint, only reachable from the VM itself.
+	wireServerAddress = "168.63.129.16"
+)
+
+// nodeProxy is the HTTP proxy set up on Linux nodes, if any.
+var nodeProxy struct {
+	url     string
+	noProxy string
+}
+
+// SetNodeProxy makes the bootstrap data of the Linux AzureMachines and AzureMachinePools created afterwards configure
+// proxyURL as the HTTP proxy of containerd, the kubelet and the processes reading /etc/environment. noProxy is a
+// comma-separated list of hosts, domain suffixes and CIDRs reached without the proxy, in addition to the VNet of the
+// cluster, the cluster services and the Azure host endpoints. An empty proxyURL disables the proxy setup.
+func SetNodeProxy(proxyURL, noProxy string) {
+	nodeProxy.url = proxyURL
+	nodeProxy.noProxy = noProxy
+}
+
+// nodeNoProxy returns the no proxy list of the nodes of a VNet.
+func nodeNoProxy(vnet *infrav1.VnetSpec) string {
+	entries := []string{}
+	for _, entry := range strings.Split(nodeProxy.noProxy, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	entries = append(entries, "localhost", "127.0.0.1", azureutil.IMDSAddress, wireServerAddress, ".svc", ".cluster.local")
+	if vnet != nil {
+		entries = append(entries, vnet.CIDRBlocks...)
+	}
+	return strings.Join(entries, ",")
+}
+
+// mergeNodeProxy merges the setup of the proxy of a node into cloud-config bootstrap data. Files are appended to
+// write_files and containerd is restarted before the commands of the bootstrap data, so that images are pulled through
+// the proxy when the node joins the cluster.
+func mergeNodeProxy(data []byte, proxyURL, noProxy string) ([]byte, error) {
+	// Percent signs, e.g. in URL encoded credentials, are specifiers in systemd unit files.
+	systemdEscape := strings.NewReplacer("%", "%%").Replace
+	dropIn := fmt.Sprintf("[Service]\nEnvironment=\"HTTP_PROXY=%[1]s\"\nEnvironment=\"HTTPS_PROXY=%[1]s\"\nEnvironment=\"NO_PROXY=%[2]s\"\n",
+		systemdEscape(proxyURL), systemdEscape(noProxy))
+	environment := fmt.Sprintf("HTTP_PROXY=%[1]s\nHTTPS_PROXY=%[1]s\nNO_PROXY=%[2]s\nhttp_proxy=%[1]s\nhttps_proxy=%[1]s\nno_proxy=%[2]s\n",
+		proxyURL, noProxy)
+
+	return mergeCloudConfig(data, func(config map[string]interface{}) {
+		writeFiles, _ := config["write_files"].([]interface{})
+		for _, unit := range []string{"containerd", "kubelet"} {
+			writeFiles = append(writeFiles, map[string]interface{}{
+				"path":        fmt.Sprintf("/etc/systemd/system/%s.service.d/http-proxy.conf", unit),
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     dropIn,
+			})
+		}
+		writeFiles = append(writeFiles, map[string]interface{}{
+			"path":    "/etc/environment",
+			"append":  true,
+			"content": environment,
+		})
+		config["write_files"] = writeFiles
+
+		existing, _ := config["runcmd"].([]interface{})
+		runCmd := []interface{}{"systemctl daemon-reload", "systemctl restart containerd"}
+		config["runcmd"] = append(runCmd, existing...)
+	})
+}
+
+// mergeCloudInit merges the additional cloud-init sections into cloud-config bootstrap data. Files are appended to
+// write_files and commands are run before the ones of the bootstrap data so they can prepare the node before it joins
+// the cluster.
+func mergeCloudInit(data []byte, additional *infrav1.CloudInit) ([]byte, error) {
+	if additional == nil || (len(additional.WriteFiles) == 0 && len(additional.RunCmd) == 0) {
+		return data, nil
+	}
+
+	return mergeCloudConfig(data, func(config map[string]interface{}) {
+		if len(additional.WriteFiles) > 0 {
+			writeFiles, _ := config["write_files"].([]interface{})
+			for _, file := range additional.WriteFiles {
+				entry := map[string]interface{}{
+					"path":    file.Path,
+					"content": file.Content,
+				}
+				if file.Owner != "" {
+					entry["owner"] = file.Owner
+				}
+				if file.Permissions != "" {
+					entry["permissions"] = file.Permissions
+				}
+				writeFiles = append(writeFiles, entry)
+			}
+			config["write_files"] = writeFiles
+		}
+
+		if len(additional.RunCmd) > 0 {
+			existing, _ := config["runcmd"].([]interface{})
+			runCmd := make([]interface{}, 0, len(additional.RunCmd)+len(existing))
+			for _, cmd := range additional.RunCmd {
+				runCmd = append(runCmd, cmd)
+			}
+			config["runcmd"] = append(runCmd, existing...)
+		}
+	})
+}
+
+// mergeEtcdDataDisk merges the partitioning, formatting and mounting of the etcd data disk attached at the device
+// path into cloud-config bootstrap data. Cloud-init sets up disks before running the commands of the bootstrap data,
+// so the disk is mounted before etcd starts. Entries already present in the bootstrap data for the device, the file
+// system label or the mount path are kept as is.
+func mergeEtcdDataDisk(data []byte, device string) ([]byte, error) {
+	return mergeCloudConfig(data, func(config map[string]interface{}) {
+		diskSetup, _ := config["disk_setup"].(map[string]interface{})
+		if diskSetup == nil {
+			diskSetup = map[string]interface{}{}
+		}
+		if _, ok := diskSetup[device]; !ok {
+			diskSetup[device] = map[string]interface{}{
+				"table_type": "gpt",
+				"layout":     true,
+				"overwrite":  false,
+			}
+		}
+		config["disk_setup"] = diskSetup
+
+		fsSetup, _ := config["fs_setup"].([]interface{})
+		hasFileSystem := false
+		for _, entry := range fsSetup {
+			if fs, ok := entry.(map[string]interface{}); ok && fs["label"] == etcdDataDiskLabel {
+				hasFileSystem = true
+			}
+		}
+		if !hasFileSystem {
+			fsSetup = append(fsSetup, map[string]interface{}{
+				"label":      etcdDataDiskLabel,
+				"filesystem": "ext4",
+				"device":     device,
+				"extra_opts": []interface{}{"-E", "lazy_itable_init=1,lazy_journal_init=1"},
+			})
+		}
+		config["fs_setup"] = fsSetup
+
+		mounts, _ := config["mounts"].([]interface{})
+		hasMount := false
+		for _, entry := range mounts {
+			if mount, ok := entry.([]interface{}); ok && len(mount) >= 2 && (mount[0] == "LABEL="+etcdDataDiskLabel || mount[1] == etcdDataDiskMountPath) {
+				hasMount = true
+			}
+		}
+		if !hasMount {
+			mounts = append(mounts, []interface{}{"LABEL=" + etcdDataDiskLabel, etcdDataDiskMountPath})
+		}
+		config["mounts"] = mounts
+	})
+}
+
+// mergeCloudConfig applies merge to the sections of cloud-config bootstrap data. The leading comment lines of the
+// bootstrap data, such as the cloud-config and jinja template headers, are preserved.
+func mergeCloudConfig(data []byte, merge func(config map[string]interface{})) ([]byte, error) {
+	var header [][]byte
+	body := data
+	for bytes.HasPrefix(body, []byte("#")) {
+		line, rest, _ := bytes.Cut(body, []byte("\n"))
+		header = append(header, line)
+		body = rest
+	}
+	isCloudConfig := false
+	for _, line := range header {
+		if bytes.Equal(bytes.TrimSpace(line), []byte(cloudConfigHeader)) {
+			isCloudConfig = true
+		}
+	}
+	if !isCloudConfig {
+		return nil, errors.New("cloud-init sections can only be merged into cloud-config bootstrap data")
+	}
+
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal(body, &config); err != nil {
+		return nil, errors.Wrap(err, "failed to parse cloud-config bootstrap data")
+	}
+
+	merge(config)
+
+	merged, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal merged cloud-config bootstrap data")
+	}
+
+	return append(append(bytes.Join(header, []byte("\n")), '\n'), merged...), nil
+}
@@ -25,26 +25,40 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
 	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20220701"
 	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/utils/net"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/applicationgateways"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/costestimates"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/diagnosticsettings"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsrecords"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedidentities"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managementlocks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkwatchers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/policychecks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatedns"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/securitygroups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/storageaccounts"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworkgateways"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -53,6 +67,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// controlPlaneExtraRulesPriority is the first priority given to the extra security rules of the control plane without
+// a priority.
+const controlPlaneExtraRulesPriority = 2300
+
+// maxSecurityRulePriority is the highest priority of a security rule.
+const maxSecurityRulePriority = 4096
+
+// The publisher and name prefix of the Azure Disk Encryption VM extensions, AzureDiskEncryption on Windows and
+// AzureDiskEncryptionForLinux on Linux.
+const (
+	azureDiskEncryptionPublisher = "Microsoft.Azure.Security"
+	azureDiskEncryptionExtension = "AzureDiskEncryption"
+)
+
 // ClusterScopeParams defines the input parameters used to create a new Scope.
 type ClusterScopeParams struct {
 	AzureClients
@@ -123,7 +151,8 @@ type ClusterScope struct {
 
 // ClusterCache stores ClusterCache data locally so we don't have to hit the API multiple times within the same reconcile loop.
 type ClusterCache struct {
-	isVnetManaged *bool
+	isVnetManaged     *bool
+	publicIPAddresses map[string]string
 }
 
 // BaseURI returns the Azure ResourceManagerEndpoint.
@@ -155,24 +184,31 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 					Location:         s.Location(),
 					ExtendedLocation: s.ExtendedLocation(),
 					FailureDomains:   s.FailureDomains(),
+					ZoneRedundant:    s.ZoneRedundantPublicIPs(),
 					AdditionalTags:   s.AdditionalTags(),
 				})
 			}
 		}
 	} else {
-		controlPlaneOutboundIPSpecs = []azure.ResourceSpecGetter{
-			&publicips.PublicIPSpec{
-				Name:             s.APIServerPublicIP().Name,
+		// The first frontend IP serves the API server, additional ones are only used for outbound connectivity.
+		for _, ip := range s.APIServerLB().FrontendIPs {
+			// Existing public IPs are neither created nor deleted.
+			if ip.PublicIP == nil || ip.PublicIP.Existing != nil {
+				continue
+			}
+			controlPlaneOutboundIPSpecs = append(controlPlaneOutboundIPSpecs, &publicips.PublicIPSpec{
+				Name:             ip.PublicIP.Name,
 				ResourceGroup:    s.ResourceGroup(),
-				DNSName:          s.APIServerPublicIP().DNSName,
+				DNSName:          ip.PublicIP.DNSName,
 				IsIPv6:           false, // Currently azure requires an IPv4 lb rule to enable IPv6
 				ClusterName:      s.ClusterName(),
 				Location:         s.Location(),
 				ExtendedLocation: s.ExtendedLocation(),
 				FailureDomains:   s.FailureDomains(),
+				ZoneRedundant:    s.ZoneRedundantPublicIPs(),
 				AdditionalTags:   s.AdditionalTags(),
-				IPTags:           s.APIServerPublicIP().IPTags,
-			},
+				IPTags:           ip.PublicIP.IPTags,
+			})
 		}
 	}
 	publicIPSpecs = append(publicIPSpecs, controlPlaneOutboundIPSpecs...)
@@ -189,6 +225,7 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 				Location:         s.Location(),
 				ExtendedLocation: s.ExtendedLocation(),
 				FailureDomains:   s.FailureDomains(),
+				ZoneRedundant:    s.ZoneRedundantPublicIPs(),
 				AdditionalTags:   s.AdditionalTags(),
 			})
 		}
@@ -206,6 +243,7 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 				ClusterName:    s.ClusterName(),
 				Location:       s.Location(),
 				FailureDomains: s.FailureDomains(),
+				ZoneRedundant:  s.ZoneRedundantPublicIPs(),
 				AdditionalTags: s.AdditionalTags(),
 				IPTags:         subnet.NatGateway.NatGatewayIP.IPTags,
 			})
@@ -223,12 +261,45 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 			ClusterName:    s.ClusterName(),
 			Location:       s.Location(),
 			FailureDomains: s.FailureDomains(),
+			ZoneRedundant:  s.ZoneRedundantPublicIPs(),
 			AdditionalTags: s.AdditionalTags(),
 			IPTags:         azureBastion.PublicIP.IPTags,
 		}
 		publicIPSpecs = append(publicIPSpecs, azureBastionPublicIP)
 	}
 
+	if appGateway := s.ApplicationGateway(); appGateway != nil {
+		// public IP for the application gateway.
+		publicIPSpecs = append(publicIPSpecs, &publicips.PublicIPSpec{
+			Name:           appGateway.PublicIP.Name,
+			ResourceGroup:  s.ResourceGroup(),
+			DNSName:        appGateway.PublicIP.DNSName,
+			IsIPv6:         false, // Public IP is IPv4 by default
+			ClusterName:    s.ClusterName(),
+			Location:       s.Location(),
+			FailureDomains: s.FailureDomains(),
+			ZoneRedundant:  s.ZoneRedundantPublicIPs(),
+			AdditionalTags: s.AdditionalTags(),
+			IPTags:         appGateway.PublicIP.IPTags,
+		})
+	}
+
+	if vng := s.VirtualNetworkGateway(); vng != nil {
+		// public IP for the virtual network gateway.
+		publicIPSpecs = append(publicIPSpecs, &publicips.PublicIPSpec{
+			Name:           vng.PublicIP.Name,
+			ResourceGroup:  s.ResourceGroup(),
+			DNSName:        vng.PublicIP.DNSName,
+			IsIPv6:         false, // Public IP is IPv4 by default
+			ClusterName:    s.ClusterName(),
+			Location:       s.Location(),
+			FailureDomains: s.FailureDomains(),
+			ZoneRedundant:  s.ZoneRedundantPublicIPs(),
+			AdditionalTags: s.AdditionalTags(),
+			IPTags:         vng.PublicIP.IPTags,
+		})
+	}
+
 	return publicIPSpecs
 }
 
@@ -253,7 +324,12 @@ func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 			Role:                 infrav1.APIServerRole,
 			BackendPoolName:      s.APIServerLB().BackendPool.Name,
 			IdleTimeoutInMinutes: s.APIServerLB().IdleTimeoutInMinutes,
+			HealthProbe:          s.APIServerLB().HealthProbe,
+			AdditionalRules:      s.apiServerLBAdditionalRules(),
+			EnableFloatingIP:     s.APIServerLB().EnableFloatingIP,
+			EnableTCPReset:       s.APIServerLB().EnableTCPReset,
 			AdditionalTags:       s.AdditionalTags(),
+			Adopt:                s.AdoptResources(),
 		},
 	}
 
@@ -275,6 +351,7 @@ func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 			IdleTimeoutInMinutes: s.NodeOutboundLB().IdleTimeoutInMinutes,
 			Role:                 infrav1.NodeOutboundRole,
 			AdditionalTags:       s.AdditionalTags(),
+			Adopt:                s.AdoptResources(),
 		})
 	}
 
@@ -296,6 +373,34 @@ func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 			IdleTimeoutInMinutes: s.ControlPlaneOutboundLB().IdleTimeoutInMinutes,
 			Role:                 infrav1.ControlPlaneOutboundRole,
 			AdditionalTags:       s.AdditionalTags(),
+			Adopt:                s.AdoptResources(),
+		})
+	}
+
+	// Node internal LB
+	if lb := s.NodeInternalLB(); lb != nil {
+		specs = append(specs, &loadbalancers.LBSpec{
+			Name:                 lb.Name,
+			ResourceGroup:        s.ResourceGroup(),
+			SubscriptionID:       s.SubscriptionID(),
+			ClusterName:          s.ClusterName(),
+			Location:             s.Location(),
+			ExtendedLocation:     s.ExtendedLocation(),
+			VNetName:             s.Vnet().Name,
+			VNetResourceGroup:    s.Vnet().ResourceGroup,
+			SubnetName:           lb.SubnetName,
+			FrontendIPConfigs:    lb.FrontendIPs,
+			Type:                 lb.Type,
+			SKU:                  lb.SKU,
+			BackendPoolName:      lb.BackendPool.Name,
+			IdleTimeoutInMinutes: lb.IdleTimeoutInMinutes,
+			HealthProbe:          lb.HealthProbe,
+			AdditionalRules:      lb.AdditionalRules,
+			EnableFloatingIP:     lb.EnableFloatingIP,
+			EnableTCPReset:       lb.EnableTCPReset,
+			Role:                 infrav1.NodeInternalRole,
+			AdditionalTags:       s.AdditionalTags(),
+			Adopt:                s.AdoptResources(),
 		})
 	}
 
@@ -312,7 +417,7 @@ func (s *ClusterScope) RouteTableSpecs() []azure.ResourceSpecGetter {
 				Location:       s.Location(),
 				ResourceGroup:  s.Vnet().ResourceGroup,
 				ClusterName:    s.ClusterName(),
-				AdditionalTags: s.AdditionalTags(),
+				AdditionalTags: s.subnetTags(subnet),
 			})
 		}
 	}
@@ -355,26 +460,273 @@ func (s *ClusterScope) NatGatewaySpecs() []azure.ASOResourceSpecGetter[*asonetwo
 func (s *ClusterScope) NSGSpecs() []azure.ResourceSpecGetter {
 	nsgspecs := make([]azure.ResourceSpecGetter, len(s.AzureCluster.Spec.NetworkSpec.Subnets))
 	for i, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
+		securityRules := subnet.SecurityGroup.SecurityRules
+		if subnet.Role == infrav1.SubnetControlPlane {
+			securityRules = s.withEgressProxySecurityRule(securityRules)
+			// Invalid extra rules are left out here and reported by ValidateControlPlaneExtraRules.
+			if withExtraRules, err := s.withControlPlaneExtraRules(securityRules, subnet.CIDRBlocks); err == nil {
+				securityRules = withExtraRules
+			}
+		}
 		nsgspecs[i] = &securitygroups.NSGSpec{
 			Name:                     subnet.SecurityGroup.Name,
-			SecurityRules:            subnet.SecurityGroup.SecurityRules,
-			ResourceGroup:            s.Vnet().ResourceGroup,
+			SecurityRules:            securityRules,
+			ResourceGroup:            s.securityGroupResourceGroup(subnet.SecurityGroup),
 			Location:                 s.Location(),
 			ClusterName:              s.ClusterName(),
-			AdditionalTags:           s.AdditionalTags(),
+			AdditionalTags:           s.subnetTags(subnet),
 			LastAppliedSecurityRules: s.getLastAppliedSecurityRules(subnet.SecurityGroup.Name),
+			Existing:                 subnet.SecurityGroup.Existing,
+			Shared:                   s.Vnet().Shared,
+			Adopt:                    s.AdoptResources(),
+		}
+	}
+	for _, region := range s.AzureCluster.Spec.SecondaryRegions {
+		for _, subnet := range region.Subnets {
+			nsgspecs = append(nsgspecs, &securitygroups.NSGSpec{
+				Name:                     subnet.SecurityGroupName,
+				SecurityRules:            subnet.SecurityRules,
+				ResourceGroup:            s.Vnet().ResourceGroup,
+				Location:                 region.Location,
+				ClusterName:              s.ClusterName(),
+				AdditionalTags:           s.AdditionalTags(),
+				LastAppliedSecurityRules: s.getLastAppliedSecurityRules(subnet.SecurityGroupName),
+			})
 		}
 	}
 
 	return nsgspecs
 }
 
+// apiServerLBAdditionalRules returns the additional load balancing rules of the API server load balancer, including
+// the rule of the egress proxy port when it is enabled.
+func (s *ClusterScope) apiServerLBAdditionalRules() []infrav1.LoadBalancingRule {
+	egressProxy := s.AzureCluster.Spec.NetworkSpec.EgressProxy
+	if egressProxy == nil {
+		return s.APIServerLB().AdditionalRules
+	}
+	rules := make([]infrav1.LoadBalancingRule, 0, len(s.APIServerLB().AdditionalRules)+1)
+	rules = append(rules, s.APIServerLB().AdditionalRules...)
+	return append(rules, infrav1.LoadBalancingRule{
+		Name:         infrav1.EgressProxyLBRuleName,
+		Protocol:     infrav1.LoadBalancingRuleProtocolTCP,
+		FrontendPort: egressProxy.Port,
+		BackendPort:  ptr.To(egressProxy.Port),
+	})
+}
+
+// withEgressProxySecurityRule returns the given security rules of the control plane subnet with a rule allowing
+// inbound traffic to the egress proxy port when it is enabled, unless a rule with the same name is already set.
+// The rule gets the first inbound priority left unused after the default control plane rules.
+func (s *ClusterScope) withEgressProxySecurityRule(rules infrav1.SecurityRules) infrav1.SecurityRules {
+	egressProxy := s.AzureCluster.Spec.NetworkSpec.EgressProxy
+	if egressProxy == nil {
+		return rules
+	}
+	priorities := make(map[int32]struct{}, len(rules))
+	for _, rule := range rules {
+		if rule.Name == infrav1.EgressProxySecurityRuleName {
+			return rules
+		}
+		if rule.Direction == infrav1.SecurityRuleDirectionInbound {
+			priorities[rule.Priority] = struct{}{}
+		}
+	}
+	priority := int32(2203)
+	for ; ; priority++ {
+		if _, ok := priorities[priority]; !ok {
+			break
+		}
+	}
+	withRule := make(infrav1.SecurityRules, 0, len(rules)+1)
+	withRule = append(withRule, rules...)
+	return append(withRule, infrav1.SecurityRule{
+		Name:             infrav1.EgressProxySecurityRuleName,
+		Description:      "Allow egress proxy",
+		Priority:         priority,
+		Protocol:         infrav1.SecurityGroupProtocolTCP,
+		Direction:        infrav1.SecurityRuleDirectionInbound,
+		Source:           ptr.To("*"),
+		SourcePorts:      ptr.To("*"),
+		Destination:      ptr.To("*"),
+		DestinationPorts: ptr.To(strconv.Itoa(int(egressProxy.Port))),
+		Action:           infrav1.SecurityRuleActionAllow,
+	})
+}
+
+// ValidateControlPlaneExtraRules returns an error if the extra rules of the control plane cannot be added to the
+// security rules of the control plane subnet.
+func (s *ClusterScope) ValidateControlPlaneExtraRules() error {
+	subnet := s.ControlPlaneSubnet()
+	_, err := s.withControlPlaneExtraRules(s.withEgressProxySecurityRule(subnet.SecurityGroup.SecurityRules), subnet.CIDRBlocks)
+	return err
+}
+
+// withControlPlaneExtraRules returns the given security rules of the control plane subnet with the extra rules of the
+// control plane, or an error if the name of an extra rule is already set or if no priority is left for it. A rule
+// without a source is rendered once per CIDR block of the control plane subnet, with the index of the CIDR block
+// appended to its name when there are several. A rule without a priority, and the copies after the first of a rule
+// with one, get the first priority of their direction left unused from 2300.
+func (s *ClusterScope) withControlPlaneExtraRules(rules infrav1.SecurityRules, cidrBlocks []string) (infrav1.SecurityRules, error) {
+	extraRules := s.AzureCluster.Spec.NetworkSpec.ControlPlaneExtraRules
+	if len(extraRules) == 0 {
+		return rules, nil
+	}
+	type directionPriority struct {
+		direction infrav1.SecurityRuleDirection
+		priority  int32
+	}
+	names := make(map[string]struct{}, len(rules))
+	priorities := make(map[directionPriority]struct{}, len(rules)+len(extraRules))
+	for _, rule := range rules {
+		names[rule.Name] = struct{}{}
+		priorities[directionPriority{rule.Direction, rule.Priority}] = struct{}{}
+	}
+	for _, rule := range extraRules {
+		if rule.Priority != 0 {
+			priorities[directionPriority{rule.Direction, rule.Priority}] = struct{}{}
+		}
+	}
+
+	withRules := make(infrav1.SecurityRules, 0, len(rules)+len(extraRules))
+	withRules = append(withRules, rules...)
+	for _, extraRule := range extraRules {
+		if _, ok := names[extraRule.Name]; ok {
+			return nil, errors.Errorf("extra control plane rule %s has the name of a security rule of the control plane subnet", extraRule.Name)
+		}
+		for i, rule := range expandControlPlaneExtraRule(extraRule, cidrBlocks) {
+			if rule.Priority == 0 || i > 0 {
+				rule.Priority = controlPlaneExtraRulesPriority
+				for ; rule.Priority <= maxSecurityRulePriority; rule.Priority++ {
+					if _, ok := priorities[directionPriority{rule.Direction, rule.Priority}]; !ok {
+						break
+					}
+				}
+				if rule.Priority > maxSecurityRulePriority {
+					return nil, errors.Errorf("no %s priority left for extra control plane rule %s", rule.Direction, rule.Name)
+				}
+				priorities[directionPriority{rule.Direction, rule.Priority}] = struct{}{}
+			}
+			withRules = append(withRules, rule)
+		}
+	}
+	return withRules, nil
+}
+
+// expandControlPlaneExtraRule returns the security rules rendered for an extra rule of the control plane: the rule
+// itself when it has a source, otherwise one rule per CIDR block of the control plane subnet.
+func expandControlPlaneExtraRule(rule infrav1.SecurityRule, cidrBlocks []string) infrav1.SecurityRules {
+	if rule.Source != nil || len(cidrBlocks) == 0 {
+		return infrav1.SecurityRules{*rule.DeepCopy()}
+	}
+	rules := make(infrav1.SecurityRules, len(cidrBlocks))
+	for i, cidrBlock := range cidrBlocks {
+		rules[i] = *rule.DeepCopy()
+		rules[i].Source = ptr.To(cidrBlock)
+		if len(cidrBlocks) > 1 {
+			rules[i].Name = fmt.Sprintf("%s-%d", rule.Name, i)
+		}
+	}
+	return rules
+}
+
+// FlowLogSpecs returns the NSG flow log specs of the network security groups created for the subnets of the cluster.
+func (s *ClusterScope) FlowLogSpecs() []azure.ResourceSpecGetter {
+	flowLogs := s.AzureCluster.Spec.NetworkSpec.FlowLogs
+	if flowLogs == nil {
+		return nil
+	}
+	var specs []azure.ResourceSpecGetter
+	securityGroups := make(map[string]struct{})
+	for _, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
+		sg := subnet.SecurityGroup
+		if sg.Name == "" || sg.Existing != nil {
+			continue
+		}
+		if _, ok := securityGroups[sg.Name]; ok {
+			continue
+		}
+		securityGroups[sg.Name] = struct{}{}
+		specs = append(specs, &networkwatchers.FlowLogSpec{
+			Name:               azure.GenerateFlowLogName(sg.Name, s.Vnet().ResourceGroup),
+			NetworkWatcherName: flowLogs.NetworkWatcher.Name,
+			NetworkWatcherRG:   flowLogs.NetworkWatcher.ResourceGroup,
+			Location:           s.Location(),
+			ClusterName:        s.ClusterName(),
+			SecurityGroupID:    azure.SecurityGroupID(s.SubscriptionID(), s.Vnet().ResourceGroup, sg.Name),
+			StorageAccountID:   flowLogs.StorageAccountID,
+			RetentionDays:      flowLogs.RetentionDays,
+			TrafficAnalytics:   flowLogs.TrafficAnalytics,
+			AdditionalTags:     s.AdditionalTags(),
+		})
+	}
+	return specs
+}
+
+// DiagnosticSettingSpecs returns the Azure Monitor diagnostic setting specs of the load balancers, network security
+// groups and public IPs created for the cluster.
+func (s *ClusterScope) DiagnosticSettingSpecs() []azure.ResourceSpecGetter {
+	diagnosticSettings := s.AzureCluster.Spec.NetworkSpec.DiagnosticSettings
+	if diagnosticSettings == nil {
+		return nil
+	}
+	var resourceIDs []string
+	for _, lb := range s.LBSpecs() {
+		resourceIDs = append(resourceIDs, azure.LoadBalancerID(s.SubscriptionID(), lb.ResourceGroupName(), lb.ResourceName()))
+	}
+	for _, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
+		sg := subnet.SecurityGroup
+		if sg.Name == "" || sg.Existing != nil {
+			continue
+		}
+		resourceIDs = append(resourceIDs, azure.SecurityGroupID(s.SubscriptionID(), s.Vnet().ResourceGroup, sg.Name))
+	}
+	for _, ip := range s.PublicIPSpecs() {
+		resourceIDs = append(resourceIDs, azure.PublicIPID(s.SubscriptionID(), ip.ResourceGroupName(), ip.ResourceName()))
+	}
+
+	var specs []azure.ResourceSpecGetter
+	seen := make(map[string]struct{}, len(resourceIDs))
+	for _, resourceID := range resourceIDs {
+		if _, ok := seen[resourceID]; ok {
+			continue
+		}
+		seen[resourceID] = struct{}{}
+		categories, ok := diagnosticsettings.LogCategories(resourceID, diagnosticSettings.Categories)
+		if !ok {
+			continue
+		}
+		specs = append(specs, &diagnosticsettings.DiagnosticSettingSpec{
+			Name:                azure.GenerateDiagnosticSettingName(s.ClusterName()),
+			ResourceID:          resourceID,
+			WorkspaceResourceID: diagnosticSettings.WorkspaceResourceID,
+			Categories:          categories,
+		})
+	}
+	return specs
+}
+
+// securityGroupResourceGroup returns the resource group of a security group: the resource group of the existing NSG
+// it references, or else the resource group of the vnet.
+func (s *ClusterScope) securityGroupResourceGroup(sg infrav1.SecurityGroup) string {
+	if rg := sg.ExistingResourceGroup(); rg != "" {
+		return rg
+	}
+	return s.Vnet().ResourceGroup
+}
+
 // SubnetSpecs returns the subnets specs.
 func (s *ClusterScope) SubnetSpecs() []azure.ResourceSpecGetter {
 	numberOfSubnets := len(s.AzureCluster.Spec.NetworkSpec.Subnets)
 	if s.IsAzureBastionEnabled() {
 		numberOfSubnets++
 	}
+	if s.Gateway() != nil {
+		numberOfSubnets++
+	}
+	if s.ApplicationGateway() != nil {
+		numberOfSubnets++
+	}
 
 	subnetSpecs := make([]azure.ResourceSpecGetter, 0, numberOfSubnets)
 
@@ -389,6 +741,7 @@ func (s *ClusterScope) SubnetSpecs() []azure.ResourceSpecGetter {
 			IsVNetManaged:     s.IsVnetManaged(),
 			RouteTableName:    subnet.RouteTable.Name,
 			SecurityGroupName: subnet.SecurityGroup.Name,
+			SecurityGroupRG:   subnet.SecurityGroup.ExistingResourceGroup(),
 			Role:              subnet.Role,
 			NatGatewayName:    subnet.NatGateway.Name,
 			ServiceEndpoints:  subnet.ServiceEndpoints,
@@ -413,6 +766,48 @@ func (s *ClusterScope) SubnetSpecs() []azure.ResourceSpecGetter {
 		})
 	}
 
+	if gateway := s.Gateway(); gateway != nil {
+		// Azure does not support network security groups on the GatewaySubnet.
+		subnetSpecs = append(subnetSpecs, &subnets.SubnetSpec{
+			Name:              infrav1.GatewaySubnetName,
+			ResourceGroup:     s.ResourceGroup(),
+			SubscriptionID:    s.SubscriptionID(),
+			CIDRs:             gateway.CIDRBlocks,
+			VNetName:          s.Vnet().Name,
+			VNetResourceGroup: s.Vnet().ResourceGroup,
+			IsVNetManaged:     s.IsVnetManaged(),
+		})
+	}
+
+	if appGateway := s.ApplicationGateway(); appGateway != nil {
+		// The subnet of the application gateway is dedicated to it, so it has no network security group or route table.
+		subnetSpecs = append(subnetSpecs, &subnets.SubnetSpec{
+			Name:              appGateway.SubnetName,
+			ResourceGroup:     s.ResourceGroup(),
+			SubscriptionID:    s.SubscriptionID(),
+			CIDRs:             appGateway.CIDRBlocks,
+			VNetName:          s.Vnet().Name,
+			VNetResourceGroup: s.Vnet().ResourceGroup,
+			IsVNetManaged:     s.IsVnetManaged(),
+		})
+	}
+
+	// The virtual networks of the secondary regions are always created by the cluster.
+	for _, region := range s.AzureCluster.Spec.SecondaryRegions {
+		for _, subnet := range region.Subnets {
+			subnetSpecs = append(subnetSpecs, &subnets.SubnetSpec{
+				Name:              subnet.Name,
+				ResourceGroup:     s.ResourceGroup(),
+				SubscriptionID:    s.SubscriptionID(),
+				CIDRs:             subnet.CIDRBlocks,
+				VNetName:          region.VnetName,
+				VNetResourceGroup: s.Vnet().ResourceGroup,
+				IsVNetManaged:     true,
+				SecurityGroupName: subnet.SecurityGroupName,
+			})
+		}
+	}
+
 	return subnetSpecs
 }
 
@@ -462,6 +857,32 @@ func (s *ClusterScope) VnetPeeringSpecs() []azure.ResourceSpecGetter {
 		peeringSpecs[i*2+1] = reversePeering
 	}
 
+	// The virtual network of each secondary region is peered with the virtual network of the cluster.
+	for _, region := range s.AzureCluster.Spec.SecondaryRegions {
+		peeringSpecs = append(peeringSpecs,
+			&vnetpeerings.VnetPeeringSpec{
+				PeeringName:               azure.GenerateVnetPeeringName(s.Vnet().Name, region.VnetName),
+				SourceVnetName:            s.Vnet().Name,
+				SourceResourceGroup:       s.Vnet().ResourceGroup,
+				RemoteVnetName:            region.VnetName,
+				RemoteResourceGroup:       s.Vnet().ResourceGroup,
+				SubscriptionID:            s.SubscriptionID(),
+				AllowForwardedTraffic:     ptr.To(true),
+				AllowVirtualNetworkAccess: ptr.To(true),
+			},
+			&vnetpeerings.VnetPeeringSpec{
+				PeeringName:               azure.GenerateVnetPeeringName(region.VnetName, s.Vnet().Name),
+				SourceVnetName:            region.VnetName,
+				SourceResourceGroup:       s.Vnet().ResourceGroup,
+				RemoteVnetName:            s.Vnet().Name,
+				RemoteResourceGroup:       s.Vnet().ResourceGroup,
+				SubscriptionID:            s.SubscriptionID(),
+				AllowForwardedTraffic:     ptr.To(true),
+				AllowVirtualNetworkAccess: ptr.To(true),
+			},
+		)
+	}
+
 	return peeringSpecs
 }
 
@@ -475,6 +896,140 @@ func (s *ClusterScope) VNetSpec() azure.ResourceSpecGetter {
 		Location:         s.Location(),
 		ClusterName:      s.ClusterName(),
 		AdditionalTags:   s.AdditionalTags(),
+
+		DDoSProtectionPlanID: s.ddosProtectionPlanID(),
+		Shared:               s.Vnet().Shared,
+		Adopt:                s.AdoptResources(),
+	}
+}
+
+// SecondaryVNetSpecs returns the specs of the virtual networks of the secondary regions of the cluster.
+func (s *ClusterScope) SecondaryVNetSpecs() []azure.ResourceSpecGetter {
+	specs := make([]azure.ResourceSpecGetter, 0, len(s.AzureCluster.Spec.SecondaryRegions))
+	for _, region := range s.AzureCluster.Spec.SecondaryRegions {
+		specs = append(specs, &virtualnetworks.VNetSpec{
+			ResourceGroup:  s.Vnet().ResourceGroup,
+			Name:           region.VnetName,
+			CIDRs:          region.CIDRBlocks,
+			Location:       region.Location,
+			ClusterName:    s.ClusterName(),
+			AdditionalTags: s.AdditionalTags(),
+		})
+	}
+	return specs
+}
+
+// DDoSProtectionPlanSpec returns the spec of the DDoS protection plan created for the vnet, or nil if the vnet is not
+// associated with a DDoS protection plan or is associated with an existing one.
+func (s *ClusterScope) DDoSProtectionPlanSpec() azure.ResourceSpecGetter {
+	plan := s.Vnet().DDoSProtectionPlan
+	if plan == nil || plan.ID != "" {
+		return nil
+	}
+	return &virtualnetworks.DDoSProtectionPlanSpec{
+		Name:           plan.Name,
+		ResourceGroup:  s.Vnet().ResourceGroup,
+		Location:       s.Location(),
+		ClusterName:    s.ClusterName(),
+		AdditionalTags: s.AdditionalTags(),
+	}
+}
+
+// ddosProtectionPlanID returns the resource ID of the DDoS protection plan of the vnet, or an empty string if it has none.
+func (s *ClusterScope) ddosProtectionPlanID() string {
+	plan := s.Vnet().DDoSProtectionPlan
+	if plan == nil {
+		return ""
+	}
+	if plan.ID != "" {
+		return plan.ID
+	}
+	return azure.DDoSProtectionPlanID(s.SubscriptionID(), s.Vnet().ResourceGroup, plan.Name)
+}
+
+// TagsSpecs returns the tags for the cluster's virtual network, load balancers, security groups and route tables.
+// Resources which are not owned by the cluster, such as a pre-existing virtual network, are skipped by the tags service.
+func (s *ClusterScope) TagsSpecs() []azure.TagsSpec {
+	specs := []azure.TagsSpec{
+		{
+			Scope:      azure.VNetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name),
+			Tags:       s.AdditionalTags(),
+			Annotation: azure.VNetTagsLastAppliedAnnotation,
+		},
+	}
+	if s.APIServerLB() != nil && s.APIServerLB().Name != "" {
+		specs = append(specs, azure.TagsSpec{
+			Scope:      azure.LoadBalancerID(s.SubscriptionID(), s.ResourceGroup(), s.APIServerLB().Name),
+			Tags:       s.AdditionalTags(),
+			Annotation: azure.APIServerLBTagsLastAppliedAnnotation,
+		})
+	}
+	if s.NodeOutboundLB() != nil {
+		specs = append(specs, azure.TagsSpec{
+			Scope:      azure.LoadBalancerID(s.SubscriptionID(), s.ResourceGroup(), s.NodeOutboundLB().Name),
+			Tags:       s.AdditionalTags(),
+			Annotation: azure.NodeOutboundLBTagsLastAppliedAnnotation,
+		})
+	}
+	if s.NodeInternalLB() != nil {
+		specs = append(specs, azure.TagsSpec{
+			Scope:      azure.LoadBalancerID(s.SubscriptionID(), s.ResourceGroup(), s.NodeInternalLB().Name),
+			Tags:       s.AdditionalTags(),
+			Annotation: azure.NodeInternalLBTagsLastAppliedAnnotation,
+		})
+	}
+	if s.ControlPlaneOutboundLB() != nil {
+		specs = append(specs, azure.TagsSpec{
+			Scope:      azure.LoadBalancerID(s.SubscriptionID(), s.ResourceGroup(), s.ControlPlaneOutboundLB().Name),
+			Tags:       s.AdditionalTags(),
+			Annotation: azure.ControlPlaneOutboundLBTagsLastAppliedAnnotation,
+		})
+	}
+
+	// The security group and route table of each subnet get their own annotation, indexed like the subnets. A security
+	// group or route table used by several subnets gets the tags of the first of them.
+	securityGroups := make(map[string]struct{})
+	routeTables := make(map[string]struct{})
+	for i, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
+		if name := subnet.SecurityGroup.Name; name != "" && subnet.SecurityGroup.Existing == nil {
+			if _, ok := securityGroups[name]; !ok {
+				securityGroups[name] = struct{}{}
+				specs = append(specs, azure.TagsSpec{
+					Scope:      azure.SecurityGroupID(s.SubscriptionID(), s.securityGroupResourceGroup(subnet.SecurityGroup), name),
+					Tags:       s.subnetTags(subnet),
+					Annotation: fmt.Sprintf("%s-%d", azure.SecurityGroupTagsLastAppliedAnnotation, i),
+				})
+			}
+		}
+		if name := subnet.RouteTable.Name; name != "" {
+			if _, ok := routeTables[name]; !ok {
+				routeTables[name] = struct{}{}
+				specs = append(specs, azure.TagsSpec{
+					Scope:      azure.RouteTableID(s.SubscriptionID(), s.Vnet().ResourceGroup, name),
+					Tags:       s.subnetTags(subnet),
+					Annotation: fmt.Sprintf("%s-%d", azure.RouteTableTagsLastAppliedAnnotation, i),
+				})
+			}
+		}
+	}
+	return specs
+}
+
+// DNSRecordSpecs returns the public DNS record set specs pointing to the API server public IP.
+func (s *ClusterScope) DNSRecordSpecs() []azure.ResourceSpecGetter {
+	record := s.AzureCluster.Spec.NetworkSpec.APIServerDNSRecord
+	if record == nil || s.IsAPIServerPrivate() {
+		return nil
+	}
+	return []azure.ResourceSpecGetter{
+		&dnsrecords.DNSRecordSpec{
+			Name:              record.RecordName,
+			ZoneName:          record.ZoneName,
+			ZoneResourceGroup: record.ZoneResourceGroup,
+			TTL:               ptr.Deref(record.TTL, infrav1.DefaultDNSRecordTTL),
+			TargetResourceID:  s.APIServerPublicIPID(),
+			ClusterName:       s.ClusterName(),
+		},
 	}
 }
 
@@ -538,10 +1093,69 @@ func (s *ClusterScope) AzureBastion() *infrav1.AzureBastion {
 	return s.AzureCluster.Spec.BastionSpec.AzureBastion
 }
 
+// Gateway returns the GatewaySubnet configuration of the cluster, or nil if the virtual network has no GatewaySubnet.
+func (s *ClusterScope) Gateway() *infrav1.GatewaySpec {
+	return s.AzureCluster.Spec.NetworkSpec.Gateway
+}
+
+// VirtualNetworkGateway returns the virtual network gateway of the cluster, or nil if the cluster doesn't create one.
+func (s *ClusterScope) VirtualNetworkGateway() *infrav1.VirtualNetworkGatewaySpec {
+	if gateway := s.Gateway(); gateway != nil {
+		return gateway.VirtualNetworkGateway
+	}
+	return nil
+}
+
+// VirtualNetworkGatewaySpec returns the virtual network gateway spec.
+func (s *ClusterScope) VirtualNetworkGatewaySpec() azure.ResourceSpecGetter {
+	vng := s.VirtualNetworkGateway()
+	if vng == nil {
+		return nil
+	}
+	return &virtualnetworkgateways.VirtualNetworkGatewaySpec{
+		Name:           vng.Name,
+		ResourceGroup:  s.Vnet().ResourceGroup,
+		Location:       s.Location(),
+		ClusterName:    s.ClusterName(),
+		SubnetID:       azure.SubnetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name, infrav1.GatewaySubnetName),
+		PublicIPID:     azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), vng.PublicIP.Name),
+		Type:           vng.Type,
+		SKU:            vng.SKU,
+		AdditionalTags: s.AdditionalTags(),
+	}
+}
+
+// ApplicationGateway returns the application gateway of the cluster, or nil if the cluster doesn't create one.
+func (s *ClusterScope) ApplicationGateway() *infrav1.ApplicationGatewaySpec {
+	return s.AzureCluster.Spec.ApplicationGateway
+}
+
+// ApplicationGatewaySpec returns the application gateway spec.
+func (s *ClusterScope) ApplicationGatewaySpec() azure.ResourceSpecGetter {
+	appGateway := s.ApplicationGateway()
+	if appGateway == nil {
+		return nil
+	}
+	return &applicationgateways.ApplicationGatewaySpec{
+		Name:           appGateway.Name,
+		ResourceGroup:  s.ResourceGroup(),
+		SubscriptionID: s.SubscriptionID(),
+		Location:       s.Location(),
+		ClusterName:    s.ClusterName(),
+		SubnetID:       azure.SubnetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name, appGateway.SubnetName),
+		PublicIPID:     azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), appGateway.PublicIP.Name),
+		FailureDomains: s.FailureDomains(),
+		MinCapacity:    appGateway.MinCapacity,
+		MaxCapacity:    appGateway.MaxCapacity,
+		WAF:            appGateway.WAF,
+		AdditionalTags: s.AdditionalTags(),
+	}
+}
+
 // AzureBastionSpec returns the bastion spec.
 func (s *ClusterScope) AzureBastionSpec() azure.ResourceSpecGetter {
 	if s.IsAzureBastionEnabled() {
-		subnetID := azure.SubnetID(s.SubscriptionID(), s.ResourceGroup(), s.Vnet().Name, s.AzureBastion().Subnet.Name)
+		subnetID := azure.SubnetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name, s.AzureBastion().Subnet.Name)
 		publicIPID := azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), s.AzureBastion().PublicIP.Name)
 
 		return &bastionhosts.AzureBastionSpec{
@@ -574,14 +1188,15 @@ func (s *ClusterScope) IsVnetManaged() bool {
 	return isVnetManaged
 }
 
+// AdoptResources returns true if the pre-existing resources with the names of those of the cluster which are not owned
+// by any cluster are adopted, see infrav1.AdoptResourcesAnnotation.
+func (s *ClusterScope) AdoptResources() bool {
+	return s.AzureCluster.Annotations[infrav1.AdoptResourcesAnnotation] == "true"
+}
+
 // IsIPv6Enabled returns true if IPv6 is enabled.
 func (s *ClusterScope) IsIPv6Enabled() bool {
-	for _, cidr := range s.AzureCluster.Spec.NetworkSpec.Vnet.CIDRBlocks {
-		if net.IsIPv6CIDRString(cidr) {
-			return true
-		}
-	}
-	return false
+	return s.AzureCluster.Spec.NetworkSpec.Vnet.IsIPv6Enabled()
 }
 
 // Subnets returns the cluster subnets.
@@ -673,6 +1288,32 @@ func (s *ClusterScope) ControlPlaneOutboundLB() *infrav1.LoadBalancerSpec {
 	return s.AzureCluster.Spec.NetworkSpec.ControlPlaneOutboundLB
 }
 
+// NodeInternalLB returns the cluster node internal load balancer.
+func (s *ClusterScope) NodeInternalLB() *infrav1.NodeInternalLBSpec {
+	return s.AzureCluster.Spec.NetworkSpec.NodeInternalLB
+}
+
+// NodeInternalLBName returns the name of the node internal LB, if any.
+func (s *ClusterScope) NodeInternalLBName() string {
+	if s.NodeInternalLB() == nil {
+		return ""
+	}
+	return s.NodeInternalLB().Name
+}
+
+// NodeInternalLBPoolName returns the node internal LB backend pool name, if any.
+func (s *ClusterScope) NodeInternalLBPoolName() string {
+	if s.NodeInternalLB() == nil {
+		return ""
+	}
+	return s.NodeInternalLB().BackendPool.Name
+}
+
+// NodeRoutes returns the configuration of the routes to the pod CIDRs of the nodes, if any.
+func (s *ClusterScope) NodeRoutes() *infrav1.NodeRoutesSpec {
+	return s.AzureCluster.Spec.NetworkSpec.NodeRoutes
+}
+
 // APIServerLBName returns the API Server LB name.
 func (s *ClusterScope) APIServerLBName() string {
 	return s.APIServerLB().Name
@@ -688,6 +1329,33 @@ func (s *ClusterScope) APIServerPublicIP() *infrav1.PublicIPSpec {
 	return s.APIServerLB().FrontendIPs[0].PublicIP
 }
 
+// APIServerPublicIPID returns the resource ID of the API Server public IP.
+func (s *ClusterScope) APIServerPublicIPID() string {
+	if existing := s.APIServerPublicIP().Existing; existing != nil && existing.ID != "" {
+		return existing.ID
+	}
+	return azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), s.APIServerPublicIP().Name)
+}
+
+// ExistingAPIServerPublicIP returns the reference to the existing public IP of a public API server, if any.
+func (s *ClusterScope) ExistingAPIServerPublicIP() *infrav1.ExistingPublicIP {
+	if s.IsAPIServerPrivate() || s.APIServerPublicIP() == nil {
+		return nil
+	}
+	return s.APIServerPublicIP().Existing
+}
+
+// SetExistingAPIServerPublicIP records the resource ID, name and address of the existing public IP of the API server.
+// The DNS name is only set when the user did not provide one.
+func (s *ClusterScope) SetExistingAPIServerPublicIP(id, name, dnsName string) {
+	ip := s.APIServerPublicIP()
+	ip.Existing.ID = id
+	ip.Name = name
+	if ip.DNSName == "" {
+		ip.DNSName = dnsName
+	}
+}
+
 // APIServerPrivateIP returns the API Server private IP.
 func (s *ClusterScope) APIServerPrivateIP() string {
 	return s.APIServerLB().FrontendIPs[0].PrivateIPAddress
@@ -835,6 +1503,9 @@ func (s *ClusterScope) PatchObject(ctx context.Context) error {
 			infrav1.NATGatewaysReadyCondition,
 			infrav1.LoadBalancersReadyCondition,
 			infrav1.BastionHostReadyCondition,
+			infrav1.VirtualNetworkGatewayReadyCondition,
+			infrav1.ApplicationGatewayReadyCondition,
+			infrav1.FlowLogsReadyCondition,
 			infrav1.VNetReadyCondition,
 			infrav1.SubnetsReadyCondition,
 			infrav1.SecurityGroupsReadyCondition,
@@ -842,6 +1513,15 @@ func (s *ClusterScope) PatchObject(ctx context.Context) error {
 			infrav1.PrivateDNSLinkReadyCondition,
 			infrav1.PrivateDNSRecordReadyCondition,
 			infrav1.PrivateEndpointsReadyCondition,
+			infrav1.PublicIPsReadyCondition,
+			infrav1.DNSRecordsReadyCondition,
+			infrav1.StorageAccountReadyCondition,
+			infrav1.ManagedIdentityReadyCondition,
+			infrav1.ResourceGroupLockedCondition,
+			infrav1.PolicyCompliantCondition,
+			infrav1.NoDriftCondition,
+			infrav1.PlatformManagedKeysOnlyCondition,
+			infrav1.ControlPlaneSingleZoneCondition,
 		}})
 }
 
@@ -859,6 +1539,13 @@ func (s *ClusterScope) AdditionalTags() infrav1.Tags {
 	return tags
 }
 
+// subnetTags returns the tags for the resources of a subnet: the cluster's AdditionalTags, overridden by the subnet's.
+func (s *ClusterScope) subnetTags(subnet infrav1.SubnetSpec) infrav1.Tags {
+	tags := s.AdditionalTags()
+	tags.Merge(subnet.AdditionalTags)
+	return tags
+}
+
 // APIServerPort returns the APIServerPort to use when creating the load balancer.
 func (s *ClusterScope) APIServerPort() int32 {
 	if s.Cluster.Spec.ClusterNetwork != nil && s.Cluster.Spec.ClusterNetwork.APIServerPort != nil {
@@ -872,9 +1559,36 @@ func (s *ClusterScope) APIServerHost() string {
 	if s.IsAPIServerPrivate() {
 		return azure.GeneratePrivateFQDN(s.GetPrivateDNSZoneName())
 	}
+	if record := s.AzureCluster.Spec.NetworkSpec.APIServerDNSRecord; record != nil {
+		return apiServerDNSRecordFQDN(record)
+	}
 	return s.APIServerPublicIP().DNSName
 }
 
+// apiServerDNSRecordFQDN returns the fully qualified domain name of an API server DNS record.
+func apiServerDNSRecordFQDN(record *infrav1.DNSRecordSpec) string {
+	if record.RecordName == "" || record.RecordName == "@" {
+		return record.ZoneName
+	}
+	return fmt.Sprintf("%s.%s", record.RecordName, record.ZoneName)
+}
+
+// FailureDomainVMSizes returns the VM sizes that must be available in an availability zone for it to be a failure domain.
+func (s *ClusterScope) FailureDomainVMSizes() []string {
+	return s.AzureCluster.Spec.FailureDomainVMSizes
+}
+
+// PlatformFaultDomainCount returns the fault domain count of the availability sets of the cluster, or nil to use the
+// maximum supported by the region.
+func (s *ClusterScope) PlatformFaultDomainCount() *int32 {
+	return s.AzureCluster.Spec.PlatformFaultDomainCount
+}
+
+// ClearFailureDomains removes all the failure domains from the cluster's status.
+func (s *ClusterScope) ClearFailureDomains() {
+	s.AzureCluster.Status.FailureDomains = nil
+}
+
 // SetFailureDomain sets a failure domain in a cluster's status by its id.
 // The provided failure domain spec may be overridden to false by cluster's spec property.
 func (s *ClusterScope) SetFailureDomain(id string, spec clusterv1.FailureDomainSpec) {
@@ -882,13 +1596,23 @@ func (s *ClusterScope) SetFailureDomain(id string, spec clusterv1.FailureDomainS
 		s.AzureCluster.Status.FailureDomains = make(clusterv1.FailureDomains)
 	}
 
-	if fd, ok := s.AzureCluster.Spec.FailureDomains[id]; ok && !fd.ControlPlane {
-		spec.ControlPlane = false
+	if fd, ok := s.AzureCluster.Spec.FailureDomains[id]; ok {
+		if !fd.ControlPlane {
+			spec.ControlPlane = false
+		}
+		if len(fd.Attributes) > 0 {
+			spec.Attributes = fd.Attributes
+		}
 	}
 
 	s.AzureCluster.Status.FailureDomains[id] = spec
 }
 
+// ZoneRedundantPublicIPs returns true if the cluster's public IPs must span the availability zones of the region.
+func (s *ClusterScope) ZoneRedundantPublicIPs() bool {
+	return s.AzureCluster.Spec.ZoneRedundantPublicIPs
+}
+
 // FailureDomains returns the failure domains for the cluster.
 func (s *ClusterScope) FailureDomains() []*string {
 	fds := make([]*string, len(s.AzureCluster.Status.FailureDomains))
@@ -906,6 +1630,569 @@ func (s *ClusterScope) FailureDomains() []*string {
 	return fds
 }
 
+// SSHKeyPairSecretName returns the name of the Secret holding the cluster SSH key pair,
+// or an empty string if the cluster does not use a generated SSH key pair.
+func (s *ClusterScope) SSHKeyPairSecretName() string {
+	if s.AzureCluster.Spec.SSHKeyPair == nil {
+		return ""
+	}
+	if s.AzureCluster.Spec.SSHKeyPair.SecretName != "" {
+		return s.AzureCluster.Spec.SSHKeyPair.SecretName
+	}
+	return azure.GenerateSSHKeyPairSecretName(s.ClusterName())
+}
+
+// SSHPublicKeys returns the base64-encoded SSH public keys of the cluster authorized on the machines of the given role.
+func (s *ClusterScope) SSHPublicKeys(role string) []string {
+	keys := s.AzureCluster.Spec.SSHPublicKeys
+	if keys == nil {
+		return nil
+	}
+	if role == infrav1.ControlPlane {
+		return keys.ControlPlane
+	}
+	return keys.Nodes
+}
+
+// BootstrapDataStorageAccountName returns the name of the storage account used to deliver bootstrap data,
+// or an empty string if the cluster does not use one.
+func (s *ClusterScope) BootstrapDataStorageAccountName() string {
+	if s.AzureCluster.Spec.BootstrapDataStorage == nil {
+		return ""
+	}
+	if s.AzureCluster.Spec.BootstrapDataStorage.StorageAccountName != "" {
+		return s.AzureCluster.Spec.BootstrapDataStorage.StorageAccountName
+	}
+	return azure.GenerateBootstrapDataStorageAccountName(s.SubscriptionID(), s.ResourceGroup(), s.ClusterName())
+}
+
+// BootstrapDataKeyVault returns the Key Vault used to deliver bootstrap data with its resource group defaulted,
+// or nil if the cluster does not use one.
+func (s *ClusterScope) BootstrapDataKeyVault() *infrav1.BootstrapDataKeyVault {
+	if s.AzureCluster.Spec.BootstrapDataKeyVault == nil {
+		return nil
+	}
+	vault := s.AzureCluster.Spec.BootstrapDataKeyVault.DeepCopy()
+	if vault.ResourceGroup == "" {
+		vault.ResourceGroup = s.ResourceGroup()
+	}
+	return vault
+}
+
+// StorageAccountSpec returns the storage account spec used to deliver bootstrap data.
+func (s *ClusterScope) StorageAccountSpec() azure.ResourceSpecGetter {
+	name := s.BootstrapDataStorageAccountName()
+	if name == "" {
+		return nil
+	}
+	storage := s.AzureCluster.Spec.BootstrapDataStorage
+	minimumTLSVersion := armstorage.MinimumTLSVersionTLS12
+	if storage.MinimumTLSVersion != "" {
+		minimumTLSVersion = armstorage.MinimumTLSVersion(storage.MinimumTLSVersion)
+	}
+	return &storageaccounts.StorageAccountSpec{
+		Name:                  name,
+		ResourceGroup:         s.ResourceGroup(),
+		ClusterName:           s.ClusterName(),
+		Location:              s.Location(),
+		AdditionalTags:        s.AdditionalTags(),
+		HTTPSTrafficOnly:      ptr.Deref(storage.HTTPSTrafficOnly, true),
+		MinimumTLSVersion:     minimumTLSVersion,
+		AllowBlobPublicAccess: ptr.Deref(storage.AllowBlobPublicAccess, false),
+	}
+}
+
+// ResourceGroupLockSpec returns the spec of the management lock protecting the cluster resource group from deletion,
+// or nil if the resource group is neither locked nor should be.
+func (s *ClusterScope) ResourceGroupLockSpec() azure.ResourceSpecGetter {
+	if !s.ShouldLockResourceGroup() && !s.isResourceGroupLocked() {
+		return nil
+	}
+	return &managementlocks.LockSpec{
+		Name:          azure.GenerateResourceGroupLockName(s.ClusterName()),
+		ResourceGroup: s.ResourceGroup(),
+		Notes:         fmt.Sprintf("Protects the resources of cluster %s/%s from deletion. Managed by cluster-api-provider-azure.", s.Namespace(), s.ClusterName()),
+	}
+}
+
+// ManagedIdentityProviderID returns the ID of the user-assigned identity created for the cluster, or an empty string if
+// the cluster has none.
+func (s *ClusterScope) ManagedIdentityProviderID() string {
+	if s.AzureCluster.Spec.ManagedIdentity == nil {
+		return ""
+	}
+	return azure.UserAssignedIdentityID(s.SubscriptionID(), s.ResourceGroup(), s.managedIdentityName())
+}
+
+// managedIdentityName returns the name of the user-assigned identity created for the cluster.
+func (s *ClusterScope) managedIdentityName() string {
+	if s.AzureCluster.Spec.ManagedIdentity.Name != "" {
+		return s.AzureCluster.Spec.ManagedIdentity.Name
+	}
+	return azure.GenerateManagedIdentityName(s.ClusterName())
+}
+
+// ManagedIdentitySpec returns the spec of the user-assigned identity created for the cluster, or nil if the cluster
+// has none.
+func (s *ClusterScope) ManagedIdentitySpec() azure.ResourceSpecGetter {
+	if s.AzureCluster.Spec.ManagedIdentity == nil {
+		return nil
+	}
+	return &managedidentities.IdentitySpec{
+		Name:           s.managedIdentityName(),
+		ResourceGroup:  s.ResourceGroup(),
+		ClusterName:    s.ClusterName(),
+		Location:       s.Location(),
+		AdditionalTags: s.AdditionalTags(),
+	}
+}
+
+// ManagedIdentityRoleAssignmentSpecs returns the specs of the role assignments of the user-assigned identity created
+// for the cluster. Without roles in the spec, the identity is assigned the Contributor role on the cluster resource
+// group.
+func (s *ClusterScope) ManagedIdentityRoleAssignmentSpecs(principalID *string) []azure.ResourceSpecGetter {
+	if s.AzureCluster.Spec.ManagedIdentity == nil {
+		return nil
+	}
+	roles := s.AzureCluster.Spec.ManagedIdentity.Roles
+	if len(roles) == 0 {
+		roles = []infrav1.ManagedIdentityRole{{
+			DefinitionID: fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", s.SubscriptionID(), infrav1.ContributorRoleID),
+		}}
+	}
+	identityID := s.ManagedIdentityProviderID()
+	specs := make([]azure.ResourceSpecGetter, 0, len(roles))
+	for _, role := range roles {
+		scope := role.Scope
+		if scope == "" {
+			scope = azure.ResourceGroupID(s.SubscriptionID(), s.ResourceGroup())
+		}
+		specs = append(specs, &roleassignments.RoleAssignmentSpec{
+			Name:             azure.GenerateRoleAssignmentName(identityID, role.DefinitionID, scope),
+			ResourceGroup:    s.ResourceGroup(),
+			PrincipalID:      principalID,
+			PrincipalType:    ptr.To(armauthorization.PrincipalTypeServicePrincipal),
+			RoleDefinitionID: role.DefinitionID,
+			Scope:            scope,
+		})
+	}
+	return specs
+}
+
+// SetResourceGroupManaged records whether the resource group of the cluster is managed by CAPZ.
+func (s *ClusterScope) SetResourceGroupManaged(managed bool) {
+	s.AzureCluster.Status.ResourceGroupManaged = ptr.To(managed)
+}
+
+// ShouldLockResourceGroup returns true if the cluster resource group should be protected by a management lock.
+// The lock is released as soon as the Cluster is deleted so that its machines can be deleted.
+func (s *ClusterScope) ShouldLockResourceGroup() bool {
+	return s.AzureCluster.Spec.ResourceGroupLock && s.Cluster.DeletionTimestamp.IsZero()
+}
+
+// isResourceGroupLocked returns true if the management lock of the cluster resource group may exist.
+func (s *ClusterScope) isResourceGroupLocked() bool {
+	condition := conditions.Get(s.AzureCluster, infrav1.ResourceGroupLockedCondition)
+	return condition != nil && condition.Reason != infrav1.DeletedReason
+}
+
+// PolicyCheckSpecs returns the resources of the cluster to check against Azure Policy before they are created, or nil
+// if the policy pre-flight check is disabled or has already passed.
+func (s *ClusterScope) PolicyCheckSpecs() []azure.ResourceSpecGetter {
+	if !s.AzureCluster.Spec.PolicyPreflightCheck || conditions.IsTrue(s.AzureCluster, infrav1.PolicyCompliantCondition) {
+		return nil
+	}
+	specs := []azure.ResourceSpecGetter{
+		&policychecks.ResourceGroupSpec{
+			Name:           s.ResourceGroup(),
+			Location:       s.Location(),
+			ClusterName:    s.ClusterName(),
+			AdditionalTags: s.AdditionalTags(),
+		},
+		s.VNetSpec(),
+	}
+	specs = append(specs, s.SecondaryVNetSpecs()...)
+	specs = append(specs, s.NSGSpecs()...)
+	specs = append(specs, s.RouteTableSpecs()...)
+	specs = append(specs, s.PublicIPSpecs()...)
+	return append(specs, s.LBSpecs()...)
+}
+
+// SetPolicyViolations sets the PolicyCompliant condition from the resources of the cluster denied by Azure Policy.
+func (s *ClusterScope) SetPolicyViolations(violations []string) {
+	if len(violations) == 0 {
+		conditions.MarkTrue(s.AzureCluster, infrav1.PolicyCompliantCondition)
+		return
+	}
+	conditions.MarkFalse(s.AzureCluster, infrav1.PolicyCompliantCondition, infrav1.PolicyViolationReason, clusterv1.ConditionSeverityError,
+		"%s", strings.Join(violations, "; "))
+}
+
+// CostEstimation returns the cost estimation configuration of the cluster, or nil if cost estimation is disabled.
+func (s *ClusterScope) CostEstimation() *infrav1.CostEstimationSpec {
+	return s.AzureCluster.Spec.CostEstimation
+}
+
+// CostEstimate returns the last estimated cost of the cluster.
+func (s *ClusterScope) CostEstimate() *infrav1.CostEstimate {
+	return s.AzureCluster.Status.CostEstimate
+}
+
+// SetCostEstimate sets the estimated cost of the cluster.
+func (s *ClusterScope) SetCostEstimate(estimate *infrav1.CostEstimate) {
+	s.AzureCluster.Status.CostEstimate = estimate
+}
+
+// EncryptionPosture returns the last reported encryption posture of the cluster.
+func (s *ClusterScope) EncryptionPosture() *infrav1.EncryptionPosture {
+	return s.AzureCluster.Status.EncryptionPosture
+}
+
+// SetEncryptionPosture sets the encryption posture of the cluster, and the PlatformManagedKeysOnly condition when the
+// disks of some machines are only encrypted with platform-managed keys.
+func (s *ClusterScope) SetEncryptionPosture(posture *infrav1.EncryptionPosture) {
+	s.AzureCluster.Status.EncryptionPosture = posture
+	if posture == nil || len(posture.PlatformManagedKeysOnly) == 0 {
+		conditions.Delete(s.AzureCluster, infrav1.PlatformManagedKeysOnlyCondition)
+		return
+	}
+	conditions.Set(s.AzureCluster, &clusterv1.Condition{
+		Type:    infrav1.PlatformManagedKeysOnlyCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.NoAdditionalEncryptionReason,
+		Message: "The disks of " + strings.Join(posture.PlatformManagedKeysOnly, ", ") + " are only encrypted at rest with platform-managed keys",
+	})
+}
+
+// ControlPlaneFailureDomains returns the sorted IDs of the failure domains of the cluster suitable for control plane
+// machines.
+func (s *ClusterScope) ControlPlaneFailureDomains() []string {
+	var ids []string
+	for id, spec := range s.AzureCluster.Status.FailureDomains {
+		if spec.ControlPlane {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ControlPlaneMachineFailureDomains returns the failure domain of each control plane machine of the cluster which is
+// not being deleted, or an empty string for machines without one.
+func (s *ClusterScope) ControlPlaneMachineFailureDomains(ctx context.Context) ([]string, error) {
+	machines := &clusterv1.MachineList{}
+	if err := s.Client.List(ctx, machines, client.InNamespace(s.Namespace()),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: s.ClusterName()},
+		client.HasLabels{clusterv1.MachineControlPlaneLabel}); err != nil {
+		return nil, errors.Wrap(err, "failed to list control plane Machines")
+	}
+	var failureDomains []string
+	for _, machine := range machines.Items {
+		if !machine.DeletionTimestamp.IsZero() {
+			continue
+		}
+		failureDomains = append(failureDomains, ptr.Deref(machine.Spec.FailureDomain, ""))
+	}
+	return failureDomains, nil
+}
+
+// SetControlPlaneSingleZone sets the ControlPlaneSingleZone condition of the cluster, as all the control plane
+// machines are in the same failure domain.
+func (s *ClusterScope) SetControlPlaneSingleZone(failureDomain string, machines int) {
+	message := fmt.Sprintf("The %d control plane machines are all in failure domain %s while the location offers failure domains %s",
+		machines, failureDomain, strings.Join(s.ControlPlaneFailureDomains(), ", "))
+	if failureDomain == "" {
+		message = fmt.Sprintf("The %d control plane machines are all without failure domain while the location offers failure domains %s",
+			machines, strings.Join(s.ControlPlaneFailureDomains(), ", "))
+	}
+	conditions.Set(s.AzureCluster, &clusterv1.Condition{
+		Type:    infrav1.ControlPlaneSingleZoneCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.ZoneRedundancyLostReason,
+		Message: message,
+	})
+}
+
+// ClearControlPlaneSingleZone removes the ControlPlaneSingleZone condition of the cluster.
+func (s *ClusterScope) ClearControlPlaneSingleZone() {
+	conditions.Delete(s.AzureCluster, infrav1.ControlPlaneSingleZoneCondition)
+}
+
+// SetPublicIPAddress records the IP address of a public IP of the cluster for its network status.
+func (s *ClusterScope) SetPublicIPAddress(name, address string) {
+	if s.cache == nil {
+		s.cache = &ClusterCache{}
+	}
+	if s.cache.publicIPAddresses == nil {
+		s.cache.publicIPAddresses = map[string]string{}
+	}
+	s.cache.publicIPAddresses[name] = address
+}
+
+// publicIPAddress returns the IP address of a public IP of the cluster recorded during the reconciliation, if any.
+func (s *ClusterScope) publicIPAddress(name string) string {
+	if s.cache == nil {
+		return ""
+	}
+	return s.cache.publicIPAddresses[name]
+}
+
+// SetNetworkStatus sets the network status of the cluster from the network resources resolved by the reconciliation.
+func (s *ClusterScope) SetNetworkStatus() {
+	network := &infrav1.NetworkStatus{}
+
+	vnet := s.Vnet()
+	network.Vnet = &infrav1.VnetStatus{
+		Name:       vnet.Name,
+		ID:         vnet.ID,
+		CIDRBlocks: vnet.CIDRBlocks,
+	}
+	if network.Vnet.ID == "" {
+		network.Vnet.ID = azure.VNetID(s.SubscriptionID(), vnet.ResourceGroup, vnet.Name)
+	}
+
+	natGateways := map[string]bool{}
+	for _, subnet := range s.Subnets() {
+		subnetStatus := infrav1.SubnetStatus{
+			Name:       subnet.Name,
+			ID:         subnet.ID,
+			Role:       subnet.Role,
+			CIDRBlocks: subnet.CIDRBlocks,
+		}
+		if subnetStatus.ID == "" {
+			subnetStatus.ID = azure.SubnetID(s.SubscriptionID(), vnet.ResourceGroup, vnet.Name, subnet.Name)
+		}
+		if subnet.SecurityGroup.Name != "" {
+			subnetStatus.SecurityGroupID = subnet.SecurityGroup.ID
+			if subnetStatus.SecurityGroupID == "" {
+				subnetStatus.SecurityGroupID = azure.SecurityGroupID(s.SubscriptionID(), s.securityGroupResourceGroup(subnet.SecurityGroup), subnet.SecurityGroup.Name)
+			}
+		}
+		if subnet.RouteTable.Name != "" {
+			subnetStatus.RouteTableID = subnet.RouteTable.ID
+			if subnetStatus.RouteTableID == "" {
+				subnetStatus.RouteTableID = azure.RouteTableID(s.SubscriptionID(), vnet.ResourceGroup, subnet.RouteTable.Name)
+			}
+		}
+		if subnet.IsNatGatewayEnabled() {
+			subnetStatus.NatGatewayID = subnet.NatGateway.ID
+			if subnetStatus.NatGatewayID == "" {
+				subnetStatus.NatGatewayID = azure.NatGatewayID(s.SubscriptionID(), s.ResourceGroup(), subnet.NatGateway.Name)
+			}
+			if !natGateways[subnet.NatGateway.Name] {
+				natGateways[subnet.NatGateway.Name] = true
+				network.NatGateways = append(network.NatGateways, infrav1.NatGatewayStatus{
+					Name:            subnet.NatGateway.Name,
+					ID:              subnetStatus.NatGatewayID,
+					PublicIPID:      azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), subnet.NatGateway.NatGatewayIP.Name),
+					PublicIPAddress: s.publicIPAddress(subnet.NatGateway.NatGatewayIP.Name),
+				})
+			}
+		}
+		network.Subnets = append(network.Subnets, subnetStatus)
+	}
+
+	if lb := s.APIServerLB(); lb.Name != "" {
+		network.LoadBalancers = append(network.LoadBalancers, s.loadBalancerStatus(infrav1.APIServerLoadBalancerRole, *lb))
+	}
+	if lb := s.NodeOutboundLB(); lb != nil {
+		network.LoadBalancers = append(network.LoadBalancers, s.loadBalancerStatus(infrav1.NodeOutboundLoadBalancerRole, *lb))
+	}
+	if lb := s.ControlPlaneOutboundLB(); lb != nil {
+		network.LoadBalancers = append(network.LoadBalancers, s.loadBalancerStatus(infrav1.ControlPlaneOutboundLoadBalancerRole, *lb))
+	}
+	if lb := s.NodeInternalLB(); lb != nil {
+		network.LoadBalancers = append(network.LoadBalancers, s.loadBalancerStatus(infrav1.NodeInternalLoadBalancerRole, lb.LoadBalancerSpec))
+	}
+
+	if appGateway := s.ApplicationGateway(); appGateway != nil {
+		network.ApplicationGateway = &infrav1.ApplicationGatewayStatus{
+			Name:            appGateway.Name,
+			ID:              azure.ApplicationGatewayID(s.SubscriptionID(), s.ResourceGroup(), appGateway.Name),
+			SubnetID:        azure.SubnetID(s.SubscriptionID(), vnet.ResourceGroup, vnet.Name, appGateway.SubnetName),
+			PublicIPID:      azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), appGateway.PublicIP.Name),
+			PublicIPAddress: s.publicIPAddress(appGateway.PublicIP.Name),
+		}
+	}
+
+	s.AzureCluster.Status.Network = network
+}
+
+// loadBalancerStatus returns the status of a load balancer of the cluster.
+func (s *ClusterScope) loadBalancerStatus(role infrav1.LoadBalancerRole, lb infrav1.LoadBalancerSpec) infrav1.LoadBalancerStatus {
+	status := infrav1.LoadBalancerStatus{
+		Name: lb.Name,
+		ID:   lb.ID,
+		Role: role,
+	}
+	if status.ID == "" {
+		status.ID = azure.LoadBalancerID(s.SubscriptionID(), s.ResourceGroup(), lb.Name)
+	}
+	for _, frontendIP := range lb.FrontendIPs {
+		frontendIPStatus := infrav1.FrontendIPStatus{
+			Name:             frontendIP.Name,
+			PrivateIPAddress: frontendIP.PrivateIPAddress,
+		}
+		if publicIP := frontendIP.PublicIP; publicIP != nil {
+			frontendIPStatus.PublicIPID = azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), publicIP.Name)
+			if publicIP.Existing != nil && publicIP.Existing.ID != "" {
+				frontendIPStatus.PublicIPID = publicIP.Existing.ID
+			}
+			frontendIPStatus.PublicIPAddress = s.publicIPAddress(publicIP.Name)
+			frontendIPStatus.DNSName = publicIP.DNSName
+		}
+		status.FrontendIPs = append(status.FrontendIPs, frontendIPStatus)
+	}
+	return status
+}
+
+// PricedResourceSpecs returns the VMs, managed disks and load balancers of the cluster whose cost is estimated. The
+// VMs are the ones of the AzureMachines and AzureMachinePools of the cluster which are not being deleted.
+func (s *ClusterScope) PricedResourceSpecs(ctx context.Context) ([]azure.PricedResourceSpec, error) {
+	var specs []azure.PricedResourceSpec
+	for _, lb := range []*infrav1.LoadBalancerSpec{s.APIServerLB(), s.NodeOutboundLB(), s.ControlPlaneOutboundLB()} {
+		if lb != nil && lb.Name != "" {
+			specs = append(specs, azure.PricedResourceSpec{Type: costestimates.LoadBalancerType, SKU: string(lb.SKU), Count: 1})
+		}
+	}
+	if s.NodeInternalLB() != nil {
+		specs = append(specs, azure.PricedResourceSpec{Type: costestimates.LoadBalancerType, SKU: string(s.NodeInternalLB().SKU), Count: 1})
+	}
+
+	listOpts := []client.ListOption{
+		client.InNamespace(s.Namespace()),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: s.ClusterName()},
+	}
+	machines := &infrav1.AzureMachineList{}
+	if err := s.Client.List(ctx, machines, listOpts...); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureMachines")
+	}
+	for _, machine := range machines.Items {
+		if !machine.DeletionTimestamp.IsZero() {
+			continue
+		}
+		specs = append(specs, vmPricedResourceSpecs(machine.Spec.VMSize, machine.Spec.OSDisk, machine.Spec.DataDisks, machine.Spec.SpotVMOptions, 1)...)
+	}
+
+	machinePools := &infrav1exp.AzureMachinePoolList{}
+	if err := s.Client.List(ctx, machinePools, listOpts...); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureMachinePools")
+	}
+	for _, machinePool := range machinePools.Items {
+		if !machinePool.DeletionTimestamp.IsZero() {
+			continue
+		}
+		template := machinePool.Spec.Template
+		specs = append(specs, vmPricedResourceSpecs(template.VMSize, template.OSDisk, template.DataDisks, template.SpotVMOptions, machinePool.Status.Replicas)...)
+	}
+	return specs, nil
+}
+
+// vmPricedResourceSpecs returns the VMs and managed disks of count identical VMs whose cost is estimated.
+func vmPricedResourceSpecs(vmSize string, osDisk infrav1.OSDisk, dataDisks []infrav1.DataDisk, spotVMOptions *infrav1.SpotVMOptions, count int32) []azure.PricedResourceSpec {
+	specs := []azure.PricedResourceSpec{
+		{
+			Type:    costestimates.VirtualMachineType,
+			SKU:     vmSize,
+			Windows: osDisk.OSType == azure.WindowsOS,
+			Spot:    spotVMOptions != nil,
+			Count:   count,
+		},
+	}
+	// Ephemeral OS disks are stored on the VM and are not billed separately.
+	if osDisk.DiffDiskSettings == nil && osDisk.ManagedDisk != nil {
+		specs = append(specs, azure.PricedResourceSpec{
+			Type:       costestimates.ManagedDiskType,
+			SKU:        osDisk.ManagedDisk.StorageAccountType,
+			DiskSizeGB: ptr.Deref(osDisk.DiskSizeGB, 0),
+			Count:      count,
+		})
+	}
+	for _, dataDisk := range dataDisks {
+		if dataDisk.ManagedDisk == nil {
+			continue
+		}
+		specs = append(specs, azure.PricedResourceSpec{
+			Type:       costestimates.ManagedDiskType,
+			SKU:        dataDisk.ManagedDisk.StorageAccountType,
+			DiskSizeGB: dataDisk.DiskSizeGB,
+			Count:      count,
+		})
+	}
+	return specs
+}
+
+// MachineEncryptionSpecs returns the disk encryption settings of the AzureMachines and AzureMachinePools of the cluster
+// which are not being deleted.
+func (s *ClusterScope) MachineEncryptionSpecs(ctx context.Context) ([]azure.MachineEncryptionSpec, error) {
+	listOpts := []client.ListOption{
+		client.InNamespace(s.Namespace()),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: s.ClusterName()},
+	}
+	var specs []azure.MachineEncryptionSpec
+	machines := &infrav1.AzureMachineList{}
+	if err := s.Client.List(ctx, machines, listOpts...); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureMachines")
+	}
+	for _, machine := range machines.Items {
+		if !machine.DeletionTimestamp.IsZero() {
+			continue
+		}
+		spec := machineEncryptionSpec("AzureMachine/"+machine.Name, machine.Spec.OSDisk, machine.Spec.DataDisks,
+			machine.Spec.SecurityProfile, machine.Spec.VMExtensions, 1)
+		spec.AzureDiskEncryption = spec.AzureDiskEncryption || machine.Spec.AzureDiskEncryption != nil
+		specs = append(specs, spec)
+	}
+
+	machinePools := &infrav1exp.AzureMachinePoolList{}
+	if err := s.Client.List(ctx, machinePools, listOpts...); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureMachinePools")
+	}
+	for _, machinePool := range machinePools.Items {
+		if !machinePool.DeletionTimestamp.IsZero() {
+			continue
+		}
+		template := machinePool.Spec.Template
+		specs = append(specs, machineEncryptionSpec("AzureMachinePool/"+machinePool.Name, template.OSDisk, template.DataDisks,
+			template.SecurityProfile, template.VMExtensions, machinePool.Status.Replicas))
+	}
+	return specs, nil
+}
+
+// machineEncryptionSpec returns the disk encryption settings of count identical VMs.
+func machineEncryptionSpec(name string, osDisk infrav1.OSDisk, dataDisks []infrav1.DataDisk, securityProfile *infrav1.SecurityProfile, extensions []infrav1.VMExtension, count int32) azure.MachineEncryptionSpec {
+	spec := azure.MachineEncryptionSpec{
+		Name:  name,
+		Count: count,
+		// Ephemeral OS disks are stored on the VM and can't be encrypted with a disk encryption set.
+		CustomerManagedKeys: osDisk.DiffDiskSettings == nil && hasDiskEncryptionSet(osDisk.ManagedDisk),
+		EncryptionAtHost:    securityProfile != nil && ptr.Deref(securityProfile.EncryptionAtHost, false),
+	}
+	for _, dataDisk := range dataDisks {
+		if !hasDiskEncryptionSet(dataDisk.ManagedDisk) {
+			spec.CustomerManagedKeys = false
+		}
+	}
+	for _, extension := range extensions {
+		if strings.EqualFold(extension.Publisher, azureDiskEncryptionPublisher) && strings.HasPrefix(strings.ToLower(extension.Name), strings.ToLower(azureDiskEncryptionExtension)) {
+			spec.AzureDiskEncryption = true
+		}
+	}
+	return spec
+}
+
+// hasDiskEncryptionSet returns true if a managed disk is encrypted with a disk encryption set, directly or as the OS
+// disk of a confidential VM.
+func hasDiskEncryptionSet(disk *infrav1.ManagedDiskParameters) bool {
+	if disk == nil {
+		return false
+	}
+	if disk.DiskEncryptionSet != nil && disk.DiskEncryptionSet.ID != "" {
+		return true
+	}
+	return disk.SecurityProfile != nil && disk.SecurityProfile.DiskEncryptionSet != nil && disk.SecurityProfile.DiskEncryptionSet.ID != ""
+}
+
 // SetControlPlaneSecurityRules sets the default security rules of the control plane subnet.
 // Note that this is not done in a webhook as it requires a valid Cluster object to exist to get the API Server port.
 func (s *ClusterScope) SetControlPlaneSecurityRules() {
@@ -913,7 +2200,7 @@ func (s *ClusterScope) SetControlPlaneSecurityRules() {
 		subnet := s.ControlPlaneSubnet()
 		subnet.SecurityGroup.SecurityRules = infrav1.SecurityRules{
 			infrav1.SecurityRule{
-				Name:             "allow_ssh",
+				Name:             infrav1.SSHSecurityRuleName,
 				Description:      "Allow SSH",
 				Priority:         2200,
 				Protocol:         infrav1.SecurityGroupProtocolTCP,
@@ -925,7 +2212,7 @@ func (s *ClusterScope) SetControlPlaneSecurityRules() {
 				Action:           infrav1.SecurityRuleActionAllow,
 			},
 			infrav1.SecurityRule{
-				Name:             "allow_apiserver",
+				Name:             infrav1.APIServerSecurityRuleName,
 				Description:      "Allow K8s API Server",
 				Priority:         2201,
 				Protocol:         infrav1.SecurityGroupProtocolTCP,
@@ -937,10 +2224,44 @@ func (s *ClusterScope) SetControlPlaneSecurityRules() {
 				Action:           infrav1.SecurityRuleActionAllow,
 			},
 		}
+		if s.IsIPv6Enabled() {
+			subnet.SecurityGroup.SecurityRules = append(subnet.SecurityGroup.SecurityRules, icmpv6SecurityRule(2202))
+		}
 		s.AzureCluster.Spec.NetworkSpec.UpdateControlPlaneSubnet(subnet)
 	}
 }
 
+// SetNodeSecurityRules sets the default security rules of the node subnets of dual-stack clusters.
+// Inbound ICMPv6 is allowed so that IPv6 path MTU discovery works, as NSGs drop it by default.
+func (s *ClusterScope) SetNodeSecurityRules() {
+	if !s.IsIPv6Enabled() {
+		return
+	}
+	for _, subnet := range s.NodeSubnets() {
+		if subnet.SecurityGroup.SecurityRules != nil {
+			continue
+		}
+		subnet.SecurityGroup.SecurityRules = infrav1.SecurityRules{icmpv6SecurityRule(2200)}
+		s.SetSubnet(subnet)
+	}
+}
+
+// icmpv6SecurityRule returns a security rule allowing inbound ICMPv6 traffic with the given priority.
+func icmpv6SecurityRule(priority int32) infrav1.SecurityRule {
+	return infrav1.SecurityRule{
+		Name:             infrav1.ICMPv6SecurityRuleName,
+		Description:      "Allow ICMPv6",
+		Priority:         priority,
+		Protocol:         infrav1.SecurityGroupProtocolICMPv6,
+		Direction:        infrav1.SecurityRuleDirectionInbound,
+		Source:           ptr.To(infrav1.IPv6AnyAddressPrefix),
+		SourcePorts:      ptr.To("*"),
+		Destination:      ptr.To(infrav1.IPv6AnyAddressPrefix),
+		DestinationPorts: ptr.To("*"),
+		Action:           infrav1.SecurityRuleActionAllow,
+	}
+}
+
 // SetDNSName sets the API Server public IP DNS name.
 // Note: this logic exists only for purposes of ensuring backwards compatibility for old clusters created without an APIServerLB, and should be removed in the future.
 func (s *ClusterScope) SetDNSName() {
@@ -969,7 +2290,8 @@ func (s *ClusterScope) SetDNSName() {
 	}
 	// Generate valid FQDN if not set.
 	// Note: this function uses the AzureCluster subscription ID.
-	if !s.IsAPIServerPrivate() && s.APIServerPublicIP().DNSName == "" {
+	// The DNS name of an existing public IP is read from Azure when the public IP is resolved.
+	if !s.IsAPIServerPrivate() && s.APIServerPublicIP().DNSName == "" && s.APIServerPublicIP().Existing == nil {
 		s.APIServerPublicIP().DNSName = s.GenerateFQDN(s.APIServerPublicIP().Name)
 	}
 }
@@ -992,6 +2314,7 @@ func (s *ClusterScope) DeleteLongRunningOperationState(name, service, futureType
 
 // UpdateDeleteStatus updates a condition on the AzureCluster status after a DELETE operation.
 func (s *ClusterScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(s.AzureCluster, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkFalse(s.AzureCluster, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
@@ -1000,10 +2323,12 @@ func (s *ClusterScope) UpdateDeleteStatus(condition clusterv1.ConditionType, ser
 	default:
 		conditions.MarkFalse(s.AzureCluster, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
 	}
+	recordConditionChange(s.AzureCluster, before, condition, "")
 }
 
 // UpdatePutStatus updates a condition on the AzureCluster status after a PUT operation.
 func (s *ClusterScope) UpdatePutStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(s.AzureCluster, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkTrue(s.AzureCluster, condition)
@@ -1012,10 +2337,12 @@ func (s *ClusterScope) UpdatePutStatus(condition clusterv1.ConditionType, servic
 	default:
 		conditions.MarkFalse(s.AzureCluster, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
 	}
+	recordConditionChange(s.AzureCluster, before, condition, fmt.Sprintf("%s successfully created or updated", service))
 }
 
 // UpdatePatchStatus updates a condition on the AzureCluster status after a PATCH operation.
 func (s *ClusterScope) UpdatePatchStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(s.AzureCluster, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkTrue(s.AzureCluster, condition)
@@ -1024,6 +2351,7 @@ func (s *ClusterScope) UpdatePatchStatus(condition clusterv1.ConditionType, serv
 	default:
 		conditions.MarkFalse(s.AzureCluster, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
 	}
+	recordConditionChange(s.AzureCluster, before, condition, fmt.Sprintf("%s successfully updated", service))
 }
 
 // AnnotationJSON returns a map[string]interface from a JSON annotation.
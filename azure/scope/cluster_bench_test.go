@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// newBenchmarkClusterScope returns the scope of a defaulted cluster with the given number of node subnets, each with
+// its own security group, route table and NAT gateway.
+func newBenchmarkClusterScope(nodeSubnets int) *ClusterScope {
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				SubscriptionID: "123",
+				Location:       "westeurope",
+				AdditionalTags: infrav1.Tags{"team": "platform"},
+			},
+			ResourceGroup: "my-rg",
+			NetworkSpec: infrav1.NetworkSpec{
+				Subnets: infrav1.Subnets{{SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetControlPlane}}},
+			},
+		},
+	}
+	for i := 0; i < nodeSubnets; i++ {
+		azureCluster.Spec.NetworkSpec.Subnets = append(azureCluster.Spec.NetworkSpec.Subnets, infrav1.SubnetSpec{
+			SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode},
+			SecurityGroup: infrav1.SecurityGroup{
+				SecurityGroupClass: infrav1.SecurityGroupClass{
+					SecurityRules: infrav1.SecurityRules{
+						{
+							Name:             "allow_ssh",
+							Protocol:         infrav1.SecurityGroupProtocolTCP,
+							Direction:        infrav1.SecurityRuleDirectionInbound,
+							Priority:         2200,
+							SourcePorts:      ptr.To("*"),
+							DestinationPorts: ptr.To("22"),
+							Source:           ptr.To("*"),
+							Destination:      ptr.To("*"),
+						},
+					},
+				},
+			},
+		})
+	}
+	azureCluster.Default()
+
+	return &ClusterScope{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-cluster",
+				Namespace: "default",
+			},
+		},
+		AzureCluster: azureCluster,
+		cache:        &ClusterCache{},
+	}
+}
+
+// BenchmarkClusterScopeSpecs measures the specs computed by every reconciliation of an AzureCluster, which grow with
+// the number of subnets of the cluster.
+func BenchmarkClusterScopeSpecs(b *testing.B) {
+	for _, nodeSubnets := range []int{1, 10, 100} {
+		s := newBenchmarkClusterScope(nodeSubnets)
+		specs := []struct {
+			name string
+			spec func()
+		}{
+			{name: "SubnetSpecs", spec: func() { s.SubnetSpecs() }},
+			{name: "NSGSpecs", spec: func() { s.NSGSpecs() }},
+			{name: "RouteTableSpecs", spec: func() { s.RouteTableSpecs() }},
+			{name: "NatGatewaySpecs", spec: func() { s.NatGatewaySpecs() }},
+			{name: "PublicIPSpecs", spec: func() { s.PublicIPSpecs() }},
+			{name: "LBSpecs", spec: func() { s.LBSpecs() }},
+			{name: "VNetSpec", spec: func() { s.VNetSpec() }},
+			{name: "TagsSpecs", spec: func() { s.TagsSpecs() }},
+		}
+		for _, tc := range specs {
+			tc := tc
+			b.Run(fmt.Sprintf("%s/subnets=%d", tc.name, nodeSubnets+1), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					tc.spec()
+				}
+			})
+		}
+	}
+}
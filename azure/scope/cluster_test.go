@@ -22,7 +22,9 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
 	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20220701"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
@@ -33,15 +35,29 @@ import (
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/applicationgateways"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/costestimates"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/diagnosticsettings"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsrecords"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedidentities"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managementlocks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkwatchers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/policychecks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/securitygroups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworkgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -143,6 +159,49 @@ func TestAPIServerHost(t *testing.T) {
 			},
 			want: "apiserver.example.private",
 		},
+		{
+			name: "public apiserver lb (dns record)",
+			azureCluster: infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: fakeSubscriptionID,
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerDNSRecord: &infrav1.DNSRecordSpec{
+							ZoneName: "example.com",
+						},
+						APIServerLB: infrav1.LoadBalancerSpec{
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								Type: infrav1.Public,
+							},
+						},
+					},
+				},
+			},
+			want: "my-cluster.example.com",
+		},
+		{
+			name: "public apiserver lb (dns record at the zone apex)",
+			azureCluster: infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: fakeSubscriptionID,
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerDNSRecord: &infrav1.DNSRecordSpec{
+							ZoneName:   "example.com",
+							RecordName: "@",
+						},
+						APIServerLB: infrav1.LoadBalancerSpec{
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								Type: infrav1.Public,
+							},
+						},
+					},
+				},
+			},
+			want: "example.com",
+		},
 	}
 
 	for _, tc := range tests {
@@ -187,6 +246,140 @@ func TestAPIServerHost(t *testing.T) {
 	}
 }
 
+func TestDNSRecordSpecs(t *testing.T) {
+	tests := []struct {
+		name         string
+		azureCluster infrav1.AzureCluster
+		want         []azure.ResourceSpecGetter
+	}{
+		{
+			name: "no dns record",
+			azureCluster: infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerLB: infrav1.LoadBalancerSpec{
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								Type: infrav1.Public,
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "dns record with defaults",
+			azureCluster: infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerDNSRecord: &infrav1.DNSRecordSpec{
+							ZoneName: "example.com",
+						},
+						APIServerLB: infrav1.LoadBalancerSpec{
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								Type: infrav1.Public,
+							},
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&dnsrecords.DNSRecordSpec{
+					Name:              "my-cluster",
+					ZoneName:          "example.com",
+					ZoneResourceGroup: "my-cluster",
+					TTL:               infrav1.DefaultDNSRecordTTL,
+					TargetResourceID:  "/subscriptions/123/resourceGroups/my-cluster/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-apiserver",
+					ClusterName:       "my-cluster",
+				},
+			},
+		},
+		{
+			name: "dns record in another resource group",
+			azureCluster: infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerDNSRecord: &infrav1.DNSRecordSpec{
+							ZoneName:          "example.com",
+							ZoneResourceGroup: "dns-rg",
+							RecordName:        "api",
+							TTL:               ptr.To[int64](60),
+						},
+						APIServerLB: infrav1.LoadBalancerSpec{
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								Type: infrav1.Public,
+							},
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&dnsrecords.DNSRecordSpec{
+					Name:              "api",
+					ZoneName:          "example.com",
+					ZoneResourceGroup: "dns-rg",
+					TTL:               60,
+					TargetResourceID:  "/subscriptions/123/resourceGroups/my-cluster/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-apiserver",
+					ClusterName:       "my-cluster",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = clusterv1.AddToScheme(scheme)
+			_ = infrav1.AddToScheme(scheme)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster",
+					Namespace: "default",
+				},
+			}
+
+			tc.azureCluster.ObjectMeta = metav1.ObjectMeta{
+				Name: cluster.Name,
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: "cluster.x-k8s.io/v1beta1",
+						Kind:       "Cluster",
+						Name:       "my-cluster",
+					},
+				},
+			}
+			tc.azureCluster.Default()
+
+			initObjects := []runtime.Object{cluster, &tc.azureCluster}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+				AzureClients: AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Cluster:      cluster,
+				AzureCluster: &tc.azureCluster,
+				Client:       fakeClient,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(clusterScope.DNSRecordSpecs()).To(Equal(tc.want))
+		})
+	}
+}
+
 func TestGettingSecurityRules(t *testing.T) {
 	g := NewWithT(t)
 	scheme := runtime.NewScheme()
@@ -249,6 +442,113 @@ func TestGettingSecurityRules(t *testing.T) {
 	g.Expect(len(subnet.SecurityGroup.SecurityRules)).To(Equal(2))
 }
 
+func TestGettingDualStackSecurityRules(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+	}
+
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-azure-cluster",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "cluster.x-k8s.io/v1beta1",
+					Kind:       "Cluster",
+					Name:       "my-cluster",
+				},
+			},
+		},
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				SubscriptionID: "123",
+			},
+			NetworkSpec: infrav1.NetworkSpec{
+				Vnet: infrav1.VnetSpec{
+					VnetClassSpec: infrav1.VnetClassSpec{
+						CIDRBlocks: []string{"10.0.0.0/8", "2001:1234:5678:9a00::/56"},
+					},
+				},
+				Subnets: infrav1.Subnets{
+					{
+						SubnetClassSpec: infrav1.SubnetClassSpec{
+							Role:       infrav1.SubnetControlPlane,
+							Name:       "control-plane",
+							CIDRBlocks: []string{"10.0.0.0/16", "2001:1234:5678:9abc::/64"},
+						},
+					},
+					{
+						SubnetClassSpec: infrav1.SubnetClassSpec{
+							Role:       infrav1.SubnetNode,
+							Name:       "node",
+							CIDRBlocks: []string{"10.1.0.0/16", "2001:1234:5678:9abd::/64"},
+						},
+					},
+					{
+						SubnetClassSpec: infrav1.SubnetClassSpec{
+							Role:       infrav1.SubnetNode,
+							Name:       "node-custom",
+							CIDRBlocks: []string{"10.2.0.0/16", "2001:1234:5678:9abe::/64"},
+						},
+						SecurityGroup: infrav1.SecurityGroup{
+							Name: "node-custom-nsg",
+							SecurityGroupClass: infrav1.SecurityGroupClass{
+								SecurityRules: infrav1.SecurityRules{
+									{
+										Name:      "allow_http",
+										Priority:  2300,
+										Protocol:  infrav1.SecurityGroupProtocolTCP,
+										Direction: infrav1.SecurityRuleDirectionInbound,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	azureCluster.Default()
+
+	initObjects := []runtime.Object{cluster, azureCluster}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		Client:       fakeClient,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	clusterScope.SetControlPlaneSecurityRules()
+	clusterScope.SetNodeSecurityRules()
+
+	controlPlaneRules := clusterScope.ControlPlaneSubnet().SecurityGroup.SecurityRules
+	g.Expect(controlPlaneRules).To(HaveLen(3))
+	g.Expect(controlPlaneRules[2].Name).To(Equal("allow_icmpv6"))
+	g.Expect(controlPlaneRules[2].Protocol).To(Equal(infrav1.SecurityGroupProtocolICMPv6))
+	g.Expect(controlPlaneRules[2].Priority).To(Equal(int32(2202)))
+
+	nodeRules := clusterScope.Subnet("node").SecurityGroup.SecurityRules
+	g.Expect(nodeRules).To(HaveLen(1))
+	g.Expect(nodeRules[0].Name).To(Equal("allow_icmpv6"))
+	g.Expect(nodeRules[0].Source).To(Equal(ptr.To("::/0")))
+
+	customRules := clusterScope.Subnet("node-custom").SecurityGroup.SecurityRules
+	g.Expect(customRules).To(HaveLen(1))
+	g.Expect(customRules[0].Name).To(Equal("allow_http"))
+}
+
 func TestPublicIPSpecs(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -576,6 +876,67 @@ func TestPublicIPSpecs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Azure cluster with public type apiserver LB and additional frontend IPs",
+			azureCluster: &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "cluster.x-k8s.io/v1beta1",
+							Kind:       "Cluster",
+							Name:       "my-cluster",
+						},
+					},
+				},
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+						Location:       "centralIndia",
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerLB: infrav1.LoadBalancerSpec{
+							FrontendIPsCount: ptr.To[int32](2),
+							FrontendIPs: []infrav1.FrontendIP{
+								{
+									PublicIP: &infrav1.PublicIPSpec{
+										Name:    "pip-my-cluster-apiserver",
+										DNSName: "fake-dns",
+									},
+								},
+								{
+									PublicIP: &infrav1.PublicIPSpec{
+										Name: "pip-my-cluster-apiserver-2",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedPublicIPSpec: []azure.ResourceSpecGetter{
+				&publicips.PublicIPSpec{
+					Name:           "pip-my-cluster-apiserver",
+					ResourceGroup:  "my-rg",
+					DNSName:        "fake-dns",
+					IsIPv6:         false,
+					ClusterName:    "my-cluster",
+					Location:       "centralIndia",
+					FailureDomains: []*string{},
+					AdditionalTags: infrav1.Tags{},
+				},
+				&publicips.PublicIPSpec{
+					Name:           "pip-my-cluster-apiserver-2",
+					ResourceGroup:  "my-rg",
+					IsIPv6:         false,
+					ClusterName:    "my-cluster",
+					Location:       "centralIndia",
+					FailureDomains: []*string{},
+					AdditionalTags: infrav1.Tags{},
+				},
+			},
+		},
 		{
 			name: "Azure cluster with public type apiserver LB and public node outbound lb",
 			azureCluster: &infrav1.AzureCluster{
@@ -1260,8 +1621,206 @@ func TestNSGSpecs(t *testing.T) {
 				},
 			},
 		},
-	}
-
+		{
+			name: "adds the egress proxy rule to the control plane security group",
+			clusterScope: ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							Location: "centralIndia",
+						},
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet: infrav1.VnetSpec{
+								ResourceGroup: "my-rg",
+							},
+							EgressProxy: &infrav1.EgressProxySpec{Port: 8132},
+							Subnets: infrav1.Subnets{
+								{
+									SubnetClassSpec: infrav1.SubnetClassSpec{
+										Role: infrav1.SubnetControlPlane,
+									},
+									SecurityGroup: infrav1.SecurityGroup{
+										Name: "fake-security-group-cp",
+										SecurityGroupClass: infrav1.SecurityGroupClass{
+											SecurityRules: infrav1.SecurityRules{
+												{
+													Name:      "fake-rule-1",
+													Direction: infrav1.SecurityRuleDirectionInbound,
+													Priority:  2203,
+												},
+											},
+										},
+									},
+								},
+								{
+									SubnetClassSpec: infrav1.SubnetClassSpec{
+										Role: infrav1.SubnetNode,
+									},
+									SecurityGroup: infrav1.SecurityGroup{
+										Name: "fake-security-group-node",
+									},
+								},
+							},
+						},
+					},
+				},
+				cache: &ClusterCache{},
+			},
+			want: []azure.ResourceSpecGetter{
+				&securitygroups.NSGSpec{
+					Name: "fake-security-group-cp",
+					SecurityRules: infrav1.SecurityRules{
+						{
+							Name:      "fake-rule-1",
+							Direction: infrav1.SecurityRuleDirectionInbound,
+							Priority:  2203,
+						},
+						{
+							Name:             "allow_egress_proxy",
+							Description:      "Allow egress proxy",
+							Priority:         2204,
+							Protocol:         infrav1.SecurityGroupProtocolTCP,
+							Direction:        infrav1.SecurityRuleDirectionInbound,
+							Source:           ptr.To("*"),
+							SourcePorts:      ptr.To("*"),
+							Destination:      ptr.To("*"),
+							DestinationPorts: ptr.To("8132"),
+							Action:           infrav1.SecurityRuleActionAllow,
+						},
+					},
+					ResourceGroup:            "my-rg",
+					Location:                 "centralIndia",
+					ClusterName:              "my-cluster",
+					AdditionalTags:           make(infrav1.Tags),
+					LastAppliedSecurityRules: map[string]interface{}{},
+				},
+				&securitygroups.NSGSpec{
+					Name:                     "fake-security-group-node",
+					ResourceGroup:            "my-rg",
+					Location:                 "centralIndia",
+					ClusterName:              "my-cluster",
+					AdditionalTags:           make(infrav1.Tags),
+					LastAppliedSecurityRules: map[string]interface{}{},
+				},
+			},
+		},
+		{
+			name: "adds the extra rules to the control plane security group",
+			clusterScope: ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							Location: "centralIndia",
+						},
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet: infrav1.VnetSpec{
+								ResourceGroup: "my-rg",
+							},
+							ControlPlaneExtraRules: infrav1.SecurityRules{
+								{
+									Name:             "allow_etcd",
+									Description:      "Allow etcd",
+									Protocol:         infrav1.SecurityGroupProtocolTCP,
+									Direction:        infrav1.SecurityRuleDirectionInbound,
+									DestinationPorts: ptr.To("2379-2380"),
+									Action:           infrav1.SecurityRuleActionAllow,
+								},
+								{
+									Name:        "deny_internet",
+									Description: "Deny internet",
+									Priority:    2300,
+									Protocol:    infrav1.SecurityGroupProtocolAll,
+									Direction:   infrav1.SecurityRuleDirectionOutbound,
+									Source:      ptr.To("*"),
+									Destination: ptr.To("Internet"),
+									Action:      infrav1.SecurityRuleActionDeny,
+								},
+							},
+							Subnets: infrav1.Subnets{
+								{
+									SubnetClassSpec: infrav1.SubnetClassSpec{
+										Role:       infrav1.SubnetControlPlane,
+										CIDRBlocks: []string{"10.0.0.0/16", "2001:1234:5678:9abd::/64"},
+									},
+									SecurityGroup: infrav1.SecurityGroup{
+										Name: "fake-security-group-cp",
+										SecurityGroupClass: infrav1.SecurityGroupClass{
+											SecurityRules: infrav1.SecurityRules{
+												{
+													Name:      "fake-rule-1",
+													Direction: infrav1.SecurityRuleDirectionInbound,
+													Priority:  2300,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				cache: &ClusterCache{},
+			},
+			want: []azure.ResourceSpecGetter{
+				&securitygroups.NSGSpec{
+					Name: "fake-security-group-cp",
+					SecurityRules: infrav1.SecurityRules{
+						{
+							Name:      "fake-rule-1",
+							Direction: infrav1.SecurityRuleDirectionInbound,
+							Priority:  2300,
+						},
+						{
+							Name:             "allow_etcd-0",
+							Description:      "Allow etcd",
+							Priority:         2301,
+							Protocol:         infrav1.SecurityGroupProtocolTCP,
+							Direction:        infrav1.SecurityRuleDirectionInbound,
+							Source:           ptr.To("10.0.0.0/16"),
+							DestinationPorts: ptr.To("2379-2380"),
+							Action:           infrav1.SecurityRuleActionAllow,
+						},
+						{
+							Name:             "allow_etcd-1",
+							Description:      "Allow etcd",
+							Priority:         2302,
+							Protocol:         infrav1.SecurityGroupProtocolTCP,
+							Direction:        infrav1.SecurityRuleDirectionInbound,
+							Source:           ptr.To("2001:1234:5678:9abd::/64"),
+							DestinationPorts: ptr.To("2379-2380"),
+							Action:           infrav1.SecurityRuleActionAllow,
+						},
+						{
+							Name:        "deny_internet",
+							Description: "Deny internet",
+							Priority:    2300,
+							Protocol:    infrav1.SecurityGroupProtocolAll,
+							Direction:   infrav1.SecurityRuleDirectionOutbound,
+							Source:      ptr.To("*"),
+							Destination: ptr.To("Internet"),
+							Action:      infrav1.SecurityRuleActionDeny,
+						},
+					},
+					ResourceGroup:            "my-rg",
+					Location:                 "centralIndia",
+					ClusterName:              "my-cluster",
+					AdditionalTags:           make(infrav1.Tags),
+					LastAppliedSecurityRules: map[string]interface{}{},
+				},
+			},
+		},
+	}
+
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
@@ -1273,6 +1832,110 @@ func TestNSGSpecs(t *testing.T) {
 	}
 }
 
+func TestValidateControlPlaneExtraRules(t *testing.T) {
+	usedPriorities := infrav1.SecurityRules{}
+	for priority := int32(2300); priority <= 4096; priority++ {
+		usedPriorities = append(usedPriorities, infrav1.SecurityRule{
+			Name:      fmt.Sprintf("rule-%d", priority),
+			Direction: infrav1.SecurityRuleDirectionInbound,
+			Priority:  priority,
+		})
+	}
+	defaultRules := infrav1.SecurityRules{
+		{
+			Name:      "allow_ssh",
+			Direction: infrav1.SecurityRuleDirectionInbound,
+			Priority:  2200,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		subnetRules   infrav1.SecurityRules
+		extraRules    infrav1.SecurityRules
+		expectedError string
+	}{
+		{
+			name:        "valid extra rules",
+			subnetRules: defaultRules,
+			extraRules: infrav1.SecurityRules{
+				{
+					Name:      "allow_etcd",
+					Direction: infrav1.SecurityRuleDirectionInbound,
+				},
+			},
+		},
+		{
+			name:        "extra rule with the name of a subnet rule",
+			subnetRules: defaultRules,
+			extraRules: infrav1.SecurityRules{
+				{
+					Name:      "allow_ssh",
+					Direction: infrav1.SecurityRuleDirectionInbound,
+				},
+			},
+			expectedError: "extra control plane rule allow_ssh has the name of a security rule of the control plane subnet",
+		},
+		{
+			name:        "no priority left",
+			subnetRules: usedPriorities,
+			extraRules: infrav1.SecurityRules{
+				{
+					Name:      "allow_etcd",
+					Direction: infrav1.SecurityRuleDirectionInbound,
+				},
+			},
+			expectedError: "no Inbound priority left for extra control plane rule allow_etcd",
+		},
+		{
+			name:        "priority left in the other direction",
+			subnetRules: usedPriorities,
+			extraRules: infrav1.SecurityRules{
+				{
+					Name:      "deny_internet",
+					Direction: infrav1.SecurityRuleDirectionOutbound,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			clusterScope := ClusterScope{
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							ControlPlaneExtraRules: tc.extraRules,
+							Subnets: infrav1.Subnets{
+								{
+									SubnetClassSpec: infrav1.SubnetClassSpec{
+										Role:       infrav1.SubnetControlPlane,
+										CIDRBlocks: []string{"10.0.0.0/16"},
+									},
+									SecurityGroup: infrav1.SecurityGroup{
+										SecurityGroupClass: infrav1.SecurityGroupClass{
+											SecurityRules: tc.subnetRules,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			err := clusterScope.ValidateControlPlaneExtraRules()
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestSubnetSpecs(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1727,22 +2390,1125 @@ func TestAzureBastionSpec(t *testing.T) {
 				Location:      "centralIndia",
 				ClusterName:   "my-cluster",
 				SubnetID: fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/"+
-					"virtualNetworks/%s/subnets/%s", "123", "my-rg", "fake-vnet-1", "fake-bastion-subnet-1"),
+					"virtualNetworks/%s/subnets/%s", "123", "my-rg-vnet", "fake-vnet-1", "fake-bastion-subnet-1"),
 				PublicIPID: fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/"+
 					"publicIPAddresses/%s", "123", "my-rg", "fake-public-ip-1"),
 			},
 		},
 	}
 
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			if got := tt.clusterScope.AzureBastionSpec(); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("AzureBastionSpec() = \n%s, want \n%s", specToString(got), specToString(tt.want))
-			}
-		})
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.clusterScope.AzureBastionSpec(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AzureBastionSpec() = \n%s, want \n%s", specToString(got), specToString(tt.want))
+			}
+		})
+	}
+}
+
+func TestVirtualNetworkGateway(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		cache:   &ClusterCache{},
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					Location: "westus",
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{
+						Name:          "my-vnet",
+						ResourceGroup: "my-vnet-rg",
+					},
+					Gateway: &infrav1.GatewaySpec{
+						CIDRBlocks: []string{"10.255.255.192/27"},
+					},
+				},
+			},
+		},
+	}
+
+	// Without a virtual network gateway, the GatewaySubnet is only reserved.
+	g.Expect(clusterScope.VirtualNetworkGatewaySpec()).To(BeNil())
+	g.Expect(clusterScope.PublicIPSpecs()).To(BeEmpty())
+	g.Expect(clusterScope.SubnetSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&subnets.SubnetSpec{
+			Name:              "GatewaySubnet",
+			ResourceGroup:     "my-rg",
+			SubscriptionID:    "123",
+			CIDRs:             []string{"10.255.255.192/27"},
+			VNetName:          "my-vnet",
+			VNetResourceGroup: "my-vnet-rg",
+			IsVNetManaged:     true,
+		},
+	}))
+
+	clusterScope.AzureCluster.Spec.NetworkSpec.Gateway.VirtualNetworkGateway = &infrav1.VirtualNetworkGatewaySpec{
+		Name:     "my-gateway",
+		Type:     infrav1.VirtualNetworkGatewayTypeVpn,
+		SKU:      "VpnGw1AZ",
+		PublicIP: infrav1.PublicIPSpec{Name: "my-gateway-pip"},
+	}
+	g.Expect(clusterScope.VirtualNetworkGatewaySpec()).To(Equal(&virtualnetworkgateways.VirtualNetworkGatewaySpec{
+		Name:           "my-gateway",
+		ResourceGroup:  "my-vnet-rg",
+		Location:       "westus",
+		ClusterName:    "my-cluster",
+		SubnetID:       "/subscriptions/123/resourceGroups/my-vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/GatewaySubnet",
+		PublicIPID:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-gateway-pip",
+		Type:           infrav1.VirtualNetworkGatewayTypeVpn,
+		SKU:            "VpnGw1AZ",
+		AdditionalTags: infrav1.Tags{},
+	}))
+	g.Expect(clusterScope.PublicIPSpecs()).To(ConsistOf(&publicips.PublicIPSpec{
+		Name:           "my-gateway-pip",
+		ResourceGroup:  "my-rg",
+		ClusterName:    "my-cluster",
+		Location:       "westus",
+		FailureDomains: []*string{},
+		AdditionalTags: infrav1.Tags{},
+	}))
+}
+
+func TestApplicationGateway(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		cache:   &ClusterCache{},
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					Location: "westus",
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{
+						Name:          "my-vnet",
+						ResourceGroup: "my-vnet-rg",
+					},
+				},
+			},
+		},
+	}
+
+	g.Expect(clusterScope.ApplicationGatewaySpec()).To(BeNil())
+	g.Expect(clusterScope.SubnetSpecs()).To(BeEmpty())
+
+	clusterScope.AzureCluster.Spec.ApplicationGateway = &infrav1.ApplicationGatewaySpec{
+		Name:        "my-appgw",
+		SubnetName:  "my-appgw-subnet",
+		CIDRBlocks:  []string{"10.255.255.128/26"},
+		PublicIP:    infrav1.PublicIPSpec{Name: "my-appgw-pip"},
+		MinCapacity: 1,
+		WAF:         &infrav1.WebApplicationFirewallSpec{Mode: infrav1.WebApplicationFirewallModePrevention, RuleSetVersion: "3.2"},
+	}
+	g.Expect(clusterScope.ApplicationGatewaySpec()).To(Equal(&applicationgateways.ApplicationGatewaySpec{
+		Name:           "my-appgw",
+		ResourceGroup:  "my-rg",
+		SubscriptionID: "123",
+		Location:       "westus",
+		ClusterName:    "my-cluster",
+		SubnetID:       "/subscriptions/123/resourceGroups/my-vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-appgw-subnet",
+		PublicIPID:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-appgw-pip",
+		FailureDomains: []*string{},
+		MinCapacity:    1,
+		WAF:            &infrav1.WebApplicationFirewallSpec{Mode: infrav1.WebApplicationFirewallModePrevention, RuleSetVersion: "3.2"},
+		AdditionalTags: infrav1.Tags{},
+	}))
+	g.Expect(clusterScope.SubnetSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&subnets.SubnetSpec{
+			Name:              "my-appgw-subnet",
+			ResourceGroup:     "my-rg",
+			SubscriptionID:    "123",
+			CIDRs:             []string{"10.255.255.128/26"},
+			VNetName:          "my-vnet",
+			VNetResourceGroup: "my-vnet-rg",
+			IsVNetManaged:     true,
+		},
+	}))
+	g.Expect(clusterScope.PublicIPSpecs()).To(ConsistOf(&publicips.PublicIPSpec{
+		Name:           "my-appgw-pip",
+		ResourceGroup:  "my-rg",
+		ClusterName:    "my-cluster",
+		Location:       "westus",
+		FailureDomains: []*string{},
+		AdditionalTags: infrav1.Tags{},
+	}))
+
+	clusterScope.SetPublicIPAddress("my-appgw-pip", "20.1.2.3")
+	clusterScope.SetNetworkStatus()
+	g.Expect(clusterScope.AzureCluster.Status.Network.ApplicationGateway).To(Equal(&infrav1.ApplicationGatewayStatus{
+		Name:            "my-appgw",
+		ID:              "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationGateways/my-appgw",
+		SubnetID:        "/subscriptions/123/resourceGroups/my-vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-appgw-subnet",
+		PublicIPID:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-appgw-pip",
+		PublicIPAddress: "20.1.2.3",
+	}))
+}
+
+func TestDiagnosticSettingSpecs(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					Location: "westus",
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{
+						Name:          "my-vnet",
+						ResourceGroup: "my-vnet-rg",
+					},
+					APIServerLB: infrav1.LoadBalancerSpec{
+						Name: "my-lb",
+						FrontendIPs: []infrav1.FrontendIP{
+							{
+								Name:     "my-lb-frontEnd",
+								PublicIP: &infrav1.PublicIPSpec{Name: "pip-my-cluster-apiserver"},
+							},
+						},
+						LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+							Type: infrav1.Public,
+						},
+					},
+					Subnets: infrav1.Subnets{
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetControlPlane, Name: "cp-subnet"},
+							SecurityGroup:   infrav1.SecurityGroup{Name: "cp-nsg"},
+						},
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode, Name: "node-subnet"},
+							SecurityGroup:   infrav1.SecurityGroup{Name: "node-nsg"},
+						},
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode, Name: "other-node-subnet"},
+							SecurityGroup:   infrav1.SecurityGroup{Name: "node-nsg"},
+						},
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode, Name: "shared-subnet"},
+							SecurityGroup: infrav1.SecurityGroup{
+								Name: "shared-nsg",
+								Existing: &infrav1.ExistingSecurityGroup{
+									ID: "/subscriptions/123/resourceGroups/security-rg/providers/Microsoft.Network/networkSecurityGroups/shared-nsg",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Diagnostic settings are disabled by default.
+	g.Expect(clusterScope.DiagnosticSettingSpecs()).To(BeNil())
+
+	workspaceID := "/subscriptions/123/resourceGroups/logs-rg/providers/Microsoft.OperationalInsights/workspaces/logs"
+	clusterScope.AzureCluster.Spec.NetworkSpec.DiagnosticSettings = &infrav1.DiagnosticSettingsSpec{
+		WorkspaceResourceID: workspaceID,
+	}
+	g.Expect(clusterScope.DiagnosticSettingSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&diagnosticsettings.DiagnosticSettingSpec{
+			Name:                "my-cluster-diagnostics",
+			ResourceID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb",
+			WorkspaceResourceID: workspaceID,
+		},
+		&diagnosticsettings.DiagnosticSettingSpec{
+			Name:                "my-cluster-diagnostics",
+			ResourceID:          "/subscriptions/123/resourceGroups/my-vnet-rg/providers/Microsoft.Network/networkSecurityGroups/cp-nsg",
+			WorkspaceResourceID: workspaceID,
+		},
+		&diagnosticsettings.DiagnosticSettingSpec{
+			Name:                "my-cluster-diagnostics",
+			ResourceID:          "/subscriptions/123/resourceGroups/my-vnet-rg/providers/Microsoft.Network/networkSecurityGroups/node-nsg",
+			WorkspaceResourceID: workspaceID,
+		},
+		&diagnosticsettings.DiagnosticSettingSpec{
+			Name:                "my-cluster-diagnostics",
+			ResourceID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-apiserver",
+			WorkspaceResourceID: workspaceID,
+		},
+	}))
+
+	// Only the resources with one of the categories get a diagnostic setting.
+	clusterScope.AzureCluster.Spec.NetworkSpec.DiagnosticSettings.Categories = []infrav1.DiagnosticLogCategory{
+		infrav1.NetworkSecurityGroupEventCategory,
+		infrav1.DDoSProtectionNotificationsCategory,
+	}
+	g.Expect(clusterScope.DiagnosticSettingSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&diagnosticsettings.DiagnosticSettingSpec{
+			Name:                "my-cluster-diagnostics",
+			ResourceID:          "/subscriptions/123/resourceGroups/my-vnet-rg/providers/Microsoft.Network/networkSecurityGroups/cp-nsg",
+			WorkspaceResourceID: workspaceID,
+			Categories:          []string{"NetworkSecurityGroupEvent"},
+		},
+		&diagnosticsettings.DiagnosticSettingSpec{
+			Name:                "my-cluster-diagnostics",
+			ResourceID:          "/subscriptions/123/resourceGroups/my-vnet-rg/providers/Microsoft.Network/networkSecurityGroups/node-nsg",
+			WorkspaceResourceID: workspaceID,
+			Categories:          []string{"NetworkSecurityGroupEvent"},
+		},
+		&diagnosticsettings.DiagnosticSettingSpec{
+			Name:                "my-cluster-diagnostics",
+			ResourceID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-apiserver",
+			WorkspaceResourceID: workspaceID,
+			Categories:          []string{"DDoSProtectionNotifications"},
+		},
+	}))
+}
+
+func TestFlowLogSpecs(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					Location: "westus",
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{
+						Name:          "my-vnet",
+						ResourceGroup: "my-vnet-rg",
+					},
+					Subnets: infrav1.Subnets{
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetControlPlane, Name: "cp-subnet"},
+							SecurityGroup:   infrav1.SecurityGroup{Name: "cp-nsg"},
+						},
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode, Name: "node-subnet"},
+							SecurityGroup:   infrav1.SecurityGroup{Name: "node-nsg"},
+						},
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode, Name: "other-node-subnet"},
+							SecurityGroup:   infrav1.SecurityGroup{Name: "node-nsg"},
+						},
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode, Name: "shared-subnet"},
+							SecurityGroup: infrav1.SecurityGroup{
+								Name: "shared-nsg",
+								Existing: &infrav1.ExistingSecurityGroup{
+									ID: "/subscriptions/123/resourceGroups/security-rg/providers/Microsoft.Network/networkSecurityGroups/shared-nsg",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Flow logs are disabled by default.
+	g.Expect(clusterScope.FlowLogSpecs()).To(BeNil())
+
+	clusterScope.AzureCluster.Spec.NetworkSpec.FlowLogs = &infrav1.FlowLogsSpec{
+		StorageAccountID: "/subscriptions/123/resourceGroups/logs-rg/providers/Microsoft.Storage/storageAccounts/flowlogs",
+		RetentionDays:    30,
+		NetworkWatcher: infrav1.NetworkWatcherReference{
+			Name:          "NetworkWatcher_westus",
+			ResourceGroup: "NetworkWatcherRG",
+		},
+	}
+	g.Expect(clusterScope.FlowLogSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&networkwatchers.FlowLogSpec{
+			Name:               "cp-nsg-my-vnet-rg-flowlog",
+			NetworkWatcherName: "NetworkWatcher_westus",
+			NetworkWatcherRG:   "NetworkWatcherRG",
+			Location:           "westus",
+			ClusterName:        "my-cluster",
+			SecurityGroupID:    "/subscriptions/123/resourceGroups/my-vnet-rg/providers/Microsoft.Network/networkSecurityGroups/cp-nsg",
+			StorageAccountID:   "/subscriptions/123/resourceGroups/logs-rg/providers/Microsoft.Storage/storageAccounts/flowlogs",
+			RetentionDays:      30,
+			AdditionalTags:     infrav1.Tags{},
+		},
+		&networkwatchers.FlowLogSpec{
+			Name:               "node-nsg-my-vnet-rg-flowlog",
+			NetworkWatcherName: "NetworkWatcher_westus",
+			NetworkWatcherRG:   "NetworkWatcherRG",
+			Location:           "westus",
+			ClusterName:        "my-cluster",
+			SecurityGroupID:    "/subscriptions/123/resourceGroups/my-vnet-rg/providers/Microsoft.Network/networkSecurityGroups/node-nsg",
+			StorageAccountID:   "/subscriptions/123/resourceGroups/logs-rg/providers/Microsoft.Storage/storageAccounts/flowlogs",
+			RetentionDays:      30,
+			AdditionalTags:     infrav1.Tags{},
+		},
+	}))
+}
+
+func TestDDoSProtectionPlanSpec(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					Location: "westus",
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{
+						Name:          "my-vnet",
+						ResourceGroup: "my-vnet-rg",
+					},
+				},
+			},
+		},
+	}
+
+	// Without a DDoS protection plan.
+	g.Expect(clusterScope.DDoSProtectionPlanSpec()).To(BeNil())
+	g.Expect(clusterScope.VNetSpec().(*virtualnetworks.VNetSpec).DDoSProtectionPlanID).To(BeEmpty())
+
+	// With a DDoS protection plan created for the vnet.
+	clusterScope.AzureCluster.Spec.NetworkSpec.Vnet.DDoSProtectionPlan = &infrav1.DDoSProtectionPlanSpec{Name: "my-plan"}
+	g.Expect(clusterScope.DDoSProtectionPlanSpec()).To(Equal(&virtualnetworks.DDoSProtectionPlanSpec{
+		Name:           "my-plan",
+		ResourceGroup:  "my-vnet-rg",
+		Location:       "westus",
+		ClusterName:    "my-cluster",
+		AdditionalTags: infrav1.Tags{},
+	}))
+	g.Expect(clusterScope.VNetSpec().(*virtualnetworks.VNetSpec).DDoSProtectionPlanID).To(Equal("/subscriptions/123/resourceGroups/my-vnet-rg/providers/Microsoft.Network/ddosProtectionPlans/my-plan"))
+
+	// With an existing DDoS protection plan.
+	existingPlanID := "/subscriptions/456/resourceGroups/security-rg/providers/Microsoft.Network/ddosProtectionPlans/shared-plan"
+	clusterScope.AzureCluster.Spec.NetworkSpec.Vnet.DDoSProtectionPlan = &infrav1.DDoSProtectionPlanSpec{ID: existingPlanID}
+	g.Expect(clusterScope.DDoSProtectionPlanSpec()).To(BeNil())
+	g.Expect(clusterScope.VNetSpec().(*virtualnetworks.VNetSpec).DDoSProtectionPlanID).To(Equal(existingPlanID))
+}
+
+func TestSecondaryRegionSpecs(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					Location: "westus",
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{
+						Name:          "my-vnet",
+						ResourceGroup: "my-vnet-rg",
+					},
+				},
+				SecondaryRegions: []infrav1.SecondaryRegionSpec{
+					{
+						Location:   "eastus",
+						VnetName:   "my-vnet-eastus",
+						CIDRBlocks: []string{"172.16.0.0/16"},
+						Subnets: []infrav1.SecondaryRegionSubnetSpec{
+							{
+								Name:              "my-subnet-eastus",
+								CIDRBlocks:        []string{"172.16.0.0/24"},
+								SecurityGroupName: "my-subnet-eastus-nsg",
+								SecurityRules:     infrav1.SecurityRules{{Name: "allow-ssh"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	g.Expect(clusterScope.SecondaryVNetSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&virtualnetworks.VNetSpec{
+			ResourceGroup:  "my-vnet-rg",
+			Name:           "my-vnet-eastus",
+			CIDRs:          []string{"172.16.0.0/16"},
+			Location:       "eastus",
+			ClusterName:    "my-cluster",
+			AdditionalTags: infrav1.Tags{},
+		},
+	}))
+	g.Expect(clusterScope.SubnetSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&subnets.SubnetSpec{
+			Name:              "my-subnet-eastus",
+			ResourceGroup:     "my-rg",
+			SubscriptionID:    "123",
+			CIDRs:             []string{"172.16.0.0/24"},
+			VNetName:          "my-vnet-eastus",
+			VNetResourceGroup: "my-vnet-rg",
+			IsVNetManaged:     true,
+			SecurityGroupName: "my-subnet-eastus-nsg",
+		},
+	}))
+	g.Expect(clusterScope.NSGSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&securitygroups.NSGSpec{
+			Name:                     "my-subnet-eastus-nsg",
+			SecurityRules:            infrav1.SecurityRules{{Name: "allow-ssh"}},
+			ResourceGroup:            "my-vnet-rg",
+			Location:                 "eastus",
+			ClusterName:              "my-cluster",
+			AdditionalTags:           infrav1.Tags{},
+			LastAppliedSecurityRules: map[string]interface{}{},
+		},
+	}))
+	g.Expect(clusterScope.VnetPeeringSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&vnetpeerings.VnetPeeringSpec{
+			PeeringName:               "my-vnet-To-my-vnet-eastus",
+			SourceVnetName:            "my-vnet",
+			SourceResourceGroup:       "my-vnet-rg",
+			RemoteVnetName:            "my-vnet-eastus",
+			RemoteResourceGroup:       "my-vnet-rg",
+			SubscriptionID:            "123",
+			AllowForwardedTraffic:     ptr.To(true),
+			AllowVirtualNetworkAccess: ptr.To(true),
+		},
+		&vnetpeerings.VnetPeeringSpec{
+			PeeringName:               "my-vnet-eastus-To-my-vnet",
+			SourceVnetName:            "my-vnet-eastus",
+			SourceResourceGroup:       "my-vnet-rg",
+			RemoteVnetName:            "my-vnet",
+			RemoteResourceGroup:       "my-vnet-rg",
+			SubscriptionID:            "123",
+			AllowForwardedTraffic:     ptr.To(true),
+			AllowVirtualNetworkAccess: ptr.To(true),
+		},
+	}))
+}
+
+func TestResourceGroupLockSpec(t *testing.T) {
+	lockSpec := &managementlocks.LockSpec{
+		Name:          "my-cluster-deletion-protection",
+		ResourceGroup: "my-rg",
+		Notes:         "Protects the resources of cluster default/my-cluster from deletion. Managed by cluster-api-provider-azure.",
+	}
+	tests := []struct {
+		name              string
+		resourceGroupLock bool
+		clusterDeleting   bool
+		conditionReason   string
+		conditionTrue     bool
+		wantSpec          azure.ResourceSpecGetter
+		wantLock          bool
+	}{
+		{
+			name:     "resource group lock is disabled",
+			wantSpec: nil,
+			wantLock: false,
+		},
+		{
+			name:              "resource group lock is enabled",
+			resourceGroupLock: true,
+			wantSpec:          lockSpec,
+			wantLock:          true,
+		},
+		{
+			name:              "resource group is locked but the cluster is being deleted",
+			resourceGroupLock: true,
+			clusterDeleting:   true,
+			conditionTrue:     true,
+			wantSpec:          lockSpec,
+			wantLock:          false,
+		},
+		{
+			name:          "resource group is locked but the lock was disabled",
+			conditionTrue: true,
+			wantSpec:      lockSpec,
+			wantLock:      false,
+		},
+		{
+			name:            "resource group lock was already removed",
+			clusterDeleting: true,
+			conditionReason: infrav1.DeletedReason,
+			wantSpec:        nil,
+			wantLock:        false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster",
+					Namespace: "default",
+				},
+			}
+			if tt.clusterDeleting {
+				cluster.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			}
+			azureCluster := &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						ResourceGroupLock: tt.resourceGroupLock,
+					},
+				},
+			}
+			if tt.conditionTrue {
+				conditions.MarkTrue(azureCluster, infrav1.ResourceGroupLockedCondition)
+			}
+			if tt.conditionReason != "" {
+				conditions.MarkFalse(azureCluster, infrav1.ResourceGroupLockedCondition, tt.conditionReason, clusterv1.ConditionSeverityInfo, "")
+			}
+			clusterScope := &ClusterScope{
+				Cluster:      cluster,
+				AzureCluster: azureCluster,
+			}
+			g.Expect(clusterScope.ShouldLockResourceGroup()).To(Equal(tt.wantLock))
+			if tt.wantSpec == nil {
+				g.Expect(clusterScope.ResourceGroupLockSpec()).To(BeNil())
+			} else {
+				g.Expect(clusterScope.ResourceGroupLockSpec()).To(Equal(tt.wantSpec))
+			}
+		})
+	}
+}
+
+func TestManagedIdentitySpecs(t *testing.T) {
+	identityID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-cluster-identity"
+	contributor := "/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/" + infrav1.ContributorRoleID
+	reader := "/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/acdd72a7-3385-48ef-bd42-f606fba81ae7"
+	tests := []struct {
+		name            string
+		managedIdentity *infrav1.ClusterManagedIdentity
+		wantProviderID  string
+		wantSpec        azure.ResourceSpecGetter
+		wantRoles       []azure.ResourceSpecGetter
+	}{
+		{
+			name: "cluster without managed identity",
+		},
+		{
+			name:            "managed identity with default name and roles",
+			managedIdentity: &infrav1.ClusterManagedIdentity{},
+			wantProviderID:  identityID,
+			wantSpec: &managedidentities.IdentitySpec{
+				Name:           "my-cluster-identity",
+				ResourceGroup:  "my-rg",
+				ClusterName:    "my-cluster",
+				Location:       "westeurope",
+				AdditionalTags: infrav1.Tags{},
+			},
+			wantRoles: []azure.ResourceSpecGetter{
+				&roleassignments.RoleAssignmentSpec{
+					Name:             azure.GenerateRoleAssignmentName(identityID, contributor, "/subscriptions/123/resourceGroups/my-rg"),
+					ResourceGroup:    "my-rg",
+					PrincipalID:      ptr.To("principal-id"),
+					PrincipalType:    ptr.To(armauthorization.PrincipalTypeServicePrincipal),
+					RoleDefinitionID: contributor,
+					Scope:            "/subscriptions/123/resourceGroups/my-rg",
+				},
+			},
+		},
+		{
+			name: "managed identity with name and roles",
+			managedIdentity: &infrav1.ClusterManagedIdentity{
+				Name: "nodes",
+				Roles: []infrav1.ManagedIdentityRole{
+					{DefinitionID: reader, Scope: "/subscriptions/123"},
+				},
+			},
+			wantProviderID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/nodes",
+			wantSpec: &managedidentities.IdentitySpec{
+				Name:           "nodes",
+				ResourceGroup:  "my-rg",
+				ClusterName:    "my-cluster",
+				Location:       "westeurope",
+				AdditionalTags: infrav1.Tags{},
+			},
+			wantRoles: []azure.ResourceSpecGetter{
+				&roleassignments.RoleAssignmentSpec{
+					Name:             azure.GenerateRoleAssignmentName("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/nodes", reader, "/subscriptions/123"),
+					ResourceGroup:    "my-rg",
+					PrincipalID:      ptr.To("principal-id"),
+					PrincipalType:    ptr.To(armauthorization.PrincipalTypeServicePrincipal),
+					RoleDefinitionID: reader,
+					Scope:            "/subscriptions/123",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			clusterScope := &ClusterScope{
+				AzureClients: AzureClients{
+					EnvironmentSettings: auth.EnvironmentSettings{
+						Values: map[string]string{
+							auth.SubscriptionID: "123",
+						},
+					},
+				},
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-cluster",
+						Namespace: "default",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							Location:        "westeurope",
+							ManagedIdentity: tt.managedIdentity,
+						},
+					},
+				},
+			}
+			g.Expect(clusterScope.ManagedIdentityProviderID()).To(Equal(tt.wantProviderID))
+			if tt.wantSpec == nil {
+				g.Expect(clusterScope.ManagedIdentitySpec()).To(BeNil())
+			} else {
+				g.Expect(clusterScope.ManagedIdentitySpec()).To(Equal(tt.wantSpec))
+			}
+			g.Expect(clusterScope.ManagedIdentityRoleAssignmentSpecs(ptr.To("principal-id"))).To(Equal(tt.wantRoles))
+		})
+	}
+}
+
+func TestPolicyCheckSpecs(t *testing.T) {
+	tests := []struct {
+		name                 string
+		policyPreflightCheck bool
+		compliant            bool
+		wantSpecs            bool
+	}{
+		{
+			name: "policy pre-flight check is disabled",
+		},
+		{
+			name:                 "policy pre-flight check is enabled",
+			policyPreflightCheck: true,
+			wantSpecs:            true,
+		},
+		{
+			name:                 "policy pre-flight check already passed",
+			policyPreflightCheck: true,
+			compliant:            true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			azureCluster := &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						Location:             "westus",
+						PolicyPreflightCheck: tt.policyPreflightCheck,
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						Vnet: infrav1.VnetSpec{
+							Name:          "my-vnet",
+							ResourceGroup: "my-rg",
+						},
+						APIServerLB: infrav1.LoadBalancerSpec{
+							Name: "my-lb",
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								Type: infrav1.Public,
+							},
+						},
+					},
+				},
+			}
+			if tt.compliant {
+				conditions.MarkTrue(azureCluster, infrav1.PolicyCompliantCondition)
+			}
+			clusterScope := &ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-cluster",
+						Namespace: "default",
+					},
+				},
+				AzureCluster: azureCluster,
+			}
+			specs := clusterScope.PolicyCheckSpecs()
+			if !tt.wantSpecs {
+				g.Expect(specs).To(BeEmpty())
+				return
+			}
+			g.Expect(len(specs)).To(BeNumerically(">=", 3))
+			g.Expect(specs[0]).To(Equal(&policychecks.ResourceGroupSpec{
+				Name:           "my-rg",
+				Location:       "westus",
+				ClusterName:    "my-cluster",
+				AdditionalTags: infrav1.Tags{},
+			}))
+			g.Expect(specs[1].ResourceName()).To(Equal("my-vnet"))
+			g.Expect(specs[len(specs)-1].ResourceName()).To(Equal("my-lb"))
+		})
+	}
+}
+
+func TestSetPolicyViolations(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := &ClusterScope{
+		AzureCluster: &infrav1.AzureCluster{},
+	}
+
+	clusterScope.SetPolicyViolations([]string{"violation one", "violation two"})
+	g.Expect(conditions.IsFalse(clusterScope.AzureCluster, infrav1.PolicyCompliantCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(clusterScope.AzureCluster, infrav1.PolicyCompliantCondition)).To(Equal(infrav1.PolicyViolationReason))
+	g.Expect(conditions.GetMessage(clusterScope.AzureCluster, infrav1.PolicyCompliantCondition)).To(Equal("violation one; violation two"))
+
+	clusterScope.SetPolicyViolations(nil)
+	g.Expect(conditions.IsTrue(clusterScope.AzureCluster, infrav1.PolicyCompliantCondition)).To(BeTrue())
+}
+
+func TestExistingAPIServerPublicIP(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				NetworkSpec: infrav1.NetworkSpec{
+					APIServerLB: infrav1.LoadBalancerSpec{
+						FrontendIPs: []infrav1.FrontendIP{
+							{
+								Name:     "my-lb-frontEnd",
+								PublicIP: &infrav1.PublicIPSpec{Existing: &infrav1.ExistingPublicIP{DNSLabel: "my-api"}},
+							},
+						},
+						LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+							Type: infrav1.Public,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	g.Expect(clusterScope.ExistingAPIServerPublicIP()).To(Equal(&infrav1.ExistingPublicIP{DNSLabel: "my-api"}))
+	g.Expect(clusterScope.PublicIPSpecs()).To(BeEmpty())
+
+	clusterScope.SetExistingAPIServerPublicIP("/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/publicIPAddresses/byo-ip", "byo-ip", "my-api.westus.cloudapp.azure.com")
+	g.Expect(clusterScope.APIServerPublicIP().Name).To(Equal("byo-ip"))
+	g.Expect(clusterScope.APIServerPublicIPID()).To(Equal("/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/publicIPAddresses/byo-ip"))
+	g.Expect(clusterScope.APIServerHost()).To(Equal("my-api.westus.cloudapp.azure.com"))
+
+	// A DNS name set by the user is kept.
+	clusterScope.APIServerPublicIP().DNSName = "api.example.com"
+	clusterScope.SetExistingAPIServerPublicIP("/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/publicIPAddresses/byo-ip", "byo-ip", "20.1.2.3")
+	g.Expect(clusterScope.APIServerHost()).To(Equal("api.example.com"))
+}
+
+func TestPricedResourceSpecs(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+	labels := map[string]string{clusterv1.ClusterNameLabel: "my-cluster"}
+
+	machine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-control-plane-abcde", Namespace: "default", Labels: labels},
+		Spec: infrav1.AzureMachineSpec{
+			VMSize: "Standard_D4s_v3",
+			OSDisk: infrav1.OSDisk{
+				OSType:      azure.LinuxOS,
+				DiskSizeGB:  ptr.To[int32](128),
+				ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "Premium_LRS"},
+			},
+			DataDisks: []infrav1.DataDisk{
+				{
+					NameSuffix:  "etcddisk",
+					DiskSizeGB:  256,
+					ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "Premium_LRS"},
+				},
+			},
+		},
+	}
+	otherMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-cluster-md-0-abcde",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "other-cluster"},
+		},
+		Spec: infrav1.AzureMachineSpec{VMSize: "Standard_D2s_v3"},
+	}
+	machinePool := &infrav1exp.AzureMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-mp-0", Namespace: "default", Labels: labels},
+		Spec: infrav1exp.AzureMachinePoolSpec{
+			Template: infrav1exp.AzureMachinePoolMachineTemplate{
+				VMSize: "Standard_D2s_v3",
+				OSDisk: infrav1.OSDisk{
+					OSType:           azure.WindowsOS,
+					DiskSizeGB:       ptr.To[int32](64),
+					ManagedDisk:      &infrav1.ManagedDiskParameters{StorageAccountType: "Standard_LRS"},
+					DiffDiskSettings: &infrav1.DiffDiskSettings{Option: "Local"},
+				},
+				SpotVMOptions: &infrav1.SpotVMOptions{},
+			},
+		},
+		Status: infrav1exp.AzureMachinePoolStatus{Replicas: 3},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(machine, otherMachine, machinePool).Build()
+
+	clusterScope := &ClusterScope{
+		Client: fakeClient,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+			Spec: infrav1.AzureClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					APIServerLB: infrav1.LoadBalancerSpec{
+						Name:                  "my-cluster-public-lb",
+						LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{SKU: infrav1.SKUStandard},
+					},
+				},
+			},
+		},
+	}
+
+	specs, err := clusterScope.PricedResourceSpecs(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(specs).To(ConsistOf(
+		azure.PricedResourceSpec{Type: costestimates.LoadBalancerType, SKU: "Standard", Count: 1},
+		azure.PricedResourceSpec{Type: costestimates.VirtualMachineType, SKU: "Standard_D4s_v3", Count: 1},
+		azure.PricedResourceSpec{Type: costestimates.ManagedDiskType, SKU: "Premium_LRS", DiskSizeGB: 128, Count: 1},
+		azure.PricedResourceSpec{Type: costestimates.ManagedDiskType, SKU: "Premium_LRS", DiskSizeGB: 256, Count: 1},
+		azure.PricedResourceSpec{Type: costestimates.VirtualMachineType, SKU: "Standard_D2s_v3", Windows: true, Spot: true, Count: 3},
+	))
+}
+
+func TestMachineEncryptionSpecs(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+	labels := map[string]string{clusterv1.ClusterNameLabel: "my-cluster"}
+	diskEncryptionSet := &infrav1.DiskEncryptionSetParameters{ID: "/subscriptions/123/resourceGroups/keys/providers/Microsoft.Compute/diskEncryptionSets/cmk"}
+
+	cmkMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-control-plane-abcde", Namespace: "default", Labels: labels},
+		Spec: infrav1.AzureMachineSpec{
+			OSDisk: infrav1.OSDisk{ManagedDisk: &infrav1.ManagedDiskParameters{DiskEncryptionSet: diskEncryptionSet}},
+			DataDisks: []infrav1.DataDisk{
+				{NameSuffix: "etcddisk", ManagedDisk: &infrav1.ManagedDiskParameters{DiskEncryptionSet: diskEncryptionSet}},
+			},
+			SecurityProfile: &infrav1.SecurityProfile{EncryptionAtHost: ptr.To(true)},
+		},
+	}
+	partialCMKMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-md-0-abcde", Namespace: "default", Labels: labels},
+		Spec: infrav1.AzureMachineSpec{
+			OSDisk:    infrav1.OSDisk{ManagedDisk: &infrav1.ManagedDiskParameters{DiskEncryptionSet: diskEncryptionSet}},
+			DataDisks: []infrav1.DataDisk{{NameSuffix: "data"}},
+		},
+	}
+	adeMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-md-1-abcde", Namespace: "default", Labels: labels},
+		Spec: infrav1.AzureMachineSpec{
+			AzureDiskEncryption: &infrav1.AzureDiskEncryption{
+				KeyVaultID:          "/subscriptions/123/resourceGroups/keys/providers/Microsoft.KeyVault/vaults/ade",
+				KeyVaultURL:         "https://ade.vault.azure.net/",
+				KeyEncryptionKeyURL: "https://ade.vault.azure.net/keys/kek/0123",
+			},
+		},
+	}
+	otherMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-cluster-md-0-abcde",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "other-cluster"},
+		},
+	}
+	machinePool := &infrav1exp.AzureMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-mp-0", Namespace: "default", Labels: labels},
+		Spec: infrav1exp.AzureMachinePoolSpec{
+			Template: infrav1exp.AzureMachinePoolMachineTemplate{
+				OSDisk: infrav1.OSDisk{
+					ManagedDisk:      &infrav1.ManagedDiskParameters{DiskEncryptionSet: diskEncryptionSet},
+					DiffDiskSettings: &infrav1.DiffDiskSettings{Option: "Local"},
+				},
+				VMExtensions: []infrav1.VMExtension{
+					{Name: "AzureDiskEncryptionForLinux", Publisher: "Microsoft.Azure.Security", Version: "1.1"},
+				},
+			},
+		},
+		Status: infrav1exp.AzureMachinePoolStatus{Replicas: 3},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cmkMachine, partialCMKMachine, adeMachine, otherMachine, machinePool).Build()
+
+	clusterScope := &ClusterScope{
+		Client: fakeClient,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		},
+	}
+
+	specs, err := clusterScope.MachineEncryptionSpecs(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(specs).To(ConsistOf(
+		azure.MachineEncryptionSpec{Name: "AzureMachine/my-cluster-control-plane-abcde", CustomerManagedKeys: true, EncryptionAtHost: true, Count: 1},
+		azure.MachineEncryptionSpec{Name: "AzureMachine/my-cluster-md-0-abcde", Count: 1},
+		azure.MachineEncryptionSpec{Name: "AzureMachine/my-cluster-md-1-abcde", AzureDiskEncryption: true, Count: 1},
+		azure.MachineEncryptionSpec{Name: "AzureMachinePool/my-cluster-mp-0", AzureDiskEncryption: true, Count: 3},
+	))
+}
+
+func TestSetEncryptionPosture(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := &ClusterScope{
+		AzureCluster: &infrav1.AzureCluster{},
+	}
+
+	clusterScope.SetEncryptionPosture(&infrav1.EncryptionPosture{
+		Machines:                3,
+		PlatformManagedKeysOnly: []string{"AzureMachine/my-cluster-md-0-abcde", "AzureMachinePool/my-cluster-mp-0"},
+	})
+	g.Expect(conditions.IsTrue(clusterScope.AzureCluster, infrav1.PlatformManagedKeysOnlyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(clusterScope.AzureCluster, infrav1.PlatformManagedKeysOnlyCondition)).To(Equal(infrav1.NoAdditionalEncryptionReason))
+	g.Expect(conditions.GetMessage(clusterScope.AzureCluster, infrav1.PlatformManagedKeysOnlyCondition)).To(Equal(
+		"The disks of AzureMachine/my-cluster-md-0-abcde, AzureMachinePool/my-cluster-mp-0 are only encrypted at rest with platform-managed keys"))
+
+	clusterScope.SetEncryptionPosture(&infrav1.EncryptionPosture{Machines: 3, EncryptionAtHost: 3})
+	g.Expect(clusterScope.AzureCluster.Status.EncryptionPosture.EncryptionAtHost).To(Equal(int32(3)))
+	g.Expect(conditions.Has(clusterScope.AzureCluster, infrav1.PlatformManagedKeysOnlyCondition)).To(BeFalse())
+}
+
+func TestControlPlaneMachineFailureDomains(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	controlPlaneLabels := map[string]string{clusterv1.ClusterNameLabel: "my-cluster", clusterv1.MachineControlPlaneLabel: ""}
+
+	machines := []client.Object{
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-control-plane-abcde", Namespace: "default", Labels: controlPlaneLabels},
+			Spec:       clusterv1.MachineSpec{FailureDomain: ptr.To("1")},
+		},
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-control-plane-fghij", Namespace: "default", Labels: controlPlaneLabels},
+		},
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "my-cluster-control-plane-klmno",
+				Namespace:         "default",
+				Labels:            controlPlaneLabels,
+				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				Finalizers:        []string{clusterv1.MachineFinalizer},
+			},
+			Spec: clusterv1.MachineSpec{FailureDomain: ptr.To("2")},
+		},
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-cluster-md-0-abcde",
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: "my-cluster"},
+			},
+			Spec: clusterv1.MachineSpec{FailureDomain: ptr.To("3")},
+		},
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other-cluster-control-plane-abcde",
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: "other-cluster", clusterv1.MachineControlPlaneLabel: ""},
+			},
+			Spec: clusterv1.MachineSpec{FailureDomain: ptr.To("3")},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(machines...).Build()
+
+	clusterScope := &ClusterScope{
+		Client: fakeClient,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		},
+	}
+
+	failureDomains, err := clusterScope.ControlPlaneMachineFailureDomains(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(failureDomains).To(ConsistOf("1", ""))
+}
+
+func TestSetControlPlaneSingleZone(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := &ClusterScope{
+		AzureCluster: &infrav1.AzureCluster{
+			Status: infrav1.AzureClusterStatus{
+				FailureDomains: clusterv1.FailureDomains{
+					"3": clusterv1.FailureDomainSpec{ControlPlane: true},
+					"1": clusterv1.FailureDomainSpec{ControlPlane: true},
+					"2": clusterv1.FailureDomainSpec{ControlPlane: false},
+				},
+			},
+		},
 	}
+	g.Expect(clusterScope.ControlPlaneFailureDomains()).To(Equal([]string{"1", "3"}))
+
+	clusterScope.SetControlPlaneSingleZone("1", 3)
+	g.Expect(conditions.IsTrue(clusterScope.AzureCluster, infrav1.ControlPlaneSingleZoneCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(clusterScope.AzureCluster, infrav1.ControlPlaneSingleZoneCondition)).To(Equal(infrav1.ZoneRedundancyLostReason))
+	g.Expect(conditions.GetMessage(clusterScope.AzureCluster, infrav1.ControlPlaneSingleZoneCondition)).To(Equal(
+		"The 3 control plane machines are all in failure domain 1 while the location offers failure domains 1, 3"))
+
+	clusterScope.ClearControlPlaneSingleZone()
+	g.Expect(conditions.Has(clusterScope.AzureCluster, infrav1.ControlPlaneSingleZoneCondition)).To(BeFalse())
 }
 
 func TestSubnet(t *testing.T) {
@@ -2610,6 +4376,94 @@ func TestAdditionalTags(t *testing.T) {
 	}
 }
 
+func TestClusterScope_TagsSpecs(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					AdditionalTags: infrav1.Tags{"foo": "bar"},
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{
+						ResourceGroup: "vnet-rg",
+						Name:          "my-vnet",
+					},
+					APIServerLB: infrav1.LoadBalancerSpec{
+						Name: "my-apiserver-lb",
+					},
+					NodeOutboundLB: &infrav1.LoadBalancerSpec{
+						Name: "my-node-outbound-lb",
+					},
+					Subnets: infrav1.Subnets{
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetControlPlane, Name: "cp-subnet"},
+							SecurityGroup:   infrav1.SecurityGroup{Name: "cp-nsg"},
+						},
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode, Name: "node-subnet"},
+							SecurityGroup:   infrav1.SecurityGroup{Name: "node-nsg"},
+							RouteTable:      infrav1.RouteTable{Name: "node-rt"},
+							AdditionalTags:  infrav1.Tags{"foo": "baz", "team": "nodes"},
+						},
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode, Name: "other-node-subnet"},
+							SecurityGroup:   infrav1.SecurityGroup{Name: "node-nsg"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	g.Expect(clusterScope.TagsSpecs()).To(Equal([]azure.TagsSpec{
+		{
+			Scope:      "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+			Tags:       infrav1.Tags{"foo": "bar"},
+			Annotation: azure.VNetTagsLastAppliedAnnotation,
+		},
+		{
+			Scope:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-apiserver-lb",
+			Tags:       infrav1.Tags{"foo": "bar"},
+			Annotation: azure.APIServerLBTagsLastAppliedAnnotation,
+		},
+		{
+			Scope:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-node-outbound-lb",
+			Tags:       infrav1.Tags{"foo": "bar"},
+			Annotation: azure.NodeOutboundLBTagsLastAppliedAnnotation,
+		},
+		{
+			Scope:      "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/networkSecurityGroups/cp-nsg",
+			Tags:       infrav1.Tags{"foo": "bar"},
+			Annotation: azure.SecurityGroupTagsLastAppliedAnnotation + "-0",
+		},
+		{
+			Scope:      "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/networkSecurityGroups/node-nsg",
+			Tags:       infrav1.Tags{"foo": "baz", "team": "nodes"},
+			Annotation: azure.SecurityGroupTagsLastAppliedAnnotation + "-1",
+		},
+		{
+			Scope:      "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/routeTables/node-rt",
+			Tags:       infrav1.Tags{"foo": "baz", "team": "nodes"},
+			Annotation: azure.RouteTableTagsLastAppliedAnnotation + "-1",
+		},
+	}))
+}
+
 func TestAPIServerPort(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -3019,6 +4873,176 @@ func TestClusterScope_LBSpecs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Private API Server LB and node internal LB",
+			azureCluster: &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+				},
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+						Location:       "westus2",
+					},
+					ResourceGroup: "my-rg",
+					NetworkSpec: infrav1.NetworkSpec{
+						Vnet: infrav1.VnetSpec{
+							Name:          "my-vnet",
+							ResourceGroup: "my-rg",
+						},
+						Subnets: []infrav1.SubnetSpec{
+							{
+								SubnetClassSpec: infrav1.SubnetClassSpec{
+									Name: "cp-subnet",
+									Role: infrav1.SubnetControlPlane,
+								},
+							},
+							{
+								SubnetClassSpec: infrav1.SubnetClassSpec{
+									Name: "node-subnet",
+									Role: infrav1.SubnetNode,
+								},
+							},
+						},
+						APIServerLB: infrav1.LoadBalancerSpec{
+							Name: "api-server-lb",
+							BackendPool: infrav1.BackendPool{
+								Name: "api-server-lb-backend-pool",
+							},
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								Type:                 infrav1.Internal,
+								IdleTimeoutInMinutes: ptr.To[int32](30),
+								SKU:                  infrav1.SKUStandard,
+							},
+						},
+						NodeInternalLB: &infrav1.NodeInternalLBSpec{
+							LoadBalancerSpec: infrav1.LoadBalancerSpec{
+								Name: "node-internal-lb",
+								BackendPool: infrav1.BackendPool{
+									Name: "node-internal-lb-backend-pool",
+								},
+								FrontendIPs: []infrav1.FrontendIP{{Name: "node-internal-lb-frontend-ip"}},
+								LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+									Type:                 infrav1.Internal,
+									IdleTimeoutInMinutes: ptr.To[int32](4),
+									SKU:                  infrav1.SKUStandard,
+									AdditionalRules: []infrav1.LoadBalancingRule{
+										{Name: "ingress", Protocol: infrav1.LoadBalancingRuleProtocolTCP, FrontendPort: 80, BackendPort: ptr.To[int32](30080)},
+									},
+								},
+							},
+							SubnetName: "node-subnet",
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&loadbalancers.LBSpec{
+					Name:                 "api-server-lb",
+					ResourceGroup:        "my-rg",
+					SubscriptionID:       "123",
+					ClusterName:          "my-cluster",
+					Location:             "westus2",
+					VNetName:             "my-vnet",
+					VNetResourceGroup:    "my-rg",
+					SubnetName:           "cp-subnet",
+					APIServerPort:        6443,
+					Type:                 infrav1.Internal,
+					SKU:                  infrav1.SKUStandard,
+					Role:                 infrav1.APIServerRole,
+					BackendPoolName:      "api-server-lb-backend-pool",
+					IdleTimeoutInMinutes: ptr.To[int32](30),
+					AdditionalTags:       infrav1.Tags{},
+				},
+				&loadbalancers.LBSpec{
+					Name:              "node-internal-lb",
+					ResourceGroup:     "my-rg",
+					SubscriptionID:    "123",
+					ClusterName:       "my-cluster",
+					Location:          "westus2",
+					VNetName:          "my-vnet",
+					VNetResourceGroup: "my-rg",
+					SubnetName:        "node-subnet",
+					FrontendIPConfigs: []infrav1.FrontendIP{{Name: "node-internal-lb-frontend-ip"}},
+					Type:              infrav1.Internal,
+					SKU:               infrav1.SKUStandard,
+					Role:              infrav1.NodeInternalRole,
+					BackendPoolName:   "node-internal-lb-backend-pool",
+					AdditionalRules: []infrav1.LoadBalancingRule{
+						{Name: "ingress", Protocol: infrav1.LoadBalancingRuleProtocolTCP, FrontendPort: 80, BackendPort: ptr.To[int32](30080)},
+					},
+					IdleTimeoutInMinutes: ptr.To[int32](4),
+					AdditionalTags:       infrav1.Tags{},
+				},
+			},
+		},
+		{
+			name: "Private API Server LB with egress proxy",
+			azureCluster: &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+				},
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+						Location:       "westus2",
+					},
+					ResourceGroup: "my-rg",
+					NetworkSpec: infrav1.NetworkSpec{
+						Vnet: infrav1.VnetSpec{
+							Name:          "my-vnet",
+							ResourceGroup: "my-rg",
+						},
+						Subnets: []infrav1.SubnetSpec{
+							{
+								SubnetClassSpec: infrav1.SubnetClassSpec{
+									Name: "cp-subnet",
+									Role: infrav1.SubnetControlPlane,
+								},
+							},
+						},
+						APIServerLB: infrav1.LoadBalancerSpec{
+							Name: "api-server-lb",
+							BackendPool: infrav1.BackendPool{
+								Name: "api-server-lb-backend-pool",
+							},
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								Type:                 infrav1.Internal,
+								IdleTimeoutInMinutes: ptr.To[int32](30),
+								SKU:                  infrav1.SKUStandard,
+								AdditionalRules: []infrav1.LoadBalancingRule{
+									{Name: "etcd", Protocol: infrav1.LoadBalancingRuleProtocolTCP, FrontendPort: 2379},
+								},
+							},
+						},
+						EgressProxy: &infrav1.EgressProxySpec{Port: 8132},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&loadbalancers.LBSpec{
+					Name:              "api-server-lb",
+					ResourceGroup:     "my-rg",
+					SubscriptionID:    "123",
+					ClusterName:       "my-cluster",
+					Location:          "westus2",
+					VNetName:          "my-vnet",
+					VNetResourceGroup: "my-rg",
+					SubnetName:        "cp-subnet",
+					APIServerPort:     6443,
+					Type:              infrav1.Internal,
+					SKU:               infrav1.SKUStandard,
+					Role:              infrav1.APIServerRole,
+					BackendPoolName:   "api-server-lb-backend-pool",
+					AdditionalRules: []infrav1.LoadBalancingRule{
+						{Name: "etcd", Protocol: infrav1.LoadBalancingRuleProtocolTCP, FrontendPort: 2379},
+						{Name: infrav1.EgressProxyLBRuleName, Protocol: infrav1.LoadBalancingRuleProtocolTCP, FrontendPort: 8132, BackendPort: ptr.To[int32](8132)},
+					},
+					IdleTimeoutInMinutes: ptr.To[int32](30),
+					AdditionalTags:       infrav1.Tags{},
+				},
+			},
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
@@ -3479,6 +5503,11 @@ func TestSetFailureDomain(t *testing.T) {
 			specifiedFDs:  clusterv1.FailureDomains{"fd1": clusterv1.FailureDomainSpec{ControlPlane: true}},
 			expectedFDs:   clusterv1.FailureDomains{"fd1": clusterv1.FailureDomainSpec{ControlPlane: false}},
 		},
+		"failure domain attributes are overridden": {
+			discoveredFDs: clusterv1.FailureDomains{"fd1": clusterv1.FailureDomainSpec{ControlPlane: true}},
+			specifiedFDs:  clusterv1.FailureDomains{"fd1": clusterv1.FailureDomainSpec{ControlPlane: true, Attributes: map[string]string{"rack": "a"}}},
+			expectedFDs:   clusterv1.FailureDomains{"fd1": clusterv1.FailureDomainSpec{ControlPlane: true, Attributes: map[string]string{"rack": "a"}}},
+		},
 	}
 
 	for name, tc := range cases {
@@ -3504,6 +5533,7 @@ func TestSetFailureDomain(t *testing.T) {
 			for fdName, fd := range tc.expectedFDs {
 				g.Expect(fdName).Should(BeKeyOf(c.AzureCluster.Status.FailureDomains))
 				g.Expect(c.AzureCluster.Status.FailureDomains[fdName].ControlPlane).To(Equal(fd.ControlPlane))
+				g.Expect(c.AzureCluster.Status.FailureDomains[fdName].Attributes).To(Equal(fd.Attributes))
 
 				delete(c.AzureCluster.Status.FailureDomains, fdName)
 			}
@@ -3512,3 +5542,141 @@ func TestSetFailureDomain(t *testing.T) {
 		})
 	}
 }
+
+func TestSetNetworkStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	s := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{
+						ResourceGroup: "vnet-rg",
+						Name:          "my-vnet",
+						ID:            "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+						VnetClassSpec: infrav1.VnetClassSpec{
+							CIDRBlocks: []string{"10.0.0.0/8"},
+						},
+					},
+					Subnets: infrav1.Subnets{
+						{
+							ID: "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/cp-subnet",
+							SubnetClassSpec: infrav1.SubnetClassSpec{
+								Name:       "cp-subnet",
+								Role:       infrav1.SubnetControlPlane,
+								CIDRBlocks: []string{"10.0.0.0/16"},
+							},
+							SecurityGroup: infrav1.SecurityGroup{Name: "cp-nsg"},
+						},
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{
+								Name:       "node-subnet",
+								Role:       infrav1.SubnetNode,
+								CIDRBlocks: []string{"10.1.0.0/16"},
+							},
+							SecurityGroup: infrav1.SecurityGroup{Name: "node-nsg"},
+							RouteTable:    infrav1.RouteTable{Name: "node-routetable"},
+							NatGateway: infrav1.NatGateway{
+								ID:                  "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/node-natgw",
+								NatGatewayIP:        infrav1.PublicIPSpec{Name: "pip-node-natgw"},
+								NatGatewayClassSpec: infrav1.NatGatewayClassSpec{Name: "node-natgw"},
+							},
+						},
+					},
+					APIServerLB: infrav1.LoadBalancerSpec{
+						Name: "my-cluster-public-lb",
+						FrontendIPs: []infrav1.FrontendIP{
+							{
+								Name:     "my-cluster-public-lb-frontEnd",
+								PublicIP: &infrav1.PublicIPSpec{Name: "pip-my-cluster-apiserver", DNSName: "my-cluster.westus.cloudapp.azure.com"},
+							},
+						},
+					},
+					NodeInternalLB: &infrav1.NodeInternalLBSpec{
+						LoadBalancerSpec: infrav1.LoadBalancerSpec{
+							Name: "my-cluster-node-internal-lb",
+							FrontendIPs: []infrav1.FrontendIP{
+								{
+									Name:            "my-cluster-node-internal-lb-frontEnd",
+									FrontendIPClass: infrav1.FrontendIPClass{PrivateIPAddress: "10.1.0.100"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	s.SetPublicIPAddress("pip-my-cluster-apiserver", "20.1.2.3")
+	s.SetPublicIPAddress("pip-node-natgw", "20.4.5.6")
+
+	s.SetNetworkStatus()
+
+	g.Expect(s.AzureCluster.Status.Network).To(Equal(&infrav1.NetworkStatus{
+		Vnet: &infrav1.VnetStatus{
+			Name:       "my-vnet",
+			ID:         "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+			CIDRBlocks: []string{"10.0.0.0/8"},
+		},
+		Subnets: []infrav1.SubnetStatus{
+			{
+				Name:            "cp-subnet",
+				ID:              "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/cp-subnet",
+				Role:            infrav1.SubnetControlPlane,
+				CIDRBlocks:      []string{"10.0.0.0/16"},
+				SecurityGroupID: "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/networkSecurityGroups/cp-nsg",
+			},
+			{
+				Name:            "node-subnet",
+				ID:              "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/node-subnet",
+				Role:            infrav1.SubnetNode,
+				CIDRBlocks:      []string{"10.1.0.0/16"},
+				SecurityGroupID: "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/networkSecurityGroups/node-nsg",
+				RouteTableID:    "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/routeTables/node-routetable",
+				NatGatewayID:    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/node-natgw",
+			},
+		},
+		LoadBalancers: []infrav1.LoadBalancerStatus{
+			{
+				Name: "my-cluster-public-lb",
+				ID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster-public-lb",
+				Role: infrav1.APIServerLoadBalancerRole,
+				FrontendIPs: []infrav1.FrontendIPStatus{
+					{
+						Name:            "my-cluster-public-lb-frontEnd",
+						PublicIPID:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-apiserver",
+						PublicIPAddress: "20.1.2.3",
+						DNSName:         "my-cluster.westus.cloudapp.azure.com",
+					},
+				},
+			},
+			{
+				Name: "my-cluster-node-internal-lb",
+				ID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster-node-internal-lb",
+				Role: infrav1.NodeInternalLoadBalancerRole,
+				FrontendIPs: []infrav1.FrontendIPStatus{
+					{
+						Name:             "my-cluster-node-internal-lb-frontEnd",
+						PrivateIPAddress: "10.1.0.100",
+					},
+				},
+			},
+		},
+		NatGateways: []infrav1.NatGatewayStatus{
+			{
+				Name:            "node-natgw",
+				ID:              "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/node-natgw",
+				PublicIPID:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-node-natgw",
+				PublicIPAddress: "20.4.5.6",
+			},
+		},
+	}))
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ClusterQuota limits the AzureClusters that can be created in each namespace of the management cluster and with each
+// AzureClusterIdentity. A zero limit is not enforced.
+type ClusterQuota struct {
+	// MaxClustersPerNamespace is the maximum number of AzureClusters in a namespace.
+	MaxClustersPerNamespace int
+	// MaxClustersPerIdentity is the maximum number of AzureClusters referencing the same AzureClusterIdentity.
+	MaxClustersPerIdentity int
+	// MaxVCPUsPerNamespace is the number of vCPUs of the AzureMachines and AzureMachinePools of a namespace from which
+	// no more AzureClusters can be created in it.
+	MaxVCPUsPerNamespace int64
+	// MaxVCPUsPerIdentity is the number of vCPUs of the AzureMachines and AzureMachinePools of the clusters
+	// referencing an AzureClusterIdentity from which no more AzureClusters can reference it.
+	MaxVCPUsPerIdentity int64
+}
+
+// IsZero returns true if no limit of the quota is enforced.
+func (q ClusterQuota) IsZero() bool {
+	return q == ClusterQuota{}
+}
+
+// ClusterQuotaChecker checks the AzureClusters created in the management cluster against a ClusterQuota. The vCPUs of
+// the VM sizes are listed with the credentials of the controller manager.
+type ClusterQuotaChecker struct {
+	client        client.Client
+	quota         ClusterQuota
+	newAuthorizer func(subscriptionID, environmentName string) (azure.Authorizer, error)
+	getSKUCache   func(azure.Authorizer, string) (*resourceskus.Cache, error)
+}
+
+// NewClusterQuotaChecker returns a ClusterQuotaChecker listing the clusters and machines of the management cluster
+// with c.
+func NewClusterQuotaChecker(c client.Client, quota ClusterQuota) *ClusterQuotaChecker {
+	return &ClusterQuotaChecker{
+		client:        c,
+		quota:         quota,
+		newAuthorizer: newEnvironmentAuthorizer,
+		getSKUCache:   resourceskus.GetCache,
+	}
+}
+
+var _ infrav1.ClusterQuotaChecker = (&ClusterQuotaChecker{}).Check
+
+// Check returns a description of each limit of the quota that creating cluster would exceed. The clusters being
+// deleted are not counted. The vCPUs of the machines whose VM size can't be looked up are not counted either, a
+// warning is returned for them instead.
+func (c *ClusterQuotaChecker) Check(ctx context.Context, cluster *infrav1.AzureCluster) ([]string, admission.Warnings, error) {
+	var exceeded []string
+	var warnings admission.Warnings
+	counter := &vCPUCounter{checker: c, sizes: map[string]int64{}}
+
+	if c.quota.MaxClustersPerNamespace > 0 || c.quota.MaxVCPUsPerNamespace > 0 {
+		clusters := &infrav1.AzureClusterList{}
+		if err := c.client.List(ctx, clusters, client.InNamespace(cluster.Namespace)); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to list AzureClusters")
+		}
+		others := otherClusters(cluster, clusters.Items, func(infrav1.AzureCluster) bool { return true })
+		scope := fmt.Sprintf("namespace %s", cluster.Namespace)
+		limitExceeded, limitWarnings, err := c.checkLimits(ctx, counter, scope, others, c.quota.MaxClustersPerNamespace, c.quota.MaxVCPUsPerNamespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		exceeded = append(exceeded, limitExceeded...)
+		warnings = append(warnings, limitWarnings...)
+	}
+
+	identity := identityKey(cluster)
+	if identity != "" && (c.quota.MaxClustersPerIdentity > 0 || c.quota.MaxVCPUsPerIdentity > 0) {
+		clusters := &infrav1.AzureClusterList{}
+		if err := c.client.List(ctx, clusters); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to list AzureClusters")
+		}
+		others := otherClusters(cluster, clusters.Items, func(other infrav1.AzureCluster) bool {
+			return identityKey(&other) == identity
+		})
+		scope := fmt.Sprintf("AzureClusterIdentity %s", identity)
+		limitExceeded, limitWarnings, err := c.checkLimits(ctx, counter, scope, others, c.quota.MaxClustersPerIdentity, c.quota.MaxVCPUsPerIdentity)
+		if err != nil {
+			return nil, nil, err
+		}
+		exceeded = append(exceeded, limitExceeded...)
+		warnings = append(warnings, limitWarnings...)
+	}
+
+	return exceeded, warnings, nil
+}
+
+// checkLimits checks the clusters of a namespace or identity other than the created one against their limits.
+func (c *ClusterQuotaChecker) checkLimits(ctx context.Context, counter *vCPUCounter, scope string, others []infrav1.AzureCluster, maxClusters int, maxVCPUs int64) ([]string, admission.Warnings, error) {
+	var exceeded []string
+	if maxClusters > 0 && len(others) >= maxClusters {
+		exceeded = append(exceeded, fmt.Sprintf("%s already has %d AzureClusters, the maximum is %d", scope, len(others), maxClusters))
+	}
+	if maxVCPUs <= 0 {
+		return exceeded, nil, nil
+	}
+	var vCPUs int64
+	var warnings admission.Warnings
+	for i := range others {
+		clusterVCPUs, clusterWarnings, err := counter.clusterVCPUs(ctx, &others[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		vCPUs += clusterVCPUs
+		warnings = append(warnings, clusterWarnings...)
+	}
+	if vCPUs >= maxVCPUs {
+		exceeded = append(exceeded, fmt.Sprintf("the machines of %s already have %d vCPUs, the maximum is %d", scope, vCPUs, maxVCPUs))
+	}
+	return exceeded, warnings, nil
+}
+
+// vCPUCounter counts the vCPUs of the machines of clusters, looking up the vCPUs of each VM size once.
+type vCPUCounter struct {
+	checker *ClusterQuotaChecker
+	sizes   map[string]int64
+}
+
+// clusterVCPUs returns the vCPUs of the AzureMachines and of the instances of the AzureMachinePools of cluster.
+func (v *vCPUCounter) clusterVCPUs(ctx context.Context, cluster *infrav1.AzureCluster) (int64, admission.Warnings, error) {
+	listOpts := []client.ListOption{
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: ownerClusterName(cluster)},
+	}
+	machines := &infrav1.AzureMachineList{}
+	if err := v.checker.client.List(ctx, machines, listOpts...); err != nil {
+		return 0, nil, errors.Wrap(err, "failed to list AzureMachines")
+	}
+	machinePools := &infrav1exp.AzureMachinePoolList{}
+	if err := v.checker.client.List(ctx, machinePools, listOpts...); err != nil {
+		return 0, nil, errors.Wrap(err, "failed to list AzureMachinePools")
+	}
+
+	var total int64
+	var warnings admission.Warnings
+	count := func(location, vmSize string, instances int64) {
+		vCPUs, err := v.vmSizeVCPUs(ctx, cluster, location, vmSize)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("the vCPUs of VM size %s in location %s are not counted: %v", vmSize, location, err))
+			return
+		}
+		total += vCPUs * instances
+	}
+	for _, machine := range machines.Items {
+		if machine.DeletionTimestamp.IsZero() {
+			count(cluster.Spec.Location, machine.Spec.VMSize, 1)
+		}
+	}
+	for _, machinePool := range machinePools.Items {
+		if machinePool.DeletionTimestamp.IsZero() {
+			count(machinePool.Spec.Location, machinePool.Spec.Template.VMSize, int64(machinePool.Status.Replicas))
+		}
+	}
+	return total, warnings, nil
+}
+
+// vmSizeVCPUs returns the number of vCPUs of vmSize in location, looked up in the subscription of cluster.
+func (v *vCPUCounter) vmSizeVCPUs(ctx context.Context, cluster *infrav1.AzureCluster, location, vmSize string) (int64, error) {
+	key := strings.ToLower(location + "/" + vmSize)
+	if vCPUs, ok := v.sizes[key]; ok {
+		return vCPUs, nil
+	}
+	auth, err := v.checker.newAuthorizer(cluster.Spec.SubscriptionID, cluster.Spec.AzureEnvironment)
+	if err != nil {
+		return 0, err
+	}
+	cache, err := v.checker.getSKUCache(auth, location)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, locationCheckTimeout)
+	defer cancel()
+	sku, err := cache.Get(ctx, vmSize, resourceskus.VirtualMachines)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := sku.GetCapability(resourceskus.VCPUs)
+	if !ok {
+		return 0, errors.New("the VM size has no vCPUs capability")
+	}
+	vCPUs, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse the vCPUs of the VM size")
+	}
+	v.sizes[key] = vCPUs
+	return vCPUs, nil
+}
+
+// otherClusters returns the clusters matching match other than cluster, leaving out the clusters being deleted.
+func otherClusters(cluster *infrav1.AzureCluster, clusters []infrav1.AzureCluster, match func(infrav1.AzureCluster) bool) []infrav1.AzureCluster {
+	var others []infrav1.AzureCluster
+	for _, other := range clusters {
+		if other.Namespace == cluster.Namespace && other.Name == cluster.Name {
+			continue
+		}
+		if other.DeletionTimestamp.IsZero() && match(other) {
+			others = append(others, other)
+		}
+	}
+	return others
+}
+
+// identityKey returns the namespace and name of the AzureClusterIdentity of cluster, or an empty string if it has
+// none. The identity defaults to the namespace of the cluster.
+func identityKey(cluster *infrav1.AzureCluster) string {
+	ref := cluster.Spec.IdentityRef
+	if ref == nil || ref.Name == "" {
+		return ""
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = cluster.Namespace
+	}
+	return namespace + "/" + ref.Name
+}
+
+// ownerClusterName returns the name of the Cluster owning cluster, which its machines are labeled with. Clusters
+// whose owner is not set yet are assumed to have the name of their Cluster.
+func ownerClusterName(cluster *infrav1.AzureCluster) string {
+	for _, ref := range cluster.OwnerReferences {
+		if ref.Kind == "Cluster" && strings.HasPrefix(ref.APIVersion, clusterv1.GroupVersion.Group+"/") {
+			return ref.Name
+		}
+	}
+	return cluster.Name
+}
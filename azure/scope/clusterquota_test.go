@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClusterQuotaChecker_Check(t *testing.T) {
+	quotaCluster := func(namespace, name, identity string) *infrav1.AzureCluster {
+		cluster := &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: name + "-cluster"},
+				},
+			},
+			Spec: infrav1.AzureClusterSpec{
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{Location: "eastus"},
+			},
+		}
+		if identity != "" {
+			cluster.Spec.IdentityRef = &corev1.ObjectReference{Name: identity}
+		}
+		return cluster
+	}
+	quotaMachine := func(namespace, name, clusterName, vmSize string) *infrav1.AzureMachine {
+		return &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+			},
+			Spec: infrav1.AzureMachineSpec{VMSize: vmSize},
+		}
+	}
+	machinePool := &infrav1exp.AzureMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "team-a",
+			Name:      "pool",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "one-cluster"},
+		},
+		Spec: infrav1exp.AzureMachinePoolSpec{
+			Location: "westus2",
+			Template: infrav1exp.AzureMachinePoolMachineTemplate{VMSize: "Standard_D4s_v3"},
+		},
+		Status: infrav1exp.AzureMachinePoolStatus{Replicas: 2},
+	}
+	deleting := quotaCluster("team-a", "deleting", "shared")
+	deleting.DeletionTimestamp = ptr.To(metav1.Now())
+	deleting.Finalizers = []string{infrav1.ClusterFinalizer}
+	objects := []client.Object{
+		quotaCluster("team-a", "one", "shared"),
+		quotaCluster("team-b", "two", "shared"),
+		quotaCluster("team-b", "three", ""),
+		deleting,
+		quotaMachine("team-a", "one-cp", "one-cluster", "Standard_D2s_v3"),
+		quotaMachine("team-a", "one-md", "one-cluster", "Standard_D2s_v3"),
+		quotaMachine("team-b", "two-cp", "two-cluster", "Standard_D8s_v3"),
+		quotaMachine("team-b", "three-cp", "three-cluster", "Standard_D2s_v3"),
+		machinePool,
+	}
+	skus := []armcompute.ResourceSKU{
+		{
+			Name:         ptr.To("Standard_D2s_v3"),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			Capabilities: []*armcompute.ResourceSKUCapabilities{{Name: ptr.To(resourceskus.VCPUs), Value: ptr.To("2")}},
+		},
+		{
+			Name:         ptr.To("Standard_D4s_v3"),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			Capabilities: []*armcompute.ResourceSKUCapabilities{{Name: ptr.To(resourceskus.VCPUs), Value: ptr.To("4")}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		quota        ClusterQuota
+		cluster      *infrav1.AzureCluster
+		wantExceeded []string
+		wantWarnings []string
+	}{
+		{
+			name:    "cluster within the cluster limits",
+			quota:   ClusterQuota{MaxClustersPerNamespace: 2, MaxClustersPerIdentity: 3},
+			cluster: quotaCluster("team-a", "new", "shared"),
+		},
+		{
+			name:         "cluster exceeding the cluster limit of its namespace",
+			quota:        ClusterQuota{MaxClustersPerNamespace: 2},
+			cluster:      quotaCluster("team-b", "new", ""),
+			wantExceeded: []string{"namespace team-b already has 2 AzureClusters, the maximum is 2"},
+		},
+		{
+			name:         "cluster exceeding the cluster limit of its identity",
+			quota:        ClusterQuota{MaxClustersPerIdentity: 1},
+			cluster:      quotaCluster("team-a", "new", "shared"),
+			wantExceeded: []string{"AzureClusterIdentity team-a/shared already has 1 AzureClusters, the maximum is 1"},
+		},
+		{
+			name:    "identity in another namespace",
+			quota:   ClusterQuota{MaxClustersPerIdentity: 1},
+			cluster: quotaCluster("team-c", "new", "shared"),
+		},
+		{
+			name:    "cluster without an identity",
+			quota:   ClusterQuota{MaxClustersPerIdentity: 1},
+			cluster: quotaCluster("team-a", "new", ""),
+		},
+		{
+			name:         "cluster exceeding the vCPU limit of its namespace",
+			quota:        ClusterQuota{MaxVCPUsPerNamespace: 12},
+			cluster:      quotaCluster("team-a", "new", ""),
+			wantExceeded: []string{"the machines of namespace team-a already have 12 vCPUs, the maximum is 12"},
+		},
+		{
+			name:    "cluster within the vCPU limit of its namespace",
+			quota:   ClusterQuota{MaxVCPUsPerNamespace: 13},
+			cluster: quotaCluster("team-a", "new", ""),
+		},
+		{
+			name:         "VM size whose vCPUs are unknown",
+			quota:        ClusterQuota{MaxVCPUsPerIdentity: 1},
+			cluster:      quotaCluster("team-b", "new", "shared"),
+			wantWarnings: []string{"the vCPUs of VM size Standard_D8s_v3 in location eastus are not counted"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(infrav1exp.AddToScheme(scheme)).To(Succeed())
+			checker := &ClusterQuotaChecker{
+				client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+				quota:  tc.quota,
+				newAuthorizer: func(_, _ string) (azure.Authorizer, error) {
+					return nil, nil
+				},
+				getSKUCache: func(_ azure.Authorizer, location string) (*resourceskus.Cache, error) {
+					return resourceskus.NewStaticCache(skus, location), nil
+				},
+			}
+			exceeded, warnings, err := checker.Check(context.Background(), tc.cluster)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(exceeded).To(Equal(tc.wantExceeded))
+			g.Expect(warnings).To(HaveLen(len(tc.wantWarnings)))
+			for i, want := range tc.wantWarnings {
+				g.Expect(warnings[i]).To(HavePrefix(want))
+			}
+		})
+	}
+}
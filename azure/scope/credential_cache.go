@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// credentialCacheKey identifies the token credential of an identity in a cloud. Secrets are hashed, so that a rotated
+// secret gets a new credential without keeping the secret itself in the cache.
+type credentialCacheKey struct {
+	identityType            infrav1.IdentityType
+	activeDirectoryEndpoint string
+	resourceManagerEndpoint string
+	tokenAudience           string
+	tenantID                string
+	clientID                string
+	secretHash              string
+	tokenFilePath           string
+}
+
+// credentialCache caches the token credentials of identities. azidentity credentials keep the tokens they acquire
+// until they expire, so reusing the credential of an identity across reconciles reuses its tokens instead of
+// requesting new ones from Azure AD for every reconcile of every cluster.
+type credentialCache struct {
+	mu    sync.Mutex
+	creds map[credentialCacheKey]azcore.TokenCredential
+}
+
+// tokenCredentials is the credential cache shared by the scopes of all the controllers of the manager.
+var tokenCredentials = newCredentialCache()
+
+func newCredentialCache() *credentialCache {
+	return &credentialCache{
+		creds: map[credentialCacheKey]azcore.TokenCredential{},
+	}
+}
+
+// getOrStore returns the cached credential for key, or creates it with newCredential and caches it. Credentials that
+// fail to be created are not cached.
+func (c *credentialCache) getOrStore(key credentialCacheKey, newCredential func() (azcore.TokenCredential, error)) (azcore.TokenCredential, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cred, ok := c.creds[key]; ok {
+		return cred, nil
+	}
+	cred, err := newCredential()
+	if err != nil {
+		return nil, err
+	}
+	c.creds[key] = cred
+	return cred, nil
+}
+
+// hashSecret returns the hash of a secret for a credential cache key.
+func hashSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+type fakeTokenCredential struct {
+	name string
+}
+
+func (f *fakeTokenCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: f.name}, nil
+}
+
+func TestCredentialCache(t *testing.T) {
+	g := NewWithT(t)
+
+	cache := newCredentialCache()
+	created := 0
+	newCredential := func(name string) func() (azcore.TokenCredential, error) {
+		return func() (azcore.TokenCredential, error) {
+			created++
+			return &fakeTokenCredential{name: name}, nil
+		}
+	}
+	key := credentialCacheKey{
+		identityType: infrav1.ManualServicePrincipal,
+		tenantID:     "tenant",
+		clientID:     "client",
+		secretHash:   hashSecret("secret"),
+	}
+
+	cred, err := cache.getOrStore(key, newCredential("first"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(created).To(Equal(1))
+
+	// The credential of the same identity is reused.
+	cached, err := cache.getOrStore(key, newCredential("second"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cached).To(BeIdenticalTo(cred))
+	g.Expect(created).To(Equal(1))
+
+	// Another secret gets another credential.
+	rotatedKey := key
+	rotatedKey.secretHash = hashSecret("rotated")
+	rotated, err := cache.getOrStore(rotatedKey, newCredential("rotated"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rotated).NotTo(BeIdenticalTo(cred))
+	g.Expect(created).To(Equal(2))
+
+	// Credentials that fail to be created are not cached.
+	failedKey := key
+	failedKey.clientID = "other-client"
+	_, err = cache.getOrStore(failedKey, func() (azcore.TokenCredential, error) {
+		return nil, errors.New("invalid client")
+	})
+	g.Expect(err).To(MatchError("invalid client"))
+	cred, err = cache.getOrStore(failedKey, newCredential("retried"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cred).To(Equal(&fakeTokenCredential{name: "retried"}))
+}
+
+func TestHashSecret(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(hashSecret("")).To(BeEmpty())
+	g.Expect(hashSecret("secret")).To(Equal(hashSecret("secret")))
+	g.Expect(hashSecret("secret")).NotTo(Equal(hashSecret("other-secret")))
+	g.Expect(hashSecret("secret")).NotTo(ContainSubstring("secret"))
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// recordConditionChange records an event on obj when a service operation changed a condition, so that
+// the events of an object tell the story of its Azure resources without repeating on every reconcile.
+// before is a copy of the condition taken before it was updated, and successMessage describes the
+// operation when it left the condition true.
+func recordConditionChange(obj conditions.Setter, before *clusterv1.Condition, conditionType clusterv1.ConditionType, successMessage string) {
+	after := conditions.Get(obj, conditionType)
+	if after == nil {
+		return
+	}
+	if before != nil && before.Status == after.Status && before.Reason == after.Reason && before.Message == after.Message {
+		return
+	}
+
+	switch {
+	case after.Status == corev1.ConditionTrue:
+		record.Event(obj, "Succeeded", successMessage)
+	case after.Severity == clusterv1.ConditionSeverityError:
+		record.Warn(obj, "Failed", after.Message)
+	default:
+		record.Event(obj, after.Reason, after.Message)
+	}
+}
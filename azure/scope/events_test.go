@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	clientrecord "k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/record"
+)
+
+func TestMachineScope_UpdateStatusRecordsEvents(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := clientrecord.NewFakeRecorder(10)
+	record.InitFromRecorder(recorder)
+
+	s := &MachineScope{AzureMachine: &infrav1.AzureMachine{}}
+
+	s.UpdatePutStatus(infrav1.VMRunningCondition, "virtualmachine", nil)
+	g.Expect(recorder.Events).To(Receive(Equal("Normal Succeeded virtualmachine successfully created or updated")))
+
+	// An unchanged condition must not record the same event on every reconcile.
+	s.UpdatePutStatus(infrav1.VMRunningCondition, "virtualmachine", nil)
+	g.Expect(recorder.Events).NotTo(Receive())
+
+	s.UpdatePutStatus(infrav1.VMRunningCondition, "virtualmachine", errors.New("boom"))
+	g.Expect(recorder.Events).To(Receive(Equal("Warning Failed virtualmachine failed to create or update. err: boom")))
+
+	s.UpdateDeleteStatus(infrav1.VMRunningCondition, "virtualmachine", nil)
+	g.Expect(recorder.Events).To(Receive(Equal("Normal Deleted virtualmachine successfully deleted")))
+}
@@ -21,11 +21,13 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	aadpodid "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity"
 	aadpodv1 "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity/v1"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/jongio/azidext/go/azidext"
@@ -35,6 +37,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/identity"
 	"sigs.k8s.io/cluster-api-provider-azure/util/system"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -90,7 +93,7 @@ func NewAzureClusterCredentialsProvider(ctx context.Context, kubeClient client.C
 	identity := &infrav1.AzureClusterIdentity{}
 	key := client.ObjectKey{Name: ref.Name, Namespace: namespace}
 	if err := kubeClient.Get(ctx, key, identity); err != nil {
-		return nil, errors.Errorf("failed to retrieve AzureClusterIdentity external object %q/%q: %v", key.Namespace, key.Name, err)
+		return nil, errors.Wrapf(err, "failed to retrieve AzureClusterIdentity external object %q/%q", key.Namespace, key.Name)
 	}
 
 	return &AzureClusterCredentialsProvider{
@@ -127,7 +130,7 @@ func NewManagedControlPlaneCredentialsProvider(ctx context.Context, kubeClient c
 	identity := &infrav1.AzureClusterIdentity{}
 	key := client.ObjectKey{Name: ref.Name, Namespace: namespace}
 	if err := kubeClient.Get(ctx, key, identity); err != nil {
-		return nil, errors.Errorf("failed to retrieve AzureClusterIdentity external object %q/%q: %v", key.Namespace, key.Name, err)
+		return nil, errors.Wrapf(err, "failed to retrieve AzureClusterIdentity external object %q/%q", key.Namespace, key.Name)
 	}
 
 	return &ManagedControlPlaneCredentialsProvider{
@@ -154,6 +157,16 @@ func (p *AzureCredentialsProvider) GetTokenCredential(ctx context.Context, resou
 	var authErr error
 	var cred azcore.TokenCredential
 
+	// Credentials are cached per identity, so that their tokens are reused across reconciles.
+	key := credentialCacheKey{
+		identityType:            p.Identity.Spec.Type,
+		activeDirectoryEndpoint: activeDirectoryEndpoint,
+		resourceManagerEndpoint: resourceManagerEndpoint,
+		tokenAudience:           tokenAudience,
+		tenantID:                p.Identity.Spec.TenantID,
+		clientID:                p.Identity.Spec.ClientID,
+	}
+
 	switch p.Identity.Spec.Type {
 	case infrav1.WorkloadIdentity:
 		azwiCredOptions, err := NewWorkloadIdentityCredentialOptions().
@@ -163,7 +176,10 @@ func (p *AzureCredentialsProvider) GetTokenCredential(ctx context.Context, resou
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to setup azwi options for identity %s", p.Identity.Name)
 		}
-		cred, authErr = NewWorkloadIdentityCredential(azwiCredOptions)
+		key.tenantID, key.clientID, key.tokenFilePath = azwiCredOptions.TenantID, azwiCredOptions.ClientID, azwiCredOptions.TokenFilePath
+		cred, authErr = tokenCredentials.getOrStore(key, func() (azcore.TokenCredential, error) {
+			return NewWorkloadIdentityCredential(azwiCredOptions)
+		})
 
 	case infrav1.ServicePrincipal, infrav1.ServicePrincipalCertificate, infrav1.UserAssignedMSI:
 		if err := createAzureIdentityWithBindings(ctx, p.Identity, resourceManagerEndpoint, activeDirectoryEndpoint, clusterMeta, p.Client); err != nil {
@@ -171,9 +187,14 @@ func (p *AzureCredentialsProvider) GetTokenCredential(ctx context.Context, resou
 		}
 
 		options := azidentity.ManagedIdentityCredentialOptions{
+			ClientOptions: azcore.ClientOptions{
+				Transport: azureutil.Transport(),
+			},
 			ID: azidentity.ClientID(p.Identity.Spec.ClientID),
 		}
-		cred, authErr = azidentity.NewManagedIdentityCredential(&options)
+		cred, authErr = tokenCredentials.getOrStore(key, func() (azcore.TokenCredential, error) {
+			return azidentity.NewManagedIdentityCredential(&options)
+		})
 
 	case infrav1.ManualServicePrincipal:
 		clientSecret, err := p.GetClientSecret(ctx)
@@ -192,9 +213,16 @@ func (p *AzureCredentialsProvider) GetTokenCredential(ctx context.Context, resou
 						},
 					},
 				},
+				Transport: azureutil.Transport(),
 			},
+			// Tokens for other tenants are only requested as auxiliary tokens, e.g. for images in a gallery of
+			// another tenant.
+			AdditionallyAllowedTenants: []string{"*"},
 		}
-		cred, authErr = azidentity.NewClientSecretCredential(p.GetTenantID(), p.Identity.Spec.ClientID, clientSecret, &options)
+		key.secretHash = hashSecret(clientSecret)
+		cred, authErr = tokenCredentials.getOrStore(key, func() (azcore.TokenCredential, error) {
+			return azidentity.NewClientSecretCredential(p.GetTenantID(), p.Identity.Spec.ClientID, clientSecret, &options)
+		})
 
 	default:
 		return nil, errors.Errorf("identity type %s not supported", p.Identity.Spec.Type)
@@ -403,3 +431,64 @@ func IsClusterNamespaceAllowed(ctx context.Context, k8sClient client.Client, all
 
 	return false
 }
+
+// identityCheckTimeout bounds the time spent acquiring a token to check the credentials of an identity.
+const identityCheckTimeout = 5 * time.Second
+
+// CheckIdentityCredentials acquires a token for the resource manager of the cloud of the controller manager with the
+// credentials of an AzureClusterIdentity, read from its secret for service principals. It lets the
+// AzureClusterIdentity webhook reject bad credentials before clusters use them. User-assigned managed identities only
+// get tokens on the VMs they are assigned to, and are not checked.
+func CheckIdentityCredentials(ctx context.Context, identity *infrav1.AzureClusterIdentity, secret *corev1.Secret) error {
+	cloudConfig, tokenScope, err := azureutil.GetEnvironmentCloud()
+	if err != nil {
+		return err
+	}
+	clientOptions := azcore.ClientOptions{
+		Cloud:     cloudConfig,
+		Transport: azureutil.Transport(),
+	}
+	secretData := func(key string) []byte {
+		if secret == nil {
+			return nil
+		}
+		return secret.Data[key]
+	}
+
+	var cred azcore.TokenCredential
+	switch identity.Spec.Type {
+	case infrav1.ServicePrincipal, infrav1.ManualServicePrincipal:
+		cred, err = azidentity.NewClientSecretCredential(identity.Spec.TenantID, identity.Spec.ClientID, string(secretData(infrav1.ClientSecretKey)),
+			&azidentity.ClientSecretCredentialOptions{ClientOptions: clientOptions})
+
+	case infrav1.ServicePrincipalCertificate:
+		certs, key, parseErr := azidentity.ParseCertificates(secretData(infrav1.ClientCertificateKey), secretData(infrav1.ClientCertificatePasswordKey))
+		if parseErr != nil {
+			return errors.Wrap(parseErr, "failed to parse certificate")
+		}
+		cred, err = azidentity.NewClientCertificateCredential(identity.Spec.TenantID, identity.Spec.ClientID, certs, key,
+			&azidentity.ClientCertificateCredentialOptions{ClientOptions: clientOptions})
+
+	case infrav1.WorkloadIdentity:
+		options, optionsErr := NewWorkloadIdentityCredentialOptions().
+			WithTenantID(identity.Spec.TenantID).
+			WithClientID(identity.Spec.ClientID).
+			WithDefaults()
+		if optionsErr != nil {
+			return optionsErr
+		}
+		options.Cloud = cloudConfig
+		cred, err = NewWorkloadIdentityCredential(options)
+
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, identityCheckTimeout)
+	defer cancel()
+	_, err = cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{tokenScope}})
+	return err
+}
@@ -22,6 +22,7 @@ import (
 
 	aadpodid "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity"
 	aadpodv1 "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity/v1"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -373,3 +374,76 @@ func TestHasClientSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckIdentityCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity *infrav1.AzureClusterIdentity
+		secret   *corev1.Secret
+		wantErr  string
+	}{
+		{
+			name: "user-assigned managed identities are not checked",
+			identity: &infrav1.AzureClusterIdentity{
+				Spec: infrav1.AzureClusterIdentitySpec{Type: infrav1.UserAssignedMSI, ClientID: "client-id", TenantID: "tenant-id"},
+			},
+		},
+		{
+			name: "invalid certificate",
+			identity: &infrav1.AzureClusterIdentity{
+				Spec: infrav1.AzureClusterIdentitySpec{Type: infrav1.ServicePrincipalCertificate, ClientID: "client-id", TenantID: "tenant-id"},
+			},
+			secret: &corev1.Secret{
+				Data: map[string][]byte{infrav1.ClientCertificateKey: []byte("not a certificate")},
+			},
+			wantErr: "failed to parse certificate",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := CheckIdentityCredentials(context.Background(), tc.identity, tc.secret)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.wantErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestGetTokenCredentialIsCached(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-client-secret", Namespace: "default"},
+		Data:       map[string][]byte{AzureSecretKey: []byte("my-secret")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	identity := &infrav1.AzureClusterIdentity{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-identity", Namespace: "default"},
+		Spec: infrav1.AzureClusterIdentitySpec{
+			Type:         infrav1.ManualServicePrincipal,
+			TenantID:     "b1a7e1c1-2f2e-4b5c-9f3e-1d2c3b4a5f60",
+			ClientID:     "cached-client-id",
+			ClientSecret: corev1.SecretReference{Name: "my-client-secret", Namespace: "default"},
+		},
+	}
+	getTokenCredential := func() azcore.TokenCredential {
+		p := &AzureCredentialsProvider{Client: fakeClient, Identity: identity}
+		cred, err := p.GetTokenCredential(context.Background(), "https://management.azure.com/", "https://login.microsoftonline.com/", "https://management.azure.com/", metav1.ObjectMeta{Name: "cluster"})
+		g.Expect(err).NotTo(HaveOccurred())
+		return cred
+	}
+
+	cred := getTokenCredential()
+	g.Expect(getTokenCredential()).To(BeIdenticalTo(cred))
+
+	// A rotated secret gets a new credential.
+	secret.Data[AzureSecretKey] = []byte("my-rotated-secret")
+	g.Expect(fakeClient.Update(context.Background(), secret)).To(Succeed())
+	g.Expect(getTokenCredential()).NotTo(BeIdenticalTo(cred))
+}
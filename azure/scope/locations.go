@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/locations"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+)
+
+// locationCheckTimeout bounds the time spent listing the locations or VM sizes available to a subscription.
+const locationCheckTimeout = 10 * time.Second
+
+// LocationChecker lists the locations and VM sizes available to the subscriptions of AzureClusters with the
+// credentials of the controller manager.
+type LocationChecker struct {
+	newAuthorizer      func(subscriptionID, environmentName string) (azure.Authorizer, error)
+	newLocationsClient func(azure.Authorizer) (locations.Client, error)
+	getSKUCache        func(azure.Authorizer, string) (*resourceskus.Cache, error)
+}
+
+var _ infrav1.LocationChecker = &LocationChecker{}
+
+// NewLocationChecker returns a LocationChecker using the credentials of the controller manager.
+func NewLocationChecker() *LocationChecker {
+	return &LocationChecker{
+		newAuthorizer: newEnvironmentAuthorizer,
+		newLocationsClient: func(auth azure.Authorizer) (locations.Client, error) {
+			return locations.NewClient(auth)
+		},
+		getSKUCache: resourceskus.GetCache,
+	}
+}
+
+// Locations returns the names of the physical locations available to the subscription of cluster.
+func (c *LocationChecker) Locations(ctx context.Context, cluster *infrav1.AzureCluster) ([]string, error) {
+	auth, err := c.newAuthorizer(cluster.Spec.SubscriptionID, cluster.Spec.AzureEnvironment)
+	if err != nil {
+		return nil, err
+	}
+	client, err := c.newLocationsClient(auth)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, locationCheckTimeout)
+	defer cancel()
+	available, err := client.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(available))
+	for _, location := range available {
+		names = append(names, location.Name)
+	}
+	return names, nil
+}
+
+// VMSizes returns the names of the VM sizes available to the subscription of cluster in location, leaving out the
+// sizes the subscription is restricted from deploying there.
+func (c *LocationChecker) VMSizes(ctx context.Context, cluster *infrav1.AzureCluster, location string) ([]string, error) {
+	auth, err := c.newAuthorizer(cluster.Spec.SubscriptionID, cluster.Spec.AzureEnvironment)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := c.getSKUCache(auth, location)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, locationCheckTimeout)
+	defer cancel()
+	var names []string
+	err = cache.Map(ctx, func(sku resourceskus.SKU) {
+		if sku.Name == nil || !strings.EqualFold(ptr.Deref(sku.ResourceType, ""), string(resourceskus.VirtualMachines)) || sku.IsLocationRestricted(location) {
+			return
+		}
+		names = append(names, *sku.Name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// VMSizeCapabilities returns the capabilities of vmSize in location for the subscription of cluster, from the SKU cache
+// of the location, or nil when the VM size is not available there.
+func (c *LocationChecker) VMSizeCapabilities(ctx context.Context, cluster *infrav1.AzureCluster, location, vmSize string) (*infrav1.VMSizeCapabilities, error) {
+	auth, err := c.newAuthorizer(cluster.Spec.SubscriptionID, cluster.Spec.AzureEnvironment)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := c.getSKUCache(auth, location)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, locationCheckTimeout)
+	defer cancel()
+	var capabilities *infrav1.VMSizeCapabilities
+	err = cache.Map(ctx, func(sku resourceskus.SKU) {
+		if capabilities != nil || !strings.EqualFold(ptr.Deref(sku.Name, ""), vmSize) ||
+			!strings.EqualFold(ptr.Deref(sku.ResourceType, ""), string(resourceskus.VirtualMachines)) || sku.IsLocationRestricted(location) {
+			return
+		}
+		_, confidentialComputing := sku.GetCapability(resourceskus.ConfidentialComputingType)
+		maxWriteAcceleratorDisks, _ := sku.GetCapability(resourceskus.MaxWriteAcceleratorDisksAllowed)
+		capabilities = &infrav1.VMSizeCapabilities{
+			AcceleratedNetworking: sku.HasCapability(resourceskus.AcceleratedNetworking),
+			EphemeralOSDisk:       sku.HasCapability(resourceskus.EphemeralOSDisk),
+			EncryptionAtHost:      sku.HasCapability(resourceskus.EncryptionAtHost),
+			TrustedLaunch:         !sku.HasCapability(resourceskus.TrustedLaunchDisabled),
+			ConfidentialComputing: confidentialComputing,
+		}
+		capabilities.MaxWriteAcceleratorDisks, _ = strconv.ParseInt(maxWriteAcceleratorDisks, 10, 64)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return capabilities, nil
+}
+
+// Catalog returns the VM sizes, zones and disk types available to subscriptionID in location, from the SKU cache of the
+// location. subscriptionID defaults to the subscription of the controller manager.
+func (c *LocationChecker) Catalog(ctx context.Context, subscriptionID, location string) (resourceskus.Catalog, error) {
+	auth, err := c.newAuthorizer(subscriptionID, os.Getenv(auth.EnvironmentName))
+	if err != nil {
+		return resourceskus.Catalog{}, err
+	}
+	cache, err := c.getSKUCache(auth, location)
+	if err != nil {
+		return resourceskus.Catalog{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, locationCheckTimeout)
+	defer cancel()
+	return cache.Catalog(ctx)
+}
+
+// environmentAuthorizer authorizes the clients of a subscription with the credentials of the controller manager.
+type environmentAuthorizer struct {
+	AzureClients
+}
+
+// BaseURI returns the Azure ResourceManagerEndpoint.
+func (a *environmentAuthorizer) BaseURI() string {
+	return a.ResourceManagerEndpoint
+}
+
+// newEnvironmentAuthorizer returns an authorizer for subscriptionID in the Azure environment environmentName with the
+// credentials of the controller manager. subscriptionID defaults to the subscription of the controller manager.
+func newEnvironmentAuthorizer(subscriptionID, environmentName string) (azure.Authorizer, error) {
+	auth := &environmentAuthorizer{}
+	if err := auth.setCredentials(subscriptionID, environmentName); err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
+
+// NewEnvironmentAuthorizer returns an authorizer for the subscription of the controller manager, in the Azure
+// environment named by its AZURE_ENVIRONMENT environment variable, with its credentials.
+func NewEnvironmentAuthorizer() (azure.Authorizer, error) {
+	return newEnvironmentAuthorizer("", os.Getenv(auth.EnvironmentName))
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/locations"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/fakearm"
+)
+
+func TestLocationChecker(t *testing.T) {
+	g := NewWithT(t)
+
+	server := fakearm.NewServer()
+	defer server.Close()
+	server.Register()
+
+	skus := []armcompute.ResourceSKU{
+		{
+			Name:         ptr.To("Standard_D2s_v3"),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
+				{Name: ptr.To(resourceskus.AcceleratedNetworking), Value: ptr.To(string(resourceskus.CapabilitySupported))},
+				{Name: ptr.To(resourceskus.TrustedLaunchDisabled), Value: ptr.To(string(resourceskus.CapabilitySupported))},
+				{Name: ptr.To(resourceskus.MaxWriteAcceleratorDisksAllowed), Value: ptr.To("2")},
+			},
+		},
+		{
+			Name:         ptr.To("Standard_D4s_v3"),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			Restrictions: []*armcompute.ResourceSKURestrictions{
+				{Type: ptr.To(armcompute.ResourceSKURestrictionsTypeLocation)},
+			},
+		},
+		{
+			Name:         ptr.To("Standard_D8s_v3"),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			Restrictions: []*armcompute.ResourceSKURestrictions{
+				{Type: ptr.To(armcompute.ResourceSKURestrictionsTypeZone)},
+			},
+		},
+		{Name: ptr.To("Premium_LRS"), ResourceType: ptr.To(string(resourceskus.Disks))},
+	}
+	var subscriptions, skuLocations []string
+	checker := &LocationChecker{
+		newAuthorizer: func(subscriptionID, _ string) (azure.Authorizer, error) {
+			subscriptions = append(subscriptions, subscriptionID)
+			return server.Authorizer(subscriptionID), nil
+		},
+		newLocationsClient: func(auth azure.Authorizer) (locations.Client, error) {
+			return locations.NewClient(auth)
+		},
+		getSKUCache: func(_ azure.Authorizer, location string) (*resourceskus.Cache, error) {
+			skuLocations = append(skuLocations, location)
+			return resourceskus.NewStaticCache(skus, location), nil
+		},
+	}
+	g.Expect(server.Put("/subscriptions/location-checker/locations", map[string]interface{}{
+		"value": []interface{}{
+			map[string]interface{}{"name": "eastus", "displayName": "East US", "metadata": map[string]interface{}{"regionType": "Physical"}},
+			map[string]interface{}{"name": "westus2", "displayName": "West US 2", "metadata": map[string]interface{}{"regionType": "Physical"}},
+		},
+	})).To(Succeed())
+	cluster := &infrav1.AzureCluster{
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				SubscriptionID: "location-checker",
+				Location:       "eastus",
+			},
+		},
+	}
+
+	available, err := checker.Locations(context.Background(), cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(available).To(Equal([]string{"eastus", "westus2"}))
+
+	vmSizes, err := checker.VMSizes(context.Background(), cluster, "westus2")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(vmSizes).To(Equal([]string{"Standard_D2s_v3", "Standard_D8s_v3"}))
+
+	capabilities, err := checker.VMSizeCapabilities(context.Background(), cluster, "westus2", "standard_d2s_v3")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(capabilities).To(Equal(&infrav1.VMSizeCapabilities{AcceleratedNetworking: true, MaxWriteAcceleratorDisks: 2}))
+
+	capabilities, err = checker.VMSizeCapabilities(context.Background(), cluster, "westus2", "Standard_D4s_v3")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(capabilities).To(BeNil())
+
+	catalog, err := checker.Catalog(context.Background(), "catalog", "westus2")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(catalog.Location).To(Equal("westus2"))
+	g.Expect(catalog.DiskTypes).To(Equal([]resourceskus.DiskType{{Name: "Premium_LRS", Zones: []string{}}}))
+
+	g.Expect(subscriptions).To(Equal([]string{"location-checker", "location-checker", "location-checker", "location-checker", "catalog"}))
+	g.Expect(skuLocations).To(Equal([]string{"westus2", "westus2", "westus2", "westus2"}))
+}
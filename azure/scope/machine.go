@@ -20,19 +20,27 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/availabilitysets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootstrapdata"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootstrapsecrets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/imagecaptures"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/noderoutes"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
@@ -41,8 +49,10 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
+	"sigs.k8s.io/cluster-api-provider-azure/util/generators"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -50,6 +60,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// adminPasswordLength is the length of the administrator passwords generated for Windows machines.
+const adminPasswordLength = 32
+
 // MachineScopeParams defines the input parameters used to create a new MachineScope.
 type MachineScopeParams struct {
 	Client       client.Client
@@ -99,6 +112,8 @@ type MachineScope struct {
 	AzureMachine *infrav1.AzureMachine
 	cache        *MachineCache
 	skuCache     SKUCacher
+	// nicAddresses are the private IP addresses claimed from IP address pools for each network interface.
+	nicAddresses [][]string
 }
 
 // SKUCacher fetches a SKU from its cache.
@@ -108,10 +123,19 @@ type SKUCacher interface {
 
 // MachineCache stores common machine information so we don't have to hit the API multiple times within the same reconcile loop.
 type MachineCache struct {
-	BootstrapData      string
-	VMImage            *infrav1.Image
-	VMSKU              resourceskus.SKU
-	availabilitySetSKU resourceskus.SKU
+	BootstrapData       string
+	BootstrapDataFormat string
+	// BootstrapDataDelivery is the mechanism delivering BootstrapData to the VM, detected from its format.
+	BootstrapDataDelivery bootstrapDataDelivery
+	// KeyVaultBootstrapData is the bootstrap data stored in a Key Vault secret when BootstrapData only fetches it.
+	KeyVaultBootstrapData string
+	SecondaryUserData     string
+	ClusterSSHKeyData     string
+	AdminPassword         string
+	VMImage               *infrav1.Image
+	VMSKU                 resourceskus.SKU
+	availabilitySetSKU    resourceskus.SKU
+	diskSKUs              map[string]resourceskus.SKU
 }
 
 // InitMachineCache sets cached information about the machine to be used in the scope.
@@ -128,6 +152,42 @@ func (m *MachineScope) InitMachineCache(ctx context.Context) error {
 			return err
 		}
 
+		m.cache.BootstrapDataFormat, err = m.GetBootstrapDataFormat(ctx)
+		if err != nil {
+			return err
+		}
+
+		m.cache.BootstrapDataDelivery = detectBootstrapDataDelivery(m.AzureMachine.Spec.OSDisk.OSType, m.cache.BootstrapDataFormat, m.cache.BootstrapData)
+		if err := m.validateBootstrapDataDelivery(); err != nil {
+			return err
+		}
+
+		if vault := m.bootstrapDataKeyVault(); vault != nil {
+			if !m.HasSystemAssignedIdentity() {
+				return errors.Errorf("AzureMachine %s/%s needs a system-assigned identity to read its bootstrap data from Key Vault %s", m.Namespace(), m.Name(), vault.Name)
+			}
+			m.cache.KeyVaultBootstrapData = m.cache.BootstrapData
+			m.cache.BootstrapData, err = bootstrapsecrets.CustomData(m.CloudEnvironment(), vault.Name, m.bootstrapDataSecretName())
+			if err != nil {
+				return err
+			}
+		}
+
+		m.cache.SecondaryUserData, err = m.GetSecondaryUserData(ctx)
+		if err != nil {
+			return err
+		}
+
+		m.cache.ClusterSSHKeyData, err = GetClusterSSHKeyData(ctx, m.client, m.Namespace(), m.SSHKeyPairSecretName())
+		if err != nil {
+			return err
+		}
+
+		m.cache.AdminPassword, err = m.GetAdminPassword(ctx)
+		if err != nil {
+			return err
+		}
+
 		m.cache.VMImage, err = m.GetVMImage(ctx)
 		if err != nil {
 			return err
@@ -151,6 +211,18 @@ func (m *MachineScope) InitMachineCache(ctx context.Context) error {
 		if err != nil {
 			return errors.Wrapf(err, "failed to get availability set SKU %s in compute api", string(armcompute.AvailabilitySetSKUTypesAligned))
 		}
+
+		// Premium SSD v2 and zone-redundant disks are only offered in some regions. A disk SKU missing from the cache
+		// is not offered in the location, which the VM spec reports.
+		m.cache.diskSKUs = make(map[string]resourceskus.SKU)
+		for _, disk := range m.AzureMachine.Spec.DataDisks {
+			if disk.ManagedDisk == nil || !resourceskus.IsRegionalDiskType(disk.ManagedDisk.StorageAccountType) {
+				continue
+			}
+			if diskSKU, err := skuCache.Get(ctx, disk.ManagedDisk.StorageAccountType, resourceskus.Disks); err == nil {
+				m.cache.diskSKUs[disk.ManagedDisk.StorageAccountType] = diskSKU
+			}
+		}
 	}
 
 	return nil
@@ -166,44 +238,94 @@ func (m *MachineScope) VMSpec() azure.ResourceSpecGetter {
 		ClusterName:            m.ClusterName(),
 		Role:                   m.Role(),
 		NICIDs:                 m.NICIDs(),
+		AdminUsername:          m.AdminUsername(),
 		SSHKeyData:             m.AzureMachine.Spec.SSHPublicKey,
+		AdditionalSSHKeyData:   m.additionalSSHPublicKeys(),
 		Size:                   m.AzureMachine.Spec.VMSize,
+		SkipShutdown:           m.AzureMachine.Spec.SkipShutdown,
+		ReimageOnFailure:       m.AzureMachine.Spec.ReimageOnFailure,
+		DataDiskDetach:         m.AzureMachine.Spec.DataDiskDetach,
+		DeletionTimestamp:      m.AzureMachine.DeletionTimestamp,
 		OSDisk:                 m.AzureMachine.Spec.OSDisk,
 		DataDisks:              m.AzureMachine.Spec.DataDisks,
 		AvailabilitySetID:      m.AvailabilitySetID(),
 		Zone:                   m.AvailabilityZone(),
-		Identity:               m.AzureMachine.Spec.Identity,
-		UserAssignedIdentities: m.AzureMachine.Spec.UserAssignedIdentities,
 		SpotVMOptions:          m.AzureMachine.Spec.SpotVMOptions,
 		SecurityProfile:        m.AzureMachine.Spec.SecurityProfile,
 		DiagnosticsProfile:     m.AzureMachine.Spec.Diagnostics,
-		AdditionalTags:         m.AdditionalTags(),
+		AdditionalTags:         m.withMachineTag(m.AdditionalTags()),
 		AdditionalCapabilities: m.AzureMachine.Spec.AdditionalCapabilities,
 		ProviderID:             m.ProviderID(),
 	}
+	spec.Identity, spec.UserAssignedIdentities = withClusterIdentity(m.ClusterScoper, m.AzureMachine.Spec.Identity, m.AzureMachine.Spec.UserAssignedIdentities)
+	if m.AzureMachine.Spec.OSDisk.Source != nil {
+		spec.OSDiskID = azure.DiskID(m.SubscriptionID(), m.ResourceGroup(), azure.GenerateOSDiskName(m.Name()))
+	}
 	if m.cache != nil {
 		spec.SKU = m.cache.VMSKU
+		spec.DiskSKUs = m.cache.diskSKUs
 		spec.Image = m.cache.VMImage
-		spec.BootstrapData = m.cache.BootstrapData
+		switch m.cache.BootstrapDataDelivery {
+		case userDataDelivery:
+			spec.UserData = m.cache.BootstrapData
+		case extensionDelivery:
+			// The bootstrap data is run by the Custom Script extension, see VMExtensionSpecs.
+		default:
+			spec.BootstrapData = m.cache.BootstrapData
+			spec.UserData = m.cache.SecondaryUserData
+		}
+		spec.ClusterSSHKeyData = m.cache.ClusterSSHKeyData
+		spec.AdminPassword = m.cache.AdminPassword
 	}
 	return spec
 }
 
 // TagsSpecs returns the tags for the AzureMachine.
 func (m *MachineScope) TagsSpecs() []azure.TagsSpec {
-	return []azure.TagsSpec{
+	specs := []azure.TagsSpec{
 		{
 			Scope:      azure.VMID(m.SubscriptionID(), m.ResourceGroup(), m.Name()),
-			Tags:       m.AdditionalTags(),
+			Tags:       m.withMachineTag(m.AdditionalTags()),
 			Annotation: azure.VMTagsLastAppliedAnnotation,
 		},
 	}
+
+	specs = append(specs, azure.TagsSpec{
+		Scope:      azure.DiskID(m.SubscriptionID(), m.ResourceGroup(), azure.GenerateOSDiskName(m.Name())),
+		Tags:       m.osDiskTags(),
+		Annotation: azure.OSDiskTagsLastAppliedAnnotation,
+	})
+
+	// Each network interface gets its own annotation, as tags removed from the spec must be removed from every one of them.
+	isMultiNIC := len(m.AzureMachine.Spec.NetworkInterfaces) > 1
+	for i := range m.AzureMachine.Spec.NetworkInterfaces {
+		specs = append(specs, azure.TagsSpec{
+			Scope:      azure.NetworkInterfaceID(m.SubscriptionID(), m.ResourceGroup(), azure.GenerateNICName(m.Name(), isMultiNIC, i)),
+			Tags:       m.withMachineTag(m.AdditionalTags()),
+			Annotation: fmt.Sprintf("%s-%d", azure.NICTagsLastAppliedAnnotation, i),
+		})
+	}
+	return specs
+}
+
+// osDiskTags returns the tags of the OS disk. The OS disk is created along with the VM and does not get any tag, mark
+// it as owned by the cluster and the machine so that it can be garbage collected if the machine deletion fails to
+// remove it.
+func (m *MachineScope) osDiskTags() infrav1.Tags {
+	tags := m.withMachineTag(m.AdditionalTags())
+	tags[infrav1.ClusterTagKey(m.ClusterName())] = string(infrav1.ResourceLifecycleOwned)
+	return tags
 }
 
 // PublicIPSpecs returns the public IP specs.
 func (m *MachineScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 	var specs []azure.ResourceSpecGetter
 	if m.AzureMachine.Spec.AllocatePublicIP {
+		// A machine pinned to an availability zone gets a zonal public IP in the same zone.
+		failureDomains := m.FailureDomains()
+		if zone := m.AvailabilityZone(); zone != "" {
+			failureDomains = []*string{ptr.To(zone)}
+		}
 		specs = append(specs, &publicips.PublicIPSpec{
 			Name:             azure.GenerateNodePublicIPName(m.Name()),
 			ResourceGroup:    m.ResourceGroup(),
@@ -212,8 +334,8 @@ func (m *MachineScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 			IsIPv6:           false, // Set to default value
 			Location:         m.Location(),
 			ExtendedLocation: m.ExtendedLocation(),
-			FailureDomains:   m.FailureDomains(),
-			AdditionalTags:   m.ClusterScoper.AdditionalTags(),
+			FailureDomains:   failureDomains,
+			AdditionalTags:   m.withMachineTag(m.ClusterScoper.AdditionalTags()),
 		})
 	}
 	return specs
@@ -222,7 +344,7 @@ func (m *MachineScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 // InboundNatSpecs returns the inbound NAT specs.
 func (m *MachineScope) InboundNatSpecs() []azure.ResourceSpecGetter {
 	// The existing inbound NAT rules are needed in order to find an available SSH port for each new inbound NAT rule.
-	if m.Role() == infrav1.ControlPlane {
+	if m.Role() == infrav1.ControlPlane && !m.APIServerLB().DisableSSHNATRules {
 		spec := &inboundnatrules.InboundNatSpec{
 			Name:                      m.Name(),
 			ResourceGroup:             m.ResourceGroup(),
@@ -240,6 +362,19 @@ func (m *MachineScope) InboundNatSpecs() []azure.ResourceSpecGetter {
 	return []azure.ResourceSpecGetter{}
 }
 
+// SetSSHFrontendPort sets the SSH endpoint of the machine from the frontend port of its inbound NAT rule.
+// It is only set for public API server load balancers, whose NAT rules are associated with the machines.
+func (m *MachineScope) SetSSHFrontendPort(port int32) {
+	if m.IsAPIServerPrivate() {
+		return
+	}
+	endpoint := &infrav1.SSHEndpoint{Port: port}
+	if frontendIPs := m.APIServerLB().FrontendIPs; len(frontendIPs) > 0 && frontendIPs[0].PublicIP != nil {
+		endpoint.Host = frontendIPs[0].PublicIP.DNSName
+	}
+	m.AzureMachine.Status.SSHEndpoint = endpoint
+}
+
 // NICSpecs returns the network interface specs.
 func (m *MachineScope) NICSpecs() []azure.ResourceSpecGetter {
 	nicSpecs := []azure.ResourceSpecGetter{}
@@ -251,11 +386,27 @@ func (m *MachineScope) NICSpecs() []azure.ResourceSpecGetter {
 	for i := 0; i < len(m.AzureMachine.Spec.NetworkInterfaces); i++ {
 		isPrimary := i == 0
 		nicName := azure.GenerateNICName(m.Name(), isMultiNIC, i)
-		nicSpecs = append(nicSpecs, m.BuildNICSpec(nicName, m.AzureMachine.Spec.NetworkInterfaces[i], isPrimary))
+		nicSpec := m.BuildNICSpec(nicName, m.AzureMachine.Spec.NetworkInterfaces[i], isPrimary)
+		if i < len(m.nicAddresses) {
+			for j, address := range m.nicAddresses[i] {
+				if j == 0 {
+					nicSpec.StaticIPAddress = address
+				} else {
+					nicSpec.IPConfigs[j].PrivateIP = ptr.To(address)
+				}
+			}
+		}
+		nicSpecs = append(nicSpecs, nicSpec)
 	}
 	return nicSpecs
 }
 
+// SetNICAddresses sets the private IP addresses claimed from IP address pools for each network interface, in the
+// order of their IP configurations.
+func (m *MachineScope) SetNICAddresses(addresses [][]string) {
+	m.nicAddresses = addresses
+}
+
 // BuildNICSpec takes a NetworkInterface from the AzureMachineSpec and returns a NICSpec for use by the networkinterfaces service.
 func (m *MachineScope) BuildNICSpec(nicName string, infrav1NetworkInterface infrav1.NetworkInterface, primaryNetworkInterface bool) *networkinterfaces.NICSpec {
 	spec := &networkinterfaces.NICSpec{
@@ -271,7 +422,7 @@ func (m *MachineScope) BuildNICSpec(nicName string, infrav1NetworkInterface infr
 		IPv6Enabled:           m.IsIPv6Enabled(),
 		EnableIPForwarding:    m.AzureMachine.Spec.EnableIPForwarding,
 		SubnetName:            infrav1NetworkInterface.SubnetName,
-		AdditionalTags:        m.AdditionalTags(),
+		AdditionalTags:        m.withMachineTag(m.AdditionalTags()),
 		ClusterName:           m.ClusterName(),
 		IPConfigs:             []networkinterfaces.IPConfig{},
 	}
@@ -286,6 +437,8 @@ func (m *MachineScope) BuildNICSpec(nicName string, infrav1NetworkInterface infr
 
 	if primaryNetworkInterface {
 		spec.DNSServers = m.AzureMachine.Spec.DNSServers
+		spec.LoadBalancerBackendPoolIDs = m.AzureMachine.Spec.LoadBalancerBackendPoolIDs
+		spec.ApplicationGatewayBackendPoolIDs = m.AzureMachine.Spec.ApplicationGatewayBackendPoolIDs
 
 		if m.Role() == infrav1.ControlPlane {
 			spec.PublicLBName = m.OutboundLBName(m.Role())
@@ -294,7 +447,9 @@ func (m *MachineScope) BuildNICSpec(nicName string, infrav1NetworkInterface infr
 				spec.InternalLBName = m.APIServerLBName()
 				spec.InternalLBAddressPoolName = m.APIServerLBPoolName()
 			} else {
-				spec.PublicLBNATRuleName = m.Name()
+				if !m.APIServerLB().DisableSSHNATRules {
+					spec.PublicLBNATRuleName = m.Name()
+				}
 				spec.PublicLBAddressPoolName = m.APIServerLBPoolName()
 			}
 		}
@@ -307,6 +462,10 @@ func (m *MachineScope) BuildNICSpec(nicName string, infrav1NetworkInterface infr
 			spec.PublicLBName = m.OutboundLBName(m.Role())
 			spec.PublicLBAddressPoolName = m.OutboundPoolName(m.Role())
 		}
+		if m.Role() == infrav1.Node {
+			spec.InternalLBName = m.NodeInternalLBName()
+			spec.InternalLBAddressPoolName = m.NodeInternalLBPoolName()
+		}
 	}
 
 	return spec
@@ -326,20 +485,75 @@ func (m *MachineScope) NICIDs() []string {
 // DiskSpecs returns the disk specs.
 func (m *MachineScope) DiskSpecs() []azure.ResourceSpecGetter {
 	diskSpecs := make([]azure.ResourceSpecGetter, 1+len(m.AzureMachine.Spec.DataDisks))
-	diskSpecs[0] = &disks.DiskSpec{
-		Name:          azure.GenerateOSDiskName(m.Name()),
-		ResourceGroup: m.ResourceGroup(),
-	}
+	diskSpecs[0] = m.osDiskSpec()
 
 	for i, dd := range m.AzureMachine.Spec.DataDisks {
-		diskSpecs[i+1] = &disks.DiskSpec{
+		diskSpec := &disks.DiskSpec{
 			Name:          azure.GenerateDataDiskName(m.Name(), dd.NameSuffix),
 			ResourceGroup: m.ResourceGroup(),
 		}
+		if dd.ManagedDisk != nil {
+			diskSpec.DiskIOPSReadWrite = dd.ManagedDisk.DiskIOPSReadWrite
+			diskSpec.DiskMBpsReadWrite = dd.ManagedDisk.DiskMBpsReadWrite
+		}
+		diskSpecs[i+1] = diskSpec
 	}
 	return diskSpecs
 }
 
+// osDiskSpec returns the spec of the OS disk, which is created before the VM when it has a source.
+func (m *MachineScope) osDiskSpec() *disks.DiskSpec {
+	osDisk := m.AzureMachine.Spec.OSDisk
+	spec := &disks.DiskSpec{
+		Name:          azure.GenerateOSDiskName(m.Name()),
+		ResourceGroup: m.ResourceGroup(),
+	}
+	if osDisk.Source == nil {
+		return spec
+	}
+
+	if osDisk.Source.SnapshotID != nil {
+		spec.SourceResourceID = *osDisk.Source.SnapshotID
+		spec.CreateOption = armcompute.DiskCreateOptionCopy
+	} else {
+		spec.SourceResourceID = ptr.Deref(osDisk.Source.RestorePointID, "")
+		spec.CreateOption = armcompute.DiskCreateOptionRestore
+	}
+	spec.Location = m.Location()
+	// Zone-redundant disks are replicated across zones and can't be pinned to the zone of the VM.
+	if !osDisk.IsZoneRedundant() {
+		spec.Zone = m.AvailabilityZone()
+	}
+	spec.OSType = osDisk.OSType
+	spec.DiskSizeGB = osDisk.DiskSizeGB
+	spec.Tags = m.osDiskTags()
+	if osDisk.ManagedDisk != nil {
+		spec.StorageAccountType = osDisk.StorageAccountType()
+		if osDisk.ManagedDisk.DiskEncryptionSet != nil {
+			spec.DiskEncryptionSetID = osDisk.ManagedDisk.DiskEncryptionSet.ID
+		}
+	}
+	return spec
+}
+
+// withClusterIdentity adds the user-assigned identity created for the cluster, if any, to the identities of a VM or
+// scale set. The identities of VMs and scale sets with a system-assigned identity are left unchanged.
+func withClusterIdentity(cluster azure.ClusterDescriber, identity infrav1.VMIdentity, userAssignedIdentities []infrav1.UserAssignedIdentity) (infrav1.VMIdentity, []infrav1.UserAssignedIdentity) {
+	providerID := cluster.ManagedIdentityProviderID()
+	if providerID == "" || identity == infrav1.VMIdentitySystemAssigned {
+		return identity, userAssignedIdentities
+	}
+	if identity == infrav1.VMIdentityNone || identity == "" {
+		return infrav1.VMIdentityUserAssigned, []infrav1.UserAssignedIdentity{{ProviderID: providerID}}
+	}
+	for _, id := range userAssignedIdentities {
+		if strings.EqualFold(strings.TrimPrefix(id.ProviderID, azureutil.ProviderIDPrefix), providerID) {
+			return identity, userAssignedIdentities
+		}
+	}
+	return identity, append(append([]infrav1.UserAssignedIdentity(nil), userAssignedIdentities...), infrav1.UserAssignedIdentity{ProviderID: providerID})
+}
+
 // RoleAssignmentSpecs returns the role assignment specs.
 func (m *MachineScope) RoleAssignmentSpecs(principalID *string) []azure.ResourceSpecGetter {
 	roles := make([]azure.ResourceSpecGetter, 1)
@@ -387,8 +601,16 @@ func (m *MachineScope) VMExtensionSpecs() []azure.ResourceSpecGetter {
 		})
 	}
 
-	cpuArchitectureType, _ := m.cache.VMSKU.GetCapability(resourceskus.CPUArchitectureType)
-	bootstrapExtensionSpec := azure.GetBootstrappingVMExtension(m.AzureMachine.Spec.OSDisk.OSType, m.CloudEnvironment(), m.Name(), cpuArchitectureType)
+	var bootstrapExtensionSpec *azure.ExtensionSpec
+	switch m.cache.BootstrapDataDelivery {
+	case extensionDelivery:
+		bootstrapExtensionSpec = azure.GetBootstrapScriptVMExtension(m.Name(), m.cache.BootstrapData)
+	case userDataDelivery:
+		// The agent reading the user data reports the bootstrap status of the machine itself.
+	default:
+		cpuArchitectureType, _ := m.cache.VMSKU.GetCapability(resourceskus.CPUArchitectureType)
+		bootstrapExtensionSpec = azure.GetBootstrappingVMExtension(m.AzureMachine.Spec.OSDisk.OSType, m.CloudEnvironment(), m.Name(), cpuArchitectureType)
+	}
 
 	if bootstrapExtensionSpec != nil {
 		extensionSpecs = append(extensionSpecs, &vmextensions.VMExtensionSpec{
@@ -398,9 +620,55 @@ func (m *MachineScope) VMExtensionSpecs() []azure.ResourceSpecGetter {
 		})
 	}
 
+	if ade := m.AzureMachine.Spec.AzureDiskEncryption; ade != nil {
+		extensionSpecs = append(extensionSpecs, &vmextensions.VMExtensionSpec{
+			ExtensionSpec: *azure.GetAzureDiskEncryptionVMExtension(m.AzureMachine.Spec.OSDisk.OSType, m.Name(), ade),
+			ResourceGroup: m.ResourceGroup(),
+			Location:      m.Location(),
+		})
+	}
+
+	if sshKeyRotationExtensionSpec := m.sshKeyRotationExtensionSpec(); sshKeyRotationExtensionSpec != nil {
+		extensionSpecs = append(extensionSpecs, &vmextensions.VMExtensionSpec{
+			ExtensionSpec: *sshKeyRotationExtensionSpec,
+			ResourceGroup: m.ResourceGroup(),
+			Location:      m.Location(),
+		})
+	}
+
 	return extensionSpecs
 }
 
+// sshKeyRotationExtensionSpec returns the spec of the extension replacing the SSH public keys authorized on the
+// existing VM of a Linux machine opted into SSH key rotation, or nil. The cluster SSH key authorized on the VM when it
+// was created stays authorized.
+func (m *MachineScope) sshKeyRotationExtensionSpec() *azure.ExtensionSpec {
+	if m.AzureMachine.Annotations[infrav1.SSHKeyRotationAnnotation] != "true" || m.AzureMachine.Spec.OSDisk.OSType == azure.WindowsOS || m.ProviderID() == "" {
+		return nil
+	}
+	sshKey, err := base64.StdEncoding.DecodeString(m.AzureMachine.Spec.SSHPublicKey)
+	if err != nil || len(sshKey) == 0 {
+		return nil
+	}
+	keys := append([]string{m.AzureMachine.Spec.SSHPublicKey}, m.additionalSSHPublicKeys()...)
+	keys = append(keys, m.cache.ClusterSSHKeyData)
+	decoded, err := azure.DecodeSSHPublicKeys(keys)
+	if err != nil {
+		return nil
+	}
+	for i := range decoded {
+		decoded[i] = strings.TrimSpace(decoded[i])
+	}
+	return azure.GetSSHKeyRotationVMExtension(m.Name(), m.AdminUsername(), strings.Join(decoded, "\n"))
+}
+
+// additionalSSHPublicKeys returns the base64-encoded SSH public keys authorized on the VM in addition to the machine's
+// SSHPublicKey and to the cluster SSH key pair: the machine's additional keys, then the cluster keys for its role.
+func (m *MachineScope) additionalSSHPublicKeys() []string {
+	keys := append([]string{}, m.AzureMachine.Spec.AdditionalSSHPublicKeys...)
+	return append(keys, m.SSHPublicKeys(m.Role())...)
+}
+
 // Subnet returns the machine's subnet.
 func (m *MachineScope) Subnet() infrav1.SubnetSpec {
 	for _, subnet := range m.Subnets() {
@@ -417,7 +685,18 @@ func (m *MachineScope) Subnet() infrav1.SubnetSpec {
 //  1. Machine.Spec.FailureDomain
 //  2. AzureMachine.Spec.FailureDomain (This is to support deprecated AZ)
 //  3. No AZ
+//
+// The zone substituted for the zone of the failure domain, if any, is returned instead.
 func (m *MachineScope) AvailabilityZone() string {
+	failureDomain := m.failureDomain()
+	if substitution := m.AzureMachine.Status.ZoneSubstitution; substitution != nil && failureDomain != "" && substitution.FailureDomain == failureDomain {
+		return substitution.Zone
+	}
+	return failureDomain
+}
+
+// failureDomain returns the failure domain the machine should be placed in.
+func (m *MachineScope) failureDomain() string {
 	if m.Machine.Spec.FailureDomain != nil {
 		return *m.Machine.Spec.FailureDomain
 	}
@@ -429,6 +708,39 @@ func (m *MachineScope) AvailabilityZone() string {
 	return ""
 }
 
+// SubstituteZone records another failure domain of the cluster as the availability zone of a VM that is not created
+// yet, when the machine allows zone fallback and the VM size is not available in the zone currently used. The zones
+// are tried in order without ever going back, so that a machine whose size is available in none of them eventually
+// fails. It returns the zone substituted, or an empty string if there is none.
+func (m *MachineScope) SubstituteZone(reason string) string {
+	zone := m.AvailabilityZone()
+	if !m.AzureMachine.Spec.AllowZoneFallback || zone == "" || m.ProviderID() != "" {
+		return ""
+	}
+	failureDomain := m.failureDomain()
+	passed := zone == failureDomain
+	for _, fd := range m.FailureDomains() {
+		candidate := ptr.Deref(fd, "")
+		if !passed {
+			passed = candidate == zone
+			continue
+		}
+		if candidate == failureDomain || candidate == zone {
+			continue
+		}
+		if m.cache != nil && !m.cache.VMSKU.IsZoneAvailable(m.Location(), candidate) {
+			continue
+		}
+		m.AzureMachine.Status.ZoneSubstitution = &infrav1.ZoneSubstitution{
+			FailureDomain: failureDomain,
+			Zone:          candidate,
+			Reason:        reason,
+		}
+		return candidate
+	}
+	return ""
+}
+
 // Name returns the AzureMachine name.
 func (m *MachineScope) Name() string {
 	if id := m.GetVMID(); id != "" {
@@ -446,6 +758,11 @@ func (m *MachineScope) Namespace() string {
 	return m.AzureMachine.Namespace
 }
 
+// GetClient returns the controller-runtime client.
+func (m *MachineScope) GetClient() client.Client {
+	return m.client
+}
+
 // IsControlPlane returns true if the machine is a control plane.
 func (m *MachineScope) IsControlPlane() bool {
 	return util.IsControlPlaneMachine(m.Machine)
@@ -473,6 +790,153 @@ func (m *MachineScope) ProviderID() string {
 	return ptr.Deref(m.AzureMachine.Spec.ProviderID, "")
 }
 
+// AuxiliaryTenantIDs returns the tenants other than the tenant of the cluster identity that the VM needs tokens for,
+// i.e. the tenant of a shared gallery image in another tenant.
+func (m *MachineScope) AuxiliaryTenantIDs() []string {
+	return auxiliaryTenantIDs(m.AzureMachine.Spec.Image, m.TenantID())
+}
+
+// auxiliaryTenantIDs returns the tenant of image when it is in a shared gallery of a tenant other than tenantID.
+func auxiliaryTenantIDs(image *infrav1.Image, tenantID string) []string {
+	if image == nil || image.SharedGallery == nil || image.SharedGallery.TenantID == nil {
+		return nil
+	}
+	if strings.EqualFold(*image.SharedGallery.TenantID, tenantID) {
+		return nil
+	}
+	return []string{*image.SharedGallery.TenantID}
+}
+
+// VMResourceID returns the Azure resource ID of the VM of the machine.
+func (m *MachineScope) VMResourceID() string {
+	return azure.VMID(m.SubscriptionID(), m.ResourceGroup(), m.Name())
+}
+
+// NodeLabelTagMapping returns the mapping between the labels of the node and the tags of the VM of the machine.
+func (m *MachineScope) NodeLabelTagMapping() *infrav1.NodeLabelTagMapping {
+	return m.AzureMachine.Spec.NodeLabelTagMapping
+}
+
+// NodeMetadataAnnotations returns true if the metadata of the VM must be copied onto annotations of the node.
+func (m *MachineScope) NodeMetadataAnnotations() bool {
+	return m.AzureMachine.Spec.NodeMetadataAnnotations
+}
+
+// VMStatus returns the description of the Azure resources of the AzureMachine VM.
+func (m *MachineScope) VMStatus() *infrav1.VMStatus {
+	return m.AzureMachine.Status.VM
+}
+
+// NodeRef returns the reference to the node of the machine, or nil if it has not joined the cluster yet. The node found
+// by its provider ID is used until the Machine references it.
+func (m *MachineScope) NodeRef() *corev1.ObjectReference {
+	if m.Machine.Status.NodeRef != nil {
+		return m.Machine.Status.NodeRef
+	}
+	return m.AzureMachine.Status.NodeRef
+}
+
+// WorkloadClient returns a client for the workload cluster of the machine.
+func (m *MachineScope) WorkloadClient(ctx context.Context) (client.Client, error) {
+	return getWorkloadClient(ctx, m.client, client.ObjectKey{Namespace: m.AzureMachine.Namespace, Name: m.ClusterName()})
+}
+
+// UpdateNodeRef looks up the node of the workload cluster running on the VM by its provider ID and records it in the
+// AzureMachine status.
+func (m *MachineScope) UpdateNodeRef(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.UpdateNodeRef")
+	defer done()
+
+	if m.ProviderID() == "" {
+		return nil
+	}
+	workloadClient, err := m.WorkloadClient(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create workload cluster client")
+	}
+	return m.updateNodeRef(ctx, workloadClient)
+}
+
+// updateNodeRef records the node running on the VM in the AzureMachine status, or clears it if no node has the
+// provider ID of the VM. The node recorded before and the node named after the machine are tried first, so that all
+// the nodes of the cluster are only listed until the node is found.
+func (m *MachineScope) updateNodeRef(ctx context.Context, workloadClient client.Client) error {
+	providerID := m.ProviderID()
+	var names []string
+	if ref := m.AzureMachine.Status.NodeRef; ref != nil && ref.Name != m.Name() {
+		names = append(names, ref.Name)
+	}
+	names = append(names, m.Name())
+	for _, name := range names {
+		node := &corev1.Node{}
+		if err := workloadClient.Get(ctx, client.ObjectKey{Name: name}, node); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to get node %s", name)
+		}
+		if strings.EqualFold(node.Spec.ProviderID, providerID) {
+			m.setNodeRef(node)
+			return nil
+		}
+	}
+
+	nodes := &corev1.NodeList{}
+	for {
+		if err := workloadClient.List(ctx, nodes, client.Continue(nodes.Continue), client.Limit(100)); err != nil {
+			return errors.Wrap(err, "failed to list nodes")
+		}
+		for i := range nodes.Items {
+			if strings.EqualFold(nodes.Items[i].Spec.ProviderID, providerID) {
+				m.setNodeRef(&nodes.Items[i])
+				return nil
+			}
+		}
+		if nodes.Continue == "" {
+			break
+		}
+	}
+	m.AzureMachine.Status.NodeRef = nil
+	return nil
+}
+
+// setNodeRef records a node in the AzureMachine status.
+func (m *MachineScope) setNodeRef(node *corev1.Node) {
+	m.AzureMachine.Status.NodeRef = &corev1.ObjectReference{
+		APIVersion: corev1.SchemeGroupVersion.String(),
+		Kind:       "Node",
+		Name:       node.Name,
+		UID:        node.UID,
+	}
+}
+
+// NodeRouteSpecs returns the routes to the pod CIDR of the node of the machine, one in the route table of each node
+// subnet, if the routes are managed by capz rather than by the cloud provider.
+func (m *MachineScope) NodeRouteSpecs() []azure.ResourceSpecGetter {
+	nodeRoutes := m.NodeRoutes()
+	if nodeRoutes == nil || nodeRoutes.Mode != infrav1.NodeRoutesModeManaged {
+		return nil
+	}
+	var specs []azure.ResourceSpecGetter
+	routeTables := make(map[string]struct{})
+	for _, subnet := range m.NodeSubnets() {
+		name := subnet.RouteTable.Name
+		if name == "" {
+			continue
+		}
+		if _, ok := routeTables[name]; ok {
+			continue
+		}
+		routeTables[name] = struct{}{}
+		specs = append(specs, &noderoutes.RouteSpec{
+			Name:           m.Name(),
+			ResourceGroup:  m.Vnet().ResourceGroup,
+			RouteTableName: name,
+		})
+	}
+	return specs
+}
+
 // AvailabilitySetSpec returns the availability set spec for this machine if available.
 func (m *MachineScope) AvailabilitySetSpec() azure.ResourceSpecGetter {
 	availabilitySetName, ok := m.AvailabilitySet()
@@ -487,6 +951,8 @@ func (m *MachineScope) AvailabilitySetSpec() azure.ResourceSpecGetter {
 		Location:       m.Location(),
 		SKU:            nil,
 		AdditionalTags: m.AdditionalTags(),
+
+		PlatformFaultDomainCount: m.PlatformFaultDomainCount(),
 	}
 
 	if m.cache != nil {
@@ -571,6 +1037,65 @@ func (m *MachineScope) SetVMState(v infrav1.ProvisioningState) {
 	m.AzureMachine.Status.VMState = &v
 }
 
+// SetPowerState sets the AzureMachine VM power state.
+func (m *MachineScope) SetPowerState(v infrav1.VMPowerState) {
+	m.AzureMachine.Status.PowerState = v
+}
+
+// DesiredPowerState returns the power state requested for the AzureMachine VM through the VMPowerStateAnnotation, or
+// an empty string if the power state of the VM is not managed.
+func (m *MachineScope) DesiredPowerState() infrav1.VMPowerState {
+	return infrav1.VMPowerState(m.AzureMachine.Annotations[infrav1.VMPowerStateAnnotation])
+}
+
+// ImageCaptureSpec returns the spec of the compute gallery image version to capture the AzureMachine VM into, as
+// requested through the CaptureImageAnnotation, or nil if no image capture is requested.
+func (m *MachineScope) ImageCaptureSpec() azure.ResourceSpecGetter {
+	id, ok := m.AzureMachine.Annotations[infrav1.CaptureImageAnnotation]
+	if !ok {
+		return nil
+	}
+	versionID, err := azureutil.ParseResourceID(id)
+	if err != nil || versionID.Parent == nil || versionID.Parent.Parent == nil {
+		return nil
+	}
+	// The cloud provider tag is left out since the image version is meant to outlive the cluster.
+	tags := make(infrav1.Tags)
+	tags.Merge(m.ClusterScoper.AdditionalTags())
+	tags.Merge(m.AzureMachine.Spec.AdditionalTags)
+	return &imagecaptures.ImageCaptureSpec{
+		Name:            versionID.Name,
+		ImageName:       versionID.Parent.Name,
+		GalleryName:     versionID.Parent.Parent.Name,
+		ResourceGroup:   versionID.ResourceGroupName,
+		SubscriptionID:  versionID.SubscriptionID,
+		Location:        m.Location(),
+		VMName:          m.Name(),
+		VMResourceGroup: m.ResourceGroup(),
+		VMID:            azure.VMID(m.SubscriptionID(), m.ResourceGroup(), m.Name()),
+		AdditionalTags:  tags,
+	}
+}
+
+// SetVMStatus sets the description of the Azure resources of the AzureMachine VM.
+func (m *MachineScope) SetVMStatus(v *infrav1.VMStatus) {
+	m.AzureMachine.Status.VM = v
+	// The fault domain of a VM in an availability set is its failure domain, as availability zones are for zonal VMs.
+	if v != nil && v.PlatformFaultDomain != nil && m.AvailabilitySetID() != "" {
+		m.AzureMachine.Status.FailureDomain = strconv.Itoa(int(*v.PlatformFaultDomain))
+	}
+}
+
+// Reimaged returns true if the AzureMachine VM has been reimaged after it failed to provision.
+func (m *MachineScope) Reimaged() bool {
+	return m.AzureMachine.Status.Reimaged
+}
+
+// SetReimaged records that the AzureMachine VM has been reimaged after it failed to provision.
+func (m *MachineScope) SetReimaged() {
+	m.AzureMachine.Status.Reimaged = true
+}
+
 // SetReady sets the AzureMachine Ready Status to true.
 func (m *MachineScope) SetReady() {
 	m.AzureMachine.Status.Ready = true
@@ -646,8 +1171,19 @@ func (m *MachineScope) PatchObject(ctx context.Context) error {
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.ReadyCondition,
 			infrav1.VMRunningCondition,
+			infrav1.VMIdentitiesReadyCondition,
 			infrav1.AvailabilitySetReadyCondition,
 			infrav1.NetworkInterfaceReadyCondition,
+			infrav1.DisksReadyCondition,
+			infrav1.PublicIPsReadyCondition,
+			infrav1.InboundNATRulesReadyCondition,
+			infrav1.RoleAssignmentReadyCondition,
+			infrav1.BootstrapSucceededCondition,
+			infrav1.BootstrapDataReadyCondition,
+			infrav1.BootstrapDataSecretReadyCondition,
+			infrav1.NoDriftCondition,
+			infrav1.OutdatedImageCondition,
+			infrav1.ImageCapturedCondition,
 		}})
 }
 
@@ -670,23 +1206,317 @@ func (m *MachineScope) AdditionalTags() infrav1.Tags {
 	return tags
 }
 
+// withMachineTag returns a copy of tags with the tag marking resources dedicated to this machine.
+func (m *MachineScope) withMachineTag(tags infrav1.Tags) infrav1.Tags {
+	machineTags := make(infrav1.Tags, len(tags)+1)
+	machineTags.Merge(tags)
+	machineTags[infrav1.NameAzureClusterAPIMachine] = m.Name()
+	return machineTags
+}
+
 // GetBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName.
 func (m *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetBootstrapData")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetBootstrapData")
+	defer done()
+
+	secret, err := m.getBootstrapDataSecret(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secret.Data["value"]
+	if !ok {
+		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
+	}
+
+	if additional := m.AzureMachine.Spec.AdditionalCloudInit; additional != nil {
+		if format := string(secret.Data["format"]); format != "" && format != string(kubeadmv1.CloudConfig) {
+			return "", errors.Errorf("additionalCloudInit is not supported with %s bootstrap data", format)
+		}
+		merged, err := mergeCloudInit(value, additional)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to merge additional cloud-init into bootstrap data for AzureMachine %s/%s", m.Namespace(), m.Name())
+		}
+		value = merged
+	}
+
+	if disk := m.etcdDataDisk(); disk != nil {
+		if format := string(secret.Data["format"]); format != "" && format != string(kubeadmv1.CloudConfig) {
+			return "", errors.Errorf("etcd data disks are not supported with %s bootstrap data", format)
+		}
+		merged, err := mergeEtcdDataDisk(value, etcdDataDiskDevice(disk))
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to merge etcd data disk setup into bootstrap data for AzureMachine %s/%s", m.Namespace(), m.Name())
+		}
+		value = merged
+	}
+
+	if nodeProxy.url != "" && m.AzureMachine.Spec.OSDisk.OSType != azure.WindowsOS {
+		if format := string(secret.Data["format"]); format != "" && format != string(kubeadmv1.CloudConfig) {
+			log.V(2).Info("not setting up the node proxy", "format", format)
+		} else {
+			merged, err := mergeNodeProxy(value, nodeProxy.url, nodeNoProxy(m.Vnet()))
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to merge node proxy setup into bootstrap data for AzureMachine %s/%s", m.Namespace(), m.Name())
+			}
+			value = merged
+		}
+	}
+
+	return base64.StdEncoding.EncodeToString(value), nil
+}
+
+// etcdDataDisk returns the data disk designated as the etcd data disk of the machine, or nil if there is none.
+func (m *MachineScope) etcdDataDisk() *infrav1.DataDisk {
+	for i, disk := range m.AzureMachine.Spec.DataDisks {
+		if ptr.Deref(disk.Etcd, false) {
+			return &m.AzureMachine.Spec.DataDisks[i]
+		}
+	}
+	return nil
+}
+
+// etcdDataDiskDevice returns the path of the block device of a data disk, as exposed by the udev rules of the Azure
+// Linux images.
+func etcdDataDiskDevice(disk *infrav1.DataDisk) string {
+	return fmt.Sprintf("/dev/disk/azure/scsi1/lun%d", ptr.Deref(disk.Lun, 0))
+}
+
+// SetEtcdDataDisk records the data disk designated as the etcd data disk of the machine in its status.
+func (m *MachineScope) SetEtcdDataDisk() {
+	disk := m.etcdDataDisk()
+	if disk == nil {
+		m.AzureMachine.Status.EtcdDataDisk = nil
+		return
+	}
+	m.AzureMachine.Status.EtcdDataDisk = &infrav1.EtcdDataDiskStatus{
+		Name:      azure.GenerateDataDiskName(m.Name(), disk.NameSuffix),
+		Device:    etcdDataDiskDevice(disk),
+		MountPath: etcdDataDiskMountPath,
+	}
+}
+
+// GetBootstrapDataFormat returns the format of the bootstrap data from the secret in the Machine's bootstrap.dataSecretName.
+func (m *MachineScope) GetBootstrapDataFormat(ctx context.Context) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetBootstrapDataFormat")
+	defer done()
+
+	secret, err := m.getBootstrapDataSecret(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(secret.Data["format"]), nil
+}
+
+// GetSecondaryUserData returns the base64 encoded user data from the Secret referenced by the AzureMachine's
+// secondaryUserData, or an empty string if it is not set.
+func (m *MachineScope) GetSecondaryUserData(ctx context.Context) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetSecondaryUserData")
+	defer done()
+
+	source := m.AzureMachine.Spec.SecondaryUserData
+	if source == nil {
+		return "", nil
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: m.Namespace(), Name: source.SecretName}
+	if err := m.client.Get(ctx, key, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to retrieve secondary user data secret for AzureMachine %s/%s", m.Namespace(), m.Name())
+	}
+	dataKey := source.Key
+	if dataKey == "" {
+		dataKey = "value"
+	}
+	value, ok := secret.Data[dataKey]
+	if !ok {
+		return "", errors.Errorf("error retrieving secondary user data: secret %s has no %s key", source.SecretName, dataKey)
+	}
+	return base64.StdEncoding.EncodeToString(value), nil
+}
+
+// AdminUsername returns the name of the administrator account of the machine.
+func (m *MachineScope) AdminUsername() string {
+	if m.AzureMachine.Spec.AdminUsername != "" {
+		return m.AzureMachine.Spec.AdminUsername
+	}
+	return azure.DefaultUserName
+}
+
+// AdminPasswordSecretName returns the name of the Secret storing the administrator password of the machine.
+func (m *MachineScope) AdminPasswordSecretName() string {
+	return fmt.Sprintf("%s-admin-password", m.Name())
+}
+
+// GetAdminPassword returns the administrator password stored for a Windows machine with storeAdminPassword set, or an
+// empty string otherwise. The password is generated and stored in a Secret owned by the AzureMachine before its VM is
+// created, and read back from the Secret afterwards. A VM created without a stored password never gets one.
+func (m *MachineScope) GetAdminPassword(ctx context.Context) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetAdminPassword")
 	defer done()
 
+	if !m.AzureMachine.Spec.StoreAdminPassword || m.AzureMachine.Spec.OSDisk.OSType != azure.WindowsOS {
+		return "", nil
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: m.Namespace(), Name: m.AdminPasswordSecretName()}
+	err := m.client.Get(ctx, key, secret)
+	switch {
+	case err == nil:
+		password, ok := secret.Data[corev1.BasicAuthPasswordKey]
+		if !ok {
+			return "", errors.Errorf("error retrieving admin password: secret %s has no %s key", key.Name, corev1.BasicAuthPasswordKey)
+		}
+		return string(password), nil
+	case !apierrors.IsNotFound(err):
+		return "", errors.Wrapf(err, "failed to retrieve admin password secret for AzureMachine %s/%s", m.Namespace(), m.Name())
+	case m.ProviderID() != "":
+		return "", nil
+	}
+
+	password := generators.SudoRandomPassword(adminPasswordLength)
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: m.ClusterName()},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(m.AzureMachine, infrav1.GroupVersion.WithKind("AzureMachine")),
+			},
+		},
+		Type: corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte(m.AdminUsername()),
+			corev1.BasicAuthPasswordKey: []byte(password),
+		},
+	}
+	if err := m.client.Create(ctx, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to create admin password secret for AzureMachine %s/%s", m.Namespace(), m.Name())
+	}
+	return password, nil
+}
+
+func (m *MachineScope) getBootstrapDataSecret(ctx context.Context) (*corev1.Secret, error) {
 	if m.Machine.Spec.Bootstrap.DataSecretName == nil {
-		return "", errors.New("error retrieving bootstrap data: linked Machine's bootstrap.dataSecretName is nil")
+		return nil, errors.New("error retrieving bootstrap data: linked Machine's bootstrap.dataSecretName is nil")
 	}
 	secret := &corev1.Secret{}
 	key := types.NamespacedName{Namespace: m.Namespace(), Name: *m.Machine.Spec.Bootstrap.DataSecretName}
 	if err := m.client.Get(ctx, key, secret); err != nil {
-		return "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for AzureMachine %s/%s", m.Namespace(), m.Name())
+		return nil, errors.Wrapf(err, "failed to retrieve bootstrap data secret for AzureMachine %s/%s", m.Namespace(), m.Name())
 	}
+	return secret, nil
+}
 
-	value, ok := secret.Data["value"]
+// BootstrapDataBlobSpec returns the spec of the blob holding the bootstrap data of the machine, or nil if the cluster
+// does not deliver bootstrap data through a storage account.
+func (m *MachineScope) BootstrapDataBlobSpec() *azure.BootstrapDataBlobSpec {
+	accountName := m.BootstrapDataStorageAccountName()
+	if accountName == "" || m.AzureMachine.Spec.OSDisk.OSType == azure.WindowsOS {
+		return nil
+	}
+	if m.cache != nil && !m.cache.BootstrapDataDelivery.isCustomData() {
+		return nil
+	}
+	spec := &azure.BootstrapDataBlobSpec{
+		StorageAccountName: accountName,
+		ResourceGroup:      m.ResourceGroup(),
+		ContainerName:      bootstrapdata.ContainerName,
+		BlobName:           m.Name(),
+	}
+	if m.cache != nil {
+		spec.BootstrapData = m.cache.BootstrapData
+		spec.Format = m.cache.BootstrapDataFormat
+	}
+	return spec
+}
+
+// SetBootstrapData replaces the bootstrap data passed to the VM as custom data.
+func (m *MachineScope) SetBootstrapData(data string) {
+	if m.cache != nil {
+		m.cache.BootstrapData = data
+	}
+}
+
+// HasBootstrapDataBlob returns whether the bootstrap data of the machine has been uploaded to a blob that was not deleted yet.
+func (m *MachineScope) HasBootstrapDataBlob() bool {
+	return conditions.Has(m.AzureMachine, infrav1.BootstrapDataReadyCondition)
+}
+
+// ClearBootstrapDataBlob records that the blob holding the bootstrap data of the machine has been deleted.
+func (m *MachineScope) ClearBootstrapDataBlob() {
+	conditions.Delete(m.AzureMachine, infrav1.BootstrapDataReadyCondition)
+}
+
+// bootstrapDataKeyVault returns the Key Vault used to deliver the bootstrap data of the machine, or nil if the cluster
+// does not use one or the bootstrap data cannot be run by the script fetching it, i.e. on Windows and with Ignition.
+func (m *MachineScope) bootstrapDataKeyVault() *infrav1.BootstrapDataKeyVault {
+	if m.AzureMachine.Spec.OSDisk.OSType == azure.WindowsOS {
+		return nil
+	}
+	if m.cache != nil && (m.cache.BootstrapDataFormat == ignitionFormat || !m.cache.BootstrapDataDelivery.isCustomData()) {
+		return nil
+	}
+	return m.BootstrapDataKeyVault()
+}
+
+// bootstrapDataSecretName returns the name of the Key Vault secret holding the bootstrap data of the machine. Secret
+// names can only contain alphanumeric characters and dashes.
+func (m *MachineScope) bootstrapDataSecretName() string {
+	return strings.ReplaceAll(m.Name(), ".", "-")
+}
+
+// BootstrapDataSecretSpec returns the spec of the Key Vault secret holding the bootstrap data of the machine, or nil
+// if its bootstrap data is not delivered through Key Vault.
+func (m *MachineScope) BootstrapDataSecretSpec() *azure.BootstrapDataSecretSpec {
+	vault := m.bootstrapDataKeyVault()
+	if vault == nil {
+		return nil
+	}
+	spec := &azure.BootstrapDataSecretSpec{
+		VaultName:          vault.Name,
+		VaultResourceGroup: vault.ResourceGroup,
+		SecretName:         m.bootstrapDataSecretName(),
+	}
+	if m.cache != nil {
+		spec.BootstrapData = m.cache.KeyVaultBootstrapData
+	}
+	return spec
+}
+
+// HasBootstrapDataSecret returns whether the bootstrap data of the machine has been stored in a Key Vault secret that
+// was not deleted yet.
+func (m *MachineScope) HasBootstrapDataSecret() bool {
+	return conditions.Has(m.AzureMachine, infrav1.BootstrapDataSecretReadyCondition)
+}
+
+// ClearBootstrapDataSecret records that the Key Vault secret holding the bootstrap data of the machine has been deleted.
+func (m *MachineScope) ClearBootstrapDataSecret() {
+	conditions.Delete(m.AzureMachine, infrav1.BootstrapDataSecretReadyCondition)
+}
+
+// IsBootstrapped returns whether the machine has joined the cluster.
+func (m *MachineScope) IsBootstrapped() bool {
+	return m.Machine.Status.NodeRef != nil
+}
+
+// GetClusterSSHKeyData returns the base64 encoded public key of the cluster SSH key pair stored in the given Secret.
+// It returns an empty string if secretName is empty, meaning the cluster does not use a generated SSH key pair.
+func GetClusterSSHKeyData(ctx context.Context, c client.Client, namespace, secretName string) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.GetClusterSSHKeyData")
+	defer done()
+
+	if secretName == "" {
+		return "", nil
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: namespace, Name: secretName}
+	if err := c.Get(ctx, key, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to retrieve cluster SSH key pair secret %s/%s", namespace, secretName)
+	}
+
+	value, ok := secret.Data[azure.SSHKeyPairPublicKey]
 	if !ok {
-		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
+		return "", errors.Errorf("error retrieving cluster SSH public key: secret %s key is missing", azure.SSHKeyPairPublicKey)
 	}
 	return base64.StdEncoding.EncodeToString(value), nil
 }
@@ -701,6 +1531,11 @@ func (m *MachineScope) GetVMImage(ctx context.Context) (*infrav1.Image, error) {
 		return m.AzureMachine.Spec.Image, nil
 	}
 
+	if lookup := m.AzureMachine.Spec.ImageLookup; lookup != nil {
+		log.V(2).Info("Looking up image for machine in compute gallery", "machine", m.AzureMachine.GetName(), "gallery", lookup.Gallery, "variant", m.AzureMachine.Spec.ImageVariant)
+		return virtualmachineimages.LookupImage(lookup, m.AzureMachine.Spec.ImageVariant, ptr.Deref(m.Machine.Spec.Version, ""))
+	}
+
 	svc, err := virtualmachineimages.New(m)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create virtualmachineimages service")
@@ -759,6 +1594,7 @@ func (m *MachineScope) DeleteLongRunningOperationState(name, service, futureType
 
 // UpdateDeleteStatus updates a condition on the AzureMachine status after a DELETE operation.
 func (m *MachineScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(m.AzureMachine, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkFalse(m.AzureMachine, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
@@ -767,10 +1603,12 @@ func (m *MachineScope) UpdateDeleteStatus(condition clusterv1.ConditionType, ser
 	default:
 		conditions.MarkFalse(m.AzureMachine, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
 	}
+	recordConditionChange(m.AzureMachine, before, condition, "")
 }
 
 // UpdatePutStatus updates a condition on the AzureMachine status after a PUT operation.
 func (m *MachineScope) UpdatePutStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(m.AzureMachine, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkTrue(m.AzureMachine, condition)
@@ -779,10 +1617,12 @@ func (m *MachineScope) UpdatePutStatus(condition clusterv1.ConditionType, servic
 	default:
 		conditions.MarkFalse(m.AzureMachine, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
 	}
+	recordConditionChange(m.AzureMachine, before, condition, fmt.Sprintf("%s successfully created or updated", service))
 }
 
 // UpdatePatchStatus updates a condition on the AzureMachine status after a PATCH operation.
 func (m *MachineScope) UpdatePatchStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(m.AzureMachine, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkTrue(m.AzureMachine, condition)
@@ -791,4 +1631,5 @@ func (m *MachineScope) UpdatePatchStatus(condition clusterv1.ConditionType, serv
 	default:
 		conditions.MarkFalse(m.AzureMachine, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
 	}
+	recordConditionChange(m.AzureMachine, before, condition, fmt.Sprintf("%s successfully updated", service))
 }
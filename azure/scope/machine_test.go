@@ -18,23 +18,31 @@ package scope
 
 import (
 	"context"
+	"encoding/base64"
 	"reflect"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/imagecaptures"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/noderoutes"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
@@ -42,6 +50,8 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages/mock_virtualmachineimages"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestMachineScope_Name(t *testing.T) {
@@ -241,6 +251,68 @@ func TestMachineScope_ProviderID(t *testing.T) {
 	}
 }
 
+func TestMachineScope_AuxiliaryTenantIDs(t *testing.T) {
+	sharedGalleryImage := func(tenantID *string) *infrav1.Image {
+		return &infrav1.Image{
+			SharedGallery: &infrav1.AzureSharedGalleryImage{
+				SubscriptionID: "456",
+				ResourceGroup:  "images-rg",
+				Gallery:        "gallery",
+				Name:           "image",
+				Version:        "1.0.0",
+				TenantID:       tenantID,
+			},
+		}
+	}
+	tests := []struct {
+		name  string
+		image *infrav1.Image
+		want  []string
+	}{
+		{
+			name:  "default image",
+			image: nil,
+			want:  nil,
+		},
+		{
+			name:  "shared gallery image in the tenant of the identity",
+			image: sharedGalleryImage(nil),
+			want:  nil,
+		},
+		{
+			name:  "shared gallery image with the tenant of the identity",
+			image: sharedGalleryImage(ptr.To("00000000-0000-0000-0000-00000000000A")),
+			want:  nil,
+		},
+		{
+			name:  "shared gallery image in another tenant",
+			image: sharedGalleryImage(ptr.To("00000000-0000-0000-0000-00000000000b")),
+			want:  []string{"00000000-0000-0000-0000-00000000000b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						Image: tt.image,
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Values: map[string]string{
+								auth.TenantID: "00000000-0000-0000-0000-00000000000a",
+							},
+						},
+					},
+				},
+			}
+			g.Expect(machineScope.AuxiliaryTenantIDs()).To(Equal(tt.want))
+		})
+	}
+}
 func TestMachineScope_PublicIPSpecs(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -272,6 +344,7 @@ func TestMachineScope_PublicIPSpecs(t *testing.T) {
 						AllocatePublicIP: true,
 					},
 				},
+				Machine: &clusterv1.Machine{},
 				ClusterScoper: &ClusterScope{
 					Cluster: &clusterv1.Cluster{
 						ObjectMeta: metav1.ObjectMeta{
@@ -323,6 +396,79 @@ func TestMachineScope_PublicIPSpecs(t *testing.T) {
 					AdditionalTags: infrav1.Tags{
 						"Name": "my-publicip-ipv6",
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine":            "machine-name",
+					},
+				},
+			},
+		},
+		{
+			name: "pins the node PublicIPSpec to the availability zone of the machine",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						AllocatePublicIP: true,
+					},
+				},
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{
+						FailureDomain: ptr.To("failure-domain-id-2"),
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "my-cluster",
+							// Note: m.ClusterName() takes the value from the Cluster object, not the AzureCluster object
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "my-cluster",
+						},
+						Status: infrav1.AzureClusterStatus{
+							FailureDomains: map[string]clusterv1.FailureDomainSpec{
+								"failure-domain-id-1": {},
+								"failure-domain-id-2": {},
+								"failure-domain-id-3": {},
+							},
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								SubscriptionID: "123",
+								Location:       "centralIndia",
+								AdditionalTags: infrav1.Tags{
+									"Name": "my-publicip-ipv6",
+									"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+								},
+							},
+							NetworkSpec: infrav1.NetworkSpec{
+								APIServerLB: infrav1.LoadBalancerSpec{
+									LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+										Type: infrav1.Internal,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&publicips.PublicIPSpec{
+					Name:           "pip-machine-name",
+					ResourceGroup:  "my-rg",
+					DNSName:        "",
+					IsIPv6:         false,
+					ClusterName:    "my-cluster",
+					Location:       "centralIndia",
+					FailureDomains: []*string{ptr.To("failure-domain-id-2")},
+					AdditionalTags: infrav1.Tags{
+						"Name": "my-publicip-ipv6",
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine":            "machine-name",
 					},
 				},
 			},
@@ -407,6 +553,38 @@ func TestMachineScope_InboundNatSpecs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "returns empty when SSH NAT rules are disabled",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							clusterv1.MachineControlPlaneLabel: "",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							NetworkSpec: infrav1.NetworkSpec{
+								APIServerLB: infrav1.LoadBalancerSpec{
+									Name: "foo-loadbalancer",
+									LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+										DisableSSHNATRules: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -419,6 +597,131 @@ func TestMachineScope_InboundNatSpecs(t *testing.T) {
 	}
 }
 
+func TestMachineScope_SetSSHFrontendPort(t *testing.T) {
+	tests := []struct {
+		name   string
+		lbType infrav1.LBType
+		want   *infrav1.SSHEndpoint
+	}{
+		{
+			name:   "sets the SSH endpoint of a public API server load balancer",
+			lbType: infrav1.Public,
+			want:   &infrav1.SSHEndpoint{Host: "my-cluster.eastus.cloudapp.azure.com", Port: 2201},
+		},
+		{
+			name:   "does not set the SSH endpoint of an internal API server load balancer",
+			lbType: infrav1.Internal,
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			machineScope := MachineScope{
+				AzureMachine: &infrav1.AzureMachine{},
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							NetworkSpec: infrav1.NetworkSpec{
+								APIServerLB: infrav1.LoadBalancerSpec{
+									FrontendIPs: []infrav1.FrontendIP{
+										{
+											Name:     "foo-frontend-ip",
+											PublicIP: &infrav1.PublicIPSpec{DNSName: "my-cluster.eastus.cloudapp.azure.com"},
+										},
+									},
+									LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+										Type: tt.lbType,
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			machineScope.SetSSHFrontendPort(2201)
+			g.Expect(machineScope.AzureMachine.Status.SSHEndpoint).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestWithClusterIdentity(t *testing.T) {
+	clusterIdentity := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-cluster-identity"
+	otherIdentity := "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/other"
+	tests := []struct {
+		name                       string
+		managedIdentity            *infrav1.ClusterManagedIdentity
+		identity                   infrav1.VMIdentity
+		userAssignedIdentities     []infrav1.UserAssignedIdentity
+		wantIdentity               infrav1.VMIdentity
+		wantUserAssignedIdentities []infrav1.UserAssignedIdentity
+	}{
+		{
+			name:         "cluster without managed identity",
+			identity:     infrav1.VMIdentityNone,
+			wantIdentity: infrav1.VMIdentityNone,
+		},
+		{
+			name:                       "machine without identity",
+			managedIdentity:            &infrav1.ClusterManagedIdentity{},
+			identity:                   infrav1.VMIdentityNone,
+			wantIdentity:               infrav1.VMIdentityUserAssigned,
+			wantUserAssignedIdentities: []infrav1.UserAssignedIdentity{{ProviderID: clusterIdentity}},
+		},
+		{
+			name:                       "machine with user-assigned identities",
+			managedIdentity:            &infrav1.ClusterManagedIdentity{},
+			identity:                   infrav1.VMIdentityUserAssigned,
+			userAssignedIdentities:     []infrav1.UserAssignedIdentity{{ProviderID: otherIdentity}},
+			wantIdentity:               infrav1.VMIdentityUserAssigned,
+			wantUserAssignedIdentities: []infrav1.UserAssignedIdentity{{ProviderID: otherIdentity}, {ProviderID: clusterIdentity}},
+		},
+		{
+			name:                       "machine already using the cluster identity",
+			managedIdentity:            &infrav1.ClusterManagedIdentity{},
+			identity:                   infrav1.VMIdentityUserAssigned,
+			userAssignedIdentities:     []infrav1.UserAssignedIdentity{{ProviderID: "azure://" + clusterIdentity}},
+			wantIdentity:               infrav1.VMIdentityUserAssigned,
+			wantUserAssignedIdentities: []infrav1.UserAssignedIdentity{{ProviderID: "azure://" + clusterIdentity}},
+		},
+		{
+			name:            "machine with system-assigned identity",
+			managedIdentity: &infrav1.ClusterManagedIdentity{},
+			identity:        infrav1.VMIdentitySystemAssigned,
+			wantIdentity:    infrav1.VMIdentitySystemAssigned,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterScope := &ClusterScope{
+				AzureClients: AzureClients{
+					EnvironmentSettings: auth.EnvironmentSettings{
+						Values: map[string]string{
+							auth.SubscriptionID: "123",
+						},
+					},
+				},
+				Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							ManagedIdentity: tt.managedIdentity,
+						},
+					},
+				},
+			}
+			identity, userAssignedIdentities := withClusterIdentity(clusterScope, tt.identity, tt.userAssignedIdentities)
+			g.Expect(identity).To(Equal(tt.wantIdentity))
+			g.Expect(userAssignedIdentities).To(Equal(tt.wantUserAssignedIdentities))
+		})
+	}
+}
+
 func TestMachineScope_RoleAssignmentSpecs(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -537,6 +840,61 @@ func TestMachineScope_RoleAssignmentSpecs(t *testing.T) {
 	}
 }
 
+func TestMachineScope_TagsSpecs(t *testing.T) {
+	g := NewWithT(t)
+
+	machineScope := MachineScope{
+		Machine: &clusterv1.Machine{},
+		AzureMachine: &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "machine-name",
+			},
+			Spec: infrav1.AzureMachineSpec{
+				AdditionalTags: infrav1.Tags{"foo": "bar"},
+				NetworkInterfaces: []infrav1.NetworkInterface{
+					{SubnetName: "subnet1"},
+					{SubnetName: "subnet2"},
+				},
+			},
+		},
+		ClusterScoper: &ClusterScope{
+			AzureClients: AzureClients{
+				EnvironmentSettings: auth.EnvironmentSettings{
+					Values: map[string]string{
+						auth.SubscriptionID: "123",
+					},
+				},
+			},
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+				},
+			},
+		},
+	}
+
+	specs := machineScope.TagsSpecs()
+	g.Expect(specs).To(HaveLen(4))
+	g.Expect(specs[0].Scope).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name"))
+	g.Expect(specs[0].Annotation).To(Equal(azure.VMTagsLastAppliedAnnotation))
+	g.Expect(specs[1].Scope).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/machine-name_OSDisk"))
+	g.Expect(specs[1].Annotation).To(Equal(azure.OSDiskTagsLastAppliedAnnotation))
+	g.Expect(specs[1].Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_cluster", "owned"))
+	g.Expect(specs[2].Scope).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/machine-name-nic-0"))
+	g.Expect(specs[2].Annotation).To(Equal("sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-nic-0"))
+	g.Expect(specs[3].Scope).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/machine-name-nic-1"))
+	g.Expect(specs[3].Annotation).To(Equal("sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-nic-1"))
+	for _, spec := range specs {
+		g.Expect(spec.Tags).To(HaveKeyWithValue("foo", "bar"))
+		g.Expect(spec.Tags).To(HaveKeyWithValue(infrav1.NameAzureClusterAPIMachine, "machine-name"))
+	}
+}
+
 func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -632,7 +990,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 			want: []azure.ResourceSpecGetter{},
 		},
 		{
-			name: "If OS type is Windows and cloud is AzurePublicCloud, it returns ExtensionSpec",
+			name: "If Azure Disk Encryption is enabled, it returns the Azure Disk Encryption extension",
 			machineScope: MachineScope{
 				Machine: &clusterv1.Machine{},
 				AzureMachine: &infrav1.AzureMachine{
@@ -641,7 +999,12 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					},
 					Spec: infrav1.AzureMachineSpec{
 						OSDisk: infrav1.OSDisk{
-							OSType: "Windows",
+							OSType: "Linux",
+						},
+						AzureDiskEncryption: &infrav1.AzureDiskEncryption{
+							KeyVaultID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+							KeyVaultURL:         "https://my-vault.vault.azure.net/",
+							KeyEncryptionKeyURL: "https://my-vault.vault.azure.net/keys/my-key/0123456789abcdef0123456789abcdef",
 						},
 					},
 				},
@@ -649,7 +1012,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					AzureClients: AzureClients{
 						EnvironmentSettings: auth.EnvironmentSettings{
 							Environment: azureautorest.Environment{
-								Name: azureautorest.PublicCloud.Name,
+								Name: azureautorest.USGovernmentCloud.Name,
 							},
 						},
 					},
@@ -669,12 +1032,18 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 			want: []azure.ResourceSpecGetter{
 				&vmextensions.VMExtensionSpec{
 					ExtensionSpec: azure.ExtensionSpec{
-						Name:      "CAPZ.Windows.Bootstrapping",
+						Name:      "AzureDiskEncryptionForLinux",
 						VMName:    "machine-name",
-						Publisher: "Microsoft.Azure.ContainerUpstream",
-						Version:   "1.0",
-						ProtectedSettings: map[string]string{
-							"commandToExecute": azure.WindowsBootstrapExtensionCommand,
+						Publisher: "Microsoft.Azure.Security",
+						Version:   "1.1",
+						Settings: map[string]string{
+							"EncryptionOperation":    "EnableEncryption",
+							"KeyVaultURL":            "https://my-vault.vault.azure.net/",
+							"KeyVaultResourceId":     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+							"KeyEncryptionKeyURL":    "https://my-vault.vault.azure.net/keys/my-key/0123456789abcdef0123456789abcdef",
+							"KekVaultResourceId":     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+							"KeyEncryptionAlgorithm": "RSA-OAEP",
+							"VolumeType":             "All",
 						},
 					},
 					ResourceGroup: "my-rg",
@@ -683,7 +1052,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 			},
 		},
 		{
-			name: "If OS type is Windows and cloud is not AzurePublicCloud, it returns empty",
+			name: "If OS type is Windows and cloud is AzurePublicCloud, it returns ExtensionSpec",
 			machineScope: MachineScope{
 				Machine: &clusterv1.Machine{},
 				AzureMachine: &infrav1.AzureMachine{
@@ -700,7 +1069,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					AzureClients: AzureClients{
 						EnvironmentSettings: auth.EnvironmentSettings{
 							Environment: azureautorest.Environment{
-								Name: azureautorest.USGovernmentCloud.Name,
+								Name: azureautorest.PublicCloud.Name,
 							},
 						},
 					},
@@ -717,10 +1086,24 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					VMSKU: resourceskus.SKU{},
 				},
 			},
-			want: []azure.ResourceSpecGetter{},
+			want: []azure.ResourceSpecGetter{
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:      "CAPZ.Windows.Bootstrapping",
+						VMName:    "machine-name",
+						Publisher: "Microsoft.Azure.ContainerUpstream",
+						Version:   "1.0",
+						ProtectedSettings: map[string]string{
+							"commandToExecute": azure.WindowsBootstrapExtensionCommand,
+						},
+					},
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+			},
 		},
 		{
-			name: "If OS type is not Linux or Windows and cloud is AzurePublicCloud, it returns empty",
+			name: "If OS type is Windows and cloud is not AzurePublicCloud, it returns empty",
 			machineScope: MachineScope{
 				Machine: &clusterv1.Machine{},
 				AzureMachine: &infrav1.AzureMachine{
@@ -729,7 +1112,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					},
 					Spec: infrav1.AzureMachineSpec{
 						OSDisk: infrav1.OSDisk{
-							OSType: "Other",
+							OSType: "Windows",
 						},
 					},
 				},
@@ -737,7 +1120,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					AzureClients: AzureClients{
 						EnvironmentSettings: auth.EnvironmentSettings{
 							Environment: azureautorest.Environment{
-								Name: azureautorest.PublicCloud.Name,
+								Name: azureautorest.USGovernmentCloud.Name,
 							},
 						},
 					},
@@ -757,7 +1140,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 			want: []azure.ResourceSpecGetter{},
 		},
 		{
-			name: "If OS type is not Windows or Linux and cloud is not AzurePublicCloud, it returns empty",
+			name: "If OS type is not Linux or Windows and cloud is AzurePublicCloud, it returns empty",
 			machineScope: MachineScope{
 				Machine: &clusterv1.Machine{},
 				AzureMachine: &infrav1.AzureMachine{
@@ -774,7 +1157,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					AzureClients: AzureClients{
 						EnvironmentSettings: auth.EnvironmentSettings{
 							Environment: azureautorest.Environment{
-								Name: azureautorest.USGovernmentCloud.Name,
+								Name: azureautorest.PublicCloud.Name,
 							},
 						},
 					},
@@ -794,7 +1177,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 			want: []azure.ResourceSpecGetter{},
 		},
 		{
-			name: "If a custom VM extension is specified, it returns the custom VM extension",
+			name: "If OS type is not Windows or Linux and cloud is not AzurePublicCloud, it returns empty",
 			machineScope: MachineScope{
 				Machine: &clusterv1.Machine{},
 				AzureMachine: &infrav1.AzureMachine{
@@ -803,7 +1186,44 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					},
 					Spec: infrav1.AzureMachineSpec{
 						OSDisk: infrav1.OSDisk{
-							OSType: "Linux",
+							OSType: "Other",
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.USGovernmentCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+						},
+					},
+				},
+				cache: &MachineCache{
+					VMSKU: resourceskus.SKU{},
+				},
+			},
+			want: []azure.ResourceSpecGetter{},
+		},
+		{
+			name: "If a custom VM extension is specified, it returns the custom VM extension",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: "Linux",
 						},
 						VMExtensions: []infrav1.VMExtension{
 							{
@@ -873,6 +1293,191 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "If the bootstrap data is a shell script, it returns the Custom Script extension running it",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: "Linux",
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.PublicCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+						},
+					},
+				},
+				cache: &MachineCache{
+					BootstrapData:         "IyEvYmluL3NoCg==",
+					BootstrapDataDelivery: extensionDelivery,
+					VMSKU:                 resourceskus.SKU{},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:      "CustomScript",
+						VMName:    "machine-name",
+						Publisher: "Microsoft.Azure.Extensions",
+						Version:   "2.1",
+						ProtectedSettings: map[string]string{
+							"script": "IyEvYmluL3NoCg==",
+						},
+					},
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+			},
+		},
+		{
+			name: "If SSH key rotation is enabled on a provisioned Linux machine, it returns the VMAccess extension authorizing its SSH key and the cluster SSH key",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+						Annotations: map[string]string{
+							infrav1.SSHKeyRotationAnnotation: "true",
+						},
+					},
+					Spec: infrav1.AzureMachineSpec{
+						ProviderID:   ptr.To("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name"),
+						SSHPublicKey: base64.StdEncoding.EncodeToString([]byte("ssh-rsa new-key")),
+						OSDisk: infrav1.OSDisk{
+							OSType: "Linux",
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.PublicCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+						},
+					},
+				},
+				cache: &MachineCache{
+					ClusterSSHKeyData: base64.StdEncoding.EncodeToString([]byte("ssh-rsa cluster-key")),
+					VMSKU:             resourceskus.SKU{},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:      "CAPZ.Linux.Bootstrapping",
+						VMName:    "machine-name",
+						Publisher: "Microsoft.Azure.ContainerUpstream",
+						Version:   "1.0",
+						ProtectedSettings: map[string]string{
+							"commandToExecute": azure.LinuxBootstrapExtensionCommand,
+						},
+					},
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: *azure.GetSSHKeyRotationVMExtension("machine-name", azure.DefaultUserName, "ssh-rsa new-key\nssh-rsa cluster-key"),
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+			},
+		},
+		{
+			name: "If SSH key rotation is enabled on a provisioned Linux machine, it also authorizes its additional SSH keys and the cluster SSH keys of its role once",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+						Annotations: map[string]string{
+							infrav1.SSHKeyRotationAnnotation: "true",
+						},
+					},
+					Spec: infrav1.AzureMachineSpec{
+						ProviderID:              ptr.To("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name"),
+						SSHPublicKey:            base64.StdEncoding.EncodeToString([]byte("ssh-rsa new-key")),
+						AdditionalSSHPublicKeys: []string{base64.StdEncoding.EncodeToString([]byte("ssh-rsa team-key"))},
+						OSDisk: infrav1.OSDisk{
+							OSType: "Linux",
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.PublicCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+								SSHPublicKeys: &infrav1.SSHPublicKeysSpec{
+									ControlPlane: []string{base64.StdEncoding.EncodeToString([]byte("ssh-rsa control-plane-key"))},
+									Nodes: []string{
+										base64.StdEncoding.EncodeToString([]byte("ssh-rsa node-key")),
+										base64.StdEncoding.EncodeToString([]byte("ssh-rsa team-key")),
+									},
+								},
+							},
+						},
+					},
+				},
+				cache: &MachineCache{
+					ClusterSSHKeyData: base64.StdEncoding.EncodeToString([]byte("ssh-rsa cluster-key")),
+					VMSKU:             resourceskus.SKU{},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:      "CAPZ.Linux.Bootstrapping",
+						VMName:    "machine-name",
+						Publisher: "Microsoft.Azure.ContainerUpstream",
+						Version:   "1.0",
+						ProtectedSettings: map[string]string{
+							"commandToExecute": azure.LinuxBootstrapExtensionCommand,
+						},
+					},
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: *azure.GetSSHKeyRotationVMExtension("machine-name", azure.DefaultUserName, "ssh-rsa new-key\nssh-rsa team-key\nssh-rsa node-key\nssh-rsa cluster-key"),
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -983,6 +1588,75 @@ func TestMachineScope_Subnet(t *testing.T) {
 	}
 }
 
+func TestMachineScope_NodeRouteSpecs(t *testing.T) {
+	subnets := infrav1.Subnets{
+		{
+			SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetControlPlane, Name: "cp-subnet"},
+		},
+		{
+			SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode, Name: "node-subnet"},
+			RouteTable:      infrav1.RouteTable{Name: "node-rt"},
+		},
+		{
+			SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode, Name: "other-node-subnet"},
+			RouteTable:      infrav1.RouteTable{Name: "node-rt"},
+		},
+		{
+			SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetNode, Name: "gpu-subnet"},
+			RouteTable:      infrav1.RouteTable{Name: "gpu-rt"},
+		},
+	}
+	tests := []struct {
+		name       string
+		nodeRoutes *infrav1.NodeRoutesSpec
+		want       []azure.ResourceSpecGetter
+	}{
+		{
+			name:       "returns nil if the routes are left to the cloud provider",
+			nodeRoutes: &infrav1.NodeRoutesSpec{Mode: infrav1.NodeRoutesModeCloudProvider},
+			want:       nil,
+		},
+		{
+			name:       "returns nil if the routes are not configured",
+			nodeRoutes: nil,
+			want:       nil,
+		},
+		{
+			name:       "returns a route in the route table of each node subnet",
+			nodeRoutes: &infrav1.NodeRoutesSpec{Mode: infrav1.NodeRoutesModeManaged},
+			want: []azure.ResourceSpecGetter{
+				&noderoutes.RouteSpec{Name: "machine-name", ResourceGroup: "vnet-rg", RouteTableName: "node-rt"},
+				&noderoutes.RouteSpec{Name: "machine-name", ResourceGroup: "vnet-rg", RouteTableName: "gpu-rt"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							NetworkSpec: infrav1.NetworkSpec{
+								Vnet:       infrav1.VnetSpec{ResourceGroup: "vnet-rg", Name: "vnet"},
+								Subnets:    subnets,
+								NodeRoutes: tt.nodeRoutes,
+							},
+						},
+					},
+				},
+			}
+			g.Expect(machineScope.NodeRouteSpecs()).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestMachineScope_AvailabilityZone(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1040,26 +1714,172 @@ func TestMachineScope_AvailabilityZone(t *testing.T) {
 			},
 			want: "dummy-failure-domain-from-azuremachine-spec",
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.machineScope.AvailabilityZone(); got != tt.want {
-				t.Errorf("AvailabilityZone() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestMachineScope_Namespace(t *testing.T) {
-	tests := []struct {
-		name         string
-		machineScope MachineScope
-		want         string
-	}{
 		{
-			name: "returns azure machine namespace",
+			name: "returns the zone substituted for the failure domain",
 			machineScope: MachineScope{
-				AzureMachine: &infrav1.AzureMachine{
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{
+						FailureDomain: ptr.To("1"),
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Status: infrav1.AzureMachineStatus{
+						ZoneSubstitution: &infrav1.ZoneSubstitution{FailureDomain: "1", Zone: "2"},
+					},
+				},
+			},
+			want: "2",
+		},
+		{
+			name: "ignores a zone substituted for another failure domain",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{
+						FailureDomain: ptr.To("3"),
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Status: infrav1.AzureMachineStatus{
+						ZoneSubstitution: &infrav1.ZoneSubstitution{FailureDomain: "1", Zone: "2"},
+					},
+				},
+			},
+			want: "3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.machineScope.AvailabilityZone(); got != tt.want {
+				t.Errorf("AvailabilityZone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMachineScope_SubstituteZone(t *testing.T) {
+	// Standard_D4s_v3 is offered in zones 1 to 4 of westus2, but restricted in zone 3 for the subscription.
+	sku := resourceskus.SKU{
+		Name: ptr.To("Standard_D4s_v3"),
+		LocationInfo: []*armcompute.ResourceSKULocationInfo{
+			{
+				Location: ptr.To("westus2"),
+				Zones:    []*string{ptr.To("1"), ptr.To("2"), ptr.To("3"), ptr.To("4")},
+			},
+		},
+		Restrictions: []*armcompute.ResourceSKURestrictions{
+			{
+				Type:            ptr.To(armcompute.ResourceSKURestrictionsTypeZone),
+				RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{Zones: []*string{ptr.To("3")}},
+			},
+		},
+	}
+	tests := []struct {
+		name              string
+		allowZoneFallback bool
+		providerID        *string
+		substitution      *infrav1.ZoneSubstitution
+		want              string
+	}{
+		{
+			name: "keeps the zone when zone fallback is not allowed",
+		},
+		{
+			name:              "keeps the zone of a VM already created",
+			allowZoneFallback: true,
+			providerID:        ptr.To("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name"),
+		},
+		{
+			name:              "substitutes the first other failure domain",
+			allowZoneFallback: true,
+			want:              "2",
+		},
+		{
+			name:              "substitutes the next failure domain where the VM size is available",
+			allowZoneFallback: true,
+			substitution:      &infrav1.ZoneSubstitution{FailureDomain: "1", Zone: "2"},
+			want:              "4",
+		},
+		{
+			name:              "keeps the zone when all the failure domains were tried",
+			allowZoneFallback: true,
+			substitution:      &infrav1.ZoneSubstitution{FailureDomain: "1", Zone: "4"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus2",
+							},
+						},
+						Status: infrav1.AzureClusterStatus{
+							FailureDomains: clusterv1.FailureDomains{
+								"1": clusterv1.FailureDomainSpec{ControlPlane: true},
+								"2": clusterv1.FailureDomainSpec{ControlPlane: true},
+								"3": clusterv1.FailureDomainSpec{ControlPlane: true},
+								"4": clusterv1.FailureDomainSpec{ControlPlane: true},
+							},
+						},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{
+						FailureDomain: ptr.To("1"),
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						ProviderID:        tt.providerID,
+						AllowZoneFallback: tt.allowZoneFallback,
+					},
+					Status: infrav1.AzureMachineStatus{
+						ZoneSubstitution: tt.substitution,
+					},
+				},
+				cache: &MachineCache{
+					VMSKU: sku,
+				},
+			}
+			zoneBefore := machineScope.AvailabilityZone()
+			g.Expect(machineScope.SubstituteZone("VM size Standard_D4s_v3 is not available")).To(Equal(tt.want))
+			if tt.want == "" {
+				g.Expect(machineScope.AvailabilityZone()).To(Equal(zoneBefore))
+				return
+			}
+			g.Expect(machineScope.AvailabilityZone()).To(Equal(tt.want))
+			g.Expect(machineScope.AzureMachine.Status.ZoneSubstitution).To(Equal(&infrav1.ZoneSubstitution{
+				FailureDomain: "1",
+				Zone:          tt.want,
+				Reason:        "VM size Standard_D4s_v3 is not available",
+			}))
+		})
+	}
+}
+
+func TestMachineScope_Namespace(t *testing.T) {
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		want         string
+	}{
+		{
+			name: "returns azure machine namespace",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "machine-name",
 						Namespace: "foo",
@@ -1348,6 +2168,55 @@ func TestMachineScope_AvailabilitySet(t *testing.T) {
 	}
 }
 
+func TestMachineScope_SetVMStatusFailureDomain(t *testing.T) {
+	tests := []struct {
+		name              string
+		failureDomains    clusterv1.FailureDomains
+		vm                *infrav1.VMStatus
+		wantFailureDomain string
+	}{
+		{
+			name:              "fault domain of a machine in an availability set",
+			vm:                &infrav1.VMStatus{PlatformFaultDomain: ptr.To[int32](2)},
+			wantFailureDomain: "2",
+		},
+		{
+			name:              "fault domain of a zonal machine",
+			failureDomains:    clusterv1.FailureDomains{"1": clusterv1.FailureDomainSpec{}},
+			vm:                &infrav1.VMStatus{Zone: "1", PlatformFaultDomain: ptr.To[int32](0)},
+			wantFailureDomain: "",
+		},
+		{
+			name:              "unknown fault domain",
+			vm:                &infrav1.VMStatus{},
+			wantFailureDomain: "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+					AzureCluster: &infrav1.AzureCluster{
+						Status: infrav1.AzureClusterStatus{FailureDomains: tt.failureDomains},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{clusterv1.MachineDeploymentNameLabel: "md"},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{},
+			}
+			machineScope.SetVMStatus(tt.vm)
+			g.Expect(machineScope.AzureMachine.Status.VM).To(Equal(tt.vm))
+			g.Expect(machineScope.AzureMachine.Status.FailureDomain).To(Equal(tt.wantFailureDomain))
+		})
+	}
+}
+
 func TestMachineScope_VMState(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1790,13 +2659,14 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					SKU:                       nil,
 					ClusterName:               "cluster",
 					AdditionalTags: infrav1.Tags{
-						"kubernetes.io_cluster_cluster": "owned",
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
 					},
 				},
 			},
 		},
 		{
-			name: "Node Machine with no NAT gateway and no public IP address and SKU is in machine cache",
+			name: "Node Machine with private IP addresses claimed from IP address pools",
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
@@ -1860,10 +2730,11 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						ProviderID: ptr.To("azure:///subscriptions/1234-5678/resourceGroups/my-cluster/providers/Microsoft.Compute/virtualMachines/machine-name"),
 						NetworkInterfaces: []infrav1.NetworkInterface{{
 							SubnetName:       "subnet1",
-							PrivateIPConfigs: 1,
+							PrivateIPConfigs: 2,
 						}},
 					},
 				},
+				nicAddresses: [][]string{{"10.0.0.10", "10.0.0.11"}},
 				Machine: &clusterv1.Machine{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:   "machine",
@@ -1872,11 +2743,6 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						},
 					},
 				},
-				cache: &MachineCache{
-					VMSKU: resourceskus.SKU{
-						Name: ptr.To("Standard_D2v2"),
-					},
-				},
 			},
 			want: []azure.ResourceSpecGetter{
 				&networkinterfaces.NICSpec{
@@ -1886,7 +2752,8 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					SubscriptionID:            "123",
 					MachineName:               "machine-name",
 					SubnetName:                "subnet1",
-					IPConfigs:                 []networkinterfaces.IPConfig{{}},
+					StaticIPAddress:           "10.0.0.10",
+					IPConfigs:                 []networkinterfaces.IPConfig{{}, {PrivateIP: ptr.To("10.0.0.11")}},
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
 					PublicLBName:              "outbound-lb",
@@ -1899,18 +2766,17 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					DNSServers:                nil,
 					IPv6Enabled:               false,
 					EnableIPForwarding:        false,
-					SKU: &resourceskus.SKU{
-						Name: ptr.To("Standard_D2v2"),
-					},
-					ClusterName: "cluster",
+					SKU:                       nil,
+					ClusterName:               "cluster",
 					AdditionalTags: infrav1.Tags{
-						"kubernetes.io_cluster_cluster": "owned",
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
 					},
 				},
 			},
 		},
 		{
-			name: "Node Machine with NAT gateway",
+			name: "Node Machine with a node internal load balancer",
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
@@ -1954,15 +2820,22 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 											Role: infrav1.SubnetNode,
 											Name: "subnet1",
 										},
-										NatGateway: infrav1.NatGateway{
-											NatGatewayClassSpec: infrav1.NatGatewayClassSpec{
-												Name: "natgw",
-											},
-										},
 									},
 								},
 								NodeOutboundLB: &infrav1.LoadBalancerSpec{
 									Name: "outbound-lb",
+									BackendPool: infrav1.BackendPool{
+										Name: "outbound-lb-outboundBackendPool",
+									},
+								},
+								NodeInternalLB: &infrav1.NodeInternalLBSpec{
+									LoadBalancerSpec: infrav1.LoadBalancerSpec{
+										Name: "node-internal-lb",
+										BackendPool: infrav1.BackendPool{
+											Name: "node-internal-lb-backendPool",
+										},
+									},
+									SubnetName: "subnet1",
 								},
 							},
 						},
@@ -2000,11 +2873,11 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					IPConfigs:                 []networkinterfaces.IPConfig{{}},
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
-					PublicLBName:              "",
-					PublicLBAddressPoolName:   "",
+					PublicLBName:              "outbound-lb",
+					PublicLBAddressPoolName:   "outbound-lb-outboundBackendPool",
 					PublicLBNATRuleName:       "",
-					InternalLBName:            "",
-					InternalLBAddressPoolName: "",
+					InternalLBName:            "node-internal-lb",
+					InternalLBAddressPoolName: "node-internal-lb-backendPool",
 					PublicIPName:              "",
 					AcceleratedNetworking:     nil,
 					DNSServers:                nil,
@@ -2013,13 +2886,14 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					SKU:                       nil,
 					ClusterName:               "cluster",
 					AdditionalTags: infrav1.Tags{
-						"kubernetes.io_cluster_cluster": "owned",
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
 					},
 				},
 			},
 		},
 		{
-			name: "Node Machine with public IP address",
+			name: "Node Machine with no NAT gateway and no public IP address and SKU is in machine cache",
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
@@ -2067,6 +2941,9 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 								},
 								NodeOutboundLB: &infrav1.LoadBalancerSpec{
 									Name: "outbound-lb",
+									BackendPool: infrav1.BackendPool{
+										Name: "outbound-lb-outboundBackendPool",
+									},
 								},
 							},
 						},
@@ -2082,7 +2959,6 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 							SubnetName:       "subnet1",
 							PrivateIPConfigs: 1,
 						}},
-						AllocatePublicIP: true,
 					},
 				},
 				Machine: &clusterv1.Machine{
@@ -2093,6 +2969,11 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						},
 					},
 				},
+				cache: &MachineCache{
+					VMSKU: resourceskus.SKU{
+						Name: ptr.To("Standard_D2v2"),
+					},
+				},
 			},
 			want: []azure.ResourceSpecGetter{
 				&networkinterfaces.NICSpec{
@@ -2105,26 +2986,29 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					IPConfigs:                 []networkinterfaces.IPConfig{{}},
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
-					PublicLBName:              "",
-					PublicLBAddressPoolName:   "",
+					PublicLBName:              "outbound-lb",
+					PublicLBAddressPoolName:   "outbound-lb-outboundBackendPool",
 					PublicLBNATRuleName:       "",
 					InternalLBName:            "",
 					InternalLBAddressPoolName: "",
-					PublicIPName:              "pip-machine-name",
+					PublicIPName:              "",
 					AcceleratedNetworking:     nil,
 					DNSServers:                nil,
 					IPv6Enabled:               false,
 					EnableIPForwarding:        false,
-					SKU:                       nil,
-					ClusterName:               "cluster",
+					SKU: &resourceskus.SKU{
+						Name: ptr.To("Standard_D2v2"),
+					},
+					ClusterName: "cluster",
 					AdditionalTags: infrav1.Tags{
-						"kubernetes.io_cluster_cluster": "owned",
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
 					},
 				},
 			},
 		},
 		{
-			name: "Control Plane Machine with private LB",
+			name: "Node Machine with NAT gateway",
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
@@ -2168,15 +3052,11 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 											Role: infrav1.SubnetNode,
 											Name: "subnet1",
 										},
-									},
-								},
-								APIServerLB: infrav1.LoadBalancerSpec{
-									Name: "api-lb",
-									LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
-										Type: infrav1.Internal,
-									},
-									BackendPool: infrav1.BackendPool{
-										Name: "api-lb-backendPool",
+										NatGateway: infrav1.NatGateway{
+											NatGatewayClassSpec: infrav1.NatGatewayClassSpec{
+												Name: "natgw",
+											},
+										},
 									},
 								},
 								NodeOutboundLB: &infrav1.LoadBalancerSpec{
@@ -2200,9 +3080,9 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 				},
 				Machine: &clusterv1.Machine{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "machine",
+						Name:   "machine",
 						Labels: map[string]string{
-							clusterv1.MachineControlPlaneLabel: "true",
+							// clusterv1.MachineControlPlaneLabel: "true",
 						},
 					},
 				},
@@ -2221,8 +3101,8 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					PublicLBName:              "",
 					PublicLBAddressPoolName:   "",
 					PublicLBNATRuleName:       "",
-					InternalLBName:            "api-lb",
-					InternalLBAddressPoolName: "api-lb-backendPool",
+					InternalLBName:            "",
+					InternalLBAddressPoolName: "",
 					PublicIPName:              "",
 					AcceleratedNetworking:     nil,
 					DNSServers:                nil,
@@ -2231,13 +3111,14 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					SKU:                       nil,
 					ClusterName:               "cluster",
 					AdditionalTags: infrav1.Tags{
-						"kubernetes.io_cluster_cluster": "owned",
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
 					},
 				},
 			},
 		},
 		{
-			name: "Control Plane Machine with public LB",
+			name: "Node Machine with public IP address",
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
@@ -2283,12 +3164,6 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 										},
 									},
 								},
-								APIServerLB: infrav1.LoadBalancerSpec{
-									Name: "api-lb",
-									BackendPool: infrav1.BackendPool{
-										Name: "api-lb-backendPool",
-									},
-								},
 								NodeOutboundLB: &infrav1.LoadBalancerSpec{
 									Name: "outbound-lb",
 								},
@@ -2306,13 +3181,14 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 							SubnetName:       "subnet1",
 							PrivateIPConfigs: 1,
 						}},
+						AllocatePublicIP: true,
 					},
 				},
 				Machine: &clusterv1.Machine{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "machine",
+						Name:   "machine",
 						Labels: map[string]string{
-							clusterv1.MachineControlPlaneLabel: "true",
+							// clusterv1.MachineControlPlaneLabel: "true",
 						},
 					},
 				},
@@ -2328,12 +3204,12 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					IPConfigs:                 []networkinterfaces.IPConfig{{}},
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
-					PublicLBName:              "api-lb",
-					PublicLBAddressPoolName:   "api-lb-backendPool",
-					PublicLBNATRuleName:       "machine-name",
+					PublicLBName:              "",
+					PublicLBAddressPoolName:   "",
+					PublicLBNATRuleName:       "",
 					InternalLBName:            "",
 					InternalLBAddressPoolName: "",
-					PublicIPName:              "",
+					PublicIPName:              "pip-machine-name",
 					AcceleratedNetworking:     nil,
 					DNSServers:                nil,
 					IPv6Enabled:               false,
@@ -2341,13 +3217,14 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					SKU:                       nil,
 					ClusterName:               "cluster",
 					AdditionalTags: infrav1.Tags{
-						"kubernetes.io_cluster_cluster": "owned",
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
 					},
 				},
 			},
 		},
 		{
-			name: "Control Plane Machine with public LB and Custom DNS Servers",
+			name: "Control Plane Machine with private LB",
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
@@ -2395,6 +3272,9 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 								},
 								APIServerLB: infrav1.LoadBalancerSpec{
 									Name: "api-lb",
+									LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+										Type: infrav1.Internal,
+									},
 									BackendPool: infrav1.BackendPool{
 										Name: "api-lb-backendPool",
 									},
@@ -2416,7 +3296,6 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 							SubnetName:       "subnet1",
 							PrivateIPConfigs: 1,
 						}},
-						DNSServers: []string{"123.123.123.123", "124.124.124.124"},
 					},
 				},
 				Machine: &clusterv1.Machine{
@@ -2439,26 +3318,27 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					IPConfigs:                 []networkinterfaces.IPConfig{{}},
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
-					PublicLBName:              "api-lb",
-					PublicLBAddressPoolName:   "api-lb-backendPool",
-					PublicLBNATRuleName:       "machine-name",
-					InternalLBName:            "",
-					InternalLBAddressPoolName: "",
+					PublicLBName:              "",
+					PublicLBAddressPoolName:   "",
+					PublicLBNATRuleName:       "",
+					InternalLBName:            "api-lb",
+					InternalLBAddressPoolName: "api-lb-backendPool",
 					PublicIPName:              "",
 					AcceleratedNetworking:     nil,
-					DNSServers:                []string{"123.123.123.123", "124.124.124.124"},
+					DNSServers:                nil,
 					IPv6Enabled:               false,
 					EnableIPForwarding:        false,
 					SKU:                       nil,
 					ClusterName:               "cluster",
 					AdditionalTags: infrav1.Tags{
-						"kubernetes.io_cluster_cluster": "owned",
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
 					},
 				},
 			},
 		},
 		{
-			name: "Node Machine with multiple Network Interfaces",
+			name: "Control Plane Machine with public LB",
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
@@ -2506,12 +3386,12 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 								},
 								APIServerLB: infrav1.LoadBalancerSpec{
 									Name: "api-lb",
+									BackendPool: infrav1.BackendPool{
+										Name: "api-lb-backendPool",
+									},
 								},
 								NodeOutboundLB: &infrav1.LoadBalancerSpec{
 									Name: "outbound-lb",
-									BackendPool: infrav1.BackendPool{
-										Name: "outbound-lb-outboundBackendPool",
-									},
 								},
 							},
 						},
@@ -2523,30 +3403,24 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					},
 					Spec: infrav1.AzureMachineSpec{
 						ProviderID: ptr.To("azure:///subscriptions/1234-5678/resourceGroups/my-cluster/providers/Microsoft.Compute/virtualMachines/machine-name"),
-						NetworkInterfaces: []infrav1.NetworkInterface{
-							{
-								SubnetName:            "subnet1",
-								AcceleratedNetworking: ptr.To(true),
-								PrivateIPConfigs:      1,
-							},
-							{
-								SubnetName:            "subnet2",
-								AcceleratedNetworking: ptr.To(true),
-								PrivateIPConfigs:      2,
-							},
-						},
+						NetworkInterfaces: []infrav1.NetworkInterface{{
+							SubnetName:       "subnet1",
+							PrivateIPConfigs: 1,
+						}},
 					},
 				},
 				Machine: &clusterv1.Machine{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:   "machine",
-						Labels: map[string]string{},
+						Name: "machine",
+						Labels: map[string]string{
+							clusterv1.MachineControlPlaneLabel: "true",
+						},
 					},
 				},
 			},
 			want: []azure.ResourceSpecGetter{
 				&networkinterfaces.NICSpec{
-					Name:                      "machine-name-nic-0",
+					Name:                      "machine-name-nic",
 					ResourceGroup:             "my-rg",
 					Location:                  "westus",
 					SubscriptionID:            "123",
@@ -2555,50 +3429,139 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					IPConfigs:                 []networkinterfaces.IPConfig{{}},
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
-					PublicLBName:              "outbound-lb",
-					PublicLBAddressPoolName:   "outbound-lb-outboundBackendPool",
-					PublicLBNATRuleName:       "",
+					PublicLBName:              "api-lb",
+					PublicLBAddressPoolName:   "api-lb-backendPool",
+					PublicLBNATRuleName:       "machine-name",
 					InternalLBName:            "",
 					InternalLBAddressPoolName: "",
 					PublicIPName:              "",
-					AcceleratedNetworking:     ptr.To(true),
+					AcceleratedNetworking:     nil,
+					DNSServers:                nil,
 					IPv6Enabled:               false,
 					EnableIPForwarding:        false,
 					SKU:                       nil,
 					ClusterName:               "cluster",
-					AdditionalTags: map[string]string{
-						"kubernetes.io_cluster_cluster": "owned",
+					AdditionalTags: infrav1.Tags{
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
+					},
+				},
+			},
+		},
+		{
+			name: "Control Plane Machine with public LB and Custom DNS Servers",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Values: map[string]string{
+								auth.SubscriptionID: "123",
+							},
+						},
+					},
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cluster",
+							Namespace: "default",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cluster",
+							Namespace: "default",
+							OwnerReferences: []metav1.OwnerReference{
+								{
+									APIVersion: "cluster.x-k8s.io/v1beta1",
+									Kind:       "Cluster",
+									Name:       "cluster",
+								},
+							},
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+							NetworkSpec: infrav1.NetworkSpec{
+								Vnet: infrav1.VnetSpec{
+									Name:          "vnet1",
+									ResourceGroup: "rg1",
+								},
+								Subnets: []infrav1.SubnetSpec{
+									{
+										SubnetClassSpec: infrav1.SubnetClassSpec{
+											Role: infrav1.SubnetNode,
+											Name: "subnet1",
+										},
+									},
+								},
+								APIServerLB: infrav1.LoadBalancerSpec{
+									Name: "api-lb",
+									BackendPool: infrav1.BackendPool{
+										Name: "api-lb-backendPool",
+									},
+								},
+								NodeOutboundLB: &infrav1.LoadBalancerSpec{
+									Name: "outbound-lb",
+								},
+							},
+						},
 					},
 				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						ProviderID: ptr.To("azure:///subscriptions/1234-5678/resourceGroups/my-cluster/providers/Microsoft.Compute/virtualMachines/machine-name"),
+						NetworkInterfaces: []infrav1.NetworkInterface{{
+							SubnetName:       "subnet1",
+							PrivateIPConfigs: 1,
+						}},
+						DNSServers: []string{"123.123.123.123", "124.124.124.124"},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+						Labels: map[string]string{
+							clusterv1.MachineControlPlaneLabel: "true",
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
 				&networkinterfaces.NICSpec{
-					Name:                      "machine-name-nic-1",
+					Name:                      "machine-name-nic",
 					ResourceGroup:             "my-rg",
 					Location:                  "westus",
 					SubscriptionID:            "123",
 					MachineName:               "machine-name",
-					SubnetName:                "subnet2",
-					IPConfigs:                 []networkinterfaces.IPConfig{{}, {}},
+					SubnetName:                "subnet1",
+					IPConfigs:                 []networkinterfaces.IPConfig{{}},
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
-					PublicLBName:              "",
-					PublicLBAddressPoolName:   "",
-					PublicLBNATRuleName:       "",
+					PublicLBName:              "api-lb",
+					PublicLBAddressPoolName:   "api-lb-backendPool",
+					PublicLBNATRuleName:       "machine-name",
 					InternalLBName:            "",
 					InternalLBAddressPoolName: "",
 					PublicIPName:              "",
-					AcceleratedNetworking:     ptr.To(true),
+					AcceleratedNetworking:     nil,
+					DNSServers:                []string{"123.123.123.123", "124.124.124.124"},
 					IPv6Enabled:               false,
 					EnableIPForwarding:        false,
 					SKU:                       nil,
 					ClusterName:               "cluster",
-					AdditionalTags: map[string]string{
-						"kubernetes.io_cluster_cluster": "owned",
+					AdditionalTags: infrav1.Tags{
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
 					},
 				},
 			},
 		},
 		{
-			name: "Node Machine with multiple Network Interfaces and Public IP Allocation enabled",
+			name: "Control Plane Machine with public LB and existing backend pools",
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
@@ -2646,6 +3609,9 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 								},
 								APIServerLB: infrav1.LoadBalancerSpec{
 									Name: "api-lb",
+									BackendPool: infrav1.BackendPool{
+										Name: "api-lb-backendPool",
+									},
 								},
 								NodeOutboundLB: &infrav1.LoadBalancerSpec{
 									Name: "outbound-lb",
@@ -2659,8 +3625,122 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						Name: "machine",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID:       ptr.To("azure:///subscriptions/1234-5678/resourceGroups/my-cluster/providers/Microsoft.Compute/virtualMachines/machine-name"),
-						AllocatePublicIP: true,
+						ProviderID: ptr.To("azure:///subscriptions/1234-5678/resourceGroups/my-cluster/providers/Microsoft.Compute/virtualMachines/machine-name"),
+						NetworkInterfaces: []infrav1.NetworkInterface{{
+							SubnetName:       "subnet1",
+							PrivateIPConfigs: 1,
+						}},
+						LoadBalancerBackendPoolIDs:       []string{"/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/loadBalancers/byo-lb/backendAddressPools/pool"},
+						ApplicationGatewayBackendPoolIDs: []string{"/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/applicationGateways/byo-agw/backendAddressPools/pool"},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+						Labels: map[string]string{
+							clusterv1.MachineControlPlaneLabel: "true",
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&networkinterfaces.NICSpec{
+					Name:                             "machine-name-nic",
+					ResourceGroup:                    "my-rg",
+					Location:                         "westus",
+					SubscriptionID:                   "123",
+					MachineName:                      "machine-name",
+					SubnetName:                       "subnet1",
+					IPConfigs:                        []networkinterfaces.IPConfig{{}},
+					VNetName:                         "vnet1",
+					VNetResourceGroup:                "rg1",
+					PublicLBName:                     "api-lb",
+					PublicLBAddressPoolName:          "api-lb-backendPool",
+					PublicLBNATRuleName:              "machine-name",
+					InternalLBName:                   "",
+					InternalLBAddressPoolName:        "",
+					PublicIPName:                     "",
+					AcceleratedNetworking:            nil,
+					LoadBalancerBackendPoolIDs:       []string{"/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/loadBalancers/byo-lb/backendAddressPools/pool"},
+					ApplicationGatewayBackendPoolIDs: []string{"/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/applicationGateways/byo-agw/backendAddressPools/pool"},
+					DNSServers:                       nil,
+					IPv6Enabled:                      false,
+					EnableIPForwarding:               false,
+					SKU:                              nil,
+					ClusterName:                      "cluster",
+					AdditionalTags: infrav1.Tags{
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
+					},
+				},
+			},
+		},
+		{
+			name: "Node Machine with multiple Network Interfaces",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Values: map[string]string{
+								auth.SubscriptionID: "123",
+							},
+						},
+					},
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cluster",
+							Namespace: "default",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cluster",
+							Namespace: "default",
+							OwnerReferences: []metav1.OwnerReference{
+								{
+									APIVersion: "cluster.x-k8s.io/v1beta1",
+									Kind:       "Cluster",
+									Name:       "cluster",
+								},
+							},
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+							NetworkSpec: infrav1.NetworkSpec{
+								Vnet: infrav1.VnetSpec{
+									Name:          "vnet1",
+									ResourceGroup: "rg1",
+								},
+								Subnets: []infrav1.SubnetSpec{
+									{
+										SubnetClassSpec: infrav1.SubnetClassSpec{
+											Role: infrav1.SubnetNode,
+											Name: "subnet1",
+										},
+									},
+								},
+								APIServerLB: infrav1.LoadBalancerSpec{
+									Name: "api-lb",
+								},
+								NodeOutboundLB: &infrav1.LoadBalancerSpec{
+									Name: "outbound-lb",
+									BackendPool: infrav1.BackendPool{
+										Name: "outbound-lb-outboundBackendPool",
+									},
+								},
+							},
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						ProviderID: ptr.To("azure:///subscriptions/1234-5678/resourceGroups/my-cluster/providers/Microsoft.Compute/virtualMachines/machine-name"),
 						NetworkInterfaces: []infrav1.NetworkInterface{
 							{
 								SubnetName:            "subnet1",
@@ -2693,19 +3773,20 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					IPConfigs:                 []networkinterfaces.IPConfig{{}},
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
-					PublicLBName:              "",
-					PublicLBAddressPoolName:   "",
+					PublicLBName:              "outbound-lb",
+					PublicLBAddressPoolName:   "outbound-lb-outboundBackendPool",
 					PublicLBNATRuleName:       "",
 					InternalLBName:            "",
 					InternalLBAddressPoolName: "",
-					PublicIPName:              "pip-machine-name",
+					PublicIPName:              "",
 					AcceleratedNetworking:     ptr.To(true),
 					IPv6Enabled:               false,
 					EnableIPForwarding:        false,
 					SKU:                       nil,
 					ClusterName:               "cluster",
 					AdditionalTags: map[string]string{
-						"kubernetes.io_cluster_cluster": "owned",
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
 					},
 				},
 				&networkinterfaces.NICSpec{
@@ -2730,13 +3811,14 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					SKU:                       nil,
 					ClusterName:               "cluster",
 					AdditionalTags: map[string]string{
-						"kubernetes.io_cluster_cluster": "owned",
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
 					},
 				},
 			},
 		},
 		{
-			name: "Node Machine with multiple IPConfigs",
+			name: "Node Machine with multiple Network Interfaces and Public IP Allocation enabled",
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
@@ -2787,9 +3869,6 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 								},
 								NodeOutboundLB: &infrav1.LoadBalancerSpec{
 									Name: "outbound-lb",
-									BackendPool: infrav1.BackendPool{
-										Name: "outbound-lb-outboundBackendPool",
-									},
 								},
 							},
 						},
@@ -2800,12 +3879,18 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						Name: "machine",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: ptr.To("azure:///subscriptions/1234-5678/resourceGroups/my-cluster/providers/Microsoft.Compute/virtualMachines/machine-name"),
+						ProviderID:       ptr.To("azure:///subscriptions/1234-5678/resourceGroups/my-cluster/providers/Microsoft.Compute/virtualMachines/machine-name"),
+						AllocatePublicIP: true,
 						NetworkInterfaces: []infrav1.NetworkInterface{
 							{
 								SubnetName:            "subnet1",
 								AcceleratedNetworking: ptr.To(true),
-								PrivateIPConfigs:      10,
+								PrivateIPConfigs:      1,
+							},
+							{
+								SubnetName:            "subnet2",
+								AcceleratedNetworking: ptr.To(true),
+								PrivateIPConfigs:      2,
 							},
 						},
 					},
@@ -2819,13 +3904,150 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 			},
 			want: []azure.ResourceSpecGetter{
 				&networkinterfaces.NICSpec{
-					Name:                      "machine-name-nic",
+					Name:                      "machine-name-nic-0",
 					ResourceGroup:             "my-rg",
 					Location:                  "westus",
 					SubscriptionID:            "123",
 					MachineName:               "machine-name",
 					SubnetName:                "subnet1",
-					IPConfigs:                 []networkinterfaces.IPConfig{{}, {}, {}, {}, {}, {}, {}, {}, {}, {}},
+					IPConfigs:                 []networkinterfaces.IPConfig{{}},
+					VNetName:                  "vnet1",
+					VNetResourceGroup:         "rg1",
+					PublicLBName:              "",
+					PublicLBAddressPoolName:   "",
+					PublicLBNATRuleName:       "",
+					InternalLBName:            "",
+					InternalLBAddressPoolName: "",
+					PublicIPName:              "pip-machine-name",
+					AcceleratedNetworking:     ptr.To(true),
+					IPv6Enabled:               false,
+					EnableIPForwarding:        false,
+					SKU:                       nil,
+					ClusterName:               "cluster",
+					AdditionalTags: map[string]string{
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
+					},
+				},
+				&networkinterfaces.NICSpec{
+					Name:                      "machine-name-nic-1",
+					ResourceGroup:             "my-rg",
+					Location:                  "westus",
+					SubscriptionID:            "123",
+					MachineName:               "machine-name",
+					SubnetName:                "subnet2",
+					IPConfigs:                 []networkinterfaces.IPConfig{{}, {}},
+					VNetName:                  "vnet1",
+					VNetResourceGroup:         "rg1",
+					PublicLBName:              "",
+					PublicLBAddressPoolName:   "",
+					PublicLBNATRuleName:       "",
+					InternalLBName:            "",
+					InternalLBAddressPoolName: "",
+					PublicIPName:              "",
+					AcceleratedNetworking:     ptr.To(true),
+					IPv6Enabled:               false,
+					EnableIPForwarding:        false,
+					SKU:                       nil,
+					ClusterName:               "cluster",
+					AdditionalTags: map[string]string{
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
+					},
+				},
+			},
+		},
+		{
+			name: "Node Machine with multiple IPConfigs",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Values: map[string]string{
+								auth.SubscriptionID: "123",
+							},
+						},
+					},
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cluster",
+							Namespace: "default",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cluster",
+							Namespace: "default",
+							OwnerReferences: []metav1.OwnerReference{
+								{
+									APIVersion: "cluster.x-k8s.io/v1beta1",
+									Kind:       "Cluster",
+									Name:       "cluster",
+								},
+							},
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+							NetworkSpec: infrav1.NetworkSpec{
+								Vnet: infrav1.VnetSpec{
+									Name:          "vnet1",
+									ResourceGroup: "rg1",
+								},
+								Subnets: []infrav1.SubnetSpec{
+									{
+										SubnetClassSpec: infrav1.SubnetClassSpec{
+											Role: infrav1.SubnetNode,
+											Name: "subnet1",
+										},
+									},
+								},
+								APIServerLB: infrav1.LoadBalancerSpec{
+									Name: "api-lb",
+								},
+								NodeOutboundLB: &infrav1.LoadBalancerSpec{
+									Name: "outbound-lb",
+									BackendPool: infrav1.BackendPool{
+										Name: "outbound-lb-outboundBackendPool",
+									},
+								},
+							},
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						ProviderID: ptr.To("azure:///subscriptions/1234-5678/resourceGroups/my-cluster/providers/Microsoft.Compute/virtualMachines/machine-name"),
+						NetworkInterfaces: []infrav1.NetworkInterface{
+							{
+								SubnetName:            "subnet1",
+								AcceleratedNetworking: ptr.To(true),
+								PrivateIPConfigs:      10,
+							},
+						},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "machine",
+						Labels: map[string]string{},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&networkinterfaces.NICSpec{
+					Name:                      "machine-name-nic",
+					ResourceGroup:             "my-rg",
+					Location:                  "westus",
+					SubscriptionID:            "123",
+					MachineName:               "machine-name",
+					SubnetName:                "subnet1",
+					IPConfigs:                 []networkinterfaces.IPConfig{{}, {}, {}, {}, {}, {}, {}, {}, {}, {}},
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
 					PublicLBName:              "outbound-lb",
@@ -2840,7 +4062,8 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					SKU:                       nil,
 					ClusterName:               "cluster",
 					AdditionalTags: map[string]string{
-						"kubernetes.io_cluster_cluster": "owned",
+						"kubernetes.io_cluster_cluster":                  "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine": "machine-name",
 					},
 				},
 			},
@@ -2906,6 +4129,138 @@ func TestDiskSpecs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "os disk created from a snapshot",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-azure-machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							DiskSizeGB: ptr.To[int32](128),
+							OSType:     "Linux",
+							ManagedDisk: &infrav1.ManagedDiskParameters{
+								StorageAccountType: "Premium_LRS",
+							},
+							Source: &infrav1.OSDiskSource{
+								SnapshotID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot"),
+							},
+						},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+					},
+					Spec: clusterv1.MachineSpec{
+						FailureDomain: ptr.To("1"),
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&disks.DiskSpec{
+					Name:               "my-azure-machine_OSDisk",
+					ResourceGroup:      "my-rg",
+					SourceResourceID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot",
+					CreateOption:       armcompute.DiskCreateOptionCopy,
+					Location:           "westus",
+					Zone:               "1",
+					OSType:             "Linux",
+					DiskSizeGB:         ptr.To[int32](128),
+					StorageAccountType: "Premium_LRS",
+					Tags: infrav1.Tags{
+						"kubernetes.io_cluster_cluster":                          "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster": "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine":         "my-azure-machine",
+					},
+				},
+			},
+		},
+		{
+			name: "zone-redundant os disk created from a snapshot is not pinned to the zone of the machine",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-azure-machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							DiskSizeGB: ptr.To[int32](128),
+							OSType:     "Linux",
+							ManagedDisk: &infrav1.ManagedDiskParameters{
+								StorageAccountType: "Premium_LRS",
+							},
+							ZoneRedundant: ptr.To(true),
+							Source: &infrav1.OSDiskSource{
+								SnapshotID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot"),
+							},
+						},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+					},
+					Spec: clusterv1.MachineSpec{
+						FailureDomain: ptr.To("1"),
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&disks.DiskSpec{
+					Name:               "my-azure-machine_OSDisk",
+					ResourceGroup:      "my-rg",
+					SourceResourceID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot",
+					CreateOption:       armcompute.DiskCreateOptionCopy,
+					Location:           "westus",
+					OSType:             "Linux",
+					DiskSizeGB:         ptr.To[int32](128),
+					StorageAccountType: "Premium_ZRS",
+					Tags: infrav1.Tags{
+						"kubernetes.io_cluster_cluster":                          "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster": "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_machine":         "my-azure-machine",
+					},
+				},
+			},
+		},
 		{
 			name: "os and data disks",
 			machineScope: MachineScope{
@@ -3027,3 +4382,483 @@ func TestDiskSpecs(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineScope_BootstrapDataBlobSpec(t *testing.T) {
+	tests := []struct {
+		name         string
+		storage      *infrav1.BootstrapDataStorage
+		osType       string
+		cache        *MachineCache
+		expectedSpec *azure.BootstrapDataBlobSpec
+	}{
+		{
+			name: "no bootstrap data storage",
+		},
+		{
+			name:    "windows machines always use custom data",
+			storage: &infrav1.BootstrapDataStorage{StorageAccountName: "capzboottest"},
+			osType:  azure.WindowsOS,
+		},
+		{
+			name:    "bootstrap data storage with cached bootstrap data",
+			storage: &infrav1.BootstrapDataStorage{StorageAccountName: "capzboottest"},
+			osType:  azure.LinuxOS,
+			cache: &MachineCache{
+				BootstrapData:       "Zm9v",
+				BootstrapDataFormat: "ignition",
+			},
+			expectedSpec: &azure.BootstrapDataBlobSpec{
+				StorageAccountName: "capzboottest",
+				ResourceGroup:      "my-rg",
+				ContainerName:      "bootstrap-data",
+				BlobName:           "machine-name",
+				BootstrapData:      "Zm9v",
+				Format:             "ignition",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								BootstrapDataStorage: tt.storage,
+							},
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{Name: "machine-name"},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{OSType: tt.osType},
+					},
+				},
+				cache: tt.cache,
+			}
+			g.Expect(machineScope.BootstrapDataBlobSpec()).To(Equal(tt.expectedSpec))
+		})
+	}
+}
+
+func TestMachineScope_BootstrapDataSecretSpec(t *testing.T) {
+	tests := []struct {
+		name         string
+		vault        *infrav1.BootstrapDataKeyVault
+		osType       string
+		cache        *MachineCache
+		expectedSpec *azure.BootstrapDataSecretSpec
+	}{
+		{
+			name: "no bootstrap data Key Vault",
+		},
+		{
+			name:   "windows machines always use custom data",
+			vault:  &infrav1.BootstrapDataKeyVault{Name: "capz-bootstrap"},
+			osType: azure.WindowsOS,
+		},
+		{
+			name:   "ignition bootstrap data is not delivered through Key Vault",
+			vault:  &infrav1.BootstrapDataKeyVault{Name: "capz-bootstrap"},
+			osType: azure.LinuxOS,
+			cache:  &MachineCache{BootstrapDataFormat: "ignition"},
+		},
+		{
+			name:   "Key Vault in the cluster resource group with cached bootstrap data",
+			vault:  &infrav1.BootstrapDataKeyVault{Name: "capz-bootstrap"},
+			osType: azure.LinuxOS,
+			cache: &MachineCache{
+				BootstrapData:         "c3R1Yg==",
+				BootstrapDataFormat:   "cloud-config",
+				KeyVaultBootstrapData: "Zm9v",
+			},
+			expectedSpec: &azure.BootstrapDataSecretSpec{
+				VaultName:          "capz-bootstrap",
+				VaultResourceGroup: "my-rg",
+				SecretName:         "machine-name",
+				BootstrapData:      "Zm9v",
+			},
+		},
+		{
+			name:   "Key Vault in another resource group",
+			vault:  &infrav1.BootstrapDataKeyVault{Name: "capz-bootstrap", ResourceGroup: "vault-rg"},
+			osType: azure.LinuxOS,
+			expectedSpec: &azure.BootstrapDataSecretSpec{
+				VaultName:          "capz-bootstrap",
+				VaultResourceGroup: "vault-rg",
+				SecretName:         "machine-name",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								BootstrapDataKeyVault: tt.vault,
+							},
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{Name: "machine-name"},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{OSType: tt.osType},
+					},
+				},
+				cache: tt.cache,
+			}
+			g.Expect(machineScope.BootstrapDataSecretSpec()).To(Equal(tt.expectedSpec))
+		})
+	}
+}
+
+func TestMachineScope_ImageCaptureSpec(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		expectedSpec *imagecaptures.ImageCaptureSpec
+	}{
+		{
+			name: "no image capture requested",
+		},
+		{
+			name: "image capture into a gallery of another subscription",
+			annotations: map[string]string{
+				infrav1.CaptureImageAnnotation: "/subscriptions/456/resourceGroups/images-rg/providers/Microsoft.Compute/galleries/golden/images/node/versions/1.2.3",
+			},
+			expectedSpec: &imagecaptures.ImageCaptureSpec{
+				Name:            "1.2.3",
+				ImageName:       "node",
+				GalleryName:     "golden",
+				ResourceGroup:   "images-rg",
+				SubscriptionID:  "456",
+				Location:        "westus",
+				VMName:          "machine-name",
+				VMResourceGroup: "my-rg",
+				VMID:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name",
+				AdditionalTags:  infrav1.Tags{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Values: map[string]string{
+								auth.SubscriptionID: "123",
+							},
+						},
+					},
+					Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								SubscriptionID: "123",
+								Location:       "westus",
+							},
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{Name: "machine-name", Annotations: tt.annotations},
+				},
+			}
+			spec := machineScope.ImageCaptureSpec()
+			if tt.expectedSpec == nil {
+				g.Expect(spec).To(BeNil())
+			} else {
+				g.Expect(spec).To(Equal(tt.expectedSpec))
+			}
+		})
+	}
+}
+
+func TestMachineScope_SetEtcdDataDisk(t *testing.T) {
+	tests := []struct {
+		name           string
+		dataDisks      []infrav1.DataDisk
+		expectedStatus *infrav1.EtcdDataDiskStatus
+	}{
+		{
+			name:      "no etcd data disk",
+			dataDisks: []infrav1.DataDisk{{NameSuffix: "data", Lun: ptr.To[int32](0)}},
+		},
+		{
+			name: "etcd data disk",
+			dataDisks: []infrav1.DataDisk{
+				{NameSuffix: "data", Lun: ptr.To[int32](0)},
+				{NameSuffix: "etcddisk", Lun: ptr.To[int32](1), Etcd: ptr.To(true)},
+			},
+			expectedStatus: &infrav1.EtcdDataDiskStatus{
+				Name:      "machine-name_etcddisk",
+				Device:    "/dev/disk/azure/scsi1/lun1",
+				MountPath: "/var/lib/etcddisk",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{Name: "machine-name"},
+					Spec: infrav1.AzureMachineSpec{
+						DataDisks: tt.dataDisks,
+					},
+				},
+			}
+			machineScope.SetEtcdDataDisk()
+			g.Expect(machineScope.AzureMachine.Status.EtcdDataDisk).To(Equal(tt.expectedStatus))
+		})
+	}
+}
+
+func TestMachineScope_GetSecondaryUserData(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   *infrav1.SecondaryUserDataSource
+		expected string
+		wantErr  bool
+	}{
+		{
+			name: "no secondary user data",
+		},
+		{
+			name:     "secondary user data from the default key",
+			source:   &infrav1.SecondaryUserDataSource{SecretName: "user-data"},
+			expected: "Zm9v",
+		},
+		{
+			name:     "secondary user data from a custom key",
+			source:   &infrav1.SecondaryUserDataSource{SecretName: "user-data", Key: "agent.json"},
+			expected: "YmFy",
+		},
+		{
+			name:    "missing key",
+			source:  &infrav1.SecondaryUserDataSource{SecretName: "user-data", Key: "missing"},
+			wantErr: true,
+		},
+		{
+			name:    "missing secret",
+			source:  &infrav1.SecondaryUserDataSource{SecretName: "other"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "user-data", Namespace: "default"},
+				Data: map[string][]byte{
+					"value":      []byte("foo"),
+					"agent.json": []byte("bar"),
+				},
+			}
+			machineScope := MachineScope{
+				client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{Name: "machine-name", Namespace: "default"},
+					Spec: infrav1.AzureMachineSpec{
+						SecondaryUserData: tt.source,
+					},
+				},
+			}
+			userData, err := machineScope.GetSecondaryUserData(context.TODO())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(userData).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestMachineScope_GetAdminPassword(t *testing.T) {
+	tests := []struct {
+		name          string
+		osType        string
+		storePassword bool
+		providerID    *string
+		existing      []client.Object
+		expected      string
+		wantSecret    bool
+		wantErr       bool
+	}{
+		{
+			name:          "password not stored",
+			osType:        azure.WindowsOS,
+			storePassword: false,
+		},
+		{
+			name:          "linux machine",
+			osType:        azure.LinuxOS,
+			storePassword: true,
+		},
+		{
+			name:          "password generated before the VM is created",
+			osType:        azure.WindowsOS,
+			storePassword: true,
+			wantSecret:    true,
+		},
+		{
+			name:          "password read from the existing secret",
+			osType:        azure.WindowsOS,
+			storePassword: true,
+			providerID:    ptr.To("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name"),
+			existing: []client.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine-name-admin-password", Namespace: "default"},
+				Data:       map[string][]byte{corev1.BasicAuthPasswordKey: []byte("my-password")},
+			}},
+			expected:   "my-password",
+			wantSecret: true,
+		},
+		{
+			name:          "no password for a VM created without one",
+			osType:        azure.WindowsOS,
+			storePassword: true,
+			providerID:    ptr.To("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name"),
+		},
+		{
+			name:          "secret missing the password",
+			osType:        azure.WindowsOS,
+			storePassword: true,
+			existing: []client.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine-name-admin-password", Namespace: "default"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.existing...).Build()
+			machineScope := MachineScope{
+				client: c,
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{Name: "machine-name", Namespace: "default"},
+					Spec: infrav1.AzureMachineSpec{
+						ProviderID:         tt.providerID,
+						OSDisk:             infrav1.OSDisk{OSType: tt.osType},
+						StoreAdminPassword: tt.storePassword,
+					},
+				},
+			}
+			password, err := machineScope.GetAdminPassword(context.TODO())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			err = c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "machine-name-admin-password"}, secret)
+			if !tt.wantSecret {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				g.Expect(password).To(BeEmpty())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(secret.Data[corev1.BasicAuthPasswordKey])).To(Equal(password))
+			if tt.expected != "" {
+				g.Expect(password).To(Equal(tt.expected))
+				return
+			}
+			g.Expect(password).To(HaveLen(adminPasswordLength))
+			g.Expect(string(secret.Data[corev1.BasicAuthUsernameKey])).To(Equal(azure.DefaultUserName))
+			g.Expect(secret.OwnerReferences).To(HaveLen(1))
+			g.Expect(secret.OwnerReferences[0].Name).To(Equal("machine-name"))
+		})
+	}
+}
+
+func TestMachineScope_updateNodeRef(t *testing.T) {
+	const providerID = "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name"
+	node := func(name, uid, providerID string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(uid)},
+			Spec:       corev1.NodeSpec{ProviderID: providerID},
+		}
+	}
+	nodeRef := func(name, uid string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: name, UID: types.UID(uid)}
+	}
+	tests := []struct {
+		name     string
+		nodeRef  *corev1.ObjectReference
+		nodes    []client.Object
+		expected *corev1.ObjectReference
+	}{
+		{
+			name:     "node named after the machine",
+			nodes:    []client.Object{node("machine-name", "uid-1", providerID)},
+			expected: nodeRef("machine-name", "uid-1"),
+		},
+		{
+			name: "node with another name found by provider ID",
+			nodes: []client.Object{
+				node("machine-name", "uid-1", "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/other"),
+				node("custom-name", "uid-2", providerID),
+			},
+			expected: nodeRef("custom-name", "uid-2"),
+		},
+		{
+			name:     "provider ID compared case-insensitively",
+			nodes:    []client.Object{node("machine-name", "uid-1", "azure:///subscriptions/123/resourceGroups/MY-RG/providers/Microsoft.Compute/virtualMachines/machine-name")},
+			expected: nodeRef("machine-name", "uid-1"),
+		},
+		{
+			name:     "node registered again",
+			nodeRef:  nodeRef("machine-name", "uid-1"),
+			nodes:    []client.Object{node("machine-name", "uid-2", providerID)},
+			expected: nodeRef("machine-name", "uid-2"),
+		},
+		{
+			name:    "node deleted",
+			nodeRef: nodeRef("machine-name", "uid-1"),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			workloadClient := fake.NewClientBuilder().WithObjects(tc.nodes...).Build()
+			s := &MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{Name: "machine-name"},
+					Spec:       infrav1.AzureMachineSpec{ProviderID: ptr.To(providerID)},
+					Status:     infrav1.AzureMachineStatus{NodeRef: tc.nodeRef},
+				},
+			}
+			g.Expect(s.updateNodeRef(context.TODO(), workloadClient)).To(Succeed())
+			g.Expect(s.AzureMachine.Status.NodeRef).To(Equal(tc.expected))
+		})
+	}
+}
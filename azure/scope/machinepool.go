@@ -22,16 +22,22 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"reflect"
 	"strings"
+	"time"
 
+	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	machinepool "sigs.k8s.io/cluster-api-provider-azure/azure/scope/strategies/machinepool_deployments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/galleryimageversions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
@@ -41,8 +47,11 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
@@ -63,6 +72,9 @@ type (
 		AzureMachinePool *infrav1exp.AzureMachinePool
 		ClusterScope     azure.ClusterScoper
 		Cache            *MachinePoolCache
+		// ImageVersionCheckInterval is how often the latest version of Major.Minor.latest compute gallery images is
+		// resolved again. When zero, the version resolved when the image was first used is kept.
+		ImageVersionCheckInterval time.Duration
 	}
 
 	// MachinePoolScope defines a scope defined around a machine pool and its cluster.
@@ -75,6 +87,8 @@ type (
 		capiMachinePoolPatchHelper *patch.Helper
 		vmssState                  *azure.VMSS
 		cache                      *MachinePoolCache
+		imageVersionCheckInterval  time.Duration
+		imageVersions              *galleryimageversions.Service
 	}
 
 	// NodeStatus represents the status of a Kubernetes node.
@@ -87,6 +101,7 @@ type (
 	MachinePoolCache struct {
 		BootstrapData           string
 		HasBootstrapDataChanges bool
+		ClusterSSHKeyData       string
 		VMImage                 *infrav1.Image
 		VMSKU                   resourceskus.SKU
 		MaxSurge                int
@@ -125,6 +140,7 @@ func NewMachinePoolScope(params MachinePoolScopeParams) (*MachinePoolScope, erro
 		patchHelper:                helper,
 		capiMachinePoolPatchHelper: capiMachinePoolPatchHelper,
 		ClusterScoper:              params.ClusterScope,
+		imageVersionCheckInterval:  params.ImageVersionCheckInterval,
 	}, nil
 }
 
@@ -147,10 +163,18 @@ func (m *MachinePoolScope) InitMachinePoolCache(ctx context.Context) error {
 			return err
 		}
 
+		m.cache.ClusterSSHKeyData, err = GetClusterSSHKeyData(ctx, m.client, m.AzureMachinePool.Namespace, m.SSHKeyPairSecretName())
+		if err != nil {
+			return err
+		}
+
 		m.cache.VMImage, err = m.GetVMImage(ctx)
 		if err != nil {
 			return err
 		}
+		if err := m.validateImageVersionSkew(ctx, m.cache.VMImage); err != nil {
+			return err
+		}
 		m.SaveVMImageToStatus(m.cache.VMImage)
 
 		m.cache.MaxSurge, err = m.MaxSurge()
@@ -181,8 +205,9 @@ func (m *MachinePoolScope) ScaleSetSpec(ctx context.Context) azure.ResourceSpecG
 		Name:                         m.Name(),
 		ResourceGroup:                m.ResourceGroup(),
 		Size:                         m.AzureMachinePool.Spec.Template.VMSize,
-		Capacity:                     int64(ptr.Deref[int32](m.MachinePool.Spec.Replicas, 0)),
+		Capacity:                     int64(m.DesiredReplicas() + m.WarmPoolSize()),
 		SSHKeyData:                   m.AzureMachinePool.Spec.Template.SSHPublicKey,
+		AdditionalSSHKeyData:         m.SSHPublicKeys(infrav1.Node),
 		OSDisk:                       m.AzureMachinePool.Spec.Template.OSDisk,
 		DataDisks:                    m.AzureMachinePool.Spec.Template.DataDisks,
 		SubnetName:                   m.AzureMachinePool.Spec.Template.NetworkInterfaces[0].SubnetName,
@@ -190,15 +215,16 @@ func (m *MachinePoolScope) ScaleSetSpec(ctx context.Context) azure.ResourceSpecG
 		VNetResourceGroup:            m.Vnet().ResourceGroup,
 		PublicLBName:                 m.OutboundLBName(infrav1.Node),
 		PublicLBAddressPoolName:      m.OutboundPoolName(infrav1.Node),
+		InternalLBName:               m.NodeInternalLBName(),
+		InternalLBAddressPoolName:    m.NodeInternalLBPoolName(),
 		AcceleratedNetworking:        m.AzureMachinePool.Spec.Template.NetworkInterfaces[0].AcceleratedNetworking,
-		Identity:                     m.AzureMachinePool.Spec.Identity,
-		UserAssignedIdentities:       m.AzureMachinePool.Spec.UserAssignedIdentities,
 		DiagnosticsProfile:           m.AzureMachinePool.Spec.Template.Diagnostics,
 		SecurityProfile:              m.AzureMachinePool.Spec.Template.SecurityProfile,
 		SpotVMOptions:                m.AzureMachinePool.Spec.Template.SpotVMOptions,
 		FailureDomains:               m.MachinePool.Spec.FailureDomains,
 		TerminateNotificationTimeout: m.AzureMachinePool.Spec.Template.TerminateNotificationTimeout,
 		NetworkInterfaces:            m.AzureMachinePool.Spec.Template.NetworkInterfaces,
+		NetworkSecurityGroupID:       m.AzureMachinePool.Spec.Template.NetworkSecurityGroupID,
 		IPv6Enabled:                  m.IsIPv6Enabled(),
 		OrchestrationMode:            m.AzureMachinePool.Spec.OrchestrationMode,
 		Location:                     m.AzureMachinePool.Spec.Location,
@@ -207,6 +233,7 @@ func (m *MachinePoolScope) ScaleSetSpec(ctx context.Context) azure.ResourceSpecG
 		ClusterName:                  m.ClusterName(),
 		AdditionalTags:               m.AzureMachinePool.Spec.AdditionalTags,
 	}
+	spec.Identity, spec.UserAssignedIdentities = withClusterIdentity(m.ClusterScoper, m.AzureMachinePool.Spec.Identity, m.AzureMachinePool.Spec.UserAssignedIdentities)
 
 	if m.cache != nil {
 		if m.HasReplicasExternallyManaged(ctx) {
@@ -217,6 +244,7 @@ func (m *MachinePoolScope) ScaleSetSpec(ctx context.Context) azure.ResourceSpecG
 		spec.SKU = m.cache.VMSKU
 		spec.VMImage = m.cache.VMImage
 		spec.BootstrapData = m.cache.BootstrapData
+		spec.ClusterSSHKeyData = m.cache.ClusterSSHKeyData
 		spec.MaxSurge = m.cache.MaxSurge
 	} else {
 		log.V(4).Info("machinepool cache is nil, this is only expected when deleting a machinepool")
@@ -243,6 +271,12 @@ func (m *MachinePoolScope) ProviderID() string {
 	return resourceID.Name
 }
 
+// AuxiliaryTenantIDs returns the tenants other than the tenant of the cluster identity that the scale set needs tokens
+// for, i.e. the tenant of a shared gallery image in another tenant.
+func (m *MachinePoolScope) AuxiliaryTenantIDs() []string {
+	return auxiliaryTenantIDs(m.AzureMachinePool.Spec.Template.Image, m.TenantID())
+}
+
 // SetProviderID sets the AzureMachinePool providerID in spec.
 func (m *MachinePoolScope) SetProviderID(v string) {
 	m.AzureMachinePool.Spec.ProviderID = v
@@ -297,7 +331,7 @@ func (m *MachinePoolScope) NeedsRequeue() bool {
 		return true
 	}
 
-	desiredMatchesActual := len(m.vmssState.Instances) == int(m.DesiredReplicas())
+	desiredMatchesActual := len(m.vmssState.Instances) == int(m.DesiredReplicas()+m.WarmPoolSize())
 	return !(state != nil && infrav1.IsTerminalProvisioningState(*state) && desiredMatchesActual)
 }
 
@@ -306,6 +340,53 @@ func (m MachinePoolScope) DesiredReplicas() int32 {
 	return ptr.Deref[int32](m.MachinePool.Spec.Replicas, 0)
 }
 
+// WarmPoolSize returns the number of instances of the warm pool of the machine pool, or 0 if it has no warm pool. The
+// warm pool is not used when the replicas are managed by an external autoscaler, which sizes the scale set itself.
+func (m MachinePoolScope) WarmPoolSize() int32 {
+	if m.AzureMachinePool.Spec.WarmPool == nil || annotations.ReplicasManagedByExternalAutoscaler(m.MachinePool) {
+		return 0
+	}
+	return m.AzureMachinePool.Spec.WarmPool.Size
+}
+
+// WarmInstanceIDs returns the IDs of the VMSS instances in the warm pool of the machine pool.
+func (m *MachinePoolScope) WarmInstanceIDs() []string {
+	if m.AzureMachinePool.Status.WarmPool == nil {
+		return nil
+	}
+	return m.AzureMachinePool.Status.WarmPool.InstanceIDs
+}
+
+// SetWarmPoolStatus sets the instances of the warm pool of the machine pool, and how many of them are ready to start.
+func (m *MachinePoolScope) SetWarmPoolStatus(instanceIDs []string, readyInstances int32) {
+	if len(instanceIDs) == 0 && m.AzureMachinePool.Spec.WarmPool == nil {
+		m.AzureMachinePool.Status.WarmPool = nil
+		return
+	}
+	m.AzureMachinePool.Status.WarmPool = &infrav1exp.AzureMachinePoolWarmPoolStatus{
+		InstanceIDs:    instanceIDs,
+		ReadyInstances: readyInstances,
+	}
+}
+
+// VMSSState returns the state of the VMSS observed during the reconciliation, or nil if it has not been observed.
+func (m *MachinePoolScope) VMSSState() *azure.VMSS {
+	return m.vmssState
+}
+
+// MachinePoolMachineInstanceIDs returns the VMSS instance IDs of the AzureMachinePoolMachines of the machine pool.
+func (m *MachinePoolScope) MachinePoolMachineInstanceIDs(ctx context.Context) ([]string, error) {
+	machines, err := m.getMachinePoolMachines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instanceIDs := make([]string, len(machines))
+	for i, machine := range machines {
+		instanceIDs[i] = machine.Spec.InstanceID
+	}
+	return instanceIDs, nil
+}
+
 // MaxSurge returns the number of machines to surge, or 0 if the deployment strategy does not support surge.
 func (m MachinePoolScope) MaxSurge() (int, error) {
 	if surger, ok := m.getDeploymentStrategy().(machinepool.Surger); ok {
@@ -384,10 +465,12 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 		existingMachinesByProviderID[machine.Spec.ProviderID] = machine
 	}
 
-	// determine which machines need to be created to reflect the current state in Azure
+	// determine which machines need to be created to reflect the current state in Azure. The instances of the warm
+	// pool are not replicas of the machine pool until they are started.
+	warmInstanceIDs := sets.New(m.WarmInstanceIDs()...)
 	azureMachinesByProviderID := m.vmssState.InstancesByProviderID(m.AzureMachinePool.Spec.OrchestrationMode)
 	for key, val := range azureMachinesByProviderID {
-		if _, ok := existingMachinesByProviderID[key]; !ok {
+		if _, ok := existingMachinesByProviderID[key]; !ok && !warmInstanceIDs.Has(val.InstanceID) {
 			log.V(4).Info("creating AzureMachinePoolMachine", "providerID", key)
 			if err := m.createMachine(ctx, val); err != nil {
 				return errors.Wrap(err, "failed creating AzureMachinePoolMachine")
@@ -606,6 +689,8 @@ func (m *MachinePoolScope) PatchObject(ctx context.Context) error {
 			infrav1.ScaleSetDesiredReplicasCondition,
 			infrav1.ScaleSetModelUpdatedCondition,
 			infrav1.ScaleSetRunningCondition,
+			infrav1.RoleAssignmentReadyCondition,
+			infrav1.OutdatedImageCondition,
 		}})
 }
 
@@ -643,7 +728,7 @@ func (m *MachinePoolScope) Close(ctx context.Context) error {
 
 // GetBootstrapData returns the bootstrap data from the secret in the MachinePool's bootstrap.dataSecretName.
 func (m *MachinePoolScope) GetBootstrapData(ctx context.Context) (string, error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.GetBootstrapData")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.GetBootstrapData")
 	defer done()
 
 	dataSecretName := m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName
@@ -660,6 +745,18 @@ func (m *MachinePoolScope) GetBootstrapData(ctx context.Context) (string, error)
 	if !ok {
 		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
+
+	if nodeProxy.url != "" && m.AzureMachinePool.Spec.Template.OSDisk.OSType != azure.WindowsOS {
+		if format := string(secret.Data["format"]); format != "" && format != string(kubeadmv1.CloudConfig) {
+			log.V(2).Info("not setting up the node proxy", "format", format)
+		} else {
+			merged, err := mergeNodeProxy(value, nodeProxy.url, nodeNoProxy(m.Vnet()))
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to merge node proxy setup into bootstrap data for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
+			}
+			value = merged
+		}
+	}
 	return base64.StdEncoding.EncodeToString(value), nil
 }
 
@@ -699,8 +796,13 @@ func (m *MachinePoolScope) GetVMImage(ctx context.Context) (*infrav1.Image, erro
 	defer done()
 
 	// Use custom Marketplace image, Image ID or a Shared Image Gallery image if provided
-	if m.AzureMachinePool.Spec.Template.Image != nil {
-		return m.AzureMachinePool.Spec.Template.Image, nil
+	if image := m.AzureMachinePool.Spec.Template.Image; image != nil {
+		if image.ComputeGallery != nil {
+			if prefix, ok := image.ComputeGallery.LatestVersionPrefix(); ok {
+				return m.getLatestGalleryImage(ctx, image, prefix)
+			}
+		}
+		return image, nil
 	}
 
 	var (
@@ -729,6 +831,154 @@ func (m *MachinePoolScope) GetVMImage(ctx context.Context) (*infrav1.Image, erro
 	return defaultImage, nil
 }
 
+// getLatestGalleryImage returns a copy of a Major.Minor.latest compute gallery image with the latest published
+// version of Major.Minor. The version in the AzureMachinePool status is kept unless the image version check interval
+// is set, so that a newer version only rolls out to the machine pool when it is enabled.
+func (m *MachinePoolScope) getLatestGalleryImage(ctx context.Context, image *infrav1.Image, prefix string) (*infrav1.Image, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.getLatestGalleryImage")
+	defer done()
+
+	if current := m.AzureMachinePool.Status.Image; m.imageVersionCheckInterval <= 0 && current != nil && current.ComputeGallery != nil {
+		resolved := image.ComputeGallery.DeepCopy()
+		resolved.Version = current.ComputeGallery.Version
+		if strings.HasPrefix(current.ComputeGallery.Version, prefix+".") && reflect.DeepEqual(resolved, current.ComputeGallery) {
+			return current, nil
+		}
+	}
+
+	if m.imageVersions == nil {
+		m.imageVersions = galleryimageversions.New(m)
+	}
+	version, err := m.imageVersions.GetLatestVersion(ctx, image.ComputeGallery, m.Location(), prefix, m.imageVersionCheckInterval)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the latest compute gallery image version")
+	}
+	log.V(4).Info("using latest compute gallery image version", "gallery", image.ComputeGallery.Gallery, "image", image.ComputeGallery.Name, "version", version)
+
+	latest := image.DeepCopy()
+	latest.ComputeGallery.Version = version
+	return latest, nil
+}
+
+// validateImageVersionSkew validates that the Kubernetes version of a compute gallery image, as tagged by
+// image-builder, is within the supported version skew of the control plane and does not downgrade the machine pool
+// before it rolls to the image. Images already in use and images without the tag are not validated.
+func (m *MachinePoolScope) validateImageVersionSkew(ctx context.Context, image *infrav1.Image) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.validateImageVersionSkew")
+	defer done()
+
+	current := m.AzureMachinePool.Status.Image
+	if reflect.DeepEqual(image, current) {
+		return nil
+	}
+	imageVersion, err := m.imageKubernetesVersion(ctx, image)
+	if err != nil || imageVersion == nil {
+		return err
+	}
+
+	controlPlaneVersion, err := m.controlPlaneVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if controlPlaneVersion != nil {
+		if err := validateKubeletVersionSkew(*imageVersion, *controlPlaneVersion); err != nil {
+			return err
+		}
+	}
+
+	// The image in use may since have been deleted from its gallery, so its version is only compared when still found.
+	currentVersion, err := m.imageKubernetesVersion(ctx, current)
+	if err != nil {
+		log.V(4).Info("unable to get the Kubernetes version of the image in use", "error", err.Error())
+		return nil
+	}
+	if currentVersion != nil && imageVersion.LT(*currentVersion) {
+		return errors.Errorf("image Kubernetes version %s would downgrade the machine pool from Kubernetes version %s", imageVersion, currentVersion)
+	}
+	return nil
+}
+
+// imageKubernetesVersion returns the Kubernetes version a compute gallery image is tagged with, or nil for other
+// images and untagged image versions.
+func (m *MachinePoolScope) imageKubernetesVersion(ctx context.Context, image *infrav1.Image) (*semver.Version, error) {
+	var gallery *infrav1.AzureComputeGalleryImage
+	switch {
+	case image == nil:
+		return nil, nil
+	case image.ComputeGallery != nil:
+		gallery = image.ComputeGallery
+	case image.SharedGallery != nil && image.SharedGallery.TenantID == nil:
+		gallery = &infrav1.AzureComputeGalleryImage{
+			Gallery:        image.SharedGallery.Gallery,
+			Name:           image.SharedGallery.Name,
+			Version:        image.SharedGallery.Version,
+			SubscriptionID: ptr.To(image.SharedGallery.SubscriptionID),
+			ResourceGroup:  ptr.To(image.SharedGallery.ResourceGroup),
+		}
+	default:
+		return nil, nil
+	}
+
+	if m.imageVersions == nil {
+		m.imageVersions = galleryimageversions.New(m)
+	}
+	tagged, err := m.imageVersions.GetKubernetesVersion(ctx, gallery)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the Kubernetes version of the compute gallery image")
+	}
+	if tagged == "" {
+		return nil, nil
+	}
+	version, err := semver.ParseTolerant(tagged)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse Kubernetes version %q of image %s in gallery %s", tagged, gallery.Name, gallery.Gallery)
+	}
+	return &version, nil
+}
+
+// controlPlaneVersion returns the Kubernetes version of the control plane of the cluster, or nil when the control plane
+// does not report one.
+func (m *MachinePoolScope) controlPlaneVersion(ctx context.Context) (*semver.Version, error) {
+	cluster, err := util.GetClusterByName(ctx, m.client, m.MachinePool.Namespace, m.MachinePool.Spec.ClusterName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the cluster of the machine pool")
+	}
+	if cluster.Spec.ControlPlaneRef == nil {
+		return nil, nil
+	}
+	controlPlane, err := external.Get(ctx, m.client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the control plane of the cluster")
+	}
+	// The version in the status is the oldest version the control plane runs while it is being upgraded.
+	for _, fields := range [][]string{{"status", "version"}, {"spec", "version"}} {
+		if v, ok, err := unstructured.NestedString(controlPlane.Object, fields...); err == nil && ok && v != "" {
+			version, err := semver.ParseTolerant(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to parse Kubernetes version %q of the control plane", v)
+			}
+			return &version, nil
+		}
+	}
+	return nil, nil
+}
+
+// maxKubeletMinorVersionSkew is how many minor versions the kubelet can be older than the API server.
+const maxKubeletMinorVersionSkew = 3
+
+// validateKubeletVersionSkew validates that nodes running a Kubernetes version are supported by the API server: the
+// kubelet must not be newer than the API server, and at most maxKubeletMinorVersionSkew minor versions older.
+func validateKubeletVersionSkew(kubelet, apiServer semver.Version) error {
+	if kubelet.Major > apiServer.Major || (kubelet.Major == apiServer.Major && kubelet.Minor > apiServer.Minor) {
+		return errors.Errorf("image Kubernetes version %s is newer than the control plane Kubernetes version %s", kubelet, apiServer)
+	}
+	if kubelet.Major < apiServer.Major || apiServer.Minor-kubelet.Minor > maxKubeletMinorVersionSkew {
+		return errors.Errorf("image Kubernetes version %s is more than %d minor versions older than the control plane Kubernetes version %s",
+			kubelet, maxKubeletMinorVersionSkew, apiServer)
+	}
+	return nil
+}
+
 // SaveVMImageToStatus persists the AzureMachinePool image to the status.
 func (m *MachinePoolScope) SaveVMImageToStatus(image *infrav1.Image) {
 	m.AzureMachinePool.Status.Image = image
@@ -823,6 +1073,7 @@ func (m *MachinePoolScope) SetSubnetName() error {
 
 // UpdateDeleteStatus updates a condition on the AzureMachinePool status after a DELETE operation.
 func (m *MachinePoolScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(m.AzureMachinePool, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
@@ -831,10 +1082,12 @@ func (m *MachinePoolScope) UpdateDeleteStatus(condition clusterv1.ConditionType,
 	default:
 		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
 	}
+	recordConditionChange(m.AzureMachinePool, before, condition, "")
 }
 
 // UpdatePutStatus updates a condition on the AzureMachinePool status after a PUT operation.
 func (m *MachinePoolScope) UpdatePutStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(m.AzureMachinePool, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkTrue(m.AzureMachinePool, condition)
@@ -843,10 +1096,12 @@ func (m *MachinePoolScope) UpdatePutStatus(condition clusterv1.ConditionType, se
 	default:
 		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
 	}
+	recordConditionChange(m.AzureMachinePool, before, condition, fmt.Sprintf("%s successfully created or updated", service))
 }
 
 // UpdatePatchStatus updates a condition on the AzureMachinePool status after a PATCH operation.
 func (m *MachinePoolScope) UpdatePatchStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(m.AzureMachinePool, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkTrue(m.AzureMachinePool, condition)
@@ -855,6 +1110,7 @@ func (m *MachinePoolScope) UpdatePatchStatus(condition clusterv1.ConditionType,
 	default:
 		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
 	}
+	recordConditionChange(m.AzureMachinePool, before, condition, fmt.Sprintf("%s successfully updated", service))
 }
 
 // PatchCAPIMachinePoolObject persists the capi machinepool configuration and status.
@@ -21,11 +21,13 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,11 +37,15 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/galleryimageversions"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/galleryimageversions/mock_galleryimageversions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -509,6 +515,240 @@ func TestMachinePoolScope_GetVMImage(t *testing.T) {
 	}
 }
 
+func TestMachinePoolScope_GetVMImageLatestGalleryVersion(t *testing.T) {
+	galleryImage := func(name, version string) *infrav1.Image {
+		return &infrav1.Image{
+			ComputeGallery: &infrav1.AzureComputeGalleryImage{
+				Gallery:        "my-gallery",
+				Name:           name,
+				Version:        version,
+				SubscriptionID: ptr.To("123"),
+				ResourceGroup:  ptr.To("my-rg"),
+			},
+		}
+	}
+	cases := []struct {
+		Name          string
+		Image         *infrav1.Image
+		StatusImage   *infrav1.Image
+		CheckInterval time.Duration
+		Expect        func(m *mock_galleryimageversions.MockClientMockRecorder)
+		Want          *infrav1.Image
+		WantErr       string
+	}{
+		{
+			Name:  "resolves the latest version when the status has no image",
+			Image: galleryImage("image-1", "1.2.latest"),
+			Expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {
+				m.ListVersions(gomockinternal.AContext(), galleryImage("image-1", "1.2.latest").ComputeGallery, "westus").Return([]string{"1.2.3", "1.2.4", "1.3.0"}, nil)
+			},
+			Want: galleryImage("image-1", "1.2.4"),
+		},
+		{
+			Name:        "keeps the version in the status when the check interval is not set",
+			Image:       galleryImage("image-2", "1.2.latest"),
+			StatusImage: galleryImage("image-2", "1.2.3"),
+			Want:        galleryImage("image-2", "1.2.3"),
+		},
+		{
+			Name:        "resolves the latest version when the status has a different Major.Minor",
+			Image:       galleryImage("image-3", "1.3.latest"),
+			StatusImage: galleryImage("image-3", "1.2.3"),
+			Expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {
+				m.ListVersions(gomockinternal.AContext(), galleryImage("image-3", "1.3.latest").ComputeGallery, "westus").Return([]string{"1.2.4", "1.3.0"}, nil)
+			},
+			Want: galleryImage("image-3", "1.3.0"),
+		},
+		{
+			Name:          "resolves a newer version when the check interval is set",
+			Image:         galleryImage("image-4", "1.2.latest"),
+			StatusImage:   galleryImage("image-4", "1.2.3"),
+			CheckInterval: time.Hour,
+			Expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {
+				m.ListVersions(gomockinternal.AContext(), galleryImage("image-4", "1.2.latest").ComputeGallery, "westus").Return([]string{"1.2.3", "1.2.4"}, nil)
+			},
+			Want: galleryImage("image-4", "1.2.4"),
+		},
+		{
+			Name:  "fails when no version is available",
+			Image: galleryImage("image-5", "1.2.latest"),
+			Expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {
+				m.ListVersions(gomockinternal.AContext(), galleryImage("image-5", "1.2.latest").ComputeGallery, "westus").Return(nil, nil)
+			},
+			WantErr: "failed to get the latest compute gallery image version: no version 1.2.x of image image-5 in gallery my-gallery is available in location westus",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			clusterMock := mock_azure.NewMockClusterScoper(mockCtrl)
+			clusterMock.EXPECT().Location().Return("westus").AnyTimes()
+			clusterMock.EXPECT().HashKey().Return("hash").AnyTimes()
+			clientMock := mock_galleryimageversions.NewMockClient(mockCtrl)
+			if c.Expect != nil {
+				c.Expect(clientMock.EXPECT())
+			}
+
+			amp := &infrav1exp.AzureMachinePool{}
+			amp.Spec.Template.Image = c.Image
+			amp.Status.Image = c.StatusImage
+			s := &MachinePoolScope{
+				MachinePool:               &expv1.MachinePool{},
+				AzureMachinePool:          amp,
+				ClusterScoper:             clusterMock,
+				imageVersionCheckInterval: c.CheckInterval,
+				imageVersions:             &galleryimageversions.Service{Client: clientMock, Authorizer: clusterMock},
+			}
+			image, err := s.GetVMImage(context.TODO())
+			if c.WantErr != "" {
+				g.Expect(err).To(MatchError(c.WantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(image).To(Equal(c.Want))
+			g.Expect(amp.Spec.Template.Image).To(Equal(c.Image))
+		})
+	}
+}
+
+func TestMachinePoolScope_validateImageVersionSkew(t *testing.T) {
+	galleryImage := func(version string) *infrav1.Image {
+		return &infrav1.Image{
+			ComputeGallery: &infrav1.AzureComputeGalleryImage{
+				Gallery:        "my-gallery",
+				Name:           "node",
+				Version:        version,
+				SubscriptionID: ptr.To("123"),
+				ResourceGroup:  ptr.To("my-rg"),
+			},
+		}
+	}
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = controlplanev1.AddToScheme(scheme)
+
+	cases := []struct {
+		Name                string
+		Image               *infrav1.Image
+		StatusImage         *infrav1.Image
+		ControlPlaneVersion string
+		// KubernetesVersions are the Kubernetes versions the image versions are tagged with.
+		KubernetesVersions map[string]string
+		WantErr            string
+	}{
+		{
+			Name:                "image in use is not validated",
+			Image:               galleryImage("1.0.0"),
+			StatusImage:         galleryImage("1.0.0"),
+			ControlPlaneVersion: "v1.29.0",
+			KubernetesVersions:  map[string]string{"1.0.0": "v1.30.0"},
+		},
+		{
+			Name:                "image without Kubernetes version tag",
+			Image:               galleryImage("1.0.1"),
+			StatusImage:         galleryImage("1.0.0"),
+			ControlPlaneVersion: "v1.29.0",
+		},
+		{
+			Name:                "image within the supported skew",
+			Image:               galleryImage("1.0.1"),
+			StatusImage:         galleryImage("1.0.0"),
+			ControlPlaneVersion: "v1.29.2",
+			KubernetesVersions:  map[string]string{"1.0.0": "v1.27.3", "1.0.1": "v1.28.5"},
+		},
+		{
+			Name:                "image newer than the control plane",
+			Image:               galleryImage("1.0.1"),
+			ControlPlaneVersion: "v1.28.3",
+			KubernetesVersions:  map[string]string{"1.0.1": "v1.29.0"},
+			WantErr:             "image Kubernetes version 1.29.0 is newer than the control plane Kubernetes version 1.28.3",
+		},
+		{
+			Name:                "image too old for the control plane",
+			Image:               galleryImage("1.0.1"),
+			ControlPlaneVersion: "v1.30.0",
+			KubernetesVersions:  map[string]string{"1.0.1": "v1.26.1"},
+			WantErr:             "image Kubernetes version 1.26.1 is more than 3 minor versions older than the control plane Kubernetes version 1.30.0",
+		},
+		{
+			Name:                "image downgrading the machine pool",
+			Image:               galleryImage("1.0.1"),
+			StatusImage:         galleryImage("1.0.0"),
+			ControlPlaneVersion: "v1.29.0",
+			KubernetesVersions:  map[string]string{"1.0.0": "v1.29.0", "1.0.1": "v1.28.1"},
+			WantErr:             "image Kubernetes version 1.28.1 would downgrade the machine pool from Kubernetes version 1.29.0",
+		},
+		{
+			Name:                "image in use no longer in the gallery",
+			Image:               galleryImage("1.0.1"),
+			StatusImage:         galleryImage("0.9.0"),
+			ControlPlaneVersion: "v1.29.0",
+			KubernetesVersions:  map[string]string{"1.0.1": "v1.28.1"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			clusterMock := mock_azure.NewMockClusterScoper(mockCtrl)
+			// Each case gets its own hash key so that the Kubernetes versions of image versions are not cached across cases.
+			clusterMock.EXPECT().HashKey().Return(c.Name).AnyTimes()
+			clientMock := mock_galleryimageversions.NewMockClient(mockCtrl)
+			clientMock.EXPECT().GetTags(gomockinternal.AContext(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, image *infrav1.AzureComputeGalleryImage) (map[string]*string, error) {
+					if image.Version == "0.9.0" {
+						return nil, errors.New("version 0.9.0 not found")
+					}
+					if version, ok := c.KubernetesVersions[image.Version]; ok {
+						return map[string]*string{galleryimageversions.KubernetesVersionTag: ptr.To(version)}, nil
+					}
+					return nil, nil
+				}).AnyTimes()
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef: &corev1.ObjectReference{
+						APIVersion: controlplanev1.GroupVersion.String(),
+						Kind:       "KubeadmControlPlane",
+						Name:       "my-control-plane",
+						Namespace:  "default",
+					},
+				},
+			}
+			controlPlane := &controlplanev1.KubeadmControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-control-plane", Namespace: "default"},
+				Spec:       controlplanev1.KubeadmControlPlaneSpec{Version: c.ControlPlaneVersion},
+			}
+			amp := &infrav1exp.AzureMachinePool{}
+			amp.Status.Image = c.StatusImage
+			s := &MachinePoolScope{
+				client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, controlPlane).Build(),
+				MachinePool: &expv1.MachinePool{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-pool", Namespace: "default"},
+					Spec:       expv1.MachinePoolSpec{ClusterName: "my-cluster"},
+				},
+				AzureMachinePool: amp,
+				ClusterScoper:    clusterMock,
+				imageVersions:    &galleryimageversions.Service{Client: clientMock, Authorizer: clusterMock},
+			}
+			err := s.validateImageVersionSkew(context.TODO(), c.Image)
+			if c.WantErr != "" {
+				g.Expect(err).To(MatchError(c.WantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
 func TestMachinePoolScope_NeedsRequeue(t *testing.T) {
 	cases := []struct {
 		Name   string
@@ -51,12 +51,17 @@ import (
 const (
 	// MachinePoolMachineScopeName is the sourceName, or more specifically the UserAgent, of client used in cordon and drain.
 	MachinePoolMachineScopeName = "azuremachinepoolmachine-scope"
+
+	// defaultNodeDeletionTimeout is how long the node of a deleted AzureMachinePoolMachine is deleted for when the
+	// AzureMachinePool doesn't set a NodeDeletionTimeout, matching the default of Cluster API machines.
+	defaultNodeDeletionTimeout = 10 * time.Second
 )
 
 type (
 	nodeGetter interface {
 		GetNodeByProviderID(ctx context.Context, providerID string) (*corev1.Node, error)
 		GetNodeByObjectReference(ctx context.Context, nodeRef corev1.ObjectReference) (*corev1.Node, error)
+		DeleteNode(ctx context.Context, node *corev1.Node) error
 	}
 
 	workloadClusterProxy struct {
@@ -165,6 +170,8 @@ func (s *MachinePoolMachineScope) ScaleSetVMSpec() azure.ResourceSpecGetter {
 
 	if spec.IsFlex {
 		spec.ResourceID = strings.TrimPrefix(spec.ProviderID, azureutil.ProviderIDPrefix)
+	} else {
+		spec.ProtectFromScaleIn = s.AzureMachinePoolMachine.Annotations[infrav1exp.ProtectFromScaleInAnnotation] == "true"
 	}
 
 	return spec
@@ -208,6 +215,7 @@ func (s *MachinePoolMachineScope) DeleteLongRunningOperationState(name, service,
 
 // UpdateDeleteStatus updates a condition on the AzureMachinePoolMachine status after a DELETE operation.
 func (s *MachinePoolMachineScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(s.AzureMachinePoolMachine, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
@@ -216,10 +224,12 @@ func (s *MachinePoolMachineScope) UpdateDeleteStatus(condition clusterv1.Conditi
 	default:
 		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
 	}
+	recordConditionChange(s.AzureMachinePoolMachine, before, condition, "")
 }
 
 // UpdatePutStatus updates a condition on the AzureMachinePoolMachine status after a PUT operation.
 func (s *MachinePoolMachineScope) UpdatePutStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(s.AzureMachinePoolMachine, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkTrue(s.AzureMachinePoolMachine, condition)
@@ -228,10 +238,12 @@ func (s *MachinePoolMachineScope) UpdatePutStatus(condition clusterv1.ConditionT
 	default:
 		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
 	}
+	recordConditionChange(s.AzureMachinePoolMachine, before, condition, fmt.Sprintf("%s successfully created or updated", service))
 }
 
 // UpdatePatchStatus updates a condition on the AzureMachinePoolMachine status after a PATCH operation.
 func (s *MachinePoolMachineScope) UpdatePatchStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(s.AzureMachinePoolMachine, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkTrue(s.AzureMachinePoolMachine, condition)
@@ -240,6 +252,7 @@ func (s *MachinePoolMachineScope) UpdatePatchStatus(condition clusterv1.Conditio
 	default:
 		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
 	}
+	recordConditionChange(s.AzureMachinePoolMachine, before, condition, fmt.Sprintf("%s successfully updated", service))
 }
 
 // SetVMSSVM update the scope with the current state of the VMSS VM.
@@ -550,6 +563,72 @@ func (s *MachinePoolMachineScope) nodeDrainTimeoutExceeded() bool {
 	return diff.Seconds() >= s.AzureMachinePool.Spec.NodeDrainTimeout.Seconds()
 }
 
+// DeleteNode deletes the Kubernetes node associated with this AzureMachinePoolMachine once its VMSS instance is
+// deleted. Failures are retried until the NodeDeletionTimeout of the AzureMachinePool is exceeded, after which the node
+// is left to the cloud provider.
+func (s *MachinePoolMachineScope) DeleteNode(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.MachinePoolMachineScope.DeleteNode",
+	)
+	defer done()
+
+	err := s.deleteNode(ctx)
+	if err == nil {
+		return nil
+	}
+	if s.nodeDeletionTimeoutExceeded() {
+		log.Error(err, "NodeDeletionTimeout exceeded, continuing without deleting the node")
+		return nil
+	}
+	// Machine will be re-reconciled after a node deletion failure.
+	return azure.WithTransientError(err, 5*time.Second)
+}
+
+func (s *MachinePoolMachineScope) deleteNode(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.MachinePoolMachineScope.deleteNode",
+	)
+	defer done()
+
+	node, found, err := s.GetNode(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to get node")
+	} else if !found {
+		return nil
+	}
+
+	log.V(4).Info("Deleting node", "node", node.Name)
+	if err := s.workloadNodeGetter.DeleteNode(ctx, node); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete node %s", node.Name)
+	}
+
+	return nil
+}
+
+// nodeDeletionTimeoutExceeded will check to see if the AzureMachinePool's NodeDeletionTimeout is exceeded for the
+// AzureMachinePoolMachine.
+func (s *MachinePoolMachineScope) nodeDeletionTimeoutExceeded() bool {
+	deletionTimestamp := s.AzureMachinePoolMachine.DeletionTimestamp
+	if deletionTimestamp.IsZero() {
+		return false
+	}
+
+	timeout := defaultNodeDeletionTimeout
+	if pool := s.AzureMachinePool; pool != nil && pool.Spec.NodeDeletionTimeout != nil {
+		timeout = pool.Spec.NodeDeletionTimeout.Duration
+	}
+	if timeout <= 0 {
+		return false
+	}
+
+	return time.Since(deletionTimestamp.Time) >= timeout
+}
+
 func (s *MachinePoolMachineScope) hasLatestModelApplied(ctx context.Context) (bool, error) {
 	ctx, _, done := tele.StartSpanWithLogger(
 		ctx,
@@ -642,6 +721,16 @@ func (np *workloadClusterProxy) GetNodeByObjectReference(ctx context.Context, no
 	return &node, err
 }
 
+// DeleteNode will delete a node from the workload cluster.
+func (np *workloadClusterProxy) DeleteNode(ctx context.Context, node *corev1.Node) error {
+	workloadClient, err := getWorkloadClient(ctx, np.Client, np.Cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the workload cluster client")
+	}
+
+	return workloadClient.Delete(ctx, node)
+}
+
 // GetNodeByProviderID will fetch a node from the workload cluster by it's providerID.
 func (np *workloadClusterProxy) GetNodeByProviderID(ctx context.Context, providerID string) (*corev1.Node, error) {
 	ctx, _, done := tele.StartSpanWithLogger(
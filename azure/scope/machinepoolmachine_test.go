@@ -20,6 +20,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
@@ -195,6 +196,61 @@ func TestMachinePoolMachineScope_ScaleSetVMSpecs(t *testing.T) {
 				ResourceID:    "",
 			},
 		},
+		{
+			name: "return vmss vm spec protected from scale-in for uniform vmss",
+			machinePoolMachineScope: MachinePoolMachineScope{
+				MachinePool: &expv1.MachinePool{},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machinepool-name",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							OSDisk: infrav1.OSDisk{
+								OSType: "Linux",
+							},
+						},
+						OrchestrationMode: infrav1.UniformOrchestrationMode,
+					},
+				},
+				AzureMachinePoolMachine: &infrav1exp.AzureMachinePoolMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machinepoolmachine-name",
+						Annotations: map[string]string{
+							infrav1exp.ProtectFromScaleInAnnotation: "true",
+						},
+					},
+					Spec: infrav1exp.AzureMachinePoolMachineSpec{
+						ProviderID: "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/machinepool-name/virtualMachines/0",
+						InstanceID: "0",
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				MachinePoolScope: &MachinePoolScope{
+					AzureMachinePool: &infrav1exp.AzureMachinePool{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "machinepool-name",
+						},
+					},
+				},
+			},
+			want: &scalesetvms.ScaleSetVMSpec{
+				Name:               "machinepoolmachine-name",
+				InstanceID:         "0",
+				ResourceGroup:      "my-rg",
+				ScaleSetName:       "machinepool-name",
+				ProviderID:         "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/machinepool-name/virtualMachines/0",
+				IsFlex:             false,
+				ResourceID:         "",
+				ProtectFromScaleIn: true,
+			},
+		},
 		{
 			name: "return vmss vm spec for vmss flex",
 			machinePoolMachineScope: MachinePoolMachineScope{
@@ -488,6 +544,133 @@ func TestMachinePoolMachineScope_CordonAndDrain(t *testing.T) {
 	}
 }
 
+func TestMachinePoolMachineScope_DeleteNode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = expv1.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+
+	var (
+		clusterScope = ClusterScope{
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-foo",
+				},
+			},
+		}
+	)
+
+	cases := []struct {
+		Name                string
+		NodeDeletionTimeout *metav1.Duration
+		DeletedAgo          time.Duration
+		Setup               func(mockNodeGetter *mock_scope.MocknodeGetter)
+		Err                 string
+	}{
+		{
+			Name: "should skip deletion if the node does not exist",
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter) {
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(nil, nil)
+			},
+		},
+		{
+			Name: "should delete the node",
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter) {
+				node := getReadyNode()
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(node, nil)
+				mockNodeGetter.EXPECT().DeleteNode(gomock2.AContext(), node).Return(nil)
+			},
+		},
+		{
+			Name: "should retry a failed deletion before the default timeout",
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter) {
+				node := getReadyNode()
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(node, nil)
+				mockNodeGetter.EXPECT().DeleteNode(gomock2.AContext(), node).Return(errors.New("boom"))
+			},
+			Err: "failed to delete node node1: boom",
+		},
+		{
+			Name:       "should give up a failed deletion after the default timeout",
+			DeletedAgo: time.Minute,
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter) {
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(nil, errors.New("boom"))
+			},
+		},
+		{
+			Name:                "should retry a failed deletion indefinitely with a timeout of 0",
+			NodeDeletionTimeout: &metav1.Duration{},
+			DeletedAgo:          time.Hour,
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter) {
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(nil, errors.New("boom"))
+			},
+			Err: "failed to get node: failed to get node by providerID: boom",
+		},
+		{
+			Name:                "should retry a failed deletion before the timeout of the AzureMachinePool",
+			NodeDeletionTimeout: &metav1.Duration{Duration: 5 * time.Minute},
+			DeletedAgo:          time.Minute,
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter) {
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(nil, errors.New("boom"))
+			},
+			Err: "failed to get node: failed to get node by providerID: boom",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var (
+				controller = gomock.NewController(t)
+				mockClient = mock_scope.NewMocknodeGetter(controller)
+				g          = NewWithT(t)
+				params     = MachinePoolMachineScopeParams{
+					Client:       fake.NewClientBuilder().WithScheme(scheme).Build(),
+					ClusterScope: &clusterScope,
+					MachinePool: &expv1.MachinePool{
+						Spec: expv1.MachinePoolSpec{
+							Template: clusterv1.MachineTemplateSpec{
+								Spec: clusterv1.MachineSpec{
+									Version: ptr.To("v1.19.11"),
+								},
+							},
+						},
+					},
+					AzureMachinePool: &infrav1exp.AzureMachinePool{
+						Spec: infrav1exp.AzureMachinePoolSpec{
+							NodeDeletionTimeout: c.NodeDeletionTimeout,
+						},
+					},
+					AzureMachinePoolMachine: &infrav1exp.AzureMachinePoolMachine{
+						ObjectMeta: metav1.ObjectMeta{
+							DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-c.DeletedAgo)},
+						},
+						Spec: infrav1exp.AzureMachinePoolMachineSpec{
+							ProviderID: FakeProviderID,
+						},
+					},
+				}
+			)
+
+			defer controller.Finish()
+
+			c.Setup(mockClient)
+			s, err := NewMachinePoolMachineScope(params)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s).NotTo(BeNil())
+			s.workloadNodeGetter = mockClient
+
+			err = s.DeleteNode(context.TODO())
+			if c.Err == "" {
+				g.Expect(err).To(Succeed())
+			} else {
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+				g.Expect(reconcileError.IsTransient()).To(BeTrue())
+				g.Expect(err).To(MatchError(ContainSubstring(c.Err)))
+			}
+		})
+	}
+}
+
 func getReadyNode() *corev1.Node {
 	return &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -19,6 +19,7 @@ package scope
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
@@ -273,6 +274,16 @@ func (s *ManagedControlPlaneScope) VNetSpec() azure.ResourceSpecGetter {
 	}
 }
 
+// SecondaryVNetSpecs returns nil, as managed clusters have no secondary regions.
+func (s *ManagedControlPlaneScope) SecondaryVNetSpecs() []azure.ResourceSpecGetter {
+	return nil
+}
+
+// DDoSProtectionPlanSpec returns nil, as the virtual network of a managed cluster has no DDoS protection plan.
+func (s *ManagedControlPlaneScope) DDoSProtectionPlanSpec() azure.ResourceSpecGetter {
+	return nil
+}
+
 // ControlPlaneRouteTable returns the cluster controlplane routetable.
 func (s *ManagedControlPlaneScope) ControlPlaneRouteTable() infrav1.RouteTable {
 	return infrav1.RouteTable{}
@@ -439,6 +450,21 @@ func (s *ManagedControlPlaneScope) OutboundPoolName(_ string) string {
 	return "aksOutboundBackendPool" // hard-coded in aks
 }
 
+// NodeInternalLBName returns the name of the node internal LB.
+func (s *ManagedControlPlaneScope) NodeInternalLBName() string {
+	return "" // does not apply for AKS
+}
+
+// NodeInternalLBPoolName returns the node internal LB backend pool name.
+func (s *ManagedControlPlaneScope) NodeInternalLBPoolName() string {
+	return "" // does not apply for AKS
+}
+
+// NodeRoutes returns the configuration of the routes to the pod CIDRs of the nodes.
+func (s *ManagedControlPlaneScope) NodeRoutes() *infrav1.NodeRoutesSpec {
+	return nil // does not apply for AKS
+}
+
 // GetPrivateDNSZoneName returns the Private DNS Zone from the spec or generate it from cluster name.
 // Currently always empty as managed control planes do not currently implement private clusters.
 func (s *ManagedControlPlaneScope) GetPrivateDNSZoneName() string {
@@ -455,6 +481,36 @@ func (s *ManagedControlPlaneScope) FailureDomains() []*string {
 	return []*string{}
 }
 
+// SSHKeyPairSecretName returns an empty string as managed clusters configure SSH access on the managed cluster itself.
+func (s *ManagedControlPlaneScope) SSHKeyPairSecretName() string {
+	return ""
+}
+
+// SSHPublicKeys returns nil as managed clusters configure SSH access on the managed cluster itself.
+func (s *ManagedControlPlaneScope) SSHPublicKeys(_ string) []string {
+	return nil
+}
+
+// BootstrapDataStorageAccountName returns an empty string as the bootstrap data of managed clusters is handled by AKS.
+func (s *ManagedControlPlaneScope) BootstrapDataStorageAccountName() string {
+	return ""
+}
+
+// BootstrapDataKeyVault returns nil as the bootstrap data of managed clusters is handled by AKS.
+func (s *ManagedControlPlaneScope) BootstrapDataKeyVault() *infrav1.BootstrapDataKeyVault {
+	return nil
+}
+
+// ManagedIdentityProviderID returns an empty string as the identities of managed clusters are handled by AKS.
+func (s *ManagedControlPlaneScope) ManagedIdentityProviderID() string {
+	return ""
+}
+
+// PlatformFaultDomainCount returns nil as managed clusters have no availability sets.
+func (s *ManagedControlPlaneScope) PlatformFaultDomainCount() *int32 {
+	return nil
+}
+
 // ManagedClusterAnnotations returns the annotations for the managed cluster.
 func (s *ManagedControlPlaneScope) ManagedClusterAnnotations() map[string]string {
 	return s.ControlPlane.Annotations
@@ -705,6 +761,7 @@ func (s *ManagedControlPlaneScope) DeleteLongRunningOperationState(name, service
 
 // UpdateDeleteStatus updates a condition on the AzureManagedControlPlane status after a DELETE operation.
 func (s *ManagedControlPlaneScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(s.ControlPlane, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkFalse(s.ControlPlane, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
@@ -713,10 +770,12 @@ func (s *ManagedControlPlaneScope) UpdateDeleteStatus(condition clusterv1.Condit
 	default:
 		conditions.MarkFalse(s.ControlPlane, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
 	}
+	recordConditionChange(s.ControlPlane, before, condition, "")
 }
 
 // UpdatePutStatus updates a condition on the AzureManagedControlPlane status after a PUT operation.
 func (s *ManagedControlPlaneScope) UpdatePutStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(s.ControlPlane, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkTrue(s.ControlPlane, condition)
@@ -725,10 +784,12 @@ func (s *ManagedControlPlaneScope) UpdatePutStatus(condition clusterv1.Condition
 	default:
 		conditions.MarkFalse(s.ControlPlane, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
 	}
+	recordConditionChange(s.ControlPlane, before, condition, fmt.Sprintf("%s successfully created or updated", service))
 }
 
 // UpdatePatchStatus updates a condition on the AzureManagedControlPlane status after a PATCH operation.
 func (s *ManagedControlPlaneScope) UpdatePatchStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(s.ControlPlane, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkTrue(s.ControlPlane, condition)
@@ -737,6 +798,7 @@ func (s *ManagedControlPlaneScope) UpdatePatchStatus(condition clusterv1.Conditi
 	default:
 		conditions.MarkFalse(s.ControlPlane, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
 	}
+	recordConditionChange(s.ControlPlane, before, condition, fmt.Sprintf("%s successfully updated", service))
 }
 
 // AnnotationJSON returns a map[string]interface from a JSON annotation.
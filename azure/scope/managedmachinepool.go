@@ -276,6 +276,7 @@ func (s *ManagedMachinePoolScope) DeleteLongRunningOperationState(name, service,
 
 // UpdateDeleteStatus updates a condition on the AzureManagedControlPlane status after a DELETE operation.
 func (s *ManagedMachinePoolScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(s.InfraMachinePool, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
@@ -284,10 +285,12 @@ func (s *ManagedMachinePoolScope) UpdateDeleteStatus(condition clusterv1.Conditi
 	default:
 		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
 	}
+	recordConditionChange(s.InfraMachinePool, before, condition, "")
 }
 
 // UpdatePutStatus updates a condition on the AzureManagedMachinePool status after a PUT operation.
 func (s *ManagedMachinePoolScope) UpdatePutStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(s.InfraMachinePool, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkTrue(s.InfraMachinePool, condition)
@@ -296,10 +299,12 @@ func (s *ManagedMachinePoolScope) UpdatePutStatus(condition clusterv1.ConditionT
 	default:
 		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
 	}
+	recordConditionChange(s.InfraMachinePool, before, condition, fmt.Sprintf("%s successfully created or updated", service))
 }
 
 // UpdatePatchStatus updates a condition on the AzureManagedMachinePool status after a PATCH operation.
 func (s *ManagedMachinePoolScope) UpdatePatchStatus(condition clusterv1.ConditionType, service string, err error) {
+	before := conditions.Get(s.InfraMachinePool, condition).DeepCopy()
 	switch {
 	case err == nil:
 		conditions.MarkTrue(s.InfraMachinePool, condition)
@@ -308,6 +313,7 @@ func (s *ManagedMachinePoolScope) UpdatePatchStatus(condition clusterv1.Conditio
 	default:
 		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
 	}
+	recordConditionChange(s.InfraMachinePool, before, condition, fmt.Sprintf("%s successfully updated", service))
 }
 
 // PatchCAPIMachinePoolObject persists the capi machinepool configuration and status.
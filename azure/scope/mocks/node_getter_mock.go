@@ -55,6 +55,20 @@ func (m *MocknodeGetter) EXPECT() *MocknodeGetterMockRecorder {
 	return m.recorder
 }
 
+// DeleteNode mocks base method.
+func (m *MocknodeGetter) DeleteNode(ctx context.Context, node *v1.Node) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNode", ctx, node)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNode indicates an expected call of DeleteNode.
+func (mr *MocknodeGetterMockRecorder) DeleteNode(ctx, node any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNode", reflect.TypeOf((*MocknodeGetter)(nil).DeleteNode), ctx, node)
+}
+
 // GetNodeByObjectReference mocks base method.
 func (m *MocknodeGetter) GetNodeByObjectReference(ctx context.Context, nodeRef v1.ObjectReference) (*v1.Node, error) {
 	m.ctrl.T.Helper()
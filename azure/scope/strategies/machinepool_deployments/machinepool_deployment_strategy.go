@@ -174,6 +174,10 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 				return toDelete, nil
 			}
 
+			if isProtectedFromScaleIn(v) {
+				continue
+			}
+
 			toDelete = append(toDelete, v)
 		}
 
@@ -184,6 +188,10 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 				return toDelete, nil
 			}
 
+			if isProtectedFromScaleIn(v) {
+				continue
+			}
+
 			toDelete = append(toDelete, v)
 		}
 
@@ -207,7 +215,7 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 			return toDelete, nil
 		}
 
-		if !v.Status.LatestModelApplied {
+		if !v.Status.LatestModelApplied && !isProtectedFromScaleIn(v) {
 			toDelete = append(toDelete, v)
 		}
 	}
@@ -273,6 +281,12 @@ func getMachinesWithoutLatestModel(machinesByProviderID map[string]infrav1exp.Az
 	return machinesWithLatestModel
 }
 
+// isProtectedFromScaleIn returns whether a machine is protected from scale-in, in which case it is never selected for
+// deletion to lower the replica count or to roll out the latest model.
+func isProtectedFromScaleIn(machine infrav1exp.AzureMachinePoolMachine) bool {
+	return machine.Annotations[infrav1exp.ProtectFromScaleInAnnotation] == "true"
+}
+
 func orderByNewest(machines []infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
 	sort.Slice(machines, func(i, j int) bool {
 		return machines[i].ObjectMeta.CreationTimestamp.After(machines[j].ObjectMeta.CreationTimestamp.Time)
@@ -20,12 +20,14 @@ import (
 	"context"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/pkg/errors"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 )
 
 /*
@@ -63,6 +65,7 @@ const (
 )
 
 type workloadIdentityCredential struct {
+	mu        sync.Mutex
 	assertion string
 	file      string
 	cred      *azidentity.ClientAssertionCredential
@@ -79,7 +82,11 @@ type WorkloadIdentityCredentialOptions struct {
 
 // NewWorkloadIdentityCredentialOptions returns an empty instance of WorkloadIdentityCredentialOptions.
 func NewWorkloadIdentityCredentialOptions() *WorkloadIdentityCredentialOptions {
-	return &WorkloadIdentityCredentialOptions{}
+	return &WorkloadIdentityCredentialOptions{
+		ClientOptions: azcore.ClientOptions{
+			Transport: azureutil.Transport(),
+		},
+	}
 }
 
 // WithClientID sets client ID to WorkloadIdentityCredentialOptions.
@@ -129,7 +136,12 @@ func (w *WorkloadIdentityCredentialOptions) WithDefaults() (*WorkloadIdentityCre
 // NewWorkloadIdentityCredential returns a workload identity credential.
 func NewWorkloadIdentityCredential(options *WorkloadIdentityCredentialOptions) (azcore.TokenCredential, error) {
 	w := &workloadIdentityCredential{file: options.TokenFilePath}
-	cred, err := azidentity.NewClientAssertionCredential(options.TenantID, options.ClientID, w.getAssertion, &azidentity.ClientAssertionCredentialOptions{ClientOptions: options.ClientOptions})
+	cred, err := azidentity.NewClientAssertionCredential(options.TenantID, options.ClientID, w.getAssertion, &azidentity.ClientAssertionCredentialOptions{
+		ClientOptions: options.ClientOptions,
+		// Tokens for other tenants are only requested as auxiliary tokens, e.g. for images in a gallery of another
+		// tenant.
+		AdditionallyAllowedTenants: []string{"*"},
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -143,6 +155,9 @@ func (w *workloadIdentityCredential) GetToken(ctx context.Context, opts policy.T
 }
 
 func (w *workloadIdentityCredential) getAssertion(context.Context) (string, error) {
+	// The credential is shared by the reconciles of all the clusters with its identity.
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	if now := time.Now(); w.lastRead.Add(azureFederatedTokenFileRefreshTime).Before(now) {
 		content, err := os.ReadFile(w.file)
 		if err != nil {
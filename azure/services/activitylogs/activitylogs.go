@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activitylogs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// lookback is how far back the Activity Log is searched for the events of a failed operation.
+const lookback = 6 * time.Hour
+
+// DetailedError is an Azure API error described by the status message of its failed event in the Activity Log.
+type DetailedError struct {
+	err           error
+	correlationID string
+	statusMessage string
+}
+
+// Error returns the error string, followed by the status message of the Activity Log.
+func (e *DetailedError) Error() string {
+	return fmt.Sprintf("%s (activity log for correlation ID %s: %s)", e.err.Error(), e.correlationID, e.statusMessage)
+}
+
+// Unwrap returns the described error.
+func (e *DetailedError) Unwrap() error {
+	return e.err
+}
+
+// StatusMessage returns the status message of the failed event in the Activity Log.
+func (e *DetailedError) StatusMessage() string {
+	return e.statusMessage
+}
+
+// Describe returns err described by the status message of the failed event with its correlation ID in the Activity
+// Log, so that the condition it ends up in explains the failure without a trip to the Azure portal. err is returned
+// unchanged if it is not an Azure API error, or if the Activity Log has no failed event for it yet.
+func Describe(ctx context.Context, client Client, err error) error {
+	var rerr *azcore.ResponseError
+	if !errors.As(err, &rerr) {
+		return err
+	}
+	// The request was sent with the correlation ID of the context if ARM did not return it. This is looked up before
+	// starting a span, which would otherwise create a new correlation ID.
+	correlationID := ""
+	if rerr.RawResponse != nil {
+		correlationID = rerr.RawResponse.Header.Get(string(tele.CorrIDKeyVal))
+	}
+	if correlationID == "" {
+		corrID, ok := tele.CorrIDFromCtx(ctx)
+		if !ok {
+			return err
+		}
+		correlationID = string(corrID)
+	}
+
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "activitylogs.Describe")
+	defer done()
+
+	statusMessage, qerr := client.FailureStatusMessage(ctx, correlationID, time.Now().Add(-lookback))
+	if qerr != nil {
+		log.V(2).Info("failed to query the activity log", "correlationID", correlationID, "error", qerr.Error())
+		return err
+	}
+	if statusMessage == "" {
+		log.V(4).Info("no failed event in the activity log yet", "correlationID", correlationID)
+		return err
+	}
+	return &DetailedError{
+		err:           err,
+		correlationID: correlationID,
+		statusMessage: formatStatusMessage(statusMessage),
+	}
+}
+
+// statusError is an error in the status message of an Activity Log event.
+type statusError struct {
+	Code    string        `json:"code"`
+	Message string        `json:"message"`
+	Details []statusError `json:"details"`
+}
+
+// formatStatusMessage returns the codes and messages of the error in a status message, and of its details. Status
+// messages are usually JSON documents with an error, other status messages are returned as is.
+func formatStatusMessage(statusMessage string) string {
+	var status struct {
+		Error *statusError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(statusMessage), &status); err != nil || status.Error == nil {
+		return statusMessage
+	}
+
+	var messages []string
+	var collect func(e statusError)
+	collect = func(e statusError) {
+		if e.Message != "" {
+			messages = append(messages, strings.TrimPrefix(e.Code+": "+e.Message, ": "))
+		}
+		for _, detail := range e.Details {
+			collect(detail)
+		}
+	}
+	collect(*status.Error)
+	if len(messages) == 0 {
+		return statusMessage
+	}
+	return strings.Join(messages, "; ")
+}
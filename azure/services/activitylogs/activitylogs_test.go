@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activitylogs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/activitylogs/mock_activitylogs"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+func newResponseError(correlationID string) *azcore.ResponseError {
+	header := http.Header{}
+	if correlationID != "" {
+		header.Set(string(tele.CorrIDKeyVal), correlationID)
+	}
+	return &azcore.ResponseError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "DeploymentFailed",
+		RawResponse: &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(`{"error":{"code":"DeploymentFailed"}}`)),
+			Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{Path: "/subscriptions/123"}},
+		},
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	testcases := []struct {
+		name                  string
+		ctx                   context.Context
+		err                   error
+		expect                func(c *mock_activitylogs.MockClientMockRecorder)
+		expectedStatusMessage string
+	}{
+		{
+			name:   "error which is not an Azure API error",
+			ctx:    context.Background(),
+			err:    errors.New("some error"),
+			expect: func(c *mock_activitylogs.MockClientMockRecorder) {},
+		},
+		{
+			name: "Azure API error described by the activity log",
+			ctx:  context.Background(),
+			err:  newResponseError("corr-id"),
+			expect: func(c *mock_activitylogs.MockClientMockRecorder) {
+				c.FailureStatusMessage(gomockinternal.AContext(), "corr-id", gomock.Any()).Return("failure", nil)
+			},
+			expectedStatusMessage: "failure",
+		},
+		{
+			name: "correlation ID from the context when the response has none",
+			ctx:  context.WithValue(context.Background(), tele.CorrIDKeyVal, tele.CorrID("ctx-corr-id")),
+			err:  newResponseError(""),
+			expect: func(c *mock_activitylogs.MockClientMockRecorder) {
+				c.FailureStatusMessage(gomockinternal.AContext(), "ctx-corr-id", gomock.Any()).Return("failure", nil)
+			},
+			expectedStatusMessage: "failure",
+		},
+		{
+			name:   "no correlation ID",
+			ctx:    context.Background(),
+			err:    newResponseError(""),
+			expect: func(c *mock_activitylogs.MockClientMockRecorder) {},
+		},
+		{
+			name: "no failed event in the activity log yet",
+			ctx:  context.Background(),
+			err:  newResponseError("corr-id"),
+			expect: func(c *mock_activitylogs.MockClientMockRecorder) {
+				c.FailureStatusMessage(gomockinternal.AContext(), "corr-id", gomock.Any()).Return("", nil)
+			},
+		},
+		{
+			name: "activity log query failure",
+			ctx:  context.Background(),
+			err:  newResponseError("corr-id"),
+			expect: func(c *mock_activitylogs.MockClientMockRecorder) {
+				c.FailureStatusMessage(gomockinternal.AContext(), "corr-id", gomock.Any()).Return("", errors.New("forbidden"))
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			clientMock := mock_activitylogs.NewMockClient(mockCtrl)
+			tc.expect(clientMock.EXPECT())
+
+			err := Describe(tc.ctx, clientMock, tc.err)
+			g.Expect(errors.Is(err, tc.err)).To(BeTrue())
+			var detailedErr *DetailedError
+			if tc.expectedStatusMessage == "" {
+				g.Expect(err).To(Equal(tc.err))
+				g.Expect(errors.As(err, &detailedErr)).To(BeFalse())
+				return
+			}
+			g.Expect(errors.As(err, &detailedErr)).To(BeTrue())
+			g.Expect(detailedErr.StatusMessage()).To(Equal(tc.expectedStatusMessage))
+			g.Expect(err.Error()).To(HaveSuffix("(activity log for correlation ID " + detailedErr.correlationID + ": " + tc.expectedStatusMessage + ")"))
+		})
+	}
+}
+
+func TestFormatStatusMessage(t *testing.T) {
+	testcases := []struct {
+		name          string
+		statusMessage string
+		expected      string
+	}{
+		{
+			name:          "status message which is not JSON",
+			statusMessage: "Something went wrong",
+			expected:      "Something went wrong",
+		},
+		{
+			name:          "status message without an error",
+			statusMessage: `{"status":"Failed"}`,
+			expected:      `{"status":"Failed"}`,
+		},
+		{
+			name:          "error with details",
+			statusMessage: `{"status":"Failed","error":{"code":"ResourceOperationFailure","message":"The resource operation completed with terminal provisioning state 'Failed'.","details":[{"code":"VMExtensionProvisioningError","message":"VM has reported a failure when processing extension 'CAPZ.Linux.Bootstrapping'."}]}}`,
+			expected:      "ResourceOperationFailure: The resource operation completed with terminal provisioning state 'Failed'.; VMExtensionProvisioningError: VM has reported a failure when processing extension 'CAPZ.Linux.Bootstrapping'.",
+		},
+		{
+			name:          "error without a code",
+			statusMessage: `{"error":{"message":"Quota exceeded."}}`,
+			expected:      "Quota exceeded.",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(formatStatusMessage(tc.statusMessage)).To(Equal(tc.expected))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activitylogs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	apiVersion   = "2015-04-01"
+	failedStatus = "Failed"
+)
+
+// Client queries the Azure Activity Log of a subscription.
+type Client interface {
+	FailureStatusMessage(ctx context.Context, correlationID string, since time.Time) (string, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	client *arm.Client
+	auth   azure.Authorizer
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new Activity Log client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create activity logs client options")
+	}
+	client, err := arm.NewClient("activitylogs.AzureClient", "v1.0.0", auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create activity logs client")
+	}
+	return &AzureClient{
+		client: client,
+		auth:   auth,
+	}, nil
+}
+
+// event is an event of the Activity Log, reduced to the fields describing the outcome of an operation.
+type event struct {
+	CorrelationID string `json:"correlationId"`
+	Status        struct {
+		Value string `json:"value"`
+	} `json:"status"`
+	Properties struct {
+		StatusMessage string `json:"statusMessage"`
+	} `json:"properties"`
+}
+
+// FailureStatusMessage returns the status message of the first failed event of the Activity Log with the correlation
+// ID since the given time, or an empty string if there is none yet. Events are usually ingested by the Activity Log
+// within a few minutes of the operation.
+func (ac *AzureClient) FailureStatusMessage(ctx context.Context, correlationID string, since time.Time) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "activitylogs.AzureClient.FailureStatusMessage")
+	defer done()
+
+	urlPath := runtime.JoinPaths("/subscriptions", ac.auth.SubscriptionID(), "providers/Microsoft.Insights/eventtypes/management/values")
+	req, err := runtime.NewRequest(ctx, http.MethodGet, runtime.JoinPaths(ac.client.Endpoint(), urlPath))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create activity log request")
+	}
+	query := req.Raw().URL.Query()
+	query.Set("api-version", apiVersion)
+	query.Set("$filter", fmt.Sprintf("eventTimestamp ge '%s' and correlationId eq '%s'", since.UTC().Format(time.RFC3339), correlationID))
+	query.Set("$select", "correlationId,status,properties")
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header["Accept"] = []string{"application/json"}
+
+	resp, err := ac.client.Pipeline().Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list activity log events with correlation ID %s", correlationID)
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return "", errors.Wrapf(runtime.NewResponseError(resp), "failed to list activity log events with correlation ID %s", correlationID)
+	}
+
+	var events struct {
+		Value []event `json:"value"`
+	}
+	if err := runtime.UnmarshalAsJSON(resp, &events); err != nil {
+		return "", errors.Wrapf(err, "failed to parse activity log events with correlation ID %s", correlationID)
+	}
+	for _, e := range events.Value {
+		if strings.EqualFold(e.CorrelationID, correlationID) && strings.EqualFold(e.Status.Value, failedStatus) && e.Properties.StatusMessage != "" {
+			return e.Properties.StatusMessage, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activitylogs
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/fakearm"
+)
+
+func TestFailureStatusMessage(t *testing.T) {
+	g := NewWithT(t)
+
+	server := fakearm.NewServer()
+	defer server.Close()
+	server.Register()
+
+	client, err := NewClient(server.Authorizer("123"))
+	g.Expect(err).NotTo(HaveOccurred())
+	id := "/subscriptions/123/providers/Microsoft.Insights/eventtypes/management/values"
+
+	server.InjectError(http.MethodGet, id, http.StatusForbidden, "AuthorizationFailed")
+	_, err = client.FailureStatusMessage(context.Background(), "corr-id", time.Now().Add(-time.Hour))
+	g.Expect(err).To(MatchError(ContainSubstring("AuthorizationFailed")))
+
+	g.Expect(server.Put(id, map[string]interface{}{"value": []interface{}{
+		map[string]interface{}{
+			"correlationId": "corr-id",
+			"status":        map[string]interface{}{"value": "Started"},
+			"properties":    map[string]interface{}{},
+		},
+	}})).To(Succeed())
+	statusMessage, err := client.FailureStatusMessage(context.Background(), "corr-id", time.Now().Add(-time.Hour))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(statusMessage).To(BeEmpty())
+
+	g.Expect(server.Put(id, map[string]interface{}{"value": []interface{}{
+		map[string]interface{}{
+			"correlationId": "other-corr-id",
+			"status":        map[string]interface{}{"value": "Failed"},
+			"properties":    map[string]interface{}{"statusMessage": "other failure"},
+		},
+		map[string]interface{}{
+			"correlationId": "corr-id",
+			"status":        map[string]interface{}{"value": "Started"},
+			"properties":    map[string]interface{}{},
+		},
+		map[string]interface{}{
+			"correlationId": "corr-id",
+			"status":        map[string]interface{}{"value": "Failed"},
+			"properties":    map[string]interface{}{"statusMessage": "failure"},
+		},
+	}})).To(Succeed())
+	statusMessage, err = client.FailureStatusMessage(context.Background(), "corr-id", time.Now().Add(-time.Hour))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(statusMessage).To(Equal("failure"))
+}
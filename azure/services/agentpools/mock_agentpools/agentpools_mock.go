@@ -127,6 +127,34 @@ func (mr *MockAgentPoolScopeMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockAgentPoolScope)(nil).BaseURI))
 }
 
+// BootstrapDataKeyVault mocks base method.
+func (m *MockAgentPoolScope) BootstrapDataKeyVault() *v1beta1.BootstrapDataKeyVault {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataKeyVault")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataKeyVault)
+	return ret0
+}
+
+// BootstrapDataKeyVault indicates an expected call of BootstrapDataKeyVault.
+func (mr *MockAgentPoolScopeMockRecorder) BootstrapDataKeyVault() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataKeyVault", reflect.TypeOf((*MockAgentPoolScope)(nil).BootstrapDataKeyVault))
+}
+
+// BootstrapDataStorageAccountName mocks base method.
+func (m *MockAgentPoolScope) BootstrapDataStorageAccountName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorageAccountName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BootstrapDataStorageAccountName indicates an expected call of BootstrapDataStorageAccountName.
+func (mr *MockAgentPoolScopeMockRecorder) BootstrapDataStorageAccountName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorageAccountName", reflect.TypeOf((*MockAgentPoolScope)(nil).BootstrapDataStorageAccountName))
+}
+
 // ClientID mocks base method.
 func (m *MockAgentPoolScope) ClientID() string {
 	m.ctrl.T.Helper()
@@ -307,6 +335,20 @@ func (mr *MockAgentPoolScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockAgentPoolScope)(nil).Location))
 }
 
+// ManagedIdentityProviderID mocks base method.
+func (m *MockAgentPoolScope) ManagedIdentityProviderID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedIdentityProviderID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ManagedIdentityProviderID indicates an expected call of ManagedIdentityProviderID.
+func (mr *MockAgentPoolScopeMockRecorder) ManagedIdentityProviderID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedIdentityProviderID", reflect.TypeOf((*MockAgentPoolScope)(nil).ManagedIdentityProviderID))
+}
+
 // Name mocks base method.
 func (m *MockAgentPoolScope) Name() string {
 	m.ctrl.T.Helper()
@@ -335,6 +377,20 @@ func (mr *MockAgentPoolScopeMockRecorder) NodeResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeResourceGroup", reflect.TypeOf((*MockAgentPoolScope)(nil).NodeResourceGroup))
 }
 
+// PlatformFaultDomainCount mocks base method.
+func (m *MockAgentPoolScope) PlatformFaultDomainCount() *int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlatformFaultDomainCount")
+	ret0, _ := ret[0].(*int32)
+	return ret0
+}
+
+// PlatformFaultDomainCount indicates an expected call of PlatformFaultDomainCount.
+func (mr *MockAgentPoolScopeMockRecorder) PlatformFaultDomainCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlatformFaultDomainCount", reflect.TypeOf((*MockAgentPoolScope)(nil).PlatformFaultDomainCount))
+}
+
 // RemoveCAPIMachinePoolAnnotation mocks base method.
 func (m *MockAgentPoolScope) RemoveCAPIMachinePoolAnnotation(key string) {
 	m.ctrl.T.Helper()
@@ -361,6 +417,34 @@ func (mr *MockAgentPoolScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockAgentPoolScope)(nil).ResourceGroup))
 }
 
+// SSHKeyPairSecretName mocks base method.
+func (m *MockAgentPoolScope) SSHKeyPairSecretName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHKeyPairSecretName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SSHKeyPairSecretName indicates an expected call of SSHKeyPairSecretName.
+func (mr *MockAgentPoolScopeMockRecorder) SSHKeyPairSecretName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHKeyPairSecretName", reflect.TypeOf((*MockAgentPoolScope)(nil).SSHKeyPairSecretName))
+}
+
+// SSHPublicKeys mocks base method.
+func (m *MockAgentPoolScope) SSHPublicKeys(role string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHPublicKeys", role)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SSHPublicKeys indicates an expected call of SSHPublicKeys.
+func (mr *MockAgentPoolScopeMockRecorder) SSHPublicKeys(role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHPublicKeys", reflect.TypeOf((*MockAgentPoolScope)(nil).SSHPublicKeys), role)
+}
+
 // SetAgentPoolProviderIDList mocks base method.
 func (m *MockAgentPoolScope) SetAgentPoolProviderIDList(arg0 []string) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,746 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../applicationgateways.go
+//
+// Generated by this command:
+//
+//	mockgen -destination applicationgateways_mock.go -package mock_applicationgateways -source ../applicationgateways.go ApplicationGatewayScope
+//
+// Package mock_applicationgateways is a generated GoMock package.
+package mock_applicationgateways
+
+import (
+	reflect "reflect"
+
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	gomock "go.uber.org/mock/gomock"
+	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+	v1beta10 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// MockApplicationGatewayScope is a mock of ApplicationGatewayScope interface.
+type MockApplicationGatewayScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockApplicationGatewayScopeMockRecorder
+}
+
+// MockApplicationGatewayScopeMockRecorder is the mock recorder for MockApplicationGatewayScope.
+type MockApplicationGatewayScopeMockRecorder struct {
+	mock *MockApplicationGatewayScope
+}
+
+// NewMockApplicationGatewayScope creates a new mock instance.
+func NewMockApplicationGatewayScope(ctrl *gomock.Controller) *MockApplicationGatewayScope {
+	mock := &MockApplicationGatewayScope{ctrl: ctrl}
+	mock.recorder = &MockApplicationGatewayScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApplicationGatewayScope) EXPECT() *MockApplicationGatewayScopeMockRecorder {
+	return m.recorder
+}
+
+// APIServerLB mocks base method.
+func (m *MockApplicationGatewayScope) APIServerLB() *v1beta1.LoadBalancerSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIServerLB")
+	ret0, _ := ret[0].(*v1beta1.LoadBalancerSpec)
+	return ret0
+}
+
+// APIServerLB indicates an expected call of APIServerLB.
+func (mr *MockApplicationGatewayScopeMockRecorder) APIServerLB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerLB", reflect.TypeOf((*MockApplicationGatewayScope)(nil).APIServerLB))
+}
+
+// APIServerLBName mocks base method.
+func (m *MockApplicationGatewayScope) APIServerLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIServerLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// APIServerLBName indicates an expected call of APIServerLBName.
+func (mr *MockApplicationGatewayScopeMockRecorder) APIServerLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerLBName", reflect.TypeOf((*MockApplicationGatewayScope)(nil).APIServerLBName))
+}
+
+// APIServerLBPoolName mocks base method.
+func (m *MockApplicationGatewayScope) APIServerLBPoolName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIServerLBPoolName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// APIServerLBPoolName indicates an expected call of APIServerLBPoolName.
+func (mr *MockApplicationGatewayScopeMockRecorder) APIServerLBPoolName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerLBPoolName", reflect.TypeOf((*MockApplicationGatewayScope)(nil).APIServerLBPoolName))
+}
+
+// AdditionalTags mocks base method.
+func (m *MockApplicationGatewayScope) AdditionalTags() v1beta1.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdditionalTags")
+	ret0, _ := ret[0].(v1beta1.Tags)
+	return ret0
+}
+
+// AdditionalTags indicates an expected call of AdditionalTags.
+func (mr *MockApplicationGatewayScopeMockRecorder) AdditionalTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockApplicationGatewayScope)(nil).AdditionalTags))
+}
+
+// AvailabilitySetEnabled mocks base method.
+func (m *MockApplicationGatewayScope) AvailabilitySetEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AvailabilitySetEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// AvailabilitySetEnabled indicates an expected call of AvailabilitySetEnabled.
+func (mr *MockApplicationGatewayScopeMockRecorder) AvailabilitySetEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AvailabilitySetEnabled", reflect.TypeOf((*MockApplicationGatewayScope)(nil).AvailabilitySetEnabled))
+}
+
+// BaseURI mocks base method.
+func (m *MockApplicationGatewayScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockApplicationGatewayScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockApplicationGatewayScope)(nil).BaseURI))
+}
+
+// BootstrapDataKeyVault mocks base method.
+func (m *MockApplicationGatewayScope) BootstrapDataKeyVault() *v1beta1.BootstrapDataKeyVault {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataKeyVault")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataKeyVault)
+	return ret0
+}
+
+// BootstrapDataKeyVault indicates an expected call of BootstrapDataKeyVault.
+func (mr *MockApplicationGatewayScopeMockRecorder) BootstrapDataKeyVault() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataKeyVault", reflect.TypeOf((*MockApplicationGatewayScope)(nil).BootstrapDataKeyVault))
+}
+
+// BootstrapDataStorageAccountName mocks base method.
+func (m *MockApplicationGatewayScope) BootstrapDataStorageAccountName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorageAccountName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BootstrapDataStorageAccountName indicates an expected call of BootstrapDataStorageAccountName.
+func (mr *MockApplicationGatewayScopeMockRecorder) BootstrapDataStorageAccountName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorageAccountName", reflect.TypeOf((*MockApplicationGatewayScope)(nil).BootstrapDataStorageAccountName))
+}
+
+// ClientID mocks base method.
+func (m *MockApplicationGatewayScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockApplicationGatewayScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockApplicationGatewayScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockApplicationGatewayScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockApplicationGatewayScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockApplicationGatewayScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockApplicationGatewayScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockApplicationGatewayScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockApplicationGatewayScope)(nil).CloudEnvironment))
+}
+
+// CloudProviderConfigOverrides mocks base method.
+func (m *MockApplicationGatewayScope) CloudProviderConfigOverrides() *v1beta1.CloudProviderConfigOverrides {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudProviderConfigOverrides")
+	ret0, _ := ret[0].(*v1beta1.CloudProviderConfigOverrides)
+	return ret0
+}
+
+// CloudProviderConfigOverrides indicates an expected call of CloudProviderConfigOverrides.
+func (mr *MockApplicationGatewayScopeMockRecorder) CloudProviderConfigOverrides() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudProviderConfigOverrides", reflect.TypeOf((*MockApplicationGatewayScope)(nil).CloudProviderConfigOverrides))
+}
+
+// ClusterName mocks base method.
+func (m *MockApplicationGatewayScope) ClusterName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClusterName indicates an expected call of ClusterName.
+func (mr *MockApplicationGatewayScopeMockRecorder) ClusterName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockApplicationGatewayScope)(nil).ClusterName))
+}
+
+// ControlPlaneRouteTable mocks base method.
+func (m *MockApplicationGatewayScope) ControlPlaneRouteTable() v1beta1.RouteTable {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneRouteTable")
+	ret0, _ := ret[0].(v1beta1.RouteTable)
+	return ret0
+}
+
+// ControlPlaneRouteTable indicates an expected call of ControlPlaneRouteTable.
+func (mr *MockApplicationGatewayScopeMockRecorder) ControlPlaneRouteTable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneRouteTable", reflect.TypeOf((*MockApplicationGatewayScope)(nil).ControlPlaneRouteTable))
+}
+
+// ControlPlaneSubnet mocks base method.
+func (m *MockApplicationGatewayScope) ControlPlaneSubnet() v1beta1.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnet")
+	ret0, _ := ret[0].(v1beta1.SubnetSpec)
+	return ret0
+}
+
+// ControlPlaneSubnet indicates an expected call of ControlPlaneSubnet.
+func (mr *MockApplicationGatewayScopeMockRecorder) ControlPlaneSubnet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockApplicationGatewayScope)(nil).ControlPlaneSubnet))
+}
+
+// DeleteLongRunningOperationState mocks base method.
+func (m *MockApplicationGatewayScope) DeleteLongRunningOperationState(arg0, arg1, arg2 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLongRunningOperationState", arg0, arg1, arg2)
+}
+
+// DeleteLongRunningOperationState indicates an expected call of DeleteLongRunningOperationState.
+func (mr *MockApplicationGatewayScopeMockRecorder) DeleteLongRunningOperationState(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockApplicationGatewayScope)(nil).DeleteLongRunningOperationState), arg0, arg1, arg2)
+}
+
+// ExtendedLocation mocks base method.
+func (m *MockApplicationGatewayScope) ExtendedLocation() *v1beta1.ExtendedLocationSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendedLocation")
+	ret0, _ := ret[0].(*v1beta1.ExtendedLocationSpec)
+	return ret0
+}
+
+// ExtendedLocation indicates an expected call of ExtendedLocation.
+func (mr *MockApplicationGatewayScopeMockRecorder) ExtendedLocation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendedLocation", reflect.TypeOf((*MockApplicationGatewayScope)(nil).ExtendedLocation))
+}
+
+// ExtendedLocationName mocks base method.
+func (m *MockApplicationGatewayScope) ExtendedLocationName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendedLocationName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ExtendedLocationName indicates an expected call of ExtendedLocationName.
+func (mr *MockApplicationGatewayScopeMockRecorder) ExtendedLocationName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendedLocationName", reflect.TypeOf((*MockApplicationGatewayScope)(nil).ExtendedLocationName))
+}
+
+// ExtendedLocationType mocks base method.
+func (m *MockApplicationGatewayScope) ExtendedLocationType() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendedLocationType")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ExtendedLocationType indicates an expected call of ExtendedLocationType.
+func (mr *MockApplicationGatewayScopeMockRecorder) ExtendedLocationType() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendedLocationType", reflect.TypeOf((*MockApplicationGatewayScope)(nil).ExtendedLocationType))
+}
+
+// FailureDomains mocks base method.
+func (m *MockApplicationGatewayScope) FailureDomains() []*string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailureDomains")
+	ret0, _ := ret[0].([]*string)
+	return ret0
+}
+
+// FailureDomains indicates an expected call of FailureDomains.
+func (mr *MockApplicationGatewayScopeMockRecorder) FailureDomains() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureDomains", reflect.TypeOf((*MockApplicationGatewayScope)(nil).FailureDomains))
+}
+
+// GetLongRunningOperationState mocks base method.
+func (m *MockApplicationGatewayScope) GetLongRunningOperationState(arg0, arg1, arg2 string) *v1beta1.Future {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongRunningOperationState", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	return ret0
+}
+
+// GetLongRunningOperationState indicates an expected call of GetLongRunningOperationState.
+func (mr *MockApplicationGatewayScopeMockRecorder) GetLongRunningOperationState(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongRunningOperationState", reflect.TypeOf((*MockApplicationGatewayScope)(nil).GetLongRunningOperationState), arg0, arg1, arg2)
+}
+
+// GetPrivateDNSZoneName mocks base method.
+func (m *MockApplicationGatewayScope) GetPrivateDNSZoneName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrivateDNSZoneName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetPrivateDNSZoneName indicates an expected call of GetPrivateDNSZoneName.
+func (mr *MockApplicationGatewayScopeMockRecorder) GetPrivateDNSZoneName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateDNSZoneName", reflect.TypeOf((*MockApplicationGatewayScope)(nil).GetPrivateDNSZoneName))
+}
+
+// HashKey mocks base method.
+func (m *MockApplicationGatewayScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockApplicationGatewayScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockApplicationGatewayScope)(nil).HashKey))
+}
+
+// IsAPIServerPrivate mocks base method.
+func (m *MockApplicationGatewayScope) IsAPIServerPrivate() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAPIServerPrivate")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAPIServerPrivate indicates an expected call of IsAPIServerPrivate.
+func (mr *MockApplicationGatewayScopeMockRecorder) IsAPIServerPrivate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAPIServerPrivate", reflect.TypeOf((*MockApplicationGatewayScope)(nil).IsAPIServerPrivate))
+}
+
+// IsIPv6Enabled mocks base method.
+func (m *MockApplicationGatewayScope) IsIPv6Enabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsIPv6Enabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsIPv6Enabled indicates an expected call of IsIPv6Enabled.
+func (mr *MockApplicationGatewayScopeMockRecorder) IsIPv6Enabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsIPv6Enabled", reflect.TypeOf((*MockApplicationGatewayScope)(nil).IsIPv6Enabled))
+}
+
+// IsVnetManaged mocks base method.
+func (m *MockApplicationGatewayScope) IsVnetManaged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsVnetManaged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsVnetManaged indicates an expected call of IsVnetManaged.
+func (mr *MockApplicationGatewayScopeMockRecorder) IsVnetManaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsVnetManaged", reflect.TypeOf((*MockApplicationGatewayScope)(nil).IsVnetManaged))
+}
+
+// Location mocks base method.
+func (m *MockApplicationGatewayScope) Location() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Location")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Location indicates an expected call of Location.
+func (mr *MockApplicationGatewayScopeMockRecorder) Location() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockApplicationGatewayScope)(nil).Location))
+}
+
+// ManagedIdentityProviderID mocks base method.
+func (m *MockApplicationGatewayScope) ManagedIdentityProviderID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedIdentityProviderID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ManagedIdentityProviderID indicates an expected call of ManagedIdentityProviderID.
+func (mr *MockApplicationGatewayScopeMockRecorder) ManagedIdentityProviderID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedIdentityProviderID", reflect.TypeOf((*MockApplicationGatewayScope)(nil).ManagedIdentityProviderID))
+}
+
+// NodeInternalLBName mocks base method.
+func (m *MockApplicationGatewayScope) NodeInternalLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeInternalLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeInternalLBName indicates an expected call of NodeInternalLBName.
+func (mr *MockApplicationGatewayScopeMockRecorder) NodeInternalLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeInternalLBName", reflect.TypeOf((*MockApplicationGatewayScope)(nil).NodeInternalLBName))
+}
+
+// NodeInternalLBPoolName mocks base method.
+func (m *MockApplicationGatewayScope) NodeInternalLBPoolName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeInternalLBPoolName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeInternalLBPoolName indicates an expected call of NodeInternalLBPoolName.
+func (mr *MockApplicationGatewayScopeMockRecorder) NodeInternalLBPoolName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeInternalLBPoolName", reflect.TypeOf((*MockApplicationGatewayScope)(nil).NodeInternalLBPoolName))
+}
+
+// NodeRoutes mocks base method.
+func (m *MockApplicationGatewayScope) NodeRoutes() *v1beta1.NodeRoutesSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeRoutes")
+	ret0, _ := ret[0].(*v1beta1.NodeRoutesSpec)
+	return ret0
+}
+
+// NodeRoutes indicates an expected call of NodeRoutes.
+func (mr *MockApplicationGatewayScopeMockRecorder) NodeRoutes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeRoutes", reflect.TypeOf((*MockApplicationGatewayScope)(nil).NodeRoutes))
+}
+
+// NodeSubnets mocks base method.
+func (m *MockApplicationGatewayScope) NodeSubnets() []v1beta1.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeSubnets")
+	ret0, _ := ret[0].([]v1beta1.SubnetSpec)
+	return ret0
+}
+
+// NodeSubnets indicates an expected call of NodeSubnets.
+func (mr *MockApplicationGatewayScopeMockRecorder) NodeSubnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnets", reflect.TypeOf((*MockApplicationGatewayScope)(nil).NodeSubnets))
+}
+
+// OutboundLBName mocks base method.
+func (m *MockApplicationGatewayScope) OutboundLBName(arg0 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OutboundLBName", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// OutboundLBName indicates an expected call of OutboundLBName.
+func (mr *MockApplicationGatewayScopeMockRecorder) OutboundLBName(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboundLBName", reflect.TypeOf((*MockApplicationGatewayScope)(nil).OutboundLBName), arg0)
+}
+
+// OutboundPoolName mocks base method.
+func (m *MockApplicationGatewayScope) OutboundPoolName(arg0 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OutboundPoolName", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// OutboundPoolName indicates an expected call of OutboundPoolName.
+func (mr *MockApplicationGatewayScopeMockRecorder) OutboundPoolName(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboundPoolName", reflect.TypeOf((*MockApplicationGatewayScope)(nil).OutboundPoolName), arg0)
+}
+
+// PlatformFaultDomainCount mocks base method.
+func (m *MockApplicationGatewayScope) PlatformFaultDomainCount() *int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlatformFaultDomainCount")
+	ret0, _ := ret[0].(*int32)
+	return ret0
+}
+
+// PlatformFaultDomainCount indicates an expected call of PlatformFaultDomainCount.
+func (mr *MockApplicationGatewayScopeMockRecorder) PlatformFaultDomainCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlatformFaultDomainCount", reflect.TypeOf((*MockApplicationGatewayScope)(nil).PlatformFaultDomainCount))
+}
+
+// ResourceGroup mocks base method.
+func (m *MockApplicationGatewayScope) ResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceGroup indicates an expected call of ResourceGroup.
+func (mr *MockApplicationGatewayScopeMockRecorder) ResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockApplicationGatewayScope)(nil).ResourceGroup))
+}
+
+// SSHKeyPairSecretName mocks base method.
+func (m *MockApplicationGatewayScope) SSHKeyPairSecretName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHKeyPairSecretName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SSHKeyPairSecretName indicates an expected call of SSHKeyPairSecretName.
+func (mr *MockApplicationGatewayScopeMockRecorder) SSHKeyPairSecretName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHKeyPairSecretName", reflect.TypeOf((*MockApplicationGatewayScope)(nil).SSHKeyPairSecretName))
+}
+
+// SSHPublicKeys mocks base method.
+func (m *MockApplicationGatewayScope) SSHPublicKeys(role string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHPublicKeys", role)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SSHPublicKeys indicates an expected call of SSHPublicKeys.
+func (mr *MockApplicationGatewayScopeMockRecorder) SSHPublicKeys(role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHPublicKeys", reflect.TypeOf((*MockApplicationGatewayScope)(nil).SSHPublicKeys), role)
+}
+
+// SetLongRunningOperationState mocks base method.
+func (m *MockApplicationGatewayScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLongRunningOperationState", arg0)
+}
+
+// SetLongRunningOperationState indicates an expected call of SetLongRunningOperationState.
+func (mr *MockApplicationGatewayScopeMockRecorder) SetLongRunningOperationState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockApplicationGatewayScope)(nil).SetLongRunningOperationState), arg0)
+}
+
+// SetSubnet mocks base method.
+func (m *MockApplicationGatewayScope) SetSubnet(arg0 v1beta1.SubnetSpec) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetSubnet", arg0)
+}
+
+// SetSubnet indicates an expected call of SetSubnet.
+func (mr *MockApplicationGatewayScopeMockRecorder) SetSubnet(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubnet", reflect.TypeOf((*MockApplicationGatewayScope)(nil).SetSubnet), arg0)
+}
+
+// Subnet mocks base method.
+func (m *MockApplicationGatewayScope) Subnet(arg0 string) v1beta1.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subnet", arg0)
+	ret0, _ := ret[0].(v1beta1.SubnetSpec)
+	return ret0
+}
+
+// Subnet indicates an expected call of Subnet.
+func (mr *MockApplicationGatewayScopeMockRecorder) Subnet(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subnet", reflect.TypeOf((*MockApplicationGatewayScope)(nil).Subnet), arg0)
+}
+
+// Subnets mocks base method.
+func (m *MockApplicationGatewayScope) Subnets() v1beta1.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subnets")
+	ret0, _ := ret[0].(v1beta1.Subnets)
+	return ret0
+}
+
+// Subnets indicates an expected call of Subnets.
+func (mr *MockApplicationGatewayScopeMockRecorder) Subnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subnets", reflect.TypeOf((*MockApplicationGatewayScope)(nil).Subnets))
+}
+
+// SubscriptionID mocks base method.
+func (m *MockApplicationGatewayScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockApplicationGatewayScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockApplicationGatewayScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockApplicationGatewayScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockApplicationGatewayScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockApplicationGatewayScope)(nil).TenantID))
+}
+
+// Token mocks base method.
+func (m *MockApplicationGatewayScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockApplicationGatewayScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockApplicationGatewayScope)(nil).Token))
+}
+
+// UpdateDeleteStatus mocks base method.
+func (m *MockApplicationGatewayScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateDeleteStatus", arg0, arg1, arg2)
+}
+
+// UpdateDeleteStatus indicates an expected call of UpdateDeleteStatus.
+func (mr *MockApplicationGatewayScopeMockRecorder) UpdateDeleteStatus(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDeleteStatus", reflect.TypeOf((*MockApplicationGatewayScope)(nil).UpdateDeleteStatus), arg0, arg1, arg2)
+}
+
+// UpdatePatchStatus mocks base method.
+func (m *MockApplicationGatewayScope) UpdatePatchStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePatchStatus", arg0, arg1, arg2)
+}
+
+// UpdatePatchStatus indicates an expected call of UpdatePatchStatus.
+func (mr *MockApplicationGatewayScopeMockRecorder) UpdatePatchStatus(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePatchStatus", reflect.TypeOf((*MockApplicationGatewayScope)(nil).UpdatePatchStatus), arg0, arg1, arg2)
+}
+
+// UpdatePutStatus mocks base method.
+func (m *MockApplicationGatewayScope) UpdatePutStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePutStatus", arg0, arg1, arg2)
+}
+
+// UpdatePutStatus indicates an expected call of UpdatePutStatus.
+func (mr *MockApplicationGatewayScopeMockRecorder) UpdatePutStatus(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePutStatus", reflect.TypeOf((*MockApplicationGatewayScope)(nil).UpdatePutStatus), arg0, arg1, arg2)
+}
+
+// ApplicationGatewaySpec mocks base method.
+func (m *MockApplicationGatewayScope) ApplicationGatewaySpec() azure.ResourceSpecGetter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationGatewaySpec")
+	ret0, _ := ret[0].(azure.ResourceSpecGetter)
+	return ret0
+}
+
+// ApplicationGatewaySpec indicates an expected call of ApplicationGatewaySpec.
+func (mr *MockApplicationGatewayScopeMockRecorder) ApplicationGatewaySpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationGatewaySpec", reflect.TypeOf((*MockApplicationGatewayScope)(nil).ApplicationGatewaySpec))
+}
+
+// Vnet mocks base method.
+func (m *MockApplicationGatewayScope) Vnet() *v1beta1.VnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Vnet")
+	ret0, _ := ret[0].(*v1beta1.VnetSpec)
+	return ret0
+}
+
+// Vnet indicates an expected call of Vnet.
+func (mr *MockApplicationGatewayScopeMockRecorder) Vnet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vnet", reflect.TypeOf((*MockApplicationGatewayScope)(nil).Vnet))
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationgateways
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+const (
+	gatewayIPConfigurationName  = "appGatewayIpConfig"
+	frontendIPConfigurationName = "appGatewayFrontendIP"
+	frontendPortName            = "port_80"
+	backendAddressPoolName      = "defaultaddresspool"
+	backendHTTPSettingsName     = "defaulthttpsetting"
+	httpListenerName            = "defaulthttplistener"
+	requestRoutingRuleName      = "defaultrule"
+	owaspRuleSetType            = "OWASP"
+)
+
+// ApplicationGatewaySpec defines the specification for an application gateway.
+type ApplicationGatewaySpec struct {
+	Name           string
+	ResourceGroup  string
+	SubscriptionID string
+	Location       string
+	ClusterName    string
+	SubnetID       string
+	PublicIPID     string
+	FailureDomains []*string
+	MinCapacity    int32
+	MaxCapacity    *int32
+	WAF            *infrav1.WebApplicationFirewallSpec
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the application gateway.
+func (s *ApplicationGatewaySpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *ApplicationGatewaySpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for application gateways.
+func (s *ApplicationGatewaySpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the application gateway.
+func (s *ApplicationGatewaySpec) Parameters(ctx context.Context, existing interface{}) (parameters interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(armnetwork.ApplicationGateway); !ok {
+			return nil, errors.Errorf("%T is not an armnetwork.ApplicationGateway", existing)
+		}
+		// The listeners, rules and backends of the application gateway are managed by the Application Gateway
+		// Ingress Controller once it is created, so it is never updated.
+		return nil, nil
+	}
+
+	// Azure requires an application gateway to be created with at least one listener routing to a backend pool,
+	// so it is created with a placeholder HTTP rule, which the ingress controller replaces.
+	sku := &armnetwork.ApplicationGatewaySKU{
+		Name: ptr.To(armnetwork.ApplicationGatewaySKUNameStandardV2),
+		Tier: ptr.To(armnetwork.ApplicationGatewayTierStandardV2),
+	}
+	var waf *armnetwork.ApplicationGatewayWebApplicationFirewallConfiguration
+	if s.WAF != nil {
+		sku = &armnetwork.ApplicationGatewaySKU{
+			Name: ptr.To(armnetwork.ApplicationGatewaySKUNameWAFV2),
+			Tier: ptr.To(armnetwork.ApplicationGatewayTierWAFV2),
+		}
+		waf = &armnetwork.ApplicationGatewayWebApplicationFirewallConfiguration{
+			Enabled:        ptr.To(true),
+			FirewallMode:   ptr.To(armnetwork.ApplicationGatewayFirewallMode(s.WAF.Mode)),
+			RuleSetType:    ptr.To(owaspRuleSetType),
+			RuleSetVersion: ptr.To(s.WAF.RuleSetVersion),
+		}
+	}
+
+	return armnetwork.ApplicationGateway{
+		Name:     ptr.To(s.Name),
+		Location: ptr.To(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Role:        ptr.To("ApplicationGateway"),
+			Additional:  s.AdditionalTags,
+		})),
+		Zones: s.FailureDomains,
+		Properties: &armnetwork.ApplicationGatewayPropertiesFormat{
+			SKU: sku,
+			AutoscaleConfiguration: &armnetwork.ApplicationGatewayAutoscaleConfiguration{
+				MinCapacity: ptr.To(s.MinCapacity),
+				MaxCapacity: s.MaxCapacity,
+			},
+			GatewayIPConfigurations: []*armnetwork.ApplicationGatewayIPConfiguration{
+				{
+					Name: ptr.To(gatewayIPConfigurationName),
+					Properties: &armnetwork.ApplicationGatewayIPConfigurationPropertiesFormat{
+						Subnet: &armnetwork.SubResource{ID: ptr.To(s.SubnetID)},
+					},
+				},
+			},
+			FrontendIPConfigurations: []*armnetwork.ApplicationGatewayFrontendIPConfiguration{
+				{
+					Name: ptr.To(frontendIPConfigurationName),
+					Properties: &armnetwork.ApplicationGatewayFrontendIPConfigurationPropertiesFormat{
+						PublicIPAddress: &armnetwork.SubResource{ID: ptr.To(s.PublicIPID)},
+					},
+				},
+			},
+			FrontendPorts: []*armnetwork.ApplicationGatewayFrontendPort{
+				{
+					Name: ptr.To(frontendPortName),
+					Properties: &armnetwork.ApplicationGatewayFrontendPortPropertiesFormat{
+						Port: ptr.To[int32](80),
+					},
+				},
+			},
+			BackendAddressPools: []*armnetwork.ApplicationGatewayBackendAddressPool{
+				{Name: ptr.To(backendAddressPoolName)},
+			},
+			BackendHTTPSettingsCollection: []*armnetwork.ApplicationGatewayBackendHTTPSettings{
+				{
+					Name: ptr.To(backendHTTPSettingsName),
+					Properties: &armnetwork.ApplicationGatewayBackendHTTPSettingsPropertiesFormat{
+						Port:                ptr.To[int32](80),
+						Protocol:            ptr.To(armnetwork.ApplicationGatewayProtocolHTTP),
+						CookieBasedAffinity: ptr.To(armnetwork.ApplicationGatewayCookieBasedAffinityDisabled),
+						RequestTimeout:      ptr.To[int32](30),
+					},
+				},
+			},
+			HTTPListeners: []*armnetwork.ApplicationGatewayHTTPListener{
+				{
+					Name: ptr.To(httpListenerName),
+					Properties: &armnetwork.ApplicationGatewayHTTPListenerPropertiesFormat{
+						FrontendIPConfiguration: &armnetwork.SubResource{ID: ptr.To(s.childID("frontendIPConfigurations", frontendIPConfigurationName))},
+						FrontendPort:            &armnetwork.SubResource{ID: ptr.To(s.childID("frontendPorts", frontendPortName))},
+						Protocol:                ptr.To(armnetwork.ApplicationGatewayProtocolHTTP),
+					},
+				},
+			},
+			RequestRoutingRules: []*armnetwork.ApplicationGatewayRequestRoutingRule{
+				{
+					Name: ptr.To(requestRoutingRuleName),
+					Properties: &armnetwork.ApplicationGatewayRequestRoutingRulePropertiesFormat{
+						RuleType:            ptr.To(armnetwork.ApplicationGatewayRequestRoutingRuleTypeBasic),
+						Priority:            ptr.To[int32](19500),
+						HTTPListener:        &armnetwork.SubResource{ID: ptr.To(s.childID("httpListeners", httpListenerName))},
+						BackendAddressPool:  &armnetwork.SubResource{ID: ptr.To(s.childID("backendAddressPools", backendAddressPoolName))},
+						BackendHTTPSettings: &armnetwork.SubResource{ID: ptr.To(s.childID("backendHttpSettingsCollection", backendHTTPSettingsName))},
+					},
+				},
+			},
+			WebApplicationFirewallConfiguration: waf,
+		},
+	}, nil
+}
+
+// childID returns the resource ID of a child resource of the application gateway.
+func (s *ApplicationGatewaySpec) childID(childType, name string) string {
+	return fmt.Sprintf("%s/%s/%s", azure.ApplicationGatewayID(s.SubscriptionID, s.ResourceGroup, s.Name), childType, name)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationgateways
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestApplicationGatewaySpec_Parameters(t *testing.T) {
+	appGatewayID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationGateways/my-appgw"
+	testCases := []struct {
+		name          string
+		spec          *ApplicationGatewaySpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name:     "error when existing gateway is not an ApplicationGateway",
+			spec:     &fakeGatewaySpec,
+			existing: struct{}{},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "struct {} is not an armnetwork.ApplicationGateway",
+		},
+		{
+			name: "nil when the gateway exists",
+			spec: &fakeGatewaySpec,
+			existing: armnetwork.ApplicationGateway{
+				Name: ptr.To("my-appgw"),
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "new gateway",
+			spec: &fakeGatewaySpec,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armnetwork.ApplicationGateway{
+					Name:     ptr.To("my-appgw"),
+					Location: ptr.To("westus"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_role":               ptr.To("ApplicationGateway"),
+						"Name": ptr.To("my-appgw"),
+					},
+					Properties: &armnetwork.ApplicationGatewayPropertiesFormat{
+						SKU: &armnetwork.ApplicationGatewaySKU{
+							Name: ptr.To(armnetwork.ApplicationGatewaySKUNameStandardV2),
+							Tier: ptr.To(armnetwork.ApplicationGatewayTierStandardV2),
+						},
+						AutoscaleConfiguration: &armnetwork.ApplicationGatewayAutoscaleConfiguration{
+							MinCapacity: ptr.To[int32](0),
+						},
+						GatewayIPConfigurations: []*armnetwork.ApplicationGatewayIPConfiguration{
+							{
+								Name: ptr.To("appGatewayIpConfig"),
+								Properties: &armnetwork.ApplicationGatewayIPConfigurationPropertiesFormat{
+									Subnet: &armnetwork.SubResource{ID: ptr.To("my-subnet-id")},
+								},
+							},
+						},
+						FrontendIPConfigurations: []*armnetwork.ApplicationGatewayFrontendIPConfiguration{
+							{
+								Name: ptr.To("appGatewayFrontendIP"),
+								Properties: &armnetwork.ApplicationGatewayFrontendIPConfigurationPropertiesFormat{
+									PublicIPAddress: &armnetwork.SubResource{ID: ptr.To("my-public-ip-id")},
+								},
+							},
+						},
+						FrontendPorts: []*armnetwork.ApplicationGatewayFrontendPort{
+							{
+								Name: ptr.To("port_80"),
+								Properties: &armnetwork.ApplicationGatewayFrontendPortPropertiesFormat{
+									Port: ptr.To[int32](80),
+								},
+							},
+						},
+						BackendAddressPools: []*armnetwork.ApplicationGatewayBackendAddressPool{
+							{Name: ptr.To("defaultaddresspool")},
+						},
+						BackendHTTPSettingsCollection: []*armnetwork.ApplicationGatewayBackendHTTPSettings{
+							{
+								Name: ptr.To("defaulthttpsetting"),
+								Properties: &armnetwork.ApplicationGatewayBackendHTTPSettingsPropertiesFormat{
+									Port:                ptr.To[int32](80),
+									Protocol:            ptr.To(armnetwork.ApplicationGatewayProtocolHTTP),
+									CookieBasedAffinity: ptr.To(armnetwork.ApplicationGatewayCookieBasedAffinityDisabled),
+									RequestTimeout:      ptr.To[int32](30),
+								},
+							},
+						},
+						HTTPListeners: []*armnetwork.ApplicationGatewayHTTPListener{
+							{
+								Name: ptr.To("defaulthttplistener"),
+								Properties: &armnetwork.ApplicationGatewayHTTPListenerPropertiesFormat{
+									FrontendIPConfiguration: &armnetwork.SubResource{ID: ptr.To(appGatewayID + "/frontendIPConfigurations/appGatewayFrontendIP")},
+									FrontendPort:            &armnetwork.SubResource{ID: ptr.To(appGatewayID + "/frontendPorts/port_80")},
+									Protocol:                ptr.To(armnetwork.ApplicationGatewayProtocolHTTP),
+								},
+							},
+						},
+						RequestRoutingRules: []*armnetwork.ApplicationGatewayRequestRoutingRule{
+							{
+								Name: ptr.To("defaultrule"),
+								Properties: &armnetwork.ApplicationGatewayRequestRoutingRulePropertiesFormat{
+									RuleType:            ptr.To(armnetwork.ApplicationGatewayRequestRoutingRuleTypeBasic),
+									Priority:            ptr.To[int32](19500),
+									HTTPListener:        &armnetwork.SubResource{ID: ptr.To(appGatewayID + "/httpListeners/defaulthttplistener")},
+									BackendAddressPool:  &armnetwork.SubResource{ID: ptr.To(appGatewayID + "/backendAddressPools/defaultaddresspool")},
+									BackendHTTPSettings: &armnetwork.SubResource{ID: ptr.To(appGatewayID + "/backendHttpSettingsCollection/defaulthttpsetting")},
+								},
+							},
+						},
+					},
+				}))
+			},
+		},
+		{
+			name: "new gateway with a web application firewall",
+			spec: &ApplicationGatewaySpec{
+				Name:           "my-appgw",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				FailureDomains: []*string{ptr.To("1"), ptr.To("2"), ptr.To("3")},
+				MinCapacity:    2,
+				MaxCapacity:    ptr.To[int32](10),
+				WAF: &infrav1.WebApplicationFirewallSpec{
+					Mode:           infrav1.WebApplicationFirewallModePrevention,
+					RuleSetVersion: "3.2",
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.ApplicationGateway{}))
+				appGateway := result.(armnetwork.ApplicationGateway)
+				g.Expect(appGateway.Zones).To(Equal([]*string{ptr.To("1"), ptr.To("2"), ptr.To("3")}))
+				g.Expect(appGateway.Properties.SKU).To(Equal(&armnetwork.ApplicationGatewaySKU{
+					Name: ptr.To(armnetwork.ApplicationGatewaySKUNameWAFV2),
+					Tier: ptr.To(armnetwork.ApplicationGatewayTierWAFV2),
+				}))
+				g.Expect(appGateway.Properties.AutoscaleConfiguration).To(Equal(&armnetwork.ApplicationGatewayAutoscaleConfiguration{
+					MinCapacity: ptr.To[int32](2),
+					MaxCapacity: ptr.To[int32](10),
+				}))
+				g.Expect(appGateway.Properties.WebApplicationFirewallConfiguration).To(Equal(&armnetwork.ApplicationGatewayWebApplicationFirewallConfiguration{
+					Enabled:        ptr.To(true),
+					FirewallMode:   ptr.To(armnetwork.ApplicationGatewayFirewallModePrevention),
+					RuleSetType:    ptr.To("OWASP"),
+					RuleSetVersion: ptr.To("3.2"),
+				}))
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			tc.expect(g, result)
+		})
+	}
+}
@@ -214,6 +214,18 @@ func (r *reconciler[T]) CreateOrUpdateResource(ctx context.Context, spec azure.A
 		return existing, nil
 	}
 
+	// In plan mode, record the operation and return the desired resource instead of creating or patching the
+	// ASO resource, so the services can carry on computing the operations of the resources depending on it.
+	if plan := azure.PlanFromContext(ctx); plan != nil {
+		opType := infrav1.PlannedOperationCreate
+		if resourceExists {
+			opType = infrav1.PlannedOperationUpdate
+		}
+		log.V(2).Info("planning operation", "operation", opType, "diff", diff)
+		plan.Record(opType, serviceName, resourceNamespace, resourceName)
+		return parameters, nil
+	}
+
 	// Create or update the resource with the desired parameters.
 	logMessageVerbPrefix := "creat"
 	if resourceExists {
@@ -266,6 +278,15 @@ func (r *reconciler[T]) DeleteResource(ctx context.Context, spec azure.ASOResour
 		return nil
 	}
 
+	// In plan mode, record the deletion unless it is already in progress, and leave the resource alone.
+	if plan := azure.PlanFromContext(ctx); plan != nil {
+		if resource.GetDeletionTimestamp().IsZero() {
+			log.V(2).Info("planning operation", "operation", infrav1.PlannedOperationDelete)
+			plan.Record(infrav1.PlannedOperationDelete, serviceName, resourceNamespace, resourceName)
+		}
+		return nil
+	}
+
 	log.V(2).Info("deleting resource")
 	err = r.Client.Delete(ctx, resource)
 	if err != nil {
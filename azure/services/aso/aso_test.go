@@ -26,6 +26,7 @@ import (
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -390,6 +391,100 @@ func TestCreateOrUpdateResource(t *testing.T) {
 		g.Expect(err).NotTo(BeNil())
 	})
 
+	t.Run("plan create does not create the resource", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		sch := runtime.NewScheme()
+		g.Expect(asoresourcesv1.AddToScheme(sch)).To(Succeed())
+		c := fakeclient.NewClientBuilder().
+			WithScheme(sch).
+			Build()
+		s := New[*asoresourcesv1.ResourceGroup](c, clusterName)
+
+		mockCtrl := gomock.NewController(t)
+		specMock := mock_azure.NewMockASOResourceSpecGetter[*asoresourcesv1.ResourceGroup](mockCtrl)
+		specMock.EXPECT().ResourceRef().Return(&asoresourcesv1.ResourceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "name",
+				Namespace: "namespace",
+			},
+		})
+		specMock.EXPECT().Parameters(gomockinternal.AContext(), gomock.Nil()).Return(&asoresourcesv1.ResourceGroup{
+			Spec: asoresourcesv1.ResourceGroup_Spec{
+				Location: ptr.To("location"),
+			},
+		}, nil)
+
+		plan := &azure.Plan{}
+		ctx := azure.WithPlan(context.Background(), plan)
+		result, err := s.CreateOrUpdateResource(ctx, specMock, "service")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.Spec.Location).To(Equal(ptr.To("location")))
+		g.Expect(plan.Operations()).To(Equal([]infrav1.PlannedOperation{
+			{Type: infrav1.PlannedOperationCreate, Service: "service", ResourceGroup: "namespace", Name: "name"},
+		}))
+
+		err = c.Get(ctx, types.NamespacedName{Name: "name", Namespace: "namespace"}, &asoresourcesv1.ResourceGroup{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	t.Run("plan update does not patch the resource", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		sch := runtime.NewScheme()
+		g.Expect(asoresourcesv1.AddToScheme(sch)).To(Succeed())
+		c := fakeclient.NewClientBuilder().
+			WithScheme(sch).
+			Build()
+		s := New[*asoresourcesv1.ResourceGroup](c, clusterName)
+
+		mockCtrl := gomock.NewController(t)
+		specMock := mock_azure.NewMockASOResourceSpecGetter[*asoresourcesv1.ResourceGroup](mockCtrl)
+		specMock.EXPECT().ResourceRef().Return(&asoresourcesv1.ResourceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "name",
+				Namespace: "namespace",
+			},
+		})
+		specMock.EXPECT().Parameters(gomockinternal.AContext(), gomock.Not(gomock.Nil())).DoAndReturn(func(_ context.Context, group *asoresourcesv1.ResourceGroup) (*asoresourcesv1.ResourceGroup, error) {
+			group.Spec.Location = ptr.To("location")
+			return group, nil
+		})
+		specMock.EXPECT().WasManaged(gomock.Any()).Return(false)
+
+		plan := &azure.Plan{}
+		ctx := azure.WithPlan(context.Background(), plan)
+		g.Expect(c.Create(ctx, &asoresourcesv1.ResourceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "name",
+				Namespace: "namespace",
+				Labels: map[string]string{
+					infrav1.OwnedByClusterLabelKey: clusterName,
+				},
+			},
+			Status: asoresourcesv1.ResourceGroup_STATUS{
+				Conditions: []conditions.Condition{
+					{
+						Type:   conditions.ConditionTypeReady,
+						Status: metav1.ConditionTrue,
+					},
+				},
+			},
+		})).To(Succeed())
+
+		result, err := s.CreateOrUpdateResource(ctx, specMock, "service")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.Spec.Location).To(Equal(ptr.To("location")))
+		g.Expect(plan.Operations()).To(Equal([]infrav1.PlannedOperation{
+			{Type: infrav1.PlannedOperationUpdate, Service: "service", ResourceGroup: "namespace", Name: "name"},
+		}))
+
+		existing := &asoresourcesv1.ResourceGroup{}
+		g.Expect(c.Get(ctx, types.NamespacedName{Name: "name", Namespace: "namespace"}, existing)).To(Succeed())
+		g.Expect(existing.Spec.Location).To(BeNil())
+		g.Expect(existing.Annotations).To(BeEmpty())
+	})
+
 	t.Run("adopt managed resource in not found state", func(t *testing.T) {
 		g := NewGomegaWithT(t)
 
@@ -943,6 +1038,45 @@ func TestDeleteResource(t *testing.T) {
 		g.Expect(recerr.IsTransient()).To(BeTrue())
 	})
 
+	t.Run("plan delete does not delete the resource", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		sch := runtime.NewScheme()
+		g.Expect(asoresourcesv1.AddToScheme(sch)).To(Succeed())
+		c := fakeclient.NewClientBuilder().
+			WithScheme(sch).
+			Build()
+		s := New[*asoresourcesv1.ResourceGroup](c, clusterName)
+
+		mockCtrl := gomock.NewController(t)
+		specMock := mock_azure.NewMockASOResourceSpecGetter[*asoresourcesv1.ResourceGroup](mockCtrl)
+		specMock.EXPECT().ResourceRef().Return(&asoresourcesv1.ResourceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "name",
+				Namespace: "namespace",
+			},
+		}).AnyTimes()
+
+		plan := &azure.Plan{}
+		ctx := azure.WithPlan(context.Background(), plan)
+		g.Expect(c.Create(ctx, &asoresourcesv1.ResourceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "name",
+				Namespace: "namespace",
+				Labels: map[string]string{
+					infrav1.OwnedByClusterLabelKey: clusterName,
+				},
+			},
+		})).To(Succeed())
+
+		err := s.DeleteResource(ctx, specMock, "service")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(plan.Operations()).To(Equal([]infrav1.PlannedOperation{
+			{Type: infrav1.PlannedOperationDelete, Service: "service", ResourceGroup: "namespace", Name: "name"},
+		}))
+		g.Expect(c.Get(ctx, types.NamespacedName{Name: "name", Namespace: "namespace"}, &asoresourcesv1.ResourceGroup{})).To(Succeed())
+	})
+
 	t.Run("skip delete for unmanaged resource", func(t *testing.T) {
 		g := NewGomegaWithT(t)
 
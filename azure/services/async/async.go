@@ -18,15 +18,15 @@ package async
 
 import (
 	"context"
-	"net/http"
-	"strconv"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/activitylogs"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -41,6 +41,8 @@ type Service[C, D any] struct {
 	Scope FutureScope
 	Creator[C]
 	Deleter[D]
+	// newActivityLogClient overrides the creation of the client describing failed operations.
+	newActivityLogClient func(azure.Authorizer) (activitylogs.Client, error)
 }
 
 // New creates an async Service.
@@ -54,17 +56,23 @@ func New[C, D any](scope FutureScope, createClient Creator[C], deleteClient Dele
 
 // CreateOrUpdateResource creates a new resource or updates an existing one asynchronously.
 func (s *Service[C, D]) CreateOrUpdateResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) (result interface{}, err error) {
-	ctx, log, done := tele.StartSpanWithLogger(ctx, "async.Service.CreateOrUpdateResource")
-	defer done()
-
 	resourceName := spec.ResourceName()
 	rgName := spec.ResourceGroupName()
 	futureType := infrav1.PutFuture
 
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "async.Service.CreateOrUpdateResource",
+		tele.KVP("service", serviceName),
+		tele.KVP("resource", resourceName),
+		tele.KVP("resourceGroup", rgName),
+	)
+	defer done()
+
 	// Check if there is an ongoing long-running operation.
 	resumeToken := ""
-	if future := s.Scope.GetLongRunningOperationState(resourceName, serviceName, futureType); future != nil {
-		t, err := converters.FutureToResumeToken(*future)
+	existingFuture := s.Scope.GetLongRunningOperationState(resourceName, serviceName, futureType)
+	start := operationStartTime(existingFuture)
+	if existingFuture != nil {
+		t, err := converters.FutureToResumeToken(*existingFuture)
 		if err != nil {
 			s.Scope.DeleteLongRunningOperationState(resourceName, serviceName, futureType)
 			return "", errors.Wrap(err, "could not decode future data, resetting long-running operation state")
@@ -80,7 +88,7 @@ func (s *Service[C, D]) CreateOrUpdateResource(ctx context.Context, spec azure.R
 		var existingResource interface{}
 		if existing, err := s.Creator.Get(ctx, spec); err != nil && !azure.ResourceNotFound(err) {
 			errWrapped := errors.Wrapf(err, "failed to get existing resource %s/%s (service: %s)", rgName, resourceName, serviceName)
-			return nil, azure.WithTransientError(errWrapped, getRetryAfterFromError(err))
+			return nil, azure.WithTransientError(errWrapped, requeueAfterError(err))
 		} else if err == nil {
 			existingResource = existing
 			log.V(2).Info("successfully got existing resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
@@ -96,6 +104,28 @@ func (s *Service[C, D]) CreateOrUpdateResource(ctx context.Context, spec azure.R
 			return existingResource, nil
 		}
 
+		// In plan mode, record the operation and return the desired resource in place of the result so the
+		// services can carry on computing the operations of the resources depending on it.
+		if plan := azure.PlanFromContext(ctx); plan != nil {
+			opType := infrav1.PlannedOperationCreate
+			if existingResource != nil {
+				opType = infrav1.PlannedOperationUpdate
+			}
+			log.V(2).Info("planning operation", "operation", opType, "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
+			plan.Record(opType, serviceName, rgName, resourceName)
+			return parameters, nil
+		}
+
+		// When the spec did not change, an existing resource which differs from its desired state was changed
+		// out-of-band. Record it, and leave it untouched unless the drift is to be corrected.
+		if drift := azure.DriftFromContext(ctx); drift != nil && existingResource != nil {
+			log.V(2).Info("resource changed out-of-band", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
+			drift.Record(serviceName, rgName, resourceName)
+			if !drift.Correct {
+				return existingResource, nil
+			}
+		}
+
 		// Create or update the resource with the desired parameters.
 		if existingResource != nil {
 			log.V(2).Info("updating resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
@@ -111,15 +141,25 @@ func (s *Service[C, D]) CreateOrUpdateResource(ctx context.Context, spec azure.R
 		if err != nil {
 			return nil, errWrapped
 		}
-		s.Scope.SetLongRunningOperationState(future)
+		s.setLongRunningOperationState(ctx, future, existingFuture)
 		return nil, azure.WithTransientError(azure.NewOperationNotDoneError(future), requeueTime())
 	}
 
 	// Once the operation is done, delete the long-running operation state. Even if the operation ended with
 	// an error, clear out any lingering state to try the operation again.
 	s.Scope.DeleteLongRunningOperationState(resourceName, serviceName, futureType)
+	observeOperationDuration(serviceName, futureType, start, err == nil)
 
 	if err != nil {
+		errWrapped = errors.Wrapf(s.describeFailure(ctx, err), "failed to create or update resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+		if azure.IsTerminalAzureError(err) {
+			// Retrying is pointless until a quota is raised or a policy is changed, which is checked again after a long
+			// interval. Services creating a single machine make these errors terminal failures of the machine instead.
+			return nil, azure.WithTransientError(errWrapped, reconciler.DefaultHopelessErrorRequeue)
+		}
+		if requeueAfter, ok := azure.RequeueAfterForError(err); ok {
+			return nil, azure.WithTransientError(errWrapped, requeueAfter)
+		}
 		return nil, errWrapped
 	}
 
@@ -129,17 +169,23 @@ func (s *Service[C, D]) CreateOrUpdateResource(ctx context.Context, spec azure.R
 
 // DeleteResource deletes a resource asynchronously.
 func (s *Service[C, D]) DeleteResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) (err error) {
-	ctx, log, done := tele.StartSpanWithLogger(ctx, "async.Service.DeleteResource")
-	defer done()
-
 	resourceName := spec.ResourceName()
 	rgName := spec.ResourceGroupName()
 	futureType := infrav1.DeleteFuture
 
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "async.Service.DeleteResource",
+		tele.KVP("service", serviceName),
+		tele.KVP("resource", resourceName),
+		tele.KVP("resourceGroup", rgName),
+	)
+	defer done()
+
 	// Check for an ongoing long-running operation.
 	resumeToken := ""
-	if future := s.Scope.GetLongRunningOperationState(resourceName, serviceName, futureType); future != nil {
-		t, err := converters.FutureToResumeToken(*future)
+	existingFuture := s.Scope.GetLongRunningOperationState(resourceName, serviceName, futureType)
+	start := operationStartTime(existingFuture)
+	if existingFuture != nil {
+		t, err := converters.FutureToResumeToken(*existingFuture)
 		if err != nil {
 			s.Scope.DeleteLongRunningOperationState(resourceName, serviceName, futureType)
 			return errors.Wrap(err, "could not decode future data, resetting long-running operation state")
@@ -147,6 +193,13 @@ func (s *Service[C, D]) DeleteResource(ctx context.Context, spec azure.ResourceS
 		resumeToken = t
 	}
 
+	// In plan mode, record the deletion unless it is already in progress.
+	if plan := azure.PlanFromContext(ctx); plan != nil && resumeToken == "" {
+		log.V(2).Info("planning operation", "operation", infrav1.PlannedOperationDelete, "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
+		plan.Record(infrav1.PlannedOperationDelete, serviceName, rgName, resourceName)
+		return nil
+	}
+
 	// Delete the resource.
 	log.V(2).Info("deleting resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
 	poller, err := s.Deleter.DeleteAsync(ctx, spec, resumeToken)
@@ -155,22 +208,68 @@ func (s *Service[C, D]) DeleteResource(ctx context.Context, spec azure.ResourceS
 		if err != nil {
 			return errors.Wrap(err, "failed to convert poller to future")
 		}
-		s.Scope.SetLongRunningOperationState(future)
+		s.setLongRunningOperationState(ctx, future, existingFuture)
 		return azure.WithTransientError(azure.NewOperationNotDoneError(future), requeueTime())
 	}
 
 	// Once the operation is done, delete the long-running operation state. Even if the operation ended with
 	// an error, clear out any lingering state to try the operation again.
 	s.Scope.DeleteLongRunningOperationState(resourceName, serviceName, futureType)
+	observeOperationDuration(serviceName, futureType, start, err == nil || azure.ResourceNotFound(err))
 
 	if err != nil && !azure.ResourceNotFound(err) {
-		return errors.Wrapf(err, "failed to delete resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+		errWrapped := errors.Wrapf(s.describeFailure(ctx, err), "failed to delete resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+		if requeueAfter, ok := azure.RequeueAfterForError(err); ok {
+			return azure.WithTransientError(errWrapped, requeueAfter)
+		}
+		return errWrapped
 	}
 
 	log.V(2).Info("successfully deleted resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
 	return nil
 }
 
+// setLongRunningOperationState stores the future of an operation that is still in progress. The start time of
+// a previously stored future for the same operation is carried over so the total duration can be tracked.
+func (s *Service[C, D]) setLongRunningOperationState(ctx context.Context, future, previous *infrav1.Future) {
+	_, log, done := tele.StartSpanWithLogger(ctx, "async.Service.setLongRunningOperationState")
+	defer done()
+
+	if previous != nil && previous.StartTime != nil {
+		future.StartTime = previous.StartTime.DeepCopy()
+	} else {
+		now := metav1.Now()
+		future.StartTime = &now
+	}
+	log.V(2).Info("long-running operation in progress", "service", future.ServiceName, "resource", future.Name,
+		"resourceGroup", future.ResourceGroup, "type", future.Type, "elapsed", time.Since(future.StartTime.Time).Round(time.Second).String())
+	s.Scope.SetLongRunningOperationState(future)
+}
+
+// describeFailure returns the error of a failed operation described by its event in the Azure Activity Log when the
+// ActivityLogDiagnostics feature is enabled, so that the condition it is reported in explains the failure.
+func (s *Service[C, D]) describeFailure(ctx context.Context, err error) error {
+	if !feature.Gates.Enabled(feature.ActivityLogDiagnostics) {
+		return err
+	}
+	// Every scope running async services is an authorizer, but the scope interface does not require it.
+	auth, ok := s.Scope.(azure.Authorizer)
+	if !ok {
+		return err
+	}
+	var client activitylogs.Client
+	var cerr error
+	if s.newActivityLogClient != nil {
+		client, cerr = s.newActivityLogClient(auth)
+	} else {
+		client, cerr = activitylogs.NewClient(auth)
+	}
+	if cerr != nil {
+		return err
+	}
+	return activitylogs.Describe(ctx, client, err)
+}
+
 // requeueTime returns the time to wait before requeuing a reconciliation.
 // It would be ideal to use the "retry-after" header from the API response, but
 // that is not readily accessible in the SDK v2 Poller framework.
@@ -178,28 +277,11 @@ func requeueTime() time.Duration {
 	return reconciler.DefaultReconcilerRequeue
 }
 
-// getRetryAfterFromError returns the time.Duration from the http.Response in the azcore.ResponseError.
-// If there is no Response object, or if there is no meaningful Retry-After header data, it returns a default.
-func getRetryAfterFromError(err error) time.Duration {
-	// In case we aren't able to introspect Retry-After from the error type, we'll return this default
-	ret := reconciler.DefaultReconcilerRequeue
-	var responseError *azcore.ResponseError
-	// if we have a strongly typed azcore.ResponseError then we can introspect the HTTP response data
-	if errors.As(err, &responseError) && responseError.RawResponse != nil {
-		// If we have Retry-After HTTP header data for any reason, prefer it
-		if retryAfter := responseError.RawResponse.Header.Get("Retry-After"); retryAfter != "" {
-			// This handles the case where Retry-After data is in the form of units of seconds
-			if rai, err := strconv.Atoi(retryAfter); err == nil {
-				ret = time.Duration(rai) * time.Second
-				// This handles the case where Retry-After data is in the form of absolute time
-			} else if t, err := time.Parse(time.RFC1123, retryAfter); err == nil {
-				ret = time.Until(t)
-			}
-			// If we didn't find Retry-After HTTP header data but the response type is 429,
-			// we'll have to come up with our sane default.
-		} else if responseError.RawResponse.StatusCode == http.StatusTooManyRequests {
-			ret = reconciler.DefaultHTTP429RetryAfter
-		}
+// requeueAfterError returns the time to wait before retrying after an error, depending on its class.
+// Errors which don't belong to any class of Azure API error are retried after the default requeue interval.
+func requeueAfterError(err error) time.Duration {
+	if requeueAfter, ok := azure.RequeueAfterForError(err); ok {
+		return requeueAfter
 	}
-	return ret
+	return reconciler.DefaultReconcilerRequeue
 }
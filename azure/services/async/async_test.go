@@ -26,16 +26,22 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/activitylogs"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/activitylogs/mock_activitylogs"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
@@ -89,6 +95,25 @@ func TestServiceCreateOrUpdateResource(t *testing.T) {
 				)
 			},
 		},
+		{
+			name:          "operation still in progress keeps the original start time",
+			serviceName:   serviceName,
+			expectedError: "operation type PUT on Azure resource mock-resourcegroup/mock-resource is not done. Object will be requeued after 15s",
+			expect: func(g *WithT, s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockCreatorMockRecorder[MockCreator], r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				future := validPutFuture.DeepCopy()
+				future.StartTime = &futureStartTime
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(future),
+					c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), resumeToken, gomock.Any()).Return(nil, fakePoller[MockCreator](g, http.StatusAccepted), context.DeadlineExceeded),
+					s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{})).Do(func(f *infrav1.Future) {
+						g.Expect(f.StartTime).NotTo(BeNil())
+						g.Expect(f.StartTime.Equal(&futureStartTime)).To(BeTrue())
+					}),
+				)
+			},
+		},
 		{
 			name:          "operation failed",
 			serviceName:   serviceName,
@@ -103,6 +128,34 @@ func TestServiceCreateOrUpdateResource(t *testing.T) {
 				)
 			},
 		},
+		{
+			name:          "operation failed with an error that retrying cannot fix",
+			serviceName:   serviceName,
+			expectedError: "failed to create or update resource mock-resourcegroup/mock-resource (service: mock-service): " + terminalErr.Error() + ". Object will be requeued after 10m0s",
+			expect: func(g *WithT, s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockCreatorMockRecorder[MockCreator], r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(validPutFuture),
+					c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), resumeToken, gomock.Any()).Return(nil, nil, terminalErr),
+					s.DeleteLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture),
+				)
+			},
+		},
+		{
+			name:          "operation failed with a server error",
+			serviceName:   serviceName,
+			expectedError: "failed to create or update resource mock-resourcegroup/mock-resource (service: mock-service): " + serverErr.Error() + ". Object will be requeued after 30s",
+			expect: func(g *WithT, s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockCreatorMockRecorder[MockCreator], r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(validPutFuture),
+					c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), resumeToken, gomock.Any()).Return(nil, nil, serverErr),
+					s.DeleteLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture),
+				)
+			},
+		},
 		{
 			name:          "get returns resource not found error",
 			serviceName:   serviceName,
@@ -226,7 +279,9 @@ func TestServiceDeleteResource(t *testing.T) {
 					r.ResourceGroupName().Return(resourceGroupName),
 					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture).Return(validDeleteFuture),
 					d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), gomock.Any()).Return(fakePoller[MockDeleter](g, http.StatusAccepted), context.DeadlineExceeded),
-					s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{})),
+					s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{})).Do(func(f *infrav1.Future) {
+						g.Expect(f.StartTime).NotTo(BeNil())
+					}),
 				)
 			},
 		},
@@ -258,6 +313,20 @@ func TestServiceDeleteResource(t *testing.T) {
 				)
 			},
 		},
+		{
+			name:          "delete is throttled",
+			serviceName:   serviceName,
+			expectedError: "failed to delete resource mock-resourcegroup/mock-resource (service: mock-service): " + throttledErr.Error() + ". Object will be requeued after 2m0s",
+			expect: func(g *GomegaWithT, s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockDeleterMockRecorder[MockDeleter], r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture).Return(validDeleteFuture),
+					d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), gomock.Any()).Return(nil, throttledErr),
+					s.DeleteLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture),
+				)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -286,6 +355,239 @@ func TestServiceDeleteResource(t *testing.T) {
 	}
 }
 
+func TestServicePlanMode(t *testing.T) {
+	t.Run("create is recorded instead of performed", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+		creatorMock := mock_async.NewMockCreator[MockCreator](mockCtrl)
+		specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+		svc := New[MockCreator, MockDeleter](scopeMock, creatorMock, nil)
+
+		specMock.EXPECT().ResourceName().Return(resourceName)
+		specMock.EXPECT().ResourceGroupName().Return(resourceGroupName)
+		scopeMock.EXPECT().GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(nil)
+		creatorMock.EXPECT().Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound})
+		specMock.EXPECT().Parameters(gomockinternal.AContext(), nil).Return(fakeParameters, nil)
+
+		plan := &azure.Plan{}
+		result, err := svc.CreateOrUpdateResource(azure.WithPlan(context.TODO(), plan), specMock, serviceName)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result).To(Equal(fakeParameters))
+		g.Expect(plan.Operations()).To(Equal([]infrav1.PlannedOperation{
+			{Type: infrav1.PlannedOperationCreate, Service: serviceName, ResourceGroup: resourceGroupName, Name: resourceName},
+		}))
+	})
+
+	t.Run("update is recorded instead of performed", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+		creatorMock := mock_async.NewMockCreator[MockCreator](mockCtrl)
+		specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+		svc := New[MockCreator, MockDeleter](scopeMock, creatorMock, nil)
+
+		specMock.EXPECT().ResourceName().Return(resourceName)
+		specMock.EXPECT().ResourceGroupName().Return(resourceGroupName)
+		scopeMock.EXPECT().GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(nil)
+		creatorMock.EXPECT().Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(fakeResource, nil)
+		specMock.EXPECT().Parameters(gomockinternal.AContext(), fakeResource).Return(fakeParameters, nil)
+
+		plan := &azure.Plan{}
+		_, err := svc.CreateOrUpdateResource(azure.WithPlan(context.TODO(), plan), specMock, serviceName)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(plan.Operations()).To(HaveLen(1))
+		g.Expect(plan.Operations()[0].Type).To(Equal(infrav1.PlannedOperationUpdate))
+	})
+
+	t.Run("up to date resource is not recorded", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+		creatorMock := mock_async.NewMockCreator[MockCreator](mockCtrl)
+		specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+		svc := New[MockCreator, MockDeleter](scopeMock, creatorMock, nil)
+
+		specMock.EXPECT().ResourceName().Return(resourceName)
+		specMock.EXPECT().ResourceGroupName().Return(resourceGroupName)
+		scopeMock.EXPECT().GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(nil)
+		creatorMock.EXPECT().Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(fakeResource, nil)
+		specMock.EXPECT().Parameters(gomockinternal.AContext(), fakeResource).Return(nil, nil)
+
+		plan := &azure.Plan{}
+		result, err := svc.CreateOrUpdateResource(azure.WithPlan(context.TODO(), plan), specMock, serviceName)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result).To(Equal(fakeResource))
+		g.Expect(plan.Operations()).To(BeEmpty())
+	})
+
+	t.Run("delete is recorded instead of performed", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+		deleterMock := mock_async.NewMockDeleter[MockDeleter](mockCtrl)
+		specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+		svc := New[MockCreator, MockDeleter](scopeMock, nil, deleterMock)
+
+		specMock.EXPECT().ResourceName().Return(resourceName)
+		specMock.EXPECT().ResourceGroupName().Return(resourceGroupName)
+		scopeMock.EXPECT().GetLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture).Return(nil)
+
+		plan := &azure.Plan{}
+		err := svc.DeleteResource(azure.WithPlan(context.TODO(), plan), specMock, serviceName)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(plan.Operations()).To(Equal([]infrav1.PlannedOperation{
+			{Type: infrav1.PlannedOperationDelete, Service: serviceName, ResourceGroup: resourceGroupName, Name: resourceName},
+		}))
+	})
+}
+
+func TestServiceDrift(t *testing.T) {
+	t.Run("drifted resource is reported and left untouched", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+		creatorMock := mock_async.NewMockCreator[MockCreator](mockCtrl)
+		specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+		svc := New[MockCreator, MockDeleter](scopeMock, creatorMock, nil)
+
+		specMock.EXPECT().ResourceName().Return(resourceName)
+		specMock.EXPECT().ResourceGroupName().Return(resourceGroupName)
+		scopeMock.EXPECT().GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(nil)
+		creatorMock.EXPECT().Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(fakeResource, nil)
+		specMock.EXPECT().Parameters(gomockinternal.AContext(), fakeResource).Return(fakeParameters, nil)
+
+		drift := &azure.Drift{}
+		result, err := svc.CreateOrUpdateResource(azure.WithDrift(context.TODO(), drift), specMock, serviceName)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result).To(Equal(fakeResource))
+		g.Expect(drift.Resources()).To(Equal([]string{resourceGroupName + "/" + resourceName + " (" + serviceName + ")"}))
+	})
+
+	t.Run("drifted resource is reported and corrected", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+		creatorMock := mock_async.NewMockCreator[MockCreator](mockCtrl)
+		specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+		svc := New[MockCreator, MockDeleter](scopeMock, creatorMock, nil)
+
+		specMock.EXPECT().ResourceName().Return(resourceName)
+		specMock.EXPECT().ResourceGroupName().Return(resourceGroupName)
+		scopeMock.EXPECT().GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(nil)
+		creatorMock.EXPECT().Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(fakeResource, nil)
+		specMock.EXPECT().Parameters(gomockinternal.AContext(), fakeResource).Return(fakeParameters, nil)
+		creatorMock.EXPECT().CreateOrUpdateAsync(gomockinternal.AContext(), specMock, "", fakeParameters).Return(fakeResource, nil, nil)
+		scopeMock.EXPECT().DeleteLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture)
+
+		drift := &azure.Drift{Correct: true}
+		result, err := svc.CreateOrUpdateResource(azure.WithDrift(context.TODO(), drift), specMock, serviceName)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result).To(Equal(fakeResource))
+		g.Expect(drift.Resources()).To(HaveLen(1))
+	})
+
+	t.Run("created resource is not reported", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+		creatorMock := mock_async.NewMockCreator[MockCreator](mockCtrl)
+		specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+		svc := New[MockCreator, MockDeleter](scopeMock, creatorMock, nil)
+
+		specMock.EXPECT().ResourceName().Return(resourceName)
+		specMock.EXPECT().ResourceGroupName().Return(resourceGroupName)
+		scopeMock.EXPECT().GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(nil)
+		creatorMock.EXPECT().Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound})
+		specMock.EXPECT().Parameters(gomockinternal.AContext(), nil).Return(fakeParameters, nil)
+		creatorMock.EXPECT().CreateOrUpdateAsync(gomockinternal.AContext(), specMock, "", fakeParameters).Return(fakeResource, nil, nil)
+		scopeMock.EXPECT().DeleteLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture)
+
+		drift := &azure.Drift{}
+		_, err := svc.CreateOrUpdateResource(azure.WithDrift(context.TODO(), drift), specMock, serviceName)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(drift.Resources()).To(BeEmpty())
+	})
+}
+
+// authorizerFutureScope is a future scope which is also an authorizer, like the scopes running async services.
+type authorizerFutureScope struct {
+	*mock_async.MockFutureScope
+	*mock_azure.MockAuthorizer
+}
+
+func TestServiceActivityLogDiagnostics(t *testing.T) {
+	testcases := []struct {
+		name          string
+		enabled       bool
+		expect        func(a *mock_activitylogs.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:          "failure is not described when the feature is disabled",
+			enabled:       false,
+			expect:        func(a *mock_activitylogs.MockClientMockRecorder) {},
+			expectedError: "failed to create or update resource mock-resourcegroup/mock-resource (service: mock-service): ",
+		},
+		{
+			name:    "failure is described by the activity log",
+			enabled: true,
+			expect: func(a *mock_activitylogs.MockClientMockRecorder) {
+				a.FailureStatusMessage(gomockinternal.AContext(), "corr-id", gomock.Any()).Return(`{"error":{"code":"InvalidParameter","message":"The value of parameter imageReference is invalid."}}`, nil)
+			},
+			expectedError: "(activity log for correlation ID corr-id: InvalidParameter: The value of parameter imageReference is invalid.)",
+		},
+		{
+			name:    "failure is not described when the activity log has no failed event yet",
+			enabled: true,
+			expect: func(a *mock_activitylogs.MockClientMockRecorder) {
+				a.FailureStatusMessage(gomockinternal.AContext(), "corr-id", gomock.Any()).Return("", nil)
+			},
+			expectedError: "failed to create or update resource mock-resourcegroup/mock-resource (service: mock-service): ",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ActivityLogDiagnostics, tc.enabled)()
+
+			failure := &azcore.ResponseError{
+				StatusCode: http.StatusBadRequest,
+				ErrorCode:  "DeploymentFailed",
+				RawResponse: &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Header:     http.Header{"X-Ms-Correlation-Request-Id": []string{"corr-id"}},
+					Body:       io.NopCloser(strings.NewReader(`{"error":{"code":"DeploymentFailed"}}`)),
+					Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{Path: "/subscriptions/123/resourceGroups/mock-resourcegroup"}},
+				},
+			}
+			mockCtrl := gomock.NewController(t)
+			scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+			creatorMock := mock_async.NewMockCreator[MockCreator](mockCtrl)
+			specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+			activityLogMock := mock_activitylogs.NewMockClient(mockCtrl)
+			svc := New[MockCreator, MockDeleter](authorizerFutureScope{scopeMock, mock_azure.NewMockAuthorizer(mockCtrl)}, creatorMock, nil)
+			svc.newActivityLogClient = func(azure.Authorizer) (activitylogs.Client, error) {
+				return activityLogMock, nil
+			}
+
+			specMock.EXPECT().ResourceName().Return(resourceName)
+			specMock.EXPECT().ResourceGroupName().Return(resourceGroupName)
+			scopeMock.EXPECT().GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(nil)
+			creatorMock.EXPECT().Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound})
+			specMock.EXPECT().Parameters(gomockinternal.AContext(), nil).Return(fakeParameters, nil)
+			creatorMock.EXPECT().CreateOrUpdateAsync(gomockinternal.AContext(), specMock, "", fakeParameters).Return(nil, nil, failure)
+			scopeMock.EXPECT().DeleteLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture)
+			tc.expect(activityLogMock.EXPECT())
+
+			_, err := svc.CreateOrUpdateResource(context.TODO(), specMock, serviceName)
+			g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+			g.Expect(errors.As(err, &failure)).To(BeTrue())
+		})
+	}
+}
+
 const (
 	resourceGroupName  = "mock-resourcegroup"
 	resourceName       = "mock-resource"
@@ -323,9 +625,38 @@ var (
 		ResourceGroup: resourceGroupName,
 		Data:          invalidResumeToken,
 	}
+	futureStartTime         = metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
 	fakeResource            = armresources.GenericResource{}
 	fakeParameters          = armresources.GenericResource{}
 	azureResourceGetterType = reflect.TypeOf((*azure.ResourceSpecGetter)(nil)).Elem()
+	terminalErr             = &azcore.ResponseError{
+		StatusCode: http.StatusConflict,
+		ErrorCode:  "QuotaExceeded",
+		RawResponse: &http.Response{
+			StatusCode: http.StatusConflict,
+			Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{}},
+			Body:       http.NoBody,
+		},
+	}
+	serverErr = &azcore.ResponseError{
+		StatusCode: http.StatusInternalServerError,
+		ErrorCode:  "InternalServerError",
+		RawResponse: &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{}},
+			Body:       http.NoBody,
+		},
+	}
+	throttledErr = &azcore.ResponseError{
+		StatusCode: http.StatusTooManyRequests,
+		ErrorCode:  "TooManyRequests",
+		RawResponse: &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{"120"}},
+			Request:    &http.Request{Method: http.MethodDelete, URL: &url.URL{}},
+			Body:       http.NoBody,
+		},
+	}
 )
 
 func fakePoller[T any](g *GomegaWithT, statusCode int) *runtime.Poller[T] {
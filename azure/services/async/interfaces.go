@@ -39,6 +39,13 @@ type TagsGetter interface {
 	GetAtScope(ctx context.Context, scope string) (result armresources.TagsResource, err error)
 }
 
+// OwnershipReleaser releases the ownership of a resource shared between clusters.
+type OwnershipReleaser interface {
+	// ReleaseOwnership removes the tag marking the resource of spec as owned by clusterName, unless clusterName is its
+	// last owner. It returns true if clusterName is the last owner of the resource, which is then to be deleted.
+	ReleaseOwnership(ctx context.Context, spec azure.ResourceSpecGetter, clusterName string) (last bool, err error)
+}
+
 // Creator creates or updates a resource asynchronously.
 type Creator[T any] interface {
 	Getter
@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var longRunningOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "capz_long_running_operation_duration_seconds",
+	Help:    "Time from starting to finishing an Azure create, update or delete operation, across reconciles, by service, operation type and result.",
+	Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+}, []string{"service", "type", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(longRunningOperationDuration)
+}
+
+// operationStartTime returns when an operation started: the start time of its stored future if it has been
+// running across reconciles, or now otherwise.
+func operationStartTime(future *infrav1.Future) time.Time {
+	if future != nil && future.StartTime != nil {
+		return future.StartTime.Time
+	}
+	return time.Now()
+}
+
+// observeOperationDuration records the duration of a finished operation.
+func observeOperationDuration(serviceName, futureType string, start time.Time, succeeded bool) {
+	result := "success"
+	if !succeeded {
+		result = "failure"
+	}
+	longRunningOperationDuration.WithLabelValues(serviceName, futureType, result).Observe(time.Since(start).Seconds())
+}
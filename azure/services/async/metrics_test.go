@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestOperationStartTime(t *testing.T) {
+	g := NewWithT(t)
+
+	startTime := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+	g.Expect(operationStartTime(&infrav1.Future{StartTime: &startTime})).To(Equal(startTime.Time))
+	g.Expect(operationStartTime(&infrav1.Future{})).To(BeTemporally("~", time.Now(), time.Second))
+	g.Expect(operationStartTime(nil)).To(BeTemporally("~", time.Now(), time.Second))
+}
+
+func TestObserveOperationDuration(t *testing.T) {
+	g := NewWithT(t)
+
+	before := testutil.CollectAndCount(longRunningOperationDuration)
+	observeOperationDuration("metrics-test-service", infrav1.PutFuture, time.Now().Add(-time.Minute), true)
+	observeOperationDuration("metrics-test-service", infrav1.PutFuture, time.Now().Add(-time.Minute), false)
+	g.Expect(testutil.CollectAndCount(longRunningOperationDuration)).To(Equal(before + 2))
+}
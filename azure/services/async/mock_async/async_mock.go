@@ -209,6 +209,44 @@ func (mr *MockTagsGetterMockRecorder) GetAtScope(ctx, scope any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAtScope", reflect.TypeOf((*MockTagsGetter)(nil).GetAtScope), ctx, scope)
 }
 
+// MockOwnershipReleaser is a mock of OwnershipReleaser interface.
+type MockOwnershipReleaser struct {
+	ctrl     *gomock.Controller
+	recorder *MockOwnershipReleaserMockRecorder
+}
+
+// MockOwnershipReleaserMockRecorder is the mock recorder for MockOwnershipReleaser.
+type MockOwnershipReleaserMockRecorder struct {
+	mock *MockOwnershipReleaser
+}
+
+// NewMockOwnershipReleaser creates a new mock instance.
+func NewMockOwnershipReleaser(ctrl *gomock.Controller) *MockOwnershipReleaser {
+	mock := &MockOwnershipReleaser{ctrl: ctrl}
+	mock.recorder = &MockOwnershipReleaserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOwnershipReleaser) EXPECT() *MockOwnershipReleaserMockRecorder {
+	return m.recorder
+}
+
+// ReleaseOwnership mocks base method.
+func (m *MockOwnershipReleaser) ReleaseOwnership(ctx context.Context, spec azure.ResourceSpecGetter, clusterName string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseOwnership", ctx, spec, clusterName)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseOwnership indicates an expected call of ReleaseOwnership.
+func (mr *MockOwnershipReleaserMockRecorder) ReleaseOwnership(ctx, spec, clusterName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseOwnership", reflect.TypeOf((*MockOwnershipReleaser)(nil).ReleaseOwnership), ctx, spec, clusterName)
+}
+
 // MockCreator is a mock of Creator interface.
 type MockCreator[T any] struct {
 	ctrl     *gomock.Controller
@@ -113,6 +113,34 @@ func (mr *MockAvailabilitySetScopeMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockAvailabilitySetScope)(nil).BaseURI))
 }
 
+// BootstrapDataKeyVault mocks base method.
+func (m *MockAvailabilitySetScope) BootstrapDataKeyVault() *v1beta1.BootstrapDataKeyVault {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataKeyVault")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataKeyVault)
+	return ret0
+}
+
+// BootstrapDataKeyVault indicates an expected call of BootstrapDataKeyVault.
+func (mr *MockAvailabilitySetScopeMockRecorder) BootstrapDataKeyVault() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataKeyVault", reflect.TypeOf((*MockAvailabilitySetScope)(nil).BootstrapDataKeyVault))
+}
+
+// BootstrapDataStorageAccountName mocks base method.
+func (m *MockAvailabilitySetScope) BootstrapDataStorageAccountName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorageAccountName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BootstrapDataStorageAccountName indicates an expected call of BootstrapDataStorageAccountName.
+func (mr *MockAvailabilitySetScopeMockRecorder) BootstrapDataStorageAccountName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorageAccountName", reflect.TypeOf((*MockAvailabilitySetScope)(nil).BootstrapDataStorageAccountName))
+}
+
 // ClientID mocks base method.
 func (m *MockAvailabilitySetScope) ClientID() string {
 	m.ctrl.T.Helper()
@@ -293,6 +321,34 @@ func (mr *MockAvailabilitySetScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockAvailabilitySetScope)(nil).Location))
 }
 
+// ManagedIdentityProviderID mocks base method.
+func (m *MockAvailabilitySetScope) ManagedIdentityProviderID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedIdentityProviderID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ManagedIdentityProviderID indicates an expected call of ManagedIdentityProviderID.
+func (mr *MockAvailabilitySetScopeMockRecorder) ManagedIdentityProviderID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedIdentityProviderID", reflect.TypeOf((*MockAvailabilitySetScope)(nil).ManagedIdentityProviderID))
+}
+
+// PlatformFaultDomainCount mocks base method.
+func (m *MockAvailabilitySetScope) PlatformFaultDomainCount() *int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlatformFaultDomainCount")
+	ret0, _ := ret[0].(*int32)
+	return ret0
+}
+
+// PlatformFaultDomainCount indicates an expected call of PlatformFaultDomainCount.
+func (mr *MockAvailabilitySetScopeMockRecorder) PlatformFaultDomainCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlatformFaultDomainCount", reflect.TypeOf((*MockAvailabilitySetScope)(nil).PlatformFaultDomainCount))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockAvailabilitySetScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -307,6 +363,34 @@ func (mr *MockAvailabilitySetScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockAvailabilitySetScope)(nil).ResourceGroup))
 }
 
+// SSHKeyPairSecretName mocks base method.
+func (m *MockAvailabilitySetScope) SSHKeyPairSecretName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHKeyPairSecretName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SSHKeyPairSecretName indicates an expected call of SSHKeyPairSecretName.
+func (mr *MockAvailabilitySetScopeMockRecorder) SSHKeyPairSecretName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHKeyPairSecretName", reflect.TypeOf((*MockAvailabilitySetScope)(nil).SSHKeyPairSecretName))
+}
+
+// SSHPublicKeys mocks base method.
+func (m *MockAvailabilitySetScope) SSHPublicKeys(role string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHPublicKeys", role)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SSHPublicKeys indicates an expected call of SSHPublicKeys.
+func (mr *MockAvailabilitySetScopeMockRecorder) SSHPublicKeys(role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHPublicKeys", reflect.TypeOf((*MockAvailabilitySetScope)(nil).SSHPublicKeys), role)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockAvailabilitySetScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
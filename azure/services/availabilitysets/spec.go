@@ -36,6 +36,9 @@ type AvailabilitySetSpec struct {
 	Location       string
 	SKU            *resourceskus.SKU
 	AdditionalTags infrav1.Tags
+	// PlatformFaultDomainCount is the fault domain count of the availability set. When nil, it is the maximum supported
+	// by the SKU.
+	PlatformFaultDomainCount *int32
 }
 
 // ResourceName returns the name of the availability set.
@@ -77,6 +80,12 @@ func (s *AvailabilitySetSpec) Parameters(ctx context.Context, existing interface
 		return nil, errors.Wrapf(err, "unable to parse availability set fault domain count")
 	}
 	faultDomainCount = ptr.To[int32](int32(count))
+	if s.PlatformFaultDomainCount != nil {
+		if int64(*s.PlatformFaultDomainCount) > count {
+			return nil, errors.Errorf("platform fault domain count %d exceeds the maximum of %d in location %s", *s.PlatformFaultDomainCount, count, s.Location)
+		}
+		faultDomainCount = s.PlatformFaultDomainCount
+	}
 
 	asParams := armcompute.AvailabilitySet{
 		SKU: &armcompute.SKU{
@@ -73,6 +73,33 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "get parameters with a platform fault domain count",
+			spec: func() *AvailabilitySetSpec {
+				spec := fakeSetSpec
+				spec.PlatformFaultDomainCount = ptr.To[int32](2)
+				return &spec
+			}(),
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.AvailabilitySet{}))
+				g.Expect(result.(armcompute.AvailabilitySet).Properties.PlatformFaultDomainCount).To(Equal(ptr.To[int32](2)))
+			},
+			expectedError: "",
+		},
+		{
+			name: "error when the platform fault domain count exceeds the maximum",
+			spec: func() *AvailabilitySetSpec {
+				spec := fakeSetSpec
+				spec.PlatformFaultDomainCount = ptr.To[int32](4)
+				return &spec
+			}(),
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "platform fault domain count 4 exceeds the maximum of 3 in location test-location",
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -155,6 +155,34 @@ func (mr *MockBastionScopeMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockBastionScope)(nil).BaseURI))
 }
 
+// BootstrapDataKeyVault mocks base method.
+func (m *MockBastionScope) BootstrapDataKeyVault() *v1beta1.BootstrapDataKeyVault {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataKeyVault")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataKeyVault)
+	return ret0
+}
+
+// BootstrapDataKeyVault indicates an expected call of BootstrapDataKeyVault.
+func (mr *MockBastionScopeMockRecorder) BootstrapDataKeyVault() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataKeyVault", reflect.TypeOf((*MockBastionScope)(nil).BootstrapDataKeyVault))
+}
+
+// BootstrapDataStorageAccountName mocks base method.
+func (m *MockBastionScope) BootstrapDataStorageAccountName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorageAccountName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BootstrapDataStorageAccountName indicates an expected call of BootstrapDataStorageAccountName.
+func (mr *MockBastionScopeMockRecorder) BootstrapDataStorageAccountName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorageAccountName", reflect.TypeOf((*MockBastionScope)(nil).BootstrapDataStorageAccountName))
+}
+
 // ClientID mocks base method.
 func (m *MockBastionScope) ClientID() string {
 	m.ctrl.T.Helper()
@@ -419,6 +447,62 @@ func (mr *MockBastionScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockBastionScope)(nil).Location))
 }
 
+// ManagedIdentityProviderID mocks base method.
+func (m *MockBastionScope) ManagedIdentityProviderID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedIdentityProviderID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ManagedIdentityProviderID indicates an expected call of ManagedIdentityProviderID.
+func (mr *MockBastionScopeMockRecorder) ManagedIdentityProviderID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedIdentityProviderID", reflect.TypeOf((*MockBastionScope)(nil).ManagedIdentityProviderID))
+}
+
+// NodeInternalLBName mocks base method.
+func (m *MockBastionScope) NodeInternalLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeInternalLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeInternalLBName indicates an expected call of NodeInternalLBName.
+func (mr *MockBastionScopeMockRecorder) NodeInternalLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeInternalLBName", reflect.TypeOf((*MockBastionScope)(nil).NodeInternalLBName))
+}
+
+// NodeInternalLBPoolName mocks base method.
+func (m *MockBastionScope) NodeInternalLBPoolName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeInternalLBPoolName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeInternalLBPoolName indicates an expected call of NodeInternalLBPoolName.
+func (mr *MockBastionScopeMockRecorder) NodeInternalLBPoolName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeInternalLBPoolName", reflect.TypeOf((*MockBastionScope)(nil).NodeInternalLBPoolName))
+}
+
+// NodeRoutes mocks base method.
+func (m *MockBastionScope) NodeRoutes() *v1beta1.NodeRoutesSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeRoutes")
+	ret0, _ := ret[0].(*v1beta1.NodeRoutesSpec)
+	return ret0
+}
+
+// NodeRoutes indicates an expected call of NodeRoutes.
+func (mr *MockBastionScopeMockRecorder) NodeRoutes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeRoutes", reflect.TypeOf((*MockBastionScope)(nil).NodeRoutes))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockBastionScope) NodeSubnets() []v1beta1.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -461,6 +545,20 @@ func (mr *MockBastionScopeMockRecorder) OutboundPoolName(arg0 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboundPoolName", reflect.TypeOf((*MockBastionScope)(nil).OutboundPoolName), arg0)
 }
 
+// PlatformFaultDomainCount mocks base method.
+func (m *MockBastionScope) PlatformFaultDomainCount() *int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlatformFaultDomainCount")
+	ret0, _ := ret[0].(*int32)
+	return ret0
+}
+
+// PlatformFaultDomainCount indicates an expected call of PlatformFaultDomainCount.
+func (mr *MockBastionScopeMockRecorder) PlatformFaultDomainCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlatformFaultDomainCount", reflect.TypeOf((*MockBastionScope)(nil).PlatformFaultDomainCount))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockBastionScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -475,6 +573,34 @@ func (mr *MockBastionScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockBastionScope)(nil).ResourceGroup))
 }
 
+// SSHKeyPairSecretName mocks base method.
+func (m *MockBastionScope) SSHKeyPairSecretName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHKeyPairSecretName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SSHKeyPairSecretName indicates an expected call of SSHKeyPairSecretName.
+func (mr *MockBastionScopeMockRecorder) SSHKeyPairSecretName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHKeyPairSecretName", reflect.TypeOf((*MockBastionScope)(nil).SSHKeyPairSecretName))
+}
+
+// SSHPublicKeys mocks base method.
+func (m *MockBastionScope) SSHPublicKeys(role string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHPublicKeys", role)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SSHPublicKeys indicates an expected call of SSHPublicKeys.
+func (mr *MockBastionScopeMockRecorder) SSHPublicKeys(role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHPublicKeys", reflect.TypeOf((*MockBastionScope)(nil).SSHPublicKeys), role)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockBastionScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
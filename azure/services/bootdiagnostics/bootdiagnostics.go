@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootdiagnostics
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// TailLines is the number of lines kept from the end of a serial console log.
+	TailLines = 20
+	// maxLogBytes is the size of the end of a serial console log downloaded to find its last lines.
+	maxLogBytes = 16 * 1024
+	// maxLineLength is the length a line of a serial console log is truncated to.
+	maxLineLength = 200
+)
+
+var (
+	doOnce    sync.Once
+	tailCache ttllru.PeekingCacher
+
+	// escapeSequences matches the ANSI escape sequences that color and position the output of consoles.
+	escapeSequences = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+)
+
+// cachedTail is the tail of a serial console log retrieved at a given time.
+type cachedTail struct {
+	tail      string
+	retrieved time.Time
+}
+
+// Service retrieves the serial console logs of VMs from their boot diagnostics.
+type Service struct {
+	Client
+	azure.Authorizer
+}
+
+// New creates a new boot diagnostics service.
+func New(auth azure.Authorizer) (*Service, error) {
+	client, err := NewClient(auth)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Client:     client,
+		Authorizer: auth,
+	}, nil
+}
+
+// SerialLogTail returns the last lines of the serial console log of a VM, e.g. the output of cloud-init.
+// The tail retrieved for a VM is reused until it is older than maxAge.
+func (s *Service) SerialLogTail(ctx context.Context, resourceGroup, vmName string, maxAge time.Duration) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootdiagnostics.Service.SerialLogTail")
+	defer done()
+
+	var err error
+	doOnce.Do(func() {
+		tailCache, err = ttllru.New(1024, 24*time.Hour)
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed creating LRU cache for serial console logs")
+	}
+
+	key := strings.Join([]string{s.HashKey(), resourceGroup, vmName}, "/")
+	if cached, ok := tailCache.Get(key); ok {
+		if c, ok := cached.(cachedTail); ok && time.Since(c.retrieved) < maxAge {
+			return c.tail, nil
+		}
+	}
+
+	log, err := s.GetSerialConsoleLog(ctx, resourceGroup, vmName, maxLogBytes)
+	if err != nil {
+		return "", err
+	}
+	tail := Tail(string(log), TailLines)
+	_ = tailCache.Add(key, cachedTail{tail: tail, retrieved: time.Now()})
+	return tail, nil
+}
+
+// Tail returns the last non-empty lines of a console log, without escape sequences and non-printable characters, and
+// with long lines truncated.
+func Tail(log string, lines int) string {
+	log = escapeSequences.ReplaceAllString(log, "")
+	var tail []string
+	for _, line := range strings.Split(log, "\n") {
+		line = strings.TrimSpace(strings.Map(printable, line))
+		if line == "" {
+			continue
+		}
+		if runes := []rune(line); len(runes) > maxLineLength {
+			line = string(runes[:maxLineLength]) + "..."
+		}
+		tail = append(tail, line)
+	}
+	if len(tail) > lines {
+		tail = tail[len(tail)-lines:]
+	}
+	return strings.Join(tail, "\n")
+}
+
+// printable drops the control characters of a console log, such as carriage returns.
+func printable(r rune) rune {
+	if r == '\t' {
+		return ' '
+	}
+	if r < ' ' || r == 0x7f || r == utf8.RuneError {
+		return -1
+	}
+	return r
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootdiagnostics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootdiagnostics/mock_bootdiagnostics"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func TestTail(t *testing.T) {
+	var longLog []string
+	for i := 1; i <= 30; i++ {
+		longLog = append(longLog, fmt.Sprintf("line %d", i))
+	}
+
+	tests := []struct {
+		name  string
+		log   string
+		lines int
+		want  string
+	}{
+		{
+			name:  "empty log",
+			log:   "",
+			lines: 3,
+			want:  "",
+		},
+		{
+			name:  "short log",
+			log:   "first\nsecond\n",
+			lines: 3,
+			want:  "first\nsecond",
+		},
+		{
+			name:  "last lines of a long log",
+			log:   strings.Join(longLog, "\n"),
+			lines: 3,
+			want:  "line 28\nline 29\nline 30",
+		},
+		{
+			name:  "escape sequences, carriage returns and blank lines are dropped",
+			log:   "\x1b[0;32m  OK  \x1b[0m] Started cloud-init.\r\n\r\n\tcloud-init[1234]: Cloud-init v. 23.1 finished\r\n",
+			lines: 3,
+			want:  "OK  ] Started cloud-init.\ncloud-init[1234]: Cloud-init v. 23.1 finished",
+		},
+		{
+			name:  "long lines are truncated",
+			log:   strings.Repeat("a", 250),
+			lines: 3,
+			want:  strings.Repeat("a", 200) + "...",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(Tail(tc.log, tc.lines)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestSerialLogTail(t *testing.T) {
+	tests := []struct {
+		name    string
+		vmName  string
+		maxAge  time.Duration
+		expect  func(m *mock_bootdiagnostics.MockClientMockRecorder)
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "tail of the serial console log",
+			vmName: "vm-tail",
+			maxAge: time.Hour,
+			expect: func(m *mock_bootdiagnostics.MockClientMockRecorder) {
+				m.GetSerialConsoleLog(gomockinternal.AContext(), "my-rg", "vm-tail", int64(maxLogBytes)).
+					Return([]byte("booting\ncloud-init failed\n"), nil).Times(1)
+			},
+			want: "booting\ncloud-init failed",
+		},
+		{
+			name:   "stale tail is retrieved again",
+			vmName: "vm-stale",
+			maxAge: 0,
+			expect: func(m *mock_bootdiagnostics.MockClientMockRecorder) {
+				m.GetSerialConsoleLog(gomockinternal.AContext(), "my-rg", "vm-stale", int64(maxLogBytes)).
+					Return([]byte("booting\n"), nil).Times(2)
+			},
+			want: "booting",
+		},
+		{
+			name:   "failure to retrieve the serial console log",
+			vmName: "vm-error",
+			maxAge: time.Hour,
+			expect: func(m *mock_bootdiagnostics.MockClientMockRecorder) {
+				m.GetSerialConsoleLog(gomockinternal.AContext(), "my-rg", "vm-error", int64(maxLogBytes)).
+					Return(nil, errors.New("boom")).Times(2)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_bootdiagnostics.NewMockClient(mockCtrl)
+			tc.expect(clientMock.EXPECT())
+			authMock := mock_azure.NewMockAuthorizer(mockCtrl)
+			authMock.EXPECT().HashKey().Return("fake-hash-key").AnyTimes()
+
+			s := &Service{
+				Client:     clientMock,
+				Authorizer: authMock,
+			}
+			// The second call is served from the cache unless the tail is stale or could not be retrieved.
+			for i := 0; i < 2; i++ {
+				tail, err := s.SerialLogTail(context.TODO(), "my-rg", tc.vmName, tc.maxAge)
+				if tc.wantErr {
+					g.Expect(err).To(HaveOccurred())
+					continue
+				}
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(tail).To(Equal(tc.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootdiagnostics
+
+import (
+	"context"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// sasExpirationMinutes is the validity of the SAS URIs to the boot diagnostics logs requested from Azure.
+const sasExpirationMinutes = 5
+
+// Client wraps go-sdk.
+type Client interface {
+	GetSerialConsoleLog(ctx context.Context, resourceGroup, vmName string, maxBytes int64) ([]byte, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	virtualmachines *armcompute.VirtualMachinesClient
+	clientOptions   *arm.ClientOptions
+}
+
+var _ Client = (*AzureClient)(nil)
+
+// NewClient creates a new boot diagnostics client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create bootdiagnostics client options")
+	}
+	vmsClient, err := armcompute.NewVirtualMachinesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armcompute virtual machines client")
+	}
+	return &AzureClient{
+		virtualmachines: vmsClient,
+		clientOptions:   opts,
+	}, nil
+}
+
+// GetSerialConsoleLog returns the last maxBytes bytes of the serial console log of a VM, downloaded from the boot
+// diagnostics storage through a short-lived SAS URI.
+func (ac *AzureClient) GetSerialConsoleLog(ctx context.Context, resourceGroup, vmName string, maxBytes int64) ([]byte, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootdiagnostics.AzureClient.GetSerialConsoleLog")
+	defer done()
+
+	resp, err := ac.virtualmachines.RetrieveBootDiagnosticsData(ctx, resourceGroup, vmName, &armcompute.VirtualMachinesClientRetrieveBootDiagnosticsDataOptions{
+		SasURIExpirationTimeInMinutes: ptr.To[int32](sasExpirationMinutes),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve boot diagnostics data of VM %s/%s", resourceGroup, vmName)
+	}
+	logURI := ptr.Deref(resp.SerialConsoleLogBlobURI, "")
+	if logURI == "" {
+		return nil, errors.Errorf("VM %s/%s has no serial console log", resourceGroup, vmName)
+	}
+
+	blobClient, err := blob.NewClientWithNoCredential(logURI, &blob.ClientOptions{ClientOptions: ac.clientOptions.ClientOptions})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create serial console log blob client")
+	}
+	props, err := blobClient.GetProperties(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the properties of the serial console log")
+	}
+	size := ptr.Deref(props.ContentLength, 0)
+	if size == 0 {
+		return nil, nil
+	}
+	var offset int64
+	if size > maxBytes {
+		offset = size - maxBytes
+	}
+	download, err := blobClient.DownloadStream(ctx, &blob.DownloadStreamOptions{
+		Range: blob.HTTPRange{Offset: offset, Count: size - offset},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download the serial console log")
+	}
+	defer download.Body.Close()
+	data, err := io.ReadAll(download.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the serial console log")
+	}
+	return data, nil
+}
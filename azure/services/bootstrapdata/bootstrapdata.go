@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapdata
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	serviceName = "bootstrapdata"
+
+	// sasExpiry is how long the VM can fetch its bootstrap data for after the blob has been uploaded.
+	sasExpiry = time.Hour
+)
+
+// BootstrapDataScope defines the scope interface for a bootstrap data service.
+type BootstrapDataScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	BootstrapDataBlobSpec() *azure.BootstrapDataBlobSpec
+	SetBootstrapData(string)
+	HasBootstrapDataBlob() bool
+	ClearBootstrapDataBlob()
+	IsBootstrapped() bool
+}
+
+// Service provides operations on the blobs holding bootstrap data.
+type Service struct {
+	Scope  BootstrapDataScope
+	client Client
+}
+
+// New creates a new bootstrap data service.
+func New(scope BootstrapDataScope) (*Service, error) {
+	client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:  scope,
+		client: client,
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile uploads the bootstrap data of the machine to a blob when it cannot be passed as custom data, and replaces
+// the custom data with instructions to fetch it. The blob is deleted once the machine has joined the cluster.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "bootstrapdata.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.BootstrapDataBlobSpec()
+	if spec == nil {
+		log.V(2).Info("skip reconciliation when no bootstrap data storage is configured")
+		return nil
+	}
+
+	if s.Scope.IsBootstrapped() {
+		if !s.Scope.HasBootstrapDataBlob() {
+			return nil
+		}
+		if err := s.client.DeleteBlob(ctx, spec); err != nil {
+			s.Scope.UpdatePutStatus(infrav1.BootstrapDataReadyCondition, serviceName, err)
+			return err
+		}
+		s.Scope.ClearBootstrapDataBlob()
+		return nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(spec.BootstrapData)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode bootstrap data")
+	}
+	if !needsBlob(spec.Format, data) {
+		return nil
+	}
+
+	err = s.upload(ctx, spec, data)
+	s.Scope.UpdatePutStatus(infrav1.BootstrapDataReadyCondition, serviceName, err)
+	return err
+}
+
+func (s *Service) upload(ctx context.Context, spec *azure.BootstrapDataBlobSpec, data []byte) error {
+	url, err := s.client.UploadBlob(ctx, spec, data, sasExpiry)
+	if err != nil {
+		return err
+	}
+	stub, err := customData(spec.Format, data, url)
+	if err != nil {
+		return err
+	}
+	s.Scope.SetBootstrapData(base64.StdEncoding.EncodeToString(stub))
+	return nil
+}
+
+// Delete deletes the blob holding the bootstrap data of the machine, if any.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "bootstrapdata.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.BootstrapDataBlobSpec()
+	if spec == nil || !s.Scope.HasBootstrapDataBlob() {
+		log.V(2).Info("skip deletion when no bootstrap data blob exists")
+		return nil
+	}
+
+	if err := s.client.DeleteBlob(ctx, spec); err != nil {
+		s.Scope.UpdateDeleteStatus(infrav1.BootstrapDataReadyCondition, serviceName, err)
+		return err
+	}
+	s.Scope.ClearBootstrapDataBlob()
+	return nil
+}
+
+// IsManaged always returns true as the blobs holding bootstrap data are always created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
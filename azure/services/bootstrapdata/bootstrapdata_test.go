@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapdata
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootstrapdata/mock_bootstrapdata"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const fakeSASURL = "https://capzboottest.blob.core.windows.net/bootstrap-data/test-machine?sig=fake"
+
+func fakeBlobSpec(data, format string) *azure.BootstrapDataBlobSpec {
+	return &azure.BootstrapDataBlobSpec{
+		StorageAccountName: "capzboottest",
+		ResourceGroup:      "test-rg",
+		ContainerName:      ContainerName,
+		BlobName:           "test-machine",
+		BootstrapData:      base64.StdEncoding.EncodeToString([]byte(data)),
+		Format:             format,
+	}
+}
+
+func TestReconcileBootstrapData(t *testing.T) {
+	largeCloudConfig := "#cloud-config\n" + strings.Repeat("#", maxCustomDataSize)
+	uploadErr := errors.New("upload failed")
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, c *mock_bootstrapdata.MockClientMockRecorder)
+	}{
+		{
+			name: "noop if no bootstrap data storage is configured",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, c *mock_bootstrapdata.MockClientMockRecorder) {
+				s.BootstrapDataBlobSpec().Return(nil)
+			},
+		},
+		{
+			name: "small cloud-config bootstrap data is passed as custom data",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, c *mock_bootstrapdata.MockClientMockRecorder) {
+				s.BootstrapDataBlobSpec().Return(fakeBlobSpec("#cloud-config\n", "cloud-config"))
+				s.IsBootstrapped().Return(false)
+			},
+		},
+		{
+			name: "large cloud-config bootstrap data is uploaded and included",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, c *mock_bootstrapdata.MockClientMockRecorder) {
+				spec := fakeBlobSpec(largeCloudConfig, "cloud-config")
+				s.BootstrapDataBlobSpec().Return(spec)
+				s.IsBootstrapped().Return(false)
+				c.UploadBlob(gomockinternal.AContext(), spec, []byte(largeCloudConfig), sasExpiry).Return(fakeSASURL, nil)
+				s.SetBootstrapData(base64.StdEncoding.EncodeToString([]byte("#include\n" + fakeSASURL + "\n")))
+				s.UpdatePutStatus(infrav1.BootstrapDataReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name: "ignition bootstrap data is uploaded and replaced",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, c *mock_bootstrapdata.MockClientMockRecorder) {
+				ignition := `{"ignition":{"version":"3.3.0"}}`
+				spec := fakeBlobSpec(ignition, "ignition")
+				s.BootstrapDataBlobSpec().Return(spec)
+				s.IsBootstrapped().Return(false)
+				c.UploadBlob(gomockinternal.AContext(), spec, []byte(ignition), sasExpiry).Return(fakeSASURL, nil)
+				s.SetBootstrapData(base64.StdEncoding.EncodeToString([]byte(`{"ignition":{"config":{"replace":{"source":"` + fakeSASURL + `"}},"version":"3.3.0"}}`)))
+				s.UpdatePutStatus(infrav1.BootstrapDataReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "error uploading bootstrap data",
+			expectedError: "upload failed",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, c *mock_bootstrapdata.MockClientMockRecorder) {
+				spec := fakeBlobSpec(largeCloudConfig, "cloud-config")
+				s.BootstrapDataBlobSpec().Return(spec)
+				s.IsBootstrapped().Return(false)
+				c.UploadBlob(gomockinternal.AContext(), spec, []byte(largeCloudConfig), sasExpiry).Return("", uploadErr)
+				s.UpdatePutStatus(infrav1.BootstrapDataReadyCondition, serviceName, uploadErr)
+			},
+		},
+		{
+			name: "blob is deleted once the machine has joined the cluster",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, c *mock_bootstrapdata.MockClientMockRecorder) {
+				spec := fakeBlobSpec(largeCloudConfig, "cloud-config")
+				s.BootstrapDataBlobSpec().Return(spec)
+				s.IsBootstrapped().Return(true)
+				s.HasBootstrapDataBlob().Return(true)
+				c.DeleteBlob(gomockinternal.AContext(), spec).Return(nil)
+				s.ClearBootstrapDataBlob()
+			},
+		},
+		{
+			name: "noop once the blob has been deleted",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, c *mock_bootstrapdata.MockClientMockRecorder) {
+				s.BootstrapDataBlobSpec().Return(fakeBlobSpec(largeCloudConfig, "cloud-config"))
+				s.IsBootstrapped().Return(true)
+				s.HasBootstrapDataBlob().Return(false)
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_bootstrapdata.NewMockBootstrapDataScope(mockCtrl)
+			clientMock := mock_bootstrapdata.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteBootstrapData(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_bootstrapdata.NewMockBootstrapDataScope(mockCtrl)
+	clientMock := mock_bootstrapdata.NewMockClient(mockCtrl)
+
+	spec := fakeBlobSpec("", "")
+	scopeMock.EXPECT().BootstrapDataBlobSpec().Return(spec)
+	scopeMock.EXPECT().HasBootstrapDataBlob().Return(true)
+	clientMock.EXPECT().DeleteBlob(gomockinternal.AContext(), spec).Return(nil)
+	scopeMock.EXPECT().ClearBootstrapDataBlob()
+
+	s := &Service{
+		Scope:  scopeMock,
+		client: clientMock,
+	}
+	g.Expect(s.Delete(context.TODO())).To(Succeed())
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapdata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	UploadBlob(ctx context.Context, spec *azure.BootstrapDataBlobSpec, data []byte, expiry time.Duration) (string, error)
+	DeleteBlob(ctx context.Context, spec *azure.BootstrapDataBlobSpec) error
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	accounts      *armstorage.AccountsClient
+	clientOptions *arm.ClientOptions
+	cloudEnv      string
+}
+
+var _ Client = (*AzureClient)(nil)
+
+// NewClient creates a new bootstrap data client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create bootstrapdata client options")
+	}
+	accountsClient, err := armstorage.NewAccountsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armstorage accounts client")
+	}
+	return &AzureClient{
+		accounts:      accountsClient,
+		clientOptions: opts,
+		cloudEnv:      auth.CloudEnvironment(),
+	}, nil
+}
+
+// UploadBlob uploads data to the blob described by the spec, creating its container if needed, and returns a read-only
+// SAS URL to the blob valid for the given duration.
+func (ac *AzureClient) UploadBlob(ctx context.Context, spec *azure.BootstrapDataBlobSpec, data []byte, expiry time.Duration) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootstrapdata.AzureClient.UploadBlob")
+	defer done()
+
+	serviceURL, cred, blobClient, err := ac.blobClient(ctx, spec)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := blobClient.CreateContainer(ctx, spec.ContainerName, nil); err != nil && !bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		return "", errors.Wrapf(err, "failed to create container %s", spec.ContainerName)
+	}
+	if _, err := blobClient.UploadBuffer(ctx, spec.ContainerName, spec.BlobName, data, nil); err != nil {
+		return "", errors.Wrapf(err, "failed to upload blob %s", spec.BlobName)
+	}
+
+	permissions := sas.BlobPermissions{Read: true}
+	params, err := sas.BlobSignatureValues{
+		Protocol:      sas.ProtocolHTTPS,
+		ExpiryTime:    time.Now().UTC().Add(expiry),
+		Permissions:   permissions.String(),
+		ContainerName: spec.ContainerName,
+		BlobName:      spec.BlobName,
+	}.SignWithSharedKey(cred)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to sign SAS for blob %s", spec.BlobName)
+	}
+
+	return fmt.Sprintf("%s%s/%s?%s", serviceURL, spec.ContainerName, spec.BlobName, params.Encode()), nil
+}
+
+// DeleteBlob deletes the blob described by the spec. It is a no-op if the blob or the storage account does not exist.
+func (ac *AzureClient) DeleteBlob(ctx context.Context, spec *azure.BootstrapDataBlobSpec) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootstrapdata.AzureClient.DeleteBlob")
+	defer done()
+
+	_, _, blobClient, err := ac.blobClient(ctx, spec)
+	if err != nil {
+		if azure.ResourceNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if _, err := blobClient.DeleteBlob(ctx, spec.ContainerName, spec.BlobName, nil); err != nil &&
+		!bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.ContainerNotFound) {
+		return errors.Wrapf(err, "failed to delete blob %s", spec.BlobName)
+	}
+	return nil
+}
+
+// blobClient returns a blob service client authenticated with a key of the storage account.
+func (ac *AzureClient) blobClient(ctx context.Context, spec *azure.BootstrapDataBlobSpec) (string, *azblob.SharedKeyCredential, *azblob.Client, error) {
+	env, err := azureautorest.EnvironmentFromName(ac.cloudEnv)
+	if err != nil {
+		return "", nil, nil, errors.Wrap(err, "failed to get Azure environment")
+	}
+
+	keys, err := ac.accounts.ListKeys(ctx, spec.ResourceGroup, spec.StorageAccountName, nil)
+	if err != nil {
+		return "", nil, nil, errors.Wrapf(err, "failed to list keys of storage account %s", spec.StorageAccountName)
+	}
+	if len(keys.Keys) == 0 || keys.Keys[0].Value == nil {
+		return "", nil, nil, errors.Errorf("storage account %s has no keys", spec.StorageAccountName)
+	}
+
+	cred, err := azblob.NewSharedKeyCredential(spec.StorageAccountName, *keys.Keys[0].Value)
+	if err != nil {
+		return "", nil, nil, errors.Wrap(err, "failed to create shared key credential")
+	}
+	serviceURL := fmt.Sprintf("https://%s.blob.%s/", spec.StorageAccountName, env.StorageEndpointSuffix)
+	blobClient, err := azblob.NewClientWithSharedKeyCredential(serviceURL, cred, &azblob.ClientOptions{ClientOptions: ac.clientOptions.ClientOptions})
+	if err != nil {
+		return "", nil, nil, errors.Wrap(err, "failed to create blob client")
+	}
+	return serviceURL, cred, blobClient, nil
+}
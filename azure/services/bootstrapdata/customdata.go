@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapdata
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ContainerName is the name of the container holding bootstrap data blobs.
+	ContainerName = "bootstrap-data"
+
+	// maxCustomDataSize is the maximum size of the decoded custom data of a VM.
+	maxCustomDataSize = 65535
+
+	ignitionFormat = "ignition"
+)
+
+// needsBlob returns whether the bootstrap data has to be delivered through a blob rather than as custom data.
+func needsBlob(format string, data []byte) bool {
+	return format == ignitionFormat || len(data) > maxCustomDataSize
+}
+
+// customData returns the custom data telling the VM to fetch its bootstrap data from the given URL.
+func customData(format string, data []byte, url string) ([]byte, error) {
+	if format != ignitionFormat {
+		return []byte(fmt.Sprintf("#include\n%s\n", url)), nil
+	}
+
+	// Reuse the version of the original config so the stub is understood by the same Ignition releases.
+	var original struct {
+		Ignition struct {
+			Version string `json:"version"`
+		} `json:"ignition"`
+	}
+	if err := json.Unmarshal(data, &original); err != nil {
+		return nil, errors.Wrap(err, "failed to parse Ignition bootstrap data")
+	}
+	stub := map[string]interface{}{
+		"ignition": map[string]interface{}{
+			"version": original.Ignition.Version,
+			"config": map[string]interface{}{
+				"replace": map[string]interface{}{
+					"source": url,
+				},
+			},
+		},
+	}
+	return json.Marshal(stub)
+}
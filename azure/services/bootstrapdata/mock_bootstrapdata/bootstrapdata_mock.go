@@ -0,0 +1,310 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../bootstrapdata.go
+//
+// Generated by this command:
+//
+//	mockgen -destination bootstrapdata_mock.go -package mock_bootstrapdata -source ../bootstrapdata.go BootstrapDataScope
+//
+// Package mock_bootstrapdata is a generated GoMock package.
+package mock_bootstrapdata
+
+import (
+	reflect "reflect"
+
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	gomock "go.uber.org/mock/gomock"
+	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+	v1beta10 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// MockBootstrapDataScope is a mock of BootstrapDataScope interface.
+type MockBootstrapDataScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockBootstrapDataScopeMockRecorder
+}
+
+// MockBootstrapDataScopeMockRecorder is the mock recorder for MockBootstrapDataScope.
+type MockBootstrapDataScopeMockRecorder struct {
+	mock *MockBootstrapDataScope
+}
+
+// NewMockBootstrapDataScope creates a new mock instance.
+func NewMockBootstrapDataScope(ctrl *gomock.Controller) *MockBootstrapDataScope {
+	mock := &MockBootstrapDataScope{ctrl: ctrl}
+	mock.recorder = &MockBootstrapDataScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBootstrapDataScope) EXPECT() *MockBootstrapDataScopeMockRecorder {
+	return m.recorder
+}
+
+// BaseURI mocks base method.
+func (m *MockBootstrapDataScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockBootstrapDataScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockBootstrapDataScope)(nil).BaseURI))
+}
+
+// BootstrapDataBlobSpec mocks base method.
+func (m *MockBootstrapDataScope) BootstrapDataBlobSpec() *azure.BootstrapDataBlobSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataBlobSpec")
+	ret0, _ := ret[0].(*azure.BootstrapDataBlobSpec)
+	return ret0
+}
+
+// BootstrapDataBlobSpec indicates an expected call of BootstrapDataBlobSpec.
+func (mr *MockBootstrapDataScopeMockRecorder) BootstrapDataBlobSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataBlobSpec", reflect.TypeOf((*MockBootstrapDataScope)(nil).BootstrapDataBlobSpec))
+}
+
+// ClearBootstrapDataBlob mocks base method.
+func (m *MockBootstrapDataScope) ClearBootstrapDataBlob() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearBootstrapDataBlob")
+}
+
+// ClearBootstrapDataBlob indicates an expected call of ClearBootstrapDataBlob.
+func (mr *MockBootstrapDataScopeMockRecorder) ClearBootstrapDataBlob() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearBootstrapDataBlob", reflect.TypeOf((*MockBootstrapDataScope)(nil).ClearBootstrapDataBlob))
+}
+
+// ClientID mocks base method.
+func (m *MockBootstrapDataScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockBootstrapDataScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockBootstrapDataScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockBootstrapDataScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockBootstrapDataScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockBootstrapDataScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockBootstrapDataScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockBootstrapDataScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockBootstrapDataScope)(nil).CloudEnvironment))
+}
+
+// DeleteLongRunningOperationState mocks base method.
+func (m *MockBootstrapDataScope) DeleteLongRunningOperationState(arg0, arg1, arg2 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLongRunningOperationState", arg0, arg1, arg2)
+}
+
+// DeleteLongRunningOperationState indicates an expected call of DeleteLongRunningOperationState.
+func (mr *MockBootstrapDataScopeMockRecorder) DeleteLongRunningOperationState(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockBootstrapDataScope)(nil).DeleteLongRunningOperationState), arg0, arg1, arg2)
+}
+
+// GetLongRunningOperationState mocks base method.
+func (m *MockBootstrapDataScope) GetLongRunningOperationState(arg0, arg1, arg2 string) *v1beta1.Future {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongRunningOperationState", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	return ret0
+}
+
+// GetLongRunningOperationState indicates an expected call of GetLongRunningOperationState.
+func (mr *MockBootstrapDataScopeMockRecorder) GetLongRunningOperationState(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongRunningOperationState", reflect.TypeOf((*MockBootstrapDataScope)(nil).GetLongRunningOperationState), arg0, arg1, arg2)
+}
+
+// HasBootstrapDataBlob mocks base method.
+func (m *MockBootstrapDataScope) HasBootstrapDataBlob() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasBootstrapDataBlob")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasBootstrapDataBlob indicates an expected call of HasBootstrapDataBlob.
+func (mr *MockBootstrapDataScopeMockRecorder) HasBootstrapDataBlob() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasBootstrapDataBlob", reflect.TypeOf((*MockBootstrapDataScope)(nil).HasBootstrapDataBlob))
+}
+
+// HashKey mocks base method.
+func (m *MockBootstrapDataScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockBootstrapDataScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockBootstrapDataScope)(nil).HashKey))
+}
+
+// IsBootstrapped mocks base method.
+func (m *MockBootstrapDataScope) IsBootstrapped() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBootstrapped")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsBootstrapped indicates an expected call of IsBootstrapped.
+func (mr *MockBootstrapDataScopeMockRecorder) IsBootstrapped() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBootstrapped", reflect.TypeOf((*MockBootstrapDataScope)(nil).IsBootstrapped))
+}
+
+// SetBootstrapData mocks base method.
+func (m *MockBootstrapDataScope) SetBootstrapData(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBootstrapData", arg0)
+}
+
+// SetBootstrapData indicates an expected call of SetBootstrapData.
+func (mr *MockBootstrapDataScopeMockRecorder) SetBootstrapData(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapData", reflect.TypeOf((*MockBootstrapDataScope)(nil).SetBootstrapData), arg0)
+}
+
+// SetLongRunningOperationState mocks base method.
+func (m *MockBootstrapDataScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLongRunningOperationState", arg0)
+}
+
+// SetLongRunningOperationState indicates an expected call of SetLongRunningOperationState.
+func (mr *MockBootstrapDataScopeMockRecorder) SetLongRunningOperationState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockBootstrapDataScope)(nil).SetLongRunningOperationState), arg0)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockBootstrapDataScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockBootstrapDataScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockBootstrapDataScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockBootstrapDataScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockBootstrapDataScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockBootstrapDataScope)(nil).TenantID))
+}
+
+// Token mocks base method.
+func (m *MockBootstrapDataScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockBootstrapDataScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockBootstrapDataScope)(nil).Token))
+}
+
+// UpdateDeleteStatus mocks base method.
+func (m *MockBootstrapDataScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateDeleteStatus", arg0, arg1, arg2)
+}
+
+// UpdateDeleteStatus indicates an expected call of UpdateDeleteStatus.
+func (mr *MockBootstrapDataScopeMockRecorder) UpdateDeleteStatus(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDeleteStatus", reflect.TypeOf((*MockBootstrapDataScope)(nil).UpdateDeleteStatus), arg0, arg1, arg2)
+}
+
+// UpdatePatchStatus mocks base method.
+func (m *MockBootstrapDataScope) UpdatePatchStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePatchStatus", arg0, arg1, arg2)
+}
+
+// UpdatePatchStatus indicates an expected call of UpdatePatchStatus.
+func (mr *MockBootstrapDataScopeMockRecorder) UpdatePatchStatus(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePatchStatus", reflect.TypeOf((*MockBootstrapDataScope)(nil).UpdatePatchStatus), arg0, arg1, arg2)
+}
+
+// UpdatePutStatus mocks base method.
+func (m *MockBootstrapDataScope) UpdatePutStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePutStatus", arg0, arg1, arg2)
+}
+
+// UpdatePutStatus indicates an expected call of UpdatePutStatus.
+func (mr *MockBootstrapDataScopeMockRecorder) UpdatePutStatus(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePutStatus", reflect.TypeOf((*MockBootstrapDataScope)(nil).UpdatePutStatus), arg0, arg1, arg2)
+}
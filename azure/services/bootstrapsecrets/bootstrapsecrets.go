@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapsecrets
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "bootstrapsecrets"
+
+// BootstrapSecretScope defines the scope interface for a bootstrap secrets service.
+type BootstrapSecretScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	BootstrapDataSecretSpec() *azure.BootstrapDataSecretSpec
+	HasBootstrapDataSecret() bool
+	ClearBootstrapDataSecret()
+	IsBootstrapped() bool
+	Name() string
+	ResourceGroup() string
+}
+
+// Service provides operations on the Key Vault secrets holding bootstrap data.
+type Service struct {
+	Scope                 BootstrapSecretScope
+	client                Client
+	virtualMachinesGetter async.Getter
+}
+
+// New creates a new bootstrap secrets service.
+func New(scope BootstrapSecretScope) (*Service, error) {
+	client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	virtualMachinesClient, err := virtualmachines.NewClient(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create virtualmachines service")
+	}
+	return &Service{
+		Scope:                 scope,
+		client:                client,
+		virtualMachinesGetter: virtualMachinesClient,
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile stores the bootstrap data of the machine in a Key Vault secret and allows the system-assigned identity of
+// its VM to read it. The secret and the access policy are deleted once the machine has joined the cluster.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "bootstrapsecrets.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.BootstrapDataSecretSpec()
+	if spec == nil {
+		log.V(2).Info("skip reconciliation when no bootstrap data Key Vault is configured")
+		return nil
+	}
+
+	if s.Scope.IsBootstrapped() {
+		if !s.Scope.HasBootstrapDataSecret() {
+			return nil
+		}
+		if err := s.deleteSecret(ctx, spec); err != nil {
+			s.Scope.UpdatePutStatus(infrav1.BootstrapDataSecretReadyCondition, serviceName, err)
+			return err
+		}
+		s.Scope.ClearBootstrapDataSecret()
+		return nil
+	}
+
+	err := s.setSecret(ctx, spec)
+	s.Scope.UpdatePutStatus(infrav1.BootstrapDataSecretReadyCondition, serviceName, err)
+	return err
+}
+
+// setSecret stores the bootstrap data in the secret and gives the VM access to it.
+func (s *Service) setSecret(ctx context.Context, spec *azure.BootstrapDataSecretSpec) error {
+	value, err := secretValue(spec.BootstrapData)
+	if err != nil {
+		return err
+	}
+	principalID, err := s.getVMPrincipalID(ctx)
+	if err != nil {
+		return err
+	}
+	if principalID == "" {
+		return errors.Errorf("VM %s has no system-assigned identity to read its bootstrap data from Key Vault %s", s.Scope.Name(), spec.VaultName)
+	}
+	if err := s.client.SetSecret(ctx, spec, value); err != nil {
+		return err
+	}
+	return s.client.AddAccessPolicy(ctx, spec, principalID)
+}
+
+// deleteSecret deletes the secret and the access policy of the VM, if it still exists.
+func (s *Service) deleteSecret(ctx context.Context, spec *azure.BootstrapDataSecretSpec) error {
+	if err := s.client.DeleteSecret(ctx, spec); err != nil {
+		return err
+	}
+	principalID, err := s.getVMPrincipalID(ctx)
+	if azure.ResourceNotFound(err) || (err == nil && principalID == "") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.client.RemoveAccessPolicy(ctx, spec, principalID)
+}
+
+// Delete deletes the secret holding the bootstrap data of the machine and the access policy of its VM, if any.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "bootstrapsecrets.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.BootstrapDataSecretSpec()
+	if spec == nil || !s.Scope.HasBootstrapDataSecret() {
+		log.V(2).Info("skip deletion when no bootstrap data secret exists")
+		return nil
+	}
+
+	if err := s.deleteSecret(ctx, spec); err != nil {
+		s.Scope.UpdateDeleteStatus(infrav1.BootstrapDataSecretReadyCondition, serviceName, err)
+		return err
+	}
+	s.Scope.ClearBootstrapDataSecret()
+	return nil
+}
+
+// IsManaged always returns true as the secrets holding bootstrap data are always created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// getVMPrincipalID returns the principal ID of the system-assigned identity of the VM, or an empty string if it has none.
+func (s *Service) getVMPrincipalID(ctx context.Context) (string, error) {
+	spec := &virtualmachines.VMSpec{
+		Name:          s.Scope.Name(),
+		ResourceGroup: s.Scope.ResourceGroup(),
+	}
+	resultVMIface, err := s.virtualMachinesGetter.Get(ctx, spec)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get principal ID for VM")
+	}
+	resultVM, ok := resultVMIface.(armcompute.VirtualMachine)
+	if !ok {
+		return "", errors.Errorf("%T is not an armcompute.VirtualMachine", resultVMIface)
+	}
+	if resultVM.Identity == nil || resultVM.Identity.PrincipalID == nil {
+		return "", nil
+	}
+	return *resultVM.Identity.PrincipalID, nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapsecrets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootstrapsecrets/mock_bootstrapsecrets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const fakePrincipalID = "00000000-0000-0000-0000-000000000001"
+
+var (
+	fakeVMSpec = &virtualmachines.VMSpec{
+		Name:          "test-machine",
+		ResourceGroup: "test-rg",
+	}
+	fakeVM = armcompute.VirtualMachine{
+		Identity: &armcompute.VirtualMachineIdentity{
+			Type:        ptr.To(armcompute.ResourceIdentityTypeSystemAssigned),
+			PrincipalID: ptr.To(fakePrincipalID),
+		},
+	}
+	notFoundErr = &azcore.ResponseError{StatusCode: http.StatusNotFound}
+)
+
+func fakeSecretSpec(data string) *azure.BootstrapDataSecretSpec {
+	return &azure.BootstrapDataSecretSpec{
+		VaultName:          "capz-bootstrap",
+		VaultResourceGroup: "vault-rg",
+		SecretName:         "test-machine",
+		BootstrapData:      base64.StdEncoding.EncodeToString([]byte(data)),
+	}
+}
+
+func expectVM(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_async.MockGetterMockRecorder, vm interface{}, err error) {
+	s.Name().Return("test-machine").AnyTimes()
+	s.ResourceGroup().Return("test-rg")
+	m.Get(gomockinternal.AContext(), fakeVMSpec).Return(vm, err)
+}
+
+func TestReconcileBootstrapSecrets(t *testing.T) {
+	setErr := errors.New("set failed")
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_async.MockGetterMockRecorder, c *mock_bootstrapsecrets.MockClientMockRecorder)
+	}{
+		{
+			name: "noop if no bootstrap data Key Vault is configured",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_async.MockGetterMockRecorder, c *mock_bootstrapsecrets.MockClientMockRecorder) {
+				s.BootstrapDataSecretSpec().Return(nil)
+			},
+		},
+		{
+			name: "bootstrap data is stored and the VM is given access to it",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_async.MockGetterMockRecorder, c *mock_bootstrapsecrets.MockClientMockRecorder) {
+				spec := fakeSecretSpec("#cloud-config\n")
+				s.BootstrapDataSecretSpec().Return(spec)
+				s.IsBootstrapped().Return(false)
+				expectVM(s, m, fakeVM, nil)
+				c.SetSecret(gomockinternal.AContext(), spec, gomock.Any()).Return(nil)
+				c.AddAccessPolicy(gomockinternal.AContext(), spec, fakePrincipalID).Return(nil)
+				s.UpdatePutStatus(infrav1.BootstrapDataSecretReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "VM without a system-assigned identity cannot be given access",
+			expectedError: "VM test-machine has no system-assigned identity",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_async.MockGetterMockRecorder, c *mock_bootstrapsecrets.MockClientMockRecorder) {
+				s.BootstrapDataSecretSpec().Return(fakeSecretSpec("#cloud-config\n"))
+				s.IsBootstrapped().Return(false)
+				expectVM(s, m, armcompute.VirtualMachine{}, nil)
+				s.UpdatePutStatus(infrav1.BootstrapDataSecretReadyCondition, serviceName, gomockinternal.ErrStrEq("VM test-machine has no system-assigned identity to read its bootstrap data from Key Vault capz-bootstrap"))
+			},
+		},
+		{
+			name:          "error storing bootstrap data",
+			expectedError: "set failed",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_async.MockGetterMockRecorder, c *mock_bootstrapsecrets.MockClientMockRecorder) {
+				spec := fakeSecretSpec("#cloud-config\n")
+				s.BootstrapDataSecretSpec().Return(spec)
+				s.IsBootstrapped().Return(false)
+				expectVM(s, m, fakeVM, nil)
+				c.SetSecret(gomockinternal.AContext(), spec, gomock.Any()).Return(setErr)
+				s.UpdatePutStatus(infrav1.BootstrapDataSecretReadyCondition, serviceName, setErr)
+			},
+		},
+		{
+			name: "secret and access policy are deleted once the machine has joined the cluster",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_async.MockGetterMockRecorder, c *mock_bootstrapsecrets.MockClientMockRecorder) {
+				spec := fakeSecretSpec("#cloud-config\n")
+				s.BootstrapDataSecretSpec().Return(spec)
+				s.IsBootstrapped().Return(true)
+				s.HasBootstrapDataSecret().Return(true)
+				c.DeleteSecret(gomockinternal.AContext(), spec).Return(nil)
+				expectVM(s, m, fakeVM, nil)
+				c.RemoveAccessPolicy(gomockinternal.AContext(), spec, fakePrincipalID).Return(nil)
+				s.ClearBootstrapDataSecret()
+			},
+		},
+		{
+			name: "noop once the secret has been deleted",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_async.MockGetterMockRecorder, c *mock_bootstrapsecrets.MockClientMockRecorder) {
+				s.BootstrapDataSecretSpec().Return(fakeSecretSpec("#cloud-config\n"))
+				s.IsBootstrapped().Return(true)
+				s.HasBootstrapDataSecret().Return(false)
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_bootstrapsecrets.NewMockBootstrapSecretScope(mockCtrl)
+			getterMock := mock_async.NewMockGetter(mockCtrl)
+			clientMock := mock_bootstrapsecrets.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), getterMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:                 scopeMock,
+				client:                clientMock,
+				virtualMachinesGetter: getterMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteBootstrapSecrets(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_bootstrapsecrets.NewMockBootstrapSecretScope(mockCtrl)
+	getterMock := mock_async.NewMockGetter(mockCtrl)
+	clientMock := mock_bootstrapsecrets.NewMockClient(mockCtrl)
+
+	// No access policy is removed when the VM was never created.
+	spec := fakeSecretSpec("")
+	scopeMock.EXPECT().BootstrapDataSecretSpec().Return(spec)
+	scopeMock.EXPECT().HasBootstrapDataSecret().Return(true)
+	clientMock.EXPECT().DeleteSecret(gomockinternal.AContext(), spec).Return(nil)
+	expectVM(scopeMock.EXPECT(), getterMock.EXPECT(), nil, notFoundErr)
+	scopeMock.EXPECT().ClearBootstrapDataSecret()
+
+	s := &Service{
+		Scope:                 scopeMock,
+		client:                clientMock,
+		virtualMachinesGetter: getterMock,
+	}
+	g.Expect(s.Delete(context.TODO())).To(Succeed())
+}
+
+func TestSecretValue(t *testing.T) {
+	g := NewWithT(t)
+
+	data := "#cloud-config\n" + strings.Repeat("a", 64*1024)
+	value, err := secretValue(base64.StdEncoding.EncodeToString([]byte(data)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(value)).To(BeNumerically("<=", maxSecretSize))
+
+	compressed, err := base64.StdEncoding.DecodeString(value)
+	g.Expect(err).NotTo(HaveOccurred())
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	g.Expect(err).NotTo(HaveOccurred())
+	decompressed, err := io.ReadAll(zr)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(decompressed)).To(Equal(data))
+}
+
+func TestCustomData(t *testing.T) {
+	g := NewWithT(t)
+
+	customData, err := CustomData("AzurePublicCloud", "capz-bootstrap", "test-machine")
+	g.Expect(err).NotTo(HaveOccurred())
+	script, err := base64.StdEncoding.DecodeString(customData)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(script)).To(HavePrefix("#!/bin/bash\n"))
+	g.Expect(string(script)).To(ContainSubstring("resource=https%3A%2F%2Fvault.azure.net"))
+	g.Expect(string(script)).To(ContainSubstring(`secret_url="https://capz-bootstrap.vault.azure.net/secrets/test-machine?api-version=7.4"`))
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapsecrets
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// vaultsAPIVersion is the version of the Key Vault management API used to update access policies.
+const vaultsAPIVersion = "2022-07-01"
+
+// Client wraps go-sdk.
+type Client interface {
+	SetSecret(ctx context.Context, spec *azure.BootstrapDataSecretSpec, value string) error
+	DeleteSecret(ctx context.Context, spec *azure.BootstrapDataSecretSpec) error
+	AddAccessPolicy(ctx context.Context, spec *azure.BootstrapDataSecretSpec, objectID string) error
+	RemoveAccessPolicy(ctx context.Context, spec *azure.BootstrapDataSecretSpec, objectID string) error
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	vaults  *arm.Client
+	secrets *azcore.Client
+	auth    azure.Authorizer
+	env     azureautorest.Environment
+}
+
+var _ Client = (*AzureClient)(nil)
+
+// NewClient creates a new bootstrap secrets client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	env, err := azureautorest.EnvironmentFromName(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Azure environment")
+	}
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create bootstrapsecrets client options")
+	}
+	vaultsClient, err := arm.NewClient("bootstrapsecrets.AzureClient", "v1.0.0", auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Key Vault management client")
+	}
+	secretsClient, err := azcore.NewClient("bootstrapsecrets.AzureClient", "v1.0.0", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{runtime.NewBearerTokenPolicy(auth.Token(), []string{env.ResourceIdentifiers.KeyVault + "/.default"}, nil)},
+	}, &opts.ClientOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Key Vault secrets client")
+	}
+	return &AzureClient{
+		vaults:  vaultsClient,
+		secrets: secretsClient,
+		auth:    auth,
+		env:     env,
+	}, nil
+}
+
+// SetSecret stores the value of the secret described by the spec, creating a new version if it already exists.
+func (ac *AzureClient) SetSecret(ctx context.Context, spec *azure.BootstrapDataSecretSpec, value string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootstrapsecrets.AzureClient.SetSecret")
+	defer done()
+
+	req, err := ac.newSecretRequest(ctx, http.MethodPut, spec)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"value":       value,
+		"contentType": "application/gzip",
+	}
+	if err := runtime.MarshalAsJSON(req, body); err != nil {
+		return errors.Wrapf(err, "failed to encode secret %s", spec.SecretName)
+	}
+	resp, err := ac.secrets.Pipeline().Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set secret %s in Key Vault %s", spec.SecretName, spec.VaultName)
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return errors.Wrapf(runtime.NewResponseError(resp), "failed to set secret %s in Key Vault %s", spec.SecretName, spec.VaultName)
+	}
+	return nil
+}
+
+// DeleteSecret deletes the secret described by the spec. It is a no-op if the secret does not exist.
+func (ac *AzureClient) DeleteSecret(ctx context.Context, spec *azure.BootstrapDataSecretSpec) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootstrapsecrets.AzureClient.DeleteSecret")
+	defer done()
+
+	req, err := ac.newSecretRequest(ctx, http.MethodDelete, spec)
+	if err != nil {
+		return err
+	}
+	resp, err := ac.secrets.Pipeline().Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete secret %s from Key Vault %s", spec.SecretName, spec.VaultName)
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusNotFound) {
+		return errors.Wrapf(runtime.NewResponseError(resp), "failed to delete secret %s from Key Vault %s", spec.SecretName, spec.VaultName)
+	}
+	return nil
+}
+
+// AddAccessPolicy allows the principal with the given object ID to read the secrets of the Key Vault described by the spec.
+func (ac *AzureClient) AddAccessPolicy(ctx context.Context, spec *azure.BootstrapDataSecretSpec, objectID string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootstrapsecrets.AzureClient.AddAccessPolicy")
+	defer done()
+
+	return ac.updateAccessPolicy(ctx, spec, "add", objectID)
+}
+
+// RemoveAccessPolicy removes the access policy of the principal with the given object ID from the Key Vault described
+// by the spec. It is a no-op if the Key Vault does not exist.
+func (ac *AzureClient) RemoveAccessPolicy(ctx context.Context, spec *azure.BootstrapDataSecretSpec, objectID string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootstrapsecrets.AzureClient.RemoveAccessPolicy")
+	defer done()
+
+	err := ac.updateAccessPolicy(ctx, spec, "remove", objectID)
+	if azure.ResourceNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// updateAccessPolicy adds or removes the permission to get secrets of a principal from the access policies of a Key Vault.
+func (ac *AzureClient) updateAccessPolicy(ctx context.Context, spec *azure.BootstrapDataSecretSpec, operation, objectID string) error {
+	urlPath := runtime.JoinPaths("/subscriptions", ac.auth.SubscriptionID(), "resourceGroups", spec.VaultResourceGroup,
+		"providers/Microsoft.KeyVault/vaults", spec.VaultName, "accessPolicies", operation)
+	req, err := runtime.NewRequest(ctx, http.MethodPut, runtime.JoinPaths(ac.vaults.Endpoint(), urlPath))
+	if err != nil {
+		return errors.Wrap(err, "failed to create Key Vault access policy request")
+	}
+	query := req.Raw().URL.Query()
+	query.Set("api-version", vaultsAPIVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header["Accept"] = []string{"application/json"}
+	body := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"accessPolicies": []interface{}{
+				map[string]interface{}{
+					"tenantId": ac.auth.TenantID(),
+					"objectId": objectID,
+					"permissions": map[string]interface{}{
+						"secrets": []string{"get"},
+					},
+				},
+			},
+		},
+	}
+	if err := runtime.MarshalAsJSON(req, body); err != nil {
+		return errors.Wrap(err, "failed to encode Key Vault access policy")
+	}
+	resp, err := ac.vaults.Pipeline().Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to %s access policy of Key Vault %s", operation, spec.VaultName)
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusCreated) {
+		return errors.Wrapf(runtime.NewResponseError(resp), "failed to %s access policy of Key Vault %s", operation, spec.VaultName)
+	}
+	return nil
+}
+
+// newSecretRequest creates a request for the secret described by the spec on the data plane of its Key Vault.
+func (ac *AzureClient) newSecretRequest(ctx context.Context, method string, spec *azure.BootstrapDataSecretSpec) (*policy.Request, error) {
+	req, err := runtime.NewRequest(ctx, method, runtime.JoinPaths(vaultURL(ac.env, spec.VaultName), "secrets", spec.SecretName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Key Vault secret request")
+	}
+	query := req.Raw().URL.Query()
+	query.Set("api-version", secretsAPIVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header["Accept"] = []string{"application/json"}
+	return req, nil
+}
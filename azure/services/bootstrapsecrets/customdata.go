@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapsecrets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxSecretSize is the maximum size of the value of a Key Vault secret.
+	maxSecretSize = 25 * 1024
+
+	// secretsAPIVersion is the version of the Key Vault data plane API used to read and write secrets.
+	secretsAPIVersion = "7.4"
+
+	// customDataScript is run by cloud-init in place of the bootstrap data. It waits until the secret holding the
+	// bootstrap data can be read with the system-assigned identity of the VM, then runs the bootstrap data with cloud-init.
+	customDataScript = `#!/bin/bash
+set -o nounset -o pipefail
+
+userdata=/etc/capz-bootstrap-data.txt
+# The script is run again by the final stage of cloud-init below once the bootstrap data has been fetched.
+if [ -e "${userdata}" ]; then
+  exit 0
+fi
+
+token_url="http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01&resource=%[1]s"
+secret_url="%[2]s/secrets/%[3]s?api-version=%[4]s"
+until token=$(curl -sSf -H Metadata:true "${token_url}" | python3 -c 'import json,sys; print(json.load(sys.stdin)["access_token"])') &&
+  curl -sSf -H "Authorization: Bearer ${token}" "${secret_url}" |
+  python3 -c 'import base64,gzip,json,sys; sys.stdout.buffer.write(gzip.decompress(base64.b64decode(json.load(sys.stdin)["value"])))' > "${userdata}.tmp"; do
+  echo "waiting for the bootstrap data to be readable from Key Vault"
+  sleep 10
+done
+chmod 0600 "${userdata}.tmp"
+if head -n 1 "${userdata}.tmp" | grep -q '^## template: jinja'; then
+  cloud-init devel render "${userdata}.tmp" > "${userdata}"
+  rm -f "${userdata}.tmp"
+else
+  mv "${userdata}.tmp" "${userdata}"
+fi
+
+# Run the modules of every stage again with the bootstrap data merged into the configuration.
+rm -rf /var/lib/cloud/instance/sem
+cloud-init --file "${userdata}" modules --mode=init
+cloud-init --file "${userdata}" modules --mode=config
+cloud-init --file "${userdata}" modules --mode=final
+`
+)
+
+// CustomData returns the base64 encoded custom data telling a VM to fetch its bootstrap data from a secret of a Key Vault.
+func CustomData(cloudEnv, vaultName, secretName string) (string, error) {
+	env, err := azureautorest.EnvironmentFromName(cloudEnv)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get Azure environment")
+	}
+	script := fmt.Sprintf(customDataScript, url.QueryEscape(env.ResourceIdentifiers.KeyVault), vaultURL(env, vaultName), secretName, secretsAPIVersion)
+	return base64.StdEncoding.EncodeToString([]byte(script)), nil
+}
+
+// vaultURL returns the URL of the data plane of a Key Vault.
+func vaultURL(env azureautorest.Environment, vaultName string) string {
+	return fmt.Sprintf("https://%s.%s", vaultName, env.KeyVaultDNSSuffix)
+}
+
+// secretValue compresses the base64 encoded bootstrap data of a machine into the value of a Key Vault secret, since
+// the bootstrap data of a control plane machine, which includes the cluster certificates, often exceeds the size
+// limit of secrets otherwise.
+func secretValue(bootstrapData string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(bootstrapData)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode bootstrap data")
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return "", errors.Wrap(err, "failed to compress bootstrap data")
+	}
+	if err := zw.Close(); err != nil {
+		return "", errors.Wrap(err, "failed to compress bootstrap data")
+	}
+	value := base64.StdEncoding.EncodeToString(buf.Bytes())
+	if len(value) > maxSecretSize {
+		return "", errors.Errorf("compressed bootstrap data of %d bytes exceeds the maximum Key Vault secret size of %d bytes", len(value), maxSecretSize)
+	}
+	return value, nil
+}
@@ -0,0 +1,326 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../bootstrapsecrets.go
+//
+// Generated by this command:
+//
+//	mockgen -destination bootstrapsecrets_mock.go -package mock_bootstrapsecrets -source ../bootstrapsecrets.go BootstrapSecretScope
+//
+// Package mock_bootstrapsecrets is a generated GoMock package.
+package mock_bootstrapsecrets
+
+import (
+	reflect "reflect"
+
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	gomock "go.uber.org/mock/gomock"
+	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+	v1beta10 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// MockBootstrapSecretScope is a mock of BootstrapSecretScope interface.
+type MockBootstrapSecretScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockBootstrapSecretScopeMockRecorder
+}
+
+// MockBootstrapSecretScopeMockRecorder is the mock recorder for MockBootstrapSecretScope.
+type MockBootstrapSecretScopeMockRecorder struct {
+	mock *MockBootstrapSecretScope
+}
+
+// NewMockBootstrapSecretScope creates a new mock instance.
+func NewMockBootstrapSecretScope(ctrl *gomock.Controller) *MockBootstrapSecretScope {
+	mock := &MockBootstrapSecretScope{ctrl: ctrl}
+	mock.recorder = &MockBootstrapSecretScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBootstrapSecretScope) EXPECT() *MockBootstrapSecretScopeMockRecorder {
+	return m.recorder
+}
+
+// BaseURI mocks base method.
+func (m *MockBootstrapSecretScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockBootstrapSecretScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockBootstrapSecretScope)(nil).BaseURI))
+}
+
+// BootstrapDataSecretSpec mocks base method.
+func (m *MockBootstrapSecretScope) BootstrapDataSecretSpec() *azure.BootstrapDataSecretSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataSecretSpec")
+	ret0, _ := ret[0].(*azure.BootstrapDataSecretSpec)
+	return ret0
+}
+
+// BootstrapDataSecretSpec indicates an expected call of BootstrapDataSecretSpec.
+func (mr *MockBootstrapSecretScopeMockRecorder) BootstrapDataSecretSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataSecretSpec", reflect.TypeOf((*MockBootstrapSecretScope)(nil).BootstrapDataSecretSpec))
+}
+
+// ClearBootstrapDataSecret mocks base method.
+func (m *MockBootstrapSecretScope) ClearBootstrapDataSecret() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearBootstrapDataSecret")
+}
+
+// ClearBootstrapDataSecret indicates an expected call of ClearBootstrapDataSecret.
+func (mr *MockBootstrapSecretScopeMockRecorder) ClearBootstrapDataSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearBootstrapDataSecret", reflect.TypeOf((*MockBootstrapSecretScope)(nil).ClearBootstrapDataSecret))
+}
+
+// ClientID mocks base method.
+func (m *MockBootstrapSecretScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockBootstrapSecretScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockBootstrapSecretScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockBootstrapSecretScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockBootstrapSecretScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockBootstrapSecretScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockBootstrapSecretScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockBootstrapSecretScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockBootstrapSecretScope)(nil).CloudEnvironment))
+}
+
+// DeleteLongRunningOperationState mocks base method.
+func (m *MockBootstrapSecretScope) DeleteLongRunningOperationState(arg0, arg1, arg2 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLongRunningOperationState", arg0, arg1, arg2)
+}
+
+// DeleteLongRunningOperationState indicates an expected call of DeleteLongRunningOperationState.
+func (mr *MockBootstrapSecretScopeMockRecorder) DeleteLongRunningOperationState(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockBootstrapSecretScope)(nil).DeleteLongRunningOperationState), arg0, arg1, arg2)
+}
+
+// GetLongRunningOperationState mocks base method.
+func (m *MockBootstrapSecretScope) GetLongRunningOperationState(arg0, arg1, arg2 string) *v1beta1.Future {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongRunningOperationState", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	return ret0
+}
+
+// GetLongRunningOperationState indicates an expected call of GetLongRunningOperationState.
+func (mr *MockBootstrapSecretScopeMockRecorder) GetLongRunningOperationState(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongRunningOperationState", reflect.TypeOf((*MockBootstrapSecretScope)(nil).GetLongRunningOperationState), arg0, arg1, arg2)
+}
+
+// HasBootstrapDataSecret mocks base method.
+func (m *MockBootstrapSecretScope) HasBootstrapDataSecret() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasBootstrapDataSecret")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasBootstrapDataSecret indicates an expected call of HasBootstrapDataSecret.
+func (mr *MockBootstrapSecretScopeMockRecorder) HasBootstrapDataSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasBootstrapDataSecret", reflect.TypeOf((*MockBootstrapSecretScope)(nil).HasBootstrapDataSecret))
+}
+
+// HashKey mocks base method.
+func (m *MockBootstrapSecretScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockBootstrapSecretScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockBootstrapSecretScope)(nil).HashKey))
+}
+
+// IsBootstrapped mocks base method.
+func (m *MockBootstrapSecretScope) IsBootstrapped() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBootstrapped")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsBootstrapped indicates an expected call of IsBootstrapped.
+func (mr *MockBootstrapSecretScopeMockRecorder) IsBootstrapped() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBootstrapped", reflect.TypeOf((*MockBootstrapSecretScope)(nil).IsBootstrapped))
+}
+
+// Name mocks base method.
+func (m *MockBootstrapSecretScope) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockBootstrapSecretScopeMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockBootstrapSecretScope)(nil).Name))
+}
+
+// ResourceGroup mocks base method.
+func (m *MockBootstrapSecretScope) ResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceGroup indicates an expected call of ResourceGroup.
+func (mr *MockBootstrapSecretScopeMockRecorder) ResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockBootstrapSecretScope)(nil).ResourceGroup))
+}
+
+// SetLongRunningOperationState mocks base method.
+func (m *MockBootstrapSecretScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLongRunningOperationState", arg0)
+}
+
+// SetLongRunningOperationState indicates an expected call of SetLongRunningOperationState.
+func (mr *MockBootstrapSecretScopeMockRecorder) SetLongRunningOperationState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockBootstrapSecretScope)(nil).SetLongRunningOperationState), arg0)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockBootstrapSecretScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockBootstrapSecretScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockBootstrapSecretScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockBootstrapSecretScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockBootstrapSecretScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockBootstrapSecretScope)(nil).TenantID))
+}
+
+// Token mocks base method.
+func (m *MockBootstrapSecretScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockBootstrapSecretScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockBootstrapSecretScope)(nil).Token))
+}
+
+// UpdateDeleteStatus mocks base method.
+func (m *MockBootstrapSecretScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateDeleteStatus", arg0, arg1, arg2)
+}
+
+// UpdateDeleteStatus indicates an expected call of UpdateDeleteStatus.
+func (mr *MockBootstrapSecretScopeMockRecorder) UpdateDeleteStatus(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDeleteStatus", reflect.TypeOf((*MockBootstrapSecretScope)(nil).UpdateDeleteStatus), arg0, arg1, arg2)
+}
+
+// UpdatePatchStatus mocks base method.
+func (m *MockBootstrapSecretScope) UpdatePatchStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePatchStatus", arg0, arg1, arg2)
+}
+
+// UpdatePatchStatus indicates an expected call of UpdatePatchStatus.
+func (mr *MockBootstrapSecretScopeMockRecorder) UpdatePatchStatus(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePatchStatus", reflect.TypeOf((*MockBootstrapSecretScope)(nil).UpdatePatchStatus), arg0, arg1, arg2)
+}
+
+// UpdatePutStatus mocks base method.
+func (m *MockBootstrapSecretScope) UpdatePutStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePutStatus", arg0, arg1, arg2)
+}
+
+// UpdatePutStatus indicates an expected call of UpdatePutStatus.
+func (mr *MockBootstrapSecretScopeMockRecorder) UpdatePutStatus(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePutStatus", reflect.TypeOf((*MockBootstrapSecretScope)(nil).UpdatePutStatus), arg0, arg1, arg2)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+//
+// Generated by this command:
+//
+//	mockgen -destination client_mock.go -package mock_bootstrapsecrets -source ../client.go Client
+//
+// Package mock_bootstrapsecrets is a generated GoMock package.
+package mock_bootstrapsecrets
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// AddAccessPolicy mocks base method.
+func (m *MockClient) AddAccessPolicy(ctx context.Context, spec *azure.BootstrapDataSecretSpec, objectID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccessPolicy", ctx, spec, objectID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAccessPolicy indicates an expected call of AddAccessPolicy.
+func (mr *MockClientMockRecorder) AddAccessPolicy(ctx, spec, objectID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccessPolicy", reflect.TypeOf((*MockClient)(nil).AddAccessPolicy), ctx, spec, objectID)
+}
+
+// DeleteSecret mocks base method.
+func (m *MockClient) DeleteSecret(ctx context.Context, spec *azure.BootstrapDataSecretSpec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSecret", ctx, spec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSecret indicates an expected call of DeleteSecret.
+func (mr *MockClientMockRecorder) DeleteSecret(ctx, spec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecret", reflect.TypeOf((*MockClient)(nil).DeleteSecret), ctx, spec)
+}
+
+// RemoveAccessPolicy mocks base method.
+func (m *MockClient) RemoveAccessPolicy(ctx context.Context, spec *azure.BootstrapDataSecretSpec, objectID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAccessPolicy", ctx, spec, objectID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAccessPolicy indicates an expected call of RemoveAccessPolicy.
+func (mr *MockClientMockRecorder) RemoveAccessPolicy(ctx, spec, objectID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAccessPolicy", reflect.TypeOf((*MockClient)(nil).RemoveAccessPolicy), ctx, spec, objectID)
+}
+
+// SetSecret mocks base method.
+func (m *MockClient) SetSecret(ctx context.Context, spec *azure.BootstrapDataSecretSpec, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSecret", ctx, spec, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSecret indicates an expected call of SetSecret.
+func (mr *MockClientMockRecorder) SetSecret(ctx, spec, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSecret", reflect.TypeOf((*MockClient)(nil).SetSecret), ctx, spec, value)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplanezones
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "controlplanezones"
+
+// minZoneRedundantMachines is the number of control plane machines from which the control plane is expected to span
+// several failure domains.
+const minZoneRedundantMachines = 3
+
+// ControlPlaneZonesScope defines the scope interface for a control plane zones service.
+type ControlPlaneZonesScope interface {
+	ControlPlaneFailureDomains() []string
+	ControlPlaneMachineFailureDomains(ctx context.Context) ([]string, error)
+	SetControlPlaneSingleZone(failureDomain string, machines int)
+	ClearControlPlaneSingleZone()
+}
+
+// Service checks that the control plane of a cluster spans several failure domains.
+type Service struct {
+	Scope ControlPlaneZonesScope
+}
+
+// New creates a new control plane zones service.
+func New(scope ControlPlaneZonesScope) *Service {
+	return &Service{
+		Scope: scope,
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile reports a control plane of at least three machines whose machines are all in the same failure domain
+// while the location of the cluster offers several. The check is informational, so failing to list the machines is
+// logged and keeps the previous result rather than failing the reconciliation of the cluster.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controlplanezones.Service.Reconcile")
+	defer done()
+
+	if len(s.Scope.ControlPlaneFailureDomains()) < 2 {
+		s.Scope.ClearControlPlaneSingleZone()
+		return nil
+	}
+
+	failureDomains, err := s.Scope.ControlPlaneMachineFailureDomains(ctx)
+	if err != nil {
+		log.Error(errors.Wrap(err, "failed to get the failure domains of the control plane machines"), "failed to check the spread of the control plane")
+		return nil
+	}
+	if failureDomain, collapsed := singleFailureDomain(failureDomains); collapsed {
+		log.V(2).Info("control plane machines are all in the same failure domain", "failureDomain", failureDomain, "machines", len(failureDomains))
+		s.Scope.SetControlPlaneSingleZone(failureDomain, len(failureDomains))
+		return nil
+	}
+	s.Scope.ClearControlPlaneSingleZone()
+	return nil
+}
+
+// Delete is a no-op as checking the spread of the control plane doesn't create any resource.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// singleFailureDomain returns the failure domain of the machines of a control plane, and true if there are enough
+// machines for the control plane to be zone-redundant but they are all in that failure domain. Machines without a
+// failure domain count as a failure domain of their own.
+func singleFailureDomain(failureDomains []string) (string, bool) {
+	if len(failureDomains) < minZoneRedundantMachines {
+		return "", false
+	}
+	for _, failureDomain := range failureDomains[1:] {
+		if failureDomain != failureDomains[0] {
+			return "", false
+		}
+	}
+	return failureDomains[0], true
+}
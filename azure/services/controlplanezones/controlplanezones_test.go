@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplanezones
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/controlplanezones/mock_controlplanezones"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func TestReconcileControlPlaneZones(t *testing.T) {
+	testcases := []struct {
+		name   string
+		expect func(s *mock_controlplanezones.MockControlPlaneZonesScopeMockRecorder)
+	}{
+		{
+			name: "control plane spread across failure domains",
+			expect: func(s *mock_controlplanezones.MockControlPlaneZonesScopeMockRecorder) {
+				s.ControlPlaneFailureDomains().Return([]string{"1", "2", "3"})
+				s.ControlPlaneMachineFailureDomains(gomockinternal.AContext()).Return([]string{"1", "2", "1"}, nil)
+				s.ClearControlPlaneSingleZone()
+			},
+		},
+		{
+			name: "control plane collapsed into a single failure domain",
+			expect: func(s *mock_controlplanezones.MockControlPlaneZonesScopeMockRecorder) {
+				s.ControlPlaneFailureDomains().Return([]string{"1", "2", "3"})
+				s.ControlPlaneMachineFailureDomains(gomockinternal.AContext()).Return([]string{"2", "2", "2"}, nil)
+				s.SetControlPlaneSingleZone("2", 3)
+			},
+		},
+		{
+			name: "control plane machines without failure domain",
+			expect: func(s *mock_controlplanezones.MockControlPlaneZonesScopeMockRecorder) {
+				s.ControlPlaneFailureDomains().Return([]string{"1", "2"})
+				s.ControlPlaneMachineFailureDomains(gomockinternal.AContext()).Return([]string{"", "", "", "", ""}, nil)
+				s.SetControlPlaneSingleZone("", 5)
+			},
+		},
+		{
+			name: "control plane of a single machine",
+			expect: func(s *mock_controlplanezones.MockControlPlaneZonesScopeMockRecorder) {
+				s.ControlPlaneFailureDomains().Return([]string{"1", "2", "3"})
+				s.ControlPlaneMachineFailureDomains(gomockinternal.AContext()).Return([]string{"1"}, nil)
+				s.ClearControlPlaneSingleZone()
+			},
+		},
+		{
+			name: "location without availability zones",
+			expect: func(s *mock_controlplanezones.MockControlPlaneZonesScopeMockRecorder) {
+				s.ControlPlaneFailureDomains().Return(nil)
+				s.ClearControlPlaneSingleZone()
+			},
+		},
+		{
+			name: "failing to list the machines keeps the previous result",
+			expect: func(s *mock_controlplanezones.MockControlPlaneZonesScopeMockRecorder) {
+				s.ControlPlaneFailureDomains().Return([]string{"1", "2", "3"})
+				s.ControlPlaneMachineFailureDomains(gomockinternal.AContext()).Return(nil, errors.New("not found"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_controlplanezones.NewMockControlPlaneZonesScope(mockCtrl)
+			tc.expect(scopeMock.EXPECT())
+
+			s := New(scopeMock)
+			g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+		})
+	}
+}
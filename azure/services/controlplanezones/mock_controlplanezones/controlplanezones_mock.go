@@ -0,0 +1,108 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../controlplanezones.go
+//
+// Generated by this command:
+//
+//	mockgen -destination controlplanezones_mock.go -package mock_controlplanezones -source ../controlplanezones.go ControlPlaneZonesScope
+//
+// Package mock_controlplanezones is a generated GoMock package.
+package mock_controlplanezones
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockControlPlaneZonesScope is a mock of ControlPlaneZonesScope interface.
+type MockControlPlaneZonesScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockControlPlaneZonesScopeMockRecorder
+}
+
+// MockControlPlaneZonesScopeMockRecorder is the mock recorder for MockControlPlaneZonesScope.
+type MockControlPlaneZonesScopeMockRecorder struct {
+	mock *MockControlPlaneZonesScope
+}
+
+// NewMockControlPlaneZonesScope creates a new mock instance.
+func NewMockControlPlaneZonesScope(ctrl *gomock.Controller) *MockControlPlaneZonesScope {
+	mock := &MockControlPlaneZonesScope{ctrl: ctrl}
+	mock.recorder = &MockControlPlaneZonesScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockControlPlaneZonesScope) EXPECT() *MockControlPlaneZonesScopeMockRecorder {
+	return m.recorder
+}
+
+// ClearControlPlaneSingleZone mocks base method.
+func (m *MockControlPlaneZonesScope) ClearControlPlaneSingleZone() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearControlPlaneSingleZone")
+}
+
+// ClearControlPlaneSingleZone indicates an expected call of ClearControlPlaneSingleZone.
+func (mr *MockControlPlaneZonesScopeMockRecorder) ClearControlPlaneSingleZone() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearControlPlaneSingleZone", reflect.TypeOf((*MockControlPlaneZonesScope)(nil).ClearControlPlaneSingleZone))
+}
+
+// ControlPlaneFailureDomains mocks base method.
+func (m *MockControlPlaneZonesScope) ControlPlaneFailureDomains() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneFailureDomains")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// ControlPlaneFailureDomains indicates an expected call of ControlPlaneFailureDomains.
+func (mr *MockControlPlaneZonesScopeMockRecorder) ControlPlaneFailureDomains() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneFailureDomains", reflect.TypeOf((*MockControlPlaneZonesScope)(nil).ControlPlaneFailureDomains))
+}
+
+// ControlPlaneMachineFailureDomains mocks base method.
+func (m *MockControlPlaneZonesScope) ControlPlaneMachineFailureDomains(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneMachineFailureDomains", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ControlPlaneMachineFailureDomains indicates an expected call of ControlPlaneMachineFailureDomains.
+func (mr *MockControlPlaneZonesScopeMockRecorder) ControlPlaneMachineFailureDomains(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneMachineFailureDomains", reflect.TypeOf((*MockControlPlaneZonesScope)(nil).ControlPlaneMachineFailureDomains), ctx)
+}
+
+// SetControlPlaneSingleZone mocks base method.
+func (m *MockControlPlaneZonesScope) SetControlPlaneSingleZone(failureDomain string, machines int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetControlPlaneSingleZone", failureDomain, machines)
+}
+
+// SetControlPlaneSingleZone indicates an expected call of SetControlPlaneSingleZone.
+func (mr *MockControlPlaneZonesScopeMockRecorder) SetControlPlaneSingleZone(failureDomain, machines any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetControlPlaneSingleZone", reflect.TypeOf((*MockControlPlaneZonesScope)(nil).SetControlPlaneSingleZone), failureDomain, machines)
+}
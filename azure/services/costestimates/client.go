@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costestimates
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/pkg/errors"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// retailPricesEndpoint is the endpoint of the Azure Retail Prices API, which doesn't require authentication.
+const retailPricesEndpoint = "https://prices.azure.com/api/retail/prices"
+
+// Price is the retail price of a meter of an Azure service.
+type Price struct {
+	CurrencyCode  string  `json:"currencyCode"`
+	RetailPrice   float64 `json:"retailPrice"`
+	UnitOfMeasure string  `json:"unitOfMeasure"`
+	ProductName   string  `json:"productName"`
+	SkuName       string  `json:"skuName"`
+	MeterName     string  `json:"meterName"`
+	Type          string  `json:"type"`
+}
+
+// Client looks up retail prices.
+type Client interface {
+	ListPrices(ctx context.Context, currencyCode, filter string) ([]Price, error)
+}
+
+// AzureClient contains the Retail Prices API client.
+type AzureClient struct {
+	pipeline runtime.Pipeline
+	endpoint string
+}
+
+var (
+	_           Client = &AzureClient{}
+	doOnce      sync.Once
+	pricesCache ttllru.PeekingCacher
+)
+
+// NewClient creates a new retail prices client.
+func NewClient() *AzureClient {
+	return &AzureClient{
+		pipeline: runtime.NewPipeline("costestimates.AzureClient", "v1.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			Transport: azureutil.Transport(),
+		}),
+		endpoint: retailPricesEndpoint,
+	}
+}
+
+// ListPrices returns the prices matching an OData filter in a currency. Prices are cached for a day since they
+// seldom change.
+func (ac *AzureClient) ListPrices(ctx context.Context, currencyCode, filter string) ([]Price, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "costestimates.AzureClient.ListPrices")
+	defer done()
+
+	var err error
+	doOnce.Do(func() {
+		pricesCache, err = ttllru.New(1024, 24*time.Hour)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating LRU cache for retail prices")
+	}
+
+	key := strings.Join([]string{ac.endpoint, currencyCode, filter}, "/")
+	if prices, ok := pricesCache.Get(key); ok {
+		return prices.([]Price), nil
+	}
+
+	var prices []Price
+	nextLink := ""
+	for {
+		page, err := ac.listPage(ctx, currencyCode, filter, nextLink)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, page.Items...)
+		if page.NextPageLink == "" {
+			break
+		}
+		nextLink = page.NextPageLink
+	}
+	_ = pricesCache.Add(key, prices)
+	return prices, nil
+}
+
+// pricesPage is a page of the response of the Retail Prices API.
+type pricesPage struct {
+	Items        []Price `json:"Items"`
+	NextPageLink string  `json:"NextPageLink"`
+}
+
+// listPage returns a page of prices, the first one if nextLink is empty.
+func (ac *AzureClient) listPage(ctx context.Context, currencyCode, filter, nextLink string) (pricesPage, error) {
+	var page pricesPage
+	url := nextLink
+	if url == "" {
+		url = ac.endpoint
+	}
+	req, err := runtime.NewRequest(ctx, http.MethodGet, url)
+	if err != nil {
+		return page, errors.Wrap(err, "failed to create retail prices request")
+	}
+	if nextLink == "" {
+		query := req.Raw().URL.Query()
+		query.Set("currencyCode", currencyCode)
+		query.Set("$filter", filter)
+		req.Raw().URL.RawQuery = query.Encode()
+	}
+	req.Raw().Header["Accept"] = []string{"application/json"}
+
+	resp, err := ac.pipeline.Do(req)
+	if err != nil {
+		return page, errors.Wrap(err, "failed to list retail prices")
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return page, errors.Wrap(runtime.NewResponseError(resp), "failed to list retail prices")
+	}
+	if err := runtime.UnmarshalAsJSON(resp, &page); err != nil {
+		return page, errors.Wrap(err, "failed to parse retail prices")
+	}
+	return page, nil
+}
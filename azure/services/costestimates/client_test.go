@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costestimates
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestListPrices(t *testing.T) {
+	g := NewWithT(t)
+
+	var requests int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page := pricesPage{}
+		switch r.URL.Query().Get("page") {
+		case "":
+			g.Expect(r.URL.Query().Get("currencyCode")).To(Equal("EUR"))
+			g.Expect(r.URL.Query().Get("$filter")).To(Equal("armSkuName eq 'Standard_D4s_v3'"))
+			page.Items = []Price{{CurrencyCode: "EUR", RetailPrice: 0.2}}
+			page.NextPageLink = server.URL + "?page=2"
+		case "2":
+			page.Items = []Price{{CurrencyCode: "EUR", RetailPrice: 0.4}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		g.Expect(json.NewEncoder(w).Encode(page)).To(Succeed())
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.endpoint = server.URL
+
+	prices, err := client.ListPrices(context.Background(), "EUR", "armSkuName eq 'Standard_D4s_v3'")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(prices).To(Equal([]Price{{CurrencyCode: "EUR", RetailPrice: 0.2}, {CurrencyCode: "EUR", RetailPrice: 0.4}}))
+	g.Expect(requests).To(Equal(2))
+
+	// Prices are served from the cache.
+	_, err = client.ListPrices(context.Background(), "EUR", "armSkuName eq 'Standard_D4s_v3'")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requests).To(Equal(2))
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costestimates
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "costestimates"
+
+// Types of the resources whose cost is estimated.
+const (
+	VirtualMachineType = "virtualMachines"
+	ManagedDiskType    = "disks"
+	LoadBalancerType   = "loadBalancers"
+)
+
+const (
+	// defaultCurrencyCode is the currency of estimates when none is configured.
+	defaultCurrencyCode = "USD"
+	// hoursPerMonth is the number of hours in a month used by Azure to convert hourly prices to monthly prices.
+	hoursPerMonth = 730
+)
+
+// CostEstimateScope defines the scope interface for a cost estimates service.
+type CostEstimateScope interface {
+	Location() string
+	CostEstimation() *infrav1.CostEstimationSpec
+	PricedResourceSpecs(ctx context.Context) ([]azure.PricedResourceSpec, error)
+	CostEstimate() *infrav1.CostEstimate
+	SetCostEstimate(*infrav1.CostEstimate)
+}
+
+// Service estimates the cost of the resources of a cluster.
+type Service struct {
+	Scope CostEstimateScope
+	Client
+}
+
+// New creates a new cost estimates service.
+func New(scope CostEstimateScope) *Service {
+	return &Service{
+		Scope:  scope,
+		Client: NewClient(),
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile estimates the monthly cost of the cluster. Cost estimation is informational, so failing to estimate the
+// cost is logged and keeps the previous estimate rather than failing the reconciliation of the cluster.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "costestimates.Service.Reconcile")
+	defer done()
+
+	costEstimation := s.Scope.CostEstimation()
+	if costEstimation == nil {
+		s.Scope.SetCostEstimate(nil)
+		return nil
+	}
+
+	estimate, err := s.estimate(ctx, costEstimation)
+	if err != nil {
+		log.Error(err, "failed to estimate the cost of the cluster")
+		return nil
+	}
+
+	// Only update the estimate when it changes, so that unchanged estimates don't update the status on every
+	// reconciliation.
+	if previous := s.Scope.CostEstimate(); previous != nil && previous.MonthlyCost == estimate.MonthlyCost &&
+		previous.CurrencyCode == estimate.CurrencyCode && equalStrings(previous.UnpricedResources, estimate.UnpricedResources) {
+		return nil
+	}
+	now := metav1.Now()
+	estimate.LastUpdated = &now
+	s.Scope.SetCostEstimate(estimate)
+	return nil
+}
+
+// Delete is a no-op as estimating the cost of a cluster doesn't create any resource.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// estimate returns the estimated monthly cost of the resources of the cluster.
+func (s *Service) estimate(ctx context.Context, costEstimation *infrav1.CostEstimationSpec) (*infrav1.CostEstimate, error) {
+	currencyCode := costEstimation.CurrencyCode
+	if currencyCode == "" {
+		currencyCode = defaultCurrencyCode
+	}
+
+	specs, err := s.Scope.PricedResourceSpecs(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the resources to price")
+	}
+
+	var total float64
+	var unpriced []string
+	for _, spec := range aggregate(specs) {
+		price, ok, err := s.monthlyPrice(ctx, currencyCode, spec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the price of %s", describe(spec))
+		}
+		if !ok {
+			unpriced = append(unpriced, describe(spec))
+			continue
+		}
+		total += price * float64(spec.Count)
+	}
+	sort.Strings(unpriced)
+
+	return &infrav1.CostEstimate{
+		MonthlyCost:       fmt.Sprintf("%.2f", total),
+		CurrencyCode:      currencyCode,
+		UnpricedResources: unpriced,
+	}, nil
+}
+
+// monthlyPrice returns the monthly retail price of a resource, and false if the resource has no known price.
+func (s *Service) monthlyPrice(ctx context.Context, currencyCode string, spec azure.PricedResourceSpec) (float64, bool, error) {
+	location := s.Scope.Location()
+	switch spec.Type {
+	case VirtualMachineType:
+		prices, err := s.Client.ListPrices(ctx, currencyCode, fmt.Sprintf(
+			"serviceName eq 'Virtual Machines' and armRegionName eq '%s' and armSkuName eq '%s' and priceType eq 'Consumption'",
+			location, spec.SKU))
+		if err != nil {
+			return 0, false, err
+		}
+		for _, price := range prices {
+			if price.UnitOfMeasure != "1 Hour" || strings.Contains(price.SkuName, "Low Priority") ||
+				strings.HasSuffix(price.ProductName, "Windows") != spec.Windows ||
+				strings.Contains(price.SkuName, "Spot") != spec.Spot {
+				continue
+			}
+			return price.RetailPrice * hoursPerMonth, true, nil
+		}
+		return 0, false, nil
+	case ManagedDiskType:
+		meter, ok := diskMeterName(spec.SKU, spec.DiskSizeGB)
+		if !ok {
+			return 0, false, nil
+		}
+		prices, err := s.Client.ListPrices(ctx, currencyCode, fmt.Sprintf(
+			"serviceName eq 'Storage' and armRegionName eq '%s' and meterName eq '%s' and priceType eq 'Consumption'",
+			location, meter))
+		if err != nil {
+			return 0, false, err
+		}
+		for _, price := range prices {
+			if price.UnitOfMeasure == "1/Month" && strings.Contains(price.ProductName, "Managed Disks") {
+				return price.RetailPrice, true, nil
+			}
+		}
+		return 0, false, nil
+	case LoadBalancerType:
+		// Basic load balancers are free of charge.
+		if strings.EqualFold(spec.SKU, string(infrav1.SKUStandard)) {
+			prices, err := s.Client.ListPrices(ctx, currencyCode, fmt.Sprintf(
+				"serviceName eq 'Load Balancer' and armRegionName eq '%s' and meterName eq 'Standard Included LB Rules and Outbound Rules' and priceType eq 'Consumption'",
+				location))
+			if err != nil {
+				return 0, false, err
+			}
+			for _, price := range prices {
+				if price.UnitOfMeasure == "1 Hour" {
+					return price.RetailPrice * hoursPerMonth, true, nil
+				}
+			}
+			return 0, false, nil
+		}
+		return 0, true, nil
+	default:
+		return 0, false, nil
+	}
+}
+
+// diskTiers are the sizes of the tiers of managed disks, in GiB, and the number of each tier.
+var diskTiers = []struct {
+	sizeGB int32
+	number int
+}{
+	{4, 1}, {8, 2}, {16, 3}, {32, 4}, {64, 6}, {128, 10}, {256, 15}, {512, 20},
+	{1024, 30}, {2048, 40}, {4096, 50}, {8192, 60}, {16384, 70}, {32767, 80},
+}
+
+// diskMeterName returns the name of the meter of a managed disk, e.g. P10 LRS Disk for a 128 GiB Premium_LRS disk,
+// and false for storage account types which are not billed per tier.
+func diskMeterName(storageAccountType string, sizeGB int32) (string, bool) {
+	prefix, redundancy, ok := strings.Cut(storageAccountType, "_")
+	if !ok {
+		return "", false
+	}
+	var letter string
+	minTier := 1
+	switch prefix {
+	case "Premium":
+		letter = "P"
+	case "StandardSSD":
+		letter = "E"
+	case "Standard":
+		letter = "S"
+		// Standard HDD tiers start at S4.
+		minTier = 4
+	default:
+		return "", false
+	}
+	for _, tier := range diskTiers {
+		if tier.number >= minTier && sizeGB <= tier.sizeGB {
+			return fmt.Sprintf("%s%d %s Disk", letter, tier.number, redundancy), true
+		}
+	}
+	return "", false
+}
+
+// aggregate merges identical resources, summing their counts.
+func aggregate(specs []azure.PricedResourceSpec) []azure.PricedResourceSpec {
+	var aggregated []azure.PricedResourceSpec
+	index := make(map[azure.PricedResourceSpec]int)
+	for _, spec := range specs {
+		if spec.Count <= 0 {
+			continue
+		}
+		key := spec
+		key.Count = 0
+		if i, ok := index[key]; ok {
+			aggregated[i].Count += spec.Count
+			continue
+		}
+		index[key] = len(aggregated)
+		aggregated = append(aggregated, spec)
+	}
+	return aggregated
+}
+
+// describe returns a human readable description of a resource, e.g. "3 Standard_D4s_v3 virtual machines".
+func describe(spec azure.PricedResourceSpec) string {
+	switch spec.Type {
+	case VirtualMachineType:
+		kind := "Linux"
+		if spec.Windows {
+			kind = "Windows"
+		}
+		if spec.Spot {
+			kind += " Spot"
+		}
+		return fmt.Sprintf("%d %s %s virtual machines", spec.Count, spec.SKU, kind)
+	case ManagedDiskType:
+		return fmt.Sprintf("%d %d GiB %s managed disks", spec.Count, spec.DiskSizeGB, spec.SKU)
+	case LoadBalancerType:
+		return fmt.Sprintf("%d %s load balancers", spec.Count, spec.SKU)
+	default:
+		return fmt.Sprintf("%d %s %s", spec.Count, spec.SKU, spec.Type)
+	}
+}
+
+// equalStrings returns true if a and b hold the same strings in the same order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
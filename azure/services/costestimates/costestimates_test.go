@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costestimates
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/costestimates/mock_costestimates"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const (
+	vmFilter   = "serviceName eq 'Virtual Machines' and armRegionName eq 'westus' and armSkuName eq 'Standard_D4s_v3' and priceType eq 'Consumption'"
+	diskFilter = "serviceName eq 'Storage' and armRegionName eq 'westus' and meterName eq 'P10 LRS Disk' and priceType eq 'Consumption'"
+	lbFilter   = "serviceName eq 'Load Balancer' and armRegionName eq 'westus' and meterName eq 'Standard Included LB Rules and Outbound Rules' and priceType eq 'Consumption'"
+)
+
+var (
+	fakeSpecs = []azure.PricedResourceSpec{
+		{Type: VirtualMachineType, SKU: "Standard_D4s_v3", Count: 2},
+		{Type: ManagedDiskType, SKU: "Premium_LRS", DiskSizeGB: 128, Count: 2},
+		{Type: VirtualMachineType, SKU: "Standard_D4s_v3", Count: 1},
+		{Type: ManagedDiskType, SKU: "Premium_LRS", DiskSizeGB: 100, Count: 1},
+		{Type: LoadBalancerType, SKU: "Standard", Count: 1},
+		{Type: ManagedDiskType, SKU: "UltraSSD_LRS", DiskSizeGB: 100, Count: 1},
+	}
+	vmPrices = []Price{
+		{CurrencyCode: "USD", RetailPrice: 0.1, UnitOfMeasure: "1 Hour", ProductName: "Virtual Machines DSv3 Series", SkuName: "D4s v3 Low Priority", Type: "Consumption"},
+		{CurrencyCode: "USD", RetailPrice: 0.05, UnitOfMeasure: "1 Hour", ProductName: "Virtual Machines DSv3 Series", SkuName: "D4s v3 Spot", Type: "Consumption"},
+		{CurrencyCode: "USD", RetailPrice: 0.4, UnitOfMeasure: "1 Hour", ProductName: "Virtual Machines DSv3 Series Windows", SkuName: "D4s v3", Type: "Consumption"},
+		{CurrencyCode: "USD", RetailPrice: 0.2, UnitOfMeasure: "1 Hour", ProductName: "Virtual Machines DSv3 Series", SkuName: "D4s v3", Type: "Consumption"},
+	}
+	diskPrices = []Price{
+		{CurrencyCode: "USD", RetailPrice: 19.71, UnitOfMeasure: "1/Month", ProductName: "Premium SSD Managed Disks", SkuName: "P10 LRS", MeterName: "P10 LRS Disk", Type: "Consumption"},
+	}
+	lbPrices = []Price{
+		{CurrencyCode: "USD", RetailPrice: 0.025, UnitOfMeasure: "1 Hour", ProductName: "Load Balancer", SkuName: "Standard", MeterName: "Standard Included LB Rules and Outbound Rules", Type: "Consumption"},
+	}
+	// 3 VMs at 0.2/hour, 3 P10 disks at 19.71/month and a Standard load balancer at 0.025/hour.
+	fakeEstimate = infrav1.CostEstimate{
+		MonthlyCost:       "515.38",
+		CurrencyCode:      "USD",
+		UnpricedResources: []string{"1 100 GiB UltraSSD_LRS managed disks"},
+	}
+)
+
+// fakeClient returns the prices of each filter, or err.
+type fakeClient struct {
+	prices map[string][]Price
+	err    error
+}
+
+func (c *fakeClient) ListPrices(_ context.Context, _, filter string) ([]Price, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.prices[filter], nil
+}
+
+func TestReconcileCostEstimates(t *testing.T) {
+	testcases := []struct {
+		name   string
+		err    error
+		expect func(g *WithT, s *mock_costestimates.MockCostEstimateScopeMockRecorder)
+	}{
+		{
+			name: "cost estimation is disabled",
+			expect: func(g *WithT, s *mock_costestimates.MockCostEstimateScopeMockRecorder) {
+				s.CostEstimation().Return(nil)
+				s.SetCostEstimate(nil)
+			},
+		},
+		{
+			name: "estimate the cost of the cluster",
+			expect: func(g *WithT, s *mock_costestimates.MockCostEstimateScopeMockRecorder) {
+				s.CostEstimation().Return(&infrav1.CostEstimationSpec{})
+				s.PricedResourceSpecs(gomockinternal.AContext()).Return(fakeSpecs, nil)
+				s.Location().Return("westus").AnyTimes()
+				s.CostEstimate().Return(nil)
+				s.SetCostEstimate(gomock.Any()).Do(func(estimate *infrav1.CostEstimate) {
+					g.Expect(estimate.LastUpdated).NotTo(BeNil())
+					estimate.LastUpdated = nil
+					g.Expect(*estimate).To(Equal(fakeEstimate))
+				})
+			},
+		},
+		{
+			name: "unchanged estimate is not updated",
+			expect: func(g *WithT, s *mock_costestimates.MockCostEstimateScopeMockRecorder) {
+				s.CostEstimation().Return(&infrav1.CostEstimationSpec{CurrencyCode: "USD"})
+				s.PricedResourceSpecs(gomockinternal.AContext()).Return(fakeSpecs, nil)
+				s.Location().Return("westus").AnyTimes()
+				previous := fakeEstimate
+				previous.LastUpdated = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+				s.CostEstimate().Return(&previous)
+			},
+		},
+		{
+			name: "failing to get prices keeps the previous estimate",
+			err:  errors.New("service unavailable"),
+			expect: func(g *WithT, s *mock_costestimates.MockCostEstimateScopeMockRecorder) {
+				s.CostEstimation().Return(&infrav1.CostEstimationSpec{})
+				s.PricedResourceSpecs(gomockinternal.AContext()).Return(fakeSpecs, nil)
+				s.Location().Return("westus").AnyTimes()
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_costestimates.NewMockCostEstimateScope(mockCtrl)
+			client := &fakeClient{
+				prices: map[string][]Price{vmFilter: vmPrices, diskFilter: diskPrices, lbFilter: lbPrices},
+				err:    tc.err,
+			}
+			tc.expect(g, scopeMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: client,
+			}
+			g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+		})
+	}
+}
+
+func TestDiskMeterName(t *testing.T) {
+	tests := []struct {
+		storageAccountType string
+		sizeGB             int32
+		want               string
+	}{
+		{storageAccountType: "Premium_LRS", sizeGB: 128, want: "P10 LRS Disk"},
+		{storageAccountType: "Premium_LRS", sizeGB: 30, want: "P4 LRS Disk"},
+		{storageAccountType: "StandardSSD_ZRS", sizeGB: 4, want: "E1 ZRS Disk"},
+		{storageAccountType: "Standard_LRS", sizeGB: 4, want: "S4 LRS Disk"},
+		{storageAccountType: "Standard_LRS", sizeGB: 1000, want: "S30 LRS Disk"},
+		{storageAccountType: "Premium_LRS", sizeGB: 40000},
+		{storageAccountType: "UltraSSD_LRS", sizeGB: 128},
+		{storageAccountType: "PremiumV2_LRS", sizeGB: 128},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.storageAccountType, func(t *testing.T) {
+			g := NewWithT(t)
+			meter, ok := diskMeterName(tt.storageAccountType, tt.sizeGB)
+			g.Expect(ok).To(Equal(tt.want != ""))
+			g.Expect(meter).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../costestimates.go
+//
+// Generated by this command:
+//
+//	mockgen -destination costestimates_mock.go -package mock_costestimates -source ../costestimates.go CostEstimateScope
+//
+// Package mock_costestimates is a generated GoMock package.
+package mock_costestimates
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockCostEstimateScope is a mock of CostEstimateScope interface.
+type MockCostEstimateScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockCostEstimateScopeMockRecorder
+}
+
+// MockCostEstimateScopeMockRecorder is the mock recorder for MockCostEstimateScope.
+type MockCostEstimateScopeMockRecorder struct {
+	mock *MockCostEstimateScope
+}
+
+// NewMockCostEstimateScope creates a new mock instance.
+func NewMockCostEstimateScope(ctrl *gomock.Controller) *MockCostEstimateScope {
+	mock := &MockCostEstimateScope{ctrl: ctrl}
+	mock.recorder = &MockCostEstimateScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCostEstimateScope) EXPECT() *MockCostEstimateScopeMockRecorder {
+	return m.recorder
+}
+
+// CostEstimate mocks base method.
+func (m *MockCostEstimateScope) CostEstimate() *v1beta1.CostEstimate {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CostEstimate")
+	ret0, _ := ret[0].(*v1beta1.CostEstimate)
+	return ret0
+}
+
+// CostEstimate indicates an expected call of CostEstimate.
+func (mr *MockCostEstimateScopeMockRecorder) CostEstimate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CostEstimate", reflect.TypeOf((*MockCostEstimateScope)(nil).CostEstimate))
+}
+
+// CostEstimation mocks base method.
+func (m *MockCostEstimateScope) CostEstimation() *v1beta1.CostEstimationSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CostEstimation")
+	ret0, _ := ret[0].(*v1beta1.CostEstimationSpec)
+	return ret0
+}
+
+// CostEstimation indicates an expected call of CostEstimation.
+func (mr *MockCostEstimateScopeMockRecorder) CostEstimation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CostEstimation", reflect.TypeOf((*MockCostEstimateScope)(nil).CostEstimation))
+}
+
+// Location mocks base method.
+func (m *MockCostEstimateScope) Location() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Location")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Location indicates an expected call of Location.
+func (mr *MockCostEstimateScopeMockRecorder) Location() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockCostEstimateScope)(nil).Location))
+}
+
+// PricedResourceSpecs mocks base method.
+func (m *MockCostEstimateScope) PricedResourceSpecs(ctx context.Context) ([]azure.PricedResourceSpec, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PricedResourceSpecs", ctx)
+	ret0, _ := ret[0].([]azure.PricedResourceSpec)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PricedResourceSpecs indicates an expected call of PricedResourceSpecs.
+func (mr *MockCostEstimateScopeMockRecorder) PricedResourceSpecs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PricedResourceSpecs", reflect.TypeOf((*MockCostEstimateScope)(nil).PricedResourceSpecs), ctx)
+}
+
+// SetCostEstimate mocks base method.
+func (m *MockCostEstimateScope) SetCostEstimate(arg0 *v1beta1.CostEstimate) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetCostEstimate", arg0)
+}
+
+// SetCostEstimate indicates an expected call of SetCostEstimate.
+func (mr *MockCostEstimateScopeMockRecorder) SetCostEstimate(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCostEstimate", reflect.TypeOf((*MockCostEstimateScope)(nil).SetCostEstimate), arg0)
+}
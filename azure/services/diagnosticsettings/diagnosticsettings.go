@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnosticsettings
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "diagnosticsettings"
+
+// DiagnosticSettingScope defines the scope interface for an Azure Monitor diagnostic settings service.
+type DiagnosticSettingScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	azure.ClusterDescriber
+	DiagnosticSettingSpecs() []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope DiagnosticSettingScope
+	async.Reconciler
+	async.Getter
+}
+
+// New creates a new service.
+func New(scope DiagnosticSettingScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		Getter:     client,
+		Reconciler: async.New[armresources.ClientCreateOrUpdateByIDResponse, armresources.ClientDeleteByIDResponse](scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile idempotently creates or updates the diagnostic settings.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "diagnosticsettings.Service.Reconcile")
+	defer done()
+
+	specs := s.Scope.DiagnosticSettingSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	// We go through the list of DiagnosticSettingSpecs to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var result error
+	for _, settingSpec := range specs {
+		if _, err := s.CreateOrUpdateResource(ctx, settingSpec, ServiceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.DiagnosticSettingsReadyCondition, ServiceName, result)
+	return result
+}
+
+// Delete deletes the diagnostic settings created by the cluster. Azure keeps the diagnostic settings of a deleted
+// resource and applies them again to a new resource with the same name, so they are deleted explicitly.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "diagnosticsettings.Service.Delete")
+	defer done()
+
+	specs := s.Scope.DiagnosticSettingSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	// We go through the list of DiagnosticSettingSpecs to delete each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error deleting) -> operationNotDoneError (i.e. deleting in progress) -> no error (i.e. deleted)
+	var result error
+	for _, settingSpec := range specs {
+		if _, err := s.Get(ctx, settingSpec); err != nil {
+			if azure.ResourceNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to get diagnostic setting %s", settingSpec.ResourceName())
+		}
+		if err := s.DeleteResource(ctx, settingSpec, ServiceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.DiagnosticSettingsReadyCondition, ServiceName, result)
+	return result
+}
+
+// IsManaged returns always returns true as diagnostic settings are managed on a one-by-one basis.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
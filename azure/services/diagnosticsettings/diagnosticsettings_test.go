@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnosticsettings
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/diagnosticsettings/mock_diagnosticsettings"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeDiagnosticSettingSpec = DiagnosticSettingSpec{
+		Name:                "my-cluster-diagnostics",
+		ResourceID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-nsg",
+		WorkspaceResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/my-workspace",
+	}
+
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+	notFoundError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "Not Found")
+)
+
+func TestReconcileDiagnosticSettings(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_diagnosticsettings.MockDiagnosticSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no diagnostic settings",
+			expectedError: "",
+			expect: func(s *mock_diagnosticsettings.MockDiagnosticSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticSettingSpecs().Return([]azure.ResourceSpecGetter{})
+			},
+		},
+		{
+			name:          "successfully create diagnostic setting",
+			expectedError: "",
+			expect: func(s *mock_diagnosticsettings.MockDiagnosticSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticSettingSpecs().Return([]azure.ResourceSpecGetter{&fakeDiagnosticSettingSpec})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeDiagnosticSettingSpec, ServiceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.DiagnosticSettingsReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "fail to create diagnostic setting",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_diagnosticsettings.MockDiagnosticSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticSettingSpecs().Return([]azure.ResourceSpecGetter{&fakeDiagnosticSettingSpec})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeDiagnosticSettingSpec, ServiceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.DiagnosticSettingsReadyCondition, ServiceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_diagnosticsettings.NewMockDiagnosticSettingScope(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteDiagnosticSettings(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_diagnosticsettings.MockDiagnosticSettingScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no diagnostic settings",
+			expectedError: "",
+			expect: func(s *mock_diagnosticsettings.MockDiagnosticSettingScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticSettingSpecs().Return([]azure.ResourceSpecGetter{})
+			},
+		},
+		{
+			name:          "successfully delete diagnostic setting",
+			expectedError: "",
+			expect: func(s *mock_diagnosticsettings.MockDiagnosticSettingScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticSettingSpecs().Return([]azure.ResourceSpecGetter{&fakeDiagnosticSettingSpec})
+				g.Get(gomockinternal.AContext(), &fakeDiagnosticSettingSpec).Return(armresources.GenericResource{}, nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeDiagnosticSettingSpec, ServiceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.DiagnosticSettingsReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "skip diagnostic setting that does not exist",
+			expectedError: "",
+			expect: func(s *mock_diagnosticsettings.MockDiagnosticSettingScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticSettingSpecs().Return([]azure.ResourceSpecGetter{&fakeDiagnosticSettingSpec})
+				g.Get(gomockinternal.AContext(), &fakeDiagnosticSettingSpec).Return(nil, notFoundError)
+				s.UpdateDeleteStatus(infrav1.DiagnosticSettingsReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "fail to delete diagnostic setting",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_diagnosticsettings.MockDiagnosticSettingScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiagnosticSettingSpecs().Return([]azure.ResourceSpecGetter{&fakeDiagnosticSettingSpec})
+				g.Get(gomockinternal.AContext(), &fakeDiagnosticSettingSpec).Return(armresources.GenericResource{}, nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeDiagnosticSettingSpec, ServiceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.DiagnosticSettingsReadyCondition, ServiceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_diagnosticsettings.NewMockDiagnosticSettingScope(mockCtrl)
+			getterMock := mock_async.NewMockGetter(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), getterMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Getter:     getterMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnosticsettings
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// allLogsCategoryGroup is the category group of all the resource log categories of a resource.
+const allLogsCategoryGroup = "allLogs"
+
+// logCategories are the resource log categories of each resource type diagnostic settings are created for.
+var logCategories = map[string][]infrav1.DiagnosticLogCategory{
+	"Microsoft.Network/loadBalancers": {
+		infrav1.LoadBalancerHealthEventCategory,
+	},
+	"Microsoft.Network/networkSecurityGroups": {
+		infrav1.NetworkSecurityGroupEventCategory,
+		infrav1.NetworkSecurityGroupRuleCounterCategory,
+	},
+	"Microsoft.Network/publicIPAddresses": {
+		infrav1.DDoSProtectionNotificationsCategory,
+		infrav1.DDoSMitigationFlowLogsCategory,
+		infrav1.DDoSMitigationReportsCategory,
+	},
+}
+
+// LogCategories returns the categories of the resource with the given ID among the given log categories. It returns
+// nil, meaning all the categories of the resource, when no log category is given, and false when none of the given
+// categories belongs to the resource.
+func LogCategories(resourceID string, categories []infrav1.DiagnosticLogCategory) ([]string, bool) {
+	if len(categories) == 0 {
+		return nil, true
+	}
+	parsed, err := arm.ParseResourceID(resourceID)
+	if err != nil {
+		return nil, false
+	}
+	var supported []string
+	for _, category := range categories {
+		for _, resourceCategory := range logCategories[parsed.ResourceType.String()] {
+			if category == resourceCategory {
+				supported = append(supported, string(category))
+			}
+		}
+	}
+	return supported, len(supported) > 0
+}
+
+// DiagnosticSettingSpec defines the specification for the Azure Monitor diagnostic setting of a resource.
+type DiagnosticSettingSpec struct {
+	Name                string
+	ResourceID          string
+	WorkspaceResourceID string
+	// Categories are the resource log categories collected. All the categories of the resource are collected when empty.
+	Categories []string
+}
+
+// ResourceName returns the name of the diagnostic setting.
+func (s *DiagnosticSettingSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the resource the diagnostic setting applies to.
+func (s *DiagnosticSettingSpec) ResourceGroupName() string {
+	parsed, err := arm.ParseResourceID(s.ResourceID)
+	if err != nil {
+		return ""
+	}
+	return parsed.ResourceGroupName
+}
+
+// OwnerResourceName returns the resource ID of the resource the diagnostic setting applies to, as diagnostic settings
+// are extension resources of any resource type.
+func (s *DiagnosticSettingSpec) OwnerResourceName() string {
+	return s.ResourceID
+}
+
+// Parameters returns the parameters for the diagnostic setting.
+func (s *DiagnosticSettingSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		existingSetting, ok := existing.(armresources.GenericResource)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armresources.GenericResource", existing)
+		}
+		if s.isUpToDate(existingSetting) {
+			// diagnostic setting already exists and sends the wanted logs to the workspace
+			return nil, nil
+		}
+	}
+
+	var logs []interface{}
+	if len(s.Categories) == 0 {
+		logs = append(logs, map[string]interface{}{
+			"categoryGroup": allLogsCategoryGroup,
+			"enabled":       true,
+		})
+	}
+	for _, category := range s.Categories {
+		logs = append(logs, map[string]interface{}{
+			"category": category,
+			"enabled":  true,
+		})
+	}
+
+	return armresources.GenericResource{
+		Properties: map[string]interface{}{
+			"workspaceId": s.WorkspaceResourceID,
+			"logs":        logs,
+		},
+	}, nil
+}
+
+// isUpToDate returns true if the existing diagnostic setting sends exactly the wanted logs to the wanted workspace.
+func (s *DiagnosticSettingSpec) isUpToDate(existing armresources.GenericResource) bool {
+	properties, ok := existing.Properties.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	workspaceID, _ := properties["workspaceId"].(string)
+	if !strings.EqualFold(workspaceID, s.WorkspaceResourceID) {
+		return false
+	}
+
+	var enabled []string
+	logs, _ := properties["logs"].([]interface{})
+	for _, l := range logs {
+		log, _ := l.(map[string]interface{})
+		if isEnabled, _ := log["enabled"].(bool); !isEnabled {
+			continue
+		}
+		if category, _ := log["category"].(string); category != "" {
+			enabled = append(enabled, category)
+		} else if categoryGroup, _ := log["categoryGroup"].(string); categoryGroup != "" {
+			enabled = append(enabled, categoryGroup)
+		}
+	}
+	wanted := append([]string{}, s.Categories...)
+	if len(wanted) == 0 {
+		wanted = []string{allLogsCategoryGroup}
+	}
+	if len(enabled) != len(wanted) {
+		return false
+	}
+	sort.Strings(enabled)
+	sort.Strings(wanted)
+	for i := range wanted {
+		if !strings.EqualFold(enabled[i], wanted[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnosticsettings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestLogCategories(t *testing.T) {
+	testcases := []struct {
+		name         string
+		resourceID   string
+		categories   []infrav1.DiagnosticLogCategory
+		expected     []string
+		expectedBool bool
+	}{
+		{
+			name:         "all categories",
+			resourceID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb",
+			categories:   nil,
+			expected:     nil,
+			expectedBool: true,
+		},
+		{
+			name:       "categories of the resource type",
+			resourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-nsg",
+			categories: []infrav1.DiagnosticLogCategory{
+				infrav1.LoadBalancerHealthEventCategory,
+				infrav1.NetworkSecurityGroupRuleCounterCategory,
+				infrav1.DDoSMitigationReportsCategory,
+			},
+			expected:     []string{"NetworkSecurityGroupRuleCounter"},
+			expectedBool: true,
+		},
+		{
+			name:         "no category of the resource type",
+			resourceID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-ip",
+			categories:   []infrav1.DiagnosticLogCategory{infrav1.NetworkSecurityGroupEventCategory},
+			expected:     nil,
+			expectedBool: false,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			categories, ok := LogCategories(tc.resourceID, tc.categories)
+			g.Expect(ok).To(Equal(tc.expectedBool))
+			g.Expect(categories).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestParameters(t *testing.T) {
+	workspaceID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/my-workspace"
+	allLogsSpec := DiagnosticSettingSpec{
+		Name:                "my-cluster-diagnostics",
+		ResourceID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-nsg",
+		WorkspaceResourceID: workspaceID,
+	}
+	categoriesSpec := allLogsSpec
+	categoriesSpec.Categories = []string{"NetworkSecurityGroupEvent", "NetworkSecurityGroupRuleCounter"}
+
+	testcases := []struct {
+		name          string
+		spec          DiagnosticSettingSpec
+		existing      interface{}
+		expected      interface{}
+		expectedError string
+	}{
+		{
+			name:     "diagnostic setting with all logs does not exist",
+			spec:     allLogsSpec,
+			existing: nil,
+			expected: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"workspaceId": workspaceID,
+					"logs": []interface{}{
+						map[string]interface{}{"categoryGroup": "allLogs", "enabled": true},
+					},
+				},
+			},
+		},
+		{
+			name:     "diagnostic setting with categories does not exist",
+			spec:     categoriesSpec,
+			existing: nil,
+			expected: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"workspaceId": workspaceID,
+					"logs": []interface{}{
+						map[string]interface{}{"category": "NetworkSecurityGroupEvent", "enabled": true},
+						map[string]interface{}{"category": "NetworkSecurityGroupRuleCounter", "enabled": true},
+					},
+				},
+			},
+		},
+		{
+			name: "diagnostic setting is up to date",
+			spec: categoriesSpec,
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"workspaceId": "/subscriptions/123/resourcegroups/my-rg/providers/microsoft.operationalinsights/workspaces/my-workspace",
+					"logs": []interface{}{
+						map[string]interface{}{"category": "NetworkSecurityGroupRuleCounter", "enabled": true},
+						map[string]interface{}{"category": "NetworkSecurityGroupEvent", "enabled": true},
+					},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "diagnostic setting sends logs to another workspace",
+			spec: allLogsSpec,
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"workspaceId": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/old-workspace",
+					"logs": []interface{}{
+						map[string]interface{}{"categoryGroup": "allLogs", "enabled": true},
+					},
+				},
+			},
+			expected: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"workspaceId": workspaceID,
+					"logs": []interface{}{
+						map[string]interface{}{"categoryGroup": "allLogs", "enabled": true},
+					},
+				},
+			},
+		},
+		{
+			name: "diagnostic setting collects other categories",
+			spec: categoriesSpec,
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"workspaceId": workspaceID,
+					"logs": []interface{}{
+						map[string]interface{}{"category": "NetworkSecurityGroupEvent", "enabled": true},
+						map[string]interface{}{"category": "NetworkSecurityGroupRuleCounter", "enabled": false},
+					},
+				},
+			},
+			expected: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"workspaceId": workspaceID,
+					"logs": []interface{}{
+						map[string]interface{}{"category": "NetworkSecurityGroupEvent", "enabled": true},
+						map[string]interface{}{"category": "NetworkSecurityGroupRuleCounter", "enabled": true},
+					},
+				},
+			},
+		},
+		{
+			name:          "existing is not a generic resource",
+			spec:          allLogsSpec,
+			existing:      "foo",
+			expectedError: "string is not an armresources.GenericResource",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expected == nil {
+				g.Expect(result).To(BeNil())
+			} else {
+				g.Expect(result).To(Equal(tc.expected))
+			}
+		})
+	}
+}
+
+func TestResourceGroupName(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(fakeDiagnosticSettingSpec.ResourceGroupName()).To(Equal("my-rg"))
+	g.Expect(fakeDiagnosticSettingSpec.OwnerResourceName()).To(Equal(fakeDiagnosticSettingSpec.ResourceID))
+}
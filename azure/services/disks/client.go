@@ -46,6 +46,51 @@ func newClient(auth azure.Authorizer) (*azureClient, error) {
 	return &azureClient{factory.NewDisksClient()}, nil
 }
 
+// Get gets the specified disk.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.azureClient.Get")
+	defer done()
+
+	resp, err := ac.disks.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Disk, nil
+}
+
+// CreateOrUpdateAsync creates or updates a disk asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a Poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armcompute.DisksClientCreateOrUpdateResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.azureClient.CreateOrUpdateAsync")
+	defer done()
+
+	disk, ok := parameters.(armcompute.Disk)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armcompute.Disk", parameters)
+	}
+
+	opts := &armcompute.DisksClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken}
+	poller, err = ac.disks.BeginCreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), disk, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	resp, err := poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, poller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return resp.Disk, nil, err
+}
+
 // DeleteAsync deletes a disk asynchronously. DeleteAsync sends a DELETE
 // request to Azure and if accepted without error, the func will return a Poller which can be used to track the ongoing
 // progress of the operation.
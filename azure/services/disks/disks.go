@@ -51,7 +51,7 @@ func New(scope DiskScope) (*Service, error) {
 	return &Service{
 		Scope: scope,
 		Reconciler: async.New[armcompute.DisksClientCreateOrUpdateResponse,
-			armcompute.DisksClientDeleteResponse](scope, nil, client),
+			armcompute.DisksClientDeleteResponse](scope, client, client),
 	}, nil
 }
 
@@ -60,13 +60,30 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
-// Reconcile on disk is currently no-op. OS disks should only be deleted and will create with the VM automatically.
+// Reconcile creates the OS disk from its snapshot or disk restore point, if any, and updates the performance of the
+// existing Premium SSD v2 and Ultra data disks. Other disks are created with the VM automatically, as the IOPS and
+// throughput of the data disks of a VM cannot be set when the VM is created.
 func (s *Service) Reconcile(ctx context.Context) error {
-	_, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.Reconcile")
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.Reconcile")
 	defer done()
 
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
 	// DisksReadyCondition is set in the VM service.
-	return nil
+	var result error
+	for _, spec := range s.Scope.DiskSpecs() {
+		diskSpec, ok := spec.(*DiskSpec)
+		if !ok || (diskSpec.SourceResourceID == "" && diskSpec.DiskIOPSReadWrite == nil && diskSpec.DiskMBpsReadWrite == nil) {
+			continue
+		}
+		if _, err := s.CreateOrUpdateResource(ctx, diskSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+	return result
 }
 
 // Delete deletes the disk associated with a VM.
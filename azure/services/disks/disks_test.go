@@ -24,6 +24,7 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
@@ -47,9 +48,76 @@ var (
 		&diskSpec2,
 	}
 
+	premiumV2DiskSpec = DiskSpec{
+		Name:              "my-disk-3",
+		ResourceGroup:     "my-group",
+		DiskIOPSReadWrite: ptr.To[int64](5000),
+		DiskMBpsReadWrite: ptr.To[int64](200),
+	}
+
 	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
 )
 
+func TestReconcileDisk(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no disk has performance settings",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiskSpecs().Return(fakeDiskSpecs)
+			},
+		},
+		{
+			name:          "update the performance of a disk",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiskSpecs().Return(append(fakeDiskSpecs, &premiumV2DiskSpec))
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &premiumV2DiskSpec, serviceName).Return(nil, nil)
+			},
+		},
+		{
+			name:          "error while trying to update the disk",
+			expectedError: "#: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiskSpecs().Return([]azure.ResourceSpecGetter{&premiumV2DiskSpec})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &premiumV2DiskSpec, serviceName).Return(nil, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_disks.NewMockDiskScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestDeleteDisk(t *testing.T) {
 	testcases := []struct {
 		name          string
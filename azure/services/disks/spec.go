@@ -16,12 +16,35 @@ limitations under the License.
 
 package disks
 
-import "context"
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
 
 // DiskSpec defines the specification for a disk.
 type DiskSpec struct {
 	Name          string
 	ResourceGroup string
+	// DiskIOPSReadWrite and DiskMBpsReadWrite are the performance of a Premium SSD v2 or Ultra data disk. Disks are
+	// created with their VM, so they are only set on existing disks.
+	DiskIOPSReadWrite *int64
+	DiskMBpsReadWrite *int64
+	// SourceResourceID is the snapshot or disk restore point an OS disk is created from, before its VM. The fields
+	// below are only used to create such a disk.
+	SourceResourceID    string
+	CreateOption        armcompute.DiskCreateOption
+	Location            string
+	Zone                string
+	OSType              string
+	DiskSizeGB          *int32
+	StorageAccountType  string
+	DiskEncryptionSetID string
+	Tags                infrav1.Tags
 }
 
 // ResourceName returns the name of the disk.
@@ -39,7 +62,67 @@ func (s *DiskSpec) OwnerResourceName() string {
 	return ""
 }
 
-// Parameters is a no-op for disks.
+// Parameters returns the parameters to create an OS disk from its source, or to update the performance of an existing
+// disk. It returns nil if the disk is created with its VM or already has the desired performance.
 func (s *DiskSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
-	return nil, nil
+	if existing == nil {
+		if s.SourceResourceID == "" {
+			return nil, nil
+		}
+		return s.diskFromSource(), nil
+	}
+	disk, ok := existing.(armcompute.Disk)
+	if !ok {
+		return nil, errors.Errorf("%T is not an armcompute.Disk", existing)
+	}
+	if disk.Properties == nil || !s.hasPerformanceChanges(*disk.Properties) {
+		return nil, nil
+	}
+
+	properties := *disk.Properties
+	if s.DiskIOPSReadWrite != nil {
+		properties.DiskIOPSReadWrite = s.DiskIOPSReadWrite
+	}
+	if s.DiskMBpsReadWrite != nil {
+		properties.DiskMBpsReadWrite = s.DiskMBpsReadWrite
+	}
+	disk.Properties = &properties
+	return disk, nil
+}
+
+// hasPerformanceChanges returns true if the disk does not have the desired IOPS or throughput.
+func (s *DiskSpec) hasPerformanceChanges(properties armcompute.DiskProperties) bool {
+	return (s.DiskIOPSReadWrite != nil && !ptr.Equal(s.DiskIOPSReadWrite, properties.DiskIOPSReadWrite)) ||
+		(s.DiskMBpsReadWrite != nil && !ptr.Equal(s.DiskMBpsReadWrite, properties.DiskMBpsReadWrite))
+}
+
+// diskFromSource returns the parameters to create a disk from a snapshot or a disk restore point.
+func (s *DiskSpec) diskFromSource() armcompute.Disk {
+	disk := armcompute.Disk{
+		Location: ptr.To(s.Location),
+		Tags:     converters.TagsToMap(s.Tags),
+		Properties: &armcompute.DiskProperties{
+			CreationData: &armcompute.CreationData{
+				CreateOption:     ptr.To(s.CreateOption),
+				SourceResourceID: ptr.To(s.SourceResourceID),
+			},
+			DiskSizeGB: s.DiskSizeGB,
+		},
+	}
+	if s.Zone != "" {
+		disk.Zones = []*string{ptr.To(s.Zone)}
+	}
+	if s.OSType != "" {
+		disk.Properties.OSType = ptr.To(armcompute.OperatingSystemTypes(s.OSType))
+	}
+	if s.StorageAccountType != "" {
+		disk.SKU = &armcompute.DiskSKU{Name: ptr.To(armcompute.DiskStorageAccountTypes(s.StorageAccountType))}
+	}
+	if s.DiskEncryptionSetID != "" {
+		disk.Properties.Encryption = &armcompute.Encryption{
+			Type:                ptr.To(armcompute.EncryptionTypeEncryptionAtRestWithCustomerKey),
+			DiskEncryptionSetID: ptr.To(s.DiskEncryptionSetID),
+		}
+	}
+	return disk
 }
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package disks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestParameters(t *testing.T) {
+	testcases := []struct {
+		name     string
+		spec     DiskSpec
+		existing interface{}
+		expect   func(g *WithT, result interface{})
+	}{
+		{
+			name:     "disk does not exist yet",
+			spec:     premiumV2DiskSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "disk already has the desired performance",
+			spec: premiumV2DiskSpec,
+			existing: armcompute.Disk{
+				Name: ptr.To("my-disk-3"),
+				Properties: &armcompute.DiskProperties{
+					DiskIOPSReadWrite: ptr.To[int64](5000),
+					DiskMBpsReadWrite: ptr.To[int64](200),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "disk performance is not managed",
+			spec: diskSpec1,
+			existing: armcompute.Disk{
+				Name: ptr.To("my-disk-1"),
+				Properties: &armcompute.DiskProperties{
+					DiskIOPSReadWrite: ptr.To[int64](3000),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "disk performance is updated",
+			spec: premiumV2DiskSpec,
+			existing: armcompute.Disk{
+				Name: ptr.To("my-disk-3"),
+				Properties: &armcompute.DiskProperties{
+					DiskSizeGB:        ptr.To[int32](128),
+					DiskIOPSReadWrite: ptr.To[int64](3000),
+					DiskMBpsReadWrite: ptr.To[int64](125),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.Disk{}))
+				disk := result.(armcompute.Disk)
+				g.Expect(disk.Properties.DiskSizeGB).To(Equal(ptr.To[int32](128)))
+				g.Expect(disk.Properties.DiskIOPSReadWrite).To(Equal(ptr.To[int64](5000)))
+				g.Expect(disk.Properties.DiskMBpsReadWrite).To(Equal(ptr.To[int64](200)))
+			},
+		},
+		{
+			name: "os disk is created from a snapshot",
+			spec: DiskSpec{
+				Name:               "my-vm_OSDisk",
+				ResourceGroup:      "my-group",
+				SourceResourceID:   "/subscriptions/123/resourceGroups/my-group/providers/Microsoft.Compute/snapshots/my-snapshot",
+				CreateOption:       armcompute.DiskCreateOptionCopy,
+				Location:           "westus",
+				Zone:               "1",
+				OSType:             "Linux",
+				DiskSizeGB:         ptr.To[int32](128),
+				StorageAccountType: "Premium_LRS",
+				Tags:               map[string]string{"foo": "bar"},
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armcompute.Disk{
+					Location: ptr.To("westus"),
+					Tags:     map[string]*string{"foo": ptr.To("bar")},
+					Zones:    []*string{ptr.To("1")},
+					SKU:      &armcompute.DiskSKU{Name: ptr.To(armcompute.DiskStorageAccountTypesPremiumLRS)},
+					Properties: &armcompute.DiskProperties{
+						CreationData: &armcompute.CreationData{
+							CreateOption:     ptr.To(armcompute.DiskCreateOptionCopy),
+							SourceResourceID: ptr.To("/subscriptions/123/resourceGroups/my-group/providers/Microsoft.Compute/snapshots/my-snapshot"),
+						},
+						DiskSizeGB: ptr.To[int32](128),
+						OSType:     ptr.To(armcompute.OperatingSystemTypesLinux),
+					},
+				}))
+			},
+		},
+		{
+			name: "os disk created from a snapshot already exists",
+			spec: DiskSpec{
+				Name:             "my-vm_OSDisk",
+				ResourceGroup:    "my-group",
+				SourceResourceID: "/subscriptions/123/resourceGroups/my-group/providers/Microsoft.Compute/snapshots/my-snapshot",
+				CreateOption:     armcompute.DiskCreateOptionCopy,
+			},
+			existing: armcompute.Disk{
+				Name:       ptr.To("my-vm_OSDisk"),
+				Properties: &armcompute.DiskProperties{},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, result)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecords
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// dnsAPIVersion is the API version of the Microsoft.Network/dnsZones resource provider.
+	dnsAPIVersion = "2018-05-01"
+
+	// recordTypeA is the type of the alias record sets pointing to public IPs.
+	recordTypeA = "A"
+)
+
+// azureClient contains the Azure go-sdk Client.
+// There is no dedicated Azure DNS client in the SDK modules this provider depends on,
+// so record sets are managed through the generic resources client.
+type azureClient struct {
+	resources      *armresources.Client
+	subscriptionID string
+}
+
+// newClient creates a new DNS record sets client from an authorizer.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create dnsrecords client options")
+	}
+	factory, err := armresources.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armresources client factory")
+	}
+	return &azureClient{
+		resources:      factory.NewClient(),
+		subscriptionID: auth.SubscriptionID(),
+	}, nil
+}
+
+// Get gets the specified record set.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "dnsrecords.azureClient.Get")
+	defer done()
+
+	resp, err := ac.resources.GetByID(ctx, ac.recordSetID(spec), dnsAPIVersion, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GenericResource, nil
+}
+
+// CreateOrUpdateAsync creates or updates a record set asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a Poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armresources.ClientCreateOrUpdateByIDResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "dnsrecords.azureClient.CreateOrUpdateAsync")
+	defer done()
+
+	recordSet, ok := parameters.(armresources.GenericResource)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armresources.GenericResource", parameters)
+	}
+
+	opts := &armresources.ClientBeginCreateOrUpdateByIDOptions{ResumeToken: resumeToken}
+	poller, err = ac.resources.BeginCreateOrUpdateByID(ctx, ac.recordSetID(spec), dnsAPIVersion, recordSet, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	resp, err := poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, poller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return resp.GenericResource, nil, err
+}
+
+// DeleteAsync deletes a record set asynchronously. DeleteAsync sends a DELETE
+// request to Azure and if accepted without error, the func will return a Poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armresources.ClientDeleteByIDResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "dnsrecords.azureClient.DeleteAsync")
+	defer done()
+
+	opts := &armresources.ClientBeginDeleteByIDOptions{ResumeToken: resumeToken}
+	poller, err = ac.resources.BeginDeleteByID(ctx, ac.recordSetID(spec), dnsAPIVersion, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	_, err = poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return poller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, err
+}
+
+// recordSetID returns the resource ID of the A record set described by spec.
+func (ac *azureClient) recordSetID(spec azure.ResourceSpecGetter) string {
+	return azure.DNSRecordSetID(ac.subscriptionID, spec.ResourceGroupName(), spec.OwnerResourceName(), recordTypeA, spec.ResourceName())
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecords
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "dnsrecords"
+
+// DNSRecordScope defines the scope interface for a public DNS records service.
+type DNSRecordScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	azure.ClusterDescriber
+	DNSRecordSpecs() []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope DNSRecordScope
+	async.Reconciler
+	async.Getter
+}
+
+// New creates a new service.
+func New(scope DNSRecordScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		Getter:     client,
+		Reconciler: async.New[armresources.ClientCreateOrUpdateByIDResponse, armresources.ClientDeleteByIDResponse](scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile idempotently creates or updates the DNS record sets.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "dnsrecords.Service.Reconcile")
+	defer done()
+
+	specs := s.Scope.DNSRecordSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	// We go through the list of DNSRecordSpecs to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var result error
+	for _, recordSpec := range specs {
+		if _, err := s.CreateOrUpdateResource(ctx, recordSpec, ServiceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.DNSRecordsReadyCondition, ServiceName, result)
+	return result
+}
+
+// Delete deletes the DNS record sets created by the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "dnsrecords.Service.Delete")
+	defer done()
+
+	specs := s.Scope.DNSRecordSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	// We go through the list of DNSRecordSpecs to delete each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error deleting) -> operationNotDoneError (i.e. deleting in progress) -> no error (i.e. deleted)
+	var result error
+	for _, recordSpec := range specs {
+		existing, err := s.Get(ctx, recordSpec)
+		if azure.ResourceNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get DNS record set %s", recordSpec.ResourceName())
+		}
+		recordSet, ok := existing.(armresources.GenericResource)
+		if !ok || !isOwnedBy(recordSet, s.Scope.ClusterName()) {
+			log.V(2).Info("Skipping deletion of DNS record set not created by the cluster", "record set", recordSpec.ResourceName())
+			continue
+		}
+		if err := s.DeleteResource(ctx, recordSpec, ServiceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.DNSRecordsReadyCondition, ServiceName, result)
+	return result
+}
+
+// IsManaged returns always returns true as DNS record sets are managed on a one-by-one basis.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecords
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsrecords/mock_dnsrecords"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeDNSRecordSpec = DNSRecordSpec{
+		Name:              "my-cluster",
+		ZoneName:          "example.com",
+		ZoneResourceGroup: "my-dns-rg",
+		TTL:               300,
+		TargetResourceID:  "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-apiserver",
+		ClusterName:       "my-cluster",
+	}
+
+	ownedRecordSet = armresources.GenericResource{
+		Properties: map[string]interface{}{
+			"metadata": map[string]interface{}{clusterMetadataKey: "my-cluster"},
+		},
+	}
+
+	unownedRecordSet = armresources.GenericResource{
+		Properties: map[string]interface{}{
+			"metadata": map[string]interface{}{"owner": "someone-else"},
+		},
+	}
+
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+	notFoundError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "Not Found")
+)
+
+func TestReconcileDNSRecords(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no DNS records",
+			expectedError: "",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.ResourceSpecGetter{})
+			},
+		},
+		{
+			name:          "successfully create DNS record",
+			expectedError: "",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.ResourceSpecGetter{&fakeDNSRecordSpec})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeDNSRecordSpec, ServiceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.DNSRecordsReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "fail to create DNS record",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.ResourceSpecGetter{&fakeDNSRecordSpec})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeDNSRecordSpec, ServiceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.DNSRecordsReadyCondition, ServiceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_dnsrecords.NewMockDNSRecordScope(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteDNSRecords(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no DNS records",
+			expectedError: "",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.ResourceSpecGetter{})
+			},
+		},
+		{
+			name:          "successfully delete DNS record owned by the cluster",
+			expectedError: "",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.ResourceSpecGetter{&fakeDNSRecordSpec})
+				g.Get(gomockinternal.AContext(), &fakeDNSRecordSpec).Return(ownedRecordSet, nil)
+				s.ClusterName().Return("my-cluster")
+				r.DeleteResource(gomockinternal.AContext(), &fakeDNSRecordSpec, ServiceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.DNSRecordsReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "skip DNS record not owned by the cluster",
+			expectedError: "",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.ResourceSpecGetter{&fakeDNSRecordSpec})
+				g.Get(gomockinternal.AContext(), &fakeDNSRecordSpec).Return(unownedRecordSet, nil)
+				s.ClusterName().Return("my-cluster")
+				s.UpdateDeleteStatus(infrav1.DNSRecordsReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "skip DNS record that does not exist",
+			expectedError: "",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.ResourceSpecGetter{&fakeDNSRecordSpec})
+				g.Get(gomockinternal.AContext(), &fakeDNSRecordSpec).Return(nil, notFoundError)
+				s.UpdateDeleteStatus(infrav1.DNSRecordsReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "fail to delete DNS record",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.ResourceSpecGetter{&fakeDNSRecordSpec})
+				g.Get(gomockinternal.AContext(), &fakeDNSRecordSpec).Return(ownedRecordSet, nil)
+				s.ClusterName().Return("my-cluster")
+				r.DeleteResource(gomockinternal.AContext(), &fakeDNSRecordSpec, ServiceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.DNSRecordsReadyCondition, ServiceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_dnsrecords.NewMockDNSRecordScope(mockCtrl)
+			getterMock := mock_async.NewMockGetter(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), getterMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Getter:     getterMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecords
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+)
+
+// clusterMetadataKey is the record set metadata key holding the name of the cluster owning the record set.
+// DNS metadata keys can't contain the characters used in the cluster ownership tag, hence a dedicated key.
+const clusterMetadataKey = "capz_cluster_name"
+
+// DNSRecordSpec defines the specification for an alias A record set in a public Azure DNS zone.
+type DNSRecordSpec struct {
+	Name              string
+	ZoneName          string
+	ZoneResourceGroup string
+	TTL               int64
+	TargetResourceID  string
+	ClusterName       string
+}
+
+// ResourceName returns the relative name of the record set.
+func (s *DNSRecordSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the DNS zone.
+func (s *DNSRecordSpec) ResourceGroupName() string {
+	return s.ZoneResourceGroup
+}
+
+// OwnerResourceName returns the name of the DNS zone.
+func (s *DNSRecordSpec) OwnerResourceName() string {
+	return s.ZoneName
+}
+
+// Parameters returns the parameters for the record set.
+func (s *DNSRecordSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		existingRecordSet, ok := existing.(armresources.GenericResource)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armresources.GenericResource", existing)
+		}
+		if !isOwnedBy(existingRecordSet, s.ClusterName) {
+			return nil, errors.Errorf("record set %s already exists in DNS zone %s and is not owned by cluster %s", s.Name, s.ZoneName, s.ClusterName)
+		}
+		if s.isUpToDate(existingRecordSet) {
+			// record set already exists and points to the target resource
+			return nil, nil
+		}
+	}
+
+	return armresources.GenericResource{
+		Properties: map[string]interface{}{
+			"TTL": s.TTL,
+			"targetResource": map[string]interface{}{
+				"id": s.TargetResourceID,
+			},
+			"metadata": map[string]interface{}{
+				clusterMetadataKey: s.ClusterName,
+			},
+		},
+	}, nil
+}
+
+// isUpToDate returns true if the existing record set has the wanted TTL and target resource.
+func (s *DNSRecordSpec) isUpToDate(existing armresources.GenericResource) bool {
+	properties, ok := existing.Properties.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	// JSON numbers are decoded as float64.
+	ttl, _ := properties["TTL"].(float64)
+	if int64(ttl) != s.TTL {
+		return false
+	}
+	target, _ := properties["targetResource"].(map[string]interface{})
+	targetID, _ := target["id"].(string)
+	return strings.EqualFold(targetID, s.TargetResourceID)
+}
+
+// isOwnedBy returns true if the record set was created by the given cluster.
+func isOwnedBy(recordSet armresources.GenericResource, clusterName string) bool {
+	owner, _ := metadata(recordSet)[clusterMetadataKey].(string)
+	return owner == clusterName
+}
+
+// metadata returns the metadata of a record set.
+func metadata(recordSet armresources.GenericResource) map[string]interface{} {
+	properties, ok := recordSet.Properties.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	m, _ := properties["metadata"].(map[string]interface{})
+	return m
+}
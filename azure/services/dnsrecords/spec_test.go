@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecords
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	. "github.com/onsi/gomega"
+)
+
+func TestParameters(t *testing.T) {
+	wantRecordSet := armresources.GenericResource{
+		Properties: map[string]interface{}{
+			"TTL": int64(300),
+			"targetResource": map[string]interface{}{
+				"id": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-apiserver",
+			},
+			"metadata": map[string]interface{}{
+				clusterMetadataKey: "my-cluster",
+			},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		existing      interface{}
+		expected      interface{}
+		expectedError string
+	}{
+		{
+			name:     "record set does not exist",
+			existing: nil,
+			expected: wantRecordSet,
+		},
+		{
+			name: "record set is up to date",
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"TTL": float64(300),
+					"targetResource": map[string]interface{}{
+						"id": "/subscriptions/123/resourcegroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-apiserver",
+					},
+					"metadata": map[string]interface{}{
+						clusterMetadataKey: "my-cluster",
+					},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "record set points to another resource",
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"TTL": float64(300),
+					"targetResource": map[string]interface{}{
+						"id": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/old-ip",
+					},
+					"metadata": map[string]interface{}{
+						clusterMetadataKey: "my-cluster",
+					},
+				},
+			},
+			expected: wantRecordSet,
+		},
+		{
+			name: "record set is not owned by the cluster",
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"TTL": float64(3600),
+					"ARecords": []interface{}{
+						map[string]interface{}{"ipv4Address": "1.2.3.4"},
+					},
+				},
+			},
+			expectedError: "record set my-cluster already exists in DNS zone example.com and is not owned by cluster my-cluster",
+		},
+		{
+			name:          "existing is not a generic resource",
+			existing:      "foo",
+			expectedError: "string is not an armresources.GenericResource",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := fakeDNSRecordSpec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expected == nil {
+				g.Expect(result).To(BeNil())
+			} else {
+				g.Expect(result).To(Equal(tc.expected))
+			}
+		})
+	}
+}
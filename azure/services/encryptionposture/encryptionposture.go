@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionposture
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "encryptionposture"
+
+// EncryptionPostureScope defines the scope interface for an encryption posture service.
+type EncryptionPostureScope interface {
+	MachineEncryptionSpecs(ctx context.Context) ([]azure.MachineEncryptionSpec, error)
+	EncryptionPosture() *infrav1.EncryptionPosture
+	SetEncryptionPosture(*infrav1.EncryptionPosture)
+}
+
+// Service reports the disk encryption posture of the machines of a cluster.
+type Service struct {
+	Scope EncryptionPostureScope
+}
+
+// New creates a new encryption posture service.
+func New(scope EncryptionPostureScope) *Service {
+	return &Service{
+		Scope: scope,
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile aggregates the disk encryption settings of the machines of the cluster. The posture is informational, so
+// failing to list the machines is logged and keeps the previous posture rather than failing the reconciliation of the
+// cluster.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "encryptionposture.Service.Reconcile")
+	defer done()
+
+	specs, err := s.Scope.MachineEncryptionSpecs(ctx)
+	if err != nil {
+		log.Error(errors.Wrap(err, "failed to get the encryption settings of the machines"), "failed to report the encryption posture of the cluster")
+		return nil
+	}
+	posture := aggregate(specs)
+
+	// Only update the posture when it changes, so that an unchanged posture doesn't update the status on every
+	// reconciliation.
+	if previous := s.Scope.EncryptionPosture(); previous != nil && previous.Machines == posture.Machines &&
+		previous.CustomerManagedKeys == posture.CustomerManagedKeys && previous.EncryptionAtHost == posture.EncryptionAtHost &&
+		previous.AzureDiskEncryption == posture.AzureDiskEncryption &&
+		equalStrings(previous.PlatformManagedKeysOnly, posture.PlatformManagedKeysOnly) {
+		return nil
+	}
+	now := metav1.Now()
+	posture.LastUpdated = &now
+	s.Scope.SetEncryptionPosture(posture)
+	return nil
+}
+
+// Delete is a no-op as reporting the encryption posture of a cluster doesn't create any resource.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// aggregate counts the VMs with each kind of disk encryption, and lists the machines without any.
+func aggregate(specs []azure.MachineEncryptionSpec) *infrav1.EncryptionPosture {
+	posture := &infrav1.EncryptionPosture{}
+	for _, spec := range specs {
+		if spec.Count <= 0 {
+			continue
+		}
+		posture.Machines += spec.Count
+		if spec.CustomerManagedKeys {
+			posture.CustomerManagedKeys += spec.Count
+		}
+		if spec.EncryptionAtHost {
+			posture.EncryptionAtHost += spec.Count
+		}
+		if spec.AzureDiskEncryption {
+			posture.AzureDiskEncryption += spec.Count
+		}
+		if !spec.CustomerManagedKeys && !spec.EncryptionAtHost && !spec.AzureDiskEncryption {
+			posture.PlatformManagedKeysOnly = append(posture.PlatformManagedKeysOnly, spec.Name)
+		}
+	}
+	sort.Strings(posture.PlatformManagedKeysOnly)
+	return posture
+}
+
+// equalStrings returns true if a and b hold the same strings in the same order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionposture
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/encryptionposture/mock_encryptionposture"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeSpecs = []azure.MachineEncryptionSpec{
+		{Name: "AzureMachine/my-cluster-control-plane-abcde", CustomerManagedKeys: true, EncryptionAtHost: true, Count: 1},
+		{Name: "AzureMachinePool/my-cluster-mp-0", AzureDiskEncryption: true, Count: 3},
+		{Name: "AzureMachinePool/my-cluster-mp-1", Count: 2},
+		{Name: "AzureMachine/my-cluster-md-0-abcde", Count: 1},
+		{Name: "AzureMachinePool/my-cluster-mp-2", Count: 0},
+	}
+	fakePosture = infrav1.EncryptionPosture{
+		Machines:                7,
+		CustomerManagedKeys:     1,
+		EncryptionAtHost:        1,
+		AzureDiskEncryption:     3,
+		PlatformManagedKeysOnly: []string{"AzureMachine/my-cluster-md-0-abcde", "AzureMachinePool/my-cluster-mp-1"},
+	}
+)
+
+func TestReconcileEncryptionPosture(t *testing.T) {
+	testcases := []struct {
+		name   string
+		expect func(g *WithT, s *mock_encryptionposture.MockEncryptionPostureScopeMockRecorder)
+	}{
+		{
+			name: "report the encryption posture of the cluster",
+			expect: func(g *WithT, s *mock_encryptionposture.MockEncryptionPostureScopeMockRecorder) {
+				s.MachineEncryptionSpecs(gomockinternal.AContext()).Return(fakeSpecs, nil)
+				s.EncryptionPosture().Return(nil)
+				s.SetEncryptionPosture(gomock.Any()).Do(func(posture *infrav1.EncryptionPosture) {
+					g.Expect(posture.LastUpdated).NotTo(BeNil())
+					posture.LastUpdated = nil
+					g.Expect(*posture).To(Equal(fakePosture))
+				})
+			},
+		},
+		{
+			name: "unchanged posture is not updated",
+			expect: func(g *WithT, s *mock_encryptionposture.MockEncryptionPostureScopeMockRecorder) {
+				s.MachineEncryptionSpecs(gomockinternal.AContext()).Return(fakeSpecs, nil)
+				previous := fakePosture
+				previous.LastUpdated = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+				s.EncryptionPosture().Return(&previous)
+			},
+		},
+		{
+			name: "cluster without machines",
+			expect: func(g *WithT, s *mock_encryptionposture.MockEncryptionPostureScopeMockRecorder) {
+				s.MachineEncryptionSpecs(gomockinternal.AContext()).Return(nil, nil)
+				s.EncryptionPosture().Return(&fakePosture)
+				s.SetEncryptionPosture(gomock.Any()).Do(func(posture *infrav1.EncryptionPosture) {
+					posture.LastUpdated = nil
+					g.Expect(*posture).To(Equal(infrav1.EncryptionPosture{}))
+				})
+			},
+		},
+		{
+			name: "failing to list the machines keeps the previous posture",
+			expect: func(g *WithT, s *mock_encryptionposture.MockEncryptionPostureScopeMockRecorder) {
+				s.MachineEncryptionSpecs(gomockinternal.AContext()).Return(nil, errors.New("not found"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_encryptionposture.NewMockEncryptionPostureScope(mockCtrl)
+			tc.expect(g, scopeMock.EXPECT())
+
+			s := New(scopeMock)
+			g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+		})
+	}
+}
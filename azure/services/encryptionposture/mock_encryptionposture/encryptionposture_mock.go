@@ -0,0 +1,98 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../encryptionposture.go
+//
+// Generated by this command:
+//
+//	mockgen -destination encryptionposture_mock.go -package mock_encryptionposture -source ../encryptionposture.go EncryptionPostureScope
+//
+// Package mock_encryptionposture is a generated GoMock package.
+package mock_encryptionposture
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockEncryptionPostureScope is a mock of EncryptionPostureScope interface.
+type MockEncryptionPostureScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockEncryptionPostureScopeMockRecorder
+}
+
+// MockEncryptionPostureScopeMockRecorder is the mock recorder for MockEncryptionPostureScope.
+type MockEncryptionPostureScopeMockRecorder struct {
+	mock *MockEncryptionPostureScope
+}
+
+// NewMockEncryptionPostureScope creates a new mock instance.
+func NewMockEncryptionPostureScope(ctrl *gomock.Controller) *MockEncryptionPostureScope {
+	mock := &MockEncryptionPostureScope{ctrl: ctrl}
+	mock.recorder = &MockEncryptionPostureScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEncryptionPostureScope) EXPECT() *MockEncryptionPostureScopeMockRecorder {
+	return m.recorder
+}
+
+// EncryptionPosture mocks base method.
+func (m *MockEncryptionPostureScope) EncryptionPosture() *v1beta1.EncryptionPosture {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptionPosture")
+	ret0, _ := ret[0].(*v1beta1.EncryptionPosture)
+	return ret0
+}
+
+// EncryptionPosture indicates an expected call of EncryptionPosture.
+func (mr *MockEncryptionPostureScopeMockRecorder) EncryptionPosture() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptionPosture", reflect.TypeOf((*MockEncryptionPostureScope)(nil).EncryptionPosture))
+}
+
+// MachineEncryptionSpecs mocks base method.
+func (m *MockEncryptionPostureScope) MachineEncryptionSpecs(ctx context.Context) ([]azure.MachineEncryptionSpec, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MachineEncryptionSpecs", ctx)
+	ret0, _ := ret[0].([]azure.MachineEncryptionSpec)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MachineEncryptionSpecs indicates an expected call of MachineEncryptionSpecs.
+func (mr *MockEncryptionPostureScopeMockRecorder) MachineEncryptionSpecs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MachineEncryptionSpecs", reflect.TypeOf((*MockEncryptionPostureScope)(nil).MachineEncryptionSpecs), ctx)
+}
+
+// SetEncryptionPosture mocks base method.
+func (m *MockEncryptionPostureScope) SetEncryptionPosture(arg0 *v1beta1.EncryptionPosture) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetEncryptionPosture", arg0)
+}
+
+// SetEncryptionPosture indicates an expected call of SetEncryptionPosture.
+func (mr *MockEncryptionPostureScopeMockRecorder) SetEncryptionPosture(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEncryptionPosture", reflect.TypeOf((*MockEncryptionPostureScope)(nil).SetEncryptionPosture), arg0)
+}
@@ -0,0 +1,30 @@
+//go:build !faultinjection
+// +build !faultinjection
+
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+import "github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+
+// Enabled is true in binaries built with the faultinjection build tag.
+const Enabled = false
+
+// Policy returns nil, as faults are only injected in binaries built with the faultinjection build tag.
+func Policy() policy.Policy {
+	return nil
+}
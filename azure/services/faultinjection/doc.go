@@ -0,0 +1,22 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package faultinjection injects faults, such as failed calls, latency and throttling, into the ARM requests of the
+// services, so that the resilience of the reconcilers can be exercised without real Azure outages.
+//
+// Faults are only injected in binaries built with the faultinjection build tag; in other builds Policy returns nil
+// and the package has no effect. It must never be enabled in release builds.
+package faultinjection
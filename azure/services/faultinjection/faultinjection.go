@@ -0,0 +1,173 @@
+//go:build faultinjection
+// +build faultinjection
+
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Enabled is true in binaries built with the faultinjection build tag.
+const Enabled = true
+
+// RulesEnvVar is the environment variable holding the JSON list of rules injected when the binary starts.
+const RulesEnvVar = "CAPZ_FAULT_INJECTION_RULES"
+
+// Rule describes the faults injected into the ARM requests it matches.
+type Rule struct {
+	// Method is the HTTP method of the matched requests, e.g. PUT. All methods are matched when empty.
+	Method string `json:"method,omitempty"`
+	// Path is a case-insensitive substring of the URL path of the matched requests, e.g.
+	// "/virtualMachines/my-machine" for the VM of a single machine, or the name of a machine for all its resources.
+	// All paths are matched when empty.
+	Path string `json:"path,omitempty"`
+	// Nth is the 1-based index of the only matched request that is faulted. All matched requests are faulted when 0.
+	Nth int `json:"nth,omitempty"`
+	// Latency delays the faulted requests.
+	Latency metav1.Duration `json:"latency,omitempty"`
+	// StatusCode is the status of the response returned instead of sending the faulted requests, e.g. 429 or 500.
+	// Faulted requests are sent to Azure after the latency when 0.
+	StatusCode int `json:"statusCode,omitempty"`
+	// RetryAfter is the Retry-After header of throttled (429) responses. Defaults to 1s.
+	RetryAfter metav1.Duration `json:"retryAfter,omitempty"`
+}
+
+// injectedRule is a rule along with the number of requests it matched.
+type injectedRule struct {
+	Rule
+	matched int
+}
+
+var (
+	mu    sync.Mutex
+	rules []*injectedRule
+)
+
+func init() {
+	value := os.Getenv(RulesEnvVar)
+	if value == "" {
+		return
+	}
+	var envRules []Rule
+	if err := json.Unmarshal([]byte(value), &envRules); err != nil {
+		panic(fmt.Sprintf("invalid %s: %v", RulesEnvVar, err))
+	}
+	for _, rule := range envRules {
+		Inject(rule)
+	}
+}
+
+// Inject adds a rule to the rules applied to ARM requests.
+func Inject(rule Rule) {
+	mu.Lock()
+	defer mu.Unlock()
+	rules = append(rules, &injectedRule{Rule: rule})
+}
+
+// Reset removes all the rules.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	rules = nil
+}
+
+// Policy returns the policy injecting faults into ARM requests according to the rules.
+func Policy() policy.Policy {
+	return faultPolicy{}
+}
+
+// faultPolicy injects faults into the ARM requests matched by the rules.
+// It implements the policy.Policy interface.
+type faultPolicy struct{}
+
+// Do applies the first rule faulting the request, if any.
+func (faultPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	rule := faultFor(raw)
+	if rule == nil {
+		return req.Next()
+	}
+
+	if rule.Latency.Duration > 0 {
+		select {
+		case <-time.After(rule.Latency.Duration):
+		case <-raw.Context().Done():
+			return nil, raw.Context().Err()
+		}
+	}
+	if rule.StatusCode == 0 {
+		return req.Next()
+	}
+	return faultResponse(raw, rule), nil
+}
+
+// faultFor counts the request against the rules matching it, and returns the first rule faulting it.
+func faultFor(req *http.Request) *Rule {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var fault *Rule
+	path := strings.ToLower(req.URL.Path)
+	for _, rule := range rules {
+		if rule.Method != "" && !strings.EqualFold(rule.Method, req.Method) {
+			continue
+		}
+		if rule.Path != "" && !strings.Contains(path, strings.ToLower(rule.Path)) {
+			continue
+		}
+		rule.matched++
+		if fault == nil && (rule.Nth == 0 || rule.Nth == rule.matched) {
+			fault = &rule.Rule
+		}
+	}
+	return fault
+}
+
+// faultResponse returns an ARM error response with the status code of the rule.
+func faultResponse(req *http.Request, rule *Rule) *http.Response {
+	code := "InjectedFault"
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	if rule.StatusCode == http.StatusTooManyRequests {
+		code = "TooManyRequests"
+		retryAfter := rule.RetryAfter.Duration
+		if retryAfter == 0 {
+			retryAfter = time.Second
+		}
+		header.Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	}
+	body := fmt.Sprintf(`{"error":{"code":%q,"message":"fault injected into %s %s"}}`, code, req.Method, req.URL.Path)
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", rule.StatusCode, http.StatusText(rule.StatusCode)),
+		StatusCode: rule.StatusCode,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
@@ -0,0 +1,121 @@
+//go:build faultinjection
+// +build faultinjection
+
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/faultinjection"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/fakearm"
+)
+
+func vmID(subscriptionID, name string) string {
+	return "/subscriptions/" + subscriptionID + "/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/" + name
+}
+
+func TestPolicy(t *testing.T) {
+	server := fakearm.NewServer()
+	defer server.Close()
+	server.Register()
+
+	opts, err := azure.ARMClientOptions(fakearm.CloudName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := arm.NewClient("faultinjection.test", "v0.0.1", server.Credential(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	send := func(method, id string) *http.Response {
+		req, err := runtime.NewRequest(context.Background(), method, runtime.JoinPaths(client.Endpoint(), id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if method == http.MethodPut {
+			if err := runtime.MarshalAsJSON(req, map[string]interface{}{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		resp, err := client.Pipeline().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	t.Run("N-th call of a single machine fails", func(t *testing.T) {
+		g := NewWithT(t)
+		defer faultinjection.Reset()
+		g.Expect(server.Put(vmID("sub-nth", "machine-0"), map[string]interface{}{})).To(Succeed())
+		g.Expect(server.Put(vmID("sub-nth", "machine-1"), map[string]interface{}{})).To(Succeed())
+		faultinjection.Inject(faultinjection.Rule{
+			Method:     http.MethodGet,
+			Path:       "/virtualmachines/machine-0",
+			Nth:        2,
+			StatusCode: http.StatusInternalServerError,
+		})
+
+		var statuses []int
+		for i := 0; i < 3; i++ {
+			statuses = append(statuses, send(http.MethodGet, vmID("sub-nth", "machine-0")).StatusCode)
+		}
+		g.Expect(statuses).To(Equal([]int{http.StatusOK, http.StatusInternalServerError, http.StatusOK}))
+		g.Expect(send(http.MethodGet, vmID("sub-nth", "machine-1")).StatusCode).To(Equal(http.StatusOK))
+	})
+
+	t.Run("latency delays the requests sent to Azure", func(t *testing.T) {
+		g := NewWithT(t)
+		defer faultinjection.Reset()
+		g.Expect(server.Put(vmID("sub-latency", "machine-0"), map[string]interface{}{})).To(Succeed())
+		faultinjection.Inject(faultinjection.Rule{
+			Path:    "machine-0",
+			Latency: metav1.Duration{Duration: 100 * time.Millisecond},
+		})
+
+		start := time.Now()
+		g.Expect(send(http.MethodGet, vmID("sub-latency", "machine-0")).StatusCode).To(Equal(http.StatusOK))
+		g.Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+	})
+
+	t.Run("throttled calls are not sent to Azure", func(t *testing.T) {
+		g := NewWithT(t)
+		defer faultinjection.Reset()
+		faultinjection.Inject(faultinjection.Rule{
+			Method:     http.MethodPut,
+			StatusCode: http.StatusTooManyRequests,
+			RetryAfter: metav1.Duration{Duration: 2 * time.Second},
+		})
+		requests := len(server.Requests())
+
+		resp := send(http.MethodPut, vmID("sub-throttled", "machine-0"))
+		g.Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+		g.Expect(resp.Header.Get("Retry-After")).To(Equal("2"))
+		g.Expect(runtime.NewResponseError(resp)).To(MatchError(ContainSubstring("TooManyRequests")))
+		g.Expect(server.Requests()).To(HaveLen(requests))
+	})
+}
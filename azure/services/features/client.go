@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// ComputeNamespace is the resource provider namespace of the compute features.
+	ComputeNamespace = "Microsoft.Compute"
+	// EncryptionAtHost is the feature that must be registered on a subscription to create VMs with encryption at host.
+	EncryptionAtHost = "EncryptionAtHost"
+
+	apiVersion      = "2021-07-01"
+	registeredState = "Registered"
+)
+
+// Client checks the registration of subscription features.
+type Client interface {
+	IsRegistered(ctx context.Context, resourceProviderNamespace, featureName string) (bool, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	client *arm.Client
+	auth   azure.Authorizer
+}
+
+var (
+	_               Client = &AzureClient{}
+	doOnce          sync.Once
+	registeredCache ttllru.PeekingCacher
+)
+
+// NewClient creates a new features client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create features client options")
+	}
+	client, err := arm.NewClient("features.AzureClient", "v1.0.0", auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create features client")
+	}
+	return &AzureClient{
+		client: client,
+		auth:   auth,
+	}, nil
+}
+
+// IsRegistered returns true if the feature is registered on the subscription. Registered features are cached since
+// they are seldom unregistered, features that are not registered yet are looked up again on the next call.
+func (ac *AzureClient) IsRegistered(ctx context.Context, resourceProviderNamespace, featureName string) (bool, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "features.AzureClient.IsRegistered")
+	defer done()
+
+	var err error
+	doOnce.Do(func() {
+		registeredCache, err = ttllru.New(128, time.Hour)
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed creating LRU cache for features")
+	}
+
+	key := strings.Join([]string{ac.auth.HashKey(), resourceProviderNamespace, featureName}, "/")
+	if _, ok := registeredCache.Get(key); ok {
+		return true, nil
+	}
+
+	state, err := ac.getState(ctx, resourceProviderNamespace, featureName)
+	if err != nil {
+		return false, err
+	}
+	if !strings.EqualFold(state, registeredState) {
+		return false, nil
+	}
+	_ = registeredCache.Add(key, struct{}{})
+	return true, nil
+}
+
+// getState returns the registration state of a feature on the subscription.
+func (ac *AzureClient) getState(ctx context.Context, resourceProviderNamespace, featureName string) (string, error) {
+	urlPath := runtime.JoinPaths("/subscriptions", ac.auth.SubscriptionID(), "providers/Microsoft.Features/providers", resourceProviderNamespace, "features", featureName)
+	req, err := runtime.NewRequest(ctx, http.MethodGet, runtime.JoinPaths(ac.client.Endpoint(), urlPath))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create feature request")
+	}
+	query := req.Raw().URL.Query()
+	query.Set("api-version", apiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header["Accept"] = []string{"application/json"}
+
+	resp, err := ac.client.Pipeline().Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get feature %s/%s", resourceProviderNamespace, featureName)
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return "", errors.Wrapf(runtime.NewResponseError(resp), "failed to get feature %s/%s", resourceProviderNamespace, featureName)
+	}
+
+	var feature struct {
+		Properties struct {
+			State string `json:"state"`
+		} `json:"properties"`
+	}
+	if err := runtime.UnmarshalAsJSON(resp, &feature); err != nil {
+		return "", errors.Wrapf(err, "failed to parse feature %s/%s", resourceProviderNamespace, featureName)
+	}
+	return feature.Properties.State, nil
+}
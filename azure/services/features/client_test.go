@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/fakearm"
+)
+
+func TestIsRegistered(t *testing.T) {
+	g := NewWithT(t)
+
+	server := fakearm.NewServer()
+	defer server.Close()
+	server.Register()
+
+	client, err := NewClient(server.Authorizer("123"))
+	g.Expect(err).NotTo(HaveOccurred())
+	id := "/subscriptions/123/providers/Microsoft.Features/providers/Microsoft.Compute/features/EncryptionAtHost"
+
+	server.InjectError(http.MethodGet, id, http.StatusForbidden, "AuthorizationFailed")
+	_, err = client.IsRegistered(context.Background(), ComputeNamespace, EncryptionAtHost)
+	g.Expect(err).To(MatchError(ContainSubstring("AuthorizationFailed")))
+
+	g.Expect(server.Put(id, map[string]interface{}{"properties": map[string]interface{}{"state": "Registering"}})).To(Succeed())
+	registered, err := client.IsRegistered(context.Background(), ComputeNamespace, EncryptionAtHost)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(registered).To(BeFalse())
+
+	g.Expect(server.Put(id, map[string]interface{}{"properties": map[string]interface{}{"state": "Registered"}})).To(Succeed())
+	registered, err = client.IsRegistered(context.Background(), ComputeNamespace, EncryptionAtHost)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(registered).To(BeTrue())
+	g.Expect(server.Requests()).To(HaveLen(3))
+
+	// A registered feature is served from the cache.
+	registered, err = client.IsRegistered(context.Background(), ComputeNamespace, EncryptionAtHost)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(registered).To(BeTrue())
+	g.Expect(server.Requests()).To(HaveLen(3))
+}
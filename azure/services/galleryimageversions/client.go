@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package galleryimageversions
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client lists the versions of compute gallery images.
+type Client interface {
+	ListVersions(ctx context.Context, image *infrav1.AzureComputeGalleryImage, location string) ([]string, error)
+	GetTags(ctx context.Context, image *infrav1.AzureComputeGalleryImage) (map[string]*string, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+// The SDK clients are created for each request since private galleries can be in another subscription.
+type AzureClient struct {
+	auth azure.Authorizer
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new gallery image versions client from an authorizer.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{auth: auth}
+}
+
+// ListVersions returns the names of the versions of a compute gallery image that can be selected as the latest
+// version in a location. Versions excluded from latest, not provisioned successfully, or not replicated to the location
+// are left out.
+func (ac *AzureClient) ListVersions(ctx context.Context, image *infrav1.AzureComputeGalleryImage, location string) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "galleryimageversions.AzureClient.ListVersions")
+	defer done()
+
+	opts, err := azure.ARMClientOptions(ac.auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create gallery image versions client options")
+	}
+
+	// For private Azure Compute Gallery consumption both resource group and subscription ID must be provided.
+	// If they are not, we assume use of community gallery.
+	if image.ResourceGroup != nil && image.SubscriptionID != nil {
+		client, err := armcompute.NewGalleryImageVersionsClient(*image.SubscriptionID, ac.auth.Token(), opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create gallery image versions client")
+		}
+		var versions []string
+		pager := client.NewListByGalleryImagePager(*image.ResourceGroup, image.Gallery, image.Name, nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list versions of image %s in gallery %s", image.Name, image.Gallery)
+			}
+			for _, version := range page.Value {
+				if isLatestCandidate(version, location) {
+					versions = append(versions, *version.Name)
+				}
+			}
+		}
+		return versions, nil
+	}
+
+	client, err := armcompute.NewCommunityGalleryImageVersionsClient(ac.auth.SubscriptionID(), ac.auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create community gallery image versions client")
+	}
+	var versions []string
+	pager := client.NewListPager(location, image.Gallery, image.Name, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list versions of image %s in community gallery %s", image.Name, image.Gallery)
+		}
+		for _, version := range page.Value {
+			if version == nil || version.Name == nil {
+				continue
+			}
+			if version.Properties != nil && ptr.Deref(version.Properties.ExcludeFromLatest, false) {
+				continue
+			}
+			versions = append(versions, *version.Name)
+		}
+	}
+	return versions, nil
+}
+
+// GetTags returns the tags of a compute gallery image version. Image versions of community galleries have no tags.
+func (ac *AzureClient) GetTags(ctx context.Context, image *infrav1.AzureComputeGalleryImage) (map[string]*string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "galleryimageversions.AzureClient.GetTags")
+	defer done()
+
+	if image.ResourceGroup == nil || image.SubscriptionID == nil {
+		return nil, nil
+	}
+
+	opts, err := azure.ARMClientOptions(ac.auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create gallery image versions client options")
+	}
+	client, err := armcompute.NewGalleryImageVersionsClient(*image.SubscriptionID, ac.auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create gallery image versions client")
+	}
+	resp, err := client.Get(ctx, *image.ResourceGroup, image.Gallery, image.Name, image.Version, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get version %s of image %s in gallery %s", image.Version, image.Name, image.Gallery)
+	}
+	return resp.Tags, nil
+}
+
+// isLatestCandidate returns true if a version of a private gallery image can be selected as the latest version in a
+// location.
+func isLatestCandidate(version *armcompute.GalleryImageVersion, location string) bool {
+	if version == nil || version.Name == nil || version.Properties == nil {
+		return false
+	}
+	if version.Properties.ProvisioningState == nil || *version.Properties.ProvisioningState != armcompute.GalleryProvisioningStateSucceeded {
+		return false
+	}
+	profile := version.Properties.PublishingProfile
+	if profile == nil {
+		return false
+	}
+	if ptr.Deref(profile.ExcludeFromLatest, false) {
+		return false
+	}
+	for _, region := range profile.TargetRegions {
+		if region != nil && normalizeLocation(ptr.Deref(region.Name, "")) == normalizeLocation(location) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeLocation converts a location display name such as "East US" to its name such as "eastus".
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package galleryimageversions
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// KubernetesVersionTag is the tag holding the Kubernetes version of the compute gallery image versions built by
+// image-builder.
+const KubernetesVersionTag = "kubernetes_version"
+
+var (
+	doOnce        sync.Once
+	versionsCache ttllru.PeekingCacher
+	tagsOnce      sync.Once
+	tagsCache     ttllru.PeekingCacher
+)
+
+// cachedVersion is the latest version of an image found at a given time.
+type cachedVersion struct {
+	version string
+	checked time.Time
+}
+
+// Service resolves the versions of compute gallery images.
+type Service struct {
+	Client
+	azure.Authorizer
+}
+
+// New creates a new gallery image versions service.
+func New(auth azure.Authorizer) *Service {
+	return &Service{
+		Client:     NewClient(auth),
+		Authorizer: auth,
+	}
+}
+
+// GetLatestVersion returns the latest version of a compute gallery image available in a location whose major and
+// minor versions match the given "Major.Minor" prefix. The latest version found is reused until it is older than maxAge.
+func (s *Service) GetLatestVersion(ctx context.Context, image *infrav1.AzureComputeGalleryImage, location, prefix string, maxAge time.Duration) (string, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "galleryimageversions.Service.GetLatestVersion")
+	defer done()
+
+	var err error
+	doOnce.Do(func() {
+		versionsCache, err = ttllru.New(1024, 24*time.Hour)
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed creating LRU cache for gallery image versions")
+	}
+
+	key := strings.Join([]string{s.HashKey(), ptr.Deref(image.SubscriptionID, ""), ptr.Deref(image.ResourceGroup, ""), image.Gallery, image.Name, location, prefix}, "/")
+	if cached, ok := versionsCache.Get(key); ok {
+		if c, ok := cached.(cachedVersion); ok && time.Since(c.checked) < maxAge {
+			return c.version, nil
+		}
+	}
+
+	versions, err := s.ListVersions(ctx, image, location)
+	if err != nil {
+		return "", err
+	}
+	version, ok := LatestVersion(versions, prefix)
+	if !ok {
+		return "", errors.Errorf("no version %s.x of image %s in gallery %s is available in location %s", prefix, image.Name, image.Gallery, location)
+	}
+	log.V(4).Info("found latest gallery image version", "image", image.Name, "gallery", image.Gallery, "version", version)
+	_ = versionsCache.Add(key, cachedVersion{version: version, checked: time.Now()})
+	return version, nil
+}
+
+// GetKubernetesVersion returns the Kubernetes version of a compute gallery image version from its KubernetesVersionTag,
+// or an empty string when the image version has no such tag. Image versions are immutable, so the version found is
+// reused for the lifetime of the cache.
+func (s *Service) GetKubernetesVersion(ctx context.Context, image *infrav1.AzureComputeGalleryImage) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "galleryimageversions.Service.GetKubernetesVersion")
+	defer done()
+
+	var err error
+	tagsOnce.Do(func() {
+		tagsCache, err = ttllru.New(1024, 24*time.Hour)
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed creating LRU cache for gallery image version tags")
+	}
+
+	key := strings.Join([]string{s.HashKey(), ptr.Deref(image.SubscriptionID, ""), ptr.Deref(image.ResourceGroup, ""), image.Gallery, image.Name, image.Version}, "/")
+	if cached, ok := tagsCache.Get(key); ok {
+		if version, ok := cached.(string); ok {
+			return version, nil
+		}
+	}
+
+	tags, err := s.GetTags(ctx, image)
+	if err != nil {
+		return "", err
+	}
+	version := ptr.Deref(tags[KubernetesVersionTag], "")
+	_ = tagsCache.Add(key, version)
+	return version, nil
+}
+
+// LatestVersion returns the version with the highest build number among the Major.Minor.Build versions matching the
+// "Major.Minor" prefix.
+func LatestVersion(versions []string, prefix string) (string, bool) {
+	latest, latestBuild := "", -1
+	for _, version := range versions {
+		build, ok := strings.CutPrefix(version, prefix+".")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(build)
+		if err != nil || n < 0 {
+			continue
+		}
+		if n > latestBuild {
+			latest, latestBuild = version, n
+		}
+	}
+	return latest, latestBuild >= 0
+}
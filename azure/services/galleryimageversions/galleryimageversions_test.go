@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package galleryimageversions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/galleryimageversions/mock_galleryimageversions"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/fakearm"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func TestLatestVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		prefix   string
+		want     string
+		wantOK   bool
+	}{
+		{
+			name:     "no versions",
+			versions: nil,
+			prefix:   "1.2",
+			wantOK:   false,
+		},
+		{
+			name:     "no version matches the prefix",
+			versions: []string{"1.1.9", "1.20.1", "2.2.3"},
+			prefix:   "1.2",
+			wantOK:   false,
+		},
+		{
+			name:     "highest build is compared numerically",
+			versions: []string{"1.2.9", "1.2.10", "1.2.2", "1.3.100"},
+			prefix:   "1.2",
+			want:     "1.2.10",
+			wantOK:   true,
+		},
+		{
+			name:     "versions with a malformed build are ignored",
+			versions: []string{"1.2.3", "1.2.x", "1.2.4.5"},
+			prefix:   "1.2",
+			want:     "1.2.3",
+			wantOK:   true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, ok := LatestVersion(tc.versions, tc.prefix)
+			g.Expect(ok).To(Equal(tc.wantOK))
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestIsLatestCandidate(t *testing.T) {
+	version := func(state armcompute.GalleryProvisioningState, excluded bool, regions ...string) *armcompute.GalleryImageVersion {
+		profile := &armcompute.GalleryImageVersionPublishingProfile{ExcludeFromLatest: ptr.To(excluded)}
+		for _, region := range regions {
+			profile.TargetRegions = append(profile.TargetRegions, &armcompute.TargetRegion{Name: ptr.To(region)})
+		}
+		return &armcompute.GalleryImageVersion{
+			Name: ptr.To("1.2.3"),
+			Properties: &armcompute.GalleryImageVersionProperties{
+				ProvisioningState: ptr.To(state),
+				PublishingProfile: profile,
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		version *armcompute.GalleryImageVersion
+		want    bool
+	}{
+		{
+			name:    "replicated to the location",
+			version: version(armcompute.GalleryProvisioningStateSucceeded, false, "West US", "East US 2"),
+			want:    true,
+		},
+		{
+			name:    "not replicated to the location",
+			version: version(armcompute.GalleryProvisioningStateSucceeded, false, "West US"),
+			want:    false,
+		},
+		{
+			name:    "excluded from latest",
+			version: version(armcompute.GalleryProvisioningStateSucceeded, true, "eastus2"),
+			want:    false,
+		},
+		{
+			name:    "still replicating",
+			version: version(armcompute.GalleryProvisioningStateUpdating, false, "eastus2"),
+			want:    false,
+		},
+		{
+			name:    "no properties",
+			version: &armcompute.GalleryImageVersion{Name: ptr.To("1.2.3")},
+			want:    false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isLatestCandidate(tc.version, "eastus2")).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestGetLatestVersion(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	server := fakearm.NewServer()
+	defer server.Close()
+	clientMock := mock_galleryimageversions.NewMockClient(mockCtrl)
+	s := &Service{Client: clientMock, Authorizer: server.Authorizer("123")}
+	image := &infrav1.AzureComputeGalleryImage{
+		Gallery:        "my-gallery",
+		Name:           "my-image",
+		ResourceGroup:  ptr.To("my-rg"),
+		SubscriptionID: ptr.To("123"),
+	}
+
+	clientMock.EXPECT().ListVersions(gomockinternal.AContext(), image, "eastus").Return([]string{"1.2.1", "1.2.3", "1.3.0"}, nil)
+	version, err := s.GetLatestVersion(context.Background(), image, "eastus", "1.2", time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(version).To(Equal("1.2.3"))
+
+	// The latest version is reused while it is younger than the maximum age.
+	version, err = s.GetLatestVersion(context.Background(), image, "eastus", "1.2", time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(version).To(Equal("1.2.3"))
+
+	clientMock.EXPECT().ListVersions(gomockinternal.AContext(), image, "eastus").Return([]string{"1.2.1", "1.2.3", "1.2.4"}, nil)
+	version, err = s.GetLatestVersion(context.Background(), image, "eastus", "1.2", 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(version).To(Equal("1.2.4"))
+
+	clientMock.EXPECT().ListVersions(gomockinternal.AContext(), image, "eastus").Return([]string{"1.2.1"}, nil)
+	_, err = s.GetLatestVersion(context.Background(), image, "eastus", "2.0", 0)
+	g.Expect(err).To(MatchError("no version 2.0.x of image my-image in gallery my-gallery is available in location eastus"))
+}
@@ -21,9 +21,11 @@ import (
 
 	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
 	asoannotations "github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -42,11 +44,17 @@ type GroupScope interface {
 	GroupSpecs() []azure.ASOResourceSpecGetter[*asoresourcesv1.ResourceGroup]
 }
 
+// ManagedStatusScope is implemented by scopes which report whether their resource group is managed by CAPZ.
+type ManagedStatusScope interface {
+	SetResourceGroupManaged(managed bool)
+}
+
 // New creates a new service.
 func New(scope GroupScope) *Service {
 	svc := aso.NewService[*asoresourcesv1.ResourceGroup](ServiceName, scope)
 	svc.Specs = scope.GroupSpecs()
 	svc.ConditionType = infrav1.ResourceGroupReadyCondition
+	svc.PostCreateOrUpdateResourceHook = postCreateOrUpdateResourceHook
 	return &Service{
 		Scope:   scope,
 		Service: svc,
@@ -63,23 +71,80 @@ func (s *Service) IsManaged(ctx context.Context, spec azure.ASOResourceSpecGette
 // managed and reconciled by ASO, meaning that we can rely on a single resource
 // group delete operation as opposed to deleting every individual resource.
 func (s *Service) ShouldDeleteIndividualResources(ctx context.Context) bool {
-	// Unless all resource groups are managed by CAPZ and reconciled by ASO, resources need to be deleted individually.
+	// Unless all resource groups are managed by CAPZ, resources need to be deleted individually.
 	for _, spec := range s.Specs {
 		// Since this is a best effort attempt to speed up delete, we don't fail the delete if we can't get the RG status.
 		// Instead, take the long way and delete all resources one by one.
-		managed, err := s.IsManaged(ctx, spec)
-		if err != nil || !managed {
-			return true
-		}
-
-		// For ASO, "managed" only tells us that we're allowed to delete the ASO
-		// resource. We also need to check that deleting the ASO resource will really
-		// delete the underlying resource group by checking the ASO reconcile-policy.
 		group := spec.ResourceRef()
-		err = s.Scope.GetClient().Get(ctx, client.ObjectKeyFromObject(group), group)
-		if err != nil || group.GetAnnotations()[asoannotations.ReconcilePolicy] != string(asoannotations.ReconcilePolicyManage) {
+		err := s.Scope.GetClient().Get(ctx, client.ObjectKeyFromObject(group), group)
+		if err != nil || !isManagedGroup(group, s.Scope.ClusterName()) {
 			return true
 		}
 	}
 	return false
 }
+
+// Delete deletes the ASO ResourceGroups created by CAPZ. ASO is first told to leave the resource groups which are not
+// managed by CAPZ in Azure, so that deleting a cluster never deletes a resource group it did not create, even one
+// adopted by ASO.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.Service.Delete")
+	defer done()
+
+	for _, spec := range s.Specs {
+		if err := s.orphanUnmanagedGroup(ctx, spec); err != nil {
+			return err
+		}
+	}
+	return s.Service.Delete(ctx)
+}
+
+// orphanUnmanagedGroup sets the reconcile policy of the ASO ResourceGroup of spec to skip if CAPZ created the ASO
+// resource but not the resource group, so that deleting the ASO resource leaves the resource group in Azure.
+func (s *Service) orphanUnmanagedGroup(ctx context.Context, spec azure.ASOResourceSpecGetter[*asoresourcesv1.ResourceGroup]) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "groups.Service.orphanUnmanagedGroup")
+	defer done()
+
+	group := spec.ResourceRef()
+	if err := s.Scope.GetClient().Get(ctx, client.ObjectKeyFromObject(group), group); err != nil {
+		return errors.Wrapf(client.IgnoreNotFound(err), "failed to get resource group %s/%s", group.Namespace, group.Name)
+	}
+	clusterName := s.Scope.ClusterName()
+	if group.Labels[infrav1.OwnedByClusterLabelKey] != clusterName || isManagedGroup(group, clusterName) ||
+		group.Annotations[asoannotations.ReconcilePolicy] == string(asoannotations.ReconcilePolicySkip) {
+		return nil
+	}
+
+	log.V(2).Info("resource group is not owned by the cluster, leaving it in Azure", "resourceGroup", group.Name)
+	patch := client.MergeFrom(group.DeepCopy())
+	annotations := group.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[asoannotations.ReconcilePolicy] = string(asoannotations.ReconcilePolicySkip)
+	group.SetAnnotations(annotations)
+	if err := s.Scope.GetClient().Patch(ctx, group, patch); err != nil {
+		return errors.Wrapf(err, "failed to set the reconcile policy of resource group %s/%s", group.Namespace, group.Name)
+	}
+	return nil
+}
+
+// postCreateOrUpdateResourceHook reports whether the resource group is managed by CAPZ, if the scope reports it.
+func postCreateOrUpdateResourceHook(_ context.Context, scope GroupScope, result *asoresourcesv1.ResourceGroup, err error) error {
+	if err != nil || result == nil {
+		return err
+	}
+	if recorder, ok := scope.(ManagedStatusScope); ok {
+		recorder.SetResourceGroupManaged(isManagedGroup(result, scope.ClusterName()))
+	}
+	return nil
+}
+
+// isManagedGroup returns true if the resource group was created by CAPZ for the cluster: its ASO resource was created
+// by CAPZ and is reconciled by ASO, and the resource group has the owned tag of the cluster. Only a managed resource
+// group is deleted along with the cluster.
+func isManagedGroup(group *asoresourcesv1.ResourceGroup, clusterName string) bool {
+	return group.Labels[infrav1.OwnedByClusterLabelKey] == clusterName &&
+		group.Annotations[asoannotations.ReconcilePolicy] == string(asoannotations.ReconcilePolicyManage) &&
+		infrav1.Tags(group.Status.Tags).HasOwned(clusterName)
+}
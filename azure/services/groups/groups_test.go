@@ -18,6 +18,7 @@ package groups
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
@@ -26,6 +27,7 @@ import (
 	"go.uber.org/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups/mock_groups"
@@ -101,7 +103,7 @@ func TestShouldDeleteIndividualResources(t *testing.T) {
 			expected: true,
 		},
 		{
-			name: "group is managed and has reconcile policy manage",
+			name: "group is managed and has reconcile policy manage but is not tagged as owned",
 			objects: []client.Object{
 				&asoresourcesv1.ResourceGroup{
 					ObjectMeta: metav1.ObjectMeta{
@@ -125,6 +127,38 @@ func TestShouldDeleteIndividualResources(t *testing.T) {
 				}).AnyTimes()
 				s.ClusterName().Return("cluster").AnyTimes()
 			},
+			expected: true,
+		},
+		{
+			name: "group is managed, has reconcile policy manage and is tagged as owned",
+			objects: []client.Object{
+				&asoresourcesv1.ResourceGroup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "name",
+						Namespace: "namespace",
+						Labels: map[string]string{
+							infrav1.OwnedByClusterLabelKey: "cluster",
+						},
+						Annotations: map[string]string{
+							asoannotations.ReconcilePolicy: string(asoannotations.ReconcilePolicyManage),
+						},
+					},
+					Status: asoresourcesv1.ResourceGroup_STATUS{
+						Tags: map[string]string{
+							infrav1.ClusterTagKey("cluster"): string(infrav1.ResourceLifecycleOwned),
+						},
+					},
+				},
+			},
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder) {
+				s.GroupSpecs().Return([]azure.ASOResourceSpecGetter[*asoresourcesv1.ResourceGroup]{
+					&GroupSpec{
+						Name:      "name",
+						Namespace: "namespace",
+					},
+				}).AnyTimes()
+				s.ClusterName().Return("cluster").AnyTimes()
+			},
 			expected: false,
 		},
 	}
@@ -151,3 +185,115 @@ func TestShouldDeleteIndividualResources(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteGroup(t *testing.T) {
+	tests := []struct {
+		name                    string
+		tags                    map[string]string
+		expectedReconcilePolicy string
+	}{
+		{
+			name:                    "group created by CAPZ is deleted",
+			tags:                    map[string]string{infrav1.ClusterTagKey("cluster"): string(infrav1.ResourceLifecycleOwned)},
+			expectedReconcilePolicy: string(asoannotations.ReconcilePolicyManage),
+		},
+		{
+			name:                    "adopted group without the owned tag is left in Azure",
+			tags:                    map[string]string{"team": "platform"},
+			expectedReconcilePolicy: string(asoannotations.ReconcilePolicySkip),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_groups.NewMockGroupScope(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			g.Expect(asoresourcesv1.AddToScheme(scheme)).To(Succeed())
+			group := &asoresourcesv1.ResourceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+					Labels: map[string]string{
+						infrav1.OwnedByClusterLabelKey: "cluster",
+					},
+					Annotations: map[string]string{
+						asoannotations.ReconcilePolicy: string(asoannotations.ReconcilePolicyManage),
+					},
+					// Keeps the deleted resource around to check its reconcile policy.
+					Finalizers: []string{"serviceoperator.azure.com/finalizer"},
+				},
+				Status: asoresourcesv1.ResourceGroup_STATUS{Tags: test.tags},
+			}
+			ctrlClient := fakeclient.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(group).
+				Build()
+			scopeMock.EXPECT().GetClient().Return(ctrlClient).AnyTimes()
+			scopeMock.EXPECT().ClusterName().Return("cluster").AnyTimes()
+			scopeMock.EXPECT().GroupSpecs().Return([]azure.ASOResourceSpecGetter[*asoresourcesv1.ResourceGroup]{
+				&GroupSpec{
+					Name:      "name",
+					Namespace: "namespace",
+				},
+			})
+			scopeMock.EXPECT().UpdateDeleteStatus(infrav1.ResourceGroupReadyCondition, ServiceName, gomock.Any())
+
+			err := New(scopeMock).Delete(context.Background())
+			g.Expect(azure.IsOperationNotDoneError(err)).To(BeTrue())
+
+			g.Expect(ctrlClient.Get(context.Background(), client.ObjectKeyFromObject(group), group)).To(Succeed())
+			g.Expect(group.DeletionTimestamp.IsZero()).To(BeFalse())
+			g.Expect(group.Annotations[asoannotations.ReconcilePolicy]).To(Equal(test.expectedReconcilePolicy))
+		})
+	}
+}
+
+type managedStatusScope struct {
+	*mock_groups.MockGroupScope
+	managed *bool
+}
+
+func (s *managedStatusScope) SetResourceGroupManaged(managed bool) {
+	s.managed = &managed
+}
+
+func TestPostCreateOrUpdateResourceHook(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_groups.NewMockGroupScope(mockCtrl)
+	scopeMock.EXPECT().ClusterName().Return("cluster").AnyTimes()
+	scope := &managedStatusScope{MockGroupScope: scopeMock}
+
+	group := &asoresourcesv1.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				infrav1.OwnedByClusterLabelKey: "cluster",
+			},
+			Annotations: map[string]string{
+				asoannotations.ReconcilePolicy: string(asoannotations.ReconcilePolicySkip),
+			},
+		},
+	}
+	g.Expect(postCreateOrUpdateResourceHook(context.Background(), scope, group, nil)).To(Succeed())
+	g.Expect(scope.managed).To(Equal(ptr.To(false)))
+
+	group.Annotations[asoannotations.ReconcilePolicy] = string(asoannotations.ReconcilePolicyManage)
+	group.Status.Tags = map[string]string{infrav1.ClusterTagKey("cluster"): string(infrav1.ResourceLifecycleOwned)}
+	g.Expect(postCreateOrUpdateResourceHook(context.Background(), scope, group, nil)).To(Succeed())
+	g.Expect(scope.managed).To(Equal(ptr.To(true)))
+
+	// The status is only reported once the resource group is reconciled.
+	scope.managed = nil
+	err := errors.New("creating")
+	g.Expect(postCreateOrUpdateResourceHook(context.Background(), scope, nil, err)).To(MatchError(err))
+	g.Expect(scope.managed).To(BeNil())
+
+	// Scopes which don't report the status are left alone.
+	g.Expect(postCreateOrUpdateResourceHook(context.Background(), scopeMock, group, nil)).To(Succeed())
+}
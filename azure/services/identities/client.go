@@ -18,7 +18,10 @@ package identities
 
 import (
 	"context"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
 	"github.com/pkg/errors"
 	"k8s.io/utils/ptr"
@@ -35,6 +38,9 @@ type Client interface {
 
 // AzureClient contains the Azure go-sdk Client.
 type AzureClient struct {
+	subscriptionID         string
+	credential             azcore.TokenCredential
+	options                *arm.ClientOptions
 	userAssignedIdentities *armmsi.UserAssignedIdentitiesClient
 }
 
@@ -48,7 +54,12 @@ func NewClient(auth azure.Authorizer) (*AzureClient, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create armmsi client factory")
 	}
-	return &AzureClient{factory.NewUserAssignedIdentitiesClient()}, nil
+	return &AzureClient{
+		subscriptionID:         auth.SubscriptionID(),
+		credential:             auth.Token(),
+		options:                opts,
+		userAssignedIdentities: factory.NewUserAssignedIdentitiesClient(),
+	}, nil
 }
 
 // Get returns a managed service identity.
@@ -72,9 +83,20 @@ func (ac *AzureClient) GetClientID(ctx context.Context, providerID string) (stri
 	if err != nil {
 		return "", err
 	}
-	ident, err := ac.Get(ctx, parsed.ResourceGroupName, parsed.Name)
+	// Machines can use an identity from another subscription than the cluster's, e.g. a shared kubelet identity.
+	userAssignedIdentities := ac.userAssignedIdentities
+	if parsed.SubscriptionID != "" && !strings.EqualFold(parsed.SubscriptionID, ac.subscriptionID) {
+		userAssignedIdentities, err = armmsi.NewUserAssignedIdentitiesClient(parsed.SubscriptionID, ac.credential, ac.options)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to create user-assigned identities client for subscription %s", parsed.SubscriptionID)
+		}
+	}
+	resp, err := userAssignedIdentities.Get(ctx, parsed.ResourceGroupName, parsed.Name, nil)
 	if err != nil {
 		return "", err
 	}
-	return ptr.Deref(ident.Properties.ClientID, ""), nil
+	if resp.Identity.Properties == nil {
+		return "", errors.Errorf("user-assigned identity %s has no client ID", providerID)
+	}
+	return ptr.Deref(resp.Identity.Properties.ClientID, ""), nil
 }
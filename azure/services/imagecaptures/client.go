@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagecaptures
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client provides operations on the VMs captured into compute gallery image versions and on the image versions.
+type Client interface {
+	Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error)
+	CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armcompute.GalleryImageVersionsClientCreateOrUpdateResponse], err error)
+	GetVM(ctx context.Context, resourceGroupName, vmName string) (armcompute.VirtualMachine, error)
+	RunCommandAsync(ctx context.Context, resourceGroupName, vmName, resumeToken, script string) (poller *runtime.Poller[armcompute.VirtualMachinesClientRunCommandResponse], err error)
+	DeallocateAsync(ctx context.Context, resourceGroupName, vmName, resumeToken string) (poller *runtime.Poller[armcompute.VirtualMachinesClientDeallocateResponse], err error)
+	Generalize(ctx context.Context, resourceGroupName, vmName string) error
+}
+
+// AzureClient contains the Azure go-sdk Client.
+// The gallery image versions SDK clients are created for each request since the gallery can be in another subscription.
+type AzureClient struct {
+	virtualmachines *armcompute.VirtualMachinesClient
+	auth            azure.Authorizer
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new image captures client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create imagecaptures client options")
+	}
+	factory, err := armcompute.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armcompute client factory")
+	}
+	return &AzureClient{
+		virtualmachines: factory.NewVirtualMachinesClient(),
+		auth:            auth,
+	}, nil
+}
+
+// Get gets a gallery image version.
+func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagecaptures.AzureClient.Get")
+	defer done()
+
+	captureSpec, client, err := ac.imageVersionsClient(spec)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Get(ctx, captureSpec.ResourceGroup, captureSpec.GalleryName, captureSpec.ImageName, captureSpec.Name, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GalleryImageVersion, nil
+}
+
+// CreateOrUpdateAsync creates or updates a gallery image version asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a Poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armcompute.GalleryImageVersionsClientCreateOrUpdateResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagecaptures.AzureClient.CreateOrUpdateAsync")
+	defer done()
+
+	version, ok := parameters.(armcompute.GalleryImageVersion)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armcompute.GalleryImageVersion", parameters)
+	}
+
+	captureSpec, client, err := ac.imageVersionsClient(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts := &armcompute.GalleryImageVersionsClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken}
+	poller, err = client.BeginCreateOrUpdate(ctx, captureSpec.ResourceGroup, captureSpec.GalleryName, captureSpec.ImageName, captureSpec.Name, version, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	resp, err := poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, poller, err
+	}
+
+	// if the operation completed, return a nil poller
+	return resp.GalleryImageVersion, nil, err
+}
+
+// GetVM retrieves the model view and the instance view of a VM.
+func (ac *AzureClient) GetVM(ctx context.Context, resourceGroupName, vmName string) (armcompute.VirtualMachine, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagecaptures.AzureClient.GetVM")
+	defer done()
+
+	opts := &armcompute.VirtualMachinesClientGetOptions{Expand: ptr.To(armcompute.InstanceViewTypesInstanceView)}
+	resp, err := ac.virtualmachines.Get(ctx, resourceGroupName, vmName, opts)
+	if err != nil {
+		return armcompute.VirtualMachine{}, err
+	}
+	return resp.VirtualMachine, nil
+}
+
+// RunCommandAsync runs a shell script on a VM asynchronously. RunCommandAsync sends a POST request to Azure and if
+// accepted without error, the func will return a Poller which can be used to track the ongoing progress of the operation.
+func (ac *AzureClient) RunCommandAsync(ctx context.Context, resourceGroupName, vmName, resumeToken, script string) (poller *runtime.Poller[armcompute.VirtualMachinesClientRunCommandResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagecaptures.AzureClient.RunCommand")
+	defer done()
+
+	input := armcompute.RunCommandInput{
+		CommandID: ptr.To("RunShellScript"),
+		Script:    []*string{ptr.To(script)},
+	}
+	opts := &armcompute.VirtualMachinesClientBeginRunCommandOptions{ResumeToken: resumeToken}
+	poller, err = ac.virtualmachines.BeginRunCommand(ctx, resourceGroupName, vmName, input, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	_, err = poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the Poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return poller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, err
+}
+
+// DeallocateAsync shuts down a VM and releases its compute resources asynchronously. DeallocateAsync sends a POST
+// request to Azure and if accepted without error, the func will return a Poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *AzureClient) DeallocateAsync(ctx context.Context, resourceGroupName, vmName, resumeToken string) (poller *runtime.Poller[armcompute.VirtualMachinesClientDeallocateResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagecaptures.AzureClient.Deallocate")
+	defer done()
+
+	opts := &armcompute.VirtualMachinesClientBeginDeallocateOptions{ResumeToken: resumeToken}
+	poller, err = ac.virtualmachines.BeginDeallocate(ctx, resourceGroupName, vmName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	_, err = poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the Poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return poller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, err
+}
+
+// Generalize marks a deallocated VM as generalized.
+func (ac *AzureClient) Generalize(ctx context.Context, resourceGroupName, vmName string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagecaptures.AzureClient.Generalize")
+	defer done()
+
+	_, err := ac.virtualmachines.Generalize(ctx, resourceGroupName, vmName, nil)
+	return err
+}
+
+// imageVersionsClient returns a gallery image versions client for the subscription of the gallery of the spec.
+func (ac *AzureClient) imageVersionsClient(spec azure.ResourceSpecGetter) (*ImageCaptureSpec, *armcompute.GalleryImageVersionsClient, error) {
+	captureSpec, ok := spec.(*ImageCaptureSpec)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not an ImageCaptureSpec", spec)
+	}
+	opts, err := azure.ARMClientOptions(ac.auth.CloudEnvironment())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create gallery image versions client options")
+	}
+	client, err := armcompute.NewGalleryImageVersionsClient(captureSpec.SubscriptionID, ac.auth.Token(), opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create gallery image versions client")
+	}
+	return captureSpec, client, nil
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagecaptures
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	serviceName            = "imagecaptures"
+	deprovisionServiceName = "imagecapturedeprovision"
+	deallocateServiceName  = "imagecapturedeallocate"
+
+	// generalizedStatus is the status code of the instance view of a generalized VM.
+	generalizedStatus = "OSState/generalized"
+
+	// deprovisionScript removes the cluster state, including the certificates and keys of a control plane machine, and
+	// the machine specific data such as its SSH host keys and users from the VM before it is generalized.
+	deprovisionScript = `set -o errexit
+systemctl stop kubelet || true
+rm -rf /etc/kubernetes /var/lib/kubelet /var/lib/etcd /etc/capz-bootstrap-data.txt
+cloud-init clean --logs
+waagent -deprovision+user -force
+`
+)
+
+// ImageCaptureScope defines the scope interface for an image captures service.
+type ImageCaptureScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	ImageCaptureSpec() azure.ResourceSpecGetter
+}
+
+// Service captures VMs into compute gallery image versions.
+type Service struct {
+	Scope ImageCaptureScope
+	async.Reconciler
+	client Client
+}
+
+// New creates a new image captures service.
+func New(scope ImageCaptureScope) (*Service, error) {
+	client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:  scope,
+		client: client,
+		// Image versions are never deleted by CAPZ.
+		Reconciler: async.New[armcompute.GalleryImageVersionsClientCreateOrUpdateResponse,
+			armcompute.GalleryImageVersionsClientDeleteResponse](scope, client, nil),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile captures the VM into the gallery image version requested through the CaptureImageAnnotation, if any. The
+// VM is deprovisioned, deallocated and generalized before the image version is created from it.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "imagecaptures.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specGetter := s.Scope.ImageCaptureSpec()
+	if specGetter == nil {
+		log.V(2).Info("skip reconciliation when no image capture is requested")
+		return nil
+	}
+	spec, ok := specGetter.(*ImageCaptureSpec)
+	if !ok {
+		return errors.Errorf("%T is not an ImageCaptureSpec", specGetter)
+	}
+
+	err := s.capture(ctx, spec)
+	s.Scope.UpdatePutStatus(infrav1.ImageCapturedCondition, serviceName, err)
+	return err
+}
+
+// capture generalizes the VM and creates the image version from it, unless the image version already exists.
+func (s *Service) capture(ctx context.Context, spec *ImageCaptureSpec) error {
+	if s.Scope.GetLongRunningOperationState(spec.ResourceName(), serviceName, infrav1.PutFuture) == nil {
+		_, err := s.client.Get(ctx, spec)
+		if err == nil {
+			return nil
+		}
+		if !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to get version %s of image %s in gallery %s", spec.Name, spec.ImageName, spec.GalleryName)
+		}
+		if err := s.generalizeVM(ctx, spec); err != nil {
+			return err
+		}
+	}
+	_, err := s.CreateOrUpdateResource(ctx, spec, serviceName)
+	return err
+}
+
+// generalizeVM deprovisions a running VM, deallocates it and marks it as generalized. A VM which is not running is not
+// deprovisioned since commands can only be run on running VMs.
+func (s *Service) generalizeVM(ctx context.Context, spec *ImageCaptureSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "imagecaptures.Service.generalizeVM")
+	defer done()
+
+	vm, err := s.client.GetVM(ctx, spec.VMResourceGroup, spec.VMName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get VM %s", spec.VMName)
+	}
+	var statuses []*armcompute.InstanceViewStatus
+	if vm.Properties != nil && vm.Properties.InstanceView != nil {
+		statuses = vm.Properties.InstanceView.Statuses
+	}
+	if isGeneralized(statuses) {
+		return nil
+	}
+	powerState := converters.SDKToVMPowerState(statuses)
+
+	deprovisionFuture := s.Scope.GetLongRunningOperationState(spec.VMName, deprovisionServiceName, infrav1.PostFuture)
+	deallocateFuture := s.Scope.GetLongRunningOperationState(spec.VMName, deallocateServiceName, infrav1.PostFuture)
+	if deprovisionFuture != nil || (deallocateFuture == nil && powerState == infrav1.VMPowerStateRunning) {
+		log.V(2).Info("deprovisioning VM", "vm", spec.VMName)
+		err := trackAsync(ctx, s.Scope, spec, deprovisionServiceName, deprovisionFuture, func(ctx context.Context, resumeToken string) (*runtime.Poller[armcompute.VirtualMachinesClientRunCommandResponse], error) {
+			return s.client.RunCommandAsync(ctx, spec.VMResourceGroup, spec.VMName, resumeToken, deprovisionScript)
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to deprovision VM")
+		}
+	}
+
+	if deallocateFuture != nil || powerState != infrav1.VMPowerStateDeallocated {
+		log.V(2).Info("deallocating VM", "vm", spec.VMName, "powerState", powerState)
+		err := trackAsync(ctx, s.Scope, spec, deallocateServiceName, deallocateFuture, func(ctx context.Context, resumeToken string) (*runtime.Poller[armcompute.VirtualMachinesClientDeallocateResponse], error) {
+			return s.client.DeallocateAsync(ctx, spec.VMResourceGroup, spec.VMName, resumeToken)
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to deallocate VM")
+		}
+	}
+
+	log.V(2).Info("generalizing VM", "vm", spec.VMName)
+	return errors.Wrap(s.client.Generalize(ctx, spec.VMResourceGroup, spec.VMName), "failed to generalize VM")
+}
+
+// Delete is a no-op as the captured image versions are meant to outlive the machine.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// IsManaged always returns true as the image versions are only captured when requested on the machine.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// isGeneralized returns true if the statuses of the instance view of a VM report it as generalized.
+func isGeneralized(statuses []*armcompute.InstanceViewStatus) bool {
+	for _, status := range statuses {
+		if status != nil && strings.EqualFold(ptr.Deref(status.Code, ""), generalizedStatus) {
+			return true
+		}
+	}
+	return false
+}
+
+// trackAsync starts a POST long-running operation on the VM, or resumes the one tracked by existingFuture, and keeps
+// track of it in the scope until it completes.
+func trackAsync[T any](ctx context.Context, scope async.FutureScope, spec *ImageCaptureSpec, service string, existingFuture *infrav1.Future, begin func(context.Context, string) (*runtime.Poller[T], error)) error {
+	resumeToken := ""
+	if existingFuture != nil {
+		t, err := converters.FutureToResumeToken(*existingFuture)
+		if err != nil {
+			scope.DeleteLongRunningOperationState(spec.VMName, service, infrav1.PostFuture)
+			return errors.Wrap(err, "could not decode future data, resetting long-running operation state")
+		}
+		resumeToken = t
+	}
+
+	poller, err := begin(ctx, resumeToken)
+	if poller != nil && azure.IsContextDeadlineExceededOrCanceledError(err) {
+		future, err := converters.PollerToFuture(poller, infrav1.PostFuture, service, spec.VMName, spec.VMResourceGroup)
+		if err != nil {
+			return errors.Wrapf(err, "failed to track long-running operation (service: %s)", service)
+		}
+		if existingFuture != nil && existingFuture.StartTime != nil {
+			future.StartTime = existingFuture.StartTime.DeepCopy()
+		} else {
+			now := metav1.Now()
+			future.StartTime = &now
+		}
+		scope.SetLongRunningOperationState(future)
+		return azure.WithTransientError(azure.NewOperationNotDoneError(future), reconciler.DefaultReconcilerRequeue)
+	}
+
+	// Once the operation is done, delete the long-running operation state, even if it ended with an error.
+	scope.DeleteLongRunningOperationState(spec.VMName, service, infrav1.PostFuture)
+	return err
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagecaptures
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/imagecaptures/mock_imagecaptures"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeCaptureSpec = &ImageCaptureSpec{
+		Name:            "1.2.3",
+		ImageName:       "node",
+		GalleryName:     "golden",
+		ResourceGroup:   "images-rg",
+		SubscriptionID:  "123",
+		Location:        "eastus",
+		VMName:          "test-vm",
+		VMResourceGroup: "test-group",
+		VMID:            "/subscriptions/123/resourceGroups/test-group/providers/Microsoft.Compute/virtualMachines/test-vm",
+	}
+	notFoundErr = &azcore.ResponseError{StatusCode: http.StatusNotFound}
+)
+
+func fakeVM(codes ...string) armcompute.VirtualMachine {
+	var statuses []*armcompute.InstanceViewStatus
+	for _, code := range codes {
+		statuses = append(statuses, &armcompute.InstanceViewStatus{Code: ptr.To(code)})
+	}
+	return armcompute.VirtualMachine{
+		Properties: &armcompute.VirtualMachineProperties{
+			InstanceView: &armcompute.VirtualMachineInstanceView{Statuses: statuses},
+		},
+	}
+}
+
+func fakePoller[T any](g *WithT) *runtime.Poller[T] {
+	response := &http.Response{
+		Body: io.NopCloser(strings.NewReader("")),
+		Request: &http.Request{
+			Method: http.MethodPost,
+			URL:    &url.URL{Path: "/"},
+		},
+		StatusCode: http.StatusAccepted,
+		Header:     http.Header{"Location": []string{"https://management.azure.com/operations/fake"}},
+	}
+	pipeline := runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, nil)
+	poller, err := runtime.NewPoller[T](response, pipeline, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	return poller
+}
+
+func TestReconcileImageCaptures(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(g *WithT, s *mock_imagecaptures.MockImageCaptureScopeMockRecorder, c *mock_imagecaptures.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name: "noop if no image capture is requested",
+			expect: func(g *WithT, s *mock_imagecaptures.MockImageCaptureScopeMockRecorder, c *mock_imagecaptures.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ImageCaptureSpec().Return(nil)
+			},
+		},
+		{
+			name: "noop if the image version already exists",
+			expect: func(g *WithT, s *mock_imagecaptures.MockImageCaptureScopeMockRecorder, c *mock_imagecaptures.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ImageCaptureSpec().Return(fakeCaptureSpec)
+				s.GetLongRunningOperationState("1.2.3", serviceName, infrav1.PutFuture).Return(nil)
+				c.Get(gomockinternal.AContext(), fakeCaptureSpec).Return(armcompute.GalleryImageVersion{}, nil)
+				s.UpdatePutStatus(infrav1.ImageCapturedCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "deprovisions a running VM and waits for it to deallocate",
+			expectedError: "failed to deallocate VM: operation type POST on Azure resource test-group/test-vm is not done",
+			expect: func(g *WithT, s *mock_imagecaptures.MockImageCaptureScopeMockRecorder, c *mock_imagecaptures.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ImageCaptureSpec().Return(fakeCaptureSpec)
+				s.GetLongRunningOperationState("1.2.3", serviceName, infrav1.PutFuture).Return(nil)
+				c.Get(gomockinternal.AContext(), fakeCaptureSpec).Return(nil, notFoundErr)
+				c.GetVM(gomockinternal.AContext(), "test-group", "test-vm").Return(fakeVM("ProvisioningState/succeeded", "PowerState/running"), nil)
+				s.GetLongRunningOperationState("test-vm", deprovisionServiceName, infrav1.PostFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture).Return(nil)
+				c.RunCommandAsync(gomockinternal.AContext(), "test-group", "test-vm", "", deprovisionScript).Return(nil, nil)
+				s.DeleteLongRunningOperationState("test-vm", deprovisionServiceName, infrav1.PostFuture)
+				c.DeallocateAsync(gomockinternal.AContext(), "test-group", "test-vm", "").Return(fakePoller[armcompute.VirtualMachinesClientDeallocateResponse](g), context.DeadlineExceeded)
+				s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{}))
+				s.UpdatePutStatus(infrav1.ImageCapturedCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name: "generalizes a deallocated VM and creates the image version",
+			expect: func(g *WithT, s *mock_imagecaptures.MockImageCaptureScopeMockRecorder, c *mock_imagecaptures.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ImageCaptureSpec().Return(fakeCaptureSpec)
+				s.GetLongRunningOperationState("1.2.3", serviceName, infrav1.PutFuture).Return(nil)
+				c.Get(gomockinternal.AContext(), fakeCaptureSpec).Return(nil, notFoundErr)
+				c.GetVM(gomockinternal.AContext(), "test-group", "test-vm").Return(fakeVM("ProvisioningState/succeeded", "PowerState/deallocated"), nil)
+				s.GetLongRunningOperationState("test-vm", deprovisionServiceName, infrav1.PostFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture).Return(nil)
+				c.Generalize(gomockinternal.AContext(), "test-group", "test-vm").Return(nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), fakeCaptureSpec, serviceName).Return(armcompute.GalleryImageVersion{}, nil)
+				s.UpdatePutStatus(infrav1.ImageCapturedCondition, serviceName, nil)
+			},
+		},
+		{
+			name: "creates the image version from a generalized VM",
+			expect: func(g *WithT, s *mock_imagecaptures.MockImageCaptureScopeMockRecorder, c *mock_imagecaptures.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ImageCaptureSpec().Return(fakeCaptureSpec)
+				s.GetLongRunningOperationState("1.2.3", serviceName, infrav1.PutFuture).Return(nil)
+				c.Get(gomockinternal.AContext(), fakeCaptureSpec).Return(nil, notFoundErr)
+				c.GetVM(gomockinternal.AContext(), "test-group", "test-vm").Return(fakeVM("OSState/generalized", "PowerState/deallocated"), nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), fakeCaptureSpec, serviceName).Return(armcompute.GalleryImageVersion{}, nil)
+				s.UpdatePutStatus(infrav1.ImageCapturedCondition, serviceName, nil)
+			},
+		},
+		{
+			name: "resumes the creation of the image version",
+			expect: func(g *WithT, s *mock_imagecaptures.MockImageCaptureScopeMockRecorder, c *mock_imagecaptures.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ImageCaptureSpec().Return(fakeCaptureSpec)
+				s.GetLongRunningOperationState("1.2.3", serviceName, infrav1.PutFuture).Return(&infrav1.Future{})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), fakeCaptureSpec, serviceName).Return(armcompute.GalleryImageVersion{}, nil)
+				s.UpdatePutStatus(infrav1.ImageCapturedCondition, serviceName, nil)
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_imagecaptures.NewMockImageCaptureScope(mockCtrl)
+			clientMock := mock_imagecaptures.NewMockClient(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(g, scopeMock.EXPECT(), clientMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+				client:     clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	params, err := fakeCaptureSpec.Parameters(context.TODO(), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	version, ok := params.(armcompute.GalleryImageVersion)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(version.Location).To(Equal(ptr.To("eastus")))
+	g.Expect(version.Properties.StorageProfile.Source.ID).To(Equal(ptr.To(fakeCaptureSpec.VMID)))
+
+	params, err = fakeCaptureSpec.Parameters(context.TODO(), armcompute.GalleryImageVersion{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+}
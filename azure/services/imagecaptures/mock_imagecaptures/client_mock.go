@@ -0,0 +1,148 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+//
+// Generated by this command:
+//
+//	mockgen -destination client_mock.go -package mock_imagecaptures -source ../client.go Client
+//
+// Package mock_imagecaptures is a generated GoMock package.
+package mock_imagecaptures
+
+import (
+	context "context"
+	reflect "reflect"
+
+	runtime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	armcompute "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	gomock "go.uber.org/mock/gomock"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// CreateOrUpdateAsync mocks base method.
+func (m *MockClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters any) (any, *runtime.Poller[armcompute.GalleryImageVersionsClientCreateOrUpdateResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateAsync", ctx, spec, resumeToken, parameters)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(*runtime.Poller[armcompute.GalleryImageVersionsClientCreateOrUpdateResponse])
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateOrUpdateAsync indicates an expected call of CreateOrUpdateAsync.
+func (mr *MockClientMockRecorder) CreateOrUpdateAsync(ctx, spec, resumeToken, parameters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateAsync", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateAsync), ctx, spec, resumeToken, parameters)
+}
+
+// DeallocateAsync mocks base method.
+func (m *MockClient) DeallocateAsync(ctx context.Context, resourceGroupName string, vmName string, resumeToken string) (*runtime.Poller[armcompute.VirtualMachinesClientDeallocateResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeallocateAsync", ctx, resourceGroupName, vmName, resumeToken)
+	ret0, _ := ret[0].(*runtime.Poller[armcompute.VirtualMachinesClientDeallocateResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeallocateAsync indicates an expected call of DeallocateAsync.
+func (mr *MockClientMockRecorder) DeallocateAsync(ctx, resourceGroupName, vmName, resumeToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeallocateAsync", reflect.TypeOf((*MockClient)(nil).DeallocateAsync), ctx, resourceGroupName, vmName, resumeToken)
+}
+
+// Generalize mocks base method.
+func (m *MockClient) Generalize(ctx context.Context, resourceGroupName string, vmName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generalize", ctx, resourceGroupName, vmName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Generalize indicates an expected call of Generalize.
+func (mr *MockClientMockRecorder) Generalize(ctx, resourceGroupName, vmName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generalize", reflect.TypeOf((*MockClient)(nil).Generalize), ctx, resourceGroupName, vmName)
+}
+
+// Get mocks base method.
+func (m *MockClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, spec)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockClientMockRecorder) Get(ctx, spec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClient)(nil).Get), ctx, spec)
+}
+
+// GetVM mocks base method.
+func (m *MockClient) GetVM(ctx context.Context, resourceGroupName string, vmName string) (armcompute.VirtualMachine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVM", ctx, resourceGroupName, vmName)
+	ret0, _ := ret[0].(armcompute.VirtualMachine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVM indicates an expected call of GetVM.
+func (mr *MockClientMockRecorder) GetVM(ctx, resourceGroupName, vmName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVM", reflect.TypeOf((*MockClient)(nil).GetVM), ctx, resourceGroupName, vmName)
+}
+
+// RunCommandAsync mocks base method.
+func (m *MockClient) RunCommandAsync(ctx context.Context, resourceGroupName string, vmName string, resumeToken string, script string) (*runtime.Poller[armcompute.VirtualMachinesClientRunCommandResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunCommandAsync", ctx, resourceGroupName, vmName, resumeToken, script)
+	ret0, _ := ret[0].(*runtime.Poller[armcompute.VirtualMachinesClientRunCommandResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunCommandAsync indicates an expected call of RunCommandAsync.
+func (mr *MockClientMockRecorder) RunCommandAsync(ctx, resourceGroupName, vmName, resumeToken, script any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunCommandAsync", reflect.TypeOf((*MockClient)(nil).RunCommandAsync), ctx, resourceGroupName, vmName, resumeToken, script)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagecaptures
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// ImageCaptureSpec defines the specification for a compute gallery image version captured from a VM.
+type ImageCaptureSpec struct {
+	// Name is the version of the gallery image, e.g. "1.2.3".
+	Name           string
+	ImageName      string
+	GalleryName    string
+	ResourceGroup  string
+	SubscriptionID string
+	Location       string
+	// VMName and VMResourceGroup identify the VM captured into the image version, in the subscription of the cluster.
+	VMName          string
+	VMResourceGroup string
+	VMID            string
+	AdditionalTags  infrav1.Tags
+}
+
+// ResourceName returns the version of the gallery image.
+func (s *ImageCaptureSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the gallery.
+func (s *ImageCaptureSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName returns the name of the gallery image.
+func (s *ImageCaptureSpec) OwnerResourceName() string {
+	return s.ImageName
+}
+
+// Parameters returns the parameters for the gallery image version.
+func (s *ImageCaptureSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(armcompute.GalleryImageVersion); !ok {
+			return nil, errors.Errorf("%T is not an armcompute.GalleryImageVersion", existing)
+		}
+		// image version already exists
+		return nil, nil
+	}
+
+	// The image version is not tagged as owned by the cluster since it is meant to outlive it.
+	return armcompute.GalleryImageVersion{
+		Location: ptr.To(s.Location),
+		Tags:     converters.TagsToMap(s.AdditionalTags),
+		Properties: &armcompute.GalleryImageVersionProperties{
+			StorageProfile: &armcompute.GalleryImageVersionStorageProfile{
+				Source: &armcompute.GalleryArtifactVersionFullSource{
+					ID: ptr.To(s.VMID),
+				},
+			},
+		},
+	}, nil
+}
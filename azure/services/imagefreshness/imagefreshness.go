@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagefreshness
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/galleryimageversions"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+var (
+	doOnce        sync.Once
+	versionsCache ttllru.PeekingCacher
+)
+
+// cachedVersion is the latest version of a marketplace image found at a given time.
+type cachedVersion struct {
+	version string
+	checked time.Time
+}
+
+// Service finds the latest builds of the marketplace and compute gallery images used by machines.
+type Service struct {
+	Marketplace virtualmachineimages.Client
+	Galleries   *galleryimageversions.Service
+	azure.Authorizer
+}
+
+// New creates a new image freshness service.
+func New(auth azure.Authorizer) (*Service, error) {
+	client, err := virtualmachineimages.NewClient(auth)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create virtualmachineimages client")
+	}
+	return &Service{
+		Marketplace: client,
+		Galleries:   galleryimageversions.New(auth),
+		Authorizer:  auth,
+	}, nil
+}
+
+// LatestVersion returns the version of an image and the latest version available in location with the same
+// Major.Minor, i.e. the latest build of the image. The latest version is never older than the current one, and both
+// are empty when the version of the image can't be compared, e.g. for images referenced by ID, "latest" versions or
+// images in a shared gallery of another tenant.
+// The latest versions found are reused until they are older than maxAge.
+func (s *Service) LatestVersion(ctx context.Context, image *infrav1.Image, location string, maxAge time.Duration) (current, latest string, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "imagefreshness.Service.LatestVersion")
+	defer done()
+
+	switch {
+	case image == nil:
+		return "", "", nil
+	case image.Marketplace != nil:
+		current = image.Marketplace.Version
+		prefix, ok := versionPrefix(current)
+		if !ok {
+			return "", "", nil
+		}
+		latest, err = s.latestMarketplaceVersion(ctx, image.Marketplace, location, prefix, maxAge)
+	case image.ComputeGallery != nil:
+		current = image.ComputeGallery.Version
+		prefix, ok := versionPrefix(current)
+		if !ok {
+			return "", "", nil
+		}
+		latest, err = s.Galleries.GetLatestVersion(ctx, image.ComputeGallery, location, prefix, maxAge)
+	case image.SharedGallery != nil && image.SharedGallery.TenantID == nil:
+		current = image.SharedGallery.Version
+		prefix, ok := versionPrefix(current)
+		if !ok {
+			return "", "", nil
+		}
+		gallery := &infrav1.AzureComputeGalleryImage{
+			Gallery:        image.SharedGallery.Gallery,
+			Name:           image.SharedGallery.Name,
+			Version:        image.SharedGallery.Version,
+			SubscriptionID: ptr.To(image.SharedGallery.SubscriptionID),
+			ResourceGroup:  ptr.To(image.SharedGallery.ResourceGroup),
+		}
+		latest, err = s.Galleries.GetLatestVersion(ctx, gallery, location, prefix, maxAge)
+	default:
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	// The version in use can be newer than the latest one found, e.g. when it is excluded from latest.
+	if build(latest) <= build(current) {
+		return current, current, nil
+	}
+	return current, latest, nil
+}
+
+// latestMarketplaceVersion returns the latest version of a marketplace image matching the "Major.Minor" prefix.
+func (s *Service) latestMarketplaceVersion(ctx context.Context, image *infrav1.AzureMarketplaceImage, location, prefix string, maxAge time.Duration) (string, error) {
+	var err error
+	doOnce.Do(func() {
+		versionsCache, err = ttllru.New(1024, 24*time.Hour)
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed creating LRU cache for marketplace image versions")
+	}
+
+	key := strings.Join([]string{s.HashKey(), location, image.Publisher, image.Offer, image.SKU, prefix}, "/")
+	if cached, ok := versionsCache.Get(key); ok {
+		if c, ok := cached.(cachedVersion); ok && time.Since(c.checked) < maxAge {
+			return c.version, nil
+		}
+	}
+
+	resp, err := s.Marketplace.List(ctx, location, image.Publisher, image.Offer, image.SKU)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list versions of image %s/%s/%s", image.Publisher, image.Offer, image.SKU)
+	}
+	var versions []string
+	for _, vmImage := range resp.VirtualMachineImageResourceArray {
+		if vmImage != nil && vmImage.Name != nil {
+			versions = append(versions, *vmImage.Name)
+		}
+	}
+	version, ok := galleryimageversions.LatestVersion(versions, prefix)
+	if !ok {
+		return "", errors.Errorf("no version %s.x of image %s/%s/%s is available in location %s", prefix, image.Publisher, image.Offer, image.SKU, location)
+	}
+	_ = versionsCache.Add(key, cachedVersion{version: version, checked: time.Now()})
+	return version, nil
+}
+
+// versionPrefix returns the "Major.Minor" prefix of a "Major.Minor.Build" image version.
+func versionPrefix(version string) (string, bool) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return "", false
+	}
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil {
+			return "", false
+		}
+	}
+	return parts[0] + "." + parts[1], true
+}
+
+// build returns the build number of a "Major.Minor.Build" image version, or -1 if it is malformed.
+func build(version string) int {
+	n, err := strconv.Atoi(version[strings.LastIndex(version, ".")+1:])
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagefreshness
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/galleryimageversions"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/galleryimageversions/mock_galleryimageversions"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages/mock_virtualmachineimages"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/fakearm"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func marketplaceVersions(versions ...string) armcompute.VirtualMachineImagesClientListResponse {
+	var resp armcompute.VirtualMachineImagesClientListResponse
+	for _, v := range versions {
+		resp.VirtualMachineImageResourceArray = append(resp.VirtualMachineImageResourceArray, &armcompute.VirtualMachineImageResource{Name: ptr.To(v)})
+	}
+	return resp
+}
+
+func TestLatestVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		image       *infrav1.Image
+		expect      func(m *mock_virtualmachineimages.MockClientMockRecorder, g *mock_galleryimageversions.MockClientMockRecorder)
+		wantCurrent string
+		wantLatest  string
+		wantErr     bool
+	}{
+		{
+			name:  "nil image",
+			image: nil,
+		},
+		{
+			name:  "image referenced by ID",
+			image: &infrav1.Image{ID: ptr.To("/subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/images/image")},
+		},
+		{
+			name: "marketplace image with a latest version",
+			image: &infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{
+				ImagePlan: infrav1.ImagePlan{Publisher: "pub", Offer: "offer", SKU: "sku-latest"},
+				Version:   "latest",
+			}},
+		},
+		{
+			name: "outdated marketplace image",
+			image: &infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{
+				ImagePlan: infrav1.ImagePlan{Publisher: "pub", Offer: "offer", SKU: "sku-outdated"},
+				Version:   "128.1.20240101",
+			}},
+			expect: func(m *mock_virtualmachineimages.MockClientMockRecorder, _ *mock_galleryimageversions.MockClientMockRecorder) {
+				m.List(gomockinternal.AContext(), "eastus", "pub", "offer", "sku-outdated").
+					Return(marketplaceVersions("128.1.20240101", "128.1.20240301", "128.2.20240401"), nil)
+			},
+			wantCurrent: "128.1.20240101",
+			wantLatest:  "128.1.20240301",
+		},
+		{
+			name: "up to date marketplace image",
+			image: &infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{
+				ImagePlan: infrav1.ImagePlan{Publisher: "pub", Offer: "offer", SKU: "sku-current"},
+				Version:   "128.1.20240301",
+			}},
+			expect: func(m *mock_virtualmachineimages.MockClientMockRecorder, _ *mock_galleryimageversions.MockClientMockRecorder) {
+				m.List(gomockinternal.AContext(), "eastus", "pub", "offer", "sku-current").
+					Return(marketplaceVersions("128.1.20240101", "128.1.20240301"), nil)
+			},
+			wantCurrent: "128.1.20240301",
+			wantLatest:  "128.1.20240301",
+		},
+		{
+			name: "failure to list marketplace image versions",
+			image: &infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{
+				ImagePlan: infrav1.ImagePlan{Publisher: "pub", Offer: "offer", SKU: "sku-error"},
+				Version:   "128.1.20240301",
+			}},
+			expect: func(m *mock_virtualmachineimages.MockClientMockRecorder, _ *mock_galleryimageversions.MockClientMockRecorder) {
+				m.List(gomockinternal.AContext(), "eastus", "pub", "offer", "sku-error").
+					Return(armcompute.VirtualMachineImagesClientListResponse{}, errors.New("boom"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "compute gallery image newer than the latest version found",
+			image: &infrav1.Image{ComputeGallery: &infrav1.AzureComputeGalleryImage{
+				Gallery: "gallery", Name: "image-excluded", Version: "1.28.5",
+			}},
+			expect: func(_ *mock_virtualmachineimages.MockClientMockRecorder, g *mock_galleryimageversions.MockClientMockRecorder) {
+				g.ListVersions(gomockinternal.AContext(), gomock.Any(), "eastus").Return([]string{"1.28.3", "1.28.4"}, nil)
+			},
+			wantCurrent: "1.28.5",
+			wantLatest:  "1.28.5",
+		},
+		{
+			name: "outdated shared gallery image",
+			image: &infrav1.Image{SharedGallery: &infrav1.AzureSharedGalleryImage{
+				SubscriptionID: "123", ResourceGroup: "rg", Gallery: "gallery", Name: "image-shared", Version: "1.28.3",
+			}},
+			expect: func(_ *mock_virtualmachineimages.MockClientMockRecorder, g *mock_galleryimageversions.MockClientMockRecorder) {
+				g.ListVersions(gomockinternal.AContext(), &infrav1.AzureComputeGalleryImage{
+					Gallery: "gallery", Name: "image-shared", Version: "1.28.3",
+					SubscriptionID: ptr.To("123"), ResourceGroup: ptr.To("rg"),
+				}, "eastus").Return([]string{"1.28.3", "1.28.4"}, nil)
+			},
+			wantCurrent: "1.28.3",
+			wantLatest:  "1.28.4",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			server := fakearm.NewServer()
+			marketplaceMock := mock_virtualmachineimages.NewMockClient(mockCtrl)
+			galleryMock := mock_galleryimageversions.NewMockClient(mockCtrl)
+			if tc.expect != nil {
+				tc.expect(marketplaceMock.EXPECT(), galleryMock.EXPECT())
+			}
+			s := &Service{
+				Marketplace: marketplaceMock,
+				Galleries:   &galleryimageversions.Service{Client: galleryMock, Authorizer: server.Authorizer("123")},
+				Authorizer:  server.Authorizer("123"),
+			}
+
+			current, latest, err := s.LatestVersion(context.TODO(), tc.image, "eastus", time.Hour)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(current).To(Equal(tc.wantCurrent))
+			g.Expect(latest).To(Equal(tc.wantLatest))
+		})
+	}
+}
@@ -70,16 +70,12 @@ func (ac *azureClient) List(ctx context.Context, resourceGroupName, lbName strin
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "inboundnatrules.azureClient.List")
 	defer done()
 
-	var natRules []armnetwork.InboundNatRule
 	pager := ac.inboundnatrules.NewListPager(resourceGroupName, lbName, nil)
-	for pager.More() {
-		nextResult, err := pager.NextPage(ctx)
-		if err != nil {
-			return natRules, errors.Wrap(err, "could not iterate inbound NAT rules")
-		}
-		for _, natRule := range nextResult.Value {
-			natRules = append(natRules, *natRule)
-		}
+	natRules, err := azure.CollectPages(ctx, pager, func(page armnetwork.InboundNatRulesClientListResponse) []*armnetwork.InboundNatRule {
+		return page.Value
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not iterate inbound NAT rules")
 	}
 
 	return natRules, nil
@@ -21,6 +21,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
@@ -36,6 +37,7 @@ type InboundNatScope interface {
 	azure.AsyncStatusUpdater
 	APIServerLBName() string
 	InboundNatSpecs() []azure.ResourceSpecGetter
+	SetSSHFrontendPort(port int32)
 }
 
 // Service provides operations on Azure resources.
@@ -91,8 +93,13 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	}
 
 	portsInUse := make(map[int32]struct{})
+	existingPorts := make(map[string]int32, len(existingRules))
 	for _, rule := range existingRules {
+		if rule.Properties == nil || rule.Properties.FrontendPort == nil {
+			continue
+		}
 		portsInUse[*rule.Properties.FrontendPort] = struct{}{} // Mark frontend port as in use
+		existingPorts[ptr.Deref(rule.Name, "")] = *rule.Properties.FrontendPort
 	}
 
 	// We go through the list of InboundNatSpecs to reconcile each one, independently of the result of the previous one.
@@ -100,10 +107,13 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
 	var result error
 	for _, spec := range specs {
-		// Find an available SSH port for the rule.
-		sshFrontendPort, err := getAvailableSSHFrontendPort(portsInUse)
-		if err != nil {
-			return errors.Wrapf(err, "failed to find available SSH Frontend port for NAT Rule %s in load balancer %s", spec.ResourceName(), spec.OwnerResourceName())
+		// Existing rules keep their port, new ones get an available SSH port.
+		sshFrontendPort, exists := existingPorts[spec.ResourceName()]
+		if !exists {
+			sshFrontendPort, err = getAvailableSSHFrontendPort(portsInUse)
+			if err != nil {
+				return errors.Wrapf(err, "failed to find available SSH Frontend port for NAT Rule %s in load balancer %s", spec.ResourceName(), spec.OwnerResourceName())
+			}
 		}
 		natRule, ok := spec.(*InboundNatSpec)
 		if !ok {
@@ -116,7 +126,9 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
 			}
+			continue
 		}
+		s.Scope.SetSSHFrontendPort(sshFrontendPort)
 	}
 
 	s.Scope.UpdatePutStatus(infrav1.InboundNATRulesReadyCondition, serviceName, result)
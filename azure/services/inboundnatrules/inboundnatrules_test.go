@@ -113,7 +113,9 @@ func TestReconcileInboundNATRule(t *testing.T) {
 				s.InboundNatSpecs().Return([]azure.ResourceSpecGetter{getFakeNatSpecWithoutPort(fakeNatSpec), getFakeNatSpecWithoutPort(fakeNatSpec2)})
 				gomock.InOrder(
 					r.CreateOrUpdateResource(gomockinternal.AContext(), getFakeNatSpecWithPort(fakeNatSpec, 22), serviceName).Return(nil, nil),
+					s.SetSSHFrontendPort(int32(22)),
 					r.CreateOrUpdateResource(gomockinternal.AContext(), getFakeNatSpecWithPort(fakeNatSpec2, 2201), serviceName).Return(nil, nil),
+					s.SetSSHFrontendPort(int32(2201)),
 					s.UpdatePutStatus(infrav1.InboundNATRulesReadyCondition, serviceName, nil),
 				)
 			},
@@ -130,6 +132,32 @@ func TestReconcileInboundNATRule(t *testing.T) {
 				s.InboundNatSpecs().Return([]azure.ResourceSpecGetter{getFakeNatSpecWithoutPort(fakeNatSpec)})
 				gomock.InOrder(
 					r.CreateOrUpdateResource(gomockinternal.AContext(), getFakeNatSpecWithPort(fakeNatSpec, 2202), serviceName).Return(nil, nil),
+					s.SetSSHFrontendPort(int32(2202)),
+					s.UpdatePutStatus(infrav1.InboundNATRulesReadyCondition, serviceName, nil),
+				)
+			},
+		},
+		{
+			name:          "existing NAT rule keeps its port",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockclientMockRecorder,
+				r *mock_async.MockReconcilerMockRecorder) {
+				s.ResourceGroup().AnyTimes().Return(fakeGroupName)
+				s.APIServerLBName().AnyTimes().Return("my-lb")
+				existingRules := append([]armnetwork.InboundNatRule{
+					{
+						Name: ptr.To("my-machine-1"),
+						Properties: &armnetwork.InboundNatRulePropertiesFormat{
+							FrontendPort: ptr.To[int32](2205),
+						},
+					},
+				}, fakeExistingRules...)
+				m.List(gomockinternal.AContext(), fakeGroupName, "my-lb").Return(existingRules, nil)
+				s.InboundNatSpecs().Return([]azure.ResourceSpecGetter{getFakeNatSpecWithoutPort(fakeNatSpec)})
+				gomock.InOrder(
+					r.CreateOrUpdateResource(gomockinternal.AContext(), getFakeNatSpecWithPort(fakeNatSpec, 2205), serviceName).Return(nil, nil),
+					s.SetSSHFrontendPort(int32(2205)),
 					s.UpdatePutStatus(infrav1.InboundNATRulesReadyCondition, serviceName, nil),
 				)
 			},
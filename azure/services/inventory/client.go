@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	ListTaggedResources(ctx context.Context, tagName, tagValue string) ([]Resource, error)
+	ExportTemplate(ctx context.Context, resourceGroup string, resourceIDs []string) (ExportedTemplate, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	resources      *armresources.Client
+	resourceGroups *armresources.ResourceGroupsClient
+}
+
+var _ Client = (*AzureClient)(nil)
+
+// NewClient creates a new inventory client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create inventory client options")
+	}
+	factory, err := armresources.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armresources client factory")
+	}
+	return &AzureClient{
+		resources:      factory.NewClient(),
+		resourceGroups: factory.NewResourceGroupsClient(),
+	}, nil
+}
+
+// ListTaggedResources returns the resource groups and resources of the subscription with the given tag.
+func (ac *AzureClient) ListTaggedResources(ctx context.Context, tagName, tagValue string) ([]Resource, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "inventory.AzureClient.ListTaggedResources")
+	defer done()
+
+	filter := fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", tagName, tagValue)
+	var resources []Resource
+
+	groupsPager := ac.resourceGroups.NewListPager(&armresources.ResourceGroupsClientListOptions{Filter: ptr.To(filter)})
+	for groupsPager.More() {
+		page, err := groupsPager.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list resource groups")
+		}
+		for _, group := range page.Value {
+			if group == nil {
+				continue
+			}
+			resources = append(resources, Resource{
+				ID:            ptr.Deref(group.ID, ""),
+				Type:          ptr.Deref(group.Type, ""),
+				ResourceGroup: ptr.Deref(group.Name, ""),
+				Location:      ptr.Deref(group.Location, ""),
+			})
+		}
+	}
+
+	resourcesPager := ac.resources.NewListPager(&armresources.ClientListOptions{
+		Filter: ptr.To(filter),
+		Expand: ptr.To("createdTime"),
+	})
+	for resourcesPager.More() {
+		page, err := resourcesPager.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list resources")
+		}
+		for _, resource := range page.Value {
+			if resource == nil {
+				continue
+			}
+			r := Resource{
+				ID:          ptr.Deref(resource.ID, ""),
+				Type:        ptr.Deref(resource.Type, ""),
+				Location:    ptr.Deref(resource.Location, ""),
+				CreatedTime: resource.CreatedTime,
+			}
+			if resource.SKU != nil {
+				r.SKU = ptr.Deref(resource.SKU.Name, "")
+			}
+			if id, err := arm.ParseResourceID(r.ID); err == nil {
+				r.ResourceGroup = id.ResourceGroupName
+			}
+			resources = append(resources, r)
+		}
+	}
+	return resources, nil
+}
+
+// ExportTemplate returns the ARM template describing the current configuration of resources of a resource group.
+func (ac *AzureClient) ExportTemplate(ctx context.Context, resourceGroup string, resourceIDs []string) (ExportedTemplate, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "inventory.AzureClient.ExportTemplate")
+	defer done()
+
+	request := armresources.ExportTemplateRequest{
+		Options: ptr.To("IncludeParameterDefaultValue,IncludeComments"),
+	}
+	for _, id := range resourceIDs {
+		request.Resources = append(request.Resources, ptr.To(id))
+	}
+	poller, err := ac.resourceGroups.BeginExportTemplate(ctx, resourceGroup, request, nil)
+	if err != nil {
+		return ExportedTemplate{}, errors.Wrapf(err, "failed to export the template of resource group %s", resourceGroup)
+	}
+	resp, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return ExportedTemplate{}, errors.Wrapf(err, "failed to export the template of resource group %s", resourceGroup)
+	}
+	template, err := json.Marshal(resp.Template)
+	if err != nil {
+		return ExportedTemplate{}, errors.Wrap(err, "failed to marshal exported template")
+	}
+	exported := ExportedTemplate{Template: template}
+	// Resources whose type can't be exported are left out of the template and reported in its error.
+	if resp.Error != nil {
+		messages := []string{ptr.Deref(resp.Error.Message, "")}
+		for _, detail := range resp.Error.Details {
+			if detail != nil {
+				messages = append(messages, ptr.Deref(detail.Message, ""))
+			}
+		}
+		exported.Error = strings.Join(messages, "; ")
+	}
+	return exported, nil
+}
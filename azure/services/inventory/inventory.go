@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Resource is an Azure resource, or resource group, owned by a cluster.
+type Resource struct {
+	ID            string     `json:"id"`
+	Type          string     `json:"type"`
+	ResourceGroup string     `json:"resourceGroup"`
+	Location      string     `json:"location"`
+	SKU           string     `json:"sku,omitempty"`
+	CreatedTime   *time.Time `json:"createdTime,omitempty"`
+}
+
+// ExportedTemplate is the ARM template of resources of a resource group.
+type ExportedTemplate struct {
+	// Template is the JSON ARM template.
+	Template json.RawMessage
+	// Error describes the resources left out of the template, if any.
+	Error string
+}
+
+// Count is the number of resources of a type in a resource group.
+type Count struct {
+	ResourceGroup string `json:"resourceGroup"`
+	Type          string `json:"type"`
+	Count         int    `json:"count"`
+}
+
+// ListOwnedResources returns the resource groups and resources of the subscription of client tagged as owned by the
+// cluster, sorted by ID. Unlike the resources found in the spec of the cluster, it also finds those left over outside
+// of its resource groups.
+func ListOwnedResources(ctx context.Context, client Client, clusterName string) ([]Resource, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "inventory.ListOwnedResources")
+	defer done()
+
+	resources, err := client.ListTaggedResources(ctx, infrav1.ClusterTagKey(clusterName), string(infrav1.ResourceLifecycleOwned))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return strings.ToLower(resources[i].ID) < strings.ToLower(resources[j].ID)
+	})
+	return resources, nil
+}
+
+// Summarize counts resources by resource group and type. Resource group names and resource types are compared
+// case-insensitively, as Azure does, and reported in lower case.
+func Summarize(resources []Resource) []Count {
+	counts := map[Count]int{}
+	for _, r := range resources {
+		key := Count{ResourceGroup: strings.ToLower(r.ResourceGroup), Type: strings.ToLower(r.Type)}
+		counts[key]++
+	}
+	summary := make([]Count, 0, len(counts))
+	for key, n := range counts {
+		key.Count = n
+		summary = append(summary, key)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].ResourceGroup != summary[j].ResourceGroup {
+			return summary[i].ResourceGroup < summary[j].ResourceGroup
+		}
+		return summary[i].Type < summary[j].Type
+	})
+	return summary
+}
+
+// resourceGroupType is the type of the resource group entries listed by ListOwnedResources.
+const resourceGroupType = "Microsoft.Resources/resourceGroups"
+
+// ResourceGroupTemplate is the ARM template of the resources owned by a cluster in a resource group.
+type ResourceGroupTemplate struct {
+	ResourceGroup string
+	ExportedTemplate
+}
+
+// ExportTemplates returns the ARM templates of resources, one per resource group sorted by name. The resource groups
+// themselves are not part of the templates, which are meant to be deployed into existing resource groups.
+func ExportTemplates(ctx context.Context, client Client, resources []Resource) ([]ResourceGroupTemplate, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "inventory.ExportTemplates")
+	defer done()
+
+	groups := map[string][]string{}
+	names := map[string]string{}
+	for _, r := range resources {
+		if strings.EqualFold(r.Type, resourceGroupType) || r.ResourceGroup == "" {
+			continue
+		}
+		key := strings.ToLower(r.ResourceGroup)
+		if _, ok := names[key]; !ok {
+			names[key] = r.ResourceGroup
+		}
+		groups[key] = append(groups[key], r.ID)
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	templates := make([]ResourceGroupTemplate, 0, len(keys))
+	for _, key := range keys {
+		exported, err := client.ExportTemplate(ctx, names[key], groups[key])
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, ResourceGroupTemplate{ResourceGroup: names[key], ExportedTemplate: exported})
+	}
+	return templates, nil
+}
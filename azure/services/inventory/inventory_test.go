@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inventory"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inventory/mock_inventory"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func TestListOwnedResources(t *testing.T) {
+	tests := []struct {
+		name    string
+		expect  func(m *mock_inventory.MockClientMockRecorder)
+		want    []inventory.Resource
+		wantErr bool
+	}{
+		{
+			name: "resources are sorted by ID",
+			expect: func(m *mock_inventory.MockClientMockRecorder) {
+				m.ListTaggedResources(gomockinternal.AContext(), "sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster", "owned").Return([]inventory.Resource{
+					{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"},
+					{ID: "/subscriptions/123/resourceGroups/my-rg"},
+					{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk"},
+				}, nil)
+			},
+			want: []inventory.Resource{
+				{ID: "/subscriptions/123/resourceGroups/my-rg"},
+				{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk"},
+				{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"},
+			},
+		},
+		{
+			name: "failure to list resources",
+			expect: func(m *mock_inventory.MockClientMockRecorder) {
+				m.ListTaggedResources(gomockinternal.AContext(), "sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster", "owned").Return(nil, errors.New("boom"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_inventory.NewMockClient(mockCtrl)
+			tc.expect(clientMock.EXPECT())
+
+			resources, err := inventory.ListOwnedResources(context.TODO(), clientMock, "my-cluster")
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(resources).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	g := NewWithT(t)
+	resources := []inventory.Resource{
+		{ResourceGroup: "my-rg", Type: "Microsoft.Resources/resourceGroups"},
+		{ResourceGroup: "my-rg", Type: "Microsoft.Compute/virtualMachines"},
+		{ResourceGroup: "MY-RG", Type: "Microsoft.Compute/virtualMachines"},
+		{ResourceGroup: "my-rg", Type: "Microsoft.Compute/disks"},
+		{ResourceGroup: "leaked-rg", Type: "Microsoft.Compute/disks"},
+	}
+	g.Expect(inventory.Summarize(resources)).To(Equal([]inventory.Count{
+		{ResourceGroup: "leaked-rg", Type: "microsoft.compute/disks", Count: 1},
+		{ResourceGroup: "my-rg", Type: "microsoft.compute/disks", Count: 1},
+		{ResourceGroup: "my-rg", Type: "microsoft.compute/virtualmachines", Count: 2},
+		{ResourceGroup: "my-rg", Type: "microsoft.resources/resourcegroups", Count: 1},
+	}))
+	g.Expect(inventory.Summarize(nil)).To(BeEmpty())
+}
+
+func TestExportTemplates(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_inventory.NewMockClient(mockCtrl)
+
+	resources := []inventory.Resource{
+		{ID: "/subscriptions/123/resourceGroups/my-rg", ResourceGroup: "my-rg", Type: "Microsoft.Resources/resourceGroups"},
+		{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/vm-0", ResourceGroup: "my-rg", Type: "Microsoft.Compute/virtualMachines"},
+		{ID: "/subscriptions/123/resourceGroups/MY-RG/providers/Microsoft.Compute/disks/disk-0", ResourceGroup: "MY-RG", Type: "Microsoft.Compute/disks"},
+		{ID: "/subscriptions/123/resourceGroups/leaked-rg/providers/Microsoft.Compute/disks/disk-1", ResourceGroup: "leaked-rg", Type: "Microsoft.Compute/disks"},
+	}
+	clientMock.EXPECT().ExportTemplate(gomockinternal.AContext(), "leaked-rg", []string{resources[3].ID}).
+		Return(inventory.ExportedTemplate{Template: []byte(`{"resources":[]}`), Error: "unsupported"}, nil)
+	clientMock.EXPECT().ExportTemplate(gomockinternal.AContext(), "my-rg", []string{resources[1].ID, resources[2].ID}).
+		Return(inventory.ExportedTemplate{Template: []byte(`{"resources":[{}]}`)}, nil)
+
+	templates, err := inventory.ExportTemplates(context.TODO(), clientMock, resources)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(templates).To(Equal([]inventory.ResourceGroupTemplate{
+		{ResourceGroup: "leaked-rg", ExportedTemplate: inventory.ExportedTemplate{Template: []byte(`{"resources":[]}`), Error: "unsupported"}},
+		{ResourceGroup: "my-rg", ExportedTemplate: inventory.ExportedTemplate{Template: []byte(`{"resources":[{}]}`)}},
+	}))
+
+	clientMock.EXPECT().ExportTemplate(gomockinternal.AContext(), "my-rg", gomock.Any()).Return(inventory.ExportedTemplate{}, errors.New("boom"))
+	_, err = inventory.ExportTemplates(context.TODO(), clientMock, resources[:2])
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,86 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+//
+// Generated by this command:
+//
+//	mockgen -destination client_mock.go -package mock_inventory -source ../client.go Client
+//
+// Package mock_inventory is a generated GoMock package.
+package mock_inventory
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	inventory "sigs.k8s.io/cluster-api-provider-azure/azure/services/inventory"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// ExportTemplate mocks base method.
+func (m *MockClient) ExportTemplate(ctx context.Context, resourceGroup string, resourceIDs []string) (inventory.ExportedTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTemplate", ctx, resourceGroup, resourceIDs)
+	ret0, _ := ret[0].(inventory.ExportedTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportTemplate indicates an expected call of ExportTemplate.
+func (mr *MockClientMockRecorder) ExportTemplate(ctx, resourceGroup, resourceIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTemplate", reflect.TypeOf((*MockClient)(nil).ExportTemplate), ctx, resourceGroup, resourceIDs)
+}
+
+// ListTaggedResources mocks base method.
+func (m *MockClient) ListTaggedResources(ctx context.Context, tagName, tagValue string) ([]inventory.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaggedResources", ctx, tagName, tagValue)
+	ret0, _ := ret[0].([]inventory.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaggedResources indicates an expected call of ListTaggedResources.
+func (mr *MockClientMockRecorder) ListTaggedResources(ctx, tagName, tagValue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaggedResources", reflect.TypeOf((*MockClient)(nil).ListTaggedResources), ctx, tagName, tagValue)
+}
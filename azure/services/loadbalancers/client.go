@@ -18,11 +18,13 @@ package loadbalancers
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
@@ -32,6 +34,7 @@ import (
 // azureClient contains the Azure go-sdk Client.
 type azureClient struct {
 	loadbalancers *armnetwork.LoadBalancersClient
+	interfaces    *armnetwork.InterfacesClient
 	auth          azure.Authorizer
 }
 
@@ -46,7 +49,7 @@ func newClient(auth azure.Authorizer) (*azureClient, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create armnetwork client factory")
 	}
-	return &azureClient{factory.NewLoadBalancersClient(), auth}, nil
+	return &azureClient{factory.NewLoadBalancersClient(), factory.NewInterfacesClient(), auth}, nil
 }
 
 // Get gets the specified load balancer.
@@ -142,3 +145,60 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 	// if the operation completed, return nil poller.
 	return nil, err
 }
+
+// RemoveLoadBalancerReferences removes the backend pools and inbound NAT rules of the load balancer with the given ID
+// from the IP configurations of a network interface.
+func (ac *azureClient) RemoveLoadBalancerReferences(ctx context.Context, resourceGroup, nicName, lbID string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "loadbalancers.azureClient.RemoveLoadBalancerReferences")
+	defer done()
+
+	resp, err := ac.interfaces.Get(ctx, resourceGroup, nicName, nil)
+	if err != nil {
+		return err
+	}
+	nic := resp.Interface
+	if nic.Properties == nil {
+		return nil
+	}
+	update := false
+	for _, ipConfig := range nic.Properties.IPConfigurations {
+		if ipConfig.Properties == nil {
+			continue
+		}
+		pools := make([]*armnetwork.BackendAddressPool, 0, len(ipConfig.Properties.LoadBalancerBackendAddressPools))
+		for _, pool := range ipConfig.Properties.LoadBalancerBackendAddressPools {
+			if isChildOf(ptr.Deref(pool.ID, ""), lbID) {
+				update = true
+				continue
+			}
+			pools = append(pools, pool)
+		}
+		ipConfig.Properties.LoadBalancerBackendAddressPools = pools
+		rules := make([]*armnetwork.InboundNatRule, 0, len(ipConfig.Properties.LoadBalancerInboundNatRules))
+		for _, rule := range ipConfig.Properties.LoadBalancerInboundNatRules {
+			if isChildOf(ptr.Deref(rule.ID, ""), lbID) {
+				update = true
+				continue
+			}
+			rules = append(rules, rule)
+		}
+		ipConfig.Properties.LoadBalancerInboundNatRules = rules
+	}
+	if !update {
+		return nil
+	}
+
+	poller, err := ac.interfaces.BeginCreateOrUpdate(ctx, resourceGroup, nicName, nic, nil)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+	_, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
+	return err
+}
+
+// isChildOf reports whether the resource ID id is a child of the resource with ID parentID.
+func isChildOf(id, parentID string) bool {
+	return parentID != "" && strings.HasPrefix(strings.ToLower(id), strings.ToLower(parentID)+"/")
+}
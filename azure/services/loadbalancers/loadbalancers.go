@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
@@ -30,8 +31,9 @@ import (
 const (
 	serviceName           = "loadbalancers"
 	httpsProbe            = "HTTPSProbe"
+	healthProbe           = "HealthProbe"
 	httpsProbeRequestPath = "/readyz"
-	lbRuleHTTPS           = "LBRuleHTTPS"
+	lbRuleHTTPS           = infrav1.APIServerLBRuleName
 	outboundNAT           = "OutboundNATAllProtocols"
 )
 
@@ -46,6 +48,7 @@ type LBScope interface {
 type Service struct {
 	Scope LBScope
 	async.Reconciler
+	skuMigrator skuMigrationClient
 }
 
 // New creates a new service.
@@ -58,6 +61,7 @@ func New(scope LBScope) (*Service, error) {
 		Scope: scope,
 		Reconciler: async.New[armnetwork.LoadBalancersClientCreateOrUpdateResponse,
 			armnetwork.LoadBalancersClientDeleteResponse](scope, client, client),
+		skuMigrator: client,
 	}, nil
 }
 
@@ -84,7 +88,12 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
 	var result error
 	for _, lbSpec := range specs {
-		if _, err := s.CreateOrUpdateResource(ctx, lbSpec, serviceName); err != nil {
+		_, err := s.CreateOrUpdateResource(ctx, lbSpec, serviceName)
+		var basicSKU *basicSKUError
+		if spec, ok := lbSpec.(*LBSpec); ok && errors.As(err, &basicSKU) {
+			err = s.migrateBasicSKU(ctx, spec, basicSKU.existing)
+		}
+		if err != nil {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
 			}
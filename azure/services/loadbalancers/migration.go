@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// skuMigrationRequeue is how long to wait before resuming the migration of a Basic load balancer.
+const skuMigrationRequeue = 15 * time.Second
+
+// skuMigrationClient performs the operations of the migration of a Basic load balancer to the Standard SKU which
+// aren't on the load balancer itself.
+type skuMigrationClient interface {
+	RemoveLoadBalancerReferences(ctx context.Context, resourceGroup, nicName, lbID string) error
+}
+
+// basicSKUError is returned when a Basic load balancer must be replaced with a Standard one.
+type basicSKUError struct {
+	existing armnetwork.LoadBalancer
+}
+
+func (e *basicSKUError) Error() string {
+	return fmt.Sprintf("load balancer %s uses the Basic SKU and must be replaced with a Standard one", ptr.Deref(e.existing.Name, ""))
+}
+
+// migrateBasicSKU replaces an existing Basic load balancer with a Standard one. Azure doesn't allow changing the SKU of
+// a load balancer in place, nor associating a Basic public IP with a Standard load balancer or a network interface
+// with load balancers of different SKUs, so the migration:
+//  1. removes the network interfaces from the backend pools and inbound NAT rules of the Basic load balancer,
+//  2. deletes the Basic load balancer.
+//
+// The public IPs service then upgrades the now unassociated public IPs to the Standard SKU in place, keeping their
+// address and DNS name so that the control plane endpoint doesn't change, the Standard load balancer is created with
+// the same name, and the network interfaces service adds the network interfaces back to its backend pool.
+// Every step is idempotent so that an interrupted migration resumes at the next reconciliation.
+func (s *Service) migrateBasicSKU(ctx context.Context, spec *LBSpec, existing armnetwork.LoadBalancer) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "loadbalancers.Service.migrateBasicSKU")
+	defer done()
+
+	log.Info("migrating load balancer from the Basic to the Standard SKU", "loadBalancer", spec.Name)
+	lbID := ptr.Deref(existing.ID, azure.LoadBalancerID(spec.SubscriptionID, spec.ResourceGroup, spec.Name))
+	for _, ipConfigID := range backendIPConfigIDs(existing) {
+		nic, err := parentNetworkInterface(ipConfigID)
+		if err != nil {
+			return azure.WithTerminalError(errors.Wrapf(err, "cannot migrate load balancer %s", spec.Name))
+		}
+		if err := s.skuMigrator.RemoveLoadBalancerReferences(ctx, nic.ResourceGroupName, nic.Name, lbID); err != nil {
+			return azure.WithTransientError(errors.Wrapf(err, "failed to remove network interface %s from load balancer %s", nic.Name, spec.Name), skuMigrationRequeue)
+		}
+	}
+	if err := s.DeleteResource(ctx, spec, serviceName); err != nil {
+		return err
+	}
+	// The Standard load balancer is created once the public IPs have been upgraded.
+	return azure.WithTransientError(errors.Errorf("deleted Basic load balancer %s to replace it with a Standard one", spec.Name), skuMigrationRequeue)
+}
+
+// backendIPConfigIDs returns the IDs of the network interface IP configurations referencing the backend pools or
+// inbound NAT rules of a load balancer.
+func backendIPConfigIDs(lb armnetwork.LoadBalancer) []string {
+	var ids []string
+	seen := map[string]bool{}
+	add := func(id *string) {
+		if id == nil || seen[strings.ToLower(*id)] {
+			return
+		}
+		seen[strings.ToLower(*id)] = true
+		ids = append(ids, *id)
+	}
+	if lb.Properties == nil {
+		return ids
+	}
+	for _, pool := range lb.Properties.BackendAddressPools {
+		if pool.Properties == nil {
+			continue
+		}
+		for _, ipConfig := range pool.Properties.BackendIPConfigurations {
+			add(ipConfig.ID)
+		}
+	}
+	for _, rule := range lb.Properties.InboundNatRules {
+		if rule.Properties != nil && rule.Properties.BackendIPConfiguration != nil {
+			add(rule.Properties.BackendIPConfiguration.ID)
+		}
+	}
+	return ids
+}
+
+// parentNetworkInterface returns the ID of the network interface of an IP configuration. Only standalone network
+// interfaces are supported, as CAPZ doesn't put scale set instances behind the API server load balancer.
+func parentNetworkInterface(ipConfigID string) (*arm.ResourceID, error) {
+	id, err := arm.ParseResourceID(ipConfigID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse IP configuration ID %s", ipConfigID)
+	}
+	if id.Parent == nil || !strings.EqualFold(id.Parent.ResourceType.String(), "Microsoft.Network/networkInterfaces") {
+		return nil, errors.Errorf("backend %s is not the IP configuration of a network interface", ipConfigID)
+	}
+	return id.Parent, nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers/mock_loadbalancers"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const fakeLBID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb"
+
+func newBasicLB(ipConfigIDs ...string) armnetwork.LoadBalancer {
+	backendIPConfigs := make([]*armnetwork.InterfaceIPConfiguration, 0, len(ipConfigIDs))
+	for _, id := range ipConfigIDs {
+		backendIPConfigs = append(backendIPConfigs, &armnetwork.InterfaceIPConfiguration{ID: ptr.To(id)})
+	}
+	return armnetwork.LoadBalancer{
+		ID:   ptr.To(fakeLBID),
+		Name: ptr.To("my-publiclb"),
+		SKU:  &armnetwork.LoadBalancerSKU{Name: ptr.To(armnetwork.LoadBalancerSKUNameBasic)},
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			BackendAddressPools: []*armnetwork.BackendAddressPool{
+				{
+					Name: ptr.To("my-publiclb-backendPool"),
+					Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+						BackendIPConfigurations: backendIPConfigs,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestMigrateBasicSKU(t *testing.T) {
+	testcases := []struct {
+		name          string
+		existing      armnetwork.LoadBalancer
+		expectedError string
+		expect        func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_loadbalancers.MockskuMigrationClientMockRecorder)
+	}{
+		{
+			name: "remove network interfaces and delete the Basic load balancer",
+			existing: newBasicLB(
+				"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/cp-0-nic/ipConfigurations/pipConfig",
+				"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/cp-1-nic/ipConfigurations/pipConfig",
+			),
+			expectedError: "deleted Basic load balancer my-publiclb to replace it with a Standard one. Object will be requeued after 15s",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_loadbalancers.MockskuMigrationClientMockRecorder) {
+				m.RemoveLoadBalancerReferences(gomockinternal.AContext(), "my-rg", "cp-0-nic", fakeLBID).Return(nil)
+				m.RemoveLoadBalancerReferences(gomockinternal.AContext(), "my-rg", "cp-1-nic", fakeLBID).Return(nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakePublicAPILBSpec, serviceName).Return(nil)
+			},
+		},
+		{
+			name:          "wait for the deletion of the Basic load balancer",
+			existing:      newBasicLB(),
+			expectedError: "operation type DELETE on Azure resource my-rg/my-publiclb is not done",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_loadbalancers.MockskuMigrationClientMockRecorder) {
+				r.DeleteResource(gomockinternal.AContext(), &fakePublicAPILBSpec, serviceName).Return(azure.NewOperationNotDoneError(&infrav1.Future{Type: infrav1.DeleteFuture, ResourceGroup: "my-rg", Name: "my-publiclb"}))
+			},
+		},
+		{
+			name:          "fail to remove a network interface from the Basic load balancer",
+			existing:      newBasicLB("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/cp-0-nic/ipConfigurations/pipConfig"),
+			expectedError: "failed to remove network interface cp-0-nic from load balancer my-publiclb: #: Internal Server Error: StatusCode=500. Object will be requeued after 15s",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_loadbalancers.MockskuMigrationClientMockRecorder) {
+				m.RemoveLoadBalancerReferences(gomockinternal.AContext(), "my-rg", "cp-0-nic", fakeLBID).Return(internalError)
+			},
+		},
+		{
+			name:          "scale set instances are not supported",
+			existing:      newBasicLB("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0/networkInterfaces/nic/ipConfigurations/pipConfig"),
+			expectedError: "reconcile error that cannot be recovered occurred: cannot migrate load balancer my-publiclb: backend /subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0/networkInterfaces/nic/ipConfigurations/pipConfig is not the IP configuration of a network interface. Object will not be requeued",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_loadbalancers.MockskuMigrationClientMockRecorder) {
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_loadbalancers.NewMockLBScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+			migratorMock := mock_loadbalancers.NewMockskuMigrationClient(mockCtrl)
+
+			scopeMock.EXPECT().LBSpecs().Return([]azure.ResourceSpecGetter{&fakePublicAPILBSpec})
+			asyncMock.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicAPILBSpec, serviceName).
+				Return(nil, errors.Wrap(&basicSKUError{existing: tc.existing}, "failed to get desired parameters"))
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT(), migratorMock.EXPECT())
+			scopeMock.EXPECT().UpdatePutStatus(infrav1.LoadBalancersReadyCondition, serviceName, gomock.Any())
+
+			s := &Service{
+				Scope:       scopeMock,
+				Reconciler:  asyncMock,
+				skuMigrator: migratorMock,
+			}
+			err := s.Reconcile(context.TODO())
+			g.Expect(err).To(MatchError(tc.expectedError))
+		})
+	}
+}
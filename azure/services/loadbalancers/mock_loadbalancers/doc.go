@@ -18,4 +18,6 @@ limitations under the License.
 //
 //go:generate ../../../../hack/tools/bin/mockgen -destination loadbalancers_mock.go -package mock_loadbalancers -source ../loadbalancers.go LBScope
 //go:generate /usr/bin/env bash -c "cat ../../../../hack/boilerplate/boilerplate.generatego.txt loadbalancers_mock.go > _loadbalancers_mock.go && mv _loadbalancers_mock.go loadbalancers_mock.go"
+//go:generate ../../../../hack/tools/bin/mockgen -destination migration_mock.go -package mock_loadbalancers -source ../migration.go skuMigrationClient
+//go:generate /usr/bin/env bash -c "cat ../../../../hack/boilerplate/boilerplate.generatego.txt migration_mock.go > _migration_mock.go && mv _migration_mock.go migration_mock.go"
 package mock_loadbalancers
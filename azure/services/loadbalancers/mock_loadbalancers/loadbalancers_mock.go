@@ -141,6 +141,34 @@ func (mr *MockLBScopeMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockLBScope)(nil).BaseURI))
 }
 
+// BootstrapDataKeyVault mocks base method.
+func (m *MockLBScope) BootstrapDataKeyVault() *v1beta1.BootstrapDataKeyVault {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataKeyVault")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataKeyVault)
+	return ret0
+}
+
+// BootstrapDataKeyVault indicates an expected call of BootstrapDataKeyVault.
+func (mr *MockLBScopeMockRecorder) BootstrapDataKeyVault() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataKeyVault", reflect.TypeOf((*MockLBScope)(nil).BootstrapDataKeyVault))
+}
+
+// BootstrapDataStorageAccountName mocks base method.
+func (m *MockLBScope) BootstrapDataStorageAccountName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorageAccountName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BootstrapDataStorageAccountName indicates an expected call of BootstrapDataStorageAccountName.
+func (mr *MockLBScopeMockRecorder) BootstrapDataStorageAccountName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorageAccountName", reflect.TypeOf((*MockLBScope)(nil).BootstrapDataStorageAccountName))
+}
+
 // ClientID mocks base method.
 func (m *MockLBScope) ClientID() string {
 	m.ctrl.T.Helper()
@@ -419,6 +447,62 @@ func (mr *MockLBScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockLBScope)(nil).Location))
 }
 
+// ManagedIdentityProviderID mocks base method.
+func (m *MockLBScope) ManagedIdentityProviderID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedIdentityProviderID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ManagedIdentityProviderID indicates an expected call of ManagedIdentityProviderID.
+func (mr *MockLBScopeMockRecorder) ManagedIdentityProviderID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedIdentityProviderID", reflect.TypeOf((*MockLBScope)(nil).ManagedIdentityProviderID))
+}
+
+// NodeInternalLBName mocks base method.
+func (m *MockLBScope) NodeInternalLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeInternalLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeInternalLBName indicates an expected call of NodeInternalLBName.
+func (mr *MockLBScopeMockRecorder) NodeInternalLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeInternalLBName", reflect.TypeOf((*MockLBScope)(nil).NodeInternalLBName))
+}
+
+// NodeInternalLBPoolName mocks base method.
+func (m *MockLBScope) NodeInternalLBPoolName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeInternalLBPoolName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeInternalLBPoolName indicates an expected call of NodeInternalLBPoolName.
+func (mr *MockLBScopeMockRecorder) NodeInternalLBPoolName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeInternalLBPoolName", reflect.TypeOf((*MockLBScope)(nil).NodeInternalLBPoolName))
+}
+
+// NodeRoutes mocks base method.
+func (m *MockLBScope) NodeRoutes() *v1beta1.NodeRoutesSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeRoutes")
+	ret0, _ := ret[0].(*v1beta1.NodeRoutesSpec)
+	return ret0
+}
+
+// NodeRoutes indicates an expected call of NodeRoutes.
+func (mr *MockLBScopeMockRecorder) NodeRoutes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeRoutes", reflect.TypeOf((*MockLBScope)(nil).NodeRoutes))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockLBScope) NodeSubnets() []v1beta1.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -461,6 +545,20 @@ func (mr *MockLBScopeMockRecorder) OutboundPoolName(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboundPoolName", reflect.TypeOf((*MockLBScope)(nil).OutboundPoolName), arg0)
 }
 
+// PlatformFaultDomainCount mocks base method.
+func (m *MockLBScope) PlatformFaultDomainCount() *int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlatformFaultDomainCount")
+	ret0, _ := ret[0].(*int32)
+	return ret0
+}
+
+// PlatformFaultDomainCount indicates an expected call of PlatformFaultDomainCount.
+func (mr *MockLBScopeMockRecorder) PlatformFaultDomainCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlatformFaultDomainCount", reflect.TypeOf((*MockLBScope)(nil).PlatformFaultDomainCount))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockLBScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -475,6 +573,34 @@ func (mr *MockLBScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockLBScope)(nil).ResourceGroup))
 }
 
+// SSHKeyPairSecretName mocks base method.
+func (m *MockLBScope) SSHKeyPairSecretName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHKeyPairSecretName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SSHKeyPairSecretName indicates an expected call of SSHKeyPairSecretName.
+func (mr *MockLBScopeMockRecorder) SSHKeyPairSecretName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHKeyPairSecretName", reflect.TypeOf((*MockLBScope)(nil).SSHKeyPairSecretName))
+}
+
+// SSHPublicKeys mocks base method.
+func (m *MockLBScope) SSHPublicKeys(role string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHPublicKeys", role)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SSHPublicKeys indicates an expected call of SSHPublicKeys.
+func (mr *MockLBScopeMockRecorder) SSHPublicKeys(role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHPublicKeys", reflect.TypeOf((*MockLBScope)(nil).SSHPublicKeys), role)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockLBScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,69 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../migration.go
+//
+// Generated by this command:
+//
+//	mockgen -destination migration_mock.go -package mock_loadbalancers -source ../migration.go skuMigrationClient
+//
+// Package mock_loadbalancers is a generated GoMock package.
+package mock_loadbalancers
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockskuMigrationClient is a mock of skuMigrationClient interface.
+type MockskuMigrationClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockskuMigrationClientMockRecorder
+}
+
+// MockskuMigrationClientMockRecorder is the mock recorder for MockskuMigrationClient.
+type MockskuMigrationClientMockRecorder struct {
+	mock *MockskuMigrationClient
+}
+
+// NewMockskuMigrationClient creates a new mock instance.
+func NewMockskuMigrationClient(ctrl *gomock.Controller) *MockskuMigrationClient {
+	mock := &MockskuMigrationClient{ctrl: ctrl}
+	mock.recorder = &MockskuMigrationClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockskuMigrationClient) EXPECT() *MockskuMigrationClientMockRecorder {
+	return m.recorder
+}
+
+// RemoveLoadBalancerReferences mocks base method.
+func (m *MockskuMigrationClient) RemoveLoadBalancerReferences(ctx context.Context, resourceGroup, nicName, lbID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveLoadBalancerReferences", ctx, resourceGroup, nicName, lbID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveLoadBalancerReferences indicates an expected call of RemoveLoadBalancerReferences.
+func (mr *MockskuMigrationClientMockRecorder) RemoveLoadBalancerReferences(ctx, resourceGroup, nicName, lbID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveLoadBalancerReferences", reflect.TypeOf((*MockskuMigrationClient)(nil).RemoveLoadBalancerReferences), ctx, resourceGroup, nicName, lbID)
+}
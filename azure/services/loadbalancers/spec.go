@@ -18,6 +18,7 @@ package loadbalancers
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
@@ -45,7 +46,13 @@ type LBSpec struct {
 	FrontendIPConfigs    []infrav1.FrontendIP
 	APIServerPort        int32
 	IdleTimeoutInMinutes *int32
+	HealthProbe          *infrav1.LoadBalancerHealthProbe
+	AdditionalRules      []infrav1.LoadBalancingRule
+	EnableFloatingIP     *bool
+	EnableTCPReset       *bool
 	AdditionalTags       map[string]string
+	// Adopt is true if an existing load balancer which is not owned by any cluster is adopted by tagging it as owned.
+	Adopt bool
 }
 
 // ResourceName returns the name of the load balancer.
@@ -74,17 +81,37 @@ func (s *LBSpec) Parameters(ctx context.Context, existing interface{}) (paramete
 		outboundRules       []*armnetwork.OutboundRule
 		probes              []*armnetwork.Probe
 	)
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.ClusterName,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Role:        ptr.To(s.Role),
+		Additional:  s.AdditionalTags,
+	})
 
 	if existing != nil {
 		existingLB, ok := existing.(armnetwork.LoadBalancer)
 		if !ok {
 			return nil, errors.Errorf("%T is not an armnetwork.LoadBalancer", existing)
 		}
+		// Azure doesn't allow changing the SKU of a load balancer, so a Basic API server load balancer must be replaced
+		// instead.
+		if s.Role == infrav1.APIServerRole && s.SKU == infrav1.SKUStandard && existingLB.SKU != nil && ptr.Deref(existingLB.SKU.Name, "") == armnetwork.LoadBalancerSKUNameBasic {
+			return nil, &basicSKUError{existing: existingLB}
+		}
 		// LB already exists
 		// We append the existing LB etag to the header to ensure we only apply the updates if the LB has not been modified.
 		etag = existingLB.Etag
 		update := false
 
+		// A load balancer owned by no cluster keeps its tags when adopted.
+		if s.Adopt && len(converters.MapToTags(existingLB.Tags).Owners()) == 0 {
+			update = true
+			existingTags := infrav1.Tags{}
+			existingTags.Merge(converters.MapToTags(existingLB.Tags))
+			existingTags.Merge(tags)
+			tags = existingTags
+		}
+
 		// merge existing LB properties with desired properties
 		frontendIPConfigs = existingLB.Properties.FrontendIPConfigurations
 		wantedIPs, wantedFrontendIDs := getFrontendIPConfigs(*s)
@@ -95,11 +122,17 @@ func (s *LBSpec) Parameters(ctx context.Context, existing interface{}) (paramete
 			}
 		}
 
-		loadBalancingRules = existingLB.Properties.LoadBalancingRules
+		loadBalancingRules = append([]*armnetwork.LoadBalancingRule{}, existingLB.Properties.LoadBalancingRules...)
 		for _, rule := range getLoadBalancingRules(*s, wantedFrontendIDs) {
-			if !lbRuleExists(loadBalancingRules, *rule) {
+			i := lbRuleIndex(loadBalancingRules, *rule)
+			if i < 0 {
 				update = true
 				loadBalancingRules = append(loadBalancingRules, rule)
+				continue
+			}
+			if updated, changed := updateLBRule(*loadBalancingRules[i], *rule, s.EnableFloatingIP != nil, s.EnableTCPReset != nil); changed {
+				update = true
+				loadBalancingRules[i] = &updated
 			}
 		}
 
@@ -111,19 +144,31 @@ func (s *LBSpec) Parameters(ctx context.Context, existing interface{}) (paramete
 			}
 		}
 
-		outboundRules = existingLB.Properties.OutboundRules
+		outboundRules = append([]*armnetwork.OutboundRule{}, existingLB.Properties.OutboundRules...)
 		for _, rule := range getOutboundRules(*s, wantedFrontendIDs) {
-			if !outboundRuleExists(outboundRules, *rule) {
+			i := outboundRuleIndex(outboundRules, *rule)
+			if i < 0 {
 				update = true
 				outboundRules = append(outboundRules, rule)
+				continue
+			}
+			if updated, changed := updateOutboundRule(*outboundRules[i], *rule); changed {
+				update = true
+				outboundRules[i] = &updated
 			}
 		}
 
-		probes = existingLB.Properties.Probes
+		probes = append([]*armnetwork.Probe{}, existingLB.Properties.Probes...)
 		for _, probe := range getProbes(*s) {
-			if !probeExists(probes, *probe) {
+			i := probeIndex(probes, *probe)
+			if i < 0 {
 				update = true
 				probes = append(probes, probe)
+				continue
+			}
+			if updated, changed := updateProbe(*probes[i], *probe); changed {
+				update = true
+				probes[i] = &updated
 			}
 		}
 
@@ -144,12 +189,7 @@ func (s *LBSpec) Parameters(ctx context.Context, existing interface{}) (paramete
 		SKU:              &armnetwork.LoadBalancerSKU{Name: ptr.To(converters.SKUtoSDK(s.SKU))},
 		Location:         ptr.To(s.Location),
 		ExtendedLocation: converters.ExtendedLocationToNetworkSDK(s.ExtendedLocation),
-		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
-			ClusterName: s.ClusterName,
-			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Role:        ptr.To(s.Role),
-			Additional:  s.AdditionalTags,
-		})),
+		Tags:             converters.TagsToMap(tags),
 		Properties: &armnetwork.LoadBalancerPropertiesFormat{
 			FrontendIPConfigurations: frontendIPConfigs,
 			BackendAddressPools:      backendAddressPools,
@@ -167,24 +207,42 @@ func getFrontendIPConfigs(lbSpec LBSpec) ([]*armnetwork.FrontendIPConfiguration,
 	frontendIDs := make([]*armnetwork.SubResource, 0)
 	for _, ipConfig := range lbSpec.FrontendIPConfigs {
 		var properties armnetwork.FrontendIPConfigurationPropertiesFormat
+		var zones []*string
 		if lbSpec.Type == infrav1.Internal {
+			subnetName := lbSpec.SubnetName
+			if ipConfig.SubnetName != "" {
+				subnetName = ipConfig.SubnetName
+			}
 			properties = armnetwork.FrontendIPConfigurationPropertiesFormat{
 				PrivateIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodStatic),
 				Subnet: &armnetwork.Subnet{
-					ID: ptr.To(azure.SubnetID(lbSpec.SubscriptionID, lbSpec.VNetResourceGroup, lbSpec.VNetName, lbSpec.SubnetName)),
+					ID: ptr.To(azure.SubnetID(lbSpec.SubscriptionID, lbSpec.VNetResourceGroup, lbSpec.VNetName, subnetName)),
 				},
 				PrivateIPAddress: ptr.To(ipConfig.PrivateIPAddress),
 			}
+			for _, zone := range ipConfig.Zones {
+				zones = append(zones, ptr.To(zone))
+			}
+			// The node internal LB gets a private IP from its subnet when none is set.
+			if ipConfig.PrivateIPAddress == "" {
+				properties.PrivateIPAllocationMethod = ptr.To(armnetwork.IPAllocationMethodDynamic)
+				properties.PrivateIPAddress = nil
+			}
 		} else {
+			publicIPID := azure.PublicIPID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, ipConfig.PublicIP.Name)
+			if ipConfig.PublicIP.Existing != nil && ipConfig.PublicIP.Existing.ID != "" {
+				publicIPID = ipConfig.PublicIP.Existing.ID
+			}
 			properties = armnetwork.FrontendIPConfigurationPropertiesFormat{
 				PublicIPAddress: &armnetwork.PublicIPAddress{
-					ID: ptr.To(azure.PublicIPID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, ipConfig.PublicIP.Name)),
+					ID: ptr.To(publicIPID),
 				},
 			}
 		}
 		frontendIPConfigurations = append(frontendIPConfigurations, &armnetwork.FrontendIPConfiguration{
 			Properties: &properties,
 			Name:       ptr.To(ipConfig.Name),
+			Zones:      zones,
 		})
 		frontendIDs = append(frontendIDs, &armnetwork.SubResource{
 			ID: ptr.To(azure.FrontendIPConfigID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, ipConfig.Name)),
@@ -213,38 +271,79 @@ func getOutboundRules(lbSpec LBSpec, frontendIDs []*armnetwork.SubResource) []*a
 }
 
 func getLoadBalancingRules(lbSpec LBSpec, frontendIDs []*armnetwork.SubResource) []*armnetwork.LoadBalancingRule {
-	if lbSpec.Role == infrav1.APIServerRole {
+	var frontendIPConfig *armnetwork.SubResource
+	if len(frontendIDs) != 0 {
+		frontendIPConfig = frontendIDs[0]
+	}
+	switch lbSpec.Role {
+	case infrav1.APIServerRole:
 		// We disable outbound SNAT explicitly in the HTTPS LB rule and enable TCP and UDP outbound NAT with an outbound rule.
 		// For more information on Standard LB outbound connections see https://learn.microsoft.com/azure/load-balancer/load-balancer-outbound-connections.
-		var frontendIPConfig *armnetwork.SubResource
-		if len(frontendIDs) != 0 {
-			frontendIPConfig = frontendIDs[0]
+		rules := []*armnetwork.LoadBalancingRule{
+			newLBRule(lbSpec, lbRuleHTTPS, armnetwork.TransportProtocolTCP, lbSpec.APIServerPort, lbSpec.APIServerPort, frontendIPConfig, httpsProbe),
 		}
-		return []*armnetwork.LoadBalancingRule{
-			{
-				Name: ptr.To(lbRuleHTTPS),
-				Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
-					DisableOutboundSnat:     ptr.To(true),
-					Protocol:                ptr.To(armnetwork.TransportProtocolTCP),
-					FrontendPort:            ptr.To[int32](lbSpec.APIServerPort),
-					BackendPort:             ptr.To[int32](lbSpec.APIServerPort),
-					IdleTimeoutInMinutes:    lbSpec.IdleTimeoutInMinutes,
-					EnableFloatingIP:        ptr.To(false),
-					LoadDistribution:        ptr.To(armnetwork.LoadDistributionDefault),
-					FrontendIPConfiguration: frontendIPConfig,
-					BackendAddressPool: &armnetwork.SubResource{
-						ID: ptr.To(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, lbSpec.BackendPoolName)),
-					},
-					Probe: &armnetwork.SubResource{
-						ID: ptr.To(azure.ProbeID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, httpsProbe)),
-					},
-				},
-			},
+		// Each additional private frontend IP of an internal load balancer, e.g. one per zone, serves the API server
+		// too. Azure only lets several rules share a backend port with floating IP enabled, so the machines receive
+		// the traffic of those frontend IPs with the frontend IP as the destination.
+		if lbSpec.Type == infrav1.Internal {
+			for i := 1; i < len(frontendIDs) && i < len(lbSpec.FrontendIPConfigs); i++ {
+				rule := newLBRule(lbSpec, lbRuleHTTPS+"-"+lbSpec.FrontendIPConfigs[i].Name, armnetwork.TransportProtocolTCP, lbSpec.APIServerPort, lbSpec.APIServerPort, frontendIDs[i], httpsProbe)
+				rule.Properties.EnableFloatingIP = ptr.To(true)
+				rules = append(rules, rule)
+			}
 		}
+		// Additional rules share the frontend IP, backend pool and health probe of the API server rule.
+		return append(rules, getAdditionalLBRules(lbSpec, frontendIPConfig, httpsProbe)...)
+	case infrav1.NodeInternalRole:
+		var probe string
+		if lbSpec.HealthProbe != nil {
+			probe = healthProbe
+		}
+		return getAdditionalLBRules(lbSpec, frontendIPConfig, probe)
 	}
 	return []*armnetwork.LoadBalancingRule{}
 }
 
+func getAdditionalLBRules(lbSpec LBSpec, frontendIPConfig *armnetwork.SubResource, probe string) []*armnetwork.LoadBalancingRule {
+	rules := make([]*armnetwork.LoadBalancingRule, 0, len(lbSpec.AdditionalRules))
+	for _, rule := range lbSpec.AdditionalRules {
+		protocol := armnetwork.TransportProtocolTCP
+		if rule.Protocol == infrav1.LoadBalancingRuleProtocolUDP {
+			protocol = armnetwork.TransportProtocolUDP
+		}
+		rules = append(rules, newLBRule(lbSpec, rule.Name, protocol, rule.FrontendPort, ptr.Deref(rule.BackendPort, rule.FrontendPort), frontendIPConfig, probe))
+	}
+	return rules
+}
+
+// newLBRule returns a load balancing rule of the first frontend IP and the backend pool of the load balancer.
+// Rules without a probe consider all the backends healthy.
+func newLBRule(lbSpec LBSpec, name string, protocol armnetwork.TransportProtocol, frontendPort, backendPort int32, frontendIPConfig *armnetwork.SubResource, probe string) *armnetwork.LoadBalancingRule {
+	rule := &armnetwork.LoadBalancingRule{
+		Name: ptr.To(name),
+		Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+			DisableOutboundSnat:     ptr.To(true),
+			Protocol:                ptr.To(protocol),
+			FrontendPort:            ptr.To(frontendPort),
+			BackendPort:             ptr.To(backendPort),
+			IdleTimeoutInMinutes:    lbSpec.IdleTimeoutInMinutes,
+			EnableFloatingIP:        ptr.To(ptr.Deref(lbSpec.EnableFloatingIP, false)),
+			EnableTCPReset:          lbSpec.EnableTCPReset,
+			LoadDistribution:        ptr.To(armnetwork.LoadDistributionDefault),
+			FrontendIPConfiguration: frontendIPConfig,
+			BackendAddressPool: &armnetwork.SubResource{
+				ID: ptr.To(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, lbSpec.BackendPoolName)),
+			},
+		},
+	}
+	if probe != "" {
+		rule.Properties.Probe = &armnetwork.SubResource{
+			ID: ptr.To(azure.ProbeID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, probe)),
+		}
+	}
+	return rule
+}
+
 func getBackendAddressPools(lbSpec LBSpec) []*armnetwork.BackendAddressPool {
 	return []*armnetwork.BackendAddressPool{
 		{
@@ -254,39 +353,112 @@ func getBackendAddressPools(lbSpec LBSpec) []*armnetwork.BackendAddressPool {
 }
 
 func getProbes(lbSpec LBSpec) []*armnetwork.Probe {
-	if lbSpec.Role == infrav1.APIServerRole {
-		return []*armnetwork.Probe{
-			{
-				Name: ptr.To(httpsProbe),
-				Properties: &armnetwork.ProbePropertiesFormat{
-					Protocol:          ptr.To(armnetwork.ProbeProtocolHTTPS),
-					Port:              ptr.To[int32](lbSpec.APIServerPort),
-					RequestPath:       ptr.To(httpsProbeRequestPath),
-					IntervalInSeconds: ptr.To[int32](15),
-					NumberOfProbes:    ptr.To[int32](4),
-				},
-			},
+	switch lbSpec.Role {
+	case infrav1.APIServerRole:
+		// The probe keeps its name whatever its protocol, as the API server load balancing rule references it.
+		return []*armnetwork.Probe{newProbe(httpsProbe, ptr.Deref(lbSpec.HealthProbe, infrav1.LoadBalancerHealthProbe{}), lbSpec.APIServerPort)}
+	case infrav1.NodeInternalRole:
+		if lbSpec.HealthProbe != nil {
+			return []*armnetwork.Probe{newProbe(healthProbe, *lbSpec.HealthProbe, 0)}
 		}
 	}
 	return []*armnetwork.Probe{}
 }
 
-func probeExists(probes []*armnetwork.Probe, probe armnetwork.Probe) bool {
-	for _, p := range probes {
+// newProbe returns a probe with the settings of the health probe, which defaults to an HTTPS probe of /readyz.
+func newProbe(name string, probe infrav1.LoadBalancerHealthProbe, defaultPort int32) *armnetwork.Probe {
+	properties := &armnetwork.ProbePropertiesFormat{
+		Protocol:          ptr.To(armnetwork.ProbeProtocolHTTPS),
+		Port:              ptr.To(ptr.Deref(probe.Port, defaultPort)),
+		RequestPath:       ptr.To(httpsProbeRequestPath),
+		IntervalInSeconds: ptr.To(ptr.Deref[int32](probe.IntervalInSeconds, infrav1.DefaultLBProbeIntervalInSeconds)),
+		NumberOfProbes:    ptr.To(ptr.Deref[int32](probe.NumberOfProbes, infrav1.DefaultLBProbeNumberOfProbes)),
+		ProbeThreshold:    probe.ProbeThreshold,
+	}
+	switch probe.Protocol {
+	case infrav1.LoadBalancerProbeProtocolTCP:
+		properties.Protocol = ptr.To(armnetwork.ProbeProtocolTCP)
+		properties.RequestPath = nil
+	case infrav1.LoadBalancerProbeProtocolHTTP:
+		properties.Protocol = ptr.To(armnetwork.ProbeProtocolHTTP)
+	}
+	if properties.RequestPath != nil && probe.RequestPath != "" {
+		properties.RequestPath = ptr.To(probe.RequestPath)
+	}
+	return &armnetwork.Probe{
+		Name:       ptr.To(name),
+		Properties: properties,
+	}
+}
+
+func probeIndex(probes []*armnetwork.Probe, probe armnetwork.Probe) int {
+	for i, p := range probes {
 		if ptr.Deref(p.Name, "") == ptr.Deref(probe.Name, "") {
-			return true
+			return i
 		}
 	}
-	return false
+	return -1
 }
 
-func outboundRuleExists(rules []*armnetwork.OutboundRule, rule armnetwork.OutboundRule) bool {
-	for _, r := range rules {
+// updateProbe returns a copy of the existing probe with the protocol, port, request path, interval, number of probes
+// and, when set, the threshold of the wanted probe, and whether any of them changed. Other properties of the existing
+// probe are kept as is.
+func updateProbe(existing, wanted armnetwork.Probe) (armnetwork.Probe, bool) {
+	if existing.Properties == nil {
+		return wanted, true
+	}
+	if wanted.Properties == nil {
+		return existing, false
+	}
+	e, w := existing.Properties, wanted.Properties
+	if ptr.Equal(e.Protocol, w.Protocol) &&
+		ptr.Equal(e.Port, w.Port) &&
+		ptr.Deref(e.RequestPath, "") == ptr.Deref(w.RequestPath, "") &&
+		ptr.Equal(e.IntervalInSeconds, w.IntervalInSeconds) &&
+		ptr.Equal(e.NumberOfProbes, w.NumberOfProbes) &&
+		(w.ProbeThreshold == nil || ptr.Equal(e.ProbeThreshold, w.ProbeThreshold)) {
+		return existing, false
+	}
+	properties := *e
+	properties.Protocol = w.Protocol
+	properties.Port = w.Port
+	properties.RequestPath = w.RequestPath
+	properties.IntervalInSeconds = w.IntervalInSeconds
+	properties.NumberOfProbes = w.NumberOfProbes
+	if w.ProbeThreshold != nil {
+		properties.ProbeThreshold = w.ProbeThreshold
+	}
+	existing.Properties = &properties
+	return existing, true
+}
+
+func outboundRuleIndex(rules []*armnetwork.OutboundRule, rule armnetwork.OutboundRule) int {
+	for i, r := range rules {
 		if ptr.Deref(r.Name, "") == ptr.Deref(rule.Name, "") {
-			return true
+			return i
 		}
 	}
-	return false
+	return -1
+}
+
+// updateOutboundRule returns a copy of the existing outbound rule with the idle timeout and frontend IP configurations
+// of the wanted rule, and whether any of them changed. Other properties of the existing rule are kept as is.
+func updateOutboundRule(existing, wanted armnetwork.OutboundRule) (armnetwork.OutboundRule, bool) {
+	if existing.Properties == nil {
+		return wanted, true
+	}
+	if wanted.Properties == nil {
+		return existing, false
+	}
+	if ptr.Equal(existing.Properties.IdleTimeoutInMinutes, wanted.Properties.IdleTimeoutInMinutes) &&
+		sameSubResources(existing.Properties.FrontendIPConfigurations, wanted.Properties.FrontendIPConfigurations) {
+		return existing, false
+	}
+	properties := *existing.Properties
+	properties.IdleTimeoutInMinutes = wanted.Properties.IdleTimeoutInMinutes
+	properties.FrontendIPConfigurations = wanted.Properties.FrontendIPConfigurations
+	existing.Properties = &properties
+	return existing, true
 }
 
 func poolExists(pools []*armnetwork.BackendAddressPool, pool armnetwork.BackendAddressPool) bool {
@@ -298,13 +470,63 @@ func poolExists(pools []*armnetwork.BackendAddressPool, pool armnetwork.BackendA
 	return false
 }
 
-func lbRuleExists(rules []*armnetwork.LoadBalancingRule, rule armnetwork.LoadBalancingRule) bool {
-	for _, r := range rules {
+func lbRuleIndex(rules []*armnetwork.LoadBalancingRule, rule armnetwork.LoadBalancingRule) int {
+	for i, r := range rules {
 		if ptr.Deref(r.Name, "") == ptr.Deref(rule.Name, "") {
-			return true
+			return i
 		}
 	}
-	return false
+	return -1
+}
+
+// updateLBRule returns a copy of the existing load balancing rule with the idle timeout of the wanted rule, and its
+// floating IP and TCP reset settings when they are set on the load balancer, and whether it changed. Other properties
+// of the existing rule are kept as is.
+func updateLBRule(existing, wanted armnetwork.LoadBalancingRule, setFloatingIP, setTCPReset bool) (armnetwork.LoadBalancingRule, bool) {
+	if existing.Properties == nil {
+		return wanted, true
+	}
+	if wanted.Properties == nil {
+		return existing, false
+	}
+	properties := *existing.Properties
+	changed := false
+	if !ptr.Equal(properties.IdleTimeoutInMinutes, wanted.Properties.IdleTimeoutInMinutes) {
+		properties.IdleTimeoutInMinutes = wanted.Properties.IdleTimeoutInMinutes
+		changed = true
+	}
+	if setFloatingIP && ptr.Deref(properties.EnableFloatingIP, false) != ptr.Deref(wanted.Properties.EnableFloatingIP, false) {
+		properties.EnableFloatingIP = wanted.Properties.EnableFloatingIP
+		changed = true
+	}
+	if setTCPReset && ptr.Deref(properties.EnableTCPReset, false) != ptr.Deref(wanted.Properties.EnableTCPReset, false) {
+		properties.EnableTCPReset = wanted.Properties.EnableTCPReset
+		changed = true
+	}
+	if !changed {
+		return existing, false
+	}
+	existing.Properties = &properties
+	return existing, true
+}
+
+// sameSubResources returns true if both lists reference the same resource IDs, regardless of order and case.
+func sameSubResources(a, b []*armnetwork.SubResource) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	ids := make(map[string]int, len(a))
+	for _, r := range a {
+		ids[strings.ToLower(ptr.Deref(r.ID, ""))]++
+	}
+	for _, r := range b {
+		id := strings.ToLower(ptr.Deref(r.ID, ""))
+		if ids[id] == 0 {
+			return false
+		}
+		ids[id]--
+	}
+	return true
 }
 
 func ipExists(configs []*armnetwork.FrontendIPConfiguration, config armnetwork.FrontendIPConfiguration) bool {
@@ -61,6 +61,14 @@ func getExistingLBWithMissingOutboundRules() armnetwork.LoadBalancer {
 	return existingLB
 }
 
+func getExistingLBWithOutdatedIdleTimeout() armnetwork.LoadBalancer {
+	existingLB := newSamplePublicAPIServerLB(false, false, true, false, true)
+	existingLB.Properties.LoadBalancingRules[0].Properties.IdleTimeoutInMinutes = ptr.To[int32](15)
+	existingLB.Properties.OutboundRules[0].Properties.IdleTimeoutInMinutes = ptr.To[int32](15)
+
+	return existingLB
+}
+
 func TestParameters(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -78,6 +86,20 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "Basic API load balancer must be replaced",
+			spec: &fakePublicAPILBSpec,
+			existing: func() armnetwork.LoadBalancer {
+				lb := newSamplePublicAPIServerLB(false, false, false, false, false)
+				lb.Name = ptr.To("my-publiclb")
+				lb.SKU = &armnetwork.LoadBalancerSKU{Name: ptr.To(armnetwork.LoadBalancerSKUNameBasic)}
+				return lb
+			}(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "load balancer my-publiclb uses the Basic SKU and must be replaced with a Standard one",
+		},
 		{
 			name:     "internal API load balancer with all expected values",
 			spec:     &fakeInternalAPILBSpec,
@@ -96,6 +118,41 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "untagged node outbound load balancer is adopted",
+			spec: func() *LBSpec {
+				spec := fakeNodeOutboundLBSpec
+				spec.Adopt = true
+				return &spec
+			}(),
+			existing: func() armnetwork.LoadBalancer {
+				lb := newDefaultNodeOutboundLB()
+				lb.Tags = map[string]*string{"team": ptr.To("networking")}
+				return lb
+			}(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				g.Expect(result.(armnetwork.LoadBalancer).Tags).To(Equal(map[string]*string{
+					"team": ptr.To("networking"),
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+					"sigs.k8s.io_cluster-api-provider-azure_role":               ptr.To(infrav1.NodeOutboundRole),
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "node outbound load balancer already owned by the cluster is not updated when adopting",
+			spec: func() *LBSpec {
+				spec := fakeNodeOutboundLBSpec
+				spec.Adopt = true
+				return &spec
+			}(),
+			existing: newDefaultNodeOutboundLB(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
 		{
 			name:     "load balancer exists with missing frontend IP configs",
 			spec:     &fakePublicAPILBSpec,
@@ -146,6 +203,314 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "load balancer exists with outdated idle timeout",
+			spec:     &fakePublicAPILBSpec,
+			existing: getExistingLBWithOutdatedIdleTimeout(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				g.Expect(result.(armnetwork.LoadBalancer)).To(Equal(newSamplePublicAPIServerLB(false, false, true, false, true)))
+			},
+			expectedError: "",
+		},
+		{
+			name: "load balancer exists with additional frontend IP",
+			spec: func() *LBSpec {
+				spec := fakePublicAPILBSpec
+				spec.FrontendIPConfigs = append([]infrav1.FrontendIP{}, fakePublicAPILBSpec.FrontendIPConfigs...)
+				spec.FrontendIPConfigs = append(spec.FrontendIPConfigs, infrav1.FrontendIP{
+					Name:     "my-publiclb-frontEnd-2",
+					PublicIP: &infrav1.PublicIPSpec{Name: "my-publicip-2"},
+				})
+				return &spec
+			}(),
+			existing: newSamplePublicAPIServerLB(false, false, false, false, true),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lb.Properties.FrontendIPConfigurations).To(HaveLen(2))
+				g.Expect(lb.Properties.LoadBalancingRules[0].Properties.FrontendIPConfiguration.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd")))
+				g.Expect(lb.Properties.OutboundRules).To(HaveLen(1))
+				g.Expect(lb.Properties.OutboundRules[0].Properties.FrontendIPConfigurations).To(ConsistOf(
+					&armnetwork.SubResource{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd")},
+					&armnetwork.SubResource{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd-2")},
+				))
+				g.Expect(lb.Properties.OutboundRules[0].Properties.AllocatedOutboundPorts).To(Equal(ptr.To[int32](1000)))
+			},
+			expectedError: "",
+		},
+		{
+			name: "public API server load balancer with an existing public IP",
+			spec: func() *LBSpec {
+				spec := fakePublicAPILBSpec
+				spec.FrontendIPConfigs = []infrav1.FrontendIP{{
+					Name: "my-publiclb-frontEnd",
+					PublicIP: &infrav1.PublicIPSpec{
+						Name:     "byo-ip",
+						Existing: &infrav1.ExistingPublicIP{ID: "/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/publicIPAddresses/byo-ip"},
+					},
+				}}
+				return &spec
+			}(),
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lb.Properties.FrontendIPConfigurations).To(HaveLen(1))
+				g.Expect(lb.Properties.FrontendIPConfigurations[0].Properties.PublicIPAddress.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/publicIPAddresses/byo-ip")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "load balancer exists with outdated health probe",
+			spec: func() *LBSpec {
+				spec := fakePublicAPILBSpec
+				spec.HealthProbe = &infrav1.LoadBalancerHealthProbe{
+					Protocol:          infrav1.LoadBalancerProbeProtocolTCP,
+					IntervalInSeconds: ptr.To[int32](5),
+					NumberOfProbes:    ptr.To[int32](2),
+					ProbeThreshold:    ptr.To[int32](3),
+				}
+				return &spec
+			}(),
+			existing: newSamplePublicAPIServerLB(false, false, false, false, false),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lb.Properties.Probes).To(HaveLen(1))
+				g.Expect(lb.Properties.Probes[0]).To(Equal(&armnetwork.Probe{
+					Name: ptr.To(httpsProbe),
+					Properties: &armnetwork.ProbePropertiesFormat{
+						Protocol:          ptr.To(armnetwork.ProbeProtocolTCP),
+						Port:              ptr.To[int32](6443),
+						IntervalInSeconds: ptr.To[int32](5),
+						NumberOfProbes:    ptr.To[int32](2),
+						ProbeThreshold:    ptr.To[int32](3),
+					},
+				}))
+				g.Expect(lb.Properties.LoadBalancingRules[0].Properties.Probe.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/probes/HTTPSProbe")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "API server load balancer with a custom HTTP health probe",
+			spec: func() *LBSpec {
+				spec := fakePublicAPILBSpec
+				spec.HealthProbe = &infrav1.LoadBalancerHealthProbe{
+					Protocol:    infrav1.LoadBalancerProbeProtocolHTTP,
+					Port:        ptr.To[int32](8080),
+					RequestPath: "/healthz",
+				}
+				return &spec
+			}(),
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lb.Properties.Probes).To(HaveLen(1))
+				g.Expect(lb.Properties.Probes[0].Properties).To(Equal(&armnetwork.ProbePropertiesFormat{
+					Protocol:          ptr.To(armnetwork.ProbeProtocolHTTP),
+					Port:              ptr.To[int32](8080),
+					RequestPath:       ptr.To("/healthz"),
+					IntervalInSeconds: ptr.To[int32](15),
+					NumberOfProbes:    ptr.To[int32](4),
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "load balancer exists with missing additional rules",
+			spec: func() *LBSpec {
+				spec := fakePublicAPILBSpec
+				spec.AdditionalRules = []infrav1.LoadBalancingRule{
+					{Name: "https", Protocol: infrav1.LoadBalancingRuleProtocolTCP, FrontendPort: 443, BackendPort: ptr.To[int32](6443)},
+					{Name: "konnectivity", Protocol: infrav1.LoadBalancingRuleProtocolUDP, FrontendPort: 8132},
+				}
+				return &spec
+			}(),
+			existing: newSamplePublicAPIServerLB(false, false, true, false, false),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lb.Properties.LoadBalancingRules).To(HaveLen(3))
+				// The existing API server rule is kept as is.
+				g.Expect(lb.Properties.LoadBalancingRules[0].Properties.EnableFloatingIP).To(Equal(ptr.To(true)))
+				for i, want := range []struct {
+					name                      string
+					protocol                  armnetwork.TransportProtocol
+					frontendPort, backendPort int32
+				}{
+					{name: "https", protocol: armnetwork.TransportProtocolTCP, frontendPort: 443, backendPort: 6443},
+					{name: "konnectivity", protocol: armnetwork.TransportProtocolUDP, frontendPort: 8132, backendPort: 8132},
+				} {
+					rule := lb.Properties.LoadBalancingRules[i+1]
+					g.Expect(rule.Name).To(Equal(ptr.To(want.name)))
+					g.Expect(rule.Properties.Protocol).To(Equal(ptr.To(want.protocol)))
+					g.Expect(rule.Properties.FrontendPort).To(Equal(ptr.To(want.frontendPort)))
+					g.Expect(rule.Properties.BackendPort).To(Equal(ptr.To(want.backendPort)))
+					g.Expect(rule.Properties.DisableOutboundSnat).To(Equal(ptr.To(true)))
+					g.Expect(rule.Properties.FrontendIPConfiguration).To(Equal(lb.Properties.LoadBalancingRules[0].Properties.FrontendIPConfiguration))
+					g.Expect(rule.Properties.Probe).To(Equal(lb.Properties.LoadBalancingRules[0].Properties.Probe))
+				}
+			},
+			expectedError: "",
+		},
+		{
+			name: "load balancer exists with floating IP and TCP reset set",
+			spec: func() *LBSpec {
+				spec := fakePublicAPILBSpec
+				spec.EnableFloatingIP = ptr.To(false)
+				spec.EnableTCPReset = ptr.To(true)
+				return &spec
+			}(),
+			existing: newSamplePublicAPIServerLB(false, false, true, false, false),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lb.Properties.LoadBalancingRules).To(HaveLen(1))
+				g.Expect(lb.Properties.LoadBalancingRules[0].Properties.EnableFloatingIP).To(Equal(ptr.To(false)))
+				g.Expect(lb.Properties.LoadBalancingRules[0].Properties.EnableTCPReset).To(Equal(ptr.To(true)))
+			},
+			expectedError: "",
+		},
+		{
+			name: "new load balancer with floating IP and TCP reset",
+			spec: func() *LBSpec {
+				spec := fakePublicAPILBSpec
+				spec.EnableFloatingIP = ptr.To(true)
+				spec.EnableTCPReset = ptr.To(true)
+				return &spec
+			}(),
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lb.Properties.LoadBalancingRules).To(HaveLen(1))
+				g.Expect(lb.Properties.LoadBalancingRules[0].Properties.EnableFloatingIP).To(Equal(ptr.To(true)))
+				g.Expect(lb.Properties.LoadBalancingRules[0].Properties.EnableTCPReset).To(Equal(ptr.To(true)))
+			},
+			expectedError: "",
+		},
+		{
+			name: "node internal load balancer with a health probe and rules",
+			spec: &LBSpec{
+				Name:                 "my-cluster-node-internal-lb",
+				ResourceGroup:        "my-rg",
+				SubscriptionID:       "123",
+				ClusterName:          "my-cluster",
+				Location:             "my-location",
+				Role:                 infrav1.NodeInternalRole,
+				Type:                 infrav1.Internal,
+				SKU:                  infrav1.SKUStandard,
+				VNetName:             "my-vnet",
+				VNetResourceGroup:    "my-rg",
+				SubnetName:           "my-node-subnet",
+				BackendPoolName:      "my-cluster-node-internal-lb-backendPool",
+				IdleTimeoutInMinutes: ptr.To[int32](4),
+				FrontendIPConfigs:    []infrav1.FrontendIP{{Name: "my-cluster-node-internal-lb-frontEnd"}},
+				HealthProbe: &infrav1.LoadBalancerHealthProbe{
+					Protocol: infrav1.LoadBalancerProbeProtocolTCP,
+					Port:     ptr.To[int32](30080),
+				},
+				AdditionalRules: []infrav1.LoadBalancingRule{
+					{Name: "ingress", Protocol: infrav1.LoadBalancingRuleProtocolTCP, FrontendPort: 80, BackendPort: ptr.To[int32](30080)},
+				},
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lb.Properties.FrontendIPConfigurations).To(HaveLen(1))
+				g.Expect(lb.Properties.FrontendIPConfigurations[0].Properties).To(Equal(&armnetwork.FrontendIPConfigurationPropertiesFormat{
+					PrivateIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodDynamic),
+					Subnet: &armnetwork.Subnet{
+						ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-node-subnet"),
+					},
+				}))
+				g.Expect(lb.Properties.OutboundRules).To(BeEmpty())
+				g.Expect(lb.Properties.Probes).To(Equal([]*armnetwork.Probe{{
+					Name: ptr.To("HealthProbe"),
+					Properties: &armnetwork.ProbePropertiesFormat{
+						Protocol:          ptr.To(armnetwork.ProbeProtocolTCP),
+						Port:              ptr.To[int32](30080),
+						IntervalInSeconds: ptr.To[int32](15),
+						NumberOfProbes:    ptr.To[int32](4),
+					},
+				}}))
+				g.Expect(lb.Properties.LoadBalancingRules).To(HaveLen(1))
+				rule := lb.Properties.LoadBalancingRules[0]
+				g.Expect(rule.Name).To(Equal(ptr.To("ingress")))
+				g.Expect(rule.Properties.FrontendPort).To(Equal(ptr.To[int32](80)))
+				g.Expect(rule.Properties.BackendPort).To(Equal(ptr.To[int32](30080)))
+				g.Expect(rule.Properties.Probe.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster-node-internal-lb/probes/HealthProbe")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "internal API server load balancer with a private frontend IP per zone",
+			spec: &LBSpec{
+				Name:              "my-private-lb",
+				ResourceGroup:     "my-rg",
+				SubscriptionID:    "123",
+				ClusterName:       "my-cluster",
+				Location:          "my-location",
+				Role:              infrav1.APIServerRole,
+				Type:              infrav1.Internal,
+				SKU:               infrav1.SKUStandard,
+				VNetName:          "my-vnet",
+				VNetResourceGroup: "my-rg",
+				SubnetName:        "my-cp-subnet",
+				BackendPoolName:   "my-private-lb-backendPool",
+				FrontendIPConfigs: []infrav1.FrontendIP{
+					{
+						Name:            "my-private-lb-frontEnd",
+						FrontendIPClass: infrav1.FrontendIPClass{PrivateIPAddress: "10.0.0.10"},
+						Zones:           []string{"1"},
+					},
+					{
+						Name:            "my-private-lb-frontEnd-2",
+						FrontendIPClass: infrav1.FrontendIPClass{PrivateIPAddress: "10.1.0.10"},
+						Zones:           []string{"2"},
+						SubnetName:      "my-cp-subnet-2",
+					},
+				},
+				APIServerPort: 6443,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lb.Properties.FrontendIPConfigurations).To(HaveLen(2))
+				g.Expect(lb.Properties.FrontendIPConfigurations[0].Zones).To(Equal([]*string{ptr.To("1")}))
+				g.Expect(lb.Properties.FrontendIPConfigurations[0].Properties.Subnet.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-cp-subnet")))
+				g.Expect(lb.Properties.FrontendIPConfigurations[1].Zones).To(Equal([]*string{ptr.To("2")}))
+				g.Expect(lb.Properties.FrontendIPConfigurations[1].Properties.Subnet.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-cp-subnet-2")))
+				g.Expect(lb.Properties.FrontendIPConfigurations[1].Properties.PrivateIPAddress).To(Equal(ptr.To("10.1.0.10")))
+				g.Expect(lb.Properties.LoadBalancingRules).To(HaveLen(2))
+				g.Expect(lb.Properties.LoadBalancingRules[0].Name).To(Equal(ptr.To("LBRuleHTTPS")))
+				g.Expect(lb.Properties.LoadBalancingRules[0].Properties.EnableFloatingIP).To(Equal(ptr.To(false)))
+				rule := lb.Properties.LoadBalancingRules[1]
+				g.Expect(rule.Name).To(Equal(ptr.To("LBRuleHTTPS-my-private-lb-frontEnd-2")))
+				g.Expect(rule.Properties.EnableFloatingIP).To(Equal(ptr.To(true)))
+				g.Expect(rule.Properties.BackendPort).To(Equal(ptr.To[int32](6443)))
+				g.Expect(rule.Properties.FrontendIPConfiguration.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-private-lb/frontendIPConfigurations/my-private-lb-frontEnd-2")))
+				g.Expect(rule.Properties.Probe.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-private-lb/probes/HTTPSProbe")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "load balancer exists with a health probe threshold set outside of CAPZ",
+			spec: &fakePublicAPILBSpec,
+			existing: func() armnetwork.LoadBalancer {
+				existingLB := newSamplePublicAPIServerLB(false, false, false, false, false)
+				existingLB.Properties.Probes[0].Properties.ProbeThreshold = ptr.To[int32](2)
+				return existingLB
+			}(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -212,8 +577,8 @@ func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools
 	var subnet *armnetwork.Subnet
 	var backendAddressPoolProps *armnetwork.BackendAddressPoolPropertiesFormat
 	enableFloatingIP := ptr.To(false)
-	numProbes := ptr.To[int32](4)
-	idleTimeout := ptr.To[int32](4)
+	var probeThreshold *int32
+	var allocatedOutboundPorts *int32
 
 	if verifyFrontendIP {
 		subnet = &armnetwork.Subnet{
@@ -229,10 +594,10 @@ func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools
 		enableFloatingIP = ptr.To(true)
 	}
 	if verifyProbes {
-		numProbes = ptr.To[int32](999)
+		probeThreshold = ptr.To[int32](999)
 	}
 	if verifyOutboundRules {
-		idleTimeout = ptr.To[int32](1000)
+		allocatedOutboundPorts = ptr.To[int32](1000)
 	}
 
 	return armnetwork.LoadBalancer{
@@ -289,7 +654,8 @@ func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools
 						Port:              ptr.To[int32](6443),
 						RequestPath:       ptr.To(httpsProbeRequestPath),
 						IntervalInSeconds: ptr.To[int32](15),
-						NumberOfProbes:    numProbes, // Add to verify that Probes aren't overwritten on update
+						NumberOfProbes:    ptr.To[int32](4),
+						ProbeThreshold:    probeThreshold, // Add to verify that Probes aren't overwritten on update
 					},
 				},
 			},
@@ -303,8 +669,9 @@ func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools
 						BackendAddressPool: &armnetwork.SubResource{
 							ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
 						},
-						Protocol:             ptr.To(armnetwork.LoadBalancerOutboundRuleProtocolAll),
-						IdleTimeoutInMinutes: idleTimeout, // Add to verify that OutboundRules aren't overwritten on update
+						Protocol:               ptr.To(armnetwork.LoadBalancerOutboundRuleProtocolAll),
+						IdleTimeoutInMinutes:   ptr.To[int32](4),
+						AllocatedOutboundPorts: allocatedOutboundPorts, // Add to verify that OutboundRules aren't overwritten on update
 					},
 				},
 			},
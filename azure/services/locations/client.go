@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package locations
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	apiVersion         = "2022-12-01"
+	physicalRegionType = "Physical"
+)
+
+// Location is an Azure location available to a subscription.
+type Location struct {
+	// Name is the name of the location, e.g. eastus.
+	Name string
+	// DisplayName is the display name of the location, e.g. East US.
+	DisplayName string
+}
+
+// Client lists the locations available to a subscription.
+type Client interface {
+	List(ctx context.Context) ([]Location, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	client *arm.Client
+	auth   azure.Authorizer
+}
+
+var (
+	_              Client = &AzureClient{}
+	doOnce         sync.Once
+	locationsCache ttllru.PeekingCacher
+)
+
+// NewClient creates a new locations client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create locations client options")
+	}
+	client, err := arm.NewClient("locations.AzureClient", "v1.0.0", auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create locations client")
+	}
+	return &AzureClient{
+		client: client,
+		auth:   auth,
+	}, nil
+}
+
+// List returns the physical locations available to the subscription, leaving out logical locations like global or
+// geographies. Locations are cached since they seldom change.
+func (ac *AzureClient) List(ctx context.Context) ([]Location, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "locations.AzureClient.List")
+	defer done()
+
+	var err error
+	doOnce.Do(func() {
+		locationsCache, err = ttllru.New(128, time.Hour)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating LRU cache for locations")
+	}
+
+	key := ac.auth.HashKey()
+	if cached, ok := locationsCache.Get(key); ok {
+		return cached.([]Location), nil
+	}
+
+	locations, err := ac.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_ = locationsCache.Add(key, locations)
+	return locations, nil
+}
+
+// list returns the physical locations available to the subscription.
+func (ac *AzureClient) list(ctx context.Context) ([]Location, error) {
+	urlPath := runtime.JoinPaths("/subscriptions", ac.auth.SubscriptionID(), "locations")
+	req, err := runtime.NewRequest(ctx, http.MethodGet, runtime.JoinPaths(ac.client.Endpoint(), urlPath))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create locations request")
+	}
+	query := req.Raw().URL.Query()
+	query.Set("api-version", apiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header["Accept"] = []string{"application/json"}
+
+	resp, err := ac.client.Pipeline().Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the locations of subscription %s", ac.auth.SubscriptionID())
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return nil, errors.Wrapf(runtime.NewResponseError(resp), "failed to list the locations of subscription %s", ac.auth.SubscriptionID())
+	}
+
+	var page struct {
+		Value []struct {
+			Name        string `json:"name"`
+			DisplayName string `json:"displayName"`
+			Metadata    struct {
+				RegionType string `json:"regionType"`
+			} `json:"metadata"`
+		} `json:"value"`
+	}
+	if err := runtime.UnmarshalAsJSON(resp, &page); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the locations of subscription %s", ac.auth.SubscriptionID())
+	}
+
+	locations := make([]Location, 0, len(page.Value))
+	for _, l := range page.Value {
+		if !strings.EqualFold(l.Metadata.RegionType, physicalRegionType) {
+			continue
+		}
+		locations = append(locations, Location{Name: l.Name, DisplayName: l.DisplayName})
+	}
+	return locations, nil
+}
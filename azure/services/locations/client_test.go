@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package locations
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/fakearm"
+)
+
+func TestList(t *testing.T) {
+	g := NewWithT(t)
+
+	server := fakearm.NewServer()
+	defer server.Close()
+	server.Register()
+
+	client, err := NewClient(server.Authorizer("123"))
+	g.Expect(err).NotTo(HaveOccurred())
+	id := "/subscriptions/123/locations"
+
+	server.InjectError(http.MethodGet, id, http.StatusForbidden, "AuthorizationFailed")
+	_, err = client.List(context.Background())
+	g.Expect(err).To(MatchError(ContainSubstring("AuthorizationFailed")))
+
+	g.Expect(server.Put(id, map[string]interface{}{
+		"value": []interface{}{
+			map[string]interface{}{"name": "eastus", "displayName": "East US", "metadata": map[string]interface{}{"regionType": "Physical"}},
+			map[string]interface{}{"name": "westeurope", "displayName": "West Europe", "metadata": map[string]interface{}{"regionType": "Physical"}},
+			map[string]interface{}{"name": "europe", "displayName": "Europe", "metadata": map[string]interface{}{"regionType": "Logical"}},
+		},
+	})).To(Succeed())
+	locations, err := client.List(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(locations).To(Equal([]Location{
+		{Name: "eastus", DisplayName: "East US"},
+		{Name: "westeurope", DisplayName: "West Europe"},
+	}))
+	g.Expect(server.Requests()).To(HaveLen(2))
+
+	// Locations are served from the cache.
+	_, err = client.List(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(server.Requests()).To(HaveLen(2))
+}
@@ -64,6 +64,8 @@ type Service struct {
 	Scope ManagedClusterScope
 	async.Reconciler
 	CredentialGetter
+	// TokenClient gets the token of the user kubeconfig. It is created from the scope when nil.
+	TokenClient token.Client
 }
 
 // New creates a new service.
@@ -203,9 +205,13 @@ func (s *Service) GetUserKubeconfigData(ctx context.Context, managedClusterSpec
 
 // GetUserKubeConfigWithToken returns the kubeconfig with user token, for capz to create the target cluster.
 func (s *Service) GetUserKubeConfigWithToken(userKubeConfigData []byte, ctx context.Context, managedClusterSpec azure.ResourceSpecGetter) ([]byte, error) {
-	tokenClient, err := token.NewClient(s.Scope)
-	if err != nil {
-		return nil, errors.Wrap(err, "error while getting aad token client")
+	tokenClient := s.TokenClient
+	if tokenClient == nil {
+		var err error
+		tokenClient, err = token.NewClient(s.Scope)
+		if err != nil {
+			return nil, errors.Wrap(err, "error while getting aad token client")
+		}
 	}
 
 	token, err := tokenClient.GetAzureActiveDirectoryToken(ctx, aadResourceID)
@@ -28,6 +28,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters/mock_managedclusters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/token/mock_token"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -252,3 +253,84 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileKubeconfigWithLocalAccountsDisabled(t *testing.T) {
+	userKubeconfig := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: my-managedcluster
+  cluster:
+    server: https://my-managedcluster-fqdn:443
+users:
+- name: clusterUser
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: kubelogin
+contexts:
+- name: my-managedcluster
+  context:
+    cluster: my-managedcluster
+    user: clusterUser
+current-context: my-managedcluster
+`)
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(m *mock_managedclusters.MockCredentialGetterMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder, tc *mock_token.MockClientMockRecorder)
+	}{
+		{
+			name:          "user kubeconfig uses the aad token",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockCredentialGetterMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder, tc *mock_token.MockClientMockRecorder) {
+				s.IsAADEnabled().Return(true)
+				s.AreLocalAccountsDisabled().Return(true)
+				m.GetUserCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(userKubeconfig, nil)
+				tc.GetAzureActiveDirectoryToken(gomockinternal.AContext(), aadResourceID).Return("aad-token", nil)
+			},
+		},
+		{
+			name:          "fail to get aad token",
+			expectedError: "error while trying to get user kubeconfig with token: error while getting aad token for user kubeconfig: failed to get token",
+			expect: func(m *mock_managedclusters.MockCredentialGetterMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder, tc *mock_token.MockClientMockRecorder) {
+				s.IsAADEnabled().Return(true)
+				s.AreLocalAccountsDisabled().Return(true)
+				m.GetUserCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(userKubeconfig, nil)
+				tc.GetAzureActiveDirectoryToken(gomockinternal.AContext(), aadResourceID).Return("", errors.New("failed to get token"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
+			credsGetterMock := mock_managedclusters.NewMockCredentialGetter(mockCtrl)
+			tokenClientMock := mock_token.NewMockClient(mockCtrl)
+
+			tc.expect(credsGetterMock.EXPECT(), scopeMock.EXPECT(), tokenClientMock.EXPECT())
+
+			s := &Service{
+				Scope:            scopeMock,
+				CredentialGetter: credsGetterMock,
+				TokenClient:      tokenClientMock,
+			}
+
+			kubeconfig, userKubeconfigData, err := s.ReconcileKubeconfig(context.TODO(), fakeManagedClusterSpecWithAAD)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(userKubeconfigData).To(Equal(userKubeconfig))
+				g.Expect(string(kubeconfig)).To(ContainSubstring("token: aad-token"))
+				g.Expect(string(kubeconfig)).NotTo(ContainSubstring("kubelogin"))
+			}
+		})
+	}
+}
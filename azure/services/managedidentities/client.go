@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedidentities
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	userAssignedIdentities *armmsi.UserAssignedIdentitiesClient
+}
+
+// NewClient creates a new user-assigned identities client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create managedidentities client options")
+	}
+	factory, err := armmsi.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armmsi client factory")
+	}
+	return &AzureClient{factory.NewUserAssignedIdentitiesClient()}, nil
+}
+
+// Get gets the specified user-assigned identity.
+func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedidentities.AzureClient.Get")
+	defer done()
+
+	resp, err := ac.userAssignedIdentities.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Identity, nil
+}
+
+// CreateOrUpdateAsync creates or updates a user-assigned identity.
+// Creating a user-assigned identity is not a long running operation, so we don't ever return a poller.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armmsi.UserAssignedIdentitiesClientCreateOrUpdateResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedidentities.AzureClient.CreateOrUpdateAsync")
+	defer done()
+
+	identity, ok := parameters.(armmsi.Identity)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not an armmsi.Identity", parameters)
+	}
+	resp, err := ac.userAssignedIdentities.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), identity, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Identity, nil, nil
+}
+
+// DeleteAsync deletes a user-assigned identity.
+// Deleting a user-assigned identity is not a long running operation, so we don't ever return a poller.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armmsi.UserAssignedIdentitiesClientDeleteResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedidentities.AzureClient.DeleteAsync")
+	defer done()
+
+	_, err = ac.userAssignedIdentities.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	return nil, err
+}
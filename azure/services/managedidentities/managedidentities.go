@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedidentities
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "managedidentities"
+
+// ManagedIdentityScope defines the scope interface for a managed identities service.
+type ManagedIdentityScope interface {
+	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
+	ManagedIdentitySpec() azure.ResourceSpecGetter
+	ManagedIdentityRoleAssignmentSpecs(principalID *string) []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope ManagedIdentityScope
+	async.Reconciler
+	roleAssignments async.Reconciler
+}
+
+// New creates a new managed identities service.
+func New(scope ManagedIdentityScope) (*Service, error) {
+	client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	roleAssignmentsClient, err := roleassignments.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope: scope,
+		Reconciler: async.New[armmsi.UserAssignedIdentitiesClientCreateOrUpdateResponse,
+			armmsi.UserAssignedIdentitiesClientDeleteResponse](scope, client, client),
+		roleAssignments: async.New[armauthorization.RoleAssignmentsClientCreateResponse,
+			armauthorization.RoleAssignmentsClientDeleteResponse](scope, roleAssignmentsClient, roleAssignmentsClient),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile idempotently creates the user-assigned identity of the cluster and assigns it its roles.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "managedidentities.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.ManagedIdentitySpec()
+	if spec == nil {
+		log.V(2).Info("skip creation when no managed identity spec is found")
+		return nil
+	}
+
+	result, err := s.CreateOrUpdateResource(ctx, spec, ServiceName)
+	if err != nil {
+		s.Scope.UpdatePutStatus(infrav1.ManagedIdentityReadyCondition, ServiceName, err)
+		return err
+	}
+	identity, ok := result.(armmsi.Identity)
+	if !ok {
+		err := errors.Errorf("%T is not an armmsi.Identity", result)
+		s.Scope.UpdatePutStatus(infrav1.ManagedIdentityReadyCondition, ServiceName, err)
+		return err
+	}
+	var principalID *string
+	if identity.Properties != nil {
+		principalID = identity.Properties.PrincipalID
+	}
+
+	for _, roleAssignmentSpec := range s.Scope.ManagedIdentityRoleAssignmentSpecs(principalID) {
+		if _, err := s.roleAssignments.CreateOrUpdateResource(ctx, roleAssignmentSpec, ServiceName); err != nil {
+			s.Scope.UpdatePutStatus(infrav1.ManagedIdentityReadyCondition, ServiceName, err)
+			return errors.Wrapf(err, "failed to assign role to managed identity %s", spec.ResourceName())
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.ManagedIdentityReadyCondition, ServiceName, nil)
+	return nil
+}
+
+// Delete deletes the role assignments of the user-assigned identity of the cluster, then the identity. Role
+// assignments are not deleted with their identity, and may be scoped outside of the cluster resource group.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "managedidentities.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.ManagedIdentitySpec()
+	if spec == nil {
+		log.V(2).Info("skip deletion when no managed identity spec is found")
+		return nil
+	}
+
+	for _, roleAssignmentSpec := range s.Scope.ManagedIdentityRoleAssignmentSpecs(nil) {
+		if err := s.roleAssignments.DeleteResource(ctx, roleAssignmentSpec, ServiceName); err != nil {
+			s.Scope.UpdateDeleteStatus(infrav1.ManagedIdentityReadyCondition, ServiceName, err)
+			return errors.Wrapf(err, "failed to delete role assignment %s", roleAssignmentSpec.ResourceName())
+		}
+	}
+
+	err := s.DeleteResource(ctx, spec, ServiceName)
+	s.Scope.UpdateDeleteStatus(infrav1.ManagedIdentityReadyCondition, ServiceName, err)
+	return err
+}
+
+// IsManaged always returns true as the managed identity is only ever created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedidentities
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedidentities/mock_managedidentities"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeIdentitySpec = IdentitySpec{
+		Name:          "test-cluster-identity",
+		ResourceGroup: "test-rg",
+		ClusterName:   "test-cluster",
+		Location:      "test-location",
+	}
+	fakeIdentity = armmsi.Identity{
+		Properties: &armmsi.UserAssignedIdentityProperties{PrincipalID: ptr.To("principal-id")},
+	}
+	fakeRoleAssignmentSpec = roleassignments.RoleAssignmentSpec{
+		Name:             "role-assignment-name",
+		Scope:            "/subscriptions/123/resourceGroups/test-rg",
+		RoleDefinitionID: "/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/" + infrav1.ContributorRoleID,
+		PrincipalID:      ptr.To("principal-id"),
+	}
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func TestReconcileManagedIdentities(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_managedidentities.MockManagedIdentityScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, ra *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name: "create identity and assign its roles",
+			expect: func(s *mock_managedidentities.MockManagedIdentityScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ManagedIdentitySpec().Return(&fakeIdentitySpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeIdentitySpec, ServiceName).Return(fakeIdentity, nil)
+				s.ManagedIdentityRoleAssignmentSpecs(ptr.To("principal-id")).Return([]azure.ResourceSpecGetter{&fakeRoleAssignmentSpec})
+				ra.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRoleAssignmentSpec, ServiceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.ManagedIdentityReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name: "noop if the cluster has no managed identity",
+			expect: func(s *mock_managedidentities.MockManagedIdentityScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ManagedIdentitySpec().Return(nil)
+			},
+		},
+		{
+			name:          "error in creating identity",
+			expectedError: "#: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_managedidentities.MockManagedIdentityScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ManagedIdentitySpec().Return(&fakeIdentitySpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeIdentitySpec, ServiceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.ManagedIdentityReadyCondition, ServiceName, internalError)
+			},
+		},
+		{
+			name:          "error in assigning role",
+			expectedError: "failed to assign role to managed identity test-cluster-identity: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_managedidentities.MockManagedIdentityScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ManagedIdentitySpec().Return(&fakeIdentitySpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeIdentitySpec, ServiceName).Return(fakeIdentity, nil)
+				s.ManagedIdentityRoleAssignmentSpecs(ptr.To("principal-id")).Return([]azure.ResourceSpecGetter{&fakeRoleAssignmentSpec})
+				ra.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRoleAssignmentSpec, ServiceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.ManagedIdentityReadyCondition, ServiceName, internalError)
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_managedidentities.NewMockManagedIdentityScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+			roleAssignmentsMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT(), roleAssignmentsMock.EXPECT())
+
+			s := &Service{
+				Scope:           scopeMock,
+				Reconciler:      asyncMock,
+				roleAssignments: roleAssignmentsMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteManagedIdentities(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_managedidentities.MockManagedIdentityScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, ra *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name: "delete role assignments and identity",
+			expect: func(s *mock_managedidentities.MockManagedIdentityScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ManagedIdentitySpec().Return(&fakeIdentitySpec)
+				s.ManagedIdentityRoleAssignmentSpecs(nil).Return([]azure.ResourceSpecGetter{&fakeRoleAssignmentSpec})
+				gomock.InOrder(
+					ra.DeleteResource(gomockinternal.AContext(), &fakeRoleAssignmentSpec, ServiceName).Return(nil),
+					r.DeleteResource(gomockinternal.AContext(), &fakeIdentitySpec, ServiceName).Return(nil),
+				)
+				s.UpdateDeleteStatus(infrav1.ManagedIdentityReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name: "noop if the cluster has no managed identity",
+			expect: func(s *mock_managedidentities.MockManagedIdentityScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ManagedIdentitySpec().Return(nil)
+			},
+		},
+		{
+			name:          "identity is kept when its role assignments can't be deleted",
+			expectedError: "failed to delete role assignment role-assignment-name: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_managedidentities.MockManagedIdentityScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, ra *mock_async.MockReconcilerMockRecorder) {
+				s.ManagedIdentitySpec().Return(&fakeIdentitySpec)
+				s.ManagedIdentityRoleAssignmentSpecs(nil).Return([]azure.ResourceSpecGetter{&fakeRoleAssignmentSpec})
+				ra.DeleteResource(gomockinternal.AContext(), &fakeRoleAssignmentSpec, ServiceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.ManagedIdentityReadyCondition, ServiceName, internalError)
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_managedidentities.NewMockManagedIdentityScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+			roleAssignmentsMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT(), roleAssignmentsMock.EXPECT())
+
+			s := &Service{
+				Scope:           scopeMock,
+				Reconciler:      asyncMock,
+				roleAssignments: roleAssignmentsMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
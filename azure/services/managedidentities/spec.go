@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedidentities
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// IdentitySpec defines the specification for the user-assigned identity of a cluster.
+type IdentitySpec struct {
+	Name           string
+	ResourceGroup  string
+	ClusterName    string
+	Location       string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the identity.
+func (s *IdentitySpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *IdentitySpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for user-assigned identities.
+func (s *IdentitySpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the identity.
+func (s *IdentitySpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(armmsi.Identity); !ok {
+			return nil, errors.Errorf("%T is not an armmsi.Identity", existing)
+		}
+		// identity already exists
+		return nil, nil
+	}
+
+	return armmsi.Identity{
+		Location: ptr.To(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Role:        ptr.To(infrav1.CommonRole),
+			Additional:  s.AdditionalTags,
+		})),
+	}, nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedidentities
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	params, err := fakeIdentitySpec.Parameters(context.TODO(), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	identity, ok := params.(armmsi.Identity)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(identity.Location).To(Equal(ptr.To("test-location")))
+	g.Expect(identity.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster", ptr.To("owned")))
+
+	params, err = fakeIdentitySpec.Parameters(context.TODO(), fakeIdentity)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+
+	_, err = fakeIdentitySpec.Parameters(context.TODO(), "not an identity")
+	g.Expect(err).To(HaveOccurred())
+}
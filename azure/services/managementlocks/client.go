@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managementlocks
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armlocks"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	locks *armlocks.ManagementLocksClient
+}
+
+// NewClient creates a new management locks client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create managementlocks client options")
+	}
+	factory, err := armlocks.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armlocks client factory")
+	}
+	return &AzureClient{factory.NewManagementLocksClient()}, nil
+}
+
+// Get gets the specified management lock of a resource group.
+func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managementlocks.AzureClient.Get")
+	defer done()
+
+	resp, err := ac.locks.GetAtResourceGroupLevel(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.ManagementLockObject, nil
+}
+
+// CreateOrUpdateAsync creates or updates a management lock of a resource group.
+// Creating a management lock is not a long running operation, so we don't ever return a poller.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armlocks.ManagementLocksClientCreateOrUpdateAtResourceGroupLevelResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managementlocks.AzureClient.CreateOrUpdateAsync")
+	defer done()
+
+	lock, ok := parameters.(armlocks.ManagementLockObject)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not an armlocks.ManagementLockObject", parameters)
+	}
+	resp, err := ac.locks.CreateOrUpdateAtResourceGroupLevel(ctx, spec.ResourceGroupName(), spec.ResourceName(), lock, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.ManagementLockObject, nil, nil
+}
+
+// DeleteAsync deletes a management lock of a resource group.
+// Deleting a management lock is not a long running operation, so we don't ever return a poller.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armlocks.ManagementLocksClientDeleteAtResourceGroupLevelResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managementlocks.AzureClient.DeleteAsync")
+	defer done()
+
+	_, err = ac.locks.DeleteAtResourceGroupLevel(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	return nil, err
+}
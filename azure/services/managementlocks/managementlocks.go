@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managementlocks
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armlocks"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "managementlocks"
+
+// ManagementLockScope defines the scope interface for a management locks service.
+type ManagementLockScope interface {
+	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
+	ResourceGroupLockSpec() azure.ResourceSpecGetter
+	ShouldLockResourceGroup() bool
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope ManagementLockScope
+	async.Reconciler
+}
+
+// New creates a new management locks service.
+func New(scope ManagementLockScope) (*Service, error) {
+	client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope: scope,
+		Reconciler: async.New[armlocks.ManagementLocksClientCreateOrUpdateAtResourceGroupLevelResponse,
+			armlocks.ManagementLocksClientDeleteAtResourceGroupLevelResponse](scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile idempotently creates the management lock protecting the cluster resource group from deletion, or removes
+// it once the resource group should no longer be locked.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "managementlocks.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.ResourceGroupLockSpec()
+	if spec == nil {
+		log.V(2).Info("skip creation when no management lock spec is found")
+		return nil
+	}
+
+	if !s.Scope.ShouldLockResourceGroup() {
+		err := s.DeleteResource(ctx, spec, ServiceName)
+		s.Scope.UpdateDeleteStatus(infrav1.ResourceGroupLockedCondition, ServiceName, err)
+		return err
+	}
+
+	_, err := s.CreateOrUpdateResource(ctx, spec, ServiceName)
+	s.Scope.UpdatePutStatus(infrav1.ResourceGroupLockedCondition, ServiceName, err)
+	return err
+}
+
+// Delete deletes the management lock protecting the cluster resource group from deletion.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "managementlocks.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.ResourceGroupLockSpec()
+	if spec == nil {
+		log.V(2).Info("skip deletion when no management lock spec is found")
+		return nil
+	}
+
+	err := s.DeleteResource(ctx, spec, ServiceName)
+	s.Scope.UpdateDeleteStatus(infrav1.ResourceGroupLockedCondition, ServiceName, err)
+	return err
+}
+
+// IsManaged always returns true as the management lock is only ever created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
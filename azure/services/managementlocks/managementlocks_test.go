@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managementlocks
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managementlocks/mock_managementlocks"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeLockSpec = LockSpec{
+		Name:          "test-cluster-deletion-protection",
+		ResourceGroup: "test-rg",
+		Notes:         "test notes",
+	}
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func TestReconcileManagementLocks(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_managementlocks.MockManagementLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name: "create resource group lock",
+			expect: func(s *mock_managementlocks.MockManagementLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ResourceGroupLockSpec().Return(&fakeLockSpec)
+				s.ShouldLockResourceGroup().Return(true)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeLockSpec, ServiceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.ResourceGroupLockedCondition, ServiceName, nil)
+			},
+		},
+		{
+			name: "noop if the resource group is neither locked nor should be",
+			expect: func(s *mock_managementlocks.MockManagementLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ResourceGroupLockSpec().Return(nil)
+			},
+		},
+		{
+			name: "remove resource group lock when it is no longer wanted",
+			expect: func(s *mock_managementlocks.MockManagementLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ResourceGroupLockSpec().Return(&fakeLockSpec)
+				s.ShouldLockResourceGroup().Return(false)
+				r.DeleteResource(gomockinternal.AContext(), &fakeLockSpec, ServiceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.ResourceGroupLockedCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "error in creating resource group lock",
+			expectedError: "#: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_managementlocks.MockManagementLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ResourceGroupLockSpec().Return(&fakeLockSpec)
+				s.ShouldLockResourceGroup().Return(true)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeLockSpec, ServiceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.ResourceGroupLockedCondition, ServiceName, internalError)
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_managementlocks.NewMockManagementLockScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteManagementLocks(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_managementlocks.MockManagementLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name: "delete resource group lock",
+			expect: func(s *mock_managementlocks.MockManagementLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ResourceGroupLockSpec().Return(&fakeLockSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeLockSpec, ServiceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.ResourceGroupLockedCondition, ServiceName, nil)
+			},
+		},
+		{
+			name: "noop if the resource group is not locked",
+			expect: func(s *mock_managementlocks.MockManagementLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ResourceGroupLockSpec().Return(nil)
+			},
+		},
+		{
+			name:          "error in deleting resource group lock",
+			expectedError: "#: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_managementlocks.MockManagementLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ResourceGroupLockSpec().Return(&fakeLockSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeLockSpec, ServiceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.ResourceGroupLockedCondition, ServiceName, internalError)
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_managementlocks.NewMockManagementLockScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managementlocks
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armlocks"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+)
+
+// LockSpec defines the specification for a CanNotDelete management lock on a resource group.
+type LockSpec struct {
+	Name          string
+	ResourceGroup string
+	Notes         string
+}
+
+// ResourceName returns the name of the lock.
+func (s *LockSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the locked resource group.
+func (s *LockSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for management locks.
+func (s *LockSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the management lock.
+func (s *LockSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		existingLock, ok := existing.(armlocks.ManagementLockObject)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armlocks.ManagementLockObject", existing)
+		}
+		if existingLock.Properties != nil && ptr.Deref(existingLock.Properties.Level, "") == armlocks.LockLevelCanNotDelete {
+			// lock already exists
+			return nil, nil
+		}
+	}
+
+	return armlocks.ManagementLockObject{
+		Properties: &armlocks.ManagementLockProperties{
+			Level: ptr.To(armlocks.LockLevelCanNotDelete),
+			Notes: ptr.To(s.Notes),
+		},
+	}, nil
+}
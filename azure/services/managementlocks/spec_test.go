@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managementlocks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armlocks"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	params, err := fakeLockSpec.Parameters(context.TODO(), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	lock, ok := params.(armlocks.ManagementLockObject)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(lock.Properties.Level).To(Equal(ptr.To(armlocks.LockLevelCanNotDelete)))
+	g.Expect(lock.Properties.Notes).To(Equal(ptr.To("test notes")))
+
+	params, err = fakeLockSpec.Parameters(context.TODO(), armlocks.ManagementLockObject{
+		Properties: &armlocks.ManagementLockProperties{Level: ptr.To(armlocks.LockLevelCanNotDelete)},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+
+	params, err = fakeLockSpec.Parameters(context.TODO(), armlocks.ManagementLockObject{
+		Properties: &armlocks.ManagementLockProperties{Level: ptr.To(armlocks.LockLevelReadOnly)},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).NotTo(BeNil())
+
+	_, err = fakeLockSpec.Parameters(context.TODO(), "not a lock")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package marketplaceagreements
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"k8s.io/utils/ptr"
+)
+
+// acceptTerms is whether EnsureAccepted accepts the marketplace terms that are not accepted yet, see SetAcceptTerms.
+var acceptTerms bool
+
+// SetAcceptTerms makes EnsureAccepted accept the marketplace terms of the image plans that are not accepted yet on
+// the subscription. Accepting the terms is a legal agreement, so it must be enabled explicitly.
+func SetAcceptTerms(accept bool) {
+	acceptTerms = accept
+}
+
+// EnsureAccepted returns an empty string if the marketplace terms of the plan of an image are accepted on the
+// subscription, accepting them first if enabled with SetAcceptTerms. Otherwise, it returns a message explaining how
+// to accept them. Nothing is checked for images without a plan.
+func EnsureAccepted(ctx context.Context, client Client, plan *armcompute.Plan) (string, error) {
+	if plan == nil {
+		return "", nil
+	}
+	publisher, offer, name := ptr.Deref(plan.Publisher, ""), ptr.Deref(plan.Product, ""), ptr.Deref(plan.Name, "")
+
+	accepted, err := client.IsAccepted(ctx, publisher, offer, name)
+	if err != nil {
+		return "", err
+	}
+	if accepted {
+		return "", nil
+	}
+	if acceptTerms {
+		return "", client.Accept(ctx, publisher, offer, name)
+	}
+	return fmt.Sprintf("the marketplace terms of image plan %s/%s/%s must be accepted on the subscription, e.g. with "+
+		"\"az vm image terms accept --publisher %s --offer %s --plan %s\", or automatically by starting the controller "+
+		"manager with --accept-marketplace-terms", publisher, offer, name, publisher, offer, name), nil
+}
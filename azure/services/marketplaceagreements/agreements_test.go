@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package marketplaceagreements
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/marketplaceagreements/mock_marketplaceagreements"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func TestEnsureAccepted(t *testing.T) {
+	plan := &armcompute.Plan{Publisher: ptr.To("kinvolk"), Product: ptr.To("flatcar"), Name: ptr.To("stable")}
+	tests := []struct {
+		name        string
+		plan        *armcompute.Plan
+		acceptTerms bool
+		expect      func(m *mock_marketplaceagreements.MockClientMockRecorder)
+		wantMessage string
+		wantErr     string
+	}{
+		{
+			name: "image without a plan",
+			plan: nil,
+		},
+		{
+			name: "accepted terms",
+			plan: plan,
+			expect: func(m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.IsAccepted(gomockinternal.AContext(), "kinvolk", "flatcar", "stable").Return(true, nil)
+			},
+		},
+		{
+			name: "terms not accepted",
+			plan: plan,
+			expect: func(m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.IsAccepted(gomockinternal.AContext(), "kinvolk", "flatcar", "stable").Return(false, nil)
+			},
+			wantMessage: "az vm image terms accept --publisher kinvolk --offer flatcar --plan stable",
+		},
+		{
+			name:        "terms not accepted are accepted when enabled",
+			plan:        plan,
+			acceptTerms: true,
+			expect: func(m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.IsAccepted(gomockinternal.AContext(), "kinvolk", "flatcar", "stable").Return(false, nil)
+				m.Accept(gomockinternal.AContext(), "kinvolk", "flatcar", "stable").Return(nil)
+			},
+		},
+		{
+			name: "failure to check the terms",
+			plan: plan,
+			expect: func(m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.IsAccepted(gomockinternal.AContext(), "kinvolk", "flatcar", "stable").Return(false, errors.New("boom"))
+			},
+			wantErr: "boom",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			SetAcceptTerms(tc.acceptTerms)
+			defer SetAcceptTerms(false)
+			client := mock_marketplaceagreements.NewMockClient(mockCtrl)
+			if tc.expect != nil {
+				tc.expect(client.EXPECT())
+			}
+
+			message, err := EnsureAccepted(context.Background(), client, tc.plan)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.wantMessage == "" {
+				g.Expect(message).To(BeEmpty())
+			} else {
+				g.Expect(message).To(ContainSubstring(tc.wantMessage))
+			}
+		})
+	}
+}
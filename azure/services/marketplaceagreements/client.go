@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package marketplaceagreements
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const apiVersion = "2021-01-01"
+
+// Client checks and accepts the marketplace terms of VM image plans on the subscription.
+type Client interface {
+	IsAccepted(ctx context.Context, publisher, offer, plan string) (bool, error)
+	Accept(ctx context.Context, publisher, offer, plan string) error
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	client *arm.Client
+	auth   azure.Authorizer
+}
+
+var (
+	_             Client = &AzureClient{}
+	doOnce        sync.Once
+	acceptedCache ttllru.PeekingCacher
+)
+
+// NewClient creates a new marketplace agreements client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create marketplace agreements client options")
+	}
+	client, err := arm.NewClient("marketplaceagreements.AzureClient", "v1.0.0", auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create marketplace agreements client")
+	}
+	return &AzureClient{
+		client: client,
+		auth:   auth,
+	}, nil
+}
+
+// IsAccepted returns true if the marketplace terms of a VM image plan are accepted on the subscription. Accepted
+// terms are cached since they are seldom canceled, terms that are not accepted yet are looked up again on the next call.
+func (ac *AzureClient) IsAccepted(ctx context.Context, publisher, offer, plan string) (bool, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "marketplaceagreements.AzureClient.IsAccepted")
+	defer done()
+
+	cache, err := getCache()
+	if err != nil {
+		return false, err
+	}
+	key := ac.cacheKey(publisher, offer, plan)
+	if _, ok := cache.Get(key); ok {
+		return true, nil
+	}
+
+	agreement, err := ac.get(ctx, publisher, offer, plan)
+	if err != nil {
+		return false, err
+	}
+	if !isAccepted(agreement) {
+		return false, nil
+	}
+	_ = cache.Add(key, struct{}{})
+	return true, nil
+}
+
+// Accept accepts the marketplace terms of a VM image plan on the subscription.
+func (ac *AzureClient) Accept(ctx context.Context, publisher, offer, plan string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "marketplaceagreements.AzureClient.Accept")
+	defer done()
+
+	cache, err := getCache()
+	if err != nil {
+		return err
+	}
+
+	// The terms are accepted by sending back the current agreement, which holds the signature of the terms.
+	agreement, err := ac.get(ctx, publisher, offer, plan)
+	if err != nil {
+		return err
+	}
+	properties, ok := agreement["properties"].(map[string]interface{})
+	if !ok {
+		return errors.Errorf("marketplace terms of plan %s/%s/%s have no properties", publisher, offer, plan)
+	}
+	properties["accepted"] = true
+
+	req, err := ac.newRequest(ctx, http.MethodPut, publisher, offer, plan)
+	if err != nil {
+		return err
+	}
+	if err := runtime.MarshalAsJSON(req, agreement); err != nil {
+		return errors.Wrapf(err, "failed to encode marketplace terms of plan %s/%s/%s", publisher, offer, plan)
+	}
+	resp, err := ac.client.Pipeline().Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to accept marketplace terms of plan %s/%s/%s", publisher, offer, plan)
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return errors.Wrapf(runtime.NewResponseError(resp), "failed to accept marketplace terms of plan %s/%s/%s", publisher, offer, plan)
+	}
+	_ = cache.Add(ac.cacheKey(publisher, offer, plan), struct{}{})
+	return nil
+}
+
+// get returns the current marketplace agreement of a VM image plan on the subscription.
+func (ac *AzureClient) get(ctx context.Context, publisher, offer, plan string) (map[string]interface{}, error) {
+	req, err := ac.newRequest(ctx, http.MethodGet, publisher, offer, plan)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ac.client.Pipeline().Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get marketplace terms of plan %s/%s/%s", publisher, offer, plan)
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return nil, errors.Wrapf(runtime.NewResponseError(resp), "failed to get marketplace terms of plan %s/%s/%s", publisher, offer, plan)
+	}
+
+	agreement := map[string]interface{}{}
+	if err := runtime.UnmarshalAsJSON(resp, &agreement); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse marketplace terms of plan %s/%s/%s", publisher, offer, plan)
+	}
+	return agreement, nil
+}
+
+// newRequest creates a request for the current marketplace agreement of a VM image plan on the subscription.
+func (ac *AzureClient) newRequest(ctx context.Context, method, publisher, offer, plan string) (*policy.Request, error) {
+	urlPath := runtime.JoinPaths("/subscriptions", ac.auth.SubscriptionID(), "providers/Microsoft.MarketplaceOrdering/offerTypes/virtualmachine",
+		"publishers", publisher, "offers", offer, "plans", plan, "agreements/current")
+	req, err := runtime.NewRequest(ctx, method, runtime.JoinPaths(ac.client.Endpoint(), urlPath))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create marketplace agreement request")
+	}
+	query := req.Raw().URL.Query()
+	query.Set("api-version", apiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header["Accept"] = []string{"application/json"}
+	return req, nil
+}
+
+func (ac *AzureClient) cacheKey(publisher, offer, plan string) string {
+	return strings.Join([]string{ac.auth.HashKey(), publisher, offer, plan}, "/")
+}
+
+// isAccepted returns true if a marketplace agreement is accepted.
+func isAccepted(agreement map[string]interface{}) bool {
+	properties, ok := agreement["properties"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	accepted, ok := properties["accepted"].(bool)
+	return ok && accepted
+}
+
+func getCache() (ttllru.PeekingCacher, error) {
+	var err error
+	doOnce.Do(func() {
+		acceptedCache, err = ttllru.New(128, time.Hour)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating LRU cache for marketplace agreements")
+	}
+	return acceptedCache, nil
+}
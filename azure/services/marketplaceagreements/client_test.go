@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package marketplaceagreements
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/fakearm"
+)
+
+func TestIsAcceptedAndAccept(t *testing.T) {
+	g := NewWithT(t)
+
+	server := fakearm.NewServer()
+	defer server.Close()
+	server.Register()
+
+	client, err := NewClient(server.Authorizer("123"))
+	g.Expect(err).NotTo(HaveOccurred())
+	id := "/subscriptions/123/providers/Microsoft.MarketplaceOrdering/offerTypes/virtualmachine/publishers/kinvolk/offers/flatcar/plans/stable/agreements/current"
+
+	server.InjectError(http.MethodGet, id, http.StatusForbidden, "AuthorizationFailed")
+	_, err = client.IsAccepted(context.Background(), "kinvolk", "flatcar", "stable")
+	g.Expect(err).To(MatchError(ContainSubstring("AuthorizationFailed")))
+
+	g.Expect(server.Put(id, map[string]interface{}{"properties": map[string]interface{}{
+		"accepted":  false,
+		"signature": "ABCDEF",
+	}})).To(Succeed())
+	accepted, err := client.IsAccepted(context.Background(), "kinvolk", "flatcar", "stable")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(accepted).To(BeFalse())
+
+	g.Expect(client.Accept(context.Background(), "kinvolk", "flatcar", "stable")).To(Succeed())
+	body, ok := server.Get(id)
+	g.Expect(ok).To(BeTrue())
+	var agreement struct {
+		Properties struct {
+			Accepted  bool   `json:"accepted"`
+			Signature string `json:"signature"`
+		} `json:"properties"`
+	}
+	g.Expect(json.Unmarshal(body, &agreement)).To(Succeed())
+	g.Expect(agreement.Properties.Accepted).To(BeTrue())
+	g.Expect(agreement.Properties.Signature).To(Equal("ABCDEF"))
+	g.Expect(server.Requests()).To(HaveLen(4))
+
+	// Accepted terms are served from the cache.
+	accepted, err = client.IsAccepted(context.Background(), "kinvolk", "flatcar", "stable")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(accepted).To(BeTrue())
+	g.Expect(server.Requests()).To(HaveLen(4))
+}
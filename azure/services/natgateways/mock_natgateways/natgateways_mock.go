@@ -143,6 +143,34 @@ func (mr *MockNatGatewayScopeMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockNatGatewayScope)(nil).BaseURI))
 }
 
+// BootstrapDataKeyVault mocks base method.
+func (m *MockNatGatewayScope) BootstrapDataKeyVault() *v1beta1.BootstrapDataKeyVault {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataKeyVault")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataKeyVault)
+	return ret0
+}
+
+// BootstrapDataKeyVault indicates an expected call of BootstrapDataKeyVault.
+func (mr *MockNatGatewayScopeMockRecorder) BootstrapDataKeyVault() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataKeyVault", reflect.TypeOf((*MockNatGatewayScope)(nil).BootstrapDataKeyVault))
+}
+
+// BootstrapDataStorageAccountName mocks base method.
+func (m *MockNatGatewayScope) BootstrapDataStorageAccountName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorageAccountName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BootstrapDataStorageAccountName indicates an expected call of BootstrapDataStorageAccountName.
+func (mr *MockNatGatewayScopeMockRecorder) BootstrapDataStorageAccountName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorageAccountName", reflect.TypeOf((*MockNatGatewayScope)(nil).BootstrapDataStorageAccountName))
+}
+
 // ClientID mocks base method.
 func (m *MockNatGatewayScope) ClientID() string {
 	m.ctrl.T.Helper()
@@ -421,6 +449,20 @@ func (mr *MockNatGatewayScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockNatGatewayScope)(nil).Location))
 }
 
+// ManagedIdentityProviderID mocks base method.
+func (m *MockNatGatewayScope) ManagedIdentityProviderID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedIdentityProviderID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ManagedIdentityProviderID indicates an expected call of ManagedIdentityProviderID.
+func (mr *MockNatGatewayScopeMockRecorder) ManagedIdentityProviderID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedIdentityProviderID", reflect.TypeOf((*MockNatGatewayScope)(nil).ManagedIdentityProviderID))
+}
+
 // NatGatewaySpecs mocks base method.
 func (m *MockNatGatewayScope) NatGatewaySpecs() []azure.ASOResourceSpecGetter[*v1api20220701.NatGateway] {
 	m.ctrl.T.Helper()
@@ -435,6 +477,48 @@ func (mr *MockNatGatewayScopeMockRecorder) NatGatewaySpecs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NatGatewaySpecs", reflect.TypeOf((*MockNatGatewayScope)(nil).NatGatewaySpecs))
 }
 
+// NodeInternalLBName mocks base method.
+func (m *MockNatGatewayScope) NodeInternalLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeInternalLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeInternalLBName indicates an expected call of NodeInternalLBName.
+func (mr *MockNatGatewayScopeMockRecorder) NodeInternalLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeInternalLBName", reflect.TypeOf((*MockNatGatewayScope)(nil).NodeInternalLBName))
+}
+
+// NodeInternalLBPoolName mocks base method.
+func (m *MockNatGatewayScope) NodeInternalLBPoolName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeInternalLBPoolName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeInternalLBPoolName indicates an expected call of NodeInternalLBPoolName.
+func (mr *MockNatGatewayScopeMockRecorder) NodeInternalLBPoolName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeInternalLBPoolName", reflect.TypeOf((*MockNatGatewayScope)(nil).NodeInternalLBPoolName))
+}
+
+// NodeRoutes mocks base method.
+func (m *MockNatGatewayScope) NodeRoutes() *v1beta1.NodeRoutesSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeRoutes")
+	ret0, _ := ret[0].(*v1beta1.NodeRoutesSpec)
+	return ret0
+}
+
+// NodeRoutes indicates an expected call of NodeRoutes.
+func (mr *MockNatGatewayScopeMockRecorder) NodeRoutes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeRoutes", reflect.TypeOf((*MockNatGatewayScope)(nil).NodeRoutes))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockNatGatewayScope) NodeSubnets() []v1beta1.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -477,6 +561,20 @@ func (mr *MockNatGatewayScopeMockRecorder) OutboundPoolName(arg0 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboundPoolName", reflect.TypeOf((*MockNatGatewayScope)(nil).OutboundPoolName), arg0)
 }
 
+// PlatformFaultDomainCount mocks base method.
+func (m *MockNatGatewayScope) PlatformFaultDomainCount() *int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlatformFaultDomainCount")
+	ret0, _ := ret[0].(*int32)
+	return ret0
+}
+
+// PlatformFaultDomainCount indicates an expected call of PlatformFaultDomainCount.
+func (mr *MockNatGatewayScopeMockRecorder) PlatformFaultDomainCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlatformFaultDomainCount", reflect.TypeOf((*MockNatGatewayScope)(nil).PlatformFaultDomainCount))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockNatGatewayScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -491,6 +589,34 @@ func (mr *MockNatGatewayScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockNatGatewayScope)(nil).ResourceGroup))
 }
 
+// SSHKeyPairSecretName mocks base method.
+func (m *MockNatGatewayScope) SSHKeyPairSecretName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHKeyPairSecretName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SSHKeyPairSecretName indicates an expected call of SSHKeyPairSecretName.
+func (mr *MockNatGatewayScopeMockRecorder) SSHKeyPairSecretName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHKeyPairSecretName", reflect.TypeOf((*MockNatGatewayScope)(nil).SSHKeyPairSecretName))
+}
+
+// SSHPublicKeys mocks base method.
+func (m *MockNatGatewayScope) SSHPublicKeys(role string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHPublicKeys", role)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SSHPublicKeys indicates an expected call of SSHPublicKeys.
+func (mr *MockNatGatewayScopeMockRecorder) SSHPublicKeys(role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHPublicKeys", reflect.TypeOf((*MockNatGatewayScope)(nil).SSHPublicKeys), role)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockNatGatewayScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
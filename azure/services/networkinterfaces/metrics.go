@@ -0,0 +1,31 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkinterfaces
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var backendPoolMembershipsRepaired = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "capz_backend_pool_memberships_repaired_total",
+	Help: "Number of times an existing network interface was added back to the load balancer or application gateway backend pools it was missing from, by resource group of the network interface.",
+}, []string{"resource_group"})
+
+func init() {
+	metrics.Registry.MustRegister(backendPoolMembershipsRepaired)
+}
@@ -19,6 +19,7 @@ package networkinterfaces
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
@@ -27,6 +28,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // NICSpec defines the specification for a Network Interface.
@@ -46,15 +48,21 @@ type NICSpec struct {
 	PublicLBNATRuleName       string
 	InternalLBName            string
 	InternalLBAddressPoolName string
-	PublicIPName              string
-	AcceleratedNetworking     *bool
-	IPv6Enabled               bool
-	EnableIPForwarding        bool
-	SKU                       *resourceskus.SKU
-	DNSServers                []string
-	AdditionalTags            infrav1.Tags
-	ClusterName               string
-	IPConfigs                 []IPConfig
+	// LoadBalancerBackendPoolIDs are the IDs of existing load balancer backend pools, managed outside of the provider,
+	// the primary IP configuration joins.
+	LoadBalancerBackendPoolIDs []string
+	// ApplicationGatewayBackendPoolIDs are the IDs of existing application gateway backend pools, managed outside of the
+	// provider, the primary IP configuration joins.
+	ApplicationGatewayBackendPoolIDs []string
+	PublicIPName                     string
+	AcceleratedNetworking            *bool
+	IPv6Enabled                      bool
+	EnableIPForwarding               bool
+	SKU                              *resourceskus.SKU
+	DNSServers                       []string
+	AdditionalTags                   infrav1.Tags
+	ClusterName                      string
+	IPConfigs                        []IPConfig
 }
 
 // IPConfig defines the specification for an IP address configuration.
@@ -80,11 +88,21 @@ func (s *NICSpec) OwnerResourceName() string {
 
 // Parameters returns the parameters for the network interface.
 func (s *NICSpec) Parameters(ctx context.Context, existing interface{}) (parameters interface{}, err error) {
+	_, log, done := tele.StartSpanWithLogger(ctx, "networkinterfaces.NICSpec.Parameters")
+	defer done()
+
 	if existing != nil {
-		if _, ok := existing.(armnetwork.Interface); !ok {
+		existingNIC, ok := existing.(armnetwork.Interface)
+		if !ok {
 			return nil, errors.Errorf("%T is not an armnetwork.Interface", existing)
 		}
-		// network interface already exists
+		// network interface already exists, only add it back to the backend pools it was removed from, e.g. by the
+		// migration of a Basic load balancer to the Standard SKU, a manual edit or a failed attach operation.
+		if addMissingBackendPools(existingNIC, s.backendAddressPools(), s.applicationGatewayBackendAddressPools()) {
+			log.Info("adding network interface back to the backend pools it is missing from", "networkInterface", s.Name, "resourceGroup", s.ResourceGroup)
+			backendPoolMembershipsRepaired.WithLabelValues(s.ResourceGroup).Inc()
+			return existingNIC, nil
+		}
 		return nil, nil
 	}
 
@@ -103,14 +121,7 @@ func (s *NICSpec) Parameters(ctx context.Context, existing interface{}) (paramet
 		primaryIPConfig.PrivateIPAddress = ptr.To(s.StaticIPAddress)
 	}
 
-	backendAddressPools := []*armnetwork.BackendAddressPool{}
 	if s.PublicLBName != "" {
-		if s.PublicLBAddressPoolName != "" {
-			backendAddressPools = append(backendAddressPools,
-				&armnetwork.BackendAddressPool{
-					ID: ptr.To(azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.PublicLBName, s.PublicLBAddressPoolName)),
-				})
-		}
 		if s.PublicLBNATRuleName != "" {
 			primaryIPConfig.LoadBalancerInboundNatRules = []*armnetwork.InboundNatRule{
 				{
@@ -119,13 +130,10 @@ func (s *NICSpec) Parameters(ctx context.Context, existing interface{}) (paramet
 			}
 		}
 	}
-	if s.InternalLBName != "" && s.InternalLBAddressPoolName != "" {
-		backendAddressPools = append(backendAddressPools,
-			&armnetwork.BackendAddressPool{
-				ID: ptr.To(azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.InternalLBName, s.InternalLBAddressPoolName)),
-			})
+	primaryIPConfig.LoadBalancerBackendAddressPools = s.backendAddressPools()
+	if pools := s.applicationGatewayBackendAddressPools(); len(pools) > 0 {
+		primaryIPConfig.ApplicationGatewayBackendAddressPools = pools
 	}
-	primaryIPConfig.LoadBalancerBackendAddressPools = backendAddressPools
 
 	if s.PublicIPName != "" {
 		primaryIPConfig.PublicIPAddress = &armnetwork.PublicIPAddress{
@@ -141,6 +149,8 @@ func (s *NICSpec) Parameters(ctx context.Context, existing interface{}) (paramet
 
 		accelNet := s.SKU.HasCapability(resourceskus.AcceleratedNetworking)
 		s.AcceleratedNetworking = &accelNet
+	} else if *s.AcceleratedNetworking && s.SKU != nil && !s.SKU.HasCapability(resourceskus.AcceleratedNetworking) {
+		return nil, azure.WithTerminalError(errors.Errorf("VM size %s does not support accelerated networking. Select a different VM size or disable accelerated networking", ptr.Deref(s.SKU.Name, "")))
 	}
 
 	dnsSettings := armnetwork.InterfaceDNSSettings{}
@@ -218,3 +228,79 @@ func (s *NICSpec) Parameters(ctx context.Context, existing interface{}) (paramet
 		})),
 	}, nil
 }
+
+// backendAddressPools returns the load balancer backend pools of the primary IP configuration.
+func (s *NICSpec) backendAddressPools() []*armnetwork.BackendAddressPool {
+	backendAddressPools := []*armnetwork.BackendAddressPool{}
+	if s.PublicLBName != "" && s.PublicLBAddressPoolName != "" {
+		backendAddressPools = append(backendAddressPools,
+			&armnetwork.BackendAddressPool{
+				ID: ptr.To(azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.PublicLBName, s.PublicLBAddressPoolName)),
+			})
+	}
+	if s.InternalLBName != "" && s.InternalLBAddressPoolName != "" {
+		backendAddressPools = append(backendAddressPools,
+			&armnetwork.BackendAddressPool{
+				ID: ptr.To(azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.InternalLBName, s.InternalLBAddressPoolName)),
+			})
+	}
+	for _, id := range s.LoadBalancerBackendPoolIDs {
+		backendAddressPools = append(backendAddressPools, &armnetwork.BackendAddressPool{ID: ptr.To(id)})
+	}
+	return backendAddressPools
+}
+
+// applicationGatewayBackendAddressPools returns the application gateway backend pools of the primary IP configuration.
+func (s *NICSpec) applicationGatewayBackendAddressPools() []*armnetwork.ApplicationGatewayBackendAddressPool {
+	pools := make([]*armnetwork.ApplicationGatewayBackendAddressPool, 0, len(s.ApplicationGatewayBackendPoolIDs))
+	for _, id := range s.ApplicationGatewayBackendPoolIDs {
+		pools = append(pools, &armnetwork.ApplicationGatewayBackendAddressPool{ID: ptr.To(id)})
+	}
+	return pools
+}
+
+// addMissingBackendPools adds the load balancer and application gateway pools missing from the primary IP
+// configuration of an existing network interface, and reports whether it did.
+func addMissingBackendPools(nic armnetwork.Interface, pools []*armnetwork.BackendAddressPool, gatewayPools []*armnetwork.ApplicationGatewayBackendAddressPool) bool {
+	if nic.Properties == nil {
+		return false
+	}
+	var primary *armnetwork.InterfaceIPConfiguration
+	for _, ipConfig := range nic.Properties.IPConfigurations {
+		if ipConfig.Properties != nil && ptr.Deref(ipConfig.Properties.Primary, false) {
+			primary = ipConfig
+			break
+		}
+	}
+	if primary == nil {
+		return false
+	}
+	added := false
+	for _, pool := range pools {
+		found := false
+		for _, existing := range primary.Properties.LoadBalancerBackendAddressPools {
+			if strings.EqualFold(ptr.Deref(existing.ID, ""), ptr.Deref(pool.ID, "")) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			primary.Properties.LoadBalancerBackendAddressPools = append(primary.Properties.LoadBalancerBackendAddressPools, pool)
+			added = true
+		}
+	}
+	for _, pool := range gatewayPools {
+		found := false
+		for _, existing := range primary.Properties.ApplicationGatewayBackendAddressPools {
+			if strings.EqualFold(ptr.Deref(existing.ID, ""), ptr.Deref(pool.ID, "")) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			primary.Properties.ApplicationGatewayBackendAddressPools = append(primary.Properties.ApplicationGatewayBackendAddressPools, pool)
+			added = true
+		}
+	}
+	return added
+}
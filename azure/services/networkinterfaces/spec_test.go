@@ -24,6 +24,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/format"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 )
@@ -268,6 +269,131 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "unable to get required network interface SKU from machine cache",
 		},
+		{
+			name: "error when accelerated networking is requested but not supported by the SKU",
+			spec: &NICSpec{
+				Name:                  "my-net-interface",
+				ResourceGroup:         "my-rg",
+				Location:              "fake-location",
+				SubscriptionID:        "123",
+				MachineName:           "azure-test1",
+				SubnetName:            "my-subnet",
+				VNetName:              "my-vnet",
+				VNetResourceGroup:     "my-rg",
+				AcceleratedNetworking: ptr.To(true),
+				SKU:                   &resourceskus.SKU{Name: ptr.To("Standard_B2s")},
+				ClusterName:           "my-cluster",
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: VM size Standard_B2s does not support accelerated networking. Select a different VM size or disable accelerated networking. Object will not be requeued",
+		},
+		{
+			name: "noop if network interface exists in its backend pools",
+			spec: &fakeControlPlaneNICSpec,
+			existing: armnetwork.Interface{
+				Properties: &armnetwork.InterfacePropertiesFormat{
+					IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+						{
+							Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+								Primary: ptr.To(true),
+								LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{
+									{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/backendAddressPools/my-public-lb-backendPool")},
+									{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-internal-lb/backendAddressPools/my-internal-lb-backendPool")},
+								},
+							},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "add existing network interface back to a missing backend pool",
+			spec: &fakeControlPlaneNICSpec,
+			existing: armnetwork.Interface{
+				Properties: &armnetwork.InterfacePropertiesFormat{
+					IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+						{
+							Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+								Primary: ptr.To(true),
+								LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{
+									{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-internal-lb/backendAddressPools/my-internal-lb-backendPool")},
+								},
+							},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				pools := result.(armnetwork.Interface).Properties.IPConfigurations[0].Properties.LoadBalancerBackendAddressPools
+				g.Expect(pools).To(HaveLen(2))
+				g.Expect(pools[1].ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/backendAddressPools/my-public-lb-backendPool")))
+			},
+		},
+		{
+			name: "add existing network interface back to a missing application gateway backend pool",
+			spec: &NICSpec{
+				Name:                             "my-net-interface",
+				ResourceGroup:                    "my-rg",
+				SubscriptionID:                   "123",
+				ApplicationGatewayBackendPoolIDs: []string{"/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/applicationGateways/my-agw/backendAddressPools/my-pool"},
+			},
+			existing: armnetwork.Interface{
+				Properties: &armnetwork.InterfacePropertiesFormat{
+					IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+						{
+							Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+								Primary: ptr.To(true),
+							},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				pools := result.(armnetwork.Interface).Properties.IPConfigurations[0].Properties.ApplicationGatewayBackendAddressPools
+				g.Expect(pools).To(HaveLen(1))
+				g.Expect(pools[0].ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/applicationGateways/my-agw/backendAddressPools/my-pool")))
+			},
+		},
+		{
+			name: "get parameters for network interface in existing backend pools",
+			spec: &NICSpec{
+				Name:                             "my-net-interface",
+				ResourceGroup:                    "my-rg",
+				Location:                         "fake-location",
+				SubscriptionID:                   "123",
+				MachineName:                      "azure-test1",
+				SubnetName:                       "my-subnet",
+				VNetName:                         "my-vnet",
+				VNetResourceGroup:                "my-rg",
+				InternalLBName:                   "my-internal-lb",
+				InternalLBAddressPoolName:        "my-internal-lb-backendPool",
+				LoadBalancerBackendPoolIDs:       []string{"/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/loadBalancers/my-lb/backendAddressPools/my-pool"},
+				ApplicationGatewayBackendPoolIDs: []string{"/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/applicationGateways/my-agw/backendAddressPools/my-pool"},
+				AcceleratedNetworking:            ptr.To(false),
+				SKU:                              &fakeSku,
+				ClusterName:                      "my-cluster",
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				ipConfig := result.(armnetwork.Interface).Properties.IPConfigurations[0].Properties
+				g.Expect(ipConfig.LoadBalancerBackendAddressPools).To(Equal([]*armnetwork.BackendAddressPool{
+					{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-internal-lb/backendAddressPools/my-internal-lb-backendPool")},
+					{ID: ptr.To("/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/loadBalancers/my-lb/backendAddressPools/my-pool")},
+				}))
+				g.Expect(ipConfig.ApplicationGatewayBackendAddressPools).To(Equal([]*armnetwork.ApplicationGatewayBackendAddressPool{
+					{ID: ptr.To("/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/applicationGateways/my-agw/backendAddressPools/my-pool")},
+				}))
+			},
+		},
 		{
 			name:     "get parameters for network interface with static private IP",
 			spec:     &fakeStaticPrivateIPNICSpec,
@@ -687,3 +813,33 @@ func TestParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestParametersCountsRepairedBackendPoolMemberships(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := fakeControlPlaneNICSpec
+	spec.ResourceGroup = "repaired-rg"
+	existing := armnetwork.Interface{
+		Properties: &armnetwork.InterfacePropertiesFormat{
+			IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+				{
+					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+						Primary: ptr.To(true),
+					},
+				},
+			},
+		},
+	}
+	counter := backendPoolMembershipsRepaired.WithLabelValues("repaired-rg")
+
+	result, err := spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).NotTo(BeNil())
+	g.Expect(testutil.ToFloat64(counter)).To(Equal(1.0))
+
+	// The network interface is back in its backend pools, there is nothing left to repair.
+	result, err = spec.Parameters(context.TODO(), result)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(BeNil())
+	g.Expect(testutil.ToFloat64(counter)).To(Equal(1.0))
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkwatchers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkwatchers/mock_networkwatchers"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeFlowLogSpec1 = FlowLogSpec{
+		Name:               "node-nsg-my-rg-flowlog",
+		NetworkWatcherName: "NetworkWatcher_westus",
+		NetworkWatcherRG:   "NetworkWatcherRG",
+		Location:           "westus",
+		ClusterName:        "my-cluster",
+		SecurityGroupID:    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/node-nsg",
+		StorageAccountID:   "/subscriptions/123/resourceGroups/logs-rg/providers/Microsoft.Storage/storageAccounts/flowlogs",
+	}
+	fakeFlowLogSpec2 = FlowLogSpec{
+		Name:               "controlplane-nsg-my-rg-flowlog",
+		NetworkWatcherName: "NetworkWatcher_westus",
+		NetworkWatcherRG:   "NetworkWatcherRG",
+		Location:           "westus",
+		ClusterName:        "my-cluster",
+		SecurityGroupID:    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/controlplane-nsg",
+		StorageAccountID:   "/subscriptions/123/resourceGroups/logs-rg/providers/Microsoft.Storage/storageAccounts/flowlogs",
+	}
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+	notDoneError  = azure.NewOperationNotDoneError(&infrav1.Future{})
+)
+
+func TestReconcileFlowLogs(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_networkwatchers.MockFlowLogScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "flow logs successfully created",
+			expectedError: "",
+			expect: func(s *mock_networkwatchers.MockFlowLogScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FlowLogSpecs().Return([]azure.ResourceSpecGetter{&fakeFlowLogSpec1, &fakeFlowLogSpec2})
+				s.IsVnetManaged().Return(true)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeFlowLogSpec1, ServiceName).Return(nil, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeFlowLogSpec2, ServiceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.FlowLogsReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "no flow log specs found",
+			expectedError: "",
+			expect: func(s *mock_networkwatchers.MockFlowLogScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FlowLogSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "vnet is not managed, should skip reconcile",
+			expectedError: "",
+			expect: func(s *mock_networkwatchers.MockFlowLogScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FlowLogSpecs().Return([]azure.ResourceSpecGetter{&fakeFlowLogSpec1})
+				s.IsVnetManaged().Return(false)
+			},
+		},
+		{
+			name:          "error is returned over not done error",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_networkwatchers.MockFlowLogScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FlowLogSpecs().Return([]azure.ResourceSpecGetter{&fakeFlowLogSpec1, &fakeFlowLogSpec2})
+				s.IsVnetManaged().Return(true)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeFlowLogSpec1, ServiceName).Return(nil, internalError)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeFlowLogSpec2, ServiceName).Return(nil, notDoneError)
+				s.UpdatePutStatus(infrav1.FlowLogsReadyCondition, ServiceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_networkwatchers.NewMockFlowLogScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteFlowLogs(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_networkwatchers.MockFlowLogScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "flow logs successfully deleted",
+			expectedError: "",
+			expect: func(s *mock_networkwatchers.MockFlowLogScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FlowLogSpecs().Return([]azure.ResourceSpecGetter{&fakeFlowLogSpec1, &fakeFlowLogSpec2})
+				s.IsVnetManaged().Return(true)
+				r.DeleteResource(gomockinternal.AContext(), &fakeFlowLogSpec1, ServiceName).Return(nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeFlowLogSpec2, ServiceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.FlowLogsReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "no flow log specs found",
+			expectedError: "",
+			expect: func(s *mock_networkwatchers.MockFlowLogScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FlowLogSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "fail to delete a flow log",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_networkwatchers.MockFlowLogScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FlowLogSpecs().Return([]azure.ResourceSpecGetter{&fakeFlowLogSpec1})
+				s.IsVnetManaged().Return(true)
+				r.DeleteResource(gomockinternal.AContext(), &fakeFlowLogSpec1, ServiceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.FlowLogsReadyCondition, ServiceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_networkwatchers.NewMockFlowLogScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
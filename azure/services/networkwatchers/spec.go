@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkwatchers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// flowLogFormatVersion is the version of the flow log format. Version 2 adds the bytes and packets of each flow.
+const flowLogFormatVersion = 2
+
+// FlowLogSpec defines the specification for the NSG flow log of a network security group.
+type FlowLogSpec struct {
+	Name               string
+	NetworkWatcherName string
+	NetworkWatcherRG   string
+	Location           string
+	ClusterName        string
+	SecurityGroupID    string
+	StorageAccountID   string
+	RetentionDays      int32
+	TrafficAnalytics   *infrav1.TrafficAnalyticsSpec
+	AdditionalTags     infrav1.Tags
+}
+
+// ResourceName returns the name of the flow log.
+func (s *FlowLogSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the network watcher.
+func (s *FlowLogSpec) ResourceGroupName() string {
+	return s.NetworkWatcherRG
+}
+
+// OwnerResourceName returns the name of the network watcher the flow log belongs to.
+func (s *FlowLogSpec) OwnerResourceName() string {
+	return s.NetworkWatcherName
+}
+
+// Parameters returns the parameters for the flow log.
+func (s *FlowLogSpec) Parameters(ctx context.Context, existing interface{}) (parameters interface{}, err error) {
+	properties := s.properties()
+
+	if existing != nil {
+		existingFlowLog, ok := existing.(armnetwork.FlowLog)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armnetwork.FlowLog", existing)
+		}
+		if flowLogUpToDate(existingFlowLog.Properties, properties) {
+			return nil, nil
+		}
+		existingFlowLog.Properties = properties
+		return existingFlowLog, nil
+	}
+
+	return armnetwork.FlowLog{
+		Location: ptr.To(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+		Properties: properties,
+	}, nil
+}
+
+func (s *FlowLogSpec) properties() *armnetwork.FlowLogPropertiesFormat {
+	properties := &armnetwork.FlowLogPropertiesFormat{
+		TargetResourceID: ptr.To(s.SecurityGroupID),
+		StorageID:        ptr.To(s.StorageAccountID),
+		Enabled:          ptr.To(true),
+		Format: &armnetwork.FlowLogFormatParameters{
+			Type:    ptr.To(armnetwork.FlowLogFormatTypeJSON),
+			Version: ptr.To[int32](flowLogFormatVersion),
+		},
+		RetentionPolicy: &armnetwork.RetentionPolicyParameters{
+			Days:    ptr.To(s.RetentionDays),
+			Enabled: ptr.To(s.RetentionDays > 0),
+		},
+	}
+	if s.TrafficAnalytics != nil {
+		properties.FlowAnalyticsConfiguration = &armnetwork.TrafficAnalyticsProperties{
+			NetworkWatcherFlowAnalyticsConfiguration: &armnetwork.TrafficAnalyticsConfigurationProperties{
+				Enabled:                  ptr.To(true),
+				WorkspaceResourceID:      ptr.To(s.TrafficAnalytics.WorkspaceResourceID),
+				WorkspaceID:              ptr.To(s.TrafficAnalytics.WorkspaceID),
+				WorkspaceRegion:          ptr.To(s.TrafficAnalytics.WorkspaceRegion),
+				TrafficAnalyticsInterval: ptr.To(s.TrafficAnalytics.IntervalInMinutes),
+			},
+		}
+	}
+	return properties
+}
+
+// flowLogUpToDate returns true if the existing flow log properties match the desired ones.
+func flowLogUpToDate(existing, desired *armnetwork.FlowLogPropertiesFormat) bool {
+	if existing == nil {
+		return false
+	}
+	if !ptr.Deref(existing.Enabled, false) ||
+		!strings.EqualFold(ptr.Deref(existing.StorageID, ""), ptr.Deref(desired.StorageID, "")) ||
+		!strings.EqualFold(ptr.Deref(existing.TargetResourceID, ""), ptr.Deref(desired.TargetResourceID, "")) {
+		return false
+	}
+	if existing.RetentionPolicy == nil ||
+		ptr.Deref(existing.RetentionPolicy.Days, 0) != ptr.Deref(desired.RetentionPolicy.Days, 0) ||
+		ptr.Deref(existing.RetentionPolicy.Enabled, false) != ptr.Deref(desired.RetentionPolicy.Enabled, false) {
+		return false
+	}
+
+	var existingAnalytics *armnetwork.TrafficAnalyticsConfigurationProperties
+	if existing.FlowAnalyticsConfiguration != nil {
+		existingAnalytics = existing.FlowAnalyticsConfiguration.NetworkWatcherFlowAnalyticsConfiguration
+	}
+	existingAnalyticsEnabled := existingAnalytics != nil && ptr.Deref(existingAnalytics.Enabled, false)
+	if desired.FlowAnalyticsConfiguration == nil {
+		return !existingAnalyticsEnabled
+	}
+	desiredAnalytics := desired.FlowAnalyticsConfiguration.NetworkWatcherFlowAnalyticsConfiguration
+	return existingAnalyticsEnabled &&
+		strings.EqualFold(ptr.Deref(existingAnalytics.WorkspaceResourceID, ""), ptr.Deref(desiredAnalytics.WorkspaceResourceID, "")) &&
+		strings.EqualFold(ptr.Deref(existingAnalytics.WorkspaceID, ""), ptr.Deref(desiredAnalytics.WorkspaceID, "")) &&
+		ptr.Deref(existingAnalytics.TrafficAnalyticsInterval, 0) == ptr.Deref(desiredAnalytics.TrafficAnalyticsInterval, 0)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkwatchers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestFlowLogSpecParameters(t *testing.T) {
+	trafficAnalytics := &infrav1.TrafficAnalyticsSpec{
+		WorkspaceResourceID: "/subscriptions/123/resourceGroups/logs-rg/providers/Microsoft.OperationalInsights/workspaces/logs",
+		WorkspaceID:         "8a1c3a0e-5b0c-4d8e-9c5f-1d2e3f4a5b6c",
+		WorkspaceRegion:     "westus",
+		IntervalInMinutes:   60,
+	}
+	spec := &FlowLogSpec{
+		Name:               "node-nsg-my-rg-flowlog",
+		NetworkWatcherName: "NetworkWatcher_westus",
+		NetworkWatcherRG:   "NetworkWatcherRG",
+		Location:           "westus",
+		ClusterName:        "my-cluster",
+		SecurityGroupID:    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/node-nsg",
+		StorageAccountID:   "/subscriptions/123/resourceGroups/logs-rg/providers/Microsoft.Storage/storageAccounts/flowlogs",
+		RetentionDays:      30,
+		TrafficAnalytics:   trafficAnalytics,
+	}
+	desiredProperties := &armnetwork.FlowLogPropertiesFormat{
+		TargetResourceID: ptr.To(spec.SecurityGroupID),
+		StorageID:        ptr.To(spec.StorageAccountID),
+		Enabled:          ptr.To(true),
+		Format: &armnetwork.FlowLogFormatParameters{
+			Type:    ptr.To(armnetwork.FlowLogFormatTypeJSON),
+			Version: ptr.To[int32](2),
+		},
+		RetentionPolicy: &armnetwork.RetentionPolicyParameters{
+			Days:    ptr.To[int32](30),
+			Enabled: ptr.To(true),
+		},
+		FlowAnalyticsConfiguration: &armnetwork.TrafficAnalyticsProperties{
+			NetworkWatcherFlowAnalyticsConfiguration: &armnetwork.TrafficAnalyticsConfigurationProperties{
+				Enabled:                  ptr.To(true),
+				WorkspaceResourceID:      ptr.To(trafficAnalytics.WorkspaceResourceID),
+				WorkspaceID:              ptr.To(trafficAnalytics.WorkspaceID),
+				WorkspaceRegion:          ptr.To("westus"),
+				TrafficAnalyticsInterval: ptr.To[int32](60),
+			},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name:     "flow log does not exist",
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armnetwork.FlowLog{
+					Location: ptr.To("westus"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+						"Name": ptr.To("node-nsg-my-rg-flowlog"),
+					},
+					Properties: desiredProperties,
+				}))
+			},
+		},
+		{
+			name: "flow log is up to date",
+			existing: armnetwork.FlowLog{
+				Name:       ptr.To("node-nsg-my-rg-flowlog"),
+				Properties: desiredProperties,
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "flow log with another retention",
+			existing: armnetwork.FlowLog{
+				Name: ptr.To("node-nsg-my-rg-flowlog"),
+				Etag: ptr.To("fake-etag"),
+				Properties: &armnetwork.FlowLogPropertiesFormat{
+					TargetResourceID: ptr.To(spec.SecurityGroupID),
+					StorageID:        ptr.To(spec.StorageAccountID),
+					Enabled:          ptr.To(true),
+					RetentionPolicy: &armnetwork.RetentionPolicyParameters{
+						Days:    ptr.To[int32](7),
+						Enabled: ptr.To(true),
+					},
+					FlowAnalyticsConfiguration: desiredProperties.FlowAnalyticsConfiguration,
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armnetwork.FlowLog{
+					Name:       ptr.To("node-nsg-my-rg-flowlog"),
+					Etag:       ptr.To("fake-etag"),
+					Properties: desiredProperties,
+				}))
+			},
+		},
+		{
+			name: "flow log without traffic analytics",
+			existing: armnetwork.FlowLog{
+				Name: ptr.To("node-nsg-my-rg-flowlog"),
+				Properties: &armnetwork.FlowLogPropertiesFormat{
+					TargetResourceID: ptr.To(spec.SecurityGroupID),
+					StorageID:        ptr.To(spec.StorageAccountID),
+					Enabled:          ptr.To(true),
+					RetentionPolicy:  desiredProperties.RetentionPolicy,
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armnetwork.FlowLog{
+					Name:       ptr.To("node-nsg-my-rg-flowlog"),
+					Properties: desiredProperties,
+				}))
+			},
+		},
+		{
+			name:          "existing is not a flow log",
+			existing:      "not a flow log",
+			expectedError: "string is not an armnetwork.FlowLog",
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			tc.expect(g, result)
+		})
+	}
+}
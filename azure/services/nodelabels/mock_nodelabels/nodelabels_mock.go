@@ -0,0 +1,282 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../nodelabels.go
+//
+// Generated by this command:
+//
+//	mockgen -destination nodelabels_mock.go -package mock_nodelabels -source ../nodelabels.go NodeLabelsScope
+//
+// Package mock_nodelabels is a generated GoMock package.
+package mock_nodelabels
+
+import (
+	context "context"
+	reflect "reflect"
+
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	armresources "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	gomock "go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MockNodeLabelsScope is a mock of NodeLabelsScope interface.
+type MockNodeLabelsScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockNodeLabelsScopeMockRecorder
+}
+
+// MockNodeLabelsScopeMockRecorder is the mock recorder for MockNodeLabelsScope.
+type MockNodeLabelsScopeMockRecorder struct {
+	mock *MockNodeLabelsScope
+}
+
+// NewMockNodeLabelsScope creates a new mock instance.
+func NewMockNodeLabelsScope(ctrl *gomock.Controller) *MockNodeLabelsScope {
+	mock := &MockNodeLabelsScope{ctrl: ctrl}
+	mock.recorder = &MockNodeLabelsScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNodeLabelsScope) EXPECT() *MockNodeLabelsScopeMockRecorder {
+	return m.recorder
+}
+
+// BaseURI mocks base method.
+func (m *MockNodeLabelsScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockNodeLabelsScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockNodeLabelsScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockNodeLabelsScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockNodeLabelsScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockNodeLabelsScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockNodeLabelsScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockNodeLabelsScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockNodeLabelsScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockNodeLabelsScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockNodeLabelsScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockNodeLabelsScope)(nil).CloudEnvironment))
+}
+
+// HashKey mocks base method.
+func (m *MockNodeLabelsScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockNodeLabelsScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockNodeLabelsScope)(nil).HashKey))
+}
+
+// NodeLabelTagMapping mocks base method.
+func (m *MockNodeLabelsScope) NodeLabelTagMapping() *v1beta1.NodeLabelTagMapping {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeLabelTagMapping")
+	ret0, _ := ret[0].(*v1beta1.NodeLabelTagMapping)
+	return ret0
+}
+
+// NodeLabelTagMapping indicates an expected call of NodeLabelTagMapping.
+func (mr *MockNodeLabelsScopeMockRecorder) NodeLabelTagMapping() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeLabelTagMapping", reflect.TypeOf((*MockNodeLabelsScope)(nil).NodeLabelTagMapping))
+}
+
+// NodeRef mocks base method.
+func (m *MockNodeLabelsScope) NodeRef() *v1.ObjectReference {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeRef")
+	ret0, _ := ret[0].(*v1.ObjectReference)
+	return ret0
+}
+
+// NodeRef indicates an expected call of NodeRef.
+func (mr *MockNodeLabelsScopeMockRecorder) NodeRef() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeRef", reflect.TypeOf((*MockNodeLabelsScope)(nil).NodeRef))
+}
+
+// SubscriptionID mocks base method.
+func (m *MockNodeLabelsScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockNodeLabelsScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockNodeLabelsScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockNodeLabelsScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockNodeLabelsScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockNodeLabelsScope)(nil).TenantID))
+}
+
+// Token mocks base method.
+func (m *MockNodeLabelsScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockNodeLabelsScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockNodeLabelsScope)(nil).Token))
+}
+
+// VMResourceID mocks base method.
+func (m *MockNodeLabelsScope) VMResourceID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VMResourceID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// VMResourceID indicates an expected call of VMResourceID.
+func (mr *MockNodeLabelsScopeMockRecorder) VMResourceID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMResourceID", reflect.TypeOf((*MockNodeLabelsScope)(nil).VMResourceID))
+}
+
+// WorkloadClient mocks base method.
+func (m *MockNodeLabelsScope) WorkloadClient(ctx context.Context) (client.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkloadClient", ctx)
+	ret0, _ := ret[0].(client.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkloadClient indicates an expected call of WorkloadClient.
+func (mr *MockNodeLabelsScopeMockRecorder) WorkloadClient(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkloadClient", reflect.TypeOf((*MockNodeLabelsScope)(nil).WorkloadClient), ctx)
+}
+
+// MocktagsClient is a mock of tagsClient interface.
+type MocktagsClient struct {
+	ctrl     *gomock.Controller
+	recorder *MocktagsClientMockRecorder
+}
+
+// MocktagsClientMockRecorder is the mock recorder for MocktagsClient.
+type MocktagsClientMockRecorder struct {
+	mock *MocktagsClient
+}
+
+// NewMocktagsClient creates a new mock instance.
+func NewMocktagsClient(ctrl *gomock.Controller) *MocktagsClient {
+	mock := &MocktagsClient{ctrl: ctrl}
+	mock.recorder = &MocktagsClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktagsClient) EXPECT() *MocktagsClientMockRecorder {
+	return m.recorder
+}
+
+// GetAtScope mocks base method.
+func (m *MocktagsClient) GetAtScope(ctx context.Context, scope string) (armresources.TagsResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAtScope", ctx, scope)
+	ret0, _ := ret[0].(armresources.TagsResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAtScope indicates an expected call of GetAtScope.
+func (mr *MocktagsClientMockRecorder) GetAtScope(ctx, scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAtScope", reflect.TypeOf((*MocktagsClient)(nil).GetAtScope), ctx, scope)
+}
+
+// UpdateAtScope mocks base method.
+func (m *MocktagsClient) UpdateAtScope(ctx context.Context, scope string, parameters armresources.TagsPatchResource) (armresources.TagsResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAtScope", ctx, scope, parameters)
+	ret0, _ := ret[0].(armresources.TagsResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAtScope indicates an expected call of UpdateAtScope.
+func (mr *MocktagsClientMockRecorder) UpdateAtScope(ctx, scope, parameters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAtScope", reflect.TypeOf((*MocktagsClient)(nil).UpdateAtScope), ctx, scope, parameters)
+}
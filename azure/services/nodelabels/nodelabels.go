@@ -0,0 +1,223 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelabels
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const serviceName = "nodelabels"
+
+// LabelsFromTagsAnnotation is set on nodes to the comma-separated keys of the labels copied from tags of their VM,
+// so that labels whose tag was removed can be removed as well.
+const LabelsFromTagsAnnotation = "infrastructure.cluster.x-k8s.io/labels-from-vm-tags"
+
+// NodeLabelsScope defines the scope interface for a node labels service.
+type NodeLabelsScope interface {
+	azure.Authorizer
+	NodeLabelTagMapping() *infrav1.NodeLabelTagMapping
+	VMResourceID() string
+	NodeRef() *corev1.ObjectReference
+	WorkloadClient(ctx context.Context) (client.Client, error)
+}
+
+// tagsClient reads and updates the tags of Azure resources.
+type tagsClient interface {
+	GetAtScope(ctx context.Context, scope string) (armresources.TagsResource, error)
+	UpdateAtScope(ctx context.Context, scope string, parameters armresources.TagsPatchResource) (armresources.TagsResource, error)
+}
+
+// Service mirrors node labels onto VM tags and VM tags onto node labels.
+type Service struct {
+	Scope  NodeLabelsScope
+	client tagsClient
+}
+
+// New creates a new service.
+func New(scope NodeLabelsScope) (*Service, error) {
+	cli, err := tags.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:  scope,
+		client: cli,
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile copies the selected node labels onto tags of the VM and the selected VM tags onto labels of the node.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "nodelabels.Service.Reconcile")
+	defer done()
+
+	mapping := s.Scope.NodeLabelTagMapping()
+	nodeRef := s.Scope.NodeRef()
+	vmID := s.Scope.VMResourceID()
+	if mapping == nil || nodeRef == nil || vmID == "" {
+		return nil
+	}
+
+	workloadClient, err := s.Scope.WorkloadClient(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create workload cluster client")
+	}
+	node := &corev1.Node{}
+	if err := workloadClient.Get(ctx, client.ObjectKey{Name: nodeRef.Name}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(4).Info("node not found, skipping node labels", "node", nodeRef.Name)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get node %s", nodeRef.Name)
+	}
+
+	existing, err := s.client.GetAtScope(ctx, vmID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get tags of VM %s", vmID)
+	}
+	vmTags := infrav1.Tags{}
+	if existing.Properties != nil && existing.Properties.Tags != nil {
+		vmTags = converters.MapToTags(existing.Properties.Tags)
+	}
+
+	if err := s.reconcileVMTags(ctx, vmID, node.Labels, vmTags, mapping.LabelsToTags); err != nil {
+		return err
+	}
+
+	if mapping.TagPrefix == "" {
+		return nil
+	}
+	return reconcileNodeLabels(ctx, workloadClient, node, vmTags, mapping.TagPrefix)
+}
+
+// reconcileVMTags sets the tags mirroring the given node labels on the VM, and deletes those whose label is gone.
+func (s *Service) reconcileVMTags(ctx context.Context, vmID string, nodeLabels map[string]string, vmTags infrav1.Tags, labels []string) error {
+	changed, deleted := infrav1.Tags{}, infrav1.Tags{}
+	for _, label := range labels {
+		key := LabelTagKey(label)
+		value, hasLabel := nodeLabels[label]
+		current, hasTag := vmTags[key]
+		switch {
+		case hasLabel && (!hasTag || current != value):
+			changed[key] = value
+		case !hasLabel && hasTag:
+			deleted[key] = current
+		}
+	}
+
+	if len(changed) > 0 {
+		if _, err := s.client.UpdateAtScope(ctx, vmID, armresources.TagsPatchResource{
+			Operation:  ptr.To(armresources.TagsPatchOperationMerge),
+			Properties: &armresources.Tags{Tags: converters.TagsToMap(changed)},
+		}); err != nil {
+			return errors.Wrapf(err, "failed to set tags of VM %s", vmID)
+		}
+	}
+	if len(deleted) > 0 {
+		if _, err := s.client.UpdateAtScope(ctx, vmID, armresources.TagsPatchResource{
+			Operation:  ptr.To(armresources.TagsPatchOperationDelete),
+			Properties: &armresources.Tags{Tags: converters.TagsToMap(deleted)},
+		}); err != nil {
+			return errors.Wrapf(err, "failed to delete tags of VM %s", vmID)
+		}
+	}
+	return nil
+}
+
+// reconcileNodeLabels sets the labels mirroring the VM tags starting with prefix on the node, and removes the ones
+// previously copied from a tag that is gone.
+func reconcileNodeLabels(ctx context.Context, workloadClient client.Client, node *corev1.Node, vmTags infrav1.Tags, prefix string) error {
+	_, log, done := tele.StartSpanWithLogger(ctx, "nodelabels.reconcileNodeLabels")
+	defer done()
+
+	desired := map[string]string{}
+	for key, value := range vmTags {
+		label, found := strings.CutPrefix(key, prefix)
+		if !found || label == "" {
+			continue
+		}
+		if errs := append(validation.IsQualifiedName(label), validation.IsValidLabelValue(value)...); len(errs) > 0 {
+			log.V(4).Info("skipping VM tag that is not a valid node label", "tag", key, "reason", strings.Join(errs, "; "))
+			continue
+		}
+		desired[label] = value
+	}
+
+	original := node.DeepCopy()
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	if previous := node.Annotations[LabelsFromTagsAnnotation]; previous != "" {
+		for _, label := range strings.Split(previous, ",") {
+			if _, ok := desired[label]; !ok {
+				delete(node.Labels, label)
+			}
+		}
+	}
+	keys := make([]string, 0, len(desired))
+	for label, value := range desired {
+		node.Labels[label] = value
+		keys = append(keys, label)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[LabelsFromTagsAnnotation] = strings.Join(keys, ",")
+	} else {
+		delete(node.Annotations, LabelsFromTagsAnnotation)
+	}
+
+	if reflect.DeepEqual(original.Labels, node.Labels) && reflect.DeepEqual(original.Annotations, node.Annotations) {
+		return nil
+	}
+	if err := workloadClient.Patch(ctx, node, client.MergeFrom(original)); err != nil {
+		return errors.Wrapf(err, "failed to update labels of node %s", node.Name)
+	}
+	return nil
+}
+
+// Delete is a no-op: the tags go away with the VM and the labels with the node.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// LabelTagKey returns the key of the VM tag mirroring a node label.
+func LabelTagKey(label string) string {
+	return strings.ReplaceAll(label, "/", "_")
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelabels
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/nodelabels/mock_nodelabels"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const vmID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm"
+
+func TestReconcileNodeLabels(t *testing.T) {
+	testcases := []struct {
+		name           string
+		mapping        *infrav1.NodeLabelTagMapping
+		node           *corev1.Node
+		expect         func(m *mock_nodelabels.MocktagsClientMockRecorder)
+		expectedLabels map[string]string
+		expectedError  string
+	}{
+		{
+			name: "copies labels onto tags and deletes tags of removed labels",
+			mapping: &infrav1.NodeLabelTagMapping{
+				LabelsToTags: []string{"topology.kubernetes.io/zone", "team", "gone"},
+			},
+			node: newNode(map[string]string{"topology.kubernetes.io/zone": "eastus-1", "team": "a"}, nil),
+			expect: func(m *mock_nodelabels.MocktagsClientMockRecorder) {
+				m.GetAtScope(gomockinternal.AContext(), vmID).Return(armresources.TagsResource{Properties: &armresources.Tags{
+					Tags: map[string]*string{
+						"team": ptr.To("a"),
+						"gone": ptr.To("x"),
+					},
+				}}, nil)
+				m.UpdateAtScope(gomockinternal.AContext(), vmID, armresources.TagsPatchResource{
+					Operation: ptr.To(armresources.TagsPatchOperationMerge),
+					Properties: &armresources.Tags{
+						Tags: map[string]*string{"topology.kubernetes.io_zone": ptr.To("eastus-1")},
+					},
+				})
+				m.UpdateAtScope(gomockinternal.AContext(), vmID, armresources.TagsPatchResource{
+					Operation: ptr.To(armresources.TagsPatchOperationDelete),
+					Properties: &armresources.Tags{
+						Tags: map[string]*string{"gone": ptr.To("x")},
+					},
+				})
+			},
+			expectedLabels: map[string]string{"topology.kubernetes.io/zone": "eastus-1", "team": "a"},
+		},
+		{
+			name:    "copies prefixed tags onto labels and removes labels of removed tags",
+			mapping: &infrav1.NodeLabelTagMapping{TagPrefix: "k8s-label-"},
+			node: newNode(
+				map[string]string{"old": "v", "unmanaged": "u"},
+				map[string]string{LabelsFromTagsAnnotation: "old"},
+			),
+			expect: func(m *mock_nodelabels.MocktagsClientMockRecorder) {
+				m.GetAtScope(gomockinternal.AContext(), vmID).Return(armresources.TagsResource{Properties: &armresources.Tags{
+					Tags: map[string]*string{
+						"k8s-label-workload": ptr.To("batch"),
+						"k8s-label-bad key":  ptr.To("v"),
+						"other":              ptr.To("ignored"),
+					},
+				}}, nil)
+			},
+			expectedLabels: map[string]string{"workload": "batch", "unmanaged": "u"},
+		},
+		{
+			name:    "returns an error when tags cannot be read",
+			mapping: &infrav1.NodeLabelTagMapping{LabelsToTags: []string{"team"}},
+			node:    newNode(nil, nil),
+			expect: func(m *mock_nodelabels.MocktagsClientMockRecorder) {
+				m.GetAtScope(gomockinternal.AContext(), vmID).Return(armresources.TagsResource{}, errors.New("boom"))
+			},
+			expectedError: "failed to get tags of VM " + vmID + ": boom",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_nodelabels.NewMockNodeLabelsScope(mockCtrl)
+			clientMock := mock_nodelabels.NewMocktagsClient(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			workloadClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.node).Build()
+
+			scopeMock.EXPECT().NodeLabelTagMapping().Return(tc.mapping).AnyTimes()
+			scopeMock.EXPECT().NodeRef().Return(&corev1.ObjectReference{Name: tc.node.Name}).AnyTimes()
+			scopeMock.EXPECT().VMResourceID().Return(vmID).AnyTimes()
+			scopeMock.EXPECT().WorkloadClient(gomockinternal.AContext()).Return(workloadClient, nil).AnyTimes()
+			tc.expect(clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			node := &corev1.Node{}
+			g.Expect(workloadClient.Get(context.TODO(), client.ObjectKeyFromObject(tc.node), node)).To(Succeed())
+			g.Expect(node.Labels).To(Equal(tc.expectedLabels))
+		})
+	}
+}
+
+func TestReconcileNodeLabelsWithoutNode(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_nodelabels.NewMockNodeLabelsScope(mockCtrl)
+	clientMock := mock_nodelabels.NewMocktagsClient(mockCtrl)
+
+	scopeMock.EXPECT().NodeLabelTagMapping().Return(&infrav1.NodeLabelTagMapping{LabelsToTags: []string{"team"}})
+	scopeMock.EXPECT().NodeRef().Return(nil)
+	scopeMock.EXPECT().VMResourceID().Return(vmID)
+
+	s := &Service{
+		Scope:  scopeMock,
+		client: clientMock,
+	}
+	g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+}
+
+func newNode(labels, annotations map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-node",
+			Labels:      labels,
+			Annotations: annotations,
+		},
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../nodemetadata.go
+//
+// Generated by this command:
+//
+//	mockgen -destination nodemetadata_mock.go -package mock_nodemetadata -source ../nodemetadata.go NodeMetadataScope
+//
+// Package mock_nodemetadata is a generated GoMock package.
+package mock_nodemetadata
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MockNodeMetadataScope is a mock of NodeMetadataScope interface.
+type MockNodeMetadataScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockNodeMetadataScopeMockRecorder
+}
+
+// MockNodeMetadataScopeMockRecorder is the mock recorder for MockNodeMetadataScope.
+type MockNodeMetadataScopeMockRecorder struct {
+	mock *MockNodeMetadataScope
+}
+
+// NewMockNodeMetadataScope creates a new mock instance.
+func NewMockNodeMetadataScope(ctrl *gomock.Controller) *MockNodeMetadataScope {
+	mock := &MockNodeMetadataScope{ctrl: ctrl}
+	mock.recorder = &MockNodeMetadataScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNodeMetadataScope) EXPECT() *MockNodeMetadataScopeMockRecorder {
+	return m.recorder
+}
+
+// NodeMetadataAnnotations mocks base method.
+func (m *MockNodeMetadataScope) NodeMetadataAnnotations() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeMetadataAnnotations")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NodeMetadataAnnotations indicates an expected call of NodeMetadataAnnotations.
+func (mr *MockNodeMetadataScopeMockRecorder) NodeMetadataAnnotations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeMetadataAnnotations", reflect.TypeOf((*MockNodeMetadataScope)(nil).NodeMetadataAnnotations))
+}
+
+// NodeRef mocks base method.
+func (m *MockNodeMetadataScope) NodeRef() *v1.ObjectReference {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeRef")
+	ret0, _ := ret[0].(*v1.ObjectReference)
+	return ret0
+}
+
+// NodeRef indicates an expected call of NodeRef.
+func (mr *MockNodeMetadataScopeMockRecorder) NodeRef() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeRef", reflect.TypeOf((*MockNodeMetadataScope)(nil).NodeRef))
+}
+
+// VMStatus mocks base method.
+func (m *MockNodeMetadataScope) VMStatus() *v1beta1.VMStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VMStatus")
+	ret0, _ := ret[0].(*v1beta1.VMStatus)
+	return ret0
+}
+
+// VMStatus indicates an expected call of VMStatus.
+func (mr *MockNodeMetadataScopeMockRecorder) VMStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMStatus", reflect.TypeOf((*MockNodeMetadataScope)(nil).VMStatus))
+}
+
+// WorkloadClient mocks base method.
+func (m *MockNodeMetadataScope) WorkloadClient(ctx context.Context) (client.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkloadClient", ctx)
+	ret0, _ := ret[0].(client.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkloadClient indicates an expected call of WorkloadClient.
+func (mr *MockNodeMetadataScopeMockRecorder) WorkloadClient(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkloadClient", reflect.TypeOf((*MockNodeMetadataScope)(nil).WorkloadClient), ctx)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetadata
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const serviceName = "nodemetadata"
+
+const (
+	// VMIDAnnotation is set on nodes to the unique ID of their VM.
+	VMIDAnnotation = "infrastructure.cluster.x-k8s.io/vm-id"
+	// VMSizeAnnotation is set on nodes to the size of their VM.
+	VMSizeAnnotation = "infrastructure.cluster.x-k8s.io/vm-size"
+	// PlatformFaultDomainAnnotation is set on nodes to the platform fault domain of their VM.
+	PlatformFaultDomainAnnotation = "infrastructure.cluster.x-k8s.io/platform-fault-domain"
+)
+
+// NodeMetadataScope defines the scope interface for a node metadata service.
+type NodeMetadataScope interface {
+	NodeMetadataAnnotations() bool
+	VMStatus() *infrav1.VMStatus
+	NodeRef() *corev1.ObjectReference
+	WorkloadClient(ctx context.Context) (client.Client, error)
+}
+
+// Service copies the metadata of a VM onto annotations of its node.
+type Service struct {
+	Scope NodeMetadataScope
+}
+
+// New creates a new service.
+func New(scope NodeMetadataScope) *Service {
+	return &Service{
+		Scope: scope,
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile sets the annotations describing the VM of the machine on its node.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "nodemetadata.Service.Reconcile")
+	defer done()
+
+	nodeRef := s.Scope.NodeRef()
+	vmStatus := s.Scope.VMStatus()
+	if !s.Scope.NodeMetadataAnnotations() || nodeRef == nil || vmStatus == nil {
+		return nil
+	}
+
+	workloadClient, err := s.Scope.WorkloadClient(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create workload cluster client")
+	}
+	node := &corev1.Node{}
+	if err := workloadClient.Get(ctx, client.ObjectKey{Name: nodeRef.Name}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(4).Info("node not found, skipping node metadata", "node", nodeRef.Name)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get node %s", nodeRef.Name)
+	}
+
+	original := node.DeepCopy()
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	for key, value := range annotations(vmStatus) {
+		if value == "" {
+			delete(node.Annotations, key)
+			continue
+		}
+		node.Annotations[key] = value
+	}
+
+	if reflect.DeepEqual(original.Annotations, node.Annotations) {
+		return nil
+	}
+	if err := workloadClient.Patch(ctx, node, client.MergeFrom(original)); err != nil {
+		return errors.Wrapf(err, "failed to update annotations of node %s", node.Name)
+	}
+	return nil
+}
+
+// annotations returns the node annotations describing a VM. An empty value means the annotation must be removed.
+func annotations(vm *infrav1.VMStatus) map[string]string {
+	faultDomain := ""
+	if vm.PlatformFaultDomain != nil {
+		faultDomain = strconv.Itoa(int(*vm.PlatformFaultDomain))
+	}
+	return map[string]string{
+		VMIDAnnotation:                vm.VMID,
+		VMSizeAnnotation:              vm.Size,
+		PlatformFaultDomainAnnotation: faultDomain,
+	}
+}
+
+// Delete is a no-op: the annotations go away with the node.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetadata
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/nodemetadata/mock_nodemetadata"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileNodeMetadata(t *testing.T) {
+	testcases := []struct {
+		name                string
+		vmStatus            *infrav1.VMStatus
+		annotations         map[string]string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name: "sets the VM metadata annotations",
+			vmStatus: &infrav1.VMStatus{
+				VMID:                "4a1c6b1e-0d0f-4a8e-9b8a-2b2f6c1d0e3f",
+				Size:                "Standard_D2s_v3",
+				PlatformFaultDomain: ptr.To[int32](0),
+			},
+			annotations: map[string]string{"unmanaged": "u"},
+			expectedAnnotations: map[string]string{
+				"unmanaged":                   "u",
+				VMIDAnnotation:                "4a1c6b1e-0d0f-4a8e-9b8a-2b2f6c1d0e3f",
+				VMSizeAnnotation:              "Standard_D2s_v3",
+				PlatformFaultDomainAnnotation: "0",
+			},
+		},
+		{
+			name: "updates changed annotations and removes the ones without metadata",
+			vmStatus: &infrav1.VMStatus{
+				VMID: "4a1c6b1e-0d0f-4a8e-9b8a-2b2f6c1d0e3f",
+				Size: "Standard_D4s_v3",
+			},
+			annotations: map[string]string{
+				VMIDAnnotation:                "4a1c6b1e-0d0f-4a8e-9b8a-2b2f6c1d0e3f",
+				VMSizeAnnotation:              "Standard_D2s_v3",
+				PlatformFaultDomainAnnotation: "1",
+			},
+			expectedAnnotations: map[string]string{
+				VMIDAnnotation:   "4a1c6b1e-0d0f-4a8e-9b8a-2b2f6c1d0e3f",
+				VMSizeAnnotation: "Standard_D4s_v3",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_nodemetadata.NewMockNodeMetadataScope(mockCtrl)
+
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-node",
+					Annotations: tc.annotations,
+				},
+			}
+			scheme := runtime.NewScheme()
+			g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			workloadClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+
+			scopeMock.EXPECT().NodeMetadataAnnotations().Return(true)
+			scopeMock.EXPECT().NodeRef().Return(&corev1.ObjectReference{Name: node.Name})
+			scopeMock.EXPECT().VMStatus().Return(tc.vmStatus)
+			scopeMock.EXPECT().WorkloadClient(gomockinternal.AContext()).Return(workloadClient, nil)
+
+			s := New(scopeMock)
+			g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+
+			updated := &corev1.Node{}
+			g.Expect(workloadClient.Get(context.TODO(), client.ObjectKeyFromObject(node), updated)).To(Succeed())
+			g.Expect(updated.Annotations).To(Equal(tc.expectedAnnotations))
+		})
+	}
+}
+
+func TestReconcileNodeMetadataDisabled(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_nodemetadata.NewMockNodeMetadataScope(mockCtrl)
+
+	scopeMock.EXPECT().NodeMetadataAnnotations().Return(false)
+	scopeMock.EXPECT().NodeRef().Return(&corev1.ObjectReference{Name: "my-node"})
+	scopeMock.EXPECT().VMStatus().Return(&infrav1.VMStatus{VMID: "4a1c6b1e-0d0f-4a8e-9b8a-2b2f6c1d0e3f"})
+
+	s := New(scopeMock)
+	g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderoutes
+
+import (
+	"context"
+	"net"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const serviceName = "noderoutes"
+
+// NodeRoutesScope defines the scope interface for a node routes service.
+type NodeRoutesScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	// NodeRouteSpecs returns the routes of the machine, one per route table, without their address prefix and next
+	// hop. It returns nil if the routes are not managed by capz.
+	NodeRouteSpecs() []azure.ResourceSpecGetter
+	NodeRef() *corev1.ObjectReference
+	WorkloadClient(ctx context.Context) (client.Client, error)
+}
+
+// Service manages the routes to the pod CIDR of the node of a machine, for kubenet-style networking.
+type Service struct {
+	Scope NodeRoutesScope
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope NodeRoutesScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope: scope,
+		Reconciler: async.New[armnetwork.RoutesClientCreateOrUpdateResponse,
+			armnetwork.RoutesClientDeleteResponse](scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile idempotently creates or updates the routes to the pod CIDR of the node of the machine.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "noderoutes.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.NodeRouteSpecs()
+	nodeRef := s.Scope.NodeRef()
+	if len(specs) == 0 || nodeRef == nil {
+		return nil
+	}
+
+	workloadClient, err := s.Scope.WorkloadClient(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create workload cluster client")
+	}
+	node := &corev1.Node{}
+	if err := workloadClient.Get(ctx, client.ObjectKey{Name: nodeRef.Name}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(4).Info("node not found, skipping node routes", "node", nodeRef.Name)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get node %s", nodeRef.Name)
+	}
+	podCIDR, nodeIP := ipv4PodCIDR(node), ipv4InternalIP(node)
+	if podCIDR == "" || nodeIP == "" {
+		log.V(4).Info("node has no IPv4 pod CIDR or internal IP yet, skipping node routes", "node", nodeRef.Name)
+		return nil
+	}
+
+	// We go through the list of routes to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var resErr error
+	for _, spec := range specs {
+		if routeSpec, ok := spec.(*RouteSpec); ok {
+			routeSpec.AddressPrefix = podCIDR
+			routeSpec.NextHopIPAddress = nodeIP
+		}
+		if _, err := s.CreateOrUpdateResource(ctx, spec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || resErr == nil {
+				resErr = err
+			}
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.NodeRoutesReadyCondition, serviceName, resErr)
+	return resErr
+}
+
+// Delete deletes the routes to the pod CIDR of the node of the machine.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "noderoutes.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.NodeRouteSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	// We go through the list of routes to delete each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one
+	// order of precedence is: error deleting -> deleting in progress -> deleted (no error)
+	var result error
+	for _, spec := range specs {
+		if err := s.DeleteResource(ctx, spec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+	s.Scope.UpdateDeleteStatus(infrav1.NodeRoutesReadyCondition, serviceName, result)
+	return result
+}
+
+// ipv4PodCIDR returns the IPv4 pod CIDR of the node, if any.
+func ipv4PodCIDR(node *corev1.Node) string {
+	podCIDRs := node.Spec.PodCIDRs
+	if len(podCIDRs) == 0 && node.Spec.PodCIDR != "" {
+		podCIDRs = []string{node.Spec.PodCIDR}
+	}
+	for _, podCIDR := range podCIDRs {
+		if ip, _, err := net.ParseCIDR(podCIDR); err == nil && ip.To4() != nil {
+			return podCIDR
+		}
+	}
+	return ""
+}
+
+// ipv4InternalIP returns the IPv4 internal IP address of the node, if any.
+func ipv4InternalIP(node *corev1.Node) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type != corev1.NodeInternalIP {
+			continue
+		}
+		if ip := net.ParseIP(address.Address); ip != nil && ip.To4() != nil {
+			return address.Address
+		}
+	}
+	return ""
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderoutes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/noderoutes/mock_noderoutes"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+
+func newNode(podCIDRs []string, addresses ...corev1.NodeAddress) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-node"},
+		Spec:       corev1.NodeSpec{PodCIDRs: podCIDRs},
+		Status:     corev1.NodeStatus{Addresses: addresses},
+	}
+}
+
+func newRouteSpecs() []azure.ResourceSpecGetter {
+	return []azure.ResourceSpecGetter{
+		&RouteSpec{Name: "my-vm", ResourceGroup: "my-rg", RouteTableName: "node-rt"},
+		&RouteSpec{Name: "my-vm", ResourceGroup: "my-rg", RouteTableName: "other-rt"},
+	}
+}
+
+func TestReconcileNodeRoutes(t *testing.T) {
+	internalIP := corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.1.0.4"}
+	expectedSpecs := func(tableName string) *RouteSpec {
+		return &RouteSpec{
+			Name:             "my-vm",
+			ResourceGroup:    "my-rg",
+			RouteTableName:   tableName,
+			AddressPrefix:    "192.168.1.0/24",
+			NextHopIPAddress: "10.1.0.4",
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		specs         []azure.ResourceSpecGetter
+		nodeRef       *corev1.ObjectReference
+		node          *corev1.Node
+		expect        func(s *mock_noderoutes.MockNodeRoutesScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+		expectedError string
+	}{
+		{
+			name:    "noop if the routes are not managed",
+			nodeRef: &corev1.ObjectReference{Name: "my-node"},
+			node:    newNode([]string{"192.168.1.0/24"}, internalIP),
+			expect:  func(s *mock_noderoutes.MockNodeRoutesScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {},
+		},
+		{
+			name:   "noop if the machine has no node yet",
+			specs:  newRouteSpecs(),
+			node:   newNode([]string{"192.168.1.0/24"}, internalIP),
+			expect: func(s *mock_noderoutes.MockNodeRoutesScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {},
+		},
+		{
+			name:    "noop if the node has no IPv4 pod CIDR",
+			specs:   newRouteSpecs(),
+			nodeRef: &corev1.ObjectReference{Name: "my-node"},
+			node:    newNode([]string{"fd00::/64"}, internalIP),
+			expect:  func(s *mock_noderoutes.MockNodeRoutesScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {},
+		},
+		{
+			name:    "creates a route to the pod CIDR of the node in each route table",
+			specs:   newRouteSpecs(),
+			nodeRef: &corev1.ObjectReference{Name: "my-node"},
+			node: newNode([]string{"fd00::/64", "192.168.1.0/24"},
+				corev1.NodeAddress{Type: corev1.NodeHostName, Address: "my-node"},
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "fd00::4"},
+				internalIP),
+			expect: func(s *mock_noderoutes.MockNodeRoutesScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				r.CreateOrUpdateResource(gomockinternal.AContext(), expectedSpecs("node-rt"), serviceName).Return(nil, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), expectedSpecs("other-rt"), serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.NodeRoutesReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:    "returns an error if a route cannot be created",
+			specs:   newRouteSpecs(),
+			nodeRef: &corev1.ObjectReference{Name: "my-node"},
+			node:    newNode([]string{"192.168.1.0/24"}, internalIP),
+			expect: func(s *mock_noderoutes.MockNodeRoutesScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				r.CreateOrUpdateResource(gomockinternal.AContext(), expectedSpecs("node-rt"), serviceName).Return(nil, internalError)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), expectedSpecs("other-rt"), serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.NodeRoutesReadyCondition, serviceName, internalError)
+			},
+			expectedError: internalError.Error(),
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_noderoutes.NewMockNodeRoutesScope(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			workloadClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.node).Build()
+
+			scopeMock.EXPECT().NodeRouteSpecs().Return(tc.specs)
+			scopeMock.EXPECT().NodeRef().Return(tc.nodeRef)
+			tc.expect(scopeMock.EXPECT(), reconcilerMock.EXPECT())
+			scopeMock.EXPECT().WorkloadClient(gomockinternal.AContext()).Return(workloadClient, nil).AnyTimes()
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteNodeRoutes(t *testing.T) {
+	testcases := []struct {
+		name          string
+		specs         []azure.ResourceSpecGetter
+		expect        func(s *mock_noderoutes.MockNodeRoutesScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+		expectedError string
+	}{
+		{
+			name:   "noop if the routes are not managed",
+			expect: func(s *mock_noderoutes.MockNodeRoutesScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {},
+		},
+		{
+			name:  "deletes the route in each route table",
+			specs: newRouteSpecs(),
+			expect: func(s *mock_noderoutes.MockNodeRoutesScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				r.DeleteResource(gomockinternal.AContext(), newRouteSpecs()[0], serviceName).Return(nil)
+				r.DeleteResource(gomockinternal.AContext(), newRouteSpecs()[1], serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.NodeRoutesReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:  "returns an error if a route cannot be deleted",
+			specs: newRouteSpecs(),
+			expect: func(s *mock_noderoutes.MockNodeRoutesScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				r.DeleteResource(gomockinternal.AContext(), newRouteSpecs()[0], serviceName).Return(internalError)
+				r.DeleteResource(gomockinternal.AContext(), newRouteSpecs()[1], serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.NodeRoutesReadyCondition, serviceName, internalError)
+			},
+			expectedError: internalError.Error(),
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_noderoutes.NewMockNodeRoutesScope(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			scopeMock.EXPECT().NodeRouteSpecs().Return(tc.specs)
+			tc.expect(scopeMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderoutes
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+)
+
+// RouteSpec defines the specification for the route to the pod CIDR of a node.
+type RouteSpec struct {
+	Name           string
+	ResourceGroup  string
+	RouteTableName string
+	// AddressPrefix is the pod CIDR of the node.
+	AddressPrefix string
+	// NextHopIPAddress is the private IP address of the node.
+	NextHopIPAddress string
+}
+
+// ResourceName returns the name of the route.
+func (s *RouteSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the route table.
+func (s *RouteSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName returns the name of the route table the route belongs to.
+func (s *RouteSpec) OwnerResourceName() string {
+	return s.RouteTableName
+}
+
+// Parameters returns the parameters for the route.
+func (s *RouteSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		existingRoute, ok := existing.(armnetwork.Route)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armnetwork.Route", existing)
+		}
+		if props := existingRoute.Properties; props != nil &&
+			ptr.Deref(props.AddressPrefix, "") == s.AddressPrefix &&
+			ptr.Deref(props.NextHopType, "") == armnetwork.RouteNextHopTypeVirtualAppliance &&
+			ptr.Deref(props.NextHopIPAddress, "") == s.NextHopIPAddress {
+			// route is up to date
+			return nil, nil
+		}
+	}
+	return armnetwork.Route{
+		Properties: &armnetwork.RoutePropertiesFormat{
+			AddressPrefix:    ptr.To(s.AddressPrefix),
+			NextHopType:      ptr.To(armnetwork.RouteNextHopTypeVirtualAppliance),
+			NextHopIPAddress: ptr.To(s.NextHopIPAddress),
+		},
+	}, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderoutes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestRouteSpec_Parameters(t *testing.T) {
+	spec := &RouteSpec{
+		Name:             "my-vm",
+		ResourceGroup:    "my-rg",
+		RouteTableName:   "node-rt",
+		AddressPrefix:    "192.168.1.0/24",
+		NextHopIPAddress: "10.1.0.4",
+	}
+	expectedRoute := armnetwork.Route{
+		Properties: &armnetwork.RoutePropertiesFormat{
+			AddressPrefix:    ptr.To("192.168.1.0/24"),
+			NextHopType:      ptr.To(armnetwork.RouteNextHopTypeVirtualAppliance),
+			NextHopIPAddress: ptr.To("10.1.0.4"),
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		existing      interface{}
+		expected      interface{}
+		expectedError string
+	}{
+		{
+			name:     "new route",
+			existing: nil,
+			expected: expectedRoute,
+		},
+		{
+			name:     "route is up to date",
+			existing: armnetwork.Route{Name: ptr.To("my-vm"), Properties: expectedRoute.Properties},
+			expected: nil,
+		},
+		{
+			name: "route to a previous IP address of the node is updated",
+			existing: armnetwork.Route{
+				Name: ptr.To("my-vm"),
+				Properties: &armnetwork.RoutePropertiesFormat{
+					AddressPrefix:    ptr.To("192.168.1.0/24"),
+					NextHopType:      ptr.To(armnetwork.RouteNextHopTypeVirtualAppliance),
+					NextHopIPAddress: ptr.To("10.1.0.5"),
+				},
+			},
+			expected: expectedRoute,
+		},
+		{
+			name:          "existing is not a route",
+			existing:      armnetwork.RouteTable{},
+			expectedError: "armnetwork.RouteTable is not an armnetwork.Route",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expected == nil {
+				g.Expect(result).To(BeNil())
+			} else {
+				g.Expect(result).To(Equal(tc.expected))
+			}
+		})
+	}
+}
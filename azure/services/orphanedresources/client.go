@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphanedresources
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	ListResources(ctx context.Context, resourceGroup, filter string) ([]*armresources.GenericResourceExpanded, error)
+	ListSubscriptionResources(ctx context.Context, filter string) ([]*armresources.GenericResourceExpanded, error)
+	GetAPIVersion(ctx context.Context, resourceType string) (string, error)
+	BeginDeleteByID(ctx context.Context, resourceID, apiVersion string) error
+}
+
+// azureClient contains the Azure go-sdk clients.
+type azureClient struct {
+	resources *armresources.Client
+	providers *armresources.ProvidersClient
+
+	// apiVersions caches the API version to use for each resource type, as looked up from its resource provider.
+	apiVersionsMu sync.Mutex
+	apiVersions   map[string]string
+}
+
+var _ Client = (*azureClient)(nil)
+
+// newClient creates a new resources client from an authorizer.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create resources client options")
+	}
+	factory, err := armresources.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armresources client factory")
+	}
+	return &azureClient{
+		resources:   factory.NewClient(),
+		providers:   factory.NewProvidersClient(),
+		apiVersions: make(map[string]string),
+	}, nil
+}
+
+// ListResources returns the resources of a resource group matching an OData filter, or all of them if filter is empty.
+func (ac *azureClient) ListResources(ctx context.Context, resourceGroup, filter string) ([]*armresources.GenericResourceExpanded, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "orphanedresources.azureClient.ListResources")
+	defer done()
+
+	opts := &armresources.ClientListByResourceGroupOptions{}
+	if filter != "" {
+		opts.Filter = &filter
+	}
+
+	pager := ac.resources.NewListByResourceGroupPager(resourceGroup, opts)
+	values, err := azure.CollectPages(ctx, pager, func(page armresources.ClientListByResourceGroupResponse) []*armresources.GenericResourceExpanded {
+		return page.Value
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not iterate resources")
+	}
+
+	resources := make([]*armresources.GenericResourceExpanded, len(values))
+	for i := range values {
+		resources[i] = &values[i]
+	}
+	return resources, nil
+}
+
+// ListSubscriptionResources returns the resources of the subscription matching an OData filter, along with their
+// creation time.
+func (ac *azureClient) ListSubscriptionResources(ctx context.Context, filter string) ([]*armresources.GenericResourceExpanded, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "orphanedresources.azureClient.ListSubscriptionResources")
+	defer done()
+
+	opts := &armresources.ClientListOptions{Expand: ptr.To("createdTime")}
+	if filter != "" {
+		opts.Filter = &filter
+	}
+
+	pager := ac.resources.NewListPager(opts)
+	values, err := azure.CollectPages(ctx, pager, func(page armresources.ClientListResponse) []*armresources.GenericResourceExpanded {
+		return page.Value
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not iterate resources")
+	}
+
+	resources := make([]*armresources.GenericResourceExpanded, len(values))
+	for i := range values {
+		resources[i] = &values[i]
+	}
+	return resources, nil
+}
+
+// GetAPIVersion returns the API version to use to manage a resource type, e.g. "Microsoft.Network/networkInterfaces".
+func (ac *azureClient) GetAPIVersion(ctx context.Context, resourceType string) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "orphanedresources.azureClient.GetAPIVersion")
+	defer done()
+
+	ac.apiVersionsMu.Lock()
+	defer ac.apiVersionsMu.Unlock()
+
+	key := strings.ToLower(resourceType)
+	if apiVersion, ok := ac.apiVersions[key]; ok {
+		return apiVersion, nil
+	}
+
+	namespace, typeName, found := strings.Cut(resourceType, "/")
+	if !found {
+		return "", errors.Errorf("invalid resource type %s", resourceType)
+	}
+	resp, err := ac.providers.Get(ctx, namespace, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get resource provider %s", namespace)
+	}
+	for _, rt := range resp.ResourceTypes {
+		if rt == nil || !strings.EqualFold(ptr.Deref(rt.ResourceType, ""), typeName) {
+			continue
+		}
+		if apiVersion := latestStableAPIVersion(rt); apiVersion != "" {
+			ac.apiVersions[key] = apiVersion
+			return apiVersion, nil
+		}
+	}
+	return "", errors.Errorf("no API version found for resource type %s", resourceType)
+}
+
+// latestStableAPIVersion returns the default API version of a resource type, falling back to the first
+// non-preview one. Resource providers list API versions from the most to the least recent.
+func latestStableAPIVersion(rt *armresources.ProviderResourceType) string {
+	if rt.DefaultAPIVersion != nil {
+		return *rt.DefaultAPIVersion
+	}
+	for _, apiVersion := range rt.APIVersions {
+		if apiVersion != nil && !strings.Contains(*apiVersion, "preview") {
+			return *apiVersion
+		}
+	}
+	return ""
+}
+
+// BeginDeleteByID starts the deletion of a resource without waiting for it to complete.
+func (ac *azureClient) BeginDeleteByID(ctx context.Context, resourceID, apiVersion string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "orphanedresources.azureClient.BeginDeleteByID")
+	defer done()
+
+	_, err := ac.resources.BeginDeleteByID(ctx, resourceID, apiVersion, nil)
+	return err
+}
@@ -0,0 +1,115 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+//
+// Generated by this command:
+//
+//	mockgen -destination client_mock.go -package mock_orphanedresources -source ../client.go Client
+//
+// Package mock_orphanedresources is a generated GoMock package.
+package mock_orphanedresources
+
+import (
+	context "context"
+	reflect "reflect"
+
+	armresources "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// BeginDeleteByID mocks base method.
+func (m *MockClient) BeginDeleteByID(ctx context.Context, resourceID, apiVersion string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginDeleteByID", ctx, resourceID, apiVersion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BeginDeleteByID indicates an expected call of BeginDeleteByID.
+func (mr *MockClientMockRecorder) BeginDeleteByID(ctx, resourceID, apiVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginDeleteByID", reflect.TypeOf((*MockClient)(nil).BeginDeleteByID), ctx, resourceID, apiVersion)
+}
+
+// GetAPIVersion mocks base method.
+func (m *MockClient) GetAPIVersion(ctx context.Context, resourceType string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIVersion", ctx, resourceType)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIVersion indicates an expected call of GetAPIVersion.
+func (mr *MockClientMockRecorder) GetAPIVersion(ctx, resourceType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIVersion", reflect.TypeOf((*MockClient)(nil).GetAPIVersion), ctx, resourceType)
+}
+
+// ListResources mocks base method.
+func (m *MockClient) ListResources(ctx context.Context, resourceGroup, filter string) ([]*armresources.GenericResourceExpanded, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResources", ctx, resourceGroup, filter)
+	ret0, _ := ret[0].([]*armresources.GenericResourceExpanded)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResources indicates an expected call of ListResources.
+func (mr *MockClientMockRecorder) ListResources(ctx, resourceGroup, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResources", reflect.TypeOf((*MockClient)(nil).ListResources), ctx, resourceGroup, filter)
+}
+
+// ListSubscriptionResources mocks base method.
+func (m *MockClient) ListSubscriptionResources(ctx context.Context, filter string) ([]*armresources.GenericResourceExpanded, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptionResources", ctx, filter)
+	ret0, _ := ret[0].([]*armresources.GenericResourceExpanded)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptionResources indicates an expected call of ListSubscriptionResources.
+func (mr *MockClientMockRecorder) ListSubscriptionResources(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptionResources", reflect.TypeOf((*MockClient)(nil).ListSubscriptionResources), ctx, filter)
+}
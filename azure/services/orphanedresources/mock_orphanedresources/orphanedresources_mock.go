@@ -0,0 +1,224 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../orphanedresources.go
+//
+// Generated by this command:
+//
+//	mockgen -destination orphanedresources_mock.go -package mock_orphanedresources -source ../orphanedresources.go OrphanedResourcesScope
+//
+// Package mock_orphanedresources is a generated GoMock package.
+package mock_orphanedresources
+
+import (
+	reflect "reflect"
+
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	gomock "go.uber.org/mock/gomock"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MockOrphanedResourcesScope is a mock of OrphanedResourcesScope interface.
+type MockOrphanedResourcesScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrphanedResourcesScopeMockRecorder
+}
+
+// MockOrphanedResourcesScopeMockRecorder is the mock recorder for MockOrphanedResourcesScope.
+type MockOrphanedResourcesScopeMockRecorder struct {
+	mock *MockOrphanedResourcesScope
+}
+
+// NewMockOrphanedResourcesScope creates a new mock instance.
+func NewMockOrphanedResourcesScope(ctrl *gomock.Controller) *MockOrphanedResourcesScope {
+	mock := &MockOrphanedResourcesScope{ctrl: ctrl}
+	mock.recorder = &MockOrphanedResourcesScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrphanedResourcesScope) EXPECT() *MockOrphanedResourcesScopeMockRecorder {
+	return m.recorder
+}
+
+// BaseURI mocks base method.
+func (m *MockOrphanedResourcesScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockOrphanedResourcesScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockOrphanedResourcesScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockOrphanedResourcesScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockOrphanedResourcesScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockOrphanedResourcesScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockOrphanedResourcesScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockOrphanedResourcesScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockOrphanedResourcesScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockOrphanedResourcesScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockOrphanedResourcesScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockOrphanedResourcesScope)(nil).CloudEnvironment))
+}
+
+// ClusterName mocks base method.
+func (m *MockOrphanedResourcesScope) ClusterName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClusterName indicates an expected call of ClusterName.
+func (mr *MockOrphanedResourcesScopeMockRecorder) ClusterName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockOrphanedResourcesScope)(nil).ClusterName))
+}
+
+// GetClient mocks base method.
+func (m *MockOrphanedResourcesScope) GetClient() client.Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClient")
+	ret0, _ := ret[0].(client.Client)
+	return ret0
+}
+
+// GetClient indicates an expected call of GetClient.
+func (mr *MockOrphanedResourcesScopeMockRecorder) GetClient() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClient", reflect.TypeOf((*MockOrphanedResourcesScope)(nil).GetClient))
+}
+
+// HashKey mocks base method.
+func (m *MockOrphanedResourcesScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockOrphanedResourcesScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockOrphanedResourcesScope)(nil).HashKey))
+}
+
+// Namespace mocks base method.
+func (m *MockOrphanedResourcesScope) Namespace() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Namespace")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Namespace indicates an expected call of Namespace.
+func (mr *MockOrphanedResourcesScopeMockRecorder) Namespace() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Namespace", reflect.TypeOf((*MockOrphanedResourcesScope)(nil).Namespace))
+}
+
+// ResourceGroup mocks base method.
+func (m *MockOrphanedResourcesScope) ResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceGroup indicates an expected call of ResourceGroup.
+func (mr *MockOrphanedResourcesScopeMockRecorder) ResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockOrphanedResourcesScope)(nil).ResourceGroup))
+}
+
+// SubscriptionID mocks base method.
+func (m *MockOrphanedResourcesScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockOrphanedResourcesScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockOrphanedResourcesScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockOrphanedResourcesScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockOrphanedResourcesScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockOrphanedResourcesScope)(nil).TenantID))
+}
+
+// Token mocks base method.
+func (m *MockOrphanedResourcesScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockOrphanedResourcesScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockOrphanedResourcesScope)(nil).Token))
+}
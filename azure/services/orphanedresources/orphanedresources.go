@@ -0,0 +1,284 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphanedresources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "orphanedresources"
+
+const virtualMachineType = "Microsoft.Compute/virtualMachines"
+
+// collectableTypes are the resource types that are garbage collected once their machine is gone, in deletion order.
+// Network interfaces come first as they reference the public IPs.
+var collectableTypes = []string{
+	"Microsoft.Network/networkInterfaces",
+	"Microsoft.Network/publicIPAddresses",
+	"Microsoft.Compute/disks",
+}
+
+// ownedResourcesRequeue is how long to wait for the deletion of the resources owned by the cluster before checking again.
+var ownedResourcesRequeue = 15 * time.Second
+
+// OrphanedResourcesScope defines the scope interface for the orphaned resources service.
+type OrphanedResourcesScope interface {
+	azure.Authorizer
+	ResourceGroup() string
+	ClusterName() string
+	Namespace() string
+	GetClient() client.Client
+}
+
+// Service garbage collects the network interfaces, public IPs and OS disks left behind by deleted machines,
+// and the resources owned by the cluster left in a resource group that is not deleted along with the cluster.
+type Service struct {
+	Scope OrphanedResourcesScope
+	Client
+}
+
+// New creates a new service.
+func New(scope OrphanedResourcesScope) (*Service, error) {
+	cli, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:  scope,
+		Client: cli,
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile deletes the resources owned by the cluster and tagged as dedicated to a machine when neither the
+// AzureMachine nor its virtual machine exist anymore. Failed partial machine deletions would otherwise leak them.
+// Deletions are best effort: a resource which cannot be deleted yet, e.g. a public IP still referenced by a network
+// interface being deleted, is retried on the next reconciliation.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "orphanedresources.Service.Reconcile")
+	defer done()
+
+	filter := fmt.Sprintf("tagName eq '%s'", infrav1.NameAzureClusterAPIMachine)
+	resources, err := s.Client.ListResources(ctx, s.Scope.ResourceGroup(), filter)
+	if azure.ResourceNotFound(err) {
+		// The resource group does not exist yet.
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to list machine resources")
+	}
+
+	vmExists := make(map[string]bool)
+	candidates := make(map[string][]*armresources.GenericResourceExpanded)
+	for _, resource := range resources {
+		tags := converters.MapToTags(resource.Tags)
+		if !tags.HasOwned(s.Scope.ClusterName()) {
+			continue
+		}
+		machineName := tags[infrav1.NameAzureClusterAPIMachine]
+		if strings.EqualFold(ptr.Deref(resource.Type, ""), virtualMachineType) {
+			vmExists[machineName] = true
+			continue
+		}
+		candidates[machineName] = append(candidates[machineName], resource)
+	}
+
+	machineNames := make([]string, 0, len(candidates))
+	for machineName := range candidates {
+		machineNames = append(machineNames, machineName)
+	}
+	sort.Strings(machineNames)
+
+	for _, machineName := range machineNames {
+		// The machine deletion is still in progress and will take care of its own resources.
+		if vmExists[machineName] {
+			continue
+		}
+		exists, err := s.machineExists(ctx, machineName)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		for _, resourceType := range collectableTypes {
+			for _, resource := range candidates[machineName] {
+				if !strings.EqualFold(ptr.Deref(resource.Type, ""), resourceType) {
+					continue
+				}
+				log.Info("Deleting orphaned resource of deleted machine", "machine", machineName, "resource", ptr.Deref(resource.ID, ""))
+				if err := s.beginDelete(ctx, resource); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// machineExists returns true if the AzureMachine with the given name exists in the cluster namespace.
+func (s *Service) machineExists(ctx context.Context, machineName string) (bool, error) {
+	azureMachine := &infrav1.AzureMachine{}
+	key := client.ObjectKey{Namespace: s.Scope.Namespace(), Name: machineName}
+	if err := s.Scope.GetClient().Get(ctx, key, azureMachine); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get AzureMachine %s", machineName)
+	}
+	return true, nil
+}
+
+// beginDelete starts the deletion of a resource. Deletions are best effort: a resource which cannot be deleted yet,
+// e.g. because it is still referenced by another resource being deleted, is only logged and retried later.
+func (s *Service) beginDelete(ctx context.Context, resource *armresources.GenericResourceExpanded) error {
+	_, log, done := tele.StartSpanWithLogger(ctx, "orphanedresources.Service.beginDelete")
+	defer done()
+
+	if plan := azure.PlanFromContext(ctx); plan != nil {
+		log.V(2).Info("planning operation", "operation", infrav1.PlannedOperationDelete, "service", ServiceName, "resource", ptr.Deref(resource.ID, ""))
+		plan.Record(infrav1.PlannedOperationDelete, ServiceName, s.Scope.ResourceGroup(), ptr.Deref(resource.ID, ""))
+		return nil
+	}
+
+	apiVersion, err := s.Client.GetAPIVersion(ctx, ptr.Deref(resource.Type, ""))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get API version to delete %s", ptr.Deref(resource.ID, ""))
+	}
+	if err := s.Client.BeginDeleteByID(ctx, ptr.Deref(resource.ID, ""), apiVersion); err != nil && !azure.ResourceNotFound(err) {
+		log.V(2).Info("Failed to delete resource, will retry", "resource", ptr.Deref(resource.ID, ""), "error", err.Error())
+	}
+	return nil
+}
+
+// Delete deletes the resources owned by the cluster which are still in the resource group once every other service
+// has deleted its own resources. It is only called when the resource group is not deleted along with the cluster,
+// so resources which do not carry the cluster's ownership tag are never deleted.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "orphanedresources.Service.Delete")
+	defer done()
+
+	resources, err := s.ownedResources(ctx)
+	if azure.ResourceNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+
+	for _, resource := range resources {
+		log.Info("Deleting remaining resource owned by the cluster", "resource", ptr.Deref(resource.ID, ""))
+		if err := s.beginDelete(ctx, resource); err != nil {
+			return err
+		}
+	}
+	return azure.WithTransientError(errors.Errorf("waiting for %d resources owned by the cluster to be deleted", len(resources)), ownedResourcesRequeue)
+}
+
+// DeletionCandidates returns the IDs of the resources that deleting the cluster would delete from its resource group:
+// every resource if the whole resource group is deleted, or only the resources owned by the cluster otherwise.
+func (s *Service) DeletionCandidates(ctx context.Context, wholeGroup bool) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "orphanedresources.Service.DeletionCandidates")
+	defer done()
+
+	var resources []*armresources.GenericResourceExpanded
+	var err error
+	if wholeGroup {
+		resources, err = s.Client.ListResources(ctx, s.Scope.ResourceGroup(), "")
+		if err != nil && !azure.ResourceNotFound(err) {
+			return nil, errors.Wrap(err, "failed to list resources")
+		}
+	} else {
+		resources, err = s.ownedResources(ctx)
+		if err != nil && !azure.ResourceNotFound(err) {
+			return nil, err
+		}
+	}
+
+	ids := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		ids = append(ids, ptr.Deref(resource.ID, ""))
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// MachineResources returns the IDs of the network interfaces, public IPs and disks of the cluster resource group
+// which are owned by the cluster and tagged as dedicated to the given machine.
+func (s *Service) MachineResources(ctx context.Context, machineName string) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "orphanedresources.Service.MachineResources")
+	defer done()
+
+	filter := fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", infrav1.NameAzureClusterAPIMachine, machineName)
+	resources, err := s.Client.ListResources(ctx, s.Scope.ResourceGroup(), filter)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return nil, errors.Wrapf(err, "failed to list the resources of machine %s", machineName)
+	}
+
+	ids := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		if !converters.MapToTags(resource.Tags).HasOwned(s.Scope.ClusterName()) || !isCollectable(resource) {
+			continue
+		}
+		ids = append(ids, ptr.Deref(resource.ID, ""))
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// isCollectable returns true if the resource is of one of the collectableTypes.
+func isCollectable(resource *armresources.GenericResourceExpanded) bool {
+	for _, resourceType := range collectableTypes {
+		if strings.EqualFold(ptr.Deref(resource.Type, ""), resourceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownedResources returns the resources of the cluster resource group tagged as owned by the cluster.
+func (s *Service) ownedResources(ctx context.Context) ([]*armresources.GenericResourceExpanded, error) {
+	filter := fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", infrav1.ClusterTagKey(s.Scope.ClusterName()), infrav1.ResourceLifecycleOwned)
+	resources, err := s.Client.ListResources(ctx, s.Scope.ResourceGroup(), filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list resources owned by the cluster")
+	}
+	return resources, nil
+}
@@ -0,0 +1,389 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphanedresources
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/orphanedresources/mock_orphanedresources"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	nicID  = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/deleted-machine-nic"
+	pipID  = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-deleted-machine"
+	diskID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/deleted-machine_OSDisk"
+	vnetID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"
+
+	machineFilter = "tagName eq 'sigs.k8s.io_cluster-api-provider-azure_machine'"
+	ownedFilter   = "tagName eq 'sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster' and tagValue eq 'owned'"
+
+	deletedMachineFilter = "tagName eq 'sigs.k8s.io_cluster-api-provider-azure_machine' and tagValue eq 'deleted-machine'"
+)
+
+var notFoundError = &azcore.ResponseError{StatusCode: http.StatusNotFound}
+
+func machineResource(id, resourceType, machineName, clusterName string) *armresources.GenericResourceExpanded {
+	return &armresources.GenericResourceExpanded{
+		ID:   ptr.To(id),
+		Type: ptr.To(resourceType),
+		Tags: map[string]*string{
+			infrav1.ClusterTagKey(clusterName): ptr.To(string(infrav1.ResourceLifecycleOwned)),
+			infrav1.NameAzureClusterAPIMachine: ptr.To(machineName),
+		},
+	}
+}
+
+func TestReconcileOrphanedResources(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expect        func(m *mock_orphanedresources.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name: "no machine resources",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", machineFilter).Return(nil, nil)
+			},
+		},
+		{
+			name: "delete the resources of a deleted machine, network interfaces first",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", machineFilter).Return([]*armresources.GenericResourceExpanded{
+					machineResource(diskID, "Microsoft.Compute/disks", "deleted-machine", "my-cluster"),
+					machineResource(pipID, "Microsoft.Network/publicIPAddresses", "deleted-machine", "my-cluster"),
+					machineResource(nicID, "Microsoft.Network/networkInterfaces", "deleted-machine", "my-cluster"),
+				}, nil)
+				gomock.InOrder(
+					m.GetAPIVersion(gomockinternal.AContext(), "Microsoft.Network/networkInterfaces").Return("2022-07-01", nil),
+					m.BeginDeleteByID(gomockinternal.AContext(), nicID, "2022-07-01").Return(nil),
+					m.GetAPIVersion(gomockinternal.AContext(), "Microsoft.Network/publicIPAddresses").Return("2022-07-01", nil),
+					m.BeginDeleteByID(gomockinternal.AContext(), pipID, "2022-07-01").Return(nil),
+					m.GetAPIVersion(gomockinternal.AContext(), "Microsoft.Compute/disks").Return("2022-07-02", nil),
+					m.BeginDeleteByID(gomockinternal.AContext(), diskID, "2022-07-02").Return(nil),
+				)
+			},
+		},
+		{
+			name: "keep the resources of an existing machine",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", machineFilter).Return([]*armresources.GenericResourceExpanded{
+					machineResource("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/existing-machine-nic", "Microsoft.Network/networkInterfaces", "existing-machine", "my-cluster"),
+				}, nil)
+			},
+		},
+		{
+			name: "keep the resources of a machine whose virtual machine still exists",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", machineFilter).Return([]*armresources.GenericResourceExpanded{
+					machineResource("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/deleted-machine", "Microsoft.Compute/virtualMachines", "deleted-machine", "my-cluster"),
+					machineResource(nicID, "Microsoft.Network/networkInterfaces", "deleted-machine", "my-cluster"),
+				}, nil)
+			},
+		},
+		{
+			name: "keep resources not owned by the cluster",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", machineFilter).Return([]*armresources.GenericResourceExpanded{
+					machineResource(nicID, "Microsoft.Network/networkInterfaces", "deleted-machine", "other-cluster"),
+				}, nil)
+			},
+		},
+		{
+			name: "failed deletions are retried later",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", machineFilter).Return([]*armresources.GenericResourceExpanded{
+					machineResource(nicID, "Microsoft.Network/networkInterfaces", "deleted-machine", "my-cluster"),
+					machineResource(pipID, "Microsoft.Network/publicIPAddresses", "deleted-machine", "my-cluster"),
+				}, nil)
+				gomock.InOrder(
+					m.GetAPIVersion(gomockinternal.AContext(), "Microsoft.Network/networkInterfaces").Return("2022-07-01", nil),
+					m.BeginDeleteByID(gomockinternal.AContext(), nicID, "2022-07-01").Return(nil),
+					m.GetAPIVersion(gomockinternal.AContext(), "Microsoft.Network/publicIPAddresses").Return("2022-07-01", nil),
+					m.BeginDeleteByID(gomockinternal.AContext(), pipID, "2022-07-01").Return(errors.New("#: InUsePublicIpAddressCannotBeDeleted")),
+				)
+			},
+		},
+		{
+			name: "resource group does not exist yet",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", machineFilter).Return(nil, notFoundError)
+			},
+		},
+		{
+			name:          "fail to list machine resources",
+			expectedError: "failed to list machine resources: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", machineFilter).Return(nil, errors.New("#: Internal Server Error: StatusCode=500"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "existing-machine",
+					Namespace: "default",
+				},
+			}).Build()
+
+			scopeMock := mock_orphanedresources.NewMockOrphanedResourcesScope(mockCtrl)
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			scopeMock.EXPECT().Namespace().AnyTimes().Return("default")
+			scopeMock.EXPECT().GetClient().AnyTimes().Return(kubeClient)
+			clientMock := mock_orphanedresources.NewMockClient(mockCtrl)
+			tc.expect(clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteOwnedResources(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expect        func(m *mock_orphanedresources.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name: "no resources left",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", ownedFilter).Return(nil, nil)
+			},
+		},
+		{
+			name: "resource group already deleted",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", ownedFilter).Return(nil, notFoundError)
+			},
+		},
+		{
+			name:          "delete the remaining owned resources and wait for them to be gone",
+			expectedError: "waiting for 2 resources owned by the cluster to be deleted. Object will be requeued after 15s",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", ownedFilter).Return([]*armresources.GenericResourceExpanded{
+					{ID: ptr.To(vnetID), Type: ptr.To("Microsoft.Network/virtualNetworks")},
+					{ID: ptr.To(pipID), Type: ptr.To("Microsoft.Network/publicIPAddresses")},
+				}, nil)
+				gomock.InOrder(
+					m.GetAPIVersion(gomockinternal.AContext(), "Microsoft.Network/virtualNetworks").Return("2022-07-01", nil),
+					m.BeginDeleteByID(gomockinternal.AContext(), vnetID, "2022-07-01").Return(errors.New("#: InUseSubnetCannotBeDeleted")),
+					m.GetAPIVersion(gomockinternal.AContext(), "Microsoft.Network/publicIPAddresses").Return("2022-07-01", nil),
+					m.BeginDeleteByID(gomockinternal.AContext(), pipID, "2022-07-01").Return(nil),
+				)
+			},
+		},
+		{
+			name:          "fail to get the API version of a resource type",
+			expectedError: "failed to get API version to delete " + pipID + ": #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", ownedFilter).Return([]*armresources.GenericResourceExpanded{
+					{ID: ptr.To(pipID), Type: ptr.To("Microsoft.Network/publicIPAddresses")},
+				}, nil)
+				m.GetAPIVersion(gomockinternal.AContext(), "Microsoft.Network/publicIPAddresses").Return("", errors.New("#: Internal Server Error: StatusCode=500"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_orphanedresources.NewMockOrphanedResourcesScope(mockCtrl)
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			clientMock := mock_orphanedresources.NewMockClient(mockCtrl)
+			tc.expect(clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeletionCandidates(t *testing.T) {
+	testcases := []struct {
+		name       string
+		wholeGroup bool
+		expect     func(m *mock_orphanedresources.MockClientMockRecorder)
+		want       []string
+	}{
+		{
+			name:       "every resource of a resource group deleted with the cluster",
+			wholeGroup: true,
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", "").Return([]*armresources.GenericResourceExpanded{
+					{ID: ptr.To(vnetID)},
+					{ID: ptr.To(nicID)},
+				}, nil)
+			},
+			want: []string{nicID, vnetID},
+		},
+		{
+			name:       "only the owned resources of a resource group kept after the cluster deletion",
+			wholeGroup: false,
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", ownedFilter).Return([]*armresources.GenericResourceExpanded{
+					{ID: ptr.To(pipID)},
+				}, nil)
+			},
+			want: []string{pipID},
+		},
+		{
+			name:       "resource group does not exist",
+			wholeGroup: true,
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", "").Return(nil, notFoundError)
+			},
+			want: []string{},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_orphanedresources.NewMockOrphanedResourcesScope(mockCtrl)
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			clientMock := mock_orphanedresources.NewMockClient(mockCtrl)
+			tc.expect(clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			got, err := s.DeletionCandidates(context.TODO(), tc.wholeGroup)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestMachineResources(t *testing.T) {
+	testcases := []struct {
+		name   string
+		expect func(m *mock_orphanedresources.MockClientMockRecorder)
+		want   []string
+	}{
+		{
+			name: "the network interfaces, public IPs and disks owned by the cluster",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", deletedMachineFilter).Return([]*armresources.GenericResourceExpanded{
+					machineResource(nicID, "Microsoft.Network/networkInterfaces", "deleted-machine", "my-cluster"),
+					machineResource(diskID, "Microsoft.Compute/disks", "deleted-machine", "my-cluster"),
+					machineResource(pipID, "Microsoft.Network/publicIPAddresses", "deleted-machine", "my-cluster"),
+					machineResource("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/deleted-machine", "Microsoft.Compute/virtualMachines", "deleted-machine", "my-cluster"),
+				}, nil)
+			},
+			want: []string{diskID, nicID, pipID},
+		},
+		{
+			name: "resources not owned by the cluster are left out",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", deletedMachineFilter).Return([]*armresources.GenericResourceExpanded{
+					machineResource(nicID, "Microsoft.Network/networkInterfaces", "deleted-machine", "other-cluster"),
+				}, nil)
+			},
+			want: []string{},
+		},
+		{
+			name: "resource group does not exist",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListResources(gomockinternal.AContext(), "my-rg", deletedMachineFilter).Return(nil, notFoundError)
+			},
+			want: []string{},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_orphanedresources.NewMockOrphanedResourcesScope(mockCtrl)
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			clientMock := mock_orphanedresources.NewMockClient(mockCtrl)
+			tc.expect(clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			got, err := s.MachineResources(context.TODO(), "deleted-machine")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphanedresources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// sweptTypes are the resource types looked for by the sweeper. They are the ones most often left behind by
+// interrupted cluster deletions, and keep being billed.
+var sweptTypes = []string{
+	"Microsoft.Network/publicIPAddresses",
+	"Microsoft.Compute/disks",
+}
+
+var (
+	orphanedAzureResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "capz_orphaned_azure_resources",
+		Help: "Number of public IPs and disks tagged as owned by clusters which no longer exist in the management cluster at the last sweep, by cluster, resource group and resource type.",
+	}, []string{"cluster", "resource_group", "resource_type"})
+
+	sweptAzureResources = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "capz_swept_azure_resources_total",
+		Help: "Number of orphaned public IPs and disks the sweeper started deleting, by resource type.",
+	}, []string{"resource_type"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanedAzureResources, sweptAzureResources)
+}
+
+// Sweeper periodically looks for the public IPs and disks of a subscription tagged as owned by clusters which no
+// longer exist in the management cluster, e.g. after an interrupted cluster deletion, and exports them as metrics.
+// When MinAge is set, it also deletes those created at least MinAge ago. Since it cannot tell them apart from the
+// resources of clusters of other management clusters, it must only delete resources in subscriptions used by a single
+// management cluster.
+type Sweeper struct {
+	Client client.Client
+	Azure  Client
+	// Interval is the time between two sweeps.
+	Interval time.Duration
+	// MinAge is the minimum age of the orphaned resources to delete. When 0, orphaned resources are not deleted.
+	MinAge time.Duration
+
+	now func() time.Time
+}
+
+// NewSweeper creates a sweeper of the subscription of auth.
+func NewSweeper(c client.Client, auth azure.Authorizer, interval, minAge time.Duration) (*Sweeper, error) {
+	cli, err := newClient(auth)
+	if err != nil {
+		return nil, err
+	}
+	return &Sweeper{
+		Client:   c,
+		Azure:    cli,
+		Interval: interval,
+		MinAge:   minAge,
+	}, nil
+}
+
+// NeedLeaderElection makes only the leader sweep.
+func (s *Sweeper) NeedLeaderElection() bool {
+	return true
+}
+
+// Start sweeps every Interval until ctx is done.
+func (s *Sweeper) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("sweeper")
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Sweep(ctx); err != nil {
+			log.Error(err, "failed to sweep orphaned resources")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep updates the orphaned resources metrics, and starts the deletion of the orphaned resources older than MinAge.
+// Deletions are best effort: a resource which cannot be deleted yet, e.g. a public IP still referenced by a leaked
+// load balancer, is retried on the next sweep.
+func (s *Sweeper) Sweep(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "orphanedresources.Sweeper.Sweep")
+	defer done()
+
+	clusters := &clusterv1.ClusterList{}
+	if err := s.Client.List(ctx, clusters); err != nil {
+		return errors.Wrap(err, "failed to list clusters")
+	}
+	// Resource tags only record the name of their cluster, a cluster in any namespace keeps them.
+	clusterExists := make(map[string]bool, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		clusterExists[cluster.Name] = true
+	}
+
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+
+	counts := map[[3]string]int{}
+	for _, resourceType := range sweptTypes {
+		resources, err := s.Azure.ListSubscriptionResources(ctx, fmt.Sprintf("resourceType eq '%s'", resourceType))
+		if err != nil {
+			return errors.Wrapf(err, "failed to list %s", resourceType)
+		}
+		for _, resource := range resources {
+			clusterName, orphaned := orphanedBy(resource, clusterExists)
+			if !orphaned {
+				continue
+			}
+			resourceGroup := ""
+			if id, err := arm.ParseResourceID(ptr.Deref(resource.ID, "")); err == nil {
+				resourceGroup = strings.ToLower(id.ResourceGroupName)
+			}
+			counts[[3]string{clusterName, resourceGroup, strings.ToLower(resourceType)}]++
+
+			if s.MinAge <= 0 || resource.CreatedTime == nil || now.Sub(*resource.CreatedTime) < s.MinAge {
+				continue
+			}
+			log.Info("Deleting orphaned resource of deleted cluster", "cluster", clusterName, "resource", ptr.Deref(resource.ID, ""))
+			if err := s.beginDelete(ctx, resource); err != nil {
+				return err
+			}
+		}
+	}
+
+	orphanedAzureResources.Reset()
+	for key, count := range counts {
+		orphanedAzureResources.WithLabelValues(key[0], key[1], key[2]).Set(float64(count))
+	}
+	return nil
+}
+
+// orphanedBy returns the cluster a resource is tagged as owned by, and whether none of the clusters owning it exist.
+func orphanedBy(resource *armresources.GenericResourceExpanded, clusterExists map[string]bool) (string, bool) {
+	owners := converters.MapToTags(resource.Tags).Owners()
+	if len(owners) == 0 {
+		return "", false
+	}
+	for _, owner := range owners {
+		if clusterExists[owner] {
+			return "", false
+		}
+	}
+	return owners[0], true
+}
+
+// beginDelete starts the deletion of a resource, only logging the failures which are retried on the next sweep.
+func (s *Sweeper) beginDelete(ctx context.Context, resource *armresources.GenericResourceExpanded) error {
+	_, log, done := tele.StartSpanWithLogger(ctx, "orphanedresources.Sweeper.beginDelete")
+	defer done()
+
+	apiVersion, err := s.Azure.GetAPIVersion(ctx, ptr.Deref(resource.Type, ""))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get API version to delete %s", ptr.Deref(resource.ID, ""))
+	}
+	if err := s.Azure.BeginDeleteByID(ctx, ptr.Deref(resource.ID, ""), apiVersion); err != nil {
+		if !azure.ResourceNotFound(err) {
+			log.V(2).Info("Failed to delete resource, will retry", "resource", ptr.Deref(resource.ID, ""), "error", err.Error())
+		}
+		return nil
+	}
+	sweptAzureResources.WithLabelValues(strings.ToLower(ptr.Deref(resource.Type, ""))).Inc()
+	return nil
+}
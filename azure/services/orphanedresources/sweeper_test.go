@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphanedresources
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/orphanedresources/mock_orphanedresources"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	publicIPFilter = "resourceType eq 'Microsoft.Network/publicIPAddresses'"
+	diskFilter     = "resourceType eq 'Microsoft.Compute/disks'"
+
+	orphanedPIPID  = "/subscriptions/123/resourceGroups/Deleted-RG/providers/Microsoft.Network/publicIPAddresses/pip-deleted-cluster-apiserver"
+	orphanedDiskID = "/subscriptions/123/resourceGroups/deleted-rg/providers/Microsoft.Compute/disks/deleted-cluster-md-0_OSDisk"
+)
+
+var sweepTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func clusterResource(id, resourceType string, created time.Time, clusterNames ...string) *armresources.GenericResourceExpanded {
+	tags := map[string]*string{}
+	for _, clusterName := range clusterNames {
+		tags[infrav1.ClusterTagKey(clusterName)] = ptr.To(string(infrav1.ResourceLifecycleOwned))
+	}
+	return &armresources.GenericResourceExpanded{
+		ID:          ptr.To(id),
+		Type:        ptr.To(resourceType),
+		Tags:        tags,
+		CreatedTime: ptr.To(created),
+	}
+}
+
+func TestSweep(t *testing.T) {
+	testcases := []struct {
+		name           string
+		minAge         time.Duration
+		expect         func(m *mock_orphanedresources.MockClientMockRecorder)
+		expectedPIPs   float64
+		expectedDisks  float64
+		expectedErrStr string
+	}{
+		{
+			name: "orphaned resources are only counted when no minimum age is set",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListSubscriptionResources(gomockinternal.AContext(), publicIPFilter).Return([]*armresources.GenericResourceExpanded{
+					clusterResource(orphanedPIPID, "Microsoft.Network/publicIPAddresses", sweepTime.Add(-48*time.Hour), "deleted-cluster"),
+				}, nil)
+				m.ListSubscriptionResources(gomockinternal.AContext(), diskFilter).Return([]*armresources.GenericResourceExpanded{
+					clusterResource(orphanedDiskID, "Microsoft.Compute/disks", sweepTime.Add(-48*time.Hour), "deleted-cluster"),
+				}, nil)
+			},
+			expectedPIPs:  1,
+			expectedDisks: 1,
+		},
+		{
+			name:   "orphaned resources older than the minimum age are deleted",
+			minAge: 24 * time.Hour,
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListSubscriptionResources(gomockinternal.AContext(), publicIPFilter).Return([]*armresources.GenericResourceExpanded{
+					clusterResource(orphanedPIPID, "Microsoft.Network/publicIPAddresses", sweepTime.Add(-48*time.Hour), "deleted-cluster"),
+				}, nil)
+				m.ListSubscriptionResources(gomockinternal.AContext(), diskFilter).Return([]*armresources.GenericResourceExpanded{
+					clusterResource(orphanedDiskID, "Microsoft.Compute/disks", sweepTime.Add(-time.Hour), "deleted-cluster"),
+				}, nil)
+				m.GetAPIVersion(gomockinternal.AContext(), "Microsoft.Network/publicIPAddresses").Return("2022-07-01", nil)
+				m.BeginDeleteByID(gomockinternal.AContext(), orphanedPIPID, "2022-07-01").Return(nil)
+			},
+			expectedPIPs:  1,
+			expectedDisks: 1,
+		},
+		{
+			name:   "resources of existing clusters and untagged resources are kept",
+			minAge: 24 * time.Hour,
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListSubscriptionResources(gomockinternal.AContext(), publicIPFilter).Return([]*armresources.GenericResourceExpanded{
+					clusterResource(orphanedPIPID, "Microsoft.Network/publicIPAddresses", sweepTime.Add(-48*time.Hour), "deleted-cluster", "my-cluster"),
+					clusterResource("/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/publicIPAddresses/other", "Microsoft.Network/publicIPAddresses", sweepTime.Add(-48*time.Hour)),
+				}, nil)
+				m.ListSubscriptionResources(gomockinternal.AContext(), diskFilter).Return(nil, nil)
+			},
+		},
+		{
+			name:           "fail to list resources",
+			expectedErrStr: "failed to list Microsoft.Network/publicIPAddresses: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_orphanedresources.MockClientMockRecorder) {
+				m.ListSubscriptionResources(gomockinternal.AContext(), publicIPFilter).Return(nil, errors.New("#: Internal Server Error: StatusCode=500"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scheme := runtime.NewScheme()
+			g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+			kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster",
+					Namespace: "default",
+				},
+			}).Build()
+			clientMock := mock_orphanedresources.NewMockClient(mockCtrl)
+			tc.expect(clientMock.EXPECT())
+
+			orphanedAzureResources.Reset()
+			s := &Sweeper{
+				Client: kubeClient,
+				Azure:  clientMock,
+				MinAge: tc.minAge,
+				now:    func() time.Time { return sweepTime },
+			}
+			err := s.Sweep(context.TODO())
+			if tc.expectedErrStr != "" {
+				g.Expect(err).To(MatchError(tc.expectedErrStr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(testutil.ToFloat64(orphanedAzureResources.WithLabelValues("deleted-cluster", "deleted-rg", "microsoft.network/publicipaddresses"))).To(Equal(tc.expectedPIPs))
+			g.Expect(testutil.ToFloat64(orphanedAzureResources.WithLabelValues("deleted-cluster", "deleted-rg", "microsoft.compute/disks"))).To(Equal(tc.expectedDisks))
+		})
+	}
+}
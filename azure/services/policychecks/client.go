@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policychecks
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/policyinsights/armpolicyinsights"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client checks the restrictions Azure Policy places on resources.
+type Client interface {
+	CheckRestrictions(ctx context.Context, request armpolicyinsights.CheckRestrictionsRequest) (armpolicyinsights.CheckRestrictionsResult, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	restrictions *armpolicyinsights.PolicyRestrictionsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new policy restrictions client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create policychecks client options")
+	}
+	restrictionsClient, err := armpolicyinsights.NewPolicyRestrictionsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armpolicyinsights policy restrictions client")
+	}
+	return &AzureClient{restrictionsClient}, nil
+}
+
+// CheckRestrictions evaluates a resource that is about to be created against the policies with a deny effect assigned
+// to the subscription.
+func (ac *AzureClient) CheckRestrictions(ctx context.Context, request armpolicyinsights.CheckRestrictionsRequest) (armpolicyinsights.CheckRestrictionsResult, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "policychecks.AzureClient.CheckRestrictions")
+	defer done()
+
+	resp, err := ac.restrictions.CheckAtSubscriptionScope(ctx, request, nil)
+	if err != nil {
+		return armpolicyinsights.CheckRestrictionsResult{}, err
+	}
+	return resp.CheckRestrictionsResult, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+//
+// Generated by this command:
+//
+//	mockgen -destination client_mock.go -package mock_policychecks -source ../client.go Client
+//
+// Package mock_policychecks is a generated GoMock package.
+package mock_policychecks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	armpolicyinsights "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/policyinsights/armpolicyinsights"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// CheckRestrictions mocks base method.
+func (m *MockClient) CheckRestrictions(ctx context.Context, request armpolicyinsights.CheckRestrictionsRequest) (armpolicyinsights.CheckRestrictionsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckRestrictions", ctx, request)
+	ret0, _ := ret[0].(armpolicyinsights.CheckRestrictionsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckRestrictions indicates an expected call of CheckRestrictions.
+func (mr *MockClientMockRecorder) CheckRestrictions(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRestrictions", reflect.TypeOf((*MockClient)(nil).CheckRestrictions), ctx, request)
+}
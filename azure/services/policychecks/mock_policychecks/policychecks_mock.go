@@ -0,0 +1,194 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../policychecks.go
+//
+// Generated by this command:
+//
+//	mockgen -destination policychecks_mock.go -package mock_policychecks -source ../policychecks.go PolicyCheckScope
+//
+// Package mock_policychecks is a generated GoMock package.
+package mock_policychecks
+
+import (
+	reflect "reflect"
+
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	gomock "go.uber.org/mock/gomock"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockPolicyCheckScope is a mock of PolicyCheckScope interface.
+type MockPolicyCheckScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyCheckScopeMockRecorder
+}
+
+// MockPolicyCheckScopeMockRecorder is the mock recorder for MockPolicyCheckScope.
+type MockPolicyCheckScopeMockRecorder struct {
+	mock *MockPolicyCheckScope
+}
+
+// NewMockPolicyCheckScope creates a new mock instance.
+func NewMockPolicyCheckScope(ctrl *gomock.Controller) *MockPolicyCheckScope {
+	mock := &MockPolicyCheckScope{ctrl: ctrl}
+	mock.recorder = &MockPolicyCheckScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPolicyCheckScope) EXPECT() *MockPolicyCheckScopeMockRecorder {
+	return m.recorder
+}
+
+// BaseURI mocks base method.
+func (m *MockPolicyCheckScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockPolicyCheckScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockPolicyCheckScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockPolicyCheckScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockPolicyCheckScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockPolicyCheckScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockPolicyCheckScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockPolicyCheckScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockPolicyCheckScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockPolicyCheckScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockPolicyCheckScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockPolicyCheckScope)(nil).CloudEnvironment))
+}
+
+// HashKey mocks base method.
+func (m *MockPolicyCheckScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockPolicyCheckScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockPolicyCheckScope)(nil).HashKey))
+}
+
+// PolicyCheckSpecs mocks base method.
+func (m *MockPolicyCheckScope) PolicyCheckSpecs() []azure.ResourceSpecGetter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PolicyCheckSpecs")
+	ret0, _ := ret[0].([]azure.ResourceSpecGetter)
+	return ret0
+}
+
+// PolicyCheckSpecs indicates an expected call of PolicyCheckSpecs.
+func (mr *MockPolicyCheckScopeMockRecorder) PolicyCheckSpecs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PolicyCheckSpecs", reflect.TypeOf((*MockPolicyCheckScope)(nil).PolicyCheckSpecs))
+}
+
+// SetPolicyViolations mocks base method.
+func (m *MockPolicyCheckScope) SetPolicyViolations(violations []string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPolicyViolations", violations)
+}
+
+// SetPolicyViolations indicates an expected call of SetPolicyViolations.
+func (mr *MockPolicyCheckScopeMockRecorder) SetPolicyViolations(violations any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPolicyViolations", reflect.TypeOf((*MockPolicyCheckScope)(nil).SetPolicyViolations), violations)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockPolicyCheckScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockPolicyCheckScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockPolicyCheckScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockPolicyCheckScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockPolicyCheckScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockPolicyCheckScope)(nil).TenantID))
+}
+
+// Token mocks base method.
+func (m *MockPolicyCheckScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockPolicyCheckScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockPolicyCheckScope)(nil).Token))
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policychecks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/policyinsights/armpolicyinsights"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "policychecks"
+
+const (
+	// nonCompliantResult is the evaluation result of a policy that denies a resource.
+	nonCompliantResult = "NonCompliant"
+	// policyViolationRequeueInterval is how long to wait before checking resources denied by Azure Policy again.
+	policyViolationRequeueInterval = 5 * time.Minute
+)
+
+// PolicyCheckScope defines the scope interface for a policy checks service.
+type PolicyCheckScope interface {
+	azure.Authorizer
+	PolicyCheckSpecs() []azure.ResourceSpecGetter
+	SetPolicyViolations(violations []string)
+}
+
+// Service checks the resources of a cluster against Azure Policy before they are created.
+type Service struct {
+	Scope PolicyCheckScope
+	Client
+}
+
+// New creates a new policy checks service.
+func New(scope PolicyCheckScope) (*Service, error) {
+	client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:  scope,
+		Client: client,
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile checks the resources that are about to be created against the policies with a deny effect and reports
+// the resources that would be denied. No further resources are created as long as some are denied.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "policychecks.Service.Reconcile")
+	defer done()
+
+	specs := s.Scope.PolicyCheckSpecs()
+	if len(specs) == 0 {
+		log.V(2).Info("skip policy checks when no resource needs to be checked")
+		return nil
+	}
+
+	var violations []string
+	for _, spec := range specs {
+		resourceViolations, err := s.checkResource(ctx, spec)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check %s against Azure Policy", spec.ResourceName())
+		}
+		violations = append(violations, resourceViolations...)
+	}
+
+	s.Scope.SetPolicyViolations(violations)
+	if len(violations) > 0 {
+		return azure.WithTransientError(errors.Errorf("resources of the cluster are denied by Azure Policy: %s", strings.Join(violations, "; ")),
+			policyViolationRequeueInterval)
+	}
+	return nil
+}
+
+// Delete is a no-op as checking resources against Azure Policy doesn't create any resource.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// checkResource returns a description of each policy assignment which would deny the creation of a resource.
+func (s *Service) checkResource(ctx context.Context, spec azure.ResourceSpecGetter) ([]string, error) {
+	resourceType, err := resourceType(spec)
+	if err != nil {
+		return nil, err
+	}
+	params, err := spec.Parameters(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get parameters")
+	}
+	if params == nil {
+		return nil, nil
+	}
+	content, err := resourceContent(spec.ResourceName(), resourceType, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resource groups are created in the subscription, other resources in their resource group.
+	scope := azure.ResourceGroupID(s.Scope.SubscriptionID(), spec.ResourceGroupName())
+	if resourceType == ResourceGroupType {
+		scope = "/subscriptions/" + s.Scope.SubscriptionID()
+	}
+	result, err := s.Client.CheckRestrictions(ctx, armpolicyinsights.CheckRestrictionsRequest{
+		ResourceDetails: &armpolicyinsights.CheckRestrictionsResourceDetails{
+			ResourceContent: content,
+			APIVersion:      ptr.To(apiVersions[resourceType]),
+			Scope:           ptr.To(scope),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.ContentEvaluationResult == nil {
+		return nil, nil
+	}
+
+	var violations []string
+	seen := make(map[string]bool)
+	for _, evaluation := range result.ContentEvaluationResult.PolicyEvaluations {
+		if evaluation == nil || ptr.Deref(evaluation.EvaluationResult, "") != nonCompliantResult || evaluation.PolicyInfo == nil {
+			continue
+		}
+		assignment := ptr.Deref(evaluation.PolicyInfo.PolicyAssignmentID, "")
+		if seen[assignment] {
+			continue
+		}
+		seen[assignment] = true
+		violations = append(violations, fmt.Sprintf("%s %s is denied by policy assignment %s", resourceType, spec.ResourceName(), assignment))
+	}
+	return violations, nil
+}
+
+// resourceContent returns the content of a resource as it is sent to ARM when creating it.
+func resourceContent(name, resourceType string, params interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal parameters")
+	}
+	content := map[string]interface{}{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal parameters")
+	}
+	content["name"] = name
+	content["type"] = resourceType
+	return content, nil
+}
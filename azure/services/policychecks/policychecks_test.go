@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policychecks
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/policyinsights/armpolicyinsights"
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/policychecks/mock_policychecks"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeResourceGroupSpec = &ResourceGroupSpec{
+		Name:        "test-rg",
+		Location:    "westus",
+		ClusterName: "test-cluster",
+	}
+	internalError  = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+	denyEvaluation = &armpolicyinsights.PolicyEvaluationResult{
+		EvaluationResult: ptr.To(nonCompliantResult),
+		PolicyInfo: &armpolicyinsights.PolicyReference{
+			PolicyAssignmentID: ptr.To("/subscriptions/123/providers/Microsoft.Authorization/policyAssignments/allowed-locations"),
+		},
+	}
+	compliantEvaluation = &armpolicyinsights.PolicyEvaluationResult{
+		EvaluationResult: ptr.To("Compliant"),
+		PolicyInfo: &armpolicyinsights.PolicyReference{
+			PolicyAssignmentID: ptr.To("/subscriptions/123/providers/Microsoft.Authorization/policyAssignments/require-tags"),
+		},
+	}
+)
+
+func checkResult(evaluations ...*armpolicyinsights.PolicyEvaluationResult) armpolicyinsights.CheckRestrictionsResult {
+	return armpolicyinsights.CheckRestrictionsResult{
+		ContentEvaluationResult: &armpolicyinsights.CheckRestrictionsResultContentEvaluationResult{
+			PolicyEvaluations: evaluations,
+		},
+	}
+}
+
+func TestReconcilePolicyChecks(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_policychecks.MockPolicyCheckScopeMockRecorder, c *mock_policychecks.MockClientMockRecorder)
+	}{
+		{
+			name: "noop if there are no resources to check",
+			expect: func(s *mock_policychecks.MockPolicyCheckScopeMockRecorder, c *mock_policychecks.MockClientMockRecorder) {
+				s.PolicyCheckSpecs().Return(nil)
+			},
+		},
+		{
+			name: "resources are compliant",
+			expect: func(s *mock_policychecks.MockPolicyCheckScopeMockRecorder, c *mock_policychecks.MockClientMockRecorder) {
+				s.PolicyCheckSpecs().Return([]azure.ResourceSpecGetter{fakeResourceGroupSpec})
+				s.SubscriptionID().Return("123").AnyTimes()
+				c.CheckRestrictions(gomockinternal.AContext(), gomock.Any()).Return(checkResult(compliantEvaluation), nil)
+				s.SetPolicyViolations(nil)
+			},
+		},
+		{
+			name:          "resources are denied by policy",
+			expectedError: "resources of the cluster are denied by Azure Policy: Microsoft.Resources/resourceGroups test-rg is denied by policy assignment /subscriptions/123/providers/Microsoft.Authorization/policyAssignments/allowed-locations. Object will be requeued after 5m0s",
+			expect: func(s *mock_policychecks.MockPolicyCheckScopeMockRecorder, c *mock_policychecks.MockClientMockRecorder) {
+				s.PolicyCheckSpecs().Return([]azure.ResourceSpecGetter{fakeResourceGroupSpec})
+				s.SubscriptionID().Return("123").AnyTimes()
+				c.CheckRestrictions(gomockinternal.AContext(), gomock.Any()).Return(checkResult(denyEvaluation, denyEvaluation, compliantEvaluation), nil)
+				s.SetPolicyViolations([]string{
+					"Microsoft.Resources/resourceGroups test-rg is denied by policy assignment /subscriptions/123/providers/Microsoft.Authorization/policyAssignments/allowed-locations",
+				})
+			},
+		},
+		{
+			name:          "error checking resources",
+			expectedError: "failed to check test-rg against Azure Policy: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_policychecks.MockPolicyCheckScopeMockRecorder, c *mock_policychecks.MockClientMockRecorder) {
+				s.PolicyCheckSpecs().Return([]azure.ResourceSpecGetter{fakeResourceGroupSpec})
+				s.SubscriptionID().Return("123").AnyTimes()
+				c.CheckRestrictions(gomockinternal.AContext(), gomock.Any()).Return(armpolicyinsights.CheckRestrictionsResult{}, internalError)
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_policychecks.NewMockPolicyCheckScope(mockCtrl)
+			clientMock := mock_policychecks.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestCheckResourceUnsupportedSpec(t *testing.T) {
+	g := NewWithT(t)
+	s := &Service{}
+	_, err := s.checkResource(context.TODO(), &subnets.SubnetSpec{})
+	g.Expect(err).To(MatchError("unsupported resource spec type *subnets.SubnetSpec"))
+}
+
+func TestCheckResourceRequest(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_policychecks.NewMockPolicyCheckScope(mockCtrl)
+	clientMock := mock_policychecks.NewMockClient(mockCtrl)
+
+	scopeMock.EXPECT().SubscriptionID().Return("123").AnyTimes()
+	var request armpolicyinsights.CheckRestrictionsRequest
+	clientMock.EXPECT().CheckRestrictions(gomockinternal.AContext(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, r armpolicyinsights.CheckRestrictionsRequest) (armpolicyinsights.CheckRestrictionsResult, error) {
+			request = r
+			return armpolicyinsights.CheckRestrictionsResult{}, nil
+		})
+
+	s := &Service{
+		Scope:  scopeMock,
+		Client: clientMock,
+	}
+	violations, err := s.checkResource(context.TODO(), fakeResourceGroupSpec)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(violations).To(BeEmpty())
+	g.Expect(request.ResourceDetails).NotTo(BeNil())
+	g.Expect(*request.ResourceDetails.Scope).To(Equal("/subscriptions/123"))
+	g.Expect(*request.ResourceDetails.APIVersion).To(Equal("2021-04-01"))
+	content, ok := request.ResourceDetails.ResourceContent.(map[string]interface{})
+	g.Expect(ok).To(BeTrue())
+	g.Expect(content).To(HaveKeyWithValue("name", "test-rg"))
+	g.Expect(content).To(HaveKeyWithValue("type", ResourceGroupType))
+	g.Expect(content).To(HaveKeyWithValue("location", "westus"))
+	g.Expect(content).To(HaveKey("tags"))
+}
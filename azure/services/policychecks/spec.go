@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policychecks
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/securitygroups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
+)
+
+// Types of the resources checked against Azure Policy.
+const (
+	ResourceGroupType        = "Microsoft.Resources/resourceGroups"
+	VirtualNetworkType       = "Microsoft.Network/virtualNetworks"
+	NetworkSecurityGroupType = "Microsoft.Network/networkSecurityGroups"
+	RouteTableType           = "Microsoft.Network/routeTables"
+	PublicIPAddressType      = "Microsoft.Network/publicIPAddresses"
+	LoadBalancerType         = "Microsoft.Network/loadBalancers"
+)
+
+// apiVersions are the API versions of the SDKs the parameters of each resource type are created with.
+var apiVersions = map[string]string{
+	ResourceGroupType:        "2021-04-01",
+	VirtualNetworkType:       "2023-05-01",
+	NetworkSecurityGroupType: "2023-05-01",
+	RouteTableType:           "2023-05-01",
+	PublicIPAddressType:      "2023-05-01",
+	LoadBalancerType:         "2023-05-01",
+}
+
+// resourceType returns the type of the resource created from a spec.
+func resourceType(spec azure.ResourceSpecGetter) (string, error) {
+	switch spec.(type) {
+	case *ResourceGroupSpec:
+		return ResourceGroupType, nil
+	case *virtualnetworks.VNetSpec:
+		return VirtualNetworkType, nil
+	case *securitygroups.NSGSpec:
+		return NetworkSecurityGroupType, nil
+	case *routetables.RouteTableSpec:
+		return RouteTableType, nil
+	case *publicips.PublicIPSpec:
+		return PublicIPAddressType, nil
+	case *loadbalancers.LBSpec:
+		return LoadBalancerType, nil
+	default:
+		return "", errors.Errorf("unsupported resource spec type %T", spec)
+	}
+}
+
+// ResourceGroupSpec defines the specification for a resource group when checking it against Azure Policy.
+// Resource groups are created through ASO, so they don't otherwise have an SDK spec.
+type ResourceGroupSpec struct {
+	Name           string
+	Location       string
+	ClusterName    string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the resource group.
+func (s *ResourceGroupSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *ResourceGroupSpec) ResourceGroupName() string {
+	return s.Name
+}
+
+// OwnerResourceName is a no-op for resource groups.
+func (s *ResourceGroupSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the resource group.
+func (s *ResourceGroupSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	return armresources.ResourceGroup{
+		Location: ptr.To(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Role:        ptr.To(infrav1.CommonRole),
+			Additional:  s.AdditionalTags,
+		})),
+	}, nil
+}
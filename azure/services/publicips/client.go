@@ -59,6 +59,22 @@ func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 	return resp.PublicIPAddress, nil
 }
 
+// ListAll returns all public IP addresses in the subscription.
+func (ac *AzureClient) ListAll(ctx context.Context) (result []armnetwork.PublicIPAddress, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicips.AzureClient.ListAll")
+	defer done()
+
+	pager := ac.publicips.NewListAllPager(nil)
+	publicIPs, err := azure.CollectPages(ctx, pager, func(page armnetwork.PublicIPAddressesClientListAllResponse) []*armnetwork.PublicIPAddress {
+		return page.Value
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not iterate public IPs")
+	}
+
+	return publicIPs, nil
+}
+
 // CreateOrUpdateAsync creates or updates a static or dynamic public IP address.
 // It sends a PUT request to Azure and if accepted without error, the func will return a Poller which can be used to track the ongoing
 // progress of the operation.
@@ -25,9 +25,11 @@ limitations under the License.
 package mock_publicips
 
 import (
+	context "context"
 	reflect "reflect"
 
 	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	armnetwork "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	gomock "go.uber.org/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -99,6 +101,34 @@ func (mr *MockPublicIPScopeMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockPublicIPScope)(nil).BaseURI))
 }
 
+// BootstrapDataKeyVault mocks base method.
+func (m *MockPublicIPScope) BootstrapDataKeyVault() *v1beta1.BootstrapDataKeyVault {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataKeyVault")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataKeyVault)
+	return ret0
+}
+
+// BootstrapDataKeyVault indicates an expected call of BootstrapDataKeyVault.
+func (mr *MockPublicIPScopeMockRecorder) BootstrapDataKeyVault() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataKeyVault", reflect.TypeOf((*MockPublicIPScope)(nil).BootstrapDataKeyVault))
+}
+
+// BootstrapDataStorageAccountName mocks base method.
+func (m *MockPublicIPScope) BootstrapDataStorageAccountName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorageAccountName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BootstrapDataStorageAccountName indicates an expected call of BootstrapDataStorageAccountName.
+func (mr *MockPublicIPScopeMockRecorder) BootstrapDataStorageAccountName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorageAccountName", reflect.TypeOf((*MockPublicIPScope)(nil).BootstrapDataStorageAccountName))
+}
+
 // ClientID mocks base method.
 func (m *MockPublicIPScope) ClientID() string {
 	m.ctrl.T.Helper()
@@ -279,6 +309,34 @@ func (mr *MockPublicIPScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockPublicIPScope)(nil).Location))
 }
 
+// ManagedIdentityProviderID mocks base method.
+func (m *MockPublicIPScope) ManagedIdentityProviderID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedIdentityProviderID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ManagedIdentityProviderID indicates an expected call of ManagedIdentityProviderID.
+func (mr *MockPublicIPScopeMockRecorder) ManagedIdentityProviderID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedIdentityProviderID", reflect.TypeOf((*MockPublicIPScope)(nil).ManagedIdentityProviderID))
+}
+
+// PlatformFaultDomainCount mocks base method.
+func (m *MockPublicIPScope) PlatformFaultDomainCount() *int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlatformFaultDomainCount")
+	ret0, _ := ret[0].(*int32)
+	return ret0
+}
+
+// PlatformFaultDomainCount indicates an expected call of PlatformFaultDomainCount.
+func (mr *MockPublicIPScopeMockRecorder) PlatformFaultDomainCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlatformFaultDomainCount", reflect.TypeOf((*MockPublicIPScope)(nil).PlatformFaultDomainCount))
+}
+
 // PublicIPSpecs mocks base method.
 func (m *MockPublicIPScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 	m.ctrl.T.Helper()
@@ -307,6 +365,34 @@ func (mr *MockPublicIPScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockPublicIPScope)(nil).ResourceGroup))
 }
 
+// SSHKeyPairSecretName mocks base method.
+func (m *MockPublicIPScope) SSHKeyPairSecretName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHKeyPairSecretName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SSHKeyPairSecretName indicates an expected call of SSHKeyPairSecretName.
+func (mr *MockPublicIPScopeMockRecorder) SSHKeyPairSecretName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHKeyPairSecretName", reflect.TypeOf((*MockPublicIPScope)(nil).SSHKeyPairSecretName))
+}
+
+// SSHPublicKeys mocks base method.
+func (m *MockPublicIPScope) SSHPublicKeys(role string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHPublicKeys", role)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SSHPublicKeys indicates an expected call of SSHPublicKeys.
+func (mr *MockPublicIPScopeMockRecorder) SSHPublicKeys(role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHPublicKeys", reflect.TypeOf((*MockPublicIPScope)(nil).SSHPublicKeys), role)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockPublicIPScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -396,3 +482,125 @@ func (mr *MockPublicIPScopeMockRecorder) UpdatePutStatus(arg0, arg1, arg2 any) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePutStatus", reflect.TypeOf((*MockPublicIPScope)(nil).UpdatePutStatus), arg0, arg1, arg2)
 }
+
+// MockExistingPublicIPScope is a mock of ExistingPublicIPScope interface.
+type MockExistingPublicIPScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockExistingPublicIPScopeMockRecorder
+}
+
+// MockExistingPublicIPScopeMockRecorder is the mock recorder for MockExistingPublicIPScope.
+type MockExistingPublicIPScopeMockRecorder struct {
+	mock *MockExistingPublicIPScope
+}
+
+// NewMockExistingPublicIPScope creates a new mock instance.
+func NewMockExistingPublicIPScope(ctrl *gomock.Controller) *MockExistingPublicIPScope {
+	mock := &MockExistingPublicIPScope{ctrl: ctrl}
+	mock.recorder = &MockExistingPublicIPScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExistingPublicIPScope) EXPECT() *MockExistingPublicIPScopeMockRecorder {
+	return m.recorder
+}
+
+// ExistingAPIServerPublicIP mocks base method.
+func (m *MockExistingPublicIPScope) ExistingAPIServerPublicIP() *v1beta1.ExistingPublicIP {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistingAPIServerPublicIP")
+	ret0, _ := ret[0].(*v1beta1.ExistingPublicIP)
+	return ret0
+}
+
+// ExistingAPIServerPublicIP indicates an expected call of ExistingAPIServerPublicIP.
+func (mr *MockExistingPublicIPScopeMockRecorder) ExistingAPIServerPublicIP() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistingAPIServerPublicIP", reflect.TypeOf((*MockExistingPublicIPScope)(nil).ExistingAPIServerPublicIP))
+}
+
+// SetExistingAPIServerPublicIP mocks base method.
+func (m *MockExistingPublicIPScope) SetExistingAPIServerPublicIP(id, name, dnsName string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetExistingAPIServerPublicIP", id, name, dnsName)
+}
+
+// SetExistingAPIServerPublicIP indicates an expected call of SetExistingAPIServerPublicIP.
+func (mr *MockExistingPublicIPScopeMockRecorder) SetExistingAPIServerPublicIP(id, name, dnsName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExistingAPIServerPublicIP", reflect.TypeOf((*MockExistingPublicIPScope)(nil).SetExistingAPIServerPublicIP), id, name, dnsName)
+}
+
+// MockAddressRecorderScope is a mock of AddressRecorderScope interface.
+type MockAddressRecorderScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddressRecorderScopeMockRecorder
+}
+
+// MockAddressRecorderScopeMockRecorder is the mock recorder for MockAddressRecorderScope.
+type MockAddressRecorderScopeMockRecorder struct {
+	mock *MockAddressRecorderScope
+}
+
+// NewMockAddressRecorderScope creates a new mock instance.
+func NewMockAddressRecorderScope(ctrl *gomock.Controller) *MockAddressRecorderScope {
+	mock := &MockAddressRecorderScope{ctrl: ctrl}
+	mock.recorder = &MockAddressRecorderScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddressRecorderScope) EXPECT() *MockAddressRecorderScopeMockRecorder {
+	return m.recorder
+}
+
+// SetPublicIPAddress mocks base method.
+func (m *MockAddressRecorderScope) SetPublicIPAddress(name, address string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPublicIPAddress", name, address)
+}
+
+// SetPublicIPAddress indicates an expected call of SetPublicIPAddress.
+func (mr *MockAddressRecorderScopeMockRecorder) SetPublicIPAddress(name, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPublicIPAddress", reflect.TypeOf((*MockAddressRecorderScope)(nil).SetPublicIPAddress), name, address)
+}
+
+// Mocklister is a mock of lister interface.
+type Mocklister struct {
+	ctrl     *gomock.Controller
+	recorder *MocklisterMockRecorder
+}
+
+// MocklisterMockRecorder is the mock recorder for Mocklister.
+type MocklisterMockRecorder struct {
+	mock *Mocklister
+}
+
+// NewMocklister creates a new mock instance.
+func NewMocklister(ctrl *gomock.Controller) *Mocklister {
+	mock := &Mocklister{ctrl: ctrl}
+	mock.recorder = &MocklisterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mocklister) EXPECT() *MocklisterMockRecorder {
+	return m.recorder
+}
+
+// ListAll mocks base method.
+func (m *Mocklister) ListAll(ctx context.Context) ([]armnetwork.PublicIPAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll", ctx)
+	ret0, _ := ret[0].([]armnetwork.PublicIPAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MocklisterMockRecorder) ListAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*Mocklister)(nil).ListAll), ctx)
+}
@@ -18,9 +18,13 @@ package publicips
 
 import (
 	"context"
+	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
@@ -31,6 +35,9 @@ import (
 
 const serviceName = "publicips"
 
+// existingPublicIPRequeue is how long to wait before looking up an existing public IP that is not usable yet.
+const existingPublicIPRequeue = 1 * time.Minute
+
 // PublicIPScope defines the scope interface for a public IP service.
 type PublicIPScope interface {
 	azure.Authorizer
@@ -39,12 +46,29 @@ type PublicIPScope interface {
 	PublicIPSpecs() []azure.ResourceSpecGetter
 }
 
+// ExistingPublicIPScope is implemented by scopes whose API server can use an existing public IP.
+type ExistingPublicIPScope interface {
+	ExistingAPIServerPublicIP() *infrav1.ExistingPublicIP
+	SetExistingAPIServerPublicIP(id, name, dnsName string)
+}
+
+// AddressRecorderScope is implemented by scopes which report the addresses of their public IPs.
+type AddressRecorderScope interface {
+	SetPublicIPAddress(name, address string)
+}
+
+// lister lists the public IPs of the subscription.
+type lister interface {
+	ListAll(ctx context.Context) ([]armnetwork.PublicIPAddress, error)
+}
+
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope PublicIPScope
 	async.Reconciler
 	async.Getter
 	async.TagsGetter
+	lister lister
 }
 
 // New creates a new service.
@@ -61,6 +85,7 @@ func New(scope PublicIPScope) (*Service, error) {
 		Scope:      scope,
 		Getter:     client,
 		TagsGetter: tagsClient,
+		lister:     client,
 		Reconciler: async.New[armnetwork.PublicIPAddressesClientCreateOrUpdateResponse, armnetwork.PublicIPAddressesClientDeleteResponse](scope, client, client),
 	}, nil
 }
@@ -75,6 +100,11 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicips.Service.Reconcile")
 	defer done()
 
+	if err := s.reconcileExistingAPIServerPublicIP(ctx); err != nil {
+		s.Scope.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, err)
+		return err
+	}
+
 	specs := s.Scope.PublicIPSpecs()
 	if len(specs) == 0 {
 		return nil
@@ -85,17 +115,118 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
 	var result error
 	for _, publicIPSpec := range specs {
-		if _, err := s.CreateOrUpdateResource(ctx, publicIPSpec, serviceName); err != nil {
+		publicIP, err := s.CreateOrUpdateResource(ctx, publicIPSpec, serviceName)
+		if err != nil {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
 			}
+			continue
 		}
+		s.recordAddress(publicIPSpec.ResourceName(), publicIP)
 	}
 
 	s.Scope.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, result)
 	return result
 }
 
+// reconcileExistingAPIServerPublicIP looks up the existing public IP of the API server, if any, and records its
+// resource ID and address in the scope. The public IP itself is never modified.
+func (s *Service) reconcileExistingAPIServerPublicIP(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "publicips.Service.reconcileExistingAPIServerPublicIP")
+	defer done()
+
+	existingScope, ok := s.Scope.(ExistingPublicIPScope)
+	if !ok {
+		return nil
+	}
+	existing := existingScope.ExistingAPIServerPublicIP()
+	if existing == nil {
+		return nil
+	}
+
+	var publicIP armnetwork.PublicIPAddress
+	if existing.ID != "" {
+		resourceID, err := arm.ParseResourceID(existing.ID)
+		if err != nil {
+			return azure.WithTerminalError(errors.Wrapf(err, "failed to parse existing public IP ID %s", existing.ID))
+		}
+		if !strings.EqualFold(resourceID.SubscriptionID, s.Scope.SubscriptionID()) {
+			return azure.WithTerminalError(errors.Errorf("existing public IP %s must be in subscription %s", existing.ID, s.Scope.SubscriptionID()))
+		}
+		result, err := s.Get(ctx, &PublicIPSpec{Name: resourceID.Name, ResourceGroup: resourceID.ResourceGroupName})
+		if err != nil {
+			if azure.ResourceNotFound(err) {
+				return azure.WithTransientError(errors.Errorf("existing public IP %s not found", existing.ID), existingPublicIPRequeue)
+			}
+			return errors.Wrapf(err, "failed to get existing public IP %s", existing.ID)
+		}
+		if publicIP, ok = result.(armnetwork.PublicIPAddress); !ok {
+			return errors.Errorf("%T is not an armnetwork.PublicIPAddress", result)
+		}
+	} else {
+		publicIPs, err := s.lister.ListAll(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to list public IPs")
+		}
+		found := false
+		for _, candidate := range publicIPs {
+			if candidate.Properties == nil || candidate.Properties.DNSSettings == nil {
+				continue
+			}
+			if ptr.Deref(candidate.Properties.DNSSettings.DomainNameLabel, "") == existing.DNSLabel &&
+				strings.EqualFold(ptr.Deref(candidate.Location, ""), s.Scope.Location()) {
+				publicIP = candidate
+				found = true
+				break
+			}
+		}
+		if !found {
+			return azure.WithTransientError(errors.Errorf("no public IP with DNS label %s found in %s", existing.DNSLabel, s.Scope.Location()), existingPublicIPRequeue)
+		}
+	}
+
+	id := ptr.Deref(publicIP.ID, "")
+	resourceID, err := arm.ParseResourceID(id)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse public IP ID %s", id)
+	}
+	// Deleting the cluster may delete its whole resource group, including anything else in it.
+	if strings.EqualFold(resourceID.ResourceGroupName, s.Scope.ResourceGroup()) {
+		return azure.WithTerminalError(errors.Errorf("existing public IP %s must not be in the resource group %s of the cluster", id, s.Scope.ResourceGroup()))
+	}
+
+	var address string
+	if publicIP.Properties != nil {
+		if publicIP.Properties.DNSSettings != nil {
+			address = ptr.Deref(publicIP.Properties.DNSSettings.Fqdn, "")
+		}
+		if address == "" {
+			address = ptr.Deref(publicIP.Properties.IPAddress, "")
+		}
+	}
+	if address == "" {
+		return azure.WithTransientError(errors.Errorf("existing public IP %s has neither a DNS name nor an IP address", id), existingPublicIPRequeue)
+	}
+
+	log.V(4).Info("using existing public IP for the API server", "public ip", id, "address", address)
+	existingScope.SetExistingAPIServerPublicIP(id, resourceID.Name, address)
+	s.recordAddress(resourceID.Name, publicIP)
+	return nil
+}
+
+// recordAddress records the IP address of a public IP in the scope, if it reports the addresses of its public IPs.
+func (s *Service) recordAddress(name string, result interface{}) {
+	recorder, ok := s.Scope.(AddressRecorderScope)
+	if !ok {
+		return
+	}
+	publicIP, ok := result.(armnetwork.PublicIPAddress)
+	if !ok || publicIP.Properties == nil || publicIP.Properties.IPAddress == nil {
+		return
+	}
+	recorder.SetPublicIPAddress(name, *publicIP.Properties.IPAddress)
+}
+
 // Delete deletes the public IP with the provided scope.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "publicips.Service.Delete")
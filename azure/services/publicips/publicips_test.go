@@ -21,6 +21,8 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/gomega"
@@ -186,6 +188,181 @@ func TestReconcilePublicIP(t *testing.T) {
 	}
 }
 
+// addressRecorderScope is a scope which reports the addresses of its public IPs.
+type addressRecorderScope struct {
+	*mock_publicips.MockPublicIPScope
+	*mock_publicips.MockAddressRecorderScope
+}
+
+func TestReconcilePublicIPRecordsAddresses(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scopeMock := mock_publicips.NewMockPublicIPScope(mockCtrl)
+	recorderMock := mock_publicips.NewMockAddressRecorderScope(mockCtrl)
+	reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+	s := scopeMock.EXPECT()
+	r := reconcilerMock.EXPECT()
+	s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPSpec1, &fakePublicIPSpec2, &fakePublicIPSpec3})
+	r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(armnetwork.PublicIPAddress{
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{IPAddress: ptr.To("20.1.2.3")},
+	}, nil)
+	// Public IPs which are not allocated yet have no address.
+	r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec2, serviceName).Return(armnetwork.PublicIPAddress{
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{},
+	}, nil)
+	r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec3, serviceName).Return(nil, internalError)
+	recorderMock.EXPECT().SetPublicIPAddress("my-publicip", "20.1.2.3")
+	s.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, internalError)
+
+	service := &Service{
+		Scope:      addressRecorderScope{MockPublicIPScope: scopeMock, MockAddressRecorderScope: recorderMock},
+		Reconciler: reconcilerMock,
+	}
+	g.Expect(service.Reconcile(context.TODO())).To(MatchError(internalError.Error()))
+}
+
+// existingPublicIPScope is a scope whose API server can use an existing public IP.
+type existingPublicIPScope struct {
+	*mock_publicips.MockPublicIPScope
+	*mock_publicips.MockExistingPublicIPScope
+}
+
+func TestReconcileExistingAPIServerPublicIP(t *testing.T) {
+	byoID := "/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/publicIPAddresses/byo-ip"
+	byoIP := armnetwork.PublicIPAddress{
+		ID:       ptr.To(byoID),
+		Location: ptr.To("westus"),
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+			IPAddress:   ptr.To("20.1.2.3"),
+			DNSSettings: &armnetwork.PublicIPAddressDNSSettings{DomainNameLabel: ptr.To("my-api"), Fqdn: ptr.To("my-api.westus.cloudapp.azure.com")},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_publicips.MockPublicIPScopeMockRecorder, e *mock_publicips.MockExistingPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, l *mock_publicips.MocklisterMockRecorder)
+	}{
+		{
+			name:          "noop if the API server does not use an existing public IP",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, e *mock_publicips.MockExistingPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, l *mock_publicips.MocklisterMockRecorder) {
+				e.ExistingAPIServerPublicIP().Return(nil)
+			},
+		},
+		{
+			name:          "use an existing public IP by ID",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, e *mock_publicips.MockExistingPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, l *mock_publicips.MocklisterMockRecorder) {
+				e.ExistingAPIServerPublicIP().Return(&infrav1.ExistingPublicIP{ID: byoID})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				g.Get(gomockinternal.AContext(), &PublicIPSpec{Name: "byo-ip", ResourceGroup: "shared-ips"}).Return(byoIP, nil)
+				e.SetExistingAPIServerPublicIP(byoID, "byo-ip", "my-api.westus.cloudapp.azure.com")
+			},
+		},
+		{
+			name:          "use an existing public IP by DNS label",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, e *mock_publicips.MockExistingPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, l *mock_publicips.MocklisterMockRecorder) {
+				e.ExistingAPIServerPublicIP().Return(&infrav1.ExistingPublicIP{DNSLabel: "my-api"})
+				s.Location().AnyTimes().Return("westus")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				other := byoIP
+				other.ID = ptr.To("/subscriptions/123/resourceGroups/shared-ips/providers/Microsoft.Network/publicIPAddresses/other-ip")
+				other.Location = ptr.To("eastus")
+				l.ListAll(gomockinternal.AContext()).Return([]armnetwork.PublicIPAddress{other, byoIP}, nil)
+				e.SetExistingAPIServerPublicIP(byoID, "byo-ip", "my-api.westus.cloudapp.azure.com")
+			},
+		},
+		{
+			name:          "fall back to the IP address of an existing public IP without a DNS name",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, e *mock_publicips.MockExistingPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, l *mock_publicips.MocklisterMockRecorder) {
+				e.ExistingAPIServerPublicIP().Return(&infrav1.ExistingPublicIP{ID: byoID})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				noDNS := byoIP
+				noDNS.Properties = &armnetwork.PublicIPAddressPropertiesFormat{IPAddress: ptr.To("20.1.2.3")}
+				g.Get(gomockinternal.AContext(), &PublicIPSpec{Name: "byo-ip", ResourceGroup: "shared-ips"}).Return(noDNS, nil)
+				e.SetExistingAPIServerPublicIP(byoID, "byo-ip", "20.1.2.3")
+			},
+		},
+		{
+			name:          "existing public IP in another subscription",
+			expectedError: "reconcile error that cannot be recovered occurred: existing public IP " + byoID + " must be in subscription 456. Object will not be requeued",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, e *mock_publicips.MockExistingPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, l *mock_publicips.MocklisterMockRecorder) {
+				e.ExistingAPIServerPublicIP().Return(&infrav1.ExistingPublicIP{ID: byoID})
+				s.SubscriptionID().AnyTimes().Return("456")
+			},
+		},
+		{
+			name:          "existing public IP in the resource group of the cluster",
+			expectedError: "reconcile error that cannot be recovered occurred: existing public IP " + byoID + " must not be in the resource group shared-ips of the cluster. Object will not be requeued",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, e *mock_publicips.MockExistingPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, l *mock_publicips.MocklisterMockRecorder) {
+				e.ExistingAPIServerPublicIP().Return(&infrav1.ExistingPublicIP{ID: byoID})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ResourceGroup().AnyTimes().Return("shared-ips")
+				g.Get(gomockinternal.AContext(), &PublicIPSpec{Name: "byo-ip", ResourceGroup: "shared-ips"}).Return(byoIP, nil)
+			},
+		},
+		{
+			name:          "existing public IP not found by ID",
+			expectedError: "existing public IP " + byoID + " not found. Object will be requeued after 1m0s",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, e *mock_publicips.MockExistingPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, l *mock_publicips.MocklisterMockRecorder) {
+				e.ExistingAPIServerPublicIP().Return(&infrav1.ExistingPublicIP{ID: byoID})
+				s.SubscriptionID().AnyTimes().Return("123")
+				g.Get(gomockinternal.AContext(), &PublicIPSpec{Name: "byo-ip", ResourceGroup: "shared-ips"}).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound})
+			},
+		},
+		{
+			name:          "existing public IP not found by DNS label",
+			expectedError: "no public IP with DNS label my-api found in eastus. Object will be requeued after 1m0s",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, e *mock_publicips.MockExistingPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, l *mock_publicips.MocklisterMockRecorder) {
+				e.ExistingAPIServerPublicIP().Return(&infrav1.ExistingPublicIP{DNSLabel: "my-api"})
+				s.Location().AnyTimes().Return("eastus")
+				l.ListAll(gomockinternal.AContext()).Return([]armnetwork.PublicIPAddress{byoIP}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_publicips.NewMockPublicIPScope(mockCtrl)
+			existingScopeMock := mock_publicips.NewMockExistingPublicIPScope(mockCtrl)
+			getterMock := mock_async.NewMockGetter(mockCtrl)
+			listerMock := mock_publicips.NewMocklister(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), existingScopeMock.EXPECT(), getterMock.EXPECT(), listerMock.EXPECT())
+
+			s := &Service{
+				Scope:  &existingPublicIPScope{MockPublicIPScope: scopeMock, MockExistingPublicIPScope: existingScopeMock},
+				Getter: getterMock,
+				lister: listerMock,
+			}
+
+			err := s.reconcileExistingAPIServerPublicIP(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestDeletePublicIP(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 )
 
@@ -37,6 +38,7 @@ type PublicIPSpec struct {
 	Location         string
 	ExtendedLocation *infrav1.ExtendedLocationSpec
 	FailureDomains   []*string
+	ZoneRedundant    bool
 	AdditionalTags   infrav1.Tags
 	IPTags           []infrav1.IPTag
 }
@@ -59,13 +61,29 @@ func (s *PublicIPSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the public IP.
 func (s *PublicIPSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
 	if existing != nil {
-		if _, ok := existing.(armnetwork.PublicIPAddress); !ok {
+		existingIP, ok := existing.(armnetwork.PublicIPAddress)
+		if !ok {
 			return nil, errors.Errorf("%T is not an armnetwork.PublicIPAddress", existing)
 		}
+		// A Basic public IP left unassociated by the migration of its load balancer to the Standard SKU is upgraded in
+		// place, which keeps its address. Azure only allows it for static public IPs.
+		if existingIP.SKU != nil && ptr.Deref(existingIP.SKU.Name, "") == armnetwork.PublicIPAddressSKUNameBasic &&
+			existingIP.Properties != nil && existingIP.Properties.IPConfiguration == nil &&
+			ptr.Deref(existingIP.Properties.PublicIPAllocationMethod, "") == armnetwork.IPAllocationMethodStatic {
+			existingIP.SKU = &armnetwork.PublicIPAddressSKU{
+				Name: ptr.To(armnetwork.PublicIPAddressSKUNameStandard),
+				Tier: ptr.To(armnetwork.PublicIPAddressSKUTierRegional),
+			}
+			return existingIP, nil
+		}
 		// public IP already exists
 		return nil, nil
 	}
 
+	if s.ZoneRedundant && len(s.FailureDomains) == 0 {
+		return nil, azure.WithTerminalError(errors.Errorf("public IP %s must be zone-redundant but location %s has no availability zones", s.Name, s.Location))
+	}
+
 	addressVersion := armnetwork.IPVersionIPv4
 	if s.IsIPv6 {
 		addressVersion = armnetwork.IPVersionIPv6
@@ -122,6 +122,44 @@ func TestParameters(t *testing.T) {
 			expected:      nil,
 			expectedError: "",
 		},
+		{
+			name: "upgrade unassociated Basic public IP",
+			existing: armnetwork.PublicIPAddress{
+				Name: ptr.To("my-publicip"),
+				SKU:  &armnetwork.PublicIPAddressSKU{Name: ptr.To(armnetwork.PublicIPAddressSKUNameBasic)},
+				Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+					IPAddress:                ptr.To("20.1.2.3"),
+					PublicIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodStatic),
+				},
+			},
+			spec: fakePublicIPSpecWithDNS,
+			expected: armnetwork.PublicIPAddress{
+				Name: ptr.To("my-publicip"),
+				SKU: &armnetwork.PublicIPAddressSKU{
+					Name: ptr.To(armnetwork.PublicIPAddressSKUNameStandard),
+					Tier: ptr.To(armnetwork.PublicIPAddressSKUTierRegional),
+				},
+				Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+					IPAddress:                ptr.To("20.1.2.3"),
+					PublicIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodStatic),
+				},
+			},
+			expectedError: "",
+		},
+		{
+			name: "noop if Basic public IP is associated",
+			existing: armnetwork.PublicIPAddress{
+				Name: ptr.To("my-publicip"),
+				SKU:  &armnetwork.PublicIPAddressSKU{Name: ptr.To(armnetwork.PublicIPAddressSKUNameBasic)},
+				Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+					PublicIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodStatic),
+					IPConfiguration:          &armnetwork.IPConfiguration{ID: ptr.To("my-lb-frontend")},
+				},
+			},
+			spec:          fakePublicIPSpecWithDNS,
+			expected:      nil,
+			expectedError: "",
+		},
 		{
 			name:          "public ipv4 address with dns",
 			existing:      nil,
@@ -143,6 +181,19 @@ func TestParameters(t *testing.T) {
 			expected:      fakePublicIPIpv6,
 			expectedError: "",
 		},
+		{
+			name:     "zone-redundant public ip in a location without availability zones",
+			existing: nil,
+			spec: PublicIPSpec{
+				Name:          "my-publicip",
+				ResourceGroup: "my-rg",
+				ClusterName:   "my-cluster",
+				Location:      "westus",
+				ZoneRedundant: true,
+			},
+			expected:      nil,
+			expectedError: "reconcile error that cannot be recovered occurred: public IP my-publicip must be zone-redundant but location westus has no availability zones. Object will not be requeued",
+		},
 	}
 
 	for _, tc := range testCases {
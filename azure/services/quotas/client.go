@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client lists the usage of compute resources against their quotas.
+type Client interface {
+	ListUsages(ctx context.Context, location string) ([]armcompute.Usage, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	usages *armcompute.UsageClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new usages client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create usages client options")
+	}
+	factory, err := armcompute.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armcompute client factory")
+	}
+	return &AzureClient{factory.NewUsageClient()}, nil
+}
+
+// ListUsages returns the usage of every compute quota of the subscription in a location.
+func (ac *AzureClient) ListUsages(ctx context.Context, location string) ([]armcompute.Usage, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "quotas.AzureClient.ListUsages")
+	defer done()
+
+	pager := ac.usages.NewListPager(location, nil)
+	usages, err := azure.CollectPages(ctx, pager, func(page armcompute.UsageClientListResponse) []*armcompute.Usage {
+		return page.Value
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list compute usages in %s", location)
+	}
+	return usages, nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/fakearm"
+)
+
+func TestListUsages(t *testing.T) {
+	g := NewWithT(t)
+
+	server := fakearm.NewServer()
+	defer server.Close()
+	server.Register()
+
+	client, err := NewClient(server.Authorizer("123"))
+	g.Expect(err).NotTo(HaveOccurred())
+	id := "/subscriptions/123/providers/Microsoft.Compute/locations/westus/usages"
+
+	server.InjectError(http.MethodGet, id, http.StatusForbidden, "AuthorizationFailed")
+	_, err = client.ListUsages(context.Background(), "westus")
+	g.Expect(err).To(MatchError(ContainSubstring("AuthorizationFailed")))
+
+	g.Expect(server.Put(id, map[string]interface{}{
+		"value": []interface{}{
+			map[string]interface{}{
+				"name":         map[string]interface{}{"value": "cores", "localizedValue": "Total Regional vCPUs"},
+				"currentValue": 4,
+				"limit":        100,
+				"unit":         "Count",
+			},
+		},
+	})).To(Succeed())
+	usages, err := client.ListUsages(context.Background(), "westus")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(usages).To(HaveLen(1))
+	g.Expect(ptr.Deref(usages[0].Name.Value, "")).To(Equal(TotalRegionalVCPUs))
+	g.Expect(ptr.Deref(usages[0].CurrentValue, 0)).To(BeEquivalentTo(4))
+	g.Expect(ptr.Deref(usages[0].Limit, 0)).To(BeEquivalentTo(100))
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+)
+
+const (
+	// TotalRegionalVCPUs is the name of the quota on the total number of vCPUs of regular VMs in a location.
+	TotalRegionalVCPUs = "cores"
+	// SpotVCPUs is the name of the quota on the total number of vCPUs of Spot VMs in a location.
+	SpotVCPUs = "lowPriorityCores"
+)
+
+// CheckVCPUs returns a description of each vCPU quota of a location that creating count VMs of a size would exceed.
+// The size is checked against the quota of its VM family and the total regional quota, or against the Spot quota for
+// Spot VMs. Nothing is checked when the vCPUs or the family of the size are unknown.
+func CheckVCPUs(ctx context.Context, client Client, location string, sku resourceskus.SKU, count int64, spot bool) ([]string, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	vCPUs, ok := skuVCPUs(sku)
+	if !ok {
+		return nil, nil
+	}
+	names := []string{TotalRegionalVCPUs, ptr.Deref(sku.Family, "")}
+	if spot {
+		names = []string{SpotVCPUs}
+	}
+	if names[len(names)-1] == "" {
+		return nil, nil
+	}
+
+	usages, err := client.ListUsages(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	required := vCPUs * count
+	var exceeded []string
+	for _, usage := range usages {
+		if usage.Name == nil || usage.Limit == nil || usage.CurrentValue == nil {
+			continue
+		}
+		name := ptr.Deref(usage.Name.Value, "")
+		if !containsFold(names, name) {
+			continue
+		}
+		limit, current := *usage.Limit, int64(*usage.CurrentValue)
+		if current+required <= limit {
+			continue
+		}
+		available := limit - current
+		if available < 0 {
+			available = 0
+		}
+		exceeded = append(exceeded, fmt.Sprintf("%d vCPUs of the %s quota are required in %s but only %d of the %d allowed are available",
+			required, name, location, available, limit))
+	}
+	return exceeded, nil
+}
+
+// skuVCPUs returns the number of vCPUs of a VM size.
+func skuVCPUs(sku resourceskus.SKU) (int64, bool) {
+	value, ok := sku.GetCapability(resourceskus.VCPUs)
+	if !ok {
+		return 0, false
+	}
+	vCPUs, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return vCPUs, true
+}
+
+// containsFold returns true if names contains name, ignoring case.
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/quotas/mock_quotas"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeSKU = resourceskus.SKU{
+		Name:   ptr.To("Standard_D4s_v3"),
+		Family: ptr.To("standardDSv3Family"),
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
+			{
+				Name:  ptr.To(resourceskus.VCPUs),
+				Value: ptr.To("4"),
+			},
+		},
+	}
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func fakeUsage(name string, current int32, limit int64) armcompute.Usage {
+	return armcompute.Usage{
+		Name:         &armcompute.UsageName{Value: ptr.To(name)},
+		CurrentValue: ptr.To(current),
+		Limit:        ptr.To(limit),
+		Unit:         ptr.To("Count"),
+	}
+}
+
+func TestCheckVCPUs(t *testing.T) {
+	testcases := []struct {
+		name             string
+		sku              resourceskus.SKU
+		count            int64
+		spot             bool
+		expectedExceeded []string
+		expectedError    string
+		expect           func(c *mock_quotas.MockClientMockRecorder)
+	}{
+		{
+			name:   "noop when no VM is created",
+			sku:    fakeSKU,
+			expect: func(c *mock_quotas.MockClientMockRecorder) {},
+		},
+		{
+			name:   "noop when the vCPUs of the size are unknown",
+			sku:    resourceskus.SKU{Name: ptr.To("Standard_D4s_v3"), Family: ptr.To("standardDSv3Family")},
+			count:  1,
+			expect: func(c *mock_quotas.MockClientMockRecorder) {},
+		},
+		{
+			name:  "quotas are not exceeded",
+			sku:   fakeSKU,
+			count: 2,
+			expect: func(c *mock_quotas.MockClientMockRecorder) {
+				c.ListUsages(gomockinternal.AContext(), "westus").Return([]armcompute.Usage{
+					fakeUsage("cores", 10, 100),
+					fakeUsage("standardDSv3Family", 2, 10),
+					fakeUsage("lowPriorityCores", 0, 0),
+				}, nil)
+			},
+		},
+		{
+			name:  "family and regional quotas are exceeded",
+			sku:   fakeSKU,
+			count: 3,
+			expectedExceeded: []string{
+				"12 vCPUs of the cores quota are required in westus but only 2 of the 100 allowed are available",
+				"12 vCPUs of the standardDSv3Family quota are required in westus but only 8 of the 10 allowed are available",
+			},
+			expect: func(c *mock_quotas.MockClientMockRecorder) {
+				c.ListUsages(gomockinternal.AContext(), "westus").Return([]armcompute.Usage{
+					fakeUsage("cores", 98, 100),
+					fakeUsage("standardDSv3Family", 2, 10),
+					fakeUsage("standardDSv4Family", 0, 0),
+				}, nil)
+			},
+		},
+		{
+			name:  "spot quota is exceeded",
+			sku:   fakeSKU,
+			count: 1,
+			spot:  true,
+			expectedExceeded: []string{
+				"4 vCPUs of the lowPriorityCores quota are required in westus but only 0 of the 0 allowed are available",
+			},
+			expect: func(c *mock_quotas.MockClientMockRecorder) {
+				c.ListUsages(gomockinternal.AContext(), "westus").Return([]armcompute.Usage{
+					fakeUsage("cores", 0, 100),
+					fakeUsage("standardDSv3Family", 0, 10),
+					fakeUsage("lowPriorityCores", 0, 0),
+				}, nil)
+			},
+		},
+		{
+			name:          "listing usages fails",
+			sku:           fakeSKU,
+			count:         1,
+			expectedError: "#: Internal Server Error: StatusCode=500",
+			expect: func(c *mock_quotas.MockClientMockRecorder) {
+				c.ListUsages(gomockinternal.AContext(), "westus").Return(nil, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			clientMock := mock_quotas.NewMockClient(mockCtrl)
+			tc.expect(clientMock.EXPECT())
+
+			exceeded, err := CheckVCPUs(context.TODO(), clientMock, "westus", tc.sku, tc.count, tc.spot)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(exceeded).To(Equal(tc.expectedExceeded))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package remediations restarts and redeploys the VMs of unhealthy machines.
+package remediations
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	Restart(ctx context.Context, resourceGroupName, vmName string) error
+	Redeploy(ctx context.Context, resourceGroupName, vmName string) error
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	virtualmachines *armcompute.VirtualMachinesClient
+}
+
+var _ Client = (*AzureClient)(nil)
+
+// NewClient creates a new remediations client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create remediations client options")
+	}
+	factory, err := armcompute.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armcompute client factory")
+	}
+	return &AzureClient{factory.NewVirtualMachinesClient()}, nil
+}
+
+// Restart starts the restart of a virtual machine. It does not wait for the restart to complete, the remediation
+// waits for the node of the machine to become healthy instead.
+func (ac *AzureClient) Restart(ctx context.Context, resourceGroupName, vmName string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "remediations.AzureClient.Restart")
+	defer done()
+
+	_, err := ac.virtualmachines.BeginRestart(ctx, resourceGroupName, vmName, nil)
+	return err
+}
+
+// Redeploy starts the redeployment of a virtual machine to a new Azure host. It does not wait for the redeployment to
+// complete, the remediation waits for the node of the machine to become healthy instead.
+func (ac *AzureClient) Redeploy(ctx context.Context, resourceGroupName, vmName string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "remediations.AzureClient.Redeploy")
+	defer done()
+
+	_, err := ac.virtualmachines.BeginRedeploy(ctx, resourceGroupName, vmName, nil)
+	return err
+}
@@ -0,0 +1,83 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+//
+// Generated by this command:
+//
+//	mockgen -destination client_mock.go -package mock_remediations -source ../client.go Client
+//
+// Package mock_remediations is a generated GoMock package.
+package mock_remediations
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// Redeploy mocks base method.
+func (m *MockClient) Redeploy(ctx context.Context, resourceGroupName string, vmName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeploy", ctx, resourceGroupName, vmName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Redeploy indicates an expected call of Redeploy.
+func (mr *MockClientMockRecorder) Redeploy(ctx, resourceGroupName, vmName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeploy", reflect.TypeOf((*MockClient)(nil).Redeploy), ctx, resourceGroupName, vmName)
+}
+
+// Restart mocks base method.
+func (m *MockClient) Restart(ctx context.Context, resourceGroupName string, vmName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restart", ctx, resourceGroupName, vmName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restart indicates an expected call of Restart.
+func (mr *MockClientMockRecorder) Restart(ctx, resourceGroupName, vmName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restart", reflect.TypeOf((*MockClient)(nil).Restart), ctx, resourceGroupName, vmName)
+}
@@ -138,7 +138,8 @@ func (c *Cache) Get(ctx context.Context, name string, kind ResourceType) (SKU, e
 	}
 
 	for _, sku := range c.data {
-		if sku.Name != nil && *sku.Name == name {
+		// Disks and snapshots share some SKU names, so the resource type is matched too when it is known.
+		if sku.Name != nil && *sku.Name == name && (sku.ResourceType == nil || *sku.ResourceType == string(kind)) {
 			return SKU(sku), nil
 		}
 	}
@@ -190,11 +191,17 @@ func (c *Cache) GetZones(ctx context.Context, location string) ([]string, error)
 
 				if sku.Restrictions != nil {
 					for _, restriction := range sku.Restrictions {
+						if !restrictsLocation(restriction, location) {
+							continue
+						}
 						// Can't deploy anything in this subscription in this location. Bail out.
 						if ptr.Deref(restriction.Type, "") == armcompute.ResourceSKURestrictionsTypeLocation {
 							availableZones = nil
 							break
 						}
+						if restriction.RestrictionInfo == nil {
+							continue
+						}
 
 						// remove restricted zones
 						for _, restrictedZone := range restriction.RestrictionInfo.Zones {
@@ -252,11 +259,17 @@ func (c *Cache) GetZonesWithVMSize(ctx context.Context, size, location string) (
 
 				if sku.Restrictions != nil {
 					for _, restriction := range sku.Restrictions {
+						if !restrictsLocation(restriction, location) {
+							continue
+						}
 						// Can't deploy anything in this subscription in this location. Bail out.
 						if ptr.Deref(restriction.Type, "") == armcompute.ResourceSKURestrictionsTypeLocation {
 							availableZones = nil
 							break
 						}
+						if restriction.RestrictionInfo == nil {
+							continue
+						}
 
 						// remove restricted zones
 						for _, restrictedZone := range restriction.RestrictionInfo.Zones {
@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceskus
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Catalog lists the VM sizes, zones and disk types a subscription can deploy in a location, e.g. for UIs to offer
+// only the valid choices.
+type Catalog struct {
+	// Location is the location of the catalog.
+	Location string `json:"location"`
+	// Zones are the availability zones of the location some VM size can be deployed in.
+	Zones []string `json:"zones"`
+	// VMSizes are the VM sizes which can be deployed in the location and meet the requirements of machines.
+	VMSizes []VMSize `json:"vmSizes"`
+	// DiskTypes are the storage account types of the managed disks which can be deployed in the location.
+	DiskTypes []DiskType `json:"diskTypes"`
+}
+
+// VMSize describes a VM size of a Catalog.
+type VMSize struct {
+	Name                  string   `json:"name"`
+	Family                string   `json:"family,omitempty"`
+	VCPUs                 int64    `json:"vCPUs"`
+	MemoryGB              float64  `json:"memoryGB"`
+	Zones                 []string `json:"zones"`
+	AcceleratedNetworking bool     `json:"acceleratedNetworking"`
+	EphemeralOSDisk       bool     `json:"ephemeralOSDisk"`
+	EncryptionAtHost      bool     `json:"encryptionAtHost"`
+}
+
+// DiskType describes a managed disk storage account type of a Catalog.
+type DiskType struct {
+	Name  string   `json:"name"`
+	Zones []string `json:"zones"`
+}
+
+// Catalog returns the VM sizes, zones and disk types available in the location of the cache. The VM sizes and disk
+// types the subscription is restricted from deploying in the location are left out, and so are the VM sizes with less
+// vCPUs or memory than machines require.
+func (c *Cache) Catalog(ctx context.Context) (Catalog, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "resourceskus.Cache.Catalog")
+	defer done()
+
+	catalog := Catalog{Location: c.location}
+	vmZones := map[string]bool{}
+	diskTypes := map[string]map[string]bool{}
+	err := c.Map(ctx, func(sku SKU) {
+		if sku.Name == nil {
+			return
+		}
+		zones, ok := sku.availableZones(c.location)
+		if !ok {
+			return
+		}
+		switch {
+		case strings.EqualFold(ptr.Deref(sku.ResourceType, ""), string(VirtualMachines)):
+			size, ok := sku.vmSize(zones)
+			if !ok {
+				return
+			}
+			for _, zone := range zones {
+				vmZones[zone] = true
+			}
+			catalog.VMSizes = append(catalog.VMSizes, size)
+		case strings.EqualFold(ptr.Deref(sku.ResourceType, ""), string(Disks)):
+			// Disk SKUs are listed once per disk size, the zones of a storage account type are those of all its sizes.
+			if diskTypes[*sku.Name] == nil {
+				diskTypes[*sku.Name] = map[string]bool{}
+			}
+			for _, zone := range zones {
+				diskTypes[*sku.Name][zone] = true
+			}
+		}
+	})
+	if err != nil {
+		return Catalog{}, err
+	}
+
+	catalog.Zones = sortedKeys(vmZones)
+	sort.Slice(catalog.VMSizes, func(i, j int) bool { return catalog.VMSizes[i].Name < catalog.VMSizes[j].Name })
+	for name, zones := range diskTypes {
+		catalog.DiskTypes = append(catalog.DiskTypes, DiskType{Name: name, Zones: sortedKeys(zones)})
+	}
+	sort.Slice(catalog.DiskTypes, func(i, j int) bool { return catalog.DiskTypes[i].Name < catalog.DiskTypes[j].Name })
+	return catalog, nil
+}
+
+// availableZones returns the zones of location the SKU can be deployed in, and false if it can't be deployed in the
+// location at all.
+func (s SKU) availableZones(location string) ([]string, bool) {
+	if s.IsLocationRestricted(location) {
+		return nil, false
+	}
+	zones := map[string]bool{}
+	for _, locationInfo := range s.LocationInfo {
+		if !strings.EqualFold(ptr.Deref(locationInfo.Location, ""), location) {
+			continue
+		}
+		for _, zone := range locationInfo.Zones {
+			zones[ptr.Deref(zone, "")] = true
+		}
+	}
+	for _, restriction := range s.Restrictions {
+		if restriction == nil || restriction.RestrictionInfo == nil || !restrictsLocation(restriction, location) {
+			continue
+		}
+		for _, zone := range restriction.RestrictionInfo.Zones {
+			delete(zones, ptr.Deref(zone, ""))
+		}
+	}
+	return sortedKeys(zones), true
+}
+
+// vmSize returns the VMSize of a VM SKU available in zones, and false if it has less vCPUs or memory than machines
+// require.
+func (s SKU) vmSize(zones []string) (VMSize, bool) {
+	vCPUs, _ := s.GetCapability(VCPUs)
+	memory, _ := s.GetCapability(MemoryGB)
+	size := VMSize{
+		Name:                  *s.Name,
+		Family:                ptr.Deref(s.Family, ""),
+		Zones:                 zones,
+		AcceleratedNetworking: s.HasCapability(AcceleratedNetworking),
+		EphemeralOSDisk:       s.HasCapability(EphemeralOSDisk),
+		EncryptionAtHost:      s.HasCapability(EncryptionAtHost),
+	}
+	var err error
+	if size.VCPUs, err = strconv.ParseInt(vCPUs, 10, 64); err != nil || size.VCPUs < MinimumVCPUS {
+		return VMSize{}, false
+	}
+	if size.MemoryGB, err = strconv.ParseFloat(memory, 64); err != nil || size.MemoryGB < MinimumMemory {
+		return VMSize{}, false
+	}
+	return size, true
+}
+
+// sortedKeys returns the keys of set in lexical order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
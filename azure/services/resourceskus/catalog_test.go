@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceskus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+)
+
+func TestCacheCatalog(t *testing.T) {
+	capability := func(name, value string) *armcompute.ResourceSKUCapabilities {
+		return &armcompute.ResourceSKUCapabilities{Name: ptr.To(name), Value: ptr.To(value)}
+	}
+	locationInfo := func(zones ...string) []*armcompute.ResourceSKULocationInfo {
+		info := &armcompute.ResourceSKULocationInfo{Location: ptr.To("eastus")}
+		for _, zone := range zones {
+			info.Zones = append(info.Zones, ptr.To(zone))
+		}
+		return []*armcompute.ResourceSKULocationInfo{info}
+	}
+	data := []armcompute.ResourceSKU{
+		{
+			Name:         ptr.To("Standard_D4s_v3"),
+			ResourceType: ptr.To(string(VirtualMachines)),
+			Family:       ptr.To("standardDSv3Family"),
+			LocationInfo: locationInfo("1", "2", "3"),
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
+				capability(VCPUs, "4"),
+				capability(MemoryGB, "16"),
+				capability(AcceleratedNetworking, string(CapabilitySupported)),
+				capability(EphemeralOSDisk, string(CapabilitySupported)),
+			},
+			Restrictions: []*armcompute.ResourceSKURestrictions{
+				{
+					Type:            ptr.To(armcompute.ResourceSKURestrictionsTypeZone),
+					RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{Zones: []*string{ptr.To("3")}},
+				},
+			},
+		},
+		{
+			Name:         ptr.To("Standard_D2s_v3"),
+			ResourceType: ptr.To(string(VirtualMachines)),
+			LocationInfo: locationInfo("1"),
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
+				capability(VCPUs, "2"),
+				capability(MemoryGB, "8"),
+				capability(EncryptionAtHost, string(CapabilitySupported)),
+			},
+		},
+		{
+			Name:         ptr.To("Standard_A1_v2"),
+			ResourceType: ptr.To(string(VirtualMachines)),
+			LocationInfo: locationInfo("4"),
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
+				capability(VCPUs, "1"),
+				capability(MemoryGB, "2"),
+			},
+		},
+		{
+			Name:         ptr.To("Standard_E2s_v3"),
+			ResourceType: ptr.To(string(VirtualMachines)),
+			LocationInfo: locationInfo("4"),
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
+				capability(VCPUs, "2"),
+				capability(MemoryGB, "16"),
+			},
+			Restrictions: []*armcompute.ResourceSKURestrictions{
+				{Type: ptr.To(armcompute.ResourceSKURestrictionsTypeLocation)},
+			},
+		},
+		{
+			Name:         ptr.To("Standard_F2s_v2"),
+			ResourceType: ptr.To(string(VirtualMachines)),
+			LocationInfo: locationInfo("2"),
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
+				capability(VCPUs, "2"),
+				capability(MemoryGB, "4"),
+			},
+			Restrictions: []*armcompute.ResourceSKURestrictions{
+				{
+					Type:            ptr.To(armcompute.ResourceSKURestrictionsTypeLocation),
+					RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{Locations: []*string{ptr.To("westus")}},
+				},
+			},
+		},
+		{
+			Name:         ptr.To("Premium_LRS"),
+			ResourceType: ptr.To(string(Disks)),
+			LocationInfo: locationInfo("1"),
+		},
+		{
+			Name:         ptr.To("Premium_LRS"),
+			ResourceType: ptr.To(string(Disks)),
+			LocationInfo: locationInfo("2"),
+		},
+		{
+			Name:         ptr.To("Standard_LRS"),
+			ResourceType: ptr.To(string(Disks)),
+			LocationInfo: locationInfo(),
+		},
+	}
+
+	catalog, err := NewStaticCache(data, "eastus").Catalog(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Catalog{
+		Location: "eastus",
+		Zones:    []string{"1", "2"},
+		VMSizes: []VMSize{
+			{
+				Name:             "Standard_D2s_v3",
+				VCPUs:            2,
+				MemoryGB:         8,
+				Zones:            []string{"1"},
+				EncryptionAtHost: true,
+			},
+			{
+				Name:                  "Standard_D4s_v3",
+				Family:                "standardDSv3Family",
+				VCPUs:                 4,
+				MemoryGB:              16,
+				Zones:                 []string{"1", "2"},
+				AcceleratedNetworking: true,
+				EphemeralOSDisk:       true,
+			},
+			{
+				Name:     "Standard_F2s_v2",
+				VCPUs:    2,
+				MemoryGB: 4,
+				Zones:    []string{"2"},
+			},
+		},
+		DiskTypes: []DiskType{
+			{Name: "Premium_LRS", Zones: []string{"1", "2"}},
+			{Name: "Standard_LRS", Zones: []string{}},
+		},
+	}
+	if diff := cmp.Diff(want, catalog); diff != "" {
+		t.Errorf("unexpected catalog (-want +got):\n%s", diff)
+	}
+}
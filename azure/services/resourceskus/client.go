@@ -55,17 +55,13 @@ func (ac *AzureClient) List(ctx context.Context, filter string) ([]armcompute.Re
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "resourceskus.AzureClient.List")
 	defer done()
 
-	var skus []armcompute.ResourceSKU
 	opts := armcompute.ResourceSKUsClientListOptions{Filter: &filter}
 	pager := ac.skus.NewListPager(&opts)
-	for pager.More() {
-		resp, err := pager.NextPage(ctx)
-		if err != nil {
-			return skus, errors.Wrap(err, "could not iterate resource skus")
-		}
-		for _, sku := range resp.Value {
-			skus = append(skus, *sku)
-		}
+	skus, err := azure.CollectPages(ctx, pager, func(page armcompute.ResourceSKUsClientListResponse) []*armcompute.ResourceSKU {
+		return page.Value
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not iterate resource skus")
 	}
 
 	return skus, nil
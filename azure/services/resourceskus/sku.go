@@ -75,6 +75,8 @@ const (
 	ConfidentialComputingType = "ConfidentialComputingType"
 	// CPUArchitectureType identifies the capability for cpu architecture.
 	CPUArchitectureType = "CpuArchitectureType"
+	// MaxWriteAcceleratorDisksAllowed identifies the capability for the maximum number of disks with write accelerator.
+	MaxWriteAcceleratorDisksAllowed = "MaxWriteAcceleratorDisksAllowed"
 )
 
 // HasCapability return true for a capability which can be either
@@ -138,6 +140,80 @@ func (s SKU) GetCapability(name string) (string, bool) {
 	return "", false
 }
 
+// IsRegionalDiskType returns true for the storage account types of the managed disks which are only offered in some
+// regions, i.e. Premium SSD v2 and zone-redundant disks, and whose availability must be checked against the disk SKUs
+// of the location.
+func IsRegionalDiskType(storageAccountType string) bool {
+	switch armcompute.StorageAccountTypes(storageAccountType) {
+	case armcompute.StorageAccountTypesPremiumV2LRS, armcompute.StorageAccountTypesPremiumZRS, armcompute.StorageAccountTypesStandardSSDZRS:
+		return true
+	}
+	return false
+}
+
+// IsZoneAvailable returns false if the SKU is known not to be offered in the given zone of a location,
+// either because the zone is not listed for the location or because it is restricted for the subscription.
+// It returns true when the SKU carries no information about the location.
+func (s SKU) IsZoneAvailable(location, zone string) bool {
+	for _, info := range s.LocationInfo {
+		if info == nil || !strings.EqualFold(ptr.Deref(info.Location, ""), location) {
+			continue
+		}
+
+		for _, restriction := range s.Restrictions {
+			if !restrictsLocation(restriction, location) {
+				continue
+			}
+			if ptr.Deref(restriction.Type, "") == armcompute.ResourceSKURestrictionsTypeLocation {
+				return false
+			}
+			if restriction.RestrictionInfo == nil {
+				continue
+			}
+			for _, restrictedZone := range restriction.RestrictionInfo.Zones {
+				if ptr.Deref(restrictedZone, "") == zone {
+					return false
+				}
+			}
+		}
+
+		for _, availableZone := range info.Zones {
+			if ptr.Deref(availableZone, "") == zone {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// IsLocationRestricted returns true if the subscription is restricted from deploying the SKU in location.
+func (s SKU) IsLocationRestricted(location string) bool {
+	for _, restriction := range s.Restrictions {
+		if restrictsLocation(restriction, location) && ptr.Deref(restriction.Type, "") == armcompute.ResourceSKURestrictionsTypeLocation {
+			return true
+		}
+	}
+	return false
+}
+
+// restrictsLocation returns true if a restriction of a SKU applies to location, i.e. if location is one of the
+// locations of the restriction. A restriction without locations applies to every location.
+func restrictsLocation(restriction *armcompute.ResourceSKURestrictions, location string) bool {
+	if restriction == nil {
+		return false
+	}
+	if restriction.RestrictionInfo == nil || len(restriction.RestrictionInfo.Locations) == 0 {
+		return true
+	}
+	for _, restrictedLocation := range restriction.RestrictionInfo.Locations {
+		if strings.EqualFold(ptr.Deref(restrictedLocation, ""), location) {
+			return true
+		}
+	}
+	return false
+}
+
 // HasLocationCapability returns true if the provided resource supports the location capability.
 func (s SKU) HasLocationCapability(capabilityName, location, zone string) bool {
 	if s.LocationInfo == nil {
@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceskus
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestSKUIsZoneAvailable(t *testing.T) {
+	zonalLocationInfo := []*armcompute.ResourceSKULocationInfo{
+		{
+			Location: ptr.To("eastus"),
+			Zones:    []*string{ptr.To("1"), ptr.To("2")},
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		sku      SKU
+		location string
+		zone     string
+		want     bool
+	}{
+		{
+			name:     "no location info is treated as available",
+			sku:      SKU{},
+			location: "eastus",
+			zone:     "3",
+			want:     true,
+		},
+		{
+			name:     "location info for other locations is treated as available",
+			sku:      SKU{LocationInfo: zonalLocationInfo},
+			location: "westus",
+			zone:     "3",
+			want:     true,
+		},
+		{
+			name:     "zone listed for the location",
+			sku:      SKU{LocationInfo: zonalLocationInfo},
+			location: "EastUS",
+			zone:     "2",
+			want:     true,
+		},
+		{
+			name:     "zone not listed for the location",
+			sku:      SKU{LocationInfo: zonalLocationInfo},
+			location: "eastus",
+			zone:     "3",
+			want:     false,
+		},
+		{
+			name: "zone restricted for the subscription",
+			sku: SKU{
+				LocationInfo: zonalLocationInfo,
+				Restrictions: []*armcompute.ResourceSKURestrictions{
+					{
+						Type:            ptr.To(armcompute.ResourceSKURestrictionsTypeZone),
+						RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{Zones: []*string{ptr.To("2")}},
+					},
+				},
+			},
+			location: "eastus",
+			zone:     "2",
+			want:     false,
+		},
+		{
+			name: "location restricted for the subscription",
+			sku: SKU{
+				LocationInfo: zonalLocationInfo,
+				Restrictions: []*armcompute.ResourceSKURestrictions{
+					{
+						Type:            ptr.To(armcompute.ResourceSKURestrictionsTypeLocation),
+						RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{Locations: []*string{ptr.To("eastus")}},
+					},
+				},
+			},
+			location: "eastus",
+			zone:     "1",
+			want:     false,
+		},
+		{
+			name: "other location restricted for the subscription",
+			sku: SKU{
+				LocationInfo: zonalLocationInfo,
+				Restrictions: []*armcompute.ResourceSKURestrictions{
+					{
+						Type:            ptr.To(armcompute.ResourceSKURestrictionsTypeLocation),
+						RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{Locations: []*string{ptr.To("westus")}},
+					},
+				},
+			},
+			location: "eastus",
+			zone:     "1",
+			want:     true,
+		},
+		{
+			name: "zone restricted for the subscription in another location",
+			sku: SKU{
+				LocationInfo: zonalLocationInfo,
+				Restrictions: []*armcompute.ResourceSKURestrictions{
+					{
+						Type: ptr.To(armcompute.ResourceSKURestrictionsTypeZone),
+						RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{
+							Locations: []*string{ptr.To("westus")},
+							Zones:     []*string{ptr.To("2")},
+						},
+					},
+				},
+			},
+			location: "eastus",
+			zone:     "2",
+			want:     true,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			g.Expect(tc.sku.IsZoneAvailable(tc.location, tc.zone)).To(Equal(tc.want))
+		})
+	}
+}
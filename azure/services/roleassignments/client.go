@@ -26,13 +26,13 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
-// azureClient contains the Azure go-sdk Client.
-type azureClient struct {
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
 	roleassignments armauthorization.RoleAssignmentsClient
 }
 
-// newClient creates a new role assignments client from an authorizer.
-func newClient(auth azure.Authorizer) (*azureClient, error) {
+// NewClient creates a new role assignments client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
 	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create roleassignments client options")
@@ -41,15 +41,15 @@ func newClient(auth azure.Authorizer) (*azureClient, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create armauthorization client factory")
 	}
-	return &azureClient{*factory.NewRoleAssignmentsClient()}, nil
+	return &AzureClient{*factory.NewRoleAssignmentsClient()}, nil
 }
 
 // Get gets the specified role assignment.
-func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (interface{}, error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.azureClient.Get")
+func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (interface{}, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.AzureClient.Get")
 	defer done()
 
-	resp, err := ac.roleassignments.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	resp, err := ac.roleassignments.Get(ctx, spec.OwnerResourceName(), spec.ResourceName(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -58,8 +58,8 @@ func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 
 // CreateOrUpdateAsync creates a roleassignment.
 // Creating a roleassignment is not a long running operation, so we don't ever return a poller.
-func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armauthorization.RoleAssignmentsClientCreateResponse], err error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.azureClient.CreateOrUpdateAsync")
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armauthorization.RoleAssignmentsClientCreateResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.AzureClient.CreateOrUpdateAsync")
 	defer done()
 
 	createParams, ok := parameters.(armauthorization.RoleAssignmentCreateParameters)
@@ -69,3 +69,13 @@ func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 	resp, err := ac.roleassignments.Create(ctx, spec.OwnerResourceName(), spec.ResourceName(), createParams, nil)
 	return resp.RoleAssignment, nil, err
 }
+
+// DeleteAsync deletes a roleassignment.
+// Deleting a roleassignment is not a long running operation, so we don't ever return a poller.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armauthorization.RoleAssignmentsClientDeleteResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.AzureClient.DeleteAsync")
+	defer done()
+
+	_, err = ac.roleassignments.Delete(ctx, spec.OwnerResourceName(), spec.ResourceName(), nil)
+	return nil, err
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
@@ -53,7 +54,7 @@ type Service struct {
 
 // New creates a new service.
 func New(scope RoleAssignmentScope) (*Service, error) {
-	client, err := newClient(scope)
+	client, err := NewClient(scope)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create roleassignments service")
 	}
@@ -70,7 +71,7 @@ func New(scope RoleAssignmentScope) (*Service, error) {
 		virtualMachinesGetter:        virtualMachinesClient,
 		virtualMachineScaleSetGetter: scaleSetsClient,
 		Reconciler: async.New[armauthorization.RoleAssignmentsClientCreateResponse,
-			armauthorization.RoleAssignmentsClientDeleteResponse](scope, client, nil),
+			armauthorization.RoleAssignmentsClientDeleteResponse](scope, client, client),
 	}, nil
 }
 
@@ -100,12 +101,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	case azure.VirtualMachine:
 		ID, err := s.getVMPrincipalID(ctx)
 		if err != nil {
+			s.Scope.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, err)
 			return errors.Wrap(err, "failed to assign role to system assigned identity")
 		}
 		principalID = ID
 	case azure.VirtualMachineScaleSet:
 		ID, err := s.getVMSSPrincipalID(ctx)
 		if err != nil {
+			s.Scope.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, err)
 			return errors.Wrap(err, "failed to assign role to system assigned identity")
 		}
 		principalID = ID
@@ -121,10 +124,12 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 		_, err := s.CreateOrUpdateResource(ctx, roleAssignmentSpec, serviceName)
 		if err != nil {
+			s.Scope.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, err)
 			return errors.Wrapf(err, "cannot assign role to %s system assigned identity", resourceType)
 		}
 	}
 
+	s.Scope.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil)
 	return nil
 }
 
@@ -171,10 +176,32 @@ func (s *Service) getVMSSPrincipalID(ctx context.Context) (*string, error) {
 	return resultVMSS.Identity.PrincipalID, nil
 }
 
-// Delete is a no-op as the role assignments get deleted as part of VM deletion.
+// Delete deletes the role assignments granted to the system-assigned identity.
+// Azure does not remove role assignments when the identity's VM or VMSS is deleted,
+// so they would otherwise be left behind pointing at a principal that no longer exists.
 func (s *Service) Delete(ctx context.Context) error {
-	_, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.Delete")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.Delete")
 	defer done()
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	if !s.Scope.HasSystemAssignedIdentity() {
+		log.V(2).Info("no role assignment spec to delete")
+		return nil
+	}
+
+	for _, roleAssignmentSpec := range s.Scope.RoleAssignmentSpecs(nil) {
+		if roleAssignmentSpec.ResourceName() == "" || roleAssignmentSpec.OwnerResourceName() == "" {
+			continue
+		}
+		log.V(2).Info("deleting role assignment", "role assignment", roleAssignmentSpec.ResourceName())
+		if err := s.DeleteResource(ctx, roleAssignmentSpec, serviceName); err != nil {
+			s.Scope.UpdateDeleteStatus(infrav1.RoleAssignmentReadyCondition, serviceName, err)
+			return errors.Wrapf(err, "failed to delete role assignment %s", roleAssignmentSpec.ResourceName())
+		}
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil)
 	return nil
 }
 
@@ -27,6 +27,7 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments/mock_roleassignments"
@@ -87,6 +88,7 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 					},
 				}, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRoleAssignment1, serviceName).Return(&fakeRoleAssignment1, nil)
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil)
 			},
 		},
 		{
@@ -101,6 +103,7 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 				s.HasSystemAssignedIdentity().Return(true)
 				s.RoleAssignmentResourceType().Return("VirtualMachine")
 				m.Get(gomockinternal.AContext(), &fakeVMSpec).Return(armcompute.VirtualMachine{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error"))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomockinternal.ErrStrEq("failed to get principal ID for VM: #: Internal Server Error: StatusCode=500"))
 			},
 		},
 		{
@@ -122,6 +125,7 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 				}, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRoleAssignment1, serviceName).Return(&RoleAssignmentSpec{},
 					autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error"))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomockinternal.ErrStrEq("#: Internal Server Error: StatusCode=500"))
 			},
 		},
 	}
@@ -180,6 +184,7 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 					},
 				}, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRoleAssignment2, serviceName).Return(&fakeRoleAssignment2, nil)
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil)
 			},
 		},
 		{
@@ -194,6 +199,7 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 				s.HasSystemAssignedIdentity().Return(true)
 				mvmss.Get(gomockinternal.AContext(), &fakeVMSSSpec).Return(armcompute.VirtualMachineScaleSet{},
 					autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error"))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomockinternal.ErrStrEq("failed to get principal ID for VMSS: #: Internal Server Error: StatusCode=500"))
 			},
 		},
 		{
@@ -214,6 +220,7 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 				}, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRoleAssignment2, serviceName).Return(&RoleAssignmentSpec{},
 					autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error"))
+				s.UpdatePutStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomockinternal.ErrStrEq("#: Internal Server Error: StatusCode=500"))
 			},
 		},
 	}
@@ -247,3 +254,84 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteRoleAssignments(t *testing.T) {
+	fakeRoleAssignment := RoleAssignmentSpec{
+		Name:          "fake-role-assignment",
+		MachineName:   "test-vm",
+		ResourceGroup: "my-rg",
+		ResourceType:  azure.VirtualMachine,
+		Scope:         "/subscriptions/12345/",
+	}
+
+	testcases := []struct {
+		name          string
+		expect        func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+		expectedError string
+	}{
+		{
+			name:          "noop if no system assigned identity",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasSystemAssignedIdentity().Return(false)
+			},
+		},
+		{
+			name:          "delete a role assignment",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasSystemAssignedIdentity().Return(true)
+				s.RoleAssignmentSpecs(nil).Return([]azure.ResourceSpecGetter{&fakeRoleAssignment})
+				r.DeleteResource(gomockinternal.AContext(), &fakeRoleAssignment, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "skip role assignments without a name or scope",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasSystemAssignedIdentity().Return(true)
+				s.RoleAssignmentSpecs(nil).Return([]azure.ResourceSpecGetter{&emptyRoleAssignmentSpec})
+				s.UpdateDeleteStatus(infrav1.RoleAssignmentReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "return error when deleting a role assignment",
+			expectedError: "failed to delete role assignment fake-role-assignment: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.HasSystemAssignedIdentity().Return(true)
+				s.RoleAssignmentSpecs(nil).Return([]azure.ResourceSpecGetter{&fakeRoleAssignment})
+				r.DeleteResource(gomockinternal.AContext(), &fakeRoleAssignment, serviceName).Return(
+					autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error"))
+				s.UpdateDeleteStatus(infrav1.RoleAssignmentReadyCondition, serviceName, gomockinternal.ErrStrEq("#: Internal Server Error: StatusCode=500"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -33,6 +33,9 @@ type RoleAssignmentSpec struct {
 	PrincipalID      *string
 	RoleDefinitionID string
 	Scope            string
+	// PrincipalType is the type of the principal, if known. Setting it allows assigning roles to identities that
+	// were just created, before they are replicated to all Microsoft Entra ID regions.
+	PrincipalType *armauthorization.PrincipalType
 }
 
 // ResourceName returns the name of the role assignment.
@@ -63,6 +66,7 @@ func (s *RoleAssignmentSpec) Parameters(ctx context.Context, existing interface{
 	return armauthorization.RoleAssignmentCreateParameters{
 		Properties: &armauthorization.RoleAssignmentProperties{
 			PrincipalID:      s.PrincipalID,
+			PrincipalType:    s.PrincipalType,
 			RoleDefinitionID: ptr.To(s.RoleDefinitionID),
 		},
 	}, nil
@@ -46,13 +46,14 @@ type AzureClient struct {
 
 var _ Client = &AzureClient{}
 
-// NewClient creates a new VMSS client from an authorizer.
-func NewClient(auth azure.Authorizer) (*AzureClient, error) {
-	scaleSetVMsClient, err := newVirtualMachineScaleSetVMsClient(auth)
+// NewClient creates a new VMSS client from an authorizer. Requests are sent along with tokens for the auxiliary
+// tenants, if any, e.g. to create scale sets from images in a gallery of another tenant.
+func NewClient(auth azure.Authorizer, auxiliaryTenants ...string) (*AzureClient, error) {
+	scaleSetVMsClient, err := newVirtualMachineScaleSetVMsClient(auth, auxiliaryTenants)
 	if err != nil {
 		return nil, err
 	}
-	scaleSetsClient, err := newVirtualMachineScaleSetsClient(auth)
+	scaleSetsClient, err := newVirtualMachineScaleSetsClient(auth, auxiliaryTenants)
 	if err != nil {
 		return nil, err
 	}
@@ -63,11 +64,12 @@ func NewClient(auth azure.Authorizer) (*AzureClient, error) {
 }
 
 // newVirtualMachineScaleSetVMsClient creates a vmss VM client from an authorizer.
-func newVirtualMachineScaleSetVMsClient(auth azure.Authorizer) (*armcompute.VirtualMachineScaleSetVMsClient, error) {
+func newVirtualMachineScaleSetVMsClient(auth azure.Authorizer, auxiliaryTenants []string) (*armcompute.VirtualMachineScaleSetVMsClient, error) {
 	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create scalesetvms client options")
 	}
+	opts.AuxiliaryTenants = auxiliaryTenants
 	factory, err := armcompute.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create armcompute client factory")
@@ -76,11 +78,12 @@ func newVirtualMachineScaleSetVMsClient(auth azure.Authorizer) (*armcompute.Virt
 }
 
 // newVirtualMachineScaleSetsClient creates a vmss client from an authorizer.
-func newVirtualMachineScaleSetsClient(auth azure.Authorizer) (*armcompute.VirtualMachineScaleSetsClient, error) {
+func newVirtualMachineScaleSetsClient(auth azure.Authorizer, auxiliaryTenants []string) (*armcompute.VirtualMachineScaleSetsClient, error) {
 	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create scalesets client options")
 	}
+	opts.AuxiliaryTenants = auxiliaryTenants
 	factory, err := armcompute.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create armcompute client factory")
@@ -93,16 +96,12 @@ func (ac *AzureClient) ListInstances(ctx context.Context, resourceGroupName stri
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.ListInstances")
 	defer done()
 
-	var instances []armcompute.VirtualMachineScaleSetVM
 	pager := ac.scalesetvms.NewListPager(resourceGroupName, resourceName, nil)
-	for pager.More() {
-		nextResult, err := pager.NextPage(ctx)
-		if err != nil {
-			return nil, errors.Wrap(err, "could not iterate scalesetvms")
-		}
-		for _, scaleSetVM := range nextResult.Value {
-			instances = append(instances, *scaleSetVM)
-		}
+	instances, err := azure.CollectPages(ctx, pager, func(page armcompute.VirtualMachineScaleSetVMsClientListResponse) []*armcompute.VirtualMachineScaleSetVM {
+		return page.Value
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not iterate scalesetvms")
 	}
 
 	return instances, nil
@@ -113,16 +112,12 @@ func (ac *AzureClient) List(ctx context.Context, resourceGroupName string) ([]ar
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.List")
 	defer done()
 
-	var scaleSets []armcompute.VirtualMachineScaleSet
 	pager := ac.scalesets.NewListPager(resourceGroupName, nil)
-	for pager.More() {
-		nextResult, err := pager.NextPage(ctx)
-		if err != nil {
-			return scaleSets, errors.Wrap(err, "could not iterate scalesets")
-		}
-		for _, scaleSet := range nextResult.Value {
-			scaleSets = append(scaleSets, *scaleSet)
-		}
+	scaleSets, err := azure.CollectPages(ctx, pager, func(page armcompute.VirtualMachineScaleSetsClientListResponse) []*armcompute.VirtualMachineScaleSet {
+		return page.Value
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not iterate scalesets")
 	}
 
 	return scaleSets, nil
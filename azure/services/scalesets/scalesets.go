@@ -19,14 +19,20 @@ package scalesets
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	azprovider "sigs.k8s.io/cloud-provider-azure/pkg/provider"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/features"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/marketplaceagreements"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/quotas"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
@@ -34,7 +40,21 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
-const serviceName = "scalesets"
+const (
+	serviceName = "scalesets"
+
+	// featureRegistrationRequeue is how long to wait before checking again whether a required subscription feature
+	// has been registered.
+	featureRegistrationRequeue = 5 * time.Minute
+
+	// quotaExceededRequeue is how long to wait before checking again whether there is enough vCPU quota to create the
+	// instances of a scale set.
+	quotaExceededRequeue = 5 * time.Minute
+
+	// marketplaceTermsRequeue is how long to wait before checking again whether the marketplace terms of the image
+	// have been accepted.
+	marketplaceTermsRequeue = 5 * time.Minute
+)
 
 type (
 	// ScaleSetScope defines the scope interface for a scale sets service.
@@ -47,6 +67,7 @@ type (
 		SetProviderID(string)
 		SetVMSSState(*azure.VMSS)
 		ReconcileReplicas(context.Context, *azure.VMSS) error
+		AuxiliaryTenantIDs() []string
 	}
 
 	// Service provides operations on Azure resources.
@@ -54,13 +75,28 @@ type (
 		Scope ScaleSetScope
 		Client
 		resourceSKUCache *resourceskus.Cache
+		featuresGetter   features.Client
+		quotasGetter     quotas.Client
+		agreementsGetter marketplaceagreements.Client
 		async.Reconciler
 	}
 )
 
 // New creates a new service.
 func New(scope ScaleSetScope, skuCache *resourceskus.Cache) (*Service, error) {
-	client, err := NewClient(scope)
+	client, err := NewClient(scope, scope.AuxiliaryTenantIDs()...)
+	if err != nil {
+		return nil, err
+	}
+	featuresClient, err := features.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	quotasClient, err := quotas.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	agreementsClient, err := marketplaceagreements.NewClient(scope)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +106,9 @@ func New(scope ScaleSetScope, skuCache *resourceskus.Cache) (*Service, error) {
 		Client:           client,
 		Scope:            scope,
 		resourceSKUCache: skuCache,
+		featuresGetter:   featuresClient,
+		quotasGetter:     quotasClient,
+		agreementsGetter: agreementsClient,
 	}, nil
 }
 
@@ -108,6 +147,17 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 		}
 	} else if !azure.ResourceNotFound(err) {
 		return errors.Wrapf(err, "failed to get existing VMSS")
+	} else if err := s.checkEncryptionAtHost(ctx, scaleSetSpec); err != nil {
+		s.Scope.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, err)
+		return err
+	} else if err := s.checkMarketplaceTerms(ctx, scaleSetSpec); err != nil {
+		s.Scope.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, err)
+		return err
+	}
+
+	if err := s.checkVCPUQuota(ctx, scaleSetSpec); err != nil {
+		s.Scope.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, err)
+		return err
 	}
 
 	result, err := s.CreateOrUpdateResource(ctx, scaleSetSpec, serviceName)
@@ -165,6 +215,60 @@ func (s *Service) Delete(ctx context.Context) error {
 	return err
 }
 
+// checkVCPUQuota returns an error when creating the instances a scale set is missing would exceed the vCPU quota of
+// the subscription, since Azure would reject the scale out. The check is skipped while the scale set is being created
+// or updated, as the vCPUs of its new instances then already count against the quota.
+func (s *Service) checkVCPUQuota(ctx context.Context, scaleSetSpec *ScaleSetSpec) error {
+	missing := scaleSetSpec.Capacity - int64(len(scaleSetSpec.VMSSInstances))
+	if scaleSetSpec.SKU.Name == nil || missing <= 0 ||
+		s.Scope.GetLongRunningOperationState(scaleSetSpec.Name, serviceName, infrav1.PutFuture) != nil {
+		return nil
+	}
+
+	exceeded, err := quotas.CheckVCPUs(ctx, s.quotasGetter, scaleSetSpec.Location, scaleSetSpec.SKU, missing, scaleSetSpec.SpotVMOptions != nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to check the vCPU quota")
+	}
+	if len(exceeded) > 0 {
+		return azure.WithTransientError(errors.Errorf("not enough vCPU quota to create %d instances of scale set %s: %s",
+			missing, scaleSetSpec.Name, strings.Join(exceeded, "; ")), quotaExceededRequeue)
+	}
+
+	return nil
+}
+
+// checkEncryptionAtHost returns an error when a scale set requests encryption at host but the EncryptionAtHost feature
+// is not registered on the subscription, since Azure would reject its creation.
+func (s *Service) checkEncryptionAtHost(ctx context.Context, scaleSetSpec *ScaleSetSpec) error {
+	if scaleSetSpec.SecurityProfile == nil || !ptr.Deref(scaleSetSpec.SecurityProfile.EncryptionAtHost, false) {
+		return nil
+	}
+
+	registered, err := s.featuresGetter.IsRegistered(ctx, features.ComputeNamespace, features.EncryptionAtHost)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check the registration of the %s feature", features.EncryptionAtHost)
+	}
+	if !registered {
+		return azure.WithTransientError(errors.Errorf("the %s/%s feature must be registered on the subscription to create scale sets with encryption at host enabled", features.ComputeNamespace, features.EncryptionAtHost), featureRegistrationRequeue)
+	}
+
+	return nil
+}
+
+// checkMarketplaceTerms returns an error when a scale set uses an image with a purchase plan whose marketplace terms
+// are not accepted on the subscription, since Azure would reject its creation.
+func (s *Service) checkMarketplaceTerms(ctx context.Context, scaleSetSpec *ScaleSetSpec) error {
+	message, err := marketplaceagreements.EnsureAccepted(ctx, s.agreementsGetter, scaleSetSpec.generateImagePlan(ctx))
+	if err != nil {
+		return errors.Wrap(err, "failed to check the marketplace terms of the image")
+	}
+	if message != "" {
+		return azure.WithTransientError(errors.New(message), marketplaceTermsRequeue)
+	}
+
+	return nil
+}
+
 func (s *Service) validateSpec(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.validateSpec")
 	defer done()
@@ -205,7 +309,11 @@ func (s *Service) validateSpec(ctx context.Context) error {
 		return azure.WithTerminalError(fmt.Errorf("vm size %s does not support ephemeral os. select a different vm size or disable ephemeral os", scaleSetSpec.Size))
 	}
 
-	if scaleSetSpec.SecurityProfile != nil && !sku.HasCapability(resourceskus.EncryptionAtHost) {
+	if ptr.Deref(scaleSetSpec.AcceleratedNetworking, false) && !sku.HasCapability(resourceskus.AcceleratedNetworking) {
+		return azure.WithTerminalError(errors.Errorf("vm size %s does not support accelerated networking. select a different vm size or disable accelerated networking", scaleSetSpec.Size))
+	}
+
+	if scaleSetSpec.SecurityProfile != nil && ptr.Deref(scaleSetSpec.SecurityProfile.EncryptionAtHost, false) && !sku.HasCapability(resourceskus.EncryptionAtHost) {
 		return azure.WithTerminalError(errors.Errorf("encryption at host is not supported for VM type %s", scaleSetSpec.Size))
 	}
 
@@ -236,6 +344,30 @@ func (s *Service) validateSpec(ctx context.Context) error {
 		}
 	}
 
+	// Premium SSD v2 and zone-redundant disks are only offered in some regions, and Premium SSD v2 disks can only be
+	// attached to zonal VMs.
+	for _, disk := range scaleSetSpec.DataDisks {
+		if disk.ManagedDisk == nil || !resourceskus.IsRegionalDiskType(disk.ManagedDisk.StorageAccountType) {
+			continue
+		}
+		storageAccountType := disk.ManagedDisk.StorageAccountType
+		diskSKU, err := s.resourceSKUCache.Get(ctx, storageAccountType, resourceskus.Disks)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the SKU of storage account type %s of data disk %s", storageAccountType, disk.NameSuffix)
+		}
+		if storageAccountType != string(armcompute.StorageAccountTypesPremiumV2LRS) {
+			continue
+		}
+		if len(scaleSetSpec.FailureDomains) == 0 {
+			return azure.WithTerminalError(errors.Errorf("data disk %s with storage account type %s can only be attached to a scale set in availability zones", disk.NameSuffix, storageAccountType))
+		}
+		for _, zone := range scaleSetSpec.FailureDomains {
+			if !diskSKU.IsZoneAvailable(scaleSetSpec.Location, zone) {
+				return azure.WithTerminalError(errors.Errorf("storage account type %s of data disk %s is not available in zone %s of location %s", storageAccountType, disk.NameSuffix, zone, scaleSetSpec.Location))
+			}
+		}
+	}
+
 	// Validate DiagnosticProfile spec
 	if scaleSetSpec.DiagnosticsProfile != nil && scaleSetSpec.DiagnosticsProfile.Boot != nil {
 		if scaleSetSpec.DiagnosticsProfile.Boot.StorageAccountType == infrav1.UserManagedDiagnosticsStorage {
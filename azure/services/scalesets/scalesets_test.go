@@ -31,6 +31,11 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/features"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/features/mock_features"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/marketplaceagreements/mock_marketplaceagreements"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/quotas"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/quotas/mock_quotas"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets/mock_scalesets"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
@@ -206,6 +211,18 @@ func TestReconcileVMSS(t *testing.T) {
 				s.ScaleSetSpec(gomockinternal.AContext()).Return(&spec).AnyTimes()
 			},
 		},
+		{
+			name:          "validate spec failure: accelerated networking requested but not supported",
+			expectedError: "reconcile error that cannot be recovered occurred: vm size VM_SIZE does not support accelerated networking. select a different vm size or disable accelerated networking. Object will not be requeued",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.Size = "VM_SIZE"
+				spec.Capacity = 2
+				spec.SSHKeyData = sshKeyData
+				spec.AcceleratedNetworking = ptr.To(true)
+				s.ScaleSetSpec(gomockinternal.AContext()).Return(&spec).AnyTimes()
+			},
+		},
 		{
 			name:          "validate spec failure: failed to get SKU",
 			expectedError: "failed to get SKU INVALID_VM_SIZE in compute api: reconcile error that cannot be recovered occurred: resource sku with name 'INVALID_VM_SIZE' and category 'virtualMachines' not found in location 'test-location'. Object will not be requeued",
@@ -317,6 +334,213 @@ func TestReconcileVMSS(t *testing.T) {
 	}
 }
 
+func TestCheckEncryptionAtHost(t *testing.T) {
+	testcases := []struct {
+		name            string
+		securityProfile *infrav1.SecurityProfile
+		expectedError   string
+		expect          func(f *mock_features.MockClientMockRecorder)
+	}{
+		{
+			name:   "encryption at host disabled",
+			expect: func(f *mock_features.MockClientMockRecorder) {},
+		},
+		{
+			name:            "feature registered",
+			securityProfile: &infrav1.SecurityProfile{EncryptionAtHost: ptr.To(true)},
+			expect: func(f *mock_features.MockClientMockRecorder) {
+				f.IsRegistered(gomockinternal.AContext(), features.ComputeNamespace, features.EncryptionAtHost).Return(true, nil)
+			},
+		},
+		{
+			name:            "feature not registered",
+			securityProfile: &infrav1.SecurityProfile{EncryptionAtHost: ptr.To(true)},
+			expectedError:   "the Microsoft.Compute/EncryptionAtHost feature must be registered on the subscription to create scale sets with encryption at host enabled. Object will be requeued after 5m0s",
+			expect: func(f *mock_features.MockClientMockRecorder) {
+				f.IsRegistered(gomockinternal.AContext(), features.ComputeNamespace, features.EncryptionAtHost).Return(false, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			featuresMock := mock_features.NewMockClient(mockCtrl)
+			tc.expect(featuresMock.EXPECT())
+
+			s := &Service{
+				featuresGetter: featuresMock,
+			}
+
+			err := s.checkEncryptionAtHost(context.TODO(), &ScaleSetSpec{SecurityProfile: tc.securityProfile})
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestCheckMarketplaceTerms(t *testing.T) {
+	thirdPartyImage := &infrav1.Image{
+		Marketplace: &infrav1.AzureMarketplaceImage{
+			ImagePlan:       infrav1.ImagePlan{Publisher: "kinvolk", Offer: "flatcar", SKU: "stable"},
+			Version:         "1.0.0",
+			ThirdPartyImage: true,
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		image         *infrav1.Image
+		expectedError string
+		expect        func(m *mock_marketplaceagreements.MockClientMockRecorder)
+	}{
+		{
+			name:   "image without a plan",
+			expect: func(m *mock_marketplaceagreements.MockClientMockRecorder) {},
+		},
+		{
+			name:  "terms accepted",
+			image: thirdPartyImage,
+			expect: func(m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.IsAccepted(gomockinternal.AContext(), "kinvolk", "flatcar", "stable").Return(true, nil)
+			},
+		},
+		{
+			name:          "terms not accepted",
+			image:         thirdPartyImage,
+			expectedError: "the marketplace terms of image plan kinvolk/flatcar/stable must be accepted on the subscription",
+			expect: func(m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.IsAccepted(gomockinternal.AContext(), "kinvolk", "flatcar", "stable").Return(false, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			agreementsMock := mock_marketplaceagreements.NewMockClient(mockCtrl)
+			tc.expect(agreementsMock.EXPECT())
+
+			s := &Service{
+				agreementsGetter: agreementsMock,
+			}
+
+			err := s.checkMarketplaceTerms(context.TODO(), &ScaleSetSpec{VMImage: tc.image})
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestCheckVCPUQuota(t *testing.T) {
+	sku := resourceskus.SKU{
+		Name:   ptr.To("Standard_D4s_v3"),
+		Family: ptr.To("standardDSv3Family"),
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
+			{
+				Name:  ptr.To(resourceskus.VCPUs),
+				Value: ptr.To("4"),
+			},
+		},
+	}
+	usages := []armcompute.Usage{
+		{
+			Name:         &armcompute.UsageName{Value: ptr.To(quotas.TotalRegionalVCPUs)},
+			CurrentValue: ptr.To[int32](4),
+			Limit:        ptr.To[int64](100),
+		},
+		{
+			Name:         &armcompute.UsageName{Value: ptr.To("standardDSv3Family")},
+			CurrentValue: ptr.To[int32](4),
+			Limit:        ptr.To[int64](12),
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		spec          *ScaleSetSpec
+		expectedError string
+		expect        func(s *mock_scalesets.MockScaleSetScopeMockRecorder, q *mock_quotas.MockClientMockRecorder)
+	}{
+		{
+			name:   "vm size is unknown",
+			spec:   &ScaleSetSpec{Name: defaultVMSSName, Capacity: 2},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, q *mock_quotas.MockClientMockRecorder) {},
+		},
+		{
+			name:   "no instance is missing",
+			spec:   &ScaleSetSpec{Name: defaultVMSSName, Capacity: 1, SKU: sku, VMSSInstances: []armcompute.VirtualMachineScaleSetVM{{}}},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, q *mock_quotas.MockClientMockRecorder) {},
+		},
+		{
+			name: "scale set is being updated",
+			spec: &ScaleSetSpec{Name: defaultVMSSName, Capacity: 2, SKU: sku},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, q *mock_quotas.MockClientMockRecorder) {
+				s.GetLongRunningOperationState(defaultVMSSName, serviceName, infrav1.PutFuture).Return(&infrav1.Future{})
+			},
+		},
+		{
+			name: "enough quota for the missing instances",
+			spec: &ScaleSetSpec{Name: defaultVMSSName, Location: "westus", Capacity: 3, SKU: sku, VMSSInstances: []armcompute.VirtualMachineScaleSetVM{{}}},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, q *mock_quotas.MockClientMockRecorder) {
+				s.GetLongRunningOperationState(defaultVMSSName, serviceName, infrav1.PutFuture).Return(nil)
+				q.ListUsages(gomockinternal.AContext(), "westus").Return(usages, nil)
+			},
+		},
+		{
+			name:          "quota exceeded",
+			spec:          &ScaleSetSpec{Name: defaultVMSSName, Location: "westus", Capacity: 3, SKU: sku},
+			expectedError: "not enough vCPU quota to create 3 instances of scale set my-vmss: 12 vCPUs of the standardDSv3Family quota are required in westus but only 8 of the 12 allowed are available. Object will be requeued after 5m0s",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, q *mock_quotas.MockClientMockRecorder) {
+				s.GetLongRunningOperationState(defaultVMSSName, serviceName, infrav1.PutFuture).Return(nil)
+				q.ListUsages(gomockinternal.AContext(), "westus").Return(usages, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_scalesets.NewMockScaleSetScope(mockCtrl)
+			quotasMock := mock_quotas.NewMockClient(mockCtrl)
+			tc.expect(scopeMock.EXPECT(), quotasMock.EXPECT())
+
+			s := &Service{
+				Scope:        scopeMock,
+				quotasGetter: quotasMock,
+			}
+
+			err := s.checkVCPUQuota(context.TODO(), tc.spec)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestDeleteVMSS(t *testing.T) {
 	defaultSpec := newDefaultVMSSSpec()
 	defaultInstances := newDefaultInstances()
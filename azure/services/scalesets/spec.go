@@ -20,9 +20,12 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -40,6 +43,8 @@ type ScaleSetSpec struct {
 	Size                         string
 	Capacity                     int64
 	SSHKeyData                   string
+	ClusterSSHKeyData            string
+	AdditionalSSHKeyData         []string
 	OSDisk                       infrav1.OSDisk
 	DataDisks                    []infrav1.DataDisk
 	SubnetName                   string
@@ -47,6 +52,8 @@ type ScaleSetSpec struct {
 	VNetResourceGroup            string
 	PublicLBName                 string
 	PublicLBAddressPoolName      string
+	InternalLBName               string
+	InternalLBAddressPoolName    string
 	AcceleratedNetworking        *bool
 	TerminateNotificationTimeout *int
 	Identity                     infrav1.VMIdentity
@@ -58,6 +65,7 @@ type ScaleSetSpec struct {
 	FailureDomains               []string
 	VMExtensions                 []infrav1.VMExtension
 	NetworkInterfaces            []infrav1.NetworkInterface
+	NetworkSecurityGroupID       string
 	IPv6Enabled                  bool
 	OrchestrationMode            infrav1.OrchestrationModeType
 	Location                     string
@@ -111,6 +119,7 @@ func (s *ScaleSetSpec) existingParameters(ctx context.Context, existing interfac
 	vmss.ID = existingVMSS.ID
 
 	hasModelChanges := hasModelModifyingDifferences(&existingInfraVMSS, vmss)
+	hasInPlaceChanges := hasInPlaceDifferences(existingVMSS, vmss)
 	isFlex := s.OrchestrationMode == infrav1.FlexibleOrchestrationMode
 	updated := true
 	if !isFlex {
@@ -122,9 +131,9 @@ func (s *ScaleSetSpec) existingParameters(ctx context.Context, existing interfac
 		vmss.SKU.Capacity = ptr.To[int64](surge)
 	}
 
-	// If there are no model changes and no increase in the replica count, do not update the VMSS.
+	// If there are no model changes, no in-place changes and no increase in the replica count, do not update the VMSS.
 	// Decreases in replica count is handled by deleting AzureMachinePoolMachine instances in the MachinePoolScope
-	if *vmss.SKU.Capacity <= existingInfraVMSS.Capacity && !hasModelChanges && !s.ShouldPatchCustomData {
+	if *vmss.SKU.Capacity <= existingInfraVMSS.Capacity && !hasModelChanges && !hasInPlaceChanges && !s.ShouldPatchCustomData {
 		// up to date, nothing to do
 		return nil, nil
 	}
@@ -278,6 +287,88 @@ func hasModelModifyingDifferences(infraVMSS *azure.VMSS, vmss armcompute.Virtual
 	return infraVMSS.HasModelChanges(other)
 }
 
+// hasInPlaceDifferences returns true if the tags, VM extensions or identities of the scale set are different. These
+// are updated on the existing scale set, without surging its capacity to replace the instances.
+func hasInPlaceDifferences(existing, vmss armcompute.VirtualMachineScaleSet) bool {
+	return !cmp.Equal(converters.MapToTags(existing.Tags), converters.MapToTags(vmss.Tags)) ||
+		!cmp.Equal(extensionsByName(existing), extensionsByName(vmss)) ||
+		!cmp.Equal(identityOf(existing.Identity), identityOf(vmss.Identity))
+}
+
+// vmssExtension holds the fields of a VMSS extension compared to detect changes. Protected settings are never
+// returned by Azure, so changes to them alone are not detected.
+type vmssExtension struct {
+	Publisher          string
+	Type               string
+	TypeHandlerVersion string
+	Settings           map[string]string
+}
+
+// extensionsByName returns the VM extensions of a scale set by name.
+func extensionsByName(vmss armcompute.VirtualMachineScaleSet) map[string]vmssExtension {
+	extensions := map[string]vmssExtension{}
+	if vmss.Properties == nil || vmss.Properties.VirtualMachineProfile == nil || vmss.Properties.VirtualMachineProfile.ExtensionProfile == nil {
+		return extensions
+	}
+	for _, extension := range vmss.Properties.VirtualMachineProfile.ExtensionProfile.Extensions {
+		if extension == nil {
+			continue
+		}
+		e := vmssExtension{}
+		if extension.Properties != nil {
+			e.Publisher = ptr.Deref(extension.Properties.Publisher, "")
+			e.Type = ptr.Deref(extension.Properties.Type, "")
+			e.TypeHandlerVersion = ptr.Deref(extension.Properties.TypeHandlerVersion, "")
+			e.Settings = extensionSettings(extension.Properties.Settings)
+		}
+		extensions[ptr.Deref(extension.Name, "")] = e
+	}
+	return extensions
+}
+
+// extensionSettings returns the settings of a VM extension as strings. The settings of extensions generated by CAPZ
+// are a map[string]string, while those returned by Azure are unmarshaled to a map[string]interface{}.
+func extensionSettings(settings interface{}) map[string]string {
+	switch s := settings.(type) {
+	case map[string]string:
+		if len(s) == 0 {
+			return nil
+		}
+		return s
+	case map[string]interface{}:
+		if len(s) == 0 {
+			return nil
+		}
+		result := make(map[string]string, len(s))
+		for k, v := range s {
+			result[k] = fmt.Sprint(v)
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// vmssIdentity holds the fields of a scale set identity compared to detect changes.
+type vmssIdentity struct {
+	Type                   armcompute.ResourceIdentityType
+	UserAssignedIdentities []string
+}
+
+// identityOf returns the type and the sorted, lowercased IDs of the user-assigned identities of a scale set identity,
+// as Azure does not preserve the casing of resource IDs.
+func identityOf(identity *armcompute.VirtualMachineScaleSetIdentity) *vmssIdentity {
+	if identity == nil || identity.Type == nil || *identity.Type == armcompute.ResourceIdentityTypeNone {
+		return nil
+	}
+	result := &vmssIdentity{Type: *identity.Type}
+	for id := range identity.UserAssignedIdentities {
+		result.UserAssignedIdentities = append(result.UserAssignedIdentities, strings.ToLower(id))
+	}
+	sort.Strings(result.UserAssignedIdentities)
+	return result
+}
+
 func (s *ScaleSetSpec) generateExtensions(ctx context.Context) ([]armcompute.VirtualMachineScaleSetExtension, error) {
 	extensions := make([]armcompute.VirtualMachineScaleSetExtension, len(s.VMSSExtensionSpecs))
 	for i, extensionSpec := range s.VMSSExtensionSpecs {
@@ -306,6 +397,12 @@ func (s *ScaleSetSpec) getVirtualMachineScaleSetNetworkConfiguration() *[]armcom
 				})
 		}
 	}
+	if s.InternalLBName != "" && s.InternalLBAddressPoolName != "" {
+		backendAddressPools = append(backendAddressPools,
+			armcompute.SubResource{
+				ID: ptr.To(azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.InternalLBName, s.InternalLBAddressPoolName)),
+			})
+	}
 	nicConfigs := []armcompute.VirtualMachineScaleSetNetworkConfiguration{}
 	for i, n := range s.NetworkInterfaces {
 		nicConfig := armcompute.VirtualMachineScaleSetNetworkConfiguration{}
@@ -319,6 +416,11 @@ func (s *ScaleSetSpec) getVirtualMachineScaleSetNetworkConfiguration() *[]armcom
 			// It will be set to true if the VMSS SKU supports it.
 			nicConfig.Properties.EnableAcceleratedNetworking = s.AcceleratedNetworking
 		}
+		if s.NetworkSecurityGroupID != "" {
+			nicConfig.Properties.NetworkSecurityGroup = &armcompute.SubResource{
+				ID: ptr.To(s.NetworkSecurityGroupID),
+			}
+		}
 
 		// Create IPConfigs
 		ipconfigs := []armcompute.VirtualMachineScaleSetIPConfiguration{}
@@ -375,6 +477,17 @@ func (s *ScaleSetSpec) generateStorageProfile(ctx context.Context) (*armcompute.
 		},
 	}
 
+	// check the support for write accelerator on the requested number of disks based on vm size
+	if writeAcceleratorDisks := s.writeAcceleratorDisks(); writeAcceleratorDisks > 0 {
+		writeAcceleratorCapability, err := s.SKU.HasCapabilityWithCapacity(resourceskus.MaxWriteAcceleratorDisksAllowed, int64(writeAcceleratorDisks))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to validate the write accelerator capability")
+		}
+		if !writeAcceleratorCapability {
+			return nil, fmt.Errorf("vm size %s does not support write accelerator on %d disks. select a different vm size or disable write accelerator", s.Size, writeAcceleratorDisks)
+		}
+	}
+
 	// enable ephemeral OS
 	if s.OSDisk.DiffDiskSettings != nil {
 		if !s.SKU.HasCapability(resourceskus.EphemeralOSDisk) {
@@ -388,8 +501,8 @@ func (s *ScaleSetSpec) generateStorageProfile(ctx context.Context) (*armcompute.
 
 	if s.OSDisk.ManagedDisk != nil {
 		storageProfile.OSDisk.ManagedDisk = &armcompute.VirtualMachineScaleSetManagedDiskParameters{}
-		if s.OSDisk.ManagedDisk.StorageAccountType != "" {
-			storageProfile.OSDisk.ManagedDisk.StorageAccountType = ptr.To(armcompute.StorageAccountTypes(s.OSDisk.ManagedDisk.StorageAccountType))
+		if storageAccountType := s.OSDisk.StorageAccountType(); storageAccountType != "" {
+			storageProfile.OSDisk.ManagedDisk.StorageAccountType = ptr.To(armcompute.StorageAccountTypes(storageAccountType))
 		}
 		if s.OSDisk.ManagedDisk.DiskEncryptionSet != nil {
 			storageProfile.OSDisk.ManagedDisk.DiskEncryptionSet = &armcompute.DiskEncryptionSetParameters{ID: ptr.To(s.OSDisk.ManagedDisk.DiskEncryptionSet.ID)}
@@ -400,6 +513,10 @@ func (s *ScaleSetSpec) generateStorageProfile(ctx context.Context) (*armcompute.
 		storageProfile.OSDisk.Caching = ptr.To(armcompute.CachingTypes(s.OSDisk.CachingType))
 	}
 
+	if ptr.Deref(s.OSDisk.WriteAcceleratorEnabled, false) {
+		storageProfile.OSDisk.WriteAcceleratorEnabled = ptr.To(true)
+	}
+
 	dataDisks := make([]armcompute.VirtualMachineScaleSetDataDisk, len(s.DataDisks))
 	for i, disk := range s.DataDisks {
 		dataDisks[i] = armcompute.VirtualMachineScaleSetDataDisk{
@@ -408,11 +525,19 @@ func (s *ScaleSetSpec) generateStorageProfile(ctx context.Context) (*armcompute.
 			Lun:          disk.Lun,
 			Name:         ptr.To(azure.GenerateDataDiskName(s.Name, disk.NameSuffix)),
 		}
+		if disk.CachingType != "" {
+			dataDisks[i].Caching = ptr.To(armcompute.CachingTypes(disk.CachingType))
+		}
+		if ptr.Deref(disk.WriteAcceleratorEnabled, false) {
+			dataDisks[i].WriteAcceleratorEnabled = ptr.To(true)
+		}
 
 		if disk.ManagedDisk != nil {
 			dataDisks[i].ManagedDisk = &armcompute.VirtualMachineScaleSetManagedDiskParameters{
 				StorageAccountType: ptr.To(armcompute.StorageAccountTypes(disk.ManagedDisk.StorageAccountType)),
 			}
+			dataDisks[i].DiskIOPSReadWrite = disk.ManagedDisk.DiskIOPSReadWrite
+			dataDisks[i].DiskMBpsReadWrite = disk.ManagedDisk.DiskMBpsReadWrite
 
 			if disk.ManagedDisk.DiskEncryptionSet != nil {
 				dataDisks[i].ManagedDisk.DiskEncryptionSet = &armcompute.DiskEncryptionSetParameters{ID: ptr.To(disk.ManagedDisk.DiskEncryptionSet.ID)}
@@ -434,11 +559,33 @@ func (s *ScaleSetSpec) generateStorageProfile(ctx context.Context) (*armcompute.
 	return storageProfile, nil
 }
 
+// writeAcceleratorDisks returns the number of disks of the scale set VMs with write accelerator enabled.
+func (s *ScaleSetSpec) writeAcceleratorDisks() int {
+	count := 0
+	if ptr.Deref(s.OSDisk.WriteAcceleratorEnabled, false) {
+		count++
+	}
+	for _, disk := range s.DataDisks {
+		if ptr.Deref(disk.WriteAcceleratorEnabled, false) {
+			count++
+		}
+	}
+	return count
+}
+
 func (s *ScaleSetSpec) generateOSProfile(_ context.Context) (*armcompute.VirtualMachineScaleSetOSProfile, error) {
 	sshKey, err := base64.StdEncoding.DecodeString(s.SSHKeyData)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to decode ssh public key")
 	}
+	clusterSSHKey, err := base64.StdEncoding.DecodeString(s.ClusterSSHKeyData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode cluster ssh public key")
+	}
+	additionalSSHKeys, err := azure.DecodeSSHPublicKeys(s.AdditionalSSHKeyData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode additional ssh public keys")
+	}
 
 	osProfile := &armcompute.VirtualMachineScaleSetOSProfile{
 		ComputerNamePrefix: ptr.To(s.Name),
@@ -460,15 +607,28 @@ func (s *ScaleSetSpec) generateOSProfile(_ context.Context) (*armcompute.Virtual
 			EnableAutomaticUpdates: ptr.To(false),
 		}
 	default:
+		publicKeys := []*armcompute.SSHPublicKey{
+			{
+				Path:    ptr.To(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
+				KeyData: ptr.To(string(sshKey)),
+			},
+		}
+		// The machine's own key comes first, then its additional keys and the cluster keys, each authorized once.
+		authorized := map[string]bool{string(sshKey): true}
+		for _, key := range append(additionalSSHKeys, string(clusterSSHKey)) {
+			if key == "" || authorized[key] {
+				continue
+			}
+			authorized[key] = true
+			publicKeys = append(publicKeys, &armcompute.SSHPublicKey{
+				Path:    ptr.To(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
+				KeyData: ptr.To(key),
+			})
+		}
 		osProfile.LinuxConfiguration = &armcompute.LinuxConfiguration{
 			DisablePasswordAuthentication: ptr.To(true),
 			SSH: &armcompute.SSHConfiguration{
-				PublicKeys: []*armcompute.SSHPublicKey{
-					{
-						Path:    ptr.To(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
-						KeyData: ptr.To(string(sshKey)),
-					},
-				},
+				PublicKeys: publicKeys,
 			},
 		}
 	}
@@ -36,6 +36,7 @@ var (
 	windowsSpec, windowsVMSS                                                           = getDefaultWindowsVMSS()
 	acceleratedNetworkingSpec, acceleratedNetworkingVMSS                               = getAcceleratedNetworkingVMSS()
 	customSubnetSpec, customSubnetVMSS                                                 = getCustomSubnetVMSS()
+	networkSecurityGroupSpec, networkSecurityGroupVMSS                                 = getNetworkSecurityGroupVMSS()
 	customNetworkingSpec, customNetworkingVMSS                                         = getCustomNetworkingVMSS()
 	spotVMSpec, spotVMVMSS                                                             = getSpotVMVMSS()
 	ephemeralSpec, ephemeralVMSS                                                       = getEPHVMSSS()
@@ -47,6 +48,7 @@ var (
 	hostEncryptionUnsupportedSpec                                                      = getHostEncryptionUnsupportedSpec()
 	ephemeralReadSpec, ephemeralReadVMSS                                               = getEphemeralReadOnlyVMSS()
 	defaultExistingSpec, defaultExistingVMSS, defaultExistingVMSSClone                 = getExistingDefaultVMSS()
+	inPlaceChangesSpec, inPlaceChangesExistingVMSS, inPlaceChangesVMSS                 = getInPlaceChangesVMSS()
 	userManagedStorageAccountDiagnosticsSpec, userManagedStorageAccountDiagnosticsVMSS = getUserManagedAndStorageAcccountDiagnosticsVMSS()
 	managedDiagnosticsSpec, managedDiagnoisticsVMSS                                    = getManagedDiagnosticsVMSS()
 	disabledDiagnosticsSpec, disabledDiagnosticsVMSS                                   = getDisabledDiagnosticsVMSS()
@@ -98,6 +100,16 @@ func getAcceleratedNetworkingVMSS() (ScaleSetSpec, armcompute.VirtualMachineScal
 	return spec, vmss
 }
 
+func getNetworkSecurityGroupVMSS() (ScaleSetSpec, armcompute.VirtualMachineScaleSet) {
+	spec, vmss := getDefaultVMSS()
+	spec.NetworkSecurityGroupID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/dmz-nsg"
+	vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations[0].Properties.NetworkSecurityGroup = &armcompute.SubResource{
+		ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/dmz-nsg"),
+	}
+
+	return spec, vmss
+}
+
 func getCustomSubnetVMSS() (ScaleSetSpec, armcompute.VirtualMachineScaleSet) {
 	spec := newDefaultVMSSSpec()
 	spec.Size = "VM_SIZE_AN"
@@ -415,6 +427,31 @@ func getExistingDefaultVMSS() (s ScaleSetSpec, existing armcompute.VirtualMachin
 	return spec, existingVMSS, clone
 }
 
+func getInPlaceChangesVMSS() (s ScaleSetSpec, existing armcompute.VirtualMachineScaleSet, result armcompute.VirtualMachineScaleSet) {
+	spec := newDefaultVMSSSpec()
+	spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+		NameSuffix: "my_disk_with_ultra_disks",
+		DiskSizeGB: 128,
+		Lun:        ptr.To[int32](3),
+		ManagedDisk: &infrav1.ManagedDiskParameters{
+			StorageAccountType: "UltraSSD_LRS",
+		},
+	})
+	spec.MaxSurge = 1
+	spec.AdditionalTags = infrav1.Tags{"team": "storage"}
+	spec.VMSSExtensionSpecs[0].(*VMSSExtensionSpec).Version = "someOtherVersion"
+
+	existingVMSS := newDefaultExistingVMSS("VM_SIZE")
+
+	clone := newDefaultExistingVMSS("VM_SIZE")
+	clone.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: ptr.To(true)}
+	clone.Tags["team"] = ptr.To("storage")
+	clone.Properties.VirtualMachineProfile.ExtensionProfile.Extensions[0].Properties.TypeHandlerVersion = ptr.To("someOtherVersion")
+	clone.Properties.VirtualMachineProfile.NetworkProfile = nil
+
+	return spec, existingVMSS, clone
+}
+
 func getUserManagedAndStorageAcccountDiagnosticsVMSS() (ScaleSetSpec, armcompute.VirtualMachineScaleSet) {
 	storageURI := "https://fakeurl"
 	spec := newDefaultVMSSSpec()
@@ -565,6 +602,13 @@ func TestScaleSetParameters(t *testing.T) {
 			expected:      customSubnetVMSS,
 			expectedError: "",
 		},
+		{
+			name:          "network security group vmss",
+			spec:          networkSecurityGroupSpec,
+			existing:      nil,
+			expected:      networkSecurityGroupVMSS,
+			expectedError: "",
+		},
 		{
 			name:          "custom networking vmss",
 			spec:          customNetworkingSpec,
@@ -649,6 +693,20 @@ func TestScaleSetParameters(t *testing.T) {
 			expected:      defaultExistingVMSSClone,
 			expectedError: "",
 		},
+		{
+			name:          "no update for up to date existing vmss",
+			spec:          newDefaultVMSSSpec(),
+			existing:      newDefaultExistingVMSS("VM_SIZE"),
+			expected:      nil,
+			expectedError: "",
+		},
+		{
+			name:          "update tags and extensions of existing vmss in place without surging",
+			spec:          inPlaceChangesSpec,
+			existing:      inPlaceChangesExistingVMSS,
+			expected:      inPlaceChangesVMSS,
+			expectedError: "",
+		},
 		{
 			name:          "vm with diagnostics set to User Managed and StorageAccountURI set",
 			spec:          userManagedStorageAccountDiagnosticsSpec,
@@ -707,3 +765,113 @@ func TestScaleSetParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestHasInPlaceDifferences(t *testing.T) {
+	identity := func(t armcompute.ResourceIdentityType, ids ...string) *armcompute.VirtualMachineScaleSetIdentity {
+		identity := &armcompute.VirtualMachineScaleSetIdentity{Type: ptr.To(t)}
+		if len(ids) > 0 {
+			identity.UserAssignedIdentities = map[string]*armcompute.UserAssignedIdentitiesValue{}
+			for _, id := range ids {
+				identity.UserAssignedIdentities[id] = &armcompute.UserAssignedIdentitiesValue{}
+			}
+		}
+		return identity
+	}
+
+	testcases := []struct {
+		name     string
+		existing func(vmss *armcompute.VirtualMachineScaleSet)
+		desired  func(vmss *armcompute.VirtualMachineScaleSet)
+		expected bool
+	}{
+		{
+			name:     "no differences",
+			expected: false,
+		},
+		{
+			name: "extension settings returned by Azure as a map[string]interface{}",
+			existing: func(vmss *armcompute.VirtualMachineScaleSet) {
+				vmss.Properties.VirtualMachineProfile.ExtensionProfile.Extensions[0].Properties.Settings = map[string]interface{}{
+					"someSetting": "someValue",
+				}
+			},
+			expected: false,
+		},
+		{
+			name: "different tags",
+			desired: func(vmss *armcompute.VirtualMachineScaleSet) {
+				vmss.Tags["team"] = ptr.To("storage")
+			},
+			expected: true,
+		},
+		{
+			name: "different extension settings",
+			desired: func(vmss *armcompute.VirtualMachineScaleSet) {
+				vmss.Properties.VirtualMachineProfile.ExtensionProfile.Extensions[0].Properties.Settings = map[string]string{
+					"someSetting": "someOtherValue",
+				}
+			},
+			expected: true,
+		},
+		{
+			name: "removed extension",
+			desired: func(vmss *armcompute.VirtualMachineScaleSet) {
+				vmss.Properties.VirtualMachineProfile.ExtensionProfile.Extensions = nil
+			},
+			expected: true,
+		},
+		{
+			name: "same user-assigned identities with a different casing",
+			existing: func(vmss *armcompute.VirtualMachineScaleSet) {
+				vmss.Identity = identity(armcompute.ResourceIdentityTypeUserAssigned, "/subscriptions/123/resourcegroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity")
+			},
+			desired: func(vmss *armcompute.VirtualMachineScaleSet) {
+				vmss.Identity = identity(armcompute.ResourceIdentityTypeUserAssigned, "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity")
+			},
+			expected: false,
+		},
+		{
+			name: "added user-assigned identity",
+			existing: func(vmss *armcompute.VirtualMachineScaleSet) {
+				vmss.Identity = identity(armcompute.ResourceIdentityTypeUserAssigned, "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity")
+			},
+			desired: func(vmss *armcompute.VirtualMachineScaleSet) {
+				vmss.Identity = identity(armcompute.ResourceIdentityTypeUserAssigned,
+					"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity",
+					"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-other-identity")
+			},
+			expected: true,
+		},
+		{
+			name: "system-assigned identity enabled",
+			desired: func(vmss *armcompute.VirtualMachineScaleSet) {
+				vmss.Identity = identity(armcompute.ResourceIdentityTypeSystemAssigned)
+			},
+			expected: true,
+		},
+		{
+			name: "no identity is the same as the None identity type",
+			existing: func(vmss *armcompute.VirtualMachineScaleSet) {
+				vmss.Identity = identity(armcompute.ResourceIdentityTypeNone)
+			},
+			expected: false,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			existing := newDefaultExistingVMSS("VM_SIZE")
+			if tc.existing != nil {
+				tc.existing(&existing)
+			}
+			desired := newDefaultVMSS("VM_SIZE")
+			if tc.desired != nil {
+				tc.desired(&desired)
+			}
+			g.Expect(hasInPlaceDifferences(existing, desired)).To(Equal(tc.expected))
+		})
+	}
+}
@@ -67,12 +67,37 @@ func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 	return resp.VirtualMachineScaleSetVM, nil
 }
 
-// CreateOrUpdateAsync is a dummy implementation to fulfill the async.Reconciler interface.
+// CreateOrUpdateAsync updates a virtual machine scale set instance asynchronously. It sends a PATCH request to Azure
+// and if accepted without error, the func will return a poller which can be used to track the ongoing progress of
+// the operation. Instances are created by their scale set, so only existing instances are ever updated.
 func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armcompute.VirtualMachineScaleSetVMsClientUpdateResponse], err error) {
-	_, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.CreateOrUpdateAsync")
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.AzureClient.CreateOrUpdateAsync")
 	defer done()
 
-	return nil, nil, nil
+	instance, ok := parameters.(armcompute.VirtualMachineScaleSetVM)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armcompute.VirtualMachineScaleSetVM", parameters)
+	}
+
+	opts := &armcompute.VirtualMachineScaleSetVMsClientBeginUpdateOptions{ResumeToken: resumeToken}
+	poller, err = ac.scalesetvms.BeginUpdate(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName(), instance, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	resp, err := poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, poller, err
+	}
+
+	// if the operation completed, return a nil poller
+	return resp.VirtualMachineScaleSetVM, nil, err
 }
 
 // DeleteAsync deletes a virtual machine scale set instance asynchronously. DeleteAsync sends a DELETE
@@ -91,7 +91,8 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	var getter azure.ResourceSpecGetter = scaleSetVMSpec
 	var result interface{}
 	var err error
-	// Fetch the latest instance or VM data. AzureMachinePoolReconciler handles model mutations.
+	// Fetch the latest instance or VM data. AzureMachinePoolReconciler handles model mutations, so instances are only
+	// updated to apply their scale-in protection.
 	if scaleSetVMSpec.IsFlex {
 		log.V(4).Info("VMSS is flex", "vmssName", scaleSetVMSpec.Name, "providerID", scaleSetVMSpec.ProviderID, "resourceID", scaleSetVMSpec.ResourceID)
 		getter, err = scaleSetVMSpecToVMSpec(*scaleSetVMSpec)
@@ -104,7 +105,8 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	}
 
 	// We only want to get the resource if it exists and handle the not found error.
-	// We're using CreateOrUpdateResource() to do so but it doesn't actually create or update anything since getter.Parameters() always returns nil.
+	// We're using CreateOrUpdateResource() to do so but it never creates anything since getter.Parameters() returns nil
+	// when the resource does not exist, and only updates the scale-in protection of uniform instances.
 	result, err = reconciler.CreateOrUpdateResource(ctx, getter, serviceName)
 	if err != nil {
 		return err
@@ -18,6 +18,10 @@ package scalesetvms
 
 import (
 	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 )
 
 // ScaleSetVMSpec defines the specification for a VMSS VM.
@@ -29,6 +33,8 @@ type ScaleSetVMSpec struct {
 	ProviderID    string
 	ResourceID    string
 	IsFlex        bool
+	// ProtectFromScaleIn is whether the instance should be protected from scale-in.
+	ProtectFromScaleIn bool
 }
 
 // ResourceName returns the instance ID of the VMSS VM. This is because the it is identified by the instance ID in Azure instead of the name.
@@ -46,9 +52,38 @@ func (s *ScaleSetVMSpec) OwnerResourceName() string {
 	return s.ScaleSetName
 }
 
-// Parameters is a no-op for VMSS VMs as this spec is only used to Get().
+// Parameters returns the parameters to update the scale-in protection of an existing VMSS VM, or nil if it is
+// already up to date. VMSS VMs are created by their scale set, so nothing is returned when the VM does not exist.
 func (s *ScaleSetVMSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
-	return nil, nil
+	if existing == nil {
+		return nil, nil
+	}
+	instance, ok := existing.(armcompute.VirtualMachineScaleSetVM)
+	if !ok {
+		return nil, errors.Errorf("%T is not an armcompute.VirtualMachineScaleSetVM", existing)
+	}
+
+	protected := false
+	if instance.Properties != nil && instance.Properties.ProtectionPolicy != nil {
+		protected = ptr.Deref(instance.Properties.ProtectionPolicy.ProtectFromScaleIn, false)
+	}
+	if protected == s.ProtectFromScaleIn {
+		return nil, nil
+	}
+
+	// Copy the properties and protection policy so that the existing instance is left untouched.
+	properties := armcompute.VirtualMachineScaleSetVMProperties{}
+	if instance.Properties != nil {
+		properties = *instance.Properties
+	}
+	protectionPolicy := armcompute.VirtualMachineScaleSetVMProtectionPolicy{}
+	if properties.ProtectionPolicy != nil {
+		protectionPolicy = *properties.ProtectionPolicy
+	}
+	protectionPolicy.ProtectFromScaleIn = ptr.To(s.ProtectFromScaleIn)
+	properties.ProtectionPolicy = &protectionPolicy
+	instance.Properties = &properties
+	return instance, nil
 }
 
 // VMSSFlexGetter defines the specification for a VMSS flex VM.
@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scalesetvms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestParameters(t *testing.T) {
+	protectedScaleSetVM := armcompute.VirtualMachineScaleSetVM{
+		ID: uniformScaleSetVM.ID,
+		Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+			ProtectionPolicy: &armcompute.VirtualMachineScaleSetVMProtectionPolicy{
+				ProtectFromScaleIn: ptr.To(true),
+			},
+		},
+	}
+
+	testcases := []struct {
+		name               string
+		protectFromScaleIn bool
+		existing           interface{}
+		expect             func(g *WithT, result interface{})
+		expectedError      string
+	}{
+		{
+			name:     "nothing to do when the instance does not exist",
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:     "nothing to do when an unprotected instance should not be protected",
+			existing: uniformScaleSetVM,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:               "nothing to do when a protected instance should be protected",
+			protectFromScaleIn: true,
+			existing:           protectedScaleSetVM,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:               "protect an unprotected instance",
+			protectFromScaleIn: true,
+			existing:           uniformScaleSetVM,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachineScaleSetVM{}))
+				g.Expect(result.(armcompute.VirtualMachineScaleSetVM).ID).To(Equal(uniformScaleSetVM.ID))
+				g.Expect(result.(armcompute.VirtualMachineScaleSetVM).Properties.ProtectionPolicy.ProtectFromScaleIn).To(Equal(ptr.To(true)))
+			},
+		},
+		{
+			name:     "unprotect a protected instance",
+			existing: protectedScaleSetVM,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachineScaleSetVM{}))
+				g.Expect(result.(armcompute.VirtualMachineScaleSetVM).Properties.ProtectionPolicy.ProtectFromScaleIn).To(Equal(ptr.To(false)))
+			},
+		},
+		{
+			name:     "error when the existing resource is not a VMSS VM",
+			existing: armcompute.VirtualMachine{},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "armcompute.VirtualMachine is not an armcompute.VirtualMachineScaleSetVM",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			spec := *uniformScaleSetVMSpec
+			spec.ProtectFromScaleIn = tc.protectFromScaleIn
+			result, err := spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			tc.expect(g, result)
+		})
+	}
+}
@@ -23,7 +23,9 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -140,3 +142,32 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 	// if the operation completed, return a nil poller.
 	return nil, err
 }
+
+// ReleaseOwnership removes the tag marking a shared network security group as owned by clusterName, unless clusterName
+// is its last owner, and returns whether it is. The security group is written back only if it wasn't modified since it
+// was read, so that clusters releasing it concurrently can't all leave it in place.
+func (ac *azureClient) ReleaseOwnership(ctx context.Context, spec azure.ResourceSpecGetter, clusterName string) (bool, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "securitygroups.azureClient.ReleaseOwnership")
+	defer done()
+
+	resp, err := ac.securitygroups.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return false, err
+	}
+	sg := resp.SecurityGroup
+	tags := converters.MapToTags(sg.Tags)
+	if !tags.HasOwned(clusterName) {
+		return false, nil
+	}
+	if len(tags.Owners()) == 1 {
+		return true, nil
+	}
+	if sg.Etag == nil {
+		return false, errors.Errorf("network security group %s has no etag", spec.ResourceName())
+	}
+	delete(sg.Tags, infrav1.ClusterTagKey(clusterName))
+
+	// The etag of the security group makes the update conditional.
+	_, _, err = ac.CreateOrUpdateAsync(ctx, spec, "", sg)
+	return false, err
+}
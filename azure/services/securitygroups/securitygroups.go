@@ -18,12 +18,14 @@ package securitygroups
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -34,6 +36,7 @@ const serviceName = "securitygroups"
 type NSGScope interface {
 	azure.Authorizer
 	azure.AsyncStatusUpdater
+	ResourceGroup() string
 	NSGSpecs() []azure.ResourceSpecGetter
 	IsVnetManaged() bool
 	UpdateAnnotationJSON(string, map[string]interface{}) error
@@ -43,6 +46,7 @@ type NSGScope interface {
 type Service struct {
 	Scope NSGScope
 	async.Reconciler
+	ownershipReleaser async.OwnershipReleaser
 }
 
 // New creates a new service.
@@ -55,6 +59,7 @@ func New(scope NSGScope) (*Service, error) {
 		Scope: scope,
 		Reconciler: async.New[armnetwork.SecurityGroupsClientCreateOrUpdateResponse,
 			armnetwork.SecurityGroupsClientDeleteResponse](scope, client, client),
+		ownershipReleaser: client,
 	}, nil
 }
 
@@ -95,7 +100,9 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		nsgSpec := resourceSpec.(*NSGSpec)
 		currentAnnotation := make(map[string]string)
 
-		if _, err := s.CreateOrUpdateResource(ctx, nsgSpec, serviceName); err != nil {
+		if err := s.checkExisting(nsgSpec); err != nil {
+			resErr = err
+		} else if _, err := s.CreateOrUpdateResource(ctx, nsgSpec, serviceName); err != nil {
 			if !azure.IsOperationNotDoneError(err) || resErr == nil {
 				resErr = err
 			}
@@ -145,6 +152,20 @@ func (s *Service) Delete(ctx context.Context) error {
 	// If multiple errors occur, we return the most pressing one.
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error deleting) -> operationNotDoneError (i.e. deleting in progress) -> no error (i.e. deleted)
 	for _, nsgSpec := range specs {
+		// Pre-existing security groups are left in place.
+		if spec, ok := nsgSpec.(*NSGSpec); ok && spec.Existing != nil {
+			continue
+		}
+		// A shared security group is only deleted along with the last cluster owning it.
+		if spec, ok := nsgSpec.(*NSGSpec); ok && spec.Shared {
+			deleteNSG, err := s.releaseSharedOwnership(ctx, spec)
+			if err != nil && (!azure.IsOperationNotDoneError(err) || result == nil) {
+				result = err
+			}
+			if !deleteNSG {
+				continue
+			}
+		}
 		if err := s.DeleteResource(ctx, nsgSpec, serviceName); err != nil {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
@@ -156,6 +177,48 @@ func (s *Service) Delete(ctx context.Context) error {
 	return result
 }
 
+// checkExisting returns a terminal error if a pre-existing security group can't be used by the cluster: it must be in
+// the subscription of the cluster, and not in its resource group, which is deleted with the cluster.
+func (s *Service) checkExisting(nsgSpec *NSGSpec) error {
+	if nsgSpec.Existing == nil {
+		return nil
+	}
+	resourceID, err := azureutil.ParseResourceID(nsgSpec.Existing.ID)
+	if err != nil {
+		return azure.WithTerminalError(errors.Wrapf(err, "failed to parse existing network security group ID %s", nsgSpec.Existing.ID))
+	}
+	if !strings.EqualFold(resourceID.SubscriptionID, s.Scope.SubscriptionID()) {
+		return azure.WithTerminalError(errors.Errorf("existing network security group %s must be in subscription %s", nsgSpec.Existing.ID, s.Scope.SubscriptionID()))
+	}
+	if strings.EqualFold(resourceID.ResourceGroupName, s.Scope.ResourceGroup()) {
+		return azure.WithTerminalError(errors.Errorf("existing network security group %s must not be in the resource group %s of the cluster", nsgSpec.Existing.ID, s.Scope.ResourceGroup()))
+	}
+	return nil
+}
+
+// releaseSharedOwnership releases the ownership of a shared security group by this cluster. It returns true if the
+// security group is to be deleted, i.e. this cluster is its last owner. A security group which isn't owned by this
+// cluster is never deleted.
+func (s *Service) releaseSharedOwnership(ctx context.Context, spec *NSGSpec) (bool, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "securitygroups.Service.releaseSharedOwnership")
+	defer done()
+
+	last, err := s.ownershipReleaser.ReleaseOwnership(ctx, spec, spec.ClusterName)
+	switch {
+	case azure.ResourceNotFound(err):
+		return false, nil
+	case azure.ResourceModified(err):
+		// Another cluster changed the security group since it was read, its owners are checked again at the next
+		// reconciliation.
+		return false, azure.WithTransientError(errors.Wrapf(err, "security group %s was modified concurrently", spec.Name), reconciler.DefaultReconcilerRequeue)
+	case err != nil:
+		return false, errors.Wrapf(err, "failed to release the ownership of security group %s", spec.Name)
+	case !last:
+		log.V(2).Info("Skipping security group deletion, it is not owned by this cluster only", "securityGroup", spec.Name)
+	}
+	return last, nil
+}
+
 // IsManaged returns true if the security groups' lifecycles are managed.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	_, _, done := tele.StartSpanWithLogger(ctx, "securitygroups.Service.IsManaged")
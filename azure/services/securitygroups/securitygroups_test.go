@@ -18,8 +18,11 @@ package securitygroups
 
 import (
 	"context"
+	"net/http"
+	"net/url"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -84,6 +87,18 @@ var (
 		DestinationPorts: ptr.To("80"),
 		Action:           infrav1.SecurityRuleActionAllow,
 	}
+	existingNSG = NSGSpec{
+		Name:        "shared-nsg",
+		Location:    "test-location",
+		ClusterName: "my-cluster",
+		SecurityRules: infrav1.SecurityRules{
+			securityRule1,
+		},
+		ResourceGroup: "security-rg",
+		Existing: &infrav1.ExistingSecurityGroup{
+			ID: "/subscriptions/123/resourceGroups/security-rg/providers/Microsoft.Network/networkSecurityGroups/shared-nsg",
+		},
+	}
 	errFake      = errors.New("this is an error")
 	notDoneError = azure.NewOperationNotDoneError(&infrav1.Future{})
 )
@@ -163,6 +178,42 @@ func TestReconcileSecurityGroups(t *testing.T) {
 				s.UpdatePutStatus(infrav1.SecurityGroupsReadyCondition, serviceName, notDoneError)
 			},
 		},
+		{
+			name:          "existing security group succeeds, should return no error",
+			expectedError: "",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.IsVnetManaged().Return(true)
+				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&existingNSG})
+				s.SubscriptionID().Return("123").AnyTimes()
+				s.ResourceGroup().Return("my-rg").AnyTimes()
+				s.UpdateAnnotationJSON(annotation, map[string]interface{}{existingNSG.Name: map[string]string{securityRule1.Name: securityRule1.Description}})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &existingNSG, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.SecurityGroupsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "existing security group in another subscription, should return terminal error",
+			expectedError: "reconcile error that cannot be recovered occurred: existing network security group /subscriptions/123/resourceGroups/security-rg/providers/Microsoft.Network/networkSecurityGroups/shared-nsg must be in subscription 456. Object will not be requeued",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.IsVnetManaged().Return(true)
+				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&existingNSG})
+				s.SubscriptionID().Return("456").AnyTimes()
+				s.UpdateAnnotationJSON(annotation, map[string]interface{}{existingNSG.Name: map[string]string{securityRule1.Name: securityRule1.Description}})
+				s.UpdatePutStatus(infrav1.SecurityGroupsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "existing security group in the cluster resource group, should return terminal error",
+			expectedError: "reconcile error that cannot be recovered occurred: existing network security group /subscriptions/123/resourceGroups/security-rg/providers/Microsoft.Network/networkSecurityGroups/shared-nsg must not be in the resource group security-rg of the cluster. Object will not be requeued",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.IsVnetManaged().Return(true)
+				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&existingNSG})
+				s.SubscriptionID().Return("123").AnyTimes()
+				s.ResourceGroup().Return("security-rg").AnyTimes()
+				s.UpdateAnnotationJSON(annotation, map[string]interface{}{existingNSG.Name: map[string]string{securityRule1.Name: securityRule1.Description}})
+				s.UpdatePutStatus(infrav1.SecurityGroupsReadyCondition, serviceName, gomock.Any())
+			},
+		},
 		{
 			name:          "vnet is not managed, should skip reconcile",
 			expectedError: "",
@@ -249,6 +300,16 @@ func TestDeleteSecurityGroups(t *testing.T) {
 				s.UpdateDeleteStatus(infrav1.SecurityGroupsReadyCondition, serviceName, notDoneError)
 			},
 		},
+		{
+			name:          "existing security groups are not deleted",
+			expectedError: "",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.IsVnetManaged().Return(true)
+				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&fakeNSG, &existingNSG})
+				r.DeleteResource(gomockinternal.AContext(), &fakeNSG, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.SecurityGroupsReadyCondition, serviceName, nil)
+			},
+		},
 		{
 			name:          "vnet is not managed, should skip delete",
 			expectedError: "",
@@ -286,6 +347,106 @@ func TestDeleteSecurityGroups(t *testing.T) {
 	}
 }
 
+func TestDeleteSharedSecurityGroups(t *testing.T) {
+	sharedNSG := fakeNSG
+	sharedNSG.Shared = true
+	preconditionFailedError := &azcore.ResponseError{
+		StatusCode: http.StatusPreconditionFailed,
+		RawResponse: &http.Response{
+			StatusCode: http.StatusPreconditionFailed,
+			Body:       http.NoBody,
+			Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{}},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_securitygroups.MockNSGScopeMockRecorder, o *mock_async.MockOwnershipReleaserMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "security group owned by other clusters is not deleted, only released",
+			expectedError: "",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, o *mock_async.MockOwnershipReleaserMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.IsVnetManaged().Return(true)
+				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&sharedNSG})
+				o.ReleaseOwnership(gomockinternal.AContext(), &sharedNSG, "my-cluster").Return(false, nil)
+				s.UpdateDeleteStatus(infrav1.SecurityGroupsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "security group owned by this cluster only is deleted",
+			expectedError: "",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, o *mock_async.MockOwnershipReleaserMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.IsVnetManaged().Return(true)
+				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&sharedNSG})
+				o.ReleaseOwnership(gomockinternal.AContext(), &sharedNSG, "my-cluster").Return(true, nil)
+				r.DeleteResource(gomockinternal.AContext(), &sharedNSG, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.SecurityGroupsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "security group which no longer exists is not deleted",
+			expectedError: "",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, o *mock_async.MockOwnershipReleaserMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.IsVnetManaged().Return(true)
+				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&sharedNSG})
+				o.ReleaseOwnership(gomockinternal.AContext(), &sharedNSG, "my-cluster").Return(false, &azcore.ResponseError{StatusCode: http.StatusNotFound})
+				s.UpdateDeleteStatus(infrav1.SecurityGroupsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "security group modified by another cluster while being released, should requeue",
+			expectedError: "security group test-nsg was modified concurrently: " + preconditionFailedError.Error() + ". Object will be requeued after 15s",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, o *mock_async.MockOwnershipReleaserMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.IsVnetManaged().Return(true)
+				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&sharedNSG})
+				o.ReleaseOwnership(gomockinternal.AContext(), &sharedNSG, "my-cluster").Return(false, preconditionFailedError)
+				s.UpdateDeleteStatus(infrav1.SecurityGroupsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "releasing the security group fails, should return an error",
+			expectedError: "failed to release the ownership of security group test-nsg: " + errFake.Error(),
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, o *mock_async.MockOwnershipReleaserMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.IsVnetManaged().Return(true)
+				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&sharedNSG})
+				o.ReleaseOwnership(gomockinternal.AContext(), &sharedNSG, "my-cluster").Return(false, errFake)
+				s.UpdateDeleteStatus(infrav1.SecurityGroupsReadyCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_securitygroups.NewMockNSGScope(mockCtrl)
+			ownershipReleaserMock := mock_async.NewMockOwnershipReleaser(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), ownershipReleaserMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:             scopeMock,
+				Reconciler:        reconcilerMock,
+				ownershipReleaser: ownershipReleaserMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 var (
 	ruleA = &armnetwork.SecurityRule{
 		Name: ptr.To("A"),
@@ -36,8 +36,19 @@ type NSGSpec struct {
 	ResourceGroup            string
 	AdditionalTags           infrav1.Tags
 	LastAppliedSecurityRules map[string]interface{}
+	// Existing is set for a pre-existing NSG, which is never created or deleted.
+	Existing *infrav1.ExistingSecurityGroup
+	// Shared is true if the NSG belongs to a shared vnet, in which case it can be used by several clusters which all
+	// tag it as owned.
+	Shared bool
+	// Adopt is true if an existing NSG which is not owned by any cluster is adopted by tagging it as owned.
+	Adopt bool
 }
 
+// ManagedRuleDescriptionPrefix prefixes the description of the security rules added to an existing NSG, so that
+// they can be told apart from the rules owned by someone else.
+const ManagedRuleDescriptionPrefix = "[capz-managed] "
+
 // ResourceName returns the name of the security group.
 func (s *NSGSpec) ResourceName() string {
 	return s.Name
@@ -58,6 +69,16 @@ func (s *NSGSpec) Parameters(ctx context.Context, existing interface{}) (interfa
 	securityRules := make([]*armnetwork.SecurityRule, 0)
 	newAnnotation := map[string]string{}
 	var etag *string
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.ClusterName,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        ptr.To(s.Name),
+		Additional:  s.AdditionalTags,
+	})
+
+	if s.Existing != nil {
+		return s.existingParameters(existing)
+	}
 
 	if existing != nil {
 		existingNSG, ok := existing.(armnetwork.SecurityGroup)
@@ -70,6 +91,16 @@ func (s *NSGSpec) Parameters(ctx context.Context, existing interface{}) (interfa
 		// Check if the expected rules are present
 		update := false
 
+		if s.Shared || (s.Adopt && len(converters.MapToTags(existingNSG.Tags).Owners()) == 0) {
+			// A shared security group keeps the tags of the other clusters owning it, and is joined by tagging it as
+			// owned by this cluster as well. A security group owned by no cluster keeps its tags when adopted.
+			existingTags := infrav1.Tags{}
+			existingTags.Merge(converters.MapToTags(existingNSG.Tags))
+			update = !existingTags.HasOwned(s.ClusterName)
+			existingTags.Merge(tags)
+			tags = existingTags
+		}
+
 		for _, rule := range s.SecurityRules {
 			sdkRule := converters.SecurityRuleToSDK(rule)
 			if !ruleExists(existingNSG.Properties.SecurityRules, sdkRule) {
@@ -109,15 +140,70 @@ func (s *NSGSpec) Parameters(ctx context.Context, existing interface{}) (interfa
 			SecurityRules: securityRules,
 		},
 		Etag: etag,
-		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
-			ClusterName: s.ClusterName,
-			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Name:        ptr.To(s.Name),
-			Additional:  s.AdditionalTags,
-		})),
+		Tags: converters.TagsToMap(tags),
 	}, nil
 }
 
+// existingParameters returns the parameters for a pre-existing security group. Only the rules CAPZ requires are added,
+// marked with ManagedRuleDescriptionPrefix, and only the rules so marked that CAPZ applied last are removed.
+func (s *NSGSpec) existingParameters(existing interface{}) (interface{}, error) {
+	if existing == nil {
+		return nil, errors.Errorf("existing network security group %s not found", s.Existing.ID)
+	}
+	existingNSG, ok := existing.(armnetwork.SecurityGroup)
+	if !ok {
+		return nil, errors.Errorf("%T is not a network.SecurityGroup", existing)
+	}
+	if s.Existing.RuleManagement == infrav1.SecurityRuleManagementNone {
+		return nil, nil
+	}
+
+	var existingRules []*armnetwork.SecurityRule
+	if existingNSG.Properties != nil {
+		existingRules = existingNSG.Properties.SecurityRules
+	}
+
+	update := false
+	securityRules := make([]*armnetwork.SecurityRule, 0, len(existingRules)+len(s.SecurityRules))
+	desired := make(map[string]bool, len(s.SecurityRules))
+	for _, rule := range s.SecurityRules {
+		rule.Description = ManagedRuleDescriptionPrefix + rule.Description
+		sdkRule := converters.SecurityRuleToSDK(rule)
+		if !ruleExists(existingRules, sdkRule) {
+			update = true
+			securityRules = append(securityRules, sdkRule)
+		}
+		desired[rule.Name] = true
+	}
+
+	for _, oldRule := range existingRules {
+		name := ptr.Deref(oldRule.Name, "")
+		_, tracked := s.LastAppliedSecurityRules[name]
+		if tracked && !desired[name] && isManagedRule(oldRule) {
+			update = true
+			continue
+		}
+		securityRules = append(securityRules, oldRule)
+	}
+
+	if !update {
+		return nil, nil
+	}
+
+	// The other properties and the tags of the NSG are kept, and its etag ensures rules added concurrently are not
+	// overwritten.
+	if existingNSG.Properties == nil {
+		existingNSG.Properties = &armnetwork.SecurityGroupPropertiesFormat{}
+	}
+	existingNSG.Properties.SecurityRules = securityRules
+	return existingNSG, nil
+}
+
+// isManagedRule returns true if the security rule was added to an existing NSG by CAPZ.
+func isManagedRule(rule *armnetwork.SecurityRule) bool {
+	return rule.Properties != nil && strings.HasPrefix(ptr.Deref(rule.Properties.Description, ""), ManagedRuleDescriptionPrefix)
+}
+
 // TODO: review this logic and make sure it is what we want. It seems incorrect to skip rules that don't have a certain protocol, etc.
 func ruleExists(rules []*armnetwork.SecurityRule, rule *armnetwork.SecurityRule) bool {
 	for _, existingRule := range rules {
@@ -76,8 +76,14 @@ var (
 		DestinationPorts: ptr.To("80"),
 		Action:           infrav1.SecurityRuleActionDeny,
 	}
+	existingNSGID = "/subscriptions/123/resourceGroups/security-rg/providers/Microsoft.Network/networkSecurityGroups/shared-nsg"
 )
 
+func managedRuleToSDK(rule infrav1.SecurityRule) *armnetwork.SecurityRule {
+	rule.Description = ManagedRuleDescriptionPrefix + rule.Description
+	return converters.SecurityRuleToSDK(rule)
+}
+
 func TestParameters(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -321,6 +327,74 @@ func TestParameters(t *testing.T) {
 				g.Expect(result).To(BeNil())
 			},
 		},
+		{
+			name: "shared NSG owned by another cluster is tagged as owned",
+			spec: &NSGSpec{
+				Name:     "test-nsg",
+				Location: "test-location",
+				SecurityRules: infrav1.SecurityRules{
+					sshRule,
+				},
+				ResourceGroup: "test-group",
+				ClusterName:   "my-cluster",
+				Shared:        true,
+			},
+			existing: armnetwork.SecurityGroup{
+				Name: ptr.To("test-nsg"),
+				Etag: ptr.To("fake-etag"),
+				Tags: map[string]*string{
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_other-cluster": ptr.To("owned"),
+				},
+				Properties: &armnetwork.SecurityGroupPropertiesFormat{
+					SecurityRules: []*armnetwork.SecurityRule{
+						converters.SecurityRuleToSDK(sshRule),
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.SecurityGroup{}))
+				g.Expect(result.(armnetwork.SecurityGroup).Tags).To(Equal(map[string]*string{
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_other-cluster": ptr.To("owned"),
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster":    ptr.To("owned"),
+					"Name": ptr.To("test-nsg"),
+				}))
+				g.Expect(result.(armnetwork.SecurityGroup).Properties.SecurityRules).To(HaveLen(1))
+			},
+		},
+		{
+			name: "untagged NSG is adopted",
+			spec: &NSGSpec{
+				Name:     "test-nsg",
+				Location: "test-location",
+				SecurityRules: infrav1.SecurityRules{
+					sshRule,
+				},
+				ResourceGroup: "test-group",
+				ClusterName:   "my-cluster",
+				Adopt:         true,
+			},
+			existing: armnetwork.SecurityGroup{
+				Name: ptr.To("test-nsg"),
+				Etag: ptr.To("fake-etag"),
+				Tags: map[string]*string{
+					"team": ptr.To("networking"),
+				},
+				Properties: &armnetwork.SecurityGroupPropertiesFormat{
+					SecurityRules: []*armnetwork.SecurityRule{
+						converters.SecurityRuleToSDK(sshRule),
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.SecurityGroup{}))
+				g.Expect(result.(armnetwork.SecurityGroup).Tags).To(Equal(map[string]*string{
+					"team": ptr.To("networking"),
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+					"Name": ptr.To("test-nsg"),
+				}))
+				g.Expect(result.(armnetwork.SecurityGroup).Properties.SecurityRules).To(HaveLen(1))
+			},
+		},
 		{
 			name: "NSG does not exist",
 			spec: &NSGSpec{
@@ -351,6 +425,107 @@ func TestParameters(t *testing.T) {
 				}))
 			},
 		},
+		{
+			name: "existing NSG does not exist",
+			spec: &NSGSpec{
+				Name:          "shared-nsg",
+				ResourceGroup: "security-rg",
+				Existing:      &infrav1.ExistingSecurityGroup{ID: existingNSGID},
+			},
+			existing:      nil,
+			expectedError: "existing network security group " + existingNSGID + " not found",
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "existing NSG with rule management disabled",
+			spec: &NSGSpec{
+				Name:          "shared-nsg",
+				ResourceGroup: "security-rg",
+				SecurityRules: infrav1.SecurityRules{sshRule},
+				Existing: &infrav1.ExistingSecurityGroup{
+					ID:             existingNSGID,
+					RuleManagement: infrav1.SecurityRuleManagementNone,
+				},
+			},
+			existing: armnetwork.SecurityGroup{
+				Name:       ptr.To("shared-nsg"),
+				Properties: &armnetwork.SecurityGroupPropertiesFormat{},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "existing NSG missing a rule",
+			spec: &NSGSpec{
+				Name:          "shared-nsg",
+				ResourceGroup: "security-rg",
+				ClusterName:   "my-cluster",
+				SecurityRules: infrav1.SecurityRules{sshRule},
+				Existing:      &infrav1.ExistingSecurityGroup{ID: existingNSGID},
+			},
+			existing: armnetwork.SecurityGroup{
+				ID:       ptr.To(existingNSGID),
+				Name:     ptr.To("shared-nsg"),
+				Location: ptr.To("test-location"),
+				Etag:     ptr.To("fake-etag"),
+				Tags:     map[string]*string{"team": ptr.To("networking")},
+				Properties: &armnetwork.SecurityGroupPropertiesFormat{
+					SecurityRules: []*armnetwork.SecurityRule{
+						converters.SecurityRuleToSDK(customRule),
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armnetwork.SecurityGroup{
+					ID:       ptr.To(existingNSGID),
+					Name:     ptr.To("shared-nsg"),
+					Location: ptr.To("test-location"),
+					Etag:     ptr.To("fake-etag"),
+					Tags:     map[string]*string{"team": ptr.To("networking")},
+					Properties: &armnetwork.SecurityGroupPropertiesFormat{
+						SecurityRules: []*armnetwork.SecurityRule{
+							managedRuleToSDK(sshRule),
+							converters.SecurityRuleToSDK(customRule),
+						},
+					},
+				}))
+			},
+		},
+		{
+			name: "existing NSG with a removed rule",
+			spec: &NSGSpec{
+				Name:          "shared-nsg",
+				ResourceGroup: "security-rg",
+				ClusterName:   "my-cluster",
+				LastAppliedSecurityRules: map[string]interface{}{
+					"other_rule":  "Test Rule",
+					"custom_rule": "Test Rule",
+				},
+				Existing: &infrav1.ExistingSecurityGroup{ID: existingNSGID},
+			},
+			existing: armnetwork.SecurityGroup{
+				Name: ptr.To("shared-nsg"),
+				Properties: &armnetwork.SecurityGroupPropertiesFormat{
+					SecurityRules: []*armnetwork.SecurityRule{
+						managedRuleToSDK(otherRule),
+						converters.SecurityRuleToSDK(customRule),
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armnetwork.SecurityGroup{
+					Name: ptr.To("shared-nsg"),
+					Properties: &armnetwork.SecurityGroupPropertiesFormat{
+						SecurityRules: []*armnetwork.SecurityRule{
+							converters.SecurityRuleToSDK(customRule),
+						},
+					},
+				}))
+			},
+		},
 	}
 
 	for _, tc := range testcases {
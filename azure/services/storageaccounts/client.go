@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageaccounts
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	accounts *armstorage.AccountsClient
+}
+
+// NewClient creates a new storage accounts client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create storageaccounts client options")
+	}
+	accountsClient, err := armstorage.NewAccountsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armstorage accounts client")
+	}
+	return &AzureClient{accountsClient}, nil
+}
+
+// Get gets a storage account.
+func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "storageaccounts.AzureClient.Get")
+	defer done()
+
+	resp, err := ac.accounts.GetProperties(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Account, nil
+}
+
+// CreateOrUpdateAsync creates a storage account asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a Poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armstorage.AccountsClientCreateResponse], err error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "storageaccounts.AzureClient.CreateOrUpdateAsync")
+	defer done()
+
+	account, ok := parameters.(armstorage.AccountCreateParameters)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armstorage.AccountCreateParameters", parameters)
+	}
+
+	opts := &armstorage.AccountsClientBeginCreateOptions{ResumeToken: resumeToken}
+	log.V(4).Info("sending request", "resumeToken", resumeToken)
+	poller, err = ac.accounts.BeginCreate(ctx, spec.ResourceGroupName(), spec.ResourceName(), account, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	resp, err := poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// If an error occurs, return the poller.
+		return nil, poller, err
+	}
+
+	// if the operation completed, return a nil poller
+	return resp.Account, nil, err
+}
+
+// DeleteAsync deletes a storage account. DeleteAsync sends a DELETE request to Azure and if accepted without error,
+// the func will return a Poller which can be used to track the ongoing progress of the operation.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, _resumeToken string) (poller *runtime.Poller[armstorage.AccountsClientDeleteResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "storageaccounts.AzureClient.DeleteAsync")
+	defer done()
+
+	// Note: there is no async `BeginDelete` implementation for storage accounts, so this func will never return a poller.
+	_, err = ac.accounts.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, err
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageaccounts
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// StorageAccountSpec defines the specification for the storage account holding bootstrap data.
+type StorageAccountSpec struct {
+	Name           string
+	ResourceGroup  string
+	ClusterName    string
+	Location       string
+	AdditionalTags infrav1.Tags
+	// HTTPSTrafficOnly, MinimumTLSVersion and AllowBlobPublicAccess are reconciled on existing storage accounts.
+	HTTPSTrafficOnly      bool
+	MinimumTLSVersion     armstorage.MinimumTLSVersion
+	AllowBlobPublicAccess bool
+}
+
+// ResourceName returns the name of the storage account.
+func (s *StorageAccountSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *StorageAccountSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for storage accounts.
+func (s *StorageAccountSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the storage account.
+func (s *StorageAccountSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		existingAccount, ok := existing.(armstorage.Account)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armstorage.Account", existing)
+		}
+		if s.isUpToDate(existingAccount) {
+			// storage account already exists with the expected settings
+			return nil, nil
+		}
+	}
+
+	return armstorage.AccountCreateParameters{
+		Kind: ptr.To(armstorage.KindStorageV2),
+		SKU: &armstorage.SKU{
+			Name: ptr.To(armstorage.SKUNameStandardLRS),
+		},
+		Location: ptr.To(s.Location),
+		Properties: &armstorage.AccountPropertiesCreateParameters{
+			AllowBlobPublicAccess:  ptr.To(s.AllowBlobPublicAccess),
+			EnableHTTPSTrafficOnly: ptr.To(s.HTTPSTrafficOnly),
+			MinimumTLSVersion:      ptr.To(s.MinimumTLSVersion),
+			Encryption: &armstorage.Encryption{
+				KeySource:                       ptr.To(armstorage.KeySourceMicrosoftStorage),
+				RequireInfrastructureEncryption: ptr.To(true),
+				Services: &armstorage.EncryptionServices{
+					Blob: &armstorage.EncryptionService{
+						Enabled: ptr.To(true),
+						KeyType: ptr.To(armstorage.KeyTypeAccount),
+					},
+				},
+			},
+		},
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Role:        ptr.To(infrav1.CommonRole),
+			Additional:  s.AdditionalTags,
+		})),
+	}, nil
+}
+
+// isUpToDate returns true if the encryption in transit and public access settings of an existing storage account
+// match the spec.
+func (s *StorageAccountSpec) isUpToDate(existing armstorage.Account) bool {
+	props := existing.Properties
+	if props == nil {
+		return false
+	}
+	return ptr.Deref(props.EnableHTTPSTrafficOnly, false) == s.HTTPSTrafficOnly &&
+		ptr.Deref(props.MinimumTLSVersion, "") == s.MinimumTLSVersion &&
+		ptr.Deref(props.AllowBlobPublicAccess, false) == s.AllowBlobPublicAccess
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageaccounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	params, err := fakeAccountSpec.Parameters(context.TODO(), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	account, ok := params.(armstorage.AccountCreateParameters)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(account.Location).To(Equal(ptr.To("test-location")))
+	g.Expect(account.Properties.AllowBlobPublicAccess).To(Equal(ptr.To(false)))
+	g.Expect(account.Properties.EnableHTTPSTrafficOnly).To(Equal(ptr.To(true)))
+	g.Expect(account.Properties.MinimumTLSVersion).To(Equal(ptr.To(armstorage.MinimumTLSVersionTLS12)))
+	g.Expect(account.Properties.Encryption.RequireInfrastructureEncryption).To(Equal(ptr.To(true)))
+	g.Expect(account.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster", ptr.To("owned")))
+
+	params, err = fakeAccountSpec.Parameters(context.TODO(), armstorage.Account{
+		Properties: &armstorage.AccountProperties{
+			AllowBlobPublicAccess:  ptr.To(false),
+			EnableHTTPSTrafficOnly: ptr.To(true),
+			MinimumTLSVersion:      ptr.To(armstorage.MinimumTLSVersionTLS12),
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+
+	// Settings changed outside of CAPZ are reverted.
+	params, err = fakeAccountSpec.Parameters(context.TODO(), armstorage.Account{
+		Properties: &armstorage.AccountProperties{
+			AllowBlobPublicAccess:  ptr.To(true),
+			EnableHTTPSTrafficOnly: ptr.To(false),
+			MinimumTLSVersion:      ptr.To(armstorage.MinimumTLSVersionTLS10),
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	account, ok = params.(armstorage.AccountCreateParameters)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(account.Properties.AllowBlobPublicAccess).To(Equal(ptr.To(false)))
+	g.Expect(account.Properties.EnableHTTPSTrafficOnly).To(Equal(ptr.To(true)))
+	g.Expect(account.Properties.MinimumTLSVersion).To(Equal(ptr.To(armstorage.MinimumTLSVersionTLS12)))
+
+	// The settings of the spec are applied.
+	spec := fakeAccountSpec
+	spec.MinimumTLSVersion = armstorage.MinimumTLSVersionTLS11
+	params, err = spec.Parameters(context.TODO(), armstorage.Account{
+		Properties: &armstorage.AccountProperties{
+			AllowBlobPublicAccess:  ptr.To(false),
+			EnableHTTPSTrafficOnly: ptr.To(true),
+			MinimumTLSVersion:      ptr.To(armstorage.MinimumTLSVersionTLS12),
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	account, ok = params.(armstorage.AccountCreateParameters)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(account.Properties.MinimumTLSVersion).To(Equal(ptr.To(armstorage.MinimumTLSVersionTLS11)))
+
+	_, err = fakeAccountSpec.Parameters(context.TODO(), "not an account")
+	g.Expect(err).To(HaveOccurred())
+}
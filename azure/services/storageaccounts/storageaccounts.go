@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageaccounts
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "storageaccounts"
+
+// StorageAccountScope defines the scope interface for a storage accounts service.
+type StorageAccountScope interface {
+	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
+	StorageAccountSpec() azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope StorageAccountScope
+	async.Reconciler
+}
+
+// New creates a new storage accounts service.
+func New(scope StorageAccountScope) (*Service, error) {
+	client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope: scope,
+		Reconciler: async.New[armstorage.AccountsClientCreateResponse,
+			armstorage.AccountsClientDeleteResponse](scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile idempotently creates the storage account holding bootstrap data.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "storageaccounts.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.StorageAccountSpec()
+	if spec == nil {
+		log.V(2).Info("skip creation when no storage account spec is found")
+		return nil
+	}
+
+	_, err := s.CreateOrUpdateResource(ctx, spec, serviceName)
+	s.Scope.UpdatePutStatus(infrav1.StorageAccountReadyCondition, serviceName, err)
+	return err
+}
+
+// Delete deletes the storage account holding bootstrap data.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "storageaccounts.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.StorageAccountSpec()
+	if spec == nil {
+		log.V(2).Info("skip deletion when no storage account spec is found")
+		return nil
+	}
+
+	err := s.DeleteResource(ctx, spec, serviceName)
+	s.Scope.UpdateDeleteStatus(infrav1.StorageAccountReadyCondition, serviceName, err)
+	return err
+}
+
+// IsManaged always returns true as CAPZ does not support a BYO storage account for bootstrap data.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageaccounts
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/storageaccounts/mock_storageaccounts"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeAccountSpec = StorageAccountSpec{
+		Name:          "capzboottest",
+		ResourceGroup: "test-rg",
+		ClusterName:   "test-cluster",
+		Location:      "test-location",
+
+		HTTPSTrafficOnly:  true,
+		MinimumTLSVersion: armstorage.MinimumTLSVersionTLS12,
+	}
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func TestReconcileStorageAccounts(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name: "create storage account",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.StorageAccountSpec().Return(&fakeAccountSpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeAccountSpec, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.StorageAccountReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name: "noop if no bootstrap data storage is configured",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.StorageAccountSpec().Return(nil)
+			},
+		},
+		{
+			name:          "error in creating storage account",
+			expectedError: "#: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.StorageAccountSpec().Return(&fakeAccountSpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeAccountSpec, serviceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.StorageAccountReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_storageaccounts.NewMockStorageAccountScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteStorageAccounts(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name: "delete storage account",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.StorageAccountSpec().Return(&fakeAccountSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeAccountSpec, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.StorageAccountReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name: "noop if no bootstrap data storage is configured",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.StorageAccountSpec().Return(nil)
+			},
+		},
+		{
+			name:          "error in deleting storage account",
+			expectedError: "#: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.StorageAccountSpec().Return(&fakeAccountSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeAccountSpec, serviceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.StorageAccountReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_storageaccounts.NewMockStorageAccountScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
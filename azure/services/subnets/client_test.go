@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subnets
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/fakearm"
+)
+
+func TestSubnetsAgainstFakeARM(t *testing.T) {
+	g := NewWithT(t)
+
+	server := fakearm.NewServer()
+	defer server.Close()
+	server.Register()
+
+	client, err := NewClient(server.Authorizer("123"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	mockCtrl := gomock.NewController(t)
+	scope := mock_async.NewMockFutureScope(mockCtrl)
+	scope.EXPECT().GetLongRunningOperationState(gomock.Any(), serviceName, gomock.Any()).Return(nil).AnyTimes()
+	scope.EXPECT().DeleteLongRunningOperationState(gomock.Any(), serviceName, gomock.Any()).AnyTimes()
+	reconciler := async.New[armnetwork.SubnetsClientCreateOrUpdateResponse, armnetwork.SubnetsClientDeleteResponse](scope, client, client)
+
+	spec := &SubnetSpec{
+		Name:              "my-subnet",
+		ResourceGroup:     "my-rg",
+		SubscriptionID:    "123",
+		CIDRs:             []string{"10.0.0.0/16"},
+		VNetName:          "my-vnet",
+		VNetResourceGroup: "my-rg",
+		IsVNetManaged:     true,
+		Role:              infrav1.SubnetNode,
+	}
+	id := azure.SubnetID("123", "my-rg", "my-vnet", "my-subnet")
+
+	result, err := reconciler.CreateOrUpdateResource(context.Background(), spec, serviceName)
+	g.Expect(err).NotTo(HaveOccurred())
+	subnet, ok := result.(armnetwork.Subnet)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(subnet.ID).To(Equal(ptr.To(id)))
+	g.Expect(subnet.Properties.AddressPrefix).To(Equal(ptr.To("10.0.0.0/16")))
+	g.Expect(subnet.Properties.ProvisioningState).To(Equal(ptr.To(armnetwork.ProvisioningStateSucceeded)))
+	g.Expect(server.Requests()).To(Equal([]string{"GET " + id, "PUT " + id}))
+
+	// An up to date subnet is not updated again.
+	_, err = reconciler.CreateOrUpdateResource(context.Background(), spec, serviceName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(server.Requests()).To(HaveLen(3))
+
+	server.InjectError(http.MethodDelete, id, http.StatusConflict, "InUseSubnetCannotBeDeleted")
+	err = reconciler.DeleteResource(context.Background(), spec, serviceName)
+	g.Expect(err).To(MatchError(ContainSubstring("InUseSubnetCannotBeDeleted")))
+	_, ok = server.Get(id)
+	g.Expect(ok).To(BeTrue())
+
+	g.Expect(reconciler.DeleteResource(context.Background(), spec, serviceName)).To(Succeed())
+	_, ok = server.Get(id)
+	g.Expect(ok).To(BeFalse())
+
+	// Deleting a subnet that is already gone succeeds.
+	g.Expect(reconciler.DeleteResource(context.Background(), spec, serviceName)).To(Succeed())
+}
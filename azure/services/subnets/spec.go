@@ -38,9 +38,11 @@ type SubnetSpec struct {
 	IsVNetManaged     bool
 	RouteTableName    string
 	SecurityGroupName string
-	Role              infrav1.SubnetRole
-	NatGatewayName    string
-	ServiceEndpoints  infrav1.ServiceEndpoints
+	// SecurityGroupRG is the resource group of the security group, if different from the resource group of the VNet.
+	SecurityGroupRG  string
+	Role             infrav1.SubnetRole
+	NatGatewayName   string
+	ServiceEndpoints infrav1.ServiceEndpoints
 }
 
 // ResourceName returns the name of the subnet.
@@ -86,9 +88,11 @@ func (s *SubnetSpec) Parameters(ctx context.Context, existing interface{}) (para
 		}
 	}
 
+	// Route tables and network security groups are created in the resource group of the VNet, which may differ from
+	// the cluster resource group.
 	if s.RouteTableName != "" {
 		subnetProperties.RouteTable = &armnetwork.RouteTable{
-			ID: ptr.To(azure.RouteTableID(s.SubscriptionID, s.ResourceGroup, s.RouteTableName)),
+			ID: ptr.To(azure.RouteTableID(s.SubscriptionID, s.VNetResourceGroup, s.RouteTableName)),
 		}
 	}
 
@@ -99,8 +103,12 @@ func (s *SubnetSpec) Parameters(ctx context.Context, existing interface{}) (para
 	}
 
 	if s.SecurityGroupName != "" {
+		securityGroupRG := s.VNetResourceGroup
+		if s.SecurityGroupRG != "" {
+			securityGroupRG = s.SecurityGroupRG
+		}
 		subnetProperties.NetworkSecurityGroup = &armnetwork.SecurityGroup{
-			ID: ptr.To(azure.SecurityGroupID(s.SubscriptionID, s.ResourceGroup, s.SecurityGroupName)),
+			ID: ptr.To(azure.SecurityGroupID(s.SubscriptionID, securityGroupRG, s.SecurityGroupName)),
 		}
 	}
 
@@ -50,6 +50,29 @@ var (
 		},
 	}
 
+	fakeSubnetSeparateNetworkRGSpec = SubnetSpec{
+		Name:              "my-subnet-1",
+		ResourceGroup:     "my-rg",
+		SubscriptionID:    "123",
+		CIDRs:             []string{"10.0.0.0/16"},
+		IsVNetManaged:     true,
+		VNetName:          "my-vnet",
+		VNetResourceGroup: "my-network-rg",
+		RouteTableName:    "my-subnet_route_table",
+		SecurityGroupName: "my-sg",
+		NatGatewayName:    "my-nat-gateway",
+		Role:              infrav1.SubnetNode,
+	}
+
+	fakeSubnetSeparateNetworkRGParams = armnetwork.Subnet{
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefix:        ptr.To("10.0.0.0/16"),
+			RouteTable:           &armnetwork.RouteTable{ID: ptr.To("/subscriptions/123/resourceGroups/my-network-rg/providers/Microsoft.Network/routeTables/my-subnet_route_table")},
+			NetworkSecurityGroup: &armnetwork.SecurityGroup{ID: ptr.To("/subscriptions/123/resourceGroups/my-network-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
+			NatGateway:           &armnetwork.SubResource{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-nat-gateway")},
+		},
+	}
+
 	fakeSubnetMultipleCidrSpec = SubnetSpec{
 		Name:              "my-subnet-1",
 		ResourceGroup:     "my-rg",
@@ -130,6 +153,15 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "get parameters for subnet in a separate network resource group",
+			spec:     &fakeSubnetSeparateNetworkRGSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(fakeSubnetSeparateNetworkRGParams))
+			},
+			expectedError: "",
+		},
 		{
 			name:     "error vnet is not managed but subnet is missing",
 			spec:     &fakeSubnetSpecNotManaged,
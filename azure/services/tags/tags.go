@@ -18,16 +18,19 @@ package tags
 
 import (
 	"context"
+	"sort"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/pkg/errors"
 	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
-const serviceName = "tags"
+// ServiceName is the name of this service.
+const ServiceName = "tags"
 
 // TagScope defines the scope interface for a tags service.
 type TagScope interface {
@@ -58,7 +61,7 @@ func New(scope TagScope) (*Service, error) {
 
 // Name returns the service name.
 func (s *Service) Name() string {
-	return serviceName
+	return ServiceName
 }
 
 // Some resource types are always assumed to be managed by CAPZ whether or not
@@ -67,6 +70,7 @@ func (s *Service) Name() string {
 // interpreted as managed.
 var alwaysManagedAnnotations = map[string]struct{}{
 	azure.ManagedClusterTagsLastAppliedAnnotation: {},
+	azure.OSDiskTagsLastAppliedAnnotation:         {},
 }
 
 // Reconcile ensures tags are correct.
@@ -76,6 +80,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	for _, tagsSpec := range s.Scope.TagsSpecs() {
 		existingTags, err := s.client.GetAtScope(ctx, tagsSpec.Scope)
+		if azure.ResourceNotFound(err) {
+			log.V(4).Info("Skipping tags reconcile for resource that does not exist yet", "scope", tagsSpec.Scope)
+			continue
+		}
 		if err != nil {
 			return errors.Wrap(err, "failed to get existing tags")
 		}
@@ -94,6 +102,25 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			return err
 		}
 		changed, createdOrUpdated, deleted, newAnnotation := TagsChanged(lastAppliedTags, tagsSpec.Tags, tags)
+		if plan := azure.PlanFromContext(ctx); plan != nil {
+			if changed {
+				log.V(2).Info("planning operation", "operation", infrav1.PlannedOperationUpdate, "service", ServiceName, "resource", tagsSpec.Scope)
+				plan.Record(infrav1.PlannedOperationUpdate, ServiceName, "", tagsSpec.Scope)
+			}
+			continue
+		}
+		if drift := azure.DriftFromContext(ctx); drift != nil {
+			if drifted := driftedTags(lastAppliedTags, tagsSpec.Tags, tags); len(drifted) > 0 {
+				log.V(2).Info("tags changed out-of-band", "scope", tagsSpec.Scope, "tags", drifted)
+				drift.Record(ServiceName, "", tagsSpec.Scope)
+				if !drift.Correct {
+					for _, t := range drifted {
+						delete(createdOrUpdated, t)
+					}
+					changed = len(createdOrUpdated) > 0 || len(deleted) > 0
+				}
+			}
+		}
 		if changed {
 			log.V(2).Info("Updating tags")
 			if len(createdOrUpdated) > 0 {
@@ -208,6 +235,23 @@ func TagsChanged(lastAppliedTags map[string]interface{}, desiredTags map[string]
 	return changed, createdOrUpdated, deleted, newAnnotation
 }
 
+// driftedTags returns the desired tags which were applied last and no longer have their desired value, i.e. which
+// were changed or deleted out-of-band.
+func driftedTags(lastAppliedTags map[string]interface{}, desiredTags map[string]string, currentTags map[string]*string) []string {
+	var drifted []string
+	for t, v := range desiredTags {
+		if lastApplied, ok := lastAppliedTags[t]; !ok || lastApplied != v {
+			// The tag is new or its desired value changed.
+			continue
+		}
+		if current, ok := currentTags[t]; !ok || *current != v {
+			drifted = append(drifted, t)
+		}
+	}
+	sort.Strings(drifted)
+	return drifted
+}
+
 // IsManaged returns always returns true as CAPZ does not support BYO tags.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	return true, nil
@@ -18,6 +18,7 @@ package tags
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -26,6 +27,7 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags/mock_tags"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
@@ -199,6 +201,23 @@ func TestReconcileTags(t *testing.T) {
 				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error"))
 			},
 		},
+		{
+			name:          "skip resources that do not exist yet",
+			expectedError: "",
+			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.TagsSpecs().Return([]azure.TagsSpec{
+					{
+						Scope: "/sub/123/fake/scope",
+						Tags: map[string]string{
+							"foo": "bar",
+						},
+						Annotation: "my-annotation",
+					},
+				})
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "Not Found"))
+			},
+		},
 		{
 			name:          "error updating tags",
 			expectedError: "cannot update tags: #: Internal Server Error: StatusCode=500",
@@ -283,6 +302,86 @@ func TestReconcileTags(t *testing.T) {
 	}
 }
 
+func TestReconcileTagsPlanMode(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	scopeMock := mock_tags.NewMockTagScope(mockCtrl)
+	clientMock := mock_tags.NewMockclient(mockCtrl)
+
+	scopeMock.EXPECT().ClusterName().AnyTimes().Return("test-cluster")
+	scopeMock.EXPECT().TagsSpecs().Return([]azure.TagsSpec{
+		{
+			Scope:      "/sub/123/fake/scope",
+			Tags:       map[string]string{"foo": "bar"},
+			Annotation: "my-annotation",
+		},
+	})
+	clientMock.EXPECT().GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{Properties: &armresources.Tags{
+		Tags: map[string]*string{
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": ptr.To("owned"),
+		},
+	}}, nil)
+	scopeMock.EXPECT().AnnotationJSON("my-annotation")
+
+	s := &Service{
+		Scope:  scopeMock,
+		client: clientMock,
+	}
+
+	plan := &azure.Plan{}
+	g.Expect(s.Reconcile(azure.WithPlan(context.TODO(), plan))).To(Succeed())
+	g.Expect(plan.Operations()).To(Equal([]infrav1.PlannedOperation{
+		{Type: infrav1.PlannedOperationUpdate, Service: ServiceName, Name: "/sub/123/fake/scope"},
+	}))
+}
+
+func TestReconcileTagsDrift(t *testing.T) {
+	for _, correct := range []bool{false, true} {
+		correct := correct
+		t.Run(fmt.Sprintf("correct %t", correct), func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			scopeMock := mock_tags.NewMockTagScope(mockCtrl)
+			clientMock := mock_tags.NewMockclient(mockCtrl)
+
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("test-cluster")
+			scopeMock.EXPECT().TagsSpecs().Return([]azure.TagsSpec{
+				{
+					Scope:      "/sub/123/fake/scope",
+					Tags:       map[string]string{"foo": "bar", "new": "tag"},
+					Annotation: "my-annotation",
+				},
+			})
+			clientMock.EXPECT().GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{Properties: &armresources.Tags{
+				Tags: map[string]*string{
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": ptr.To("owned"),
+					"foo": ptr.To("changed"),
+				},
+			}}, nil)
+			scopeMock.EXPECT().AnnotationJSON("my-annotation").Return(map[string]interface{}{"foo": "bar"}, nil)
+			// The new tag comes from a spec change and is applied with either policy, the drifted tag only when correcting drift.
+			wantTags := map[string]*string{"new": ptr.To("tag")}
+			if correct {
+				wantTags["foo"] = ptr.To("bar")
+			}
+			clientMock.EXPECT().UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", armresources.TagsPatchResource{
+				Operation:  ptr.To(armresources.TagsPatchOperationMerge),
+				Properties: &armresources.Tags{Tags: wantTags},
+			})
+			scopeMock.EXPECT().UpdateAnnotationJSON("my-annotation", map[string]interface{}{"foo": "bar", "new": "tag"})
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			drift := &azure.Drift{Correct: correct}
+			g.Expect(s.Reconcile(azure.WithDrift(context.TODO(), drift))).To(Succeed())
+			g.Expect(drift.Resources()).To(Equal([]string{"/sub/123/fake/scope (tags)"}))
+		})
+	}
+}
+
 func TestTagsChanged(t *testing.T) {
 	g := NewWithT(t)
 
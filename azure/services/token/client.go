@@ -26,6 +26,11 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// Client gets azure active directory tokens.
+type Client interface {
+	GetAzureActiveDirectoryToken(ctx context.Context, resourceID string) (string, error)
+}
+
 // AzureClient to get azure active directory token.
 type AzureClient struct {
 	aadToken *azidentity.ClientSecretCredential
@@ -72,3 +77,5 @@ func (ac *AzureClient) GetAzureActiveDirectoryToken(ctx context.Context, resourc
 	}
 	return spnAccessToken.Token, nil
 }
+
+var _ Client = (*AzureClient)(nil)
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachineimages
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// imageLookupParams are the fields available to the format of an image lookup.
+type imageLookupParams struct {
+	BaseOS     string
+	Variant    string
+	K8sVersion string
+}
+
+// LookupImage returns the compute gallery image of a Kubernetes version found with an image lookup, in the given
+// variant if any. The image definition is named after the format of the lookup, and its version is the Kubernetes
+// version, so that each Kubernetes release is published as a version of the image definitions of the gallery.
+func LookupImage(lookup *infrav1.ImageLookup, variant infrav1.ImageVariant, k8sVersion string) (*infrav1.Image, error) {
+	v, err := semver.ParseTolerant(k8sVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse Kubernetes version \"%s\"", k8sVersion)
+	}
+
+	baseOS := lookup.BaseOS
+	if baseOS == "" {
+		baseOS = fmt.Sprintf("ubuntu-%s", getUbuntuOSVersion(v.Major, v.Minor, v.Patch))
+	}
+	format := lookup.Format
+	if format == "" {
+		format = infrav1.DefaultImageLookupFormat
+	}
+	tmpl, err := template.New("format").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse image lookup format %q", format)
+	}
+
+	version := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	var name strings.Builder
+	if err := tmpl.Execute(&name, imageLookupParams{
+		BaseOS:     baseOS,
+		Variant:    strings.ToLower(string(variant)),
+		K8sVersion: version,
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to format image name with %q", format)
+	}
+	if name.Len() == 0 {
+		return nil, errors.Errorf("image lookup format %q produced an empty image name", format)
+	}
+
+	return &infrav1.Image{
+		ComputeGallery: &infrav1.AzureComputeGalleryImage{
+			Gallery:        lookup.Gallery,
+			Name:           name.String(),
+			Version:        version,
+			SubscriptionID: lookup.SubscriptionID,
+			ResourceGroup:  lookup.ResourceGroup,
+		},
+	}, nil
+}
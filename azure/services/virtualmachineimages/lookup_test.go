@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachineimages
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestLookupImage(t *testing.T) {
+	tests := []struct {
+		name       string
+		lookup     infrav1.ImageLookup
+		variant    infrav1.ImageVariant
+		k8sVersion string
+		expected   *infrav1.Image
+		expectErr  bool
+	}{
+		{
+			name:       "community gallery image without variant",
+			lookup:     infrav1.ImageLookup{Gallery: "hardened"},
+			k8sVersion: "v1.28.3",
+			expected: &infrav1.Image{
+				ComputeGallery: &infrav1.AzureComputeGalleryImage{
+					Gallery: "hardened",
+					Name:    "capi-ubuntu-2204",
+					Version: "1.28.3",
+				},
+			},
+		},
+		{
+			name: "private gallery FIPS image",
+			lookup: infrav1.ImageLookup{
+				Gallery:        "hardened",
+				SubscriptionID: ptr.To("00000000-0000-0000-0000-000000000000"),
+				ResourceGroup:  ptr.To("images"),
+			},
+			variant:    infrav1.ImageVariantFIPS,
+			k8sVersion: "1.24.6",
+			expected: &infrav1.Image{
+				ComputeGallery: &infrav1.AzureComputeGalleryImage{
+					Gallery:        "hardened",
+					Name:           "capi-ubuntu-2004-fips",
+					Version:        "1.24.6",
+					SubscriptionID: ptr.To("00000000-0000-0000-0000-000000000000"),
+					ResourceGroup:  ptr.To("images"),
+				},
+			},
+		},
+		{
+			name: "custom format and base OS",
+			lookup: infrav1.ImageLookup{
+				Gallery: "hardened",
+				Format:  "{{.BaseOS}}-{{.Variant}}-k8s-{{.K8sVersion}}",
+				BaseOS:  "windows-2022",
+			},
+			variant:    infrav1.ImageVariantCIS,
+			k8sVersion: "v1.29.0",
+			expected: &infrav1.Image{
+				ComputeGallery: &infrav1.AzureComputeGalleryImage{
+					Gallery: "hardened",
+					Name:    "windows-2022-cis-k8s-1.29.0",
+					Version: "1.29.0",
+				},
+			},
+		},
+		{
+			name:       "invalid Kubernetes version",
+			lookup:     infrav1.ImageLookup{Gallery: "hardened"},
+			k8sVersion: "invalid",
+			expectErr:  true,
+		},
+		{
+			name:       "unknown field in format",
+			lookup:     infrav1.ImageLookup{Gallery: "hardened", Format: "{{.Distribution}}"},
+			k8sVersion: "v1.28.3",
+			expectErr:  true,
+		},
+		{
+			name:       "empty image name",
+			lookup:     infrav1.ImageLookup{Gallery: "hardened", Format: "{{.Variant}}"},
+			k8sVersion: "v1.28.3",
+			expectErr:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			image, err := LookupImage(&tc.lookup, tc.variant, tc.k8sVersion)
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(image).To(Equal(tc.expected))
+		})
+	}
+}
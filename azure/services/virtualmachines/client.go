@@ -40,17 +40,25 @@ type (
 		Get(context.Context, azure.ResourceSpecGetter) (interface{}, error)
 		CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armcompute.VirtualMachinesClientCreateOrUpdateResponse], err error)
 		DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armcompute.VirtualMachinesClientDeleteResponse], err error)
+		ListAvailableSizes(ctx context.Context, resourceGroupName, name string) ([]string, error)
+		PowerOffAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armcompute.VirtualMachinesClientPowerOffResponse], err error)
+		DeallocateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, hibernate bool) (poller *runtime.Poller[armcompute.VirtualMachinesClientDeallocateResponse], err error)
+		StartAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armcompute.VirtualMachinesClientStartResponse], err error)
+		ReimageAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, customData string) (poller *runtime.Poller[armcompute.VirtualMachinesClientReimageResponse], err error)
+		UpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters armcompute.VirtualMachineUpdate) (poller *runtime.Poller[armcompute.VirtualMachinesClientUpdateResponse], err error)
 	}
 )
 
 var _ Client = &AzureClient{}
 
-// NewClient creates a VMs client from an authorizer.
-func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+// NewClient creates a VMs client from an authorizer. Requests are sent along with tokens for the auxiliary tenants, if
+// any, e.g. to create VMs from images in a gallery of another tenant.
+func NewClient(auth azure.Authorizer, auxiliaryTenants ...string) (*AzureClient, error) {
 	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create virtualmachines client options")
 	}
+	opts.AuxiliaryTenants = auxiliaryTenants
 	factory, err := armcompute.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create armcompute client factory")
@@ -58,12 +66,13 @@ func NewClient(auth azure.Authorizer) (*AzureClient, error) {
 	return &AzureClient{factory.NewVirtualMachinesClient()}, nil
 }
 
-// Get retrieves information about the model view or the instance view of a virtual machine.
+// Get retrieves information about the model view and the instance view of a virtual machine.
 func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Get")
 	defer done()
 
-	resp, err := ac.virtualmachines.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	opts := &armcompute.VirtualMachinesClientGetOptions{Expand: ptr.To(armcompute.InstanceViewTypesInstanceView)}
+	resp, err := ac.virtualmachines.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -131,3 +140,171 @@ func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 	// if the operation completed, return a nil poller.
 	return nil, err
 }
+
+// PowerOffAsync gracefully shuts down and powers off a virtual machine asynchronously. PowerOffAsync sends a POST
+// request to Azure and if accepted without error, the func will return a Poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *AzureClient) PowerOffAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armcompute.VirtualMachinesClientPowerOffResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.PowerOff")
+	defer done()
+
+	opts := &armcompute.VirtualMachinesClientBeginPowerOffOptions{ResumeToken: resumeToken, SkipShutdown: ptr.To(false)}
+	poller, err = ac.virtualmachines.BeginPowerOff(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	_, err = poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the Poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return poller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, err
+}
+
+// DeallocateAsync shuts down a virtual machine and releases its compute resources asynchronously, hibernating it first
+// when hibernate is true. DeallocateAsync sends a POST request to Azure and if accepted without error, the func will
+// return a Poller which can be used to track the ongoing progress of the operation.
+func (ac *AzureClient) DeallocateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, hibernate bool) (poller *runtime.Poller[armcompute.VirtualMachinesClientDeallocateResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Deallocate")
+	defer done()
+
+	opts := &armcompute.VirtualMachinesClientBeginDeallocateOptions{ResumeToken: resumeToken}
+	if hibernate {
+		opts.Hibernate = ptr.To(true)
+	}
+	poller, err = ac.virtualmachines.BeginDeallocate(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	_, err = poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the Poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return poller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, err
+}
+
+// StartAsync starts a virtual machine asynchronously. StartAsync sends a POST request to Azure and if accepted without
+// error, the func will return a Poller which can be used to track the ongoing progress of the operation.
+func (ac *AzureClient) StartAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armcompute.VirtualMachinesClientStartResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Start")
+	defer done()
+
+	opts := &armcompute.VirtualMachinesClientBeginStartOptions{ResumeToken: resumeToken}
+	poller, err = ac.virtualmachines.BeginStart(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	_, err = poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the Poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return poller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, err
+}
+
+// ReimageAsync reimages a virtual machine asynchronously, rerunning its provisioning with customData when it is set.
+// ReimageAsync sends a POST request to Azure and if accepted without error, the func will return a Poller which can be
+// used to track the ongoing progress of the operation.
+func (ac *AzureClient) ReimageAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, customData string) (poller *runtime.Poller[armcompute.VirtualMachinesClientReimageResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Reimage")
+	defer done()
+
+	opts := &armcompute.VirtualMachinesClientBeginReimageOptions{ResumeToken: resumeToken}
+	if customData != "" {
+		opts.Parameters = &armcompute.VirtualMachineReimageParameters{
+			OSProfile: &armcompute.OSProfileProvisioningData{CustomData: ptr.To(customData)},
+		}
+	}
+	poller, err = ac.virtualmachines.BeginReimage(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	_, err = poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the Poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return poller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, err
+}
+
+// ListAvailableSizes returns the names of the sizes an existing virtual machine can be resized to on the hardware
+// cluster currently hosting it.
+func (ac *AzureClient) ListAvailableSizes(ctx context.Context, resourceGroupName, name string) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.ListAvailableSizes")
+	defer done()
+
+	var sizes []string
+	pager := ac.virtualmachines.NewListAvailableSizesPager(resourceGroupName, name, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, size := range page.Value {
+			if size != nil && size.Name != nil {
+				sizes = append(sizes, *size.Name)
+			}
+		}
+	}
+	return sizes, nil
+}
+
+// UpdateAsync partially updates a virtual machine asynchronously. UpdateAsync sends a PATCH request to Azure and if
+// accepted without error, the func will return a Poller which can be used to track the ongoing progress of the operation.
+func (ac *AzureClient) UpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters armcompute.VirtualMachineUpdate) (poller *runtime.Poller[armcompute.VirtualMachinesClientUpdateResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Update")
+	defer done()
+
+	opts := &armcompute.VirtualMachinesClientBeginUpdateOptions{ResumeToken: resumeToken}
+	poller, err = ac.virtualmachines.BeginUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), parameters, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	_, err = poller.PollUntilDone(ctx, pollOpts)
+	if err != nil {
+		// if an error occurs, return the Poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return poller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, err
+}
@@ -73,6 +73,21 @@ func (mr *MockClientMockRecorder) CreateOrUpdateAsync(ctx, spec, resumeToken, pa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateAsync", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateAsync), ctx, spec, resumeToken, parameters)
 }
 
+// DeallocateAsync mocks base method.
+func (m *MockClient) DeallocateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, hibernate bool) (*runtime.Poller[armcompute.VirtualMachinesClientDeallocateResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeallocateAsync", ctx, spec, resumeToken, hibernate)
+	ret0, _ := ret[0].(*runtime.Poller[armcompute.VirtualMachinesClientDeallocateResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeallocateAsync indicates an expected call of DeallocateAsync.
+func (mr *MockClientMockRecorder) DeallocateAsync(ctx, spec, resumeToken, hibernate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeallocateAsync", reflect.TypeOf((*MockClient)(nil).DeallocateAsync), ctx, spec, resumeToken, hibernate)
+}
+
 // DeleteAsync mocks base method.
 func (m *MockClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (*runtime.Poller[armcompute.VirtualMachinesClientDeleteResponse], error) {
 	m.ctrl.T.Helper()
@@ -102,3 +117,78 @@ func (mr *MockClientMockRecorder) Get(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClient)(nil).Get), arg0, arg1)
 }
+
+// ListAvailableSizes mocks base method.
+func (m *MockClient) ListAvailableSizes(ctx context.Context, resourceGroupName, name string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAvailableSizes", ctx, resourceGroupName, name)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAvailableSizes indicates an expected call of ListAvailableSizes.
+func (mr *MockClientMockRecorder) ListAvailableSizes(ctx, resourceGroupName, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailableSizes", reflect.TypeOf((*MockClient)(nil).ListAvailableSizes), ctx, resourceGroupName, name)
+}
+
+// PowerOffAsync mocks base method.
+func (m *MockClient) PowerOffAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (*runtime.Poller[armcompute.VirtualMachinesClientPowerOffResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PowerOffAsync", ctx, spec, resumeToken)
+	ret0, _ := ret[0].(*runtime.Poller[armcompute.VirtualMachinesClientPowerOffResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PowerOffAsync indicates an expected call of PowerOffAsync.
+func (mr *MockClientMockRecorder) PowerOffAsync(ctx, spec, resumeToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PowerOffAsync", reflect.TypeOf((*MockClient)(nil).PowerOffAsync), ctx, spec, resumeToken)
+}
+
+// ReimageAsync mocks base method.
+func (m *MockClient) ReimageAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken, customData string) (*runtime.Poller[armcompute.VirtualMachinesClientReimageResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReimageAsync", ctx, spec, resumeToken, customData)
+	ret0, _ := ret[0].(*runtime.Poller[armcompute.VirtualMachinesClientReimageResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReimageAsync indicates an expected call of ReimageAsync.
+func (mr *MockClientMockRecorder) ReimageAsync(ctx, spec, resumeToken, customData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReimageAsync", reflect.TypeOf((*MockClient)(nil).ReimageAsync), ctx, spec, resumeToken, customData)
+}
+
+// StartAsync mocks base method.
+func (m *MockClient) StartAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (*runtime.Poller[armcompute.VirtualMachinesClientStartResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartAsync", ctx, spec, resumeToken)
+	ret0, _ := ret[0].(*runtime.Poller[armcompute.VirtualMachinesClientStartResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartAsync indicates an expected call of StartAsync.
+func (mr *MockClientMockRecorder) StartAsync(ctx, spec, resumeToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartAsync", reflect.TypeOf((*MockClient)(nil).StartAsync), ctx, spec, resumeToken)
+}
+
+// UpdateAsync mocks base method.
+func (m *MockClient) UpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters armcompute.VirtualMachineUpdate) (*runtime.Poller[armcompute.VirtualMachinesClientUpdateResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAsync", ctx, spec, resumeToken, parameters)
+	ret0, _ := ret[0].(*runtime.Poller[armcompute.VirtualMachinesClientUpdateResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAsync indicates an expected call of UpdateAsync.
+func (mr *MockClientMockRecorder) UpdateAsync(ctx, spec, resumeToken, parameters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAsync", reflect.TypeOf((*MockClient)(nil).UpdateAsync), ctx, spec, resumeToken, parameters)
+}
@@ -58,6 +58,20 @@ func (m *MockVMScope) EXPECT() *MockVMScopeMockRecorder {
 	return m.recorder
 }
 
+// AuxiliaryTenantIDs mocks base method.
+func (m *MockVMScope) AuxiliaryTenantIDs() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuxiliaryTenantIDs")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// AuxiliaryTenantIDs indicates an expected call of AuxiliaryTenantIDs.
+func (mr *MockVMScopeMockRecorder) AuxiliaryTenantIDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuxiliaryTenantIDs", reflect.TypeOf((*MockVMScope)(nil).AuxiliaryTenantIDs))
+}
+
 // BaseURI mocks base method.
 func (m *MockVMScope) BaseURI() string {
 	m.ctrl.T.Helper()
@@ -126,6 +140,20 @@ func (mr *MockVMScopeMockRecorder) DeleteLongRunningOperationState(arg0, arg1, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockVMScope)(nil).DeleteLongRunningOperationState), arg0, arg1, arg2)
 }
 
+// DesiredPowerState mocks base method.
+func (m *MockVMScope) DesiredPowerState() v1beta1.VMPowerState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DesiredPowerState")
+	ret0, _ := ret[0].(v1beta1.VMPowerState)
+	return ret0
+}
+
+// DesiredPowerState indicates an expected call of DesiredPowerState.
+func (mr *MockVMScopeMockRecorder) DesiredPowerState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DesiredPowerState", reflect.TypeOf((*MockVMScope)(nil).DesiredPowerState))
+}
+
 // GetLongRunningOperationState mocks base method.
 func (m *MockVMScope) GetLongRunningOperationState(arg0, arg1, arg2 string) *v1beta1.Future {
 	m.ctrl.T.Helper()
@@ -154,6 +182,34 @@ func (mr *MockVMScopeMockRecorder) HashKey() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockVMScope)(nil).HashKey))
 }
 
+// ProviderID mocks base method.
+func (m *MockVMScope) ProviderID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProviderID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ProviderID indicates an expected call of ProviderID.
+func (mr *MockVMScopeMockRecorder) ProviderID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProviderID", reflect.TypeOf((*MockVMScope)(nil).ProviderID))
+}
+
+// Reimaged mocks base method.
+func (m *MockVMScope) Reimaged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reimaged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Reimaged indicates an expected call of Reimaged.
+func (mr *MockVMScopeMockRecorder) Reimaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reimaged", reflect.TypeOf((*MockVMScope)(nil).Reimaged))
+}
+
 // SetAddresses mocks base method.
 func (m *MockVMScope) SetAddresses(arg0 []v1.NodeAddress) {
 	m.ctrl.T.Helper()
@@ -202,6 +258,18 @@ func (mr *MockVMScopeMockRecorder) SetLongRunningOperationState(arg0 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockVMScope)(nil).SetLongRunningOperationState), arg0)
 }
 
+// SetPowerState mocks base method.
+func (m *MockVMScope) SetPowerState(arg0 v1beta1.VMPowerState) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPowerState", arg0)
+}
+
+// SetPowerState indicates an expected call of SetPowerState.
+func (mr *MockVMScopeMockRecorder) SetPowerState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPowerState", reflect.TypeOf((*MockVMScope)(nil).SetPowerState), arg0)
+}
+
 // SetProviderID mocks base method.
 func (m *MockVMScope) SetProviderID(arg0 string) {
 	m.ctrl.T.Helper()
@@ -214,6 +282,18 @@ func (mr *MockVMScopeMockRecorder) SetProviderID(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProviderID", reflect.TypeOf((*MockVMScope)(nil).SetProviderID), arg0)
 }
 
+// SetReimaged mocks base method.
+func (m *MockVMScope) SetReimaged() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetReimaged")
+}
+
+// SetReimaged indicates an expected call of SetReimaged.
+func (mr *MockVMScopeMockRecorder) SetReimaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReimaged", reflect.TypeOf((*MockVMScope)(nil).SetReimaged))
+}
+
 // SetVMState mocks base method.
 func (m *MockVMScope) SetVMState(arg0 v1beta1.ProvisioningState) {
 	m.ctrl.T.Helper()
@@ -226,6 +306,18 @@ func (mr *MockVMScopeMockRecorder) SetVMState(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVMState", reflect.TypeOf((*MockVMScope)(nil).SetVMState), arg0)
 }
 
+// SetVMStatus mocks base method.
+func (m *MockVMScope) SetVMStatus(arg0 *v1beta1.VMStatus) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetVMStatus", arg0)
+}
+
+// SetVMStatus indicates an expected call of SetVMStatus.
+func (mr *MockVMScopeMockRecorder) SetVMStatus(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVMStatus", reflect.TypeOf((*MockVMScope)(nil).SetVMStatus), arg0)
+}
+
 // SubscriptionID mocks base method.
 func (m *MockVMScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -240,6 +332,20 @@ func (mr *MockVMScopeMockRecorder) SubscriptionID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockVMScope)(nil).SubscriptionID))
 }
 
+// SubstituteZone mocks base method.
+func (m *MockVMScope) SubstituteZone(reason string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubstituteZone", reason)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubstituteZone indicates an expected call of SubstituteZone.
+func (mr *MockVMScopeMockRecorder) SubstituteZone(reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubstituteZone", reflect.TypeOf((*MockVMScope)(nil).SubstituteZone), reason)
+}
+
 // TenantID mocks base method.
 func (m *MockVMScope) TenantID() string {
 	m.ctrl.T.Helper()
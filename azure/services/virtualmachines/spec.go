@@ -20,9 +20,11 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -31,6 +33,10 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/generators"
 )
 
+// maxUserDataLength is the maximum size of the base64 encoded user data of a VM.
+// See: https://learn.microsoft.com/azure/virtual-machines/user-data
+const maxUserDataLength = 64 * 1024
+
 // VMSpec defines the specification for a Virtual Machine.
 type VMSpec struct {
 	Name                   string
@@ -40,12 +46,17 @@ type VMSpec struct {
 	ClusterName            string
 	Role                   string
 	NICIDs                 []string
+	AdminUsername          string
+	AdminPassword          string
 	SSHKeyData             string
+	ClusterSSHKeyData      string
+	AdditionalSSHKeyData   []string
 	Size                   string
 	AvailabilitySetID      string
 	Zone                   string
 	Identity               infrav1.VMIdentity
 	OSDisk                 infrav1.OSDisk
+	OSDiskID               string
 	DataDisks              []infrav1.DataDisk
 	UserAssignedIdentities []infrav1.UserAssignedIdentity
 	SpotVMOptions          *infrav1.SpotVMOptions
@@ -54,9 +65,15 @@ type VMSpec struct {
 	AdditionalCapabilities *infrav1.AdditionalCapabilities
 	DiagnosticsProfile     *infrav1.Diagnostics
 	SKU                    resourceskus.SKU
+	DiskSKUs               map[string]resourceskus.SKU
 	Image                  *infrav1.Image
 	BootstrapData          string
+	UserData               string
 	ProviderID             string
+	SkipShutdown           bool
+	ReimageOnFailure       bool
+	DataDiskDetach         *infrav1.DataDiskDetach
+	DeletionTimestamp      *metav1.Time
 }
 
 // ResourceName returns the name of the virtual machine.
@@ -89,6 +106,10 @@ func (s *VMSpec) Parameters(ctx context.Context, existing interface{}) (params i
 		return nil, azure.VMDeletedError{ProviderID: s.ProviderID}
 	}
 
+	if userData := ptr.Deref(s.getUserData(), ""); len(userData) > maxUserDataLength {
+		return nil, azure.WithTerminalError(errors.Errorf("user data of VM %s is %d bytes once base64 encoded, which exceeds the maximum of %d bytes", s.Name, len(userData), maxUserDataLength))
+	}
+
 	storageProfile, err := s.generateStorageProfile()
 	if err != nil {
 		return nil, err
@@ -99,9 +120,12 @@ func (s *VMSpec) Parameters(ctx context.Context, existing interface{}) (params i
 		return nil, err
 	}
 
-	osProfile, err := s.generateOSProfile()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to generate OS Profile")
+	var osProfile *armcompute.OSProfile
+	if s.OSDisk.Source == nil {
+		osProfile, err = s.generateOSProfile()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate OS Profile")
+		}
 	}
 
 	priority, evictionPolicy, billingProfile, err := converters.GetSpotVMOptions(s.SpotVMOptions, s.OSDisk.DiffDiskSettings)
@@ -141,12 +165,80 @@ func (s *VMSpec) Parameters(ctx context.Context, existing interface{}) (params i
 			EvictionPolicy:     evictionPolicy,
 			BillingProfile:     billingProfile,
 			DiagnosticsProfile: converters.GetDiagnosticsProfile(s.DiagnosticsProfile),
+			UserData:           s.getUserData(),
 		},
 		Identity: identity,
 		Zones:    s.getZones(),
 	}, nil
 }
 
+// getUserData returns the user data of the VM, or nil if it has none. A VM with an attached OS disk has no OS profile
+// to pass the bootstrap data as custom data, so it gets the bootstrap data as user data instead.
+func (s *VMSpec) getUserData() *string {
+	if s.OSDisk.Source != nil && s.BootstrapData != "" {
+		return ptr.To(s.BootstrapData)
+	}
+	if s.UserData == "" {
+		return nil
+	}
+	return ptr.To(s.UserData)
+}
+
+// VMSizeSpec defines the specification for resizing an existing Virtual Machine.
+type VMSizeSpec struct {
+	Name          string
+	ResourceGroup string
+	Size          string
+}
+
+// ResourceName returns the name of the virtual machine.
+func (s *VMSizeSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the virtual machine.
+func (s *VMSizeSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for virtual machines.
+func (s *VMSizeSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the existing virtual machine with its size updated, or nil if the size is already up to date.
+// Virtual machines are only resized here, creating them is left to VMSpec.
+func (s *VMSizeSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
+	if existing == nil {
+		return nil, nil
+	}
+	vm, ok := existing.(armcompute.VirtualMachine)
+	if !ok {
+		return nil, errors.Errorf("%T is not an armcompute.VirtualMachine", existing)
+	}
+	if strings.EqualFold(vmSize(vm), s.Size) {
+		return nil, nil
+	}
+	if vm.Properties == nil {
+		vm.Properties = &armcompute.VirtualMachineProperties{}
+	}
+	if vm.Properties.HardwareProfile == nil {
+		vm.Properties.HardwareProfile = &armcompute.HardwareProfile{}
+	}
+	vm.Properties.HardwareProfile.VMSize = ptr.To(armcompute.VirtualMachineSizeTypes(s.Size))
+	// The instance view is read-only and not part of the model sent back to Azure.
+	vm.Properties.InstanceView = nil
+	return vm, nil
+}
+
+// vmSize returns the size of a virtual machine.
+func vmSize(vm armcompute.VirtualMachine) string {
+	if vm.Properties == nil || vm.Properties.HardwareProfile == nil {
+		return ""
+	}
+	return string(ptr.Deref(vm.Properties.HardwareProfile.VMSize, ""))
+}
+
 // generateStorageProfile generates a pointer to an armcompute.StorageProfile which can utilized for VM creation.
 func (s *VMSpec) generateStorageProfile() (*armcompute.StorageProfile, error) {
 	osDisk := &armcompute.OSDisk{
@@ -158,34 +250,19 @@ func (s *VMSpec) generateStorageProfile() (*armcompute.StorageProfile, error) {
 	if s.OSDisk.CachingType != "" {
 		osDisk.Caching = ptr.To(armcompute.CachingTypes(s.OSDisk.CachingType))
 	}
+	if ptr.Deref(s.OSDisk.WriteAcceleratorEnabled, false) {
+		osDisk.WriteAcceleratorEnabled = ptr.To(true)
+	}
 	storageProfile := &armcompute.StorageProfile{
 		OSDisk: osDisk,
 	}
 
-	// Checking if the requested VM size has at least 2 vCPUS
-	vCPUCapability, err := s.SKU.HasCapabilityWithCapacity(resourceskus.VCPUs, resourceskus.MinimumVCPUS)
-	if err != nil {
-		return nil, azure.WithTerminalError(errors.Wrap(err, "failed to validate the vCPU capability"))
-	}
-	if !vCPUCapability {
-		return nil, azure.WithTerminalError(errors.New("VM size should be bigger or equal to at least 2 vCPUs"))
-	}
-
-	// Checking if the requested VM size has at least 2 Gi of memory
-	MemoryCapability, err := s.SKU.HasCapabilityWithCapacity(resourceskus.MemoryGB, resourceskus.MinimumMemory)
-	if err != nil {
-		return nil, azure.WithTerminalError(errors.Wrap(err, "failed to validate the memory capability"))
+	if err := s.validateSize(); err != nil {
+		return nil, azure.WithTerminalError(err)
 	}
 
-	if !MemoryCapability {
-		return nil, azure.WithTerminalError(errors.New("VM memory should be bigger or equal to at least 2Gi"))
-	}
 	// enable ephemeral OS
 	if s.OSDisk.DiffDiskSettings != nil {
-		if !s.SKU.HasCapability(resourceskus.EphemeralOSDisk) {
-			return nil, azure.WithTerminalError(fmt.Errorf("VM size %s does not support ephemeral os. Select a different VM size or disable ephemeral os", s.Size))
-		}
-
 		storageProfile.OSDisk.DiffDiskSettings = &armcompute.DiffDiskSettings{
 			Option: ptr.To(armcompute.DiffDiskOptions(s.OSDisk.DiffDiskSettings.Option)),
 		}
@@ -193,8 +270,8 @@ func (s *VMSpec) generateStorageProfile() (*armcompute.StorageProfile, error) {
 
 	if s.OSDisk.ManagedDisk != nil {
 		storageProfile.OSDisk.ManagedDisk = &armcompute.ManagedDiskParameters{}
-		if s.OSDisk.ManagedDisk.StorageAccountType != "" {
-			storageProfile.OSDisk.ManagedDisk.StorageAccountType = ptr.To(armcompute.StorageAccountTypes(s.OSDisk.ManagedDisk.StorageAccountType))
+		if storageAccountType := s.OSDisk.StorageAccountType(); storageAccountType != "" {
+			storageProfile.OSDisk.ManagedDisk.StorageAccountType = ptr.To(armcompute.StorageAccountTypes(storageAccountType))
 		}
 		if s.OSDisk.ManagedDisk.DiskEncryptionSet != nil {
 			storageProfile.OSDisk.ManagedDisk.DiskEncryptionSet = &armcompute.DiskEncryptionSetParameters{ID: ptr.To(s.OSDisk.ManagedDisk.DiskEncryptionSet.ID)}
@@ -215,6 +292,17 @@ func (s *VMSpec) generateStorageProfile() (*armcompute.StorageProfile, error) {
 		}
 	}
 
+	// An OS disk with a source is created from it by the disks service, the VM only attaches it.
+	if s.OSDisk.Source != nil {
+		storageProfile.OSDisk.CreateOption = ptr.To(armcompute.DiskCreateOptionTypesAttach)
+		storageProfile.OSDisk.DiskSizeGB = nil
+		managedDisk := &armcompute.ManagedDiskParameters{ID: ptr.To(s.OSDiskID)}
+		if storageProfile.OSDisk.ManagedDisk != nil {
+			managedDisk.SecurityProfile = storageProfile.OSDisk.ManagedDisk.SecurityProfile
+		}
+		storageProfile.OSDisk.ManagedDisk = managedDisk
+	}
+
 	dataDisks := make([]*armcompute.DataDisk, len(s.DataDisks))
 	for i, disk := range s.DataDisks {
 		dataDisks[i] = &armcompute.DataDisk{
@@ -226,6 +314,9 @@ func (s *VMSpec) generateStorageProfile() (*armcompute.StorageProfile, error) {
 		if disk.CachingType != "" {
 			dataDisks[i].Caching = ptr.To(armcompute.CachingTypes(disk.CachingType))
 		}
+		if ptr.Deref(disk.WriteAcceleratorEnabled, false) {
+			dataDisks[i].WriteAcceleratorEnabled = ptr.To(true)
+		}
 
 		if disk.ManagedDisk != nil {
 			dataDisks[i].ManagedDisk = &armcompute.ManagedDiskParameters{
@@ -240,10 +331,18 @@ func (s *VMSpec) generateStorageProfile() (*armcompute.StorageProfile, error) {
 			if disk.ManagedDisk.StorageAccountType == string(armcompute.StorageAccountTypesUltraSSDLRS) && !s.SKU.HasLocationCapability(resourceskus.UltraSSDAvailable, s.Location, s.Zone) {
 				return nil, azure.WithTerminalError(fmt.Errorf("VM size %s does not support ultra disks in location %s. Select a different VM size or disable ultra disks", s.Size, s.Location))
 			}
+
+			if err := s.checkDiskAvailability(disk); err != nil {
+				return nil, err
+			}
 		}
 	}
 	storageProfile.DataDisks = dataDisks
 
+	if s.OSDisk.Source != nil {
+		return storageProfile, nil
+	}
+
 	imageRef, err := converters.ImageToSDK(s.Image)
 	if err != nil {
 		return nil, err
@@ -254,16 +353,135 @@ func (s *VMSpec) generateStorageProfile() (*armcompute.StorageProfile, error) {
 	return storageProfile, nil
 }
 
+// checkDiskAvailability checks that the storage account type of a data disk is offered in the location of the VM,
+// and in its zone for Premium SSD v2 disks, which can only be attached to zonal VMs.
+func (s *VMSpec) checkDiskAvailability(disk infrav1.DataDisk) error {
+	storageAccountType := disk.ManagedDisk.StorageAccountType
+	if s.DiskSKUs == nil || !resourceskus.IsRegionalDiskType(storageAccountType) {
+		return nil
+	}
+	diskSKU, ok := s.DiskSKUs[storageAccountType]
+	if !ok {
+		return azure.WithTerminalError(fmt.Errorf("storage account type %s of data disk %s is not available in location %s", storageAccountType, disk.NameSuffix, s.Location))
+	}
+	if storageAccountType != string(armcompute.StorageAccountTypesPremiumV2LRS) {
+		return nil
+	}
+	if s.Zone == "" {
+		return azure.WithTerminalError(fmt.Errorf("data disk %s with storage account type %s can only be attached to a VM in an availability zone", disk.NameSuffix, storageAccountType))
+	}
+	if !diskSKU.IsZoneAvailable(s.Location, s.Zone) {
+		return azure.WithTerminalError(fmt.Errorf("storage account type %s of data disk %s is not available in zone %s of location %s", storageAccountType, disk.NameSuffix, s.Zone, s.Location))
+	}
+	return nil
+}
+
+// writeAcceleratorDisks returns the number of disks of the VM with write accelerator enabled.
+func (s *VMSpec) writeAcceleratorDisks() int {
+	count := 0
+	if ptr.Deref(s.OSDisk.WriteAcceleratorEnabled, false) {
+		count++
+	}
+	for _, disk := range s.DataDisks {
+		if ptr.Deref(disk.WriteAcceleratorEnabled, false) {
+			count++
+		}
+	}
+	return count
+}
+
+// csiDataDisks returns the names of the data disks attached to a VM which are not part of the spec, i.e. the ones
+// attached by a CSI driver.
+func (s *VMSpec) csiDataDisks(vm armcompute.VirtualMachine) []string {
+	if vm.Properties == nil || vm.Properties.StorageProfile == nil {
+		return nil
+	}
+	managed := make(map[string]struct{}, len(s.DataDisks))
+	for _, disk := range s.DataDisks {
+		managed[azure.GenerateDataDiskName(s.Name, disk.NameSuffix)] = struct{}{}
+	}
+	var disks []string
+	for _, disk := range vm.Properties.StorageProfile.DataDisks {
+		if disk == nil || disk.Name == nil {
+			continue
+		}
+		if _, ok := managed[*disk.Name]; !ok {
+			disks = append(disks, *disk.Name)
+		}
+	}
+	return disks
+}
+
+// validateSize checks that the requested VM size provides the capabilities required by the rest of the spec.
+func (s *VMSpec) validateSize() error {
+	// Checking if the requested VM size has at least 2 vCPUS
+	vCPUCapability, err := s.SKU.HasCapabilityWithCapacity(resourceskus.VCPUs, resourceskus.MinimumVCPUS)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate the vCPU capability")
+	}
+	if !vCPUCapability {
+		return errors.New("VM size should be bigger or equal to at least 2 vCPUs")
+	}
+
+	// Checking if the requested VM size has at least 2 Gi of memory
+	MemoryCapability, err := s.SKU.HasCapabilityWithCapacity(resourceskus.MemoryGB, resourceskus.MinimumMemory)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate the memory capability")
+	}
+
+	if !MemoryCapability {
+		return errors.New("VM memory should be bigger or equal to at least 2Gi")
+	}
+
+	// Checking if the requested VM size is offered in the requested zone
+	if s.Zone != "" && !s.SKU.IsZoneAvailable(s.Location, s.Zone) {
+		return fmt.Errorf("VM size %s is not available in zone %s of location %s. Select a different VM size or failure domain", s.Size, s.Zone, s.Location)
+	}
+	// Checking if the requested VM size supports write accelerator on the requested number of disks
+	if writeAcceleratorDisks := s.writeAcceleratorDisks(); writeAcceleratorDisks > 0 {
+		writeAcceleratorCapability, err := s.SKU.HasCapabilityWithCapacity(resourceskus.MaxWriteAcceleratorDisksAllowed, int64(writeAcceleratorDisks))
+		if err != nil {
+			return errors.Wrap(err, "failed to validate the write accelerator capability")
+		}
+		if !writeAcceleratorCapability {
+			return fmt.Errorf("VM size %s does not support write accelerator on %d disks. Select a different VM size or disable write accelerator", s.Size, writeAcceleratorDisks)
+		}
+	}
+
+	// Checking if the requested VM size supports ephemeral OS disks
+	if s.OSDisk.DiffDiskSettings != nil && !s.SKU.HasCapability(resourceskus.EphemeralOSDisk) {
+		return fmt.Errorf("VM size %s does not support ephemeral os. Select a different VM size or disable ephemeral os", s.Size)
+	}
+
+	return nil
+}
+
 func (s *VMSpec) generateOSProfile() (*armcompute.OSProfile, error) {
 	sshKey, err := base64.StdEncoding.DecodeString(s.SSHKeyData)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to decode ssh public key")
 	}
+	clusterSSHKey, err := base64.StdEncoding.DecodeString(s.ClusterSSHKeyData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode cluster ssh public key")
+	}
+	additionalSSHKeys, err := azure.DecodeSSHPublicKeys(s.AdditionalSSHKeyData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode additional ssh public keys")
+	}
+
+	adminUsername := s.AdminUsername
+	if adminUsername == "" {
+		adminUsername = azure.DefaultUserName
+	}
 
 	osProfile := &armcompute.OSProfile{
 		ComputerName:  ptr.To(s.Name),
-		AdminUsername: ptr.To(azure.DefaultUserName),
-		CustomData:    ptr.To(s.BootstrapData),
+		AdminUsername: ptr.To(adminUsername),
+	}
+	// Bootstrap data which is passed as user data or run by an extension leaves the custom data empty.
+	if s.BootstrapData != "" {
+		osProfile.CustomData = ptr.To(s.BootstrapData)
 	}
 
 	switch s.OSDisk.OSType {
@@ -275,20 +493,38 @@ func (s *VMSpec) generateOSProfile() (*armcompute.OSProfile, error) {
 		// but the password on the VM will NOT be the same as created here.
 		// Access is provided via SSH public key that is set during deployment
 		// Azure also provides a way to reset user passwords in the case of need.
-		osProfile.AdminPassword = ptr.To(generators.SudoRandomPassword(123))
+		// A password stored for the machine is used instead when one was requested.
+		adminPassword := s.AdminPassword
+		if adminPassword == "" {
+			adminPassword = generators.SudoRandomPassword(123)
+		}
+		osProfile.AdminPassword = ptr.To(adminPassword)
 		osProfile.WindowsConfiguration = &armcompute.WindowsConfiguration{
 			EnableAutomaticUpdates: ptr.To(false),
 		}
 	default:
+		publicKeys := []*armcompute.SSHPublicKey{
+			{
+				Path:    ptr.To(fmt.Sprintf("/home/%s/.ssh/authorized_keys", adminUsername)),
+				KeyData: ptr.To(string(sshKey)),
+			},
+		}
+		// The machine's own key comes first, then its additional keys and the cluster keys, each authorized once.
+		authorized := map[string]bool{string(sshKey): true}
+		for _, key := range append(additionalSSHKeys, string(clusterSSHKey)) {
+			if key == "" || authorized[key] {
+				continue
+			}
+			authorized[key] = true
+			publicKeys = append(publicKeys, &armcompute.SSHPublicKey{
+				Path:    ptr.To(fmt.Sprintf("/home/%s/.ssh/authorized_keys", adminUsername)),
+				KeyData: ptr.To(key),
+			})
+		}
 		osProfile.LinuxConfiguration = &armcompute.LinuxConfiguration{
 			DisablePasswordAuthentication: ptr.To(true),
 			SSH: &armcompute.SSHConfiguration{
-				PublicKeys: []*armcompute.SSHPublicKey{
-					{
-						Path:    ptr.To(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
-						KeyData: ptr.To(string(sshKey)),
-					},
-				},
+				PublicKeys: publicKeys,
 			},
 		}
 	}
@@ -418,6 +654,9 @@ func (s *VMSpec) generateAdditionalCapabilities() *armcompute.AdditionalCapabili
 		if s.AdditionalCapabilities.UltraSSDEnabled != nil {
 			capabilities.UltraSSDEnabled = s.AdditionalCapabilities.UltraSSDEnabled
 		}
+		if s.AdditionalCapabilities.HibernationEnabled != nil {
+			capabilities.HibernationEnabled = s.AdditionalCapabilities.HibernationEnabled
+		}
 	}
 
 	return capabilities
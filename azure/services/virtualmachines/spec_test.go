@@ -18,6 +18,8 @@ package virtualmachines
 
 import (
 	"context"
+	"encoding/base64"
+	"strings"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
@@ -209,6 +211,28 @@ var (
 		},
 	}
 
+	validSKUWithWriteAccelerator = resourceskus.SKU{
+		Name: ptr.To("Standard_M8ms"),
+		Kind: ptr.To(string(resourceskus.VirtualMachines)),
+		Locations: []*string{
+			ptr.To("test-location"),
+		},
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
+			{
+				Name:  ptr.To(resourceskus.VCPUs),
+				Value: ptr.To("8"),
+			},
+			{
+				Name:  ptr.To(resourceskus.MemoryGB),
+				Value: ptr.To("218"),
+			},
+			{
+				Name:  ptr.To(resourceskus.MaxWriteAcceleratorDisksAllowed),
+				Value: ptr.To("1"),
+			},
+		},
+	}
+
 	deletePolicy = infrav1.SpotEvictionPolicyDelete
 )
 
@@ -270,6 +294,87 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm with an additional cluster ssh key",
+			spec: &VMSpec{
+				Name:              "my-vm",
+				Role:              infrav1.Node,
+				NICIDs:            []string{"my-nic"},
+				SSHKeyData:        "fakesshpublickey",
+				ClusterSSHKeyData: "fakeclusterkey00",
+				Size:              "Standard_D2v3",
+				Zone:              "1",
+				Image:             &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:               validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				publicKeys := result.(armcompute.VirtualMachine).Properties.OSProfile.LinuxConfiguration.SSH.PublicKeys
+				g.Expect(publicKeys).To(HaveLen(2))
+				g.Expect(publicKeys[0].Path).To(Equal(publicKeys[1].Path))
+				g.Expect(publicKeys[0].KeyData).NotTo(Equal(publicKeys[1].KeyData))
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm with additional ssh keys authorized once",
+			spec: &VMSpec{
+				Name:                 "my-vm",
+				Role:                 infrav1.Node,
+				NICIDs:               []string{"my-nic"},
+				SSHKeyData:           "fakesshpublickey",
+				AdditionalSSHKeyData: []string{"fakeextrakey0000", "fakesshpublickey", "fakeclusterkey00", "fakeextrakey0000"},
+				ClusterSSHKeyData:    "fakeclusterkey00",
+				Size:                 "Standard_D2v3",
+				Zone:                 "1",
+				Image:                &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:                  validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				publicKeys := result.(armcompute.VirtualMachine).Properties.OSProfile.LinuxConfiguration.SSH.PublicKeys
+				g.Expect(publicKeys).To(HaveLen(3))
+				sshKey, _ := base64.StdEncoding.DecodeString("fakesshpublickey")
+				additionalKey, _ := base64.StdEncoding.DecodeString("fakeextrakey0000")
+				clusterKey, _ := base64.StdEncoding.DecodeString("fakeclusterkey00")
+				g.Expect(publicKeys[0].KeyData).To(Equal(ptr.To(string(sshKey))))
+				g.Expect(publicKeys[1].KeyData).To(Equal(ptr.To(string(additionalKey))))
+				g.Expect(publicKeys[2].KeyData).To(Equal(ptr.To(string(clusterKey))))
+			},
+			expectedError: "",
+		},
+		{
+			name: "creating a vm in a zone where the VM size is not available fails",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Location:   "test-location",
+				Zone:       "3",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU: resourceskus.SKU{
+					Name:         validSKU.Name,
+					Kind:         validSKU.Kind,
+					Locations:    validSKU.Locations,
+					Capabilities: validSKU.Capabilities,
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
+						{
+							Location: ptr.To("test-location"),
+							Zones:    []*string{ptr.To("1"), ptr.To("2")},
+						},
+					},
+				},
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: VM size Standard_D2v3 is not available in zone 3 of location test-location. Select a different VM size or failure domain. Object will not be requeued",
+		},
 		{
 			name: "can create a vm with user assigned identity ",
 			spec: &VMSpec{
@@ -365,6 +470,59 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a windows vm with a stored admin password",
+			spec: &VMSpec{
+				Name:          "my-vm",
+				Role:          infrav1.Node,
+				NICIDs:        []string{"my-nic"},
+				AdminUsername: "capiadmin",
+				AdminPassword: "my-password",
+				SSHKeyData:    "fakesshpublickey",
+				Size:          "Standard_D2v3",
+				Zone:          "1",
+				Image:         &infrav1.Image{ID: ptr.To("fake-image-id")},
+				OSDisk: infrav1.OSDisk{
+					OSType:     "Windows",
+					DiskSizeGB: ptr.To[int32](128),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: string(armcompute.StorageAccountTypesPremiumLRS),
+					},
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(*result.(armcompute.VirtualMachine).Properties.OSProfile.AdminPassword).Should(Equal("my-password"))
+				g.Expect(*result.(armcompute.VirtualMachine).Properties.OSProfile.AdminUsername).Should(Equal("capiadmin"))
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a linux vm with a custom admin username",
+			spec: &VMSpec{
+				Name:          "my-vm",
+				Role:          infrav1.Node,
+				NICIDs:        []string{"my-nic"},
+				AdminUsername: "azureuser",
+				SSHKeyData:    "fakesshpublickey",
+				Size:          "Standard_D2v3",
+				Zone:          "1",
+				Image:         &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:           validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				osProfile := result.(armcompute.VirtualMachine).Properties.OSProfile
+				g.Expect(*osProfile.AdminUsername).Should(Equal("azureuser"))
+				g.Expect(osProfile.AdminPassword).To(BeNil())
+				g.Expect(*osProfile.LinuxConfiguration.DisablePasswordAuthentication).To(BeTrue())
+				g.Expect(*osProfile.LinuxConfiguration.SSH.PublicKeys[0].Path).To(Equal("/home/azureuser/.ssh/authorized_keys"))
+			},
+			expectedError: "",
+		},
 		{
 			name: "can create a vm with encryption",
 			spec: &VMSpec{
@@ -392,6 +550,32 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm with a zone-redundant os disk",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Zone:       "1",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				OSDisk: infrav1.OSDisk{
+					ZoneRedundant: ptr.To(true),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: string(armcompute.StorageAccountTypesPremiumLRS),
+					},
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.StorageProfile.OSDisk.ManagedDisk.StorageAccountType).To(Equal(ptr.To(armcompute.StorageAccountTypesPremiumZRS)))
+				g.Expect(result.(armcompute.VirtualMachine).Zones).To(Equal([]*string{ptr.To("1")}))
+			},
+			expectedError: "",
+		},
 		{
 			name: "can create a vm with encryption at host",
 			spec: &VMSpec{
@@ -412,6 +596,102 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm with user data",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:        validSKU,
+				UserData:   "Zm9v",
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.UserData).To(Equal(ptr.To("Zm9v")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "fails to create a vm with too much user data",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:        validSKU,
+				UserData:   strings.Repeat("a", maxUserDataLength+4),
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: user data of VM my-vm is 65540 bytes once base64 encoded, which exceeds the maximum of 65536 bytes. Object will not be requeued",
+		},
+		{
+			name: "can create a vm with an os disk created from a snapshot",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				OSDisk: infrav1.OSDisk{
+					OSType:     "Linux",
+					DiskSizeGB: ptr.To[int32](128),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					Source: &infrav1.OSDiskSource{
+						SnapshotID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot"),
+					},
+				},
+				OSDiskID:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-vm_OSDisk",
+				Image:         &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:           validSKU,
+				BootstrapData: "Zm9v",
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				vm := result.(armcompute.VirtualMachine)
+				g.Expect(vm.Properties.OSProfile).To(BeNil())
+				g.Expect(vm.Properties.UserData).To(Equal(ptr.To("Zm9v")))
+				g.Expect(vm.Properties.StorageProfile.ImageReference).To(BeNil())
+				g.Expect(vm.Properties.StorageProfile.OSDisk.CreateOption).To(Equal(ptr.To(armcompute.DiskCreateOptionTypesAttach)))
+				g.Expect(vm.Properties.StorageProfile.OSDisk.DiskSizeGB).To(BeNil())
+				g.Expect(vm.Properties.StorageProfile.OSDisk.ManagedDisk).To(Equal(&armcompute.ManagedDiskParameters{
+					ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-vm_OSDisk"),
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm without custom data when its bootstrap data is passed as user data",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:        validSKU,
+				UserData:   "Zm9v",
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				vm := result.(armcompute.VirtualMachine)
+				g.Expect(vm.Properties.OSProfile.CustomData).To(BeNil())
+				g.Expect(vm.Properties.UserData).To(Equal(ptr.To("Zm9v")))
+			},
+			expectedError: "",
+		},
 		{
 			name: "can create a vm and assign it to an availability set",
 			spec: &VMSpec{
@@ -1057,6 +1337,76 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "reconcile error that cannot be recovered occurred: VM size Standard_D2v3 does not support ultra disks in location test-location. Select a different VM size or disable ultra disks. Object will not be requeued",
 		},
+		{
+			name: "can create a vm with write accelerator enabled",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_M8ms",
+				Location:   "test-location",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				OSDisk: infrav1.OSDisk{
+					OSType:      "Linux",
+					CachingType: string(armcompute.CachingTypesReadWrite),
+				},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix:  "mydisk",
+						DiskSizeGB:  128,
+						Lun:         ptr.To[int32](0),
+						CachingType: string(armcompute.CachingTypesNone),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: string(armcompute.StorageAccountTypesPremiumLRS),
+						},
+						WriteAcceleratorEnabled: ptr.To(true),
+					},
+				},
+				SKU: validSKUWithWriteAccelerator,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				storageProfile := result.(armcompute.VirtualMachine).Properties.StorageProfile
+				g.Expect(storageProfile.OSDisk.WriteAcceleratorEnabled).To(BeNil())
+				g.Expect(storageProfile.DataDisks).To(HaveLen(1))
+				g.Expect(storageProfile.DataDisks[0].Caching).To(Equal(ptr.To(armcompute.CachingTypesNone)))
+				g.Expect(storageProfile.DataDisks[0].WriteAcceleratorEnabled).To(Equal(ptr.To(true)))
+			},
+			expectedError: "",
+		},
+		{
+			name: "creating vm with write accelerator enabled on more disks than the vm size allows fails",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_M8ms",
+				Location:   "test-location",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				OSDisk: infrav1.OSDisk{
+					OSType:                  "Linux",
+					CachingType:             string(armcompute.CachingTypesReadOnly),
+					WriteAcceleratorEnabled: ptr.To(true),
+				},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix:              "mydisk",
+						DiskSizeGB:              128,
+						Lun:                     ptr.To[int32](0),
+						WriteAcceleratorEnabled: ptr.To(true),
+					},
+				},
+				SKU: validSKUWithWriteAccelerator,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: VM size Standard_M8ms does not support write accelerator on 2 disks. Select a different VM size or disable write accelerator. Object will not be requeued",
+		},
 		{
 			name: "creates a vm with AdditionalCapabilities.UltraSSDEnabled false, if an ultra disk is specified as data disk but AdditionalCapabilities.UltraSSDEnabled is false",
 			spec: &VMSpec{
@@ -1189,6 +1539,29 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "creates a vm with AdditionalCapabilities.HibernationEnabled true",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Location:   "test-location",
+				Zone:       "1",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				AdditionalCapabilities: &infrav1.AdditionalCapabilities{
+					HibernationEnabled: ptr.To(true),
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.AdditionalCapabilities.HibernationEnabled).To(Equal(ptr.To(true)))
+			},
+			expectedError: "",
+		},
 		{
 			name: "creates a vm with AdditionalCapabilities.UltraSSDEnabled true, if no ultra disk is specified as data disk and AdditionalCapabilities.UltraSSDEnabled is true",
 			spec: &VMSpec{
@@ -1363,3 +1736,74 @@ func TestParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestSizeParameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          *VMSizeSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name:     "returns nil if vm does not exist",
+			spec:     &VMSizeSpec{Size: "Standard_D4v3"},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:     "fails if existing is not a VirtualMachine",
+			spec:     &VMSizeSpec{Size: "Standard_D4v3"},
+			existing: armnetwork.VirtualNetwork{},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "armnetwork.VirtualNetwork is not an armcompute.VirtualMachine",
+		},
+		{
+			name: "returns nil if vm size is up to date",
+			spec: &VMSizeSpec{Size: "Standard_D4v3"},
+			existing: armcompute.VirtualMachine{
+				Properties: &armcompute.VirtualMachineProperties{
+					HardwareProfile: &armcompute.HardwareProfile{VMSize: ptr.To(armcompute.VirtualMachineSizeTypes("standard_d4v3"))},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "returns the existing vm with the new size",
+			spec: &VMSizeSpec{Size: "Standard_D4v3"},
+			existing: armcompute.VirtualMachine{
+				Name: ptr.To("test-vm"),
+				Properties: &armcompute.VirtualMachineProperties{
+					HardwareProfile: &armcompute.HardwareProfile{VMSize: ptr.To(armcompute.VirtualMachineSizeTypesStandardD2V3)},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Name).To(Equal(ptr.To("test-vm")))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.HardwareProfile.VMSize).To(Equal(ptr.To(armcompute.VirtualMachineSizeTypes("Standard_D4v3"))))
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			tc.expect(g, result)
+		})
+	}
+}
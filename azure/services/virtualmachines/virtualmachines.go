@@ -18,28 +18,70 @@ package virtualmachines
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	azprovider "sigs.k8s.io/cloud-provider-azure/pkg/provider"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/features"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/identities"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/marketplaceagreements"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/quotas"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
-const serviceName = "virtualmachine"
+const (
+	serviceName            = "virtualmachine"
+	resizeServiceName      = "virtualmachinesize"
+	powerOffServiceName    = "virtualmachinepoweroff"
+	deallocateServiceName  = "virtualmachinedeallocate"
+	startServiceName       = "virtualmachinestart"
+	reimageServiceName     = "virtualmachinereimage"
+	detachDisksServiceName = "virtualmachinedetachdisks"
+
+	// shutdownTimeout is how long a VM is given to shut down gracefully before it gets deleted anyway.
+	shutdownTimeout = 5 * time.Minute
+
+	// defaultDataDiskDetachTimeout is how long to wait for the data disks attached by a CSI driver to be detached when
+	// DataDiskDetach.Timeout is not set.
+	defaultDataDiskDetachTimeout = 5 * time.Minute
+
+	// dataDiskDetachRequeue is how long to wait before checking again whether the data disks attached by a CSI driver
+	// have been detached.
+	dataDiskDetachRequeue = 15 * time.Second
+
+	// featureRegistrationRequeue is how long to wait before checking again whether a required subscription feature
+	// has been registered.
+	featureRegistrationRequeue = 5 * time.Minute
+
+	// quotaExceededRequeue is how long to wait before checking again whether there is enough vCPU quota to create a VM.
+	quotaExceededRequeue = 5 * time.Minute
+
+	// marketplaceTermsRequeue is how long to wait before checking again whether the marketplace terms of the image
+	// have been accepted.
+	marketplaceTermsRequeue = 5 * time.Minute
+
+	// zoneFallbackRequeue is how long to wait before creating a VM again in the availability zone substituted for the
+	// one its size is not available in.
+	zoneFallbackRequeue = 5 * time.Second
+)
 
 // VMScope defines the scope interface for a virtual machines service.
 type VMScope interface {
@@ -47,24 +89,36 @@ type VMScope interface {
 	azure.AsyncStatusUpdater
 	VMSpec() azure.ResourceSpecGetter
 	SetAnnotation(string, string)
+	ProviderID() string
 	SetProviderID(string)
 	SetAddresses([]corev1.NodeAddress)
 	SetVMState(infrav1.ProvisioningState)
+	SetPowerState(infrav1.VMPowerState)
+	SetVMStatus(*infrav1.VMStatus)
+	DesiredPowerState() infrav1.VMPowerState
+	Reimaged() bool
+	SetReimaged()
 	SetConditionFalse(clusterv1.ConditionType, string, clusterv1.ConditionSeverity, string)
+	AuxiliaryTenantIDs() []string
+	SubstituteZone(reason string) string
 }
 
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope VMScope
 	async.Reconciler
+	client           Client
 	interfacesGetter async.Getter
 	publicIPsGetter  async.Getter
 	identitiesGetter identities.Client
+	featuresGetter   features.Client
+	quotasGetter     quotas.Client
+	agreementsGetter marketplaceagreements.Client
 }
 
 // New creates a new service.
 func New(scope VMScope) (*Service, error) {
-	Client, err := NewClient(scope)
+	Client, err := NewClient(scope, scope.AuxiliaryTenantIDs()...)
 	if err != nil {
 		return nil, err
 	}
@@ -80,11 +134,27 @@ func New(scope VMScope) (*Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	featuresSvc, err := features.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	quotasSvc, err := quotas.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	agreementsSvc, err := marketplaceagreements.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:            scope,
+		client:           Client,
 		interfacesGetter: interfacesSvc,
 		publicIPsGetter:  publicIPsSvc,
 		identitiesGetter: identitiesSvc,
+		featuresGetter:   featuresSvc,
+		quotasGetter:     quotasSvc,
+		agreementsGetter: agreementsSvc,
 		Reconciler: async.New[armcompute.VirtualMachinesClientCreateOrUpdateResponse,
 			armcompute.VirtualMachinesClientDeleteResponse](scope, Client, Client),
 	}, nil
@@ -108,7 +178,32 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		return nil
 	}
 
+	vmSpec = s.fallBackToAvailableZone(ctx, vmSpec)
+
+	if err := s.checkEncryptionAtHost(ctx, vmSpec); err != nil {
+		s.Scope.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, err)
+		return err
+	}
+
+	if err := s.checkVCPUQuota(ctx, vmSpec); err != nil {
+		return err
+	}
+
+	if err := s.checkMarketplaceTerms(ctx, vmSpec); err != nil {
+		return err
+	}
+
 	result, err := s.CreateOrUpdateResource(ctx, vmSpec, serviceName)
+	if spec, ok := vmSpec.(*VMSpec); ok && spec.Zone != "" && azure.IsSKUNotAvailableError(err) {
+		if zone := s.substituteZone(ctx, spec, fmt.Sprintf("Azure rejected VM size %s in zone %s of location %s", spec.Size, spec.Zone, spec.Location)); zone != "" {
+			err = azure.WithTransientError(errors.Errorf("VM size %s is not available in zone %s, creating VM %s in zone %s instead", spec.Size, spec.Zone, spec.Name, zone), zoneFallbackRequeue)
+		}
+	}
+	// A VM which Azure refuses to create, e.g. because of an exceeded quota, fails its machine so that it is replaced.
+	// Errors updating an existing VM are retried instead.
+	if azure.IsTerminalAzureError(err) && s.Scope.ProviderID() == "" {
+		err = azure.WithTerminalError(err)
+	}
 	s.Scope.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, err)
 	// Set the DiskReady condition here since the disk gets created with the VM.
 	s.Scope.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, err)
@@ -133,20 +228,212 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 		s.Scope.SetAddresses(addresses)
 		s.Scope.SetVMState(infraVM.State)
+		s.Scope.SetPowerState(infraVM.PowerState)
+		s.Scope.SetVMStatus(&infrav1.VMStatus{
+			ID:                  infraVM.ID,
+			NetworkInterfaceIDs: infraVM.NetworkInterfaceIDs,
+			OSDiskID:            infraVM.OSDiskID,
+			Image:               infraVM.Image,
+			Zone:                infraVM.AvailabilityZone,
+			VMID:                infraVM.VMID,
+			Size:                infraVM.VMSize,
+			PlatformFaultDomain: infraVM.PlatformFaultDomain,
+		})
 
 		spec, ok := vmSpec.(*VMSpec)
 		if !ok {
 			return errors.Errorf("%T is not a valid VM spec", vmSpec)
 		}
 
+		if err := s.reimageFailedVM(ctx, spec, infraVM.State); err != nil {
+			return err
+		}
+
 		err = s.checkUserAssignedIdentities(ctx, spec.UserAssignedIdentities, infraVM.UserAssignedIdentities)
 		if err != nil {
 			return errors.Wrap(err, "failed to check user assigned identities")
 		}
+
+		if err := s.reconcileSize(ctx, spec, vm); err != nil {
+			return err
+		}
+
+		err = s.reconcilePowerState(ctx, spec, infraVM.PowerState)
 	}
 	return err
 }
 
+// reimageFailedVM reimages a VM in the Failed provisioning state once when the machine opted into it. A VM that is
+// still failed after it has been reimaged is reported as failed.
+func (s *Service) reimageFailedVM(ctx context.Context, spec *VMSpec, state infrav1.ProvisioningState) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.reimageFailedVM")
+	defer done()
+
+	existingFuture := s.Scope.GetLongRunningOperationState(spec.Name, reimageServiceName, infrav1.PostFuture)
+	if existingFuture == nil {
+		if !spec.ReimageOnFailure || state != infrav1.Failed {
+			return nil
+		}
+		if s.Scope.Reimaged() {
+			return azure.WithTerminalError(errors.Errorf("VM %s is in %s provisioning state after being reimaged", spec.Name, state))
+		}
+		// The reimage is only attempted once, even if it fails.
+		s.Scope.SetReimaged()
+	}
+
+	log.V(2).Info("reimaging VM in Failed provisioning state")
+	customData := spec.BootstrapData
+	if spec.OSDisk.DiffDiskSettings != nil {
+		// Ephemeral OS disks are reset to their initial state, including the custom data the VM was created with.
+		customData = ""
+	}
+	err := postAsync(ctx, s.Scope, spec, reimageServiceName, existingFuture, func(ctx context.Context, resumeToken string) (*runtime.Poller[armcompute.VirtualMachinesClientReimageResponse], error) {
+		return s.client.ReimageAsync(ctx, spec, resumeToken, customData)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to reimage VM")
+	}
+	// Requeue to get the provisioning state of the reimaged VM.
+	return azure.WithTransientError(errors.Errorf("VM %s was reimaged after it failed to provision", spec.Name), reconciler.DefaultReconcilerRequeue)
+}
+
+// reconcileSize resizes an existing VM whose size no longer matches the spec. Azure stops the VM, applies the new
+// size and starts it again, which is only possible when the new size is available on the hardware cluster currently
+// hosting the VM.
+func (s *Service) reconcileSize(ctx context.Context, spec *VMSpec, vm armcompute.VirtualMachine) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.reconcileSize")
+	defer done()
+
+	// A resize already in progress is resumed without checking the size again.
+	if s.Scope.GetLongRunningOperationState(spec.Name, resizeServiceName, infrav1.PutFuture) == nil {
+		currentSize := vmSize(vm)
+		if currentSize == "" || strings.EqualFold(currentSize, spec.Size) {
+			return nil
+		}
+
+		if err := spec.validateSize(); err != nil {
+			return errors.Wrapf(err, "failed to resize VM %s to %s", spec.Name, spec.Size)
+		}
+		available, err := s.client.ListAvailableSizes(ctx, spec.ResourceGroup, spec.Name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list the sizes available for VM %s", spec.Name)
+		}
+		if !slices.ContainsFunc(available, func(size string) bool { return strings.EqualFold(size, spec.Size) }) {
+			return errors.Errorf("VM size %s is not available on the hardware cluster hosting VM %s. Select a different VM size or replace the machine", spec.Size, spec.Name)
+		}
+		log.V(2).Info("resizing VM", "from", currentSize, "to", spec.Size)
+	}
+
+	_, err := s.CreateOrUpdateResource(ctx, &VMSizeSpec{
+		Name:          spec.Name,
+		ResourceGroup: spec.ResourceGroup,
+		Size:          spec.Size,
+	}, resizeServiceName)
+	s.Scope.UpdatePatchStatus(infrav1.VMRunningCondition, resizeServiceName, err)
+	return err
+}
+
+// checkEncryptionAtHost returns an error when a VM that is not created yet requests encryption at host but the
+// EncryptionAtHost feature is not registered on the subscription, since Azure would reject its creation.
+func (s *Service) checkEncryptionAtHost(ctx context.Context, vmSpec azure.ResourceSpecGetter) error {
+	spec, ok := vmSpec.(*VMSpec)
+	if !ok || spec.SecurityProfile == nil || !ptr.Deref(spec.SecurityProfile.EncryptionAtHost, false) || s.Scope.ProviderID() != "" {
+		return nil
+	}
+
+	registered, err := s.featuresGetter.IsRegistered(ctx, features.ComputeNamespace, features.EncryptionAtHost)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check the registration of the %s feature", features.EncryptionAtHost)
+	}
+	if !registered {
+		return azure.WithTransientError(errors.Errorf("the %s/%s feature must be registered on the subscription to create VMs with encryption at host enabled", features.ComputeNamespace, features.EncryptionAtHost), featureRegistrationRequeue)
+	}
+
+	return nil
+}
+
+// checkVCPUQuota returns an error when creating a VM would exceed the vCPU quota of the subscription, since Azure
+// would reject its creation. The check is skipped once the VM exists or is being created, as its vCPUs then already
+// count against the quota.
+func (s *Service) checkVCPUQuota(ctx context.Context, vmSpec azure.ResourceSpecGetter) error {
+	spec, ok := vmSpec.(*VMSpec)
+	if !ok || spec.SKU.Name == nil || s.Scope.ProviderID() != "" ||
+		s.Scope.GetLongRunningOperationState(spec.Name, serviceName, infrav1.PutFuture) != nil {
+		return nil
+	}
+
+	exceeded, err := quotas.CheckVCPUs(ctx, s.quotasGetter, spec.Location, spec.SKU, 1, spec.SpotVMOptions != nil)
+	if err != nil {
+		err = errors.Wrap(err, "failed to check the vCPU quota")
+		s.Scope.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, err)
+		return err
+	}
+	if len(exceeded) > 0 {
+		message := fmt.Sprintf("not enough vCPU quota to create VM %s: %s", spec.Name, strings.Join(exceeded, "; "))
+		s.Scope.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VCPUQuotaExceededReason, clusterv1.ConditionSeverityError, message)
+		return azure.WithTransientError(errors.New(message), quotaExceededRequeue)
+	}
+
+	return nil
+}
+
+// fallBackToAvailableZone returns the spec of a VM that is not created yet in another availability zone, when its size
+// is known not to be available in its zone and the machine allows zone fallback. Otherwise it returns the given spec,
+// which fails to validate if the size is not available.
+func (s *Service) fallBackToAvailableZone(ctx context.Context, vmSpec azure.ResourceSpecGetter) azure.ResourceSpecGetter {
+	spec, ok := vmSpec.(*VMSpec)
+	if !ok || spec.Zone == "" || spec.SKU.IsZoneAvailable(spec.Location, spec.Zone) {
+		return vmSpec
+	}
+	if s.substituteZone(ctx, spec, fmt.Sprintf("VM size %s is not available in zone %s of location %s", spec.Size, spec.Zone, spec.Location)) == "" {
+		return vmSpec
+	}
+	return s.Scope.VMSpec()
+}
+
+// substituteZone substitutes another availability zone for the zone of a VM that is not created yet, and returns it.
+// It returns an empty string if the zone is kept.
+func (s *Service) substituteZone(ctx context.Context, spec *VMSpec, reason string) string {
+	_, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.substituteZone")
+	defer done()
+
+	if s.Scope.ProviderID() != "" || s.Scope.GetLongRunningOperationState(spec.Name, serviceName, infrav1.PutFuture) != nil {
+		return ""
+	}
+	zone := s.Scope.SubstituteZone(reason)
+	if zone != "" {
+		log.Info("substituting another availability zone for the zone of the VM", "vm", spec.Name, "zone", zone, "reason", reason)
+	}
+	return zone
+}
+
+// checkMarketplaceTerms returns an error when a VM that is not created yet uses an image with a purchase plan whose
+// marketplace terms are not accepted on the subscription, since Azure would reject its creation.
+func (s *Service) checkMarketplaceTerms(ctx context.Context, vmSpec azure.ResourceSpecGetter) error {
+	spec, ok := vmSpec.(*VMSpec)
+	if !ok {
+		return nil
+	}
+	plan := converters.ImageToPlan(spec.Image)
+	if plan == nil || s.Scope.ProviderID() != "" ||
+		s.Scope.GetLongRunningOperationState(spec.Name, serviceName, infrav1.PutFuture) != nil {
+		return nil
+	}
+
+	message, err := marketplaceagreements.EnsureAccepted(ctx, s.agreementsGetter, plan)
+	if err != nil {
+		err = errors.Wrap(err, "failed to check the marketplace terms of the image")
+		s.Scope.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, err)
+		return err
+	}
+	if message != "" {
+		s.Scope.SetConditionFalse(infrav1.VMRunningCondition, infrav1.MarketplaceTermsNotAcceptedReason, clusterv1.ConditionSeverityError, message)
+		return azure.WithTransientError(errors.New(message), marketplaceTermsRequeue)
+	}
+
+	return nil
+}
+
 // Delete deletes the virtual machine with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.Delete")
@@ -160,6 +447,18 @@ func (s *Service) Delete(ctx context.Context) error {
 		return nil
 	}
 
+	if err := s.detachDataDisks(ctx, vmSpec); err != nil {
+		s.Scope.SetVMState(infrav1.Deleting)
+		s.Scope.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, err)
+		return err
+	}
+
+	if err := s.powerOff(ctx, vmSpec); err != nil {
+		s.Scope.SetVMState(infrav1.Deleting)
+		s.Scope.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, err)
+		return err
+	}
+
 	err := s.DeleteResource(ctx, vmSpec, serviceName)
 	if err != nil {
 		s.Scope.SetVMState(infrav1.Deleting)
@@ -170,6 +469,209 @@ func (s *Service) Delete(ctx context.Context) error {
 	return err
 }
 
+// powerOff gracefully shuts down a VM before it gets deleted, so the kubelet and workloads can terminate cleanly and
+// its disks get detached. Shutting down is best effort: the VM is deleted anyway when powering it off fails or takes
+// longer than shutdownTimeout.
+func (s *Service) powerOff(ctx context.Context, vmSpec azure.ResourceSpecGetter) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.powerOff")
+	defer done()
+
+	spec, ok := vmSpec.(*VMSpec)
+	if !ok || spec.SkipShutdown {
+		return nil
+	}
+	// Once the deletion started, there is no need to power off the VM anymore.
+	if s.Scope.GetLongRunningOperationState(spec.Name, serviceName, infrav1.DeleteFuture) != nil {
+		return nil
+	}
+
+	existingFuture := s.Scope.GetLongRunningOperationState(spec.Name, powerOffServiceName, infrav1.PostFuture)
+	if existingFuture != nil && existingFuture.StartTime != nil && time.Since(existingFuture.StartTime.Time) > shutdownTimeout {
+		log.Info("VM did not shut down in time, deleting it anyway", "timeout", shutdownTimeout)
+		s.Scope.DeleteLongRunningOperationState(spec.Name, powerOffServiceName, infrav1.PostFuture)
+		return nil
+	}
+
+	err := postAsync(ctx, s.Scope, spec, powerOffServiceName, existingFuture, func(ctx context.Context, resumeToken string) (*runtime.Poller[armcompute.VirtualMachinesClientPowerOffResponse], error) {
+		return s.client.PowerOffAsync(ctx, spec, resumeToken)
+	})
+	if azure.IsOperationNotDoneError(err) {
+		return err
+	}
+	if err != nil && !azure.ResourceNotFound(err) {
+		log.Info("failed to power off VM, deleting it anyway", "error", err.Error())
+	}
+	return nil
+}
+
+// detachDataDisks waits for the data disks attached to a VM by a CSI driver to be detached before the VM gets deleted.
+// Once DataDiskDetach.Timeout elapsed, the disks still attached are force-detached or left attached to the VM being
+// deleted, depending on DataDiskDetach.Policy.
+func (s *Service) detachDataDisks(ctx context.Context, vmSpec azure.ResourceSpecGetter) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.detachDataDisks")
+	defer done()
+
+	spec, ok := vmSpec.(*VMSpec)
+	if !ok || spec.DataDiskDetach == nil {
+		return nil
+	}
+	// Once the deletion started, the disks are detached along with the VM.
+	if s.Scope.GetLongRunningOperationState(spec.Name, serviceName, infrav1.DeleteFuture) != nil {
+		return nil
+	}
+
+	var update armcompute.VirtualMachineUpdate
+	existingFuture := s.Scope.GetLongRunningOperationState(spec.Name, detachDisksServiceName, infrav1.PatchFuture)
+	if existingFuture == nil {
+		existing, err := s.client.Get(ctx, spec)
+		if azure.ResourceNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to get VM")
+		}
+		vm, ok := existing.(armcompute.VirtualMachine)
+		if !ok {
+			return errors.Errorf("%T is not an armcompute.VirtualMachine", existing)
+		}
+		attached := spec.csiDataDisks(vm)
+		if len(attached) == 0 {
+			return nil
+		}
+
+		timeout := defaultDataDiskDetachTimeout
+		if spec.DataDiskDetach.Timeout != nil {
+			timeout = spec.DataDiskDetach.Timeout.Duration
+		}
+		if spec.DeletionTimestamp != nil && time.Since(spec.DeletionTimestamp.Time) < timeout {
+			log.V(2).Info("waiting for data disks to be detached", "disks", attached)
+			return azure.WithTransientError(errors.Errorf("waiting for data disks %s to be detached", strings.Join(attached, ", ")), dataDiskDetachRequeue)
+		}
+		if spec.DataDiskDetach.Policy != infrav1.DataDiskDetachForceDetach {
+			log.Info("data disks were not detached in time, deleting the VM anyway", "disks", attached, "timeout", timeout)
+			return nil
+		}
+		log.Info("data disks were not detached in time, force-detaching them", "disks", attached, "timeout", timeout)
+		update = forceDetachUpdate(vm, attached)
+	}
+
+	err := trackAsync(ctx, s.Scope, spec, detachDisksServiceName, infrav1.PatchFuture, existingFuture, func(ctx context.Context, resumeToken string) (*runtime.Poller[armcompute.VirtualMachinesClientUpdateResponse], error) {
+		return s.client.UpdateAsync(ctx, spec, resumeToken, update)
+	})
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrap(err, "failed to force-detach data disks")
+	}
+	return nil
+}
+
+// forceDetachUpdate returns the update force-detaching the given data disks from a VM. The other data disks are kept
+// as they are, since the update replaces the whole list of data disks.
+func forceDetachUpdate(vm armcompute.VirtualMachine, disks []string) armcompute.VirtualMachineUpdate {
+	dataDisks := []*armcompute.DataDisk{}
+	if vm.Properties != nil && vm.Properties.StorageProfile != nil {
+		for _, disk := range vm.Properties.StorageProfile.DataDisks {
+			if disk == nil {
+				continue
+			}
+			disk := *disk
+			if slices.Contains(disks, ptr.Deref(disk.Name, "")) {
+				disk.ToBeDetached = ptr.To(true)
+				disk.DetachOption = ptr.To(armcompute.DiskDetachOptionTypesForceDetach)
+			}
+			dataDisks = append(dataDisks, &disk)
+		}
+	}
+	return armcompute.VirtualMachineUpdate{
+		Properties: &armcompute.VirtualMachineProperties{
+			StorageProfile: &armcompute.StorageProfile{DataDisks: dataDisks},
+		},
+	}
+}
+
+// reconcilePowerState deallocates, hibernates or starts a VM to reach the power state requested through the
+// VMPowerStateAnnotation. The power state is left untouched when the annotation is not set. A hibernated VM is
+// deallocated already, so it is left hibernated when the VM should be deallocated.
+func (s *Service) reconcilePowerState(ctx context.Context, spec *VMSpec, current infrav1.VMPowerState) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.reconcilePowerState")
+	defer done()
+
+	switch s.Scope.DesiredPowerState() {
+	case infrav1.VMPowerStateDeallocated:
+		existingFuture := s.Scope.GetLongRunningOperationState(spec.Name, deallocateServiceName, infrav1.PostFuture)
+		if existingFuture == nil && (current == infrav1.VMPowerStateDeallocated || current == infrav1.VMPowerStateHibernated) {
+			return nil
+		}
+		log.V(2).Info("deallocating VM", "powerState", current)
+		err := postAsync(ctx, s.Scope, spec, deallocateServiceName, existingFuture, func(ctx context.Context, resumeToken string) (*runtime.Poller[armcompute.VirtualMachinesClientDeallocateResponse], error) {
+			return s.client.DeallocateAsync(ctx, spec, resumeToken, false)
+		})
+		return errors.Wrap(err, "failed to deallocate VM")
+	case infrav1.VMPowerStateHibernated:
+		existingFuture := s.Scope.GetLongRunningOperationState(spec.Name, deallocateServiceName, infrav1.PostFuture)
+		// A deallocated VM cannot be hibernated without starting it, so it is left deallocated.
+		if existingFuture == nil && (current == infrav1.VMPowerStateHibernated || current == infrav1.VMPowerStateDeallocated) {
+			return nil
+		}
+		log.V(2).Info("hibernating VM", "powerState", current)
+		err := postAsync(ctx, s.Scope, spec, deallocateServiceName, existingFuture, func(ctx context.Context, resumeToken string) (*runtime.Poller[armcompute.VirtualMachinesClientDeallocateResponse], error) {
+			return s.client.DeallocateAsync(ctx, spec, resumeToken, true)
+		})
+		return errors.Wrap(err, "failed to hibernate VM")
+	case infrav1.VMPowerStateRunning:
+		existingFuture := s.Scope.GetLongRunningOperationState(spec.Name, startServiceName, infrav1.PostFuture)
+		if existingFuture == nil && current != infrav1.VMPowerStateStopped && current != infrav1.VMPowerStateDeallocated && current != infrav1.VMPowerStateHibernated {
+			return nil
+		}
+		log.V(2).Info("starting VM", "powerState", current)
+		err := postAsync(ctx, s.Scope, spec, startServiceName, existingFuture, func(ctx context.Context, resumeToken string) (*runtime.Poller[armcompute.VirtualMachinesClientStartResponse], error) {
+			return s.client.StartAsync(ctx, spec, resumeToken)
+		})
+		return errors.Wrap(err, "failed to start VM")
+	default:
+		return nil
+	}
+}
+
+// postAsync starts a POST long-running operation on a VM, or resumes the one tracked by existingFuture, and keeps
+// track of it in the scope until it completes.
+func postAsync[T any](ctx context.Context, scope VMScope, spec *VMSpec, service string, existingFuture *infrav1.Future, begin func(context.Context, string) (*runtime.Poller[T], error)) error {
+	return trackAsync(ctx, scope, spec, service, infrav1.PostFuture, existingFuture, begin)
+}
+
+// trackAsync starts a long-running operation of the given future type on a VM, or resumes the one tracked by
+// existingFuture, and keeps track of it in the scope until it completes.
+func trackAsync[T any](ctx context.Context, scope VMScope, spec *VMSpec, service string, futureType string, existingFuture *infrav1.Future, begin func(context.Context, string) (*runtime.Poller[T], error)) error {
+	resumeToken := ""
+	if existingFuture != nil {
+		t, err := converters.FutureToResumeToken(*existingFuture)
+		if err != nil {
+			scope.DeleteLongRunningOperationState(spec.Name, service, futureType)
+			return errors.Wrap(err, "could not decode future data, resetting long-running operation state")
+		}
+		resumeToken = t
+	}
+
+	poller, err := begin(ctx, resumeToken)
+	if poller != nil && azure.IsContextDeadlineExceededOrCanceledError(err) {
+		future, err := converters.PollerToFuture(poller, futureType, service, spec.Name, spec.ResourceGroup)
+		if err != nil {
+			return errors.Wrapf(err, "failed to track long-running operation (service: %s)", service)
+		}
+		if existingFuture != nil && existingFuture.StartTime != nil {
+			future.StartTime = existingFuture.StartTime.DeepCopy()
+		} else {
+			now := metav1.Now()
+			future.StartTime = &now
+		}
+		scope.SetLongRunningOperationState(future)
+		return azure.WithTransientError(azure.NewOperationNotDoneError(future), reconciler.DefaultReconcilerRequeue)
+	}
+
+	// Once the operation is done, delete the long-running operation state, even if it ended with an error.
+	scope.DeleteLongRunningOperationState(spec.Name, service, futureType)
+	return err
+}
+
 func (s *Service) checkUserAssignedIdentities(ctx context.Context, specIdentities []infrav1.UserAssignedIdentity, vmIdentities []infrav1.UserAssignedIdentity) error {
 	expectedMap := make(map[string]struct{})
 	actualMap := make(map[string]struct{})
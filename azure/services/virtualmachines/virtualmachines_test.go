@@ -18,9 +18,15 @@ package virtualmachines
 
 import (
 	"context"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/Azure/go-autorest/autorest"
@@ -28,14 +34,23 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/features"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/features/mock_features"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/identities/mock_identities"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/marketplaceagreements/mock_marketplaceagreements"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/quotas"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/quotas/mock_quotas"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines/mock_virtualmachines"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
@@ -60,6 +75,11 @@ var (
 		Name: ptr.To("test-vm-name"),
 		Properties: &armcompute.VirtualMachineProperties{
 			ProvisioningState: ptr.To("Succeeded"),
+			VMID:              ptr.To("4a1c6b1e-0d0f-4a8e-9b8a-2b2f6c1d0e3f"),
+			HardwareProfile:   &armcompute.HardwareProfile{VMSize: ptr.To(armcompute.VirtualMachineSizeTypes("Standard_Fake_Size"))},
+			InstanceView: &armcompute.VirtualMachineInstanceView{
+				PlatformFaultDomain: ptr.To[int32](1),
+			},
 			NetworkProfile: &armcompute.NetworkProfile{
 				NetworkInterfaces: []*armcompute.NetworkInterfaceReference{
 					{
@@ -117,9 +137,24 @@ var (
 		ProviderID: "fake-provider-id-2",
 	}
 	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+	notFoundError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "Not Found")
 )
 
 func TestReconcileVM(t *testing.T) {
+	zonalVMSpec := fakeVMSpec
+	zonalVMSpec.AvailabilitySetID = ""
+	zonalVMSpec.Zone = "1"
+	skuNotAvailableError := azure.WithTransientError(&azcore.ResponseError{
+		StatusCode: http.StatusConflict,
+		ErrorCode:  "SkuNotAvailable",
+		RawResponse: &http.Response{
+			StatusCode: http.StatusConflict,
+			Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{}},
+			Body:       io.NopCloser(strings.NewReader(`{"error":{"code":"SkuNotAvailable","message":"The requested VM size Standard_Fake_Size is currently not available in location 'test-location' zones '1'."}}`)),
+		},
+	}, reconciler.DefaultHopelessErrorRequeue)
+	skuNotAvailableFailure := azure.WithTerminalError(skuNotAvailableError)
+
 	testcases := []struct {
 		name          string
 		expectedError string
@@ -146,6 +181,17 @@ func TestReconcileVM(t *testing.T) {
 				mpip.Get(gomockinternal.AContext(), &fakePublicIPSpec).Return(fakePublicIPs, nil)
 				s.SetAddresses(fakeNodeAddresses)
 				s.SetVMState(infrav1.Succeeded)
+				s.SetPowerState(infrav1.VMPowerState(""))
+				s.SetVMStatus(&infrav1.VMStatus{
+					ID:                  "subscriptions/123/resourceGroups/my_resource_group/providers/Microsoft.Compute/virtualMachines/my-vm",
+					NetworkInterfaceIDs: []string{"/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.Network/networkInterfaces/nic-1"},
+					VMID:                "4a1c6b1e-0d0f-4a8e-9b8a-2b2f6c1d0e3f",
+					Size:                "Standard_Fake_Size",
+					PlatformFaultDomain: ptr.To[int32](1),
+				})
+				s.GetLongRunningOperationState("test-vm", reimageServiceName, infrav1.PostFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", resizeServiceName, infrav1.PutFuture).Return(nil)
+				s.DesiredPowerState().Return(infrav1.VMPowerState(""))
 			},
 		},
 		{
@@ -158,6 +204,43 @@ func TestReconcileVM(t *testing.T) {
 				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, internalError)
 			},
 		},
+		{
+			name:          "creating vm in another zone after its size is rejected in its zone",
+			expectedError: "VM size Standard_Fake_Size is not available in zone 1, creating VM test-vm in zone 2 instead. Object will be requeued after 5s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().Return(&zonalVMSpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &zonalVMSpec, serviceName).Return(nil, skuNotAvailableError)
+				s.ProviderID().Return("")
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.PutFuture).Return(nil)
+				s.SubstituteZone("Azure rejected VM size Standard_Fake_Size in zone 1 of location test-location").Return("2")
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, gomock.Any())
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "creating vm fails when its size is rejected in its zone and no other zone can be used",
+			expectedError: skuNotAvailableFailure.Error(),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().Return(&zonalVMSpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &zonalVMSpec, serviceName).Return(nil, skuNotAvailableError)
+				s.ProviderID().Return("").Times(2)
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.PutFuture).Return(nil)
+				s.SubstituteZone("Azure rejected VM size Standard_Fake_Size in zone 1 of location test-location").Return("")
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, skuNotAvailableFailure)
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, skuNotAvailableFailure)
+			},
+		},
+		{
+			name:          "updating vm is retried when its size is rejected",
+			expectedError: skuNotAvailableError.Error(),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().Return(&fakeVMSpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(nil, skuNotAvailableError)
+				s.ProviderID().Return("azure://subscriptions/123/resourceGroups/my_resource_group/providers/Microsoft.Compute/virtualMachines/my-vm")
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, skuNotAvailableError)
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, skuNotAvailableError)
+			},
+		},
 		{
 			name:          "create vm succeeds but failed to get network interfaces",
 			expectedError: "failed to fetch VM addresses: #: Internal Server Error: StatusCode=500",
@@ -220,24 +303,806 @@ func TestReconcileVM(t *testing.T) {
 	}
 }
 
+func TestReconcileSize(t *testing.T) {
+	resizeVMSpec := fakeVMSpec
+	resizeVMSpec.Size = "Standard_D4v3"
+	resizeVMSpec.SKU = validSKU
+	upToDateVMSpec := fakeVMSpec
+	upToDateVMSpec.Size = "Standard_D2_v3"
+	resizeVMSizeSpec := &VMSizeSpec{Name: "test-vm", ResourceGroup: "test-group", Size: "Standard_D4v3"}
+
+	existingVM := fakeExistingVM
+	existingVM.Properties = &armcompute.VirtualMachineProperties{
+		HardwareProfile: &armcompute.HardwareProfile{VMSize: ptr.To(armcompute.VirtualMachineSizeTypesStandardD2V3)},
+	}
+
+	testcases := []struct {
+		name          string
+		spec          *VMSpec
+		expectedError string
+		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name: "noop if vm size is up to date",
+			spec: &upToDateVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", resizeServiceName, infrav1.PutFuture).Return(nil)
+			},
+		},
+		{
+			name: "resizes vm when the new size is available",
+			spec: &resizeVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", resizeServiceName, infrav1.PutFuture).Return(nil)
+				c.ListAvailableSizes(gomockinternal.AContext(), "test-group", "test-vm").Return([]string{"Standard_D2_v3", "Standard_D4v3"}, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), resizeVMSizeSpec, resizeServiceName).Return(existingVM, nil)
+				s.UpdatePatchStatus(infrav1.VMRunningCondition, resizeServiceName, nil)
+			},
+		},
+		{
+			name:          "fails if the new size is not available on the current hardware cluster",
+			spec:          &resizeVMSpec,
+			expectedError: "VM size Standard_D4v3 is not available on the hardware cluster hosting VM test-vm. Select a different VM size or replace the machine",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", resizeServiceName, infrav1.PutFuture).Return(nil)
+				c.ListAvailableSizes(gomockinternal.AContext(), "test-group", "test-vm").Return([]string{"Standard_D2_v3"}, nil)
+			},
+		},
+		{
+			name:          "fails if the new size lacks required capabilities",
+			spec:          &fakeVMSpec,
+			expectedError: "failed to resize VM test-vm to Standard_Fake_Size: VM size should be bigger or equal to at least 2 vCPUs",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", resizeServiceName, infrav1.PutFuture).Return(nil)
+			},
+		},
+		{
+			name:          "fails if the available sizes can't be listed",
+			spec:          &resizeVMSpec,
+			expectedError: "failed to list the sizes available for VM test-vm: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", resizeServiceName, infrav1.PutFuture).Return(nil)
+				c.ListAvailableSizes(gomockinternal.AContext(), "test-group", "test-vm").Return(nil, internalError)
+			},
+		},
+		{
+			name: "resumes a resize in progress",
+			spec: &resizeVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", resizeServiceName, infrav1.PutFuture).Return(&infrav1.Future{})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), resizeVMSizeSpec, resizeServiceName).Return(nil, internalError)
+				s.UpdatePatchStatus(infrav1.VMRunningCondition, resizeServiceName, internalError)
+			},
+			expectedError: "#: Internal Server Error: StatusCode=500",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				client:     clientMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.reconcileSize(context.TODO(), tc.spec, existingVM)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcilePowerState(t *testing.T) {
+	testcases := []struct {
+		name          string
+		current       infrav1.VMPowerState
+		expectedError string
+		expect        func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder)
+	}{
+		{
+			name:    "noop if no power state is requested",
+			current: infrav1.VMPowerStateStopped,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerState(""))
+			},
+		},
+		{
+			name:    "noop if vm is already deallocated",
+			current: infrav1.VMPowerStateDeallocated,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateDeallocated)
+				s.GetLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture).Return(nil)
+			},
+		},
+		{
+			name:    "deallocates a running vm",
+			current: infrav1.VMPowerStateRunning,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateDeallocated)
+				s.GetLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture).Return(nil)
+				c.DeallocateAsync(gomockinternal.AContext(), &fakeVMSpec, "", false).Return(nil, nil)
+				s.DeleteLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture)
+			},
+		},
+		{
+			name:          "waits for a vm to deallocate",
+			current:       infrav1.VMPowerStateRunning,
+			expectedError: "failed to deallocate VM: operation type POST on Azure resource test-group/test-vm is not done. Object will be requeued after 15s",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateDeallocated)
+				s.GetLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture).Return(nil)
+				c.DeallocateAsync(gomockinternal.AContext(), &fakeVMSpec, "", false).Return(fakePoller[armcompute.VirtualMachinesClientDeallocateResponse](g), context.DeadlineExceeded)
+				s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{}))
+			},
+		},
+		{
+			name:          "fails to deallocate a vm",
+			current:       infrav1.VMPowerStateRunning,
+			expectedError: "failed to deallocate VM: #: Internal Server Error: StatusCode=500",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateDeallocated)
+				s.GetLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture).Return(nil)
+				c.DeallocateAsync(gomockinternal.AContext(), &fakeVMSpec, "", false).Return(nil, internalError)
+				s.DeleteLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture)
+			},
+		},
+		{
+			name:    "noop if vm is hibernated and should be deallocated",
+			current: infrav1.VMPowerStateHibernated,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateDeallocated)
+				s.GetLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture).Return(nil)
+			},
+		},
+		{
+			name:    "hibernates a running vm",
+			current: infrav1.VMPowerStateRunning,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateHibernated)
+				s.GetLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture).Return(nil)
+				c.DeallocateAsync(gomockinternal.AContext(), &fakeVMSpec, "", true).Return(nil, nil)
+				s.DeleteLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture)
+			},
+		},
+		{
+			name:          "fails to hibernate a vm",
+			current:       infrav1.VMPowerStateRunning,
+			expectedError: "failed to hibernate VM: #: Internal Server Error: StatusCode=500",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateHibernated)
+				s.GetLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture).Return(nil)
+				c.DeallocateAsync(gomockinternal.AContext(), &fakeVMSpec, "", true).Return(nil, internalError)
+				s.DeleteLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture)
+			},
+		},
+		{
+			name:    "noop if vm is already hibernated",
+			current: infrav1.VMPowerStateHibernated,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateHibernated)
+				s.GetLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture).Return(nil)
+			},
+		},
+		{
+			name:    "leaves a deallocated vm deallocated when it should be hibernated",
+			current: infrav1.VMPowerStateDeallocated,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateHibernated)
+				s.GetLongRunningOperationState("test-vm", deallocateServiceName, infrav1.PostFuture).Return(nil)
+			},
+		},
+		{
+			name:    "noop if vm is already running",
+			current: infrav1.VMPowerStateRunning,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateRunning)
+				s.GetLongRunningOperationState("test-vm", startServiceName, infrav1.PostFuture).Return(nil)
+			},
+		},
+		{
+			name:    "starts a deallocated vm",
+			current: infrav1.VMPowerStateDeallocated,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateRunning)
+				s.GetLongRunningOperationState("test-vm", startServiceName, infrav1.PostFuture).Return(nil)
+				c.StartAsync(gomockinternal.AContext(), &fakeVMSpec, "").Return(nil, nil)
+				s.DeleteLongRunningOperationState("test-vm", startServiceName, infrav1.PostFuture)
+			},
+		},
+		{
+			name:    "starts a hibernated vm",
+			current: infrav1.VMPowerStateHibernated,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateRunning)
+				s.GetLongRunningOperationState("test-vm", startServiceName, infrav1.PostFuture).Return(nil)
+				c.StartAsync(gomockinternal.AContext(), &fakeVMSpec, "").Return(nil, nil)
+				s.DeleteLongRunningOperationState("test-vm", startServiceName, infrav1.PostFuture)
+			},
+		},
+		{
+			name:    "resumes starting a vm",
+			current: infrav1.VMPowerStateStarting,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DesiredPowerState().Return(infrav1.VMPowerStateRunning)
+				s.GetLongRunningOperationState("test-vm", startServiceName, infrav1.PostFuture).Return(&infrav1.Future{Type: infrav1.PostFuture, Data: "ZmFrZQ=="})
+				c.StartAsync(gomockinternal.AContext(), &fakeVMSpec, "fake").Return(nil, nil)
+				s.DeleteLongRunningOperationState("test-vm", startServiceName, infrav1.PostFuture)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+			tc.expect(g, scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.reconcilePowerState(context.TODO(), &fakeVMSpec, tc.current)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReimageFailedVM(t *testing.T) {
+	reimageVMSpec := fakeVMSpec
+	reimageVMSpec.ReimageOnFailure = true
+	reimageVMSpec.BootstrapData = "fake-bootstrap-data"
+
+	testcases := []struct {
+		name          string
+		spec          *VMSpec
+		state         infrav1.ProvisioningState
+		expectedError string
+		expect        func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder)
+	}{
+		{
+			name:  "noop if reimage on failure is not enabled",
+			spec:  &fakeVMSpec,
+			state: infrav1.Failed,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", reimageServiceName, infrav1.PostFuture).Return(nil)
+			},
+		},
+		{
+			name:  "noop if vm has not failed",
+			spec:  &reimageVMSpec,
+			state: infrav1.Succeeded,
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", reimageServiceName, infrav1.PostFuture).Return(nil)
+			},
+		},
+		{
+			name:          "reimages a failed vm",
+			spec:          &reimageVMSpec,
+			state:         infrav1.Failed,
+			expectedError: "VM test-vm was reimaged after it failed to provision. Object will be requeued after 15s",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", reimageServiceName, infrav1.PostFuture).Return(nil)
+				s.Reimaged().Return(false)
+				s.SetReimaged()
+				c.ReimageAsync(gomockinternal.AContext(), &reimageVMSpec, "", "fake-bootstrap-data").Return(nil, nil)
+				s.DeleteLongRunningOperationState("test-vm", reimageServiceName, infrav1.PostFuture)
+			},
+		},
+		{
+			name:          "waits for a failed vm to be reimaged",
+			spec:          &reimageVMSpec,
+			state:         infrav1.Failed,
+			expectedError: "failed to reimage VM: operation type POST on Azure resource test-group/test-vm is not done. Object will be requeued after 15s",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", reimageServiceName, infrav1.PostFuture).Return(nil)
+				s.Reimaged().Return(false)
+				s.SetReimaged()
+				c.ReimageAsync(gomockinternal.AContext(), &reimageVMSpec, "", "fake-bootstrap-data").Return(fakePoller[armcompute.VirtualMachinesClientReimageResponse](g), context.DeadlineExceeded)
+				s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{}))
+			},
+		},
+		{
+			name:          "resumes reimaging a vm",
+			spec:          &reimageVMSpec,
+			state:         infrav1.Updating,
+			expectedError: "VM test-vm was reimaged after it failed to provision. Object will be requeued after 15s",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", reimageServiceName, infrav1.PostFuture).Return(&infrav1.Future{Type: infrav1.PostFuture, Data: "ZmFrZQ=="})
+				c.ReimageAsync(gomockinternal.AContext(), &reimageVMSpec, "fake", "fake-bootstrap-data").Return(nil, nil)
+				s.DeleteLongRunningOperationState("test-vm", reimageServiceName, infrav1.PostFuture)
+			},
+		},
+		{
+			name:          "fails to reimage a vm",
+			spec:          &reimageVMSpec,
+			state:         infrav1.Failed,
+			expectedError: "failed to reimage VM: #: Internal Server Error: StatusCode=500",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", reimageServiceName, infrav1.PostFuture).Return(nil)
+				s.Reimaged().Return(false)
+				s.SetReimaged()
+				c.ReimageAsync(gomockinternal.AContext(), &reimageVMSpec, "", "fake-bootstrap-data").Return(nil, internalError)
+				s.DeleteLongRunningOperationState("test-vm", reimageServiceName, infrav1.PostFuture)
+			},
+		},
+		{
+			name:          "reports a vm that is still failed after being reimaged",
+			spec:          &reimageVMSpec,
+			state:         infrav1.Failed,
+			expectedError: "reconcile error that cannot be recovered occurred: VM test-vm is in Failed provisioning state after being reimaged. Object will not be requeued",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", reimageServiceName, infrav1.PostFuture).Return(nil)
+				s.Reimaged().Return(true)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+			tc.expect(g, scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.reimageFailedVM(context.TODO(), tc.spec, tc.state)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestCheckEncryptionAtHost(t *testing.T) {
+	encryptionAtHostVMSpec := fakeVMSpec
+	encryptionAtHostVMSpec.SecurityProfile = &infrav1.SecurityProfile{EncryptionAtHost: ptr.To(true)}
+
+	testcases := []struct {
+		name          string
+		spec          *VMSpec
+		expectedError string
+		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, f *mock_features.MockClientMockRecorder)
+	}{
+		{
+			name:   "encryption at host disabled",
+			spec:   &fakeVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, f *mock_features.MockClientMockRecorder) {},
+		},
+		{
+			name: "vm already created",
+			spec: &encryptionAtHostVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, f *mock_features.MockClientMockRecorder) {
+				s.ProviderID().Return("azure:///subscriptions/123/resourceGroups/test-group/providers/Microsoft.Compute/virtualMachines/test-vm")
+			},
+		},
+		{
+			name: "feature registered",
+			spec: &encryptionAtHostVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, f *mock_features.MockClientMockRecorder) {
+				s.ProviderID().Return("")
+				f.IsRegistered(gomockinternal.AContext(), features.ComputeNamespace, features.EncryptionAtHost).Return(true, nil)
+			},
+		},
+		{
+			name:          "feature not registered",
+			spec:          &encryptionAtHostVMSpec,
+			expectedError: "the Microsoft.Compute/EncryptionAtHost feature must be registered on the subscription to create VMs with encryption at host enabled. Object will be requeued after 5m0s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, f *mock_features.MockClientMockRecorder) {
+				s.ProviderID().Return("")
+				f.IsRegistered(gomockinternal.AContext(), features.ComputeNamespace, features.EncryptionAtHost).Return(false, nil)
+			},
+		},
+		{
+			name:          "feature lookup fails",
+			spec:          &encryptionAtHostVMSpec,
+			expectedError: "failed to check the registration of the EncryptionAtHost feature: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, f *mock_features.MockClientMockRecorder) {
+				s.ProviderID().Return("")
+				f.IsRegistered(gomockinternal.AContext(), features.ComputeNamespace, features.EncryptionAtHost).Return(false, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			featuresMock := mock_features.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), featuresMock.EXPECT())
+
+			s := &Service{
+				Scope:          scopeMock,
+				featuresGetter: featuresMock,
+			}
+
+			err := s.checkEncryptionAtHost(context.TODO(), tc.spec)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestCheckMarketplaceTerms(t *testing.T) {
+	thirdPartyVMSpec := fakeVMSpec
+	thirdPartyVMSpec.Image = &infrav1.Image{
+		Marketplace: &infrav1.AzureMarketplaceImage{
+			ImagePlan:       infrav1.ImagePlan{Publisher: "kinvolk", Offer: "flatcar", SKU: "stable"},
+			Version:         "1.0.0",
+			ThirdPartyImage: true,
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		spec          *VMSpec
+		expectedError string
+		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_marketplaceagreements.MockClientMockRecorder)
+	}{
+		{
+			name: "image without a plan",
+			spec: &fakeVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_marketplaceagreements.MockClientMockRecorder) {
+			},
+		},
+		{
+			name: "vm already created",
+			spec: &thirdPartyVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_marketplaceagreements.MockClientMockRecorder) {
+				s.ProviderID().Return("azure:///subscriptions/123/resourceGroups/test-group/providers/Microsoft.Compute/virtualMachines/test-vm")
+			},
+		},
+		{
+			name: "terms accepted",
+			spec: &thirdPartyVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_marketplaceagreements.MockClientMockRecorder) {
+				s.ProviderID().Return("")
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.PutFuture).Return(nil)
+				m.IsAccepted(gomockinternal.AContext(), "kinvolk", "flatcar", "stable").Return(true, nil)
+			},
+		},
+		{
+			name:          "terms not accepted",
+			spec:          &thirdPartyVMSpec,
+			expectedError: "the marketplace terms of image plan kinvolk/flatcar/stable must be accepted on the subscription",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_marketplaceagreements.MockClientMockRecorder) {
+				s.ProviderID().Return("")
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.PutFuture).Return(nil)
+				m.IsAccepted(gomockinternal.AContext(), "kinvolk", "flatcar", "stable").Return(false, nil)
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.MarketplaceTermsNotAcceptedReason, clusterv1.ConditionSeverityError, gomock.Any())
+			},
+		},
+		{
+			name:          "terms lookup fails",
+			spec:          &thirdPartyVMSpec,
+			expectedError: "failed to check the marketplace terms of the image: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_marketplaceagreements.MockClientMockRecorder) {
+				s.ProviderID().Return("")
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.PutFuture).Return(nil)
+				m.IsAccepted(gomockinternal.AContext(), "kinvolk", "flatcar", "stable").Return(false, internalError)
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			agreementsMock := mock_marketplaceagreements.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), agreementsMock.EXPECT())
+
+			s := &Service{
+				Scope:            scopeMock,
+				agreementsGetter: agreementsMock,
+			}
+
+			err := s.checkMarketplaceTerms(context.TODO(), tc.spec)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestFallBackToAvailableZone(t *testing.T) {
+	zonalVMSpec := fakeVMSpec
+	zonalVMSpec.AvailabilitySetID = ""
+	zonalVMSpec.Zone = "1"
+	zonalVMSpec.SKU = resourceskus.SKU{
+		Name: ptr.To("Standard_Fake_Size"),
+		LocationInfo: []*armcompute.ResourceSKULocationInfo{
+			{
+				Location: ptr.To("test-location"),
+				Zones:    []*string{ptr.To("1"), ptr.To("2")},
+			},
+		},
+	}
+	restrictedVMSpec := zonalVMSpec
+	restrictedVMSpec.SKU.Restrictions = []*armcompute.ResourceSKURestrictions{
+		{
+			Type:            ptr.To(armcompute.ResourceSKURestrictionsTypeZone),
+			RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{Zones: []*string{ptr.To("1")}},
+		},
+	}
+	substitutedVMSpec := restrictedVMSpec
+	substitutedVMSpec.Zone = "2"
+	reason := "VM size Standard_Fake_Size is not available in zone 1 of location test-location"
+
+	testcases := []struct {
+		name   string
+		spec   *VMSpec
+		want   *VMSpec
+		expect func(s *mock_virtualmachines.MockVMScopeMockRecorder)
+	}{
+		{
+			name:   "vm without zone",
+			spec:   &fakeVMSpec,
+			want:   &fakeVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder) {},
+		},
+		{
+			name:   "vm size available in the zone",
+			spec:   &zonalVMSpec,
+			want:   &zonalVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder) {},
+		},
+		{
+			name: "vm already created",
+			spec: &restrictedVMSpec,
+			want: &restrictedVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder) {
+				s.ProviderID().Return("azure:///subscriptions/123/resourceGroups/test-group/providers/Microsoft.Compute/virtualMachines/test-vm")
+			},
+		},
+		{
+			name: "no other zone can be used",
+			spec: &restrictedVMSpec,
+			want: &restrictedVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder) {
+				s.ProviderID().Return("")
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.PutFuture).Return(nil)
+				s.SubstituteZone(reason).Return("")
+			},
+		},
+		{
+			name: "another zone is substituted",
+			spec: &restrictedVMSpec,
+			want: &substitutedVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder) {
+				s.ProviderID().Return("")
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.PutFuture).Return(nil)
+				s.SubstituteZone(reason).Return("2")
+				s.VMSpec().Return(&substitutedVMSpec)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT())
+
+			s := &Service{
+				Scope: scopeMock,
+			}
+
+			g.Expect(s.fallBackToAvailableZone(context.TODO(), tc.spec)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestCheckVCPUQuota(t *testing.T) {
+	quotaVMSpec := fakeVMSpec
+	quotaVMSpec.SKU = resourceskus.SKU{
+		Name:   ptr.To("Standard_D4s_v3"),
+		Family: ptr.To("standardDSv3Family"),
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
+			{
+				Name:  ptr.To(resourceskus.VCPUs),
+				Value: ptr.To("4"),
+			},
+		},
+	}
+	usages := []armcompute.Usage{
+		{
+			Name:         &armcompute.UsageName{Value: ptr.To(quotas.TotalRegionalVCPUs)},
+			CurrentValue: ptr.To[int32](8),
+			Limit:        ptr.To[int64](100),
+		},
+		{
+			Name:         &armcompute.UsageName{Value: ptr.To("standardDSv3Family")},
+			CurrentValue: ptr.To[int32](8),
+			Limit:        ptr.To[int64](10),
+		},
+	}
+	quotaExceededMessage := "not enough vCPU quota to create VM test-vm: 4 vCPUs of the standardDSv3Family quota are required in test-location but only 2 of the 10 allowed are available"
+
+	testcases := []struct {
+		name          string
+		spec          *VMSpec
+		expectedError string
+		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, q *mock_quotas.MockClientMockRecorder)
+	}{
+		{
+			name:   "vm size is unknown",
+			spec:   &fakeVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, q *mock_quotas.MockClientMockRecorder) {},
+		},
+		{
+			name: "vm already created",
+			spec: &quotaVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, q *mock_quotas.MockClientMockRecorder) {
+				s.ProviderID().Return("azure:///subscriptions/123/resourceGroups/test-group/providers/Microsoft.Compute/virtualMachines/test-vm")
+			},
+		},
+		{
+			name: "vm is being created",
+			spec: &quotaVMSpec,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, q *mock_quotas.MockClientMockRecorder) {
+				s.ProviderID().Return("")
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.PutFuture).Return(&infrav1.Future{})
+			},
+		},
+		{
+			name:          "quota exceeded",
+			spec:          &quotaVMSpec,
+			expectedError: quotaExceededMessage + ". Object will be requeued after 5m0s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, q *mock_quotas.MockClientMockRecorder) {
+				s.ProviderID().Return("")
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.PutFuture).Return(nil)
+				q.ListUsages(gomockinternal.AContext(), "test-location").Return(usages, nil)
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VCPUQuotaExceededReason, clusterv1.ConditionSeverityError, quotaExceededMessage)
+			},
+		},
+		{
+			name:          "quota lookup fails",
+			spec:          &quotaVMSpec,
+			expectedError: "failed to check the vCPU quota: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, q *mock_quotas.MockClientMockRecorder) {
+				s.ProviderID().Return("")
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.PutFuture).Return(nil)
+				q.ListUsages(gomockinternal.AContext(), "test-location").Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			quotasMock := mock_quotas.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), quotasMock.EXPECT())
+
+			s := &Service{
+				Scope:        scopeMock,
+				quotasGetter: quotasMock,
+			}
+
+			err := s.checkVCPUQuota(context.TODO(), tc.spec)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestDeleteVM(t *testing.T) {
+	skipShutdownVMSpec := fakeVMSpec
+	skipShutdownVMSpec.SkipShutdown = true
+	waitDetachVMSpec := skipShutdownVMSpec
+	waitDetachVMSpec.DataDisks = []infrav1.DataDisk{{NameSuffix: "etcddisk"}}
+	waitDetachVMSpec.DataDiskDetach = &infrav1.DataDiskDetach{Policy: infrav1.DataDiskDetachWait}
+	waitDetachVMSpec.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	timedOutWaitDetachVMSpec := waitDetachVMSpec
+	timedOutWaitDetachVMSpec.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-2 * defaultDataDiskDetachTimeout)}
+	timedOutForceDetachVMSpec := timedOutWaitDetachVMSpec
+	timedOutForceDetachVMSpec.DataDiskDetach = &infrav1.DataDiskDetach{Policy: infrav1.DataDiskDetachForceDetach}
+	vmWithDataDisks := func(names ...string) armcompute.VirtualMachine {
+		vm := armcompute.VirtualMachine{Properties: &armcompute.VirtualMachineProperties{StorageProfile: &armcompute.StorageProfile{}}}
+		for i, name := range names {
+			vm.Properties.StorageProfile.DataDisks = append(vm.Properties.StorageProfile.DataDisks, &armcompute.DataDisk{Name: ptr.To(name), Lun: ptr.To(int32(i))})
+		}
+		return vm
+	}
+
 	testcases := []struct {
 		name          string
 		expectedError string
-		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+		expect        func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder)
 	}{
 		{
 			name:          "noop if no vm spec is found",
 			expectedError: "",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.VMSpec().Return(nil)
 			},
 		},
 		{
 			name:          "vm doesn't exist",
 			expectedError: "",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.VMSpec().AnyTimes().Return(&fakeVMSpec)
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.DeleteFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", powerOffServiceName, infrav1.PostFuture).Return(nil)
+				c.PowerOffAsync(gomockinternal.AContext(), &fakeVMSpec, "").Return(nil, notFoundError)
+				s.DeleteLongRunningOperationState("test-vm", powerOffServiceName, infrav1.PostFuture)
 				r.DeleteResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(nil)
 				s.SetVMState(infrav1.Deleted)
 				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, nil)
@@ -246,8 +1111,12 @@ func TestDeleteVM(t *testing.T) {
 		{
 			name:          "error occurs when deleting vm",
 			expectedError: "#: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.VMSpec().AnyTimes().Return(&fakeVMSpec)
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.DeleteFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", powerOffServiceName, infrav1.PostFuture).Return(nil)
+				c.PowerOffAsync(gomockinternal.AContext(), &fakeVMSpec, "").Return(nil, nil)
+				s.DeleteLongRunningOperationState("test-vm", powerOffServiceName, infrav1.PostFuture)
 				r.DeleteResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(internalError)
 				s.SetVMState(infrav1.Deleting)
 				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, internalError)
@@ -256,8 +1125,144 @@ func TestDeleteVM(t *testing.T) {
 		{
 			name:          "delete the vm successfully",
 			expectedError: "",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.VMSpec().AnyTimes().Return(&fakeVMSpec)
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.DeleteFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", powerOffServiceName, infrav1.PostFuture).Return(nil)
+				c.PowerOffAsync(gomockinternal.AContext(), &fakeVMSpec, "").Return(nil, nil)
+				s.DeleteLongRunningOperationState("test-vm", powerOffServiceName, infrav1.PostFuture)
+				r.DeleteResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(nil)
+				s.SetVMState(infrav1.Deleted)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "skip shutdown deletes the vm without powering it off",
+			expectedError: "",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().AnyTimes().Return(&skipShutdownVMSpec)
+				r.DeleteResource(gomockinternal.AContext(), &skipShutdownVMSpec, serviceName).Return(nil)
+				s.SetVMState(infrav1.Deleted)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "deletes the vm when no data disk was attached by a csi driver",
+			expectedError: "",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().AnyTimes().Return(&waitDetachVMSpec)
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.DeleteFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", detachDisksServiceName, infrav1.PatchFuture).Return(nil)
+				c.Get(gomockinternal.AContext(), &waitDetachVMSpec).Return(vmWithDataDisks("test-vm_etcddisk"), nil)
+				r.DeleteResource(gomockinternal.AContext(), &waitDetachVMSpec, serviceName).Return(nil)
+				s.SetVMState(infrav1.Deleted)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "waits for the data disks attached by a csi driver to be detached",
+			expectedError: "waiting for data disks pvc-1 to be detached. Object will be requeued after 15s",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().AnyTimes().Return(&waitDetachVMSpec)
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.DeleteFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", detachDisksServiceName, infrav1.PatchFuture).Return(nil)
+				c.Get(gomockinternal.AContext(), &waitDetachVMSpec).Return(vmWithDataDisks("test-vm_etcddisk", "pvc-1"), nil)
+				s.SetVMState(infrav1.Deleting)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "deletes the vm with the data disks still attached once the timeout elapsed",
+			expectedError: "",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().AnyTimes().Return(&timedOutWaitDetachVMSpec)
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.DeleteFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", detachDisksServiceName, infrav1.PatchFuture).Return(nil)
+				c.Get(gomockinternal.AContext(), &timedOutWaitDetachVMSpec).Return(vmWithDataDisks("test-vm_etcddisk", "pvc-1"), nil)
+				r.DeleteResource(gomockinternal.AContext(), &timedOutWaitDetachVMSpec, serviceName).Return(nil)
+				s.SetVMState(infrav1.Deleted)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "force-detaches the data disks still attached once the timeout elapsed",
+			expectedError: "",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().AnyTimes().Return(&timedOutForceDetachVMSpec)
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.DeleteFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", detachDisksServiceName, infrav1.PatchFuture).Return(nil)
+				c.Get(gomockinternal.AContext(), &timedOutForceDetachVMSpec).Return(vmWithDataDisks("test-vm_etcddisk", "pvc-1"), nil)
+				c.UpdateAsync(gomockinternal.AContext(), &timedOutForceDetachVMSpec, "", armcompute.VirtualMachineUpdate{
+					Properties: &armcompute.VirtualMachineProperties{
+						StorageProfile: &armcompute.StorageProfile{
+							DataDisks: []*armcompute.DataDisk{
+								{Name: ptr.To("test-vm_etcddisk"), Lun: ptr.To(int32(0))},
+								{
+									Name:         ptr.To("pvc-1"),
+									Lun:          ptr.To(int32(1)),
+									ToBeDetached: ptr.To(true),
+									DetachOption: ptr.To(armcompute.DiskDetachOptionTypesForceDetach),
+								},
+							},
+						},
+					},
+				}).Return(nil, nil)
+				s.DeleteLongRunningOperationState("test-vm", detachDisksServiceName, infrav1.PatchFuture)
+				r.DeleteResource(gomockinternal.AContext(), &timedOutForceDetachVMSpec, serviceName).Return(nil)
+				s.SetVMState(infrav1.Deleted)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "vm is not powered off again once its deletion started",
+			expectedError: "",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().AnyTimes().Return(&fakeVMSpec)
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.DeleteFuture).Return(&infrav1.Future{})
+				r.DeleteResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(nil)
+				s.SetVMState(infrav1.Deleted)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "waits for the vm to power off",
+			expectedError: "operation type POST on Azure resource test-group/test-vm is not done. Object will be requeued after 15s",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().AnyTimes().Return(&fakeVMSpec)
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.DeleteFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", powerOffServiceName, infrav1.PostFuture).Return(nil)
+				c.PowerOffAsync(gomockinternal.AContext(), &fakeVMSpec, "").Return(fakePoller[armcompute.VirtualMachinesClientPowerOffResponse](g), context.DeadlineExceeded)
+				s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{}))
+				s.SetVMState(infrav1.Deleting)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "deletes the vm anyway when it doesn't power off in time",
+			expectedError: "",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().AnyTimes().Return(&fakeVMSpec)
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.DeleteFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", powerOffServiceName, infrav1.PostFuture).Return(&infrav1.Future{
+					Type:      infrav1.PostFuture,
+					Data:      "ZmFrZQ==",
+					StartTime: &metav1.Time{Time: time.Now().Add(-2 * shutdownTimeout)},
+				})
+				s.DeleteLongRunningOperationState("test-vm", powerOffServiceName, infrav1.PostFuture)
+				r.DeleteResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(nil)
+				s.SetVMState(infrav1.Deleted)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "deletes the vm anyway when powering it off fails",
+			expectedError: "",
+			expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().AnyTimes().Return(&fakeVMSpec)
+				s.GetLongRunningOperationState("test-vm", serviceName, infrav1.DeleteFuture).Return(nil)
+				s.GetLongRunningOperationState("test-vm", powerOffServiceName, infrav1.PostFuture).Return(nil)
+				c.PowerOffAsync(gomockinternal.AContext(), &fakeVMSpec, "").Return(nil, internalError)
+				s.DeleteLongRunningOperationState("test-vm", powerOffServiceName, infrav1.PostFuture)
 				r.DeleteResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(nil)
 				s.SetVMState(infrav1.Deleted)
 				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, nil)
@@ -273,12 +1278,14 @@ func TestDeleteVM(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
 			asyncMock := mock_async.NewMockReconciler(mockCtrl)
 
-			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+			tc.expect(g, scopeMock.EXPECT(), clientMock.EXPECT(), asyncMock.EXPECT())
 
 			s := &Service{
 				Scope:      scopeMock,
+				client:     clientMock,
 				Reconciler: asyncMock,
 			}
 
@@ -293,6 +1300,22 @@ func TestDeleteVM(t *testing.T) {
 	}
 }
 
+func fakePoller[T any](g *WithT) *runtime.Poller[T] {
+	response := &http.Response{
+		Body: io.NopCloser(strings.NewReader("")),
+		Request: &http.Request{
+			Method: http.MethodPost,
+			URL:    &url.URL{Path: "/"},
+		},
+		StatusCode: http.StatusAccepted,
+		Header:     http.Header{"Location": []string{"https://management.azure.com/operations/fake"}},
+	}
+	pipeline := runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, nil)
+	poller, err := runtime.NewPoller[T](response, pipeline, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	return poller
+}
+
 func TestCheckUserAssignedIdentities(t *testing.T) {
 	testcases := []struct {
 		name             string
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworkgateways
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// VirtualNetworkGatewaySpec defines the specification for a virtual network gateway.
+type VirtualNetworkGatewaySpec struct {
+	Name           string
+	ResourceGroup  string
+	Location       string
+	ClusterName    string
+	SubnetID       string
+	PublicIPID     string
+	Type           infrav1.VirtualNetworkGatewayType
+	SKU            string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the virtual network gateway.
+func (s *VirtualNetworkGatewaySpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *VirtualNetworkGatewaySpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for virtual network gateways.
+func (s *VirtualNetworkGatewaySpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the virtual network gateway.
+func (s *VirtualNetworkGatewaySpec) Parameters(ctx context.Context, existing interface{}) (parameters interface{}, err error) {
+	if existing != nil {
+		existingGateway, ok := existing.(armnetwork.VirtualNetworkGateway)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armnetwork.VirtualNetworkGateway", existing)
+		}
+		// Resizing a gateway is the only supported change; any other change requires deleting the gateway.
+		if existingGateway.Properties == nil || existingGateway.Properties.SKU == nil ||
+			ptr.Deref(existingGateway.Properties.SKU.Name, "") == armnetwork.VirtualNetworkGatewaySKUName(s.SKU) {
+			return nil, nil
+		}
+		existingGateway.Properties.SKU = s.sku()
+		return existingGateway, nil
+	}
+
+	properties := &armnetwork.VirtualNetworkGatewayPropertiesFormat{
+		GatewayType: ptr.To(armnetwork.VirtualNetworkGatewayType(s.Type)),
+		SKU:         s.sku(),
+		IPConfigurations: []*armnetwork.VirtualNetworkGatewayIPConfiguration{
+			{
+				Name: ptr.To(fmt.Sprintf("%s-ipconfig", s.Name)),
+				Properties: &armnetwork.VirtualNetworkGatewayIPConfigurationPropertiesFormat{
+					Subnet: &armnetwork.SubResource{
+						ID: ptr.To(s.SubnetID),
+					},
+					PublicIPAddress: &armnetwork.SubResource{
+						ID: ptr.To(s.PublicIPID),
+					},
+					PrivateIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodDynamic),
+				},
+			},
+		},
+	}
+	if s.Type == infrav1.VirtualNetworkGatewayTypeVpn {
+		properties.VPNType = ptr.To(armnetwork.VPNTypeRouteBased)
+	}
+
+	return armnetwork.VirtualNetworkGateway{
+		Name:     ptr.To(s.Name),
+		Location: ptr.To(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        ptr.To(s.Name),
+			Role:        ptr.To("VirtualNetworkGateway"),
+			Additional:  s.AdditionalTags,
+		})),
+		Properties: properties,
+	}, nil
+}
+
+func (s *VirtualNetworkGatewaySpec) sku() *armnetwork.VirtualNetworkGatewaySKU {
+	return &armnetwork.VirtualNetworkGatewaySKU{
+		Name: ptr.To(armnetwork.VirtualNetworkGatewaySKUName(s.SKU)),
+		Tier: ptr.To(armnetwork.VirtualNetworkGatewaySKUTier(s.SKU)),
+	}
+}
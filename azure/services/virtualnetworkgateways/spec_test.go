@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworkgateways
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestVirtualNetworkGatewaySpec_Parameters(t *testing.T) {
+	testCases := []struct {
+		name          string
+		spec          *VirtualNetworkGatewaySpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name:     "error when existing gateway is not a VirtualNetworkGateway",
+			spec:     &fakeGatewaySpec,
+			existing: struct{}{},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "struct {} is not an armnetwork.VirtualNetworkGateway",
+		},
+		{
+			name: "nil when existing gateway has the same SKU",
+			spec: &fakeGatewaySpec,
+			existing: armnetwork.VirtualNetworkGateway{
+				Properties: &armnetwork.VirtualNetworkGatewayPropertiesFormat{
+					SKU: &armnetwork.VirtualNetworkGatewaySKU{Name: ptr.To(armnetwork.VirtualNetworkGatewaySKUNameVPNGw1AZ)},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "resize existing gateway with a different SKU",
+			spec: &fakeGatewaySpec,
+			existing: armnetwork.VirtualNetworkGateway{
+				Name: ptr.To("my-gateway"),
+				Properties: &armnetwork.VirtualNetworkGatewayPropertiesFormat{
+					GatewayType: ptr.To(armnetwork.VirtualNetworkGatewayTypeVPN),
+					SKU:         &armnetwork.VirtualNetworkGatewaySKU{Name: ptr.To(armnetwork.VirtualNetworkGatewaySKUNameVPNGw2AZ)},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armnetwork.VirtualNetworkGateway{
+					Name: ptr.To("my-gateway"),
+					Properties: &armnetwork.VirtualNetworkGatewayPropertiesFormat{
+						GatewayType: ptr.To(armnetwork.VirtualNetworkGatewayTypeVPN),
+						SKU: &armnetwork.VirtualNetworkGatewaySKU{
+							Name: ptr.To(armnetwork.VirtualNetworkGatewaySKUNameVPNGw1AZ),
+							Tier: ptr.To(armnetwork.VirtualNetworkGatewaySKUTierVPNGw1AZ),
+						},
+					},
+				}))
+			},
+		},
+		{
+			name: "new VPN gateway",
+			spec: &fakeGatewaySpec,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armnetwork.VirtualNetworkGateway{
+					Name:     ptr.To("my-gateway"),
+					Location: ptr.To("westus"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_role":               ptr.To("VirtualNetworkGateway"),
+						"Name": ptr.To("my-gateway"),
+					},
+					Properties: &armnetwork.VirtualNetworkGatewayPropertiesFormat{
+						GatewayType: ptr.To(armnetwork.VirtualNetworkGatewayTypeVPN),
+						VPNType:     ptr.To(armnetwork.VPNTypeRouteBased),
+						SKU: &armnetwork.VirtualNetworkGatewaySKU{
+							Name: ptr.To(armnetwork.VirtualNetworkGatewaySKUNameVPNGw1AZ),
+							Tier: ptr.To(armnetwork.VirtualNetworkGatewaySKUTierVPNGw1AZ),
+						},
+						IPConfigurations: []*armnetwork.VirtualNetworkGatewayIPConfiguration{
+							{
+								Name: ptr.To("my-gateway-ipconfig"),
+								Properties: &armnetwork.VirtualNetworkGatewayIPConfigurationPropertiesFormat{
+									Subnet:                    &armnetwork.SubResource{ID: ptr.To("my-subnet-id")},
+									PublicIPAddress:           &armnetwork.SubResource{ID: ptr.To("my-public-ip-id")},
+									PrivateIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodDynamic),
+								},
+							},
+						},
+					},
+				}))
+			},
+		},
+		{
+			name: "new ExpressRoute gateway has no VPN type",
+			spec: &VirtualNetworkGatewaySpec{
+				Name: "my-gateway",
+				Type: infrav1.VirtualNetworkGatewayTypeExpressRoute,
+				SKU:  "ErGw1AZ",
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.VirtualNetworkGateway{}))
+				properties := result.(armnetwork.VirtualNetworkGateway).Properties
+				g.Expect(properties.GatewayType).To(Equal(ptr.To(armnetwork.VirtualNetworkGatewayTypeExpressRoute)))
+				g.Expect(properties.VPNType).To(BeNil())
+				g.Expect(properties.SKU.Name).To(Equal(ptr.To(armnetwork.VirtualNetworkGatewaySKUNameErGw1AZ)))
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			tc.expect(g, result)
+		})
+	}
+}
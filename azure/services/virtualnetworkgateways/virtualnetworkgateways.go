@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworkgateways
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "virtualnetworkgateways"
+
+// VirtualNetworkGatewayScope defines the scope interface for a virtual network gateway service.
+type VirtualNetworkGatewayScope interface {
+	azure.ClusterScoper
+	azure.AsyncStatusUpdater
+	VirtualNetworkGatewaySpec() azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope VirtualNetworkGatewayScope
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope VirtualNetworkGatewayScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope: scope,
+		Reconciler: async.New[armnetwork.VirtualNetworkGatewaysClientCreateOrUpdateResponse,
+			armnetwork.VirtualNetworkGatewaysClientDeleteResponse](scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile idempotently creates or updates a virtual network gateway.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworkgateways.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	gatewaySpec := s.Scope.VirtualNetworkGatewaySpec()
+	if gatewaySpec == nil {
+		return nil
+	}
+
+	_, err := s.CreateOrUpdateResource(ctx, gatewaySpec, serviceName)
+	s.Scope.UpdatePutStatus(infrav1.VirtualNetworkGatewayReadyCondition, serviceName, err)
+	return err
+}
+
+// Delete deletes the virtual network gateway.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworkgateways.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	gatewaySpec := s.Scope.VirtualNetworkGatewaySpec()
+	if gatewaySpec == nil {
+		return nil
+	}
+
+	err := s.DeleteResource(ctx, gatewaySpec, serviceName)
+	s.Scope.UpdateDeleteStatus(infrav1.VirtualNetworkGatewayReadyCondition, serviceName, err)
+	return err
+}
+
+// IsManaged returns always returns true as the virtual network gateway is only created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
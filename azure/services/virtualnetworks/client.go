@@ -18,23 +18,55 @@ package virtualnetworks
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// getCacheTTL is how long a virtual network read from Azure is served from the cache.
+// It is kept short so that changes made outside of CAPZ to a BYO virtual network are picked up quickly.
+const getCacheTTL = 30 * time.Second
+
 // azureClient contains the Azure go-sdk Client.
 type azureClient struct {
 	virtualnetworks *armnetwork.VirtualNetworksClient
+	auth            azure.Authorizer
+	subscriptionID  string
+	cache           ttllru.PeekingCacher
+}
+
+// cacheKey identifies a virtual network in the GET cache.
+type cacheKey struct {
+	subscriptionID string
+	resourceGroup  string
+	name           string
 }
 
+var (
+	doOnce   sync.Once
+	getCache ttllru.PeekingCacher
+)
+
 // newClient creates a new virtual networks client from an authorizer.
 func newClient(auth azure.Authorizer) (*azureClient, error) {
+	var err error
+	doOnce.Do(func() {
+		getCache, err = ttllru.New(1024, getCacheTTL)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating LRU cache for virtual networks")
+	}
+
 	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create virtualnetworks client options")
@@ -43,21 +75,80 @@ func newClient(auth azure.Authorizer) (*azureClient, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create armnetwork client factory")
 	}
-	return &azureClient{factory.NewVirtualNetworksClient()}, nil
+	return &azureClient{
+		virtualnetworks: factory.NewVirtualNetworksClient(),
+		auth:            auth,
+		subscriptionID:  auth.SubscriptionID(),
+		cache:           getCache,
+	}, nil
 }
 
-// Get gets the specified virtual network.
+// Get gets the specified virtual network. Successful reads are cached for a short time per subscription
+// so that steady-state reconciliation of many clusters does not exhaust the subscription read quota.
 func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.azureClient.Get")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.azureClient.Get")
 	defer done()
 
+	key := ac.cacheKey(spec)
+	if ac.cache != nil {
+		if cached, _, ok := ac.cache.Peek(key); ok {
+			// Callers such as VNetSpec.Parameters modify the virtual network they get, so each gets its own copy.
+			if vnet, err := copyVirtualNetwork(cached.(armnetwork.VirtualNetwork)); err == nil {
+				log.V(4).Info("virtual network cache hit", "resourceGroup", key.resourceGroup, "name", key.name)
+				return vnet, nil
+			}
+		}
+	}
+
 	resp, err := ac.virtualnetworks.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
 	if err != nil {
 		return nil, err
 	}
+	ac.setCached(key, resp.VirtualNetwork)
 	return resp.VirtualNetwork, nil
 }
 
+// cacheKey returns the GET cache key of the virtual network described by spec.
+func (ac *azureClient) cacheKey(spec azure.ResourceSpecGetter) cacheKey {
+	return cacheKey{
+		subscriptionID: ac.subscriptionID,
+		resourceGroup:  spec.ResourceGroupName(),
+		name:           spec.ResourceName(),
+	}
+}
+
+// setCached stores a copy of a virtual network in the GET cache, so that changes made by the caller to the virtual
+// network it got are not cached. The virtual network is not cached if it can't be copied.
+func (ac *azureClient) setCached(key cacheKey, vnet armnetwork.VirtualNetwork) {
+	if ac.cache == nil {
+		return
+	}
+	vnetCopy, err := copyVirtualNetwork(vnet)
+	if err != nil {
+		ac.cache.Remove(key)
+		return
+	}
+	ac.cache.Add(key, vnetCopy)
+}
+
+// copyVirtualNetwork returns a deep copy of a virtual network, which shares no pointers with it.
+func copyVirtualNetwork(vnet armnetwork.VirtualNetwork) (armnetwork.VirtualNetwork, error) {
+	var vnetCopy armnetwork.VirtualNetwork
+	data, err := vnet.MarshalJSON()
+	if err != nil {
+		return vnetCopy, err
+	}
+	err = vnetCopy.UnmarshalJSON(data)
+	return vnetCopy, err
+}
+
+// invalidate removes a virtual network from the GET cache.
+func (ac *azureClient) invalidate(key cacheKey) {
+	if ac.cache != nil {
+		ac.cache.Remove(key)
+	}
+}
+
 // CreateOrUpdateAsync creates or updates a virtual network in the specified resource group asynchronously.
 // It sends a PUT request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
 // progress of the operation.
@@ -70,6 +161,9 @@ func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 		return nil, nil, errors.Errorf("%T is not an armnetwork.VirtualNetwork", parameters)
 	}
 
+	key := ac.cacheKey(spec)
+	ac.invalidate(key)
+
 	opts := &armnetwork.VirtualNetworksClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken}
 	poller, err = ac.virtualnetworks.BeginCreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), vn, opts)
 	if err != nil {
@@ -88,6 +182,7 @@ func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 	}
 
 	// if the operation completed, return a nil poller
+	ac.setCached(key, resp.VirtualNetwork)
 	return resp.VirtualNetwork, nil, err
 }
 
@@ -98,6 +193,8 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.azureClient.DeleteAsync")
 	defer done()
 
+	ac.invalidate(ac.cacheKey(spec))
+
 	opts := &armnetwork.VirtualNetworksClientBeginDeleteOptions{ResumeToken: resumeToken}
 	poller, err = ac.virtualnetworks.BeginDelete(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
 	if err != nil {
@@ -118,3 +215,55 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 	// if the operation completed, return a nil poller.
 	return nil, err
 }
+
+// ReleaseOwnership removes the tag marking a shared virtual network as owned by clusterName, unless clusterName is its
+// last owner, and returns whether it is. The virtual network is read bypassing the cache and written back only if it
+// wasn't modified in the meantime, so that clusters releasing it concurrently can't all leave it in place.
+func (ac *azureClient) ReleaseOwnership(ctx context.Context, spec azure.ResourceSpecGetter, clusterName string) (bool, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.azureClient.ReleaseOwnership")
+	defer done()
+
+	resp, err := ac.virtualnetworks.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return false, err
+	}
+	vnet := resp.VirtualNetwork
+	tags := converters.MapToTags(vnet.Tags)
+	if !tags.HasOwned(clusterName) {
+		return false, nil
+	}
+	if len(tags.Owners()) == 1 {
+		return true, nil
+	}
+	if vnet.Etag == nil {
+		return false, errors.Errorf("virtual network %s has no etag", spec.ResourceName())
+	}
+	delete(vnet.Tags, infrav1.ClusterTagKey(clusterName))
+
+	// Create a new client that knows how to add the etag header.
+	clientOpts, err := azure.ARMClientOptions(ac.auth.CloudEnvironment(), azure.CustomPutPatchHeaderPolicy{
+		Headers: map[string]string{
+			"If-Match": *vnet.Etag,
+		},
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create virtualnetworks client options")
+	}
+	factory, err := armnetwork.NewClientFactory(ac.auth.SubscriptionID(), ac.auth.Token(), clientOpts)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create armnetwork client factory")
+	}
+
+	ac.invalidate(ac.cacheKey(spec))
+	poller, err := factory.NewVirtualNetworksClient().BeginCreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), vnet, nil)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	_, err = poller.PollUntilDone(ctx, pollOpts)
+	return false, err
+}
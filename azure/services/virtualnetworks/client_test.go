@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+)
+
+func TestAzureClientGetCache(t *testing.T) {
+	g := NewWithT(t)
+
+	cache, err := ttllru.New(10, time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	spec := &VNetSpec{ResourceGroup: "my-rg", Name: "my-vnet"}
+	otherSubscription := &azureClient{subscriptionID: "other-sub", cache: cache}
+	ac := &azureClient{subscriptionID: "123", cache: cache}
+
+	vnet := armnetwork.VirtualNetwork{ID: ptr.To("my-vnet-id")}
+	ac.setCached(ac.cacheKey(spec), vnet)
+
+	// A cache hit is served without calling the Azure SDK client, which is nil here.
+	result, err := ac.Get(context.Background(), spec)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(vnet))
+
+	// Entries are scoped to a subscription.
+	_, _, ok := cache.Peek(otherSubscription.cacheKey(spec))
+	g.Expect(ok).To(BeFalse())
+
+	ac.invalidate(ac.cacheKey(spec))
+	_, _, ok = cache.Peek(ac.cacheKey(spec))
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestAzureClientGetCacheReturnsCopies(t *testing.T) {
+	g := NewWithT(t)
+
+	cache, err := ttllru.New(10, time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	spec := &VNetSpec{ResourceGroup: "my-rg", Name: "my-vnet"}
+	ac := &azureClient{subscriptionID: "123", cache: cache}
+
+	vnet := armnetwork.VirtualNetwork{
+		ID:   ptr.To("my-vnet-id"),
+		Tags: map[string]*string{"foo": ptr.To("bar")},
+		Properties: &armnetwork.VirtualNetworkPropertiesFormat{
+			AddressSpace: &armnetwork.AddressSpace{AddressPrefixes: []*string{ptr.To("10.0.0.0/8")}},
+		},
+	}
+	ac.setCached(ac.cacheKey(spec), vnet)
+
+	// Changes to the cached virtual network are not cached.
+	vnet.Tags["foo"] = ptr.To("changed")
+
+	result, err := ac.Get(context.Background(), spec)
+	g.Expect(err).NotTo(HaveOccurred())
+	got := result.(armnetwork.VirtualNetwork)
+	g.Expect(*got.Tags["foo"]).To(Equal("bar"))
+
+	// Changes to a virtual network served from the cache are not cached either.
+	got.Tags["foo"] = ptr.To("changed")
+	*got.Properties.AddressSpace.AddressPrefixes[0] = "192.168.0.0/16"
+	got.Properties.DdosProtectionPlan = &armnetwork.SubResource{ID: ptr.To("my-ddos-plan")}
+
+	result, err = ac.Get(context.Background(), spec)
+	g.Expect(err).NotTo(HaveOccurred())
+	got = result.(armnetwork.VirtualNetwork)
+	g.Expect(*got.Tags["foo"]).To(Equal("bar"))
+	g.Expect(*got.Properties.AddressSpace.AddressPrefixes[0]).To(Equal("10.0.0.0/8"))
+	g.Expect(got.Properties.DdosProtectionPlan).To(BeNil())
+}
+
+func TestAzureClientGetCacheExpires(t *testing.T) {
+	g := NewWithT(t)
+
+	cache, err := ttllru.New(10, time.Millisecond)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	spec := &VNetSpec{ResourceGroup: "my-rg", Name: "my-vnet"}
+	ac := &azureClient{subscriptionID: "123", cache: cache}
+	ac.setCached(ac.cacheKey(spec), armnetwork.VirtualNetwork{})
+
+	g.Eventually(func() bool {
+		_, _, ok := cache.Peek(ac.cacheKey(spec))
+		return ok
+	}).Should(BeFalse())
+}
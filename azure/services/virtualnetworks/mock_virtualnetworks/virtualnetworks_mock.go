@@ -127,6 +127,20 @@ func (mr *MockVNetScopeMockRecorder) ClusterName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockVNetScope)(nil).ClusterName))
 }
 
+// DDoSProtectionPlanSpec mocks base method.
+func (m *MockVNetScope) DDoSProtectionPlanSpec() azure.ResourceSpecGetter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DDoSProtectionPlanSpec")
+	ret0, _ := ret[0].(azure.ResourceSpecGetter)
+	return ret0
+}
+
+// DDoSProtectionPlanSpec indicates an expected call of DDoSProtectionPlanSpec.
+func (mr *MockVNetScopeMockRecorder) DDoSProtectionPlanSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DDoSProtectionPlanSpec", reflect.TypeOf((*MockVNetScope)(nil).DDoSProtectionPlanSpec))
+}
+
 // DeleteLongRunningOperationState mocks base method.
 func (m *MockVNetScope) DeleteLongRunningOperationState(arg0, arg1, arg2 string) {
 	m.ctrl.T.Helper()
@@ -181,6 +195,20 @@ func (mr *MockVNetScopeMockRecorder) IsVnetManaged() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsVnetManaged", reflect.TypeOf((*MockVNetScope)(nil).IsVnetManaged))
 }
 
+// SecondaryVNetSpecs mocks base method.
+func (m *MockVNetScope) SecondaryVNetSpecs() []azure.ResourceSpecGetter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecondaryVNetSpecs")
+	ret0, _ := ret[0].([]azure.ResourceSpecGetter)
+	return ret0
+}
+
+// SecondaryVNetSpecs indicates an expected call of SecondaryVNetSpecs.
+func (mr *MockVNetScopeMockRecorder) SecondaryVNetSpecs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecondaryVNetSpecs", reflect.TypeOf((*MockVNetScope)(nil).SecondaryVNetSpecs))
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockVNetScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -18,8 +18,10 @@ package virtualnetworks
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -35,6 +37,12 @@ type VNetSpec struct {
 	ExtendedLocation *infrav1.ExtendedLocationSpec
 	ClusterName      string
 	AdditionalTags   infrav1.Tags
+	// DDoSProtectionPlanID is the resource ID of the DDoS protection plan the vnet is associated with, if any.
+	DDoSProtectionPlanID string
+	// Shared is true if the vnet can be used by several clusters, which all tag it as owned.
+	Shared bool
+	// Adopt is true if an existing vnet which is not owned by any cluster is adopted by tagging it as owned.
+	Adopt bool
 }
 
 // ResourceName returns the name of the vnet.
@@ -55,8 +63,42 @@ func (s *VNetSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the vnet.
 func (s *VNetSpec) Parameters(ctx context.Context, existing interface{}) (interface{}, error) {
 	if existing != nil {
-		// vnet already exists, nothing to update.
-		return nil, nil
+		existingVnet, ok := existing.(armnetwork.VirtualNetwork)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armnetwork.VirtualNetwork", existing)
+		}
+		// A shared vnet managed by other clusters is joined by tagging it as owned by this cluster as well, and a vnet
+		// owned by no cluster is adopted the same way.
+		tags := converters.MapToTags(existingVnet.Tags)
+		join := (s.Shared && !tags.HasOwned(s.ClusterName) && len(tags.Owners()) > 0) || (s.Adopt && len(tags.Owners()) == 0)
+		// The DDoS protection plan is the only property updated, and only on a vnet managed by this cluster.
+		addPlan := s.DDoSProtectionPlanID != "" && !s.hasDDoSProtectionPlan(existingVnet) && (join || tags.HasOwned(s.ClusterName))
+		if !join && !addPlan {
+			return nil, nil
+		}
+		if join {
+			if tags == nil {
+				tags = infrav1.Tags{}
+			}
+			tags[infrav1.ClusterTagKey(s.ClusterName)] = string(infrav1.ResourceLifecycleOwned)
+			existingVnet.Tags = converters.TagsToMap(tags)
+		}
+		if addPlan {
+			if existingVnet.Properties == nil {
+				existingVnet.Properties = &armnetwork.VirtualNetworkPropertiesFormat{}
+			}
+			s.setDDoSProtectionPlan(existingVnet.Properties)
+		}
+		return existingVnet, nil
+	}
+
+	properties := &armnetwork.VirtualNetworkPropertiesFormat{
+		AddressSpace: &armnetwork.AddressSpace{
+			AddressPrefixes: azure.PtrSlice(&s.CIDRs),
+		},
+	}
+	if s.DDoSProtectionPlanID != "" {
+		s.setDDoSProtectionPlan(properties)
 	}
 	return armnetwork.VirtualNetwork{
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
@@ -68,10 +110,18 @@ func (s *VNetSpec) Parameters(ctx context.Context, existing interface{}) (interf
 		})),
 		Location:         ptr.To(s.Location),
 		ExtendedLocation: converters.ExtendedLocationToNetworkSDK(s.ExtendedLocation),
-		Properties: &armnetwork.VirtualNetworkPropertiesFormat{
-			AddressSpace: &armnetwork.AddressSpace{
-				AddressPrefixes: azure.PtrSlice(&s.CIDRs),
-			},
-		},
+		Properties:       properties,
 	}, nil
 }
+
+// hasDDoSProtectionPlan returns true if the vnet is already protected by the DDoS protection plan of the spec.
+func (s *VNetSpec) hasDDoSProtectionPlan(vnet armnetwork.VirtualNetwork) bool {
+	return vnet.Properties != nil && ptr.Deref(vnet.Properties.EnableDdosProtection, false) &&
+		vnet.Properties.DdosProtectionPlan != nil &&
+		strings.EqualFold(ptr.Deref(vnet.Properties.DdosProtectionPlan.ID, ""), s.DDoSProtectionPlanID)
+}
+
+func (s *VNetSpec) setDDoSProtectionPlan(properties *armnetwork.VirtualNetworkPropertiesFormat) {
+	properties.EnableDdosProtection = ptr.To(true)
+	properties.DdosProtectionPlan = &armnetwork.SubResource{ID: ptr.To(s.DDoSProtectionPlanID)}
+}
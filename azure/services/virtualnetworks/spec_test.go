@@ -75,6 +75,26 @@ var (
 		"foo":  ptr.To("bar"),
 		"Name": ptr.To("test-vnet"),
 	}
+	fakeDDoSProtectionPlanID = "/subscriptions/123/resourceGroups/test-group/providers/Microsoft.Network/ddosProtectionPlans/test-plan"
+	fakeVNetSpecWithDDoS     = VNetSpec{
+		Name:                 "test-vnet",
+		ClusterName:          "cluster",
+		CIDRs:                []string{"10.0.0.0/8"},
+		Location:             "test-location",
+		DDoSProtectionPlanID: fakeDDoSProtectionPlanID,
+	}
+	fakeManagedVirtualNetwork = armnetwork.VirtualNetwork{
+		Name: ptr.To("test-vnet"),
+		Etag: ptr.To("fake-etag"),
+		Tags: map[string]*string{
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster": ptr.To("owned"),
+		},
+		Properties: &armnetwork.VirtualNetworkPropertiesFormat{
+			AddressSpace: &armnetwork.AddressSpace{
+				AddressPrefixes: []*string{ptr.To("10.0.0.0/8")},
+			},
+		},
+	}
 )
 
 func TestVNetSpec_Parameters(t *testing.T) {
@@ -117,6 +137,105 @@ func TestVNetSpec_Parameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "new VirtualNetwork is associated with the DDoS protection plan",
+			spec:     &fakeVNetSpecWithDDoS,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.VirtualNetwork{}))
+				g.Expect(result.(armnetwork.VirtualNetwork).Properties.EnableDdosProtection).To(Equal(ptr.To(true)))
+				g.Expect(result.(armnetwork.VirtualNetwork).Properties.DdosProtectionPlan).To(Equal(&armnetwork.SubResource{ID: ptr.To(fakeDDoSProtectionPlanID)}))
+			},
+		},
+		{
+			name:     "existing managed VirtualNetwork is associated with the DDoS protection plan",
+			spec:     &fakeVNetSpecWithDDoS,
+			existing: fakeManagedVirtualNetwork,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armnetwork.VirtualNetwork{
+					Name: ptr.To("test-vnet"),
+					Etag: ptr.To("fake-etag"),
+					Tags: fakeManagedVirtualNetwork.Tags,
+					Properties: &armnetwork.VirtualNetworkPropertiesFormat{
+						AddressSpace:         fakeManagedVirtualNetwork.Properties.AddressSpace,
+						EnableDdosProtection: ptr.To(true),
+						DdosProtectionPlan:   &armnetwork.SubResource{ID: ptr.To(fakeDDoSProtectionPlanID)},
+					},
+				}))
+			},
+		},
+		{
+			name: "existing VirtualNetwork already associated with the DDoS protection plan",
+			spec: &fakeVNetSpecWithDDoS,
+			existing: armnetwork.VirtualNetwork{
+				Tags: fakeManagedVirtualNetwork.Tags,
+				Properties: &armnetwork.VirtualNetworkPropertiesFormat{
+					EnableDdosProtection: ptr.To(true),
+					DdosProtectionPlan:   &armnetwork.SubResource{ID: ptr.To(fakeDDoSProtectionPlanID)},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:     "existing custom VirtualNetwork is not associated with the DDoS protection plan",
+			spec:     &fakeVNetSpecWithDDoS,
+			existing: fakeVirtualNetwork,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:     "existing shared VirtualNetwork owned by another cluster is tagged as owned",
+			spec:     &VNetSpec{Name: "test-vnet", ClusterName: "other", Shared: true},
+			existing: fakeManagedVirtualNetwork,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.VirtualNetwork{}))
+				g.Expect(result.(armnetwork.VirtualNetwork).Tags).To(Equal(map[string]*string{
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster": ptr.To("owned"),
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_other":   ptr.To("owned"),
+				}))
+			},
+		},
+		{
+			name:     "existing shared VirtualNetwork already owned by the cluster is not updated",
+			spec:     &VNetSpec{Name: "test-vnet", ClusterName: "cluster", Shared: true},
+			existing: fakeManagedVirtualNetwork,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:     "existing custom VirtualNetwork is not joined",
+			spec:     &VNetSpec{Name: "test-vnet", ClusterName: "other", Shared: true},
+			existing: fakeVirtualNetwork,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:     "existing custom VirtualNetwork is adopted",
+			spec:     &VNetSpec{Name: "test-vnet", ClusterName: "cluster", Adopt: true},
+			existing: fakeVirtualNetwork,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.VirtualNetwork{}))
+				g.Expect(result.(armnetwork.VirtualNetwork).Tags).To(Equal(map[string]*string{
+					"foo":       ptr.To("bar"),
+					"something": ptr.To("else"),
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster": ptr.To("owned"),
+				}))
+				g.Expect(result.(armnetwork.VirtualNetwork).Properties.AddressSpace.AddressPrefixes).To(Equal([]*string{ptr.To("fake-cidr")}))
+			},
+		},
+		{
+			name:     "existing VirtualNetwork owned by another cluster is not adopted",
+			spec:     &VNetSpec{Name: "test-vnet", ClusterName: "other", Adopt: true},
+			existing: fakeManagedVirtualNetwork,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -39,6 +39,8 @@ type VNetScope interface {
 	azure.AsyncStatusUpdater
 	Vnet() *infrav1.VnetSpec
 	VNetSpec() azure.ResourceSpecGetter
+	SecondaryVNetSpecs() []azure.ResourceSpecGetter
+	DDoSProtectionPlanSpec() azure.ResourceSpecGetter
 	ClusterName() string
 	IsVnetManaged() bool
 	UpdateSubnetCIDRs(string, []string)
@@ -50,6 +52,8 @@ type Service struct {
 	async.Reconciler
 	async.Getter
 	async.TagsGetter
+	ownershipReleaser            async.OwnershipReleaser
+	ddosProtectionPlanReconciler async.Reconciler
 }
 
 // New creates a new service.
@@ -58,16 +62,23 @@ func New(scope VNetScope) (*Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	ddosProtectionPlansClient, err := newDDoSProtectionPlansClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	tagsClient, err := tags.NewClient(scope)
 	if err != nil {
 		return nil, err
 	}
 	return &Service{
-		Scope:      scope,
-		Getter:     client,
-		TagsGetter: tagsClient,
+		Scope:             scope,
+		Getter:            client,
+		TagsGetter:        tagsClient,
+		ownershipReleaser: client,
 		Reconciler: async.New[armnetwork.VirtualNetworksClientCreateOrUpdateResponse,
 			armnetwork.VirtualNetworksClientDeleteResponse](scope, client, client),
+		ddosProtectionPlanReconciler: async.New[armnetwork.DdosProtectionPlansClientCreateOrUpdateResponse,
+			armnetwork.DdosProtectionPlansClientDeleteResponse](scope, ddosProtectionPlansClient, ddosProtectionPlansClient),
 	}, nil
 }
 
@@ -89,6 +100,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		return nil
 	}
 
+	// The DDoS protection plan must exist before the vnet is associated with it.
+	if planSpec := s.Scope.DDoSProtectionPlanSpec(); planSpec != nil {
+		if err := s.reconcileDDoSProtectionPlan(ctx, planSpec); err != nil {
+			s.Scope.UpdatePutStatus(infrav1.VNetReadyCondition, serviceName, err)
+			return err
+		}
+	}
+
 	result, err := s.CreateOrUpdateResource(ctx, vnetSpec, serviceName)
 	if err == nil && result != nil {
 		existingVnet, ok := result.(armnetwork.VirtualNetwork)
@@ -119,6 +138,17 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 	}
 
+	// The virtual networks of the secondary regions are reconciled independently of the result of the primary one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	for _, secondarySpec := range s.Scope.SecondaryVNetSpecs() {
+		if _, secondaryErr := s.CreateOrUpdateResource(ctx, secondarySpec, serviceName); secondaryErr != nil {
+			if !azure.IsOperationNotDoneError(secondaryErr) || err == nil {
+				err = secondaryErr
+			}
+		}
+	}
+
 	if s.Scope.IsVnetManaged() {
 		s.Scope.UpdatePutStatus(infrav1.VNetReadyCondition, serviceName, err)
 	}
@@ -139,8 +169,15 @@ func (s *Service) Delete(ctx context.Context) error {
 		return nil
 	}
 
+	// The virtual networks of the secondary regions are deleted first, as they don't depend on whether the primary
+	// one is managed.
+	if err := s.deleteSecondaryVNets(ctx); err != nil {
+		s.Scope.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, err)
+		return err
+	}
+
 	// Check that the vnet is not BYO.
-	managed, err := s.IsManaged(ctx)
+	tags, err := s.getTags(ctx)
 	if err != nil {
 		if azure.ResourceNotFound(err) {
 			// already deleted or doesn't exist, cleanup status and return.
@@ -150,38 +187,122 @@ func (s *Service) Delete(ctx context.Context) error {
 		}
 		return errors.Wrap(err, "could not get VNet management state")
 	}
-	if !managed {
+	if !tags.HasOwned(s.Scope.ClusterName()) {
 		log.Info("Skipping VNet deletion in custom vnet mode")
 		return nil
 	}
 
+	// A shared vnet is only deleted along with the last cluster owning it, the others only release their ownership.
+	if spec, ok := vnetSpec.(*VNetSpec); ok && spec.Shared {
+		last, err := s.ownershipReleaser.ReleaseOwnership(ctx, spec, s.Scope.ClusterName())
+		switch {
+		case azure.ResourceNotFound(err):
+			err = nil
+		case azure.ResourceModified(err):
+			// Another cluster changed the vnet since it was read, its owners are checked again at the next reconciliation.
+			err = azure.WithTransientError(errors.Wrap(err, "shared VNet was modified concurrently"), reconciler.DefaultReconcilerRequeue)
+		case err != nil:
+			err = errors.Wrap(err, "failed to release the ownership of the shared VNet")
+		case !last:
+			log.Info("Skipping VNet deletion, the VNet is still owned by other clusters", "owners", tags.Owners())
+		}
+		if err != nil || !last {
+			s.Scope.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, err)
+			return err
+		}
+	}
+
 	err = s.DeleteResource(ctx, vnetSpec, serviceName)
+	// The DDoS protection plan can only be deleted once no vnet is associated with it.
+	if err == nil {
+		if planSpec := s.Scope.DDoSProtectionPlanSpec(); planSpec != nil {
+			err = s.ddosProtectionPlanReconciler.DeleteResource(ctx, planSpec, serviceName)
+		}
+	}
 	s.Scope.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, err)
 	return err
 }
 
+// deleteSecondaryVNets deletes the virtual networks of the secondary regions which are owned by the cluster.
+func (s *Service) deleteSecondaryVNets(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.Service.deleteSecondaryVNets")
+	defer done()
+
+	var result error
+	for _, spec := range s.Scope.SecondaryVNetSpecs() {
+		tags, err := s.getTagsOf(ctx, spec)
+		if err != nil {
+			if azure.ResourceNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "could not get management state of VNet %s", spec.ResourceName())
+		}
+		if !tags.HasOwned(s.Scope.ClusterName()) {
+			log.Info("Skipping deletion of a secondary region VNet not owned by the cluster", "vnet", spec.ResourceName())
+			continue
+		}
+		if err := s.DeleteResource(ctx, spec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+	return result
+}
+
+// reconcileDDoSProtectionPlan creates the DDoS protection plan of the vnet, unless the vnet is not managed by this cluster.
+func (s *Service) reconcileDDoSProtectionPlan(ctx context.Context, planSpec azure.ResourceSpecGetter) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.Service.reconcileDDoSProtectionPlan")
+	defer done()
+
+	// A vnet which doesn't exist yet is created by this cluster.
+	managed, err := s.IsManaged(ctx)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrap(err, "could not get VNet management state")
+	}
+	if err == nil && !managed {
+		log.V(4).Info("Skipping DDoS protection plan reconcile in custom vnet mode")
+		return nil
+	}
+
+	_, err = s.ddosProtectionPlanReconciler.CreateOrUpdateResource(ctx, planSpec, serviceName)
+	return err
+}
+
 // IsManaged returns true if the virtual network has an owned tag with the cluster name as value,
 // meaning that the vnet's lifecycle is managed.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.Service.IsManaged")
 	defer done()
 
+	tags, err := s.getTags(ctx)
+	if err != nil {
+		return false, err
+	}
+	return tags.HasOwned(s.Scope.ClusterName()), nil
+}
+
+// getTags returns the tags of the virtual network.
+func (s *Service) getTags(ctx context.Context) (infrav1.Tags, error) {
 	spec := s.Scope.VNetSpec()
 	if spec == nil {
-		return false, errors.New("cannot get vnet to check if it is managed: spec is nil")
+		return nil, errors.New("cannot get vnet to check if it is managed: spec is nil")
 	}
 
+	return s.getTagsOf(ctx, spec)
+}
+
+// getTagsOf returns the tags of the virtual network of spec.
+func (s *Service) getTagsOf(ctx context.Context, spec azure.ResourceSpecGetter) (infrav1.Tags, error) {
 	scope := azure.VNetID(s.Scope.SubscriptionID(), spec.ResourceGroupName(), spec.ResourceName())
 	result, err := s.TagsGetter.GetAtScope(ctx, scope)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	tagsMap := make(map[string]*string)
 	if result.Properties != nil && result.Properties.Tags != nil {
 		tagsMap = result.Properties.Tags
 	}
-
-	tags := converters.MapToTags(tagsMap)
-	return tags.HasOwned(s.Scope.ClusterName()), nil
+	return converters.MapToTags(tagsMap), nil
 }
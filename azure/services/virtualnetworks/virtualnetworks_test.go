@@ -19,8 +19,10 @@ package virtualnetworks
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/Azure/go-autorest/autorest"
@@ -92,7 +94,30 @@ var (
 			},
 		},
 	}
+	fakeSecondaryVNetSpec = VNetSpec{
+		ResourceGroup:  "test-group",
+		Name:           "test-vnet-eastus",
+		CIDRs:          []string{"172.16.0.0/16"},
+		Location:       "eastus",
+		ClusterName:    "test-cluster",
+		AdditionalTags: map[string]string{"foo": "bar"},
+	}
+	fakeOtherSecondaryVNetSpec = VNetSpec{
+		ResourceGroup:  "test-group",
+		Name:           "test-vnet-centralus",
+		CIDRs:          []string{"172.17.0.0/16"},
+		Location:       "centralus",
+		ClusterName:    "test-cluster",
+		AdditionalTags: map[string]string{"foo": "bar"},
+	}
+	fakeDDoSProtectionPlanSpec = DDoSProtectionPlanSpec{
+		Name:          "test-ddos-protection-plan",
+		ResourceGroup: "test-group",
+		Location:      "test-location",
+		ClusterName:   "test-cluster",
+	}
 	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+	notFoundError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "Not Found")
 )
 
 func TestReconcileVnet(t *testing.T) {
@@ -113,7 +138,9 @@ func TestReconcileVnet(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.VNetSpec().Return(&fakeVNetSpec)
+				s.DDoSProtectionPlanSpec().Return(nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVNetSpec, serviceName).Return(nil, nil)
+				s.SecondaryVNetSpecs().Return(nil)
 				s.IsVnetManaged().Return(false)
 			},
 		},
@@ -122,7 +149,9 @@ func TestReconcileVnet(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.VNetSpec().Return(&fakeVNetSpec)
+				s.DDoSProtectionPlanSpec().Return(nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVNetSpec, serviceName).Return(nil, nil)
+				s.SecondaryVNetSpecs().Return(nil)
 				s.IsVnetManaged().Return(true)
 				s.UpdatePutStatus(infrav1.VNetReadyCondition, serviceName, nil)
 			},
@@ -132,7 +161,9 @@ func TestReconcileVnet(t *testing.T) {
 			expectedError: internalError.Error(),
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.VNetSpec().Return(&fakeVNetSpec)
+				s.DDoSProtectionPlanSpec().Return(nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVNetSpec, serviceName).Return(nil, internalError)
+				s.SecondaryVNetSpecs().Return(nil)
 				s.IsVnetManaged().Return(true)
 				s.UpdatePutStatus(infrav1.VNetReadyCondition, serviceName, internalError)
 			},
@@ -142,13 +173,85 @@ func TestReconcileVnet(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.VNetSpec().Return(&fakeVNetSpec)
+				s.DDoSProtectionPlanSpec().Return(nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVNetSpec, serviceName).Return(customVnet, nil)
+				s.SecondaryVNetSpecs().Return(nil)
 				s.Vnet().Return(&infrav1.VnetSpec{})
 				s.UpdateSubnetCIDRs("test-subnet", []string{"subnet-cidr"})
 				s.UpdateSubnetCIDRs("test-subnet-2", []string{"subnet-cidr-1", "subnet-cidr-2"})
 				s.IsVnetManaged().Return(false)
 			},
 		},
+		{
+			name:          "DDoS protection plan is created before a new vnet",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Times(2).Return(&fakeVNetSpec)
+				s.DDoSProtectionPlanSpec().Return(&fakeDDoSProtectionPlanSpec)
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), azure.VNetID("123", fakeVNetSpec.ResourceGroupName(), fakeVNetSpec.Name)).Return(armresources.TagsResource{}, notFoundError)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeDDoSProtectionPlanSpec, serviceName).Return(nil, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVNetSpec, serviceName).Return(nil, nil)
+				s.SecondaryVNetSpecs().Return(nil)
+				s.IsVnetManaged().Return(true)
+				s.UpdatePutStatus(infrav1.VNetReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "DDoS protection plan is not created for a custom vnet",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Times(2).Return(&fakeVNetSpec)
+				s.DDoSProtectionPlanSpec().Return(&fakeDDoSProtectionPlanSpec)
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), azure.VNetID("123", fakeVNetSpec.ResourceGroupName(), fakeVNetSpec.Name)).Return(unmanagedTags, nil)
+				s.ClusterName().Return("test-cluster")
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVNetSpec, serviceName).Return(nil, nil)
+				s.SecondaryVNetSpecs().Return(nil)
+				s.IsVnetManaged().Return(false)
+			},
+		},
+		{
+			name:          "secondary region vnets are created after the vnet",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Return(&fakeVNetSpec)
+				s.DDoSProtectionPlanSpec().Return(nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVNetSpec, serviceName).Return(nil, nil)
+				s.SecondaryVNetSpecs().Return([]azure.ResourceSpecGetter{&fakeSecondaryVNetSpec, &fakeOtherSecondaryVNetSpec})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeSecondaryVNetSpec, serviceName).Return(nil, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeOtherSecondaryVNetSpec, serviceName).Return(nil, nil)
+				s.IsVnetManaged().Return(true)
+				s.UpdatePutStatus(infrav1.VNetReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "create secondary region vnet fails, should return an error",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Return(&fakeVNetSpec)
+				s.DDoSProtectionPlanSpec().Return(nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVNetSpec, serviceName).Return(nil, nil)
+				s.SecondaryVNetSpecs().Return([]azure.ResourceSpecGetter{&fakeSecondaryVNetSpec, &fakeOtherSecondaryVNetSpec})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeSecondaryVNetSpec, serviceName).Return(nil, internalError)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeOtherSecondaryVNetSpec, serviceName).Return(nil, nil)
+				s.IsVnetManaged().Return(true)
+				s.UpdatePutStatus(infrav1.VNetReadyCondition, serviceName, internalError)
+			},
+		},
+		{
+			name:          "create DDoS protection plan fails, should return an error",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Times(2).Return(&fakeVNetSpec)
+				s.DDoSProtectionPlanSpec().Return(&fakeDDoSProtectionPlanSpec)
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), azure.VNetID("123", fakeVNetSpec.ResourceGroupName(), fakeVNetSpec.Name)).Return(managedTags, nil)
+				s.ClusterName().Return("test-cluster")
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeDDoSProtectionPlanSpec, serviceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.VNetReadyCondition, serviceName, internalError)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -169,6 +272,8 @@ func TestReconcileVnet(t *testing.T) {
 				Scope:      scopeMock,
 				TagsGetter: tagsGetterMock,
 				Reconciler: reconcilerMock,
+
+				ddosProtectionPlanReconciler: reconcilerMock,
 			}
 
 			err := s.Reconcile(context.TODO())
@@ -200,10 +305,27 @@ func TestDeleteVnet(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.VNetSpec().Times(2).Return(&fakeVNetSpec)
+				s.SecondaryVNetSpecs().Return(nil)
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), azure.VNetID("123", fakeVNetSpec.ResourceGroupName(), fakeVNetSpec.Name)).Return(managedTags, nil)
+				s.ClusterName().Return("test-cluster")
+				r.DeleteResource(gomockinternal.AContext(), &fakeVNetSpec, serviceName).Return(nil)
+				s.DDoSProtectionPlanSpec().Return(nil)
+				s.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "DDoS protection plan is deleted after the vnet",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Times(2).Return(&fakeVNetSpec)
+				s.SecondaryVNetSpecs().Return(nil)
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.VNetID("123", fakeVNetSpec.ResourceGroupName(), fakeVNetSpec.Name)).Return(managedTags, nil)
 				s.ClusterName().Return("test-cluster")
 				r.DeleteResource(gomockinternal.AContext(), &fakeVNetSpec, serviceName).Return(nil)
+				s.DDoSProtectionPlanSpec().Return(&fakeDDoSProtectionPlanSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeDDoSProtectionPlanSpec, serviceName).Return(nil)
 				s.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, nil)
 			},
 		},
@@ -212,6 +334,7 @@ func TestDeleteVnet(t *testing.T) {
 			expectedError: internalError.Error(),
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.VNetSpec().Times(2).Return(&fakeVNetSpec)
+				s.SecondaryVNetSpecs().Return(nil)
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.VNetID("123", fakeVNetSpec.ResourceGroupName(), fakeVNetSpec.Name)).Return(managedTags, nil)
 				s.ClusterName().Return("test-cluster")
@@ -219,11 +342,42 @@ func TestDeleteVnet(t *testing.T) {
 				s.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, internalError)
 			},
 		},
+		{
+			name:          "secondary region vnets owned by the cluster are deleted before the vnet",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Times(2).Return(&fakeVNetSpec)
+				s.SecondaryVNetSpecs().Return([]azure.ResourceSpecGetter{&fakeSecondaryVNetSpec, &fakeOtherSecondaryVNetSpec})
+				s.SubscriptionID().Times(3).Return("123")
+				m.GetAtScope(gomockinternal.AContext(), azure.VNetID("123", fakeSecondaryVNetSpec.ResourceGroupName(), fakeSecondaryVNetSpec.Name)).Return(managedTags, nil)
+				m.GetAtScope(gomockinternal.AContext(), azure.VNetID("123", fakeOtherSecondaryVNetSpec.ResourceGroupName(), fakeOtherSecondaryVNetSpec.Name)).Return(unmanagedTags, nil)
+				m.GetAtScope(gomockinternal.AContext(), azure.VNetID("123", fakeVNetSpec.ResourceGroupName(), fakeVNetSpec.Name)).Return(managedTags, nil)
+				s.ClusterName().Times(3).Return("test-cluster")
+				r.DeleteResource(gomockinternal.AContext(), &fakeSecondaryVNetSpec, serviceName).Return(nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeVNetSpec, serviceName).Return(nil)
+				s.DDoSProtectionPlanSpec().Return(nil)
+				s.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "delete secondary region vnet fails, should return an error",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Return(&fakeVNetSpec)
+				s.SecondaryVNetSpecs().Return([]azure.ResourceSpecGetter{&fakeSecondaryVNetSpec})
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), azure.VNetID("123", fakeSecondaryVNetSpec.ResourceGroupName(), fakeSecondaryVNetSpec.Name)).Return(managedTags, nil)
+				s.ClusterName().Return("test-cluster")
+				r.DeleteResource(gomockinternal.AContext(), &fakeSecondaryVNetSpec, serviceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, internalError)
+			},
+		},
 		{
 			name:          "vnet is not managed, do nothing",
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.VNetSpec().Times(2).Return(&fakeVNetSpec)
+				s.SecondaryVNetSpecs().Return(nil)
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.VNetID("123", fakeVNetSpec.ResourceGroupName(), fakeVNetSpec.Name)).Return(unmanagedTags, nil)
 				s.ClusterName().Return("test-cluster")
@@ -248,6 +402,8 @@ func TestDeleteVnet(t *testing.T) {
 				Scope:      scopeMock,
 				TagsGetter: tagsGetterMock,
 				Reconciler: reconcilerMock,
+
+				ddosProtectionPlanReconciler: reconcilerMock,
 			}
 
 			err := s.Delete(context.TODO())
@@ -337,3 +493,119 @@ func TestIsVnetManaged(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteSharedVnet(t *testing.T) {
+	sharedVNetSpec := fakeVNetSpec
+	sharedVNetSpec.Shared = true
+	vnetID := azure.VNetID("123", sharedVNetSpec.ResourceGroupName(), sharedVNetSpec.Name)
+	sharedTags := armresources.TagsResource{
+		Properties: &armresources.Tags{
+			Tags: map[string]*string{
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster":  ptr.To("owned"),
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_other-cluster": ptr.To("owned"),
+			},
+		},
+	}
+	preconditionFailedError := &azcore.ResponseError{
+		StatusCode: http.StatusPreconditionFailed,
+		RawResponse: &http.Response{
+			StatusCode: http.StatusPreconditionFailed,
+			Body:       http.NoBody,
+			Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{}},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, o *mock_async.MockOwnershipReleaserMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "vnet owned by other clusters is not deleted, only released",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, o *mock_async.MockOwnershipReleaserMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Times(2).Return(&sharedVNetSpec)
+				s.SecondaryVNetSpecs().Return(nil)
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), vnetID).Return(sharedTags, nil)
+				s.ClusterName().Times(2).Return("test-cluster")
+				o.ReleaseOwnership(gomockinternal.AContext(), &sharedVNetSpec, "test-cluster").Return(false, nil)
+				s.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "vnet owned by this cluster only is deleted",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, o *mock_async.MockOwnershipReleaserMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Times(2).Return(&sharedVNetSpec)
+				s.SecondaryVNetSpecs().Return(nil)
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), vnetID).Return(managedTags, nil)
+				s.ClusterName().Times(2).Return("test-cluster")
+				o.ReleaseOwnership(gomockinternal.AContext(), &sharedVNetSpec, "test-cluster").Return(true, nil)
+				r.DeleteResource(gomockinternal.AContext(), &sharedVNetSpec, serviceName).Return(nil)
+				s.DDoSProtectionPlanSpec().Return(nil)
+				s.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "vnet modified by another cluster while being released, should requeue",
+			expectedError: "shared VNet was modified concurrently: " + preconditionFailedError.Error() + ". Object will be requeued after 15s",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, o *mock_async.MockOwnershipReleaserMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Times(2).Return(&sharedVNetSpec)
+				s.SecondaryVNetSpecs().Return(nil)
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), vnetID).Return(sharedTags, nil)
+				s.ClusterName().Times(2).Return("test-cluster")
+				o.ReleaseOwnership(gomockinternal.AContext(), &sharedVNetSpec, "test-cluster").Return(false, preconditionFailedError)
+				s.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "releasing the vnet fails, should return an error",
+			expectedError: "failed to release the ownership of the shared VNet: " + internalError.Error(),
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, o *mock_async.MockOwnershipReleaserMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VNetSpec().Times(2).Return(&sharedVNetSpec)
+				s.SecondaryVNetSpecs().Return(nil)
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), vnetID).Return(sharedTags, nil)
+				s.ClusterName().Times(2).Return("test-cluster")
+				o.ReleaseOwnership(gomockinternal.AContext(), &sharedVNetSpec, "test-cluster").Return(false, internalError)
+				s.UpdateDeleteStatus(infrav1.VNetReadyCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_virtualnetworks.NewMockVNetScope(mockCtrl)
+			tagsGetterMock := mock_async.NewMockTagsGetter(mockCtrl)
+			ownershipReleaserMock := mock_async.NewMockOwnershipReleaser(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), tagsGetterMock.EXPECT(), ownershipReleaserMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:             scopeMock,
+				TagsGetter:        tagsGetterMock,
+				Reconciler:        reconcilerMock,
+				ownershipReleaser: ownershipReleaserMock,
+
+				ddosProtectionPlanReconciler: reconcilerMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -50,13 +50,20 @@ func (s *VMExtensionSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the VM extension.
 func (s *VMExtensionSpec) Parameters(ctx context.Context, existing interface{}) (interface{}, error) {
 	if existing != nil {
-		_, ok := existing.(armcompute.VirtualMachineExtension)
+		existingExtension, ok := existing.(armcompute.VirtualMachineExtension)
 		if !ok {
 			return nil, errors.Errorf("%T is not an armcompute.VirtualMachineExtension", existing)
 		}
 
-		// VM extension already exists, nothing to update.
-		return nil, nil
+		// VM extension already exists, nothing to update unless its force update tag changed.
+		if s.ForceUpdateTag == "" || (existingExtension.Properties != nil && ptr.Deref(existingExtension.Properties.ForceUpdateTag, "") == s.ForceUpdateTag) {
+			return nil, nil
+		}
+	}
+
+	var forceUpdateTag *string
+	if s.ForceUpdateTag != "" {
+		forceUpdateTag = ptr.To(s.ForceUpdateTag)
 	}
 
 	return armcompute.VirtualMachineExtension{
@@ -66,6 +73,7 @@ func (s *VMExtensionSpec) Parameters(ctx context.Context, existing interface{})
 			TypeHandlerVersion: ptr.To(s.Version),
 			Settings:           s.Settings,
 			ProtectedSettings:  s.ProtectedSettings,
+			ForceUpdateTag:     forceUpdateTag,
 		},
 		Location: ptr.To(s.Location),
 	}, nil
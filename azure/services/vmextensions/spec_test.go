@@ -78,6 +78,24 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "vmextension that already exists with the same force update tag",
+			spec:     withForceUpdateTag(fakeVMExtensionSpec, "tag-1"),
+			existing: withExistingForceUpdateTag(fakeVMExtensionParams, "tag-1"),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name:     "vmextension that already exists with another force update tag",
+			spec:     withForceUpdateTag(fakeVMExtensionSpec, "tag-2"),
+			existing: withExistingForceUpdateTag(fakeVMExtensionParams, "tag-1"),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(withExistingForceUpdateTag(fakeVMExtensionParams, "tag-2")))
+			},
+			expectedError: "",
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -96,3 +114,15 @@ func TestParameters(t *testing.T) {
 		})
 	}
 }
+
+func withForceUpdateTag(spec VMExtensionSpec, tag string) *VMExtensionSpec {
+	spec.ForceUpdateTag = tag
+	return &spec
+}
+
+func withExistingForceUpdateTag(extension armcompute.VirtualMachineExtension, tag string) armcompute.VirtualMachineExtension {
+	properties := *extension.Properties
+	properties.ForceUpdateTag = ptr.To(tag)
+	extension.Properties = &properties
+	return extension
+}
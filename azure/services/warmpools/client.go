@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package warmpools
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// powerStatePrefix is the prefix of the instance view status codes reporting the power state of a VM.
+const powerStatePrefix = "PowerState/"
+
+// Client wraps go-sdk.
+type Client interface {
+	PowerStates(ctx context.Context, resourceGroupName, vmssName string) (map[string]string, error)
+	Start(ctx context.Context, resourceGroupName, vmssName, instanceID string) error
+	Deallocate(ctx context.Context, resourceGroupName, vmssName, instanceID string) error
+	Delete(ctx context.Context, resourceGroupName, vmssName, instanceID string) error
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	scalesetvms *armcompute.VirtualMachineScaleSetVMsClient
+}
+
+var _ Client = (*AzureClient)(nil)
+
+// NewClient creates a new warm pools client from an authorizer.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create warm pools client options")
+	}
+	factory, err := armcompute.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armcompute client factory")
+	}
+	return &AzureClient{factory.NewVirtualMachineScaleSetVMsClient()}, nil
+}
+
+// PowerStates returns the power state of the instances of a scale set, e.g. "running" or "deallocated", by instance
+// ID. Instances whose power state is not reported yet are omitted.
+func (ac *AzureClient) PowerStates(ctx context.Context, resourceGroupName, vmssName string) (map[string]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "warmpools.AzureClient.PowerStates")
+	defer done()
+
+	pager := ac.scalesetvms.NewListPager(resourceGroupName, vmssName, &armcompute.VirtualMachineScaleSetVMsClientListOptions{
+		Expand: ptr.To("instanceView"),
+	})
+	instances, err := azure.CollectPages(ctx, pager, func(page armcompute.VirtualMachineScaleSetVMsClientListResponse) []*armcompute.VirtualMachineScaleSetVM {
+		return page.Value
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not iterate scalesetvms")
+	}
+
+	powerStates := make(map[string]string, len(instances))
+	for _, instance := range instances {
+		if instance.Properties == nil || instance.Properties.InstanceView == nil {
+			continue
+		}
+		for _, status := range instance.Properties.InstanceView.Statuses {
+			if code := ptr.Deref(status.Code, ""); strings.HasPrefix(code, powerStatePrefix) {
+				powerStates[ptr.Deref(instance.InstanceID, "")] = strings.TrimPrefix(code, powerStatePrefix)
+				break
+			}
+		}
+	}
+	return powerStates, nil
+}
+
+// Start starts a scale set instance. It does not wait for the instance to be running, the AzureMachinePoolMachine of
+// the instance reports when it is.
+func (ac *AzureClient) Start(ctx context.Context, resourceGroupName, vmssName, instanceID string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "warmpools.AzureClient.Start")
+	defer done()
+
+	_, err := ac.scalesetvms.BeginStart(ctx, resourceGroupName, vmssName, instanceID, nil)
+	return err
+}
+
+// Deallocate starts the deallocation of a scale set instance. It does not wait for the deallocation to complete, the
+// power state of the instance is checked again on the next reconciliation.
+func (ac *AzureClient) Deallocate(ctx context.Context, resourceGroupName, vmssName, instanceID string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "warmpools.AzureClient.Deallocate")
+	defer done()
+
+	_, err := ac.scalesetvms.BeginDeallocate(ctx, resourceGroupName, vmssName, instanceID, nil)
+	return err
+}
+
+// Delete starts the deletion of a scale set instance. It does not wait for the deletion to complete, the scale set
+// replaces the instance once its capacity is reconciled.
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vmssName, instanceID string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "warmpools.AzureClient.Delete")
+	defer done()
+
+	_, err := ac.scalesetvms.BeginDelete(ctx, resourceGroupName, vmssName, instanceID, nil)
+	return err
+}
@@ -0,0 +1,112 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+//
+// Generated by this command:
+//
+//	mockgen -destination client_mock.go -package mock_warmpools -source ../client.go client
+//
+// Package mock_warmpools is a generated GoMock package.
+package mock_warmpools
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// Deallocate mocks base method.
+func (m *MockClient) Deallocate(ctx context.Context, resourceGroupName, vmssName, instanceID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deallocate", ctx, resourceGroupName, vmssName, instanceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Deallocate indicates an expected call of Deallocate.
+func (mr *MockClientMockRecorder) Deallocate(ctx, resourceGroupName, vmssName, instanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deallocate", reflect.TypeOf((*MockClient)(nil).Deallocate), ctx, resourceGroupName, vmssName, instanceID)
+}
+
+// Delete mocks base method.
+func (m *MockClient) Delete(ctx context.Context, resourceGroupName, vmssName, instanceID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, resourceGroupName, vmssName, instanceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockClientMockRecorder) Delete(ctx, resourceGroupName, vmssName, instanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClient)(nil).Delete), ctx, resourceGroupName, vmssName, instanceID)
+}
+
+// PowerStates mocks base method.
+func (m *MockClient) PowerStates(ctx context.Context, resourceGroupName, vmssName string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PowerStates", ctx, resourceGroupName, vmssName)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PowerStates indicates an expected call of PowerStates.
+func (mr *MockClientMockRecorder) PowerStates(ctx, resourceGroupName, vmssName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PowerStates", reflect.TypeOf((*MockClient)(nil).PowerStates), ctx, resourceGroupName, vmssName)
+}
+
+// Start mocks base method.
+func (m *MockClient) Start(ctx context.Context, resourceGroupName, vmssName, instanceID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, resourceGroupName, vmssName, instanceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockClientMockRecorder) Start(ctx, resourceGroupName, vmssName, instanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockClient)(nil).Start), ctx, resourceGroupName, vmssName, instanceID)
+}
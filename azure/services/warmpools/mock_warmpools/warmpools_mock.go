@@ -0,0 +1,280 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../warmpools.go
+//
+// Generated by this command:
+//
+//	mockgen -destination warmpools_mock.go -package mock_warmpools -source ../warmpools.go WarmPoolScope
+//
+// Package mock_warmpools is a generated GoMock package.
+package mock_warmpools
+
+import (
+	context "context"
+	reflect "reflect"
+
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	gomock "go.uber.org/mock/gomock"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockWarmPoolScope is a mock of WarmPoolScope interface.
+type MockWarmPoolScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockWarmPoolScopeMockRecorder
+}
+
+// MockWarmPoolScopeMockRecorder is the mock recorder for MockWarmPoolScope.
+type MockWarmPoolScopeMockRecorder struct {
+	mock *MockWarmPoolScope
+}
+
+// NewMockWarmPoolScope creates a new mock instance.
+func NewMockWarmPoolScope(ctrl *gomock.Controller) *MockWarmPoolScope {
+	mock := &MockWarmPoolScope{ctrl: ctrl}
+	mock.recorder = &MockWarmPoolScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWarmPoolScope) EXPECT() *MockWarmPoolScopeMockRecorder {
+	return m.recorder
+}
+
+// BaseURI mocks base method.
+func (m *MockWarmPoolScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockWarmPoolScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockWarmPoolScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockWarmPoolScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockWarmPoolScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockWarmPoolScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockWarmPoolScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockWarmPoolScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockWarmPoolScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockWarmPoolScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockWarmPoolScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockWarmPoolScope)(nil).CloudEnvironment))
+}
+
+// DesiredReplicas mocks base method.
+func (m *MockWarmPoolScope) DesiredReplicas() int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DesiredReplicas")
+	ret0, _ := ret[0].(int32)
+	return ret0
+}
+
+// DesiredReplicas indicates an expected call of DesiredReplicas.
+func (mr *MockWarmPoolScopeMockRecorder) DesiredReplicas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DesiredReplicas", reflect.TypeOf((*MockWarmPoolScope)(nil).DesiredReplicas))
+}
+
+// HashKey mocks base method.
+func (m *MockWarmPoolScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockWarmPoolScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockWarmPoolScope)(nil).HashKey))
+}
+
+// MachinePoolMachineInstanceIDs mocks base method.
+func (m *MockWarmPoolScope) MachinePoolMachineInstanceIDs(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MachinePoolMachineInstanceIDs", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MachinePoolMachineInstanceIDs indicates an expected call of MachinePoolMachineInstanceIDs.
+func (mr *MockWarmPoolScopeMockRecorder) MachinePoolMachineInstanceIDs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MachinePoolMachineInstanceIDs", reflect.TypeOf((*MockWarmPoolScope)(nil).MachinePoolMachineInstanceIDs), ctx)
+}
+
+// Name mocks base method.
+func (m *MockWarmPoolScope) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockWarmPoolScopeMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockWarmPoolScope)(nil).Name))
+}
+
+// ResourceGroup mocks base method.
+func (m *MockWarmPoolScope) ResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceGroup indicates an expected call of ResourceGroup.
+func (mr *MockWarmPoolScopeMockRecorder) ResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockWarmPoolScope)(nil).ResourceGroup))
+}
+
+// SetWarmPoolStatus mocks base method.
+func (m *MockWarmPoolScope) SetWarmPoolStatus(instanceIDs []string, readyInstances int32) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetWarmPoolStatus", instanceIDs, readyInstances)
+}
+
+// SetWarmPoolStatus indicates an expected call of SetWarmPoolStatus.
+func (mr *MockWarmPoolScopeMockRecorder) SetWarmPoolStatus(instanceIDs, readyInstances any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWarmPoolStatus", reflect.TypeOf((*MockWarmPoolScope)(nil).SetWarmPoolStatus), instanceIDs, readyInstances)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockWarmPoolScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockWarmPoolScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockWarmPoolScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockWarmPoolScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockWarmPoolScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockWarmPoolScope)(nil).TenantID))
+}
+
+// Token mocks base method.
+func (m *MockWarmPoolScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockWarmPoolScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockWarmPoolScope)(nil).Token))
+}
+
+// VMSSState mocks base method.
+func (m *MockWarmPoolScope) VMSSState() *azure.VMSS {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VMSSState")
+	ret0, _ := ret[0].(*azure.VMSS)
+	return ret0
+}
+
+// VMSSState indicates an expected call of VMSSState.
+func (mr *MockWarmPoolScopeMockRecorder) VMSSState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSSState", reflect.TypeOf((*MockWarmPoolScope)(nil).VMSSState))
+}
+
+// WarmInstanceIDs mocks base method.
+func (m *MockWarmPoolScope) WarmInstanceIDs() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WarmInstanceIDs")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// WarmInstanceIDs indicates an expected call of WarmInstanceIDs.
+func (mr *MockWarmPoolScopeMockRecorder) WarmInstanceIDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmInstanceIDs", reflect.TypeOf((*MockWarmPoolScope)(nil).WarmInstanceIDs))
+}
+
+// WarmPoolSize mocks base method.
+func (m *MockWarmPoolScope) WarmPoolSize() int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WarmPoolSize")
+	ret0, _ := ret[0].(int32)
+	return ret0
+}
+
+// WarmPoolSize indicates an expected call of WarmPoolSize.
+func (mr *MockWarmPoolScopeMockRecorder) WarmPoolSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmPoolSize", reflect.TypeOf((*MockWarmPoolScope)(nil).WarmPoolSize))
+}
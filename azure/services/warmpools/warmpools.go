@@ -0,0 +1,252 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package warmpools keeps deallocated instances of the scale sets of machine pools ready to start on scale up.
+package warmpools
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "warmpools"
+
+const (
+	powerStateRunning      = "running"
+	powerStateDeallocating = "deallocating"
+	powerStateDeallocated  = "deallocated"
+	powerStateStopping     = "stopping"
+	powerStateStopped      = "stopped"
+)
+
+// WarmPoolScope defines the scope interface for a warm pools service.
+type WarmPoolScope interface {
+	azure.Authorizer
+	ResourceGroup() string
+	Name() string
+	DesiredReplicas() int32
+	WarmPoolSize() int32
+	WarmInstanceIDs() []string
+	SetWarmPoolStatus(instanceIDs []string, readyInstances int32)
+	VMSSState() *azure.VMSS
+	MachinePoolMachineInstanceIDs(ctx context.Context) ([]string, error)
+}
+
+// Service keeps the warm pool of a machine pool filled with deallocated instances, and starts them on scale up.
+type Service struct {
+	Scope WarmPoolScope
+	Client
+}
+
+// New creates a new warm pools service.
+func New(scope WarmPoolScope) (*Service, error) {
+	client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:  scope,
+		Client: client,
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile fills the warm pool with the new instances of the scale set which are not needed as replicas, deallocates
+// them once they are bootstrapped, and starts them when the machine pool is short of replicas. The scale set capacity
+// includes the warm pool, so the scale set replaces the instances started or deleted from the warm pool.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "warmpools.Service.Reconcile")
+	defer done()
+
+	vmss := s.Scope.VMSSState()
+	if vmss == nil {
+		return nil
+	}
+	if s.Scope.WarmPoolSize() == 0 && len(s.Scope.WarmInstanceIDs()) == 0 {
+		s.Scope.SetWarmPoolStatus(nil, 0)
+		return nil
+	}
+
+	machineInstanceIDs, err := s.Scope.MachinePoolMachineInstanceIDs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the instances of the machine pool")
+	}
+	powerStates, err := s.Client.PowerStates(ctx, s.Scope.ResourceGroup(), s.Scope.Name())
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the power states of the instances of scale set %s", s.Scope.Name())
+	}
+
+	p := plan(vmss, s.Scope.WarmInstanceIDs(), machineInstanceIDs, powerStates, s.Scope.DesiredReplicas(), s.Scope.WarmPoolSize())
+	warm := p.warm
+	var errs []error
+	for _, id := range p.start {
+		log.V(2).Info("starting warm pool instance", "instanceID", id)
+		if err := s.Client.Start(ctx, s.Scope.ResourceGroup(), s.Scope.Name(), id); err != nil {
+			// The instance is kept in the warm pool so that it doesn't join the machine pool deallocated.
+			errs = append(errs, errors.Wrapf(err, "failed to start instance %s of scale set %s", id, s.Scope.Name()))
+			warm = append(warm, id)
+		}
+	}
+	for _, id := range p.deallocate {
+		log.V(2).Info("deallocating warm pool instance", "instanceID", id)
+		if err := s.Client.Deallocate(ctx, s.Scope.ResourceGroup(), s.Scope.Name(), id); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to deallocate instance %s of scale set %s", id, s.Scope.Name()))
+		}
+	}
+	for _, id := range p.delete {
+		log.V(2).Info("deleting warm pool instance", "instanceID", id)
+		if err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), s.Scope.Name(), id); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to delete instance %s of scale set %s", id, s.Scope.Name()))
+		}
+	}
+
+	sort.Strings(warm)
+	s.Scope.SetWarmPoolStatus(warm, p.ready)
+	return kerrors.NewAggregate(errs)
+}
+
+// Delete is a no-op as the instances of the warm pool are deleted with the scale set.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// warmPoolPlan is the result of a reconciliation of a warm pool.
+type warmPoolPlan struct {
+	// warm are the instances in the warm pool once the plan is applied, including the instances being deleted.
+	warm []string
+	// start are the instances leaving the warm pool to become replicas which need to be started.
+	start []string
+	// deallocate are the instances of the warm pool which are bootstrapped but still running.
+	deallocate []string
+	// delete are the instances of the warm pool which are failed, outdated, or in excess of its size.
+	delete []string
+	// ready is the number of instances of the warm pool which are deallocated.
+	ready int32
+}
+
+// plan reconciles the warm pool of a scale set given the instances of the warm pool, the instances of the
+// AzureMachinePoolMachines and the power states of the instances.
+// Warm instances make up for missing replicas first, then the new instances which are not needed as replicas fill the
+// warm pool up to its size.
+func plan(vmss *azure.VMSS, warmInstanceIDs, machineInstanceIDs []string, powerStates map[string]string, replicas, size int32) warmPoolPlan {
+	var p warmPoolPlan
+	instances := make(map[string]azure.VMSSVM, len(vmss.Instances))
+	for _, instance := range vmss.Instances {
+		instances[instance.InstanceID] = instance
+	}
+	machines := sets.New[string]()
+	for _, id := range machineInstanceIDs {
+		if _, ok := instances[id]; ok {
+			machines.Insert(id)
+		}
+	}
+
+	// Instances which no longer exist or which have become replicas leave the warm pool. Instances being deleted stay in
+	// it until they are gone, so that they don't become replicas.
+	var usable []azure.VMSSVM
+	for _, id := range warmInstanceIDs {
+		instance, ok := instances[id]
+		if !ok || machines.Has(id) {
+			continue
+		}
+		switch {
+		case instance.State == infrav1.Deleting:
+			p.warm = append(p.warm, id)
+		case len(usable) >= int(size), instance.State == infrav1.Failed, instance.BootstrappingState == infrav1.Failed,
+			!vmss.HasLatestModelApplied(instance):
+			p.warm = append(p.warm, id)
+			p.delete = append(p.delete, id)
+		default:
+			usable = append(usable, instance)
+		}
+	}
+
+	// Warm instances make up for the missing replicas, starting with the deallocated ones as they are bootstrapped.
+	sort.SliceStable(usable, func(i, j int) bool {
+		return powerStates[usable[i].InstanceID] == powerStateDeallocated && powerStates[usable[j].InstanceID] != powerStateDeallocated
+	})
+	missing := int(replicas) - machines.Len()
+	leaving := sets.New[string]()
+	for missing > 0 && len(usable) > 0 {
+		id := usable[0].InstanceID
+		if isStopped(powerStates[id]) {
+			p.start = append(p.start, id)
+		}
+		leaving.Insert(id)
+		usable = usable[1:]
+		missing--
+	}
+
+	// The new instances make up for the replicas the warm pool couldn't provide, and the next ones fill the warm pool.
+	warm := sets.New(p.warm...)
+	for _, instance := range usable {
+		warm.Insert(instance.InstanceID)
+	}
+	var pending []azure.VMSSVM
+	for _, instance := range vmss.Instances {
+		id := instance.InstanceID
+		if !machines.Has(id) && !warm.Has(id) && !leaving.Has(id) && instance.State != infrav1.Deleting {
+			pending = append(pending, instance)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].InstanceID < pending[j].InstanceID
+	})
+	for _, instance := range pending {
+		if missing > 0 {
+			missing--
+			continue
+		}
+		if len(usable) < int(size) {
+			usable = append(usable, instance)
+		}
+	}
+
+	// Instances are deallocated once they are bootstrapped, which requires the bootstrap extension.
+	for _, instance := range usable {
+		p.warm = append(p.warm, instance.InstanceID)
+		switch powerStates[instance.InstanceID] {
+		case powerStateDeallocated:
+			p.ready++
+		case powerStateRunning:
+			if instance.State == infrav1.Succeeded && instance.BootstrappingState == infrav1.Succeeded {
+				p.deallocate = append(p.deallocate, instance.InstanceID)
+			}
+		}
+	}
+	return p
+}
+
+// isStopped returns true if a power state is one of a VM which needs to be started to run.
+func isStopped(powerState string) bool {
+	switch powerState {
+	case powerStateDeallocating, powerStateDeallocated, powerStateStopping, powerStateStopped:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package warmpools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/warmpools/mock_warmpools"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var latestImage = infrav1.Image{ID: ptr.To("latest")}
+
+func instance(id string, bootstrappingState infrav1.ProvisioningState) azure.VMSSVM {
+	return azure.VMSSVM{
+		InstanceID:         id,
+		Image:              latestImage,
+		State:              infrav1.Succeeded,
+		BootstrappingState: bootstrappingState,
+	}
+}
+
+func TestPlan(t *testing.T) {
+	outdated := instance("1", infrav1.Succeeded)
+	outdated.Image = infrav1.Image{ID: ptr.To("outdated")}
+
+	tests := []struct {
+		name               string
+		instances          []azure.VMSSVM
+		warmInstanceIDs    []string
+		machineInstanceIDs []string
+		powerStates        map[string]string
+		replicas           int32
+		size               int32
+		want               warmPoolPlan
+	}{
+		{
+			name:               "new instances fill the warm pool and are deallocated once bootstrapped",
+			instances:          []azure.VMSSVM{instance("0", infrav1.Succeeded), instance("1", infrav1.Succeeded), instance("2", infrav1.Creating)},
+			machineInstanceIDs: []string{"0"},
+			powerStates:        map[string]string{"0": powerStateRunning, "1": powerStateRunning, "2": powerStateRunning},
+			replicas:           1,
+			size:               2,
+			want: warmPoolPlan{
+				warm:       []string{"1", "2"},
+				deallocate: []string{"1"},
+			},
+		},
+		{
+			name:               "new instances are replicas first",
+			instances:          []azure.VMSSVM{instance("0", infrav1.Succeeded), instance("1", infrav1.Succeeded), instance("2", infrav1.Succeeded)},
+			machineInstanceIDs: []string{"0"},
+			powerStates:        map[string]string{"0": powerStateRunning, "1": powerStateRunning, "2": powerStateRunning},
+			replicas:           2,
+			size:               1,
+			want: warmPoolPlan{
+				warm:       []string{"2"},
+				deallocate: []string{"2"},
+			},
+		},
+		{
+			name:               "deallocated warm instances are started on scale up",
+			instances:          []azure.VMSSVM{instance("0", infrav1.Succeeded), instance("1", infrav1.Succeeded), instance("2", infrav1.Creating), instance("3", infrav1.Creating)},
+			warmInstanceIDs:    []string{"2", "1"},
+			machineInstanceIDs: []string{"0"},
+			powerStates:        map[string]string{"0": powerStateRunning, "1": powerStateDeallocated, "2": powerStateRunning, "3": powerStateRunning},
+			replicas:           2,
+			size:               2,
+			want: warmPoolPlan{
+				warm:  []string{"2", "3"},
+				start: []string{"1"},
+			},
+		},
+		{
+			name:               "warm instances which became replicas leave the warm pool",
+			instances:          []azure.VMSSVM{instance("0", infrav1.Succeeded), instance("1", infrav1.Succeeded)},
+			warmInstanceIDs:    []string{"0", "1"},
+			machineInstanceIDs: []string{"0"},
+			powerStates:        map[string]string{"0": powerStateRunning, "1": powerStateDeallocated},
+			replicas:           1,
+			size:               1,
+			want: warmPoolPlan{
+				warm:  []string{"1"},
+				ready: 1,
+			},
+		},
+		{
+			name:            "outdated and excess warm instances are deleted",
+			instances:       []azure.VMSSVM{outdated, instance("2", infrav1.Succeeded), instance("3", infrav1.Succeeded)},
+			warmInstanceIDs: []string{"1", "2", "3"},
+			powerStates:     map[string]string{"1": powerStateDeallocated, "2": powerStateDeallocated, "3": powerStateDeallocated},
+			size:            1,
+			want: warmPoolPlan{
+				warm:   []string{"1", "3", "2"},
+				delete: []string{"1", "3"},
+				ready:  1,
+			},
+		},
+		{
+			name:            "warm instances being deleted stay in the warm pool",
+			instances:       []azure.VMSSVM{{InstanceID: "1", Image: latestImage, State: infrav1.Deleting}},
+			warmInstanceIDs: []string{"1", "2"},
+			powerStates:     map[string]string{"1": powerStateDeallocated},
+			size:            1,
+			want: warmPoolPlan{
+				warm: []string{"1"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			vmss := &azure.VMSS{Image: latestImage, Instances: tc.instances}
+			got := plan(vmss, tc.warmInstanceIDs, tc.machineInstanceIDs, tc.powerStates, tc.replicas, tc.size)
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestReconcileWarmPool(t *testing.T) {
+	vmss := &azure.VMSS{
+		Image:     latestImage,
+		Instances: []azure.VMSSVM{instance("0", infrav1.Succeeded), instance("1", infrav1.Succeeded), instance("2", infrav1.Succeeded)},
+	}
+
+	tests := []struct {
+		name          string
+		expect        func(s *mock_warmpools.MockWarmPoolScopeMockRecorder, c *mock_warmpools.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name: "noop if the scale set has not been observed",
+			expect: func(s *mock_warmpools.MockWarmPoolScopeMockRecorder, c *mock_warmpools.MockClientMockRecorder) {
+				s.VMSSState().Return(nil)
+			},
+		},
+		{
+			name: "clears the status if there is no warm pool",
+			expect: func(s *mock_warmpools.MockWarmPoolScopeMockRecorder, c *mock_warmpools.MockClientMockRecorder) {
+				s.VMSSState().Return(vmss)
+				s.WarmPoolSize().Return(int32(0))
+				s.WarmInstanceIDs().Return(nil)
+				s.SetWarmPoolStatus(nil, int32(0))
+			},
+		},
+		{
+			name: "starts and deallocates warm pool instances",
+			expect: func(s *mock_warmpools.MockWarmPoolScopeMockRecorder, c *mock_warmpools.MockClientMockRecorder) {
+				s.VMSSState().Return(vmss)
+				s.WarmPoolSize().Return(int32(1)).AnyTimes()
+				s.WarmInstanceIDs().Return([]string{"1"}).AnyTimes()
+				s.DesiredReplicas().Return(int32(2))
+				s.ResourceGroup().Return("my-rg").AnyTimes()
+				s.Name().Return("my-vmss").AnyTimes()
+				s.MachinePoolMachineInstanceIDs(gomockinternal.AContext()).Return([]string{"0"}, nil)
+				c.PowerStates(gomockinternal.AContext(), "my-rg", "my-vmss").Return(map[string]string{
+					"0": powerStateRunning,
+					"1": powerStateDeallocated,
+					"2": powerStateRunning,
+				}, nil)
+				c.Start(gomockinternal.AContext(), "my-rg", "my-vmss", "1").Return(nil)
+				c.Deallocate(gomockinternal.AContext(), "my-rg", "my-vmss", "2").Return(nil)
+				s.SetWarmPoolStatus([]string{"2"}, int32(0))
+			},
+		},
+		{
+			name:          "keeps the warm pool instances which fail to start",
+			expectedError: "failed to start instance 1 of scale set my-vmss: start failed",
+			expect: func(s *mock_warmpools.MockWarmPoolScopeMockRecorder, c *mock_warmpools.MockClientMockRecorder) {
+				s.VMSSState().Return(vmss)
+				s.WarmPoolSize().Return(int32(1)).AnyTimes()
+				s.WarmInstanceIDs().Return([]string{"1"}).AnyTimes()
+				s.DesiredReplicas().Return(int32(2))
+				s.ResourceGroup().Return("my-rg").AnyTimes()
+				s.Name().Return("my-vmss").AnyTimes()
+				s.MachinePoolMachineInstanceIDs(gomockinternal.AContext()).Return([]string{"0"}, nil)
+				c.PowerStates(gomockinternal.AContext(), "my-rg", "my-vmss").Return(map[string]string{
+					"0": powerStateRunning,
+					"1": powerStateDeallocated,
+					"2": powerStateRunning,
+				}, nil)
+				c.Start(gomockinternal.AContext(), "my-rg", "my-vmss", "1").Return(errors.New("start failed"))
+				c.Deallocate(gomockinternal.AContext(), "my-rg", "my-vmss", "2").Return(nil)
+				s.SetWarmPoolStatus([]string{"1", "2"}, int32(0))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_warmpools.NewMockWarmPoolScope(mockCtrl)
+			clientMock := mock_warmpools.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
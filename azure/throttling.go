@@ -0,0 +1,252 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// rateLimitRemainingHeaderPrefix is the prefix of the ARM response headers reporting the remaining
+	// subscription quota, e.g. "x-ms-ratelimit-remaining-subscription-reads".
+	rateLimitRemainingHeaderPrefix = "X-Ms-Ratelimit-Remaining-Subscription-"
+
+	// rateLimitLowWatermark is the remaining quota under which requests of the same kind are held back
+	// for rateLimitLowWatermarkBackoff, so the controller slows down before ARM starts returning 429s.
+	rateLimitLowWatermark = 10
+
+	// rateLimitLowWatermarkBackoff is how long requests are held back once the remaining quota
+	// drops under rateLimitLowWatermark.
+	rateLimitLowWatermarkBackoff = 15 * time.Second
+
+	// defaultThrottlingBackoff is used when a 429 response does not carry a usable Retry-After header.
+	defaultThrottlingBackoff = 1 * time.Minute
+
+	// throttledSpanAttribute is set on the span of a throttled ARM request to either "azure" or "local",
+	// matching the source label of capz_arm_throttled_requests_total.
+	throttledSpanAttribute = "arm.throttled"
+)
+
+// Operation kinds used to group ARM requests the same way subscription quotas are tracked.
+const (
+	rateLimitReads   = "reads"
+	rateLimitWrites  = "writes"
+	rateLimitDeletes = "deletes"
+)
+
+var (
+	armRateLimitRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "capz_arm_ratelimit_remaining",
+		Help: "Remaining ARM request quota for a subscription, as last reported by Azure.",
+	}, []string{"subscription_id", "operation"})
+
+	armThrottledRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "capz_arm_throttled_requests_total",
+		Help: "Number of ARM requests that were throttled, either by Azure (source=azure) or held back locally before being sent (source=local).",
+	}, []string{"subscription_id", "operation", "source"})
+
+	// armThrottler is shared by all ARM clients so that every controller backs off together.
+	armThrottler = newThrottler()
+)
+
+func init() {
+	metrics.Registry.MustRegister(armRateLimitRemaining, armThrottledRequests)
+}
+
+// throttlingKey identifies an ARM quota bucket.
+type throttlingKey struct {
+	subscriptionID string
+	operation      string
+}
+
+// throttler keeps track, per subscription and kind of operation, of until when requests should be held back.
+type throttler struct {
+	mu           sync.Mutex
+	blockedUntil map[throttlingKey]time.Time
+	now          func() time.Time
+}
+
+func newThrottler() *throttler {
+	return &throttler{
+		blockedUntil: make(map[throttlingKey]time.Time),
+		now:          time.Now,
+	}
+}
+
+// retryAfter returns how long requests for key are still held back, or zero if they may be sent.
+func (t *throttler) retryAfter(key throttlingKey) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	until, ok := t.blockedUntil[key]
+	if !ok {
+		return 0
+	}
+	if wait := until.Sub(t.now()); wait > 0 {
+		return wait
+	}
+	delete(t.blockedUntil, key)
+	return 0
+}
+
+// block holds back requests for key for the given duration, unless they are already held back for longer.
+func (t *throttler) block(key throttlingKey, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	until := t.now().Add(d)
+	if until.After(t.blockedUntil[key]) {
+		t.blockedUntil[key] = until
+	}
+}
+
+// observe records the quota information of an ARM response.
+func (t *throttler) observe(key throttlingKey, resp *http.Response) {
+	if remaining, ok := remainingQuota(resp.Header, key.operation); ok {
+		armRateLimitRemaining.WithLabelValues(key.subscriptionID, key.operation).Set(float64(remaining))
+		if remaining < rateLimitLowWatermark {
+			t.block(key, rateLimitLowWatermarkBackoff)
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		armThrottledRequests.WithLabelValues(key.subscriptionID, key.operation, "azure").Inc()
+		t.block(key, retryAfterFromHeader(resp.Header))
+	}
+}
+
+// throttlingPolicy holds back ARM requests for a subscription once Azure has throttled it or reported that
+// its remaining quota is nearly exhausted. Held back requests are answered locally with a 429 response
+// carrying a Retry-After header, so callers requeue exactly as if ARM had throttled them.
+// It implements the policy.Policy interface.
+type throttlingPolicy struct {
+	throttler *throttler
+}
+
+// Do sends the request unless the subscription is currently throttled, and records the returned quota.
+func (p throttlingPolicy) Do(req *policy.Request) (*http.Response, error) {
+	subscriptionID := subscriptionIDFromPath(req.Raw().URL.Path)
+	if subscriptionID == "" {
+		return req.Next()
+	}
+	key := throttlingKey{
+		subscriptionID: subscriptionID,
+		operation:      operationKind(req.Raw().Method),
+	}
+
+	if wait := p.throttler.retryAfter(key); wait > 0 {
+		armThrottledRequests.WithLabelValues(key.subscriptionID, key.operation, "local").Inc()
+		trace.SpanFromContext(req.Raw().Context()).SetAttributes(attribute.String(throttledSpanAttribute, "local"))
+		return throttledResponse(req.Raw(), wait), nil
+	}
+
+	resp, err := req.Next()
+	if err == nil && resp != nil {
+		p.throttler.observe(key, resp)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			trace.SpanFromContext(req.Raw().Context()).SetAttributes(attribute.String(throttledSpanAttribute, "azure"))
+		}
+	}
+	return resp, err
+}
+
+// subscriptionIDFromPath returns the subscription ID of an ARM request path, or an empty string if
+// the path is not scoped to a subscription.
+func subscriptionIDFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if strings.EqualFold(segments[i], "subscriptions") {
+			return strings.ToLower(segments[i+1])
+		}
+	}
+	return ""
+}
+
+// operationKind maps an HTTP method to the ARM quota bucket it consumes.
+func operationKind(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return rateLimitReads
+	case http.MethodDelete:
+		return rateLimitDeletes
+	default:
+		return rateLimitWrites
+	}
+}
+
+// remainingQuota returns the remaining subscription quota reported in the response headers for an operation kind.
+func remainingQuota(header http.Header, operation string) (int, bool) {
+	value := header.Get(rateLimitRemainingHeaderPrefix + operation)
+	if value == "" {
+		return 0, false
+	}
+	remaining, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return remaining, true
+}
+
+// retryAfterFromHeader returns the duration described by a Retry-After header, which can be either
+// a number of seconds or an absolute time, falling back to defaultThrottlingBackoff.
+func retryAfterFromHeader(header http.Header) time.Duration {
+	retryAfter := header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := time.Parse(time.RFC1123, retryAfter); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return defaultThrottlingBackoff
+}
+
+// throttledResponse builds a 429 response for a request that was held back locally.
+func throttledResponse(req *http.Request, wait time.Duration) *http.Response {
+	seconds := int(wait.Round(time.Second) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	body := `{"error":{"code":"TooManyRequests","message":"request held back by cluster-api-provider-azure: subscription is being throttled by ARM"}}`
+	return &http.Response{
+		Status:     http.StatusText(http.StatusTooManyRequests),
+		StatusCode: http.StatusTooManyRequests,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Retry-After":  []string{strconv.Itoa(seconds)},
+			"Content-Type": []string{"application/json"},
+		},
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	. "github.com/onsi/gomega"
+)
+
+func TestThrottlingPolicy(t *testing.T) {
+	testcases := []struct {
+		name            string
+		method          string
+		path            string
+		responseHeaders map[string]string
+		responseStatus  int
+		expectHeldBack  bool
+	}{
+		{
+			name:           "requests outside of a subscription are never held back",
+			method:         http.MethodGet,
+			path:           "/providers/Microsoft.Compute/operations",
+			responseStatus: http.StatusTooManyRequests,
+			expectHeldBack: false,
+		},
+		{
+			name:            "requests are sent while quota remains",
+			method:          http.MethodGet,
+			path:            "/subscriptions/sub-1/resourceGroups/rg",
+			responseHeaders: map[string]string{"x-ms-ratelimit-remaining-subscription-reads": "11999"},
+			responseStatus:  http.StatusOK,
+			expectHeldBack:  false,
+		},
+		{
+			name:            "requests are held back when the remaining quota is low",
+			method:          http.MethodGet,
+			path:            "/subscriptions/sub-2/resourceGroups/rg",
+			responseHeaders: map[string]string{"x-ms-ratelimit-remaining-subscription-reads": "3"},
+			responseStatus:  http.StatusOK,
+			expectHeldBack:  true,
+		},
+		{
+			name:            "requests are held back after a 429",
+			method:          http.MethodPut,
+			path:            "/subscriptions/sub-3/resourceGroups/rg",
+			responseHeaders: map[string]string{"Retry-After": "30"},
+			responseStatus:  http.StatusTooManyRequests,
+			expectHeldBack:  true,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				for k, v := range tc.responseHeaders {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tc.responseStatus)
+			}))
+			defer server.Close()
+
+			// Disable retries like ARMClientOptions does, so every request reaches the server at most once.
+			pipeline := runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+				PerCallPolicies: []policy.Policy{throttlingPolicy{throttler: newThrottler()}},
+				Retry:           policy.RetryOptions{MaxRetries: -1},
+			})
+			for i := 0; i < 2; i++ {
+				req, err := runtime.NewRequest(context.Background(), tc.method, server.URL+tc.path)
+				g.Expect(err).NotTo(HaveOccurred())
+				resp, err := pipeline.Do(req)
+				g.Expect(err).NotTo(HaveOccurred())
+				resp.Body.Close()
+
+				if i == 1 && tc.expectHeldBack {
+					g.Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+					g.Expect(resp.Header.Get("Retry-After")).NotTo(BeEmpty())
+				}
+			}
+
+			if tc.expectHeldBack {
+				g.Expect(atomic.LoadInt32(&calls)).To(BeEquivalentTo(1))
+			} else {
+				g.Expect(atomic.LoadInt32(&calls)).To(BeEquivalentTo(2))
+			}
+		})
+	}
+}
+
+func TestThrottlerBlockExpires(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	th := newThrottler()
+	th.now = func() time.Time { return now }
+	key := throttlingKey{subscriptionID: "sub", operation: rateLimitWrites}
+
+	th.block(key, time.Minute)
+	g.Expect(th.retryAfter(key)).To(Equal(time.Minute))
+	g.Expect(th.retryAfter(throttlingKey{subscriptionID: "sub", operation: rateLimitReads})).To(BeZero())
+
+	// A shorter block does not shorten an existing one.
+	th.block(key, time.Second)
+	g.Expect(th.retryAfter(key)).To(Equal(time.Minute))
+
+	now = now.Add(2 * time.Minute)
+	g.Expect(th.retryAfter(key)).To(BeZero())
+}
+
+func TestSubscriptionIDFromPath(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(subscriptionIDFromPath("/subscriptions/ABC-123/resourceGroups/rg")).To(Equal("abc-123"))
+	g.Expect(subscriptionIDFromPath("/providers/Microsoft.Compute/operations")).To(BeEmpty())
+	g.Expect(subscriptionIDFromPath("/subscriptions")).To(BeEmpty())
+}
+
+func TestRetryAfterFromHeader(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(retryAfterFromHeader(http.Header{"Retry-After": []string{"20"}})).To(Equal(20 * time.Second))
+	g.Expect(retryAfterFromHeader(http.Header{})).To(Equal(defaultThrottlingBackoff))
+	g.Expect(retryAfterFromHeader(http.Header{"Retry-After": []string{"garbage"}})).To(Equal(defaultThrottlingBackoff))
+}
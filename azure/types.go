@@ -83,6 +83,27 @@ type TagsSpec struct {
 	Annotation string
 }
 
+// BootstrapDataBlobSpec defines the specification for the blob holding the bootstrap data of a machine.
+type BootstrapDataBlobSpec struct {
+	StorageAccountName string
+	ResourceGroup      string
+	ContainerName      string
+	BlobName           string
+	// BootstrapData is the base64 encoded bootstrap data of the machine.
+	BootstrapData string
+	// Format is the format of the bootstrap data, e.g. cloud-config or ignition.
+	Format string
+}
+
+// BootstrapDataSecretSpec defines the specification for the Key Vault secret holding the bootstrap data of a machine.
+type BootstrapDataSecretSpec struct {
+	VaultName          string
+	VaultResourceGroup string
+	SecretName         string
+	// BootstrapData is the base64 encoded bootstrap data of the machine.
+	BootstrapData string
+}
+
 // ExtensionSpec defines the specification for a VM or VMSS extension.
 type ExtensionSpec struct {
 	Name              string
@@ -91,6 +112,39 @@ type ExtensionSpec struct {
 	Version           string
 	Settings          map[string]string
 	ProtectedSettings map[string]string
+	// ForceUpdateTag, when set, updates an existing VM extension whenever it changes, since its protected settings
+	// can't be compared with the ones Azure never returns.
+	ForceUpdateTag string
+}
+
+// PricedResourceSpec defines a set of identical resources whose cost is estimated.
+type PricedResourceSpec struct {
+	// Type is the resource type, e.g. virtualMachines.
+	Type string
+	// SKU is the VM size, the storage account type of a managed disk or the SKU of a load balancer.
+	SKU string
+	// DiskSizeGB is the size of a managed disk.
+	DiskSizeGB int32
+	// Windows is true for VMs running Windows.
+	Windows bool
+	// Spot is true for Spot VMs.
+	Spot bool
+	// Count is the number of resources.
+	Count int32
+}
+
+// MachineEncryptionSpec describes the disk encryption settings of the VMs of an AzureMachine or AzureMachinePool.
+type MachineEncryptionSpec struct {
+	// Name identifies the machine, e.g. AzureMachine/my-cluster-md-0-abcde.
+	Name string
+	// CustomerManagedKeys is true when the managed OS and data disks are all encrypted with disk encryption sets.
+	CustomerManagedKeys bool
+	// EncryptionAtHost is true for VMs with encryption at host.
+	EncryptionAtHost bool
+	// AzureDiskEncryption is true for VMs with the Azure Disk Encryption extension.
+	AzureDiskEncryption bool
+	// Count is the number of VMs.
+	Count int32
 }
 
 type (
@@ -121,12 +175,11 @@ type (
 	}
 )
 
-// HasModelChanges returns true if the spec fields which will mutate the Azure VMSS model are different.
+// HasModelChanges returns true if the spec fields which will mutate the Azure VMSS model are different. Tags and
+// identities are not part of the model, they are updated in place without replacing the instances.
 func (vmss VMSS) HasModelChanges(other VMSS) bool {
 	equal := cmp.Equal(vmss.Image, other.Image) &&
-		cmp.Equal(vmss.Identity, other.Identity) &&
 		cmp.Equal(vmss.Zones, other.Zones) &&
-		cmp.Equal(vmss.Tags, other.Tags) &&
 		cmp.Equal(vmss.Sku, other.Sku)
 	return !equal
 }
@@ -80,7 +80,7 @@ func TestVMSS_HasModelChanges(t *testing.T) {
 				r := getDefaultVMSSForModelTesting()
 				return r, l
 			},
-			HasModelChanges: true,
+			HasModelChanges: false,
 		},
 		{
 			Name: "with different Zones",
@@ -134,7 +134,7 @@ func TestVMSS_HasModelChanges(t *testing.T) {
 				r := getDefaultVMSSForModelTesting()
 				return r, l
 			},
-			HasModelChanges: true,
+			HasModelChanges: false,
 		},
 	}
 
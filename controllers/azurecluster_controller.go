@@ -18,17 +18,23 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/adaptive"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/statussink"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -39,7 +45,9 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -47,9 +55,11 @@ import (
 // AzureClusterReconciler reconciles an AzureCluster object.
 type AzureClusterReconciler struct {
 	client.Client
-	Recorder                  record.EventRecorder
-	ReconcileTimeout          time.Duration
-	WatchFilterValue          string
+	Recorder         record.EventRecorder
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+	// StatusSink receives the provisioning phase transitions of AzureClusters. It is optional.
+	StatusSink                statussink.Sink
 	createAzureClusterService azureClusterServiceCreator
 }
 
@@ -78,15 +88,18 @@ func (acr *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctr
 	defer done()
 
 	var r reconcile.Reconciler = acr
+	if options.Intervals != nil {
+		r = adaptive.NewReconciler(r, options.Intervals, mgr.GetClient(), &infrav1.AzureCluster{}, log)
+	}
 	if options.Cache != nil {
-		r = coalescing.NewReconciler(acr, options.Cache, log)
+		r = coalescing.NewReconciler(r, options.Cache, log)
 	}
 
 	c, err := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options.Options).
 		For(&infrav1.AzureCluster{}).
 		WithEventFilter(predicates.ResourceHasFilterLabel(log, acr.WatchFilterValue)).
-		WithEventFilter(predicates.ResourceIsNotExternallyManaged(log)).
+		WithEventFilter(ResourceIsNotExternallyManagedOrReportsStatus(log)).
 		Build(r)
 	if err != nil {
 		return errors.Wrap(err, "error creating controller")
@@ -102,19 +115,54 @@ func (acr *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctr
 		return errors.Wrap(err, "failed adding a watch for ready clusters")
 	}
 
+	// Add a watch on the control plane Machines, so that a control plane collapsing into a single failure domain, e.g.
+	// after remediation, is reported without waiting for the next resync.
+	if err = c.Watch(
+		source.Kind(mgr.GetCache(), &clusterv1.Machine{}),
+		handler.EnqueueRequestsFromMapFunc(acr.controlPlaneMachineToAzureCluster),
+		predicate.Funcs{
+			UpdateFunc:  func(event.UpdateEvent) bool { return false },
+			GenericFunc: func(event.GenericEvent) bool { return false },
+		},
+		predicates.ResourceHasFilterLabel(log, acr.WatchFilterValue),
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for control plane machines")
+	}
+
 	return nil
 }
 
+// controlPlaneMachineToAzureCluster maps a control plane Machine to the AzureCluster of its Cluster.
+func (acr *AzureClusterReconciler) controlPlaneMachineToAzureCluster(ctx context.Context, o client.Object) []reconcile.Request {
+	machine, ok := o.(*clusterv1.Machine)
+	if !ok || !util.IsControlPlaneMachine(machine) {
+		return nil
+	}
+	cluster, err := util.GetClusterFromMetadata(ctx, acr.Client, machine.ObjectMeta)
+	if err != nil {
+		return nil
+	}
+	ref := cluster.Spec.InfrastructureRef
+	if ref == nil || ref.GroupVersionKind().GroupKind() != infrav1.GroupVersion.WithKind("AzureCluster").GroupKind() {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: cluster.Namespace, Name: ref.Name}}}
+}
+
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinetemplates;azuremachinetemplates/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusteridentities;azureclusteridentities/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=list;
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=resources.azure.com,resources=resourcegroups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=resources.azure.com,resources=resourcegroups/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=network.azure.com,resources=natgateways,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=network.azure.com,resources=natgateways/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch
 
 // Reconcile idempotently gets, creates, and updates a cluster.
 func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -162,16 +210,36 @@ func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		AzureCluster: azureCluster,
 	})
 	if err != nil {
+		// The AzureClusterIdentity or its secret can briefly be missing while clusterctl move pivots
+		// objects to a new management cluster, so wait for them instead of failing the reconcile.
+		if apierrors.IsNotFound(err) {
+			if annotations.IsPaused(cluster, azureCluster) {
+				log.Info("AzureCluster or linked Cluster is marked as paused and credentials are not available yet. Won't reconcile")
+				return reconcile.Result{}, nil
+			}
+			acr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "ClusterCredentialsNotFound", err.Error())
+			log.Info("credentials are not available yet, requeuing", "reason", err.Error())
+			return reconcile.Result{RequeueAfter: reconciler.DefaultReconcilerRequeue}, nil
+		}
 		err = errors.Wrap(err, "failed to create scope")
 		acr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "CreateClusterScopeFailed", err.Error())
 		return reconcile.Result{}, err
 	}
 
+	// Attach the cluster and resource group to every log line written while reconciling, including Azure API requests.
+	ctx = tele.WithLogValues(ctx, "cluster", cluster.Name, "resourceGroup", clusterScope.ResourceGroup())
+
 	// Always close the scope when exiting this function so we can persist any AzureMachine changes.
+	previousPhase, _ := statussink.AzureClusterPhase(azureCluster)
 	defer func() {
-		if err := clusterScope.Close(ctx); err != nil && reterr == nil {
-			reterr = err
+		if err := clusterScope.Close(ctx); err != nil {
+			if reterr == nil {
+				reterr = err
+			}
+			return
 		}
+		phase, message := statussink.AzureClusterPhase(azureCluster)
+		reportPhaseTransition(ctx, acr.StatusSink, "AzureCluster", azureCluster, cluster.Name, previousPhase, phase, message)
 	}()
 
 	// Return early if the object or Cluster is paused.
@@ -181,6 +249,17 @@ func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return acr.reconcilePause(ctx, clusterScope)
 	}
 
+	if annotations.IsExternallyManaged(azureCluster) {
+		if !reportsExternallyManagedStatus(azureCluster) {
+			log.Info("AzureCluster is externally managed, skipping reconciliation")
+			return reconcile.Result{}, nil
+		}
+		if !azureCluster.DeletionTimestamp.IsZero() {
+			return reconcile.Result{}, nil
+		}
+		return acr.reconcileExternallyManaged(ctx, clusterScope)
+	}
+
 	if azureCluster.Spec.IdentityRef != nil {
 		err := EnsureClusterIdentity(ctx, acr.Client, azureCluster, azureCluster.Spec.IdentityRef, infrav1.ClusterFinalizer)
 		if err != nil {
@@ -215,19 +294,69 @@ func (acr *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 		}
 	}
 
+	if err := reconcileSSHKeyPairSecret(ctx, acr.Client, clusterScope); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile SSH key pair secret")
+	}
+
+	ready, err := reconcileSubnetCIDRBlockClaims(ctx, acr.Client, clusterScope)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile subnet IP address claims")
+	}
+	if !ready {
+		log.Info("Waiting for IP address pools to fulfill the subnet IP address claims")
+		conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.WaitingForIPAddressReason, clusterv1.ConditionSeverityInfo, "")
+		return reconcile.Result{RequeueAfter: reconciler.DefaultReconcilerRequeue}, nil
+	}
+
 	acs, err := acr.createAzureClusterService(clusterScope)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create a new AzureClusterReconciler")
 	}
 
-	if err := acs.Reconcile(ctx); err != nil {
+	if azureCluster.Annotations[infrav1.PlanAnnotation] == "true" {
+		plan := acs.Plan(ctx)
+		// Keep the previous plan when nothing changed so recording it does not trigger another reconciliation.
+		if previous := azureCluster.Status.Plan; previous == nil || previous.Message != plan.Message || !reflect.DeepEqual(previous.Operations, plan.Operations) {
+			azureCluster.Status.Plan = plan
+		}
+		log.Info("AzureCluster reconciliation in plan mode, no resource changed", "operations", plan.Operations, "message", plan.Message)
+		acr.Recorder.Eventf(azureCluster, corev1.EventTypeNormal, "ReconcilePlan", "Reconciling the AzureCluster would perform %d Azure operations", len(plan.Operations))
+		return reconcile.Result{}, nil
+	}
+	azureCluster.Status.Plan = nil
+
+	// A spec which only changed tags is reconciled by the tags service alone, which is much faster than reconciling
+	// every service when the tags of a fleet of clusters are updated at once. The other services only update the tags
+	// of the resources they create, except for the resources managed through ASO, which get the new tags at the next
+	// reconciliation.
+	tagsOnly, err := isTagsOnlyChange(azureCluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if tagsOnly {
+		if err := acs.ReconcileTags(ctx); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to reconcile tags-only change")
+		}
+		azureCluster.Status.ObservedGeneration = azureCluster.Generation
+		log.Info("Reconciled tags-only change of AzureCluster")
+		return reconcile.Result{}, nil
+	}
+
+	// Existing resources which differ from an unchanged spec were changed out-of-band.
+	reconcileCtx := ctx
+	var drift *azure.Drift
+	if azureCluster.Generation == azureCluster.Status.ObservedGeneration {
+		reconcileCtx, drift = withDriftCheck(ctx, azureCluster.Spec.DriftRemediation)
+	}
+
+	if err := acs.Reconcile(reconcileCtx); err != nil {
 		// Handle terminal & transient errors
 		var reconcileError azure.ReconcileError
 		if errors.As(err, &reconcileError) {
 			if reconcileError.IsTerminal() {
 				acr.Recorder.Eventf(clusterScope.AzureCluster, corev1.EventTypeWarning, "ReconcileError", errors.Wrapf(err, "failed to reconcile AzureCluster").Error())
 				log.Error(err, "failed to reconcile AzureCluster", "name", clusterScope.ClusterName())
-				conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "")
+				conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.FailedReason, clusterv1.ConditionSeverityError, err.Error())
 				return reconcile.Result{}, nil
 			}
 			if reconcileError.IsTransient() {
@@ -246,6 +375,12 @@ func (acr *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 		return reconcile.Result{}, wrappedErr
 	}
 
+	if azureCluster.Annotations[infrav1.RotateControlPlaneEndpointAnnotation] == "true" {
+		if err := acr.rotateControlPlaneEndpoint(ctx, clusterScope); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to rotate control plane endpoint")
+		}
+	}
+
 	// Set APIEndpoints so the Cluster API Cluster Controller can pull them
 	if azureCluster.Spec.ControlPlaneEndpoint.Host == "" {
 		azureCluster.Spec.ControlPlaneEndpoint.Host = clusterScope.APIServerHost()
@@ -254,7 +389,105 @@ func (acr *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 		azureCluster.Spec.ControlPlaneEndpoint.Port = clusterScope.APIServerPort()
 	}
 
+	clusterScope.SetNetworkStatus()
+
 	// No errors, so mark us ready so the Cluster API Cluster Controller can pull it
+	azureCluster.Status.Ready = true
+	conditions.MarkTrue(azureCluster, infrav1.NetworkInfrastructureReadyCondition)
+	azureCluster.Status.ObservedGeneration = azureCluster.Generation
+	if azureCluster.Status.ObservedSpecHash, err = specHashWithoutTags(azureCluster); err != nil {
+		return reconcile.Result{}, err
+	}
+	if drift != nil {
+		reportDrift(acr.Recorder, azureCluster, drift)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// rotateControlPlaneEndpoint points the control plane endpoint of the AzureCluster and its Cluster to the API server
+// load balancer once it has been re-issued, and removes the rotation annotation, also when the endpoint did not change.
+// The endpoint of the AzureCluster is patched before the annotation is removed, as the webhook only allows changing it
+// while the annotation is set.
+func (acr *AzureClusterReconciler) rotateControlPlaneEndpoint(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterReconciler.rotateControlPlaneEndpoint")
+	defer done()
+
+	azureCluster := clusterScope.AzureCluster
+	cluster := clusterScope.Cluster
+	endpoint := clusterv1.APIEndpoint{
+		Host: clusterScope.APIServerHost(),
+		Port: clusterScope.APIServerPort(),
+	}
+	// The endpoint is set for the first time by the regular reconciliation.
+	if azureCluster.Spec.ControlPlaneEndpoint.IsZero() || endpoint.Host == "" {
+		return nil
+	}
+	clusterEndpointRotated := cluster.Spec.ControlPlaneEndpoint.IsZero() || cluster.Spec.ControlPlaneEndpoint == endpoint
+	if azureCluster.Spec.ControlPlaneEndpoint == endpoint && clusterEndpointRotated {
+		// The API server load balancer kept its host, e.g. because its DNS name did not change, so there is nothing
+		// to rotate. The annotation is removed anyway so that it does not keep unlocking changes to the load balancer.
+		delete(azureCluster.Annotations, infrav1.RotateControlPlaneEndpointAnnotation)
+		log.Info("control plane endpoint unchanged, nothing to rotate", "endpoint", endpoint.String())
+		acr.Recorder.Eventf(azureCluster, corev1.EventTypeNormal, "ControlPlaneEndpointUnchanged",
+			"The control plane endpoint %s did not change, removing the %s annotation", endpoint.String(), infrav1.RotateControlPlaneEndpointAnnotation)
+		return nil
+	}
+
+	previous := azureCluster.Spec.ControlPlaneEndpoint
+	if previous != endpoint {
+		rotated := azureCluster.DeepCopy()
+		rotated.Spec.ControlPlaneEndpoint = endpoint
+		if err := acr.Client.Patch(ctx, rotated, client.MergeFrom(azureCluster)); err != nil {
+			return errors.Wrap(err, "failed to update the control plane endpoint of the AzureCluster")
+		}
+		azureCluster.Spec.ControlPlaneEndpoint = endpoint
+	}
+	if !clusterEndpointRotated {
+		previous = cluster.Spec.ControlPlaneEndpoint
+		clusterPatch := client.MergeFrom(cluster.DeepCopy())
+		cluster.Spec.ControlPlaneEndpoint = endpoint
+		if err := acr.Client.Patch(ctx, cluster, clusterPatch); err != nil {
+			return errors.Wrap(err, "failed to update the control plane endpoint of the Cluster")
+		}
+	}
+
+	delete(azureCluster.Annotations, infrav1.RotateControlPlaneEndpointAnnotation)
+	log.Info("rotated control plane endpoint", "previous", previous.String(), "endpoint", endpoint.String())
+	acr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "ControlPlaneEndpointRotated",
+		"The control plane endpoint changed from %s to %s. Add %s to the certificate SANs of the API server, e.g. in the apiServer certSANs of the KubeadmControlPlane cluster configuration, "+
+			"roll out the control plane machines, then delete the %s-kubeconfig secret so that kubeconfigs using the new endpoint are issued",
+		previous.String(), endpoint.String(), endpoint.Host, cluster.Name)
+	return nil
+}
+
+// reconcileExternallyManaged reports the status of an AzureCluster whose infrastructure is managed out-of-band.
+// It only reads from Azure: no finalizer is added and no Azure resource is created, updated or deleted.
+func (acr *AzureClusterReconciler) reconcileExternallyManaged(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterReconciler.reconcileExternallyManaged")
+	defer done()
+
+	log.Info("Reporting status of externally managed AzureCluster")
+	azureCluster := clusterScope.AzureCluster
+
+	acs, err := acr.createAzureClusterService(clusterScope)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to create a new azureClusterService")
+	}
+
+	if err := acs.setFailureDomainsForLocation(ctx); err != nil {
+		wrappedErr := errors.Wrap(err, "failed to get availability zones")
+		conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.FailedReason, clusterv1.ConditionSeverityError, wrappedErr.Error())
+		return reconcile.Result{}, wrappedErr
+	}
+
+	if azureCluster.Spec.ControlPlaneEndpoint.Host == "" {
+		azureCluster.Spec.ControlPlaneEndpoint.Host = clusterScope.APIServerHost()
+	}
+	if azureCluster.Spec.ControlPlaneEndpoint.Port == 0 {
+		azureCluster.Spec.ControlPlaneEndpoint.Port = clusterScope.APIServerPort()
+	}
+
 	azureCluster.Status.Ready = true
 	conditions.MarkTrue(azureCluster, infrav1.NetworkInfrastructureReadyCondition)
 
@@ -292,6 +525,16 @@ func (acr *AzureClusterReconciler) reconcileDelete(ctx context.Context, clusterS
 		return reconcile.Result{}, errors.Wrap(err, "failed to create a new AzureClusterReconciler")
 	}
 
+	if azureCluster.Annotations[infrav1.DeletionDryRunAnnotation] == "true" {
+		resources, err := acs.DeletionDryRun(ctx)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to list the resources to delete")
+		}
+		log.Info("AzureCluster deletion dry run, no resource deleted", "resources", resources)
+		acr.Recorder.Eventf(azureCluster, corev1.EventTypeNormal, "DeletionDryRun", "Deleting the AzureCluster would delete %d Azure resources: %s", len(resources), summarizeResources(resources))
+		return reconcile.Result{}, nil
+	}
+
 	if err := acs.Delete(ctx); err != nil {
 		// Handle transient errors
 		var reconcileError azure.ReconcileError
@@ -307,9 +550,21 @@ func (acr *AzureClusterReconciler) reconcileDelete(ctx context.Context, clusterS
 		}
 
 		wrappedErr := errors.Wrapf(err, "error deleting AzureCluster %s/%s", azureCluster.Namespace, azureCluster.Name)
-		acr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "ClusterReconcilerDeleteFailed", wrappedErr.Error())
-		conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, clusterv1.DeletionFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
-		return reconcile.Result{}, wrappedErr
+		if !shouldForceDelete(azureCluster, err, time.Now()) {
+			acr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "ClusterReconcilerDeleteFailed", wrappedErr.Error())
+			conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, clusterv1.DeletionFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return reconcile.Result{}, wrappedErr
+		}
+
+		// The deletion is forced: the resources left behind are recorded and the finalizer removed anyway. Failing to
+		// list them must not block the deletion, the error is recorded instead.
+		resources, listErr := acs.DeletionDryRun(ctx)
+		if listErr != nil {
+			wrappedErr = kerrors.NewAggregate([]error{wrappedErr, errors.Wrap(listErr, "failed to list the abandoned resources")})
+		}
+		if err := abandonResources(ctx, acr.Client, acr.Recorder, azureCluster, "AzureCluster", clusterScope.ClusterName(), resources, wrappedErr); err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
 	// Cluster is deleted so remove the finalizer.
@@ -325,3 +580,32 @@ func (acr *AzureClusterReconciler) reconcileDelete(ctx context.Context, clusterS
 
 	return reconcile.Result{}, nil
 }
+
+// isTagsOnlyChange returns true if the spec of a ready AzureCluster only changed tags since it was last reconciled
+// successfully.
+func isTagsOnlyChange(azureCluster *infrav1.AzureCluster) (bool, error) {
+	status := azureCluster.Status
+	if !status.Ready || status.ObservedSpecHash == "" || azureCluster.Generation == status.ObservedGeneration {
+		return false, nil
+	}
+	hash, err := specHashWithoutTags(azureCluster)
+	if err != nil {
+		return false, err
+	}
+	return hash == status.ObservedSpecHash, nil
+}
+
+// specHashWithoutTags returns the hash of the spec of an AzureCluster, leaving out the additional tags of the cluster
+// and of its subnets.
+func specHashWithoutTags(azureCluster *infrav1.AzureCluster) (string, error) {
+	spec := azureCluster.Spec.DeepCopy()
+	spec.AdditionalTags = nil
+	for i := range spec.NetworkSpec.Subnets {
+		spec.NetworkSpec.Subnets[i].AdditionalTags = nil
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal AzureCluster spec")
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
@@ -18,16 +18,30 @@ package controllers
 
 import (
 	"context"
+	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/orphanedresources"
 	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -133,3 +147,433 @@ func TestAzureClusterReconcilePaused(t *testing.T) {
 
 	g.Eventually(recorder.Events).Should(Receive(Equal("Normal ClusterPaused AzureCluster or linked Cluster is marked as paused. Won't reconcile normally")))
 }
+
+func TestAzureClusterReconcileMissingIdentity(t *testing.T) {
+	tests := []struct {
+		name          string
+		paused        bool
+		expectRequeue bool
+	}{
+		{
+			name:          "requeues while the identity is missing",
+			expectRequeue: true,
+		},
+		{
+			name:   "does nothing while paused and the identity is missing",
+			paused: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			s := runtime.NewScheme()
+			g.Expect(clusterv1.AddToScheme(s)).To(Succeed())
+			g.Expect(infrav1.AddToScheme(s)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(s).Build()
+			recorder := record.NewFakeRecorder(1)
+			r := NewAzureClusterReconciler(c, recorder, reconciler.DefaultLoopTimeout, "")
+
+			name := test.RandomName("moved", 10)
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec:       clusterv1.ClusterSpec{Paused: tc.paused},
+			}
+			g.Expect(c.Create(ctx, cluster)).To(Succeed())
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:       "Cluster",
+							APIVersion: clusterv1.GroupVersion.String(),
+							Name:       cluster.Name,
+						},
+					},
+				},
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "something",
+						IdentityRef: &corev1.ObjectReference{
+							Kind: "AzureClusterIdentity",
+							Name: "not-moved-yet",
+						},
+					},
+				},
+			}
+			g.Expect(c.Create(ctx, azureCluster)).To(Succeed())
+
+			result, err := r.Reconcile(ctx, ctrl.Request{
+				NamespacedName: client.ObjectKeyFromObject(azureCluster),
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expectRequeue {
+				g.Expect(result.RequeueAfter).To(Equal(reconciler.DefaultReconcilerRequeue))
+				g.Expect(recorder.Events).To(Receive(HavePrefix("Warning ClusterCredentialsNotFound")))
+			} else {
+				g.Expect(result.RequeueAfter).To(BeZero())
+				g.Expect(recorder.Events).NotTo(Receive())
+			}
+		})
+	}
+}
+
+func TestAzureClusterRotateControlPlaneEndpoint(t *testing.T) {
+	tests := []struct {
+		name             string
+		endpoint         clusterv1.APIEndpoint
+		expectedEndpoint clusterv1.APIEndpoint
+		expectedEvent    string
+	}{
+		{
+			name:             "endpoint pointing to the previous public IP is rotated",
+			endpoint:         clusterv1.APIEndpoint{Host: "old.eastus.cloudapp.azure.com", Port: 6443},
+			expectedEndpoint: clusterv1.APIEndpoint{Host: "new.eastus.cloudapp.azure.com", Port: 6443},
+			expectedEvent:    "ControlPlaneEndpointRotated",
+		},
+		{
+			name:             "endpoint pointing to the current public IP is kept",
+			endpoint:         clusterv1.APIEndpoint{Host: "new.eastus.cloudapp.azure.com", Port: 6443},
+			expectedEndpoint: clusterv1.APIEndpoint{Host: "new.eastus.cloudapp.azure.com", Port: 6443},
+			expectedEvent:    "ControlPlaneEndpointUnchanged",
+		},
+		{
+			name:             "endpoint not set yet is left to the regular reconciliation",
+			endpoint:         clusterv1.APIEndpoint{},
+			expectedEndpoint: clusterv1.APIEndpoint{},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			s := runtime.NewScheme()
+			g.Expect(clusterv1.AddToScheme(s)).To(Succeed())
+			g.Expect(infrav1.AddToScheme(s)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster",
+					Namespace: "default",
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneEndpoint: tc.endpoint,
+				},
+			}
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-azure-cluster",
+					Namespace:   "default",
+					Annotations: map[string]string{infrav1.RotateControlPlaneEndpointAnnotation: "true"},
+				},
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerLB: infrav1.LoadBalancerSpec{
+							Name: "my-cluster-public-lb",
+							FrontendIPs: []infrav1.FrontendIP{
+								{
+									Name: "my-cluster-public-lb-frontEnd",
+									PublicIP: &infrav1.PublicIPSpec{
+										Name:    "pip-my-cluster-apiserver-new",
+										DNSName: "new.eastus.cloudapp.azure.com",
+									},
+								},
+							},
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								Type: infrav1.Public,
+							},
+						},
+					},
+					ControlPlaneEndpoint: tc.endpoint,
+				},
+			}
+			c := fake.NewClientBuilder().WithScheme(s).WithObjects(cluster, azureCluster).Build()
+			recorder := record.NewFakeRecorder(1)
+
+			clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+				AzureClients: scope.AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Client:       c,
+				Cluster:      cluster,
+				AzureCluster: azureCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			reconciler := NewAzureClusterReconciler(c, recorder, reconciler.DefaultLoopTimeout, "")
+			g.Expect(reconciler.rotateControlPlaneEndpoint(ctx, clusterScope)).To(Succeed())
+			g.Expect(clusterScope.Close(ctx)).To(Succeed())
+
+			updatedAzureCluster := &infrav1.AzureCluster{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(azureCluster), updatedAzureCluster)).To(Succeed())
+			g.Expect(updatedAzureCluster.Spec.ControlPlaneEndpoint).To(Equal(tc.expectedEndpoint))
+			updatedCluster := &clusterv1.Cluster{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cluster), updatedCluster)).To(Succeed())
+			g.Expect(updatedCluster.Spec.ControlPlaneEndpoint).To(Equal(tc.expectedEndpoint))
+
+			if tc.expectedEvent != "" {
+				g.Expect(updatedAzureCluster.Annotations).NotTo(HaveKey(infrav1.RotateControlPlaneEndpointAnnotation))
+				g.Expect(recorder.Events).To(Receive(ContainSubstring(tc.expectedEvent)))
+			} else {
+				g.Expect(updatedAzureCluster.Annotations).To(HaveKey(infrav1.RotateControlPlaneEndpointAnnotation))
+				g.Expect(recorder.Events).NotTo(Receive())
+			}
+		})
+	}
+}
+
+func TestIsTagsOnlyChange(t *testing.T) {
+	reconciled := func() *infrav1.AzureCluster {
+		azureCluster := &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec: infrav1.AzureClusterSpec{
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					Location:       "westus2",
+					AdditionalTags: infrav1.Tags{"team": "a"},
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Subnets: infrav1.Subnets{
+						{SubnetClassSpec: infrav1.SubnetClassSpec{Name: "node-subnet", Role: infrav1.SubnetNode}},
+					},
+				},
+			},
+			Status: infrav1.AzureClusterStatus{Ready: true, ObservedGeneration: 2},
+		}
+		hash, err := specHashWithoutTags(azureCluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		azureCluster.Status.ObservedSpecHash = hash
+		return azureCluster
+	}
+
+	tests := []struct {
+		name   string
+		change func(*infrav1.AzureCluster)
+		want   bool
+	}{
+		{
+			name:   "unchanged spec",
+			change: func(*infrav1.AzureCluster) {},
+			want:   false,
+		},
+		{
+			name: "changed cluster tags",
+			change: func(c *infrav1.AzureCluster) {
+				c.Generation++
+				c.Spec.AdditionalTags = infrav1.Tags{"team": "b", "env": "prod"}
+			},
+			want: true,
+		},
+		{
+			name: "changed subnet tags",
+			change: func(c *infrav1.AzureCluster) {
+				c.Generation++
+				c.Spec.NetworkSpec.Subnets[0].AdditionalTags = infrav1.Tags{"zone": "dmz"}
+			},
+			want: true,
+		},
+		{
+			name: "changed tags and subnets",
+			change: func(c *infrav1.AzureCluster) {
+				c.Generation++
+				c.Spec.AdditionalTags = infrav1.Tags{"team": "b"}
+				c.Spec.NetworkSpec.Subnets = append(c.Spec.NetworkSpec.Subnets, infrav1.SubnetSpec{SubnetClassSpec: infrav1.SubnetClassSpec{Name: "extra-subnet", Role: infrav1.SubnetNode}})
+			},
+			want: false,
+		},
+		{
+			name: "changed tags of a cluster not ready",
+			change: func(c *infrav1.AzureCluster) {
+				c.Generation++
+				c.Spec.AdditionalTags = infrav1.Tags{"team": "b"}
+				c.Status.Ready = false
+			},
+			want: false,
+		},
+		{
+			name: "changed tags of a cluster reconciled before the spec hash was recorded",
+			change: func(c *infrav1.AzureCluster) {
+				c.Generation++
+				c.Spec.AdditionalTags = infrav1.Tags{"team": "b"}
+				c.Status.ObservedSpecHash = ""
+			},
+			want: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			azureCluster := reconciled()
+			tc.change(azureCluster)
+			tagsOnly, err := isTagsOnlyChange(azureCluster)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(tagsOnly).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestAzureClusterReconcileForceDelete(t *testing.T) {
+	vnetID := "/subscriptions/123/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet"
+
+	policyErr := &azcore.ResponseError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "RequestDisallowedByPolicy",
+		RawResponse: &http.Response{
+			StatusCode: http.StatusForbidden,
+			Request:    &http.Request{Method: http.MethodDelete, URL: &url.URL{}},
+			Body:       http.NoBody,
+		},
+	}
+	longAgo := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	cases := map[string]struct {
+		annotations       map[string]string
+		deleteErr         error
+		expectedErr       string
+		expectedFinalizer bool
+	}{
+		"deletion failure blocks the deletion": {
+			deleteErr:         policyErr,
+			expectedErr:       "error deleting AzureCluster default/my-azure-cluster",
+			expectedFinalizer: true,
+		},
+		"forced deletion abandons the remaining resources when denied by a policy": {
+			annotations: map[string]string{infrav1.ForceDeleteAnnotation: "true"},
+			deleteErr:   policyErr,
+		},
+		"forced deletion waits for other failures to persist": {
+			annotations:       map[string]string{infrav1.ForceDeleteAnnotation: "true"},
+			deleteErr:         errors.New("RequestDisallowedByPolicy"),
+			expectedErr:       "error deleting AzureCluster default/my-azure-cluster",
+			expectedFinalizer: true,
+		},
+		"forced deletion abandons the remaining resources once other failures persisted": {
+			annotations: map[string]string{infrav1.ForceDeleteAnnotation: "true", deleteFailingSinceAnnotation: longAgo},
+			deleteErr:   errors.New("RequestDisallowedByPolicy"),
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			sb := runtime.NewSchemeBuilder(
+				clusterv1.AddToScheme,
+				infrav1.AddToScheme,
+				corev1.AddToScheme,
+			)
+			s := runtime.NewScheme()
+			g.Expect(sb.AddToScheme(s)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"}}
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-azure-cluster",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+					Finalizers:  []string{infrav1.ClusterFinalizer},
+				},
+			}
+			c := fake.NewClientBuilder().WithScheme(s).WithObjects(cluster, azureCluster).Build()
+			clusterScope := &scope.ClusterScope{Client: c, Cluster: cluster, AzureCluster: azureCluster}
+
+			orphansMock := mock_azure.NewMockServiceReconciler(mockCtrl)
+			orphansMock.EXPECT().Name().AnyTimes().Return(orphanedresources.ServiceName)
+			orphansMock.EXPECT().Delete(gomockinternal.AContext()).Return(tc.deleteErr)
+			lister := &fakeDeletionCandidatesLister{MockServiceReconciler: orphansMock, candidates: []string{vnetID}}
+
+			recorder := record.NewFakeRecorder(10)
+			acr := &AzureClusterReconciler{
+				Client:   c,
+				Recorder: recorder,
+				createAzureClusterService: func(*scope.ClusterScope) (*azureClusterService, error) {
+					return &azureClusterService{scope: clusterScope, services: []azure.ServiceReconciler{lister}}, nil
+				},
+			}
+
+			_, err := acr.reconcileDelete(ctx, clusterScope)
+			configMap := &corev1.ConfigMap{}
+			configMapErr := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "azurecluster-my-azure-cluster-abandoned-resources"}, configMap)
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+				g.Expect(apierrors.IsNotFound(configMapErr)).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(configMapErr).NotTo(HaveOccurred())
+				g.Expect(configMap.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "my-cluster"))
+				g.Expect(configMap.Data).To(HaveKeyWithValue(abandonedResourcesKey, `["`+vnetID+`"]`))
+				g.Expect(configMap.Data[abandonedErrorKey]).To(ContainSubstring("RequestDisallowedByPolicy"))
+				g.Expect(recorder.Events).To(Receive(HavePrefix("Warning ForceDeleted")))
+			}
+			g.Expect(azureCluster.Annotations).To(HaveKey(deleteFailingSinceAnnotation))
+			if tc.expectedFinalizer {
+				g.Expect(azureCluster.Finalizers).To(ContainElement(infrav1.ClusterFinalizer))
+			} else {
+				g.Expect(azureCluster.Finalizers).NotTo(ContainElement(infrav1.ClusterFinalizer))
+			}
+		})
+	}
+}
+
+func TestControlPlaneMachineToAzureCluster(t *testing.T) {
+	g := NewWithT(t)
+	sb := runtime.NewSchemeBuilder(
+		clusterv1.AddToScheme,
+		infrav1.AddToScheme,
+	)
+	s := runtime.NewScheme()
+	g.Expect(sb.AddToScheme(s)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: infrav1.GroupVersion.String(),
+				Kind:       "AzureCluster",
+				Name:       "my-azure-cluster",
+			},
+		},
+	}
+	managedCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-managed-cluster", Namespace: "default"},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: infrav1.GroupVersion.String(),
+				Kind:       "AzureManagedCluster",
+				Name:       "my-managed-cluster",
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(cluster, managedCluster).Build()
+	acr := &AzureClusterReconciler{Client: c}
+
+	machine := func(clusterName string, controlPlane bool) *clusterv1.Machine {
+		labels := map[string]string{clusterv1.ClusterNameLabel: clusterName}
+		if controlPlane {
+			labels[clusterv1.MachineControlPlaneLabel] = ""
+		}
+		return &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: clusterName + "-abcde", Namespace: "default", Labels: labels}}
+	}
+
+	g.Expect(acr.controlPlaneMachineToAzureCluster(context.Background(), machine("my-cluster", true))).To(Equal([]ctrl.Request{
+		{NamespacedName: client.ObjectKey{Namespace: "default", Name: "my-azure-cluster"}},
+	}))
+	g.Expect(acr.controlPlaneMachineToAzureCluster(context.Background(), machine("my-cluster", false))).To(BeEmpty())
+	g.Expect(acr.controlPlaneMachineToAzureCluster(context.Background(), machine("my-managed-cluster", true))).To(BeEmpty())
+	g.Expect(acr.controlPlaneMachineToAzureCluster(context.Background(), machine("missing-cluster", true))).To(BeEmpty())
+}
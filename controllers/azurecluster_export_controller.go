@@ -0,0 +1,232 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inventory"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// exportErrorsKey is the key of the resources left out of the templates in export ConfigMaps.
+	exportErrorsKey = "errors.json"
+	// maxExportBytes bounds the total size of the templates of export ConfigMaps, the templates that don't fit are
+	// left out to stay below the 1MiB limit of Kubernetes objects.
+	maxExportBytes = 768 * 1024
+	// exportedAtAnnotation records when the templates of an export ConfigMap were exported.
+	exportedAtAnnotation = "infrastructure.cluster.x-k8s.io/exported-at"
+)
+
+// invalidConfigMapKeyChars matches the characters of resource group names that are not allowed in ConfigMap keys.
+var invalidConfigMapKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// AzureClusterExportReconciler exports the ARM templates of the Azure resources owned by AzureClusters into a
+// ConfigMap next to each AzureCluster when requested with the ExportResourcesAnnotation. The templates describe the
+// actual configuration of the resources, including the changes made outside of CAPZ, and can be used for audits or
+// to recreate the resources of a cluster out of band.
+type AzureClusterExportReconciler struct {
+	client.Client
+	Recorder         record.EventRecorder
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+
+	newClient inventoryClientFactory
+}
+
+// SetupWithManager initializes this controller with a manager.
+func (r *AzureClusterExportReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	_, log, done := tele.StartSpanWithLogger(ctx,
+		"controllers.AzureClusterExportReconciler.SetupWithManager",
+		tele.KVP("controller", "AzureClusterExport"),
+	)
+	defer done()
+
+	if r.newClient == nil {
+		r.newClient = newInventoryClient
+	}
+
+	// Exports are requested with an annotation, other changes of the AzureCluster don't need to trigger one.
+	err := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AzureCluster{}, builder.WithPredicates(predicate.AnnotationChangedPredicate{})).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue)).
+		Named("AzureClusterExport").
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "error creating controller")
+	}
+	return nil
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+
+// Reconcile exports the ARM templates of the Azure resources owned by an AzureCluster into its export ConfigMap
+// when the value of its ExportResourcesAnnotation has not been exported yet.
+func (r *AzureClusterExportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
+	defer cancel()
+
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterExportReconciler.Reconcile",
+		tele.KVP("namespace", req.Namespace),
+		tele.KVP("name", req.Name),
+		tele.KVP("kind", "AzureCluster"),
+	)
+	defer done()
+
+	azureCluster := &infrav1.AzureCluster{}
+	if err := r.Get(ctx, req.NamespacedName, azureCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	request := azureCluster.Annotations[infrav1.ExportResourcesAnnotation]
+	// The export ConfigMap is garbage collected with the AzureCluster.
+	if request == "" || !azureCluster.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: azureCluster.Namespace, Name: ExportConfigMapName(azureCluster.Name)}, configMap)
+	if err == nil && configMap.Annotations[infrav1.ExportResourcesAnnotation] == request {
+		return reconcile.Result{}, nil
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, azureCluster.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cluster == nil {
+		log.V(4).Info("Cluster Controller has not yet set OwnerRef")
+		return reconcile.Result{}, nil
+	}
+
+	if annotations.IsPaused(cluster, azureCluster) {
+		return reconcile.Result{RequeueAfter: reconciler.DefaultReconcilerRequeue}, nil
+	}
+
+	inventoryClient, err := r.newClient(ctx, r.Client, cluster, azureCluster)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to create inventory client")
+	}
+	resources, err := inventory.ListOwnedResources(ctx, inventoryClient, cluster.Name)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to list owned Azure resources")
+	}
+	templates, err := inventory.ExportTemplates(ctx, inventoryClient, resources)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to export the templates of owned Azure resources")
+	}
+
+	if err := r.reconcileExportConfigMap(ctx, cluster, azureCluster, request, templates); err != nil {
+		return reconcile.Result{}, err
+	}
+	r.Recorder.Eventf(azureCluster, corev1.EventTypeNormal, "ResourcesExported",
+		"Exported the templates of the Azure resources in %d resource groups to ConfigMap %s", len(templates), ExportConfigMapName(azureCluster.Name))
+	log.V(2).Info("exported resource templates", "resourceGroups", len(templates), "request", request)
+
+	return reconcile.Result{}, nil
+}
+
+// reconcileExportConfigMap creates or updates the export ConfigMap of an AzureCluster with templates, one key per
+// resource group, recording the export request it fulfills.
+func (r *AzureClusterExportReconciler) reconcileExportConfigMap(ctx context.Context, cluster *clusterv1.Cluster, azureCluster *infrav1.AzureCluster, request string, templates []inventory.ResourceGroupTemplate) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterExportReconciler.reconcileExportConfigMap")
+	defer done()
+
+	data := map[string]string{}
+	exportErrors := map[string]string{}
+	size := 0
+	for _, template := range templates {
+		if template.Error != "" {
+			exportErrors[template.ResourceGroup] = template.Error
+		}
+		if size+len(template.Template) > maxExportBytes {
+			log.V(2).Info("template is too large for the export ConfigMap", "resourceGroup", template.ResourceGroup)
+			exportErrors[template.ResourceGroup] = fmt.Sprintf("the template of %d bytes is too large for the ConfigMap", len(template.Template))
+			continue
+		}
+		size += len(template.Template)
+		data[exportConfigMapKey(template.ResourceGroup)] = string(template.Template)
+	}
+	if len(exportErrors) > 0 {
+		errorsJSON, err := json.Marshal(exportErrors)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal export errors")
+		}
+		data[exportErrorsKey] = string(errorsJSON)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ExportConfigMapName(azureCluster.Name),
+			Namespace: azureCluster.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[clusterv1.ClusterNameLabel] = cluster.Name
+		if configMap.Annotations == nil {
+			configMap.Annotations = map[string]string{}
+		}
+		configMap.Annotations[infrav1.ExportResourcesAnnotation] = request
+		configMap.Annotations[exportedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		configMap.Data = data
+		return controllerutil.SetControllerReference(azureCluster, configMap, r.Scheme())
+	})
+	return errors.Wrap(err, "failed to create or update export ConfigMap")
+}
+
+// ExportConfigMapName returns the name of the export ConfigMap of an AzureCluster.
+func ExportConfigMapName(azureClusterName string) string {
+	return azureClusterName + "-azure-export"
+}
+
+// exportConfigMapKey returns the key of the template of a resource group in export ConfigMaps.
+func exportConfigMapKey(resourceGroup string) string {
+	return invalidConfigMapKeyChars.ReplaceAllString(resourceGroup, "_") + ".template.json"
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inventory"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inventory/mock_inventory"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAzureClusterExportReconcile(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-azure-cluster",
+			Namespace: "default",
+			UID:       "azure-cluster-uid",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "my-cluster"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, azureCluster).Build()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_inventory.NewMockClient(mockCtrl)
+	resources := []inventory.Resource{
+		{ID: "/subscriptions/123/resourceGroups/my-rg", Type: "Microsoft.Resources/resourceGroups", ResourceGroup: "my-rg"},
+		{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/vm-0", Type: "Microsoft.Compute/virtualMachines", ResourceGroup: "my-rg"},
+		{ID: "/subscriptions/123/resourceGroups/other(rg)/providers/Microsoft.Compute/disks/leaked-disk", Type: "Microsoft.Compute/disks", ResourceGroup: "other(rg)"},
+	}
+	clientMock.EXPECT().ListTaggedResources(gomockinternal.AContext(), infrav1.ClusterTagKey("my-cluster"), "owned").Return(resources, nil)
+	clientMock.EXPECT().ExportTemplate(gomockinternal.AContext(), "my-rg", []string{resources[1].ID}).
+		Return(inventory.ExportedTemplate{Template: []byte(`{"resources":[{"type":"Microsoft.Compute/virtualMachines"}]}`)}, nil)
+	clientMock.EXPECT().ExportTemplate(gomockinternal.AContext(), "other(rg)", []string{resources[2].ID}).
+		Return(inventory.ExportedTemplate{Template: []byte(`{"resources":[]}`), Error: "Could not get resources of the type 'Microsoft.Compute/disks'."}, nil)
+
+	r := &AzureClusterExportReconciler{
+		Client:   c,
+		Recorder: record.NewFakeRecorder(10),
+		newClient: func(context.Context, client.Client, *clusterv1.Cluster, *infrav1.AzureCluster) (inventory.Client, error) {
+			return clientMock, nil
+		},
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(azureCluster)}
+	configMapKey := client.ObjectKey{Namespace: "default", Name: "my-azure-cluster-azure-export"}
+
+	// Nothing is exported until requested.
+	_, err := r.Reconcile(context.Background(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Get(context.Background(), configMapKey, &corev1.ConfigMap{})).NotTo(Succeed())
+
+	g.Expect(c.Get(context.Background(), req.NamespacedName, azureCluster)).To(Succeed())
+	azureCluster.Annotations = map[string]string{infrav1.ExportResourcesAnnotation: "2024-05-01"}
+	g.Expect(c.Update(context.Background(), azureCluster)).To(Succeed())
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	configMap := &corev1.ConfigMap{}
+	g.Expect(c.Get(context.Background(), configMapKey, configMap)).To(Succeed())
+	g.Expect(configMap.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "my-cluster"))
+	g.Expect(configMap.Annotations).To(HaveKeyWithValue(infrav1.ExportResourcesAnnotation, "2024-05-01"))
+	g.Expect(configMap.OwnerReferences).To(ConsistOf(HaveField("UID", azureCluster.UID)))
+	g.Expect(configMap.Data).To(Equal(map[string]string{
+		"my-rg.template.json":     `{"resources":[{"type":"Microsoft.Compute/virtualMachines"}]}`,
+		"other_rg_.template.json": `{"resources":[]}`,
+		exportErrorsKey:           `{"other(rg)":"Could not get resources of the type 'Microsoft.Compute/disks'."}`,
+	}))
+
+	// The same request is only exported once.
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/secret"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// apiServerProbeTimeout is how long a probe waits for the TLS handshake with the API server to complete.
+const apiServerProbeTimeout = 10 * time.Second
+
+var (
+	apiServerReachable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "capz_apiserver_reachable",
+		Help: "Whether the control plane endpoint of an AzureCluster accepted a TLS connection at the last probe (1) or not (0), by namespace and name.",
+	}, []string{"namespace", "name"})
+
+	apiServerProbeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "capz_apiserver_probe_duration_seconds",
+		Help:    "Latency of control plane endpoint probes, by result.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 11),
+	}, []string{"result"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiServerReachable, apiServerProbeDuration)
+}
+
+// apiServerProber checks that the API server at address completes a TLS handshake. When rootCAs is nil, the
+// certificate of the API server is not verified.
+type apiServerProber func(ctx context.Context, address string, rootCAs *x509.CertPool) error
+
+// AzureClusterHealthReconciler periodically probes the control plane endpoint of ready AzureClusters and reports
+// the result in the APIServerReachable condition, so that network changes breaking access to the API server,
+// e.g. NSG rules or routes, are noticed after the cluster is created.
+type AzureClusterHealthReconciler struct {
+	client.Client
+	Recorder         record.EventRecorder
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+	// Interval is the time between two probes of the same cluster.
+	Interval time.Duration
+
+	probe apiServerProber
+}
+
+// SetupWithManager initializes this controller with a manager.
+func (r *AzureClusterHealthReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	_, log, done := tele.StartSpanWithLogger(ctx,
+		"controllers.AzureClusterHealthReconciler.SetupWithManager",
+		tele.KVP("controller", "AzureClusterHealth"),
+	)
+	defer done()
+
+	if r.probe == nil {
+		r.probe = tlsProbe
+	}
+
+	// Probes are driven by requeues, status updates of the AzureCluster don't need to trigger one.
+	err := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AzureCluster{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue)).
+		Named("AzureClusterHealth").
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "error creating controller")
+	}
+	return nil
+}
+
+// Reconcile probes the control plane endpoint of an AzureCluster and updates its APIServerReachable condition.
+func (r *AzureClusterHealthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
+	defer cancel()
+
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterHealthReconciler.Reconcile",
+		tele.KVP("namespace", req.Namespace),
+		tele.KVP("name", req.Name),
+		tele.KVP("kind", "AzureCluster"),
+	)
+	defer done()
+
+	azureCluster := &infrav1.AzureCluster{}
+	if err := r.Get(ctx, req.NamespacedName, azureCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			apiServerReachable.DeleteLabelValues(req.Namespace, req.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if !azureCluster.DeletionTimestamp.IsZero() {
+		apiServerReachable.DeleteLabelValues(req.Namespace, req.Name)
+		return reconcile.Result{}, nil
+	}
+
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, azureCluster.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cluster == nil {
+		log.V(4).Info("Cluster Controller has not yet set OwnerRef")
+		return reconcile.Result{}, nil
+	}
+
+	endpoint := azureCluster.Spec.ControlPlaneEndpoint
+	if annotations.IsPaused(cluster, azureCluster) || !azureCluster.Status.Ready || endpoint.Host == "" {
+		return reconcile.Result{RequeueAfter: r.Interval}, nil
+	}
+
+	rootCAs, err := r.clusterCA(ctx, cluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	address := net.JoinHostPort(endpoint.Host, strconv.Itoa(int(endpoint.Port)))
+	probeCtx, probeCancel := context.WithTimeout(ctx, apiServerProbeTimeout)
+	start := time.Now()
+	probeErr := r.probe(probeCtx, address, rootCAs)
+	probeCancel()
+
+	result := "success"
+	if probeErr != nil {
+		result = "failure"
+	}
+	apiServerProbeDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+
+	patchHelper, err := patch.NewHelper(azureCluster, r.Client)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to init patch helper")
+	}
+
+	wasReachable := !conditions.IsFalse(azureCluster, infrav1.APIServerReachableCondition)
+	if probeErr != nil {
+		log.V(2).Info("control plane endpoint is not reachable", "address", address, "error", probeErr.Error())
+		apiServerReachable.WithLabelValues(req.Namespace, req.Name).Set(0)
+		conditions.MarkFalse(azureCluster, infrav1.APIServerReachableCondition, infrav1.APIServerUnreachableReason, clusterv1.ConditionSeverityWarning,
+			"failed to connect to %s: %s", address, probeErr.Error())
+		if wasReachable {
+			r.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, infrav1.APIServerUnreachableReason, "Control plane endpoint %s is not reachable: %s", address, probeErr.Error())
+		}
+	} else {
+		apiServerReachable.WithLabelValues(req.Namespace, req.Name).Set(1)
+		conditions.MarkTrue(azureCluster, infrav1.APIServerReachableCondition)
+		if !wasReachable {
+			r.Recorder.Eventf(azureCluster, corev1.EventTypeNormal, string(infrav1.APIServerReachableCondition), "Control plane endpoint %s is reachable again", address)
+		}
+	}
+
+	if err := patchHelper.Patch(ctx, azureCluster, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+		infrav1.APIServerReachableCondition,
+	}}); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to patch AzureCluster")
+	}
+
+	return reconcile.Result{RequeueAfter: r.Interval}, nil
+}
+
+// clusterCA returns the certificate authority of the workload cluster, or nil if the cluster has no CA secret.
+func (r *AzureClusterHealthReconciler) clusterCA(ctx context.Context, cluster *clusterv1.Cluster) (*x509.CertPool, error) {
+	caSecret, err := secret.GetFromNamespacedName(ctx, r.Client, client.ObjectKeyFromObject(cluster), secret.ClusterCA)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get cluster CA secret")
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caSecret.Data[secret.TLSCrtDataName]) {
+		return nil, errors.Errorf("failed to parse the certificate of cluster CA secret %s", caSecret.Name)
+	}
+	return rootCAs, nil
+}
+
+// tlsProbe dials address and completes a TLS handshake, verifying the server certificate against rootCAs if set.
+func tlsProbe(ctx context.Context, address string, rootCAs *x509.CertPool) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	dialer := &tls.Dialer{
+		Config: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ServerName: host,
+			RootCAs:    rootCAs,
+			// Without the cluster CA, only reachability can be checked.
+			InsecureSkipVerify: rootCAs == nil, //nolint:gosec // The certificate is verified whenever the cluster CA is known.
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAzureClusterHealthReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+	}
+	newAzureCluster := func(ready bool, reachable *bool) *infrav1.AzureCluster {
+		azureCluster := &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-azure-cluster",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "my-cluster"},
+				},
+			},
+			Spec: infrav1.AzureClusterSpec{
+				ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "apiserver.example.com", Port: 6443},
+			},
+			Status: infrav1.AzureClusterStatus{Ready: ready},
+		}
+		if reachable != nil && *reachable {
+			conditions.MarkTrue(azureCluster, infrav1.APIServerReachableCondition)
+		} else if reachable != nil {
+			conditions.MarkFalse(azureCluster, infrav1.APIServerReachableCondition, infrav1.APIServerUnreachableReason, clusterv1.ConditionSeverityWarning, "")
+		}
+		return azureCluster
+	}
+	reachable, unreachable := true, false
+
+	cases := map[string]struct {
+		azureCluster      *infrav1.AzureCluster
+		probeErr          error
+		expectProbe       bool
+		expectedCondition *clusterv1.Condition
+		expectedEvent     string
+	}{
+		"cluster not ready yet is not probed": {
+			azureCluster: newAzureCluster(false, nil),
+		},
+		"reachable API server": {
+			azureCluster:      newAzureCluster(true, nil),
+			expectProbe:       true,
+			expectedCondition: conditions.TrueCondition(infrav1.APIServerReachableCondition),
+		},
+		"unreachable API server": {
+			azureCluster: newAzureCluster(true, &reachable),
+			probeErr:     errors.New("i/o timeout"),
+			expectProbe:  true,
+			expectedCondition: conditions.FalseCondition(infrav1.APIServerReachableCondition, infrav1.APIServerUnreachableReason, clusterv1.ConditionSeverityWarning,
+				"failed to connect to apiserver.example.com:6443: i/o timeout"),
+			expectedEvent: "Warning APIServerUnreachable Control plane endpoint apiserver.example.com:6443 is not reachable: i/o timeout",
+		},
+		"API server reachable again": {
+			azureCluster:      newAzureCluster(true, &unreachable),
+			expectProbe:       true,
+			expectedCondition: conditions.TrueCondition(infrav1.APIServerReachableCondition),
+			expectedEvent:     "Normal APIServerReachable Control plane endpoint apiserver.example.com:6443 is reachable again",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(cluster.DeepCopy(), tc.azureCluster).
+				WithStatusSubresource(&infrav1.AzureCluster{}).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+
+			probed := false
+			r := &AzureClusterHealthReconciler{
+				Client:   c,
+				Recorder: recorder,
+				Interval: time.Minute,
+				probe: func(_ context.Context, address string, rootCAs *x509.CertPool) error {
+					probed = true
+					g.Expect(address).To(Equal("apiserver.example.com:6443"))
+					g.Expect(rootCAs).To(BeNil())
+					return tc.probeErr
+				},
+			}
+
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tc.azureCluster)})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.RequeueAfter).To(Equal(r.Interval))
+			g.Expect(probed).To(Equal(tc.expectProbe))
+
+			azureCluster := &infrav1.AzureCluster{}
+			g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(tc.azureCluster), azureCluster)).To(Succeed())
+			if tc.expectedCondition != nil {
+				condition := conditions.Get(azureCluster, infrav1.APIServerReachableCondition)
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Status).To(Equal(tc.expectedCondition.Status))
+				g.Expect(condition.Reason).To(Equal(tc.expectedCondition.Reason))
+				g.Expect(condition.Message).To(Equal(tc.expectedCondition.Message))
+			}
+
+			if tc.expectedEvent != "" {
+				g.Expect(recorder.Events).To(Receive(Equal(tc.expectedEvent)))
+			} else {
+				g.Expect(recorder.Events).NotTo(Receive())
+			}
+		})
+	}
+}
+
+func TestTLSProbe(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "https://")
+
+	trusted := x509.NewCertPool()
+	trusted.AddCert(server.Certificate())
+	g.Expect(tlsProbe(context.Background(), address, trusted)).To(Succeed())
+
+	// Without the cluster CA, the certificate is not verified.
+	g.Expect(tlsProbe(context.Background(), address, nil)).To(Succeed())
+
+	// A certificate not signed by the cluster CA is reported.
+	g.Expect(tlsProbe(context.Background(), address, x509.NewCertPool())).NotTo(Succeed())
+
+	server.Close()
+	g.Expect(tlsProbe(context.Background(), address, trusted)).NotTo(Succeed())
+}
+
+func TestAzureClusterHealthClusterCA(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"}}
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-ca", Namespace: "default"},
+		Data: map[string][]byte{
+			"tls.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+		},
+	}
+
+	r := &AzureClusterHealthReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+	rootCAs, err := r.clusterCA(context.Background(), cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rootCAs).To(BeNil())
+
+	r = &AzureClusterHealthReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(caSecret).Build()}
+	rootCAs, err = r.clusterCA(context.Background(), cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tlsProbe(context.Background(), strings.TrimPrefix(server.URL, "https://"), rootCAs)).To(Succeed())
+}
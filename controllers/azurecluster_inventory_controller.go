@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inventory"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// inventorySummaryKey is the key of the resource counts in inventory ConfigMaps.
+	inventorySummaryKey = "summary.json"
+	// inventoryResourcesKey is the key of the resource list in inventory ConfigMaps.
+	inventoryResourcesKey = "resources.json"
+	// maxInventoryResourcesBytes bounds the size of the resource list, which is left out of inventory ConfigMaps
+	// when larger, to stay below the 1MiB limit of Kubernetes objects.
+	maxInventoryResourcesBytes = 768 * 1024
+)
+
+var ownedAzureResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "capz_owned_azure_resources",
+	Help: "Number of Azure resources tagged as owned by an AzureCluster at the last inventory, by namespace and name of the AzureCluster, resource group and resource type.",
+}, []string{"namespace", "name", "resource_group", "resource_type"})
+
+func init() {
+	metrics.Registry.MustRegister(ownedAzureResources)
+}
+
+// inventoryClientFactory returns the client listing the resources of the subscription of an AzureCluster.
+type inventoryClientFactory func(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, azureCluster *infrav1.AzureCluster) (inventory.Client, error)
+
+// AzureClusterInventoryReconciler periodically lists the Azure resources tagged as owned by AzureClusters across
+// their subscription, and exports them as metrics and in a ConfigMap next to each AzureCluster. Unlike the resources
+// in the spec of the AzureCluster, the inventory includes the resources leaked by machines or services of the
+// workload cluster, and can be used for chargeback reporting.
+type AzureClusterInventoryReconciler struct {
+	client.Client
+	Recorder         record.EventRecorder
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+	// Interval is the time between two inventories of the same cluster.
+	Interval time.Duration
+
+	newClient inventoryClientFactory
+}
+
+// SetupWithManager initializes this controller with a manager.
+func (r *AzureClusterInventoryReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	_, log, done := tele.StartSpanWithLogger(ctx,
+		"controllers.AzureClusterInventoryReconciler.SetupWithManager",
+		tele.KVP("controller", "AzureClusterInventory"),
+	)
+	defer done()
+
+	if r.newClient == nil {
+		r.newClient = newInventoryClient
+	}
+
+	// Inventories are driven by requeues, status updates of the AzureCluster don't need to trigger one.
+	err := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AzureCluster{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue)).
+		Named("AzureClusterInventory").
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "error creating controller")
+	}
+	return nil
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+
+// Reconcile lists the Azure resources owned by an AzureCluster and updates its inventory metrics and ConfigMap.
+func (r *AzureClusterInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
+	defer cancel()
+
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterInventoryReconciler.Reconcile",
+		tele.KVP("namespace", req.Namespace),
+		tele.KVP("name", req.Name),
+		tele.KVP("kind", "AzureCluster"),
+	)
+	defer done()
+
+	azureCluster := &infrav1.AzureCluster{}
+	if err := r.Get(ctx, req.NamespacedName, azureCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			deleteInventoryMetrics(req.Namespace, req.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// The inventory ConfigMap is garbage collected with the AzureCluster.
+	if !azureCluster.DeletionTimestamp.IsZero() {
+		deleteInventoryMetrics(req.Namespace, req.Name)
+		return reconcile.Result{}, nil
+	}
+
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, azureCluster.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cluster == nil {
+		log.V(4).Info("Cluster Controller has not yet set OwnerRef")
+		return reconcile.Result{}, nil
+	}
+
+	if annotations.IsPaused(cluster, azureCluster) {
+		return reconcile.Result{RequeueAfter: r.Interval}, nil
+	}
+
+	inventoryClient, err := r.newClient(ctx, r.Client, cluster, azureCluster)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to create inventory client")
+	}
+	resources, err := inventory.ListOwnedResources(ctx, inventoryClient, cluster.Name)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to list owned Azure resources")
+	}
+	summary := inventory.Summarize(resources)
+
+	deleteInventoryMetrics(req.Namespace, req.Name)
+	for _, count := range summary {
+		ownedAzureResources.WithLabelValues(req.Namespace, req.Name, count.ResourceGroup, count.Type).Set(float64(count.Count))
+	}
+
+	if err := r.reconcileInventoryConfigMap(ctx, cluster, azureCluster, resources, summary); err != nil {
+		return reconcile.Result{}, err
+	}
+	log.V(4).Info("updated inventory", "resources", len(resources))
+
+	return reconcile.Result{RequeueAfter: r.Interval}, nil
+}
+
+// reconcileInventoryConfigMap creates or updates the inventory ConfigMap of an AzureCluster.
+func (r *AzureClusterInventoryReconciler) reconcileInventoryConfigMap(ctx context.Context, cluster *clusterv1.Cluster, azureCluster *infrav1.AzureCluster, resources []inventory.Resource, summary []inventory.Count) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterInventoryReconciler.reconcileInventoryConfigMap")
+	defer done()
+
+	summaryJSON, err := json.Marshal(summary)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal inventory summary")
+	}
+	data := map[string]string{inventorySummaryKey: string(summaryJSON)}
+	resourcesJSON, err := json.Marshal(resources)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal inventory resources")
+	}
+	if len(resourcesJSON) <= maxInventoryResourcesBytes {
+		data[inventoryResourcesKey] = string(resourcesJSON)
+	} else {
+		log.V(2).Info("resource list is too large for the inventory ConfigMap, only the summary is stored", "resources", len(resources))
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      InventoryConfigMapName(azureCluster.Name),
+			Namespace: azureCluster.Namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, r.Client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[clusterv1.ClusterNameLabel] = cluster.Name
+		configMap.Data = data
+		return controllerutil.SetControllerReference(azureCluster, configMap, r.Scheme())
+	})
+	return errors.Wrap(err, "failed to create or update inventory ConfigMap")
+}
+
+// InventoryConfigMapName returns the name of the inventory ConfigMap of an AzureCluster.
+func InventoryConfigMapName(azureClusterName string) string {
+	return azureClusterName + "-azure-inventory"
+}
+
+// deleteInventoryMetrics removes the inventory metrics of an AzureCluster.
+func deleteInventoryMetrics(namespace, name string) {
+	ownedAzureResources.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+}
+
+// newInventoryClient returns a client listing resources with the credentials of the AzureCluster.
+func newInventoryClient(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, azureCluster *infrav1.AzureCluster) (inventory.Client, error) {
+	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+		Client:       c,
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cluster scope")
+	}
+	return inventory.NewClient(clusterScope)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inventory"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inventory/mock_inventory"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAzureClusterInventoryReconcile(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-azure-cluster",
+			Namespace: "default",
+			UID:       "azure-cluster-uid",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "my-cluster"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, azureCluster).Build()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_inventory.NewMockClient(mockCtrl)
+	resources := []inventory.Resource{
+		{ID: "/subscriptions/123/resourceGroups/my-rg", Type: "Microsoft.Resources/resourceGroups", ResourceGroup: "my-rg", Location: "westus2"},
+		{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/vm-0", Type: "Microsoft.Compute/virtualMachines", ResourceGroup: "my-rg", Location: "westus2"},
+		{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/vm-1", Type: "Microsoft.Compute/virtualMachines", ResourceGroup: "my-rg", Location: "westus2"},
+		{ID: "/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Compute/disks/leaked-disk", Type: "Microsoft.Compute/disks", ResourceGroup: "other-rg", Location: "westus2", SKU: "Premium_LRS"},
+	}
+	clientMock.EXPECT().ListTaggedResources(gomockinternal.AContext(), infrav1.ClusterTagKey("my-cluster"), "owned").Return(resources, nil)
+	clientMock.EXPECT().ListTaggedResources(gomockinternal.AContext(), infrav1.ClusterTagKey("my-cluster"), "owned").Return(resources[:3], nil)
+
+	r := &AzureClusterInventoryReconciler{
+		Client:   c,
+		Recorder: record.NewFakeRecorder(10),
+		Interval: time.Hour,
+		newClient: func(context.Context, client.Client, *clusterv1.Cluster, *infrav1.AzureCluster) (inventory.Client, error) {
+			return clientMock, nil
+		},
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(azureCluster)}
+
+	result, err := r.Reconcile(context.Background(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(time.Hour))
+
+	configMap := &corev1.ConfigMap{}
+	g.Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "my-azure-cluster-azure-inventory"}, configMap)).To(Succeed())
+	g.Expect(configMap.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "my-cluster"))
+	g.Expect(configMap.OwnerReferences).To(ConsistOf(HaveField("UID", azureCluster.UID)))
+	var summary []inventory.Count
+	g.Expect(json.Unmarshal([]byte(configMap.Data[inventorySummaryKey]), &summary)).To(Succeed())
+	g.Expect(summary).To(Equal([]inventory.Count{
+		{ResourceGroup: "my-rg", Type: "microsoft.compute/virtualmachines", Count: 2},
+		{ResourceGroup: "my-rg", Type: "microsoft.resources/resourcegroups", Count: 1},
+		{ResourceGroup: "other-rg", Type: "microsoft.compute/disks", Count: 1},
+	}))
+	var exported []inventory.Resource
+	g.Expect(json.Unmarshal([]byte(configMap.Data[inventoryResourcesKey]), &exported)).To(Succeed())
+	g.Expect(exported).To(Equal(resources))
+
+	g.Expect(testutil.ToFloat64(ownedAzureResources.WithLabelValues("default", "my-azure-cluster", "my-rg", "microsoft.compute/virtualmachines"))).To(Equal(2.0))
+	g.Expect(testutil.ToFloat64(ownedAzureResources.WithLabelValues("default", "my-azure-cluster", "other-rg", "microsoft.compute/disks"))).To(Equal(1.0))
+
+	// Series of resources that are gone are removed at the next inventory.
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "my-azure-cluster-azure-inventory"}, configMap)).To(Succeed())
+	g.Expect(configMap.Data[inventorySummaryKey]).NotTo(ContainSubstring("other-rg"))
+	g.Expect(testutil.CollectAndCount(ownedAzureResources)).To(Equal(2))
+
+	// Series are removed with the AzureCluster.
+	g.Expect(c.Delete(context.Background(), azureCluster)).To(Succeed())
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(testutil.CollectAndCount(ownedAzureResources)).To(BeZero())
+}
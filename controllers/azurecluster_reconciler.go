@@ -20,21 +20,38 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/applicationgateways"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/controlplanezones"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/costestimates"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/diagnosticsettings"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsrecords"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/encryptionposture"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedidentities"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managementlocks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkwatchers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/orphanedresources"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/policychecks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatedns"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/securitygroups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/storageaccounts"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworkgateways"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -58,6 +75,10 @@ func newAzureClusterService(scope *scope.ClusterScope) (*azureClusterService, er
 	if err != nil {
 		return nil, err
 	}
+	networkWatchersSvc, err := networkwatchers.New(scope)
+	if err != nil {
+		return nil, err
+	}
 	routeTablesSvc, err := routetables.New(scope)
 	if err != nil {
 		return nil, err
@@ -66,6 +87,14 @@ func newAzureClusterService(scope *scope.ClusterScope) (*azureClusterService, er
 	if err != nil {
 		return nil, err
 	}
+	virtualNetworkGatewaysSvc, err := virtualnetworkgateways.New(scope)
+	if err != nil {
+		return nil, err
+	}
+	applicationGatewaysSvc, err := applicationgateways.New(scope)
+	if err != nil {
+		return nil, err
+	}
 	privateEndpointsSvc, err := privateendpoints.New(scope)
 	if err != nil {
 		return nil, err
@@ -94,21 +123,71 @@ func newAzureClusterService(scope *scope.ClusterScope) (*azureClusterService, er
 	if err != nil {
 		return nil, err
 	}
+	dnsRecordsSvc, err := dnsrecords.New(scope)
+	if err != nil {
+		return nil, err
+	}
+	diagnosticSettingsSvc, err := diagnosticsettings.New(scope)
+	if err != nil {
+		return nil, err
+	}
+	storageAccountsSvc, err := storageaccounts.New(scope)
+	if err != nil {
+		return nil, err
+	}
+	tagsSvc, err := tags.New(scope)
+	if err != nil {
+		return nil, err
+	}
+	orphanedResourcesSvc, err := orphanedresources.New(scope)
+	if err != nil {
+		return nil, err
+	}
+	managementLocksSvc, err := managementlocks.New(scope)
+	if err != nil {
+		return nil, err
+	}
+	managedIdentitiesSvc, err := managedidentities.New(scope)
+	if err != nil {
+		return nil, err
+	}
+	policyChecksSvc, err := policychecks.New(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &azureClusterService{
 		scope: scope,
 		services: []azure.ServiceReconciler{
+			// Reconciled first so that no resource is created before the resources of the cluster are known to comply with Azure Policy.
+			policyChecksSvc,
 			groups.New(scope),
+			// Reconciled right after the resource group and deleted last, once every other service has deleted its resources.
+			orphanedResourcesSvc,
 			virtualNetworksSvc,
 			securityGroupsSvc,
+			networkWatchersSvc,
 			routeTablesSvc,
 			publicIPsSvc,
 			natgateways.New(scope),
 			subnetsSvc,
 			vnetPeeringsSvc,
 			loadbalancersSvc,
+			dnsRecordsSvc,
 			privateDNSSvc,
 			bastionHostsSvc,
+			virtualNetworkGatewaysSvc,
+			applicationGatewaysSvc,
+			// Reconciled once the load balancers, security groups and public IPs exist.
+			diagnosticSettingsSvc,
 			privateEndpointsSvc,
+			storageAccountsSvc,
+			managedIdentitiesSvc,
+			tagsSvc,
+			costestimates.New(scope),
+			encryptionposture.New(scope),
+			controlplanezones.New(scope),
+			// Reconciled last and deleted first, as the lock prevents deleting any resource in the resource group.
+			managementLocksSvc,
 		},
 		skuCache: skuCache,
 	}, nil
@@ -116,7 +195,7 @@ func newAzureClusterService(scope *scope.ClusterScope) (*azureClusterService, er
 
 // Reconcile reconciles all the services in a predetermined order.
 func (s *azureClusterService) Reconcile(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.Reconcile")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.Reconcile")
 	defer done()
 
 	if err := s.setFailureDomainsForLocation(ctx); err != nil {
@@ -126,9 +205,15 @@ func (s *azureClusterService) Reconcile(ctx context.Context) error {
 	s.scope.AzureCluster.SetBackendPoolNameDefault()
 	s.scope.SetDNSName()
 	s.scope.SetControlPlaneSecurityRules()
+	s.scope.SetNodeSecurityRules()
+	if err := s.scope.ValidateControlPlaneExtraRules(); err != nil {
+		return errors.Wrap(err, "failed to add the extra control plane rules")
+	}
 
+	timings := &reconciler.ServiceTimings{}
+	defer timings.Log(log, "reconciled AzureCluster services")
 	for _, service := range s.services {
-		if err := service.Reconcile(ctx); err != nil {
+		if err := timings.Time(service.Name(), func() error { return service.Reconcile(ctx) }); err != nil {
 			return errors.Wrapf(err, "failed to reconcile AzureCluster service %s", service.Name())
 		}
 	}
@@ -136,6 +221,40 @@ func (s *azureClusterService) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// ReconcileTags only reconciles the tags of the resources of the cluster, leaving the resources themselves untouched.
+func (s *azureClusterService) ReconcileTags(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.ReconcileTags")
+	defer done()
+
+	tagsSvc, err := s.getService(tags.ServiceName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get tags service")
+	}
+	if err := tagsSvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile AzureCluster service %s", tagsSvc.Name())
+	}
+	return nil
+}
+
+// Plan computes the Azure operations Reconcile would perform, without performing them. The returned plan is
+// incomplete when a service failed to compute its operations, e.g. because it depends on a resource which does not
+// exist yet.
+func (s *azureClusterService) Plan(ctx context.Context) *infrav1.ReconcilePlan {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.Plan")
+	defer done()
+
+	plan := &azure.Plan{}
+	var message string
+	if err := s.Reconcile(azure.WithPlan(ctx, plan)); err != nil {
+		message = err.Error()
+	}
+	return &infrav1.ReconcilePlan{
+		Operations:  plan.Operations(),
+		Message:     message,
+		GeneratedAt: metav1.Now(),
+	}
+}
+
 // Pause pauses all components making up the cluster.
 func (s *azureClusterService) Pause(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.Pause")
@@ -156,7 +275,7 @@ func (s *azureClusterService) Pause(ctx context.Context) error {
 
 // Delete reconciles all the services in a predetermined order.
 func (s *azureClusterService) Delete(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.Delete")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.Delete")
 	defer done()
 
 	if !ShouldDeleteIndividualResources(ctx, s.scope) {
@@ -169,6 +288,49 @@ func (s *azureClusterService) Delete(ctx context.Context) error {
 		if err := vnetPeeringsSvc.Delete(ctx); err != nil {
 			return errors.Wrap(err, "failed to delete peerings")
 		}
+		// DNS record sets live in the DNS zone's resource group, so they are not deleted with the cluster resource group either.
+		dnsRecordsSvc, err := s.getService(dnsrecords.ServiceName)
+		if err != nil {
+			return errors.Wrap(err, "failed to get DNS records service")
+		}
+		if err := dnsRecordsSvc.Delete(ctx); err != nil {
+			return errors.Wrap(err, "failed to delete DNS records")
+		}
+		// Diagnostic settings outlive their resource and would apply to a new resource with the same name.
+		diagnosticSettingsSvc, err := s.getService(diagnosticsettings.ServiceName)
+		if err != nil {
+			return errors.Wrap(err, "failed to get diagnostic settings service")
+		}
+		if err := diagnosticSettingsSvc.Delete(ctx); err != nil {
+			return errors.Wrap(err, "failed to delete diagnostic settings")
+		}
+		// NSG flow logs live in the resource group of the network watcher.
+		networkWatchersSvc, err := s.getService(networkwatchers.ServiceName)
+		if err != nil {
+			return errors.Wrap(err, "failed to get network watchers service")
+		}
+		if err := networkWatchersSvc.Delete(ctx); err != nil {
+			return errors.Wrap(err, "failed to delete flow logs")
+		}
+
+		// The role assignments of the managed identity may be scoped outside of the resource group, and are not deleted
+		// with the identity either.
+		managedIdentitiesSvc, err := s.getService(managedidentities.ServiceName)
+		if err != nil {
+			return errors.Wrap(err, "failed to get managed identities service")
+		}
+		if err := managedIdentitiesSvc.Delete(ctx); err != nil {
+			return errors.Wrap(err, "failed to delete managed identity")
+		}
+
+		// The resource group can't be deleted while it is locked.
+		managementLocksSvc, err := s.getService(managementlocks.ServiceName)
+		if err != nil {
+			return errors.Wrap(err, "failed to get management locks service")
+		}
+		if err := managementLocksSvc.Delete(ctx); err != nil {
+			return errors.Wrap(err, "failed to delete resource group lock")
+		}
 
 		groupSvc, err := s.getService(groups.ServiceName)
 		if err != nil {
@@ -179,10 +341,12 @@ func (s *azureClusterService) Delete(ctx context.Context) error {
 			return errors.Wrap(err, "failed to delete resource group")
 		}
 	} else {
+		timings := &reconciler.ServiceTimings{}
+		defer timings.Log(log, "deleted AzureCluster services")
 		// If the resource group is not managed we need to delete resources inside the group one by one.
 		// services are deleted in reverse order from the order in which they are reconciled.
 		for i := len(s.services) - 1; i >= 0; i-- {
-			if err := s.services[i].Delete(ctx); err != nil {
+			if err := timings.Time(s.services[i].Name(), func() error { return s.services[i].Delete(ctx) }); err != nil {
 				return errors.Wrapf(err, "failed to delete AzureCluster service %s", s.services[i].Name())
 			}
 		}
@@ -191,6 +355,51 @@ func (s *azureClusterService) Delete(ctx context.Context) error {
 	return nil
 }
 
+// deletionCandidatesLister lists the resources deleting the cluster would delete.
+type deletionCandidatesLister interface {
+	DeletionCandidates(ctx context.Context, wholeGroup bool) ([]string, error)
+}
+
+// DeletionDryRun returns the IDs of the Azure resources Delete would delete, without deleting anything.
+func (s *azureClusterService) DeletionDryRun(ctx context.Context) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.DeletionDryRun")
+	defer done()
+
+	svc, err := s.getService(orphanedresources.ServiceName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get orphaned resources service")
+	}
+	lister, ok := svc.(deletionCandidatesLister)
+	if !ok {
+		return nil, errors.Errorf("service %s cannot list the resources to delete", svc.Name())
+	}
+
+	wholeGroup := !ShouldDeleteIndividualResources(ctx, s.scope)
+	resources, err := lister.DeletionCandidates(ctx, wholeGroup)
+	if err != nil {
+		return nil, err
+	}
+	if wholeGroup {
+		resources = append([]string{azure.ResourceGroupID(s.scope.SubscriptionID(), s.scope.ResourceGroup())}, resources...)
+	}
+	return resources, nil
+}
+
+// intersectZones returns the zones of a which are also in b, preserving the order of a.
+func intersectZones(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, zone := range b {
+		inB[zone] = true
+	}
+	zones := make([]string, 0, len(a))
+	for _, zone := range a {
+		if inB[zone] {
+			zones = append(zones, zone)
+		}
+	}
+	return zones
+}
+
 func (s *azureClusterService) getService(name string) (azure.ServiceReconciler, error) {
 	for _, service := range s.services {
 		if service.Name() == name {
@@ -212,6 +421,17 @@ func (s *azureClusterService) setFailureDomainsForLocation(ctx context.Context)
 		return errors.Wrapf(err, "failed to get zones for location %s", s.scope.Location())
 	}
 
+	// Only keep the zones in which all the requested VM sizes can be deployed.
+	for _, size := range s.scope.FailureDomainVMSizes() {
+		sizeZones, err := s.skuCache.GetZonesWithVMSize(ctx, size, s.scope.Location())
+		if err != nil {
+			return errors.Wrapf(err, "failed to get zones for VM size %s in location %s", size, s.scope.Location())
+		}
+		zones = intersectZones(zones, sizeZones)
+	}
+
+	// Rebuild the failure domains so that zones which are no longer eligible are removed from the status.
+	s.scope.ClearFailureDomains()
 	for _, zone := range zones {
 		s.scope.SetFailureDomain(zone, clusterv1.FailureDomainSpec{
 			ControlPlane: true,
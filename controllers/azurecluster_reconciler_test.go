@@ -29,12 +29,20 @@ import (
 	"go.uber.org/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/diagnosticsettings"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsrecords"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedidentities"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managementlocks"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkwatchers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/orphanedresources"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -80,6 +88,9 @@ func TestAzureClusterServiceReconcile(t *testing.T) {
 			svcThreeMock := mock_azure.NewMockServiceReconciler(mockCtrl)
 
 			tc.expect(svcOneMock.EXPECT(), svcTwoMock.EXPECT(), svcThreeMock.EXPECT())
+			svcOneMock.EXPECT().Name().Return("one").AnyTimes()
+			svcTwoMock.EXPECT().Name().Return("two").AnyTimes()
+			svcThreeMock.EXPECT().Name().Return("three").AnyTimes()
 
 			s := &azureClusterService{
 				scope: &scope.ClusterScope{
@@ -105,6 +116,191 @@ func TestAzureClusterServiceReconcile(t *testing.T) {
 	}
 }
 
+func TestAzureClusterServicePlan(t *testing.T) {
+	cases := map[string]struct {
+		expectedOperations []infrav1.PlannedOperation
+		expectedMessage    string
+		expect             func(one *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder)
+	}{
+		"operations of all services are recorded in order": {
+			expectedOperations: []infrav1.PlannedOperation{
+				{Type: infrav1.PlannedOperationCreate, Service: "one", ResourceGroup: "my-rg", Name: "my-vnet"},
+				{Type: infrav1.PlannedOperationUpdate, Service: "two", ResourceGroup: "my-rg", Name: "my-nsg"},
+			},
+			expect: func(one *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder) {
+				gomock.InOrder(
+					one.Reconcile(gomockinternal.AContext()).DoAndReturn(func(ctx context.Context) error {
+						azure.PlanFromContext(ctx).Record(infrav1.PlannedOperationCreate, "one", "my-rg", "my-vnet")
+						return nil
+					}),
+					two.Reconcile(gomockinternal.AContext()).DoAndReturn(func(ctx context.Context) error {
+						azure.PlanFromContext(ctx).Record(infrav1.PlannedOperationUpdate, "two", "my-rg", "my-nsg")
+						return nil
+					}))
+			},
+		},
+		"service failure is reported in the plan": {
+			expectedOperations: []infrav1.PlannedOperation{
+				{Type: infrav1.PlannedOperationCreate, Service: "one", ResourceGroup: "my-rg", Name: "my-vnet"},
+			},
+			expectedMessage: "failed to reconcile AzureCluster service two: some error happened",
+			expect: func(one *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder) {
+				gomock.InOrder(
+					one.Reconcile(gomockinternal.AContext()).DoAndReturn(func(ctx context.Context) error {
+						azure.PlanFromContext(ctx).Record(infrav1.PlannedOperationCreate, "one", "my-rg", "my-vnet")
+						return nil
+					}),
+					two.Reconcile(gomockinternal.AContext()).Return(errors.New("some error happened")),
+					two.Name().Return("two"))
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			svcOneMock := mock_azure.NewMockServiceReconciler(mockCtrl)
+			svcTwoMock := mock_azure.NewMockServiceReconciler(mockCtrl)
+
+			tc.expect(svcOneMock.EXPECT(), svcTwoMock.EXPECT())
+			svcOneMock.EXPECT().Name().Return("one").AnyTimes()
+			svcTwoMock.EXPECT().Name().Return("two").AnyTimes()
+
+			s := &azureClusterService{
+				scope: &scope.ClusterScope{
+					Cluster:      &clusterv1.Cluster{},
+					AzureCluster: &infrav1.AzureCluster{},
+				},
+				services: []azure.ServiceReconciler{
+					svcOneMock,
+					svcTwoMock,
+				},
+				skuCache: resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, ""),
+			}
+
+			plan := s.Plan(context.TODO())
+			g.Expect(plan.Operations).To(Equal(tc.expectedOperations))
+			g.Expect(plan.Message).To(Equal(tc.expectedMessage))
+			g.Expect(plan.GeneratedAt.IsZero()).To(BeFalse())
+		})
+	}
+}
+
+func TestAzureClusterServiceReconcileTags(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	vnetMock := mock_azure.NewMockServiceReconciler(mockCtrl)
+	tagsMock := mock_azure.NewMockServiceReconciler(mockCtrl)
+	vnetMock.EXPECT().Name().Return("virtualnetworks").AnyTimes()
+	tagsMock.EXPECT().Name().Return(tags.ServiceName).AnyTimes()
+
+	s := &azureClusterService{
+		scope: &scope.ClusterScope{
+			Cluster:      &clusterv1.Cluster{},
+			AzureCluster: &infrav1.AzureCluster{},
+		},
+		services: []azure.ServiceReconciler{
+			vnetMock,
+			tagsMock,
+		},
+		skuCache: resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, ""),
+	}
+
+	// Only the tags service is reconciled.
+	tagsMock.EXPECT().Reconcile(gomockinternal.AContext()).Return(nil)
+	g.Expect(s.ReconcileTags(context.TODO())).To(Succeed())
+
+	tagsMock.EXPECT().Reconcile(gomockinternal.AContext()).Return(errors.New("some error happened"))
+	g.Expect(s.ReconcileTags(context.TODO())).To(MatchError("failed to reconcile AzureCluster service tags: some error happened"))
+}
+
+func TestAzureClusterServiceSetFailureDomains(t *testing.T) {
+	skus := []armcompute.ResourceSKU{
+		{
+			Name:         ptr.To("Standard_D2s_v3"),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			LocationInfo: []*armcompute.ResourceSKULocationInfo{
+				{
+					Location: ptr.To("eastus"),
+					Zones:    []*string{ptr.To("1"), ptr.To("2"), ptr.To("3")},
+				},
+			},
+		},
+		{
+			Name:         ptr.To("Standard_NC6"),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			LocationInfo: []*armcompute.ResourceSKULocationInfo{
+				{
+					Location: ptr.To("eastus"),
+					Zones:    []*string{ptr.To("1"), ptr.To("3")},
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		vmSizes     []string
+		existingFDs clusterv1.FailureDomains
+		expectedFDs []string
+	}{
+		"all zones of the location": {
+			expectedFDs: []string{"1", "2", "3"},
+		},
+		"zones supporting every VM size": {
+			vmSizes:     []string{"Standard_D2s_v3", "Standard_NC6"},
+			expectedFDs: []string{"1", "3"},
+		},
+		"VM size not available in the location": {
+			vmSizes:     []string{"Standard_M128"},
+			expectedFDs: []string{},
+		},
+		"zones no longer eligible are removed": {
+			vmSizes:     []string{"Standard_NC6"},
+			existingFDs: clusterv1.FailureDomains{"2": clusterv1.FailureDomainSpec{ControlPlane: true}},
+			expectedFDs: []string{"1", "3"},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			s := &azureClusterService{
+				scope: &scope.ClusterScope{
+					Cluster: &clusterv1.Cluster{},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location:             "eastus",
+								FailureDomainVMSizes: tc.vmSizes,
+							},
+						},
+						Status: infrav1.AzureClusterStatus{
+							FailureDomains: tc.existingFDs,
+						},
+					},
+				},
+				skuCache: resourceskus.NewStaticCache(skus, "eastus"),
+			}
+
+			g.Expect(s.setFailureDomainsForLocation(context.TODO())).To(Succeed())
+			fds := make([]string, 0)
+			for _, fd := range s.scope.FailureDomains() {
+				fds = append(fds, *fd)
+			}
+			g.Expect(fds).To(Equal(tc.expectedFDs))
+		})
+	}
+}
+
 func TestAzureClusterServicePause(t *testing.T) {
 	type pausingServiceReconciler struct {
 		*mock_azure.MockServiceReconciler
@@ -183,7 +379,7 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 	cases := map[string]struct {
 		expectedError string
 		clientBuilder func(g Gomega) client.Client
-		expect        func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, one *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder, three *mock_azure.MockServiceReconcilerMockRecorder)
+		expect        func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, one *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder, three *mock_azure.MockServiceReconcilerMockRecorder, four *mock_azure.MockServiceReconcilerMockRecorder, five *mock_azure.MockServiceReconcilerMockRecorder)
 	}{
 		"Resource Group is deleted successfully": {
 			expectedError: "",
@@ -202,6 +398,12 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 							asoannotations.ReconcilePolicy: string(asoannotations.ReconcilePolicyManage),
 						},
 					},
+					Status: asoresourcesv1.ResourceGroup_STATUS{
+						Tags: infrav1.Build(infrav1.BuildParams{
+							ClusterName: clusterName,
+							Lifecycle:   infrav1.ResourceLifecycleOwned,
+						}),
+					},
 				}
 
 				c := fakeclient.NewClientBuilder().
@@ -211,12 +413,42 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 
 				return c
 			},
-			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder) {
+			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, dns *mock_azure.MockServiceReconcilerMockRecorder, lck *mock_azure.MockServiceReconcilerMockRecorder, nw *mock_azure.MockServiceReconcilerMockRecorder, mi *mock_azure.MockServiceReconcilerMockRecorder, ds *mock_azure.MockServiceReconcilerMockRecorder) {
 				gomock.InOrder(
 					grp.Name().Return(groups.ServiceName),
 					vpr.Name().Return(vnetpeerings.ServiceName),
 					vpr.Delete(gomockinternal.AContext()).Return(nil),
 					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					dns.Delete(gomockinternal.AContext()).Return(nil),
+					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					lck.Name().Return(managementlocks.ServiceName),
+					nw.Name().Return(networkwatchers.ServiceName),
+					mi.Name().Return(managedidentities.ServiceName),
+					ds.Name().Return(diagnosticsettings.ServiceName),
+					ds.Delete(gomockinternal.AContext()).Return(nil),
+					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					lck.Name().Return(managementlocks.ServiceName),
+					nw.Name().Return(networkwatchers.ServiceName),
+					nw.Delete(gomockinternal.AContext()).Return(nil),
+					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					lck.Name().Return(managementlocks.ServiceName),
+					nw.Name().Return(networkwatchers.ServiceName),
+					mi.Name().Return(managedidentities.ServiceName),
+					mi.Delete(gomockinternal.AContext()).Return(nil),
+					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					lck.Name().Return(managementlocks.ServiceName),
+					lck.Delete(gomockinternal.AContext()).Return(nil),
+					grp.Name().Return(groups.ServiceName),
 					grp.Delete(gomockinternal.AContext()).Return(nil))
 			},
 		},
@@ -237,6 +469,12 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 							asoannotations.ReconcilePolicy: string(asoannotations.ReconcilePolicyManage),
 						},
 					},
+					Status: asoresourcesv1.ResourceGroup_STATUS{
+						Tags: infrav1.Build(infrav1.BuildParams{
+							ClusterName: clusterName,
+							Lifecycle:   infrav1.ResourceLifecycleOwned,
+						}),
+					},
 				}
 
 				c := fakeclient.NewClientBuilder().
@@ -246,12 +484,42 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 
 				return c
 			},
-			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder) {
+			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, dns *mock_azure.MockServiceReconcilerMockRecorder, lck *mock_azure.MockServiceReconcilerMockRecorder, nw *mock_azure.MockServiceReconcilerMockRecorder, mi *mock_azure.MockServiceReconcilerMockRecorder, ds *mock_azure.MockServiceReconcilerMockRecorder) {
 				gomock.InOrder(
 					grp.Name().Return(groups.ServiceName),
 					vpr.Name().Return(vnetpeerings.ServiceName),
 					vpr.Delete(gomockinternal.AContext()).Return(nil),
 					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					dns.Delete(gomockinternal.AContext()).Return(nil),
+					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					lck.Name().Return(managementlocks.ServiceName),
+					nw.Name().Return(networkwatchers.ServiceName),
+					mi.Name().Return(managedidentities.ServiceName),
+					ds.Name().Return(diagnosticsettings.ServiceName),
+					ds.Delete(gomockinternal.AContext()).Return(nil),
+					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					lck.Name().Return(managementlocks.ServiceName),
+					nw.Name().Return(networkwatchers.ServiceName),
+					nw.Delete(gomockinternal.AContext()).Return(nil),
+					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					lck.Name().Return(managementlocks.ServiceName),
+					nw.Name().Return(networkwatchers.ServiceName),
+					mi.Name().Return(managedidentities.ServiceName),
+					mi.Delete(gomockinternal.AContext()).Return(nil),
+					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					lck.Name().Return(managementlocks.ServiceName),
+					lck.Delete(gomockinternal.AContext()).Return(nil),
+					grp.Name().Return(groups.ServiceName),
 					grp.Delete(gomockinternal.AContext()).Return(errors.New("internal error")))
 			},
 		},
@@ -278,8 +546,10 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 
 				return c
 			},
-			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, one *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder, three *mock_azure.MockServiceReconcilerMockRecorder) {
+			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, one *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder, three *mock_azure.MockServiceReconcilerMockRecorder, four *mock_azure.MockServiceReconcilerMockRecorder, five *mock_azure.MockServiceReconcilerMockRecorder) {
 				gomock.InOrder(
+					five.Delete(gomockinternal.AContext()).Return(nil),
+					four.Delete(gomockinternal.AContext()).Return(nil),
 					three.Delete(gomockinternal.AContext()).Return(nil),
 					two.Delete(gomockinternal.AContext()).Return(nil),
 					one.Delete(gomockinternal.AContext()).Return(nil),
@@ -310,8 +580,10 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 
 				return c
 			},
-			expect: func(_ *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder, three *mock_azure.MockServiceReconcilerMockRecorder) {
+			expect: func(_ *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder, three *mock_azure.MockServiceReconcilerMockRecorder, four *mock_azure.MockServiceReconcilerMockRecorder, five *mock_azure.MockServiceReconcilerMockRecorder) {
 				gomock.InOrder(
+					five.Delete(gomockinternal.AContext()).Return(nil),
+					four.Delete(gomockinternal.AContext()).Return(nil),
 					three.Delete(gomockinternal.AContext()).Return(nil),
 					two.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
 					two.Name().Return("two"))
@@ -332,8 +604,17 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 			svcOneMock := mock_azure.NewMockServiceReconciler(mockCtrl)
 			svcTwoMock := mock_azure.NewMockServiceReconciler(mockCtrl)
 			svcThreeMock := mock_azure.NewMockServiceReconciler(mockCtrl)
-
-			tc.expect(groupsMock.EXPECT(), vnetpeeringsMock.EXPECT(), svcOneMock.EXPECT(), svcTwoMock.EXPECT(), svcThreeMock.EXPECT())
+			svcFourMock := mock_azure.NewMockServiceReconciler(mockCtrl)
+			svcFiveMock := mock_azure.NewMockServiceReconciler(mockCtrl)
+
+			tc.expect(groupsMock.EXPECT(), vnetpeeringsMock.EXPECT(), svcOneMock.EXPECT(), svcTwoMock.EXPECT(), svcThreeMock.EXPECT(), svcFourMock.EXPECT(), svcFiveMock.EXPECT())
+			groupsMock.EXPECT().Name().Return(groups.ServiceName).AnyTimes()
+			vnetpeeringsMock.EXPECT().Name().Return(vnetpeerings.ServiceName).AnyTimes()
+			svcOneMock.EXPECT().Name().Return("one").AnyTimes()
+			svcTwoMock.EXPECT().Name().Return("two").AnyTimes()
+			svcThreeMock.EXPECT().Name().Return("three").AnyTimes()
+			svcFourMock.EXPECT().Name().Return("four").AnyTimes()
+			svcFiveMock.EXPECT().Name().Return("five").AnyTimes()
 			c := tc.clientBuilder(g)
 
 			s := &azureClusterService{
@@ -358,6 +639,8 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 					svcOneMock,
 					svcTwoMock,
 					svcThreeMock,
+					svcFourMock,
+					svcFiveMock,
 				},
 				skuCache: resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, ""),
 			}
@@ -372,3 +655,111 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 		})
 	}
 }
+
+// fakeDeletionCandidatesLister is a service reconciler which also lists the resources deleting the cluster would delete.
+type fakeDeletionCandidatesLister struct {
+	*mock_azure.MockServiceReconciler
+	candidates []string
+	wholeGroup bool
+}
+
+func (f *fakeDeletionCandidatesLister) DeletionCandidates(_ context.Context, wholeGroup bool) ([]string, error) {
+	f.wholeGroup = wholeGroup
+	return f.candidates, nil
+}
+
+func TestAzureClusterServiceDeletionDryRun(t *testing.T) {
+	clusterName := "cluster"
+	namespace := "ns"
+	resourceGroup := "rg"
+	vnetID := "/subscriptions/123/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet"
+
+	cases := map[string]struct {
+		rgLabels       map[string]string
+		rgAnnotations  map[string]string
+		rgTags         map[string]string
+		wantWholeGroup bool
+		wantResources  []string
+	}{
+		"managed resource group is deleted with all its resources": {
+			rgLabels: map[string]string{
+				infrav1.OwnedByClusterLabelKey: clusterName,
+			},
+			rgAnnotations: map[string]string{
+				asoannotations.ReconcilePolicy: string(asoannotations.ReconcilePolicyManage),
+			},
+			rgTags: infrav1.Build(infrav1.BuildParams{
+				ClusterName: clusterName,
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+			}),
+			wantWholeGroup: true,
+			wantResources:  []string{"/subscriptions//resourceGroups/rg", vnetID},
+		},
+		"only owned resources are deleted from a BYO resource group": {
+			rgLabels: map[string]string{
+				infrav1.OwnedByClusterLabelKey: "not-" + clusterName,
+			},
+			wantWholeGroup: false,
+			wantResources:  []string{vnetID},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scheme := runtime.NewScheme()
+			g.Expect(asoresourcesv1.AddToScheme(scheme)).To(Succeed())
+			rg := &asoresourcesv1.ResourceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        resourceGroup,
+					Namespace:   namespace,
+					Labels:      tc.rgLabels,
+					Annotations: tc.rgAnnotations,
+				},
+				Status: asoresourcesv1.ResourceGroup_STATUS{
+					Tags: tc.rgTags,
+				},
+			}
+			c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(rg).Build()
+
+			groupsMock := mock_azure.NewMockServiceReconciler(mockCtrl)
+			groupsMock.EXPECT().Name().AnyTimes().Return(groups.ServiceName)
+			orphansMock := mock_azure.NewMockServiceReconciler(mockCtrl)
+			orphansMock.EXPECT().Name().AnyTimes().Return(orphanedresources.ServiceName)
+			lister := &fakeDeletionCandidatesLister{MockServiceReconciler: orphansMock, candidates: []string{vnetID}}
+
+			s := &azureClusterService{
+				scope: &scope.ClusterScope{
+					Client: c,
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: resourceGroup,
+						},
+					},
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:              clusterName,
+							Namespace:         namespace,
+							DeletionTimestamp: &metav1.Time{Time: time.Now()},
+						},
+					},
+				},
+				services: []azure.ServiceReconciler{
+					groupsMock,
+					lister,
+				},
+			}
+
+			resources, err := s.DeletionDryRun(context.TODO())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lister.wholeGroup).To(Equal(tc.wantWholeGroup))
+			g.Expect(resources).To(Equal(tc.wantResources))
+		})
+	}
+}
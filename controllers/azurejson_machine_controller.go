@@ -35,8 +35,10 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -120,10 +122,13 @@ func (f filterUnclonedMachinesPredicate) Generic(e event.GenericEvent) bool {
 	// outside of machinedeployments/machinesets and using AzureMachineTemplates. if a machine is part of a machineset
 	// or machinedeployment, we already created a secret for the template. All machines
 	// in the machinedeployment will share that one secret.
-	gvk := infrav1.GroupVersion.WithKind("AzureMachineTemplate")
-	isClonedFromTemplate := e.Object.GetAnnotations()[clusterv1.TemplateClonedFromGroupKindAnnotation] == gvk.GroupKind().String()
+	return !isClonedFromAzureMachineTemplate(e.Object)
+}
 
-	return !isClonedFromTemplate
+// isClonedFromAzureMachineTemplate returns true if obj was created from an AzureMachineTemplate.
+func isClonedFromAzureMachineTemplate(obj client.Object) bool {
+	gvk := infrav1.GroupVersion.WithKind("AzureMachineTemplate")
+	return obj.GetAnnotations()[clusterv1.TemplateClonedFromGroupKindAnnotation] == gvk.GroupKind().String()
 }
 
 // Reconcile reconciles the Azure json for a specific machine not in a machine deployment.
@@ -249,5 +254,18 @@ func (r *AzureJSONMachineReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile azure secret")
 	}
 
+	// Control plane machines also publish the worker node config into the workload cluster so that the CSI drivers
+	// can provision Azure Disk and Azure File volumes as soon as they are installed.
+	if _, ok := azureMachine.Labels[clusterv1.MachineControlPlaneLabel]; ok && conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		workloadClient, err := remote.NewClusterClient(ctx, "azurejsonmachine", r.Client, util.ObjectKey(cluster))
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to create workload cluster client")
+		}
+		if err := reconcileWorkloadCloudProviderSecret(ctx, workloadClient, newSecret.Data["worker-node-azure.json"], clusterScope.ClusterName()); err != nil {
+			r.Recorder.Eventf(azureMachine, corev1.EventTypeWarning, "Error reconciling cloud provider secret in workload cluster", err.Error())
+			return ctrl.Result{}, errors.Wrap(err, "failed to reconcile workload cluster cloud provider secret")
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
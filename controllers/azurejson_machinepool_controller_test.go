@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"testing"
 
@@ -130,6 +131,22 @@ func TestAzureJSONPoolReconciler(t *testing.T) {
 				azureMachinePool,
 			},
 		},
+		"paused cluster should not be reconciled": {
+			objects: []runtime.Object{
+				&clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+					Spec: clusterv1.ClusterSpec{
+						Paused:            true,
+						InfrastructureRef: cluster.Spec.InfrastructureRef,
+					},
+				},
+				machinePool,
+				azureMachinePool,
+			},
+			fail: false,
+		},
 		"missing azure cluster should return error": {
 			objects: []runtime.Object{
 				cluster,
@@ -209,3 +226,136 @@ func TestAzureJSONPoolReconciler(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureJSONPoolReconcilerIdentities(t *testing.T) {
+	scheme, err := newScheme()
+	if err != nil {
+		t.Error(err)
+	}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-cluster",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Kind:       "AzureCluster",
+				Name:       "my-azure-cluster",
+			},
+		},
+	}
+
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-azure-cluster",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "cluster.x-k8s.io/v1beta1",
+					Kind:       "Cluster",
+					Name:       "my-cluster",
+				},
+			},
+		},
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				SubscriptionID: "123",
+			},
+			NetworkSpec: infrav1.NetworkSpec{
+				Subnets: infrav1.Subnets{
+					{
+						SubnetClassSpec: infrav1.SubnetClassSpec{
+							Name: "node",
+							Role: infrav1.SubnetNode,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	newPool := func(name string, identity infrav1.VMIdentity) (*expv1.MachinePool, *infrav1exp.AzureMachinePool) {
+		machinePool := &expv1.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel: "my-cluster",
+				},
+			},
+		}
+		azureMachinePool := &infrav1exp.AzureMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "azure-" + name,
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: "cluster.x-k8s.io/v1beta1",
+						Kind:       "MachinePool",
+						Name:       name,
+					},
+				},
+			},
+			Spec: infrav1exp.AzureMachinePoolSpec{
+				Identity: identity,
+			},
+		}
+		return machinePool, azureMachinePool
+	}
+	spMachinePool, spAzureMachinePool := newPool("sp-pool", infrav1.VMIdentityNone)
+	msiMachinePool, msiAzureMachinePool := newPool("msi-pool", infrav1.VMIdentitySystemAssigned)
+
+	os.Setenv(auth.ClientID, "fooClient")
+	os.Setenv(auth.ClientSecret, "fooSecret")
+	os.Setenv(auth.TenantID, "fooTenant")
+
+	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster, azureCluster,
+		spMachinePool, spAzureMachinePool, msiMachinePool, msiAzureMachinePool).Build()
+	reconciler := &AzureJSONMachinePoolReconciler{
+		Client:   client,
+		Recorder: record.NewFakeRecorder(128),
+	}
+
+	// Each pool gets its own secret, with the credentials of its own identity.
+	cases := map[string]struct {
+		pool                        *infrav1exp.AzureMachinePool
+		wantClientSecret            string
+		useManagedIdentityExtension bool
+	}{
+		"service principal pool": {
+			pool:             spAzureMachinePool,
+			wantClientSecret: "fooSecret",
+		},
+		"system-assigned identity pool": {
+			pool:                        msiAzureMachinePool,
+			useManagedIdentityExtension: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: tc.pool.Name},
+			})
+			if err != nil {
+				t.Fatalf("expected success, but got error: %s", err.Error())
+			}
+
+			secret := &corev1.Secret{}
+			if err := client.Get(context.Background(), types.NamespacedName{Name: tc.pool.Name + "-azure-json"}, secret); err != nil {
+				t.Fatalf("expected the secret of the pool, but got error: %s", err.Error())
+			}
+			if owner := secret.OwnerReferences[0].Name; owner != tc.pool.Name {
+				t.Errorf("expected the secret to be owned by %s, but got %s", tc.pool.Name, owner)
+			}
+			config := &CloudProviderConfig{}
+			if err := json.Unmarshal(secret.Data["worker-node-azure.json"], config); err != nil {
+				t.Fatal(err)
+			}
+			if config.AadClientSecret != tc.wantClientSecret {
+				t.Errorf("expected aadClientSecret %q, but got %q", tc.wantClientSecret, config.AadClientSecret)
+			}
+			if config.UseManagedIdentityExtension != tc.useManagedIdentityExtension {
+				t.Errorf("expected useManagedIdentityExtension %t, but got %t", tc.useManagedIdentityExtension, config.UseManagedIdentityExtension)
+			}
+		})
+	}
+}
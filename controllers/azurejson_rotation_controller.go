@@ -0,0 +1,360 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capifeature "sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// AzureJSONRotationReconciler regenerates the azure.json secrets of the AzureMachines, AzureMachineTemplates and
+// AzureMachinePools of an AzureCluster when the values they are generated from change: the spec of the AzureCluster,
+// its AzureClusterIdentity or the client secret of the identity. The other azure.json controllers only regenerate the
+// secret of an object when the object itself changes, which leaves nodes with stale credentials and network settings
+// until they are recreated.
+type AzureJSONRotationReconciler struct {
+	client.Client
+	Recorder         record.EventRecorder
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+	// Rollout sets the rolloutAfter of the MachineDeployments and the KubeadmControlPlane using an
+	// AzureMachineTemplate whose azure.json secret changed, so that their machines are replaced by machines bootstrapped
+	// with the new secret.
+	Rollout bool
+}
+
+// SetupWithManager initializes this controller with a manager.
+func (r *AzureJSONRotationReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	_, log, done := tele.StartSpanWithLogger(ctx,
+		"controllers.AzureJSONRotationReconciler.SetupWithManager",
+		tele.KVP("controller", "AzureJSONRotation"),
+	)
+	defer done()
+
+	// Status updates of the AzureCluster don't change the azure.json secrets.
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AzureCluster{}, builder.WithPredicates(
+			predicate.GenerationChangedPredicate{},
+			predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue),
+		)).
+		Named("AzureJSONRotation").
+		Build(r)
+	if err != nil {
+		return errors.Wrap(err, "error creating controller")
+	}
+
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &infrav1.AzureClusterIdentity{}),
+		handler.EnqueueRequestsFromMapFunc(r.identityToAzureClusters),
+		predicate.GenerationChangedPredicate{},
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for AzureClusterIdentities")
+	}
+
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &corev1.Secret{}),
+		handler.EnqueueRequestsFromMapFunc(r.identitySecretToAzureClusters),
+		predicate.ResourceVersionChangedPredicate{},
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for identity secrets")
+	}
+
+	return nil
+}
+
+// identityToAzureClusters maps an AzureClusterIdentity to the AzureClusters using it.
+func (r *AzureJSONRotationReconciler) identityToAzureClusters(ctx context.Context, o client.Object) []reconcile.Request {
+	azureClusters := &infrav1.AzureClusterList{}
+	if err := r.List(ctx, azureClusters); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, azureCluster := range azureClusters.Items {
+		ref := azureCluster.Spec.IdentityRef
+		if ref == nil || ref.Name != o.GetName() {
+			continue
+		}
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = azureCluster.Namespace
+		}
+		if namespace == o.GetNamespace() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&azureCluster)})
+		}
+	}
+	return requests
+}
+
+// identitySecretToAzureClusters maps the client secret of AzureClusterIdentities to the AzureClusters using them.
+func (r *AzureJSONRotationReconciler) identitySecretToAzureClusters(ctx context.Context, o client.Object) []reconcile.Request {
+	identities := &infrav1.AzureClusterIdentityList{}
+	if err := r.List(ctx, identities); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range identities.Items {
+		identity := &identities.Items[i]
+		ref := identity.Spec.ClientSecret
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = identity.Namespace
+		}
+		if ref.Name == o.GetName() && namespace == o.GetNamespace() {
+			requests = append(requests, r.identityToAzureClusters(ctx, identity)...)
+		}
+	}
+	return requests
+}
+
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=kubeadmcontrolplanes,verbs=get;patch
+
+// Reconcile regenerates the azure.json secrets of the machines of an AzureCluster.
+func (r *AzureJSONRotationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
+	defer cancel()
+
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureJSONRotationReconciler.Reconcile",
+		tele.KVP("namespace", req.Namespace),
+		tele.KVP("name", req.Name),
+		tele.KVP("kind", "AzureCluster"),
+	)
+	defer done()
+
+	azureCluster := &infrav1.AzureCluster{}
+	if err := r.Get(ctx, req.NamespacedName, azureCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if !azureCluster.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, azureCluster.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cluster == nil {
+		log.V(4).Info("Cluster Controller has not yet set OwnerRef")
+		return reconcile.Result{}, nil
+	}
+	if annotations.IsPaused(cluster, azureCluster) {
+		return reconcile.Result{}, nil
+	}
+
+	rotated, err := r.regenerateSecrets(ctx, cluster)
+	if len(rotated) > 0 {
+		names := make([]string, 0, len(rotated))
+		for _, owner := range rotated {
+			names = append(names, owner.secretName())
+		}
+		log.Info("regenerated azure.json secrets", "secrets", names)
+		r.Recorder.Eventf(azureCluster, corev1.EventTypeNormal, "AzureJSONRotated", "Regenerated azure.json secrets %s", strings.Join(names, ", "))
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if r.Rollout {
+		var errs []error
+		for _, owner := range rotated {
+			if owner.kind == "AzureMachineTemplate" {
+				errs = append(errs, r.rolloutTemplate(ctx, cluster, owner.name))
+			}
+		}
+		if err := kerrors.NewAggregate(errs); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// azureJSONOwner is an object with an azure.json secret.
+type azureJSONOwner struct {
+	kind      string
+	namespace string
+	name      string
+	reconcile func(context.Context, ctrl.Request) (ctrl.Result, error)
+}
+
+// secretName returns the name of the azure.json secret of the object.
+func (o azureJSONOwner) secretName() string {
+	return o.name + "-azure-json"
+}
+
+// regenerateSecrets regenerates the azure.json secrets of the objects of cluster with the azure.json controller of
+// each object, and returns the objects whose existing secret changed.
+func (r *AzureJSONRotationReconciler) regenerateSecrets(ctx context.Context, cluster *clusterv1.Cluster) ([]azureJSONOwner, error) {
+	owners, err := r.azureJSONOwners(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var rotated []azureJSONOwner
+	var errs []error
+	for _, owner := range owners {
+		key := client.ObjectKey{Namespace: owner.namespace, Name: owner.secretName()}
+		// Secrets which don't exist yet are created by the azure.json controller of their object.
+		before := &corev1.Secret{}
+		if err := r.Get(ctx, key, before); err != nil {
+			if !apierrors.IsNotFound(err) {
+				errs = append(errs, errors.Wrapf(err, "failed to get secret %s", key))
+			}
+			continue
+		}
+		if _, err := owner.reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Namespace: owner.namespace, Name: owner.name}}); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to regenerate the azure.json secret of %s %s", owner.kind, owner.name))
+			continue
+		}
+		after := &corev1.Secret{}
+		if err := r.Get(ctx, key, after); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to get secret %s", key))
+			continue
+		}
+		if !equality.Semantic.DeepEqual(before.Data, after.Data) {
+			rotated = append(rotated, owner)
+		}
+	}
+	sort.Slice(rotated, func(i, j int) bool { return rotated[i].secretName() < rotated[j].secretName() })
+	return rotated, kerrors.NewAggregate(errs)
+}
+
+// azureJSONOwners returns the AzureMachines not created from an AzureMachineTemplate, the AzureMachineTemplates and the
+// AzureMachinePools of cluster.
+func (r *AzureJSONRotationReconciler) azureJSONOwners(ctx context.Context, cluster *clusterv1.Cluster) ([]azureJSONOwner, error) {
+	listOpts := []client.ListOption{
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name},
+	}
+	var owners []azureJSONOwner
+
+	machineReconciler := &AzureJSONMachineReconciler{Client: r.Client, Recorder: r.Recorder, ReconcileTimeout: r.ReconcileTimeout, WatchFilterValue: r.WatchFilterValue}
+	machines := &infrav1.AzureMachineList{}
+	if err := r.List(ctx, machines, listOpts...); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureMachines")
+	}
+	for _, machine := range machines.Items {
+		// The machines created from a template share the secret of the template.
+		if isClonedFromAzureMachineTemplate(&machine) {
+			continue
+		}
+		owners = append(owners, azureJSONOwner{kind: "AzureMachine", namespace: machine.Namespace, name: machine.Name, reconcile: machineReconciler.Reconcile})
+	}
+
+	templateReconciler := &AzureJSONTemplateReconciler{Client: r.Client, Recorder: r.Recorder, ReconcileTimeout: r.ReconcileTimeout, WatchFilterValue: r.WatchFilterValue}
+	templates := &infrav1.AzureMachineTemplateList{}
+	if err := r.List(ctx, templates, listOpts...); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureMachineTemplates")
+	}
+	for _, template := range templates.Items {
+		owners = append(owners, azureJSONOwner{kind: "AzureMachineTemplate", namespace: template.Namespace, name: template.Name, reconcile: templateReconciler.Reconcile})
+	}
+
+	if feature.Gates.Enabled(capifeature.MachinePool) {
+		poolReconciler := &AzureJSONMachinePoolReconciler{Client: r.Client, Recorder: r.Recorder, ReconcileTimeout: r.ReconcileTimeout, WatchFilterValue: r.WatchFilterValue}
+		pools := &infrav1exp.AzureMachinePoolList{}
+		if err := r.List(ctx, pools, listOpts...); err != nil {
+			return nil, errors.Wrap(err, "failed to list AzureMachinePools")
+		}
+		for _, pool := range pools.Items {
+			owners = append(owners, azureJSONOwner{kind: "AzureMachinePool", namespace: pool.Namespace, name: pool.Name, reconcile: poolReconciler.Reconcile})
+		}
+	}
+	return owners, nil
+}
+
+// rolloutTemplate sets the rolloutAfter of the MachineDeployments and the KubeadmControlPlane of cluster using the
+// AzureMachineTemplate named template, so that their machines are replaced.
+func (r *AzureJSONRotationReconciler) rolloutTemplate(ctx context.Context, cluster *clusterv1.Cluster, template string) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureJSONRotationReconciler.rolloutTemplate")
+	defer done()
+
+	now := metav1.Now()
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := r.List(ctx, machineDeployments, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return errors.Wrap(err, "failed to list MachineDeployments")
+	}
+	for i := range machineDeployments.Items {
+		md := &machineDeployments.Items[i]
+		ref := md.Spec.Template.Spec.InfrastructureRef
+		if ref.Kind != "AzureMachineTemplate" || ref.Name != template {
+			continue
+		}
+		patch := client.MergeFrom(md.DeepCopy())
+		md.Spec.RolloutAfter = &now
+		if err := r.Patch(ctx, md, patch); err != nil {
+			return errors.Wrapf(err, "failed to roll out MachineDeployment %s", md.Name)
+		}
+		log.Info("rolling out MachineDeployment to use the regenerated azure.json secret", "machineDeployment", md.Name)
+	}
+
+	ref := cluster.Spec.ControlPlaneRef
+	if ref == nil || ref.Kind != "KubeadmControlPlane" {
+		return nil
+	}
+	controlPlane := &unstructured.Unstructured{}
+	controlPlane.SetAPIVersion(ref.APIVersion)
+	controlPlane.SetKind(ref.Kind)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: ref.Name}, controlPlane); err != nil {
+		return errors.Wrapf(err, "failed to get KubeadmControlPlane %s", ref.Name)
+	}
+	name, _, err := unstructured.NestedString(controlPlane.Object, "spec", "machineTemplate", "infrastructureRef", "name")
+	if err != nil || name != template {
+		return nil
+	}
+	patch := client.MergeFrom(controlPlane.DeepCopy())
+	if err := unstructured.SetNestedField(controlPlane.Object, now.UTC().Format(time.RFC3339), "spec", "rolloutAfter"); err != nil {
+		return err
+	}
+	if err := r.Patch(ctx, controlPlane, patch); err != nil {
+		return errors.Wrapf(err, "failed to roll out KubeadmControlPlane %s", ref.Name)
+	}
+	log.Info("rolling out KubeadmControlPlane to use the regenerated azure.json secret", "kubeadmControlPlane", ref.Name)
+	return nil
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestAzureJSONRotationReconciler(t *testing.T) {
+	g := NewWithT(t)
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	labels := map[string]string{clusterv1.ClusterNameLabel: "my-cluster"}
+	clusterOwner := metav1.OwnerReference{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "my-cluster"}
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: infrav1.GroupVersion.String(),
+				Kind:       "AzureCluster",
+				Name:       "my-azure-cluster",
+			},
+		},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "my-azure-cluster",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{clusterOwner},
+		},
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				SubscriptionID: "123",
+			},
+		},
+	}
+	template := &infrav1.AzureMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "my-cluster-md-0",
+			Namespace:       "default",
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{clusterOwner},
+		},
+	}
+	// Machines created from the template share its secret.
+	clonedMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-cluster-md-0-abcde",
+			Namespace:   "default",
+			Labels:      labels,
+			Annotations: map[string]string{clusterv1.TemplateClonedFromGroupKindAnnotation: "AzureMachineTemplate.infrastructure.cluster.x-k8s.io"},
+		},
+	}
+	staleSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster-md-0-azure-json",
+			Namespace: "default",
+			Labels:    map[string]string{"my-cluster": string(infrav1.ResourceLifecycleOwned)},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: infrav1.GroupVersion.String(), Kind: "AzureMachineTemplate", Name: "my-cluster-md-0"},
+			},
+		},
+		Data: map[string][]byte{
+			"control-plane-azure.json": []byte("{}"),
+			"worker-node-azure.json":   []byte("{}"),
+		},
+	}
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-md-0", Namespace: "default", Labels: labels},
+		Spec: clusterv1.MachineDeploymentSpec{
+			ClusterName: "my-cluster",
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					ClusterName: "my-cluster",
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: infrav1.GroupVersion.String(),
+						Kind:       "AzureMachineTemplate",
+						Name:       "my-cluster-md-0",
+					},
+				},
+			},
+		},
+	}
+	otherMD := md.DeepCopy()
+	otherMD.Name = "my-cluster-md-1"
+	otherMD.Spec.Template.Spec.InfrastructureRef.Name = "my-cluster-md-1"
+
+	os.Setenv(auth.ClientID, "fooClient")
+	os.Setenv(auth.ClientSecret, "fooSecret")
+	os.Setenv(auth.TenantID, "fooTenant")
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, azureCluster, template, clonedMachine, staleSecret, md, otherMD).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &AzureJSONRotationReconciler{
+		Client:   c,
+		Recorder: recorder,
+		Rollout:  true,
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(azureCluster)}
+
+	_, err = reconciler.Reconcile(context.Background(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	secret := &corev1.Secret{}
+	g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(staleSecret), secret)).To(Succeed())
+	g.Expect(secret.Data).NotTo(Equal(staleSecret.Data))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("AzureJSONRotated")))
+
+	g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(md), md)).To(Succeed())
+	g.Expect(md.Spec.RolloutAfter).NotTo(BeNil())
+	g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(otherMD), otherMD)).To(Succeed())
+	g.Expect(otherMD.Spec.RolloutAfter).To(BeNil())
+
+	// The secrets are only rolled when they change.
+	rolloutAfter := md.Spec.RolloutAfter
+	_, err = reconciler.Reconcile(context.Background(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(recorder.Events).NotTo(Receive())
+	g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(md), md)).To(Succeed())
+	g.Expect(md.Spec.RolloutAfter).To(Equal(rolloutAfter))
+	g.Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "my-cluster-md-0-abcde-azure-json"}, &corev1.Secret{})).NotTo(Succeed())
+}
+
+func TestAzureJSONRotationMapping(t *testing.T) {
+	g := NewWithT(t)
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	identity := &infrav1.AzureClusterIdentity{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-identity", Namespace: "identities"},
+		Spec: infrav1.AzureClusterIdentitySpec{
+			ClientSecret: corev1.SecretReference{Name: "my-identity-secret"},
+		},
+	}
+	usingCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "using", Namespace: "default"},
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				IdentityRef: &corev1.ObjectReference{Name: "my-identity", Namespace: "identities"},
+			},
+		},
+	}
+	sameNamespaceCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "same-namespace", Namespace: "identities"},
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				IdentityRef: &corev1.ObjectReference{Name: "my-identity"},
+			},
+		},
+	}
+	otherCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				IdentityRef: &corev1.ObjectReference{Name: "my-identity"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(identity, usingCluster, sameNamespaceCluster, otherCluster).Build()
+	reconciler := &AzureJSONRotationReconciler{Client: c}
+
+	expected := []reconcile.Request{
+		{NamespacedName: client.ObjectKeyFromObject(sameNamespaceCluster)},
+		{NamespacedName: client.ObjectKeyFromObject(usingCluster)},
+	}
+	g.Expect(reconciler.identityToAzureClusters(context.Background(), identity)).To(ConsistOf(expected))
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "my-identity-secret", Namespace: "identities"}}
+	g.Expect(reconciler.identitySecretToAzureClusters(context.Background(), secret)).To(ConsistOf(expected))
+
+	otherSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "my-identity-secret", Namespace: "default"}}
+	g.Expect(reconciler.identitySecretToAzureClusters(context.Background(), otherSecret)).To(BeEmpty())
+}
@@ -19,16 +19,22 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootdiagnostics"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/imagefreshness"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/statussink"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -48,9 +54,21 @@ import (
 // AzureMachineReconciler reconciles an AzureMachine object.
 type AzureMachineReconciler struct {
 	client.Client
-	Recorder                  record.EventRecorder
-	ReconcileTimeout          time.Duration
-	WatchFilterValue          string
+	Recorder         record.EventRecorder
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+	// StatusSink receives the provisioning phase transitions of AzureMachines. It is optional.
+	StatusSink statussink.Sink
+	// ImageFreshnessCheckInterval is how often the image of the VM is compared against the latest build available.
+	// When 0, the image is not checked.
+	ImageFreshnessCheckInterval time.Duration
+	// NodeJoinTimeout is how long a VM can run without its node joining the cluster before its serial console log is
+	// collected into the NodeNotJoined condition. When 0, the serial console log is not collected.
+	NodeJoinTimeout time.Duration
+	// BackendPoolCheckInterval is how often control plane machines are reconciled to check that their network
+	// interfaces are in the backend pools of the API server load balancer, and add them back to the pools they are
+	// missing from. When 0, they are only checked when the AzureMachine is reconciled for another reason.
+	BackendPoolCheckInterval  time.Duration
 	createAzureMachineService azureMachineServiceCreator
 }
 
@@ -130,7 +148,9 @@ func (amr *AzureMachineReconciler) SetupWithManager(ctx context.Context, mgr ctr
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachines/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
-// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch
 
 // Reconcile idempotently gets, creates, and updates a machine.
 func (amr *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -198,10 +218,23 @@ func (amr *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		AzureCluster: azureCluster,
 	})
 	if err != nil {
+		// The AzureClusterIdentity or its secret can briefly be missing while clusterctl move pivots
+		// objects to a new management cluster, so wait for them instead of failing the reconcile.
+		if apierrors.IsNotFound(err) {
+			if annotations.IsPaused(cluster, azureMachine) {
+				log.Info("AzureMachine or linked Cluster is marked as paused and credentials are not available yet. Won't reconcile")
+				return reconcile.Result{}, nil
+			}
+			amr.Recorder.Eventf(azureMachine, corev1.EventTypeWarning, "ClusterCredentialsNotFound", err.Error())
+			log.Info("credentials are not available yet, requeuing", "reason", err.Error())
+			return reconcile.Result{RequeueAfter: reconciler.DefaultReconcilerRequeue}, nil
+		}
 		amr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "Error creating the cluster scope", err.Error())
 		return reconcile.Result{}, err
 	}
 
+	ctx = tele.WithLogValues(ctx, "cluster", cluster.Name, "resourceGroup", clusterScope.ResourceGroup())
+
 	// Create the machine scope
 	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
 		Client:       amr.Client,
@@ -215,10 +248,16 @@ func (amr *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 
 	// Always close the scope when exiting this function so we can persist any AzureMachine changes.
+	previousPhase, _ := statussink.AzureMachinePhase(azureMachine)
 	defer func() {
-		if err := machineScope.Close(ctx); err != nil && reterr == nil {
-			reterr = err
+		if err := machineScope.Close(ctx); err != nil {
+			if reterr == nil {
+				reterr = err
+			}
+			return
 		}
+		phase, message := statussink.AzureMachinePhase(azureMachine)
+		reportPhaseTransition(ctx, amr.StatusSink, "AzureMachine", azureMachine, cluster.Name, previousPhase, phase, message)
 	}()
 
 	// Return early if the object or Cluster is paused.
@@ -294,12 +333,23 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 		return reconcile.Result{}, errors.New("VM identities are not ready")
 	}
 
+	ready, err := reconcileNICAddressClaims(ctx, amr.Client, machineScope)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile network interface IP address claims")
+	}
+	if !ready {
+		log.Info("Waiting for IP address pools to fulfill the network interface IP address claims")
+		conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, infrav1.WaitingForIPAddressReason, clusterv1.ConditionSeverityInfo, "")
+		return reconcile.Result{RequeueAfter: reconciler.DefaultReconcilerRequeue}, nil
+	}
+
 	ams, err := amr.createAzureMachineService(machineScope)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create azure machine service")
 	}
 
-	if err := ams.Reconcile(ctx); err != nil {
+	driftCtx, drift := withDriftCheck(ctx, clusterScope.AzureCluster.Spec.DriftRemediation)
+	if err := ams.Reconcile(driftCtx); err != nil {
 		// This means that a VM was created and managed by this controller, but is not present anymore.
 		// In this case, we mark it as failed and leave it to MHC for remediation
 		if errors.As(err, &azure.VMDeletedError{}) {
@@ -316,7 +366,11 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 			if reconcileError.IsTerminal() {
 				amr.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "ReconcileError", errors.Wrapf(err, "failed to reconcile AzureMachine").Error())
 				log.Error(err, "failed to reconcile AzureMachine", "name", machineScope.Name())
-				machineScope.SetFailureReason(capierrors.CreateMachineError)
+				failureReason := capierrors.CreateMachineError
+				if reason, ok := azure.TerminalFailureReason(err); ok {
+					failureReason = reason
+				}
+				machineScope.SetFailureReason(failureReason)
 				machineScope.SetFailureMessage(err)
 				machineScope.SetNotReady()
 				machineScope.SetVMState(infrav1.Failed)
@@ -336,9 +390,91 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile AzureMachine")
 	}
 
+	machineScope.SetEtcdDataDisk()
 	machineScope.SetReady()
+	reportDrift(amr.Recorder, machineScope.AzureMachine, drift)
 
-	return reconcile.Result{}, nil
+	if conditions.IsTrue(clusterScope.Cluster, clusterv1.ControlPlaneInitializedCondition) {
+		amr.reconcileNodeRef(ctx, machineScope)
+	}
+
+	var result reconcile.Result
+	if amr.NodeJoinTimeout > 0 {
+		result.RequeueAfter = amr.reportNodeNotJoined(ctx, machineScope)
+	}
+
+	if amr.ImageFreshnessCheckInterval > 0 {
+		amr.reportImageFreshness(ctx, machineScope)
+		if result.RequeueAfter == 0 || amr.ImageFreshnessCheckInterval < result.RequeueAfter {
+			result.RequeueAfter = amr.ImageFreshnessCheckInterval
+		}
+	}
+
+	// The network interfaces service adds the NICs back to the backend pools they were removed from, e.g. by a manual
+	// edit or a failed attach operation, which would otherwise silently remove API server capacity.
+	if amr.BackendPoolCheckInterval > 0 && machineScope.IsControlPlane() {
+		if result.RequeueAfter == 0 || amr.BackendPoolCheckInterval < result.RequeueAfter {
+			result.RequeueAfter = amr.BackendPoolCheckInterval
+		}
+	}
+
+	return result, nil
+}
+
+// reconcileNodeRef records the node of the machine in the AzureMachine status, until the Machine references the same
+// node. Failing to reach the workload cluster is not an error: the node is looked up again on the next reconciliation.
+func (amr *AzureMachineReconciler) reconcileNodeRef(ctx context.Context, machineScope *scope.MachineScope) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachine.reconcileNodeRef")
+	defer done()
+
+	nodeRef, machineNodeRef := machineScope.AzureMachine.Status.NodeRef, machineScope.Machine.Status.NodeRef
+	if nodeRef != nil && machineNodeRef != nil && nodeRef.UID == machineNodeRef.UID {
+		return
+	}
+	if err := machineScope.UpdateNodeRef(ctx); err != nil {
+		log.V(2).Info("failed to look up the node of the machine", "error", err.Error())
+	}
+}
+
+// reportNodeNotJoined collects the serial console log of a running VM whose node has not joined the cluster within
+// the node join timeout, and returns when the machine should be checked again.
+func (amr *AzureMachineReconciler) reportNodeNotJoined(ctx context.Context, machineScope *scope.MachineScope) time.Duration {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachine.reportNodeNotJoined")
+	defer done()
+
+	diagnostics := machineScope.AzureMachine.Spec.Diagnostics
+	bootDiagnosticsDisabled := diagnostics != nil && diagnostics.Boot != nil && diagnostics.Boot.StorageAccountType == infrav1.DisabledDiagnosticsStorage
+	if machineScope.Machine.Status.NodeRef != nil || bootDiagnosticsDisabled {
+		conditions.Delete(machineScope.AzureMachine, infrav1.NodeNotJoinedCondition)
+		return 0
+	}
+	running := conditions.Get(machineScope.AzureMachine, infrav1.VMRunningCondition)
+	if running == nil || running.Status != corev1.ConditionTrue {
+		return 0
+	}
+	svc, err := bootdiagnostics.New(machineScope)
+	if err != nil {
+		log.V(2).Info("failed to create boot diagnostics service", "error", err.Error())
+		return 0
+	}
+	return ReportNodeNotJoined(ctx, amr.Recorder, machineScope.AzureMachine, svc, machineScope.ResourceGroup(), machineScope.Name(), running.LastTransitionTime.Time, amr.NodeJoinTimeout)
+}
+
+// reportImageFreshness reports whether a newer build of the image of the VM is available.
+func (amr *AzureMachineReconciler) reportImageFreshness(ctx context.Context, machineScope *scope.MachineScope) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachine.reportImageFreshness")
+	defer done()
+
+	vm := machineScope.AzureMachine.Status.VM
+	if vm == nil {
+		return
+	}
+	svc, err := imagefreshness.New(machineScope)
+	if err != nil {
+		log.V(2).Info("failed to create image freshness service", "error", err.Error())
+		return
+	}
+	ReportImageFreshness(ctx, amr.Recorder, machineScope.AzureMachine, svc, vm.Image, machineScope.Location(), amr.ImageFreshnessCheckInterval)
 }
 
 func (amr *AzureMachineReconciler) reconcilePause(ctx context.Context, machineScope *scope.MachineScope) (reconcile.Result, error) {
@@ -390,8 +526,27 @@ func (amr *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineS
 				}
 			}
 
-			amr.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "Error deleting AzureMachine", errors.Wrapf(err, "error deleting AzureMachine %s/%s", machineScope.Namespace(), machineScope.Name()).Error())
-			return reconcile.Result{}, errors.Wrapf(err, "error deleting AzureMachine %s/%s", machineScope.Namespace(), machineScope.Name())
+			if !shouldForceDelete(machineScope.AzureMachine, err, time.Now()) {
+				amr.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "Error deleting AzureMachine", errors.Wrapf(err, "error deleting AzureMachine %s/%s", machineScope.Namespace(), machineScope.Name()).Error())
+				return reconcile.Result{}, errors.Wrapf(err, "error deleting AzureMachine %s/%s", machineScope.Namespace(), machineScope.Name())
+			}
+
+			// The deletion is forced: the VM and the NICs, disks and public IPs tagged as dedicated to the machine are
+			// recorded as abandoned and the finalizer removed anyway. Failing to list them must not block the
+			// deletion, the error is recorded instead.
+			var resources []string
+			if providerID := machineScope.ProviderID(); providerID != "" {
+				resources = append(resources, strings.TrimPrefix(providerID, azureutil.ProviderIDPrefix))
+			}
+			deleteErr := errors.Wrapf(err, "error deleting AzureMachine %s/%s", machineScope.Namespace(), machineScope.Name())
+			machineResources, listErr := ams.ListResources(ctx)
+			if listErr != nil {
+				deleteErr = kerrors.NewAggregate([]error{deleteErr, errors.Wrap(listErr, "failed to list the abandoned resources")})
+			}
+			resources = append(resources, machineResources...)
+			if err := abandonResources(ctx, amr.Client, amr.Recorder, machineScope.AzureMachine, "AzureMachine", machineScope.ClusterName(), resources, deleteErr); err != nil {
+				return reconcile.Result{}, err
+			}
 		}
 	} else {
 		log.Info("Skipping AzureMachine Deletion; will delete whole resource group.")
@@ -18,13 +18,17 @@ package controllers
 
 import (
 	"context"
+	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -213,6 +217,11 @@ func TestAzureMachineReconcileNormal(t *testing.T) {
 			machineScopeFailureReason: capierrors.CreateMachineError,
 			cache:                     &scope.MachineCache{},
 		},
+		"should set the failure reason of a terminal Azure error": {
+			createAzureMachineService: getFakeAzureMachineServiceWithQuotaExceededError,
+			machineScopeFailureReason: capierrors.InsufficientResourcesMachineError,
+			cache:                     &scope.MachineCache{},
+		},
 		"should requeue if transient error is received": {
 			createAzureMachineService: getFakeAzureMachineServiceWithTransientError,
 			cache:                     &scope.MachineCache{},
@@ -253,6 +262,42 @@ func TestAzureMachineReconcileNormal(t *testing.T) {
 	}
 }
 
+func TestAzureMachineReconcileNormalBackendPoolCheck(t *testing.T) {
+	cases := map[string]struct {
+		controlPlane   bool
+		expectedResult reconcile.Result
+	}{
+		"control plane machines are requeued to check their backend pools": {
+			controlPlane:   true,
+			expectedResult: reconcile.Result{RequeueAfter: 5 * time.Minute},
+		},
+		"worker machines are not requeued": {
+			expectedResult: reconcile.Result{},
+		},
+	}
+
+	for name, c := range cases {
+		tc := c
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			reconciler, machineScope, clusterScope, err := getReconcileInputs(TestReconcileInput{
+				createAzureMachineService: getFakeAzureMachineService,
+				cache:                     &scope.MachineCache{},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			reconciler.BackendPoolCheckInterval = 5 * time.Minute
+			if tc.controlPlane {
+				machineScope.Machine.Labels = map[string]string{clusterv1.MachineControlPlaneLabel: ""}
+			}
+
+			result, err := reconciler.reconcileNormal(context.Background(), machineScope, clusterScope)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result).To(Equal(tc.expectedResult))
+		})
+	}
+}
+
 func TestAzureMachineReconcilePause(t *testing.T) {
 	cases := map[string]TestReconcileInput{
 		"should pause successfully": {
@@ -336,6 +381,73 @@ func TestAzureMachineReconcileDelete(t *testing.T) {
 	}
 }
 
+func TestAzureMachineReconcileForceDelete(t *testing.T) {
+	g := NewWithT(t)
+
+	vmID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm"
+	nicID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-vm-nic"
+	diskID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-vm_OSDisk"
+	reconciler, machineScope, clusterScope, err := getReconcileInputs(TestReconcileInput{
+		createAzureMachineService: func(machineScope *scope.MachineScope) (*azureMachineService, error) {
+			ams, err := getFakeAzureMachineServiceWithGeneralError(machineScope)
+			if err != nil {
+				return nil, err
+			}
+			ams.ListResources = func(context.Context) ([]string, error) {
+				return []string{diskID, nicID}, nil
+			}
+			return ams, nil
+		},
+		cache: &scope.MachineCache{},
+		azureMachineOptions: func(am *infrav1.AzureMachine) {
+			am.Annotations = map[string]string{
+				infrav1.ForceDeleteAnnotation: "true",
+				deleteFailingSinceAnnotation:  time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+			}
+			am.Finalizers = []string{infrav1.MachineFinalizer}
+			am.Spec.ProviderID = ptr.To("azure://" + vmID)
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	result, err := reconciler.reconcileDelete(context.Background(), machineScope, clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(reconcile.Result{}))
+	g.Expect(machineScope.AzureMachine.Finalizers).NotTo(ContainElement(infrav1.MachineFinalizer))
+
+	configMap := &corev1.ConfigMap{}
+	g.Expect(reconciler.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "azuremachine-my-machine-abandoned-resources"}, configMap)).To(Succeed())
+	g.Expect(configMap.OwnerReferences).To(BeEmpty())
+	g.Expect(configMap.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "my-cluster"))
+	g.Expect(configMap.Annotations).To(HaveKey(abandonedAtAnnotation))
+	g.Expect(configMap.Data).To(HaveKeyWithValue(abandonedResourcesKey, `["`+vmID+`","`+diskID+`","`+nicID+`"]`))
+	g.Expect(configMap.Data[abandonedErrorKey]).To(ContainSubstring("foo error"))
+	g.Expect(reconciler.Recorder.(*record.FakeRecorder).Events).To(Receive(HavePrefix("Warning ForceDeleted")))
+}
+
+func TestAzureMachineReconcileForceDeleteWaitsForFailureToPersist(t *testing.T) {
+	g := NewWithT(t)
+
+	reconciler, machineScope, clusterScope, err := getReconcileInputs(TestReconcileInput{
+		createAzureMachineService: getFakeAzureMachineServiceWithGeneralError,
+		cache:                     &scope.MachineCache{},
+		azureMachineOptions: func(am *infrav1.AzureMachine) {
+			am.Annotations = map[string]string{infrav1.ForceDeleteAnnotation: "true"}
+			am.Finalizers = []string{infrav1.MachineFinalizer}
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = reconciler.reconcileDelete(context.Background(), machineScope, clusterScope)
+	g.Expect(err).To(MatchError(ContainSubstring("foo error")))
+	g.Expect(machineScope.AzureMachine.Finalizers).To(ContainElement(infrav1.MachineFinalizer))
+	g.Expect(machineScope.AzureMachine.Annotations).To(HaveKey(deleteFailingSinceAnnotation))
+
+	configMap := &corev1.ConfigMap{}
+	err = reconciler.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "azuremachine-my-machine-abandoned-resources"}, configMap)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+}
+
 func getReconcileInputs(tc TestReconcileInput) (*AzureMachineReconciler, *scope.MachineScope, *scope.ClusterScope, error) {
 	scheme, err := newScheme()
 	if err != nil {
@@ -454,6 +566,29 @@ func getFakeAzureMachineServiceWithTerminalError(machineScope *scope.MachineScop
 	return ams, nil
 }
 
+func getFakeAzureMachineServiceWithQuotaExceededError(machineScope *scope.MachineScope) (*azureMachineService, error) {
+	cache, err := resourceskus.GetCache(machineScope, machineScope.Location())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating a NewCache")
+	}
+
+	ams := getDefaultAzureMachineService(machineScope, cache)
+	ams.Reconcile = func(context.Context) error {
+		quotaErr := &azcore.ResponseError{
+			StatusCode: http.StatusConflict,
+			ErrorCode:  "QuotaExceeded",
+			RawResponse: &http.Response{
+				StatusCode: http.StatusConflict,
+				Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{}},
+				Body:       http.NoBody,
+			},
+		}
+		return azure.WithTerminalError(errors.Wrap(quotaErr, "failed to create VM"))
+	}
+
+	return ams, nil
+}
+
 func getFakeAzureMachineServiceWithTransientError(machineScope *scope.MachineScope) (*azureMachineService, error) {
 	cache, err := resourceskus.GetCache(machineScope, machineScope.Location())
 	if err != nil {
@@ -23,15 +23,23 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/availabilitysets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootstrapdata"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootstrapsecrets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/imagecaptures"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/nodelabels"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/nodemetadata"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/noderoutes"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/orphanedresources"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -40,11 +48,12 @@ type azureMachineService struct {
 	scope *scope.MachineScope
 	// services is the list of services to be reconciled.
 	// The order of the services is important as it determines the order in which the services are reconciled.
-	services  []azure.ServiceReconciler
-	skuCache  *resourceskus.Cache
-	Reconcile func(context.Context) error
-	Pause     func(context.Context) error
-	Delete    func(context.Context) error
+	services      []azure.ServiceReconciler
+	skuCache      *resourceskus.Cache
+	Reconcile     func(context.Context) error
+	Pause         func(context.Context) error
+	Delete        func(context.Context) error
+	ListResources func(context.Context) ([]string, error)
 }
 
 // newAzureMachineService populates all the services based on input scope.
@@ -57,10 +66,22 @@ func newAzureMachineService(machineScope *scope.MachineScope) (*azureMachineServ
 	if err != nil {
 		return nil, errors.Wrap(err, "failed creating availabilitysets service")
 	}
+	bootstrapDataSvc, err := bootstrapdata.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating bootstrapdata service")
+	}
+	bootstrapSecretsSvc, err := bootstrapsecrets.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating bootstrapsecrets service")
+	}
 	disksSvc, err := disks.New(machineScope)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed creating disks service")
 	}
+	imageCapturesSvc, err := imagecaptures.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating imagecaptures service")
+	}
 	inboundnatrulesSvc, err := inboundnatrules.New(machineScope)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed creating inboundnatrules service")
@@ -73,6 +94,15 @@ func newAzureMachineService(machineScope *scope.MachineScope) (*azureMachineServ
 	if err != nil {
 		return nil, errors.Wrap(err, "failed creating roleassignments service")
 	}
+	nodeLabelsSvc, err := nodelabels.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating nodelabels service")
+	}
+	nodeMetadataSvc := nodemetadata.New(machineScope)
+	nodeRoutesSvc, err := noderoutes.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating noderoutes service")
+	}
 	tagsSvc, err := tags.New(machineScope)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed creating tags service")
@@ -97,23 +127,30 @@ func newAzureMachineService(machineScope *scope.MachineScope) (*azureMachineServ
 			networkInterfacesSvc,
 			availabilitySetsSvc,
 			disksSvc,
+			bootstrapDataSvc,
 			virtualmachinesSvc,
+			bootstrapSecretsSvc,
 			roleAssignmentsSvc,
 			vmextensionsSvc,
 			tagsSvc,
+			nodeLabelsSvc,
+			nodeMetadataSvc,
+			nodeRoutesSvc,
+			imageCapturesSvc,
 		},
 		skuCache: cache,
 	}
 	ams.Reconcile = ams.reconcile
 	ams.Pause = ams.pause
 	ams.Delete = ams.delete
+	ams.ListResources = ams.listResources
 
 	return ams, nil
 }
 
 // reconcile reconciles all the services in a predetermined order.
 func (s *azureMachineService) reconcile(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachineService.reconcile")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachineService.reconcile")
 	defer done()
 
 	// Ensure that the deprecated networking field values have been migrated to the new NetworkInterfaces field.
@@ -123,8 +160,10 @@ func (s *azureMachineService) reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed defaulting subnet name")
 	}
 
+	timings := &reconciler.ServiceTimings{}
+	defer timings.Log(log, "reconciled AzureMachine services")
 	for _, service := range s.services {
-		if err := service.Reconcile(ctx); err != nil {
+		if err := timings.Time(service.Name(), func() error { return service.Reconcile(ctx) }); err != nil {
 			return errors.Wrapf(err, "failed to reconcile AzureMachine service %s", service.Name())
 		}
 	}
@@ -152,15 +191,29 @@ func (s *azureMachineService) pause(ctx context.Context) error {
 
 // delete deletes all the services in a predetermined order.
 func (s *azureMachineService) delete(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachineService.delete")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachineService.delete")
 	defer done()
 
+	timings := &reconciler.ServiceTimings{}
+	defer timings.Log(log, "deleted AzureMachine services")
 	// Delete services in reverse order of creation.
 	for i := len(s.services) - 1; i >= 0; i-- {
-		if err := s.services[i].Delete(ctx); err != nil {
+		if err := timings.Time(s.services[i].Name(), func() error { return s.services[i].Delete(ctx) }); err != nil {
 			return errors.Wrapf(err, "failed to delete AzureMachine service %s", s.services[i].Name())
 		}
 	}
 
 	return nil
 }
+
+// listResources returns the IDs of the NICs, disks and public IPs tagged as dedicated to the machine.
+func (s *azureMachineService) listResources(ctx context.Context) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachineService.listResources")
+	defer done()
+
+	orphanedResourcesSvc, err := orphanedresources.New(s.scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating orphanedresources service")
+	}
+	return orphanedResourcesSvc.MachineResources(ctx, s.scope.Name())
+}
@@ -71,6 +71,9 @@ func TestAzureMachineServiceReconcile(t *testing.T) {
 			svcThreeMock := mock_azure.NewMockServiceReconciler(mockCtrl)
 
 			tc.expect(svcOneMock.EXPECT(), svcTwoMock.EXPECT(), svcThreeMock.EXPECT())
+			svcOneMock.EXPECT().Name().Return("one").AnyTimes()
+			svcTwoMock.EXPECT().Name().Return("two").AnyTimes()
+			svcThreeMock.EXPECT().Name().Return("three").AnyTimes()
 
 			s := &azureMachineService{
 				scope: &scope.MachineScope{
@@ -212,6 +215,9 @@ func TestAzureMachineServiceDelete(t *testing.T) {
 			svcThreeMock := mock_azure.NewMockServiceReconciler(mockCtrl)
 
 			tc.expect(svcOneMock.EXPECT(), svcTwoMock.EXPECT(), svcThreeMock.EXPECT())
+			svcOneMock.EXPECT().Name().Return("one").AnyTimes()
+			svcTwoMock.EXPECT().Name().Return("two").AnyTimes()
+			svcThreeMock.EXPECT().Name().Return("three").AnyTimes()
 
 			s := &azureMachineService{
 				scope: &scope.MachineScope{
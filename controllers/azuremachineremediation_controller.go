@@ -0,0 +1,267 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/remediations"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// defaultRemediationTimeout is how long the node of a machine is given to become healthy again after its VM was
+// restarted or redeployed, when the remediation strategy does not set a timeout.
+const defaultRemediationTimeout = 5 * time.Minute
+
+// remediationClientFactory returns the client restarting and redeploying the VMs of an AzureCluster.
+type remediationClientFactory func(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, azureCluster *infrav1.AzureCluster) (remediations.Client, error)
+
+// AzureMachineRemediationReconciler reconciles the AzureMachineRemediations created by MachineHealthChecks for
+// unhealthy machines. It restarts or redeploys the VM of the machine, and gives its node time to become healthy again,
+// in which case the MachineHealthCheck deletes the AzureMachineRemediation. Once the retries are exhausted, the machine
+// is marked for its owner to delete and recreate it, like a machine remediated by the MachineHealthCheck itself.
+type AzureMachineRemediationReconciler struct {
+	client.Client
+	Recorder         record.EventRecorder
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+
+	newClient remediationClientFactory
+}
+
+// SetupWithManager initializes this controller with a manager.
+func (r *AzureMachineRemediationReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	_, log, done := tele.StartSpanWithLogger(ctx,
+		"controllers.AzureMachineRemediationReconciler.SetupWithManager",
+		tele.KVP("controller", "AzureMachineRemediation"),
+	)
+	defer done()
+
+	if r.newClient == nil {
+		r.newClient = newRemediationClient
+	}
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AzureMachineRemediation{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "error creating controller")
+	}
+	return nil
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachineremediations,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachineremediations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachineremediationtemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch;update;patch
+
+// Reconcile restarts or redeploys the VM of the unhealthy machine of an AzureMachineRemediation.
+func (r *AzureMachineRemediationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
+	defer cancel()
+
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachineRemediationReconciler.Reconcile",
+		tele.KVP("namespace", req.Namespace),
+		tele.KVP("name", req.Name),
+		tele.KVP("kind", "AzureMachineRemediation"),
+	)
+	defer done()
+
+	remediation := &infrav1.AzureMachineRemediation{}
+	if err := r.Get(ctx, req.NamespacedName, remediation); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if !remediation.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	machine, err := util.GetOwnerMachine(ctx, r.Client, remediation.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if machine == nil {
+		log.Info("MachineHealthCheck has not yet set OwnerRef")
+		return reconcile.Result{}, nil
+	}
+
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machine.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to get cluster of machine")
+	}
+	if annotations.IsPaused(cluster, remediation) {
+		log.Info("AzureMachineRemediation or linked Cluster is marked as paused. Won't reconcile")
+		return reconcile.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(remediation, r.Client)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to init patch helper")
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, remediation); err != nil && reterr == nil {
+			reterr = errors.Wrap(err, "failed to patch AzureMachineRemediation")
+		}
+	}()
+
+	strategy := remediationStrategy(remediation)
+	switch remediation.Status.Phase {
+	case infrav1.RemediationPhaseDeleting:
+		return reconcile.Result{}, nil
+	case infrav1.RemediationPhaseWaiting:
+		if remediation.Status.LastRemediated != nil {
+			if remaining := time.Until(remediation.Status.LastRemediated.Add(strategy.Timeout.Duration)); remaining > 0 {
+				return reconcile.Result{RequeueAfter: remaining}, nil
+			}
+		}
+		if remediation.Status.RetryCount >= strategy.RetryLimit {
+			log.Info("machine is still unhealthy after remediation, giving up", "retries", remediation.Status.RetryCount)
+			return reconcile.Result{}, r.giveUp(ctx, remediation, machine)
+		}
+	}
+
+	azureMachine, azureCluster, err := r.infrastructure(ctx, cluster, machine)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if azureMachine == nil || azureMachine.Spec.ProviderID == nil {
+		log.Info("machine has no VM to remediate, giving up")
+		return reconcile.Result{}, r.giveUp(ctx, remediation, machine)
+	}
+
+	azureClient, err := r.newClient(ctx, r.Client, cluster, azureCluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	remediation.Status.Phase = infrav1.RemediationPhaseRunning
+	resourceGroup := azureCluster.Spec.ResourceGroup
+	if strategy.Type == infrav1.RemediationStrategyRedeploy {
+		err = azureClient.Redeploy(ctx, resourceGroup, azureMachine.Name)
+	} else {
+		err = azureClient.Restart(ctx, resourceGroup, azureMachine.Name)
+	}
+	if azure.ResourceNotFound(err) {
+		log.Info("VM of the machine does not exist, giving up")
+		return reconcile.Result{}, r.giveUp(ctx, remediation, machine)
+	}
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to %s VM %s", strategy.Type, azureMachine.Name)
+	}
+
+	remediation.Status.RetryCount++
+	now := metav1.Now()
+	remediation.Status.LastRemediated = &now
+	remediation.Status.Phase = infrav1.RemediationPhaseWaiting
+	r.Recorder.Eventf(machine, corev1.EventTypeNormal, "Remediating", "%s of VM %s requested, attempt %d of %d", strategy.Type, azureMachine.Name, remediation.Status.RetryCount, strategy.RetryLimit)
+	return reconcile.Result{RequeueAfter: strategy.Timeout.Duration}, nil
+}
+
+// infrastructure returns the AzureMachine of a machine, or nil if it does not exist, and the AzureCluster of its cluster.
+func (r *AzureMachineRemediationReconciler) infrastructure(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) (*infrav1.AzureMachine, *infrav1.AzureCluster, error) {
+	if cluster.Spec.InfrastructureRef == nil || cluster.Spec.InfrastructureRef.Kind != "AzureCluster" {
+		return nil, nil, errors.Errorf("cluster %s/%s is not an AzureCluster", cluster.Namespace, cluster.Name)
+	}
+	azureCluster := &infrav1.AzureCluster{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name}, azureCluster); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get AzureCluster")
+	}
+
+	azureMachine := &infrav1.AzureMachine{}
+	key := client.ObjectKey{Namespace: machine.Namespace, Name: machine.Spec.InfrastructureRef.Name}
+	if err := r.Get(ctx, key, azureMachine); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, azureCluster, nil
+		}
+		return nil, nil, errors.Wrap(err, "failed to get AzureMachine")
+	}
+	return azureMachine, azureCluster, nil
+}
+
+// giveUp marks the machine of a remediation as waiting for its owner to remediate it, which deletes and recreates it.
+func (r *AzureMachineRemediationReconciler) giveUp(ctx context.Context, remediation *infrav1.AzureMachineRemediation, machine *clusterv1.Machine) error {
+	machinePatchHelper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return errors.Wrap(err, "failed to init patch helper")
+	}
+	conditions.MarkFalse(machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning,
+		"AzureMachineRemediation %s gave up after %d attempts", remediation.Name, remediation.Status.RetryCount)
+	if err := machinePatchHelper.Patch(ctx, machine, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+		clusterv1.MachineOwnerRemediatedCondition,
+	}}); err != nil {
+		return errors.Wrap(err, "failed to patch Machine")
+	}
+	remediation.Status.Phase = infrav1.RemediationPhaseDeleting
+	r.Recorder.Eventf(machine, corev1.EventTypeWarning, "RemediationFailed", "Machine is still unhealthy after %d remediation attempts and will be deleted", remediation.Status.RetryCount)
+	return nil
+}
+
+// remediationStrategy returns the strategy of a remediation with its defaults applied.
+func remediationStrategy(remediation *infrav1.AzureMachineRemediation) infrav1.RemediationStrategy {
+	strategy := infrav1.RemediationStrategy{}
+	if remediation.Spec.Strategy != nil {
+		strategy = *remediation.Spec.Strategy
+	}
+	if strategy.Type == "" {
+		strategy.Type = infrav1.RemediationStrategyRestart
+	}
+	if strategy.RetryLimit < 1 {
+		strategy.RetryLimit = 1
+	}
+	if strategy.Timeout == nil {
+		strategy.Timeout = &metav1.Duration{Duration: defaultRemediationTimeout}
+	}
+	return strategy
+}
+
+// newRemediationClient returns a client restarting and redeploying VMs with the credentials of the AzureCluster.
+func newRemediationClient(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, azureCluster *infrav1.AzureCluster) (remediations.Client, error) {
+	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+		Client:       c,
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cluster scope")
+	}
+	return remediations.NewClient(clusterScope)
+}
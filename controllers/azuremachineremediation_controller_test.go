@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/remediations"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/remediations/mock_remediations"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAzureMachineRemediationReconcile(t *testing.T) {
+	longAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+	recently := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	tests := []struct {
+		name                  string
+		strategy              *infrav1.RemediationStrategy
+		status                infrav1.AzureMachineRemediationStatus
+		expect                func(c *mock_remediations.MockClientMockRecorder)
+		expectedStatus        func(g *WithT, status infrav1.AzureMachineRemediationStatus)
+		expectedRequeue       time.Duration
+		expectOwnerRemediated bool
+	}{
+		{
+			name: "VM of an unhealthy machine is restarted by default",
+			expect: func(c *mock_remediations.MockClientMockRecorder) {
+				c.Restart(gomockinternal.AContext(), "my-rg", "my-azure-machine").Return(nil)
+			},
+			expectedStatus: func(g *WithT, status infrav1.AzureMachineRemediationStatus) {
+				g.Expect(status.Phase).To(Equal(infrav1.RemediationPhaseWaiting))
+				g.Expect(status.RetryCount).To(Equal(1))
+				g.Expect(status.LastRemediated).NotTo(BeNil())
+			},
+			expectedRequeue: 5 * time.Minute,
+		},
+		{
+			name:     "node is given time to become healthy again",
+			strategy: &infrav1.RemediationStrategy{Timeout: &metav1.Duration{Duration: 10 * time.Minute}},
+			status: infrav1.AzureMachineRemediationStatus{
+				Phase:          infrav1.RemediationPhaseWaiting,
+				RetryCount:     1,
+				LastRemediated: &recently,
+			},
+			expectedStatus: func(g *WithT, status infrav1.AzureMachineRemediationStatus) {
+				g.Expect(status.Phase).To(Equal(infrav1.RemediationPhaseWaiting))
+				g.Expect(status.RetryCount).To(Equal(1))
+			},
+			expectedRequeue: 9 * time.Minute,
+		},
+		{
+			name:     "VM is redeployed again until the retry limit is reached",
+			strategy: &infrav1.RemediationStrategy{Type: infrav1.RemediationStrategyRedeploy, RetryLimit: 2},
+			status: infrav1.AzureMachineRemediationStatus{
+				Phase:          infrav1.RemediationPhaseWaiting,
+				RetryCount:     1,
+				LastRemediated: &longAgo,
+			},
+			expect: func(c *mock_remediations.MockClientMockRecorder) {
+				c.Redeploy(gomockinternal.AContext(), "my-rg", "my-azure-machine").Return(nil)
+			},
+			expectedStatus: func(g *WithT, status infrav1.AzureMachineRemediationStatus) {
+				g.Expect(status.Phase).To(Equal(infrav1.RemediationPhaseWaiting))
+				g.Expect(status.RetryCount).To(Equal(2))
+			},
+			expectedRequeue: 5 * time.Minute,
+		},
+		{
+			name: "machine is left to its owner once the retry limit is reached",
+			status: infrav1.AzureMachineRemediationStatus{
+				Phase:          infrav1.RemediationPhaseWaiting,
+				RetryCount:     1,
+				LastRemediated: &longAgo,
+			},
+			expectedStatus: func(g *WithT, status infrav1.AzureMachineRemediationStatus) {
+				g.Expect(status.Phase).To(Equal(infrav1.RemediationPhaseDeleting))
+				g.Expect(status.RetryCount).To(Equal(1))
+			},
+			expectOwnerRemediated: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = clusterv1.AddToScheme(scheme)
+			_ = infrav1.AddToScheme(scheme)
+			_ = clientgoscheme.AddToScheme(scheme)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{Kind: "AzureCluster", Name: "my-azure-cluster"},
+				},
+			}
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-azure-cluster", Namespace: "default"},
+				Spec:       infrav1.AzureClusterSpec{ResourceGroup: "my-rg"},
+			}
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-machine",
+					Namespace: "default",
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: "my-cluster"},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName:       "my-cluster",
+					InfrastructureRef: corev1.ObjectReference{Kind: "AzureMachine", Name: "my-azure-machine"},
+				},
+			}
+			azureMachine := &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-azure-machine", Namespace: "default"},
+				Spec:       infrav1.AzureMachineSpec{ProviderID: ptr.To("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-azure-machine")},
+			}
+			remediation := &infrav1.AzureMachineRemediation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-machine",
+					Namespace: "default",
+					OwnerReferences: []metav1.OwnerReference{
+						{APIVersion: clusterv1.GroupVersion.String(), Kind: "Machine", Name: "my-machine"},
+					},
+				},
+				Spec:   infrav1.AzureMachineRemediationSpec{Strategy: tc.strategy},
+				Status: tc.status,
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(cluster, azureCluster, machine, azureMachine, remediation).
+				WithStatusSubresource(remediation, machine).
+				Build()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_remediations.NewMockClient(mockCtrl)
+			if tc.expect != nil {
+				tc.expect(clientMock.EXPECT())
+			}
+
+			r := &AzureMachineRemediationReconciler{
+				Client:   c,
+				Recorder: record.NewFakeRecorder(10),
+				newClient: func(context.Context, client.Client, *clusterv1.Cluster, *infrav1.AzureCluster) (remediations.Client, error) {
+					return clientMock, nil
+				},
+			}
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(remediation)})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.RequeueAfter).To(BeNumerically("~", tc.expectedRequeue, time.Second))
+
+			g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(remediation), remediation)).To(Succeed())
+			tc.expectedStatus(g, remediation.Status)
+
+			g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(machine), machine)).To(Succeed())
+			g.Expect(conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition)).To(Equal(tc.expectOwnerRemediated))
+		})
+	}
+}
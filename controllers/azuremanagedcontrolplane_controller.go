@@ -28,6 +28,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/adaptive"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -62,8 +63,11 @@ func (amcpr *AzureManagedControlPlaneReconciler) SetupWithManager(ctx context.Co
 	defer done()
 
 	var r reconcile.Reconciler = amcpr
+	if options.Intervals != nil {
+		r = adaptive.NewReconciler(r, options.Intervals, mgr.GetClient(), &infrav1.AzureManagedControlPlane{}, log)
+	}
 	if options.Cache != nil {
-		r = coalescing.NewReconciler(amcpr, options.Cache, log)
+		r = coalescing.NewReconciler(r, options.Cache, log)
 	}
 
 	azManagedControlPlane := &infrav1.AzureManagedControlPlane{}
@@ -184,6 +188,8 @@ func (amcpr *AzureManagedControlPlaneReconciler) Reconcile(ctx context.Context,
 		return reconcile.Result{}, errors.Wrap(err, "failed to create scope")
 	}
 
+	ctx = tele.WithLogValues(ctx, "cluster", cluster.Name, "resourceGroup", mcpScope.ResourceGroup())
+
 	// Always patch when exiting so we can persist changes to finalizers and status
 	defer func() {
 		if err := mcpScope.Close(ctx); err != nil && reterr == nil {
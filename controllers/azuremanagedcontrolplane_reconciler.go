@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -86,11 +87,13 @@ func newAzureManagedControlPlaneReconciler(scope *scope.ManagedControlPlaneScope
 
 // Reconcile reconciles all the services in a predetermined order.
 func (r *azureManagedControlPlaneService) Reconcile(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.Reconcile")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.Reconcile")
 	defer done()
 
+	timings := &reconciler.ServiceTimings{}
+	defer timings.Log(log, "reconciled AzureManagedControlPlane services")
 	for _, service := range r.services {
-		if err := service.Reconcile(ctx); err != nil {
+		if err := timings.Time(service.Name(), func() error { return service.Reconcile(ctx) }); err != nil {
 			return errors.Wrapf(err, "failed to reconcile AzureManagedControlPlane service %s", service.Name())
 		}
 	}
@@ -122,12 +125,14 @@ func (r *azureManagedControlPlaneService) Pause(ctx context.Context) error {
 
 // Delete reconciles all the services in a predetermined order.
 func (r *azureManagedControlPlaneService) Delete(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.Delete")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.Delete")
 	defer done()
 
+	timings := &reconciler.ServiceTimings{}
+	defer timings.Log(log, "deleted AzureManagedControlPlane services")
 	// Delete services in reverse order of creation.
 	for i := len(r.services) - 1; i >= 0; i-- {
-		if err := r.services[i].Delete(ctx); err != nil {
+		if err := timings.Time(r.services[i].Name(), func() error { return r.services[i].Delete(ctx) }); err != nil {
 			return errors.Wrapf(err, "failed to delete AzureManagedControlPlane service %s", r.services[i].Name())
 		}
 	}
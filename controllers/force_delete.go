@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// abandonedResourcesKey is the key of the resource IDs in abandoned resources ConfigMaps.
+	abandonedResourcesKey = "resources.json"
+	// abandonedErrorKey is the key of the error which blocked the deletion in abandoned resources ConfigMaps.
+	abandonedErrorKey = "error"
+	// abandonedAtAnnotation records when the resources of an abandoned resources ConfigMap were abandoned.
+	abandonedAtAnnotation = "infrastructure.cluster.x-k8s.io/abandoned-at"
+	// deleteFailingSinceAnnotation records when the deletion of an AzureCluster or an AzureMachine started failing.
+	deleteFailingSinceAnnotation = "infrastructure.cluster.x-k8s.io/delete-failing-since"
+)
+
+// forceDeleteAfter is how long the deletion of an object annotated for forced deletion must have been failing before
+// it is forced, unless Azure denied it with one of forceDeleteErrorCodes.
+var forceDeleteAfter = 30 * time.Minute
+
+// forceDeleteErrorCodes are the codes of the Azure API errors denying a deletion for good, a resource lock and an
+// Azure Policy, for which a deletion annotated for forced deletion is forced right away.
+var forceDeleteErrorCodes = []string{"ScopeLocked", "RequestDisallowedByPolicy"}
+
+// forceDeleteRequested returns true if the ForceDeleteAnnotation of obj is set to "true".
+func forceDeleteRequested(obj metav1.Object) bool {
+	return obj.GetAnnotations()[infrav1.ForceDeleteAnnotation] == "true"
+}
+
+// shouldForceDelete records when the deletion of obj started failing, and returns true if the deletion is to be forced
+// after failing with deleteErr: obj must be annotated for forced deletion, and either Azure denied the deletion with
+// one of forceDeleteErrorCodes or the deletion has been failing for forceDeleteAfter. Transient failures, such as
+// throttling, must not be passed as they would never persist long enough.
+func shouldForceDelete(obj metav1.Object, deleteErr error, now time.Time) bool {
+	annotations := obj.GetAnnotations()
+	failingSince, err := time.Parse(time.RFC3339, annotations[deleteFailingSinceAnnotation])
+	if err != nil {
+		failingSince = now
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[deleteFailingSinceAnnotation] = now.UTC().Format(time.RFC3339)
+		obj.SetAnnotations(annotations)
+	}
+
+	if !forceDeleteRequested(obj) {
+		return false
+	}
+	return azure.HasErrorCode(deleteErr, forceDeleteErrorCodes...) || now.Sub(failingSince) >= forceDeleteAfter
+}
+
+// AbandonedResourcesConfigMapName returns the name of the ConfigMap listing the Azure resources abandoned by the
+// forced deletion of an object of the given kind.
+func AbandonedResourcesConfigMapName(kind, name string) string {
+	return strings.ToLower(kind) + "-" + name + "-abandoned-resources"
+}
+
+// abandonResources records the Azure resources left behind by the forced deletion of obj, after deleting them failed
+// with deleteErr, in a warning event on obj and in a ConfigMap next to it. The ConfigMap has no owner reference so
+// that it outlives obj, it is up to users to delete it once they cleaned up the resources.
+func abandonResources(ctx context.Context, c client.Client, recorder record.EventRecorder, obj client.Object, kind, clusterName string, resources []string, deleteErr error) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.abandonResources")
+	defer done()
+
+	resourcesJSON, err := json.Marshal(resources)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal abandoned resources")
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AbandonedResourcesConfigMapName(kind, obj.GetName()),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, c, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[clusterv1.ClusterNameLabel] = clusterName
+		if configMap.Annotations == nil {
+			configMap.Annotations = map[string]string{}
+		}
+		configMap.Annotations[abandonedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		configMap.Data = map[string]string{
+			abandonedResourcesKey: string(resourcesJSON),
+			abandonedErrorKey:     deleteErr.Error(),
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create or update abandoned resources ConfigMap")
+	}
+
+	log.Info("Forcing deletion, abandoning Azure resources", "kind", kind, "resources", resources, "error", deleteErr.Error())
+	recorder.Eventf(obj, corev1.EventTypeWarning, "ForceDeleted",
+		"Deleting the %s failed, forcing its deletion and abandoning %d Azure resources which must be deleted manually, see ConfigMap %s: %s. Error: %s",
+		kind, len(resources), configMap.Name, summarizeResources(resources), deleteErr.Error())
+	return nil
+}
@@ -19,12 +19,18 @@ package controllers
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"net"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -33,6 +39,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -40,13 +47,19 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/adaptive"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/statussink"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	sshutil "sigs.k8s.io/cluster-api-provider-azure/util/ssh"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	capifeature "sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
@@ -68,6 +81,11 @@ const (
 	deprecatedManagerCredsWarning = "You're using deprecated functionality: " +
 		"Using Azure credentials from the manager environment is deprecated and will be removed in future releases. " +
 		"Please specify an AzureClusterIdentity for the AzureCluster instead, see: https://capz.sigs.k8s.io/topics/multitenancy.html "
+
+	// workloadCloudProviderSecretName and workloadCloudProviderSecretKey are where the Azure Disk and Azure File
+	// CSI drivers look for the cloud provider config in the workload cluster by default.
+	workloadCloudProviderSecretName = "azure-cloud-provider"
+	workloadCloudProviderSecretKey  = "cloud-config"
 )
 
 type (
@@ -75,6 +93,9 @@ type (
 	Options struct {
 		controller.Options
 		Cache *coalescing.ReconcileCache
+		// Intervals, when set, requeues the objects successfully reconciled after an interval adapted to how
+		// recently they drifted or failed to reconcile.
+		Intervals *adaptive.Intervals
 	}
 )
 
@@ -268,7 +289,7 @@ func newCloudProviderConfig(d azure.ClusterScoper) (controlPlaneConfig *CloudPro
 			SubscriptionID:               d.SubscriptionID(),
 			ResourceGroup:                d.ResourceGroup(),
 			SecurityGroupName:            subnet.SecurityGroup.Name,
-			SecurityGroupResourceGroup:   d.Vnet().ResourceGroup,
+			SecurityGroupResourceGroup:   securityGroupResourceGroup(d, subnet),
 			Location:                     d.Location(),
 			ExtendedLocationType:         d.ExtendedLocationType(),
 			ExtendedLocationName:         d.ExtendedLocationName(),
@@ -277,6 +298,7 @@ func newCloudProviderConfig(d azure.ClusterScoper) (controlPlaneConfig *CloudPro
 			VnetResourceGroup:            d.Vnet().ResourceGroup,
 			SubnetName:                   subnet.Name,
 			RouteTableName:               subnet.RouteTable.Name,
+			RouteTableResourceGroup:      routeTableResourceGroup(d),
 			LoadBalancerSku:              "Standard",
 			LoadBalancerName:             d.OutboundLBName(infrav1.Node),
 			MaximumLoadBalancerRuleCount: 250,
@@ -291,7 +313,7 @@ func newCloudProviderConfig(d azure.ClusterScoper) (controlPlaneConfig *CloudPro
 			SubscriptionID:               d.SubscriptionID(),
 			ResourceGroup:                d.ResourceGroup(),
 			SecurityGroupName:            subnet.SecurityGroup.Name,
-			SecurityGroupResourceGroup:   d.Vnet().ResourceGroup,
+			SecurityGroupResourceGroup:   securityGroupResourceGroup(d, subnet),
 			Location:                     d.Location(),
 			ExtendedLocationType:         d.ExtendedLocationType(),
 			ExtendedLocationName:         d.ExtendedLocationName(),
@@ -300,6 +322,7 @@ func newCloudProviderConfig(d azure.ClusterScoper) (controlPlaneConfig *CloudPro
 			VnetResourceGroup:            d.Vnet().ResourceGroup,
 			SubnetName:                   subnet.Name,
 			RouteTableName:               subnet.RouteTable.Name,
+			RouteTableResourceGroup:      routeTableResourceGroup(d),
 			LoadBalancerSku:              "Standard",
 			LoadBalancerName:             d.OutboundLBName(infrav1.Node),
 			MaximumLoadBalancerRuleCount: 250,
@@ -318,6 +341,24 @@ func getOneNodeSubnet(d azure.ClusterScoper) infrav1.SubnetSpec {
 	return infrav1.SubnetSpec{}
 }
 
+// securityGroupResourceGroup returns the resource group of the security group of subnet: the resource group of the
+// existing NSG it references, or else the resource group of the vnet.
+func securityGroupResourceGroup(d azure.ClusterScoper, subnet infrav1.SubnetSpec) string {
+	if rg := subnet.SecurityGroup.ExistingResourceGroup(); rg != "" {
+		return rg
+	}
+	return d.Vnet().ResourceGroup
+}
+
+// routeTableResourceGroup returns the resource group of the cluster route tables if it differs from the cluster
+// resource group, which the cloud provider uses by default.
+func routeTableResourceGroup(d azure.ClusterScoper) string {
+	if d.Vnet().ResourceGroup == d.ResourceGroup() {
+		return ""
+	}
+	return d.Vnet().ResourceGroup
+}
+
 // CloudProviderConfig is an abbreviated version of the same struct in k/k.
 type CloudProviderConfig struct {
 	Cloud                        string `json:"cloud"`
@@ -336,6 +377,7 @@ type CloudProviderConfig struct {
 	VnetResourceGroup            string `json:"vnetResourceGroup"`
 	SubnetName                   string `json:"subnetName"`
 	RouteTableName               string `json:"routeTableName"`
+	RouteTableResourceGroup      string `json:"routeTableResourceGroup,omitempty"`
 	LoadBalancerSku              string `json:"loadBalancerSku"`
 	LoadBalancerName             string `json:"loadBalancerName"`
 	MaximumLoadBalancerRuleCount int    `json:"maximumLoadBalancerRuleCount"`
@@ -353,6 +395,10 @@ func (cpc *CloudProviderConfig) overrideFromSpec(d azure.ClusterScoper) *CloudPr
 		return cpc
 	}
 
+	if vmType := d.CloudProviderConfigOverrides().VMType; vmType != "" {
+		cpc.VMType = vmType
+	}
+
 	for _, rateLimit := range d.CloudProviderConfigOverrides().RateLimits {
 		switch rateLimit.Name {
 		case infrav1.DefaultRateLimit:
@@ -528,6 +574,234 @@ func reconcileAzureSecret(ctx context.Context, kubeclient client.Client, owner m
 	return nil
 }
 
+// reconcileWorkloadCloudProviderSecret ensures the cloud provider config read by the Azure Disk and Azure File CSI drivers
+// exists in the kube-system namespace of the workload cluster. A Secret not labeled as owned by the cluster is
+// considered user provided and is left untouched.
+func reconcileWorkloadCloudProviderSecret(ctx context.Context, workloadClient client.Client, cloudConfig []byte, clusterName string) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.reconcileWorkloadCloudProviderSecret")
+	defer done()
+
+	newSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceSystem,
+			Name:      workloadCloudProviderSecretName,
+			Labels: map[string]string{
+				clusterName: string(infrav1.ResourceLifecycleOwned),
+			},
+		},
+		Data: map[string][]byte{
+			workloadCloudProviderSecretKey: cloudConfig,
+		},
+	}
+
+	old := &corev1.Secret{}
+	err := workloadClient.Get(ctx, client.ObjectKeyFromObject(newSecret), old)
+	if apierrors.IsNotFound(err) {
+		if err := workloadClient.Create(ctx, newSecret); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrap(err, "failed to create workload cluster cloud provider secret")
+		}
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch workload cluster cloud provider secret")
+	}
+
+	if old.Labels[clusterName] != string(infrav1.ResourceLifecycleOwned) {
+		log.V(2).Info("returning early from workload cluster secret reconcile, user provided secret already exists")
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(old.Data, newSecret.Data) {
+		return nil
+	}
+
+	old.Data = newSecret.Data
+	if err := workloadClient.Update(ctx, old); err != nil {
+		return errors.Wrap(err, "failed to update workload cluster cloud provider secret")
+	}
+
+	return nil
+}
+
+// reconcileSSHKeyPairSecret ensures the Secret holding the cluster SSH key pair exists, generating a new key pair
+// if needed. An existing Secret, whether generated or provided by the user, is never modified.
+func reconcileSSHKeyPairSecret(ctx context.Context, kubeclient client.Client, clusterScope *scope.ClusterScope) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.reconcileSSHKeyPairSecret")
+	defer done()
+
+	secretName := clusterScope.SSHKeyPairSecretName()
+	if secretName == "" {
+		return nil
+	}
+
+	key := types.NamespacedName{
+		Namespace: clusterScope.Namespace(),
+		Name:      secretName,
+	}
+	if err := kubeclient.Get(ctx, key, &corev1.Secret{}); err == nil {
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to fetch SSH key pair secret")
+	}
+
+	privateKey, publicKey, err := sshutil.GenerateSSHKey()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate SSH key pair")
+	}
+	owner := metav1.OwnerReference{
+		APIVersion: infrav1.GroupVersion.String(),
+		Kind:       "AzureCluster",
+		Name:       clusterScope.AzureCluster.Name,
+		UID:        clusterScope.AzureCluster.UID,
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels: map[string]string{
+				clusterScope.ClusterName(): string(infrav1.ResourceLifecycleOwned),
+				clusterv1.ClusterNameLabel: clusterScope.ClusterName(),
+			},
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+		Type: corev1.SecretTypeSSHAuth,
+		Data: map[string][]byte{
+			corev1.SSHAuthPrivateKey: pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PRIVATE KEY",
+				Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+			}),
+			azure.SSHKeyPairPublicKey: ssh.MarshalAuthorizedKey(publicKey),
+		},
+	}
+
+	log.V(2).Info("creating SSH key pair secret", "secret", key.String())
+	if err := kubeclient.Create(ctx, secret); err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, "failed to create SSH key pair secret")
+	}
+	return nil
+}
+
+// reconcileSubnetCIDRBlockClaims claims the address space of the subnets referencing an IP address pool and sets their
+// CIDR blocks to the network of the claimed addresses. It returns false while a pool has not fulfilled a claim yet.
+func reconcileSubnetCIDRBlockClaims(ctx context.Context, kubeclient client.Client, clusterScope *scope.ClusterScope) (bool, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.reconcileSubnetCIDRBlockClaims")
+	defer done()
+
+	azureCluster := clusterScope.AzureCluster
+	owner := metav1.OwnerReference{
+		APIVersion: infrav1.GroupVersion.String(),
+		Kind:       "AzureCluster",
+		Name:       azureCluster.Name,
+		UID:        azureCluster.UID,
+	}
+	ready := true
+	for i, subnet := range azureCluster.Spec.NetworkSpec.Subnets {
+		if subnet.CIDRBlockFromPool == nil || len(subnet.CIDRBlocks) > 0 {
+			continue
+		}
+		claimName := azure.GenerateSubnetIPAddressClaimName(azureCluster.Name, subnet.Name)
+		address, err := claimIPAddress(ctx, kubeclient, owner, clusterScope.Namespace(), clusterScope.ClusterName(), claimName, *subnet.CIDRBlockFromPool)
+		if err != nil {
+			return false, err
+		}
+		if address == nil {
+			log.V(2).Info("waiting for the address space of subnet to be claimed", "subnet", subnet.Name, "claim", claimName)
+			ready = false
+			continue
+		}
+		_, network, err := net.ParseCIDR(fmt.Sprintf("%s/%d", address.Spec.Address, address.Spec.Prefix))
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse the address claimed for subnet %s", subnet.Name)
+		}
+		log.V(2).Info("setting the address space of subnet from its IP address claim", "subnet", subnet.Name, "cidr", network.String())
+		azureCluster.Spec.NetworkSpec.Subnets[i].CIDRBlocks = []string{network.String()}
+	}
+	return ready, nil
+}
+
+// reconcileNICAddressClaims claims the private IP addresses of the network interfaces of a machine from the IP address
+// pools they reference, and sets them on the machine scope. It returns false while a pool has not fulfilled a claim yet.
+func reconcileNICAddressClaims(ctx context.Context, kubeclient client.Client, machineScope *scope.MachineScope) (bool, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.reconcileNICAddressClaims")
+	defer done()
+
+	azureMachine := machineScope.AzureMachine
+	owner := metav1.OwnerReference{
+		APIVersion: infrav1.GroupVersion.String(),
+		Kind:       "AzureMachine",
+		Name:       azureMachine.Name,
+		UID:        azureMachine.UID,
+	}
+	ready := true
+	addresses := make([][]string, len(azureMachine.Spec.NetworkInterfaces))
+	for i, nic := range azureMachine.Spec.NetworkInterfaces {
+		for j, pool := range nic.AddressesFromPools {
+			claimName := azure.GenerateNICIPAddressClaimName(azureMachine.Name, i, j)
+			address, err := claimIPAddress(ctx, kubeclient, owner, azureMachine.Namespace, machineScope.ClusterName(), claimName, pool)
+			if err != nil {
+				return false, err
+			}
+			if address == nil {
+				log.V(2).Info("waiting for the private IP address of network interface to be claimed", "claim", claimName)
+				ready = false
+				continue
+			}
+			addresses[i] = append(addresses[i], address.Spec.Address)
+		}
+	}
+	if ready {
+		machineScope.SetNICAddresses(addresses)
+	}
+	return ready, nil
+}
+
+// claimIPAddress ensures an IPAddressClaim exists for the IP address pool, and returns the IPAddress allocated to the
+// claim, or nil while the pool has not fulfilled it yet. The claim is owned by the given object, so the address is
+// released back to the pool once the object is deleted.
+func claimIPAddress(ctx context.Context, kubeclient client.Client, owner metav1.OwnerReference, namespace, clusterName, claimName string, pool corev1.TypedLocalObjectReference) (*ipamv1.IPAddress, error) {
+	key := types.NamespacedName{
+		Namespace: namespace,
+		Name:      claimName,
+	}
+	claim := &ipamv1.IPAddressClaim{}
+	if err := kubeclient.Get(ctx, key, claim); apierrors.IsNotFound(err) {
+		claim = &ipamv1.IPAddressClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel: clusterName,
+				},
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+			Spec: ipamv1.IPAddressClaimSpec{
+				PoolRef: pool,
+			},
+		}
+		if err := kubeclient.Create(ctx, claim); err != nil && !apierrors.IsAlreadyExists(err) {
+			return nil, errors.Wrapf(err, "failed to create IP address claim %s", key)
+		}
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch IP address claim %s", key)
+	}
+
+	if claim.Status.AddressRef.Name == "" {
+		return nil, nil
+	}
+	address := &ipamv1.IPAddress{}
+	addressKey := types.NamespacedName{
+		Namespace: namespace,
+		Name:      claim.Status.AddressRef.Name,
+	}
+	if err := kubeclient.Get(ctx, addressKey, address); apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch IP address %s", addressKey)
+	}
+	return address, nil
+}
+
 // GetOwnerMachinePool returns the MachinePool object owning the current resource.
 func GetOwnerMachinePool(ctx context.Context, c client.Client, obj metav1.ObjectMeta) (*expv1.MachinePool, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.GetOwnerMachinePool")
@@ -599,6 +873,7 @@ func GetAzureMachinePoolByName(ctx context.Context, c client.Client, namespace,
 
 // ShouldDeleteIndividualResources returns false if the resource group is managed and the whole cluster is being deleted
 // meaning that we can rely on a single resource group delete operation as opposed to deleting every individual VM resource.
+// This is never the case when the cluster has a separate network resource group.
 func ShouldDeleteIndividualResources(ctx context.Context, clusterScope *scope.ClusterScope) bool {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.ShouldDeleteIndividualResources")
 	defer done()
@@ -607,6 +882,12 @@ func ShouldDeleteIndividualResources(ctx context.Context, clusterScope *scope.Cl
 		return true
 	}
 
+	// Subnets in a separate network resource group can only be deleted once the NICs and load balancers using them are
+	// gone, so these can't be left to the deletion of the cluster resource group.
+	if clusterScope.AzureCluster.Spec.NetworkResourceGroup != "" {
+		return true
+	}
+
 	return groups.New(clusterScope).ShouldDeleteIndividualResources(ctx)
 }
 
@@ -656,6 +937,10 @@ func EnsureClusterIdentity(ctx context.Context, c client.Client, object conditio
 		return errors.New("AzureClusterIdentity list of allowed namespaces doesn't include current cluster namespace")
 	}
 
+	if err := ensureClusterIdentitySecretMoveLabel(ctx, c, identity); err != nil {
+		return err
+	}
+
 	// Remove deprecated finalizer if it exists, Register the finalizer immediately to avoid orphaning Azure resources on delete.
 	if controllerutil.RemoveFinalizer(identity, deprecatedClusterIdentityFinalizer(finalizerPrefix, namespace, name)) ||
 		controllerutil.AddFinalizer(identity, clusterIdentityFinalizer(finalizerPrefix, namespace, name)) {
@@ -670,6 +955,39 @@ func EnsureClusterIdentity(ctx context.Context, c client.Client, object conditio
 	return nil
 }
 
+// ensureClusterIdentitySecretMoveLabel labels the secret referenced by an AzureClusterIdentity so clusterctl move
+// pivots it together with the identity. A missing secret is not an error here; it is reported when credentials are used.
+func ensureClusterIdentitySecretMoveLabel(ctx context.Context, c client.Client, identity *infrav1.AzureClusterIdentity) error {
+	secretRef := identity.Spec.ClientSecret
+	if secretRef.Name == "" {
+		return nil
+	}
+	key := client.ObjectKey{Namespace: secretRef.Namespace, Name: secretRef.Name}
+	if key.Namespace == "" {
+		key.Namespace = identity.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get AzureClusterIdentity secret %s", key)
+	}
+	if _, ok := secret.Labels[clusterctlv1.ClusterctlMoveLabel]; ok {
+		return nil
+	}
+
+	secretHelper, err := patch.NewHelper(secret, c)
+	if err != nil {
+		return errors.Wrap(err, "failed to init patch helper")
+	}
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[clusterctlv1.ClusterctlMoveLabel] = ""
+	return errors.Wrapf(secretHelper.Patch(ctx, secret), "failed to label AzureClusterIdentity secret %s for clusterctl move", key)
+}
+
 // RemoveClusterIdentityFinalizer removes the finalizer on an AzureClusterIdentity.
 func RemoveClusterIdentityFinalizer(ctx context.Context, c client.Client, object client.Object, identityRef *corev1.ObjectReference, finalizerPrefix string) error {
 	name := object.GetName()
@@ -1025,6 +1343,24 @@ func MachinePoolToAzureManagedControlPlaneMapFunc(ctx context.Context, c client.
 	}
 }
 
+// reportsExternallyManagedStatus returns true if CAPZ should report the status of an externally managed object.
+func reportsExternallyManagedStatus(o metav1.Object) bool {
+	return o.GetAnnotations()[infrav1.ExternallyManagedReportStatusAnnotation] == "true"
+}
+
+// ResourceIsNotExternallyManagedOrReportsStatus returns a predicate that filters out objects carrying the
+// Cluster API managed-by annotation, unless they also opt in to status reporting with the
+// infrav1.ExternallyManagedReportStatusAnnotation annotation.
+func ResourceIsNotExternallyManagedOrReportsStatus(logger logr.Logger) predicate.Funcs {
+	return predicate.NewPredicateFuncs(func(o client.Object) bool {
+		if !annotations.IsExternallyManaged(o) || reportsExternallyManagedStatus(o) {
+			return true
+		}
+		logger.V(6).Info("Resource is externally managed, will not attempt to map resource", "namespace", o.GetNamespace(), o.GetObjectKind().GroupVersionKind().Kind, o.GetName())
+		return false
+	})
+}
+
 // ClusterUpdatePauseChange returns a predicate that returns true for an update event when a cluster's
 // Spec.Paused changes between any two distinct values.
 func ClusterUpdatePauseChange(logger logr.Logger) predicate.Funcs {
@@ -1060,3 +1396,134 @@ func ClusterUpdatePauseChange(logger logr.Logger) predicate.Funcs {
 func ClusterPauseChangeAndInfrastructureReady(log logr.Logger) predicate.Funcs {
 	return predicates.Any(log, predicates.ClusterCreateInfraReady(log), predicates.ClusterUpdateInfraReady(log), ClusterUpdatePauseChange(log))
 }
+
+// maxSummarizedResources is the maximum number of resources listed by summarizeResources.
+const maxSummarizedResources = 20
+
+// summarizeResources returns a comma separated list of resource IDs short enough to fit in an event.
+func summarizeResources(resources []string) string {
+	if len(resources) <= maxSummarizedResources {
+		return strings.Join(resources, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(resources[:maxSummarizedResources], ", "), len(resources)-maxSummarizedResources)
+}
+
+// reportPhaseTransition sends the phase of an object to sink if it changed during reconciliation. It is a no-op when
+// sink is nil.
+func reportPhaseTransition(ctx context.Context, sink statussink.Sink, kind string, obj metav1.Object, clusterName string, previous, phase statussink.Phase, message string) {
+	if sink == nil || phase == previous {
+		return
+	}
+	sink.Send(ctx, statussink.Event{
+		Kind:          kind,
+		Namespace:     obj.GetNamespace(),
+		Name:          obj.GetName(),
+		ClusterName:   clusterName,
+		Phase:         phase,
+		PreviousPhase: previous,
+		Message:       message,
+		Time:          time.Now(),
+	})
+}
+
+// withDriftCheck returns a copy of ctx in which the services record the Azure resources changed out-of-band, and only
+// revert the changes when the drift remediation policy of the cluster is to correct them.
+func withDriftCheck(ctx context.Context, policy infrav1.DriftRemediationPolicy) (context.Context, *azure.Drift) {
+	drift := &azure.Drift{Correct: policy != infrav1.DriftRemediationReport}
+	return azure.WithDrift(ctx, drift), drift
+}
+
+// reportDrift reports the Azure resources found changed out-of-band on obj: corrected drift is recorded in an event,
+// while drift left in place is reported in the NoDrift condition as well.
+func reportDrift(recorder record.EventRecorder, obj conditions.Setter, drift *azure.Drift) {
+	resources := drift.Resources()
+	if drift.Correct {
+		conditions.Delete(obj, infrav1.NoDriftCondition)
+		if len(resources) > 0 {
+			recorder.Eventf(obj, corev1.EventTypeNormal, "DriftCorrected", "Reverted out-of-band changes to %d Azure resources: %s", len(resources), summarizeResources(resources))
+		}
+		return
+	}
+
+	if len(resources) == 0 {
+		conditions.MarkTrue(obj, infrav1.NoDriftCondition)
+		return
+	}
+	message := fmt.Sprintf("%d Azure resources were changed out-of-band: %s", len(resources), summarizeResources(resources))
+	conditions.MarkFalse(obj, infrav1.NoDriftCondition, infrav1.DriftDetectedReason, clusterv1.ConditionSeverityWarning, message)
+	recorder.Event(obj, corev1.EventTypeWarning, infrav1.DriftDetectedReason, message)
+}
+
+// ImageVersionGetter finds the latest build of the image of a machine.
+type ImageVersionGetter interface {
+	LatestVersion(ctx context.Context, image *infrav1.Image, location string, maxAge time.Duration) (current, latest string, err error)
+}
+
+// ReportImageFreshness compares the version of the image used by obj against the latest build of the same
+// Major.Minor version available in location. A newer build is reported in the OutdatedImage condition, and in a
+// warning event the first time it is found. Failing to find the latest build is only logged, as the check is advisory.
+func ReportImageFreshness(ctx context.Context, recorder record.EventRecorder, obj conditions.Setter, versions ImageVersionGetter, image *infrav1.Image, location string, maxAge time.Duration) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.ReportImageFreshness")
+	defer done()
+
+	current, latest, err := versions.LatestVersion(ctx, image, location, maxAge)
+	if err != nil {
+		log.V(2).Info("failed to check if the image is up to date", "error", err.Error())
+		return
+	}
+	if current == latest {
+		conditions.Delete(obj, infrav1.OutdatedImageCondition)
+		return
+	}
+
+	message := fmt.Sprintf("Image version %s is outdated, version %s is available", current, latest)
+	if previous := conditions.Get(obj, infrav1.OutdatedImageCondition); previous == nil || previous.Message != message {
+		recorder.Event(obj, corev1.EventTypeWarning, string(infrav1.OutdatedImageCondition), message)
+	}
+	conditions.Set(obj, &clusterv1.Condition{
+		Type:    infrav1.OutdatedImageCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.NewerImageAvailableReason,
+		Message: message,
+	})
+}
+
+// SerialLogGetter returns the last lines of the serial console log of a VM.
+type SerialLogGetter interface {
+	SerialLogTail(ctx context.Context, resourceGroup, vmName string, maxAge time.Duration) (string, error)
+}
+
+// ReportNodeNotJoined reports a machine whose VM has been running since runningSince without its node joining the
+// cluster. Once timeout has elapsed, the last lines of the serial console log of the VM are collected into the
+// NodeNotJoined condition, and in a warning event whenever they change. It returns when the machine should be checked
+// again.
+func ReportNodeNotJoined(ctx context.Context, recorder record.EventRecorder, obj conditions.Setter, logs SerialLogGetter, resourceGroup, vmName string, runningSince time.Time, timeout time.Duration) time.Duration {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.ReportNodeNotJoined")
+	defer done()
+
+	if waited := time.Since(runningSince); waited < timeout {
+		return timeout - waited
+	}
+
+	message := fmt.Sprintf("Node has not joined the cluster %s after the VM started running", timeout)
+	tail, err := logs.SerialLogTail(ctx, resourceGroup, vmName, timeout)
+	switch {
+	case err != nil:
+		log.V(2).Info("failed to retrieve the serial console log", "error", err.Error())
+		message += fmt.Sprintf(", failed to retrieve the serial console log: %s", err)
+	case tail == "":
+		message += ", the serial console log is empty"
+	default:
+		message += fmt.Sprintf(", last lines of the serial console log:\n%s", tail)
+	}
+	if previous := conditions.Get(obj, infrav1.NodeNotJoinedCondition); previous == nil || previous.Message != message {
+		recorder.Event(obj, corev1.EventTypeWarning, string(infrav1.NodeNotJoinedCondition), message)
+	}
+	conditions.Set(obj, &clusterv1.Condition{
+		Type:    infrav1.NodeNotJoinedCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.NodeJoinTimedOutReason,
+		Message: message,
+	})
+	return timeout
+}
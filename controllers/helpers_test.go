@@ -22,12 +22,14 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -35,14 +37,20 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/internal/test/mock_log"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/statussink"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	capifeature "sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -156,6 +164,14 @@ func TestGetCloudProviderConfig(t *testing.T) {
 			expectedControlPlaneConfig: backOffCloudConfig,
 			expectedWorkerNodeConfig:   backOffCloudConfig,
 		},
+		"with standard vm type": {
+			cluster:                    cluster,
+			azureCluster:               withVMType(*azureCluster, "standard"),
+			identityType:               infrav1.VMIdentityNone,
+			machinePoolFeature:         true,
+			expectedControlPlaneConfig: standardVMTypeCloudConfig,
+			expectedWorkerNodeConfig:   standardVMTypeCloudConfig,
+		},
 		"with machinepools": {
 			cluster:                    cluster,
 			azureCluster:               azureCluster,
@@ -339,11 +355,267 @@ func TestReconcileAzureSecret(t *testing.T) {
 	}
 }
 
+func TestReconcileSSHKeyPairSecret(t *testing.T) {
+	cases := map[string]struct {
+		sshKeyPair     *infrav1.SSHKeyPairSpec
+		existingSecret *corev1.Secret
+		expectedName   string
+		expectedData   map[string][]byte
+	}{
+		"should not create a secret when no SSH key pair is requested": {},
+		"should generate a secret with the default name": {
+			sshKeyPair:   &infrav1.SSHKeyPairSpec{},
+			expectedName: "testCluster-ssh-keypair",
+		},
+		"should generate a secret with a custom name": {
+			sshKeyPair:   &infrav1.SSHKeyPairSpec{SecretName: "my-keys"},
+			expectedName: "my-keys",
+		},
+		"should not replace the content of a pre-existing secret": {
+			sshKeyPair: &infrav1.SSHKeyPairSpec{SecretName: "my-keys"},
+			existingSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-keys",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"ssh-publickey": []byte("foobar"),
+				},
+			},
+			expectedName: "my-keys",
+			expectedData: map[string][]byte{
+				"ssh-publickey": []byte("foobar"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := newCluster("testCluster")
+			azureCluster := newAzureCluster("bar")
+			azureCluster.Spec.SSHKeyPair = tc.sshKeyPair
+
+			initObjects := []runtime.Object{cluster, azureCluster}
+			if tc.existingSecret != nil {
+				initObjects = append(initObjects, tc.existingSecret)
+			}
+			kubeclient := fake.NewClientBuilder().WithScheme(setupScheme(g)).WithRuntimeObjects(initObjects...).Build()
+
+			clusterScope, err := scope.NewClusterScope(context.Background(), scope.ClusterScopeParams{
+				AzureClients: scope.AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Cluster:      cluster,
+				AzureCluster: azureCluster,
+				Client:       kubeclient,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(reconcileSSHKeyPairSecret(context.Background(), kubeclient, clusterScope)).To(Succeed())
+
+			secrets := &corev1.SecretList{}
+			g.Expect(kubeclient.List(context.Background(), secrets)).To(Succeed())
+			if tc.expectedName == "" {
+				g.Expect(secrets.Items).To(BeEmpty())
+				return
+			}
+			g.Expect(secrets.Items).To(HaveLen(1))
+			found := secrets.Items[0]
+			g.Expect(found.Name).To(Equal(tc.expectedName))
+			if tc.expectedData != nil {
+				g.Expect(found.Data).To(Equal(tc.expectedData))
+				return
+			}
+			g.Expect(found.Type).To(Equal(corev1.SecretTypeSSHAuth))
+			g.Expect(found.Data).To(HaveKey(corev1.SSHAuthPrivateKey))
+			g.Expect(string(found.Data["ssh-publickey"])).To(HavePrefix("ssh-rsa "))
+			g.Expect(found.OwnerReferences).To(HaveLen(1))
+			g.Expect(found.OwnerReferences[0].Kind).To(Equal("AzureCluster"))
+		})
+	}
+}
+
+func TestReconcileSubnetCIDRBlockClaims(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := corev1.TypedLocalObjectReference{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "subnets"}
+	cluster := newCluster("testCluster")
+	azureCluster := newAzureCluster("bar")
+	azureCluster.Spec.NetworkSpec.Subnets = infrav1.Subnets{
+		{
+			SubnetClassSpec:   infrav1.SubnetClassSpec{Name: "node-subnet", Role: infrav1.SubnetNode},
+			CIDRBlockFromPool: &pool,
+		},
+		{
+			SubnetClassSpec: infrav1.SubnetClassSpec{Name: "cp-subnet", Role: infrav1.SubnetControlPlane, CIDRBlocks: []string{"10.0.0.0/16"}},
+		},
+	}
+	kubeclient := fake.NewClientBuilder().WithScheme(setupScheme(g)).WithRuntimeObjects(cluster, azureCluster).WithStatusSubresource(&ipamv1.IPAddressClaim{}).Build()
+
+	clusterScope, err := scope.NewClusterScope(context.Background(), scope.ClusterScopeParams{
+		AzureClients: scope.AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		Client:       kubeclient,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ready, err := reconcileSubnetCIDRBlockClaims(context.Background(), kubeclient, clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ready).To(BeFalse())
+
+	claims := &ipamv1.IPAddressClaimList{}
+	g.Expect(kubeclient.List(context.Background(), claims)).To(Succeed())
+	g.Expect(claims.Items).To(HaveLen(1))
+	claim := claims.Items[0]
+	g.Expect(claim.Name).To(Equal("foo-node-subnet-cidr"))
+	g.Expect(claim.Spec.PoolRef).To(Equal(pool))
+	g.Expect(claim.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "testCluster"))
+	g.Expect(claim.OwnerReferences).To(HaveLen(1))
+	g.Expect(claim.OwnerReferences[0].Kind).To(Equal("AzureCluster"))
+
+	g.Expect(kubeclient.Create(context.Background(), &ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{Name: "subnets-1", Namespace: "default"},
+		Spec:       ipamv1.IPAddressSpec{Address: "10.1.2.0", Prefix: 24, PoolRef: pool},
+	})).To(Succeed())
+	claim.Status.AddressRef.Name = "subnets-1"
+	g.Expect(kubeclient.Status().Update(context.Background(), &claim)).To(Succeed())
+
+	ready, err = reconcileSubnetCIDRBlockClaims(context.Background(), kubeclient, clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ready).To(BeTrue())
+	g.Expect(azureCluster.Spec.NetworkSpec.Subnets[0].CIDRBlocks).To(Equal([]string{"10.1.2.0/24"}))
+	g.Expect(azureCluster.Spec.NetworkSpec.Subnets[1].CIDRBlocks).To(Equal([]string{"10.0.0.0/16"}))
+}
+
+func TestReconcileNICAddressClaims(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := corev1.TypedLocalObjectReference{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "nodes"}
+	cluster := newCluster("testCluster")
+	azureCluster := newAzureCluster("bar")
+	machine := newMachine("testCluster", "my-machine")
+	azureMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-machine",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureMachineSpec{
+			NetworkInterfaces: []infrav1.NetworkInterface{{
+				SubnetName:         "node-subnet",
+				PrivateIPConfigs:   2,
+				AddressesFromPools: []corev1.TypedLocalObjectReference{pool},
+			}},
+		},
+	}
+	kubeclient := fake.NewClientBuilder().WithScheme(setupScheme(g)).WithRuntimeObjects(cluster, azureCluster, azureMachine).WithStatusSubresource(&ipamv1.IPAddressClaim{}).Build()
+
+	clusterScope, err := scope.NewClusterScope(context.Background(), scope.ClusterScopeParams{
+		AzureClients: scope.AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		Client:       kubeclient,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	machineScope := &scope.MachineScope{
+		ClusterScoper: clusterScope,
+		Machine:       machine,
+		AzureMachine:  azureMachine,
+	}
+
+	ready, err := reconcileNICAddressClaims(context.Background(), kubeclient, machineScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ready).To(BeFalse())
+
+	claim := &ipamv1.IPAddressClaim{}
+	g.Expect(kubeclient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "my-machine-nic-0-ipconfig-0"}, claim)).To(Succeed())
+	g.Expect(claim.Spec.PoolRef).To(Equal(pool))
+	g.Expect(claim.OwnerReferences).To(HaveLen(1))
+	g.Expect(claim.OwnerReferences[0].Kind).To(Equal("AzureMachine"))
+
+	g.Expect(kubeclient.Create(context.Background(), &ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{Name: "nodes-1", Namespace: "default"},
+		Spec:       ipamv1.IPAddressSpec{Address: "10.1.0.10", Prefix: 16, PoolRef: pool},
+	})).To(Succeed())
+	claim.Status.AddressRef.Name = "nodes-1"
+	g.Expect(kubeclient.Status().Update(context.Background(), claim)).To(Succeed())
+
+	ready, err = reconcileNICAddressClaims(context.Background(), kubeclient, machineScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ready).To(BeTrue())
+	nicSpecs := machineScope.NICSpecs()
+	g.Expect(nicSpecs).To(HaveLen(1))
+	nicSpec, ok := nicSpecs[0].(*networkinterfaces.NICSpec)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(nicSpec.StaticIPAddress).To(Equal("10.1.0.10"))
+	g.Expect(nicSpec.IPConfigs).To(HaveLen(2))
+	g.Expect(nicSpec.IPConfigs[1].PrivateIP).To(BeNil())
+}
+
+func TestReconcileWorkloadCloudProviderSecret(t *testing.T) {
+	cases := map[string]struct {
+		existingSecret *corev1.Secret
+		expectedData   map[string][]byte
+	}{
+		"should create the secret": {
+			expectedData: map[string][]byte{"cloud-config": []byte("new")},
+		},
+		"should update an owned secret": {
+			existingSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "azure-cloud-provider",
+					Namespace: "kube-system",
+					Labels:    map[string]string{"testCluster": string(infrav1.ResourceLifecycleOwned)},
+				},
+				Data: map[string][]byte{"cloud-config": []byte("old")},
+			},
+			expectedData: map[string][]byte{"cloud-config": []byte("new")},
+		},
+		"should not replace a user provided secret": {
+			existingSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "azure-cloud-provider",
+					Namespace: "kube-system",
+				},
+				Data: map[string][]byte{"cloud-config": []byte("user")},
+			},
+			expectedData: map[string][]byte{"cloud-config": []byte("user")},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			initObjects := []runtime.Object{}
+			if tc.existingSecret != nil {
+				initObjects = append(initObjects, tc.existingSecret)
+			}
+			workloadClient := fake.NewClientBuilder().WithScheme(setupScheme(g)).WithRuntimeObjects(initObjects...).Build()
+
+			g.Expect(reconcileWorkloadCloudProviderSecret(context.Background(), workloadClient, []byte("new"), "testCluster")).To(Succeed())
+
+			found := &corev1.Secret{}
+			g.Expect(workloadClient.Get(context.Background(), types.NamespacedName{Namespace: "kube-system", Name: "azure-cloud-provider"}, found)).To(Succeed())
+			g.Expect(found.Data).To(Equal(tc.expectedData))
+		})
+	}
+}
+
 func setupScheme(g *WithT) *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
 	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
 	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(ipamv1.AddToScheme(scheme)).To(Succeed())
 	return scheme
 }
 
@@ -432,6 +704,11 @@ func withbackOffConfig(ac infrav1.AzureCluster) *infrav1.AzureCluster {
 	return &ac
 }
 
+func withVMType(ac infrav1.AzureCluster, vmType string) *infrav1.AzureCluster {
+	ac.Spec.CloudProviderConfigOverrides = &infrav1.CloudProviderConfigOverrides{VMType: vmType}
+	return &ac
+}
+
 func newAzureClusterWithCustomVnet(location string) *infrav1.AzureCluster {
 	return &infrav1.AzureCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -607,6 +884,7 @@ const (
     "vnetResourceGroup": "custom-vnet-resource-group",
     "subnetName": "foo-node-subnet",
     "routeTableName": "foo-node-routetable",
+    "routeTableResourceGroup": "custom-vnet-resource-group",
     "loadBalancerSku": "Standard",
     "loadBalancerName": "",
     "maximumLoadBalancerRuleCount": 250,
@@ -628,6 +906,7 @@ const (
     "vnetResourceGroup": "custom-vnet-resource-group",
     "subnetName": "foo-node-subnet",
     "routeTableName": "foo-node-routetable",
+    "routeTableResourceGroup": "custom-vnet-resource-group",
     "loadBalancerSku": "Standard",
     "loadBalancerName": "",
     "maximumLoadBalancerRuleCount": 250,
@@ -733,6 +1012,27 @@ const (
     "useManagedIdentityExtension": false,
     "useInstanceMetadata": true,
     "enableVmssFlexNodes": true
+}`
+	standardVMTypeCloudConfig = `{
+    "cloud": "AzurePublicCloud",
+    "tenantId": "fooTenant",
+    "subscriptionId": "baz",
+    "aadClientId": "fooClient",
+    "aadClientSecret": "fooSecret",
+    "resourceGroup": "bar",
+    "securityGroupName": "foo-node-nsg",
+    "securityGroupResourceGroup": "bar",
+    "location": "bar",
+    "vmType": "standard",
+    "vnetName": "foo-vnet",
+    "vnetResourceGroup": "bar",
+    "subnetName": "foo-node-subnet",
+    "routeTableName": "foo-node-routetable",
+    "loadBalancerSku": "Standard",
+    "loadBalancerName": "",
+    "maximumLoadBalancerRuleCount": 250,
+    "useManagedIdentityExtension": false,
+    "useInstanceMetadata": true
 }`
 )
 
@@ -1522,3 +1822,318 @@ func TestClusterPauseChangeAndInfrastructureReady(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceIsNotExternallyManagedOrReportsStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expect      bool
+	}{
+		{
+			name:   "not externally managed",
+			expect: true,
+		},
+		{
+			name: "externally managed",
+			annotations: map[string]string{
+				clusterv1.ManagedByAnnotation: "",
+			},
+			expect: false,
+		},
+		{
+			name: "externally managed with status reporting",
+			annotations: map[string]string{
+				clusterv1.ManagedByAnnotation:                   "",
+				infrav1.ExternallyManagedReportStatusAnnotation: "true",
+			},
+			expect: true,
+		},
+		{
+			name: "externally managed with status reporting disabled",
+			annotations: map[string]string{
+				clusterv1.ManagedByAnnotation:                   "",
+				infrav1.ExternallyManagedReportStatusAnnotation: "false",
+			},
+			expect: false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			p := ResourceIsNotExternallyManagedOrReportsStatus(logr.New(nil))
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-cluster",
+					Annotations: test.annotations,
+				},
+			}
+			g.Expect(p.Create(event.CreateEvent{Object: azureCluster})).To(Equal(test.expect))
+		})
+	}
+}
+
+func TestSummarizeResources(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(summarizeResources(nil)).To(BeEmpty())
+	g.Expect(summarizeResources([]string{"a", "b"})).To(Equal("a, b"))
+
+	resources := make([]string, maxSummarizedResources+3)
+	for i := range resources {
+		resources[i] = fmt.Sprintf("r%d", i)
+	}
+	summary := summarizeResources(resources)
+	g.Expect(summary).To(HavePrefix("r0, r1, "))
+	g.Expect(summary).To(HaveSuffix("r19 and 3 more"))
+}
+
+func TestEnsureClusterIdentitySecretMoveLabel(t *testing.T) {
+	tests := []struct {
+		name        string
+		secretRef   corev1.SecretReference
+		secret      *corev1.Secret
+		expectLabel bool
+	}{
+		{
+			name:      "labels the referenced secret",
+			secretRef: corev1.SecretReference{Name: "sp-secret", Namespace: "identities"},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "sp-secret", Namespace: "identities"},
+			},
+			expectLabel: true,
+		},
+		{
+			name:      "defaults the secret namespace to the identity namespace",
+			secretRef: corev1.SecretReference{Name: "sp-secret"},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "sp-secret", Namespace: "default"},
+			},
+			expectLabel: true,
+		},
+		{
+			name:      "keeps existing labels",
+			secretRef: corev1.SecretReference{Name: "sp-secret", Namespace: "default"},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sp-secret",
+					Namespace: "default",
+					Labels:    map[string]string{"foo": "bar"},
+				},
+			},
+			expectLabel: true,
+		},
+		{
+			name:      "ignores a missing secret",
+			secretRef: corev1.SecretReference{Name: "sp-secret", Namespace: "default"},
+		},
+		{
+			name: "ignores identities without a secret",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			builder := fake.NewClientBuilder().WithScheme(setupScheme(g))
+			if tc.secret != nil {
+				builder = builder.WithObjects(tc.secret)
+			}
+			c := builder.Build()
+			identity := &infrav1.AzureClusterIdentity{
+				ObjectMeta: metav1.ObjectMeta{Name: "identity", Namespace: "default"},
+				Spec:       infrav1.AzureClusterIdentitySpec{ClientSecret: tc.secretRef},
+			}
+
+			g.Expect(ensureClusterIdentitySecretMoveLabel(context.Background(), c, identity)).To(Succeed())
+
+			if !tc.expectLabel {
+				return
+			}
+			secret := &corev1.Secret{}
+			g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(tc.secret), secret)).To(Succeed())
+			g.Expect(secret.Labels).To(HaveKey(clusterctlv1.ClusterctlMoveLabel))
+			for k, v := range tc.secret.Labels {
+				g.Expect(secret.Labels).To(HaveKeyWithValue(k, v))
+			}
+		})
+	}
+}
+
+type fakeStatusSink struct {
+	events []statussink.Event
+}
+
+func (f *fakeStatusSink) Send(_ context.Context, event statussink.Event) {
+	f.events = append(f.events, event)
+}
+
+func TestReportPhaseTransition(t *testing.T) {
+	g := NewWithT(t)
+	azureMachine := &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "my-machine"}}
+
+	// A nil sink is ignored.
+	reportPhaseTransition(context.Background(), nil, "AzureMachine", azureMachine, clusterName, statussink.PhaseProvisioning, statussink.PhaseProvisioned, "")
+
+	sink := &fakeStatusSink{}
+	reportPhaseTransition(context.Background(), sink, "AzureMachine", azureMachine, clusterName, statussink.PhaseProvisioning, statussink.PhaseProvisioning, "")
+	g.Expect(sink.events).To(BeEmpty())
+
+	reportPhaseTransition(context.Background(), sink, "AzureMachine", azureMachine, clusterName, statussink.PhaseProvisioning, statussink.PhaseFailed, "boom")
+	g.Expect(sink.events).To(HaveLen(1))
+	g.Expect(sink.events[0].Kind).To(Equal("AzureMachine"))
+	g.Expect(sink.events[0].Namespace).To(Equal("default"))
+	g.Expect(sink.events[0].Name).To(Equal("my-machine"))
+	g.Expect(sink.events[0].ClusterName).To(Equal(clusterName))
+	g.Expect(sink.events[0].Phase).To(Equal(statussink.PhaseFailed))
+	g.Expect(sink.events[0].PreviousPhase).To(Equal(statussink.PhaseProvisioning))
+	g.Expect(sink.events[0].Message).To(Equal("boom"))
+}
+
+func TestReportDrift(t *testing.T) {
+	t.Run("reported drift marks the NoDrift condition false", func(t *testing.T) {
+		g := NewWithT(t)
+		azureCluster := &infrav1.AzureCluster{}
+		recorder := record.NewFakeRecorder(1)
+
+		_, drift := withDriftCheck(context.Background(), infrav1.DriftRemediationReport)
+		g.Expect(drift.Correct).To(BeFalse())
+		drift.Record("securitygroups", "my-rg", "my-nsg")
+		reportDrift(recorder, azureCluster, drift)
+
+		g.Expect(conditions.IsFalse(azureCluster, infrav1.NoDriftCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(azureCluster, infrav1.NoDriftCondition)).To(Equal(infrav1.DriftDetectedReason))
+		g.Expect(conditions.GetMessage(azureCluster, infrav1.NoDriftCondition)).To(ContainSubstring("my-rg/my-nsg (securitygroups)"))
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(infrav1.DriftDetectedReason)))
+	})
+
+	t.Run("no drift marks the NoDrift condition true", func(t *testing.T) {
+		g := NewWithT(t)
+		azureCluster := &infrav1.AzureCluster{}
+		recorder := record.NewFakeRecorder(1)
+
+		_, drift := withDriftCheck(context.Background(), infrav1.DriftRemediationReport)
+		reportDrift(recorder, azureCluster, drift)
+
+		g.Expect(conditions.IsTrue(azureCluster, infrav1.NoDriftCondition)).To(BeTrue())
+		g.Expect(recorder.Events).To(BeEmpty())
+	})
+
+	t.Run("corrected drift is recorded in an event only", func(t *testing.T) {
+		g := NewWithT(t)
+		azureCluster := &infrav1.AzureCluster{}
+		conditions.MarkTrue(azureCluster, infrav1.NoDriftCondition)
+		recorder := record.NewFakeRecorder(1)
+
+		_, drift := withDriftCheck(context.Background(), "")
+		g.Expect(drift.Correct).To(BeTrue())
+		drift.Record("loadbalancers", "my-rg", "my-lb")
+		reportDrift(recorder, azureCluster, drift)
+
+		g.Expect(conditions.Has(azureCluster, infrav1.NoDriftCondition)).To(BeFalse())
+		g.Expect(recorder.Events).To(Receive(ContainSubstring("DriftCorrected")))
+	})
+}
+
+type fakeImageVersionGetter struct {
+	current, latest string
+	err             error
+}
+
+func (f fakeImageVersionGetter) LatestVersion(_ context.Context, _ *infrav1.Image, _ string, _ time.Duration) (string, string, error) {
+	return f.current, f.latest, f.err
+}
+
+type fakeSerialLogGetter struct {
+	tail string
+	err  error
+}
+
+func (f fakeSerialLogGetter) SerialLogTail(_ context.Context, _, _ string, _ time.Duration) (string, error) {
+	return f.tail, f.err
+}
+
+func TestReportNodeNotJoined(t *testing.T) {
+	t.Run("VM running for less than the timeout is checked again once it elapses", func(t *testing.T) {
+		g := NewWithT(t)
+		azureMachine := &infrav1.AzureMachine{}
+		recorder := record.NewFakeRecorder(1)
+
+		requeueAfter := ReportNodeNotJoined(context.Background(), recorder, azureMachine, fakeSerialLogGetter{tail: "booting"}, "my-rg", "my-vm", time.Now().Add(-5*time.Minute), 15*time.Minute)
+
+		g.Expect(requeueAfter).To(BeNumerically("~", 10*time.Minute, time.Minute))
+		g.Expect(conditions.Has(azureMachine, infrav1.NodeNotJoinedCondition)).To(BeFalse())
+		g.Expect(recorder.Events).To(BeEmpty())
+	})
+
+	t.Run("timed out node join reports the serial console log once", func(t *testing.T) {
+		g := NewWithT(t)
+		azureMachine := &infrav1.AzureMachine{}
+		recorder := record.NewFakeRecorder(2)
+		logs := fakeSerialLogGetter{tail: "cloud-init[1234]: failed to run kubeadm join"}
+
+		ReportNodeNotJoined(context.Background(), recorder, azureMachine, logs, "my-rg", "my-vm", time.Now().Add(-time.Hour), 15*time.Minute)
+		requeueAfter := ReportNodeNotJoined(context.Background(), recorder, azureMachine, logs, "my-rg", "my-vm", time.Now().Add(-time.Hour), 15*time.Minute)
+
+		g.Expect(requeueAfter).To(Equal(15 * time.Minute))
+		g.Expect(conditions.IsTrue(azureMachine, infrav1.NodeNotJoinedCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(azureMachine, infrav1.NodeNotJoinedCondition)).To(Equal(infrav1.NodeJoinTimedOutReason))
+		g.Expect(conditions.GetMessage(azureMachine, infrav1.NodeNotJoinedCondition)).To(Equal("Node has not joined the cluster 15m0s after the VM started running, last lines of the serial console log:\ncloud-init[1234]: failed to run kubeadm join"))
+		g.Expect(recorder.Events).To(HaveLen(1))
+	})
+
+	t.Run("failure to retrieve the serial console log is reported in the condition", func(t *testing.T) {
+		g := NewWithT(t)
+		azureMachine := &infrav1.AzureMachine{}
+		recorder := record.NewFakeRecorder(1)
+
+		ReportNodeNotJoined(context.Background(), recorder, azureMachine, fakeSerialLogGetter{err: errors.New("boom")}, "my-rg", "my-vm", time.Now().Add(-time.Hour), 15*time.Minute)
+
+		g.Expect(conditions.IsTrue(azureMachine, infrav1.NodeNotJoinedCondition)).To(BeTrue())
+		g.Expect(conditions.GetMessage(azureMachine, infrav1.NodeNotJoinedCondition)).To(Equal("Node has not joined the cluster 15m0s after the VM started running, failed to retrieve the serial console log: boom"))
+		g.Expect(recorder.Events).To(HaveLen(1))
+	})
+}
+
+func TestReportImageFreshness(t *testing.T) {
+	t.Run("newer build marks the OutdatedImage condition true once", func(t *testing.T) {
+		g := NewWithT(t)
+		azureMachine := &infrav1.AzureMachine{}
+		recorder := record.NewFakeRecorder(2)
+		versions := fakeImageVersionGetter{current: "1.28.3", latest: "1.28.4"}
+
+		ReportImageFreshness(context.Background(), recorder, azureMachine, versions, &infrav1.Image{}, "eastus", time.Hour)
+		ReportImageFreshness(context.Background(), recorder, azureMachine, versions, &infrav1.Image{}, "eastus", time.Hour)
+
+		g.Expect(conditions.IsTrue(azureMachine, infrav1.OutdatedImageCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(azureMachine, infrav1.OutdatedImageCondition)).To(Equal(infrav1.NewerImageAvailableReason))
+		g.Expect(conditions.GetMessage(azureMachine, infrav1.OutdatedImageCondition)).To(Equal("Image version 1.28.3 is outdated, version 1.28.4 is available"))
+		g.Expect(recorder.Events).To(HaveLen(1))
+	})
+
+	t.Run("up to date image removes the OutdatedImage condition", func(t *testing.T) {
+		g := NewWithT(t)
+		azureMachine := &infrav1.AzureMachine{}
+		conditions.MarkTrue(azureMachine, infrav1.OutdatedImageCondition)
+		recorder := record.NewFakeRecorder(1)
+
+		ReportImageFreshness(context.Background(), recorder, azureMachine, fakeImageVersionGetter{current: "1.28.4", latest: "1.28.4"}, &infrav1.Image{}, "eastus", time.Hour)
+
+		g.Expect(conditions.Has(azureMachine, infrav1.OutdatedImageCondition)).To(BeFalse())
+		g.Expect(recorder.Events).To(BeEmpty())
+	})
+
+	t.Run("failure to find the latest build leaves the condition unchanged", func(t *testing.T) {
+		g := NewWithT(t)
+		azureMachine := &infrav1.AzureMachine{}
+		conditions.MarkTrue(azureMachine, infrav1.OutdatedImageCondition)
+		recorder := record.NewFakeRecorder(1)
+
+		ReportImageFreshness(context.Background(), recorder, azureMachine, fakeImageVersionGetter{err: errors.New("boom")}, &infrav1.Image{}, "eastus", time.Hour)
+
+		g.Expect(conditions.IsTrue(azureMachine, infrav1.OutdatedImageCondition)).To(BeTrue())
+		g.Expect(recorder.Events).To(BeEmpty())
+	})
+}
@@ -103,6 +103,12 @@ type (
 		// The primary interface will be the first networkInterface specified (index 0) in the list.
 		// +optional
 		NetworkInterfaces []infrav1.NetworkInterface `json:"networkInterfaces,omitempty"`
+
+		// NetworkSecurityGroupID is the resource ID of an existing network security group associated with the network
+		// interfaces of the scale set instances, in addition to the security group of their subnet. This allows the
+		// machine pools sharing a subnet to be segmented further. The security group is not managed by CAPZ.
+		// +optional
+		NetworkSecurityGroupID string `json:"networkSecurityGroupID,omitempty"`
 	}
 
 	// AzureMachinePoolSpec defines the desired state of AzureMachinePool.
@@ -163,9 +169,31 @@ type (
 		// +optional
 		NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
 
+		// NodeDeletionTimeout is how long the controller will attempt to delete the Kubernetes node of an
+		// AzureMachinePoolMachine once its VMSS instance is deleted, measured from the deletion of the
+		// AzureMachinePoolMachine. A duration of 0 retries the deletion indefinitely.
+		// Defaults to 10 seconds.
+		// +optional
+		NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
+
 		// OrchestrationMode specifies the orchestration mode for the Virtual Machine Scale Set
 		// +kubebuilder:default=Uniform
 		OrchestrationMode infrav1.OrchestrationModeType `json:"orchestrationMode,omitempty"`
+
+		// WarmPool keeps instances of the scale set provisioned and deallocated in addition to the replicas of the
+		// machine pool, and starts them on scale up so that new nodes join the cluster faster. Deallocated instances are
+		// not charged for compute, but their disks are still charged.
+		// The warm pool is only supported with the Uniform orchestration mode.
+		// +optional
+		WarmPool *AzureMachinePoolWarmPool `json:"warmPool,omitempty"`
+	}
+
+	// AzureMachinePoolWarmPool defines the warm pool of an AzureMachinePool.
+	AzureMachinePoolWarmPool struct {
+		// Size is the number of deallocated instances to keep ready to start. Setting it to 0 deletes the instances of
+		// the warm pool.
+		// +kubebuilder:validation:Minimum=0
+		Size int32 `json:"size"`
 	}
 
 	// AzureMachinePoolDeploymentStrategyType is the type of deployment strategy employed to rollout a new version of
@@ -308,6 +336,22 @@ type (
 		// next reconciliation loop.
 		// +optional
 		LongRunningOperationStates infrav1.Futures `json:"longRunningOperationStates,omitempty"`
+
+		// WarmPool is the status of the warm pool of the AzureMachinePool.
+		// +optional
+		WarmPool *AzureMachinePoolWarmPoolStatus `json:"warmPool,omitempty"`
+	}
+
+	// AzureMachinePoolWarmPoolStatus provides status information for the warm pool of an AzureMachinePool.
+	AzureMachinePoolWarmPoolStatus struct {
+		// InstanceIDs are the IDs of the VMSS instances in the warm pool. These instances are not replicas of the
+		// machine pool and have no AzureMachinePoolMachine.
+		// +optional
+		InstanceIDs []string `json:"instanceIDs,omitempty"`
+
+		// ReadyInstances is the number of instances of the warm pool which are deallocated and ready to start.
+		// +optional
+		ReadyInstances int32 `json:"readyInstances,omitempty"`
 	}
 
 	// AzureMachinePoolInstanceStatus provides status information for each instance in the VMSS.
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/blang/semver"
@@ -29,30 +30,57 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
+	webhookutils "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
 	capifeature "sigs.k8s.io/cluster-api/feature"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-// SetupAzureMachinePoolWebhookWithManager sets up and registers the webhook with the manager.
-func SetupAzureMachinePoolWebhookWithManager(mgr ctrl.Manager) error {
-	ampw := &azureMachinePoolWebhook{Client: mgr.GetClient()}
+// SetupAzureMachinePoolWebhookWithManager sets up and registers the webhook with the manager. The VM sizes of the
+// machine pools are only checked against the VM sizes available in their locations when checkLocations is not nil.
+func SetupAzureMachinePoolWebhookWithManager(mgr ctrl.Manager, checkLocations infrav1.LocationChecker) error {
+	ampw := &azureMachinePoolWebhook{Client: mgr.GetClient(), checkLocations: checkLocations}
+	if err := webhookutils.RegisterDefaulterWithWarnings(mgr, &AzureMachinePool{}, ampw, azureMachinePoolDeprecationWarnings); err != nil {
+		return err
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&AzureMachinePool{}).
-		WithDefaulter(ampw).
 		WithValidator(ampw).
 		Complete()
 }
 
+// azureMachinePoolDeprecationWarnings returns the admission warnings for the deprecated fields set in an
+// AzureMachinePool.
+func azureMachinePoolDeprecationWarnings(obj runtime.Object) admission.Warnings {
+	amp, ok := obj.(*AzureMachinePool)
+	if !ok {
+		return nil
+	}
+	var warnings admission.Warnings
+	if amp.Spec.RoleAssignmentName != "" {
+		warnings = append(warnings, infrav1.DeprecatedFieldWarning(field.NewPath("spec", "roleAssignmentName"), "spec.systemAssignedIdentityRole.name"))
+	}
+	templatePath := field.NewPath("spec", "template")
+	if amp.Spec.Template.SubnetName != "" {
+		warnings = append(warnings, infrav1.DeprecatedFieldWarning(templatePath.Child("subnetName"), "spec.template.networkInterfaces[].subnetName"))
+	}
+	if amp.Spec.Template.AcceleratedNetworking != nil {
+		warnings = append(warnings, infrav1.DeprecatedFieldWarning(templatePath.Child("acceleratedNetworking"), "spec.template.networkInterfaces[].acceleratedNetworking"))
+	}
+	return append(warnings, infrav1.ImageDeprecationWarnings(amp.Spec.Template.Image, templatePath.Child("image"))...)
+}
+
 // +kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachinepool,mutating=true,failurePolicy=fail,groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepools,verbs=create;update,versions=v1beta1,name=default.azuremachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 // azureMachinePoolWebhook implements a validating and defaulting webhook for AzureMachinePool.
 type azureMachinePoolWebhook struct {
-	Client client.Client
+	Client         client.Client
+	checkLocations infrav1.LocationChecker
 }
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
@@ -80,7 +108,13 @@ func (ampw *azureMachinePoolWebhook) ValidateCreate(ctx context.Context, obj run
 			"can be set only if the MachinePool feature flag is enabled",
 		)
 	}
-	return nil, amp.Validate(nil, ampw.Client)
+	if errs := infrav1.ValidateWindowsFeatureGate(amp.Spec.Template.OSDisk.OSType, field.NewPath("spec", "template", "osDisk", "osType")); len(errs) > 0 {
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("AzureMachinePool").GroupKind(), amp.Name, errs)
+	}
+	if err := amp.Validate(nil, ampw.Client); err != nil {
+		return nil, err
+	}
+	return ampw.validateVMSize(ctx, amp)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -89,7 +123,49 @@ func (ampw *azureMachinePoolWebhook) ValidateUpdate(ctx context.Context, oldObj,
 	if !ok {
 		return nil, apierrors.NewBadRequest("expected an AzureMachinePool")
 	}
-	return nil, amp.Validate(oldObj, ampw.Client)
+	if err := amp.Validate(oldObj, ampw.Client); err != nil {
+		return nil, err
+	}
+	if old, ok := oldObj.(*AzureMachinePool); ok && reflect.DeepEqual(old.Spec.Template, amp.Spec.Template) {
+		return nil, nil
+	}
+	return ampw.validateVMSize(ctx, amp)
+}
+
+// validateVMSize checks that the VM size of a machine pool is available in its location and supports the features of
+// its template, when enabled. The VM size is not checked when the machine pool has no cluster name label yet or its
+// cluster is not an AzureCluster.
+func (ampw *azureMachinePoolWebhook) validateVMSize(ctx context.Context, amp *AzureMachinePool) (admission.Warnings, error) {
+	if ampw.checkLocations == nil {
+		return nil, nil
+	}
+	cluster, err := infrav1.GetOwnerAzureCluster(ctx, ampw.Client, amp)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("failed to get the AzureCluster of the machine pool, VM size %s was not validated: %v", amp.Spec.Template.VMSize, err)}, nil
+	}
+	if cluster == nil {
+		return nil, nil
+	}
+	location := amp.Spec.Location
+	if location == "" {
+		location = cluster.Spec.Location
+	}
+	warnings, fieldErr := infrav1.ValidateVMSizeAvailability(ctx, ampw.checkLocations, cluster, location, amp.Spec.Template.VMSize, field.NewPath("spec", "template", "vmSize"))
+	if fieldErr != nil {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind("AzureMachinePool").GroupKind(), amp.Name, field.ErrorList{fieldErr})
+	}
+	features := infrav1.VMSizeFeatures{
+		OSDisk:            amp.Spec.Template.OSDisk,
+		DataDisks:         amp.Spec.Template.DataDisks,
+		SecurityProfile:   amp.Spec.Template.SecurityProfile,
+		NetworkInterfaces: amp.Spec.Template.NetworkInterfaces,
+	}
+	capabilityWarnings, errs := infrav1.ValidateVMSizeCapabilities(ctx, ampw.checkLocations, cluster, location, amp.Spec.Template.VMSize, features, field.NewPath("spec", "template"))
+	warnings = append(warnings, capabilityWarnings...)
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind("AzureMachinePool").GroupKind(), amp.Name, errs)
+	}
+	return warnings, nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -108,8 +184,15 @@ func (amp *AzureMachinePool) Validate(old runtime.Object, client client.Client)
 		amp.ValidateOrchestrationMode(client),
 		amp.ValidateStrategy(),
 		amp.ValidateSystemAssignedIdentity(old),
+		amp.ValidateLocation(old),
+		amp.ValidateNetworkSecurityGroupImmutable(old),
 		amp.ValidateSystemAssignedIdentityRole,
 		amp.ValidateNetwork,
+		amp.ValidateWriteAccelerator,
+		amp.ValidateEtcdDataDisk,
+		amp.ValidateOSDiskSource,
+		amp.ValidateOSDiskZoneRedundancy,
+		amp.ValidateWarmPool,
 	}
 
 	var errs []error
@@ -127,6 +210,91 @@ func (amp *AzureMachinePool) ValidateNetwork() error {
 	if (amp.Spec.Template.NetworkInterfaces != nil) && len(amp.Spec.Template.NetworkInterfaces) > 0 && amp.Spec.Template.SubnetName != "" {
 		return errors.New("cannot set both NetworkInterfaces and machine SubnetName")
 	}
+	for i, nic := range amp.Spec.Template.NetworkInterfaces {
+		if len(nic.AddressesFromPools) > 0 {
+			return field.Forbidden(field.NewPath("template", "networkInterfaces").Index(i).Child("addressesFromPools"), "static addresses from IP address pools are not supported by machine pools")
+		}
+	}
+	if id := amp.Spec.Template.NetworkSecurityGroupID; id != "" {
+		resourceID, err := azureutil.ParseResourceID(id)
+		if err != nil || !strings.EqualFold(resourceID.ResourceType.String(), "Microsoft.Network/networkSecurityGroups") {
+			return field.Invalid(field.NewPath("template", "networkSecurityGroupID"), id, "must be the resource ID of a network security group")
+		}
+	}
+	return nil
+}
+
+// ValidateNetworkSecurityGroupImmutable validates that the network security group of an AzureMachinePool is not
+// changed, since the network profile of an existing scale set is not updated.
+func (amp *AzureMachinePool) ValidateNetworkSecurityGroupImmutable(old runtime.Object) func() error {
+	return func() error {
+		if old == nil {
+			return nil
+		}
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+		if err := webhookutils.ValidateImmutable(field.NewPath("spec", "template", "networkSecurityGroupID"),
+			oldMachinePool.Spec.Template.NetworkSecurityGroupID, amp.Spec.Template.NetworkSecurityGroupID); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+// ValidateWriteAccelerator validates the write accelerator settings of the disks of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateWriteAccelerator() error {
+	template := amp.Spec.Template
+	if errs := infrav1.ValidateWriteAccelerator(template.VMSize, template.OSDisk, template.DataDisks, field.NewPath("osDisk"), field.NewPath("dataDisks")); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateEtcdDataDisk validates that no data disk of an AzureMachinePool is designated as the etcd data disk, since
+// machine pools can't run control plane machines.
+func (amp *AzureMachinePool) ValidateEtcdDataDisk() error {
+	for i, disk := range amp.Spec.Template.DataDisks {
+		if ptr.Deref(disk.Etcd, false) {
+			return field.Invalid(field.NewPath("dataDisks").Index(i).Child("etcd"), true, "etcd data disks are not supported on AzureMachinePools")
+		}
+	}
+	return nil
+}
+
+// ValidateOSDiskSource validates that the OS disk of an AzureMachinePool is not created from a snapshot or a restore
+// point, since scale sets can only create their OS disks from an image.
+func (amp *AzureMachinePool) ValidateOSDiskSource() error {
+	if amp.Spec.Template.OSDisk.Source != nil {
+		return field.Forbidden(field.NewPath("osDisk", "source"), "OS disk sources are not supported on AzureMachinePools")
+	}
+	return nil
+}
+
+// ValidateOSDiskZoneRedundancy validates the zone redundancy of the OS disk of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateOSDiskZoneRedundancy() error {
+	if errs := infrav1.ValidateOSDiskZoneRedundancy(amp.Spec.Template.OSDisk, field.NewPath("osDisk")); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+	return nil
+}
+
+// ValidateWarmPool validates that the warm pool of an AzureMachinePool is only used with the Uniform orchestration
+// mode, whose instances are started and deallocated individually, and not with Spot VMs, which may not be available
+// when the warm pool is started.
+func (amp *AzureMachinePool) ValidateWarmPool() error {
+	if amp.Spec.WarmPool == nil {
+		return nil
+	}
+	if amp.Spec.OrchestrationMode == infrav1.FlexibleOrchestrationMode {
+		return field.Forbidden(field.NewPath("warmPool"), "the warm pool is only supported with the Uniform orchestration mode")
+	}
+	if amp.Spec.Template.SpotVMOptions != nil {
+		return field.Forbidden(field.NewPath("warmPool"), "the warm pool is not supported with Spot VMs")
+	}
 	return nil
 }
 
@@ -228,6 +396,24 @@ func (amp *AzureMachinePool) ValidateSystemAssignedIdentity(old runtime.Object)
 	}
 }
 
+// ValidateLocation validates that the location of the scale set does not change once it is set.
+func (amp *AzureMachinePool) ValidateLocation(old runtime.Object) func() error {
+	return func() error {
+		if old == nil {
+			return nil
+		}
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+		if err := webhookutils.ValidateImmutable(field.NewPath("spec", "location"), oldMachinePool.Spec.Location, amp.Spec.Location); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
 // ValidateSystemAssignedIdentityRole validates the scope and roleDefinitionID for the system-assigned identity.
 func (amp *AzureMachinePool) ValidateSystemAssignedIdentityRole() error {
 	var allErrs field.ErrorList
@@ -31,6 +31,7 @@ import (
 	"golang.org/x/crypto/ssh"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	utilfeature "k8s.io/component-base/featuregate/testing"
@@ -41,6 +42,8 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	capifeature "sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 var (
@@ -128,6 +131,34 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 			amp:     createMachinePoolWithSSHPublicKey("invalid ssh key"),
 			wantErr: true,
 		},
+		{
+			name:    "azuremachinepool with a network security group",
+			amp:     createMachinePoolWithNetworkSecurityGroup("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Network/networkSecurityGroups/dmz-nsg"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with an invalid network security group ID",
+			amp:     createMachinePoolWithNetworkSecurityGroup("dmz-nsg"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with the ID of another resource type as network security group",
+			amp:     createMachinePoolWithNetworkSecurityGroup("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet"),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with addresses from ip address pools",
+			amp: func() *AzureMachinePool {
+				amp := getKnownValidAzureMachinePool()
+				amp.Spec.Template.NetworkInterfaces = []infrav1.NetworkInterface{{
+					SubnetName:         "node-subnet",
+					PrivateIPConfigs:   1,
+					AddressesFromPools: []corev1.TypedLocalObjectReference{{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "nodes"}},
+				}}
+				return amp
+			}(),
+			wantErr: true,
+		},
 		{
 			name:    "azuremachinepool with wrong terminate notification",
 			amp:     createMachinePoolWithSharedImage("SUB123", "RG123", "NAME123", "GALLERY1", "1.0.0", ptr.To(35)),
@@ -237,6 +268,42 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 			ownerNotFound: true,
 			wantErr:       true,
 		},
+		{
+			name:    "azuremachinepool with etcd data disk",
+			amp:     createMachinePoolWithDataDisks([]infrav1.DataDisk{{NameSuffix: "etcddisk", DiskSizeGB: 256, Lun: ptr.To[int32](0), Etcd: ptr.To(true)}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with an os disk created from a snapshot",
+			amp:     createMachinePoolWithOSDiskSource(&infrav1.OSDiskSource{SnapshotID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot")}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with a zone-redundant premium os disk",
+			amp:     createMachinePoolWithZoneRedundantOSDisk("Premium_LRS"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with a zone-redundant standard HDD os disk",
+			amp:     createMachinePoolWithZoneRedundantOSDisk("Standard_LRS"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with a warm pool",
+			amp:     createMachinePoolWithWarmPool(infrav1.UniformOrchestrationMode, nil),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with a warm pool and Flexible orchestration mode",
+			amp:     createMachinePoolWithWarmPool(infrav1.FlexibleOrchestrationMode, nil),
+			version: "v1.26.0",
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with a warm pool of Spot VMs",
+			amp:     createMachinePoolWithWarmPool(infrav1.UniformOrchestrationMode, &infrav1.SpotVMOptions{}),
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -327,6 +394,12 @@ func TestAzureMachinePool_ValidateUpdate(t *testing.T) {
 			amp:     createMachinePoolWithSSHPublicKey("invalid ssh key"),
 			wantErr: true,
 		},
+		{
+			name:    "azuremachinepool network security group is immutable",
+			oldAMP:  createMachinePoolWithNetworkSecurityGroup("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Network/networkSecurityGroups/dmz-nsg"),
+			amp:     createMachinePoolWithNetworkSecurityGroup("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Network/networkSecurityGroups/internal-nsg"),
+			wantErr: true,
+		},
 		{
 			name:    "azuremachinepool with system-assigned identity, and role unchanged",
 			oldAMP:  createMachinePoolWithSystemAssignedIdentity("30a757d8-fcf0-4c8b-acf0-9253a7e093ea"),
@@ -381,6 +454,29 @@ func TestAzureMachinePool_ValidateUpdate(t *testing.T) {
 			amp:     createMachinePoolWithNetworkConfig("subnet", []infrav1.NetworkInterface{{SubnetName: "testSubnet2"}}),
 			wantErr: true,
 		},
+		{
+			name:    "azuremachinepool with location unchanged",
+			oldAMP:  createMachinePoolWithLocation("westus2"),
+			amp:     createMachinePoolWithLocation("westus2"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with location changed",
+			oldAMP:  createMachinePoolWithLocation("westus2"),
+			amp:     createMachinePoolWithLocation("eastus"),
+			wantErr: true,
+		},
+		{
+			name:   "azuremachinepool with vm size and additional tags changed",
+			oldAMP: createMachinePoolWithLocation("westus2"),
+			amp: func() *AzureMachinePool {
+				amp := createMachinePoolWithLocation("westus2")
+				amp.Spec.Template.VMSize = "Standard_D4s_v3"
+				amp.Spec.AdditionalTags = infrav1.Tags{"team": "platform"}
+				return amp
+			}(),
+			wantErr: false,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -486,6 +582,107 @@ func TestAzureMachinePool_Default(t *testing.T) {
 	g.Expect(emptyTest.amp.Spec.SystemAssignedIdentityRole.DefinitionID).To(Equal(fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", fakeSubscriptionID, infrav1.ContributorRoleID)))
 }
 
+// fakeLocationChecker is an infrav1.LocationChecker returning fixed VM sizes and VM size capabilities.
+type fakeLocationChecker struct {
+	vmSizes      map[string][]string
+	capabilities map[string]*infrav1.VMSizeCapabilities
+}
+
+func (c fakeLocationChecker) Locations(_ context.Context, _ *infrav1.AzureCluster) ([]string, error) {
+	return nil, nil
+}
+
+func (c fakeLocationChecker) VMSizes(_ context.Context, _ *infrav1.AzureCluster, location string) ([]string, error) {
+	return c.vmSizes[location], nil
+}
+
+func (c fakeLocationChecker) VMSizeCapabilities(_ context.Context, _ *infrav1.AzureCluster, _, vmSize string) (*infrav1.VMSizeCapabilities, error) {
+	return c.capabilities[vmSize], nil
+}
+
+func TestAzureMachinePoolWebhook_ValidateVMSize(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{Kind: "AzureCluster", Name: "test-cluster"},
+			},
+		},
+		&infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec:       infrav1.AzureClusterSpec{AzureClusterClassSpec: infrav1.AzureClusterClassSpec{Location: "eastus"}},
+		},
+	).Build()
+	checker := fakeLocationChecker{
+		vmSizes: map[string][]string{
+			"eastus":  {"Standard_D2s_v3"},
+			"westus2": {"Standard_D4s_v3"},
+		},
+		capabilities: map[string]*infrav1.VMSizeCapabilities{
+			"Standard_D2s_v3": {},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		vmSize      string
+		location    string
+		ephemeralOS bool
+		wantErr     string
+	}{
+		{
+			name:   "VM size available in the location of the cluster",
+			vmSize: "Standard_D2s_v3",
+		},
+		{
+			name:    "VM size not available in the location of the cluster",
+			vmSize:  "Standard_D4s_v3",
+			wantErr: "VM size is not available in location eastus, the sizes of the same series available there are: Standard_D2s_v3",
+		},
+		{
+			name:     "VM size available in the location of the machine pool",
+			vmSize:   "Standard_D4s_v3",
+			location: "westus2",
+		},
+		{
+			name:        "feature not supported by the VM size",
+			vmSize:      "Standard_D2s_v3",
+			ephemeralOS: true,
+			wantErr:     "spec.template.osDisk.diffDiskSettings: Invalid value: \"Local\": VM size Standard_D2s_v3 does not support ephemeral OS disks",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ampw := &azureMachinePoolWebhook{Client: cli, checkLocations: checker}
+			amp := &AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-pool",
+					Namespace: "default",
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+				},
+				Spec: AzureMachinePoolSpec{
+					Location: tc.location,
+					Template: AzureMachinePoolMachineTemplate{VMSize: tc.vmSize},
+				},
+			}
+			if tc.ephemeralOS {
+				amp.Spec.Template.OSDisk.DiffDiskSettings = &infrav1.DiffDiskSettings{Option: "Local"}
+			}
+			_, err := ampw.validateVMSize(context.Background(), amp)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.wantErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func createMachinePoolWithMarketPlaceImage(publisher, offer, sku, version string, terminateNotificationTimeout *int) *AzureMachinePool {
 	image := infrav1.Image{
 		Marketplace: &infrav1.AzureMarketplaceImage{
@@ -531,6 +728,33 @@ func createMachinePoolWithSharedImage(subscriptionID, resourceGroup, name, galle
 	}
 }
 
+func createMachinePoolWithDataDisks(dataDisks []infrav1.DataDisk) *AzureMachinePool {
+	amp := getKnownValidAzureMachinePool()
+	amp.Spec.Template.DataDisks = dataDisks
+	return amp
+}
+
+func createMachinePoolWithOSDiskSource(source *infrav1.OSDiskSource) *AzureMachinePool {
+	amp := getKnownValidAzureMachinePool()
+	amp.Spec.Template.OSDisk.Source = source
+	return amp
+}
+
+func createMachinePoolWithWarmPool(mode infrav1.OrchestrationModeType, spotVMOptions *infrav1.SpotVMOptions) *AzureMachinePool {
+	amp := getKnownValidAzureMachinePool()
+	amp.Spec.OrchestrationMode = mode
+	amp.Spec.Template.SpotVMOptions = spotVMOptions
+	amp.Spec.WarmPool = &AzureMachinePoolWarmPool{Size: 2}
+	return amp
+}
+
+func createMachinePoolWithZoneRedundantOSDisk(storageAccountType string) *AzureMachinePool {
+	amp := getKnownValidAzureMachinePool()
+	amp.Spec.Template.OSDisk.ZoneRedundant = ptr.To(true)
+	amp.Spec.Template.OSDisk.ManagedDisk = &infrav1.ManagedDiskParameters{StorageAccountType: storageAccountType}
+	return amp
+}
+
 func createMachinePoolWithNetworkConfig(subnetName string, interfaces []infrav1.NetworkInterface) *AzureMachinePool {
 	return &AzureMachinePool{
 		Spec: AzureMachinePoolSpec{
@@ -629,6 +853,14 @@ func createMachinePoolWithStrategy(strategy AzureMachinePoolDeploymentStrategy)
 	}
 }
 
+func createMachinePoolWithLocation(location string) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Location: location,
+		},
+	}
+}
+
 func createMachinePoolWithOrchestrationMode(mode armcompute.OrchestrationMode) *AzureMachinePool {
 	return &AzureMachinePool{
 		Spec: AzureMachinePoolSpec{
@@ -666,6 +898,12 @@ func TestAzureMachinePool_ValidateCreateFailure(t *testing.T) {
 	}
 }
 
+func createMachinePoolWithNetworkSecurityGroup(id string) *AzureMachinePool {
+	amp := getKnownValidAzureMachinePool()
+	amp.Spec.Template.NetworkSecurityGroupID = id
+	return amp
+}
+
 func getKnownValidAzureMachinePool() *AzureMachinePool {
 	image := infrav1.Image{
 		Marketplace: &infrav1.AzureMarketplaceImage{
@@ -700,3 +938,20 @@ func getKnownValidAzureMachinePool() *AzureMachinePool {
 		},
 	}
 }
+
+func TestAzureMachinePoolDeprecationWarnings(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(azureMachinePoolDeprecationWarnings(getKnownValidAzureMachinePool())).To(BeEmpty())
+
+	amp := getKnownValidAzureMachinePool()
+	amp.Spec.RoleAssignmentName = "role"
+	amp.Spec.Template.SubnetName = "subnet"
+	amp.Spec.Template.AcceleratedNetworking = ptr.To(true)
+	amp.Spec.Template.Image = &infrav1.Image{SharedGallery: &infrav1.AzureSharedGalleryImage{Name: "image"}}
+	g.Expect(azureMachinePoolDeprecationWarnings(amp)).To(Equal(admission.Warnings{
+		"spec.roleAssignmentName is deprecated and will be removed in v1beta2, use spec.systemAssignedIdentityRole.name instead",
+		"spec.template.subnetName is deprecated and will be removed in v1beta2, use spec.template.networkInterfaces[].subnetName instead",
+		"spec.template.acceleratedNetworking is deprecated and will be removed in v1beta2, use spec.template.networkInterfaces[].acceleratedNetworking instead",
+		"spec.template.image.sharedGallery is deprecated and will be removed in v1beta2, use spec.template.image.computeGallery instead",
+	}))
+}
@@ -27,6 +27,11 @@ import (
 const (
 	// AzureMachinePoolMachineFinalizer is used to ensure deletion of dependencies (nodes, infra).
 	AzureMachinePoolMachineFinalizer = "azuremachinepoolmachine.infrastructure.cluster.x-k8s.io"
+
+	// ProtectFromScaleInAnnotation can be set to "true" on an AzureMachinePoolMachine to protect its instance from
+	// scale-in. Protected instances are never selected for deletion when the machine pool scales down, and Azure does
+	// not remove them when the capacity of the scale set is lowered. Protection is only supported for Uniform scale sets.
+	ProtectFromScaleInAnnotation = "azuremachinepoolmachine.infrastructure.cluster.x-k8s.io/protect-from-scale-in"
 )
 
 type (
@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	capifeature "sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/topology"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// AzureMachinePoolTemplateImmutableMsg ...
+const (
+	AzureMachinePoolTemplateImmutableMsg                      = "AzureMachinePoolTemplate spec.template.spec field is immutable. Please create new resource instead. ref doc: https://cluster-api.sigs.k8s.io/tasks/updating-machine-templates.html"
+	AzureMachinePoolTemplateRoleAssignmentNameMsg             = "AzureMachinePoolTemplate spec.template.spec.roleAssignmentName field can't be set"
+	AzureMachinePoolTemplateSystemAssignedIdentityRoleNameMsg = "AzureMachinePoolTemplate spec.template.spec.systemAssignedIdentityRole.name field can't be set"
+)
+
+// SetupAzureMachinePoolTemplateWebhookWithManager sets up and registers the webhook with the manager.
+func SetupAzureMachinePoolTemplateWebhookWithManager(mgr ctrl.Manager) error {
+	amptw := &azureMachinePoolTemplateWebhook{}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&AzureMachinePoolTemplate{}).
+		WithDefaulter(amptw).
+		WithValidator(amptw).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachinepooltemplate,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepooltemplates,versions=v1beta1,name=default.azuremachinepooltemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachinepooltemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepooltemplates,versions=v1beta1,name=validation.azuremachinepooltemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// azureMachinePoolTemplateWebhook implements a validating and defaulting webhook for AzureMachinePoolTemplate.
+type azureMachinePoolTemplateWebhook struct{}
+
+var _ webhook.CustomDefaulter = &azureMachinePoolTemplateWebhook{}
+var _ webhook.CustomValidator = &azureMachinePoolTemplateWebhook{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type.
+func (amptw *azureMachinePoolTemplateWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	t, ok := obj.(*AzureMachinePoolTemplate)
+	if !ok {
+		return apierrors.NewBadRequest("expected an AzureMachinePoolTemplate")
+	}
+	// Templates carry no subscription or cluster context, so only the defaults that
+	// don't depend on them are applied here. The rest are set on the AzureMachinePool
+	// created from the template.
+	amp := t.machinePool()
+	if err := amp.SetDefaultSSHPublicKey(); err != nil {
+		ctrl.Log.WithName("SetDefault").Error(err, "SetDefaultSSHPublicKey failed")
+	}
+	amp.SetDiagnosticsDefaults()
+	amp.SetNetworkInterfacesDefaults()
+	t.Spec.Template.Spec = amp.Spec
+	return nil
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (amptw *azureMachinePoolTemplateWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	t, ok := obj.(*AzureMachinePoolTemplate)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureMachinePoolTemplate")
+	}
+	// NOTE: AzureMachinePoolTemplate is behind MachinePool feature gate flag; the webhook
+	// must prevent creating new objects in case the feature flag is disabled.
+	if !feature.Gates.Enabled(capifeature.MachinePool) {
+		return nil, field.Forbidden(
+			field.NewPath("spec"),
+			"can be set only if the MachinePool feature flag is enabled",
+		)
+	}
+
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec", "template", "spec")
+	spec := t.Spec.Template.Spec
+
+	if spec.RoleAssignmentName != "" {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("roleAssignmentName"), spec.RoleAssignmentName, AzureMachinePoolTemplateRoleAssignmentNameMsg))
+	}
+	if spec.SystemAssignedIdentityRole != nil && spec.SystemAssignedIdentityRole.Name != "" {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("systemAssignedIdentityRole", "name"), spec.SystemAssignedIdentityRole.Name, AzureMachinePoolTemplateSystemAssignedIdentityRoleNameMsg))
+	}
+
+	amp := t.machinePool()
+	validators := []func() error{
+		amp.ValidateImage,
+		amp.ValidateTerminateNotificationTimeout,
+		amp.ValidateSSHKey,
+		amp.ValidateUserAssignedIdentity,
+		amp.ValidateDiagnostics,
+		amp.ValidateStrategy(),
+		amp.ValidateNetwork,
+		amp.ValidateWriteAccelerator,
+		amp.ValidateEtcdDataDisk,
+	}
+	for _, validator := range validators {
+		if err := validator(); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath, spec, err.Error()))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GroupVersion.WithKind("AzureMachinePoolTemplate").GroupKind(), t.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (amptw *azureMachinePoolTemplateWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	old, ok := oldObj.(*AzureMachinePoolTemplate)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureMachinePoolTemplate")
+	}
+	t, ok := newObj.(*AzureMachinePoolTemplate)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureMachinePoolTemplate")
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a admission.Request inside context: %v", err))
+	}
+
+	var allErrs field.ErrorList
+	if !topology.ShouldSkipImmutabilityChecks(req, t) &&
+		!reflect.DeepEqual(t.Spec.Template.Spec, old.Spec.Template.Spec) {
+		// The old object may predate defaults added since it was stored, so default it
+		// before comparing. The ssh key is copied over explicitly, otherwise Default()
+		// would generate a new one.
+		if old.Spec.Template.Spec.Template.SSHPublicKey == "" {
+			old.Spec.Template.Spec.Template.SSHPublicKey = t.Spec.Template.Spec.Template.SSHPublicKey
+		}
+
+		if err := amptw.Default(ctx, old); err != nil {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("AzureMachinePoolTemplate"), t, fmt.Sprintf("Unable to apply defaults: %v", err)),
+			)
+		}
+
+		if !reflect.DeepEqual(t.Spec.Template.Spec, old.Spec.Template.Spec) {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("AzureMachinePoolTemplate", "spec", "template", "spec"), t, AzureMachinePoolTemplateImmutableMsg),
+			)
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GroupVersion.WithKind("AzureMachinePoolTemplate").GroupKind(), t.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (amptw *azureMachinePoolTemplateWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// machinePool returns an AzureMachinePool carrying a copy of the template spec, so the
+// AzureMachinePool defaulting and validation helpers can be reused for the template.
+func (t *AzureMachinePoolTemplate) machinePool() *AzureMachinePool {
+	return &AzureMachinePool{
+		ObjectMeta: t.ObjectMeta,
+		Spec:       *t.Spec.Template.Spec.DeepCopy(),
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	capifeature "sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestAzureMachinePoolTemplate_ValidateCreate(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+
+	tests := []struct {
+		name     string
+		template *AzureMachinePoolTemplate
+		wantErr  bool
+	}{
+		{
+			name:     "valid template",
+			template: createAzureMachinePoolTemplateFromPool(getKnownValidAzureMachinePoolWithoutRoleName()),
+			wantErr:  false,
+		},
+		{
+			name:     "template with a role assignment name",
+			template: createAzureMachinePoolTemplateFromPool(getKnownValidAzureMachinePool()),
+			wantErr:  true,
+		},
+		{
+			name: "template with deprecated role assignment name",
+			template: func() *AzureMachinePoolTemplate {
+				amp := getKnownValidAzureMachinePoolWithoutRoleName()
+				amp.Spec.RoleAssignmentName = "role"
+				return createAzureMachinePoolTemplateFromPool(amp)
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "template with an invalid ssh key",
+			template: func() *AzureMachinePoolTemplate {
+				amp := getKnownValidAzureMachinePoolWithoutRoleName()
+				amp.Spec.Template.SSHPublicKey = "invalid ssh key"
+				return createAzureMachinePoolTemplateFromPool(amp)
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "template with both subnet name and network interfaces",
+			template: func() *AzureMachinePoolTemplate {
+				amp := getKnownValidAzureMachinePoolWithoutRoleName()
+				amp.Spec.Template.SubnetName = "subnet"
+				amp.Spec.Template.NetworkInterfaces = []infrav1.NetworkInterface{{SubnetName: "subnet", PrivateIPConfigs: 1}}
+				return createAzureMachinePoolTemplateFromPool(amp)
+			}(),
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amptw := &azureMachinePoolTemplateWebhook{}
+			_, err := amptw.ValidateCreate(context.Background(), tc.template)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePoolTemplate_ValidateCreateFeatureGateDisabled(t *testing.T) {
+	g := NewWithT(t)
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, false)()
+
+	amptw := &azureMachinePoolTemplateWebhook{}
+	_, err := amptw.ValidateCreate(context.Background(), createAzureMachinePoolTemplateFromPool(getKnownValidAzureMachinePoolWithoutRoleName()))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestAzureMachinePoolTemplate_ValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldTemplate *AzureMachinePoolTemplate
+		template    *AzureMachinePoolTemplate
+		wantErr     bool
+	}{
+		{
+			name:        "unchanged spec",
+			oldTemplate: createAzureMachinePoolTemplateFromPool(getKnownValidAzureMachinePoolWithoutRoleName()),
+			template:    createAzureMachinePoolTemplateFromPool(getKnownValidAzureMachinePoolWithoutRoleName()),
+			wantErr:     false,
+		},
+		{
+			name:        "changed vm size",
+			oldTemplate: createAzureMachinePoolTemplateFromPool(getKnownValidAzureMachinePoolWithoutRoleName()),
+			template: func() *AzureMachinePoolTemplate {
+				amp := getKnownValidAzureMachinePoolWithoutRoleName()
+				amp.Spec.Template.VMSize = "Standard_D4s_v3"
+				return createAzureMachinePoolTemplateFromPool(amp)
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "old object missing defaults",
+			oldTemplate: func() *AzureMachinePoolTemplate {
+				amp := getKnownValidAzureMachinePoolWithoutRoleName()
+				amp.Spec.Template.SSHPublicKey = ""
+				return createAzureMachinePoolTemplateFromPool(amp)
+			}(),
+			template: func() *AzureMachinePoolTemplate {
+				amp := getKnownValidAzureMachinePoolWithoutRoleName()
+				amp.SetDiagnosticsDefaults()
+				amp.SetNetworkInterfacesDefaults()
+				return createAzureMachinePoolTemplateFromPool(amp)
+			}(),
+			wantErr: false,
+		},
+		{
+			name:        "changed metadata",
+			oldTemplate: createAzureMachinePoolTemplateFromPool(getKnownValidAzureMachinePoolWithoutRoleName()),
+			template: func() *AzureMachinePoolTemplate {
+				template := createAzureMachinePoolTemplateFromPool(getKnownValidAzureMachinePoolWithoutRoleName())
+				template.Spec.Template.ObjectMeta.Labels = map[string]string{"foo": "bar"}
+				return template
+			}(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: ptr.To(false)}})
+			amptw := &azureMachinePoolTemplateWebhook{}
+			_, err := amptw.ValidateUpdate(ctx, tc.oldTemplate, tc.template)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePoolTemplate_Default(t *testing.T) {
+	g := NewWithT(t)
+
+	amp := getKnownValidAzureMachinePoolWithoutRoleName()
+	amp.Spec.Template.SSHPublicKey = ""
+	template := createAzureMachinePoolTemplateFromPool(amp)
+
+	amptw := &azureMachinePoolTemplateWebhook{}
+	g.Expect(amptw.Default(context.Background(), template)).To(Succeed())
+	g.Expect(template.Spec.Template.Spec.Template.SSHPublicKey).NotTo(BeEmpty())
+	g.Expect(template.Spec.Template.Spec.Template.Diagnostics).NotTo(BeNil())
+	g.Expect(template.Spec.Template.Spec.Template.Diagnostics.Boot.StorageAccountType).To(Equal(infrav1.ManagedDiagnosticsStorage))
+	g.Expect(template.Spec.Template.Spec.SystemAssignedIdentityRole.Name).To(BeEmpty())
+}
+
+func getKnownValidAzureMachinePoolWithoutRoleName() *AzureMachinePool {
+	amp := getKnownValidAzureMachinePool()
+	amp.Spec.SystemAssignedIdentityRole.Name = ""
+	return amp
+}
+
+func createAzureMachinePoolTemplateFromPool(amp *AzureMachinePool) *AzureMachinePoolTemplate {
+	return &AzureMachinePoolTemplate{
+		Spec: AzureMachinePoolTemplateSpec{
+			Template: AzureMachinePoolTemplateResource{
+				Spec: amp.Spec,
+			},
+		},
+	}
+}
@@ -28,6 +28,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/imagefreshness"
 	infracontroller "sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
@@ -58,6 +59,8 @@ type (
 		Recorder                      record.EventRecorder
 		ReconcileTimeout              time.Duration
 		WatchFilterValue              string
+		ImageVersionCheckInterval     time.Duration
+		ImageFreshnessCheckInterval   time.Duration
 		createAzureMachinePoolService azureMachinePoolServiceCreator
 	}
 
@@ -231,12 +234,15 @@ func (ampr *AzureMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.
 		return reconcile.Result{}, err
 	}
 
+	ctx = tele.WithLogValues(ctx, "cluster", cluster.Name, "resourceGroup", clusterScope.ResourceGroup())
+
 	// Create the machine pool scope
 	machinePoolScope, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
-		Client:           ampr.Client,
-		MachinePool:      machinePool,
-		AzureMachinePool: azMachinePool,
-		ClusterScope:     clusterScope,
+		Client:                    ampr.Client,
+		MachinePool:               machinePool,
+		AzureMachinePool:          azMachinePool,
+		ClusterScope:              clusterScope,
+		ImageVersionCheckInterval: ampr.ImageVersionCheckInterval,
 	})
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create scope")
@@ -320,6 +326,7 @@ func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, mac
 		var reconcileError azure.ReconcileError
 		if errors.As(err, &reconcileError) {
 			if reconcileError.IsTerminal() {
+				ampr.Recorder.Eventf(machinePoolScope.AzureMachinePool, corev1.EventTypeWarning, "ReconcileError", errors.Wrap(err, "failed to reconcile AzureMachinePool").Error())
 				log.Error(err, "failed to reconcile AzureMachinePool", "name", machinePoolScope.Name())
 				return reconcile.Result{}, nil
 			}
@@ -353,9 +360,27 @@ func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, mac
 		}, nil
 	}
 
+	if ampr.ImageFreshnessCheckInterval > 0 {
+		ampr.reportImageFreshness(ctx, machinePoolScope)
+		return reconcile.Result{RequeueAfter: ampr.ImageFreshnessCheckInterval}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
+// reportImageFreshness reports whether a newer build of the image of the scale set is available.
+func (ampr *AzureMachinePoolReconciler) reportImageFreshness(ctx context.Context, machinePoolScope *scope.MachinePoolScope) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachinePoolReconciler.reportImageFreshness")
+	defer done()
+
+	svc, err := imagefreshness.New(machinePoolScope)
+	if err != nil {
+		log.V(2).Info("failed to create image freshness service", "error", err.Error())
+		return
+	}
+	infracontroller.ReportImageFreshness(ctx, ampr.Recorder, machinePoolScope.AzureMachinePool, svc, machinePoolScope.AzureMachinePool.Status.Image, machinePoolScope.Location(), ampr.ImageFreshnessCheckInterval)
+}
+
 func (ampr *AzureMachinePoolReconciler) reconcilePause(ctx context.Context, machinePoolScope *scope.MachinePoolScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachinePoolReconciler.reconcilePause")
 	defer done()
@@ -25,6 +25,8 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/warmpools"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -49,11 +51,16 @@ func newAzureMachinePoolService(machinePoolScope *scope.MachinePoolScope) (*azur
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create a scalesets service")
 	}
+	warmPoolsSvc, err := warmpools.New(machinePoolScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create a warmpools service")
+	}
 
 	return &azureMachinePoolService{
 		scope: machinePoolScope,
 		services: []azure.ServiceReconciler{
 			scaleSetsSvc,
+			warmPoolsSvc,
 			roleAssignmentsSvc,
 		},
 		skuCache: cache,
@@ -62,7 +69,7 @@ func newAzureMachinePoolService(machinePoolScope *scope.MachinePoolScope) (*azur
 
 // Reconcile reconciles all the services in pre determined order.
 func (s *azureMachinePoolService) Reconcile(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachinePoolService.Reconcile")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachinePoolService.Reconcile")
 	defer done()
 
 	// Ensure that the deprecated networking field values have been migrated to the new NetworkInterfaces field.
@@ -72,8 +79,10 @@ func (s *azureMachinePoolService) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed defaulting subnet name")
 	}
 
+	timings := &reconciler.ServiceTimings{}
+	defer timings.Log(log, "reconciled AzureMachinePool services")
 	for _, service := range s.services {
-		if err := service.Reconcile(ctx); err != nil {
+		if err := timings.Time(service.Name(), func() error { return service.Reconcile(ctx) }); err != nil {
 			return errors.Wrapf(err, "failed to reconcile AzureMachinePool service %s", service.Name())
 		}
 	}
@@ -101,12 +110,14 @@ func (s *azureMachinePoolService) Pause(ctx context.Context) error {
 
 // Delete reconciles all the services in pre determined order.
 func (s *azureMachinePoolService) Delete(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachinePoolService.Delete")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachinePoolService.Delete")
 	defer done()
 
+	timings := &reconciler.ServiceTimings{}
+	defer timings.Log(log, "deleted AzureMachinePool services")
 	// Delete services in reverse order of creation.
 	for i := len(s.services) - 1; i >= 0; i-- {
-		if err := s.services[i].Delete(ctx); err != nil {
+		if err := timings.Time(s.services[i].Name(), func() error { return s.services[i].Delete(ctx) }); err != nil {
 			return errors.Wrapf(err, "failed to delete AzureMachinePool service %s", s.services[i].Name())
 		}
 	}
@@ -73,6 +73,9 @@ func TestAzureMachinePoolServiceReconcile(t *testing.T) {
 			svcThreeMock := mock_azure.NewMockServiceReconciler(mockCtrl)
 
 			tc.expect(svcOneMock.EXPECT(), svcTwoMock.EXPECT(), svcThreeMock.EXPECT())
+			svcOneMock.EXPECT().Name().Return("one").AnyTimes()
+			svcTwoMock.EXPECT().Name().Return("two").AnyTimes()
+			svcThreeMock.EXPECT().Name().Return("three").AnyTimes()
 
 			s := &azureMachinePoolService{
 				scope: &scope.MachinePoolScope{
@@ -216,6 +219,9 @@ func TestAzureMachinePoolServiceDelete(t *testing.T) {
 			svcThreeMock := mock_azure.NewMockServiceReconciler(mockCtrl)
 
 			tc.expect(svcOneMock.EXPECT(), svcTwoMock.EXPECT(), svcThreeMock.EXPECT())
+			svcOneMock.EXPECT().Name().Return("one").AnyTimes()
+			svcTwoMock.EXPECT().Name().Return("two").AnyTimes()
+			svcThreeMock.EXPECT().Name().Return("three").AnyTimes()
 
 			s := &azureMachinePoolService{
 				scope: &scope.MachinePoolScope{
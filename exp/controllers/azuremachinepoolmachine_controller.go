@@ -209,6 +209,8 @@ func (ampmr *AzureMachinePoolMachineController) Reconcile(ctx context.Context, r
 		return reconcile.Result{}, err
 	}
 
+	ctx = tele.WithLogValues(ctx, "cluster", cluster.Name, "resourceGroup", clusterScope.ResourceGroup())
+
 	// Create the machine pool scope
 	machineScope, err := scope.NewMachinePoolMachineScope(scope.MachinePoolMachineScopeParams{
 		Client:                  ampmr.Client,
@@ -320,9 +322,10 @@ func (ampmr *AzureMachinePoolMachineController) reconcileDelete(ctx context.Cont
 	}
 
 	// deleting a single machine
-	// 1) drain the node (TODO: @devigned)
+	// 1) drain the node
 	// 2) after drained, delete the infrastructure
-	// 3) remove finalizer
+	// 3) delete the node
+	// 4) remove finalizer
 
 	ampms, err := ampmr.reconcilerFactory(machineScope)
 	if err != nil {
@@ -406,6 +409,10 @@ func (r *azureMachinePoolMachineReconciler) Delete(ctx context.Context) error {
 		return errors.Wrap(err, "failed to reconcile scalesetVMs")
 	}
 
+	if err := r.Scope.DeleteNode(ctx); err != nil {
+		return errors.Wrap(err, "failed to delete the node of the scalesetVM")
+	}
+
 	// no long running operation, so we are finished deleting the resource. Remove the finalizer.
 	controllerutil.RemoveFinalizer(r.Scope.AzureMachinePoolMachine, infrav1exp.AzureMachinePoolMachineFinalizer)
 
@@ -37,12 +37,34 @@ const (
 	// GA: v1.8
 	AKS featuregate.Feature = "AKS"
 
+	// Bastion is the feature gate for Azure Bastion hosts of AzureClusters.
+	// owner: @pluralsh
+	// beta: v1.12
+	Bastion featuregate.Feature = "Bastion"
+
+	// NATGateway is the feature gate for the NAT gateways of AzureCluster node subnets. When disabled, nodes of public
+	// clusters egress through a node outbound load balancer instead.
+	// owner: @pluralsh
+	// beta: v1.12
+	NATGateway featuregate.Feature = "NATGateway"
+
+	// Windows is the feature gate for Windows AzureMachines and AzureMachinePools.
+	// owner: @pluralsh
+	// beta: v1.12
+	Windows featuregate.Feature = "Windows"
+
 	// AKSResourceHealth is the feature gate for reporting Azure Resource Health
 	// on AKS managed clusters.
 	// owner: @nojnhuh
 	// alpha: v1.7
 	AKSResourceHealth featuregate.Feature = "AKSResourceHealth"
 
+	// ActivityLogDiagnostics is the feature gate for describing failed Azure operations with the status message of
+	// their event in the Azure Activity Log.
+	// owner: @pluralsh
+	// alpha: v1.12
+	ActivityLogDiagnostics featuregate.Feature = "ActivityLogDiagnostics"
+
 	// EdgeZone is the feature gate for creating clusters on public MEC.
 	// owner: @upxinxin
 	// alpha: v1.8
@@ -57,7 +79,11 @@ func init() {
 // To add a new feature, define a key for it above and add it here.
 var defaultCAPZFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	// Every feature should be initiated here:
-	AKS:               {Default: true, PreRelease: featuregate.GA, LockToDefault: true}, // Remove in 1.12
-	AKSResourceHealth: {Default: false, PreRelease: featuregate.Alpha},
-	EdgeZone:          {Default: false, PreRelease: featuregate.Alpha},
+	AKS:                    {Default: true, PreRelease: featuregate.GA},
+	AKSResourceHealth:      {Default: false, PreRelease: featuregate.Alpha},
+	ActivityLogDiagnostics: {Default: false, PreRelease: featuregate.Alpha},
+	Bastion:                {Default: true, PreRelease: featuregate.Beta},
+	EdgeZone:               {Default: false, PreRelease: featuregate.Alpha},
+	NATGateway:             {Default: true, PreRelease: featuregate.Beta},
+	Windows:                {Default: true, PreRelease: featuregate.Beta},
 }
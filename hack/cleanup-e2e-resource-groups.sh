@@ -0,0 +1,52 @@
+#!/bin/bash
+
+# Copyright 2024 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Deletes the resource groups leaked by e2e runs: the groups tagged with the jobName of the e2e job whose
+# creationTimestamp tag, set by hack/ensure-tags.sh, is older than MAX_AGE_HOURS.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+REPO_ROOT=$(dirname "${BASH_SOURCE[0]}")/..
+cd "${REPO_ROOT}" || exit 1
+
+JOB_NAME="${JOB_NAME:-"cluster-api-provider-azure-e2e"}"
+MAX_AGE_HOURS="${MAX_AGE_HOURS:-6}"
+DRY_RUN="${DRY_RUN:-false}"
+
+# shellcheck source=hack/ensure-azcli.sh
+source "${REPO_ROOT}/hack/ensure-azcli.sh"
+
+# The timestamps are in RFC-3339 format, so they can be compared as strings.
+CUTOFF="$(date -u -d "-${MAX_AGE_HOURS} hours" '+%Y-%m-%dT%H:%M:%SZ')"
+
+groups="$(az group list --tag "jobName=${JOB_NAME}" \
+  --query "[?tags.creationTimestamp != null && tags.creationTimestamp < '${CUTOFF}'].name" --output tsv)"
+
+if [[ -z "${groups}" ]]; then
+  echo "no resource groups of ${JOB_NAME} created before ${CUTOFF}"
+  exit 0
+fi
+
+for group in ${groups}; do
+  if [[ "${DRY_RUN}" == "true" ]]; then
+    echo "would delete resource group ${group}"
+    continue
+  fi
+  echo "deleting resource group ${group}"
+  az group delete --name "${group}" --yes --no-wait
+done
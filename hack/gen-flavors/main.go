@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// gen-flavors renders the cluster templates of the flavors defined in pkg/flavors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/flavors"
+)
+
+func main() {
+	var outputDir, flavor string
+	flag.StringVar(&outputDir, "output-dir", ".", "Directory to write the cluster templates to.")
+	flag.StringVar(&flavor, "flavor", "", "Name of the flavor to render. All flavors are rendered if empty.")
+	flag.Parse()
+
+	all := flavors.All()
+	selected := all
+	if flavor != "" {
+		selected = nil
+		for _, f := range all {
+			if f.Name == flavor {
+				selected = append(selected, f)
+			}
+		}
+		if len(selected) == 0 {
+			fmt.Fprintf(os.Stderr, "unknown flavor %q\n", flavor)
+			os.Exit(1)
+		}
+	}
+
+	if err := flavors.Write(outputDir, selected...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
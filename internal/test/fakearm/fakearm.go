@@ -0,0 +1,275 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fakearm implements an in-memory Azure Resource Manager server, so that the services layer
+// can be tested end to end against real SDK clients without an Azure subscription.
+//
+// The server stores the resources it receives with PUT and PATCH requests, and serves them back with
+// GET requests until they are removed with a DELETE request. Every operation completes synchronously and
+// every stored resource reports a Succeeded provisioning state.
+package fakearm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// CloudName is the name of the cloud to set as the Azure environment of the scopes whose clients
+// should target the server registered with Register.
+const CloudName = "FakeARMCloud"
+
+// Server is an in-memory ARM server.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	resources map[string]map[string]interface{}
+	errors    map[string]responseError
+	requests  []string
+}
+
+type responseError struct {
+	statusCode int
+	code       string
+}
+
+// NewServer starts a new ARM server. Callers should call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		resources: map[string]map[string]interface{}{},
+		errors:    map[string]responseError{},
+	}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Register makes the clients of scopes using the CloudName Azure environment target s.
+func (s *Server) Register() {
+	azure.RegisterCloud(CloudName, s.ClientOptions())
+}
+
+// ClientOptions returns the options of SDK clients that target s.
+func (s *Server) ClientOptions() policy.ClientOptions {
+	return policy.ClientOptions{
+		Cloud: cloud.Configuration{
+			ActiveDirectoryAuthorityHost: s.URL,
+			Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+				cloud.ResourceManager: {
+					Audience: s.URL,
+					Endpoint: s.URL,
+				},
+			},
+		},
+		Transport: s.Client(),
+	}
+}
+
+// Credential returns a token credential accepted by s.
+func (s *Server) Credential() azcore.TokenCredential {
+	return credential{}
+}
+
+// Authorizer returns an azure.Authorizer for subscriptionID whose clients target s once it is registered.
+func (s *Server) Authorizer(subscriptionID string) azure.Authorizer {
+	return authorizer{subscriptionID: subscriptionID, baseURI: s.URL}
+}
+
+// Put stores resource under the resource ID id, as if it had been created in Azure beforehand.
+func (s *Server) Put(id string, resource interface{}) error {
+	raw, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.store(id, raw, false)
+	return err
+}
+
+// Get returns the JSON representation of the resource with the resource ID id, if it exists.
+func (s *Server) Get(id string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resource, ok := s.resources[strings.ToLower(id)]
+	if !ok {
+		return nil, false
+	}
+	raw, _ := json.Marshal(resource)
+	return raw, true
+}
+
+// InjectError makes the next request with method to the resource ID id fail with statusCode and the ARM error code.
+func (s *Server) InjectError(method, id string, statusCode int, code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors[method+" "+strings.ToLower(id)] = responseError{statusCode: statusCode, code: code}
+}
+
+// Requests returns the method and resource ID of every request served so far, in order.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := strings.TrimSuffix(r.URL.Path, "/")
+	key := strings.ToLower(id)
+	s.requests = append(s.requests, r.Method+" "+id)
+
+	if injected, ok := s.errors[r.Method+" "+key]; ok {
+		delete(s.errors, r.Method+" "+key)
+		writeError(w, injected.statusCode, injected.code, fmt.Sprintf("injected error for %s %s", r.Method, id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		resource, ok := s.resources[key]
+		if !ok {
+			writeError(w, http.StatusNotFound, "ResourceNotFound", fmt.Sprintf("The resource '%s' was not found.", id))
+			return
+		}
+		writeJSON(w, http.StatusOK, resource)
+	case http.MethodPut, http.MethodPatch:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequestContent", err.Error())
+			return
+		}
+		resource, err := s.store(id, body, r.Method == http.MethodPatch)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequestContent", err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, resource)
+	case http.MethodDelete:
+		if _, ok := s.resources[key]; !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		for k := range s.resources {
+			if k == key || strings.HasPrefix(k, key+"/") {
+				delete(s.resources, k)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("%s is not supported by the fake ARM server", r.Method))
+	}
+}
+
+// store saves body as the resource with the resource ID id, merged into the existing resource when merge is set.
+// It must be called with the lock held.
+func (s *Server) store(id string, body []byte, merge bool) (map[string]interface{}, error) {
+	key := strings.ToLower(id)
+	resource := map[string]interface{}{}
+	if existing, ok := s.resources[key]; ok && merge {
+		resource = existing
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &resource); err != nil {
+			return nil, err
+		}
+	}
+
+	resource["id"] = id
+	resource["name"] = path.Base(id)
+	resource["type"] = resourceType(id)
+	properties, ok := resource["properties"].(map[string]interface{})
+	if !ok {
+		properties = map[string]interface{}{}
+		resource["properties"] = properties
+	}
+	properties["provisioningState"] = "Succeeded"
+
+	s.resources[key] = resource
+	return resource, nil
+}
+
+// resourceType returns the ARM resource type of a resource ID, e.g. Microsoft.Network/virtualNetworks/subnets.
+func resourceType(id string) string {
+	segments := strings.Split(strings.Trim(id, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if !strings.EqualFold(segments[i], "providers") || i+1 >= len(segments) {
+			continue
+		}
+		types := []string{segments[i+1]}
+		for j := i + 2; j < len(segments); j += 2 {
+			types = append(types, segments[j])
+		}
+		return strings.Join(types, "/")
+	}
+	if len(segments) == 4 && strings.EqualFold(segments[2], "resourceGroups") {
+		return "Microsoft.Resources/resourceGroups"
+	}
+	return ""
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	w.Header().Set("x-ms-error-code", code)
+	writeJSON(w, statusCode, map[string]interface{}{
+		"error": map[string]string{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+// credential is a token credential returning a static token.
+type credential struct{}
+
+// GetToken returns a static token valid for an hour.
+func (credential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "fake-token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// authorizer authorizes the clients of a subscription of the fake ARM server.
+type authorizer struct {
+	subscriptionID string
+	baseURI        string
+}
+
+func (a authorizer) SubscriptionID() string        { return a.subscriptionID }
+func (a authorizer) ClientID() string              { return "fake-client-id" }
+func (a authorizer) ClientSecret() string          { return "fake-client-secret" }
+func (a authorizer) CloudEnvironment() string      { return CloudName }
+func (a authorizer) TenantID() string              { return "fake-tenant-id" }
+func (a authorizer) BaseURI() string               { return a.baseURI }
+func (a authorizer) HashKey() string               { return CloudName + "/" + a.subscriptionID }
+func (a authorizer) Token() azcore.TokenCredential { return credential{} }
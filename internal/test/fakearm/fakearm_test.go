@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakearm
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestResourceType(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{
+			id:   "/subscriptions/123/resourceGroups/my-rg",
+			want: "Microsoft.Resources/resourceGroups",
+		},
+		{
+			id:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+			want: "Microsoft.Network/virtualNetworks",
+		},
+		{
+			id:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+			want: "Microsoft.Network/virtualNetworks/subnets",
+		},
+		{
+			id:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm/providers/Microsoft.Resources/tags/default",
+			want: "Microsoft.Resources/tags",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.id, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(resourceType(tc.id)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestStoreMergesPatches(t *testing.T) {
+	g := NewWithT(t)
+
+	s := &Server{resources: map[string]map[string]interface{}{}}
+	id := "/subscriptions/123/resourceGroups/my-rg"
+
+	_, err := s.store(id, []byte(`{"location":"westus2","tags":{"a":"b"}}`), false)
+	g.Expect(err).NotTo(HaveOccurred())
+	resource, err := s.store(id, []byte(`{"tags":{"c":"d"}}`), true)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(resource).To(HaveKeyWithValue("location", "westus2"))
+	g.Expect(resource).To(HaveKeyWithValue("tags", map[string]interface{}{"c": "d"}))
+	g.Expect(resource).To(HaveKeyWithValue("name", "my-rg"))
+	g.Expect(resource["properties"]).To(HaveKeyWithValue("provisioningState", "Succeeded"))
+}
@@ -22,13 +22,16 @@ import (
 	"fmt"
 	_ "net/http/pprof"
 	"os"
+	goruntime "runtime"
 	"time"
 
 	// +kubebuilder:scaffold:imports
 	aadpodv1 "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity/v1"
 	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20220701"
 	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/spf13/pflag"
+	"golang.org/x/net/http/httpproxy"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -39,17 +42,27 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/marketplaceagreements"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/orphanedresources"
 	"sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	infrav1controllersexp "sigs.k8s.io/cluster-api-provider-azure/exp/controllers"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/adaptive"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/health"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/ot"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/skucatalog"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/statussink"
+	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/version"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	capifeature "sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -73,6 +86,7 @@ func init() {
 	_ = infrav1exp.AddToScheme(scheme)
 	_ = clusterv1.AddToScheme(scheme)
 	_ = expv1.AddToScheme(scheme)
+	_ = ipamv1.AddToScheme(scheme)
 	_ = kubeadmv1.AddToScheme(scheme)
 	_ = asoresourcesv1.AddToScheme(scheme)
 	_ = asonetworkv1.AddToScheme(scheme)
@@ -92,26 +106,63 @@ func init() {
 }
 
 var (
-	metricsAddr                        string
-	enableLeaderElection               bool
-	leaderElectionNamespace            string
-	leaderElectionLeaseDuration        time.Duration
-	leaderElectionRenewDeadline        time.Duration
-	leaderElectionRetryPeriod          time.Duration
-	watchNamespace                     string
-	watchFilterValue                   string
-	profilerAddress                    string
-	azureClusterConcurrency            int
-	azureMachineConcurrency            int
-	azureMachinePoolConcurrency        int
-	azureMachinePoolMachineConcurrency int
-	debouncingTimer                    time.Duration
-	syncPeriod                         time.Duration
-	healthAddr                         string
-	webhookPort                        int
-	webhookCertDir                     string
-	reconcileTimeout                   time.Duration
-	enableTracing                      bool
+	metricsAddr                         string
+	enableLeaderElection                bool
+	leaderElectionNamespace             string
+	leaderElectionLeaseDuration         time.Duration
+	leaderElectionRenewDeadline         time.Duration
+	leaderElectionRetryPeriod           time.Duration
+	leaderElectionReleaseOnCancel       bool
+	watchNamespaces                     []string
+	watchFilterValue                    string
+	profilerAddress                     string
+	contentionProfiling                 bool
+	azureClusterConcurrency             int
+	azureMachineConcurrency             int
+	azureMachinePoolConcurrency         int
+	azureMachinePoolMachineConcurrency  int
+	azureManagedClusterConcurrency      int
+	azureManagedControlPlaneConcurrency int
+	azureManagedMachinePoolConcurrency  int
+	rateLimiterBaseDelay                time.Duration
+	rateLimiterMaxDelay                 time.Duration
+	rateLimiterQPS                      float64
+	rateLimiterBurst                    int
+	debouncingTimer                     time.Duration
+	syncPeriod                          time.Duration
+	adaptiveRequeueMinInterval          time.Duration
+	adaptiveRequeueMaxInterval          time.Duration
+	healthAddr                          string
+	credentialsCheckInterval            time.Duration
+	galleryImageVersionCheckInterval    time.Duration
+	imageFreshnessCheckInterval         time.Duration
+	nodeJoinTimeout                     time.Duration
+	backendPoolCheckInterval            time.Duration
+	acceptMarketplaceTerms              bool
+	apiServerHealthCheckInterval        time.Duration
+	resourceInventoryInterval           time.Duration
+	orphanedResourcesSweepInterval      time.Duration
+	orphanedResourcesSweepMinAge        time.Duration
+	webhookPort                         int
+	webhookCertDir                      string
+	reconcileTimeout                    time.Duration
+	enableTracing                       bool
+	tracingEndpoint                     string
+	userAgentSuffix                     string
+	partnerID                           string
+	azureProxyURL                       string
+	azureNoProxy                        string
+	azureCABundle                       string
+	propagateProxyToNodes               bool
+	validateIdentityCredentials         bool
+	validateLocations                   bool
+	azureJSONRollout                    bool
+	serveSKUCatalog                     bool
+	defaultLocations                    map[string]string
+	clusterQuota                        scope.ClusterQuota
+	statusWebhookURL                    string
+	statusWebhookTokenFile              string
+	armAuditLogFile                     string
 )
 
 // InitFlags initializes all command-line flags.
@@ -158,11 +209,18 @@ func InitFlags(fs *pflag.FlagSet) {
 		"Duration the LeaderElector clients should wait between tries of actions (duration string)",
 	)
 
-	fs.StringVar(
-		&watchNamespace,
+	fs.BoolVar(
+		&leaderElectionReleaseOnCancel,
+		"leader-elect-release-on-cancel",
+		true,
+		"Release the leader election lease when the controller manager stops, so that another replica can take over without waiting for the lease to expire.",
+	)
+
+	fs.StringSliceVar(
+		&watchNamespaces,
 		"namespace",
-		"",
-		"Namespace that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.",
+		nil,
+		"Comma-separated list of namespaces that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.",
 	)
 
 	fs.StringVar(
@@ -179,6 +237,13 @@ func InitFlags(fs *pflag.FlagSet) {
 		"Bind address to expose the pprof profiler (e.g. localhost:6060)",
 	)
 
+	fs.BoolVar(
+		&contentionProfiling,
+		"contention-profiling",
+		false,
+		"Enable block and mutex profiling, if the pprof profiler is exposed with --profiler-address. Adds some overhead to every lock and channel operation.",
+	)
+
 	fs.IntVar(&azureClusterConcurrency,
 		"azurecluster-concurrency",
 		10,
@@ -201,6 +266,45 @@ func InitFlags(fs *pflag.FlagSet) {
 		10,
 		"Number of AzureMachinePoolMachines to process simultaneously")
 
+	fs.IntVar(&azureManagedClusterConcurrency,
+		"azuremanagedcluster-concurrency",
+		10,
+		"Number of AzureManagedClusters to process simultaneously. Defaults to the value of --azurecluster-concurrency")
+
+	fs.IntVar(&azureManagedControlPlaneConcurrency,
+		"azuremanagedcontrolplane-concurrency",
+		10,
+		"Number of AzureManagedControlPlanes to process simultaneously. Defaults to the value of --azurecluster-concurrency")
+
+	fs.IntVar(&azureManagedMachinePoolConcurrency,
+		"azuremanagedmachinepool-concurrency",
+		10,
+		"Number of AzureManagedMachinePools to process simultaneously. Defaults to the value of --azuremachinepool-concurrency")
+
+	fs.DurationVar(&rateLimiterBaseDelay,
+		"rate-limiter-base-delay",
+		reconciler.DefaultRateLimiterBaseDelay,
+		"The delay before the first retry of a failed reconcile, doubled on every consecutive failure of the same object",
+	)
+
+	fs.DurationVar(&rateLimiterMaxDelay,
+		"rate-limiter-max-delay",
+		reconciler.DefaultRateLimiterMaxDelay,
+		"The maximum delay between retries of a failed reconcile",
+	)
+
+	fs.Float64Var(&rateLimiterQPS,
+		"rate-limiter-qps",
+		reconciler.DefaultRateLimiterQPS,
+		"The overall rate at which each controller requeues objects, in objects per second",
+	)
+
+	fs.IntVar(&rateLimiterBurst,
+		"rate-limiter-burst",
+		reconciler.DefaultRateLimiterBurst,
+		"The number of requeues each controller may burst above the rate-limiter-qps rate",
+	)
+
 	fs.DurationVar(&debouncingTimer,
 		"debouncing-timer",
 		10*time.Second,
@@ -213,12 +317,84 @@ func InitFlags(fs *pflag.FlagSet) {
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)",
 	)
 
+	fs.DurationVar(&adaptiveRequeueMinInterval,
+		"adaptive-requeue-min-interval",
+		0,
+		"The interval after which AzureClusters and AzureManagedControlPlanes that recently drifted or failed to reconcile are reconciled again. It doubles after each successful reconciliation up to --adaptive-requeue-max-interval. When 0, objects are only reconciled again on changes and every --sync-period",
+	)
+
+	fs.DurationVar(&adaptiveRequeueMaxInterval,
+		"adaptive-requeue-max-interval",
+		30*time.Minute,
+		"The interval after which healthy, unchanged AzureClusters and AzureManagedControlPlanes are reconciled again when --adaptive-requeue-min-interval is set. --sync-period should be longer for it to take effect",
+	)
+
 	fs.StringVar(&healthAddr,
 		"health-addr",
 		":9440",
 		"The address the health endpoint binds to.",
 	)
 
+	fs.DurationVar(&credentialsCheckInterval,
+		"azure-credentials-check-interval",
+		5*time.Minute,
+		"The minimum interval between two checks of the Azure credentials of the controller manager by the readiness probe",
+	)
+
+	fs.DurationVar(&galleryImageVersionCheckInterval,
+		"gallery-image-version-check-interval",
+		0,
+		"The interval at which AzureMachinePools using Major.Minor.latest compute gallery image versions look for a newer version to roll out. When 0, the version found when the image is first used is kept",
+	)
+
+	fs.DurationVar(&imageFreshnessCheckInterval,
+		"image-freshness-check-interval",
+		0,
+		"The interval at which the images of AzureMachines and AzureMachinePools are compared against the latest build of their Major.Minor version, reported in the OutdatedImage condition. When 0, images are not checked",
+	)
+
+	fs.DurationVar(&nodeJoinTimeout,
+		"node-join-timeout",
+		0,
+		"How long the VM of an AzureMachine can run without its node joining the cluster before the last lines of its serial console log are collected into the NodeNotJoined condition. When 0, serial console logs are not collected",
+	)
+
+	fs.DurationVar(&backendPoolCheckInterval,
+		"backend-pool-check-interval",
+		0,
+		"The interval at which the network interfaces of control plane AzureMachines are checked to be in the backend pools of the API server load balancer, and added back to the pools they are missing from. When 0, they are only checked when the AzureMachine is reconciled for another reason",
+	)
+
+	fs.BoolVar(&acceptMarketplaceTerms,
+		"accept-marketplace-terms",
+		false,
+		"Accept the marketplace terms of the purchase plans of the images used by AzureMachines and AzureMachinePools on their subscription before creating them, when the terms are not accepted yet.",
+	)
+
+	fs.DurationVar(&apiServerHealthCheckInterval,
+		"apiserver-health-check-interval",
+		time.Minute,
+		"The interval at which the control plane endpoint of ready AzureClusters is probed to report their APIServerReachable condition. When 0, control plane endpoints are not probed",
+	)
+
+	fs.DurationVar(&resourceInventoryInterval,
+		"resource-inventory-interval",
+		0,
+		"The interval at which the Azure resources tagged as owned by AzureClusters are listed across their subscription and exported as metrics and inventory ConfigMaps. When 0, resources are not inventoried",
+	)
+
+	fs.DurationVar(&orphanedResourcesSweepInterval,
+		"orphaned-resources-sweep-interval",
+		0,
+		"The interval at which the public IPs and disks of the subscription of the controller manager tagged as owned by clusters which no longer exist are listed and exported as metrics. When 0, orphaned resources are not looked for",
+	)
+
+	fs.DurationVar(&orphanedResourcesSweepMinAge,
+		"orphaned-resources-sweep-min-age",
+		0,
+		"The minimum age of the orphaned public IPs and disks deleted at each sweep. Only enable it when no other management cluster creates clusters in the subscription of the controller manager. When 0, orphaned resources are not deleted",
+	)
+
 	fs.IntVar(&webhookPort,
 		"webhook-port",
 		9443,
@@ -241,6 +417,139 @@ func InitFlags(fs *pflag.FlagSet) {
 		"Enable tracing to the opentelemetry-collector service in the same namespace.",
 	)
 
+	fs.StringVar(
+		&tracingEndpoint,
+		"tracing-endpoint",
+		ot.DefaultTracingEndpoint,
+		"The OTLP gRPC endpoint traces are exported to when tracing is enabled.",
+	)
+
+	fs.StringVar(
+		&userAgentSuffix,
+		"user-agent-suffix",
+		"",
+		"A string appended to the user agent of all Azure Resource Manager requests, e.g. to identify a product embedding the provider.",
+	)
+
+	fs.StringVar(
+		&partnerID,
+		"partner-id",
+		"",
+		"The Azure partner GUID sent in the user agent of all Azure Resource Manager requests for customer usage attribution.",
+	)
+
+	fs.StringVar(
+		&azureProxyURL,
+		"azure-proxy-url",
+		"",
+		"The URL of the HTTP proxy all Azure requests are sent through. When empty, the HTTPS_PROXY and NO_PROXY environment variables are honored.",
+	)
+
+	fs.StringVar(
+		&azureNoProxy,
+		"azure-no-proxy",
+		"",
+		"A comma-separated list of hosts, domain suffixes and CIDRs reached without the proxy set with --azure-proxy-url.",
+	)
+
+	fs.StringVar(
+		&azureCABundle,
+		"azure-ca-bundle",
+		"",
+		"The path of a file containing PEM encoded certificate authorities trusted for Azure requests in addition to the system ones, e.g. the CA of a TLS inspecting proxy.",
+	)
+
+	fs.BoolVar(
+		&propagateProxyToNodes,
+		"propagate-proxy-to-nodes",
+		false,
+		"Set up the proxy set with --azure-proxy-url, or the HTTPS_PROXY environment variable, on the Linux nodes of new AzureMachines and AzureMachinePools through their bootstrap data.",
+	)
+
+	fs.BoolVar(
+		&validateIdentityCredentials,
+		"validate-identity-credentials",
+		false,
+		"Reject AzureClusterIdentities whose credentials can't be used to acquire a token from Microsoft Entra ID when they are created or their credentials change.",
+	)
+
+	fs.BoolVar(
+		&validateLocations,
+		"validate-locations",
+		false,
+		"Reject AzureClusters whose location is not available to their subscription, and AzureMachines and AzureMachinePools whose VM size is not available in their location or does not support their features, listing the valid alternatives. Locations and VM sizes are listed with the Azure credentials of the controller manager.",
+	)
+
+	fs.BoolVar(
+		&azureJSONRollout,
+		"azure-json-rollout",
+		false,
+		"Roll out the MachineDeployments and the KubeadmControlPlane of a cluster when the azure.json secret of their AzureMachineTemplate is regenerated after a change of the AzureCluster, its identity or the secret of its identity, so that their nodes get the new credentials and settings.",
+	)
+
+	fs.BoolVar(
+		&serveSKUCatalog,
+		"sku-catalog",
+		false,
+		"Serve the VM sizes, zones and disk types available in a location as JSON at "+skucatalog.Path+"?location=<location>[&subscriptionID=<subscription>] on the metrics endpoint, for UIs to offer only the valid choices. They are listed with the Azure credentials of the controller manager.",
+	)
+
+	fs.IntVar(
+		&clusterQuota.MaxClustersPerNamespace,
+		"max-clusters-per-namespace",
+		0,
+		"The maximum number of AzureClusters in a namespace, new AzureClusters are rejected beyond it. Unlimited when 0.",
+	)
+
+	fs.IntVar(
+		&clusterQuota.MaxClustersPerIdentity,
+		"max-clusters-per-identity",
+		0,
+		"The maximum number of AzureClusters referencing the same AzureClusterIdentity, new AzureClusters are rejected beyond it. Unlimited when 0.",
+	)
+
+	fs.Int64Var(
+		&clusterQuota.MaxVCPUsPerNamespace,
+		"max-vcpus-per-namespace",
+		0,
+		"Reject new AzureClusters in the namespaces whose AzureMachines and AzureMachinePools already have this many vCPUs. Unlimited when 0.",
+	)
+
+	fs.Int64Var(
+		&clusterQuota.MaxVCPUsPerIdentity,
+		"max-vcpus-per-identity",
+		0,
+		"Reject new AzureClusters referencing an AzureClusterIdentity whose clusters' AzureMachines and AzureMachinePools already have this many vCPUs. Unlimited when 0.",
+	)
+
+	fs.StringToStringVar(
+		&defaultLocations,
+		"default-locations",
+		nil,
+		"The locations of the AzureClusters created without one, keyed by subscription ID, e.g. 00000000-0000-0000-0000-000000000000=eastus. The location of the * key applies to the other subscriptions, including the subscription of the controller manager for clusters without a subscription ID.",
+	)
+
+	fs.StringVar(
+		&statusWebhookURL,
+		"status-webhook-url",
+		"",
+		"The URL of an HTTP endpoint notified of AzureCluster and AzureMachine provisioning phase transitions, e.g. a Plural console webhook. Disabled when empty.",
+	)
+
+	fs.StringVar(
+		&statusWebhookTokenFile,
+		"status-webhook-token-file",
+		"",
+		"The path of a file containing a bearer token sent to the status webhook.",
+	)
+
+	fs.StringVar(
+		&armAuditLogFile,
+		"arm-audit-log-file",
+		"",
+		"The path of a file to which the audit records of all mutating Azure API requests are appended as JSON lines, e.g. on a persistent volume. Audit records are always logged.",
+	)
+
 	feature.MutableGates.AddFlag(fs)
 }
 
@@ -248,11 +557,37 @@ func main() {
 	InitFlags(pflag.CommandLine)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	pflag.Parse()
+	defaultManagedConcurrency(pflag.CommandLine)
 
 	ctrl.SetLogger(klogr.New())
 
-	if watchNamespace != "" {
-		setupLog.Info("Watching cluster-api objects only in namespace for reconciliation", "namespace", watchNamespace)
+	if err := azure.SetUserAgentExtensions(userAgentSuffix, partnerID); err != nil {
+		setupLog.Error(err, "unable to configure the Azure user agent")
+		os.Exit(1)
+	}
+	if armAuditLogFile != "" {
+		auditSink, err := azure.NewFileAuditSink(armAuditLogFile)
+		if err != nil {
+			setupLog.Error(err, "unable to open the Azure API audit log")
+			os.Exit(1)
+		}
+		azure.SetAuditSink(auditSink)
+	}
+	if err := configureProxy(); err != nil {
+		setupLog.Error(err, "unable to configure the proxy")
+		os.Exit(1)
+	}
+	if contentionProfiling && profilerAddress != "" {
+		goruntime.SetBlockProfileRate(1)
+		goruntime.SetMutexProfileFraction(1)
+	}
+	marketplaceagreements.SetAcceptTerms(acceptMarketplaceTerms)
+
+	if len(watchNamespaces) > 0 {
+		setupLog.Info("Watching cluster-api objects only in namespaces for reconciliation", "namespaces", watchNamespaces)
+	}
+	if watchFilterValue != "" {
+		setupLog.Info("Watching only cluster-api objects with the watch label for reconciliation", "label", clusterv1.WatchLabel, "value", watchFilterValue)
 	}
 
 	// Machine and cluster operations can create enough events to trigger the event recorder spam filter
@@ -261,25 +596,21 @@ func main() {
 		BurstSize: 100,
 	})
 
-	var watchNamespaces []string
-	if watchNamespace != "" {
-		watchNamespaces = []string{watchNamespace}
-	}
-
 	restConfig := ctrl.GetConfigOrDie()
 	restConfig.UserAgent = "cluster-api-provider-azure-manager"
 	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
-		Scheme:                     scheme,
-		MetricsBindAddress:         metricsAddr,
-		LeaderElection:             enableLeaderElection,
-		LeaderElectionID:           "controller-leader-election-capz",
-		LeaderElectionNamespace:    leaderElectionNamespace,
-		LeaseDuration:              &leaderElectionLeaseDuration,
-		RenewDeadline:              &leaderElectionRenewDeadline,
-		RetryPeriod:                &leaderElectionRetryPeriod,
-		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
-		HealthProbeBindAddress:     healthAddr,
-		PprofBindAddress:           profilerAddress,
+		Scheme:                        scheme,
+		MetricsBindAddress:            metricsAddr,
+		LeaderElection:                enableLeaderElection,
+		LeaderElectionID:              "controller-leader-election-capz",
+		LeaderElectionNamespace:       leaderElectionNamespace,
+		LeaseDuration:                 &leaderElectionLeaseDuration,
+		RenewDeadline:                 &leaderElectionRenewDeadline,
+		RetryPeriod:                   &leaderElectionRetryPeriod,
+		LeaderElectionReleaseOnCancel: leaderElectionReleaseOnCancel,
+		LeaderElectionResourceLock:    resourcelock.LeasesResourceLock,
+		HealthProbeBindAddress:        healthAddr,
+		PprofBindAddress:              profilerAddress,
 		Cache: cache.Options{
 			Namespaces: watchNamespaces,
 			SyncPeriod: &syncPeriod,
@@ -310,7 +641,7 @@ func main() {
 	ctx := ctrl.SetupSignalHandler()
 
 	if enableTracing {
-		if err := ot.RegisterTracing(ctx, setupLog); err != nil {
+		if err := ot.RegisterTracing(ctx, tracingEndpoint, setupLog); err != nil {
 			setupLog.Error(err, "unable to initialize tracing")
 			os.Exit(1)
 		}
@@ -325,6 +656,15 @@ func main() {
 
 	registerWebhooks(mgr)
 
+	registerHealthChecks(mgr)
+
+	if serveSKUCatalog {
+		if err := mgr.AddMetricsExtraHandler(skucatalog.Path, skucatalog.NewHandler(scope.NewLocationChecker().Catalog)); err != nil {
+			setupLog.Error(err, "unable to serve the SKU catalog")
+			os.Exit(1)
+		}
+	}
+
 	// +kubebuilder:scaffold:builder
 	setupLog.Info("starting manager", "version", version.Get().String())
 	if err := mgr.Start(ctx); err != nil {
@@ -333,40 +673,152 @@ func main() {
 	}
 }
 
+// configureProxy sets the proxy and certificate authorities used to reach Azure, and the proxy of the nodes.
+func configureProxy() error {
+	options := azureutil.TransportOptions{
+		ProxyURL: azureProxyURL,
+		NoProxy:  azureNoProxy,
+	}
+	if azureCABundle != "" {
+		bundle, err := os.ReadFile(azureCABundle)
+		if err != nil {
+			return fmt.Errorf("failed to read the Azure CA bundle: %w", err)
+		}
+		options.CABundle = bundle
+	}
+	if err := azureutil.SetTransport(options); err != nil {
+		return err
+	}
+
+	if propagateProxyToNodes {
+		proxyURL, noProxy := azureProxyURL, azureNoProxy
+		if proxyURL == "" {
+			env := httpproxy.FromEnvironment()
+			proxyURL, noProxy = env.HTTPSProxy, env.NoProxy
+		}
+		if proxyURL == "" {
+			return fmt.Errorf("--propagate-proxy-to-nodes requires --azure-proxy-url or the HTTPS_PROXY environment variable")
+		}
+		scope.SetNodeProxy(proxyURL, noProxy)
+	}
+	return nil
+}
+
 func registerControllers(ctx context.Context, mgr manager.Manager) {
 	machineCache, err := coalescing.NewRequestCache(debouncingTimer)
 	if err != nil {
 		setupLog.Error(err, "failed to build machineCache ReconcileCache")
 	}
-	if err := controllers.NewAzureMachineReconciler(mgr.GetClient(),
+	var statusSink statussink.Sink
+	if statusWebhookURL != "" {
+		webhookSink := statussink.NewWebhookSink(statusWebhookURL, statusWebhookTokenFile)
+		if err := mgr.Add(webhookSink); err != nil {
+			setupLog.Error(err, "unable to add the status webhook sink to the manager")
+			os.Exit(1)
+		}
+		statusSink = webhookSink
+	}
+
+	amReconciler := controllers.NewAzureMachineReconciler(mgr.GetClient(),
 		mgr.GetEventRecorderFor("azuremachine-reconciler"),
 		reconcileTimeout,
 		watchFilterValue,
-	).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}, Cache: machineCache}); err != nil {
+	)
+	amReconciler.StatusSink = statusSink
+	amReconciler.ImageFreshnessCheckInterval = imageFreshnessCheckInterval
+	amReconciler.NodeJoinTimeout = nodeJoinTimeout
+	amReconciler.BackendPoolCheckInterval = backendPoolCheckInterval
+	if err := amReconciler.SetupWithManager(ctx, mgr, controllers.Options{Options: controllerOptions(azureMachineConcurrency), Cache: machineCache}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureMachine")
 		os.Exit(1)
 	}
 
+	if err := (&controllers.AzureMachineRemediationReconciler{
+		Client:           mgr.GetClient(),
+		Recorder:         mgr.GetEventRecorderFor("azuremachineremediation-reconciler"),
+		ReconcileTimeout: reconcileTimeout,
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, controllerOptions(azureMachineConcurrency)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AzureMachineRemediation")
+		os.Exit(1)
+	}
+
 	clusterCache, err := coalescing.NewRequestCache(debouncingTimer)
 	if err != nil {
 		setupLog.Error(err, "failed to build clusterCache ReconcileCache")
 	}
-	if err := controllers.NewAzureClusterReconciler(
+	clusterIntervals := adaptiveIntervals()
+	acReconciler := controllers.NewAzureClusterReconciler(
 		mgr.GetClient(),
 		mgr.GetEventRecorderFor("azurecluster-reconciler"),
 		reconcileTimeout,
 		watchFilterValue,
-	).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}, Cache: clusterCache}); err != nil {
+	)
+	acReconciler.StatusSink = statusSink
+	if err := acReconciler.SetupWithManager(ctx, mgr, controllers.Options{Options: controllerOptions(azureClusterConcurrency), Cache: clusterCache, Intervals: clusterIntervals}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureCluster")
 		os.Exit(1)
 	}
 
+	if apiServerHealthCheckInterval > 0 {
+		if err := (&controllers.AzureClusterHealthReconciler{
+			Client:           mgr.GetClient(),
+			Recorder:         mgr.GetEventRecorderFor("azureclusterhealth-reconciler"),
+			ReconcileTimeout: reconcileTimeout,
+			WatchFilterValue: watchFilterValue,
+			Interval:         apiServerHealthCheckInterval,
+		}).SetupWithManager(ctx, mgr, controllerOptions(azureClusterConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AzureClusterHealth")
+			os.Exit(1)
+		}
+	}
+
+	if resourceInventoryInterval > 0 {
+		if err := (&controllers.AzureClusterInventoryReconciler{
+			Client:           mgr.GetClient(),
+			Recorder:         mgr.GetEventRecorderFor("azureclusterinventory-reconciler"),
+			ReconcileTimeout: reconcileTimeout,
+			WatchFilterValue: watchFilterValue,
+			Interval:         resourceInventoryInterval,
+		}).SetupWithManager(ctx, mgr, controllerOptions(azureClusterConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AzureClusterInventory")
+			os.Exit(1)
+		}
+	}
+
+	if err := (&controllers.AzureClusterExportReconciler{
+		Client:           mgr.GetClient(),
+		Recorder:         mgr.GetEventRecorderFor("azureclusterexport-reconciler"),
+		ReconcileTimeout: reconcileTimeout,
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, controllerOptions(azureClusterConcurrency)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AzureClusterExport")
+		os.Exit(1)
+	}
+
+	if orphanedResourcesSweepInterval > 0 {
+		authorizer, err := scope.NewEnvironmentAuthorizer()
+		if err != nil {
+			setupLog.Error(err, "unable to get the Azure credentials of the orphaned resources sweeper")
+			os.Exit(1)
+		}
+		sweeper, err := orphanedresources.NewSweeper(mgr.GetClient(), authorizer, orphanedResourcesSweepInterval, orphanedResourcesSweepMinAge)
+		if err != nil {
+			setupLog.Error(err, "unable to create the orphaned resources sweeper")
+			os.Exit(1)
+		}
+		if err := mgr.Add(sweeper); err != nil {
+			setupLog.Error(err, "unable to add the orphaned resources sweeper to the manager")
+			os.Exit(1)
+		}
+	}
+
 	if err := (&controllers.AzureJSONTemplateReconciler{
 		Client:           mgr.GetClient(),
 		Recorder:         mgr.GetEventRecorderFor("azurejsontemplate-reconciler"),
 		ReconcileTimeout: reconcileTimeout,
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}); err != nil {
+	}).SetupWithManager(ctx, mgr, controllerOptions(azureMachineConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureJSONTemplate")
 		os.Exit(1)
 	}
@@ -376,17 +828,28 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		Recorder:         mgr.GetEventRecorderFor("azurejsonmachine-reconciler"),
 		ReconcileTimeout: reconcileTimeout,
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}); err != nil {
+	}).SetupWithManager(ctx, mgr, controllerOptions(azureMachineConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureJSONMachine")
 		os.Exit(1)
 	}
 
+	if err := (&controllers.AzureJSONRotationReconciler{
+		Client:           mgr.GetClient(),
+		Recorder:         mgr.GetEventRecorderFor("azurejsonrotation-reconciler"),
+		ReconcileTimeout: reconcileTimeout,
+		WatchFilterValue: watchFilterValue,
+		Rollout:          azureJSONRollout,
+	}).SetupWithManager(ctx, mgr, controllerOptions(azureClusterConcurrency)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AzureJSONRotation")
+		os.Exit(1)
+	}
+
 	if err := (&controllers.AzureIdentityReconciler{
 		Client:           mgr.GetClient(),
 		Recorder:         mgr.GetEventRecorderFor("azureidentity-reconciler"),
 		ReconcileTimeout: reconcileTimeout,
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}); err != nil {
+	}).SetupWithManager(ctx, mgr, controllerOptions(azureClusterConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureIdentity")
 		os.Exit(1)
 	}
@@ -396,7 +859,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		Recorder:         mgr.GetEventRecorderFor("asosecret-reconciler"),
 		ReconcileTimeout: reconcileTimeout,
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}); err != nil {
+	}).SetupWithManager(ctx, mgr, controllerOptions(azureClusterConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ASOSecret")
 		os.Exit(1)
 	}
@@ -409,12 +872,15 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			setupLog.Error(err, "failed to build mpCache ReconcileCache")
 		}
 
-		if err := infrav1controllersexp.NewAzureMachinePoolReconciler(
+		ampReconciler := infrav1controllersexp.NewAzureMachinePoolReconciler(
 			mgr.GetClient(),
 			mgr.GetEventRecorderFor("azuremachinepool-reconciler"),
 			reconcileTimeout,
 			watchFilterValue,
-		).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachinePoolConcurrency}, Cache: mpCache}); err != nil {
+		)
+		ampReconciler.ImageVersionCheckInterval = galleryImageVersionCheckInterval
+		ampReconciler.ImageFreshnessCheckInterval = imageFreshnessCheckInterval
+		if err := ampReconciler.SetupWithManager(ctx, mgr, controllers.Options{Options: controllerOptions(azureMachinePoolConcurrency), Cache: mpCache}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AzureMachinePool")
 			os.Exit(1)
 		}
@@ -429,7 +895,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			mgr.GetEventRecorderFor("azuremachinepoolmachine-reconciler"),
 			reconcileTimeout,
 			watchFilterValue,
-		).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachinePoolMachineConcurrency}, Cache: mpmCache}); err != nil {
+		).SetupWithManager(ctx, mgr, controllers.Options{Options: controllerOptions(azureMachinePoolMachineConcurrency), Cache: mpmCache}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AzureMachinePoolMachine")
 			os.Exit(1)
 		}
@@ -439,60 +905,115 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			Recorder:         mgr.GetEventRecorderFor("azurejsonmachinepool-reconciler"),
 			ReconcileTimeout: reconcileTimeout,
 			WatchFilterValue: watchFilterValue,
-		}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureMachinePoolConcurrency}); err != nil {
+		}).SetupWithManager(ctx, mgr, controllerOptions(azureMachinePoolConcurrency)); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AzureJSONMachinePool")
 			os.Exit(1)
 		}
 
-		mmpmCache, err := coalescing.NewRequestCache(debouncingTimer)
-		if err != nil {
-			setupLog.Error(err, "failed to build mmpmCache ReconcileCache")
-		}
-
-		if err := controllers.NewAzureManagedMachinePoolReconciler(
-			mgr.GetClient(),
-			mgr.GetEventRecorderFor("azuremanagedmachinepoolmachine-reconciler"),
-			reconcileTimeout,
-			watchFilterValue,
-		).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachinePoolConcurrency}, Cache: mmpmCache}); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "AzureManagedMachinePool")
-			os.Exit(1)
-		}
-
-		mcCache, err := coalescing.NewRequestCache(debouncingTimer)
-		if err != nil {
-			setupLog.Error(err, "failed to build mcCache ReconcileCache")
+		if feature.Gates.Enabled(feature.AKS) {
+			mmpmCache, err := coalescing.NewRequestCache(debouncingTimer)
+			if err != nil {
+				setupLog.Error(err, "failed to build mmpmCache ReconcileCache")
+			}
+
+			if err := controllers.NewAzureManagedMachinePoolReconciler(
+				mgr.GetClient(),
+				mgr.GetEventRecorderFor("azuremanagedmachinepoolmachine-reconciler"),
+				reconcileTimeout,
+				watchFilterValue,
+			).SetupWithManager(ctx, mgr, controllers.Options{Options: controllerOptions(azureManagedMachinePoolConcurrency), Cache: mmpmCache}); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "AzureManagedMachinePool")
+				os.Exit(1)
+			}
+
+			mcCache, err := coalescing.NewRequestCache(debouncingTimer)
+			if err != nil {
+				setupLog.Error(err, "failed to build mcCache ReconcileCache")
+			}
+
+			if err := (&controllers.AzureManagedClusterReconciler{
+				Client:           mgr.GetClient(),
+				Recorder:         mgr.GetEventRecorderFor("azuremanagedcluster-reconciler"),
+				ReconcileTimeout: reconcileTimeout,
+				WatchFilterValue: watchFilterValue,
+			}).SetupWithManager(ctx, mgr, controllers.Options{Options: controllerOptions(azureManagedClusterConcurrency), Cache: mcCache}); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "AzureManagedCluster")
+				os.Exit(1)
+			}
+
+			mcpCache, err := coalescing.NewRequestCache(debouncingTimer)
+			if err != nil {
+				setupLog.Error(err, "failed to build mcpCache ReconcileCache")
+			}
+			mcpIntervals := adaptiveIntervals()
+
+			if err := (&controllers.AzureManagedControlPlaneReconciler{
+				Client:           mgr.GetClient(),
+				Recorder:         mgr.GetEventRecorderFor("azuremanagedcontrolplane-reconciler"),
+				ReconcileTimeout: reconcileTimeout,
+				WatchFilterValue: watchFilterValue,
+			}).SetupWithManager(ctx, mgr, controllers.Options{Options: controllerOptions(azureManagedControlPlaneConcurrency), Cache: mcpCache, Intervals: mcpIntervals}); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "AzureManagedControlPlane")
+				os.Exit(1)
+			}
 		}
+	}
+}
 
-		if err := (&controllers.AzureManagedClusterReconciler{
-			Client:           mgr.GetClient(),
-			Recorder:         mgr.GetEventRecorderFor("azuremanagedcluster-reconciler"),
-			ReconcileTimeout: reconcileTimeout,
-			WatchFilterValue: watchFilterValue,
-		}).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}, Cache: mcCache}); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "AzureManagedCluster")
-			os.Exit(1)
-		}
+// adaptiveIntervals returns the adaptive requeue intervals of a controller, or nil when adaptive requeues are disabled.
+func adaptiveIntervals() *adaptive.Intervals {
+	if adaptiveRequeueMinInterval == 0 {
+		return nil
+	}
+	intervals, err := adaptive.NewIntervals(adaptiveRequeueMinInterval, adaptiveRequeueMaxInterval)
+	if err != nil {
+		setupLog.Error(err, "unable to set up adaptive requeues")
+		os.Exit(1)
+	}
+	if syncPeriod < adaptiveRequeueMaxInterval {
+		setupLog.Info("--sync-period is shorter than --adaptive-requeue-max-interval, healthy objects are reconciled every --sync-period",
+			"syncPeriod", syncPeriod, "adaptiveRequeueMaxInterval", adaptiveRequeueMaxInterval)
+	}
+	return intervals
+}
 
-		mcpCache, err := coalescing.NewRequestCache(debouncingTimer)
-		if err != nil {
-			setupLog.Error(err, "failed to build mcpCache ReconcileCache")
-		}
+// defaultManagedConcurrency defaults the concurrency of the AKS controllers which are not set explicitly to the
+// concurrency of the controllers they used to share it with, so that existing deployments keep their tuning.
+func defaultManagedConcurrency(fs *pflag.FlagSet) {
+	if !fs.Changed("azuremanagedcluster-concurrency") {
+		azureManagedClusterConcurrency = azureClusterConcurrency
+	}
+	if !fs.Changed("azuremanagedcontrolplane-concurrency") {
+		azureManagedControlPlaneConcurrency = azureClusterConcurrency
+	}
+	if !fs.Changed("azuremanagedmachinepool-concurrency") {
+		azureManagedMachinePoolConcurrency = azureMachinePoolConcurrency
+	}
+}
 
-		if err := (&controllers.AzureManagedControlPlaneReconciler{
-			Client:           mgr.GetClient(),
-			Recorder:         mgr.GetEventRecorderFor("azuremanagedcontrolplane-reconciler"),
-			ReconcileTimeout: reconcileTimeout,
-			WatchFilterValue: watchFilterValue,
-		}).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}, Cache: mcpCache}); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "AzureManagedControlPlane")
-			os.Exit(1)
-		}
+// controllerOptions returns the options of a controller reconciling up to concurrency objects at once.
+// Every controller gets its own rate limiter since it tracks the failures of the objects it requeues.
+func controllerOptions(concurrency int) controller.Options {
+	return controller.Options{
+		MaxConcurrentReconciles: concurrency,
+		RateLimiter:             reconciler.NewRateLimiter(rateLimiterBaseDelay, rateLimiterMaxDelay, rateLimiterQPS, rateLimiterBurst),
 	}
 }
 
 func registerWebhooks(mgr manager.Manager) {
-	if err := (&infrav1.AzureCluster{}).SetupWebhookWithManager(mgr); err != nil {
+	var checkLocations infrav1.LocationChecker
+	if validateLocations {
+		checkLocations = scope.NewLocationChecker()
+	}
+	var defaultLocation infrav1.LocationDefaulter
+	if len(defaultLocations) > 0 {
+		defaultLocation = defaultSubscriptionLocation
+	}
+	var checkQuota infrav1.ClusterQuotaChecker
+	if !clusterQuota.IsZero() {
+		checkQuota = scope.NewClusterQuotaChecker(mgr.GetClient(), clusterQuota).Check
+	}
+	if err := infrav1.SetupAzureClusterWebhookWithManager(mgr, defaultLocation, checkLocations, checkQuota); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureCluster")
 		os.Exit(1)
 	}
@@ -507,7 +1028,11 @@ func registerWebhooks(mgr manager.Manager) {
 		os.Exit(1)
 	}
 
-	if err := (&infrav1.AzureClusterIdentity{}).SetupWebhookWithManager(mgr); err != nil {
+	var checkIdentityCredentials infrav1.IdentityCredentialsChecker
+	if validateIdentityCredentials {
+		checkIdentityCredentials = scope.CheckIdentityCredentials
+	}
+	if err := infrav1.SetupAzureClusterIdentityWebhookWithManager(mgr, checkIdentityCredentials); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureClusterIdentity")
 		os.Exit(1)
 	}
@@ -518,18 +1043,22 @@ func registerWebhooks(mgr manager.Manager) {
 	}
 
 	// NOTE: AzureManagedCluster is behind AKS feature gate flag; the webhook
-	// is going to prevent creating or updating new objects in case the feature flag is disabled
+	// is going to prevent creating new objects in case the feature flag is disabled
 	if err := (&infrav1.AzureManagedCluster{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureManagedCluster")
 		os.Exit(1)
 	}
 
-	if err := infrav1exp.SetupAzureMachinePoolWebhookWithManager(mgr); err != nil {
+	if err := infrav1exp.SetupAzureMachinePoolWebhookWithManager(mgr, checkLocations); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureMachinePool")
 		os.Exit(1)
 	}
+	if err := infrav1exp.SetupAzureMachinePoolTemplateWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "AzureMachinePoolTemplate")
+		os.Exit(1)
+	}
 
-	if err := infrav1.SetupAzureMachineWebhookWithManager(mgr); err != nil {
+	if err := infrav1.SetupAzureMachineWebhookWithManager(mgr, checkLocations); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureMachine")
 		os.Exit(1)
 	}
@@ -544,6 +1073,21 @@ func registerWebhooks(mgr manager.Manager) {
 		os.Exit(1)
 	}
 
+}
+
+// defaultSubscriptionLocation returns the location set with --default-locations for the AzureClusters created without
+// one in subscriptionID, the subscription of the controller manager when empty.
+func defaultSubscriptionLocation(subscriptionID string) string {
+	if subscriptionID == "" {
+		subscriptionID = os.Getenv(auth.SubscriptionID)
+	}
+	if location, ok := defaultLocations[subscriptionID]; ok {
+		return location
+	}
+	return defaultLocations["*"]
+}
+
+func registerHealthChecks(mgr manager.Manager) {
 	if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
 		setupLog.Error(err, "unable to create ready check")
 		os.Exit(1)
@@ -553,4 +1097,20 @@ func registerWebhooks(mgr manager.Manager) {
 		setupLog.Error(err, "unable to create health check")
 		os.Exit(1)
 	}
+
+	if err := mgr.AddReadyzCheck("cache-sync", health.CacheSyncChecker(mgr.GetCache())); err != nil {
+		setupLog.Error(err, "unable to create ready check", "check", "cache-sync")
+		os.Exit(1)
+	}
+
+	// Clusters without an identity use the credentials of the controller manager, if it has any.
+	cred, scope, err := azureutil.GetEnvironmentCredential()
+	if err != nil {
+		setupLog.Info("skipping Azure credentials ready check, the controller manager has no Azure credentials", "reason", err.Error())
+		return
+	}
+	if err := mgr.AddReadyzCheck("azure-credentials", health.AzureCredentialsChecker(cred, scope, credentialsCheckInterval)); err != nil {
+		setupLog.Error(err, "unable to create ready check", "check", "azure-credentials")
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adaptive provides a reconciler middleware requeueing healthy, unchanged objects at a long interval and
+// objects that recently drifted or failed at a short one.
+package adaptive
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// cacheSize is the number of objects whose interval is tracked. Objects evicted from the cache start over at the
+// minimum interval.
+const cacheSize = 16384
+
+var requeueInterval = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "capz_adaptive_requeue_interval_seconds",
+	Help:    "Interval after which objects successfully reconciled are reconciled again, by kind.",
+	Buckets: prometheus.ExponentialBuckets(15, 2, 10),
+}, []string{"kind"})
+
+func init() {
+	metrics.Registry.MustRegister(requeueInterval)
+}
+
+type (
+	// Intervals tracks the requeue interval of each object. An object that failed to reconcile or asked to be
+	// requeued, e.g. because it drifted from its spec and an Azure operation is in progress, starts over at the
+	// minimum interval. Each following successful reconciliation doubles the interval up to the maximum one.
+	Intervals struct {
+		min     time.Duration
+		max     time.Duration
+		current ttllru.PeekingCacher
+	}
+
+	// reconciler is the adaptive requeue reconciler middleware that uses the intervals.
+	reconciler struct {
+		upstream  reconcile.Reconciler
+		intervals *Intervals
+		client    client.Reader
+		object    client.Object
+		kind      string
+		log       logr.Logger
+	}
+)
+
+// NewIntervals creates a new instance of Intervals requeueing objects between the minimum and maximum intervals.
+func NewIntervals(minInterval, maxInterval time.Duration) (*Intervals, error) {
+	if minInterval <= 0 || maxInterval < minInterval {
+		return nil, errors.Errorf("invalid adaptive requeue intervals: min %s, max %s", minInterval, maxInterval)
+	}
+	// Objects not reconciled for twice the maximum interval are no longer watched, e.g. because they were deleted.
+	cache, err := ttllru.New(cacheSize, 2*maxInterval)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build ttllru cache")
+	}
+
+	return &Intervals{
+		min:     minInterval,
+		max:     maxInterval,
+		current: cache,
+	}, nil
+}
+
+// Reset starts the interval of the key over at the minimum interval.
+func (i *Intervals) Reset(key string) {
+	i.current.Add(key, time.Duration(0))
+}
+
+// Next returns the interval after which the key should be reconciled again and doubles it for the next time.
+func (i *Intervals) Next(key string) time.Duration {
+	next := i.min
+	if value, ok := i.current.Get(key); ok {
+		if previous, _ := value.(time.Duration); previous > 0 {
+			next = previous * 2
+		}
+	}
+	if next > i.max {
+		next = i.max
+	}
+	i.current.Add(key, next)
+	return next
+}
+
+// Forget stops tracking the interval of the key.
+func (i *Intervals) Forget(key string) {
+	i.current.Remove(key)
+}
+
+// NewReconciler returns a reconcile wrapper that requeues the requests successfully reconciled without an explicit
+// requeue after the interval of their object. The object is read through the client to stop requeuing deleted ones.
+func NewReconciler(upstream reconcile.Reconciler, intervals *Intervals, c client.Reader, object client.Object, log logr.Logger) reconcile.Reconciler {
+	return &reconciler{
+		upstream:  upstream,
+		intervals: intervals,
+		client:    c,
+		object:    object,
+		kind:      reflect.TypeOf(object).Elem().Name(),
+		log:       log.WithName("AdaptiveReconciler"),
+	}
+}
+
+// Reconcile sends a request to the upstream reconciler and picks when to requeue it from its outcome.
+func (rc *reconciler) Reconcile(ctx context.Context, r reconcile.Request) (reconcile.Result, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.adaptiveReconciler.Reconcile",
+		tele.KVP("namespace", r.Namespace),
+		tele.KVP("name", r.Name),
+	)
+	defer done()
+
+	log = log.WithValues("request", r.String())
+
+	result, err := rc.upstream.Reconcile(ctx, r)
+	if err != nil || result.Requeue || result.RequeueAfter > 0 {
+		log.V(4).Info("not settled, resetting requeue interval")
+		rc.intervals.Reset(r.String())
+		return result, err
+	}
+
+	obj, _ := rc.object.DeepCopyObject().(client.Object)
+	if err := rc.client.Get(ctx, r.NamespacedName, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			rc.intervals.Forget(r.String())
+			return result, nil
+		}
+		return result, err
+	}
+
+	result.RequeueAfter = rc.intervals.Next(r.String())
+	requeueInterval.WithLabelValues(rc.kind).Observe(result.RequeueAfter.Seconds())
+	log.V(4).Info("settled", "requeueAfter", result.RequeueAfter)
+	return result, nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestNewIntervals(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewIntervals(0, time.Hour)
+	g.Expect(err).To(HaveOccurred())
+	_, err = NewIntervals(time.Hour, time.Minute)
+	g.Expect(err).To(HaveOccurred())
+	_, err = NewIntervals(time.Minute, time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestAdaptiveReconciler_Reconcile(t *testing.T) {
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "aName",
+			Namespace: "aNamespace",
+		},
+	}
+
+	cases := []struct {
+		Name     string
+		Exists   bool
+		Outcomes []reconcile.Result
+		Errors   []error
+		Expected []time.Duration
+	}{
+		{
+			Name:     "should double the interval of settled objects up to the maximum",
+			Exists:   true,
+			Outcomes: []reconcile.Result{{}, {}, {}, {}, {}},
+			Errors:   []error{nil, nil, nil, nil, nil},
+			Expected: []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute},
+		},
+		{
+			Name:     "should keep the requeue of objects not settled and start over at the minimum interval",
+			Exists:   true,
+			Outcomes: []reconcile.Result{{}, {}, {RequeueAfter: 15 * time.Second}, {}},
+			Errors:   []error{nil, nil, nil, nil},
+			Expected: []time.Duration{time.Minute, 2 * time.Minute, 15 * time.Second, time.Minute},
+		},
+		{
+			Name:     "should start over at the minimum interval after an error",
+			Exists:   true,
+			Outcomes: []reconcile.Result{{}, {}, {}},
+			Errors:   []error{nil, errors.New("boom"), nil},
+			Expected: []time.Duration{time.Minute, 0, time.Minute},
+		},
+		{
+			Name:     "should not requeue deleted objects",
+			Exists:   false,
+			Outcomes: []reconcile.Result{{}},
+			Errors:   []error{nil},
+			Expected: []time.Duration{0},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			clientBuilder := fake.NewClientBuilder().WithScheme(scheme)
+			if c.Exists {
+				clientBuilder = clientBuilder.WithObjects(&infrav1.AzureCluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      request.Name,
+						Namespace: request.Namespace,
+					},
+				})
+			}
+
+			intervals, err := NewIntervals(time.Minute, 5*time.Minute)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			call := 0
+			upstream := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				defer func() { call++ }()
+				return c.Outcomes[call], c.Errors[call]
+			})
+			subject := NewReconciler(upstream, intervals, clientBuilder.Build(), &infrav1.AzureCluster{}, logr.New(log.NullLogSink{}))
+
+			for i := range c.Outcomes {
+				result, err := subject.Reconcile(context.Background(), request)
+				if c.Errors[i] != nil {
+					g.Expect(err).To(MatchError(c.Errors[i]))
+				} else {
+					g.Expect(err).NotTo(HaveOccurred())
+				}
+				g.Expect(result.RequeueAfter).To(Equal(c.Expected[i]))
+			}
+		})
+	}
+}
@@ -0,0 +1,424 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clustervalidation validates the objects of cluster definitions together, before they are applied, so that
+// GitOps pipelines and CLIs can catch the mistakes the webhooks can't see as they admit one object at a time: CIDR
+// overlaps between clusters and with the cluster network, VM sizes, zones and disk types the location can't provide,
+// and references to missing subnets, identities and infrastructure objects.
+package clustervalidation
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	clusterKind              = "Cluster"
+	azureClusterKind         = "AzureCluster"
+	azureMachineKind         = "AzureMachine"
+	azureMachineTemplateKind = "AzureMachineTemplate"
+	azureClusterIdentityKind = "AzureClusterIdentity"
+)
+
+// Objects are the objects of the cluster definitions to validate. Objects which already exist in the management
+// cluster and are referenced by the others, such as AzureClusterIdentities, must be included too.
+type Objects struct {
+	Clusters               []clusterv1.Cluster
+	AzureClusters          []infrav1.AzureCluster
+	AzureMachines          []infrav1.AzureMachine
+	AzureMachineTemplates  []infrav1.AzureMachineTemplate
+	AzureClusterIdentities []infrav1.AzureClusterIdentity
+}
+
+// Validate validates objects together and returns the errors found, with paths rooted at the kind and the namespaced
+// name of the object they belong to. The objects are expected to pass their webhooks, and AzureClusters are defaulted
+// as their webhook would before they are validated. The VM sizes, zones and disk types of the machines are only
+// validated in the locations catalogs are given for.
+func Validate(objects Objects, catalogs ...resourceskus.Catalog) field.ErrorList {
+	v := newValidator(objects, catalogs)
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, v.validateClusters()...)
+	allErrs = append(allErrs, v.validateAzureClusters()...)
+	allErrs = append(allErrs, v.validateMachines()...)
+	return allErrs
+}
+
+type validator struct {
+	objects       Objects
+	azureClusters []*infrav1.AzureCluster
+	catalogs      map[string]resourceskus.Catalog
+}
+
+func newValidator(objects Objects, catalogs []resourceskus.Catalog) *validator {
+	v := &validator{
+		objects:  objects,
+		catalogs: make(map[string]resourceskus.Catalog, len(catalogs)),
+	}
+	for i := range objects.AzureClusters {
+		azureCluster := objects.AzureClusters[i].DeepCopy()
+		azureCluster.Default()
+		v.azureClusters = append(v.azureClusters, azureCluster)
+	}
+	for _, catalog := range catalogs {
+		v.catalogs[strings.ToLower(catalog.Location)] = catalog
+	}
+	return v
+}
+
+// objectPath returns the root path of the errors of an object.
+func objectPath(kind, namespace, name string) *field.Path {
+	return field.NewPath(kind).Key(namespace + "/" + name)
+}
+
+// azureCluster returns the defaulted AzureCluster with the given namespace and name, or nil if there is none.
+func (v *validator) azureCluster(namespace, name string) *infrav1.AzureCluster {
+	for _, azureCluster := range v.azureClusters {
+		if azureCluster.Namespace == namespace && azureCluster.Name == name {
+			return azureCluster
+		}
+	}
+	return nil
+}
+
+// azureClusterOf returns the defaulted AzureCluster of the Cluster named clusterName in namespace: the one its
+// infrastructure reference points to, or the AzureCluster with the same name when the Cluster is not given. Without
+// clusterName, it returns the only AzureCluster of namespace. It returns nil if there is none.
+func (v *validator) azureClusterOf(namespace, clusterName string) *infrav1.AzureCluster {
+	if clusterName == "" {
+		var found *infrav1.AzureCluster
+		for _, azureCluster := range v.azureClusters {
+			if azureCluster.Namespace != namespace {
+				continue
+			}
+			if found != nil {
+				return nil
+			}
+			found = azureCluster
+		}
+		return found
+	}
+	for _, cluster := range v.objects.Clusters {
+		if cluster.Namespace == namespace && cluster.Name == clusterName {
+			if ref := cluster.Spec.InfrastructureRef; ref != nil && ref.Kind == azureClusterKind {
+				return v.azureCluster(namespace, ref.Name)
+			}
+			return nil
+		}
+	}
+	return v.azureCluster(namespace, clusterName)
+}
+
+// validateClusters validates the infrastructure references of the Clusters, and that their service network overlaps
+// neither with their pod network nor with the subnets of their AzureCluster.
+func (v *validator) validateClusters() field.ErrorList {
+	var allErrs field.ErrorList
+	for _, cluster := range v.objects.Clusters {
+		fldPath := objectPath(clusterKind, cluster.Namespace, cluster.Name).Child("spec")
+		var azureCluster *infrav1.AzureCluster
+		if ref := cluster.Spec.InfrastructureRef; ref != nil && ref.Kind == azureClusterKind {
+			namespace := ref.Namespace
+			if namespace == "" {
+				namespace = cluster.Namespace
+			}
+			if azureCluster = v.azureCluster(namespace, ref.Name); azureCluster == nil {
+				allErrs = append(allErrs, field.NotFound(fldPath.Child("infrastructureRef"), namespace+"/"+ref.Name))
+			}
+		}
+
+		network := cluster.Spec.ClusterNetwork
+		if network == nil || network.Services == nil {
+			continue
+		}
+		servicesPath := fldPath.Child("clusterNetwork", "services", "cidrBlocks")
+		if network.Pods != nil {
+			for _, overlap := range overlaps(network.Services.CIDRBlocks, network.Pods.CIDRBlocks) {
+				allErrs = append(allErrs, field.Invalid(servicesPath.Index(overlap.index), overlap.cidr,
+					fmt.Sprintf("service CIDR overlaps with pod CIDR %s", overlap.other)))
+			}
+		}
+		if azureCluster == nil {
+			continue
+		}
+		for _, subnet := range azureCluster.Spec.NetworkSpec.Subnets {
+			for _, overlap := range overlaps(network.Services.CIDRBlocks, subnet.CIDRBlocks) {
+				allErrs = append(allErrs, field.Invalid(servicesPath.Index(overlap.index), overlap.cidr,
+					fmt.Sprintf("service CIDR overlaps with CIDR %s of subnet %s of AzureCluster %s", overlap.other, subnet.Name, azureCluster.Name)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateAzureClusters validates the identity references of the AzureClusters, and that the CIDR blocks of the
+// virtual networks of different AzureClusters don't overlap when they are peered, nor the CIDR blocks of their subnets
+// when they share a virtual network.
+func (v *validator) validateAzureClusters() field.ErrorList {
+	var allErrs field.ErrorList
+	for i, azureCluster := range v.azureClusters {
+		fldPath := objectPath(azureClusterKind, azureCluster.Namespace, azureCluster.Name).Child("spec")
+		allErrs = append(allErrs, v.validateIdentityRef(azureCluster, fldPath.Child("identityRef"))...)
+
+		vnet := azureCluster.Spec.NetworkSpec.Vnet
+		for _, other := range v.azureClusters[:i] {
+			otherVnet := other.Spec.NetworkSpec.Vnet
+			switch {
+			case sameVnet(vnet.ResourceGroup, vnet.Name, otherVnet.ResourceGroup, otherVnet.Name):
+				allErrs = append(allErrs, validateSharedVnetSubnets(azureCluster, other, fldPath.Child("networkSpec", "subnets"))...)
+			case isPeered(vnet, otherVnet) || isPeered(otherVnet, vnet):
+				for _, overlap := range overlaps(vnet.CIDRBlocks, otherVnet.CIDRBlocks) {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("networkSpec", "vnet", "cidrBlocks").Index(overlap.index), overlap.cidr,
+						fmt.Sprintf("CIDR overlaps with CIDR %s of the peered virtual network %s of AzureCluster %s", overlap.other, otherVnet.Name, other.Name)))
+				}
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateIdentityRef validates that the AzureClusterIdentity of an AzureCluster is given and allows its namespace.
+// Namespaces selected by a label selector can't be resolved offline and are assumed to be allowed.
+func (v *validator) validateIdentityRef(azureCluster *infrav1.AzureCluster, fldPath *field.Path) field.ErrorList {
+	ref := azureCluster.Spec.IdentityRef
+	if ref == nil || ref.Kind != azureClusterIdentityKind {
+		return nil
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = azureCluster.Namespace
+	}
+	for _, identity := range v.objects.AzureClusterIdentities {
+		if identity.Namespace != namespace || identity.Name != ref.Name {
+			continue
+		}
+		if !isNamespaceAllowed(identity.Spec.AllowedNamespaces, azureCluster.Namespace) {
+			return field.ErrorList{field.Forbidden(fldPath, fmt.Sprintf("AzureClusterIdentity %s/%s does not allow namespace %s", namespace, ref.Name, azureCluster.Namespace))}
+		}
+		return nil
+	}
+	return field.ErrorList{field.NotFound(fldPath, namespace+"/"+ref.Name)}
+}
+
+// isNamespaceAllowed returns true unless allowedNamespaces surely doesn't allow namespace.
+func isNamespaceAllowed(allowedNamespaces *infrav1.AllowedNamespaces, namespace string) bool {
+	if allowedNamespaces == nil {
+		return false
+	}
+	// An empty value matches all namespaces.
+	if reflect.DeepEqual(*allowedNamespaces, infrav1.AllowedNamespaces{}) {
+		return true
+	}
+	for _, allowed := range allowedNamespaces.NamespaceList {
+		if allowed == namespace {
+			return true
+		}
+	}
+	selector := allowedNamespaces.Selector
+	return selector != nil && (len(selector.MatchLabels) > 0 || len(selector.MatchExpressions) > 0)
+}
+
+// sameVnet returns true if two resource groups and names designate the same virtual network.
+func sameVnet(resourceGroup, name, otherResourceGroup, otherName string) bool {
+	return strings.EqualFold(resourceGroup, otherResourceGroup) && strings.EqualFold(name, otherName)
+}
+
+// isPeered returns true if vnet has a peering with the remote virtual network other.
+func isPeered(vnet, other infrav1.VnetSpec) bool {
+	for _, peering := range vnet.Peerings {
+		if sameVnet(peering.ResourceGroup, peering.RemoteVnetName, other.ResourceGroup, other.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSharedVnetSubnets validates that the subnets of two AzureClusters sharing a virtual network don't overlap,
+// unless they are the same subnet.
+func validateSharedVnetSubnets(azureCluster, other *infrav1.AzureCluster, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, subnet := range azureCluster.Spec.NetworkSpec.Subnets {
+		for _, otherSubnet := range other.Spec.NetworkSpec.Subnets {
+			if strings.EqualFold(subnet.Name, otherSubnet.Name) {
+				continue
+			}
+			for _, overlap := range overlaps(subnet.CIDRBlocks, otherSubnet.CIDRBlocks) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("cidrBlocks").Index(overlap.index), overlap.cidr,
+					fmt.Sprintf("CIDR overlaps with CIDR %s of subnet %s of AzureCluster %s, which shares the virtual network", overlap.other, otherSubnet.Name, other.Name)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateMachines validates the AzureMachines and AzureMachineTemplates against the AzureCluster they belong to: the
+// AzureCluster of the Cluster of their cluster name label or, without the label, the only AzureCluster of their
+// namespace.
+func (v *validator) validateMachines() field.ErrorList {
+	var allErrs field.ErrorList
+	for _, machine := range v.objects.AzureMachines {
+		azureCluster := v.azureClusterOf(machine.Namespace, machine.Labels[clusterv1.ClusterNameLabel])
+		fldPath := objectPath(azureMachineKind, machine.Namespace, machine.Name).Child("spec")
+		allErrs = append(allErrs, v.validateMachineSpec(machine.Spec, azureCluster, fldPath)...)
+	}
+	for _, template := range v.objects.AzureMachineTemplates {
+		azureCluster := v.azureClusterOf(template.Namespace, template.Labels[clusterv1.ClusterNameLabel])
+		fldPath := objectPath(azureMachineTemplateKind, template.Namespace, template.Name).Child("spec", "template", "spec")
+		allErrs = append(allErrs, v.validateMachineSpec(template.Spec.Template.Spec, azureCluster, fldPath)...)
+	}
+	return allErrs
+}
+
+// validateMachineSpec validates that the subnets of a machine are subnets of its AzureCluster, and that its VM size,
+// zone and disk types are available in the location of its AzureCluster.
+func (v *validator) validateMachineSpec(spec infrav1.AzureMachineSpec, azureCluster *infrav1.AzureCluster, fldPath *field.Path) field.ErrorList {
+	if azureCluster == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+
+	subnets := azureCluster.Spec.NetworkSpec.Subnets
+	if spec.SubnetName != "" && !hasSubnet(subnets, spec.SubnetName) {
+		allErrs = append(allErrs, field.NotFound(fldPath.Child("subnetName"), spec.SubnetName))
+	}
+	for i, nic := range spec.NetworkInterfaces {
+		if nic.SubnetName != "" && !hasSubnet(subnets, nic.SubnetName) {
+			allErrs = append(allErrs, field.NotFound(fldPath.Child("networkInterfaces").Index(i).Child("subnetName"), nic.SubnetName))
+		}
+	}
+
+	catalog, ok := v.catalogs[strings.ToLower(azureCluster.Spec.Location)]
+	if !ok {
+		return allErrs
+	}
+	var size *resourceskus.VMSize
+	for i := range catalog.VMSizes {
+		if strings.EqualFold(catalog.VMSizes[i].Name, spec.VMSize) {
+			size = &catalog.VMSizes[i]
+			break
+		}
+	}
+	if size == nil {
+		return append(allErrs, field.Invalid(fldPath.Child("vmSize"), spec.VMSize,
+			fmt.Sprintf("VM size is not available in location %s", catalog.Location)))
+	}
+
+	zone := ptr.Deref(spec.FailureDomain, "")
+	if zone != "" && !contains(size.Zones, zone) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("failureDomain"), zone,
+			fmt.Sprintf("VM size %s is not available in zone %s of location %s", size.Name, zone, catalog.Location)))
+	}
+	if ptr.Deref(spec.AcceleratedNetworking, false) && !size.AcceleratedNetworking {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("acceleratedNetworking"), true,
+			fmt.Sprintf("VM size %s does not support accelerated networking", size.Name)))
+	}
+	if spec.OSDisk.DiffDiskSettings != nil && !size.EphemeralOSDisk {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("osDisk", "diffDiskSettings"), spec.OSDisk.DiffDiskSettings.Option,
+			fmt.Sprintf("VM size %s does not support ephemeral OS disks", size.Name)))
+	}
+	if spec.SecurityProfile != nil && ptr.Deref(spec.SecurityProfile.EncryptionAtHost, false) && !size.EncryptionAtHost {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("securityProfile", "encryptionAtHost"), true,
+			fmt.Sprintf("VM size %s does not support encryption at host", size.Name)))
+	}
+
+	if spec.OSDisk.ManagedDisk != nil {
+		allErrs = append(allErrs, validateDiskType(catalog, spec.OSDisk.ManagedDisk.StorageAccountType, zone,
+			fldPath.Child("osDisk", "managedDisk", "storageAccountType"))...)
+	}
+	for i, dataDisk := range spec.DataDisks {
+		if dataDisk.ManagedDisk != nil {
+			allErrs = append(allErrs, validateDiskType(catalog, dataDisk.ManagedDisk.StorageAccountType, zone,
+				fldPath.Child("dataDisks").Index(i).Child("managedDisk", "storageAccountType"))...)
+		}
+	}
+	return allErrs
+}
+
+// validateDiskType validates that a storage account type of managed disks is available in the location of catalog
+// and, for zonal machines, in their zone.
+func validateDiskType(catalog resourceskus.Catalog, storageAccountType, zone string, fldPath *field.Path) field.ErrorList {
+	if storageAccountType == "" {
+		return nil
+	}
+	for _, diskType := range catalog.DiskTypes {
+		if !strings.EqualFold(diskType.Name, storageAccountType) {
+			continue
+		}
+		if zone != "" && len(catalog.Zones) > 0 && !contains(diskType.Zones, zone) {
+			return field.ErrorList{field.Invalid(fldPath, storageAccountType,
+				fmt.Sprintf("disk type is not available in zone %s of location %s", zone, catalog.Location))}
+		}
+		return nil
+	}
+	return field.ErrorList{field.Invalid(fldPath, storageAccountType,
+		fmt.Sprintf("disk type is not available in location %s", catalog.Location))}
+}
+
+// hasSubnet returns true if subnets has a subnet named name.
+func hasSubnet(subnets infrav1.Subnets, name string) bool {
+	for _, subnet := range subnets {
+		if subnet.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// contains returns true if values contains value.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// overlap is a CIDR block overlapping with another.
+type overlap struct {
+	index int
+	cidr  string
+	other string
+}
+
+// overlaps returns the CIDR blocks of cidrs overlapping with one of others. Malformed CIDR blocks are left to the
+// webhooks.
+func overlaps(cidrs, others []string) []overlap {
+	var found []overlap
+	for i, cidr := range cidrs {
+		_, nw, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		for _, other := range others {
+			_, otherNw, err := net.ParseCIDR(other)
+			if err != nil {
+				continue
+			}
+			if nw.Contains(otherNw.IP) || otherNw.Contains(nw.IP) {
+				found = append(found, overlap{index: i, cidr: cidr, other: other})
+			}
+		}
+	}
+	return found
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustervalidation
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestValidate(t *testing.T) {
+	catalog := resourceskus.Catalog{
+		Location: "eastus",
+		Zones:    []string{"1", "2", "3"},
+		VMSizes: []resourceskus.VMSize{
+			{Name: "Standard_D2s_v3", VCPUs: 2, MemoryGB: 8, Zones: []string{"1", "2", "3"}, AcceleratedNetworking: true},
+			{Name: "Standard_B2s", VCPUs: 2, MemoryGB: 4, Zones: []string{"1", "2"}},
+		},
+		DiskTypes: []resourceskus.DiskType{
+			{Name: "Premium_LRS", Zones: []string{"1", "2", "3"}},
+			{Name: "UltraSSD_LRS", Zones: []string{"1"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		objects  func(*Objects)
+		catalogs []resourceskus.Catalog
+		expected []string
+	}{
+		{
+			name:     "valid cluster",
+			catalogs: []resourceskus.Catalog{catalog},
+		},
+		{
+			name: "missing infrastructure reference",
+			objects: func(o *Objects) {
+				o.AzureClusters = nil
+				o.AzureMachines = nil
+			},
+			expected: []string{
+				`Cluster[default/my-cluster].spec.infrastructureRef: Not found: "default/my-cluster"`,
+			},
+		},
+		{
+			name: "service CIDR overlapping with the pod CIDR and a subnet",
+			objects: func(o *Objects) {
+				o.Clusters[0].Spec.ClusterNetwork.Services.CIDRBlocks = []string{"10.1.0.0/24", "192.168.128.0/24"}
+			},
+			expected: []string{
+				`Cluster[default/my-cluster].spec.clusterNetwork.services.cidrBlocks[1]: Invalid value: "192.168.128.0/24": service CIDR overlaps with pod CIDR 192.168.0.0/16`,
+				`Cluster[default/my-cluster].spec.clusterNetwork.services.cidrBlocks[0]: Invalid value: "10.1.0.0/24": service CIDR overlaps with CIDR 10.1.0.0/16 of subnet my-cluster-node-subnet of AzureCluster my-cluster`,
+			},
+		},
+		{
+			name: "missing identity",
+			objects: func(o *Objects) {
+				o.AzureClusterIdentities = nil
+			},
+			expected: []string{
+				`AzureCluster[default/my-cluster].spec.identityRef: Not found: "default/my-identity"`,
+			},
+		},
+		{
+			name: "identity not allowing the namespace",
+			objects: func(o *Objects) {
+				o.AzureClusterIdentities[0].Spec.AllowedNamespaces = &infrav1.AllowedNamespaces{NamespaceList: []string{"other"}}
+			},
+			expected: []string{
+				`AzureCluster[default/my-cluster].spec.identityRef: Forbidden: AzureClusterIdentity default/my-identity does not allow namespace default`,
+			},
+		},
+		{
+			name: "identity allowing namespaces by label",
+			objects: func(o *Objects) {
+				o.AzureClusterIdentities[0].Spec.AllowedNamespaces = &infrav1.AllowedNamespaces{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"capz": "true"}},
+				}
+			},
+		},
+		{
+			name: "peered virtual networks overlapping",
+			objects: func(o *Objects) {
+				other := newAzureCluster("other-cluster", "10.2.0.0/16")
+				other.Spec.NetworkSpec.Vnet.Peerings = infrav1.VnetPeerings{
+					{VnetPeeringClassSpec: infrav1.VnetPeeringClassSpec{ResourceGroup: "my-cluster", RemoteVnetName: "my-cluster-vnet"}},
+				}
+				o.AzureClusters = append(o.AzureClusters, *other)
+			},
+			expected: []string{
+				`AzureCluster[default/other-cluster].spec.networkSpec.vnet.cidrBlocks[0]: Invalid value: "10.2.0.0/16": CIDR overlaps with CIDR 10.0.0.0/8 of the peered virtual network my-cluster-vnet of AzureCluster my-cluster`,
+			},
+		},
+		{
+			name: "shared virtual network with overlapping subnets",
+			objects: func(o *Objects) {
+				other := newAzureCluster("other-cluster", "10.0.0.0/16")
+				other.Spec.NetworkSpec.Vnet.ResourceGroup = "my-cluster"
+				other.Spec.NetworkSpec.Vnet.Name = "my-cluster-vnet"
+				other.Spec.NetworkSpec.Subnets = infrav1.Subnets{
+					{SubnetClassSpec: infrav1.SubnetClassSpec{Name: "other-cluster-node-subnet", Role: infrav1.SubnetNode, CIDRBlocks: []string{"10.1.2.0/24"}}},
+					{SubnetClassSpec: infrav1.SubnetClassSpec{Name: "my-cluster-controlplane-subnet", Role: infrav1.SubnetControlPlane, CIDRBlocks: []string{"10.0.0.0/24"}}},
+				}
+				o.AzureClusters = append(o.AzureClusters, *other)
+			},
+			expected: []string{
+				`AzureCluster[default/other-cluster].spec.networkSpec.subnets[0].cidrBlocks[0]: Invalid value: "10.1.2.0/24": CIDR overlaps with CIDR 10.1.0.0/16 of subnet my-cluster-node-subnet of AzureCluster my-cluster, which shares the virtual network`,
+			},
+		},
+		{
+			name: "machine in a missing subnet",
+			objects: func(o *Objects) {
+				o.AzureMachineTemplates[0].Spec.Template.Spec.NetworkInterfaces = []infrav1.NetworkInterface{{SubnetName: "missing"}}
+			},
+			expected: []string{
+				`AzureMachineTemplate[default/my-cluster-md-0].spec.template.spec.networkInterfaces[0].subnetName: Not found: "missing"`,
+			},
+		},
+		{
+			name:     "VM size not available",
+			catalogs: []resourceskus.Catalog{catalog},
+			objects: func(o *Objects) {
+				o.AzureMachineTemplates[0].Spec.Template.Spec.VMSize = "Standard_M416ms_v2"
+			},
+			expected: []string{
+				`AzureMachineTemplate[default/my-cluster-md-0].spec.template.spec.vmSize: Invalid value: "Standard_M416ms_v2": VM size is not available in location eastus`,
+			},
+		},
+		{
+			name: "VM size not checked without a catalog of the location",
+			catalogs: []resourceskus.Catalog{func() resourceskus.Catalog {
+				c := catalog
+				c.Location = "westus"
+				return c
+			}()},
+			objects: func(o *Objects) {
+				o.AzureMachineTemplates[0].Spec.Template.Spec.VMSize = "Standard_M416ms_v2"
+			},
+		},
+		{
+			name:     "zone and features not available for the VM size",
+			catalogs: []resourceskus.Catalog{catalog},
+			objects: func(o *Objects) {
+				spec := &o.AzureMachines[0].Spec
+				spec.VMSize = "Standard_B2s"
+				spec.FailureDomain = ptr.To("3")
+				spec.AcceleratedNetworking = ptr.To(true)
+				spec.OSDisk.DiffDiskSettings = &infrav1.DiffDiskSettings{Option: "Local"}
+				spec.SecurityProfile = &infrav1.SecurityProfile{EncryptionAtHost: ptr.To(true)}
+			},
+			expected: []string{
+				`AzureMachine[default/my-cluster-control-plane-abcde].spec.failureDomain: Invalid value: "3": VM size Standard_B2s is not available in zone 3 of location eastus`,
+				`AzureMachine[default/my-cluster-control-plane-abcde].spec.acceleratedNetworking: Invalid value: true: VM size Standard_B2s does not support accelerated networking`,
+				`AzureMachine[default/my-cluster-control-plane-abcde].spec.osDisk.diffDiskSettings: Invalid value: "Local": VM size Standard_B2s does not support ephemeral OS disks`,
+				`AzureMachine[default/my-cluster-control-plane-abcde].spec.securityProfile.encryptionAtHost: Invalid value: true: VM size Standard_B2s does not support encryption at host`,
+			},
+		},
+		{
+			name:     "disk types not available",
+			catalogs: []resourceskus.Catalog{catalog},
+			objects: func(o *Objects) {
+				spec := &o.AzureMachines[0].Spec
+				spec.OSDisk.ManagedDisk = &infrav1.ManagedDiskParameters{StorageAccountType: "PremiumV2_LRS"}
+				spec.DataDisks = []infrav1.DataDisk{
+					{NameSuffix: "etcddisk", ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "UltraSSD_LRS"}},
+				}
+			},
+			expected: []string{
+				`AzureMachine[default/my-cluster-control-plane-abcde].spec.osDisk.managedDisk.storageAccountType: Invalid value: "PremiumV2_LRS": disk type is not available in location eastus`,
+				`AzureMachine[default/my-cluster-control-plane-abcde].spec.dataDisks[0].managedDisk.storageAccountType: Invalid value: "UltraSSD_LRS": disk type is not available in zone 2 of location eastus`,
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			objects := newObjects()
+			if tc.objects != nil {
+				tc.objects(&objects)
+			}
+			errs := Validate(objects, tc.catalogs...)
+			messages := make([]string, len(errs))
+			for i, err := range errs {
+				messages[i] = err.Error()
+			}
+			g.Expect(messages).To(ConsistOf(tc.expected))
+		})
+	}
+}
+
+// newObjects returns the objects of a valid cluster.
+func newObjects() Objects {
+	return Objects{
+		Clusters: []clusterv1.Cluster{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+				Spec: clusterv1.ClusterSpec{
+					ClusterNetwork: &clusterv1.ClusterNetwork{
+						Pods:     &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+						Services: &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.96.0.0/12"}},
+					},
+					InfrastructureRef: &corev1.ObjectReference{
+						APIVersion: infrav1.GroupVersion.String(),
+						Kind:       "AzureCluster",
+						Name:       "my-cluster",
+					},
+				},
+			},
+		},
+		AzureClusters: []infrav1.AzureCluster{*newAzureCluster("my-cluster", "10.0.0.0/8")},
+		AzureMachines: []infrav1.AzureMachine{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster-control-plane-abcde",
+					Namespace: "default",
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: "my-cluster"},
+				},
+				Spec: infrav1.AzureMachineSpec{
+					VMSize:        "Standard_D2s_v3",
+					FailureDomain: ptr.To("2"),
+					OSDisk:        infrav1.OSDisk{ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "Premium_LRS"}},
+				},
+			},
+		},
+		AzureMachineTemplates: []infrav1.AzureMachineTemplate{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-md-0", Namespace: "default"},
+				Spec: infrav1.AzureMachineTemplateSpec{
+					Template: infrav1.AzureMachineTemplateResource{
+						Spec: infrav1.AzureMachineSpec{
+							VMSize:     "Standard_D2s_v3",
+							SubnetName: "my-cluster-node-subnet",
+						},
+					},
+				},
+			},
+		},
+		AzureClusterIdentities: []infrav1.AzureClusterIdentity{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-identity", Namespace: "default"},
+				Spec: infrav1.AzureClusterIdentitySpec{
+					Type:              infrav1.WorkloadIdentity,
+					AllowedNamespaces: &infrav1.AllowedNamespaces{},
+				},
+			},
+		},
+	}
+}
+
+// newAzureCluster returns an AzureCluster in eastus whose virtual network has the given CIDR block.
+func newAzureCluster(name, vnetCIDR string) *infrav1.AzureCluster {
+	return &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: infrav1.AzureClusterSpec{
+			ResourceGroup: name,
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				Location: "eastus",
+				IdentityRef: &corev1.ObjectReference{
+					Kind: "AzureClusterIdentity",
+					Name: "my-identity",
+				},
+			},
+			NetworkSpec: infrav1.NetworkSpec{
+				Vnet: infrav1.VnetSpec{
+					VnetClassSpec: infrav1.VnetClassSpec{CIDRBlocks: []string{vnetCIDR}},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustervalidation
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// templateVariable matches the clusterctl template variables, such as ${AZURE_LOCATION} or ${CLUSTER_NAME:=default}.
+var templateVariable = regexp.MustCompile(`\$\{\s*([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Decode decodes the objects of rendered cluster definitions, YAML or JSON documents separated by "---", and adds them
+// to objects. Documents of other kinds, such as KubeadmControlPlanes, are ignored. It returns an error if a decoded
+// object still has template variables, which means the cluster definitions were not rendered with all their variables.
+func (o *Objects) Decode(data []byte) error {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var u unstructured.Unstructured
+		if err := decoder.Decode(&u.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return errors.Wrap(err, "failed to decode the cluster definitions")
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+
+		gvk := u.GroupVersionKind()
+		// The object is decoded into a local value, and only added to objects once it is fully decoded.
+		var obj interface{}
+		var add func()
+		switch {
+		case gvk == clusterv1.GroupVersion.WithKind(clusterKind):
+			cluster := &clusterv1.Cluster{}
+			obj, add = cluster, func() { o.Clusters = append(o.Clusters, *cluster) }
+		case gvk == infrav1.GroupVersion.WithKind(azureClusterKind):
+			azureCluster := &infrav1.AzureCluster{}
+			obj, add = azureCluster, func() { o.AzureClusters = append(o.AzureClusters, *azureCluster) }
+		case gvk == infrav1.GroupVersion.WithKind(azureMachineKind):
+			azureMachine := &infrav1.AzureMachine{}
+			obj, add = azureMachine, func() { o.AzureMachines = append(o.AzureMachines, *azureMachine) }
+		case gvk == infrav1.GroupVersion.WithKind(azureMachineTemplateKind):
+			template := &infrav1.AzureMachineTemplate{}
+			obj, add = template, func() { o.AzureMachineTemplates = append(o.AzureMachineTemplates, *template) }
+		case gvk == infrav1.GroupVersion.WithKind(azureClusterIdentityKind):
+			identity := &infrav1.AzureClusterIdentity{}
+			obj, add = identity, func() { o.AzureClusterIdentities = append(o.AzureClusterIdentities, *identity) }
+		default:
+			continue
+		}
+
+		if variables, err := templateVariables(u.Object); err != nil {
+			return err
+		} else if len(variables) > 0 {
+			return errors.Errorf("%s %s/%s has unresolved template variables: %s", gvk.Kind, u.GetNamespace(), u.GetName(), strings.Join(variables, ", "))
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+			return errors.Wrapf(err, "failed to decode %s %s/%s", gvk.Kind, u.GetNamespace(), u.GetName())
+		}
+		add()
+	}
+}
+
+// templateVariables returns the sorted names of the template variables left in an object.
+func templateVariables(object map[string]interface{}) ([]string, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal object")
+	}
+	names := map[string]bool{}
+	for _, match := range templateVariable.FindAllSubmatch(data, -1) {
+		names[string(match[1])] = true
+	}
+	variables := make([]string, 0, len(names))
+	for name := range names {
+		variables = append(variables, name)
+	}
+	sort.Strings(variables)
+	return variables, nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustervalidation
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const clusterDefinitions = `apiVersion: cluster.x-k8s.io/v1beta1
+kind: Cluster
+metadata:
+  name: my-cluster
+  namespace: default
+spec:
+  clusterNetwork:
+    pods:
+      cidrBlocks:
+      - 192.168.0.0/16
+  infrastructureRef:
+    apiVersion: infrastructure.cluster.x-k8s.io/v1beta1
+    kind: AzureCluster
+    name: my-cluster
+---
+apiVersion: infrastructure.cluster.x-k8s.io/v1beta1
+kind: AzureCluster
+metadata:
+  name: my-cluster
+  namespace: default
+spec:
+  location: eastus
+  identityRef:
+    kind: AzureClusterIdentity
+    name: my-identity
+---
+apiVersion: controlplane.cluster.x-k8s.io/v1beta1
+kind: KubeadmControlPlane
+metadata:
+  name: my-cluster-control-plane
+  namespace: default
+spec:
+  kubeadmConfigSpec:
+    preKubeadmCommands:
+    - echo ${HOSTNAME}
+---
+apiVersion: infrastructure.cluster.x-k8s.io/v1beta1
+kind: AzureMachineTemplate
+metadata:
+  name: my-cluster-md-0
+  namespace: default
+spec:
+  template:
+    spec:
+      vmSize: Standard_D2s_v3
+---
+{"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1", "kind": "AzureClusterIdentity", "metadata": {"name": "my-identity", "namespace": "default"}, "spec": {"type": "WorkloadIdentity", "allowedNamespaces": {}}}
+`
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expectedErr string
+	}{
+		{
+			name: "rendered cluster definitions",
+			data: clusterDefinitions,
+		},
+		{
+			name: "unresolved template variables",
+			data: `apiVersion: infrastructure.cluster.x-k8s.io/v1beta1
+kind: AzureMachineTemplate
+metadata:
+  name: ${CLUSTER_NAME}-md-0
+  namespace: default
+spec:
+  template:
+    spec:
+      vmSize: ${AZURE_NODE_MACHINE_TYPE:=Standard_D2s_v3}
+`,
+			expectedErr: "AzureMachineTemplate default/${CLUSTER_NAME}-md-0 has unresolved template variables: AZURE_NODE_MACHINE_TYPE, CLUSTER_NAME",
+		},
+		{
+			name:        "malformed document",
+			data:        "kind: [AzureCluster",
+			expectedErr: "failed to decode the cluster definitions",
+		},
+		{
+			name: "object not matching its kind",
+			data: `apiVersion: infrastructure.cluster.x-k8s.io/v1beta1
+kind: AzureCluster
+metadata:
+  name: my-cluster
+  namespace: default
+spec:
+  location: [eastus]
+`,
+			expectedErr: "failed to decode AzureCluster default/my-cluster",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var objects Objects
+			err := objects.Decode([]byte(tc.data))
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+				g.Expect(objects).To(Equal(Objects{}))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(objects.Clusters).To(HaveLen(1))
+			g.Expect(objects.Clusters[0].Spec.InfrastructureRef.Name).To(Equal("my-cluster"))
+			g.Expect(objects.AzureClusters).To(HaveLen(1))
+			g.Expect(objects.AzureClusters[0].Spec.Location).To(Equal("eastus"))
+			g.Expect(objects.AzureMachines).To(BeEmpty())
+			g.Expect(objects.AzureMachineTemplates).To(HaveLen(1))
+			g.Expect(objects.AzureMachineTemplates[0].Spec.Template.Spec.VMSize).To(Equal("Standard_D2s_v3"))
+			g.Expect(objects.AzureClusterIdentities).To(HaveLen(1))
+			g.Expect(Validate(objects)).To(BeEmpty())
+		})
+	}
+}
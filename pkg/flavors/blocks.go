@@ -0,0 +1,478 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flavors
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	clusterAPIVersion      = "cluster.x-k8s.io/v1beta1"
+	infraAPIVersion        = "infrastructure.cluster.x-k8s.io/v1beta1"
+	controlPlaneAPIVersion = "controlplane.cluster.x-k8s.io/v1beta1"
+	bootstrapAPIVersion    = "bootstrap.cluster.x-k8s.io/v1beta1"
+
+	// ClusterName is the name of the Cluster, and of the AzureCluster or AzureManagedControlPlane, in every flavor.
+	ClusterName = "${CLUSTER_NAME}"
+	// ControlPlaneName is the name of the KubeadmControlPlane and of its AzureMachineTemplate.
+	ControlPlaneName = "${CLUSTER_NAME}-control-plane"
+
+	localHostname = `{{ ds.meta_data["local_hostname"] }}`
+	sshPublicKey  = `${AZURE_SSH_PUBLIC_KEY_B64:=""}`
+)
+
+// WorkerName returns the name of the objects of a worker pool with the given suffix, e.g. ${CLUSTER_NAME}-md-0.
+func WorkerName(suffix string) string {
+	return "${CLUSTER_NAME}-" + suffix
+}
+
+// Cluster adds a Cluster and its AzureCluster, with a control plane and a node subnet.
+func Cluster() Block {
+	return func(t *Template) {
+		cluster := object(clusterAPIVersion, "Cluster", ClusterName)
+		set(cluster, list("192.168.0.0/16"), "spec", "clusterNetwork", "pods", "cidrBlocks")
+		set(cluster, ref(infraAPIVersion, "AzureCluster", ClusterName), "spec", "infrastructureRef")
+		set(cluster, ref(controlPlaneAPIVersion, "KubeadmControlPlane", ControlPlaneName), "spec", "controlPlaneRef")
+
+		azureCluster := object(infraAPIVersion, "AzureCluster", ClusterName)
+		set(azureCluster, "${AZURE_RESOURCE_GROUP:=${CLUSTER_NAME}}", "spec", "resourceGroup")
+		set(azureCluster, "${AZURE_LOCATION}", "spec", "location")
+		set(azureCluster, "${AZURE_SUBSCRIPTION_ID}", "spec", "subscriptionID")
+		set(azureCluster, "${AZURE_VNET_NAME:=${CLUSTER_NAME}-vnet}", "spec", "networkSpec", "vnet", "name")
+		set(azureCluster, []interface{}{
+			map[string]interface{}{"name": "control-plane-subnet", "role": "control-plane"},
+			map[string]interface{}{"name": "node-subnet", "role": "node"},
+		}, "spec", "networkSpec", "subnets")
+
+		t.Add(cluster, azureCluster)
+	}
+}
+
+// KubeadmControlPlane adds a KubeadmControlPlane and its AzureMachineTemplate, with a dedicated etcd data disk.
+func KubeadmControlPlane() Block {
+	return func(t *Template) {
+		kcp := object(controlPlaneAPIVersion, "KubeadmControlPlane", ControlPlaneName)
+		set(kcp, "${CONTROL_PLANE_MACHINE_COUNT}", "spec", "replicas")
+		set(kcp, "${KUBERNETES_VERSION}", "spec", "version")
+		set(kcp, ref(infraAPIVersion, "AzureMachineTemplate", ControlPlaneName), "spec", "machineTemplate", "infrastructureRef")
+		set(kcp, map[string]interface{}{
+			"initConfiguration": map[string]interface{}{
+				"nodeRegistration": nodeRegistration("/etc/kubernetes/azure.json"),
+			},
+			"joinConfiguration": map[string]interface{}{
+				"nodeRegistration": nodeRegistration("/etc/kubernetes/azure.json"),
+			},
+			"clusterConfiguration": map[string]interface{}{
+				"apiServer": map[string]interface{}{
+					"timeoutForControlPlane": "20m",
+					"extraArgs":              map[string]interface{}{"cloud-provider": "external"},
+				},
+				"controllerManager": map[string]interface{}{
+					"extraArgs": map[string]interface{}{
+						"allocate-node-cidrs": "false",
+						"cluster-name":        ClusterName,
+						"cloud-provider":      "external",
+					},
+				},
+				"etcd": map[string]interface{}{
+					"local": map[string]interface{}{
+						"dataDir":   "/var/lib/etcddisk/etcd",
+						"extraArgs": map[string]interface{}{"quota-backend-bytes": "8589934592"},
+					},
+				},
+			},
+			"files": []interface{}{
+				azureJSONFile(ControlPlaneName+"-azure-json", "control-plane-azure.json", "/etc/kubernetes/azure.json"),
+			},
+			"diskSetup": map[string]interface{}{
+				"partitions": []interface{}{
+					map[string]interface{}{
+						"device":    "/dev/disk/azure/scsi1/lun0",
+						"tableType": "gpt",
+						"layout":    true,
+						"overwrite": false,
+					},
+				},
+				"filesystems": []interface{}{
+					map[string]interface{}{
+						"label":      "etcd_disk",
+						"filesystem": "ext4",
+						"device":     "/dev/disk/azure/scsi1/lun0",
+						"extraOpts":  list("-E", "lazy_itable_init=1,lazy_journal_init=1"),
+					},
+					map[string]interface{}{
+						"label":      "ephemeral0",
+						"filesystem": "ext4",
+						"device":     "ephemeral0.1",
+						"replaceFS":  "ntfs",
+					},
+				},
+			},
+			"mounts":              []interface{}{list("LABEL=etcd_disk", "/var/lib/etcddisk")},
+			"preKubeadmCommands":  []interface{}{},
+			"postKubeadmCommands": []interface{}{},
+		}, "spec", "kubeadmConfigSpec")
+
+		amt := object(infraAPIVersion, "AzureMachineTemplate", ControlPlaneName)
+		set(amt, map[string]interface{}{
+			"vmSize": "${AZURE_CONTROL_PLANE_MACHINE_TYPE}",
+			"osDisk": map[string]interface{}{
+				"osType":     "Linux",
+				"diskSizeGB": int64(128),
+			},
+			"dataDisks": []interface{}{
+				map[string]interface{}{
+					"nameSuffix": "etcddisk",
+					"diskSizeGB": int64(256),
+					"lun":        int64(0),
+				},
+			},
+			"sshPublicKey": sshPublicKey,
+		}, "spec", "template", "spec")
+
+		t.Add(kcp, amt)
+	}
+}
+
+// LinuxWorkers adds a MachineDeployment of Linux machines, with its AzureMachineTemplate and KubeadmConfigTemplate,
+// named after the given suffix.
+func LinuxWorkers(suffix string) Block {
+	return func(t *Template) {
+		name := WorkerName(suffix)
+		amt := object(infraAPIVersion, "AzureMachineTemplate", name)
+		set(amt, map[string]interface{}{
+			"vmSize": "${AZURE_NODE_MACHINE_TYPE}",
+			"osDisk": map[string]interface{}{
+				"osType":     "Linux",
+				"diskSizeGB": int64(128),
+			},
+			"sshPublicKey": sshPublicKey,
+		}, "spec", "template", "spec")
+
+		kct := object(bootstrapAPIVersion, "KubeadmConfigTemplate", name)
+		set(kct, map[string]interface{}{
+			"preKubeadmCommands": []interface{}{},
+			"joinConfiguration": map[string]interface{}{
+				"nodeRegistration": nodeRegistration("/etc/kubernetes/azure.json"),
+			},
+			"files": []interface{}{
+				azureJSONFile(name+"-azure-json", "worker-node-azure.json", "/etc/kubernetes/azure.json"),
+			},
+		}, "spec", "template", "spec")
+
+		t.Add(machineDeployment(name), amt, kct)
+	}
+}
+
+// WindowsWorkers adds a MachineDeployment of Windows machines using containerd, with its AzureMachineTemplate and
+// KubeadmConfigTemplate, named after the given suffix. It labels the Cluster so the Windows CNI and CSI proxy addons
+// are installed.
+func WindowsWorkers(suffix string) Block {
+	return func(t *Template) {
+		name := WorkerName(suffix)
+		amt := object(infraAPIVersion, "AzureMachineTemplate", name)
+		amt.SetAnnotations(map[string]string{"runtime": "containerd"})
+		set(amt, map[string]interface{}{"runtime": "containerd"}, "spec", "template", "metadata", "annotations")
+		set(amt, map[string]interface{}{
+			"vmSize": "${AZURE_NODE_MACHINE_TYPE}",
+			"osDisk": map[string]interface{}{
+				"osType":      "Windows",
+				"diskSizeGB":  int64(128),
+				"managedDisk": map[string]interface{}{"storageAccountType": "Premium_LRS"},
+			},
+			"sshPublicKey": sshPublicKey,
+		}, "spec", "template", "spec")
+
+		registration := nodeRegistration("c:/k/azure.json")
+		registration["criSocket"] = "npipe:////./pipe/containerd-containerd"
+		kubeletArgs := registration["kubeletExtraArgs"].(map[string]interface{})
+		kubeletArgs["v"] = "2"
+		kubeletArgs["windows-priorityclass"] = "ABOVE_NORMAL_PRIORITY_CLASS"
+
+		kct := object(bootstrapAPIVersion, "KubeadmConfigTemplate", name)
+		set(kct, map[string]interface{}{
+			"users": []interface{}{
+				map[string]interface{}{
+					"name":              "capi",
+					"groups":            "Administrators",
+					"sshAuthorizedKeys": list(`${AZURE_SSH_PUBLIC_KEY:=""}`),
+				},
+			},
+			"preKubeadmCommands": []interface{}{},
+			"postKubeadmCommands": list(
+				"nssm set kubelet start SERVICE_AUTO_START",
+				"powershell C:/defender-exclude-calico.ps1",
+			),
+			"joinConfiguration": map[string]interface{}{
+				"nodeRegistration": registration,
+			},
+			"files": []interface{}{
+				azureJSONFile(name+"-azure-json", "worker-node-azure.json", "c:/k/azure.json"),
+				map[string]interface{}{
+					"path":        "C:/defender-exclude-calico.ps1",
+					"permissions": "0744",
+					"content": "Add-MpPreference -ExclusionProcess C:/opt/cni/bin/calico.exe\n" +
+						"Add-MpPreference -ExclusionProcess C:/opt/cni/bin/calico-ipam.exe",
+				},
+			},
+		}, "spec", "template", "spec")
+
+		if cluster := t.Get("Cluster", ClusterName); cluster != nil {
+			labels := cluster.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels["cni-windows"] = "calico"
+			labels["csi-proxy"] = "enabled"
+			labels["windows"] = "enabled"
+			cluster.SetLabels(labels)
+		}
+
+		t.Add(machineDeployment(name), amt, kct)
+	}
+}
+
+// PremiumOSDisk makes the worker pool with the given suffix use Premium SSD OS disks.
+func PremiumOSDisk(suffix string) Block {
+	return func(t *Template) {
+		set(t.Get("AzureMachineTemplate", WorkerName(suffix)), "Premium_LRS",
+			"spec", "template", "spec", "osDisk", "managedDisk", "storageAccountType")
+	}
+}
+
+// ClusterIdentity adds an AzureClusterIdentity backed by a service principal secret, and references it from the
+// AzureCluster or AzureManagedControlPlane of the template.
+func ClusterIdentity() Block {
+	return func(t *Template) {
+		identityRef := ref(infraAPIVersion, "AzureClusterIdentity", "${CLUSTER_IDENTITY_NAME}")
+		set(t.Get("AzureCluster", ClusterName), identityRef, "spec", "identityRef")
+		set(t.Get("AzureManagedControlPlane", ClusterName), identityRef, "spec", "identityRef")
+
+		identity := object(infraAPIVersion, "AzureClusterIdentity", "${CLUSTER_IDENTITY_NAME}")
+		identity.SetLabels(map[string]string{"clusterctl.cluster.x-k8s.io/move-hierarchy": "true"})
+		set(identity, map[string]interface{}{
+			"type":              "ServicePrincipal",
+			"allowedNamespaces": map[string]interface{}{},
+			"tenantID":          "${AZURE_TENANT_ID}",
+			"clientID":          "${AZURE_CLIENT_ID}",
+			"clientSecret": map[string]interface{}{
+				"name":      "${AZURE_CLUSTER_IDENTITY_SECRET_NAME}",
+				"namespace": "${AZURE_CLUSTER_IDENTITY_SECRET_NAMESPACE}",
+			},
+		}, "spec")
+
+		t.Add(identity)
+	}
+}
+
+// PrivateAPIServer makes the API server reachable only from the virtual network through an internal load balancer,
+// adds outbound load balancers for the nodes and control plane, and an Azure Bastion to reach the machines.
+func PrivateAPIServer() Block {
+	return func(t *Template) {
+		azureCluster := t.Get("AzureCluster", ClusterName)
+		set(azureCluster, map[string]interface{}{
+			"name": "${CLUSTER_NAME}-internal-lb",
+			"type": "Internal",
+		}, "spec", "networkSpec", "apiServerLB")
+		set(azureCluster, map[string]interface{}{"frontendIPsCount": int64(1)}, "spec", "networkSpec", "nodeOutboundLB")
+		set(azureCluster, map[string]interface{}{"frontendIPsCount": int64(1)}, "spec", "networkSpec", "controlPlaneOutboundLB")
+		set(azureCluster, map[string]interface{}{}, "spec", "bastionSpec", "azureBastion")
+
+		// The first control plane machine must route to itself for kubeadm init to succeed, as internal load balancers
+		// don't support hairpin routing. Joining machines route through the load balancer until they have joined.
+		hostsEntry := "echo '127.0.0.1   apiserver.${CLUSTER_NAME}.capz.io apiserver' >> /etc/hosts"
+		kcp := t.Get("KubeadmControlPlane", ControlPlaneName)
+		appendTo(kcp, list("if [ -f /tmp/kubeadm.yaml ] || [ -f /run/kubeadm/kubeadm.yaml ]; then "+hostsEntry+"; fi"),
+			"spec", "kubeadmConfigSpec", "preKubeadmCommands")
+		appendTo(kcp, list("if [ -f /tmp/kubeadm-join-config.yaml ] || [ -f /run/kubeadm/kubeadm-join-config.yaml ]; then "+hostsEntry+"; fi"),
+			"spec", "kubeadmConfigSpec", "postKubeadmCommands")
+	}
+}
+
+// IPv6Network makes the cluster IPv6-only for pods and services, with dual-stack subnets for the machines, and configures
+// the control plane and the Linux worker pools of the template accordingly.
+func IPv6Network() Block {
+	return func(t *Template) {
+		cluster := t.Get("Cluster", ClusterName)
+		set(cluster, list("2001:1234:5678:9a40::/58"), "spec", "clusterNetwork", "pods", "cidrBlocks")
+		set(cluster, list("fd00::/108"), "spec", "clusterNetwork", "services", "cidrBlocks")
+
+		azureCluster := t.Get("AzureCluster", ClusterName)
+		set(azureCluster, list("10.0.0.0/8", "2001:1234:5678:9a00::/56"), "spec", "networkSpec", "vnet", "cidrBlocks")
+		set(azureCluster, []interface{}{
+			map[string]interface{}{
+				"name":       "control-plane-subnet",
+				"role":       "control-plane",
+				"cidrBlocks": list("10.0.0.0/16", "2001:1234:5678:9abc::/64"),
+			},
+			map[string]interface{}{
+				"name":       "node-subnet",
+				"role":       "node",
+				"cidrBlocks": list("10.1.0.0/16", "2001:1234:5678:9abd::/64"),
+			},
+		}, "spec", "networkSpec", "subnets")
+
+		// This frees up port 53 on the host for the CoreDNS pods.
+		freeDNSPort := list(
+			`echo "DNSStubListener=no" >> /etc/systemd/resolved.conf`,
+			"mv /etc/resolv.conf /etc/resolv.conf.OLD && ln -s /run/systemd/resolve/resolv.conf /etc/resolv.conf",
+			"systemctl restart systemd-resolved",
+		)
+
+		if kcp := t.Get("KubeadmControlPlane", ControlPlaneName); kcp != nil {
+			spec := []string{"spec", "kubeadmConfigSpec"}
+			appendTo(kcp, freeDNSPort, join(spec, "postKubeadmCommands")...)
+			set(kcp, "fd00::10", join(spec, "initConfiguration", "nodeRegistration", "kubeletExtraArgs", "cluster-dns")...)
+			set(kcp, "fd00::10", join(spec, "joinConfiguration", "nodeRegistration", "kubeletExtraArgs", "cluster-dns")...)
+			set(kcp, localAPIEndpoint(), join(spec, "initConfiguration", "localAPIEndpoint")...)
+			set(kcp, localAPIEndpoint(), join(spec, "joinConfiguration", "controlPlane", "localAPIEndpoint")...)
+			set(kcp, "::", join(spec, "clusterConfiguration", "apiServer", "extraArgs", "bind-address")...)
+			set(kcp, "::", join(spec, "clusterConfiguration", "scheduler", "extraArgs", "bind-address")...)
+			// Calico requires the controller manager to allocate node CIDRs and configure cloud routes with IPv6.
+			controllerManagerArgs := join(spec, "clusterConfiguration", "controllerManager", "extraArgs")
+			set(kcp, "true", join(controllerManagerArgs, "allocate-node-cidrs")...)
+			set(kcp, "2001:1234:5678:9a40::/58", join(controllerManagerArgs, "cluster-cidr")...)
+			set(kcp, "true", join(controllerManagerArgs, "configure-cloud-routes")...)
+			set(kcp, "::", join(controllerManagerArgs, "bind-address")...)
+		}
+
+		for _, obj := range t.Objects {
+			switch obj.GetKind() {
+			case "AzureMachineTemplate":
+				osType, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "spec", "osDisk", "osType")
+				if osType == "Linux" {
+					set(obj, true, "spec", "template", "spec", "enableIPForwarding")
+				}
+			case "KubeadmConfigTemplate":
+				spec := []string{"spec", "template", "spec"}
+				if _, windows, _ := unstructured.NestedString(obj.Object, join(spec, "joinConfiguration", "nodeRegistration", "criSocket")...); windows {
+					continue
+				}
+				appendTo(obj, freeDNSPort, join(spec, "postKubeadmCommands")...)
+				set(obj, "[fd00::10]", join(spec, "joinConfiguration", "nodeRegistration", "kubeletExtraArgs", "cluster-dns")...)
+			}
+		}
+	}
+}
+
+// AKSCluster adds a Cluster managed by AKS, with its AzureManagedControlPlane and AzureManagedCluster.
+func AKSCluster() Block {
+	return func(t *Template) {
+		cluster := object(clusterAPIVersion, "Cluster", ClusterName)
+		set(cluster, list("192.168.0.0/16"), "spec", "clusterNetwork", "services", "cidrBlocks")
+		set(cluster, ref(infraAPIVersion, "AzureManagedControlPlane", ClusterName), "spec", "controlPlaneRef")
+		set(cluster, ref(infraAPIVersion, "AzureManagedCluster", ClusterName), "spec", "infrastructureRef")
+
+		controlPlane := object(infraAPIVersion, "AzureManagedControlPlane", ClusterName)
+		set(controlPlane, map[string]interface{}{
+			"subscriptionID":    "${AZURE_SUBSCRIPTION_ID}",
+			"resourceGroupName": "${AZURE_RESOURCE_GROUP:=${CLUSTER_NAME}}",
+			"location":          "${AZURE_LOCATION}",
+			"sshPublicKey":      sshPublicKey,
+			"version":           "${KUBERNETES_VERSION}",
+		}, "spec")
+
+		t.Add(cluster, controlPlane, object(infraAPIVersion, "AzureManagedCluster", ClusterName))
+	}
+}
+
+// AKSMachinePool adds a MachinePool and its AzureManagedMachinePool, an AKS node pool with the given name and mode,
+// System or User.
+func AKSMachinePool(poolName, mode string) Block {
+	return func(t *Template) {
+		name := WorkerName(poolName)
+		mp := object(clusterAPIVersion, "MachinePool", name)
+		set(mp, ClusterName, "spec", "clusterName")
+		set(mp, "${WORKER_MACHINE_COUNT}", "spec", "replicas")
+		set(mp, map[string]interface{}{}, "spec", "template", "metadata")
+		set(mp, map[string]interface{}{
+			"bootstrap":         map[string]interface{}{"dataSecretName": ""},
+			"clusterName":       ClusterName,
+			"infrastructureRef": ref(infraAPIVersion, "AzureManagedMachinePool", name),
+			"version":           "${KUBERNETES_VERSION}",
+		}, "spec", "template", "spec")
+
+		ammp := object(infraAPIVersion, "AzureManagedMachinePool", name)
+		set(ammp, map[string]interface{}{
+			"mode": mode,
+			"sku":  "${AZURE_NODE_MACHINE_TYPE}",
+			"name": poolName,
+		}, "spec")
+
+		t.Add(mp, ammp)
+	}
+}
+
+func machineDeployment(name string) *unstructured.Unstructured {
+	md := object(clusterAPIVersion, "MachineDeployment", name)
+	set(md, ClusterName, "spec", "clusterName")
+	set(md, "${WORKER_MACHINE_COUNT}", "spec", "replicas")
+	set(md, map[string]interface{}{}, "spec", "selector", "matchLabels")
+	set(md, map[string]interface{}{
+		"clusterName":       ClusterName,
+		"version":           "${KUBERNETES_VERSION}",
+		"bootstrap":         map[string]interface{}{"configRef": ref(bootstrapAPIVersion, "KubeadmConfigTemplate", name)},
+		"infrastructureRef": ref(infraAPIVersion, "AzureMachineTemplate", name),
+	}, "spec", "template", "spec")
+	return md
+}
+
+func ref(apiVersion, kind, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"name":       name,
+	}
+}
+
+func nodeRegistration(azureJSONPath string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": localHostname,
+		"kubeletExtraArgs": map[string]interface{}{
+			"azure-container-registry-config": azureJSONPath,
+			"cloud-provider":                  "external",
+		},
+	}
+}
+
+func azureJSONFile(secretName, key, path string) map[string]interface{} {
+	return map[string]interface{}{
+		"contentFrom": map[string]interface{}{
+			"secret": map[string]interface{}{
+				"name": secretName,
+				"key":  key,
+			},
+		},
+		"owner":       "root:root",
+		"path":        path,
+		"permissions": "0644",
+	}
+}
+
+func localAPIEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"advertiseAddress": "::",
+		"bindPort":         int64(6443),
+	}
+}
+
+// join returns a new path made of the elements of base followed by elems.
+func join(base []string, elems ...string) []string {
+	path := make([]string, 0, len(base)+len(elems))
+	path = append(path, base...)
+	return append(path, elems...)
+}
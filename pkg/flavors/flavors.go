@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flavors
+
+// Default is a self-managed cluster with a kubeadm control plane and a pool of Linux workers.
+func Default() Flavor {
+	return Flavor{
+		Name: "default",
+		Blocks: []Block{
+			Cluster(),
+			KubeadmControlPlane(),
+			LinuxWorkers("md-0"),
+			ClusterIdentity(),
+		},
+	}
+}
+
+// Private is the default flavor with an API server only reachable from the virtual network.
+func Private() Flavor {
+	return Default().With("private", PrivateAPIServer())
+}
+
+// IPv6 is the default flavor with IPv6 pod and service networks.
+func IPv6() Flavor {
+	return Flavor{
+		Name: "ipv6",
+		Blocks: []Block{
+			Cluster(),
+			KubeadmControlPlane(),
+			ClusterIdentity(),
+			LinuxWorkers("md-0"),
+			PremiumOSDisk("md-0"),
+			IPv6Network(),
+		},
+	}
+}
+
+// NvidiaGPU is the default flavor with workers meant to run on GPU-enabled virtual machine sizes, such as N-series
+// sizes, set through AZURE_NODE_MACHINE_TYPE.
+func NvidiaGPU() Flavor {
+	return Flavor{
+		Name: "nvidia-gpu",
+		Blocks: []Block{
+			Cluster(),
+			KubeadmControlPlane(),
+			ClusterIdentity(),
+			LinuxWorkers("md-0"),
+			PremiumOSDisk("md-0"),
+		},
+	}
+}
+
+// Windows is the default flavor with an additional pool of Windows workers.
+func Windows() Flavor {
+	return Default().With("windows", WindowsWorkers("md-win"))
+}
+
+// AKS is a cluster managed by AKS, with a System and a User node pool.
+func AKS() Flavor {
+	return Flavor{
+		Name: "aks",
+		Blocks: []Block{
+			AKSCluster(),
+			AKSMachinePool("pool0", "System"),
+			AKSMachinePool("pool1", "User"),
+			ClusterIdentity(),
+		},
+	}
+}
+
+// All returns the flavors defined in this package.
+func All() []Flavor {
+	return []Flavor{
+		Default(),
+		Private(),
+		IPv6(),
+		NvidiaGPU(),
+		Windows(),
+		AKS(),
+	}
+}
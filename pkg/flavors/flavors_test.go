@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flavors_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/flavors"
+	"sigs.k8s.io/yaml"
+)
+
+func TestFlavors(t *testing.T) {
+	tests := []struct {
+		flavor    flavors.Flavor
+		wantKinds []string
+		check     func(g *WithT, tmpl *flavors.Template)
+	}{
+		{
+			flavor:    flavors.Default(),
+			wantKinds: []string{"Cluster", "AzureCluster", "KubeadmControlPlane", "AzureMachineTemplate", "MachineDeployment", "AzureMachineTemplate", "KubeadmConfigTemplate", "AzureClusterIdentity"},
+			check: func(g *WithT, tmpl *flavors.Template) {
+				name, _, _ := unstructured.NestedString(tmpl.Get("AzureCluster", flavors.ClusterName).Object, "spec", "identityRef", "name")
+				g.Expect(name).To(Equal("${CLUSTER_IDENTITY_NAME}"))
+			},
+		},
+		{
+			flavor:    flavors.Private(),
+			wantKinds: []string{"Cluster", "AzureCluster", "KubeadmControlPlane", "AzureMachineTemplate", "MachineDeployment", "AzureMachineTemplate", "KubeadmConfigTemplate", "AzureClusterIdentity"},
+			check: func(g *WithT, tmpl *flavors.Template) {
+				lbType, _, _ := unstructured.NestedString(tmpl.Get("AzureCluster", flavors.ClusterName).Object, "spec", "networkSpec", "apiServerLB", "type")
+				g.Expect(lbType).To(Equal("Internal"))
+				preKubeadm, _, _ := unstructured.NestedStringSlice(tmpl.Get("KubeadmControlPlane", flavors.ControlPlaneName).Object, "spec", "kubeadmConfigSpec", "preKubeadmCommands")
+				g.Expect(preKubeadm).To(HaveLen(1))
+			},
+		},
+		{
+			flavor:    flavors.IPv6(),
+			wantKinds: []string{"Cluster", "AzureCluster", "KubeadmControlPlane", "AzureMachineTemplate", "AzureClusterIdentity", "MachineDeployment", "AzureMachineTemplate", "KubeadmConfigTemplate"},
+			check: func(g *WithT, tmpl *flavors.Template) {
+				services, _, _ := unstructured.NestedStringSlice(tmpl.Get("Cluster", flavors.ClusterName).Object, "spec", "clusterNetwork", "services", "cidrBlocks")
+				g.Expect(services).To(ConsistOf("fd00::/108"))
+				forwarding, _, _ := unstructured.NestedBool(tmpl.Get("AzureMachineTemplate", flavors.WorkerName("md-0")).Object, "spec", "template", "spec", "enableIPForwarding")
+				g.Expect(forwarding).To(BeTrue())
+				dns, _, _ := unstructured.NestedString(tmpl.Get("KubeadmConfigTemplate", flavors.WorkerName("md-0")).Object, "spec", "template", "spec", "joinConfiguration", "nodeRegistration", "kubeletExtraArgs", "cluster-dns")
+				g.Expect(dns).To(Equal("[fd00::10]"))
+			},
+		},
+		{
+			flavor:    flavors.NvidiaGPU(),
+			wantKinds: []string{"Cluster", "AzureCluster", "KubeadmControlPlane", "AzureMachineTemplate", "AzureClusterIdentity", "MachineDeployment", "AzureMachineTemplate", "KubeadmConfigTemplate"},
+			check: func(g *WithT, tmpl *flavors.Template) {
+				diskType, _, _ := unstructured.NestedString(tmpl.Get("AzureMachineTemplate", flavors.WorkerName("md-0")).Object, "spec", "template", "spec", "osDisk", "managedDisk", "storageAccountType")
+				g.Expect(diskType).To(Equal("Premium_LRS"))
+			},
+		},
+		{
+			flavor:    flavors.Windows(),
+			wantKinds: []string{"Cluster", "AzureCluster", "KubeadmControlPlane", "AzureMachineTemplate", "MachineDeployment", "AzureMachineTemplate", "KubeadmConfigTemplate", "AzureClusterIdentity", "MachineDeployment", "AzureMachineTemplate", "KubeadmConfigTemplate"},
+			check: func(g *WithT, tmpl *flavors.Template) {
+				g.Expect(tmpl.Get("Cluster", flavors.ClusterName).GetLabels()).To(HaveKeyWithValue("windows", "enabled"))
+				osType, _, _ := unstructured.NestedString(tmpl.Get("AzureMachineTemplate", flavors.WorkerName("md-win")).Object, "spec", "template", "spec", "osDisk", "osType")
+				g.Expect(osType).To(Equal("Windows"))
+			},
+		},
+		{
+			flavor:    flavors.AKS(),
+			wantKinds: []string{"Cluster", "AzureManagedControlPlane", "AzureManagedCluster", "MachinePool", "AzureManagedMachinePool", "MachinePool", "AzureManagedMachinePool", "AzureClusterIdentity"},
+			check: func(g *WithT, tmpl *flavors.Template) {
+				name, _, _ := unstructured.NestedString(tmpl.Get("AzureManagedControlPlane", flavors.ClusterName).Object, "spec", "identityRef", "name")
+				g.Expect(name).To(Equal("${CLUSTER_IDENTITY_NAME}"))
+				mode, _, _ := unstructured.NestedString(tmpl.Get("AzureManagedMachinePool", flavors.WorkerName("pool0")).Object, "spec", "mode")
+				g.Expect(mode).To(Equal("System"))
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.flavor.Name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			tmpl := tc.flavor.Template()
+
+			var kinds []string
+			for _, obj := range tmpl.Objects {
+				kinds = append(kinds, obj.GetKind())
+			}
+			g.Expect(kinds).To(Equal(tc.wantKinds))
+			tc.check(g, tmpl)
+
+			var buf bytes.Buffer
+			g.Expect(tmpl.Render(&buf)).To(Succeed())
+			docs := strings.Split(strings.TrimPrefix(buf.String(), "---\n"), "---\n")
+			g.Expect(docs).To(HaveLen(len(tmpl.Objects)))
+			for _, doc := range docs {
+				obj := &unstructured.Unstructured{}
+				g.Expect(yaml.Unmarshal([]byte(doc), &obj.Object)).To(Succeed())
+				g.Expect(obj.GetName()).NotTo(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestRenderUnquotedVariables(t *testing.T) {
+	g := NewWithT(t)
+	var buf bytes.Buffer
+	g.Expect(flavors.Default().Template().Render(&buf)).To(Succeed())
+	// Variables in integer fields must not be quoted, so that they are integers once substituted.
+	g.Expect(buf.String()).To(ContainSubstring("replicas: ${CONTROL_PLANE_MACHINE_COUNT}\n"))
+	g.Expect(buf.String()).To(ContainSubstring("replicas: ${WORKER_MACHINE_COUNT}\n"))
+}
+
+func TestFlavorWith(t *testing.T) {
+	g := NewWithT(t)
+	custom := flavors.Default().With("custom", func(t *flavors.Template) {
+		azureCluster := t.Get("AzureCluster", flavors.ClusterName)
+		_ = unstructured.SetNestedField(azureCluster.Object, "${AZURE_NETWORK_RESOURCE_GROUP}", "spec", "networkResourceGroup")
+	})
+	g.Expect(custom.FileName()).To(Equal("cluster-template-custom.yaml"))
+	g.Expect(flavors.Default().FileName()).To(Equal("cluster-template.yaml"))
+
+	rg, _, _ := unstructured.NestedString(custom.Template().Get("AzureCluster", flavors.ClusterName).Object, "spec", "networkResourceGroup")
+	g.Expect(rg).To(Equal("${AZURE_NETWORK_RESOURCE_GROUP}"))
+	_, found, _ := unstructured.NestedString(flavors.Default().Template().Get("AzureCluster", flavors.ClusterName).Object, "spec", "networkResourceGroup")
+	g.Expect(found).To(BeFalse())
+}
+
+func TestWrite(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+	g.Expect(flavors.Write(dir, flavors.Default(), flavors.AKS())).To(Succeed())
+
+	for _, name := range []string{"cluster-template.yaml", "cluster-template-aks.yaml"} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(string(b)).To(HavePrefix("---\napiVersion: cluster.x-k8s.io/v1beta1\nkind: Cluster\n"))
+	}
+}
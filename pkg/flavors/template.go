@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package flavors renders clusterctl cluster templates for common topologies from Go-defined building blocks.
+// Downstream forks can compose the blocks, or add their own, to generate customized flavors programmatically.
+package flavors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// Template is a cluster template: an ordered list of objects whose fields may reference clusterctl variables,
+// e.g. ${CLUSTER_NAME}. Objects are unstructured so that variables can be used in fields of any type.
+type Template struct {
+	Objects []*unstructured.Unstructured
+}
+
+// Block adds objects to a template, or modifies the objects already in it.
+type Block func(t *Template)
+
+// Flavor is a named cluster template built by applying blocks in order.
+type Flavor struct {
+	// Name is the name of the flavor, as passed to clusterctl generate cluster --flavor.
+	Name string
+	// Blocks are applied in order to an empty template to build the flavor.
+	Blocks []Block
+}
+
+// With returns a copy of the flavor with the given name and additional blocks applied after its own.
+func (f Flavor) With(name string, blocks ...Block) Flavor {
+	all := make([]Block, 0, len(f.Blocks)+len(blocks))
+	all = append(all, f.Blocks...)
+	all = append(all, blocks...)
+	return Flavor{Name: name, Blocks: all}
+}
+
+// Template builds the template of the flavor.
+func (f Flavor) Template() *Template {
+	t := &Template{}
+	for _, block := range f.Blocks {
+		block(t)
+	}
+	return t
+}
+
+// FileName returns the name of the file clusterctl expects the flavor's template in.
+func (f Flavor) FileName() string {
+	if f.Name == "" || f.Name == "default" {
+		return "cluster-template.yaml"
+	}
+	return fmt.Sprintf("cluster-template-%s.yaml", f.Name)
+}
+
+// Add appends objects to the template.
+func (t *Template) Add(objs ...*unstructured.Unstructured) {
+	t.Objects = append(t.Objects, objs...)
+}
+
+// Get returns the object of the template with the given kind and name, or nil if there is none.
+func (t *Template) Get(kind, name string) *unstructured.Unstructured {
+	for _, obj := range t.Objects {
+		if obj.GetKind() == kind && obj.GetName() == name {
+			return obj
+		}
+	}
+	return nil
+}
+
+// Remove removes the object with the given kind and name from the template.
+func (t *Template) Remove(kind, name string) {
+	objs := t.Objects[:0]
+	for _, obj := range t.Objects {
+		if obj.GetKind() != kind || obj.GetName() != name {
+			objs = append(objs, obj)
+		}
+	}
+	t.Objects = objs
+}
+
+// Render writes the template as a multi-document YAML stream.
+func (t *Template) Render(w io.Writer) error {
+	for _, obj := range t.Objects {
+		b, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal %s %s", obj.GetKind(), obj.GetName())
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write renders the templates of the flavors to files in dir, named as clusterctl expects.
+func Write(dir string, flavors ...Flavor) error {
+	for _, flavor := range flavors {
+		path := filepath.Join(dir, flavor.FileName())
+		f, err := os.Create(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s", path)
+		}
+		if err := flavor.Template().Render(f); err != nil {
+			f.Close()
+			return errors.Wrapf(err, "failed to render flavor %s", flavor.Name)
+		}
+		if err := f.Close(); err != nil {
+			return errors.Wrapf(err, "failed to write %s", path)
+		}
+	}
+	return nil
+}
+
+// object returns a new object with the given API version, kind and name.
+func object(apiVersion, kind, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace("default")
+	return obj
+}
+
+// set sets the field at path of the object to value. The value must only hold JSON-compatible types,
+// e.g. int64 rather than int, and []interface{} rather than []string.
+func set(obj *unstructured.Unstructured, value interface{}, path ...string) {
+	if obj == nil {
+		return
+	}
+	if err := unstructured.SetNestedField(obj.Object, value, path...); err != nil {
+		panic(errors.Wrapf(err, "failed to set %v of %s %s", path, obj.GetKind(), obj.GetName()))
+	}
+}
+
+// appendTo appends values to the list at path of the object, creating it if needed.
+func appendTo(obj *unstructured.Unstructured, values []interface{}, path ...string) {
+	if obj == nil {
+		return
+	}
+	list, _, err := unstructured.NestedSlice(obj.Object, path...)
+	if err != nil {
+		panic(errors.Wrapf(err, "failed to get %v of %s %s", path, obj.GetKind(), obj.GetName()))
+	}
+	set(obj, append(list, values...), path...)
+}
+
+// list converts strings to a list value.
+func list(values ...string) []interface{} {
+	l := make([]interface{}, len(values))
+	for i, v := range values {
+		l[i] = v
+	}
+	return l
+}
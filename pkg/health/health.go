@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health provides health and readiness checks of the controller manager.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// cacheSyncTimeout bounds how long a probe waits for the informer caches, so that probes answer
+// well within their own timeout.
+const cacheSyncTimeout = time.Second
+
+// CacheSyncChecker returns a checker that fails until the informer caches of c have synced.
+func CacheSyncChecker(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+		defer cancel()
+		if !c.WaitForCacheSync(ctx) {
+			return errors.New("informer caches have not synced")
+		}
+		return nil
+	}
+}
+
+// credentialsChecker gets a token with the Azure credentials of the manager at most once per interval,
+// so that frequent probes neither slow down nor get throttled by Azure AD.
+type credentialsChecker struct {
+	credential azcore.TokenCredential
+	scope      string
+	interval   time.Duration
+
+	mu        sync.Mutex
+	lastCheck time.Time
+	lastErr   error
+}
+
+// AzureCredentialsChecker returns a checker that fails when no token can be obtained for scope with credential.
+// The result of a check is reused for interval.
+func AzureCredentialsChecker(credential azcore.TokenCredential, scope string, interval time.Duration) healthz.Checker {
+	c := &credentialsChecker{
+		credential: credential,
+		scope:      scope,
+		interval:   interval,
+	}
+	return c.check
+}
+
+func (c *credentialsChecker) check(req *http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.lastCheck.IsZero() && time.Since(c.lastCheck) < c.interval {
+		return c.lastErr
+	}
+
+	_, err := c.credential.GetToken(req.Context(), policy.TokenRequestOptions{Scopes: []string{c.scope}})
+	if req.Context().Err() != nil {
+		// The probe gave up, which says nothing about the credentials.
+		return err
+	}
+	c.lastCheck = time.Now()
+	c.lastErr = errors.Wrap(err, "failed to get an Azure token")
+	return c.lastErr
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+)
+
+type fakeCredential struct {
+	calls int
+	err   error
+}
+
+func (f *fakeCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	f.calls++
+	return azcore.AccessToken{}, f.err
+}
+
+func TestCacheSyncChecker(t *testing.T) {
+	g := NewWithT(t)
+	req := httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+
+	informers := &informertest.FakeInformers{Synced: ptr.To(false)}
+	check := CacheSyncChecker(informers)
+	g.Expect(check(req)).To(HaveOccurred())
+
+	informers.Synced = ptr.To(true)
+	g.Expect(check(req)).To(Succeed())
+}
+
+func TestAzureCredentialsChecker(t *testing.T) {
+	g := NewWithT(t)
+	req := httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+
+	credential := &fakeCredential{err: errors.New("invalid client secret")}
+	check := AzureCredentialsChecker(credential, "https://management.azure.com/.default", time.Hour)
+
+	g.Expect(check(req)).To(MatchError(ContainSubstring("invalid client secret")))
+	g.Expect(credential.calls).To(Equal(1))
+
+	// The result is reused until the interval has passed.
+	credential.err = nil
+	g.Expect(check(req)).To(HaveOccurred())
+	g.Expect(credential.calls).To(Equal(1))
+
+	check = AzureCredentialsChecker(credential, "https://management.azure.com/.default", 0)
+	g.Expect(check(req)).To(Succeed())
+	g.Expect(check(req)).To(Succeed())
+	g.Expect(credential.calls).To(Equal(3))
+}
@@ -34,9 +34,12 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/version"
 )
 
-// RegisterTracing enables code tracing via OpenTelemetry.
-func RegisterTracing(ctx context.Context, log logr.Logger) error {
-	tp, err := otlpTracerProvider(ctx, "opentelemetry-collector:4317")
+// DefaultTracingEndpoint is the OTLP endpoint of the opentelemetry-collector service in the controller's namespace.
+const DefaultTracingEndpoint = "opentelemetry-collector:4317"
+
+// RegisterTracing enables code tracing via OpenTelemetry, exporting traces to the given OTLP gRPC endpoint.
+func RegisterTracing(ctx context.Context, endpoint string, log logr.Logger) error {
+	tp, err := otlpTracerProvider(ctx, endpoint)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package skucatalog serves the VM sizes, zones and disk types available in a location, so that UIs can offer only the
+// valid choices without integrating with Azure themselves.
+package skucatalog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+)
+
+// Path is the path the catalog is served at on the metrics endpoint of the controller manager.
+const Path = "/sku-catalog"
+
+// CatalogFunc returns the catalog of location for subscriptionID, the subscription of the controller manager when empty.
+type CatalogFunc func(ctx context.Context, subscriptionID, location string) (resourceskus.Catalog, error)
+
+// NewHandler returns a handler answering GET requests with the JSON catalog of the location given by the location query
+// parameter, for the subscription given by the optional subscriptionID query parameter.
+func NewHandler(catalog CatalogFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		query := req.URL.Query()
+		location := query.Get("location")
+		if location == "" {
+			http.Error(w, "the location query parameter is required", http.StatusBadRequest)
+			return
+		}
+
+		c, err := catalog(req.Context(), query.Get("subscriptionID"), location)
+		if err != nil {
+			http.Error(w, "failed to list the SKUs of location "+location+": "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(c)
+	})
+}
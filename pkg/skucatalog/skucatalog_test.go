@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skucatalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+)
+
+func TestHandler(t *testing.T) {
+	catalog := resourceskus.Catalog{
+		Location:  "eastus",
+		Zones:     []string{"1", "2"},
+		VMSizes:   []resourceskus.VMSize{{Name: "Standard_D2s_v3", VCPUs: 2, MemoryGB: 8, Zones: []string{"1", "2"}}},
+		DiskTypes: []resourceskus.DiskType{{Name: "Premium_LRS", Zones: []string{"1"}}},
+	}
+
+	tests := []struct {
+		name                 string
+		method               string
+		target               string
+		err                  error
+		expectedStatus       int
+		expectedSubscription string
+	}{
+		{
+			name:           "returns the catalog of the location",
+			method:         http.MethodGet,
+			target:         Path + "?location=eastus",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:                 "passes the subscription on",
+			method:               http.MethodGet,
+			target:               Path + "?location=eastus&subscriptionID=123",
+			expectedStatus:       http.StatusOK,
+			expectedSubscription: "123",
+		},
+		{
+			name:           "requires a location",
+			method:         http.MethodGet,
+			target:         Path,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "only allows GET",
+			method:         http.MethodPost,
+			target:         Path + "?location=eastus",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "fails when the SKUs can't be listed",
+			method:         http.MethodGet,
+			target:         Path + "?location=eastus",
+			err:            errors.New("unauthorized"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var subscriptionID, location string
+			handler := NewHandler(func(_ context.Context, s, l string) (resourceskus.Catalog, error) {
+				subscriptionID, location = s, l
+				return catalog, tc.err
+			})
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.target, http.NoBody))
+			g.Expect(rec.Code).To(Equal(tc.expectedStatus))
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			g.Expect(location).To(Equal("eastus"))
+			g.Expect(subscriptionID).To(Equal(tc.expectedSubscription))
+			g.Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+			var actual resourceskus.Catalog
+			g.Expect(json.Unmarshal(rec.Body.Bytes(), &actual)).To(Succeed())
+			g.Expect(actual).To(Equal(catalog))
+		})
+	}
+}
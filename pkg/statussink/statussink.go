@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statussink reports the provisioning phase transitions of AzureClusters and AzureMachines to external
+// systems, so that a hosting platform can show the progress of a cluster build without polling the management cluster.
+package statussink
+
+import (
+	"context"
+	"time"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Phase is the provisioning phase of an AzureCluster or AzureMachine.
+type Phase string
+
+const (
+	// PhaseProvisioning means the Azure resources are being created or updated.
+	PhaseProvisioning Phase = "Provisioning"
+	// PhaseProvisioned means the Azure resources are ready.
+	PhaseProvisioned Phase = "Provisioned"
+	// PhaseFailed means provisioning failed and requires an intervention.
+	PhaseFailed Phase = "Failed"
+	// PhaseDeleting means the Azure resources are being deleted.
+	PhaseDeleting Phase = "Deleting"
+	// PhaseDeleted means the Azure resources have been deleted.
+	PhaseDeleted Phase = "Deleted"
+)
+
+// Event describes the transition of an AzureCluster or AzureMachine to a new phase.
+type Event struct {
+	// Kind is the kind of the object, AzureCluster or AzureMachine.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the object.
+	Namespace string `json:"namespace"`
+	// Name is the name of the object.
+	Name string `json:"name"`
+	// ClusterName is the name of the Cluster the object belongs to.
+	ClusterName string `json:"clusterName,omitempty"`
+	// Phase is the new phase of the object.
+	Phase Phase `json:"phase"`
+	// PreviousPhase is the phase of the object before the transition.
+	PreviousPhase Phase `json:"previousPhase,omitempty"`
+	// Message explains the phase, e.g. why provisioning failed.
+	Message string `json:"message,omitempty"`
+	// Time is when the transition was observed.
+	Time time.Time `json:"time"`
+}
+
+// Sink receives phase transitions. Send must not block reconciliation: implementations deliver events asynchronously.
+type Sink interface {
+	Send(ctx context.Context, event Event)
+}
+
+// AzureClusterPhase returns the provisioning phase of an AzureCluster and a message explaining it.
+func AzureClusterPhase(c *infrav1.AzureCluster) (Phase, string) {
+	if !c.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(c, infrav1.ClusterFinalizer) {
+			return PhaseDeleted, ""
+		}
+		return PhaseDeleting, ""
+	}
+	if c.Status.Ready {
+		return PhaseProvisioned, ""
+	}
+	// The Ready condition has an error severity when the last reconciliation failed. It goes back to Provisioning once
+	// the error is resolved.
+	if severity := conditions.GetSeverity(c, clusterv1.ReadyCondition); conditions.IsFalse(c, clusterv1.ReadyCondition) &&
+		severity != nil && *severity == clusterv1.ConditionSeverityError {
+		return PhaseFailed, conditions.GetMessage(c, clusterv1.ReadyCondition)
+	}
+	return PhaseProvisioning, ""
+}
+
+// AzureMachinePhase returns the provisioning phase of an AzureMachine and a message explaining it.
+func AzureMachinePhase(m *infrav1.AzureMachine) (Phase, string) {
+	if !m.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(m, infrav1.MachineFinalizer) {
+			return PhaseDeleted, ""
+		}
+		return PhaseDeleting, ""
+	}
+	if m.Status.FailureReason != nil || m.Status.FailureMessage != nil {
+		message := ""
+		if m.Status.FailureMessage != nil {
+			message = *m.Status.FailureMessage
+		}
+		return PhaseFailed, message
+	}
+	if m.Status.Ready {
+		return PhaseProvisioned, ""
+	}
+	return PhaseProvisioning, ""
+}
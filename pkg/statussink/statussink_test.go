@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statussink
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestAzureClusterPhase(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name            string
+		cluster         func(*infrav1.AzureCluster)
+		expectedPhase   Phase
+		expectedMessage string
+	}{
+		{
+			name:          "new cluster is provisioning",
+			cluster:       func(*infrav1.AzureCluster) {},
+			expectedPhase: PhaseProvisioning,
+		},
+		{
+			name: "ready cluster is provisioned",
+			cluster: func(c *infrav1.AzureCluster) {
+				c.Status.Ready = true
+			},
+			expectedPhase: PhaseProvisioned,
+		},
+		{
+			name: "cluster with an error is failed",
+			cluster: func(c *infrav1.AzureCluster) {
+				conditions.MarkFalse(c, clusterv1.ReadyCondition, "Failed", clusterv1.ConditionSeverityError, "quota exceeded")
+			},
+			expectedPhase:   PhaseFailed,
+			expectedMessage: "quota exceeded",
+		},
+		{
+			name: "cluster with a warning is provisioning",
+			cluster: func(c *infrav1.AzureCluster) {
+				conditions.MarkFalse(c, clusterv1.ReadyCondition, "Creating", clusterv1.ConditionSeverityWarning, "waiting")
+			},
+			expectedPhase: PhaseProvisioning,
+		},
+		{
+			name: "cluster being deleted is deleting",
+			cluster: func(c *infrav1.AzureCluster) {
+				c.DeletionTimestamp = &now
+				c.Finalizers = []string{infrav1.ClusterFinalizer}
+				c.Status.Ready = true
+			},
+			expectedPhase: PhaseDeleting,
+		},
+		{
+			name: "cluster without finalizer is deleted",
+			cluster: func(c *infrav1.AzureCluster) {
+				c.DeletionTimestamp = &now
+			},
+			expectedPhase: PhaseDeleted,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := &infrav1.AzureCluster{}
+			tc.cluster(c)
+			phase, message := AzureClusterPhase(c)
+			g.Expect(phase).To(Equal(tc.expectedPhase))
+			g.Expect(message).To(Equal(tc.expectedMessage))
+		})
+	}
+}
+
+func TestAzureMachinePhase(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name            string
+		machine         func(*infrav1.AzureMachine)
+		expectedPhase   Phase
+		expectedMessage string
+	}{
+		{
+			name:          "new machine is provisioning",
+			machine:       func(*infrav1.AzureMachine) {},
+			expectedPhase: PhaseProvisioning,
+		},
+		{
+			name: "ready machine is provisioned",
+			machine: func(m *infrav1.AzureMachine) {
+				m.Status.Ready = true
+			},
+			expectedPhase: PhaseProvisioned,
+		},
+		{
+			name: "machine with a failure message is failed",
+			machine: func(m *infrav1.AzureMachine) {
+				m.Status.FailureMessage = ptr.To("VM is in a failed state")
+			},
+			expectedPhase:   PhaseFailed,
+			expectedMessage: "VM is in a failed state",
+		},
+		{
+			name: "machine being deleted is deleting",
+			machine: func(m *infrav1.AzureMachine) {
+				m.DeletionTimestamp = &now
+				m.Finalizers = []string{infrav1.MachineFinalizer}
+			},
+			expectedPhase: PhaseDeleting,
+		},
+		{
+			name: "machine without finalizer is deleted",
+			machine: func(m *infrav1.AzureMachine) {
+				m.DeletionTimestamp = &now
+			},
+			expectedPhase: PhaseDeleted,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := &infrav1.AzureMachine{}
+			tc.machine(m)
+			phase, message := AzureMachinePhase(m)
+			g.Expect(phase).To(Equal(tc.expectedPhase))
+			g.Expect(message).To(Equal(tc.expectedMessage))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statussink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// queueSize is the number of events buffered while the endpoint is slow or unavailable. Events sent while the
+	// queue is full are dropped.
+	queueSize = 1000
+	// maxAttempts is the number of times the delivery of an event is attempted before it is dropped.
+	maxAttempts = 5
+	// initialBackoff is the delay before the first retry, doubled on every retry.
+	initialBackoff = time.Second
+	// requestTimeout bounds each delivery attempt.
+	requestTimeout = 10 * time.Second
+)
+
+// WebhookSink posts phase transitions as JSON to an HTTP endpoint, retrying failed deliveries with an exponential
+// backoff. Events are delivered one at a time in the order they were sent.
+// It implements the manager.Runnable interface: events are only delivered once it has been started.
+type WebhookSink struct {
+	url       string
+	tokenFile string
+	client    *http.Client
+	events    chan Event
+	backoff   time.Duration
+}
+
+// NewWebhookSink returns a sink posting events to url. When tokenFile is set, its content is sent as a bearer token.
+// The file is read for every request, so that the token can be rotated.
+func NewWebhookSink(url, tokenFile string) *WebhookSink {
+	return &WebhookSink{
+		url:       url,
+		tokenFile: tokenFile,
+		client:    &http.Client{Timeout: requestTimeout},
+		events:    make(chan Event, queueSize),
+		backoff:   initialBackoff,
+	}
+}
+
+// Send queues an event for delivery. It drops the event when the queue is full rather than blocking reconciliation.
+func (s *WebhookSink) Send(ctx context.Context, event Event) {
+	select {
+	case s.events <- event:
+	default:
+		ctrl.LoggerFrom(ctx).Info("status sink queue is full, dropping event", "kind", event.Kind, "name", event.Name, "phase", event.Phase)
+	}
+}
+
+// Start delivers the queued events until ctx is done.
+func (s *WebhookSink) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("statussink")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-s.events:
+			if err := s.deliver(ctx, event); err != nil {
+				log.Error(err, "failed to deliver event, dropping it", "kind", event.Kind, "namespace", event.Namespace, "name", event.Name, "phase", event.Phase)
+			}
+		}
+	}
+}
+
+// deliver posts an event, retrying up to maxAttempts times on network errors, throttling and server errors.
+func (s *WebhookSink) deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal event")
+	}
+	backoff := s.backoff
+	for attempt := 1; ; attempt++ {
+		retriable, err := s.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retriable || attempt == maxAttempts {
+			return errors.Wrapf(err, "giving up after %d attempts", attempt)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends a single request and reports whether a failure is worth retrying.
+func (s *WebhookSink) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.tokenFile != "" {
+		token, err := os.ReadFile(s.tokenFile)
+		if err != nil {
+			return true, errors.Wrap(err, "failed to read token file")
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return false, nil
+	}
+	err = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError, err
+}
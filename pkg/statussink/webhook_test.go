@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statussink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWebhookSinkDeliver(t *testing.T) {
+	tests := []struct {
+		name             string
+		statusCodes      []int
+		expectErr        bool
+		expectedAttempts int32
+	}{
+		{
+			name:             "delivered on first attempt",
+			statusCodes:      []int{http.StatusNoContent},
+			expectedAttempts: 1,
+		},
+		{
+			name:             "retries server errors and throttling",
+			statusCodes:      []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "does not retry client errors",
+			statusCodes:      []int{http.StatusBadRequest, http.StatusOK},
+			expectErr:        true,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "gives up after max attempts",
+			statusCodes:      []int{http.StatusServiceUnavailable},
+			expectErr:        true,
+			expectedAttempts: maxAttempts,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := int(attempts.Add(1)) - 1
+				if i >= len(tc.statusCodes) {
+					i = len(tc.statusCodes) - 1
+				}
+				w.WriteHeader(tc.statusCodes[i])
+			}))
+			defer server.Close()
+
+			sink := NewWebhookSink(server.URL, "")
+			sink.backoff = time.Millisecond
+			err := sink.deliver(context.Background(), Event{Kind: "AzureCluster", Name: "my-cluster", Phase: PhaseProvisioned})
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(attempts.Load()).To(Equal(tc.expectedAttempts))
+		})
+	}
+}
+
+func TestWebhookSinkStart(t *testing.T) {
+	g := NewWithT(t)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	g.Expect(os.WriteFile(tokenFile, []byte("my-token\n"), 0o600)).To(Succeed())
+
+	received := make(chan Event, 1)
+	var authorization atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization.Store(r.Header.Get("Authorization"))
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- event
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sink := NewWebhookSink(server.URL, tokenFile)
+	go func() {
+		_ = sink.Start(ctx)
+	}()
+
+	sink.Send(ctx, Event{Kind: "AzureMachine", Namespace: "default", Name: "my-machine", Phase: PhaseFailed, PreviousPhase: PhaseProvisioning, Message: "boom"})
+	var event Event
+	g.Eventually(received).WithTimeout(5 * time.Second).Should(Receive(&event))
+	g.Expect(event.Kind).To(Equal("AzureMachine"))
+	g.Expect(event.Name).To(Equal("my-machine"))
+	g.Expect(event.Phase).To(Equal(PhaseFailed))
+	g.Expect(event.PreviousPhase).To(Equal(PhaseProvisioning))
+	g.Expect(event.Message).To(Equal("boom"))
+	g.Expect(authorization.Load()).To(Equal("Bearer my-token"))
+}
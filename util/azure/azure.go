@@ -18,9 +18,12 @@ package azure
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -44,6 +47,15 @@ const (
 	ProviderIDPrefix = "azure://"
 )
 
+const (
+	// MaxResourceNameLength is the maximum length of the names of most Azure resources, including network interfaces,
+	// public IPs, route tables and managed disks.
+	MaxResourceNameLength = 80
+
+	// truncatedNameHashLength is the number of hex characters of the hash replacing the end of a truncated name.
+	truncatedNameHashLength = 8
+)
+
 // IsAzureSystemNodeLabelKey is a helper function that determines whether a node label key is an Azure "system" label.
 func IsAzureSystemNodeLabelKey(labelKey string) bool {
 	return strings.HasPrefix(labelKey, AzureSystemNodeLabelPrefix)
@@ -72,8 +84,9 @@ func getCloudConfig(environment azureautorest.Environment) cloud.Configuration {
 	return config
 }
 
-// GetAuthorizer returns an autorest.Authorizer-compatible object from MSAL.
-func GetAuthorizer(settings auth.EnvironmentSettings) (autorest.Authorizer, error) {
+// GetTokenCredential returns an azidentity token credential for environment, configured from the environment
+// variables or the managed identity of the controller manager. Its tokens are cached and refreshed by azidentity.
+func GetTokenCredential(environment azureautorest.Environment) (azcore.TokenCredential, error) {
 	// azidentity uses different envvars for certificate authentication:
 	//  azidentity: AZURE_CLIENT_CERTIFICATE_{PATH,PASSWORD}
 	//  autorest: AZURE_CERTIFICATE_{PATH,PASSWORD}
@@ -87,21 +100,71 @@ func GetAuthorizer(settings auth.EnvironmentSettings) (autorest.Authorizer, erro
 
 	options := azidentity.DefaultAzureCredentialOptions{
 		ClientOptions: azcore.ClientOptions{
-			Cloud: getCloudConfig(settings.Environment),
+			Cloud:     getCloudConfig(environment),
+			Transport: Transport(),
 		},
 	}
-	cred, err := azidentity.NewDefaultAzureCredential(&options)
+	return azidentity.NewDefaultAzureCredential(&options)
+}
+
+// GetAuthorizer returns an autorest.Authorizer-compatible object from MSAL.
+func GetAuthorizer(settings auth.EnvironmentSettings) (autorest.Authorizer, error) {
+	cred, err := GetTokenCredential(settings.Environment)
 	if err != nil {
 		return nil, err
 	}
+	return GetAuthorizerForCredential(cred, settings.Environment), nil
+}
 
+// GetAuthorizerForCredential returns an autorest.Authorizer that authorizes requests to environment with cred.
+func GetAuthorizerForCredential(cred azcore.TokenCredential, environment azureautorest.Environment) autorest.Authorizer {
 	// We must use TokenAudience for StackCloud, otherwise we get an
 	// AADSTS500011 error from the API
-	scope := settings.Environment.TokenAudience
+	scope := environment.TokenAudience
 	if !strings.HasSuffix(scope, "/.default") {
 		scope += "/.default"
 	}
-	return azidext.NewTokenCredentialAdapter(cred, []string{scope}), nil
+	return azidext.NewTokenCredentialAdapter(cred, []string{scope})
+}
+
+// GetEnvironmentCredential returns the token credential configured by the AZURE_* environment variables of the
+// controller manager, along with the scope of the resource manager of the cloud named by AZURE_ENVIRONMENT.
+func GetEnvironmentCredential() (azcore.TokenCredential, string, error) {
+	cloudConfig, scope, err := GetEnvironmentCloud()
+	if err != nil {
+		return nil, "", err
+	}
+
+	options := azidentity.EnvironmentCredentialOptions{
+		ClientOptions: azcore.ClientOptions{
+			Cloud:     cloudConfig,
+			Transport: Transport(),
+		},
+	}
+	cred, err := azidentity.NewEnvironmentCredential(&options)
+	if err != nil {
+		return nil, "", err
+	}
+	return cred, scope, nil
+}
+
+// GetEnvironmentCloud returns the configuration of the cloud named by the AZURE_ENVIRONMENT environment variable of
+// the controller manager, the public cloud by default, along with the scope of its resource manager.
+func GetEnvironmentCloud() (cloud.Configuration, string, error) {
+	environment := azureautorest.PublicCloud
+	if name := os.Getenv(auth.EnvironmentName); name != "" {
+		var err error
+		environment, err = azureautorest.EnvironmentFromName(name)
+		if err != nil {
+			return cloud.Configuration{}, "", err
+		}
+	}
+
+	scope := environment.TokenAudience
+	if !strings.HasSuffix(scope, "/.default") {
+		scope += "/.default"
+	}
+	return getCloudConfig(environment), scope, nil
 }
 
 // FindParentMachinePool finds the parent MachinePool for the AzureMachinePool.
@@ -138,3 +201,24 @@ func FindParentMachinePoolWithRetry(ampName string, cli client.Client, maxAttemp
 func ParseResourceID(id string) (*arm.ResourceID, error) {
 	return arm.ParseResourceID(strings.TrimPrefix(id, ProviderIDPrefix))
 }
+
+// TruncateName returns name followed by suffix, shortening name when the result would exceed maxLength characters. The
+// end of a shortened name is replaced by a hash of the whole name, so that the same name is always shortened the same
+// way and long names sharing a prefix stay distinct. Names which fit are returned unchanged.
+func TruncateName(name, suffix string, maxLength int) string {
+	if len(name)+len(suffix) <= maxLength {
+		return name + suffix
+	}
+	sum := sha256.Sum256([]byte(name + suffix))
+	hash := hex.EncodeToString(sum[:])[:truncatedNameHashLength]
+	keep := maxLength - len(suffix) - len(hash) - 1
+	// Multi-byte characters are not cut in half, which would leave an invalid UTF-8 string.
+	for keep > 0 && !utf8.RuneStart(name[keep]) {
+		keep--
+	}
+	if keep <= 0 {
+		return hash + suffix
+	}
+	// Separators left at the end of the shortened name are dropped so the hash is not preceded by two of them.
+	return strings.TrimRight(name[:keep], ".-_") + "-" + hash + suffix
+}
@@ -18,7 +18,9 @@ package azure
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	. "github.com/onsi/gomega"
 	utilfeature "k8s.io/component-base/featuregate/testing"
@@ -175,3 +177,23 @@ func TestParseResourceID(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncateName(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(TruncateName("my-machine", "_OSDisk", MaxResourceNameLength)).To(Equal("my-machine_OSDisk"))
+
+	long := strings.Repeat("a", 70) + "-machine"
+	truncated := TruncateName(long, "_OSDisk", MaxResourceNameLength)
+	g.Expect(truncated).To(HaveLen(MaxResourceNameLength))
+	g.Expect(truncated).To(HavePrefix(strings.Repeat("a", 64) + "-"))
+	g.Expect(truncated).To(HaveSuffix("_OSDisk"))
+	g.Expect(TruncateName(long, "_OSDisk", MaxResourceNameLength)).To(Equal(truncated))
+	g.Expect(TruncateName(long+"2", "_OSDisk", MaxResourceNameLength)).NotTo(Equal(truncated))
+
+	// Separators are not doubled before the hash.
+	g.Expect(TruncateName(strings.Repeat("a", 10)+"-"+strings.Repeat("b", 10), "", 20)).To(MatchRegexp(`^a{10}-[0-9a-f]{8}$`))
+
+	// Multi-byte characters are kept whole.
+	g.Expect(utf8.ValidString(TruncateName(strings.Repeat("é", 20), "", 20))).To(BeTrue())
+}
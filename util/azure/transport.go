@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// IMDSAddress is the address of the Azure Instance Metadata Service, which managed identities get their tokens from.
+// It is only reachable from the VM itself, never through a proxy.
+const IMDSAddress = "169.254.169.254"
+
+// TransportOptions configures the connections of the Azure SDK clients to Azure.
+type TransportOptions struct {
+	// ProxyURL is the URL of the HTTP proxy requests are sent through. When empty, the HTTPS_PROXY and NO_PROXY
+	// environment variables are honored.
+	ProxyURL string
+	// NoProxy is a comma-separated list of hosts, domain suffixes and CIDRs reached without ProxyURL.
+	NoProxy string
+	// CABundle holds PEM encoded certificate authorities trusted in addition to the system ones, e.g. the CA of a
+	// proxy inspecting TLS traffic.
+	CABundle []byte
+}
+
+var (
+	transportMu sync.RWMutex
+	transport   policy.Transporter
+)
+
+// SetTransport makes the Azure SDK clients created afterwards connect to Azure according to options. It resets
+// them to the default transport of the SDK when options is empty.
+func SetTransport(options TransportOptions) error {
+	if options.ProxyURL == "" && len(options.CABundle) == 0 {
+		transportMu.Lock()
+		defer transportMu.Unlock()
+		transport = nil
+		return nil
+	}
+
+	// The default transport honors the proxy environment variables.
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if options.ProxyURL != "" {
+		proxyURL, err := url.Parse(options.ProxyURL)
+		if err != nil {
+			return errors.Wrapf(err, "invalid proxy URL %q", options.ProxyURL)
+		}
+		if (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || proxyURL.Host == "" {
+			return errors.Errorf("invalid proxy URL %q: an http or https URL with a host is expected", options.ProxyURL)
+		}
+		proxy := (&httpproxy.Config{
+			HTTPProxy:  options.ProxyURL,
+			HTTPSProxy: options.ProxyURL,
+			NoProxy:    joinNoProxy(options.NoProxy, IMDSAddress),
+		}).ProxyFunc()
+		base.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		}
+	}
+	if len(options.CABundle) > 0 {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(options.CABundle) {
+			return errors.New("invalid CA bundle: no PEM encoded certificate found")
+		}
+		base.TLSClientConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    rootCAs,
+		}
+	}
+
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	transport = &http.Client{Transport: base}
+	return nil
+}
+
+// Transport returns the transport set with SetTransport, or nil for the default transport of the Azure SDK.
+func Transport() policy.Transporter {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	return transport
+}
+
+// joinNoProxy appends hosts to a comma-separated no proxy list.
+func joinNoProxy(noProxy string, hosts ...string) string {
+	entries := []string{}
+	for _, entry := range strings.Split(noProxy, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return strings.Join(append(entries, hosts...), ",")
+}
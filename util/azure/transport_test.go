@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSetTransportProxy(t *testing.T) {
+	g := NewWithT(t)
+	defer func() { g.Expect(SetTransport(TransportOptions{})).To(Succeed()) }()
+
+	g.Expect(SetTransport(TransportOptions{})).To(Succeed())
+	g.Expect(Transport()).To(BeNil())
+
+	g.Expect(SetTransport(TransportOptions{ProxyURL: "proxy.example.com:3128"})).NotTo(Succeed())
+	g.Expect(SetTransport(TransportOptions{ProxyURL: "http://proxy.example.com:3128", NoProxy: "example.org, .internal"})).To(Succeed())
+	g.Expect(Transport()).NotTo(BeNil())
+
+	proxy := Transport().(*http.Client).Transport.(*http.Transport).Proxy
+	tests := []struct {
+		url       string
+		wantProxy bool
+	}{
+		{url: "https://management.azure.com/subscriptions", wantProxy: true},
+		{url: "https://login.microsoftonline.com/tenant/oauth2/v2.0/token", wantProxy: true},
+		{url: "https://example.org/", wantProxy: false},
+		{url: "https://vault.internal/", wantProxy: false},
+		{url: "http://169.254.169.254/metadata/identity/oauth2/token", wantProxy: false},
+	}
+	for _, tc := range tests {
+		req, err := http.NewRequest(http.MethodGet, tc.url, http.NoBody)
+		g.Expect(err).NotTo(HaveOccurred())
+		proxyURL, err := proxy(req)
+		g.Expect(err).NotTo(HaveOccurred())
+		if tc.wantProxy {
+			g.Expect(proxyURL).NotTo(BeNil(), tc.url)
+			g.Expect(proxyURL.Host).To(Equal("proxy.example.com:3128"))
+		} else {
+			g.Expect(proxyURL).To(BeNil(), tc.url)
+		}
+	}
+}
+
+func TestSetTransportCABundle(t *testing.T) {
+	g := NewWithT(t)
+	defer func() { g.Expect(SetTransport(TransportOptions{})).To(Succeed()) }()
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	req, err := http.NewRequest(http.MethodGet, server.URL, http.NoBody)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(SetTransport(TransportOptions{CABundle: []byte("not a certificate")})).NotTo(Succeed())
+
+	// PEM blocks that are not certificates are rejected.
+	g.Expect(SetTransport(TransportOptions{CABundle: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")})})).NotTo(Succeed())
+	g.Expect(SetTransport(TransportOptions{CABundle: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})})).To(Succeed())
+	resp, err := Transport().Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	defer resp.Body.Close()
+	g.Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+}
@@ -35,6 +35,14 @@ const (
 	DefaultReconcilerRequeue = 15 * time.Second
 	// DefaultHTTP429RetryAfter is a default backoff wait time when we get a HTTP 429 response with no Retry-After data.
 	DefaultHTTP429RetryAfter = 1 * time.Minute
+	// DefaultServerErrorRequeue is the requeue interval after a transient server error (HTTP 5xx) from Azure.
+	DefaultServerErrorRequeue = 30 * time.Second
+	// DefaultDependencyRequeue is the requeue interval after an Azure resource was not found, which usually means that
+	// it depends on a resource that is still being created.
+	DefaultDependencyRequeue = 5 * time.Second
+	// DefaultHopelessErrorRequeue is the requeue interval after an Azure error that retrying the same request cannot fix
+	// until the subscription changes, such as an exceeded quota or a request denied by Azure Policy.
+	DefaultHopelessErrorRequeue = 10 * time.Minute
 )
 
 // DefaultedLoopTimeout will default the timeout if it is zero-valued.
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// DefaultRateLimiterBaseDelay is the default delay before the first retry of a failed reconcile.
+	DefaultRateLimiterBaseDelay = 5 * time.Millisecond
+	// DefaultRateLimiterMaxDelay is the default upper bound of the per-item exponential backoff.
+	DefaultRateLimiterMaxDelay = 1000 * time.Second
+	// DefaultRateLimiterQPS is the default overall rate at which items are requeued by a controller.
+	DefaultRateLimiterQPS = 10
+	// DefaultRateLimiterBurst is the default bucket size of the overall requeue rate limiter.
+	DefaultRateLimiterBurst = 100
+)
+
+// NewRateLimiter returns a controller rate limiter that combines a per-item exponential backoff between
+// baseDelay and maxDelay with an overall token bucket of qps and burst, whichever is slower.
+// The defaults match workqueue.DefaultControllerRateLimiter.
+func NewRateLimiter(baseDelay, maxDelay time.Duration, qps float64, burst int) workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	limiter := reconciler.NewRateLimiter(10*time.Millisecond, 40*time.Millisecond, 1000, 1000)
+
+	// Failures of a single item back off exponentially up to the max delay.
+	g.Expect(limiter.When("a")).To(gomega.Equal(10 * time.Millisecond))
+	g.Expect(limiter.When("a")).To(gomega.Equal(20 * time.Millisecond))
+	g.Expect(limiter.When("a")).To(gomega.Equal(40 * time.Millisecond))
+	g.Expect(limiter.When("a")).To(gomega.Equal(40 * time.Millisecond))
+	g.Expect(limiter.NumRequeues("a")).To(gomega.Equal(4))
+
+	// Other items are backed off independently.
+	g.Expect(limiter.When("b")).To(gomega.Equal(10 * time.Millisecond))
+
+	limiter.Forget("a")
+	g.Expect(limiter.NumRequeues("a")).To(gomega.Equal(0))
+	g.Expect(limiter.When("a")).To(gomega.Equal(10 * time.Millisecond))
+}
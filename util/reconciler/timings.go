@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// SlowServicesThreshold is the total duration of the services of a reconcile loop above which their timings are
+// logged at the default verbosity instead of only at verbosity 4.
+const SlowServicesThreshold = 30 * time.Second
+
+// ServiceTimings records how long each service of a reconcile loop takes, so that slow reconciles can be broken down
+// by service in the logs.
+type ServiceTimings struct {
+	names     []string
+	durations []time.Duration
+}
+
+// Time calls fn and records its duration under the name of the service.
+func (t *ServiceTimings) Time(name string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	t.names = append(t.names, name)
+	t.durations = append(t.durations, time.Since(start))
+	return err
+}
+
+// Total returns the sum of the recorded durations.
+func (t *ServiceTimings) Total() time.Duration {
+	var total time.Duration
+	for _, d := range t.durations {
+		total += d
+	}
+	return total
+}
+
+// KeysAndValues returns the recorded durations as logr key/value pairs, in the order the services were timed, followed
+// by their total. Durations are rounded to the millisecond.
+func (t *ServiceTimings) KeysAndValues() []interface{} {
+	keysAndValues := make([]interface{}, 0, 2*len(t.names)+2)
+	for i, name := range t.names {
+		keysAndValues = append(keysAndValues, name, t.durations[i].Round(time.Millisecond).String())
+	}
+	return append(keysAndValues, "total", t.Total().Round(time.Millisecond).String())
+}
+
+// Log logs the recorded durations with msg, at verbosity 2 when they exceed SlowServicesThreshold and at verbosity 4
+// otherwise.
+func (t *ServiceTimings) Log(log logr.Logger, msg string) {
+	level := 4
+	if t.Total() > SlowServicesThreshold {
+		level = 2
+	}
+	log.V(level).Info(msg, t.KeysAndValues()...)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+)
+
+func TestServiceTimings(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	timings := &reconciler.ServiceTimings{}
+	g.Expect(timings.KeysAndValues()).To(gomega.Equal([]interface{}{"total", "0s"}))
+
+	g.Expect(timings.Time("groups", func() error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})).To(gomega.Succeed())
+	errFailed := errors.New("failed")
+	g.Expect(timings.Time("virtualnetworks", func() error {
+		return errFailed
+	})).To(gomega.MatchError(errFailed))
+
+	g.Expect(timings.Total()).To(gomega.BeNumerically(">=", 20*time.Millisecond))
+	keysAndValues := timings.KeysAndValues()
+	g.Expect(keysAndValues).To(gomega.HaveLen(6))
+	g.Expect(keysAndValues[0]).To(gomega.Equal("groups"))
+	g.Expect(keysAndValues[2]).To(gomega.Equal("virtualnetworks"))
+	g.Expect(keysAndValues[4]).To(gomega.Equal("total"))
+	g.Expect(keysAndValues[5]).To(gomega.Equal(timings.Total().Round(time.Millisecond).String()))
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tele
+
+import (
+	"context"
+	"fmt"
+)
+
+// Object identifies the Kubernetes object a reconciliation is for.
+type Object struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// String returns the object as "Kind namespace/name".
+func (o Object) String() string {
+	return fmt.Sprintf("%s %s/%s", o.Kind, o.Namespace, o.Name)
+}
+
+type objectKey struct{}
+
+// ctxWithObject returns a copy of ctx recording the object identified by the "kind", "namespace" and "name" KVPs of
+// a span, or ctx itself if the span is not started for an object.
+func ctxWithObject(ctx context.Context, cfg *Config) context.Context {
+	kind, ok := cfg.KVPs["kind"]
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, objectKey{}, Object{
+		Kind:      kind,
+		Namespace: cfg.KVPs["namespace"],
+		Name:      cfg.KVPs["name"],
+	})
+}
+
+// ObjectFromCtx returns the object recorded in ctx by the innermost span started with a "kind" KVP, usually the
+// object a controller reconciles. It returns false if there is none.
+func ObjectFromCtx(ctx context.Context) (Object, bool) {
+	obj, ok := ctx.Value(objectKey{}).(Object)
+	return obj, ok
+}
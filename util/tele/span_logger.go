@@ -141,6 +141,9 @@ func KVP(key, value string) Option {
 // implementation that composes both the logger from the
 // given ctx and a logger that logs to the newly created span.
 //
+// Spans started with the "kind", "namespace" and "name" KVPs of an object
+// record the object in the returned context, see ObjectFromCtx.
+//
 // Callers should make sure to call the function in the 3rd return
 // value to ensure that the span is ended properly. In many cases,
 // that can be done with a defer:
@@ -156,6 +159,7 @@ func StartSpanWithLogger(
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	ctx = ctxWithObject(ctx, cfg)
 	ctx, span := Tracer().Start(
 		ctx,
 		spanName,
@@ -176,3 +180,10 @@ func StartSpanWithLogger(
 		NewSpanLogSink(span),
 	}), endFn
 }
+
+// WithLogValues returns a copy of ctx whose logger includes the given key/value pairs.
+// Loggers for spans started from the returned context, and for Azure API requests made with it,
+// include these values as well.
+func WithLogValues(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	return log.IntoContext(ctx, log.FromContext(ctx).WithValues(keysAndValues...))
+}
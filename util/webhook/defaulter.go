@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// WarningFunc returns the admission warnings for an object as it was submitted, before any defaults are applied.
+type WarningFunc func(obj runtime.Object) admission.Warnings
+
+// RegisterDefaulterWithWarnings registers a defaulting webhook for obj at the path the controller-runtime webhook
+// builder would use, so it replaces WithDefaulter in the builder of the type. Unlike the defaulting webhooks of the
+// builder, it returns the warnings of warn, which lets users know about deprecated fields that the defaulter moves to
+// their replacements before any validating webhook sees them.
+func RegisterDefaulterWithWarnings(mgr ctrl.Manager, obj runtime.Object, defaulter admission.CustomDefaulter, warn WarningFunc) error {
+	gvk, err := apiutil.GVKForObject(obj, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	path := "/mutate-" + strings.ReplaceAll(gvk.Group, ".", "-") + "-" + gvk.Version + "-" + strings.ToLower(gvk.Kind)
+	mgr.GetWebhookServer().Register(path, DefaulterWithWarnings(mgr.GetScheme(), obj, defaulter, warn))
+	return nil
+}
+
+// DefaulterWithWarnings returns a defaulting webhook for obj that adds the warnings of warn to the responses of the
+// defaulter.
+func DefaulterWithWarnings(scheme *runtime.Scheme, obj runtime.Object, defaulter admission.CustomDefaulter, warn WarningFunc) *admission.Webhook {
+	return &admission.Webhook{
+		Handler: &warningHandler{
+			handler: admission.WithCustomDefaulter(scheme, obj, defaulter),
+			decoder: admission.NewDecoder(scheme),
+			object:  obj,
+			warn:    warn,
+		},
+	}
+}
+
+// warningHandler adds warnings for the submitted object to the responses of a handler.
+type warningHandler struct {
+	handler admission.Handler
+	decoder *admission.Decoder
+	object  runtime.Object
+	warn    WarningFunc
+}
+
+// Handle implements admission.Handler.
+func (h *warningHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	resp := h.handler.Handle(ctx, req)
+	if !resp.Allowed || len(req.Object.Raw) == 0 {
+		return resp
+	}
+	obj := h.object.DeepCopyObject()
+	if err := h.decoder.DecodeRaw(req.Object, obj); err != nil {
+		return resp
+	}
+	resp.Warnings = append(resp.Warnings, h.warn(obj)...)
+	return resp
+}
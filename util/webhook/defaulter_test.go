@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+type fakeDefaulter struct {
+	err error
+}
+
+func (d fakeDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	if d.err != nil {
+		return d.err
+	}
+	cm := obj.(*corev1.ConfigMap)
+	cm.Data = map[string]string{"new": cm.Data["old"]}
+	return nil
+}
+
+func warnOldKey(obj runtime.Object) admission.Warnings {
+	if _, ok := obj.(*corev1.ConfigMap).Data["old"]; ok {
+		return admission.Warnings{"data.old is deprecated"}
+	}
+	return nil
+}
+
+func TestDefaulterWithWarnings(t *testing.T) {
+	tests := []struct {
+		name             string
+		data             map[string]string
+		defaulterErr     error
+		expectAllowed    bool
+		expectedWarnings []string
+	}{
+		{
+			name:             "warns about the object as submitted",
+			data:             map[string]string{"old": "value"},
+			expectAllowed:    true,
+			expectedWarnings: []string{"data.old is deprecated"},
+		},
+		{
+			name:          "no warnings",
+			data:          map[string]string{"new": "value"},
+			expectAllowed: true,
+		},
+		{
+			name:         "no warnings when the defaulter fails",
+			data:         map[string]string{"old": "value"},
+			defaulterErr: errors.New("failed"),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cm := &corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"},
+				Data:       tc.data,
+			}
+			raw, err := json.Marshal(cm)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			hook := DefaulterWithWarnings(clientgoscheme.Scheme, &corev1.ConfigMap{}, fakeDefaulter{err: tc.defaulterErr}, warnOldKey)
+			resp := hook.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			g.Expect(resp.Allowed).To(Equal(tc.expectAllowed))
+			if tc.expectedWarnings == nil {
+				g.Expect(resp.Warnings).To(BeEmpty())
+			} else {
+				g.Expect(resp.Warnings).To(Equal(tc.expectedWarnings))
+			}
+		})
+	}
+}